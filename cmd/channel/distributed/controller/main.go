@@ -20,7 +20,9 @@ import (
 	// Uncomment the following line to load the gcp plugin (only required to authenticate against GKE clusters).
 	// _ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/broker"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/eventredelivery"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkachannel"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkasecret"
 	"knative.dev/pkg/injection/sharedmain"
@@ -37,5 +39,5 @@ func main() {
 	// sarama.EnableSaramaLogging()
 
 	// Create The SharedMain Instance With The Various Controllers
-	sharedmain.Main(constants.ControllerComponentName, kafkachannel.NewController, kafkasecret.NewController)
+	sharedmain.Main(constants.ControllerComponentName, kafkachannel.NewController, kafkasecret.NewController, eventredelivery.NewController, broker.NewController)
 }
@@ -23,24 +23,42 @@ import (
 
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/events"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/gctuning"
 	commonk8s "knative.dev/eventing-kafka/pkg/channel/distributed/common/k8s"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/identity"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/listener"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/controller"
 	dispatch "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/dispatcher"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/env"
 	dispatcherhealth "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/health"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/replicator"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/snapshot"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/tail"
 	"knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	"knative.dev/eventing-kafka/pkg/client/informers/externalversions"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	eventinginformers "knative.dev/eventing/pkg/client/informers/externalversions"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	kncontroller "knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	eventingmetrics "knative.dev/pkg/metrics"
 	"knative.dev/pkg/signals"
+	"knative.dev/pkg/system"
 )
 
 // Variables
@@ -74,13 +92,66 @@ func main() {
 	}
 
 	// Load The Sarama & Eventing-Kafka Configuration From The ConfigMap
-	saramaConfig, _, err := sarama.LoadSettings(ctx)
+	saramaConfig, ekConfig, err := sarama.LoadSettings(ctx)
 	if err != nil {
 		logger.Fatal("Failed To Load Sarama Settings", zap.Error(err))
 	}
 
+	// Validate The Network Configuration & Bind The Listeners To The Configured IP Family (Both On Dual-Stack Nodes By Default)
+	if err = listener.ValidateNetworkConfig(ekConfig.Network); err != nil {
+		logger.Fatal("Invalid Network Configuration - Terminating!", zap.Error(err))
+	}
+	listener.SetIPFamily(ekConfig.Network)
+
+	// Apply Any Namespace Overrides Of The Dispatcher Configuration (Passed By The Controller)
+	err = commonconfig.MergeDispatcherOverrides(&ekConfig.Dispatcher, environment.DispatcherConfigOverrides)
+	if err != nil {
+		logger.Fatal("Invalid Dispatcher Config Overrides - Terminating!", zap.Error(err))
+	}
+
+	// Apply The Go Runtime's Garbage Collection Tuning (GOGC & Memory Ballast)
+	if err = gctuning.Apply(logger, ekConfig.Dispatcher.Runtime); err != nil {
+		logger.Fatal("Invalid Dispatcher Runtime Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Render The Kafka ClientID From The Configured ClientIdTemplate (Defaults To The Component Name)
+	clientId, err := sarama.NewClientId(ekConfig.Kafka, constants.Component, environment.ChannelKey, environment.PodName)
+	if err != nil {
+		logger.Fatal("Invalid Kafka ClientIdTemplate - Terminating!", zap.Error(err))
+	}
+
 	// Update The Sarama Config - Username/Password Overrides (EnvVars From Secret Take Precedence Over ConfigMap)
-	sarama.UpdateSaramaConfig(saramaConfig, constants.Component, environment.KafkaUsername, environment.KafkaPassword)
+	sarama.UpdateSaramaConfig(saramaConfig, clientId, environment.KafkaUsername, environment.KafkaPassword)
+
+	// Authenticate Via The KafkaAuthSpec Rather Than The Kafka Secret's Username/Password If Configured
+	err = sarama.UpdateSaramaAuthSpec(ctx, kubeclient.Get(ctx), system.Namespace(), saramaConfig, ekConfig.Kafka.AuthSpec)
+	if err != nil {
+		logger.Fatal("Failed To Apply KafkaAuthSpec - Terminating!", zap.Error(err))
+	}
+
+	// Authenticate Via The Workload Identity Rather Than The Kafka Secret's Username/Password If Enabled
+	err = identity.UpdateSaramaConfig(saramaConfig, ekConfig.Kafka.WorkloadIdentity, logger)
+	if err != nil {
+		logger.Fatal("Failed To Configure Workload Identity - Terminating!", zap.Error(err))
+	}
+
+	// Connect To The Kafka Brokers Through The Egress Proxy If Configured
+	err = sarama.UpdateSaramaProxy(ctx, kubeclient.Get(ctx), system.Namespace(), saramaConfig, ekConfig.Kafka)
+	if err != nil {
+		logger.Fatal("Failed To Configure Kafka Egress Proxy - Terminating!", zap.Error(err))
+	}
+
+	// Dial The Kafka Brokers At Their Rewritten Addresses (e.g. Split-Horizon DNS) If Configured
+	err = sarama.UpdateSaramaBrokerAddressRewrites(saramaConfig, ekConfig.Kafka)
+	if err != nil {
+		logger.Fatal("Invalid Kafka Broker Address Rewrites - Terminating!", zap.Error(err))
+	}
+
+	// Apply The ConsumerGroup Settings (e.g. The Offsets Retention) Of The Subscribers' ConsumerGroups
+	err = sarama.UpdateSaramaConsumerGroup(saramaConfig, ekConfig.Dispatcher.ConsumerGroup)
+	if err != nil {
+		logger.Fatal("Invalid ConsumerGroup Configuration - Terminating!", zap.Error(err))
+	}
 
 	// Initialize Tracing (Watches config-tracing ConfigMap, Assumes Context Came From LoggingContext With Embedded K8S Client Key)
 	err = commonconfig.InitializeTracing(logger.Sugar(), ctx, environment.ServiceName)
@@ -100,26 +171,68 @@ func main() {
 
 	statsReporter := metrics.NewStatsReporter(logger)
 
-	// Create The Dispatcher With Specified Configuration
-	dispatcherConfig := dispatch.DispatcherConfig{
-		Logger:        logger,
-		ClientId:      constants.Component,
-		Brokers:       strings.Split(environment.KafkaBrokers, ","),
-		Topic:         environment.KafkaTopic,
-		Username:      environment.KafkaUsername,
-		Password:      environment.KafkaPassword,
-		ChannelKey:    environment.ChannelKey,
-		StatsReporter: statsReporter,
-		SaramaConfig:  saramaConfig,
+	// Create The Subscriber RetryPolicies From The Dispatcher's Retry Configuration
+	retryPolicies, err := dispatch.NewRetryPolicies(ekConfig.Dispatcher.Retry)
+	if err != nil {
+		logger.Fatal("Invalid Dispatcher Retry Configuration - Terminating!", zap.Error(err))
 	}
-	dispatcher = dispatch.NewDispatcher(dispatcherConfig)
 
-	// Watch The Settings ConfigMap For Changes
-	err = commonconfig.InitializeConfigWatcher(ctx, logger.Sugar(), configMapObserver)
+	// Validate The Dispatcher's Deduplication Configuration
+	if err = dispatch.ValidateDedupeConfig(ekConfig.Dispatcher.Dedupe); err != nil {
+		logger.Fatal("Invalid Dispatcher Dedupe Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Dispatcher's Poison Pill Configuration
+	if err = dispatch.ValidatePoisonPillConfig(ekConfig.Dispatcher.PoisonPill); err != nil {
+		logger.Fatal("Invalid Dispatcher PoisonPill Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Kafka Headers Policy (Propagating Kafka Headers Into CloudEvent Extensions)
+	if err = ekConfig.Kafka.Headers.Validate(); err != nil {
+		logger.Fatal("Invalid Kafka Headers Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Dispatcher's Destination Resolution Configuration
+	if err = dispatch.ValidateResolutionConfig(ekConfig.Dispatcher.Resolution); err != nil {
+		logger.Fatal("Invalid Dispatcher Resolution Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Dispatcher's Endpoint Load Balancing Configuration
+	if err = dispatch.ValidateEndpointsConfig(ekConfig.Dispatcher.Endpoints); err != nil {
+		logger.Fatal("Invalid Dispatcher Endpoints Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Dispatcher's Subscriber Health Probe Configuration
+	if err = dispatch.ValidateHealthProbeConfig(ekConfig.Dispatcher.HealthProbe); err != nil {
+		logger.Fatal("Invalid Dispatcher HealthProbe Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Dispatcher's Subscriber Destination Check Configuration
+	if err = dispatch.ValidateDestinationCheckConfig(ekConfig.Dispatcher.DestinationCheck); err != nil {
+		logger.Fatal("Invalid Dispatcher DestinationCheck Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Kafka Encryption Configuration & Create The Envelope (nil Unless Enabled) From The Mounted Keys
+	if err = encryption.ValidateEncryptionConfig(ekConfig.Kafka.Encryption); err != nil {
+		logger.Fatal("Invalid Kafka Encryption Configuration - Terminating!", zap.Error(err))
+	}
+	envelope, err := encryption.NewEnvelope(ekConfig.Kafka.Encryption)
 	if err != nil {
-		logger.Fatal("Failed To Initialize ConfigMap Watcher", zap.Error(err))
+		logger.Fatal("Failed To Read Kafka Encryption Keys - Terminating!", zap.Error(err))
 	}
 
+	// Validate The Middleware Configuration & Load The Modules (nil Unless Enabled) With The Registered Runtime
+	if err = middleware.ValidateMiddlewareConfig(ekConfig.Middleware); err != nil {
+		logger.Fatal("Invalid Middleware Configuration - Terminating!", zap.Error(err))
+	}
+	middlewares, err := middleware.NewRegistry(logger, ekConfig.Middleware)
+	if err != nil {
+		logger.Fatal("Failed To Load Middleware - Terminating!", zap.Error(err))
+	}
+
+	// Create The Tap Sampling Events For The Tail Endpoint (nil Unless Enabled)
+	tap := tail.NewTap(ekConfig.Dispatcher.Tail)
+
 	config, err := clientcmd.BuildConfigFromFlags(*serverURL, *kubeconfig)
 	if err != nil {
 		logger.Fatal("Error building kubeconfig", zap.Error(err))
@@ -130,10 +243,136 @@ func main() {
 	config.Burst = numControllers * rest.DefaultBurst
 	kafkaClientSet := versioned.NewForConfigOrDie(config)
 	kubeClient := kubernetes.NewForConfigOrDie(config)
+	eventingClientSet := eventingclientset.NewForConfigOrDie(config)
 	kafkaInformerFactory := externalversions.NewSharedInformerFactory(kafkaClientSet, kncontroller.DefaultResyncPeriod)
 
+	// Create The Resolver Periodically Re-Resolving The Subscribers' Destinations (nil Unless Enabled)
+	resolver := dispatch.NewDestinationResolver(logger, ekConfig.Dispatcher.Resolution, environment.ChannelKey,
+		eventingClientSet.MessagingV1(), dynamic.NewForConfigOrDie(config))
+
 	// Create KafkaChannel Informer
 	kafkaChannelInformer := kafkaInformerFactory.Messaging().V1beta1().KafkaChannels()
+	informers := []kncontroller.Informer{kafkaChannelInformer.Informer()}
+
+	// Create The Balancer Delivering To The Endpoints Of Subscriber Services (nil Unless Enabled, Watching Them Cluster-Wide)
+	var balancer *dispatch.EndpointBalancer
+	if ekConfig.Dispatcher.Endpoints.Enabled {
+		kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, kncontroller.DefaultResyncPeriod)
+		serviceInformer := kubeInformerFactory.Core().V1().Services()
+		endpointSliceInformer := kubeInformerFactory.Discovery().V1beta1().EndpointSlices()
+		balancer = dispatch.NewEndpointBalancer(logger, ekConfig.Dispatcher.Endpoints, serviceInformer.Lister(), endpointSliceInformer.Lister())
+		informers = append(informers, serviceInformer.Informer(), endpointSliceInformer.Informer())
+	}
+
+	// Create The Informer Of The Subscriptions In The KafkaChannel's Namespace (Whose Annotations May Push Filters Down)
+	channelNamespace, _, err := cache.SplitMetaNamespaceKey(environment.ChannelKey)
+	if err != nil {
+		logger.Fatal("Invalid KafkaChannel Key", zap.String("ChannelKey", environment.ChannelKey), zap.Error(err))
+	}
+	eventingInformerFactory := eventinginformers.NewSharedInformerFactoryWithOptions(eventingClientSet, kncontroller.DefaultResyncPeriod, eventinginformers.WithNamespace(channelNamespace))
+	subscriptionInformer := eventingInformerFactory.Messaging().V1().Subscriptions()
+	informers = append(informers, subscriptionInformer.Informer())
+
+	// Create The Access Control Of The Subscriptions Of Other Namespaces, Per The ChannelAccessPolicies Of The KafkaChannel's Namespace
+	channelAccessPolicyInformer := kafkaInformerFactory.Messaging().V1beta1().ChannelAccessPolicies()
+	informers = append(informers, channelAccessPolicyInformer.Informer())
+	accessControl := dispatch.NewAccessControl(logger, channelNamespace, channelAccessPolicyInformer, subscriptionInformer.Lister(), eventingClientSet.MessagingV1())
+
+	// Create The Reporter Posting Data Plane Warning Events Against The KafkaChannel
+	eventReporter := events.NewReporter(logger, events.NewRecorder(kubeClient, constants.Component, ctx.Done()), kafkaChannelInformer.Lister()).ForChannel(environment.ChannelKey)
+
+	// Create The Health Probing Of The Subscribers, Shared By Recreated Dispatchers (nil Unless Enabled)
+	subscriberHealth := dispatch.NewSubscriberHealth(logger, ekConfig.Dispatcher.HealthProbe, eventReporter)
+
+	// Quarantine Undeliverable Events In The KafkaChannel's Quarantine Topic If Enabled
+	var quarantineTopic string
+	if ekConfig.Kafka.Quarantine.Enabled {
+		quarantineTopic = kafkautil.QuarantineTopicName(environment.KafkaTopic)
+	}
+
+	// Set The Liveness Flag Before Waiting For Kafka So That The Pod Isn't Restarted While The Brokers Are Unreachable
+	healthServer.SetAlive(true)
+
+	// Wait For The Kafka Brokers To Be Reachable (e.g. Cluster Cold Start) Rather Than Failing To Create The Consumers
+	kafkaBrokers := strings.Split(environment.KafkaBrokers, ",")
+	err = sarama.WaitForBrokers(ctx, logger, ekConfig.Kafka, saramaConfig, kafkaBrokers)
+	if err != nil {
+		logger.Fatal("Kafka Brokers Unreachable - Terminating!", zap.Error(err))
+	}
+
+	// Create The Dispatcher With Specified Configuration
+	dispatcherConfig := dispatch.DispatcherConfig{
+		Logger:           logger,
+		ClientId:         clientId,
+		Brokers:          kafkaBrokers,
+		Topic:            environment.KafkaTopic,
+		Username:         environment.KafkaUsername,
+		Password:         environment.KafkaPassword,
+		ChannelKey:       environment.ChannelKey,
+		StatsReporter:    statsReporter,
+		SaramaConfig:     saramaConfig,
+		RetryPolicies:    retryPolicies,
+		FaultInjector:    faults.NewInjector(logger, ekConfig.FaultInjection),
+		Tap:              tap,
+		Dedupe:           ekConfig.Dispatcher.Dedupe,
+		ConsumerGroup:    ekConfig.Dispatcher.ConsumerGroup,
+		PoisonPill:       ekConfig.Dispatcher.PoisonPill,
+		ExtraTopics:      ekConfig.Kafka.ExtraTopics,
+		QuarantineTopic:  quarantineTopic,
+		HeadersPolicy:    &ekConfig.Kafka.Headers,
+		EventReporter:    eventReporter,
+		Resolver:         resolver,
+		Balancer:         balancer,
+		SubscriberHealth: subscriberHealth,
+		Envelope:         envelope,
+		Middleware:       middlewares,
+	}
+	dispatcher = dispatch.NewDispatcher(dispatcherConfig)
+
+	// Create The Replicator Mirroring The KafkaChannel's Topic To A Remote Kafka Cluster (nil Unless Configured)
+	var replicationBrokers []string
+	if len(environment.ReplicationKafkaBrokers) > 0 {
+		replicationBrokers = strings.Split(environment.ReplicationKafkaBrokers, ",")
+	}
+	topicReplicator, err := replicator.NewReplicator(replicator.ReplicatorConfig{
+		Logger:         logger,
+		Topic:          environment.KafkaTopic,
+		Brokers:        kafkaBrokers,
+		RemoteBrokers:  replicationBrokers,
+		RemoteUsername: environment.ReplicationKafkaUsername,
+		RemotePassword: environment.ReplicationKafkaPassword,
+		SaramaConfig:   saramaConfig,
+	})
+	if err != nil {
+		logger.Fatal("Failed To Create Topic Replicator - Terminating!", zap.Error(err))
+	}
+	topicReplicator.Start()
+
+	// Watch The Settings ConfigMap For Changes
+	err = commonconfig.InitializeConfigWatcher(ctx, logger.Sugar(), configMapObserver)
+	if err != nil {
+		logger.Fatal("Failed To Initialize ConfigMap Watcher", zap.Error(err))
+	}
+
+	// Start The Tail Server If Enabled (Authorizes Callers Against The KafkaChannel)
+	tailServer, err := tail.NewServer(logger, tap, kubeClient, environment.ChannelKey, ekConfig.Dispatcher.Tail)
+	if err != nil {
+		logger.Fatal("Failed To Create Tail Server - Terminating!", zap.Error(err))
+	}
+	if tailServer != nil {
+		if err = tailServer.Start(); err != nil {
+			logger.Fatal("Failed To Start Tail Server - Terminating!", zap.Error(err))
+		}
+	}
+
+	// Create The Store Persisting The Subscription Snapshot (nil Unless Enabled)
+	snapshotStore := snapshot.NewStore(logger, kubeClient, system.Namespace(), environment.ServiceName, ekConfig.Dispatcher.Snapshot)
+
+	// Resume Consuming The Subscriptions Of Any Persisted Snapshot Without Waiting For The KafkaChannel To Be Reconciled
+	err = controller.RestoreSnapshot(ctx, logger, environment.ChannelKey, dispatcher, snapshotStore)
+	if err != nil {
+		logger.Warn("Failed To Restore Subscription Snapshot - Awaiting KafkaChannel Reconciliation", zap.Error(err))
+	}
 
 	// Construct Array Of Controllers, In Our Case Just The One
 	controllers := [...]*kncontroller.Impl{
@@ -142,22 +381,30 @@ func main() {
 			environment.ChannelKey,
 			dispatcher,
 			kafkaChannelInformer,
+			subscriptionInformer,
 			kubeClient,
 			kafkaClientSet,
+			snapshotStore,
+			subscriberHealth,
+			dispatch.NewDestinationCheck(logger, ekConfig.Dispatcher.DestinationCheck),
+			dispatch.NewOIDCTokens(logger, kubeClient, system.Namespace(), ekConfig.Dispatcher.OIDC),
+			accessControl,
 			ctx.Done(),
 		),
 	}
 
 	// Start The Informers
 	logger.Info("Starting informers.")
-	if err := kncontroller.StartInformers(ctx.Done(), kafkaChannelInformer.Informer()); err != nil {
+	if err := kncontroller.StartInformers(ctx.Done(), informers...); err != nil {
 		logger.Error("Failed to start informers", zap.Error(err))
 		return
 	}
 
-	// Set The Liveness And Readiness Flags
-	logger.Info("Registering dispatcher as alive and ready")
-	healthServer.SetAlive(true)
+	// Start Re-Resolving The Subscribers' Destinations (No-Op Unless Enabled)
+	resolver.Start(ctx.Done())
+
+	// Set The Readiness Flag (The Liveness Flag Was Set Before Waiting For Kafka)
+	logger.Info("Registering dispatcher as ready")
 	healthServer.SetDispatcherReady(true)
 
 	// Start The Controllers (Blocking WaitGroup.Wait Call)
@@ -170,6 +417,12 @@ func main() {
 	// Shutdown The Dispatcher (Close ConsumerGroups)
 	dispatcher.Shutdown()
 
+	// Stop Replicating The KafkaChannel's Topic
+	topicReplicator.Stop()
+
+	// Stop The Tail Server
+	tailServer.Stop()
+
 	// Stop The Liveness And Readiness Servers
 	healthServer.Stop(logger)
 }
@@ -22,25 +22,47 @@ import (
 	nethttp "net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/events"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/gctuning"
 	commonk8s "knative.dev/eventing-kafka/pkg/channel/distributed/common/k8s"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/identity"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
 	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/latency"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/listener"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/batch"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/bodylimit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/channel"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/env"
 	channelhealth "knative.dev/eventing-kafka/pkg/channel/distributed/receiver/health"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/policy"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/producer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/routing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/shutdown"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/status"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/throttle"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/validation"
 	eventingchannel "knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	eventingmetrics "knative.dev/pkg/metrics"
+	"knative.dev/pkg/signals"
+	"knative.dev/pkg/system"
 )
 
 // Variables
@@ -49,6 +71,9 @@ var (
 	serverURL     = flag.String("server", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
 	kubeconfig    = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	kafkaProducer *producer.Producer
+	eventReporter *events.Reporter
+	ingressPolicy *policy.Policy
+	middlewares   *middleware.Registry
 )
 
 // The Main Function (Go Command)
@@ -57,8 +82,8 @@ func main() {
 	// Parse The Flags For Local Development Usage
 	flag.Parse()
 
-	// Initialize A Knative Injection Lite Context (K8S Client & Logger)
-	ctx := commonk8s.LoggingContext(context.Background(), constants.Component, *serverURL, *kubeconfig)
+	// Initialize A Knative Injection Lite Context (K8S Client & Logger), Done When Signalled To Terminate
+	ctx := commonk8s.LoggingContext(signals.NewContext(), constants.Component, *serverURL, *kubeconfig)
 
 	// Get The Logger From The Context & Defer Flushing Any Buffered Log Entries On Exit
 	logger = logging.FromContext(ctx).Desugar()
@@ -74,13 +99,54 @@ func main() {
 	}
 
 	// Load The Sarama (& Eventing-Kafka) Configuration From The ConfigMap
-	saramaConfig, _, err := sarama.LoadSettings(ctx)
+	saramaConfig, ekConfig, err := sarama.LoadSettings(ctx)
 	if err != nil {
 		logger.Fatal("Failed To Load Sarama Settings", zap.Error(err))
 	}
 
+	// Validate The Network Configuration & Bind The Listeners To The Configured IP Family (Both On Dual-Stack Nodes By Default)
+	if err = listener.ValidateNetworkConfig(ekConfig.Network); err != nil {
+		logger.Fatal("Invalid Network Configuration - Terminating!", zap.Error(err))
+	}
+	listener.SetIPFamily(ekConfig.Network)
+
+	// Apply The Go Runtime's Garbage Collection Tuning (GOGC & Memory Ballast)
+	if err = gctuning.Apply(logger, ekConfig.Receiver.Runtime); err != nil {
+		logger.Fatal("Invalid Receiver Runtime Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Render The Kafka ClientID From The Configured ClientIdTemplate (Defaults To The Component Name)
+	clientId, err := sarama.NewClientId(ekConfig.Kafka, constants.Component, "", environment.PodName)
+	if err != nil {
+		logger.Fatal("Invalid Kafka ClientIdTemplate - Terminating!", zap.Error(err))
+	}
+
 	// Update The Sarama Config - Username/Password Overrides (EnvVars From Secret Take Precedence Over ConfigMap)
-	sarama.UpdateSaramaConfig(saramaConfig, constants.Component, environment.KafkaUsername, environment.KafkaPassword)
+	sarama.UpdateSaramaConfig(saramaConfig, clientId, environment.KafkaUsername, environment.KafkaPassword)
+
+	// Authenticate Via The KafkaAuthSpec Rather Than The Kafka Secret's Username/Password If Configured
+	err = sarama.UpdateSaramaAuthSpec(ctx, kubeclient.Get(ctx), system.Namespace(), saramaConfig, ekConfig.Kafka.AuthSpec)
+	if err != nil {
+		logger.Fatal("Failed To Apply KafkaAuthSpec - Terminating!", zap.Error(err))
+	}
+
+	// Authenticate Via The Workload Identity Rather Than The Kafka Secret's Username/Password If Enabled
+	err = identity.UpdateSaramaConfig(saramaConfig, ekConfig.Kafka.WorkloadIdentity, logger)
+	if err != nil {
+		logger.Fatal("Failed To Configure Workload Identity - Terminating!", zap.Error(err))
+	}
+
+	// Connect To The Kafka Brokers Through The Egress Proxy If Configured
+	err = sarama.UpdateSaramaProxy(ctx, kubeclient.Get(ctx), system.Namespace(), saramaConfig, ekConfig.Kafka)
+	if err != nil {
+		logger.Fatal("Failed To Configure Kafka Egress Proxy - Terminating!", zap.Error(err))
+	}
+
+	// Dial The Kafka Brokers At Their Rewritten Addresses (e.g. Split-Horizon DNS) If Configured
+	err = sarama.UpdateSaramaBrokerAddressRewrites(saramaConfig, ekConfig.Kafka)
+	if err != nil {
+		logger.Fatal("Invalid Kafka Broker Address Rewrites - Terminating!", zap.Error(err))
+	}
 
 	// Initialize Tracing (Watches config-tracing ConfigMap, Assumes Context Came From LoggingContext With Embedded K8S Client Key)
 	err = commonconfig.InitializeTracing(logger.Sugar(), ctx, environment.ServiceName)
@@ -105,6 +171,15 @@ func main() {
 	}
 	defer channel.Close()
 
+	// Serve The Receiver's Status (Channels, Recent Produce Errors & Broker Connections) Alongside The Health Endpoints
+	produceErrors := status.NewErrorTracker(status.DefaultErrorWindow)
+	healthServer.Handle(status.Path, status.NewHandler(logger, channel.ListChannels, produceErrors, func() []status.BrokerStatus {
+		return kafkaProducer.BrokerStatuses() // Re-Read As The Producer Is Created Later (And Recreated On Config Changes)
+	}))
+
+	// Create The Reporter Posting Data Plane Warning Events Against The KafkaChannels
+	eventReporter = events.NewReporter(logger, events.NewRecorder(kubeclient.Get(ctx), constants.Component, ctx.Done()), channel.GetKafkaChannelLister())
+
 	// Create A New Stats StatsReporter
 	statsReporter := metrics.NewStatsReporter(logger)
 
@@ -114,8 +189,92 @@ func main() {
 		logger.Fatal("Failed To Initialize ConfigMap Watcher", zap.Error(err))
 	}
 
+	// Validate The Receiver's Maximum Request Body Size & Create The Body Limit
+	if err = bodylimit.ValidateMaxBodySize(ekConfig.Receiver.MaxBodySize); err != nil {
+		logger.Fatal("Invalid Receiver Maximum Body Size - Terminating!", zap.Error(err))
+	}
+	bodyLimit := bodylimit.NewLimit(logger, ekConfig.Receiver.MaxBodySize)
+
+	// Validate The Receiver's KafkaChannel Addressing (Host-Based Unless Path-Based Addressing Is Configured)
+	if err = routing.ValidateAddressing(ekConfig.Receiver.Addressing); err != nil {
+		logger.Fatal("Invalid Receiver Addressing - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Receiver's Kafka Record Timestamp (The Produce Time Unless The CloudEvent Time Is Configured)
+	if !kafkautil.IsValidReceiverTimestamp(ekConfig.Receiver.Timestamp) {
+		logger.Fatal("Invalid Receiver Timestamp - Terminating!", zap.String("Timestamp", ekConfig.Receiver.Timestamp))
+	}
+
+	// Validate The Receiver's Throttle Configuration & Create The Throttle (nil Unless Enabled)
+	if err = throttle.ValidateThrottleConfig(ekConfig.Receiver.Throttle); err != nil {
+		logger.Fatal("Invalid Receiver Throttle Configuration - Terminating!", zap.Error(err))
+	}
+	producerThrottle := throttle.NewThrottle(logger, ekConfig.Receiver.Throttle)
+
+	// Validate The Receiver's Validation Configuration & Create The Event Validator (nil Unless Enabled)
+	if err = validation.ValidateValidationConfig(ekConfig.Receiver.Validation); err != nil {
+		logger.Fatal("Invalid Receiver Validation Configuration - Terminating!", zap.Error(err))
+	}
+	registryCredentials := validation.RegistryCredentials{
+		Username:     environment.SchemaRegistryUsername,
+		Password:     environment.SchemaRegistryPassword,
+		Token:        environment.SchemaRegistryToken,
+		ClientId:     environment.SchemaRegistryClientId,
+		ClientSecret: environment.SchemaRegistryClientSecret,
+	}
+	if err = validation.ValidateRegistryAuth(ekConfig.Receiver.Validation.SchemaRegistryAuth, registryCredentials); err != nil {
+		logger.Fatal("Invalid Schema Registry Authentication - Terminating!", zap.Error(err))
+	}
+	eventValidator := validation.NewValidator(logger, ekConfig.Receiver.Validation, registryCredentials, resolveTopicName)
+
+	// Validate The Receiver's Ingress Policy Configuration & Create The Ingress Policy (nil Unless Enabled)
+	if err = policy.ValidatePolicyConfig(ekConfig.Receiver.Policy); err != nil {
+		logger.Fatal("Invalid Receiver Ingress Policy Configuration - Terminating!", zap.Error(err))
+	}
+	ingressPolicy = policy.NewPolicy(logger, ekConfig.Receiver.Policy)
+
+	// Validate The Receiver's Graceful Shutdown Configuration
+	if err = shutdown.ValidateShutdownConfig(ekConfig.Receiver.Shutdown); err != nil {
+		logger.Fatal("Invalid Receiver Shutdown Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Kafka Headers Policy (Writing Back Extensions Propagated From Kafka Headers)
+	if err = ekConfig.Kafka.Headers.Validate(); err != nil {
+		logger.Fatal("Invalid Kafka Headers Configuration - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Kafka Encryption Configuration & Create The Envelope (nil Unless Enabled) From The Mounted Keys
+	if err = encryption.ValidateEncryptionConfig(ekConfig.Kafka.Encryption); err != nil {
+		logger.Fatal("Invalid Kafka Encryption Configuration - Terminating!", zap.Error(err))
+	}
+	envelope, err := encryption.NewEnvelope(ekConfig.Kafka.Encryption)
+	if err != nil {
+		logger.Fatal("Failed To Read Kafka Encryption Keys - Terminating!", zap.Error(err))
+	}
+
+	// Validate The Middleware Configuration & Load The Modules (nil Unless Enabled) With The Registered Runtime
+	if err = middleware.ValidateMiddlewareConfig(ekConfig.Middleware); err != nil {
+		logger.Fatal("Invalid Middleware Configuration - Terminating!", zap.Error(err))
+	}
+	middlewares, err = middleware.NewRegistry(logger, ekConfig.Middleware)
+	if err != nil {
+		logger.Fatal("Failed To Load Middleware - Terminating!", zap.Error(err))
+	}
+
+	// Set The Liveness Flag - Readiness Is Set By Individual Components
+	// (Before Waiting For Kafka So That The Pod Isn't Restarted While The Brokers Are Unreachable)
+	healthServer.SetAlive(true)
+
+	// Wait For The Kafka Brokers To Be Reachable (e.g. Cluster Cold Start) Rather Than Failing To Create The Producer
+	kafkaBrokers := strings.Split(environment.KafkaBrokers, ",")
+	err = sarama.WaitForBrokers(ctx, logger, ekConfig.Kafka, saramaConfig, kafkaBrokers)
+	if err != nil {
+		logger.Fatal("Kafka Brokers Unreachable - Terminating!", zap.Error(err))
+	}
+
 	// Initialize The Kafka Producer In Order To Start Processing Status Events
-	kafkaProducer, err = producer.NewProducer(logger, saramaConfig, strings.Split(environment.KafkaBrokers, ","), statsReporter, healthServer)
+	faultInjector := faults.NewInjector(logger, ekConfig.FaultInjection)
+	kafkaProducer, err = producer.NewProducer(logger, saramaConfig, kafkaBrokers, statsReporter, healthServer, faultInjector, producerThrottle, &ekConfig.Kafka.Headers, envelope, ekConfig.Receiver.Latency.Enabled, ekConfig.Receiver.Timestamp == kafkaconstants.ReceiverTimestampCeTime, produceErrors, nil)
 	if err != nil {
 		logger.Fatal("Failed To Initialize Kafka Producer", zap.Error(err))
 	}
@@ -129,13 +288,19 @@ func main() {
 		logger.Fatal("Failed To Create MessageReceiver", zap.Error(err))
 	}
 
-	// Set The Liveness Flag - Readiness Is Set By Individual Components
-	healthServer.SetAlive(true)
-
-	// Start The Message Receiver (Blocking)
-	err = messageReceiver.Start(ctx)
+	// Wrap The MessageReceiver With Support For CloudEvents Batched Requests (Each Event Is Handled Individually)
+	batchHandler := batch.NewHandler(logger, messageReceiver, eventingchannel.ParseChannel, handleMessage, channelReporter)
+
+	// Start The HTTP Receiver (Blocking Until Terminated, Then Draining The In-Flight Requests Before The Producer Is Closed)
+	// Reject Requests With Bodies Exceeding The Maximum Size With 413 (Streaming The Others With The Limit)
+	// Route Requests To /<namespace>/<name> Paths To Their KafkaChannel's Host (If Path-Based Addressing Is Configured)
+	// Reject Requests With 503 & Retry-After While Kafka Is Throttling The Producer (If Enabled)
+	// Reject Forbidden Events With 403 (And Unauthenticated Callers With 401) & Tag Accepted Events (If Enabled)
+	// Reject Invalid Events With 400 & Problem Details Before They Are Produced (If Enabled)
+	handler := kncloudevents.CreateHandler(bodyLimit.Handler(routing.Handler(logger, ekConfig.Receiver.Addressing, producerThrottle.Handler(ingressPolicy.Handler(eventValidator.Handler(batchHandler))))))
+	err = shutdown.NewServer(logger, constants.HttpPort, handler, healthServer, ekConfig.Receiver.Shutdown).ListenAndServe(ctx)
 	if err != nil {
-		logger.Error("Failed To Start MessageReceiver", zap.Error(err))
+		logger.Error("Failed To Start Or Gracefully Stop MessageReceiver", zap.Error(err))
 	}
 
 	// Reset The Liveness and Readiness Flags In Preparation For Shutdown
@@ -151,9 +316,21 @@ func flush(logger *zap.Logger) {
 	eventingmetrics.FlushExporter()
 }
 
+// Resolve The Kafka Topic Of The KafkaChannel Addressed By The Specified Host (For Naming Schema Registry Subjects)
+func resolveTopicName(host string) (string, error) {
+	channelReference, err := eventingchannel.ParseChannel(host)
+	if err != nil {
+		return "", err
+	}
+	return channel.GetTopicName(channelReference)
+}
+
 // CloudEvent Message Handler - Converts To KafkaMessage And Produces To Channel's Kafka Topic
 func handleMessage(ctx context.Context, channelReference eventingchannel.ChannelReference, message binding.Message, transformers []binding.Transformer, _ nethttp.Header) error {
 
+	// Record The Time The Event Was Received (Injected As A Hop Timestamp Header If Enabled)
+	ctx = latency.WithReceivedTime(ctx, time.Now())
+
 	// Note - The context provided here is a different context from the one created in main() and does not have our logger instance.
 	logger.Debug("~~~~~~~~~~~~~~~~~~~~  Processing Request  ~~~~~~~~~~~~~~~~~~~~")
 	logger.Debug("Received Message", zap.Any("ChannelReference", channelReference))
@@ -168,10 +345,47 @@ func handleMessage(ctx context.Context, channelReference eventingchannel.Channel
 		return err
 	}
 
+	// Get The KafkaChannel's EventTypeRouting (Nil Unless Enabled Via Annotations)
+	eventTypeRouting, err := channel.GetEventTypeRouting(channelReference)
+	if err != nil {
+		logger.Warn("Unable To Get EventTypeRouting", zap.Any("ChannelReference", channelReference), zap.Error(err))
+		return err
+	}
+
+	// Determine Whether The KafkaChannel's Topic Is Compacted (Requiring Keyed Messages)
+	compacted, err := channel.IsCompacted(channelReference)
+	if err != nil {
+		logger.Warn("Unable To Get CleanupPolicy", zap.Any("ChannelReference", channelReference), zap.Error(err))
+		return err
+	}
+
+	// Get The KafkaChannel's Record KeyTemplate (Nil Unless Enabled Via Annotation)
+	keyTemplate, err := channel.GetKeyTemplate(channelReference)
+	if err != nil {
+		logger.Warn("Unable To Get KeyTemplate", zap.Any("ChannelReference", channelReference), zap.Error(err))
+		return err
+	}
+
+	// Get The KafkaChannel's Topic Name (Overridden By The Topic Annotation Of Migrated KafkaChannels)
+	topicName, err := channel.GetTopicName(channelReference)
+	if err != nil {
+		logger.Warn("Unable To Get TopicName", zap.Any("ChannelReference", channelReference), zap.Error(err))
+		return err
+	}
+
+	// Apply The KafkaChannel's Middleware (If Any) - Rejected Events Fail The Request & Dropped Events Are Not Produced
+	message, transformers, err = applyMiddleware(ctx, channelReference, message, transformers)
+	if err != nil {
+		return err
+	} else if message == nil {
+		return nil
+	}
+
 	// Produce The CloudEvent Binding Message (Send To The Appropriate Kafka Topic)
-	err = kafkaProducer.ProduceKafkaMessage(ctx, channelReference, message, transformers...)
+	err = kafkaProducer.ProduceKafkaMessage(ctx, topicName, eventTypeRouting, compacted, keyTemplate, message, transformers...)
 	if err != nil {
 		logger.Error("Failed To Produce Kafka Message", zap.Error(err))
+		eventReporter.Warning(channelReference.Namespace, channelReference.Name, events.ProduceFailed, "Failed To Produce Event To Kafka Topic %s: %v", topicName, err)
 		return err
 	}
 
@@ -179,12 +393,51 @@ func handleMessage(ctx context.Context, channelReference eventingchannel.Channel
 	return nil
 }
 
+// Apply The Receive Phase Middleware Selected By The KafkaChannel, Returning The Resulting Message (nil If Dropped)
+func applyMiddleware(ctx context.Context, channelReference eventingchannel.ChannelReference, message binding.Message, transformers []binding.Transformer) (binding.Message, []binding.Transformer, error) {
+
+	// Get The KafkaChannel's Middleware Pipeline (Nil Unless Selected Via Annotation)
+	names, err := channel.GetMiddleware(channelReference)
+	if err != nil {
+		logger.Warn("Unable To Get Middleware", zap.Any("ChannelReference", channelReference), zap.Error(err))
+		return nil, nil, err
+	}
+	pipeline, err := middlewares.Pipeline(middleware.ReceivePhase, names)
+	if err != nil {
+		logger.Warn("Invalid KafkaChannel Middleware", zap.Any("ChannelReference", channelReference), zap.Error(err))
+		return nil, nil, err
+	} else if len(pipeline) == 0 {
+		return message, transformers, nil
+	}
+
+	// Process The Event (With The Transformers Applied) Through The Pipeline
+	cloudEvent, err := binding.ToEvent(ctx, message, transformers...)
+	if err != nil {
+		logger.Warn("Unable To Convert Message To Event For Middleware", zap.Error(err))
+		return nil, nil, err
+	}
+	target := middleware.Target{Phase: middleware.ReceivePhase, Namespace: channelReference.Namespace, Channel: channelReference.Name}
+	cloudEvent, err = pipeline.Process(ctx, target, cloudEvent)
+	if err != nil {
+		logger.Warn("Middleware Rejected Event", zap.Any("ChannelReference", channelReference), zap.Error(err))
+		return nil, nil, err
+	} else if cloudEvent == nil {
+		logger.Debug("Middleware Dropped Event", zap.Any("ChannelReference", channelReference), zap.Strings("Middleware", pipeline.Names()))
+		return nil, nil, nil
+	}
+	return binding.ToMessage(cloudEvent), nil, nil
+}
+
 // configMapObserver is the callback function that handles changes to our ConfigMap
 func configMapObserver(configMap *v1.ConfigMap) {
 	if configMap == nil {
 		logger.Warn("Nil ConfigMap passed to configMapObserver; ignoring")
 		return
 	}
+
+	// Apply Any Changes To The Ingress Policy's Rules
+	ingressPolicy.ConfigChanged(configMap)
+
 	if kafkaProducer == nil {
 		// This typically happens during startup
 		logger.Debug("Producer is nil during call to configMapObserver; ignoring changes")
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	k8sclientcmd "k8s.io/client-go/tools/clientcmd"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/cli"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	kafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned"
+	"knative.dev/pkg/signals"
+	"knative.dev/pkg/system"
+)
+
+// Variables
+var (
+	serverURL  = flag.String("server", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig.")
+	kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Defaults to the standard kubectl loading rules.")
+	verbose    = flag.Bool("v", false, "Enable verbose logging.")
+)
+
+// The Main Function (Go Command)
+func main() {
+
+	// Parse The Global Flags (Command Flags Are Parsed By The CLI)
+	flag.Parse()
+
+	// The Settings ConfigMap Is Loaded From The System Namespace (Default To knative-eventing When Run Locally)
+	if len(os.Getenv(system.NamespaceEnvKey)) == 0 {
+		_ = os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace)
+	}
+
+	// Create The Logger (Quiet Unless Verbose)
+	logger := zap.NewNop()
+	if *verbose {
+		logger, _ = zap.NewDevelopment()
+	}
+	defer func() { _ = logger.Sync() }()
+
+	// Create The K8S Configuration Using The Standard kubectl Loading Rules
+	loadingRules := k8sclientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = *kubeconfig
+	overrides := &k8sclientcmd.ConfigOverrides{}
+	overrides.ClusterInfo.Server = *serverURL
+	k8sConfig, err := k8sclientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		exit(fmt.Errorf("failed to build Kubernetes config: %w", err))
+	}
+
+	// Create The Kubernetes & KafkaChannel Clients
+	k8sClient, err := kubernetes.NewForConfig(k8sConfig)
+	if err != nil {
+		exit(fmt.Errorf("failed to create Kubernetes client: %w", err))
+	}
+	kafkaClient, err := kafkaclientset.NewForConfig(k8sConfig)
+	if err != nil {
+		exit(fmt.Errorf("failed to create KafkaChannel client: %w", err))
+	}
+
+	// Run The Specified Command
+	err = cli.NewCLI(logger, k8sClient, kafkaClient, os.Stdout).Run(signals.NewContext(), flag.Args())
+	if errors.Is(err, cli.ErrUsage) {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	} else if err != nil {
+		exit(err)
+	}
+}
+
+// Report The Specified Error & Exit With A Failure Status
+func exit(err error) {
+	_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/common/perf"
+	"knative.dev/pkg/signals"
+)
+
+// Variables
+var (
+	mode           = flag.String("mode", perf.ModeBoth, "The mode of the run: both (send & receive), send or receive.")
+	target         = flag.String("target", "", "The URL to which events are sent (e.g. a KafkaChannel's address). Defaults to the sink itself in both mode.")
+	sinkAddress    = flag.String("sink", ":8080", "The address on which the sink receiver listens.")
+	runId          = flag.String("run-id", "", "The id of the run. Defaults to the current time (a receive-only run counts the events of any run if unset).")
+	rate           = flag.Int("rate", 100, "The events sent per second.")
+	duration       = flag.Duration("duration", time.Minute, "How long to send events for (or receive them for in receive mode, until interrupted if zero).")
+	concurrency    = flag.Int("concurrency", 10, "The maximum number of events being sent concurrently.")
+	payloadSize    = flag.Int("payload-size", 1024, "The size (in bytes) of each event's data.")
+	drainTimeout   = flag.Duration("drain-timeout", perf.DefaultDrainTimeout, "How long to wait for the sent events to be received in both mode.")
+	reportInterval = flag.Duration("report-interval", 0, "The interval between interim JSON results (none if zero), for soak runs.")
+	outputPath     = flag.String("output", "", "The file to which the JSON results are written. Defaults to stdout.")
+	verbose        = flag.Bool("v", false, "Enable verbose logging.")
+)
+
+// The Main Function (Go Command)
+func main() {
+
+	// Parse The Flags
+	flag.Parse()
+
+	// Create The Logger (On stderr, Leaving stdout To The Results)
+	loggerConfig := zap.NewProductionConfig()
+	if *verbose {
+		loggerConfig = zap.NewDevelopmentConfig()
+	}
+	loggerConfig.OutputPaths = []string{"stderr"}
+	logger, err := loggerConfig.Build()
+	if err != nil {
+		exit(fmt.Errorf("failed to create logger: %w", err))
+	}
+	defer func() { _ = logger.Sync() }()
+
+	// Default The RunId (Except For Receive-Only Runs, Which Then Count The Events Of Any Run)
+	if len(*runId) == 0 && *mode != perf.ModeReceive {
+		*runId = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	// Open The Output
+	var output io.Writer = os.Stdout
+	if len(*outputPath) > 0 {
+		file, err := os.Create(*outputPath)
+		if err != nil {
+			exit(fmt.Errorf("failed to create output file: %w", err))
+		}
+		defer func() { _ = file.Close() }()
+		output = file
+	}
+
+	// Run The Load Generator Until Done Or Interrupted
+	_, err = perf.Run(signals.NewContext(), logger, perf.Config{
+		Mode:           *mode,
+		Target:         *target,
+		SinkAddress:    *sinkAddress,
+		RunId:          *runId,
+		Rate:           *rate,
+		Duration:       *duration,
+		Concurrency:    *concurrency,
+		PayloadSize:    *payloadSize,
+		DrainTimeout:   *drainTimeout,
+		ReportInterval: *reportInterval,
+	}, output)
+	if err != nil {
+		exit(err)
+	}
+}
+
+// Report The Specified Error & Exit With A Failure Status
+func exit(err error) {
+	_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
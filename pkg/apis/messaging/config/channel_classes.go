@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+const (
+	// ChannelClassesConfigName is the name of config map for the classes of
+	// KafkaChannels, which bundle the settings of the KafkaChannels selecting them.
+	ChannelClassesConfigName = "config-kafka-channel-classes"
+
+	// ChannelClassesKey is the key in the ConfigMap to get the classes of
+	// KafkaChannels.
+	ChannelClassesKey = "channel-classes"
+
+	// ChannelClassAnnotation is the annotation of a KafkaChannel selecting its class.
+	ChannelClassAnnotation = "kafka.eventing.knative.dev/channel-class"
+)
+
+// NewChannelClassesConfigFromMap creates a ChannelClasses from the supplied Map.
+// An absent (or empty) key results in no classes.
+func NewChannelClassesConfigFromMap(data map[string]string) (*ChannelClasses, error) {
+	cc := &ChannelClasses{}
+
+	value, present := data[ChannelClassesKey]
+	if !present || value == "" {
+		return cc, nil
+	}
+	j, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap's value could not be converted to JSON: %s : %v", err, value)
+	}
+	if err := json.Unmarshal(j, cc); err != nil {
+		return nil, fmt.Errorf("failed to parse the entry: %s", err)
+	}
+
+	// Reject invalid classes, rather than misconfiguring the KafkaChannels selecting them later on
+	for name, class := range cc.Classes {
+		if err := class.validate(); err != nil {
+			return nil, fmt.Errorf("invalid class %s: %s", name, err)
+		}
+	}
+	if cc.DefaultClass != "" && cc.GetClass(cc.DefaultClass) == nil {
+		return nil, fmt.Errorf("invalid defaultClass: class %s does not exist", cc.DefaultClass)
+	}
+	return cc, nil
+}
+
+// NewChannelClassesConfigFromConfigMap creates a ChannelClasses from the supplied configMap
+func NewChannelClassesConfigFromConfigMap(config *corev1.ConfigMap) (*ChannelClasses, error) {
+	return NewChannelClassesConfigFromMap(config.Data)
+}
+
+// ChannelClasses includes the classes (e.g. "gold", "silver" & "bronze") which KafkaChannels
+// select with their ChannelClassAnnotation, resolved by the webhook and the controller.
+type ChannelClasses struct {
+	// Classes are the bundles of settings of each class. The class name is the key.
+	Classes map[string]*ChannelClass `json:"classes,omitempty"`
+	// DefaultClass is the class of the KafkaChannels which do not select one.
+	DefaultClass string `json:"defaultClass,omitempty"`
+}
+
+// ChannelClass bundles the settings of the KafkaChannels of a class. The NumPartitions,
+// ReplicationFactor & Delivery are defaults of the KafkaChannel's spec, and EventingKafka
+// holds the eventing-kafka settings (in the format of a namespace's overrides) which the
+// distributed channel's controller applies to the KafkaChannels of the class.
+type ChannelClass struct {
+	NumPartitions     int32                        `json:"numPartitions,omitempty"`
+	ReplicationFactor int16                        `json:"replicationFactor,omitempty"`
+	Delivery          *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+	EventingKafka     json.RawMessage              `json:"eventingKafka,omitempty"`
+}
+
+// validate ensures the settings of the ChannelClass (which may be nil) are valid.
+func (c *ChannelClass) validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.NumPartitions < 0 {
+		return fmt.Errorf("invalid value: %d: numPartitions", c.NumPartitions)
+	}
+	if c.ReplicationFactor < 0 {
+		return fmt.Errorf("invalid value: %d: replicationFactor", c.ReplicationFactor)
+	}
+	if err := c.Delivery.Validate(context.Background()); err != nil {
+		return fmt.Errorf("invalid delivery: %s", err)
+	}
+	if len(c.EventingKafka) > 0 {
+		eventingKafka := map[string]interface{}{}
+		if err := json.Unmarshal(c.EventingKafka, &eventingKafka); err != nil {
+			return fmt.Errorf("invalid eventingKafka: %s", err)
+		}
+	}
+	return nil
+}
+
+// ClassName returns the name of the class selected by the specified KafkaChannel annotations,
+// and if there is none, the default class (empty if neither exists).
+func (c *ChannelClasses) ClassName(annotations map[string]string) string {
+	if name, present := annotations[ChannelClassAnnotation]; present {
+		return name
+	}
+	if c == nil {
+		return ""
+	}
+	return c.DefaultClass
+}
+
+// GetClass returns the class with the specified name (nil if it doesn't exist).
+func (c *ChannelClasses) GetClass(name string) *ChannelClass {
+	if c == nil {
+		return nil
+	}
+	return c.Classes[name]
+}
+
+// DeepCopy copies the receiver, creating a new ChannelClasses.
+func (c *ChannelClasses) DeepCopy() *ChannelClasses {
+	if c == nil {
+		return nil
+	}
+	out := &ChannelClasses{
+		DefaultClass: c.DefaultClass,
+	}
+	if c.Classes != nil {
+		out.Classes = make(map[string]*ChannelClass, len(c.Classes))
+		for name, class := range c.Classes {
+			out.Classes[name] = class.DeepCopy()
+		}
+	}
+	return out
+}
+
+// DeepCopy copies the receiver, creating a new ChannelClass.
+func (c *ChannelClass) DeepCopy() *ChannelClass {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	out.Delivery = c.Delivery.DeepCopy()
+	if c.EventingKafka != nil {
+		out.EventingKafka = append(json.RawMessage{}, c.EventingKafka...)
+	}
+	return &out
+}
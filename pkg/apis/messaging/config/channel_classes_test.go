@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestNewChannelClassesConfigFromMap(t *testing.T) {
+	testCases := map[string]struct {
+		data    map[string]string
+		want    *ChannelClasses
+		wantErr string
+	}{
+		"missing key": {
+			data: map[string]string{},
+			want: &ChannelClasses{},
+		},
+		"classes and default class": {
+			data: map[string]string{ChannelClassesKey: `
+defaultClass: bronze
+classes:
+  gold:
+    numPartitions: 12
+    replicationFactor: 3
+    delivery:
+      retry: 5
+    eventingKafka:
+      dispatcher:
+        replicas: 3
+  bronze:
+    numPartitions: 1
+`},
+			want: &ChannelClasses{
+				DefaultClass: "bronze",
+				Classes: map[string]*ChannelClass{
+					"gold": {
+						NumPartitions:     12,
+						ReplicationFactor: 3,
+						Delivery:          &eventingduckv1.DeliverySpec{Retry: ptr.Int32(5)},
+						EventingKafka:     json.RawMessage(`{"dispatcher":{"replicas":3}}`),
+					},
+					"bronze": {NumPartitions: 1},
+				},
+			},
+		},
+		"invalid yaml": {
+			data:    map[string]string{ChannelClassesKey: "classes: [:"},
+			wantErr: "ConfigMap's value could not be converted to JSON",
+		},
+		"invalid partitions": {
+			data:    map[string]string{ChannelClassesKey: "classes: {gold: {numPartitions: -1}}"},
+			wantErr: "invalid class gold: invalid value: -1: numPartitions",
+		},
+		"invalid replication factor": {
+			data:    map[string]string{ChannelClassesKey: "classes: {gold: {replicationFactor: -1}}"},
+			wantErr: "invalid class gold: invalid value: -1: replicationFactor",
+		},
+		"invalid delivery": {
+			data:    map[string]string{ChannelClassesKey: "classes: {gold: {delivery: {retry: -1}}}"},
+			wantErr: "invalid class gold: invalid delivery",
+		},
+		"invalid eventing-kafka settings": {
+			data:    map[string]string{ChannelClassesKey: "classes: {gold: {eventingKafka: [replicas]}}"},
+			wantErr: "invalid class gold: invalid eventingKafka",
+		},
+		"unknown default class": {
+			data:    map[string]string{ChannelClassesKey: "defaultClass: silver\nclasses: {gold: {numPartitions: 12}}"},
+			wantErr: "invalid defaultClass: class silver does not exist",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewChannelClassesConfigFromConfigMap(&corev1.ConfigMap{Data: tc.data})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("Unexpected classes (-want, +got):", diff)
+			}
+			if diff := cmp.Diff(got, got.DeepCopy()); diff != "" {
+				t.Error("Unexpected deep copy (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestChannelClassesGetClass(t *testing.T) {
+	gold := &ChannelClass{NumPartitions: 12}
+	bronze := &ChannelClass{NumPartitions: 1}
+	classes := &ChannelClasses{
+		DefaultClass: "bronze",
+		Classes:      map[string]*ChannelClass{"gold": gold, "bronze": bronze},
+	}
+
+	if got := classes.GetClass(classes.ClassName(map[string]string{ChannelClassAnnotation: "gold"})); got != gold {
+		t.Errorf("Expected selected class %v, got %v", gold, got)
+	}
+	if got := classes.GetClass(classes.ClassName(nil)); got != bronze {
+		t.Errorf("Expected default class %v, got %v", bronze, got)
+	}
+	if got := classes.GetClass("silver"); got != nil {
+		t.Errorf("Expected no class, got %v", got)
+	}
+	if got := FromContextOrDefaults(context.Background()).ChannelClasses.ClassName(nil); got != "" {
+		t.Errorf("Expected no class without config, got %q", got)
+	}
+}
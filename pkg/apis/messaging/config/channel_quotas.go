@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ChannelQuotasConfigName is the name of config map for the quotas limiting
+	// the KafkaChannels of each namespace.
+	ChannelQuotasConfigName = "config-kafka-channel-quotas"
+
+	// ChannelQuotasKey is the key in the ConfigMap to get the quotas limiting
+	// the KafkaChannels of each namespace.
+	ChannelQuotasKey = "channel-quotas"
+)
+
+// NewChannelQuotasConfigFromMap creates a ChannelQuotas from the supplied Map.
+// An absent (or empty) key results in no quotas.
+func NewChannelQuotasConfigFromMap(data map[string]string) (*ChannelQuotas, error) {
+	cq := &ChannelQuotas{}
+
+	value, present := data[ChannelQuotasKey]
+	if !present || value == "" {
+		return cq, nil
+	}
+	j, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap's value could not be converted to JSON: %s : %v", err, value)
+	}
+	if err := json.Unmarshal(j, cq); err != nil {
+		return nil, fmt.Errorf("failed to parse the entry: %s", err)
+	}
+
+	// Reject negative limits, rather than silently treating them as unlimited
+	if err := cq.ClusterDefault.validate(); err != nil {
+		return nil, fmt.Errorf("invalid clusterDefault: %s", err)
+	}
+	for namespace, quota := range cq.NamespaceQuotas {
+		if err := quota.validate(); err != nil {
+			return nil, fmt.Errorf("invalid namespaceQuotas for namespace %s: %s", namespace, err)
+		}
+	}
+	return cq, nil
+}
+
+// NewChannelQuotasConfigFromConfigMap creates a ChannelQuotas from the supplied configMap
+func NewChannelQuotasConfigFromConfigMap(config *corev1.ConfigMap) (*ChannelQuotas, error) {
+	return NewChannelQuotasConfigFromMap(config.Data)
+}
+
+// ChannelQuotas includes the quotas limiting the KafkaChannels which may be created in
+// each namespace, enforced by the webhook.
+type ChannelQuotas struct {
+	// NamespaceQuotas are the quotas of each namespace. namespace is the key, the value
+	// is the ChannelQuota to enforce.
+	NamespaceQuotas map[string]*ChannelQuota `json:"namespaceQuotas,omitempty"`
+	// ClusterDefault is the quota of all namespaces that are not in NamespaceQuotas.
+	ClusterDefault *ChannelQuota `json:"clusterDefault,omitempty"`
+}
+
+// ChannelQuota limits the number of KafkaChannels in a namespace, and the total number of
+// partitions of their topics. A zero limit is unlimited.
+type ChannelQuota struct {
+	MaxChannels   int   `json:"maxChannels,omitempty"`
+	MaxPartitions int64 `json:"maxPartitions,omitempty"`
+}
+
+// validate ensures the limits of the ChannelQuota (which may be nil) are not negative.
+func (q *ChannelQuota) validate() error {
+	if q == nil {
+		return nil
+	}
+	if q.MaxChannels < 0 {
+		return fmt.Errorf("invalid value: %d: maxChannels", q.MaxChannels)
+	}
+	if q.MaxPartitions < 0 {
+		return fmt.Errorf("invalid value: %d: maxPartitions", q.MaxPartitions)
+	}
+	return nil
+}
+
+// GetQuota returns the namespace specific quota, and if that doesn't exist, the
+// cluster default quota (nil if neither exists).
+func (q *ChannelQuotas) GetQuota(ns string) *ChannelQuota {
+	if q == nil {
+		return nil
+	}
+	if value, present := q.NamespaceQuotas[ns]; present {
+		return value
+	}
+	return q.ClusterDefault
+}
+
+// DeepCopy copies the receiver, creating a new ChannelQuotas.
+func (q *ChannelQuotas) DeepCopy() *ChannelQuotas {
+	if q == nil {
+		return nil
+	}
+	out := &ChannelQuotas{
+		ClusterDefault: q.ClusterDefault.DeepCopy(),
+	}
+	if q.NamespaceQuotas != nil {
+		out.NamespaceQuotas = make(map[string]*ChannelQuota, len(q.NamespaceQuotas))
+		for namespace, quota := range q.NamespaceQuotas {
+			out.NamespaceQuotas[namespace] = quota.DeepCopy()
+		}
+	}
+	return out
+}
+
+// DeepCopy copies the receiver, creating a new ChannelQuota.
+func (q *ChannelQuota) DeepCopy() *ChannelQuota {
+	if q == nil {
+		return nil
+	}
+	out := *q
+	return &out
+}
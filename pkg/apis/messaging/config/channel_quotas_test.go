@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNewChannelQuotasConfigFromMap(t *testing.T) {
+	testCases := map[string]struct {
+		data    map[string]string
+		want    *ChannelQuotas
+		wantErr string
+	}{
+		"missing key": {
+			data: map[string]string{},
+			want: &ChannelQuotas{},
+		},
+		"cluster and namespace quotas": {
+			data: map[string]string{ChannelQuotasKey: `
+clusterDefault:
+  maxChannels: 10
+  maxPartitions: 100
+namespaceQuotas:
+  some-namespace:
+    maxChannels: 50
+`},
+			want: &ChannelQuotas{
+				ClusterDefault: &ChannelQuota{MaxChannels: 10, MaxPartitions: 100},
+				NamespaceQuotas: map[string]*ChannelQuota{
+					"some-namespace": {MaxChannels: 50},
+				},
+			},
+		},
+		"invalid yaml": {
+			data:    map[string]string{ChannelQuotasKey: "clusterDefault: [:"},
+			wantErr: "ConfigMap's value could not be converted to JSON",
+		},
+		"invalid cluster default": {
+			data:    map[string]string{ChannelQuotasKey: "clusterDefault: {maxChannels: -1}"},
+			wantErr: "invalid clusterDefault: invalid value: -1: maxChannels",
+		},
+		"invalid namespace quota": {
+			data:    map[string]string{ChannelQuotasKey: "namespaceQuotas: {some-namespace: {maxPartitions: -1}}"},
+			wantErr: "invalid namespaceQuotas for namespace some-namespace: invalid value: -1: maxPartitions",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewChannelQuotasConfigFromConfigMap(&corev1.ConfigMap{Data: tc.data})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("Unexpected quotas (-want, +got):", diff)
+			}
+			if diff := cmp.Diff(got, got.DeepCopy()); diff != "" {
+				t.Error("Unexpected deep copy (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestChannelQuotasGetQuota(t *testing.T) {
+	clusterDefault := &ChannelQuota{MaxChannels: 10}
+	namespaceQuota := &ChannelQuota{MaxChannels: 50}
+	quotas := &ChannelQuotas{
+		ClusterDefault:  clusterDefault,
+		NamespaceQuotas: map[string]*ChannelQuota{"some-namespace": namespaceQuota},
+	}
+
+	if got := quotas.GetQuota("some-namespace"); got != namespaceQuota {
+		t.Errorf("Expected namespace quota %v, got %v", namespaceQuota, got)
+	}
+	if got := quotas.GetQuota("other-namespace"); got != clusterDefault {
+		t.Errorf("Expected cluster default %v, got %v", clusterDefault, got)
+	}
+	if got := (&ChannelQuotas{}).GetQuota("other-namespace"); got != nil {
+		t.Errorf("Expected no quota, got %v", got)
+	}
+	if got := FromContextOrDefaults(context.Background()).ChannelQuotas.GetQuota("some-namespace"); got != nil {
+		t.Errorf("Expected no quota without config, got %v", got)
+	}
+}
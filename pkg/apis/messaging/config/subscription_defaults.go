@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+const (
+	// SubscriptionDefaultsConfigName is the name of config map for the default
+	// delivery of the Subscriptions to KafkaChannels.
+	SubscriptionDefaultsConfigName = "config-kafka-subscription-defaults"
+
+	// SubscriptionDefaultsKey is the key in the ConfigMap to get the default
+	// delivery of the Subscriptions to KafkaChannels.
+	SubscriptionDefaultsKey = "default-delivery"
+)
+
+// NewSubscriptionDefaultsConfigFromMap creates a SubscriptionDefaults from the supplied Map.
+// An absent (or empty) key results in no default delivery.
+func NewSubscriptionDefaultsConfigFromMap(data map[string]string) (*SubscriptionDefaults, error) {
+	sd := &SubscriptionDefaults{}
+
+	value, present := data[SubscriptionDefaultsKey]
+	if !present || value == "" {
+		return sd, nil
+	}
+	j, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap's value could not be converted to JSON: %s : %v", err, value)
+	}
+	if err := json.Unmarshal(j, sd); err != nil {
+		return nil, fmt.Errorf("failed to parse the entry: %s", err)
+	}
+
+	// Reject invalid delivery specs, rather than rejecting the defaulted Subscriptions later on
+	if err := sd.ClusterDefault.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid clusterDefault: %s", err)
+	}
+	for namespace, delivery := range sd.NamespaceDefaults {
+		if err := delivery.Validate(context.Background()); err != nil {
+			return nil, fmt.Errorf("invalid namespaceDefaults for namespace %s: %s", namespace, err)
+		}
+	}
+	return sd, nil
+}
+
+// NewSubscriptionDefaultsConfigFromConfigMap creates a SubscriptionDefaults from the supplied configMap
+func NewSubscriptionDefaultsConfigFromConfigMap(config *corev1.ConfigMap) (*SubscriptionDefaults, error) {
+	return NewSubscriptionDefaultsConfigFromMap(config.Data)
+}
+
+// SubscriptionDefaults includes the default delivery of the Subscriptions to KafkaChannels
+// which do not specify any delivery, populated by the webhook.
+type SubscriptionDefaults struct {
+	// NamespaceDefaults are the default delivery specs for each namespace. namespace is the
+	// key, the value is the default DeliverySpec to use.
+	NamespaceDefaults map[string]*eventingduckv1.DeliverySpec `json:"namespaceDefaults,omitempty"`
+	// ClusterDefault is the default delivery spec for all namespaces that are not in
+	// NamespaceDefaults.
+	ClusterDefault *eventingduckv1.DeliverySpec `json:"clusterDefault,omitempty"`
+}
+
+// GetDelivery returns the namespace specific default delivery, and if that doesn't
+// exist, the cluster default delivery (nil if neither exists).
+func (d *SubscriptionDefaults) GetDelivery(ns string) *eventingduckv1.DeliverySpec {
+	if d == nil {
+		return nil
+	}
+	if value, present := d.NamespaceDefaults[ns]; present {
+		return value
+	}
+	return d.ClusterDefault
+}
+
+// DeepCopy copies the receiver, creating a new SubscriptionDefaults.
+func (d *SubscriptionDefaults) DeepCopy() *SubscriptionDefaults {
+	if d == nil {
+		return nil
+	}
+	out := &SubscriptionDefaults{
+		ClusterDefault: d.ClusterDefault.DeepCopy(),
+	}
+	if d.NamespaceDefaults != nil {
+		out.NamespaceDefaults = make(map[string]*eventingduckv1.DeliverySpec, len(d.NamespaceDefaults))
+		for namespace, delivery := range d.NamespaceDefaults {
+			out.NamespaceDefaults[namespace] = delivery.DeepCopy()
+		}
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestNewSubscriptionDefaultsConfigFromMap(t *testing.T) {
+	exponential := eventingduckv1.BackoffPolicyExponential
+	testCases := map[string]struct {
+		data    map[string]string
+		want    *SubscriptionDefaults
+		wantErr string
+	}{
+		"missing key": {
+			data: map[string]string{},
+			want: &SubscriptionDefaults{},
+		},
+		"cluster and namespace defaults": {
+			data: map[string]string{SubscriptionDefaultsKey: `
+clusterDefault:
+  retry: 5
+  backoffPolicy: exponential
+  backoffDelay: PT0.5S
+  deadLetterSink:
+    uri: http://dead-letter-sink
+namespaceDefaults:
+  some-namespace:
+    retry: 1
+`},
+			want: &SubscriptionDefaults{
+				ClusterDefault: &eventingduckv1.DeliverySpec{
+					Retry:          ptr.Int32(5),
+					BackoffPolicy:  &exponential,
+					BackoffDelay:   ptr.String("PT0.5S"),
+					DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter-sink")},
+				},
+				NamespaceDefaults: map[string]*eventingduckv1.DeliverySpec{
+					"some-namespace": {Retry: ptr.Int32(1)},
+				},
+			},
+		},
+		"invalid yaml": {
+			data:    map[string]string{SubscriptionDefaultsKey: "clusterDefault: [:"},
+			wantErr: "ConfigMap's value could not be converted to JSON",
+		},
+		"invalid cluster default": {
+			data:    map[string]string{SubscriptionDefaultsKey: "clusterDefault: {retry: -1}"},
+			wantErr: "invalid clusterDefault: invalid value: -1: retry",
+		},
+		"invalid namespace default": {
+			data:    map[string]string{SubscriptionDefaultsKey: "namespaceDefaults: {some-namespace: {backoffPolicy: random}}"},
+			wantErr: "invalid namespaceDefaults for namespace some-namespace: invalid value: random: backoffPolicy",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewSubscriptionDefaultsConfigFromConfigMap(&corev1.ConfigMap{Data: tc.data})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("Unexpected defaults (-want, +got):", diff)
+			}
+			if diff := cmp.Diff(got, got.DeepCopy()); diff != "" {
+				t.Error("Unexpected deep copy (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestSubscriptionDefaultsGetDelivery(t *testing.T) {
+	clusterDefault := &eventingduckv1.DeliverySpec{Retry: ptr.Int32(5)}
+	namespaceDefault := &eventingduckv1.DeliverySpec{Retry: ptr.Int32(1)}
+	defaults := &SubscriptionDefaults{
+		ClusterDefault:    clusterDefault,
+		NamespaceDefaults: map[string]*eventingduckv1.DeliverySpec{"some-namespace": namespaceDefault},
+	}
+
+	if got := defaults.GetDelivery("some-namespace"); got != namespaceDefault {
+		t.Errorf("Expected namespace default %v, got %v", namespaceDefault, got)
+	}
+	if got := defaults.GetDelivery("other-namespace"); got != clusterDefault {
+		t.Errorf("Expected cluster default %v, got %v", clusterDefault, got)
+	}
+	if got := (&SubscriptionDefaults{}).GetDelivery("other-namespace"); got != nil {
+		t.Errorf("Expected no default, got %v", got)
+	}
+	if got := FromContextOrDefaults(context.Background()).SubscriptionDefaults.GetDelivery("some-namespace"); got != nil {
+		t.Errorf("Expected no default without config, got %v", got)
+	}
+}
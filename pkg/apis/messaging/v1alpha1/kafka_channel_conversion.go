@@ -105,6 +105,7 @@ func (source *KafkaChannel) ConvertTo(ctx context.Context, obj apis.Convertible)
 				// no DeadLetterChannel in v1alpha1
 				DeadLetterChannel: nil,
 			},
+			Topic: source.Status.Topic,
 		}
 
 		return nil
@@ -183,6 +184,7 @@ func (sink *KafkaChannel) ConvertFrom(ctx context.Context, obj apis.Convertible)
 			SubscribableTypeStatus: eventingduckv1alpha1.SubscribableTypeStatus{
 				SubscribableStatus: subscribableStatus,
 			},
+			Topic: source.Status.Topic,
 		}
 
 		return nil
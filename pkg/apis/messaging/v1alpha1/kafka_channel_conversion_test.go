@@ -149,6 +149,7 @@ func TestKafkaChannelConversionRoundTripV1alpha1(t *testing.T) {
 						},
 					},
 				},
+				Topic: "status-topic",
 			},
 		},
 	}}
@@ -292,6 +293,7 @@ func TestKafkaChannelConversionRoundTripV1beta1(t *testing.T) {
 					//	APIVersion: "status-dl-channel-apiversion",
 					//},
 				},
+				Topic: "status-topic",
 			},
 		},
 	}}
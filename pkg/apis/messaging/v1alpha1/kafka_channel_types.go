@@ -79,6 +79,10 @@ type KafkaChannelStatus struct {
 
 	// Subscribers is populated with the statuses of each of the Channelable's subscribers.
 	eventingduck.SubscribableTypeStatus `json:",inline"`
+
+	// Topic is the name of the Kafka topic backing the channel, populated once the topic is ready.
+	// +optional
+	Topic string `json:"topic,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
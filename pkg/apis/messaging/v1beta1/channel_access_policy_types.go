@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ChannelAccessPolicy is a resource allowing the Subscriptions of other namespaces to subscribe to the
+// KafkaChannels of its namespace, e.g. to share an organization's event hub across teams. Subscriptions
+// are only allowed to subscribe to the KafkaChannels of another namespace if a ChannelAccessPolicy of that
+// namespace allows them.
+type ChannelAccessPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the KafkaChannels and the Subscriptions allowed to subscribe to them.
+	Spec ChannelAccessPolicySpec `json:"spec,omitempty"`
+}
+
+var (
+	// Check that this policy can be validated and defaulted.
+	_ apis.Validatable = (*ChannelAccessPolicy)(nil)
+	_ apis.Defaultable = (*ChannelAccessPolicy)(nil)
+
+	_ runtime.Object = (*ChannelAccessPolicy)(nil)
+
+	// Check that we can create OwnerReferences to this policy.
+	_ kmeta.OwnerRefable = (*ChannelAccessPolicy)(nil)
+)
+
+// AllNamespaces is the ChannelAccessPolicySpec AllowedNamespaces value allowing every namespace.
+const AllNamespaces = "*"
+
+// ChannelAccessPolicySpec defines the specification for a ChannelAccessPolicy. A Subscription of another
+// namespace is allowed if it matches every non-empty list of the ChannelAccessPolicy.
+type ChannelAccessPolicySpec struct {
+	// Channels are the names of the KafkaChannels, in the namespace of the ChannelAccessPolicy, to which
+	// access is allowed. Access is allowed to every KafkaChannel of the namespace if empty.
+	// +optional
+	Channels []string `json:"channels,omitempty"`
+
+	// AllowedNamespaces are the namespaces whose Subscriptions are allowed, "*" allowing every namespace.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// AllowedServiceAccounts are the service accounts, as <namespace>/<name>, whose Subscriptions (those
+	// which they created, as recorded by the messaging.knative.dev/creator annotation) are allowed.
+	// +optional
+	AllowedServiceAccounts []string `json:"allowedServiceAccounts,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ChannelAccessPolicyList is a collection of ChannelAccessPolicies.
+type ChannelAccessPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChannelAccessPolicy `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for ChannelAccessPolicies
+func (p *ChannelAccessPolicy) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("ChannelAccessPolicy")
+}
+
+// ServiceAccountUsernamePrefix is the prefix of the Kubernetes usernames of service accounts
+// (system:serviceaccount:<namespace>:<name>), which are recorded as the creators of Subscriptions.
+const ServiceAccountUsernamePrefix = "system:serviceaccount:"
+
+// AppliesTo determines whether the ChannelAccessPolicy allows access to the named KafkaChannel of its namespace.
+func (p *ChannelAccessPolicy) AppliesTo(channelName string) bool {
+	return len(p.Spec.Channels) == 0 || containsString(p.Spec.Channels, channelName)
+}
+
+// Allows determines whether the ChannelAccessPolicy allows a Subscription of the specified namespace, created by the
+// specified Kubernetes user, to subscribe.
+func (p *ChannelAccessPolicy) Allows(namespace string, creator string) bool {
+	if len(p.Spec.AllowedNamespaces) == 0 && len(p.Spec.AllowedServiceAccounts) == 0 {
+		return false
+	}
+	if len(p.Spec.AllowedNamespaces) > 0 && !containsString(p.Spec.AllowedNamespaces, AllNamespaces) && !containsString(p.Spec.AllowedNamespaces, namespace) {
+		return false
+	}
+	if len(p.Spec.AllowedServiceAccounts) > 0 {
+		serviceAccount := strings.Replace(strings.TrimPrefix(creator, ServiceAccountUsernamePrefix), ":", "/", 1)
+		if !strings.HasPrefix(creator, ServiceAccountUsernamePrefix) || !containsString(p.Spec.AllowedServiceAccounts, serviceAccount) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString determines whether the specified slice contains the specified string.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+)
+
+func TestChannelAccessPolicy_GetGroupVersionKind(t *testing.T) {
+	p := ChannelAccessPolicy{}
+	gvk := p.GetGroupVersionKind()
+
+	if gvk.Kind != "ChannelAccessPolicy" {
+		t.Errorf("Should be 'ChannelAccessPolicy'.")
+	}
+}
+
+func TestChannelAccessPolicyAppliesTo(t *testing.T) {
+	p := ChannelAccessPolicy{}
+	if !p.AppliesTo("test-channel") {
+		t.Errorf("A policy without channels should apply to every channel")
+	}
+
+	p.Spec.Channels = []string{"test-channel"}
+	if !p.AppliesTo("test-channel") || p.AppliesTo("other-channel") {
+		t.Errorf("A policy with channels should only apply to those channels")
+	}
+}
+
+func TestChannelAccessPolicyAllows(t *testing.T) {
+	const deployer = "system:serviceaccount:team-a:deployer"
+
+	testCases := map[string]struct {
+		spec      ChannelAccessPolicySpec
+		namespace string
+		creator   string
+		want      bool
+	}{
+		"empty spec": {
+			namespace: "team-a",
+			creator:   deployer,
+			want:      false,
+		},
+		"allowed namespace": {
+			spec:      ChannelAccessPolicySpec{AllowedNamespaces: []string{"team-a"}},
+			namespace: "team-a",
+			want:      true,
+		},
+		"other namespace": {
+			spec:      ChannelAccessPolicySpec{AllowedNamespaces: []string{"team-a"}},
+			namespace: "team-b",
+			want:      false,
+		},
+		"all namespaces": {
+			spec:      ChannelAccessPolicySpec{AllowedNamespaces: []string{AllNamespaces}},
+			namespace: "team-b",
+			want:      true,
+		},
+		"allowed service account": {
+			spec:      ChannelAccessPolicySpec{AllowedServiceAccounts: []string{"team-a/deployer"}},
+			namespace: "team-a",
+			creator:   deployer,
+			want:      true,
+		},
+		"other service account": {
+			spec:      ChannelAccessPolicySpec{AllowedServiceAccounts: []string{"team-a/admin"}},
+			namespace: "team-a",
+			creator:   deployer,
+			want:      false,
+		},
+		"user creator": {
+			spec:      ChannelAccessPolicySpec{AllowedServiceAccounts: []string{"team-a/deployer"}},
+			namespace: "team-a",
+			creator:   "team-a/deployer",
+			want:      false,
+		},
+		"allowed service account of other namespace": {
+			spec: ChannelAccessPolicySpec{
+				AllowedNamespaces:      []string{"team-b"},
+				AllowedServiceAccounts: []string{"team-a/deployer"},
+			},
+			namespace: "team-a",
+			creator:   deployer,
+			want:      false,
+		},
+	}
+
+	for n, test := range testCases {
+		t.Run(n, func(t *testing.T) {
+			p := ChannelAccessPolicy{Spec: test.spec}
+			if got := p.Allows(test.namespace, test.creator); got != test.want {
+				t.Errorf("Allows(%q, %q) = %v, want %v", test.namespace, test.creator, got, test.want)
+			}
+		})
+	}
+}
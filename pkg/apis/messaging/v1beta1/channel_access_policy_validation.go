@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+)
+
+func (p *ChannelAccessPolicy) Validate(ctx context.Context) *apis.FieldError {
+	return p.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (ps *ChannelAccessPolicySpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	for i, channel := range ps.Channels {
+		if len(validation.IsDNS1123Subdomain(channel)) > 0 {
+			errs = errs.Also(apis.ErrInvalidArrayValue(channel, "channels", i))
+		}
+	}
+
+	// A policy without any allowed namespaces or service accounts would allow every Subscription.
+	if len(ps.AllowedNamespaces) == 0 && len(ps.AllowedServiceAccounts) == 0 {
+		errs = errs.Also(apis.ErrMissingOneOf("allowedNamespaces", "allowedServiceAccounts"))
+	}
+
+	for i, namespace := range ps.AllowedNamespaces {
+		if namespace != AllNamespaces && len(validation.IsDNS1123Label(namespace)) > 0 {
+			errs = errs.Also(apis.ErrInvalidArrayValue(namespace, "allowedNamespaces", i))
+		}
+	}
+
+	for i, serviceAccount := range ps.AllowedServiceAccounts {
+		parts := strings.Split(serviceAccount, "/")
+		if len(parts) != 2 || len(validation.IsDNS1123Label(parts[0])) > 0 || len(validation.IsDNS1123Subdomain(parts[1])) > 0 {
+			fe := apis.ErrInvalidArrayValue(serviceAccount, "allowedServiceAccounts", i)
+			fe.Details = "expected <namespace>/<name>"
+			errs = errs.Also(fe)
+		}
+	}
+
+	return errs
+}
+
+// SetDefaults implements apis.Defaultable (a ChannelAccessPolicy has no defaults).
+func (p *ChannelAccessPolicy) SetDefaults(ctx context.Context) {}
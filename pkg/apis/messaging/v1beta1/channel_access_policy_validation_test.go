@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+)
+
+func TestChannelAccessPolicyValidation(t *testing.T) {
+	testCases := map[string]struct {
+		cr   *ChannelAccessPolicy
+		want *apis.FieldError
+	}{
+		"empty spec": {
+			cr:   &ChannelAccessPolicy{},
+			want: apis.ErrMissingOneOf("spec.allowedNamespaces", "spec.allowedServiceAccounts"),
+		},
+		"valid spec": {
+			cr: &ChannelAccessPolicy{
+				Spec: ChannelAccessPolicySpec{
+					Channels:               []string{"test-channel"},
+					AllowedNamespaces:      []string{"team-a", AllNamespaces},
+					AllowedServiceAccounts: []string{"team-a/deployer"},
+				},
+			},
+			want: nil,
+		},
+		"invalid channel": {
+			cr: &ChannelAccessPolicy{
+				Spec: ChannelAccessPolicySpec{
+					Channels:          []string{"Test_Channel"},
+					AllowedNamespaces: []string{"team-a"},
+				},
+			},
+			want: apis.ErrInvalidArrayValue("Test_Channel", "spec.channels", 0),
+		},
+		"invalid namespace": {
+			cr: &ChannelAccessPolicy{
+				Spec: ChannelAccessPolicySpec{
+					AllowedNamespaces: []string{"team-a", "team.b"},
+				},
+			},
+			want: apis.ErrInvalidArrayValue("team.b", "spec.allowedNamespaces", 1),
+		},
+		"invalid service account": {
+			cr: &ChannelAccessPolicy{
+				Spec: ChannelAccessPolicySpec{
+					AllowedServiceAccounts: []string{"deployer"},
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidArrayValue("deployer", "spec.allowedServiceAccounts", 0)
+				fe.Details = "expected <namespace>/<name>"
+				return fe
+			}(),
+		},
+	}
+
+	for n, test := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := test.cr.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", n, diff)
+			}
+		})
+	}
+}
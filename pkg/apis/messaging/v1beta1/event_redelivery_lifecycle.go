@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+var redeliveryCondSet = apis.NewBatchConditionSet(
+	EventRedeliveryConditionChannelReady,
+	EventRedeliveryConditionRedelivered)
+
+const (
+	// EventRedeliveryConditionSucceeded has status True when all subconditions below have been set to True.
+	EventRedeliveryConditionSucceeded = apis.ConditionSucceeded
+
+	// EventRedeliveryConditionChannelReady has status True when the KafkaChannel exists and quarantines events.
+	EventRedeliveryConditionChannelReady apis.ConditionType = "ChannelReady"
+
+	// EventRedeliveryConditionRedelivered has status True when all of the selected quarantined events have been
+	// redelivered, and Unknown while the redelivery is in progress.
+	EventRedeliveryConditionRedelivered apis.ConditionType = "Redelivered"
+)
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*EventRedelivery) GetConditionSet() apis.ConditionSet {
+	return redeliveryCondSet
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (rs *EventRedeliveryStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return redeliveryCondSet.Manage(rs).GetCondition(t)
+}
+
+// IsSucceeded returns true if all of the selected events have been redelivered.
+func (rs *EventRedeliveryStatus) IsSucceeded() bool {
+	return redeliveryCondSet.Manage(rs).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (rs *EventRedeliveryStatus) InitializeConditions() {
+	redeliveryCondSet.Manage(rs).InitializeConditions()
+}
+
+func (rs *EventRedeliveryStatus) MarkChannelReady() {
+	redeliveryCondSet.Manage(rs).MarkTrue(EventRedeliveryConditionChannelReady)
+}
+
+func (rs *EventRedeliveryStatus) MarkChannelFailed(reason, messageFormat string, messageA ...interface{}) {
+	redeliveryCondSet.Manage(rs).MarkFalse(EventRedeliveryConditionChannelReady, reason, messageFormat, messageA...)
+}
+
+func (rs *EventRedeliveryStatus) MarkRedelivered() {
+	redeliveryCondSet.Manage(rs).MarkTrue(EventRedeliveryConditionRedelivered)
+}
+
+func (rs *EventRedeliveryStatus) MarkRedeliveryInProgress(reason, messageFormat string, messageA ...interface{}) {
+	redeliveryCondSet.Manage(rs).MarkUnknown(EventRedeliveryConditionRedelivered, reason, messageFormat, messageA...)
+}
+
+func (rs *EventRedeliveryStatus) MarkRedeliveryFailed(reason, messageFormat string, messageA ...interface{}) {
+	redeliveryCondSet.Manage(rs).MarkFalse(EventRedeliveryConditionRedelivered, reason, messageFormat, messageA...)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEventRedeliveryGetConditionSet(t *testing.T) {
+	r := &EventRedelivery{}
+	assert.Equal(t, redeliveryCondSet, r.GetConditionSet())
+}
+
+func TestEventRedeliveryInitializeConditions(t *testing.T) {
+	rs := &EventRedeliveryStatus{}
+	rs.InitializeConditions()
+	assert.Equal(t, corev1.ConditionUnknown, rs.GetCondition(EventRedeliveryConditionSucceeded).Status)
+	assert.Equal(t, corev1.ConditionUnknown, rs.GetCondition(EventRedeliveryConditionChannelReady).Status)
+	assert.Equal(t, corev1.ConditionUnknown, rs.GetCondition(EventRedeliveryConditionRedelivered).Status)
+	assert.False(t, rs.IsSucceeded())
+}
+
+func TestEventRedeliveryIsSucceeded(t *testing.T) {
+	rs := &EventRedeliveryStatus{}
+	rs.InitializeConditions()
+
+	rs.MarkChannelReady()
+	rs.MarkRedeliveryInProgress("Redelivering", "%d of %d partitions redelivered", 1, 2)
+	assert.False(t, rs.IsSucceeded())
+	assert.Equal(t, "1 of 2 partitions redelivered", rs.GetCondition(EventRedeliveryConditionRedelivered).Message)
+
+	rs.MarkRedelivered()
+	assert.True(t, rs.IsSucceeded())
+
+	rs.MarkRedeliveryFailed("RedeliveryFailed", "test error")
+	assert.False(t, rs.IsSucceeded())
+	assert.Equal(t, corev1.ConditionFalse, rs.GetCondition(EventRedeliveryConditionSucceeded).Status)
+
+	rs.MarkRedelivered()
+	rs.MarkChannelFailed("ChannelNotFound", "test error")
+	assert.False(t, rs.IsSucceeded())
+	assert.Equal(t, "ChannelNotFound", rs.GetCondition(EventRedeliveryConditionSucceeded).Reason)
+}
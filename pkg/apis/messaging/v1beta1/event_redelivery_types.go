@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventRedelivery is a resource requesting that the events quarantined by a KafkaChannel are re-injected
+// into the KafkaChannel, typically once the cause of their failed delivery has been fixed.
+type EventRedelivery struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the KafkaChannel and the quarantined events to redeliver.
+	Spec EventRedeliverySpec `json:"spec,omitempty"`
+
+	// Status represents the progress of the EventRedelivery. This data may be out of date.
+	// +optional
+	Status EventRedeliveryStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that this redelivery can be validated and defaulted.
+	_ apis.Validatable = (*EventRedelivery)(nil)
+	_ apis.Defaultable = (*EventRedelivery)(nil)
+
+	_ runtime.Object = (*EventRedelivery)(nil)
+
+	// Check that we can create OwnerReferences to this redelivery.
+	_ kmeta.OwnerRefable = (*EventRedelivery)(nil)
+
+	// Check that the type conforms to the duck Knative Resource shape.
+	_ duckv1.KRShaped = (*EventRedelivery)(nil)
+)
+
+// The CloudEvent extensions with which redelivered events are annotated for auditing.
+const (
+	// RedeliveryExtension is the namespace/name of the EventRedelivery which redelivered the event.
+	RedeliveryExtension = "knativeredelivery"
+	// RedeliveredAtExtension is the RFC 3339 time at which the event was redelivered.
+	RedeliveredAtExtension = "knativeredeliveredat"
+)
+
+// EventRedeliverySpec defines the specification for an EventRedelivery.
+type EventRedeliverySpec struct {
+	// Channel is the name of the KafkaChannel, in the namespace of the EventRedelivery, whose quarantined
+	// events are redelivered. The events are re-injected into the KafkaChannel and therefore delivered to
+	// all of its subscribers.
+	Channel string `json:"channel"`
+
+	// Filter selects the quarantined events to redeliver. All quarantined events are redelivered if empty.
+	// +optional
+	Filter EventRedeliveryFilter `json:"filter,omitempty"`
+}
+
+// EventRedeliveryFilter selects quarantined events. An event must match every specified criterion.
+type EventRedeliveryFilter struct {
+	// IDs are the CloudEvent ids of the events to redeliver.
+	// +optional
+	IDs []string `json:"ids,omitempty"`
+
+	// Types are the CloudEvent types of the events to redeliver.
+	// +optional
+	Types []string `json:"types,omitempty"`
+
+	// Since excludes the events quarantined before this time.
+	// +optional
+	Since *metav1.Time `json:"since,omitempty"`
+
+	// Until excludes the events quarantined after this time.
+	// +optional
+	Until *metav1.Time `json:"until,omitempty"`
+}
+
+// EventRedeliveryStatus represents the current state of an EventRedelivery.
+type EventRedeliveryStatus struct {
+	// inherits duck/v1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the EventRedelivery that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1.Status `json:",inline"`
+
+	// Partitions are the quarantine topic's partitions, whose events up to the end offsets at the start of
+	// the redelivery are redelivered.
+	// +optional
+	Partitions []EventRedeliveryPartition `json:"partitions,omitempty"`
+
+	// ScannedEvents is the number of quarantined events examined.
+	// +optional
+	ScannedEvents int64 `json:"scannedEvents,omitempty"`
+
+	// RedeliveredEvents is the number of quarantined events matching the filter which were redelivered.
+	// +optional
+	RedeliveredEvents int64 `json:"redeliveredEvents,omitempty"`
+
+	// CompletionTime is the time at which all of the selected events were redelivered.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// EventRedeliveryPartition is the redelivery progress of a single partition of the quarantine topic.
+type EventRedeliveryPartition struct {
+	// Partition is the partition of the quarantine topic.
+	Partition int32 `json:"partition"`
+
+	// Offset is the offset of the next event to examine.
+	Offset int64 `json:"offset"`
+
+	// EndOffset is the offset (exclusive) up to which events are examined.
+	EndOffset int64 `json:"endOffset"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventRedeliveryList is a collection of EventRedeliveries.
+type EventRedeliveryList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EventRedelivery `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for EventRedeliveries
+func (r *EventRedelivery) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("EventRedelivery")
+}
+
+// GetStatus retrieves the duck status for this resource. Implements the KRShaped interface.
+func (r *EventRedelivery) GetStatus() *duckv1.Status {
+	return &r.Status.Status
+}
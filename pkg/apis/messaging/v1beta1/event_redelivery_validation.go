@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+func (r *EventRedelivery) Validate(ctx context.Context) *apis.FieldError {
+	return r.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (rs *EventRedeliverySpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if rs.Channel == "" {
+		errs = errs.Also(apis.ErrMissingField("channel"))
+	}
+
+	if rs.Filter.Since != nil && rs.Filter.Until != nil && rs.Filter.Until.Before(rs.Filter.Since) {
+		fe := apis.ErrInvalidValue(rs.Filter.Until, "until")
+		fe.Details = "expected until to be after since"
+		errs = errs.Also(fe.ViaField("filter"))
+	}
+
+	return errs
+}
+
+// SetDefaults implements apis.Defaultable (an EventRedelivery has no defaults).
+func (r *EventRedelivery) SetDefaults(ctx context.Context) {}
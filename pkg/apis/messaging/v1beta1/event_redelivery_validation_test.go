@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestEventRedeliveryValidation(t *testing.T) {
+	since := metav1.NewTime(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC))
+	until := metav1.NewTime(since.Add(time.Hour))
+
+	testCases := map[string]struct {
+		cr   *EventRedelivery
+		want *apis.FieldError
+	}{
+		"empty spec": {
+			cr:   &EventRedelivery{},
+			want: apis.ErrMissingField("spec.channel"),
+		},
+		"valid spec": {
+			cr: &EventRedelivery{
+				Spec: EventRedeliverySpec{
+					Channel: "test-channel",
+					Filter: EventRedeliveryFilter{
+						IDs:   []string{"id-1"},
+						Types: []string{"type-1"},
+						Since: &since,
+						Until: &until,
+					},
+				},
+			},
+			want: nil,
+		},
+		"until before since": {
+			cr: &EventRedelivery{
+				Spec: EventRedeliverySpec{
+					Channel: "test-channel",
+					Filter: EventRedeliveryFilter{
+						Since: &until,
+						Until: &since,
+					},
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue(&since, "spec.filter.until")
+				fe.Details = "expected until to be after since"
+				return fe
+			}(),
+		},
+	}
+
+	for n, test := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := test.cr.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", n, diff)
+			}
+		})
+	}
+}
@@ -19,8 +19,10 @@ package v1beta1
 import (
 	"context"
 
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
 	"knative.dev/eventing-kafka/pkg/common/constants"
 	"knative.dev/eventing/pkg/apis/messaging"
+	"knative.dev/pkg/apis"
 )
 
 func (c *KafkaChannel) SetDefaults(ctx context.Context) {
@@ -36,9 +38,35 @@ func (c *KafkaChannel) SetDefaults(ctx context.Context) {
 		c.Annotations[messaging.SubscribableDuckVersionAnnotation] = "v1"
 	}
 
+	c.setClassDefaults(ctx)
 	c.Spec.SetDefaults(ctx)
 }
 
+// setClassDefaults applies the settings of the KafkaChannel's class (or of the default class) to the unset
+// fields of its spec when it is created, recording the class so that later changes of the default class do
+// not reclassify the KafkaChannel.
+func (c *KafkaChannel) setClassDefaults(ctx context.Context) {
+	if !apis.IsInCreate(ctx) {
+		return
+	}
+	classes := messagingconfig.FromContextOrDefaults(ctx).ChannelClasses
+	name := classes.ClassName(c.Annotations)
+	class := classes.GetClass(name)
+	if class == nil {
+		return
+	}
+	c.Annotations[messagingconfig.ChannelClassAnnotation] = name
+	if c.Spec.NumPartitions == 0 {
+		c.Spec.NumPartitions = class.NumPartitions
+	}
+	if c.Spec.ReplicationFactor == 0 {
+		c.Spec.ReplicationFactor = class.ReplicationFactor
+	}
+	if c.Spec.Delivery == nil {
+		c.Spec.Delivery = class.Delivery.DeepCopy()
+	}
+}
+
 func (cs *KafkaChannelSpec) SetDefaults(ctx context.Context) {
 	if cs.NumPartitions == 0 {
 		cs.NumPartitions = constants.DefaultNumPartitions
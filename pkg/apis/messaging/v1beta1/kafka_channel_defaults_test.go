@@ -19,7 +19,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
 	"knative.dev/eventing-kafka/pkg/common/constants"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
 )
 
 const (
@@ -86,3 +90,113 @@ func TestKafkaChannelDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaChannelClassDefaults(t *testing.T) {
+	goldDelivery := &eventingduck.DeliverySpec{Retry: ptr.Int32(5)}
+	classesCtx := apis.WithinCreate(messagingconfig.ToContext(context.TODO(), &messagingconfig.Config{
+		ChannelClasses: &messagingconfig.ChannelClasses{
+			DefaultClass: "bronze",
+			Classes: map[string]*messagingconfig.ChannelClass{
+				"gold":   {NumPartitions: 12, ReplicationFactor: 3, Delivery: goldDelivery},
+				"bronze": {NumPartitions: 1},
+			},
+		},
+	}))
+
+	testCases := map[string]struct {
+		ctx      context.Context
+		initial  KafkaChannel
+		expected KafkaChannel
+	}{
+		"selected class": {
+			ctx: classesCtx,
+			initial: KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{messagingconfig.ChannelClassAnnotation: "gold"},
+				},
+			},
+			expected: KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1", messagingconfig.ChannelClassAnnotation: "gold"},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     12,
+					ReplicationFactor: 3,
+					ChannelableSpec:   eventingduck.ChannelableSpec{Delivery: goldDelivery},
+				},
+			},
+		},
+		"selected class with spec set": {
+			ctx: classesCtx,
+			initial: KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{messagingconfig.ChannelClassAnnotation: "gold"},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions: testNumPartitions,
+				},
+			},
+			expected: KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1", messagingconfig.ChannelClassAnnotation: "gold"},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     testNumPartitions,
+					ReplicationFactor: 3,
+					ChannelableSpec:   eventingduck.ChannelableSpec{Delivery: goldDelivery},
+				},
+			},
+		},
+		"default class": {
+			ctx:     classesCtx,
+			initial: KafkaChannel{},
+			expected: KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1", messagingconfig.ChannelClassAnnotation: "bronze"},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: constants.DefaultReplicationFactor,
+				},
+			},
+		},
+		"unknown class": {
+			ctx: classesCtx,
+			initial: KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{messagingconfig.ChannelClassAnnotation: "silver"},
+				},
+			},
+			expected: KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1", messagingconfig.ChannelClassAnnotation: "silver"},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     constants.DefaultNumPartitions,
+					ReplicationFactor: constants.DefaultReplicationFactor,
+				},
+			},
+		},
+		"not in create": {
+			ctx:     apis.WithinUpdate(messagingconfig.ToContext(context.TODO(), messagingconfig.FromContext(classesCtx)), &KafkaChannel{}),
+			initial: KafkaChannel{},
+			expected: KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1"},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     constants.DefaultNumPartitions,
+					ReplicationFactor: constants.DefaultReplicationFactor,
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			tc.initial.SetDefaults(tc.ctx)
+			if diff := cmp.Diff(tc.expected, tc.initial); diff != "" {
+				t.Fatalf("Unexpected defaults (-want, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
 	"sync"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -35,6 +36,18 @@ var kc = apis.NewLivingConditionSet(
 	KafkaChannelConditionConfigReady)
 var channelCondSetLock = sync.RWMutex{}
 
+// The order in which the dependent conditions summarize the Ready condition (see SummarizeReady), from the
+// configuration and topic which everything else depends upon to the address which is only set once the rest is.
+var readySummaryOrder = []apis.ConditionType{
+	KafkaChannelConditionConfigReady,
+	KafkaChannelConditionTopicReady,
+	KafkaChannelConditionChannelServiceReady,
+	KafkaChannelConditionServiceReady,
+	KafkaChannelConditionEndpointsReady,
+	KafkaChannelConditionDispatcherReady,
+	KafkaChannelConditionAddressable,
+}
+
 const (
 	// KafkaChannelConditionReady has status True when all subconditions below have been set to True.
 	KafkaChannelConditionReady = apis.ConditionReady
@@ -67,6 +80,26 @@ const (
 	// KafkaChannelConditionConfigReady has status True when the Kafka configuration to use by the channel exists and is valid
 	// (ie. the connection has been established).
 	KafkaChannelConditionConfigReady apis.ConditionType = "ConfigurationReady"
+
+	// KafkaChannelConditionPartitionsSufficient has status False (with a Warning severity) when the observed
+	// dispatch throughput indicates that the partition count of the Kafka topic is the bottleneck. It is not
+	// part of the condition set and therefore does not affect the readiness of the channel.
+	KafkaChannelConditionPartitionsSufficient apis.ConditionType = "PartitionsSufficient"
+
+	// KafkaChannelConditionSubscribersHealthy has status False (with a Warning severity) while the dispatcher has
+	// paused the consumption of any subscribers whose health probes are failing. It is not part of the condition
+	// set and therefore does not affect the readiness of the channel.
+	KafkaChannelConditionSubscribersHealthy apis.ConditionType = "SubscribersHealthy"
+
+	// KafkaChannelConditionResourcesRightsized has status False (with a Warning severity) when the observed
+	// resource usage of the channel's dispatcher or receiver deviates from their resource requests. It is not
+	// part of the condition set and therefore does not affect the readiness of the channel.
+	KafkaChannelConditionResourcesRightsized apis.ConditionType = "ResourcesRightsized"
+
+	// KafkaChannelConditionOffsetsRetained has status False (with a Warning severity) when the consumer groups
+	// of any subscribers have been inactive for long enough that their committed offsets risk expiring. It is
+	// not part of the condition set and therefore does not affect the readiness of the channel.
+	KafkaChannelConditionOffsetsRetained apis.ConditionType = "OffsetsRetained"
 )
 
 // RegisterAlternateKafkaChannelConditionSet register a different apis.ConditionSet.
@@ -103,6 +136,30 @@ func (cs *KafkaChannelStatus) IsReady() bool {
 	return cs.GetConditionSet().Manage(cs).IsHappy()
 }
 
+// SummarizeReady sets the reason and message of the Ready condition, when it is not True, to those of the first of
+// the dependent conditions in readySummaryOrder with the same status (and a reason, so not merely initialized), so
+// that they describe the most fundamental failure rather than whichever condition happened to be marked last.
+func (cs *KafkaChannelStatus) SummarizeReady() {
+	ready := cs.GetCondition(KafkaChannelConditionReady)
+	if ready == nil || ready.IsTrue() {
+		return
+	}
+	for _, conditionType := range readySummaryOrder {
+		condition := cs.GetCondition(conditionType)
+		if condition == nil || condition.Status != ready.Status || len(condition.Reason) == 0 {
+			continue // Not Failing Or Merely Initialized
+		}
+		if condition.Reason != ready.Reason || condition.Message != ready.Message {
+			if condition.IsFalse() {
+				cs.GetConditionSet().Manage(cs).MarkFalse(KafkaChannelConditionReady, condition.Reason, "%s", condition.Message)
+			} else {
+				cs.GetConditionSet().Manage(cs).MarkUnknown(KafkaChannelConditionReady, condition.Reason, "%s", condition.Message)
+			}
+		}
+		return
+	}
+}
+
 // InitializeConditions sets relevant unset conditions to Unknown state.
 func (cs *KafkaChannelStatus) InitializeConditions() {
 	cs.GetConditionSet().Manage(cs).InitializeConditions()
@@ -188,3 +245,87 @@ func (cs *KafkaChannelStatus) MarkConfigTrue() {
 func (cs *KafkaChannelStatus) MarkConfigFailed(reason, messageFormat string, messageA ...interface{}) {
 	cs.GetConditionSet().Manage(cs).MarkFalse(KafkaChannelConditionConfigReady, reason, messageFormat, messageA...)
 }
+
+func (cs *KafkaChannelStatus) MarkPartitionsSufficient() {
+	cs.GetConditionSet().Manage(cs).MarkTrue(KafkaChannelConditionPartitionsSufficient)
+}
+
+// MarkPartitionsInsufficient sets the PartitionsSufficient condition to False with a Warning severity, which
+// (unlike MarkFalse) leaves the Ready condition untouched.
+func (cs *KafkaChannelStatus) MarkPartitionsInsufficient(reason, messageFormat string, messageA ...interface{}) {
+	cs.GetConditionSet().Manage(cs).SetCondition(apis.Condition{
+		Type:     KafkaChannelConditionPartitionsSufficient,
+		Status:   corev1.ConditionFalse,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+	})
+}
+
+// ClearPartitionsCondition removes the PartitionsSufficient condition (e.g. when it is no longer evaluated).
+func (cs *KafkaChannelStatus) ClearPartitionsCondition() {
+	_ = cs.GetConditionSet().Manage(cs).ClearCondition(KafkaChannelConditionPartitionsSufficient)
+}
+
+func (cs *KafkaChannelStatus) MarkSubscribersHealthy() {
+	cs.GetConditionSet().Manage(cs).MarkTrue(KafkaChannelConditionSubscribersHealthy)
+}
+
+// MarkSubscribersUnhealthy sets the SubscribersHealthy condition to False with a Warning severity, which
+// (unlike MarkFalse) leaves the Ready condition untouched.
+func (cs *KafkaChannelStatus) MarkSubscribersUnhealthy(reason, messageFormat string, messageA ...interface{}) {
+	cs.GetConditionSet().Manage(cs).SetCondition(apis.Condition{
+		Type:     KafkaChannelConditionSubscribersHealthy,
+		Status:   corev1.ConditionFalse,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+	})
+}
+
+// ClearSubscribersHealthyCondition removes the SubscribersHealthy condition (e.g. when subscribers are not probed).
+func (cs *KafkaChannelStatus) ClearSubscribersHealthyCondition() {
+	_ = cs.GetConditionSet().Manage(cs).ClearCondition(KafkaChannelConditionSubscribersHealthy)
+}
+
+func (cs *KafkaChannelStatus) MarkResourcesRightsized() {
+	cs.GetConditionSet().Manage(cs).MarkTrue(KafkaChannelConditionResourcesRightsized)
+}
+
+// MarkResourcesNotRightsized sets the ResourcesRightsized condition to False with a Warning severity, which
+// (unlike MarkFalse) leaves the Ready condition untouched.
+func (cs *KafkaChannelStatus) MarkResourcesNotRightsized(reason, messageFormat string, messageA ...interface{}) {
+	cs.GetConditionSet().Manage(cs).SetCondition(apis.Condition{
+		Type:     KafkaChannelConditionResourcesRightsized,
+		Status:   corev1.ConditionFalse,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+	})
+}
+
+// ClearResourcesCondition removes the ResourcesRightsized condition (e.g. when it is no longer evaluated).
+func (cs *KafkaChannelStatus) ClearResourcesCondition() {
+	_ = cs.GetConditionSet().Manage(cs).ClearCondition(KafkaChannelConditionResourcesRightsized)
+}
+
+func (cs *KafkaChannelStatus) MarkOffsetsRetained() {
+	cs.GetConditionSet().Manage(cs).MarkTrue(KafkaChannelConditionOffsetsRetained)
+}
+
+// MarkOffsetsAtRisk sets the OffsetsRetained condition to False with a Warning severity, which (unlike
+// MarkFalse) leaves the Ready condition untouched.
+func (cs *KafkaChannelStatus) MarkOffsetsAtRisk(reason, messageFormat string, messageA ...interface{}) {
+	cs.GetConditionSet().Manage(cs).SetCondition(apis.Condition{
+		Type:     KafkaChannelConditionOffsetsRetained,
+		Status:   corev1.ConditionFalse,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+	})
+}
+
+// ClearOffsetsRetainedCondition removes the OffsetsRetained condition (e.g. when it is no longer evaluated).
+func (cs *KafkaChannelStatus) ClearOffsetsRetainedCondition() {
+	_ = cs.GetConditionSet().Manage(cs).ClearCondition(KafkaChannelConditionOffsetsRetained)
+}
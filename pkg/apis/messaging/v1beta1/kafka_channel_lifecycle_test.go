@@ -436,6 +436,113 @@ func TestKafkaChannelStatus_SetAddressable(t *testing.T) {
 	}
 }
 
+func TestKafkaChannelStatus_PartitionsCondition(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkPartitionsInsufficient("PartitionsBottleneck", "%d partitions recommended", 8)
+	condition := cs.GetCondition(KafkaChannelConditionPartitionsSufficient)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, apis.ConditionSeverityWarning, condition.Severity)
+	assert.Equal(t, "8 partitions recommended", condition.Message)
+	assert.Equal(t, corev1.ConditionUnknown, cs.GetCondition(KafkaChannelConditionReady).Status)
+
+	cs.MarkPartitionsSufficient()
+	assert.Equal(t, corev1.ConditionTrue, cs.GetCondition(KafkaChannelConditionPartitionsSufficient).Status)
+
+	cs.ClearPartitionsCondition()
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionPartitionsSufficient))
+}
+
+func TestKafkaChannelStatus_SubscribersHealthyCondition(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkSubscribersUnhealthy("SubscriberOutage", "%d subscribers paused", 2)
+	condition := cs.GetCondition(KafkaChannelConditionSubscribersHealthy)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, apis.ConditionSeverityWarning, condition.Severity)
+	assert.Equal(t, "2 subscribers paused", condition.Message)
+	assert.Equal(t, corev1.ConditionUnknown, cs.GetCondition(KafkaChannelConditionReady).Status)
+
+	cs.MarkSubscribersHealthy()
+	assert.Equal(t, corev1.ConditionTrue, cs.GetCondition(KafkaChannelConditionSubscribersHealthy).Status)
+
+	cs.ClearSubscribersHealthyCondition()
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionSubscribersHealthy))
+}
+
+func TestKafkaChannelStatus_ResourcesCondition(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkResourcesNotRightsized("ResizeRecommended", "resize %s", "cpu 500m -> 120m")
+	condition := cs.GetCondition(KafkaChannelConditionResourcesRightsized)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, apis.ConditionSeverityWarning, condition.Severity)
+	assert.Equal(t, "resize cpu 500m -> 120m", condition.Message)
+	assert.Equal(t, corev1.ConditionUnknown, cs.GetCondition(KafkaChannelConditionReady).Status)
+
+	cs.MarkResourcesRightsized()
+	assert.Equal(t, corev1.ConditionTrue, cs.GetCondition(KafkaChannelConditionResourcesRightsized).Status)
+
+	cs.ClearResourcesCondition()
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionResourcesRightsized))
+}
+
+func TestKafkaChannelStatus_OffsetsRetainedCondition(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkOffsetsAtRisk("OffsetsExpiring", "offsets of %d subscribers expire", 2)
+	condition := cs.GetCondition(KafkaChannelConditionOffsetsRetained)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, apis.ConditionSeverityWarning, condition.Severity)
+	assert.Equal(t, "offsets of 2 subscribers expire", condition.Message)
+	assert.Equal(t, corev1.ConditionUnknown, cs.GetCondition(KafkaChannelConditionReady).Status)
+
+	cs.MarkOffsetsRetained()
+	assert.Equal(t, corev1.ConditionTrue, cs.GetCondition(KafkaChannelConditionOffsetsRetained).Status)
+
+	cs.ClearOffsetsRetainedCondition()
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionOffsetsRetained))
+}
+
+func TestKafkaChannelStatus_SummarizeReady(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+
+	// The Last Failure Marked Is Summarized Until The Ready Condition Is Summarized By The Most Fundamental One
+	cs.MarkTopicFailed("TopicFailed", "failed to create topic %s", "test-topic")
+	cs.MarkDispatcherFailed("DispatcherFailed", "dispatcher unavailable")
+	assert.Equal(t, "DispatcherFailed", cs.GetCondition(KafkaChannelConditionReady).Reason)
+	cs.SummarizeReady()
+	ready := cs.GetCondition(KafkaChannelConditionReady)
+	assert.Equal(t, corev1.ConditionFalse, ready.Status)
+	assert.Equal(t, "TopicFailed", ready.Reason)
+	assert.Equal(t, "failed to create topic test-topic", ready.Message)
+
+	// Unknown Conditions Summarize A Ready Condition Which Is Unknown
+	cs = &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkDispatcherUnknown("DispatcherUnknown", "dispatcher %s", "starting")
+	cs.MarkServiceUnknown("ServiceUnknown", "service %s", "pending")
+	cs.SummarizeReady()
+	ready = cs.GetCondition(KafkaChannelConditionReady)
+	assert.Equal(t, corev1.ConditionUnknown, ready.Status)
+	assert.Equal(t, "ServiceUnknown", ready.Reason)
+	assert.Equal(t, "service pending", ready.Message)
+
+	// A Ready Condition Which Is True Is Unchanged
+	cs = &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkConfigTrue()
+	cs.MarkTopicTrue()
+	cs.MarkChannelServiceTrue()
+	cs.MarkServiceTrue()
+	cs.MarkEndpointsTrue()
+	cs.PropagateDispatcherStatus(&appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}})
+	cs.SetAddress(apis.HTTP("example.com"))
+	cs.SummarizeReady()
+	assert.True(t, cs.IsReady())
+}
+
 func TestRegisterAlternateKafkaChannelConditionSet(t *testing.T) {
 
 	cs := apis.NewLivingConditionSet(apis.ConditionReady, "hello")
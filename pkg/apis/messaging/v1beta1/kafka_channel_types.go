@@ -59,6 +59,17 @@ var (
 	_ duckv1.KRShaped = (*KafkaChannel)(nil)
 )
 
+// The supported KafkaChannelSpec CleanupPolicy values.
+const (
+	CleanupPolicyDelete  = "delete"
+	CleanupPolicyCompact = "compact"
+)
+
+// TopicAnnotation overrides the name of the KafkaChannel's topic, which is otherwise derived from the
+// namespace and name of the KafkaChannel by each channel implementation. It is set when migrating a
+// KafkaChannel between implementations, so that both use the same topic, and cannot be changed once set.
+const TopicAnnotation = "kafka.eventing.knative.dev/topic"
+
 // KafkaChannelSpec defines the specification for a KafkaChannel.
 type KafkaChannelSpec struct {
 	// NumPartitions is the number of partitions of a Kafka topic. By default, it is set to 1.
@@ -67,6 +78,19 @@ type KafkaChannelSpec struct {
 	// ReplicationFactor is the replication factor of a Kafka topic. By default, it is set to 1.
 	ReplicationFactor int16 `json:"replicationFactor"`
 
+	// CleanupPolicy is the cleanup policy of a Kafka topic, either "delete" or "compact". By default, it is
+	// set to "delete". Compacted channels retain the latest event per record key, which is the CloudEvent
+	// partitionkey extension, or the subject if there is none. Only applied when the topic is created.
+	// +optional
+	CleanupPolicy string `json:"cleanupPolicy,omitempty"`
+
+	// ExtraTopics are additional, externally managed Kafka topics whose records are also dispatched to the
+	// KafkaChannel's subscribers, fanning them in to the channel. They are neither created nor deleted with
+	// the KafkaChannel, and are only supported by the distributed channel implementation, which only fans in
+	// those allowed by the operator's configuration (none by default).
+	// +optional
+	ExtraTopics []string `json:"extraTopics,omitempty"`
+
 	// Channel conforms to Duck type Channelable.
 	eventingduck.ChannelableSpec `json:",inline"`
 }
@@ -75,6 +99,10 @@ type KafkaChannelSpec struct {
 type KafkaChannelStatus struct {
 	// Channel conforms to Duck type Channelable.
 	eventingduck.ChannelableStatus `json:",inline"`
+
+	// Topic is the name of the Kafka topic backing the channel, populated once the topic is ready.
+	// +optional
+	Topic string `json:"topic,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
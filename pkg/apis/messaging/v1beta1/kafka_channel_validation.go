@@ -19,11 +19,16 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"regexp"
 
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
 	"knative.dev/eventing/pkg/apis/eventing"
 	"knative.dev/pkg/apis"
 )
 
+// Kafka topic names are limited to 249 ASCII alphanumerics, '.', '_' and '-'.
+var topicNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
 func (c *KafkaChannel) Validate(ctx context.Context) *apis.FieldError {
 	errs := c.Spec.Validate(ctx).ViaField("spec")
 
@@ -36,6 +41,34 @@ func (c *KafkaChannel) Validate(ctx context.Context) *apis.FieldError {
 				errs = errs.Also(iv.ViaFieldKey("annotations", eventing.ScopeAnnotationKey).ViaField("metadata"))
 			}
 		}
+		if topic, ok := c.Annotations[TopicAnnotation]; ok && !topicNameRegexp.MatchString(topic) {
+			iv := apis.ErrInvalidValue(topic, "")
+			iv.Details = "expected a valid Kafka topic name"
+			errs = errs.Also(iv.ViaFieldKey("annotations", TopicAnnotation).ViaField("metadata"))
+		}
+	}
+
+	// The class must exist when the KafkaChannel is created (but may since have been removed from the config)
+	if cfg := messagingconfig.FromContext(ctx); cfg != nil && apis.IsInCreate(ctx) {
+		if class, ok := c.Annotations[messagingconfig.ChannelClassAnnotation]; ok && cfg.ChannelClasses.GetClass(class) == nil {
+			iv := apis.ErrInvalidValue(class, "")
+			iv.Details = "expected a class of the config-kafka-channel-classes ConfigMap"
+			errs = errs.Also(iv.ViaFieldKey("annotations", messagingconfig.ChannelClassAnnotation).ViaField("metadata"))
+		}
+	}
+
+	// The topic cannot be changed once set (but may be set when migrating an existing KafkaChannel)
+	if apis.IsInUpdate(ctx) {
+		if original, ok := apis.GetBaseline(ctx).(*KafkaChannel); ok && original != nil {
+			if originalTopic, ok := original.Annotations[TopicAnnotation]; ok && c.Annotations[TopicAnnotation] != originalTopic {
+				fe := &apis.FieldError{
+					Message: "Immutable fields changed (-old +new)",
+					Paths:   []string{"metadata.annotations." + TopicAnnotation},
+					Details: fmt.Sprintf("-%q +%q", originalTopic, c.Annotations[TopicAnnotation]),
+				}
+				errs = errs.Also(fe)
+			}
+		}
 	}
 
 	return errs
@@ -54,6 +87,26 @@ func (cs *KafkaChannelSpec) Validate(ctx context.Context) *apis.FieldError {
 		errs = errs.Also(fe)
 	}
 
+	if cs.CleanupPolicy != "" && cs.CleanupPolicy != CleanupPolicyDelete && cs.CleanupPolicy != CleanupPolicyCompact {
+		fe := apis.ErrInvalidValue(cs.CleanupPolicy, "cleanupPolicy")
+		fe.Details = "expected either 'delete' or 'compact'"
+		errs = errs.Also(fe)
+	}
+
+	extraTopics := make(map[string]bool, len(cs.ExtraTopics))
+	for i, topic := range cs.ExtraTopics {
+		if !topicNameRegexp.MatchString(topic) {
+			fe := apis.ErrInvalidArrayValue(topic, "extraTopics", i)
+			fe.Details = "expected a valid Kafka topic name"
+			errs = errs.Also(fe)
+		} else if extraTopics[topic] {
+			fe := apis.ErrInvalidArrayValue(topic, "extraTopics", i)
+			fe.Details = "expected unique topic names"
+			errs = errs.Also(fe)
+		}
+		extraTopics[topic] = true
+	}
+
 	for i, subscriber := range cs.SubscribableSpec.Subscribers {
 		if subscriber.ReplyURI == nil && subscriber.SubscriberURI == nil {
 			fe := apis.ErrMissingField("replyURI", "subscriberURI")
@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
 	"knative.dev/pkg/webhook/resourcesemantics"
 
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
@@ -72,6 +73,59 @@ func TestKafkaChannelValidation(t *testing.T) {
 				return fe
 			}(),
 		},
+		"compact cleanupPolicy": {
+			cr: &KafkaChannel{
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+					CleanupPolicy:     CleanupPolicyCompact,
+				},
+			},
+			want: nil,
+		},
+		"invalid cleanupPolicy": {
+			cr: &KafkaChannel{
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+					CleanupPolicy:     "invalid",
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("invalid", "spec.cleanupPolicy")
+				fe.Details = "expected either 'delete' or 'compact'"
+				return fe
+			}(),
+		},
+		"valid extraTopics": {
+			cr: &KafkaChannel{
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+					ExtraTopics:       []string{"external-topic-1", "external.topic_2"},
+				},
+			},
+			want: nil,
+		},
+		"invalid and duplicate extraTopics": {
+			cr: &KafkaChannel{
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+					ExtraTopics:       []string{"external-topic", "invalid/topic", "external-topic"},
+				},
+			},
+			want: func() *apis.FieldError {
+				var errs *apis.FieldError
+				fe := apis.ErrInvalidArrayValue("invalid/topic", "spec.extraTopics", 1)
+				fe.Details = "expected a valid Kafka topic name"
+				errs = errs.Also(fe)
+				fe = apis.ErrInvalidArrayValue("external-topic", "spec.extraTopics", 2)
+				fe.Details = "expected unique topic names"
+				errs = errs.Also(fe)
+				return errs
+			}(),
+		},
 		"valid subscribers array": {
 			cr: &KafkaChannel{
 				Spec: KafkaChannelSpec{
@@ -149,6 +203,37 @@ func TestKafkaChannelValidation(t *testing.T) {
 				return fe
 			}(),
 		},
+		"valid topic annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						TopicAnnotation: "knative-messaging-kafka.ns.name",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+		},
+		"invalid topic annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						TopicAnnotation: "not/valid",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("not/valid", "metadata.annotations.[kafka.eventing.knative.dev/topic]")
+				fe.Details = "expected a valid Kafka topic name"
+				return fe
+			}(),
+		},
 	}
 
 	for n, test := range testCases {
@@ -160,3 +245,117 @@ func TestKafkaChannelValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaChannelTopicImmutability(t *testing.T) {
+	newChannel := func(topic string) *KafkaChannel {
+		c := &KafkaChannel{
+			Spec: KafkaChannelSpec{
+				NumPartitions:     1,
+				ReplicationFactor: 1,
+			},
+		}
+		if topic != "" {
+			c.Annotations = map[string]string{TopicAnnotation: topic}
+		}
+		return c
+	}
+
+	testCases := map[string]struct {
+		original *KafkaChannel
+		updated  *KafkaChannel
+		want     *apis.FieldError
+	}{
+		"topic set": {
+			original: newChannel(""),
+			updated:  newChannel("topic"),
+		},
+		"topic unchanged": {
+			original: newChannel("topic"),
+			updated:  newChannel("topic"),
+		},
+		"topic changed": {
+			original: newChannel("topic"),
+			updated:  newChannel("other-topic"),
+			want: &apis.FieldError{
+				Message: "Immutable fields changed (-old +new)",
+				Paths:   []string{"metadata.annotations." + TopicAnnotation},
+				Details: `-"topic" +"other-topic"`,
+			},
+		},
+		"topic removed": {
+			original: newChannel("topic"),
+			updated:  newChannel(""),
+			want: &apis.FieldError{
+				Message: "Immutable fields changed (-old +new)",
+				Paths:   []string{"metadata.annotations." + TopicAnnotation},
+				Details: `-"topic" +""`,
+			},
+		},
+	}
+
+	for n, test := range testCases {
+		t.Run(n, func(t *testing.T) {
+			ctx := apis.WithinUpdate(context.Background(), test.original)
+			got := test.updated.Validate(ctx)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", n, diff)
+			}
+		})
+	}
+}
+
+func TestKafkaChannelClassValidation(t *testing.T) {
+	newChannel := func(class string) *KafkaChannel {
+		return &KafkaChannel{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{messagingconfig.ChannelClassAnnotation: class},
+			},
+			Spec: KafkaChannelSpec{
+				NumPartitions:     1,
+				ReplicationFactor: 1,
+			},
+		}
+	}
+	classesCtx := messagingconfig.ToContext(context.Background(), &messagingconfig.Config{
+		ChannelClasses: &messagingconfig.ChannelClasses{
+			Classes: map[string]*messagingconfig.ChannelClass{"gold": {NumPartitions: 12}},
+		},
+	})
+
+	testCases := map[string]struct {
+		ctx     context.Context
+		channel *KafkaChannel
+		want    *apis.FieldError
+	}{
+		"known class": {
+			ctx:     apis.WithinCreate(classesCtx),
+			channel: newChannel("gold"),
+		},
+		"unknown class": {
+			ctx:     apis.WithinCreate(classesCtx),
+			channel: newChannel("silver"),
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("silver", "metadata.annotations.["+messagingconfig.ChannelClassAnnotation+"]")
+				fe.Details = "expected a class of the config-kafka-channel-classes ConfigMap"
+				return fe
+			}(),
+		},
+		"unknown class without config": {
+			ctx:     apis.WithinCreate(context.Background()),
+			channel: newChannel("silver"),
+		},
+		"unknown class in update": {
+			ctx:     apis.WithinUpdate(classesCtx, newChannel("silver")),
+			channel: newChannel("silver"),
+		},
+	}
+
+	for n, test := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := test.channel.Validate(test.ctx)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", n, diff)
+			}
+		})
+	}
+}
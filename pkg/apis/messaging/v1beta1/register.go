@@ -46,6 +46,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&KafkaChannel{},
 		&KafkaChannelList{},
+		&EventRedelivery{},
+		&EventRedeliveryList{},
+		&ChannelAccessPolicy{},
+		&ChannelAccessPolicyList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
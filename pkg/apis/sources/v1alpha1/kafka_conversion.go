@@ -43,6 +43,7 @@ func (source *KafkaSource) ConvertTo(ctx context.Context, obj apis.Convertible)
 			KafkaAuthSpec: kafkaAuthSpec,
 			Topics:        source.Spec.Topics,
 			ConsumerGroup: source.Spec.ConsumerGroup,
+			Partitions:    source.Spec.Partitions,
 		}
 		source.Status.Status.DeepCopyInto(&sink.Status.Status)
 
@@ -55,9 +56,45 @@ func (source *KafkaSource) ConvertTo(ctx context.Context, obj apis.Convertible)
 			sink.Spec.CloudEventOverrides = source.Spec.CloudEventOverrides.DeepCopy()
 		}
 
+		if source.Spec.ConsumptionWindow != nil {
+			sink.Spec.ConsumptionWindow = &v1beta1.KafkaConsumptionWindow{
+				From: *source.Spec.ConsumptionWindow.From.DeepCopy(),
+				To:   *source.Spec.ConsumptionWindow.To.DeepCopy(),
+			}
+		}
+
+		if source.Spec.DerivedAttributes != nil {
+			sink.Spec.DerivedAttributes = make(map[string]string, len(source.Spec.DerivedAttributes))
+			for name, template := range source.Spec.DerivedAttributes {
+				sink.Spec.DerivedAttributes[name] = template
+			}
+		}
+
+		if source.Spec.Sinks != nil {
+			sink.Spec.Sinks = make([]v1beta1.KafkaSourceSink, len(source.Spec.Sinks))
+			for i := range source.Spec.Sinks {
+				sink.Spec.Sinks[i] = v1beta1.KafkaSourceSink{
+					Destination: *source.Spec.Sinks[i].Destination.DeepCopy(),
+					Delivery:    source.Spec.Sinks[i].Delivery.DeepCopy(),
+				}
+			}
+		}
+
 		if source.Status.SinkURI != nil {
 			sink.Status.SinkURI = source.Status.SinkURI.DeepCopy()
 		}
+		if source.Status.SinkURIs != nil {
+			sink.Status.SinkURIs = make([]apis.URL, len(source.Status.SinkURIs))
+			for i := range source.Status.SinkURIs {
+				source.Status.SinkURIs[i].DeepCopyInto(&sink.Status.SinkURIs[i])
+			}
+		}
+		if source.Status.TopicFailures != nil {
+			sink.Status.TopicFailures = make([]v1beta1.KafkaTopicFailure, len(source.Status.TopicFailures))
+			for i, failure := range source.Status.TopicFailures {
+				sink.Status.TopicFailures[i] = v1beta1.KafkaTopicFailure(*failure.DeepCopy())
+			}
+		}
 		if source.Status.CloudEventAttributes != nil {
 			sink.Status.CloudEventAttributes = make([]duckv1.CloudEventAttributes, len(source.Status.CloudEventAttributes))
 			copy(sink.Status.CloudEventAttributes, source.Status.CloudEventAttributes)
@@ -83,6 +120,7 @@ func (sink *KafkaSource) ConvertFrom(ctx context.Context, obj apis.Convertible)
 			KafkaAuthSpec: kafkaAuthSpec,
 			Topics:        source.Spec.Topics,
 			ConsumerGroup: source.Spec.ConsumerGroup,
+			Partitions:    source.Spec.Partitions,
 			Sink:          source.Spec.Sink.DeepCopy(),
 		}
 		if reflect.DeepEqual(*sink.Spec.Sink, duckv1.Destination{}) {
@@ -100,6 +138,44 @@ func (sink *KafkaSource) ConvertFrom(ctx context.Context, obj apis.Convertible)
 			sink.Spec.CloudEventOverrides = source.Spec.CloudEventOverrides.DeepCopy()
 		}
 
+		if source.Spec.ConsumptionWindow != nil {
+			sink.Spec.ConsumptionWindow = &KafkaConsumptionWindow{
+				From: *source.Spec.ConsumptionWindow.From.DeepCopy(),
+				To:   *source.Spec.ConsumptionWindow.To.DeepCopy(),
+			}
+		}
+
+		if source.Spec.DerivedAttributes != nil {
+			sink.Spec.DerivedAttributes = make(map[string]string, len(source.Spec.DerivedAttributes))
+			for name, template := range source.Spec.DerivedAttributes {
+				sink.Spec.DerivedAttributes[name] = template
+			}
+		}
+
+		if source.Spec.Sinks != nil {
+			sink.Spec.Sinks = make([]KafkaSourceSink, len(source.Spec.Sinks))
+			for i := range source.Spec.Sinks {
+				sink.Spec.Sinks[i] = KafkaSourceSink{
+					Destination: *source.Spec.Sinks[i].Destination.DeepCopy(),
+					Delivery:    source.Spec.Sinks[i].Delivery.DeepCopy(),
+				}
+			}
+		}
+
+		if source.Status.SinkURIs != nil {
+			sink.Status.SinkURIs = make([]apis.URL, len(source.Status.SinkURIs))
+			for i := range source.Status.SinkURIs {
+				source.Status.SinkURIs[i].DeepCopyInto(&sink.Status.SinkURIs[i])
+			}
+		}
+
+		if source.Status.TopicFailures != nil {
+			sink.Status.TopicFailures = make([]KafkaTopicFailure, len(source.Status.TopicFailures))
+			for i, failure := range source.Status.TopicFailures {
+				sink.Status.TopicFailures[i] = KafkaTopicFailure(*failure.DeepCopy())
+			}
+		}
+
 		if source.Status.CloudEventAttributes != nil {
 			sink.Status.CloudEventAttributes = make([]duckv1.CloudEventAttributes, len(source.Status.CloudEventAttributes))
 			copy(sink.Status.CloudEventAttributes, source.Status.CloudEventAttributes)
@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	bindingsv1alpha1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1alpha1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
@@ -95,6 +96,39 @@ type KafkaSourceSpec struct {
 	// +optional
 	// Needed for supporting round-tripping
 	CloudEventOverrides *duckv1.CloudEventOverrides `json:"ceOverrides,omitempty"`
+
+	// Partitions statically assigns the given partitions of the topics to the source.
+	// +optional
+	// Needed for supporting round-tripping
+	Partitions []int32 `json:"partitions,omitempty"`
+
+	// ConsumptionWindow bounds the consumption to the events produced within the window.
+	// +optional
+	// Needed for supporting round-tripping
+	ConsumptionWindow *KafkaConsumptionWindow `json:"consumptionWindow,omitempty"`
+
+	// DerivedAttributes derives CloudEvent attributes from the JSON payload of the records.
+	// +optional
+	// Needed for supporting round-tripping
+	DerivedAttributes map[string]string `json:"derivedAttributes,omitempty"`
+
+	// Sinks fans the events out to several destinations instead of the single Sink.
+	// +optional
+	// Needed for supporting round-tripping
+	Sinks []KafkaSourceSink `json:"sinks,omitempty"`
+}
+
+// KafkaSourceSink is one of the destinations a KafkaSource fans its events out to.
+type KafkaSourceSink struct {
+	duckv1.Destination `json:",inline"`
+	Delivery           *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+}
+
+// KafkaConsumptionWindow defines a one-shot, bounded consumption of the events whose timestamp
+// falls within [From, To).
+type KafkaConsumptionWindow struct {
+	From metav1.Time `json:"from"`
+	To   metav1.Time `json:"to"`
 }
 
 const (
@@ -121,6 +155,32 @@ type KafkaSourceStatus struct {
 	// * SinkURI - the current active sink URI that has been configured for the
 	//   Source.
 	duckv1.SourceStatus `json:",inline"`
+
+	// SinkURIs are the resolved URIs of the Sinks the events are fanned out to, if any.
+	// +optional
+	// Needed for supporting round-tripping
+	SinkURIs []apis.URL `json:"sinkUris,omitempty"`
+
+	// TopicFailures are the topics whose consumption is stalled on events which fail to be delivered, if any.
+	// +optional
+	// Needed for supporting round-tripping
+	TopicFailures []KafkaTopicFailure `json:"topicFailures,omitempty"`
+}
+
+// KafkaTopicFailure describes the partitions of a topic whose consumption is stalled, each on an event which
+// fails to be delivered.
+type KafkaTopicFailure struct {
+	// Topic is the name of the topic.
+	Topic string `json:"topic"`
+
+	// Partitions are the stalled partitions of the topic.
+	Partitions []int32 `json:"partitions"`
+
+	// Error is the last delivery error of the partition stalled for the longest time.
+	Error string `json:"error"`
+
+	// Since is the time of the first delivery error of the partition stalled for the longest time.
+	Since metav1.Time `json:"since"`
 }
 
 func (*KafkaSource) GetGroupVersionKind() schema.GroupVersionKind {
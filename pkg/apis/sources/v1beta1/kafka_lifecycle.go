@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
+
 	appsv1 "k8s.io/api/apps/v1"
 	"knative.dev/eventing/pkg/apis/duck"
 	"knative.dev/pkg/apis"
@@ -35,6 +37,15 @@ const (
 	// KafkaConditionKeyType is True when the KafkaSource has been configured with valid key type for
 	// the key deserializer.
 	KafkaConditionKeyType apis.ConditionType = "KeyTypeCorrect"
+
+	// KafkaConditionCompleted has status True when the KafkaSource has consumed all the events of its
+	// consumption window. It is only set when the KafkaSource has a consumption window.
+	KafkaConditionCompleted apis.ConditionType = "Completed"
+
+	// KafkaConditionTopicsHealthy has status False when the consumption of some partitions of the topics is
+	// stalled on events which fail to be delivered. It doesn't affect the readiness of the KafkaSource, as the
+	// other partitions are still consumed.
+	KafkaConditionTopicsHealthy apis.ConditionType = "TopicsHealthy"
 )
 
 var KafkaSourceCondSet = apis.NewLivingConditionSet(
@@ -113,3 +124,37 @@ func (s *KafkaSourceStatus) MarkKeyTypeCorrect() {
 func (s *KafkaSourceStatus) MarkKeyTypeIncorrect(reason, messageFormat string, messageA ...interface{}) {
 	KafkaSourceCondSet.Manage(s).MarkFalse(KafkaConditionKeyType, reason, messageFormat, messageA...)
 }
+
+// MarkTopicFailures sets the stalled topics (if any) and the condition that the topics are consumed healthily or not.
+func (s *KafkaSourceStatus) MarkTopicFailures(failures []KafkaTopicFailure) {
+	s.TopicFailures = failures
+	if len(failures) == 0 {
+		KafkaSourceCondSet.Manage(s).MarkTrue(KafkaConditionTopicsHealthy)
+		return
+	}
+	topics := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		topics = append(topics, failure.Topic)
+	}
+	KafkaSourceCondSet.Manage(s).MarkFalse(KafkaConditionTopicsHealthy, "TopicsStalled", "The consumption of topics %s is stalled on events failing to be delivered", strings.Join(topics, ", "))
+}
+
+// MarkTopicsHealthUnknown sets the condition that the health of the topics could not be checked.
+func (s *KafkaSourceStatus) MarkTopicsHealthUnknown(reason, messageFormat string, messageA ...interface{}) {
+	KafkaSourceCondSet.Manage(s).MarkUnknown(KafkaConditionTopicsHealthy, reason, messageFormat, messageA...)
+}
+
+// IsCompleted returns true if the KafkaSource has consumed all the events of its consumption window.
+func (s *KafkaSourceStatus) IsCompleted() bool {
+	return KafkaSourceCondSet.Manage(s).GetCondition(KafkaConditionCompleted).IsTrue()
+}
+
+// MarkCompleted sets the condition that the source has consumed all the events of its consumption window.
+func (s *KafkaSourceStatus) MarkCompleted() {
+	KafkaSourceCondSet.Manage(s).MarkTrue(KafkaConditionCompleted)
+}
+
+// MarkNotCompleted sets the condition that the source is still consuming the events of its consumption window.
+func (s *KafkaSourceStatus) MarkNotCompleted(reason, messageFormat string, messageA ...interface{}) {
+	KafkaSourceCondSet.Manage(s).MarkUnknown(KafkaConditionCompleted, reason, messageFormat, messageA...)
+}
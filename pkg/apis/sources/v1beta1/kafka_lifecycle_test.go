@@ -44,6 +44,50 @@ var (
 // Check that KafkaSource implements the Conditions duck type.
 var _ = duck.VerifyType(&KafkaSource{}, &duckv1.Conditions{})
 
+func TestKafkaSourceStatusIsCompleted(t *testing.T) {
+	s := &KafkaSourceStatus{}
+	s.InitializeConditions()
+	if s.IsCompleted() {
+		t.Error("IsCompleted=true, want=false")
+	}
+
+	s.MarkNotCompleted("Testing", "hi%s", "")
+	if s.IsCompleted() {
+		t.Error("IsCompleted=true, want=false")
+	}
+
+	s.MarkCompleted()
+	if !s.IsCompleted() {
+		t.Error("IsCompleted=false, want=true")
+	}
+}
+
+func TestKafkaSourceStatusMarkTopicFailures(t *testing.T) {
+	s := &KafkaSourceStatus{}
+	s.InitializeConditions()
+
+	s.MarkTopicFailures([]KafkaTopicFailure{{Topic: "a", Partitions: []int32{0, 2}}, {Topic: "b", Partitions: []int32{1}}})
+	if got := s.GetCondition(KafkaConditionTopicsHealthy); got == nil || !got.IsFalse() {
+		t.Errorf("TopicsHealthy=%v, want=False", got)
+	}
+	if got, want := len(s.TopicFailures), 2; got != want {
+		t.Errorf("len(TopicFailures)=%d, want=%d", got, want)
+	}
+
+	s.MarkTopicsHealthUnknown("Testing", "hi%s", "")
+	if got := s.GetCondition(KafkaConditionTopicsHealthy); got == nil || !got.IsUnknown() {
+		t.Errorf("TopicsHealthy=%v, want=Unknown", got)
+	}
+
+	s.MarkTopicFailures(nil)
+	if got := s.GetCondition(KafkaConditionTopicsHealthy); got == nil || !got.IsTrue() {
+		t.Errorf("TopicsHealthy=%v, want=True", got)
+	}
+	if s.TopicFailures != nil {
+		t.Errorf("TopicFailures=%v, want=nil", s.TopicFailures)
+	}
+}
+
 func TestKafkaSourceGetConditionSet(t *testing.T) {
 	r := &KafkaSource{}
 
@@ -213,6 +257,50 @@ func TestKafkaSourceStatusGetCondition(t *testing.T) {
 			Type:   KafkaConditionReady,
 			Status: corev1.ConditionTrue,
 		},
+	}, {
+		name: "mark sink and deployed then not completed",
+		s: func() *KafkaSourceStatus {
+			s := &KafkaSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example"))
+			s.MarkDeployed(availableDeployment)
+			s.MarkNotCompleted("Testing", "hi%s", "")
+			return s
+		}(),
+		condQuery: KafkaConditionReady,
+		want: &apis.Condition{
+			Type:   KafkaConditionReady,
+			Status: corev1.ConditionTrue,
+		},
+	}, {
+		name: "mark not completed",
+		s: func() *KafkaSourceStatus {
+			s := &KafkaSourceStatus{}
+			s.InitializeConditions()
+			s.MarkNotCompleted("Testing", "hi%s", "")
+			return s
+		}(),
+		condQuery: KafkaConditionCompleted,
+		want: &apis.Condition{
+			Type:    KafkaConditionCompleted,
+			Status:  corev1.ConditionUnknown,
+			Reason:  "Testing",
+			Message: "hi",
+		},
+	}, {
+		name: "mark not completed then completed",
+		s: func() *KafkaSourceStatus {
+			s := &KafkaSourceStatus{}
+			s.InitializeConditions()
+			s.MarkNotCompleted("Testing", "hi%s", "")
+			s.MarkCompleted()
+			return s
+		}(),
+		condQuery: KafkaConditionCompleted,
+		want: &apis.Condition{
+			Type:   KafkaConditionCompleted,
+			Status: corev1.ConditionTrue,
+		},
 	}}
 
 	for _, test := range tests {
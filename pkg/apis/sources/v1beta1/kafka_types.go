@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
@@ -77,6 +78,27 @@ type KafkaSourceSpec struct {
 	// +optional
 	ConsumerGroup string `json:"consumerGroup,omitempty"`
 
+	// Partitions statically assigns the given partitions of the topics to the source, bypassing the
+	// consumer group protocol. The consumer group is then only used to commit the offsets.
+	// +optional
+	Partitions []int32 `json:"partitions,omitempty"`
+
+	// ConsumptionWindow bounds the consumption to the events produced within the window, after which
+	// the source stops consuming and is marked as completed.
+	// +optional
+	ConsumptionWindow *KafkaConsumptionWindow `json:"consumptionWindow,omitempty"`
+
+	// DerivedAttributes derives CloudEvent attributes (type, subject or extensions) of the events translated
+	// from records which are not CloudEvents, by attribute name, from Kubernetes JSONPath templates (e.g.
+	// "{.order.status}") applied to the JSON payload of the records.
+	// +optional
+	DerivedAttributes map[string]string `json:"derivedAttributes,omitempty"`
+
+	// Sinks fans the events out to several destinations, each with its own retries and dead letter sink,
+	// instead of the single Sink. An event is committed once it was delivered (or dead lettered) to all of them.
+	// +optional
+	Sinks []KafkaSourceSink `json:"sinks,omitempty"`
+
 	// inherits duck/v1 SourceSpec, which currently provides:
 	// * Sink - a reference to an object that will resolve to a domain name or
 	//   a URI directly to use as the sink.
@@ -85,11 +107,64 @@ type KafkaSourceSpec struct {
 	duckv1.SourceSpec `json:",inline"`
 }
 
+// KafkaSourceSink is one of the destinations a KafkaSource fans its events out to.
+type KafkaSourceSink struct {
+	duckv1.Destination `json:",inline"`
+
+	// Delivery configures the retries of the deliveries to the destination, and the dead letter sink
+	// receiving the events which failed to be delivered to it.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+}
+
+// KafkaConsumptionWindow defines a one-shot, bounded consumption of the events whose timestamp
+// falls within [From, To).
+type KafkaConsumptionWindow struct {
+	// From is the timestamp of the first event to consume.
+	// +required
+	From metav1.Time `json:"from"`
+
+	// To is the timestamp at which the consumption stops (exclusive).
+	// +required
+	To metav1.Time `json:"to"`
+}
+
 const (
 	// KafkaEventType is the Kafka CloudEvent type.
 	KafkaEventType = "dev.knative.kafka.event"
 
 	KafkaKeyTypeLabel = "kafkasources.sources.knative.dev/key-type"
+
+	// KafkaDeadLetterSinkAnnotation is the optional URI of the sink receiving the events which failed to be delivered.
+	KafkaDeadLetterSinkAnnotation = "kafkasources.sources.knative.dev/dead-letter-sink"
+
+	// KafkaRebalanceStrategyAnnotation is the optional rebalance strategy of the consumer group (range, roundrobin or sticky).
+	KafkaRebalanceStrategyAnnotation = "kafkasources.sources.knative.dev/rebalance-strategy"
+
+	// KafkaTuningPresetAnnotation is the optional tuning preset of the consumer group (throughput, latency or balanced).
+	KafkaTuningPresetAnnotation = "kafkasources.sources.knative.dev/tuning-preset"
+
+	// KafkaHeadersPolicyAnnotation is the optional JSON policy of the Kafka headers propagated into CloudEvent extensions.
+	KafkaHeadersPolicyAnnotation = "kafkasources.sources.knative.dev/headers-policy"
+
+	// KafkaStartupMaxWaitAnnotation is the optional duration (e.g. "10m") the adapter waits at startup for the Kafka
+	// brokers to become reachable, defaulting to 5 minutes.
+	KafkaStartupMaxWaitAnnotation = "kafkasources.sources.knative.dev/startup-max-wait"
+
+	// KafkaCloudEventTimeAnnotation is the optional source of the CloudEvent time of the records which aren't
+	// CloudEvents (record, logAppendTime or wallClock).
+	KafkaCloudEventTimeAnnotation = "kafkasources.sources.knative.dev/ce-time"
+
+	// KafkaOffsetsExportAnnotation is the optional name of the ConfigMap (in the namespace of the KafkaSource) into
+	// which the committed offsets of its consumer group are periodically mirrored, e.g. to move it between clusters.
+	KafkaOffsetsExportAnnotation = "kafkasources.sources.knative.dev/offsets-export"
+
+	// KafkaOffsetsImportAnnotation is the optional name of a ConfigMap of exported offsets (in the namespace of the
+	// KafkaSource) seeding the partitions of its consumer group which have no committed offset yet.
+	KafkaOffsetsImportAnnotation = "kafkasources.sources.knative.dev/offsets-import"
+
+	// KafkaOffsetsSourceLabel labels the ConfigMaps of exported offsets with the name of their KafkaSource.
+	KafkaOffsetsSourceLabel = "kafkasources.sources.knative.dev/offsets-of"
 )
 
 var KafkaKeyTypeAllowed = []string{"string", "int", "float", "byte-array"}
@@ -109,6 +184,30 @@ type KafkaSourceStatus struct {
 	// * SinkURI - the current active sink URI that has been configured for the
 	//   Source.
 	duckv1.SourceStatus `json:",inline"`
+
+	// SinkURIs are the resolved URIs of the Sinks the events are fanned out to, if any.
+	// +optional
+	SinkURIs []apis.URL `json:"sinkUris,omitempty"`
+
+	// TopicFailures are the topics whose consumption is stalled on events which fail to be delivered, if any.
+	// +optional
+	TopicFailures []KafkaTopicFailure `json:"topicFailures,omitempty"`
+}
+
+// KafkaTopicFailure describes the partitions of a topic whose consumption is stalled, each on an event which
+// fails to be delivered. The other topics and partitions are consumed independently.
+type KafkaTopicFailure struct {
+	// Topic is the name of the topic.
+	Topic string `json:"topic"`
+
+	// Partitions are the stalled partitions of the topic.
+	Partitions []int32 `json:"partitions"`
+
+	// Error is the last delivery error of the partition stalled for the longest time.
+	Error string `json:"error"`
+
+	// Since is the time of the first delivery error of the partition stalled for the longest time.
+	Since metav1.Time `json:"since"`
 }
 
 func (*KafkaSource) GetGroupVersionKind() schema.GroupVersionKind {
@@ -18,8 +18,15 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/headers"
+	"knative.dev/eventing-kafka/pkg/common/tuning"
+	"knative.dev/eventing-kafka/pkg/source/attributes"
 	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmp"
 )
 
@@ -42,5 +49,155 @@ func (r *KafkaSource) Validate(ctx context.Context) *apis.FieldError {
 		}
 	}
 
+	var errs *apis.FieldError
+	errs = errs.Also(validateRebalanceStrategy(r.GetAnnotations()).ViaField("metadata", "annotations"))
+	errs = errs.Also(validateTuningPreset(r.GetAnnotations()).ViaField("metadata", "annotations"))
+	errs = errs.Also(validateHeadersPolicy(r.GetAnnotations()).ViaField("metadata", "annotations"))
+	errs = errs.Also(validateStartupMaxWait(r.GetAnnotations()).ViaField("metadata", "annotations"))
+	errs = errs.Also(validateCloudEventTime(r.GetAnnotations()).ViaField("metadata", "annotations"))
+	errs = errs.Also(validatePartitions(r.Spec.Partitions).ViaField("spec"))
+	errs = errs.Also(validateSinks(ctx, &r.Spec).ViaField("spec"))
+	errs = errs.Also(validateDerivedAttributes(r.Spec.DerivedAttributes).ViaField("spec"))
+	errs = errs.Also(r.Spec.ConsumptionWindow.Validate(ctx).ViaField("spec", "consumptionWindow"))
+	return errs
+}
+
+// validateRebalanceStrategy ensures the optional rebalance strategy annotation names a supported strategy.
+func validateRebalanceStrategy(annotations map[string]string) *apis.FieldError {
+	name, ok := annotations[KafkaRebalanceStrategyAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := consumer.NewBalanceStrategy(name); err != nil {
+		return &apis.FieldError{
+			Message: err.Error(),
+			Paths:   []string{KafkaRebalanceStrategyAnnotation},
+		}
+	}
+	return nil
+}
+
+// validateTuningPreset ensures the optional tuning preset annotation names a supported preset.
+func validateTuningPreset(annotations map[string]string) *apis.FieldError {
+	name, ok := annotations[KafkaTuningPresetAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := tuning.NewPreset(name); err != nil {
+		return &apis.FieldError{
+			Message: err.Error(),
+			Paths:   []string{KafkaTuningPresetAnnotation},
+		}
+	}
+	return nil
+}
+
+// validateHeadersPolicy ensures the optional headers policy annotation is a valid headers policy.
+func validateHeadersPolicy(annotations map[string]string) *apis.FieldError {
+	value, ok := annotations[KafkaHeadersPolicyAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := headers.Parse(value); err != nil {
+		return &apis.FieldError{
+			Message: err.Error(),
+			Paths:   []string{KafkaHeadersPolicyAnnotation},
+		}
+	}
+	return nil
+}
+
+// validateStartupMaxWait ensures the optional startup max wait annotation is a positive duration.
+func validateStartupMaxWait(annotations map[string]string) *apis.FieldError {
+	value, ok := annotations[KafkaStartupMaxWaitAnnotation]
+	if !ok {
+		return nil
+	}
+	if maxWait, err := time.ParseDuration(value); err != nil || maxWait <= 0 {
+		return apis.ErrInvalidValue(value, KafkaStartupMaxWaitAnnotation)
+	}
+	return nil
+}
+
+// validateCloudEventTime ensures the optional CloudEvent time annotation names a supported time mode.
+func validateCloudEventTime(annotations map[string]string) *apis.FieldError {
+	name, ok := annotations[KafkaCloudEventTimeAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := attributes.ParseTimeMode(name); err != nil {
+		return &apis.FieldError{
+			Message: err.Error(),
+			Paths:   []string{KafkaCloudEventTimeAnnotation},
+		}
+	}
 	return nil
 }
+
+// validatePartitions ensures the statically assigned partitions are valid and distinct.
+func validatePartitions(partitions []int32) *apis.FieldError {
+	var errs *apis.FieldError
+	seen := make(map[int32]bool, len(partitions))
+	for i, partition := range partitions {
+		if partition < 0 {
+			errs = errs.Also(apis.ErrInvalidArrayValue(partition, "partitions", i))
+		} else if seen[partition] {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("duplicate partition: %d", partition),
+				Paths:   []string{fmt.Sprintf("partitions[%d]", i)},
+			})
+		}
+		seen[partition] = true
+	}
+	return errs
+}
+
+// validateSinks ensures the fanned out sinks are valid destinations with valid delivery specs, which replace the sink.
+func validateSinks(ctx context.Context, spec *KafkaSourceSpec) *apis.FieldError {
+	if len(spec.Sinks) == 0 {
+		return nil
+	}
+	var errs *apis.FieldError
+	if spec.Sink != (duckv1.Destination{}) {
+		errs = errs.Also(apis.ErrMultipleOneOf("sink", "sinks"))
+	}
+	for i := range spec.Sinks {
+		errs = errs.Also(spec.Sinks[i].Destination.Validate(ctx).ViaFieldIndex("sinks", i))
+		errs = errs.Also(spec.Sinks[i].Delivery.Validate(ctx).ViaField("delivery").ViaFieldIndex("sinks", i))
+	}
+	return errs
+}
+
+// validateDerivedAttributes ensures the derived attributes are derivable, from valid JSONPath templates.
+func validateDerivedAttributes(derivedAttributes map[string]string) *apis.FieldError {
+	if _, err := attributes.New(derivedAttributes); err != nil {
+		return &apis.FieldError{
+			Message: err.Error(),
+			Paths:   []string{"derivedAttributes"},
+		}
+	}
+	return nil
+}
+
+// Validate ensures the KafkaConsumptionWindow is bounded on both ends.
+func (w *KafkaConsumptionWindow) Validate(ctx context.Context) *apis.FieldError {
+	if w == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	if w.From.IsZero() {
+		errs = errs.Also(apis.ErrMissingField("from"))
+	}
+	if w.To.IsZero() {
+		errs = errs.Also(apis.ErrMissingField("to"))
+	}
+	if errs == nil && !w.To.After(w.From.Time) {
+		errs = &apis.FieldError{
+			Message: "invalid value: " + w.To.Format(time.RFC3339),
+			Paths:   []string{"to"},
+			Details: "to must be after from",
+		}
+	}
+	return errs
+}
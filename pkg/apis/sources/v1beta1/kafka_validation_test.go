@@ -19,8 +19,11 @@ package v1beta1
 import (
 	"context"
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -184,3 +187,350 @@ func TestKafkaSourceCheckImmutableFields(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaSourceConsumptionWindowValidation(t *testing.T) {
+	from := metav1.NewTime(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC))
+	to := metav1.NewTime(from.Add(time.Hour))
+
+	testCases := map[string]struct {
+		window  *KafkaConsumptionWindow
+		allowed bool
+	}{
+		"no window": {
+			allowed: true,
+		},
+		"valid window": {
+			window:  &KafkaConsumptionWindow{From: from, To: to},
+			allowed: true,
+		},
+		"missing from": {
+			window:  &KafkaConsumptionWindow{To: to},
+			allowed: false,
+		},
+		"missing to": {
+			window:  &KafkaConsumptionWindow{From: from},
+			allowed: false,
+		},
+		"to before from": {
+			window:  &KafkaConsumptionWindow{From: to, To: from},
+			allowed: false,
+		},
+		"empty window": {
+			window:  &KafkaConsumptionWindow{From: from, To: from},
+			allowed: false,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			spec := fullSpec.DeepCopy()
+			spec.ConsumptionWindow = tc.window
+			source := &KafkaSource{
+				Spec: *spec,
+			}
+
+			err := source.Validate(context.TODO())
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected consumption window validation. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
+
+func TestKafkaSourcePartitionsValidation(t *testing.T) {
+	testCases := map[string]struct {
+		partitions []int32
+		want       string
+	}{
+		"no partitions": {},
+		"valid partitions": {
+			partitions: []int32{0, 2, 5},
+		},
+		"negative partition": {
+			partitions: []int32{0, -1},
+			want:       "invalid value: -1: spec.partitions[1]",
+		},
+		"duplicate partition": {
+			partitions: []int32{3, 1, 3},
+			want:       "duplicate partition: 3: spec.partitions[2]",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			spec := fullSpec.DeepCopy()
+			spec.Partitions = tc.partitions
+			source := &KafkaSource{
+				Spec: *spec,
+			}
+
+			err := source.Validate(context.TODO())
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Unexpected partitions validation. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestKafkaSourceSinksValidation(t *testing.T) {
+	retry := int32(-1)
+	sinkURI := apis.HTTP("sink.example.com")
+	testCases := map[string]struct {
+		sink  duckv1.Destination
+		sinks []KafkaSourceSink
+		want  string
+	}{
+		"no sinks": {
+			sink: fullSpec.Sink,
+		},
+		"valid sinks": {
+			sinks: []KafkaSourceSink{{
+				Destination: duckv1.Destination{URI: sinkURI},
+			}, {
+				Destination: fullSpec.Sink,
+				Delivery: &eventingduckv1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: sinkURI},
+				},
+			}},
+		},
+		"sink and sinks": {
+			sink:  fullSpec.Sink,
+			sinks: []KafkaSourceSink{{Destination: duckv1.Destination{URI: sinkURI}}},
+			want:  "expected exactly one, got both: spec.sink, spec.sinks",
+		},
+		"invalid destination": {
+			sinks: []KafkaSourceSink{{Destination: duckv1.Destination{URI: sinkURI}}, {}},
+			want:  "expected at least one, got none: spec.sinks[1].ref, spec.sinks[1].uri",
+		},
+		"invalid delivery": {
+			sinks: []KafkaSourceSink{{
+				Destination: duckv1.Destination{URI: sinkURI},
+				Delivery:    &eventingduckv1.DeliverySpec{Retry: &retry},
+			}},
+			want: "invalid value: -1: spec.sinks[0].delivery.retry",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			spec := fullSpec.DeepCopy()
+			spec.Sink = tc.sink
+			spec.Sinks = tc.sinks
+			source := &KafkaSource{
+				Spec: *spec,
+			}
+
+			err := source.Validate(context.TODO())
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Unexpected sinks validation. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestKafkaSourceDerivedAttributesValidation(t *testing.T) {
+	testCases := map[string]struct {
+		derivedAttributes map[string]string
+		want              string
+	}{
+		"no derived attributes": {},
+		"valid derived attributes": {
+			derivedAttributes: map[string]string{"type": "com.example.{.kind}", "region": "{.region}"},
+		},
+		"reserved attribute": {
+			derivedAttributes: map[string]string{"source": "{.origin}"},
+			want:              `the "source" attribute can't be derived: spec.derivedAttributes`,
+		},
+		"invalid template": {
+			derivedAttributes: map[string]string{"subject": "{.id"},
+			want:              `invalid template of the "subject" attribute: unclosed action: spec.derivedAttributes`,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			spec := fullSpec.DeepCopy()
+			spec.DerivedAttributes = tc.derivedAttributes
+			source := &KafkaSource{
+				Spec: *spec,
+			}
+
+			err := source.Validate(context.TODO())
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Unexpected derived attributes validation. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestKafkaSourceRebalanceStrategyValidation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        string
+	}{
+		"no annotation": {},
+		"valid strategy": {
+			annotations: map[string]string{KafkaRebalanceStrategyAnnotation: "sticky"},
+		},
+		"unknown strategy": {
+			annotations: map[string]string{KafkaRebalanceStrategyAnnotation: "foo"},
+			want:        `unknown rebalance strategy "foo", use one of [range roundrobin sticky]: metadata.annotations.kafkasources.sources.knative.dev/rebalance-strategy`,
+		},
+		"unsupported strategy": {
+			annotations: map[string]string{KafkaRebalanceStrategyAnnotation: "cooperative-sticky"},
+			want:        `rebalance strategy "cooperative-sticky" is not supported by the Kafka client, use one of [range roundrobin sticky]: metadata.annotations.kafkasources.sources.knative.dev/rebalance-strategy`,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			source := &KafkaSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: fullSpec,
+			}
+
+			err := source.Validate(context.TODO())
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Unexpected rebalance strategy validation. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestKafkaSourceTuningPresetValidation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        string
+	}{
+		"no annotation": {},
+		"valid preset": {
+			annotations: map[string]string{KafkaTuningPresetAnnotation: "throughput"},
+		},
+		"unknown preset": {
+			annotations: map[string]string{KafkaTuningPresetAnnotation: "fast"},
+			want:        `unknown tuning preset "fast", use one of [throughput latency balanced]: metadata.annotations.kafkasources.sources.knative.dev/tuning-preset`,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			source := &KafkaSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: fullSpec,
+			}
+
+			err := source.Validate(context.TODO())
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Unexpected tuning preset validation. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestKafkaSourceStartupMaxWaitValidation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        string
+	}{
+		"no annotation": {},
+		"valid duration": {
+			annotations: map[string]string{KafkaStartupMaxWaitAnnotation: "10m"},
+		},
+		"malformed duration": {
+			annotations: map[string]string{KafkaStartupMaxWaitAnnotation: "ten minutes"},
+			want:        "invalid value: ten minutes: metadata.annotations.kafkasources.sources.knative.dev/startup-max-wait",
+		},
+		"negative duration": {
+			annotations: map[string]string{KafkaStartupMaxWaitAnnotation: "-1m"},
+			want:        "invalid value: -1m: metadata.annotations.kafkasources.sources.knative.dev/startup-max-wait",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			source := &KafkaSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: fullSpec,
+			}
+
+			err := source.Validate(context.TODO())
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Unexpected startup max wait validation. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestKafkaSourceCloudEventTimeValidation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        string
+	}{
+		"no annotation": {},
+		"valid mode": {
+			annotations: map[string]string{KafkaCloudEventTimeAnnotation: "logAppendTime"},
+		},
+		"unknown mode": {
+			annotations: map[string]string{KafkaCloudEventTimeAnnotation: "createTime"},
+			want:        `unknown time mode "createTime": must be "record", "logAppendTime" or "wallClock": metadata.annotations.kafkasources.sources.knative.dev/ce-time`,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			source := &KafkaSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: fullSpec,
+			}
+
+			err := source.Validate(context.TODO())
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Unexpected ce-time validation. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestKafkaSourceHeadersPolicyValidation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        string
+	}{
+		"no annotation": {},
+		"valid policy": {
+			annotations: map[string]string{KafkaHeadersPolicyAnnotation: `{"enabled": true, "allow": ["x-*"], "stripPrefix": "x-"}`},
+		},
+		"malformed policy": {
+			annotations: map[string]string{KafkaHeadersPolicyAnnotation: "allow"},
+			want:        "invalid headers policy: invalid character 'a' looking for beginning of value: metadata.annotations.kafkasources.sources.knative.dev/headers-policy",
+		},
+		"invalid pattern": {
+			annotations: map[string]string{KafkaHeadersPolicyAnnotation: `{"enabled": true, "deny": ["x-*-secret"]}`},
+			want:        `invalid header pattern "x-*-secret": expected a header name or a prefix ending in '*': metadata.annotations.kafkasources.sources.knative.dev/headers-policy`,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			source := &KafkaSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: fullSpec,
+			}
+
+			err := source.Validate(context.TODO())
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Unexpected headers policy validation. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -22,8 +22,28 @@ package v1beta1
 
 import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	duckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	apis "knative.dev/pkg/apis"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaConsumptionWindow) DeepCopyInto(out *KafkaConsumptionWindow) {
+	*out = *in
+	in.From.DeepCopyInto(&out.From)
+	in.To.DeepCopyInto(&out.To)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConsumptionWindow.
+func (in *KafkaConsumptionWindow) DeepCopy() *KafkaConsumptionWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaConsumptionWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaLimitsSpec) DeepCopyInto(out *KafkaLimitsSpec) {
 	*out = *in
@@ -135,6 +155,28 @@ func (in *KafkaSourceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSourceSink) DeepCopyInto(out *KafkaSourceSink) {
+	*out = *in
+	in.Destination.DeepCopyInto(&out.Destination)
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		*out = new(duckv1.DeliverySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSourceSink.
+func (in *KafkaSourceSink) DeepCopy() *KafkaSourceSink {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSourceSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaSourceSpec) DeepCopyInto(out *KafkaSourceSpec) {
 	*out = *in
@@ -144,6 +186,30 @@ func (in *KafkaSourceSpec) DeepCopyInto(out *KafkaSourceSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConsumptionWindow != nil {
+		in, out := &in.ConsumptionWindow, &out.ConsumptionWindow
+		*out = new(KafkaConsumptionWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DerivedAttributes != nil {
+		in, out := &in.DerivedAttributes, &out.DerivedAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]KafkaSourceSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
 	return
 }
@@ -162,6 +228,20 @@ func (in *KafkaSourceSpec) DeepCopy() *KafkaSourceSpec {
 func (in *KafkaSourceStatus) DeepCopyInto(out *KafkaSourceStatus) {
 	*out = *in
 	in.SourceStatus.DeepCopyInto(&out.SourceStatus)
+	if in.SinkURIs != nil {
+		in, out := &in.SinkURIs, &out.SinkURIs
+		*out = make([]apis.URL, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopicFailures != nil {
+		in, out := &in.TopicFailures, &out.TopicFailures
+		*out = make([]KafkaTopicFailure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -174,3 +254,25 @@ func (in *KafkaSourceStatus) DeepCopy() *KafkaSourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTopicFailure) DeepCopyInto(out *KafkaTopicFailure) {
+	*out = *in
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	in.Since.DeepCopyInto(&out.Since)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTopicFailure.
+func (in *KafkaTopicFailure) DeepCopy() *KafkaTopicFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTopicFailure)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// The Default Number Of Partitions For Topics Which Are Auto-Created On First Use
+const DefaultNumPartitions = 1
+
+// Error Returned When Producing To A Cluster Which Has Been Closed
+var ErrClosedCluster = errors.New("conformance: kafka cluster has been closed")
+
+//
+// In-Process Fake Kafka Cluster
+//
+// The Cluster stores every produced message in an in-memory, per-partition log and hands out
+// sarama.SyncProducer, sarama.AsyncProducer and sarama.ConsumerGroup implementations which read
+// and write that log.  This allows channel implementations to be exercised end-to-end through
+// the standard Sarama interfaces without requiring a real Kafka broker to be running.
+//
+type Cluster struct {
+	partitions int32
+	topics     map[string][][]*sarama.ConsumerMessage
+	offsets    map[string]map[string]map[int32]int64 // GroupId -> Topic -> Partition -> Next Offset
+	roundRobin map[string]int32
+	closed     bool
+	mutex      sync.Mutex
+	cond       *sync.Cond
+}
+
+// Cluster Constructor (Topics Are Created With The Specified Number Of Partitions On First Use)
+func NewCluster(partitions int32) *Cluster {
+	if partitions < 1 {
+		partitions = DefaultNumPartitions
+	}
+	cluster := &Cluster{
+		partitions: partitions,
+		topics:     make(map[string][][]*sarama.ConsumerMessage),
+		offsets:    make(map[string]map[string]map[int32]int64),
+		roundRobin: make(map[string]int32),
+	}
+	cluster.cond = sync.NewCond(&cluster.mutex)
+	return cluster
+}
+
+// Create The Specified Topic (If It Does Not Already Exist) With The Specified Number Of Partitions
+func (c *Cluster) CreateTopic(topic string, partitions int32) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.createTopic(topic, partitions)
+}
+
+// Return The Names Of All Topics In The Cluster
+func (c *Cluster) Topics() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// Return A Copy Of All Messages Produced To The Specified Topic (Ordered By Partition Then Offset)
+func (c *Cluster) Messages(topic string) []*sarama.ConsumerMessage {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var messages []*sarama.ConsumerMessage
+	for _, partitionLog := range c.topics[topic] {
+		messages = append(messages, partitionLog...)
+	}
+	return messages
+}
+
+// Return The Committed (Next) Offset Of The Specified ConsumerGroup For The Specified Topic / Partition
+func (c *Cluster) CommittedOffset(groupId string, topic string, partition int32) int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.offsets[groupId][topic][partition]
+}
+
+// Close The Cluster, Terminating All Active ConsumerGroup Sessions
+func (c *Cluster) Close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	c.cond.Broadcast()
+}
+
+// Create A New SyncProducer Which Appends To The Cluster
+func (c *Cluster) NewSyncProducer() sarama.SyncProducer {
+	return &syncProducer{cluster: c}
+}
+
+// Create A New AsyncProducer Which Appends To The Cluster
+func (c *Cluster) NewAsyncProducer() sarama.AsyncProducer {
+	return newAsyncProducer(c)
+}
+
+// Create A New ConsumerGroup Which Reads From The Cluster
+func (c *Cluster) NewConsumerGroup(groupId string) sarama.ConsumerGroup {
+	return newConsumerGroup(c, groupId)
+}
+
+// Return A SyncProducer Factory (Ignoring The Brokers & Config) For Injecting The Cluster Into An Implementation
+func (c *Cluster) SyncProducerFactory() func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+	return func(_ []string, _ *sarama.Config) (sarama.SyncProducer, error) {
+		return c.NewSyncProducer(), nil
+	}
+}
+
+// Return A ConsumerGroup Factory (Ignoring The Brokers & Config) For Injecting The Cluster Into An Implementation
+func (c *Cluster) ConsumerGroupFactory() func(brokers []string, groupId string, config *sarama.Config) (sarama.ConsumerGroup, error) {
+	return func(_ []string, groupId string, _ *sarama.Config) (sarama.ConsumerGroup, error) {
+		return c.NewConsumerGroup(groupId), nil
+	}
+}
+
+// Create The Specified Topic (Must Be Called While Holding The Mutex)
+func (c *Cluster) createTopic(topic string, partitions int32) [][]*sarama.ConsumerMessage {
+	if partitionLogs, ok := c.topics[topic]; ok {
+		return partitionLogs
+	}
+	if partitions < 1 {
+		partitions = c.partitions
+	}
+	partitionLogs := make([][]*sarama.ConsumerMessage, partitions)
+	c.topics[topic] = partitionLogs
+	return partitionLogs
+}
+
+// Append The Specified ProducerMessage To The Appropriate Partition Log & Return Its Partition / Offset
+func (c *Cluster) append(producerMessage *sarama.ProducerMessage) (int32, int64, error) {
+
+	// Encode The Key & Value Outside Of The Lock
+	key, err := encode(producerMessage.Key)
+	if err != nil {
+		return -1, -1, err
+	}
+	value, err := encode(producerMessage.Value)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return -1, -1, ErrClosedCluster
+	}
+
+	// Select The Partition (Hash Of Key If Present, Otherwise Round-Robin)
+	partitionLogs := c.createTopic(producerMessage.Topic, c.partitions)
+	numPartitions := int32(len(partitionLogs))
+	var partition int32
+	if key != nil {
+		hash := fnv.New32a()
+		_, _ = hash.Write(key)
+		partition = int32(hash.Sum32() % uint32(numPartitions))
+	} else {
+		partition = c.roundRobin[producerMessage.Topic] % numPartitions
+		c.roundRobin[producerMessage.Topic] = partition + 1
+	}
+
+	// Convert (Copy) The Headers
+	headers := make([]*sarama.RecordHeader, len(producerMessage.Headers))
+	for index, header := range producerMessage.Headers {
+		headers[index] = &sarama.RecordHeader{Key: copyBytes(header.Key), Value: copyBytes(header.Value)}
+	}
+
+	// Default The Timestamp
+	timestamp := producerMessage.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	// Append The ConsumerMessage To The Partition Log
+	offset := int64(len(partitionLogs[partition]))
+	partitionLogs[partition] = append(partitionLogs[partition], &sarama.ConsumerMessage{
+		Headers:   headers,
+		Timestamp: timestamp,
+		Key:       key,
+		Value:     value,
+		Topic:     producerMessage.Topic,
+		Partition: partition,
+		Offset:    offset,
+	})
+
+	// Populate The ProducerMessage Results & Wake Any Waiting Consumers
+	producerMessage.Partition = partition
+	producerMessage.Offset = offset
+	c.cond.Broadcast()
+	return partition, offset, nil
+}
+
+// Encode The Specified Sarama Encoder (Nil Safe)
+func encode(encoder sarama.Encoder) ([]byte, error) {
+	if encoder == nil {
+		return nil, nil
+	}
+	encoded, err := encoder.Encode()
+	return copyBytes(encoded), err
+}
+
+// Utility Function For Copying The Bytes Of A Record (As A Broker Receives Them, Producers Being Free To Reuse Their Own)
+func copyBytes(data []byte) []byte {
+	if data == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(data)), data...)
+}
+
+//
+// SyncProducer Implementation
+//
+
+// Verify The syncProducer Implements The Sarama SyncProducer Interface
+var _ sarama.SyncProducer = &syncProducer{}
+
+// SyncProducer Which Appends Directly To The Cluster
+type syncProducer struct {
+	cluster *Cluster
+}
+
+func (p *syncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	return p.cluster.append(msg)
+}
+
+func (p *syncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	for _, msg := range msgs {
+		if _, _, err := p.cluster.append(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *syncProducer) Close() error {
+	return nil
+}
+
+//
+// AsyncProducer Implementation
+//
+
+// Verify The asyncProducer Implements The Sarama AsyncProducer Interface
+var _ sarama.AsyncProducer = &asyncProducer{}
+
+// AsyncProducer Which Appends To The Cluster From A Background Goroutine
+type asyncProducer struct {
+	cluster   *Cluster
+	input     chan *sarama.ProducerMessage
+	successes chan *sarama.ProducerMessage
+	errors    chan *sarama.ProducerError
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// AsyncProducer Constructor (Starts The Background Goroutine)
+func newAsyncProducer(cluster *Cluster) *asyncProducer {
+	producer := &asyncProducer{
+		cluster:   cluster,
+		input:     make(chan *sarama.ProducerMessage),
+		successes: make(chan *sarama.ProducerMessage, 256),
+		errors:    make(chan *sarama.ProducerError, 256),
+		done:      make(chan struct{}),
+	}
+	go func() {
+		defer close(producer.done)
+		defer close(producer.successes)
+		defer close(producer.errors)
+		for msg := range producer.input {
+			if _, _, err := cluster.append(msg); err != nil {
+				select {
+				case producer.errors <- &sarama.ProducerError{Msg: msg, Err: err}:
+				default: // Drop Rather Than Block When The Caller Is Not Reading Errors
+				}
+			} else {
+				select {
+				case producer.successes <- msg:
+				default: // Drop Rather Than Block When The Caller Is Not Reading Successes
+				}
+			}
+		}
+	}()
+	return producer
+}
+
+func (p *asyncProducer) AsyncClose() {
+	p.closeOnce.Do(func() { close(p.input) })
+}
+
+func (p *asyncProducer) Close() error {
+	p.AsyncClose()
+	<-p.done
+	return nil
+}
+
+func (p *asyncProducer) Input() chan<- *sarama.ProducerMessage {
+	return p.input
+}
+
+func (p *asyncProducer) Successes() <-chan *sarama.ProducerMessage {
+	return p.successes
+}
+
+func (p *asyncProducer) Errors() <-chan *sarama.ProducerError {
+	return p.errors
+}
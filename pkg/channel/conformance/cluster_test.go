@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Data
+const (
+	testTopic   = "TestTopic"
+	testGroupId = "TestGroupId"
+)
+
+// Test The SyncProducer Appends Messages To The Partition Logs
+func TestSyncProducer(t *testing.T) {
+	cluster := NewCluster(2)
+	defer cluster.Close()
+	producer := cluster.NewSyncProducer()
+
+	partition, offset, err := producer.SendMessage(&sarama.ProducerMessage{Topic: testTopic, Value: sarama.StringEncoder("value1")})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), partition)
+	assert.Equal(t, int64(0), offset)
+
+	partition, offset, err = producer.SendMessage(&sarama.ProducerMessage{Topic: testTopic, Value: sarama.StringEncoder("value2")})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), partition)
+	assert.Equal(t, int64(0), offset)
+
+	err = producer.SendMessages([]*sarama.ProducerMessage{
+		{Topic: testTopic, Key: sarama.StringEncoder("key"), Value: sarama.StringEncoder("value3")},
+		{Topic: testTopic, Key: sarama.StringEncoder("key"), Value: sarama.StringEncoder("value4")},
+	})
+	assert.Nil(t, err)
+
+	messages := cluster.Messages(testTopic)
+	assert.Len(t, messages, 4)
+	assert.Equal(t, []string{testTopic}, cluster.Topics())
+
+	// Messages With The Same Key Must Land On The Same Partition In Order
+	var keyed []*sarama.ConsumerMessage
+	for _, message := range messages {
+		if string(message.Key) == "key" {
+			keyed = append(keyed, message)
+		}
+	}
+	assert.Len(t, keyed, 2)
+	assert.Equal(t, keyed[0].Partition, keyed[1].Partition)
+	assert.Equal(t, "value3", string(keyed[0].Value))
+	assert.Equal(t, "value4", string(keyed[1].Value))
+	assert.Equal(t, keyed[0].Offset+1, keyed[1].Offset)
+
+	cluster.Close()
+	_, _, err = producer.SendMessage(&sarama.ProducerMessage{Topic: testTopic})
+	assert.Equal(t, ErrClosedCluster, err)
+}
+
+// Test The AsyncProducer Appends Messages & Reports Successes
+func TestAsyncProducer(t *testing.T) {
+	cluster := NewCluster(1)
+	defer cluster.Close()
+	producer := cluster.NewAsyncProducer()
+
+	producer.Input() <- &sarama.ProducerMessage{Topic: testTopic, Value: sarama.StringEncoder("value")}
+	success := <-producer.Successes()
+	assert.Equal(t, int64(0), success.Offset)
+
+	assert.Nil(t, producer.Close())
+	assert.Len(t, cluster.Messages(testTopic), 1)
+}
+
+// Test The ConsumerGroup Only Receives Messages Produced After Creation & Commits Marked Offsets
+func TestConsumerGroup(t *testing.T) {
+	cluster := NewCluster(1)
+	defer cluster.Close()
+	producer := cluster.NewSyncProducer()
+
+	_, _, err := producer.SendMessage(&sarama.ProducerMessage{Topic: testTopic, Value: sarama.StringEncoder("before")})
+	assert.Nil(t, err)
+
+	consumerGroup := cluster.NewConsumerGroup(testGroupId)
+	handler := &testHandler{messages: make(chan *sarama.ConsumerMessage)}
+	consumeErr := make(chan error)
+	go func() { consumeErr <- consumerGroup.Consume(context.Background(), []string{testTopic}, handler) }()
+
+	_, _, err = producer.SendMessage(&sarama.ProducerMessage{Topic: testTopic, Value: sarama.StringEncoder("after")})
+	assert.Nil(t, err)
+
+	select {
+	case message := <-handler.messages:
+		assert.Equal(t, "after", string(message.Value))
+		assert.Equal(t, int64(1), message.Offset)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed Out Waiting For Message")
+	}
+
+	assert.Nil(t, consumerGroup.Close())
+	assert.Equal(t, sarama.ErrClosedConsumerGroup, <-consumeErr)
+	assert.Equal(t, int64(2), cluster.CommittedOffset(testGroupId, testTopic, 0))
+	assert.Equal(t, sarama.ErrClosedConsumerGroup, consumerGroup.Consume(context.Background(), []string{testTopic}, handler))
+}
+
+// Test ConsumerGroupHandler Which Marks & Forwards Every Message
+type testHandler struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (h *testHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *testHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+func (h *testHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for message := range claim.Messages() {
+		session.MarkMessage(message, "")
+		h.messages <- message
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Shopify/sarama"
+)
+
+//
+// ConsumerGroup Implementation
+//
+// The ConsumerGroup behaves as a single-member Sarama ConsumerGroup which is assigned every partition of
+// the requested topics.  A new group starts at the end of any existing partition logs (equivalent to the
+// Sarama default of OffsetNewest) and resumes from its committed offsets in subsequent sessions.
+//
+
+// Verify The consumerGroup Implements The Sarama ConsumerGroup Interface
+var _ sarama.ConsumerGroup = &consumerGroup{}
+
+// ConsumerGroup Reading From The Cluster
+type consumerGroup struct {
+	cluster   *Cluster
+	groupId   string
+	errors    chan error
+	closed    chan struct{}
+	closeOnce sync.Once
+	mutex     sync.Mutex
+	sessions  sync.WaitGroup
+}
+
+// ConsumerGroup Constructor (Initializes Offsets To The End Of All Existing Partitions)
+func newConsumerGroup(cluster *Cluster, groupId string) *consumerGroup {
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	if _, ok := cluster.offsets[groupId]; !ok {
+		groupOffsets := make(map[string]map[int32]int64)
+		for topic, partitionLogs := range cluster.topics {
+			groupOffsets[topic] = make(map[int32]int64)
+			for partition, partitionLog := range partitionLogs {
+				groupOffsets[topic][int32(partition)] = int64(len(partitionLog))
+			}
+		}
+		cluster.offsets[groupId] = groupOffsets
+	}
+	return &consumerGroup{
+		cluster: cluster,
+		groupId: groupId,
+		errors:  make(chan error),
+		closed:  make(chan struct{}),
+	}
+}
+
+// Consume The Specified Topics Until The Context Is Done Or The ConsumerGroup / Cluster Is Closed
+func (g *consumerGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+
+	// Refuse To Start A Session On A Closed ConsumerGroup (Otherwise Track It So That Close() Can Wait For It)
+	g.mutex.Lock()
+	select {
+	case <-g.closed:
+		g.mutex.Unlock()
+		return sarama.ErrClosedConsumerGroup
+	default:
+	}
+	g.sessions.Add(1)
+	g.mutex.Unlock()
+	defer g.sessions.Done()
+
+	// Create The Session Context (Cancelled When The Parent Context Is Done Or The ConsumerGroup Is Closed)
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-g.closed:
+			cancel()
+		case <-sessionCtx.Done():
+		}
+	}()
+
+	// Create The Claims For Every Partition Of Every Requested Topic
+	session := &consumerGroupSession{cluster: g.cluster, groupId: g.groupId, ctx: sessionCtx, claims: make(map[string][]int32)}
+	var claims []*consumerGroupClaim
+	g.cluster.mutex.Lock()
+	for _, topic := range topics {
+		partitionLogs := g.cluster.createTopic(topic, g.cluster.partitions)
+		if g.cluster.offsets[g.groupId][topic] == nil {
+			g.cluster.offsets[g.groupId][topic] = make(map[int32]int64)
+		}
+		for partition := range partitionLogs {
+			claims = append(claims, &consumerGroupClaim{
+				topic:         topic,
+				partition:     int32(partition),
+				initialOffset: g.cluster.offsets[g.groupId][topic][int32(partition)],
+				messages:      make(chan *sarama.ConsumerMessage),
+			})
+			session.claims[topic] = append(session.claims[topic], int32(partition))
+		}
+	}
+	g.cluster.mutex.Unlock()
+
+	// Run The Handler's Setup Hook
+	if err := handler.Setup(session); err != nil {
+		return err
+	}
+
+	// Wake Any Claim Feeders Waiting On The Cluster When The Session Ends
+	go func() {
+		<-sessionCtx.Done()
+		g.cluster.mutex.Lock()
+		g.cluster.cond.Broadcast()
+		g.cluster.mutex.Unlock()
+	}()
+
+	// Feed & Consume Every Claim, Ending The Session When Any ConsumeClaim Returns
+	waitGroup := sync.WaitGroup{}
+	for _, claim := range claims {
+		waitGroup.Add(2)
+		go func(claim *consumerGroupClaim) {
+			defer waitGroup.Done()
+			g.feedClaim(sessionCtx, claim)
+		}(claim)
+		go func(claim *consumerGroupClaim) {
+			defer waitGroup.Done()
+			defer cancel()
+			if err := handler.ConsumeClaim(session, claim); err != nil {
+				g.sendError(err)
+			}
+		}(claim)
+	}
+	waitGroup.Wait()
+
+	// Run The Handler's Cleanup Hook
+	err := handler.Cleanup(session)
+
+	// Distinguish Closure From Normal Session Termination
+	select {
+	case <-g.closed:
+		return sarama.ErrClosedConsumerGroup
+	default:
+		return err
+	}
+}
+
+// Return The ConsumerGroup's Error Channel (Closed When The ConsumerGroup Is Closed)
+func (g *consumerGroup) Errors() <-chan error {
+	return g.errors
+}
+
+// Close The ConsumerGroup, Blocking Until Any Active Session Has Ended (As Sarama Does)
+func (g *consumerGroup) Close() error {
+	g.closeOnce.Do(func() {
+		g.mutex.Lock()
+		close(g.closed)
+		g.mutex.Unlock()
+		g.sessions.Wait()
+		close(g.errors)
+	})
+	return nil
+}
+
+// Send The Specified Error To The Error Channel Unless The ConsumerGroup Has Been Closed
+func (g *consumerGroup) sendError(err error) {
+	defer func() { _ = recover() }() // The Errors Channel May Be Closed Concurrently
+	select {
+	case <-g.closed:
+	case g.errors <- err:
+	}
+}
+
+// Feed Messages From The Partition Log Into The Claim Until The Session Ends
+func (g *consumerGroup) feedClaim(ctx context.Context, claim *consumerGroupClaim) {
+	defer close(claim.messages)
+	offset := claim.initialOffset
+	for {
+
+		// Wait For The Next Message (Or The End Of The Session)
+		g.cluster.mutex.Lock()
+		for ctx.Err() == nil && !g.cluster.closed && offset >= int64(len(g.cluster.topics[claim.topic][claim.partition])) {
+			g.cluster.cond.Wait()
+		}
+		if ctx.Err() != nil || g.cluster.closed {
+			g.cluster.mutex.Unlock()
+			return
+		}
+		message := g.cluster.topics[claim.topic][claim.partition][offset]
+		g.cluster.mutex.Unlock()
+
+		// Deliver The Message To The Handler
+		select {
+		case claim.messages <- message:
+			offset++
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//
+// ConsumerGroupSession Implementation
+//
+
+// Verify The consumerGroupSession Implements The Sarama ConsumerGroupSession Interface
+var _ sarama.ConsumerGroupSession = &consumerGroupSession{}
+
+// ConsumerGroupSession Committing Offsets Directly To The Cluster
+type consumerGroupSession struct {
+	cluster *Cluster
+	groupId string
+	ctx     context.Context
+	claims  map[string][]int32
+}
+
+func (s *consumerGroupSession) Claims() map[string][]int32 {
+	return s.claims
+}
+
+func (s *consumerGroupSession) MemberID() string {
+	return s.groupId
+}
+
+func (s *consumerGroupSession) GenerationID() int32 {
+	return 1
+}
+
+func (s *consumerGroupSession) MarkOffset(topic string, partition int32, offset int64, _ string) {
+	s.cluster.mutex.Lock()
+	defer s.cluster.mutex.Unlock()
+	if s.cluster.offsets[s.groupId][topic][partition] < offset {
+		s.cluster.offsets[s.groupId][topic][partition] = offset
+	}
+}
+
+func (s *consumerGroupSession) Commit() {
+	// Offsets Are Committed Synchronously When Marked
+}
+
+func (s *consumerGroupSession) ResetOffset(topic string, partition int32, offset int64, _ string) {
+	s.cluster.mutex.Lock()
+	defer s.cluster.mutex.Unlock()
+	s.cluster.offsets[s.groupId][topic][partition] = offset
+}
+
+func (s *consumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+func (s *consumerGroupSession) Context() context.Context {
+	return s.ctx
+}
+
+//
+// ConsumerGroupClaim Implementation
+//
+
+// Verify The consumerGroupClaim Implements The Sarama ConsumerGroupClaim Interface
+var _ sarama.ConsumerGroupClaim = &consumerGroupClaim{}
+
+// ConsumerGroupClaim For A Single Topic Partition
+type consumerGroupClaim struct {
+	topic         string
+	partition     int32
+	initialOffset int64
+	messages      chan *sarama.ConsumerMessage
+}
+
+func (c *consumerGroupClaim) Topic() string {
+	return c.topic
+}
+
+func (c *consumerGroupClaim) Partition() int32 {
+	return c.partition
+}
+
+func (c *consumerGroupClaim) InitialOffset() int64 {
+	return c.initialOffset
+}
+
+func (c *consumerGroupClaim) HighWaterMarkOffset() int64 {
+	return c.initialOffset
+}
+
+func (c *consumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
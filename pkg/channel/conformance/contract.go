@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package conformance provides a reusable contract test suite for KafkaChannel data plane implementations.
+
+The suite is homegrown and is NOT the upstream Knative channel conformance suite (knative.dev/eventing/test/conformance),
+which requires a live Kubernetes cluster with the channel installed.  Instead it exercises the same core data plane
+contract - delivery, fan out, replies, dead letter sinks and unsubscribing - in process against a fake Kafka Cluster so
+that it may be executed as part of the normal unit tests (no external Kafka or Kubernetes required).  Passing it is
+therefore a necessary, but not a sufficient, condition for passing the upstream suite.
+
+Implementations (including downstream forks) participate by providing a ChannelFactory which injects the Cluster's
+Sarama producer / consumer group factories into their receiver (ingress) and dispatcher (egress), and then calling
+RunChannelContract() from a standard Go test...
+
+	func TestConformance(t *testing.T) {
+		conformance.RunChannelContract(t, func(t *testing.T, cluster *conformance.Cluster) conformance.Channel {
+			return newMyChannel(t, cluster.SyncProducerFactory(), cluster.ConsumerGroupFactory())
+		})
+	}
+*/
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// The Default Time To Wait For Events To Arrive At A Subscriber
+const DefaultEventTimeout = 10 * time.Second
+
+// The Time To Wait When Verifying That No (Further) Events Arrive At A Subscriber
+const QuietPeriod = 500 * time.Millisecond
+
+// The Channel Under Test
+type Channel interface {
+
+	// Send The Specified Event Into The Channel's Ingress (Receiver)
+	Send(ctx context.Context, event cloudevents.Event) error
+
+	// Reconcile The Channel's Subscriptions To Exactly The Specified Set (Dispatcher)
+	Subscribe(ctx context.Context, subscribers []eventingduck.SubscriberSpec) error
+
+	// Close The Channel & Release All Resources
+	Close() error
+}
+
+// Factory Function For Creating A New Channel Under Test Backed By The Specified Cluster
+type ChannelFactory func(t *testing.T, cluster *Cluster) Channel
+
+// A Single Contract Test Case
+type testCase struct {
+	name string
+	run  func(t *testing.T, channel Channel)
+}
+
+// The Channel Contract Test Cases
+var testCases = []testCase{
+	{name: "Delivers Event To Subscriber", run: testDeliversEvent},
+	{name: "Fans Out Event To All Subscribers", run: testFanOut},
+	{name: "Delivers Subscriber Reply To Reply URI", run: testReply},
+	{name: "Delivers Failed Event To Dead Letter Sink", run: testDeadLetter},
+	{name: "Stops Delivery To Removed Subscriber", run: testUnsubscribe},
+}
+
+// Run The Channel Contract Suite Against Channels Created By The Specified Factory
+func RunChannelContract(t *testing.T, factory ChannelFactory) {
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := NewCluster(DefaultNumPartitions)
+			defer cluster.Close()
+			channel := factory(t, cluster)
+			defer func() { assert.Nil(t, channel.Close()) }()
+			tc.run(t, channel)
+		})
+	}
+}
+
+// Create A New Test CloudEvent
+func NewEvent(eventType string) cloudevents.Event {
+	event := cloudevents.New()
+	event.SetID(uuid.New().String())
+	event.SetType(eventType)
+	event.SetSource("/eventing-kafka/conformance")
+	event.SetSubject("conformance")
+	_ = event.SetData("application/json", map[string]string{"test": eventType})
+	return event
+}
+
+// Create A New SubscriberSpec For The Specified Subscriber & Optional Reply / DeadLetter Subscribers
+func NewSubscriberSpec(subscriber *Subscriber, reply *Subscriber, deadLetter *Subscriber) eventingduck.SubscriberSpec {
+	subscriberSpec := eventingduck.SubscriberSpec{
+		UID:           types.UID(uuid.New().String()),
+		Generation:    1,
+		SubscriberURI: subscriber.URI(),
+	}
+	if reply != nil {
+		subscriberSpec.ReplyURI = reply.URI()
+	}
+	if deadLetter != nil {
+		retry := int32(0)
+		subscriberSpec.Delivery = &eventingduck.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: deadLetter.URI()},
+			Retry:          &retry,
+		}
+	}
+	return subscriberSpec
+}
+
+// Verify The Received Event Matches The Sent Event
+func assertEventEqual(t *testing.T, expected cloudevents.Event, actual cloudevents.Event) {
+	assert.Equal(t, expected.ID(), actual.ID())
+	assert.Equal(t, expected.Type(), actual.Type())
+	assert.Equal(t, expected.Source(), actual.Source())
+	assert.Equal(t, expected.Subject(), actual.Subject())
+	assert.Equal(t, expected.DataContentType(), actual.DataContentType())
+	assert.JSONEq(t, string(expected.Data()), string(actual.Data()))
+}
+
+// Verify The Subscriber Receives Exactly The Specified Events
+func assertReceived(t *testing.T, subscriber *Subscriber, expected ...cloudevents.Event) {
+	events := subscriber.WaitForEvents(len(expected), DefaultEventTimeout)
+	if !assert.Len(t, events, len(expected)) {
+		return
+	}
+	for index := range expected {
+		assertEventEqual(t, expected[index], events[index])
+	}
+}
+
+func testDeliversEvent(t *testing.T, channel Channel) {
+	subscriber := NewSubscriber(t, http.StatusAccepted, nil)
+	defer subscriber.Close()
+
+	assert.Nil(t, channel.Subscribe(context.TODO(), []eventingduck.SubscriberSpec{NewSubscriberSpec(subscriber, nil, nil)}))
+
+	var events []cloudevents.Event
+	for index := 0; index < 3; index++ {
+		event := NewEvent(fmt.Sprintf("dev.knative.conformance.event.%d", index))
+		assert.Nil(t, channel.Send(context.TODO(), event))
+		events = append(events, event)
+	}
+
+	assertReceived(t, subscriber, events...)
+}
+
+func testFanOut(t *testing.T, channel Channel) {
+	subscriber1 := NewSubscriber(t, http.StatusAccepted, nil)
+	defer subscriber1.Close()
+	subscriber2 := NewSubscriber(t, http.StatusAccepted, nil)
+	defer subscriber2.Close()
+
+	assert.Nil(t, channel.Subscribe(context.TODO(), []eventingduck.SubscriberSpec{
+		NewSubscriberSpec(subscriber1, nil, nil),
+		NewSubscriberSpec(subscriber2, nil, nil),
+	}))
+
+	event := NewEvent("dev.knative.conformance.fanout")
+	assert.Nil(t, channel.Send(context.TODO(), event))
+
+	assertReceived(t, subscriber1, event)
+	assertReceived(t, subscriber2, event)
+}
+
+func testReply(t *testing.T, channel Channel) {
+	replyEvent := NewEvent("dev.knative.conformance.reply")
+	subscriber := NewSubscriber(t, http.StatusOK, &replyEvent)
+	defer subscriber.Close()
+	reply := NewSubscriber(t, http.StatusAccepted, nil)
+	defer reply.Close()
+
+	assert.Nil(t, channel.Subscribe(context.TODO(), []eventingduck.SubscriberSpec{NewSubscriberSpec(subscriber, reply, nil)}))
+
+	event := NewEvent("dev.knative.conformance.request")
+	assert.Nil(t, channel.Send(context.TODO(), event))
+
+	assertReceived(t, subscriber, event)
+	assertReceived(t, reply, replyEvent)
+}
+
+func testDeadLetter(t *testing.T, channel Channel) {
+	subscriber := NewSubscriber(t, http.StatusInternalServerError, nil)
+	defer subscriber.Close()
+	deadLetter := NewSubscriber(t, http.StatusAccepted, nil)
+	defer deadLetter.Close()
+
+	assert.Nil(t, channel.Subscribe(context.TODO(), []eventingduck.SubscriberSpec{NewSubscriberSpec(subscriber, nil, deadLetter)}))
+
+	event := NewEvent("dev.knative.conformance.deadletter")
+	assert.Nil(t, channel.Send(context.TODO(), event))
+
+	assertReceived(t, deadLetter, event)
+	assert.Len(t, subscriber.Events(), 1)
+}
+
+func testUnsubscribe(t *testing.T, channel Channel) {
+	subscriber := NewSubscriber(t, http.StatusAccepted, nil)
+	defer subscriber.Close()
+
+	assert.Nil(t, channel.Subscribe(context.TODO(), []eventingduck.SubscriberSpec{NewSubscriberSpec(subscriber, nil, nil)}))
+	event := NewEvent("dev.knative.conformance.subscribed")
+	assert.Nil(t, channel.Send(context.TODO(), event))
+	assertReceived(t, subscriber, event)
+
+	assert.Nil(t, channel.Subscribe(context.TODO(), []eventingduck.SubscriberSpec{}))
+	assert.Nil(t, channel.Send(context.TODO(), NewEvent("dev.knative.conformance.unsubscribed")))
+	time.Sleep(QuietPeriod)
+	assert.Len(t, subscriber.Events(), 1)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	protocolhttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"knative.dev/pkg/apis"
+)
+
+// Test Subscriber (HTTP CloudEvent Sink) Recording All Received Events
+type Subscriber struct {
+	t          *testing.T
+	server     *httptest.Server
+	statusCode int
+	reply      *cloudevents.Event
+	events     []cloudevents.Event
+	mutex      sync.Mutex
+	received   chan struct{}
+}
+
+// Subscriber Constructor - Responds To Every Request With The Specified StatusCode (And Optional Reply Event)
+func NewSubscriber(t *testing.T, statusCode int, reply *cloudevents.Event) *Subscriber {
+	subscriber := &Subscriber{
+		t:          t,
+		statusCode: statusCode,
+		reply:      reply,
+		received:   make(chan struct{}, 1024),
+	}
+	subscriber.server = httptest.NewServer(http.HandlerFunc(subscriber.ServeHTTP))
+	return subscriber
+}
+
+// The Subscriber's URI
+func (s *Subscriber) URI() *apis.URL {
+	uri, err := apis.ParseURL(s.server.URL)
+	if err != nil {
+		s.t.Fatalf("Failed To Parse Subscriber URL: %v", err)
+	}
+	return uri
+}
+
+// Return A Copy Of The Events Received So Far
+func (s *Subscriber) Events() []cloudevents.Event {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]cloudevents.Event{}, s.events...)
+}
+
+// Wait Until The Specified Number Of Events Have Been Received Or The Timeout Expires
+func (s *Subscriber) WaitForEvents(count int, timeout time.Duration) []cloudevents.Event {
+	deadline := time.After(timeout)
+	for {
+		events := s.Events()
+		if len(events) >= count {
+			return events
+		}
+		select {
+		case <-s.received:
+		case <-deadline:
+			return events
+		}
+	}
+}
+
+// Close The Subscriber's HTTP Server
+func (s *Subscriber) Close() {
+	s.server.Close()
+}
+
+// Record The Received CloudEvent & Respond As Configured
+func (s *Subscriber) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+
+	// Convert The Request Into A CloudEvent
+	message := protocolhttp.NewMessageFromHttpRequest(request)
+	defer func() { _ = message.Finish(nil) }()
+	event, err := binding.ToEvent(context.Background(), message)
+	if err != nil {
+		s.t.Errorf("Subscriber Received Invalid CloudEvent: %v", err)
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Track The Received Event
+	s.mutex.Lock()
+	s.events = append(s.events, *event)
+	s.mutex.Unlock()
+	s.received <- struct{}{}
+
+	// Respond With The Configured Reply Event Or StatusCode
+	if s.reply != nil && s.statusCode >= 200 && s.statusCode < 300 {
+		err = protocolhttp.WriteResponseWriter(context.Background(), binding.ToMessage(s.reply), s.statusCode, writer)
+		if err != nil {
+			s.t.Errorf("Subscriber Failed To Write Reply: %v", err)
+		}
+		return
+	}
+	writer.WriteHeader(s.statusCode)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingchannels "knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/channel/fanout"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	"knative.dev/eventing-kafka/pkg/channel/conformance"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+)
+
+// Run the channel contract suite against the consolidated dispatcher (receiver and consumers).
+func TestConformance(t *testing.T) {
+	conformance.RunChannelContract(t, func(t *testing.T, cluster *conformance.Cluster) conformance.Channel {
+		logger := logtesting.TestLogger(t)
+		return &conformanceChannel{
+			channelRef: eventingchannels.ChannelReference{Namespace: "conformance-namespace", Name: "conformance-channel"},
+			dispatcher: &KafkaDispatcher{
+				dispatcher:           eventingchannels.NewMessageDispatcher(logger.Desugar()),
+				kafkaConsumerFactory: conformanceConsumerGroupFactory{cluster: cluster},
+				channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+				subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+				subscriptions:        make(map[types.UID]Subscription),
+				kafkaAsyncProducer:   cluster.NewAsyncProducer(),
+				logger:               logger,
+				topicFunc:            utils.TopicName,
+			},
+		}
+	})
+}
+
+// conformanceChannel adapts a single channel of the KafkaDispatcher to the conformance.Channel interface.
+type conformanceChannel struct {
+	channelRef eventingchannels.ChannelReference
+	dispatcher *KafkaDispatcher
+}
+
+func (c *conformanceChannel) Send(ctx context.Context, event cloudevents.Event) error {
+	return c.dispatcher.produceMessage(ctx, c.channelRef, binding.ToMessage(&event), nil, nil)
+}
+
+func (c *conformanceChannel) Subscribe(_ context.Context, subscribers []eventingduck.SubscriberSpec) error {
+	channelConfig := ChannelConfig{Namespace: c.channelRef.Namespace, Name: c.channelRef.Name}
+	for _, subscriber := range subscribers {
+		subscription, err := fanout.SubscriberSpecToFanoutConfig(subscriber)
+		if err != nil {
+			return err
+		}
+		channelConfig.Subscriptions = append(channelConfig.Subscriptions, Subscription{UID: subscriber.UID, Subscription: *subscription})
+	}
+	failed, err := c.dispatcher.UpdateKafkaConsumers(&Config{ChannelConfigs: []ChannelConfig{channelConfig}})
+	if err != nil {
+		return err
+	}
+	for _, err = range failed {
+		return err
+	}
+	return nil
+}
+
+func (c *conformanceChannel) Close() error {
+	if err := c.Subscribe(context.TODO(), nil); err != nil {
+		return err
+	}
+	return c.dispatcher.kafkaAsyncProducer.Close()
+}
+
+// conformanceConsumerGroupFactory starts consumer groups against the conformance cluster.
+type conformanceConsumerGroupFactory struct {
+	cluster *conformance.Cluster
+}
+
+func (f conformanceConsumerGroupFactory) StartConsumerGroup(groupID string, topics []string, logger *zap.SugaredLogger, handler consumer.KafkaConsumerHandler) (sarama.ConsumerGroup, error) {
+	consumerGroup := f.cluster.NewConsumerGroup(groupID)
+	consumerHandler := consumer.NewConsumerHandler(logger, handler)
+	go func() {
+		for {
+			if err := consumerGroup.Consume(context.Background(), topics, &consumerHandler); err == sarama.ErrClosedConsumerGroup {
+				return
+			}
+		}
+	}()
+	return consumerGroup, nil
+}
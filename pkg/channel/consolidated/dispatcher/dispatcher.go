@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -33,19 +33,25 @@ import (
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/types"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	"knative.dev/eventing-kafka/pkg/common/client"
 	"knative.dev/eventing-kafka/pkg/common/consumer"
 	eventingchannels "knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/channel/fanout"
 	"knative.dev/eventing/pkg/kncloudevents"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/system"
 )
 
 type KafkaDispatcher struct {
 	hostToChannelMap atomic.Value
-	// hostToChannelMapLock is used to update hostToChannelMap
+	// hostToChannelMapLock is used to update hostToChannelMap and channelTopicMap
 	hostToChannelMapLock sync.Mutex
+	// channelTopicMap holds the topics of the channels whose topic is overridden
+	channelTopicMap atomic.Value
 
 	receiver   *eventingchannels.MessageReceiver
 	dispatcher *eventingchannels.MessageDispatcherImpl
@@ -60,6 +66,11 @@ type KafkaDispatcher struct {
 
 	topicFunc TopicFunc
 	logger    *zap.SugaredLogger
+
+	// tlsCertFile and tlsKeyFile hold the serving certificate of the HTTPS ingress, which is disabled when unset
+	tlsCertFile string
+	tlsKeyFile  string
+	tlsAddr     string
 }
 
 type Subscription struct {
@@ -92,6 +103,13 @@ func NewDispatcher(ctx context.Context, args *KafkaDispatcherArgs) (*KafkaDispat
 	conf.ClientID = args.ClientID
 	conf.Consumer.Return.Errors = true // Returns the errors in ConsumerGroup#Errors() https://godoc.org/github.com/Shopify/sarama#ConsumerGroup
 
+	// The Secrets referenced by the auth spec live in the namespace of the config-kafka ConfigMap
+	if args.AuthSpec != nil {
+		if err := client.UpdateConfigFromSpec(ctx, kubeclient.Get(ctx), system.Namespace(), *args.AuthSpec, conf); err != nil {
+			return nil, fmt.Errorf("unable to apply the kafka auth spec: %v", err)
+		}
+	}
+
 	producer, err := sarama.NewAsyncProducer(args.Brokers, conf)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create kafka producer against Kafka bootstrap servers %v : %v", args.Brokers, err)
@@ -106,6 +124,9 @@ func NewDispatcher(ctx context.Context, args *KafkaDispatcherArgs) (*KafkaDispat
 		kafkaAsyncProducer:   producer,
 		logger:               args.Logger,
 		topicFunc:            args.TopicFunc,
+		tlsCertFile:          args.TLSCertFile,
+		tlsKeyFile:           args.TLSKeyFile,
+		tlsAddr:              fmt.Sprintf(":%d", utils.TLSPort),
 	}
 
 	podName, err := env.GetRequiredConfigValue(args.Logger.Desugar(), env.PodNameEnvVarKey)
@@ -118,22 +139,7 @@ func NewDispatcher(ctx context.Context, args *KafkaDispatcherArgs) (*KafkaDispat
 	}
 	reporter := eventingchannels.NewStatsReporter(containerName, kmeta.ChildName(podName, uuid.New().String()))
 	receiverFunc, err := eventingchannels.NewMessageReceiver(
-		func(ctx context.Context, channel eventingchannels.ChannelReference, message binding.Message, transformers []binding.Transformer, _ nethttp.Header) error {
-			kafkaProducerMessage := sarama.ProducerMessage{
-				Topic: dispatcher.topicFunc(utils.KafkaChannelSeparator, channel.Namespace, channel.Name),
-			}
-
-			dispatcher.logger.Debugw("Received a new message from MessageReceiver, dispatching to Kafka", zap.Any("channel", channel))
-			err := protocolkafka.WriteProducerMessage(ctx, message, &kafkaProducerMessage, transformers...)
-			if err != nil {
-				return err
-			}
-
-			kafkaProducerMessage.Headers = append(kafkaProducerMessage.Headers, tracing.SerializeTrace(trace.FromContext(ctx).SpanContext())...)
-
-			dispatcher.kafkaAsyncProducer.Input() <- &kafkaProducerMessage
-			return nil
-		},
+		dispatcher.produceMessage,
 		args.Logger.Desugar(),
 		reporter,
 		eventingchannels.ResolveMessageChannelFromHostHeader(dispatcher.getChannelReferenceFromHost))
@@ -146,6 +152,24 @@ func NewDispatcher(ctx context.Context, args *KafkaDispatcherArgs) (*KafkaDispat
 	return dispatcher, nil
 }
 
+// produceMessage is the MessageReceiver callback which writes the received message to the channel's Kafka topic.
+func (d *KafkaDispatcher) produceMessage(ctx context.Context, channel eventingchannels.ChannelReference, message binding.Message, transformers []binding.Transformer, _ nethttp.Header) error {
+	kafkaProducerMessage := sarama.ProducerMessage{
+		Topic: d.topicName(channel),
+	}
+
+	d.logger.Debugw("Received a new message from MessageReceiver, dispatching to Kafka", zap.Any("channel", channel))
+	err := protocolkafka.WriteProducerMessage(ctx, message, &kafkaProducerMessage, transformers...)
+	if err != nil {
+		return err
+	}
+
+	kafkaProducerMessage.Headers = append(kafkaProducerMessage.Headers, tracing.SerializeTrace(trace.FromContext(ctx).SpanContext())...)
+
+	d.kafkaAsyncProducer.Input() <- &kafkaProducerMessage
+	return nil
+}
+
 type TopicFunc func(separator, namespace, name string) string
 
 type KafkaDispatcherArgs struct {
@@ -154,6 +178,11 @@ type KafkaDispatcherArgs struct {
 	Brokers            []string
 	TopicFunc          TopicFunc
 	Logger             *zap.SugaredLogger
+	// AuthSpec holds the SASL / TLS settings of the Kafka cluster (optional).
+	AuthSpec *bindingsv1beta1.KafkaAuthSpec
+	// TLSCertFile and TLSKeyFile enable the HTTPS ingress (optional).
+	TLSCertFile string
+	TLSKeyFile  string
 }
 
 type consumerMessageHandler struct {
@@ -210,6 +239,8 @@ type ChannelConfig struct {
 	Name          string
 	HostName      string
 	Subscriptions []Subscription
+	// Topic overrides the name of the channel's topic (optional).
+	Topic string
 }
 
 // UpdateKafkaConsumers will be called by new CRD based kafka channel dispatcher controller.
@@ -288,9 +319,20 @@ func (d *KafkaDispatcher) UpdateHostToChannelMap(config *Config) error {
 	}
 
 	d.setHostToChannelMap(hcMap)
+	d.channelTopicMap.Store(createChannelTopicMap(config))
 	return nil
 }
 
+func createChannelTopicMap(config *Config) map[eventingchannels.ChannelReference]string {
+	ctMap := make(map[eventingchannels.ChannelReference]string)
+	for _, cConfig := range config.ChannelConfigs {
+		if cConfig.Topic != "" {
+			ctMap[eventingchannels.ChannelReference{Name: cConfig.Name, Namespace: cConfig.Namespace}] = cConfig.Topic
+		}
+	}
+	return ctMap
+}
+
 func createHostToChannelMap(config *Config) (map[string]eventingchannels.ChannelReference, error) {
 	hcMap := make(map[string]eventingchannels.ChannelReference, len(config.ChannelConfigs))
 	for _, cConfig := range config.ChannelConfigs {
@@ -331,6 +373,14 @@ func (d *KafkaDispatcher) Start(ctx context.Context) error {
 		}
 	}()
 
+	if d.tlsCertFile != "" {
+		go func() {
+			if err := d.startTLS(ctx); err != nil {
+				d.logger.Errorw("Cannot serve the channels over TLS", zap.Error(err))
+			}
+		}()
+	}
+
 	return d.receiver.Start(ctx)
 }
 
@@ -339,7 +389,7 @@ func (d *KafkaDispatcher) Start(ctx context.Context) error {
 func (d *KafkaDispatcher) subscribe(channelRef eventingchannels.ChannelReference, sub Subscription) error {
 	d.logger.Info("Subscribing", zap.Any("channelRef", channelRef), zap.Any("subscription", sub.UID))
 
-	topicName := d.topicFunc(utils.KafkaChannelSeparator, channelRef.Namespace, channelRef.Name)
+	topicName := d.topicName(channelRef)
 	groupID := fmt.Sprintf("kafka.%s.%s.%s", channelRef.Namespace, channelRef.Name, string(sub.UID))
 
 	handler := &consumerMessageHandler{d.logger, sub, d.dispatcher}
@@ -396,6 +446,17 @@ func (d *KafkaDispatcher) setHostToChannelMap(hcMap map[string]eventingchannels.
 	d.hostToChannelMap.Store(hcMap)
 }
 
+// topicName returns the name of the channel's topic, which is overridden for channels migrated from another
+// implementation.
+func (d *KafkaDispatcher) topicName(channel eventingchannels.ChannelReference) string {
+	if ctMap, ok := d.channelTopicMap.Load().(map[eventingchannels.ChannelReference]string); ok {
+		if topic, ok := ctMap[channel]; ok {
+			return topic
+		}
+	}
+	return d.topicFunc(utils.KafkaChannelSeparator, channel.Namespace, channel.Name)
+}
+
 func (d *KafkaDispatcher) getChannelReferenceFromHost(host string) (eventingchannels.ChannelReference, error) {
 	chMap := d.getHostToChannelMap()
 	cr, ok := chMap[host]
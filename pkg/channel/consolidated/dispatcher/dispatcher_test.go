@@ -420,6 +420,33 @@ func TestUnsubscribeUnknownSub(t *testing.T) {
 	}
 }
 
+func TestTopicName(t *testing.T) {
+	d := &KafkaDispatcher{
+		topicFunc: utils.TopicName,
+		logger:    zap.NewNop().Sugar(),
+	}
+
+	channelRef := eventingchannels.ChannelReference{Name: "test-channel", Namespace: "test-ns"}
+	if got, want := d.topicName(channelRef), "knative-messaging-kafka.test-ns.test-channel"; got != want {
+		t.Errorf("Want topic %q, got %q", want, got)
+	}
+
+	config := &Config{
+		ChannelConfigs: []ChannelConfig{{
+			Namespace: "test-ns",
+			Name:      "test-channel",
+			HostName:  "a.b.c.d",
+			Topic:     "test-ns.test-channel",
+		}},
+	}
+	if err := d.UpdateHostToChannelMap(config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, want := d.topicName(channelRef), "test-ns.test-channel"; got != want {
+		t.Errorf("Want topic %q, got %q", want, got)
+	}
+}
+
 func TestKafkaDispatcher_Start(t *testing.T) {
 	d := &KafkaDispatcher{}
 	reporter := eventingchannels.NewStatsReporter("testcontainer", "testpod")
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// certificateReloader serves the certificate of a key pair mounted from a Secret, reloading it when the Secret
+// is updated (e.g. when the certificate is renewed by cert-manager).
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	lock    sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (c *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	info, err := os.Stat(c.certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cert == nil || !info.ModTime().Equal(c.modTime) {
+		cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the TLS certificate: %w", err)
+		}
+		c.cert = &cert
+		c.modTime = info.ModTime()
+	}
+	return c.cert, nil
+}
+
+// tlsHandler serves the message receiver over TLS. Requests whose Host differs from the server name (SNI) of
+// the TLS connection are rejected, so that a connection established for one channel's hostname cannot be used
+// to send events to another channel.
+func (d *KafkaDispatcher) tlsHandler() nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if r.TLS != nil && r.TLS.ServerName != "" && !strings.EqualFold(r.TLS.ServerName, host) {
+			d.logger.Warnf("Rejecting request for host %q on a TLS connection for %q", host, r.TLS.ServerName)
+			w.WriteHeader(nethttp.StatusMisdirectedRequest)
+			return
+		}
+		d.receiver.ServeHTTP(w, r)
+	})
+}
+
+// startTLS serves the channels over HTTPS until the context is done.
+func (d *KafkaDispatcher) startTLS(ctx context.Context) error {
+	reloader := &certificateReloader{certFile: d.tlsCertFile, keyFile: d.tlsKeyFile}
+	// Fail fast when the certificate cannot be loaded
+	if _, err := reloader.GetCertificate(nil); err != nil {
+		return err
+	}
+
+	server := &nethttp.Server{
+		Addr:    d.tlsAddr,
+		Handler: d.tlsHandler(),
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServeTLS("", "")
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return server.Close()
+	}
+}
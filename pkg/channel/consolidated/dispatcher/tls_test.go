@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"go.uber.org/zap"
+	eventingchannels "knative.dev/eventing/pkg/channel"
+)
+
+const testChannelHost = "test-channel-kn-channel.test-ns.svc.cluster.local"
+
+// writeTestKeyPair writes a self-signed certificate for the given host and its key to the given files.
+func writeTestKeyPair(t *testing.T, certFile, keyFile, host string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kafka-channel-tls")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	reloader := &certificateReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := reloader.GetCertificate(nil); err == nil {
+		t.Error("Expected an error for a missing certificate")
+	}
+
+	writeTestKeyPair(t, certFile, keyFile, testChannelHost)
+	cert, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if same, _ := reloader.GetCertificate(nil); same != cert {
+		t.Error("Expected the certificate to be cached")
+	}
+
+	// Renew the certificate
+	writeTestKeyPair(t, certFile, keyFile, testChannelHost)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(certFile, future, future); err != nil {
+		t.Fatalf("Failed to touch certificate: %v", err)
+	}
+	renewed, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if renewed == cert {
+		t.Error("Expected the renewed certificate to be reloaded")
+	}
+}
+
+func TestTLSHandler(t *testing.T) {
+	d := &KafkaDispatcher{logger: zap.NewNop().Sugar()}
+	d.setHostToChannelMap(map[string]eventingchannels.ChannelReference{})
+	receiver, err := eventingchannels.NewMessageReceiver(
+		func(ctx context.Context, channel eventingchannels.ChannelReference, message binding.Message, _ []binding.Transformer, _ http.Header) error {
+			return nil
+		},
+		zap.NewNop(),
+		eventingchannels.NewStatsReporter("testcontainer", "testpod"),
+		eventingchannels.ResolveMessageChannelFromHostHeader(d.getChannelReferenceFromHost))
+	if err != nil {
+		t.Fatalf("Error creating new message receiver. Error:%s", err)
+	}
+	d.receiver = receiver
+
+	testCases := []struct {
+		name       string
+		host       string
+		serverName string
+		wantStatus int
+	}{{
+		name:       "host matching the server name",
+		host:       testChannelHost,
+		serverName: testChannelHost,
+		wantStatus: http.StatusNotFound, // unknown channel, handled by the receiver
+	}, {
+		name:       "host with port matching the server name",
+		host:       testChannelHost + ":443",
+		serverName: testChannelHost,
+		wantStatus: http.StatusNotFound,
+	}, {
+		name:       "host differing from the server name",
+		host:       "other-kn-channel.test-ns.svc.cluster.local",
+		serverName: testChannelHost,
+		wantStatus: http.StatusMisdirectedRequest,
+	}, {
+		name:       "without server name",
+		host:       testChannelHost,
+		wantStatus: http.StatusNotFound,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "https://"+tc.host+"/", nil)
+			request.TLS = &tls.ConnectionState{ServerName: tc.serverName}
+			response := httptest.NewRecorder()
+			d.tlsHandler().ServeHTTP(response, request)
+			if response.Code != tc.wantStatus {
+				t.Errorf("Want status %d, got %d", tc.wantStatus, response.Code)
+			}
+		})
+	}
+}
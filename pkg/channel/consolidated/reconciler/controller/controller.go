@@ -83,6 +83,7 @@ func NewController(
 	}
 
 	r.dispatcherImage = env.Image
+	r.podName = env.PodName
 
 	impl := kafkaChannelReconciler.NewImpl(ctx, r)
 
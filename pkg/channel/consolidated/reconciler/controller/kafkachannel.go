@@ -56,6 +56,7 @@ import (
 	kafkaScheme "knative.dev/eventing-kafka/pkg/client/clientset/versioned/scheme"
 	kafkaChannelReconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/kafkachannel"
 	listers "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/client"
 )
 
 const (
@@ -109,6 +110,7 @@ type Reconciler struct {
 
 	systemNamespace string
 	dispatcherImage string
+	podName         string
 
 	kafkaConfig      *utils.KafkaConfig
 	kafkaConfigError error
@@ -133,7 +135,8 @@ var (
 )
 
 type envConfig struct {
-	Image string `envconfig:"DISPATCHER_IMAGE" required:"true"`
+	Image   string `envconfig:"DISPATCHER_IMAGE" required:"true"`
+	PodName string `envconfig:"POD_NAME"`
 }
 
 // Check that our Reconciler implements kafka's injection Interface
@@ -179,6 +182,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, kc *v1beta1.KafkaChannel
 		return err
 	}
 	kc.Status.MarkTopicTrue()
+	kc.Status.Topic = utils.ChannelTopicName(kc)
 
 	scope, ok := kc.Annotations[eventing.ScopeAnnotationKey]
 	if !ok {
@@ -231,8 +235,12 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, kc *v1beta1.KafkaChannel
 		return err
 	}
 	kc.Status.MarkChannelServiceTrue()
+	scheme := "http"
+	if r.kafkaConfig.TLSSecretName != "" {
+		scheme = "https"
+	}
 	kc.Status.SetAddress(&apis.URL{
-		Scheme: "http",
+		Scheme: scheme,
 		Host:   network.GetServiceHostname(svc.Name, svc.Namespace),
 	})
 
@@ -276,6 +284,7 @@ func (r *Reconciler) reconcileDispatcher(ctx context.Context, scope string, disp
 		DispatcherNamespace: dispatcherNamespace,
 		Image:               r.dispatcherImage,
 		Replicas:            1,
+		TLSSecretName:       r.kafkaConfig.TLSSecretName,
 	}
 
 	expected := resources.MakeDispatcher(args)
@@ -324,6 +333,14 @@ func (r *Reconciler) reconcileDispatcher(ctx context.Context, scope string, disp
 			needsUpdate = true
 		}
 
+		if resources.DispatcherTLSSecretName(d) != args.TLSSecretName {
+			logging.FromContext(ctx).Infof("Dispatcher deployment TLS secret is not what we expect it to be, updating Deployment Got: %q Expect: %q", resources.DispatcherTLSSecretName(d), args.TLSSecretName)
+			existing.Ports = expectedContainer.Ports
+			existing.VolumeMounts = expectedContainer.VolumeMounts
+			d.Spec.Template.Spec.Volumes = expected.Spec.Template.Spec.Volumes
+			needsUpdate = true
+		}
+
 		if *d.Spec.Replicas == 0 {
 			logging.FromContext(ctx).Infof("Dispatcher deployment has 0 replica. Scaling up deployment to 1 replica")
 			d.Spec.Replicas = pointer.Int32Ptr(1)
@@ -392,7 +409,7 @@ func (r *Reconciler) reconcileDispatcherService(ctx context.Context, dispatcherN
 	svc, err := r.serviceLister.Services(dispatcherNamespace).Get(dispatcherName)
 	if err != nil {
 		if apierrs.IsNotFound(err) {
-			expected := resources.MakeDispatcherService(dispatcherNamespace)
+			expected := resources.MakeDispatcherService(dispatcherNamespace, r.kafkaConfig.TLSSecretName != "")
 			svc, err := r.KubeClientSet.CoreV1().Services(dispatcherNamespace).Create(ctx, expected, metav1.CreateOptions{})
 
 			if err == nil {
@@ -412,10 +429,32 @@ func (r *Reconciler) reconcileDispatcherService(ctx context.Context, dispatcherN
 		return nil, newDispatcherServiceWarn(err)
 	}
 
+	// Expose or hide the HTTPS port of the dispatcher when TLS is enabled or disabled
+	expected := resources.MakeDispatcherService(dispatcherNamespace, r.kafkaConfig.TLSSecretName != "")
+	if !equality.Semantic.DeepEqual(servicePortNames(svc), servicePortNames(expected)) {
+		svc = svc.DeepCopy()
+		svc.Spec.Ports = expected.Spec.Ports
+		svc, err = r.KubeClientSet.CoreV1().Services(dispatcherNamespace).Update(ctx, svc, metav1.UpdateOptions{})
+		if err != nil {
+			logging.FromContext(ctx).Errorw("Unable to update the dispatcher service", zap.Error(err))
+			kc.Status.MarkServiceFailed("DispatcherServiceFailed", "Failed to update the dispatcher service: %v", err)
+			return nil, newDispatcherServiceWarn(err)
+		}
+	}
+
 	kc.Status.MarkServiceTrue()
 	return svc, nil
 }
 
+// servicePortNames returns the names of the ports of the service.
+func servicePortNames(svc *corev1.Service) []string {
+	names := make([]string, 0, len(svc.Spec.Ports))
+	for _, port := range svc.Spec.Ports {
+		names = append(names, port.Name)
+	}
+	return names
+}
+
 func (r *Reconciler) reconcileChannelService(ctx context.Context, dispatcherNamespace string, channel *v1beta1.KafkaChannel) (*corev1.Service, error) {
 	logger := logging.FromContext(ctx)
 	// Get the  Service and propagate the status to the Channel in case it does not exist.
@@ -468,8 +507,16 @@ func (r *Reconciler) createClient(ctx context.Context, kc *v1beta1.KafkaChannel)
 	// used to pass a fake admin client in the tests.
 	kafkaClusterAdmin := r.kafkaClusterAdmin
 	if kafkaClusterAdmin == nil {
-		var err error
-		kafkaClusterAdmin, err = resources.MakeClient(controllerAgentName, r.kafkaConfig.Brokers)
+		clientID, err := client.NewClientID(r.kafkaConfig.ClientIDTemplate, client.ClientIDFields{
+			Component: controllerAgentName,
+			Namespace: kc.Namespace,
+			Channel:   kc.Name,
+			Pod:       r.podName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		kafkaClusterAdmin, err = resources.MakeClient(ctx, r.KubeClientSet, r.systemNamespace, clientID, r.kafkaConfig.Brokers, r.kafkaConfig.AuthSpec)
 		if err != nil {
 			return nil, err
 		}
@@ -480,7 +527,7 @@ func (r *Reconciler) createClient(ctx context.Context, kc *v1beta1.KafkaChannel)
 func (r *Reconciler) createTopic(ctx context.Context, channel *v1beta1.KafkaChannel, kafkaClusterAdmin sarama.ClusterAdmin) error {
 	logger := logging.FromContext(ctx)
 
-	topicName := utils.TopicName(utils.KafkaChannelSeparator, channel.Namespace, channel.Name)
+	topicName := utils.ChannelTopicName(channel)
 	logger.Infow("Creating topic on Kafka cluster", zap.String("topic", topicName))
 	err := kafkaClusterAdmin.CreateTopic(topicName, &sarama.TopicDetail{
 		ReplicationFactor: channel.Spec.ReplicationFactor,
@@ -499,7 +546,7 @@ func (r *Reconciler) createTopic(ctx context.Context, channel *v1beta1.KafkaChan
 func (r *Reconciler) deleteTopic(ctx context.Context, channel *v1beta1.KafkaChannel, kafkaClusterAdmin sarama.ClusterAdmin) error {
 	logger := logging.FromContext(ctx)
 
-	topicName := utils.TopicName(utils.KafkaChannelSeparator, channel.Namespace, channel.Name)
+	topicName := utils.ChannelTopicName(channel)
 	logger.Infow("Deleting topic on Kafka Cluster", zap.String("topic", topicName))
 	err := kafkaClusterAdmin.DeleteTopic(topicName)
 	if err == sarama.ErrUnknownTopicOrPartition {
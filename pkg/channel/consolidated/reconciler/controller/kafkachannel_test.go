@@ -55,6 +55,7 @@ const (
 	testNS                = "test-namespace"
 	kcName                = "test-kc"
 	testDispatcherImage   = "test-image"
+	testTLSSecretName     = "test-tls-secret"
 	channelServiceAddress = "test-kc-kn-channel.test-namespace.svc.cluster.local"
 	brokerName            = "test-broker"
 	finalizerName         = "kafkachannels.messaging.knative.dev"
@@ -475,6 +476,82 @@ func TestDeploymentUpdatedOnImageChange(t *testing.T) {
 	}, zap.L()))
 }
 
+func TestDeploymentUpdatedOnTLSChange(t *testing.T) {
+	kcKey := testNS + "/" + kcName
+	row := TableRow{
+		Name: "Works, TLS enabled",
+		Key:  kcKey,
+		Objects: []runtime.Object{
+			makeDeployment(),
+			makeService(),
+			makeReadyEndpoints(),
+			reconcilertesting.NewKafkaChannel(kcName, testNS,
+				reconcilertesting.WithKafkaFinalizer(finalizerName)),
+		},
+		WantErr: false,
+		WantCreates: []runtime.Object{
+			makeChannelService(reconcilertesting.NewKafkaChannel(kcName, testNS)),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: resources.MakeDispatcher(resources.DispatcherArgs{
+				DispatcherNamespace: testNS,
+				Image:               testDispatcherImage,
+				Replicas:            1,
+				TLSSecretName:       testTLSSecretName,
+			}),
+		}, {
+			Object: resources.MakeDispatcherService(testNS, true),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertesting.NewKafkaChannel(kcName, testNS,
+				reconcilertesting.WithInitKafkaChannelConditions,
+				reconcilertesting.WithKafkaFinalizer(finalizerName),
+				reconcilertesting.WithKafkaChannelConfigReady(),
+				reconcilertesting.WithKafkaChannelTopicReady(),
+				reconcilertesting.WithKafkaChannelServiceReady(),
+				reconcilertesting.WithKafkaChannelEndpointsReady(),
+				reconcilertesting.WithKafkaChannelChannelServiceReady(),
+				reconcilertesting.WithKafkaChannelHTTPSAddress(channelServiceAddress),
+			),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, dispatcherDeploymentUpdated, "Dispatcher deployment updated"),
+			Eventf(corev1.EventTypeNormal, "KafkaChannelReconciled", `KafkaChannel reconciled: "test-namespace/test-kc"`),
+		},
+	}
+
+	row.Test(t, reconcilertesting.MakeFactory(func(ctx context.Context, listers *reconcilertesting.Listers, cmw configmap.Watcher) controller.Reconciler {
+
+		r := &Reconciler{
+			systemNamespace: testNS,
+			dispatcherImage: testDispatcherImage,
+			kafkaConfig: &KafkaConfig{
+				Brokers:       []string{brokerName},
+				TLSSecretName: testTLSSecretName,
+			},
+			kafkachannelLister: listers.GetKafkaChannelLister(),
+			// TODO fix
+			kafkachannelInformer: nil,
+			deploymentLister:     listers.GetDeploymentLister(),
+			serviceLister:        listers.GetServiceLister(),
+			endpointsLister:      listers.GetEndpointsLister(),
+			kafkaClusterAdmin: &mockClusterAdmin{
+				mockCreateTopicFunc: func(topic string, detail *sarama.TopicDetail, validateOnly bool) error {
+					errMsg := sarama.ErrTopicAlreadyExists.Error()
+					return &sarama.TopicError{
+						Err:    sarama.ErrTopicAlreadyExists,
+						ErrMsg: &errMsg,
+					}
+				},
+			},
+			kafkaClientSet:    fakekafkaclient.Get(ctx),
+			KubeClientSet:     kubeclient.Get(ctx),
+			EventingClientSet: eventingClient.Get(ctx),
+		}
+		return kafkachannel.NewReconciler(ctx, logging.FromContext(ctx), r.kafkaClientSet, listers.GetKafkaChannelLister(), controller.GetEventRecorder(ctx), r)
+	}, zap.L()))
+}
+
 func TestDeploymentZeroReplicas(t *testing.T) {
 	kcKey := testNS + "/" + kcName
 	row := TableRow{
@@ -723,7 +800,7 @@ func makeReadyDeployment() *appsv1.Deployment {
 }
 
 func makeService() *corev1.Service {
-	return resources.MakeDispatcherService(testNS)
+	return resources.MakeDispatcherService(testNS, false)
 }
 
 func makeChannelService(nc *v1beta1.KafkaChannel) *corev1.Service {
@@ -17,12 +17,25 @@ limitations under the License.
 package resources
 
 import (
+	"context"
+
 	"github.com/Shopify/sarama"
+	"k8s.io/client-go/kubernetes"
+
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/client"
 )
 
-func MakeClient(clientID string, bootstrapServers []string) (sarama.ClusterAdmin, error) {
+// MakeClient creates a ClusterAdmin for the given bootstrap servers, authenticating according to the
+// (optional) auth spec whose Secrets are read from the given namespace.
+func MakeClient(ctx context.Context, kubeClient kubernetes.Interface, namespace string, clientID string, bootstrapServers []string, authSpec *bindingsv1beta1.KafkaAuthSpec) (sarama.ClusterAdmin, error) {
 	saramaConf := sarama.NewConfig()
 	saramaConf.Version = sarama.V1_1_0_0
 	saramaConf.ClientID = clientID
+	if authSpec != nil {
+		if err := client.UpdateConfigFromSpec(ctx, kubeClient, namespace, *authSpec, saramaConf); err != nil {
+			return nil, err
+		}
+	}
 	return sarama.NewClusterAdmin(bootstrapServers, saramaConf)
 }
@@ -21,10 +21,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
 	"knative.dev/pkg/system"
 )
 
-const DispatcherContainerName = "dispatcher"
+const (
+	DispatcherContainerName = "dispatcher"
+
+	tlsVolumeName = "kafka-channel-tls"
+)
 
 var (
 	serviceAccountName = "kafka-ch-dispatcher"
@@ -40,13 +45,15 @@ type DispatcherArgs struct {
 	DispatcherNamespace string
 	Image               string
 	Replicas            int32
+	// TLSSecretName is the name of the Secret holding the serving certificate of the channels (optional).
+	TLSSecretName string
 }
 
 // MakeDispatcher generates the dispatcher deployment for the KafKa channel
 func MakeDispatcher(args DispatcherArgs) *v1.Deployment {
 	replicas := args.Replicas
 
-	return &v1.Deployment{
+	d := &v1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
 			Kind:       "Deployments",
@@ -99,6 +106,43 @@ func MakeDispatcher(args DispatcherArgs) *v1.Deployment {
 			},
 		},
 	}
+
+	if args.TLSSecretName != "" {
+		withTLS(d, args.TLSSecretName)
+	}
+	return d
+}
+
+// withTLS mounts the TLS Secret in the dispatcher container and exposes its HTTPS port.
+func withTLS(d *v1.Deployment, secretName string) {
+	container := &d.Spec.Template.Spec.Containers[0]
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		Name:          "https",
+		ContainerPort: utils.TLSPort,
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      tlsVolumeName,
+		MountPath: utils.TLSMountPath,
+		ReadOnly:  true,
+	})
+	d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: tlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	})
+}
+
+// DispatcherTLSSecretName returns the name of the TLS Secret mounted in the dispatcher deployment, if any.
+func DispatcherTLSSecretName(d *v1.Deployment) string {
+	for _, volume := range d.Spec.Template.Spec.Volumes {
+		if volume.Name == tlsVolumeName && volume.Secret != nil {
+			return volume.Secret.SecretName
+		}
+	}
+	return ""
 }
 
 func makeEnv(args DispatcherArgs) []corev1.EnvVar {
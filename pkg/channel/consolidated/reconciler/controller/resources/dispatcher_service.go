@@ -20,11 +20,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
 )
 
-// MakeDispatcherService creates the Kafka dispatcher service
-func MakeDispatcherService(namespace string) *corev1.Service {
-	return &corev1.Service{
+// MakeDispatcherService creates the Kafka dispatcher service, exposing the HTTPS port of the dispatcher if tls is set
+func MakeDispatcherService(namespace string, tls bool) *corev1.Service {
+	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
 			Kind:       "Service",
@@ -46,4 +48,13 @@ func MakeDispatcherService(namespace string) *corev1.Service {
 			},
 		},
 	}
+	if tls {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:       "https-dispatcher",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       443,
+			TargetPort: intstr.IntOrString{IntVal: utils.TLSPort},
+		})
+	}
+	return svc
 }
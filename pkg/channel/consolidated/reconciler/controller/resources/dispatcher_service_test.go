@@ -49,9 +49,31 @@ func TestNewDispatcherService(t *testing.T) {
 		},
 	}
 
-	got := MakeDispatcherService(testNS)
+	got := MakeDispatcherService(testNS, false)
 
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Errorf("unexpected condition (-want, +got) = %v", diff)
 	}
 }
+
+func TestNewDispatcherServiceWithTLS(t *testing.T) {
+	got := MakeDispatcherService(testNS, true)
+
+	want := []corev1.ServicePort{
+		{
+			Name:       "http-dispatcher",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       80,
+			TargetPort: intstr.IntOrString{IntVal: 8080},
+		},
+		{
+			Name:       "https-dispatcher",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       443,
+			TargetPort: intstr.IntOrString{IntVal: 8443},
+		},
+	}
+	if diff := cmp.Diff(want, got.Spec.Ports); diff != "" {
+		t.Errorf("unexpected ports (-want, +got) = %v", diff)
+	}
+}
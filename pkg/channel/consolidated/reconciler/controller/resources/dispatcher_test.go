@@ -202,3 +202,44 @@ func TestNewNamespaceDispatcher(t *testing.T) {
 		t.Errorf("unexpected condition (-want, +got) = %v", diff)
 	}
 }
+
+func TestNewDispatcherWithTLS(t *testing.T) {
+	os.Setenv(system.NamespaceEnvKey, "knative-testing")
+
+	args := DispatcherArgs{
+		DispatcherScope:     "cluster",
+		DispatcherNamespace: testNS,
+		Image:               imageName,
+		Replicas:            1,
+		TLSSecretName:       "kafka-channel-tls",
+	}
+
+	got := MakeDispatcher(args)
+
+	wantPorts := []corev1.ContainerPort{{
+		Name:          "metrics",
+		ContainerPort: 9090,
+	}, {
+		Name:          "https",
+		ContainerPort: 8443,
+	}}
+	if diff := cmp.Diff(wantPorts, got.Spec.Template.Spec.Containers[0].Ports); diff != "" {
+		t.Errorf("unexpected ports (-want, +got) = %v", diff)
+	}
+
+	wantMount := corev1.VolumeMount{
+		Name:      "kafka-channel-tls",
+		MountPath: "/etc/kafka-channel-tls",
+		ReadOnly:  true,
+	}
+	if diff := cmp.Diff(wantMount, got.Spec.Template.Spec.Containers[0].VolumeMounts[1]); diff != "" {
+		t.Errorf("unexpected volume mount (-want, +got) = %v", diff)
+	}
+
+	if secretName := DispatcherTLSSecretName(got); secretName != "kafka-channel-tls" {
+		t.Errorf("Want TLS secret %q, got %q", "kafka-channel-tls", secretName)
+	}
+	if secretName := DispatcherTLSSecretName(MakeDispatcher(DispatcherArgs{})); secretName != "" {
+		t.Errorf("Want no TLS secret, got %q", secretName)
+	}
+}
@@ -19,6 +19,8 @@ package controller
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -41,12 +43,14 @@ import (
 	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/consolidated/dispatcher"
 	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
 	kafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	kafkaScheme "knative.dev/eventing-kafka/pkg/client/clientset/versioned/scheme"
 	kafkaclientsetinjection "knative.dev/eventing-kafka/pkg/client/injection/client"
 	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
 	kafkachannelreconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/kafkachannel"
 	listers "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/client"
 )
 
 func init() {
@@ -93,14 +97,27 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		MaxIdleConnsPerHost: int(kafkaConfig.MaxIdleConnsPerHost),
 	}
 
+	clientID, err := client.NewClientID(kafkaConfig.ClientIDTemplate, client.ClientIDFields{
+		Component: "kafka-ch-dispatcher",
+		Pod:       os.Getenv(env.PodNameEnvVarKey),
+	})
+	if err != nil {
+		logger.Fatalw("Error rendering kafka client id", zap.Error(err))
+	}
+
 	kafkaChannelInformer := kafkachannel.Get(ctx)
 	args := &dispatcher.KafkaDispatcherArgs{
 		KnCEConnectionArgs: connectionArgs,
-		ClientID:           "kafka-ch-dispatcher",
+		ClientID:           clientID,
 		Brokers:            kafkaConfig.Brokers,
+		AuthSpec:           kafkaConfig.AuthSpec,
 		TopicFunc:          utils.TopicName,
 		Logger:             logger,
 	}
+	if kafkaConfig.TLSSecretName != "" {
+		args.TLSCertFile = filepath.Join(utils.TLSMountPath, corev1.TLSCertKey)
+		args.TLSKeyFile = filepath.Join(utils.TLSMountPath, corev1.TLSPrivateKeyKey)
+	}
 	kafkaDispatcher, err := dispatcher.NewDispatcher(ctx, args)
 	if err != nil {
 		logger.Fatalw("Unable to create kafka dispatcher", zap.Error(err))
@@ -205,6 +222,7 @@ func (r *Reconciler) newChannelConfigFromKafkaChannel(c *v1beta1.KafkaChannel) *
 		Namespace: c.Namespace,
 		Name:      c.Name,
 		HostName:  c.Status.Address.URL.Host,
+		Topic:     c.Annotations[v1beta1.TopicAnnotation],
 	}
 	if c.Spec.SubscribableSpec.Subscribers != nil {
 		newSubs := make([]dispatcher.Subscription, 0, len(c.Spec.SubscribableSpec.Subscribers))
@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
 	"knative.dev/pkg/apis"
 )
 
@@ -60,6 +61,7 @@ func WithKafkaChannelDeleted(nc *v1beta1.KafkaChannel) {
 func WithKafkaChannelTopicReady() KafkaChannelOption {
 	return func(nc *v1beta1.KafkaChannel) {
 		nc.Status.MarkTopicTrue()
+		nc.Status.Topic = utils.ChannelTopicName(nc)
 	}
 }
 
@@ -126,6 +128,15 @@ func WithKafkaChannelAddress(a string) KafkaChannelOption {
 	}
 }
 
+func WithKafkaChannelHTTPSAddress(a string) KafkaChannelOption {
+	return func(nc *v1beta1.KafkaChannel) {
+		nc.Status.SetAddress(&apis.URL{
+			Scheme: "https",
+			Host:   a,
+		})
+	}
+}
+
 func WithKafkaFinalizer(finalizerName string) KafkaChannelOption {
 	return func(nc *v1beta1.KafkaChannel) {
 		finalizers := sets.NewString(nc.Finalizers...)
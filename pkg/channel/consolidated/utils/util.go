@@ -24,6 +24,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/client"
 	"knative.dev/pkg/configmap"
 )
 
@@ -31,6 +34,9 @@ const (
 	BrokerConfigMapKey           = "bootstrapServers"
 	MaxIdleConnectionsKey        = "maxIdleConns"
 	MaxIdleConnectionsPerHostKey = "maxIdleConnsPerHost"
+	TLSSecretNameKey             = "tlsSecretName"
+	AuthSpecKey                  = "authSpec"
+	ClientIDTemplateKey          = "clientIdTemplate"
 
 	KafkaChannelSeparator = "."
 
@@ -38,12 +44,26 @@ const (
 
 	DefaultMaxIdleConns        = 1000
 	DefaultMaxIdleConnsPerHost = 100
+
+	// TLSMountPath is where the dispatcher mounts the TLS Secret used to serve the channels over HTTPS.
+	TLSMountPath = "/etc/kafka-channel-tls"
+	// TLSPort is the port of the dispatcher's HTTPS ingress.
+	TLSPort = 8443
 )
 
 type KafkaConfig struct {
 	Brokers             []string
 	MaxIdleConns        int32
 	MaxIdleConnsPerHost int32
+	// TLSSecretName is the name of the kubernetes.io/tls Secret, in the namespace of the dispatcher, holding the
+	// serving certificate of the channels. The channels are served over HTTPS when set.
+	TLSSecretName string
+	// AuthSpec holds the bootstrap servers and the SASL / TLS settings of the Kafka cluster in the same format as
+	// the KafkaSource, referencing Secrets in the namespace of the controller and dispatcher (optional).
+	AuthSpec *bindingsv1beta1.KafkaAuthSpec
+	// ClientIDTemplate renders the client.id of the Kafka clients of the controller and dispatcher so that broker
+	// quotas and logs can attribute their traffic (optional, see client.ClientIDFields for the available fields).
+	ClientIDTemplate string
 }
 
 // GetKafkaConfig returns the details of the Kafka cluster.
@@ -57,17 +77,34 @@ func GetKafkaConfig(configMap map[string]string) (*KafkaConfig, error) {
 		MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
 	}
 
-	var bootstrapServers string
+	var bootstrapServers, authSpec string
 
 	err := configmap.Parse(configMap,
 		configmap.AsString(BrokerConfigMapKey, &bootstrapServers),
 		configmap.AsInt32(MaxIdleConnectionsKey, &config.MaxIdleConns),
 		configmap.AsInt32(MaxIdleConnectionsPerHostKey, &config.MaxIdleConnsPerHost),
+		configmap.AsString(TLSSecretNameKey, &config.TLSSecretName),
+		configmap.AsString(AuthSpecKey, &authSpec),
+		configmap.AsString(ClientIDTemplateKey, &config.ClientIDTemplate),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if _, err := client.NewClientID(config.ClientIDTemplate, client.ClientIDFields{}); err != nil {
+		return nil, err
+	}
+
+	// The bootstrap servers of the auth spec take precedence over the bootstrapServers key
+	config.AuthSpec, err = client.ParseAuthSpec(authSpec)
+	if err != nil {
+		return nil, err
+	}
+	if config.AuthSpec != nil {
+		config.Brokers = config.AuthSpec.BootstrapServers
+		return config, nil
+	}
+
 	if bootstrapServers == "" {
 		return nil, errors.New("missing or empty key bootstrapServers in configuration")
 	}
@@ -87,6 +124,15 @@ func TopicName(separator, namespace, name string) string {
 	return strings.Join(topic, separator)
 }
 
+// ChannelTopicName returns the name of the channel's topic, unless overridden by the topic annotation of a
+// channel migrated from another implementation.
+func ChannelTopicName(channel *v1beta1.KafkaChannel) string {
+	if topic := channel.Annotations[v1beta1.TopicAnnotation]; topic != "" {
+		return topic
+	}
+	return TopicName(KafkaChannelSeparator, channel.Namespace, channel.Name)
+}
+
 func FindContainer(d *appsv1.Deployment, containerName string) *corev1.Container {
 	for i := range d.Spec.Template.Spec.Containers {
 		if d.Spec.Template.Spec.Containers[i].Name == containerName {
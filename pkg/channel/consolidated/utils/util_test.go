@@ -23,6 +23,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	_ "knative.dev/pkg/system/testing"
 )
 
@@ -42,6 +45,18 @@ func TestGenerateTopicNameWithHyphen(t *testing.T) {
 	}
 }
 
+func TestChannelTopicName(t *testing.T) {
+	channel := &v1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Namespace: "channel-namespace", Name: "channel-name"}}
+	if actual := ChannelTopicName(channel); actual != "knative-messaging-kafka.channel-namespace.channel-name" {
+		t.Errorf("Expected the default topic name. Actual '%s'", actual)
+	}
+
+	channel.Annotations = map[string]string{v1beta1.TopicAnnotation: "channel-namespace.channel-name"}
+	if actual := ChannelTopicName(channel); actual != "channel-namespace.channel-name" {
+		t.Errorf("Expected the annotated topic name. Actual '%s'", actual)
+	}
+}
+
 func TestGetKafkaConfig(t *testing.T) {
 
 	testCases := []struct {
@@ -142,6 +157,59 @@ func TestGetKafkaConfig(t *testing.T) {
 				MaxIdleConnsPerHost: 600,
 			},
 		},
+		{
+			name: "tls secret",
+			data: map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "tlsSecretName": "kafka-channel-tls"},
+			expected: &KafkaConfig{
+				Brokers:             []string{"kafkabroker.kafka:9092"},
+				MaxIdleConns:        1000,
+				MaxIdleConnsPerHost: 100,
+				TLSSecretName:       "kafka-channel-tls",
+			},
+		},
+		{
+			name: "auth spec",
+			data: map[string]string{"bootstrapServers": "ignored.kafka:9092", "authSpec": "bootstrapServers:\n- kafkabroker1.kafka:9093\n- kafkabroker2.kafka:9093\nnet:\n  sasl:\n    enable: true\n    user:\n      secretKeyRef:\n        name: kafka-auth\n        key: user\n"},
+			expected: &KafkaConfig{
+				Brokers:             []string{"kafkabroker1.kafka:9093", "kafkabroker2.kafka:9093"},
+				MaxIdleConns:        1000,
+				MaxIdleConnsPerHost: 100,
+				AuthSpec: &bindingsv1beta1.KafkaAuthSpec{
+					BootstrapServers: []string{"kafkabroker1.kafka:9093", "kafkabroker2.kafka:9093"},
+					Net: bindingsv1beta1.KafkaNetSpec{
+						SASL: bindingsv1beta1.KafkaSASLSpec{
+							Enable: true,
+							User: bindingsv1beta1.SecretValueFromSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-auth"},
+									Key:                  "user",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "auth spec without bootstrapServers",
+			data:     map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "authSpec": "net:\n  tls:\n    enable: true\n"},
+			getError: "KafkaAuthSpec is missing bootstrapServers",
+		},
+		{
+			name: "client id template",
+			data: map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "clientIdTemplate": "{{.Component}}.{{.Pod}}"},
+			expected: &KafkaConfig{
+				Brokers:             []string{"kafkabroker.kafka:9092"},
+				MaxIdleConns:        1000,
+				MaxIdleConnsPerHost: 100,
+				ClientIDTemplate:    "{{.Component}}.{{.Pod}}",
+			},
+		},
+		{
+			name:     "invalid client id template",
+			data:     map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "clientIdTemplate": "{{.Unknown}}"},
+			getError: `invalid client.id template "{{.Unknown}}": template: clientId:1:2: executing "clientId" at <.Unknown>: can't evaluate field Unknown in type client.ClientIDFields`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/resourcesemantics/validation"
+
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+	messaginglisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+)
+
+// channelQuota enforces the configured quota of each namespace, rejecting the KafkaChannels which would exceed the
+// number of KafkaChannels or the total number of partitions allowed in their namespace. The existing KafkaChannels
+// are counted from the informer's cache, so that runaway automation is stopped without querying the API server.
+type channelQuota struct {
+	lister messaginglisters.KafkaChannelLister
+}
+
+// callback returns the validation callback enforcing the quotas when KafkaChannels are created or updated.
+func (q *channelQuota) callback() validation.Callback {
+	return validation.NewCallback(q.validate, webhook.Create, webhook.Update)
+}
+
+// validate rejects the KafkaChannel if creating it, or increasing its partitions, exceeds its namespace's quota.
+// Updates which do not increase the partitions are always allowed, so that the KafkaChannels of a namespace which
+// is already over its (e.g. newly lowered) quota can still be modified.
+func (q *channelQuota) validate(ctx context.Context, u *unstructured.Unstructured) error {
+	namespace := u.GetNamespace()
+	quota := messagingconfig.FromContextOrDefaults(ctx).ChannelQuotas.GetQuota(namespace)
+	if quota == nil || (quota.MaxChannels == 0 && quota.MaxPartitions == 0) {
+		return nil
+	}
+
+	partitions, _, err := unstructured.NestedInt64(u.Object, "spec", "numPartitions")
+	if err != nil {
+		return fmt.Errorf("invalid numPartitions: %w", err)
+	}
+
+	channels, err := q.lister.KafkaChannels(namespace).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list the KafkaChannels of namespace %s: %w", namespace, err)
+	}
+
+	// Count The Other KafkaChannels & Their Partitions (The Stored Version Of An Updated KafkaChannel Is Replaced)
+	otherChannels, totalPartitions := 0, partitions
+	var existingPartitions int64
+	exists := false
+	for _, channel := range channels {
+		if channel.Name == u.GetName() {
+			exists, existingPartitions = true, int64(channel.Spec.NumPartitions)
+			continue
+		}
+		otherChannels++
+		totalPartitions += int64(channel.Spec.NumPartitions)
+	}
+
+	if exists && partitions <= existingPartitions {
+		return nil
+	}
+	if !exists && quota.MaxChannels > 0 && otherChannels >= quota.MaxChannels {
+		return fmt.Errorf("namespace %s has reached its quota of %d KafkaChannels", namespace, quota.MaxChannels)
+	}
+	if quota.MaxPartitions > 0 && totalPartitions > quota.MaxPartitions {
+		return fmt.Errorf("namespace %s would exceed its quota of %d KafkaChannel partitions with %d partitions", namespace, quota.MaxPartitions, totalPartitions)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+	messagingv1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	messaginglisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+)
+
+func TestChannelQuotaValidate(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, channel := range []*messagingv1beta1.KafkaChannel{
+		newQuotaTestChannel("some-namespace", "channel-1", 4),
+		newQuotaTestChannel("some-namespace", "channel-2", 4),
+		newQuotaTestChannel("other-namespace", "channel-1", 100),
+	} {
+		if err := indexer.Add(channel); err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+	}
+	quota := &channelQuota{lister: messaginglisters.NewKafkaChannelLister(indexer)}
+
+	withQuota := func(channelQuota *messagingconfig.ChannelQuota) context.Context {
+		return messagingconfig.ToContext(context.Background(), &messagingconfig.Config{
+			ChannelQuotas: &messagingconfig.ChannelQuotas{
+				NamespaceQuotas: map[string]*messagingconfig.ChannelQuota{"some-namespace": channelQuota},
+			},
+		})
+	}
+
+	testCases := map[string]struct {
+		ctx        context.Context
+		name       string
+		partitions int64
+		wantErr    string
+	}{
+		"no config": {
+			ctx:        context.Background(),
+			name:       "channel-3",
+			partitions: 1000,
+		},
+		"unlimited quota": {
+			ctx:        withQuota(&messagingconfig.ChannelQuota{}),
+			name:       "channel-3",
+			partitions: 1000,
+		},
+		"within quota": {
+			ctx:        withQuota(&messagingconfig.ChannelQuota{MaxChannels: 3, MaxPartitions: 12}),
+			name:       "channel-3",
+			partitions: 4,
+		},
+		"channels exceeded": {
+			ctx:        withQuota(&messagingconfig.ChannelQuota{MaxChannels: 2}),
+			name:       "channel-3",
+			partitions: 1,
+			wantErr:    "namespace some-namespace has reached its quota of 2 KafkaChannels",
+		},
+		"partitions exceeded": {
+			ctx:        withQuota(&messagingconfig.ChannelQuota{MaxPartitions: 10}),
+			name:       "channel-3",
+			partitions: 4,
+			wantErr:    "namespace some-namespace would exceed its quota of 10 KafkaChannel partitions with 12 partitions",
+		},
+		"update within channels quota": {
+			ctx:        withQuota(&messagingconfig.ChannelQuota{MaxChannels: 2}),
+			name:       "channel-2",
+			partitions: 8,
+		},
+		"update without partitions increase": {
+			ctx:        withQuota(&messagingconfig.ChannelQuota{MaxChannels: 1, MaxPartitions: 1}),
+			name:       "channel-2",
+			partitions: 4,
+		},
+		"update exceeding partitions": {
+			ctx:        withQuota(&messagingconfig.ChannelQuota{MaxPartitions: 10}),
+			name:       "channel-2",
+			partitions: 8,
+			wantErr:    "namespace some-namespace would exceed its quota of 10 KafkaChannel partitions with 12 partitions",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			u := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "messaging.knative.dev/v1beta1",
+				"kind":       "KafkaChannel",
+				"metadata":   map[string]interface{}{"namespace": "some-namespace", "name": tc.name},
+				"spec":       map[string]interface{}{"numPartitions": tc.partitions},
+			}}
+			err := quota.validate(tc.ctx, u)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatal("Unexpected error:", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func newQuotaTestChannel(namespace, name string, partitions int32) *messagingv1beta1.KafkaChannel {
+	return &messagingv1beta1.KafkaChannel{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       messagingv1beta1.KafkaChannelSpec{NumPartitions: partitions},
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+)
+
+// kafkaSubscription wraps the Subscriptions defaulted by the KafkaChannel webhook, in order to apply the
+// configured default delivery to the Subscriptions to KafkaChannels (the Subscription's own defaulting is
+// left to the eventing webhook).
+type kafkaSubscription struct {
+	messagingv1.Subscription
+}
+
+// Verify kafkaSubscription Implements The GenericCRD & Populatable Interfaces
+var _ resourcesemantics.GenericCRD = (*kafkaSubscription)(nil)
+var _ duck.Populatable = (*kafkaSubscription)(nil)
+
+// SetDefaults applies the default delivery of the Subscription's namespace when a Subscription to a
+// KafkaChannel is created without any delivery, and retains the creator annotation of the Subscriptions
+// to KafkaChannels when they are updated (ChannelAccessPolicies rely upon it to identify their creators).
+func (s *kafkaSubscription) SetDefaults(ctx context.Context) {
+	if !isKafkaChannel(s.Spec.Channel.GroupVersionKind()) {
+		return
+	}
+	if apis.IsInUpdate(ctx) {
+		s.retainCreator(apis.GetBaseline(ctx))
+		return
+	}
+	if !apis.IsInCreate(ctx) || s.Spec.Delivery != nil {
+		return
+	}
+	if delivery := messagingconfig.FromContextOrDefaults(ctx).SubscriptionDefaults.GetDelivery(s.Namespace); delivery != nil {
+		s.Spec.Delivery = delivery.DeepCopy()
+	}
+}
+
+// retainCreator restores the creator annotation of the specified baseline Subscription, so that it can't be
+// altered (e.g. to impersonate a service account allowed by a ChannelAccessPolicy).
+func (s *kafkaSubscription) retainCreator(baseline interface{}) {
+	original, ok := baseline.(*kafkaSubscription)
+	if !ok {
+		return
+	}
+	creatorAnnotation := messaging.GroupName + apis.CreatorAnnotationSuffix
+	if creator, ok := original.Annotations[creatorAnnotation]; ok {
+		if s.Annotations == nil {
+			s.Annotations = map[string]string{}
+		}
+		s.Annotations[creatorAnnotation] = creator
+	} else {
+		delete(s.Annotations, creatorAnnotation)
+	}
+}
+
+// Validate is a no-op, the Subscriptions are validated by the eventing webhook.
+func (s *kafkaSubscription) Validate(_ context.Context) *apis.FieldError {
+	return nil
+}
+
+// DeepCopyObject retains the kafkaSubscription wrapper, which the webhook relies upon to decode Subscriptions.
+func (s *kafkaSubscription) DeepCopyObject() runtime.Object {
+	return &kafkaSubscription{Subscription: *s.Subscription.DeepCopy()}
+}
+
+// GetListType implements apis.Listable.
+func (s *kafkaSubscription) GetListType() runtime.Object {
+	return &messagingv1.SubscriptionList{}
+}
+
+// Populate implements duck.Populatable, so that the webhook treats the kafkaSubscription as a partial
+// (duck) type and only patches the defaulted fields, rather than round tripping the Subscription through
+// this (possibly older) version of its Go type and dropping any unknown fields.
+func (s *kafkaSubscription) Populate() {}
+
+// isKafkaChannel determines whether the Subscription's channel reference is a KafkaChannel (of any version).
+func isKafkaChannel(gvk schema.GroupVersionKind) bool {
+	return gvk.Group == messaging.GroupName && gvk.Kind == "KafkaChannel"
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+)
+
+func TestKafkaSubscriptionSetDefaults(t *testing.T) {
+	clusterDefault := &eventingduckv1.DeliverySpec{Retry: ptr.Int32(5)}
+	namespaceDefault := &eventingduckv1.DeliverySpec{Retry: ptr.Int32(1)}
+	subscriptionDelivery := &eventingduckv1.DeliverySpec{Retry: ptr.Int32(3)}
+	kafkaChannel := corev1.ObjectReference{APIVersion: "messaging.knative.dev/v1beta1", Kind: "KafkaChannel", Name: "channel"}
+	inMemoryChannel := corev1.ObjectReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Name: "channel"}
+
+	ctx := messagingconfig.ToContext(context.Background(), &messagingconfig.Config{
+		SubscriptionDefaults: &messagingconfig.SubscriptionDefaults{
+			ClusterDefault:    clusterDefault,
+			NamespaceDefaults: map[string]*eventingduckv1.DeliverySpec{"some-namespace": namespaceDefault},
+		},
+	})
+
+	testCases := map[string]struct {
+		ctx       context.Context
+		namespace string
+		channel   corev1.ObjectReference
+		delivery  *eventingduckv1.DeliverySpec
+		want      *eventingduckv1.DeliverySpec
+	}{
+		"cluster default": {
+			ctx:       apis.WithinCreate(ctx),
+			namespace: "other-namespace",
+			channel:   kafkaChannel,
+			want:      clusterDefault,
+		},
+		"namespace default": {
+			ctx:       apis.WithinCreate(ctx),
+			namespace: "some-namespace",
+			channel:   kafkaChannel,
+			want:      namespaceDefault,
+		},
+		"specified delivery": {
+			ctx:       apis.WithinCreate(ctx),
+			namespace: "some-namespace",
+			channel:   kafkaChannel,
+			delivery:  subscriptionDelivery,
+			want:      subscriptionDelivery,
+		},
+		"other channel": {
+			ctx:       apis.WithinCreate(ctx),
+			namespace: "some-namespace",
+			channel:   inMemoryChannel,
+		},
+		"update": {
+			ctx:       apis.WithinUpdate(ctx, &kafkaSubscription{}),
+			namespace: "some-namespace",
+			channel:   kafkaChannel,
+		},
+		"no config": {
+			ctx:       apis.WithinCreate(context.Background()),
+			namespace: "some-namespace",
+			channel:   kafkaChannel,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			subscription := &kafkaSubscription{
+				Subscription: messagingv1.Subscription{
+					ObjectMeta: metav1.ObjectMeta{Namespace: tc.namespace, Name: "subscription"},
+					Spec: messagingv1.SubscriptionSpec{
+						Channel:  tc.channel,
+						Delivery: tc.delivery,
+					},
+				},
+			}
+			subscription.SetDefaults(tc.ctx)
+			if diff := cmp.Diff(tc.want, subscription.Spec.Delivery); diff != "" {
+				t.Error("Unexpected delivery (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestKafkaSubscriptionRetainsCreator(t *testing.T) {
+	const creatorAnnotation = "messaging.knative.dev/creator"
+	kafkaChannel := corev1.ObjectReference{APIVersion: "messaging.knative.dev/v1beta1", Kind: "KafkaChannel", Name: "channel"}
+	inMemoryChannel := corev1.ObjectReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Name: "channel"}
+
+	newSubscription := func(channel corev1.ObjectReference, annotations map[string]string) *kafkaSubscription {
+		return &kafkaSubscription{
+			Subscription: messagingv1.Subscription{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "some-namespace", Name: "subscription", Annotations: annotations},
+				Spec:       messagingv1.SubscriptionSpec{Channel: channel},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		ctx         context.Context
+		channel     corev1.ObjectReference
+		annotations map[string]string
+		want        map[string]string
+	}{
+		"altered creator": {
+			ctx:         apis.WithinUpdate(context.Background(), newSubscription(kafkaChannel, map[string]string{creatorAnnotation: "alice"})),
+			channel:     kafkaChannel,
+			annotations: map[string]string{creatorAnnotation: "system:serviceaccount:hub:admin", "other": "value"},
+			want:        map[string]string{creatorAnnotation: "alice", "other": "value"},
+		},
+		"removed creator": {
+			ctx:     apis.WithinUpdate(context.Background(), newSubscription(kafkaChannel, map[string]string{creatorAnnotation: "alice"})),
+			channel: kafkaChannel,
+			want:    map[string]string{creatorAnnotation: "alice"},
+		},
+		"added creator": {
+			ctx:         apis.WithinUpdate(context.Background(), newSubscription(kafkaChannel, nil)),
+			channel:     kafkaChannel,
+			annotations: map[string]string{creatorAnnotation: "system:serviceaccount:hub:admin"},
+			want:        map[string]string{},
+		},
+		"other channel": {
+			ctx:         apis.WithinUpdate(context.Background(), newSubscription(inMemoryChannel, map[string]string{creatorAnnotation: "alice"})),
+			channel:     inMemoryChannel,
+			annotations: map[string]string{creatorAnnotation: "bob"},
+			want:        map[string]string{creatorAnnotation: "bob"},
+		},
+		"create": {
+			ctx:         apis.WithinCreate(context.Background()),
+			channel:     kafkaChannel,
+			annotations: map[string]string{creatorAnnotation: "bob"},
+			want:        map[string]string{creatorAnnotation: "bob"},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			subscription := newSubscription(tc.channel, tc.annotations)
+			subscription.SetDefaults(tc.ctx)
+			if diff := cmp.Diff(tc.want, subscription.Annotations); diff != "" {
+				t.Error("Unexpected annotations (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestKafkaSubscriptionDeepCopyObject(t *testing.T) {
+	subscription := &kafkaSubscription{
+		Subscription: messagingv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "some-namespace", Name: "subscription"},
+		},
+	}
+	copied, ok := subscription.DeepCopyObject().(*kafkaSubscription)
+	if !ok {
+		t.Fatalf("Expected a *kafkaSubscription, got %T", subscription.DeepCopyObject())
+	}
+	if diff := cmp.Diff(subscription, copied); diff != "" {
+		t.Error("Unexpected copy (-want, +got):", diff)
+	}
+	if subscription.Validate(context.Background()) != nil {
+		t.Error("Expected no validation errors")
+	}
+}
@@ -20,9 +20,11 @@ import (
 	"context"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/logging"
 	"knative.dev/pkg/signals"
 	"knative.dev/pkg/webhook"
 	"knative.dev/pkg/webhook/certificates"
@@ -32,8 +34,10 @@ import (
 	"knative.dev/pkg/webhook/resourcesemantics/validation"
 
 	"knative.dev/eventing-kafka/pkg/apis/messaging"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
 	messagingv1alpha1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1alpha1"
 	messagingv1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkachannelinformer "knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
 )
 
 func Main(component string, options webhook.Options) {
@@ -44,6 +48,7 @@ func Main(component string, options webhook.Options) {
 	sharedmain.MainWithContext(ctx, component,
 		certificates.NewController,
 		newDefaultingAdmissionController,
+		newSubscriptionDefaultingAdmissionController,
 		newValidationAdmissionController,
 		newConversionController,
 		// TODO(mattmoor): Support config validation in eventing-kafka.
@@ -52,13 +57,21 @@ func Main(component string, options webhook.Options) {
 
 var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	// For group messaging.knative.dev
-	messagingv1alpha1.SchemeGroupVersion.WithKind("KafkaChannel"): &messagingv1alpha1.KafkaChannel{},
-	messagingv1beta1.SchemeGroupVersion.WithKind("KafkaChannel"):  &messagingv1beta1.KafkaChannel{},
+	messagingv1alpha1.SchemeGroupVersion.WithKind("KafkaChannel"):       &messagingv1alpha1.KafkaChannel{},
+	messagingv1beta1.SchemeGroupVersion.WithKind("KafkaChannel"):        &messagingv1beta1.KafkaChannel{},
+	messagingv1beta1.SchemeGroupVersion.WithKind("ChannelAccessPolicy"): &messagingv1beta1.ChannelAccessPolicy{},
 }
 
-var callbacks = map[schema.GroupVersionKind]validation.Callback{}
+// The Subscriptions are defaulted by a separate webhook, which is allowed to fail without blocking them.
+var subscriptionTypes = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
+	messagingv1.SchemeGroupVersion.WithKind("Subscription"): &kafkaSubscription{},
+}
+
+func newDefaultingAdmissionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	// Decorate contexts with the current state of the KafkaChannel classes config.
+	store := messagingconfig.NewStore(logging.FromContext(ctx).Named("channel-classes"))
+	store.WatchConfigs(cmw)
 
-func newDefaultingAdmissionController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return defaulting.NewAdmissionController(ctx,
 		// Name of the resource webhook.
 		"defaulting.webhook.kafka.messaging.knative.dev",
@@ -70,16 +83,48 @@ func newDefaultingAdmissionController(ctx context.Context, _ configmap.Watcher)
 		types,
 
 		// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
-		func(ctx context.Context) context.Context {
-			return ctx
-		},
+		store.ToContext,
 
 		// Whether to disallow unknown fields.
 		true,
 	)
 }
 
-func newValidationAdmissionController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+func newSubscriptionDefaultingAdmissionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	// Decorate contexts with the current state of the Subscription defaults config.
+	store := messagingconfig.NewStore(logging.FromContext(ctx).Named("subscription-defaults"))
+	store.WatchConfigs(cmw)
+
+	return defaulting.NewAdmissionController(ctx,
+		// Name of the resource webhook.
+		"subscription.defaulting.webhook.kafka.messaging.knative.dev",
+
+		// The path on which to serve the webhook.
+		"/subscription-defaulting",
+
+		// The resources to default.
+		subscriptionTypes,
+
+		// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
+		store.ToContext,
+
+		// Whether to disallow unknown fields (the Subscription is owned by eventing, and may have newer fields).
+		false,
+	)
+}
+
+func newValidationAdmissionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	// Decorate contexts with the current state of the KafkaChannel quotas config.
+	store := messagingconfig.NewStore(logging.FromContext(ctx).Named("channel-quotas"))
+	store.WatchConfigs(cmw)
+
+	// Enforce the quotas of the namespaces on the KafkaChannels (of either version).
+	quota := &channelQuota{lister: kafkachannelinformer.Get(ctx).Lister()}
+	callbacks := map[schema.GroupVersionKind]validation.Callback{
+		messagingv1alpha1.SchemeGroupVersion.WithKind("KafkaChannel"): quota.callback(),
+		messagingv1beta1.SchemeGroupVersion.WithKind("KafkaChannel"):  quota.callback(),
+	}
+
 	return validation.NewAdmissionController(ctx,
 		// Name of the resource webhook.
 		"validation.webhook.kafka.messaging.knative.dev",
@@ -91,9 +136,7 @@ func newValidationAdmissionController(ctx context.Context, _ configmap.Watcher)
 		types,
 
 		// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
-		func(ctx context.Context) context.Context {
-			return ctx
-		},
+		store.ToContext,
 
 		// Whether to disallow unknown fields.
 		true,
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	adminutil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	kafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned"
+	injectionclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/system"
+)
+
+// The ClientId Used For All Kafka Connections Made By The CLI
+const ClientId = "kafka-eventing-cli"
+
+// Error Returned When The CLI Is Invoked With Invalid Arguments
+var ErrUsage = errors.New("invalid usage")
+
+// CLI Usage Text
+const usage = `Usage: kafka-eventing <command> [flags] [arguments]
+
+Commands:
+  channels [-n namespace]
+        List KafkaChannels with their Topics & ConsumerGroups
+  lag <namespace>/<name>
+        Show ConsumerGroup lag per Subscription & Partition
+  reset-offsets -to earliest|latest [-subscriber uid] <namespace>/<name>
+        Reset Subscription ConsumerGroup offsets (the Dispatcher must be stopped first!)
+  migrate -to distributed|consolidated [-dry-run] <namespace>/<name>
+        Move a KafkaChannel's topic & offsets to another implementation (stop the source dispatcher first!)
+  config
+        Dump the effective eventing-kafka & Sarama configuration
+  topology [-n namespace] [-o json|dot]
+        Export the graph of KafkaChannels, KafkaSources, Topics, ConsumerGroups & sinks
+  preflight [-o text|json] [-service-account name]
+        Validate the installation (settings, Kafka secret & brokers, topic creation, webhook & controller RBAC)
+  help
+        Show this usage text
+`
+
+//
+// The eventing-kafka Command Line Interface
+//
+// The CLI talks to the Kubernetes APIs for KafkaChannel / ConfigMap state, and directly to the
+// Kafka Brokers (resolved from the Kafka Secret exactly as the controller does) for Topic and
+// ConsumerGroup offsets.
+//
+type CLI struct {
+	logger      *zap.Logger
+	k8sClient   kubernetes.Interface
+	kafkaClient kafkaclientset.Interface
+	out         io.Writer
+}
+
+// CLI Constructor
+func NewCLI(logger *zap.Logger, k8sClient kubernetes.Interface, kafkaClient kafkaclientset.Interface, out io.Writer) *CLI {
+	return &CLI{
+		logger:      logger,
+		k8sClient:   k8sClient,
+		kafkaClient: kafkaClient,
+		out:         out,
+	}
+}
+
+// Run The Command Specified By The Arguments (Excluding The Program Name)
+func (c *CLI) Run(ctx context.Context, args []string) error {
+
+	// Validate That A Command Was Specified
+	if len(args) == 0 {
+		c.printf(usage)
+		return ErrUsage
+	}
+
+	// Delegate To The Appropriate Command
+	switch args[0] {
+	case "channels":
+		return c.channels(ctx, args[1:])
+	case "lag":
+		return c.lag(ctx, args[1:])
+	case "reset-offsets":
+		return c.resetOffsets(ctx, args[1:])
+	case "migrate":
+		return c.migrate(ctx, args[1:])
+	case "config":
+		return c.config(ctx, args[1:])
+	case "topology":
+		return c.topology(ctx, args[1:])
+	case "preflight":
+		return c.preflight(ctx, args[1:])
+	case "help", "-h", "-help", "--help":
+		c.printf(usage)
+		return nil
+	default:
+		c.printf(usage)
+		return fmt.Errorf("unknown command %q: %w", args[0], ErrUsage)
+	}
+}
+
+// Load The Sarama & EventingKafka Configuration From The ConfigMap (Same As The Controller)
+func (c *CLI) loadSettings(ctx context.Context) (*sarama.Config, *config.EventingKafkaConfig, error) {
+	ctx = context.WithValue(ctx, injectionclient.Key{}, c.k8sClient)
+	return kafkasarama.LoadSettings(ctx)
+}
+
+// Resolve The Single Kafka Secret In The Knative Eventing Namespace (Same As The Controller's Kafka AdminClient)
+func (c *CLI) resolveKafkaSecret(ctx context.Context) (*corev1.Secret, error) {
+
+	// Get A List Of The Kafka Secrets
+	kafkaSecrets, err := adminutil.GetKafkaSecrets(ctx, c.k8sClient, system.Namespace())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kafka secrets: %w", err)
+	}
+
+	// Currently Only Support One Kafka Secret
+	if len(kafkaSecrets.Items) != 1 {
+		return nil, fmt.Errorf("expected 1 Kafka secret in namespace %s but found %d", system.Namespace(), len(kafkaSecrets.Items))
+	}
+	kafkaSecret := &kafkaSecrets.Items[0]
+
+	// Validate Secret Data
+	if !adminutil.ValidateKafkaSecret(c.logger, kafkaSecret) {
+		return nil, fmt.Errorf("invalid Kafka secret %s", kafkaSecret.Name)
+	}
+
+	// Return The Valid Kafka Secret
+	return kafkaSecret, nil
+}
+
+// Create The KafkaOperations Using The Brokers & Credentials From The Kafka Secret
+func (c *CLI) newKafkaOperations(ctx context.Context) (KafkaOperations, error) {
+
+	// Load The Sarama Configuration
+	saramaConfig, _, err := c.loadSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	// Resolve The Kafka Secret
+	kafkaSecret, err := c.resolveKafkaSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract The Relevant Data From The Kafka Secret & Update The Sarama Config
+	brokers := strings.Split(string(kafkaSecret.Data[constants.KafkaSecretKeyBrokers]), ",")
+	username := string(kafkaSecret.Data[constants.KafkaSecretKeyUsername])
+	password := string(kafkaSecret.Data[constants.KafkaSecretKeyPassword])
+	kafkasarama.UpdateSaramaConfig(saramaConfig, ClientId, username, password)
+
+	// Create The KafkaOperations
+	return NewKafkaOperationsWrapper(brokers, saramaConfig)
+}
+
+// Utility Function For Formatted Output
+func (c *CLI) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(c.out, format, args...)
+}
+
+// Utility Function For Parsing A "<namespace>/<name>" KafkaChannel Argument
+func parseChannelKey(args []string) (string, string, error) {
+	if len(args) != 1 {
+		return "", "", fmt.Errorf("expected a single <namespace>/<name> argument: %w", ErrUsage)
+	}
+	parts := strings.Split(args[0], "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("invalid KafkaChannel %q, expected <namespace>/<name>: %w", args[0], ErrUsage)
+	}
+	return parts[0], parts[1], nil
+}
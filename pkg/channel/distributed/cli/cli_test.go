@@ -0,0 +1,236 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	sourcesv1beta1 "knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	commontesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
+	kafkafake "knative.dev/eventing-kafka/pkg/client/clientset/versioned/fake"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
+)
+
+// Test Data
+const (
+	testNamespace     = "test-namespace"
+	testName          = "test-name"
+	testTopic         = testNamespace + "." + testName
+	testSubscriberUID = "test-subscriber-uid"
+	testGroupId       = "kafka." + testSubscriberUID
+	testSecretName    = "test-kafka-secret"
+	testBrokers       = "TestBroker1:9092,TestBroker2:9092"
+	testUsername      = "TestUsername"
+	testPassword      = "TestPassword"
+)
+
+// Test The Run() Functionality's Command Dispatching
+func TestRun(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name   string
+		args   []string
+		usage  bool
+		errMsg string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "No Command", args: []string{}, usage: true, errMsg: ErrUsage.Error()},
+		{name: "Help", args: []string{"help"}, usage: true},
+		{name: "Unknown Command", args: []string{"foo"}, usage: true, errMsg: "unknown command \"foo\": invalid usage"},
+		{name: "Lag Without Channel", args: []string{"lag"}, errMsg: "expected a single <namespace>/<name> argument: invalid usage"},
+		{name: "Lag With Invalid Channel", args: []string{"lag", "foo"}, errMsg: "invalid KafkaChannel \"foo\", expected <namespace>/<name>: invalid usage"},
+		{name: "Migrate Without To", args: []string{"migrate", testNamespace + "/" + testName}, errMsg: "invalid -to value \"\", expected \"distributed\" or \"consolidated\": invalid usage"},
+		{name: "Reset Offsets Without To", args: []string{"reset-offsets", testNamespace + "/" + testName}, errMsg: "invalid -to value \"\", expected \"earliest\" or \"latest\": invalid usage"},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cli, out := createTestCLI(t)
+			err := cli.Run(context.TODO(), testCase.args)
+			if len(testCase.errMsg) > 0 {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, ErrUsage))
+				assert.Equal(t, testCase.errMsg, err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, testCase.usage, strings.Contains(out.String(), "Usage: kafka-eventing"))
+		})
+	}
+}
+
+// Test The resolveKafkaSecret() Functionality
+func TestResolveKafkaSecret(t *testing.T) {
+
+	// Valid Kafka Secret
+	cli, _ := createTestCLI(t, createTestKafkaSecret(testSecretName, testBrokers))
+	secret, err := cli.resolveKafkaSecret(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, testSecretName, secret.Name)
+
+	// No Kafka Secret
+	cli, _ = createTestCLI(t)
+	secret, err = cli.resolveKafkaSecret(context.TODO())
+	assert.Nil(t, secret)
+	assert.Equal(t, "expected 1 Kafka secret in namespace knative-eventing but found 0", err.Error())
+
+	// Invalid Kafka Secret
+	cli, _ = createTestCLI(t, createTestKafkaSecret(testSecretName, ""))
+	secret, err = cli.resolveKafkaSecret(context.TODO())
+	assert.Nil(t, secret)
+	assert.Equal(t, "invalid Kafka secret "+testSecretName, err.Error())
+}
+
+// Test The newKafkaOperations() Functionality Resolves Brokers & Credentials From The Kafka Secret
+func TestNewKafkaOperations(t *testing.T) {
+
+	// Mock The KafkaOperations Creation & Capture The Brokers / Config
+	var actualBrokers []string
+	var actualConfig *sarama.Config
+	newKafkaOperationsWrapperPlaceholder := NewKafkaOperationsWrapper
+	NewKafkaOperationsWrapper = func(brokers []string, config *sarama.Config) (KafkaOperations, error) {
+		actualBrokers = brokers
+		actualConfig = config
+		return &MockKafkaOperations{}, nil
+	}
+	defer func() { NewKafkaOperationsWrapper = newKafkaOperationsWrapperPlaceholder }()
+
+	// Perform The Test
+	cli, _ := createTestCLI(t, createTestKafkaSecret(testSecretName, testBrokers))
+	kafkaOperations, err := cli.newKafkaOperations(context.TODO())
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.NotNil(t, kafkaOperations)
+	assert.Equal(t, strings.Split(testBrokers, ","), actualBrokers)
+	assert.Equal(t, ClientId, actualConfig.ClientID)
+	assert.Equal(t, testUsername, actualConfig.Net.SASL.User)
+	assert.Equal(t, testPassword, actualConfig.Net.SASL.Password)
+}
+
+// Utility Function For Creating A CLI With Fake Clients & The Settings ConfigMap (Plus Specified Objects)
+func createTestCLI(t *testing.T, objects ...runtime.Object) (*CLI, *bytes.Buffer) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace))
+	k8sObjects := []runtime.Object{commontesting.GetTestSaramaConfigMap(commontesting.OldSaramaConfig, commontesting.TestEKConfig)}
+	var kafkaObjects []runtime.Object
+	for _, object := range objects {
+		switch object.(type) {
+		case *kafkav1beta1.KafkaChannel, *sourcesv1beta1.KafkaSource:
+			kafkaObjects = append(kafkaObjects, object)
+		default:
+			k8sObjects = append(k8sObjects, object)
+		}
+	}
+	out := &bytes.Buffer{}
+	cli := NewCLI(logtesting.TestLogger(t).Desugar(), fake.NewSimpleClientset(k8sObjects...), kafkafake.NewSimpleClientset(kafkaObjects...), out)
+	return cli, out
+}
+
+// Utility Function For Creating A Kafka Secret
+func createTestKafkaSecret(name string, brokers string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: commonconstants.KnativeEventingNamespace,
+			Labels:    map[string]string{constants.KafkaSecretLabel: "true"},
+		},
+		Data: map[string][]byte{
+			constants.KafkaSecretKeyBrokers:  []byte(brokers),
+			constants.KafkaSecretKeyUsername: []byte(testUsername),
+			constants.KafkaSecretKeyPassword: []byte(testPassword),
+		},
+	}
+}
+
+// Utility Function For Creating A KafkaChannel With The Specified Subscribers
+func createTestKafkaChannel(subscriberUIDs ...string) *kafkav1beta1.KafkaChannel {
+	kafkaChannel := &kafkav1beta1.KafkaChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+	}
+	for _, subscriberUID := range subscriberUIDs {
+		kafkaChannel.Spec.Subscribers = append(kafkaChannel.Spec.Subscribers, eventingduck.SubscriberSpec{UID: types.UID(subscriberUID)})
+	}
+	return kafkaChannel
+}
+
+// Mock KafkaOperations Implementation
+type MockKafkaOperations struct {
+	offsets          map[int64]map[int32]int64
+	committedOffsets map[string]map[int32]int64
+	resetOffsets     map[string]map[int32]int64
+	groupMembers     map[string]int
+	createTopicErr   error
+	validatedTopics  []string
+	closed           bool
+}
+
+var _ KafkaOperations = &MockKafkaOperations{}
+
+func (m *MockKafkaOperations) Offsets(_ string, time int64) (map[int32]int64, error) {
+	return m.offsets[time], nil
+}
+
+func (m *MockKafkaOperations) CommittedOffsets(groupId string, _ string) (map[int32]int64, error) {
+	return m.committedOffsets[groupId], nil
+}
+
+func (m *MockKafkaOperations) ResetOffsets(groupId string, _ string, offsets map[int32]int64) error {
+	if m.resetOffsets == nil {
+		m.resetOffsets = make(map[string]map[int32]int64)
+	}
+	m.resetOffsets[groupId] = offsets
+	if m.committedOffsets == nil {
+		m.committedOffsets = make(map[string]map[int32]int64)
+	}
+	m.committedOffsets[groupId] = offsets
+	return nil
+}
+
+func (m *MockKafkaOperations) GroupMembers(groupId string) (int, error) {
+	return m.groupMembers[groupId], nil
+}
+
+func (m *MockKafkaOperations) ValidateCreateTopic(topic string, _ int32, _ int16) error {
+	m.validatedTopics = append(m.validatedTopics, topic)
+	return m.createTopicErr
+}
+
+func (m *MockKafkaOperations) Close() error {
+	m.closed = true
+	return nil
+}
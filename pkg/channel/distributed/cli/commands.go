@@ -0,0 +1,276 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Shopify/sarama"
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	controllerutil "knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+// List The KafkaChannels With Their Topics & ConsumerGroups
+func (c *CLI) channels(ctx context.Context, args []string) error {
+
+	// Parse The Command Flags
+	flagSet := c.newFlagSet("channels")
+	namespace := flagSet.String("n", metav1.NamespaceAll, "The namespace of the KafkaChannels to list (default all namespaces)")
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrUsage)
+	}
+
+	// Get The KafkaChannels
+	kafkaChannels, err := c.kafkaClient.MessagingV1beta1().KafkaChannels(*namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list KafkaChannels: %w", err)
+	}
+
+	// Output The KafkaChannels
+	writer := tabwriter.NewWriter(c.out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAMESPACE\tNAME\tREADY\tTOPIC\tCONSUMER GROUPS")
+	for _, kafkaChannel := range kafkaChannels.Items {
+		groupIds := make([]string, 0, len(kafkaChannel.Spec.Subscribers))
+		for _, subscriber := range kafkaChannel.Spec.Subscribers {
+			groupIds = append(groupIds, util.GroupId(string(subscriber.UID)))
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%t\t%s\t%s\n",
+			kafkaChannel.Namespace,
+			kafkaChannel.Name,
+			kafkaChannel.Status.IsReady(),
+			controllerutil.TopicName(&kafkaChannel),
+			strings.Join(groupIds, ","))
+	}
+	return writer.Flush()
+}
+
+// Show The ConsumerGroup Lag Per Subscription & Partition
+func (c *CLI) lag(ctx context.Context, args []string) error {
+
+	// Parse The Command Flags & Arguments
+	flagSet := c.newFlagSet("lag")
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrUsage)
+	}
+	namespace, name, err := parseChannelKey(flagSet.Args())
+	if err != nil {
+		return err
+	}
+
+	// Get The KafkaChannel
+	kafkaChannel, err := c.getKafkaChannel(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	subscribers := kafkaChannel.Spec.Subscribers
+
+	// Create The KafkaOperations
+	kafkaOperations, err := c.newKafkaOperations(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = kafkaOperations.Close() }()
+
+	// Get The End Offsets Of The KafkaChannel's Topic
+	topic := controllerutil.TopicName(kafkaChannel)
+	endOffsets, err := kafkaOperations.Offsets(topic, sarama.OffsetNewest)
+	if err != nil {
+		return fmt.Errorf("failed to get offsets of topic %s: %w", topic, err)
+	}
+
+	// Output The Lag For Each Subscriber's ConsumerGroup & Partition
+	writer := tabwriter.NewWriter(c.out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SUBSCRIBER\tCONSUMER GROUP\tPARTITION\tOFFSET\tEND\tLAG")
+	for _, subscriber := range subscribers {
+		groupId := util.GroupId(string(subscriber.UID))
+		committedOffsets, err := kafkaOperations.CommittedOffsets(groupId, topic)
+		if err != nil {
+			return fmt.Errorf("failed to get offsets of consumer group %s: %w", groupId, err)
+		}
+		for _, partition := range sortedPartitions(endOffsets) {
+			offset, lag := "-", "-"
+			if committedOffset, ok := committedOffsets[partition]; ok && committedOffset >= 0 {
+				offset = fmt.Sprint(committedOffset)
+				lag = fmt.Sprint(endOffsets[partition] - committedOffset)
+			}
+			_, _ = fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%d\t%s\n", subscriber.UID, groupId, partition, offset, endOffsets[partition], lag)
+		}
+	}
+	return writer.Flush()
+}
+
+// Reset The ConsumerGroup Offsets Of The KafkaChannel's Subscriptions
+func (c *CLI) resetOffsets(ctx context.Context, args []string) error {
+
+	// Parse The Command Flags & Arguments
+	flagSet := c.newFlagSet("reset-offsets")
+	to := flagSet.String("to", "", "The offset to reset to, one of \"earliest\" or \"latest\"")
+	subscriberUID := flagSet.String("subscriber", "", "The UID of the single Subscriber to reset (default all Subscribers)")
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrUsage)
+	}
+	var offsetTime int64
+	switch *to {
+	case "earliest":
+		offsetTime = sarama.OffsetOldest
+	case "latest":
+		offsetTime = sarama.OffsetNewest
+	default:
+		return fmt.Errorf("invalid -to value %q, expected \"earliest\" or \"latest\": %w", *to, ErrUsage)
+	}
+	namespace, name, err := parseChannelKey(flagSet.Args())
+	if err != nil {
+		return err
+	}
+
+	// Get The KafkaChannel's Subscribers (Filtered To The Specified Subscriber If Any)
+	kafkaChannel, err := c.getKafkaChannel(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	subscribers := kafkaChannel.Spec.Subscribers
+	if len(*subscriberUID) > 0 {
+		var filteredSubscribers []eventingduck.SubscriberSpec
+		for _, subscriber := range subscribers {
+			if string(subscriber.UID) == *subscriberUID {
+				filteredSubscribers = append(filteredSubscribers, subscriber)
+			}
+		}
+		if len(filteredSubscribers) == 0 {
+			return fmt.Errorf("KafkaChannel %s/%s has no subscriber with UID %s", namespace, name, *subscriberUID)
+		}
+		subscribers = filteredSubscribers
+	}
+
+	// Create The KafkaOperations
+	kafkaOperations, err := c.newKafkaOperations(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = kafkaOperations.Close() }()
+
+	// Get The Target Offsets Of The KafkaChannel's Topic
+	topic := controllerutil.TopicName(kafkaChannel)
+	offsets, err := kafkaOperations.Offsets(topic, offsetTime)
+	if err != nil {
+		return fmt.Errorf("failed to get offsets of topic %s: %w", topic, err)
+	}
+
+	// Reset The Offsets Of Each Subscriber's ConsumerGroup
+	for _, subscriber := range subscribers {
+		groupId := util.GroupId(string(subscriber.UID))
+		if err := kafkaOperations.ResetOffsets(groupId, topic, offsets); err != nil {
+			return fmt.Errorf("failed to reset offsets of consumer group %s: %w", groupId, err)
+		}
+		c.printf("Reset offsets of consumer group %s to %s\n", groupId, *to)
+	}
+	return nil
+}
+
+// Dump The Effective eventing-kafka & Sarama Configuration
+func (c *CLI) config(ctx context.Context, args []string) error {
+
+	// Parse The Command Flags
+	flagSet := c.newFlagSet("config")
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrUsage)
+	}
+
+	// Load The Sarama & EventingKafka Configuration
+	saramaConfig, ekConfig, err := c.loadSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	// Resolve The Kafka Secret
+	kafkaSecret, err := c.resolveKafkaSecret(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Output The EventingKafka Configuration
+	ekConfigYaml, err := yaml.Marshal(ekConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal eventing-kafka config: %w", err)
+	}
+	c.printf("eventing-kafka:\n%s\n", indent(string(ekConfigYaml)))
+
+	// Output The Kafka Secret (Without Password)
+	c.printf("kafkaSecret:\n")
+	c.printf("  name: %s\n", kafkaSecret.Name)
+	c.printf("  brokers: %s\n", kafkaSecret.Data[constants.KafkaSecretKeyBrokers])
+	c.printf("  username: %s\n\n", kafkaSecret.Data[constants.KafkaSecretKeyUsername])
+
+	// Output The Relevant Effective Sarama Configuration
+	c.printf("sarama:\n")
+	c.printf("  version: %s\n", saramaConfig.Version)
+	c.printf("  net.tls.enable: %t\n", saramaConfig.Net.TLS.Enable)
+	c.printf("  net.sasl.enable: %t\n", saramaConfig.Net.SASL.Enable)
+	c.printf("  net.sasl.mechanism: %s\n", saramaConfig.Net.SASL.Mechanism)
+	c.printf("  metadata.refreshFrequency: %s\n", saramaConfig.Metadata.RefreshFrequency)
+	c.printf("  consumer.offsets.initial: %d\n", saramaConfig.Consumer.Offsets.Initial)
+	c.printf("  consumer.offsets.autoCommit.interval: %s\n", saramaConfig.Consumer.Offsets.AutoCommit.Interval)
+	c.printf("  consumer.offsets.retention: %s\n", saramaConfig.Consumer.Offsets.Retention)
+	c.printf("  producer.requiredAcks: %d\n", saramaConfig.Producer.RequiredAcks)
+	c.printf("  producer.idempotent: %t\n", saramaConfig.Producer.Idempotent)
+	return nil
+}
+
+// Get The Specified KafkaChannel
+func (c *CLI) getKafkaChannel(ctx context.Context, namespace string, name string) (*kafkav1beta1.KafkaChannel, error) {
+	kafkaChannel, err := c.kafkaClient.MessagingV1beta1().KafkaChannels(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KafkaChannel %s/%s: %w", namespace, name, err)
+	}
+	return kafkaChannel, nil
+}
+
+// Create A FlagSet For The Specified Command Which Reports Errors Rather Than Exiting
+func (c *CLI) newFlagSet(command string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(command, flag.ContinueOnError)
+	flagSet.SetOutput(c.out)
+	return flagSet
+}
+
+// Utility Function For Getting The Partitions Of An Offset Map In Ascending Order
+func sortedPartitions(offsets map[int32]int64) []int32 {
+	partitions := make([]int32, 0, len(offsets))
+	for partition := range offsets {
+		partitions = append(partitions, partition)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	return partitions
+}
+
+// Utility Function For Indenting Multi-Line YAML By Two Spaces
+func indent(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "  " + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test The "channels" Command
+func TestChannels(t *testing.T) {
+	cli, out := createTestCLI(t, createTestKafkaChannel(testSubscriberUID, "other-uid"))
+	err := cli.Run(context.TODO(), []string{"channels", "-n", testNamespace})
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"NAMESPACE       NAME       READY  TOPIC                     CONSUMER GROUPS\n"+
+		"test-namespace  test-name  false  test-namespace.test-name  kafka.test-subscriber-uid,kafka.other-uid\n",
+		out.String())
+}
+
+// Test The "lag" Command
+func TestLag(t *testing.T) {
+
+	// Mock The KafkaOperations
+	mockKafkaOperations := &MockKafkaOperations{
+		offsets:          map[int64]map[int32]int64{sarama.OffsetNewest: {0: 10, 1: 20}},
+		committedOffsets: map[string]map[int32]int64{testGroupId: {0: 4, 1: -1}},
+	}
+	restore := mockKafkaOperationsWrapper(mockKafkaOperations)
+	defer restore()
+
+	// Perform The Test
+	cli, out := createTestCLI(t, createTestKafkaSecret(testSecretName, testBrokers), createTestKafkaChannel(testSubscriberUID))
+	err := cli.Run(context.TODO(), []string{"lag", testNamespace + "/" + testName})
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.True(t, mockKafkaOperations.closed)
+	assert.Equal(t, ""+
+		"SUBSCRIBER           CONSUMER GROUP             PARTITION  OFFSET  END  LAG\n"+
+		"test-subscriber-uid  kafka.test-subscriber-uid  0          4       10   6\n"+
+		"test-subscriber-uid  kafka.test-subscriber-uid  1          -       20   -\n",
+		out.String())
+}
+
+// Test The "lag" Command With An Unknown KafkaChannel
+func TestLagUnknownChannel(t *testing.T) {
+	cli, _ := createTestCLI(t, createTestKafkaSecret(testSecretName, testBrokers))
+	err := cli.Run(context.TODO(), []string{"lag", testNamespace + "/unknown"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed to get KafkaChannel test-namespace/unknown")
+}
+
+// Test The "reset-offsets" Command
+func TestResetOffsets(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name           string
+		args           []string
+		expectedResets map[string]map[int32]int64
+		errMsg         string
+	}
+
+	// Test Data
+	earliestOffsets := map[int32]int64{0: 1, 1: 2}
+	latestOffsets := map[int32]int64{0: 10, 1: 20}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:           "All Subscribers To Earliest",
+			args:           []string{"reset-offsets", "-to", "earliest", testNamespace + "/" + testName},
+			expectedResets: map[string]map[int32]int64{testGroupId: earliestOffsets, "kafka.other-uid": earliestOffsets},
+		},
+		{
+			name:           "Single Subscriber To Latest",
+			args:           []string{"reset-offsets", "-to", "latest", "-subscriber", testSubscriberUID, testNamespace + "/" + testName},
+			expectedResets: map[string]map[int32]int64{testGroupId: latestOffsets},
+		},
+		{
+			name:   "Unknown Subscriber",
+			args:   []string{"reset-offsets", "-to", "latest", "-subscriber", "unknown", testNamespace + "/" + testName},
+			errMsg: "KafkaChannel test-namespace/test-name has no subscriber with UID unknown",
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockKafkaOperations := &MockKafkaOperations{
+				offsets: map[int64]map[int32]int64{sarama.OffsetOldest: earliestOffsets, sarama.OffsetNewest: latestOffsets},
+			}
+			restore := mockKafkaOperationsWrapper(mockKafkaOperations)
+			defer restore()
+
+			cli, _ := createTestCLI(t, createTestKafkaSecret(testSecretName, testBrokers), createTestKafkaChannel(testSubscriberUID, "other-uid"))
+			err := cli.Run(context.TODO(), testCase.args)
+			if len(testCase.errMsg) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.errMsg, err.Error())
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.expectedResets, mockKafkaOperations.resetOffsets)
+			}
+		})
+	}
+}
+
+// Test The "config" Command
+func TestConfig(t *testing.T) {
+	cli, out := createTestCLI(t, createTestKafkaSecret(testSecretName, testBrokers))
+	err := cli.Run(context.TODO(), []string{"config"})
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "eventing-kafka:\n  audit: {}\n  dispatcher:\n")
+	assert.Contains(t, out.String(), "  name: "+testSecretName+"\n")
+	assert.Contains(t, out.String(), "  brokers: "+testBrokers+"\n")
+	assert.Contains(t, out.String(), "  net.tls.enable: true\n")
+	assert.NotContains(t, out.String(), testPassword)
+}
+
+// Utility Function For Mocking The KafkaOperations Creation (Returns A Restore Function)
+func mockKafkaOperationsWrapper(mockKafkaOperations *MockKafkaOperations) func() {
+	newKafkaOperationsWrapperPlaceholder := NewKafkaOperationsWrapper
+	NewKafkaOperationsWrapper = func(_ []string, _ *sarama.Config) (KafkaOperations, error) {
+		return mockKafkaOperations, nil
+	}
+	return func() { NewKafkaOperationsWrapper = newKafkaOperationsWrapperPlaceholder }
+}
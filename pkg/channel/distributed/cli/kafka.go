@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"github.com/Shopify/sarama"
+)
+
+// The Kafka Operations Required By The CLI
+type KafkaOperations interface {
+	// Get The Offset Of Every Partition In The Topic At The Specified Time (sarama.OffsetOldest / sarama.OffsetNewest)
+	Offsets(topic string, time int64) (map[int32]int64, error)
+	// Get The Committed Offset Of Every Partition In The Topic For The ConsumerGroup (-1 If None Committed)
+	CommittedOffsets(groupId string, topic string) (map[int32]int64, error)
+	// Commit The Specified Offsets For The ConsumerGroup (Which Must Not Have Any Active Members)
+	ResetOffsets(groupId string, topic string, offsets map[int32]int64) error
+	// Get The Number Of Active Members Of The ConsumerGroup
+	GroupMembers(groupId string) (int, error)
+	// Validate That The Brokers Would Create The Topic (Without Actually Creating It)
+	ValidateCreateTopic(topic string, numPartitions int32, replicationFactor int16) error
+	Close() error
+}
+
+// KafkaOperations Wrapper To Facilitate Unit Testing
+var NewKafkaOperationsWrapper = func(brokers []string, config *sarama.Config) (KafkaOperations, error) {
+	return NewSaramaKafkaOperations(brokers, config)
+}
+
+// Ensure The SaramaKafkaOperations Struct Implements The KafkaOperations Interface
+var _ KafkaOperations = &SaramaKafkaOperations{}
+
+// Sarama Backed KafkaOperations Implementation
+type SaramaKafkaOperations struct {
+	client       sarama.Client
+	clusterAdmin sarama.ClusterAdmin
+}
+
+// Create A New Sarama Backed KafkaOperations
+func NewSaramaKafkaOperations(brokers []string, config *sarama.Config) (*SaramaKafkaOperations, error) {
+
+	// Create The Sarama Client
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create A Sarama ClusterAdmin Sharing The Client
+	clusterAdmin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	// Return The SaramaKafkaOperations
+	return &SaramaKafkaOperations{client: client, clusterAdmin: clusterAdmin}, nil
+}
+
+// Get The Offset Of Every Partition In The Topic At The Specified Time
+func (s *SaramaKafkaOperations) Offsets(topic string, time int64) (map[int32]int64, error) {
+	partitions, err := s.client.Partitions(topic)
+	if err != nil {
+		return nil, err
+	}
+	offsets := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		offset, err := s.client.GetOffset(topic, partition, time)
+		if err != nil {
+			return nil, err
+		}
+		offsets[partition] = offset
+	}
+	return offsets, nil
+}
+
+// Get The Committed Offset Of Every Partition In The Topic For The ConsumerGroup
+func (s *SaramaKafkaOperations) CommittedOffsets(groupId string, topic string) (map[int32]int64, error) {
+	partitions, err := s.client.Partitions(topic)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.clusterAdmin.ListConsumerGroupOffsets(groupId, map[string][]int32{topic: partitions})
+	if err != nil {
+		return nil, err
+	}
+	offsets := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		block := response.GetBlock(topic, partition)
+		if block == nil {
+			offsets[partition] = -1
+		} else if block.Err != sarama.ErrNoError {
+			return nil, block.Err
+		} else {
+			offsets[partition] = block.Offset
+		}
+	}
+	return offsets, nil
+}
+
+// Commit The Specified Offsets For The ConsumerGroup
+func (s *SaramaKafkaOperations) ResetOffsets(groupId string, topic string, offsets map[int32]int64) error {
+
+	// Create An OffsetManager For The ConsumerGroup
+	offsetManager, err := sarama.NewOffsetManagerFromClient(groupId, s.client)
+	if err != nil {
+		return err
+	}
+
+	// Move Each Partition's Offset (ResetOffset Only Moves Backwards & MarkOffset Only Moves Forwards)
+	for partition, offset := range offsets {
+		partitionOffsetManager, err := offsetManager.ManagePartition(topic, partition)
+		if err != nil {
+			_ = offsetManager.Close()
+			return err
+		}
+		partitionOffsetManager.ResetOffset(offset, "")
+		partitionOffsetManager.MarkOffset(offset, "")
+		partitionOffsetManager.AsyncClose()
+	}
+
+	// Closing The OffsetManager Flushes The Offsets To The Broker
+	return offsetManager.Close()
+}
+
+// Get The Number Of Active Members Of The ConsumerGroup
+func (s *SaramaKafkaOperations) GroupMembers(groupId string) (int, error) {
+	groups, err := s.clusterAdmin.DescribeConsumerGroups([]string{groupId})
+	if err != nil {
+		return 0, err
+	}
+	for _, group := range groups {
+		if group.GroupId == groupId {
+			if group.Err != sarama.ErrNoError {
+				return 0, group.Err
+			}
+			return len(group.Members), nil
+		}
+	}
+	return 0, nil
+}
+
+// Validate That The Brokers Would Create The Topic (The CreateTopics Request's ValidateOnly Mode)
+func (s *SaramaKafkaOperations) ValidateCreateTopic(topic string, numPartitions int32, replicationFactor int16) error {
+	return s.clusterAdmin.CreateTopic(topic, &sarama.TopicDetail{NumPartitions: numPartitions, ReplicationFactor: replicationFactor}, true)
+}
+
+// Close The Sarama ClusterAdmin (And The Underlying Client)
+func (s *SaramaKafkaOperations) Close() error {
+	return s.clusterAdmin.Close()
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	consolidatedutils "knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	controllerutil "knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/system"
+)
+
+// The KafkaChannel Implementations Between Which The "migrate" Command Can Move KafkaChannels
+const (
+	Distributed  = "distributed"
+	Consolidated = "consolidated"
+)
+
+// A Single Subscriber's ConsumerGroup Handoff Between The Source & Target Implementations
+type groupHandoff struct {
+	subscriber    eventingduck.SubscriberSpec
+	sourceGroupId string
+	targetGroupId string
+}
+
+//
+// Migrate A KafkaChannel Between The Distributed & Consolidated Implementations
+//
+// The KafkaChannel's Topic (Including Its Messages) Is Preserved By Pinning Its Name With The Topic Annotation,
+// Which Both Implementations Honor.  The Committed Offsets Of Each Subscriber's ConsumerGroup Are Copied To The
+// ConsumerGroup Used By The Target Implementation, And Verified, So That Delivery Continues Where It Stopped.
+// The Source Implementation's Dispatcher Must Be Stopped First, Which Is Enforced By Refusing To Migrate While
+// Any Source ConsumerGroup Has Active Members.
+//
+func (c *CLI) migrate(ctx context.Context, args []string) error {
+
+	// Parse The Command Flags & Arguments
+	flagSet := c.newFlagSet("migrate")
+	to := flagSet.String("to", "", "The target implementation, one of \"distributed\" or \"consolidated\"")
+	dryRun := flagSet.Bool("dry-run", false, "Only show the migration plan without changing anything")
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrUsage)
+	}
+	if *to != Distributed && *to != Consolidated {
+		return fmt.Errorf("invalid -to value %q, expected \"distributed\" or \"consolidated\": %w", *to, ErrUsage)
+	}
+	namespace, name, err := parseChannelKey(flagSet.Args())
+	if err != nil {
+		return err
+	}
+
+	// Get The KafkaChannel
+	kafkaChannel, err := c.kafkaClient.MessagingV1beta1().KafkaChannels(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get KafkaChannel %s/%s: %w", namespace, name, err)
+	}
+
+	// Determine The Topic & ConsumerGroup Handoffs From The Source To The Target Implementation
+	topic := sourceTopicName(kafkaChannel, *to)
+	handoffs := make([]groupHandoff, 0, len(kafkaChannel.Spec.Subscribers))
+	for _, subscriber := range kafkaChannel.Spec.Subscribers {
+		distributedGroupId := util.GroupId(string(subscriber.UID))
+		consolidatedGroupId := consolidatedGroupId(kafkaChannel, string(subscriber.UID))
+		handoff := groupHandoff{subscriber: subscriber, sourceGroupId: distributedGroupId, targetGroupId: consolidatedGroupId}
+		if *to == Distributed {
+			handoff.sourceGroupId, handoff.targetGroupId = consolidatedGroupId, distributedGroupId
+		}
+		handoffs = append(handoffs, handoff)
+	}
+
+	// Output The Migration Plan
+	c.printf("Migrating KafkaChannel %s/%s to the %s implementation using topic %s\n", namespace, name, *to, topic)
+	writer := tabwriter.NewWriter(c.out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SUBSCRIBER\tSOURCE CONSUMER GROUP\tTARGET CONSUMER GROUP")
+	for _, handoff := range handoffs {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\n", handoff.subscriber.UID, handoff.sourceGroupId, handoff.targetGroupId)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if *dryRun {
+		return nil
+	}
+
+	// Create The KafkaOperations
+	kafkaOperations, err := c.newKafkaOperations(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = kafkaOperations.Close() }()
+
+	// Refuse To Migrate While The Source Implementation Is Still Consuming The Topic
+	for _, handoff := range handoffs {
+		members, err := kafkaOperations.GroupMembers(handoff.sourceGroupId)
+		if err != nil {
+			return fmt.Errorf("failed to describe consumer group %s: %w", handoff.sourceGroupId, err)
+		}
+		if members > 0 {
+			return fmt.Errorf("consumer group %s has %d active members, the source dispatcher must be stopped first", handoff.sourceGroupId, members)
+		}
+	}
+
+	// Copy The Committed Offsets Of Each Source ConsumerGroup To The Target ConsumerGroup & Verify Them
+	for _, handoff := range handoffs {
+		if err := copyOffsets(kafkaOperations, topic, handoff.sourceGroupId, handoff.targetGroupId); err != nil {
+			return err
+		}
+		c.printf("Copied offsets of consumer group %s to %s\n", handoff.sourceGroupId, handoff.targetGroupId)
+	}
+
+	// Pin The Topic Name So That The Target Implementation Keeps Using The Existing Topic
+	if kafkaChannel.Annotations[kafkav1beta1.TopicAnnotation] != topic {
+		kafkaChannel = kafkaChannel.DeepCopy()
+		if kafkaChannel.Annotations == nil {
+			kafkaChannel.Annotations = make(map[string]string)
+		}
+		kafkaChannel.Annotations[kafkav1beta1.TopicAnnotation] = topic
+		if _, err := c.kafkaClient.MessagingV1beta1().KafkaChannels(namespace).Update(ctx, kafkaChannel, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to annotate KafkaChannel %s/%s: %w", namespace, name, err)
+		}
+		c.printf("Annotated KafkaChannel %s/%s with %s=%s\n", namespace, name, kafkav1beta1.TopicAnnotation, topic)
+	}
+
+	// Remove The Distributed Dispatcher Which Is Not Cleaned Up By The Consolidated Implementation
+	if *to == Consolidated {
+		if err := c.deleteDistributedDispatcher(ctx, kafkaChannel); err != nil {
+			return err
+		}
+	}
+
+	c.printf("Migrated KafkaChannel %s/%s to the %s implementation\n", namespace, name, *to)
+	return nil
+}
+
+// Copy The Committed Offsets Of The Source ConsumerGroup To The Target ConsumerGroup & Verify Them
+func copyOffsets(kafkaOperations KafkaOperations, topic string, sourceGroupId string, targetGroupId string) error {
+
+	// Get The Committed Offsets Of The Source ConsumerGroup (Skipping Partitions Without Any)
+	committedOffsets, err := kafkaOperations.CommittedOffsets(sourceGroupId, topic)
+	if err != nil {
+		return fmt.Errorf("failed to get offsets of consumer group %s: %w", sourceGroupId, err)
+	}
+	offsets := make(map[int32]int64, len(committedOffsets))
+	for partition, offset := range committedOffsets {
+		if offset >= 0 {
+			offsets[partition] = offset
+		}
+	}
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	// Commit The Offsets For The Target ConsumerGroup
+	if err := kafkaOperations.ResetOffsets(targetGroupId, topic, offsets); err != nil {
+		return fmt.Errorf("failed to reset offsets of consumer group %s: %w", targetGroupId, err)
+	}
+
+	// Verify The Target ConsumerGroup Resumes Exactly Where The Source ConsumerGroup Stopped
+	targetOffsets, err := kafkaOperations.CommittedOffsets(targetGroupId, topic)
+	if err != nil {
+		return fmt.Errorf("failed to get offsets of consumer group %s: %w", targetGroupId, err)
+	}
+	for _, partition := range sortedPartitions(offsets) {
+		if targetOffsets[partition] != offsets[partition] {
+			return fmt.Errorf("offset of consumer group %s partition %d is %d, expected %d", targetGroupId, partition, targetOffsets[partition], offsets[partition])
+		}
+	}
+	return nil
+}
+
+// Delete The Distributed Dispatcher Deployment & Service Of The Specified KafkaChannel
+func (c *CLI) deleteDistributedDispatcher(ctx context.Context, kafkaChannel *kafkav1beta1.KafkaChannel) error {
+
+	// Select The Dispatcher Resources By The Owning KafkaChannel's Labels
+	listOptions := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			constants.KafkaChannelDispatcherLabel: "true",
+			constants.KafkaChannelNameLabel:       kafkaChannel.Name,
+			constants.KafkaChannelNamespaceLabel:  kafkaChannel.Namespace,
+		}).String(),
+	}
+
+	// Delete The Dispatcher Deployments
+	deployments, err := c.k8sClient.AppsV1().Deployments(system.Namespace()).List(ctx, listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list dispatcher deployments: %w", err)
+	}
+	for _, deployment := range deployments.Items {
+		if err := c.k8sClient.AppsV1().Deployments(deployment.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{}); err != nil {
+			return fmt.Errorf("failed to delete dispatcher deployment %s: %w", deployment.Name, err)
+		}
+		c.printf("Deleted dispatcher deployment %s/%s\n", deployment.Namespace, deployment.Name)
+	}
+
+	// Delete The Dispatcher Services
+	services, err := c.k8sClient.CoreV1().Services(system.Namespace()).List(ctx, listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list dispatcher services: %w", err)
+	}
+	for _, service := range services.Items {
+		if err := c.k8sClient.CoreV1().Services(service.Namespace).Delete(ctx, service.Name, metav1.DeleteOptions{}); err != nil {
+			return fmt.Errorf("failed to delete dispatcher service %s: %w", service.Name, err)
+		}
+		c.printf("Deleted dispatcher service %s/%s\n", service.Namespace, service.Name)
+	}
+	return nil
+}
+
+// Get The Name Of The Topic Currently Used By The Source Implementation (The Opposite Of The Target)
+func sourceTopicName(kafkaChannel *kafkav1beta1.KafkaChannel, to string) string {
+	if to == Distributed {
+		return consolidatedutils.ChannelTopicName(kafkaChannel)
+	}
+	return controllerutil.TopicName(kafkaChannel)
+}
+
+// Get The ConsumerGroup Id Of A Subscriber In The Consolidated Implementation (Matches The Consolidated Dispatcher)
+func consolidatedGroupId(kafkaChannel *kafkav1beta1.KafkaChannel, subscriberUID string) string {
+	return fmt.Sprintf("kafka.%s.%s.%s", kafkaChannel.Namespace, kafkaChannel.Name, subscriberUID)
+}
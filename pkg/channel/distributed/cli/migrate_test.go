@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Test Data
+const (
+	testConsolidatedTopic   = "knative-messaging-kafka." + testNamespace + "." + testName
+	testConsolidatedGroupId = "kafka." + testNamespace + "." + testName + "." + testSubscriberUID
+	testDispatcherName      = testName + "-dispatcher"
+)
+
+// Test The "migrate" Command
+func TestMigrate(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name             string
+		args             []string
+		groupMembers     map[string]int
+		committedOffsets map[string]map[int32]int64
+		expectedResets   map[string]map[int32]int64
+		expectedTopic    string
+		expectDispatcher bool
+		expectedOutput   string
+		errMsg           string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:             "To Consolidated",
+			args:             []string{"migrate", "-to", "consolidated", testNamespace + "/" + testName},
+			committedOffsets: map[string]map[int32]int64{testGroupId: {0: 4, 1: -1}},
+			expectedResets:   map[string]map[int32]int64{testConsolidatedGroupId: {0: 4}},
+			expectedTopic:    testTopic,
+			expectedOutput:   "Deleted dispatcher deployment knative-eventing/" + testDispatcherName + "\n",
+		},
+		{
+			name:             "To Distributed",
+			args:             []string{"migrate", "-to", "distributed", testNamespace + "/" + testName},
+			committedOffsets: map[string]map[int32]int64{testConsolidatedGroupId: {0: 4, 1: 7}},
+			expectedResets:   map[string]map[int32]int64{testGroupId: {0: 4, 1: 7}},
+			expectedTopic:    testConsolidatedTopic,
+			expectDispatcher: true,
+			expectedOutput:   "Annotated KafkaChannel test-namespace/test-name with kafka.eventing.knative.dev/topic=" + testConsolidatedTopic + "\n",
+		},
+		{
+			name:             "Without Committed Offsets",
+			args:             []string{"migrate", "-to", "distributed", testNamespace + "/" + testName},
+			expectedTopic:    testConsolidatedTopic,
+			expectDispatcher: true,
+		},
+		{
+			name:             "Dry Run",
+			args:             []string{"migrate", "-to", "consolidated", "-dry-run", testNamespace + "/" + testName},
+			committedOffsets: map[string]map[int32]int64{testGroupId: {0: 4}},
+			expectDispatcher: true,
+			expectedOutput: "" +
+				"Migrating KafkaChannel test-namespace/test-name to the consolidated implementation using topic " + testTopic + "\n" +
+				"SUBSCRIBER           SOURCE CONSUMER GROUP      TARGET CONSUMER GROUP\n" +
+				"test-subscriber-uid  kafka.test-subscriber-uid  " + testConsolidatedGroupId + "\n",
+		},
+		{
+			name:             "Active Source Consumer Group",
+			args:             []string{"migrate", "-to", "consolidated", testNamespace + "/" + testName},
+			groupMembers:     map[string]int{testGroupId: 2},
+			committedOffsets: map[string]map[int32]int64{testGroupId: {0: 4}},
+			expectDispatcher: true,
+			errMsg:           "consumer group kafka.test-subscriber-uid has 2 active members, the source dispatcher must be stopped first",
+		},
+		{
+			name:             "Unknown KafkaChannel",
+			args:             []string{"migrate", "-to", "consolidated", testNamespace + "/unknown"},
+			expectDispatcher: true,
+			errMsg:           "failed to get KafkaChannel test-namespace/unknown: kafkachannels.messaging.knative.dev \"unknown\" not found",
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The KafkaOperations
+			mockKafkaOperations := &MockKafkaOperations{
+				groupMembers:     testCase.groupMembers,
+				committedOffsets: testCase.committedOffsets,
+			}
+			restore := mockKafkaOperationsWrapper(mockKafkaOperations)
+			defer restore()
+
+			// Perform The Test
+			cli, out := createTestCLI(t,
+				createTestKafkaSecret(testSecretName, testBrokers),
+				createTestKafkaChannel(testSubscriberUID),
+				createTestDispatcherDeployment(),
+				createTestDispatcherService())
+			err := cli.Run(context.TODO(), testCase.args)
+
+			// Verify The Results
+			if len(testCase.errMsg) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.errMsg, err.Error())
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.expectedResets, mockKafkaOperations.resetOffsets)
+			}
+			assert.Contains(t, out.String(), testCase.expectedOutput)
+
+			kafkaChannel, err := cli.kafkaClient.MessagingV1beta1().KafkaChannels(testNamespace).Get(context.TODO(), testName, metav1.GetOptions{})
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedTopic, kafkaChannel.Annotations[kafkav1beta1.TopicAnnotation])
+
+			deployments, err := cli.k8sClient.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).List(context.TODO(), metav1.ListOptions{})
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectDispatcher, len(deployments.Items) == 1)
+			_, err = cli.k8sClient.CoreV1().Services(commonconstants.KnativeEventingNamespace).Get(context.TODO(), testDispatcherName, metav1.GetOptions{})
+			assert.Equal(t, testCase.expectDispatcher, err == nil)
+		})
+	}
+}
+
+// Test The "migrate" Command With A Topic Annotation From A Previous Migration
+func TestMigrateAnnotatedTopic(t *testing.T) {
+
+	// Mock The KafkaOperations
+	mockKafkaOperations := &MockKafkaOperations{}
+	restore := mockKafkaOperationsWrapper(mockKafkaOperations)
+	defer restore()
+
+	// Perform The Test
+	kafkaChannel := createTestKafkaChannel(testSubscriberUID)
+	kafkaChannel.Annotations = map[string]string{kafkav1beta1.TopicAnnotation: testTopic}
+	cli, out := createTestCLI(t, createTestKafkaSecret(testSecretName, testBrokers), kafkaChannel)
+	err := cli.Run(context.TODO(), []string{"migrate", "-to", "distributed", testNamespace + "/" + testName})
+
+	// Verify The Original Topic Is Kept
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "to the distributed implementation using topic "+testTopic+"\n")
+	assert.NotContains(t, out.String(), "Annotated KafkaChannel")
+}
+
+// Utility Function For Creating A Distributed Dispatcher Deployment Of The Test KafkaChannel
+func createTestDispatcherDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDispatcherName,
+			Namespace: commonconstants.KnativeEventingNamespace,
+			Labels:    createTestDispatcherLabels(),
+		},
+	}
+}
+
+// Utility Function For Creating A Distributed Dispatcher Service Of The Test KafkaChannel
+func createTestDispatcherService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDispatcherName,
+			Namespace: commonconstants.KnativeEventingNamespace,
+			Labels:    createTestDispatcherLabels(),
+		},
+	}
+}
+
+// Utility Function For Creating The Labels Of The Test KafkaChannel's Dispatcher Resources
+func createTestDispatcherLabels() map[string]string {
+	return map[string]string{
+		constants.KafkaChannelDispatcherLabel: "true",
+		constants.KafkaChannelNameLabel:       testName,
+		constants.KafkaChannelNamespaceLabel:  testNamespace,
+	}
+}
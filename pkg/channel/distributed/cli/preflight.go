@@ -0,0 +1,357 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	controllerconfig "knative.dev/eventing-kafka/pkg/channel/distributed/controller/config"
+	"knative.dev/pkg/system"
+)
+
+// Preflight Check Names
+const (
+	PreflightCheckConfig  = "config"
+	PreflightCheckSecret  = "secret"
+	PreflightCheckTopics  = "topics"
+	PreflightCheckWebhook = "webhook"
+	PreflightCheckRBAC    = "rbac"
+)
+
+// Preflight Check Statuses
+const (
+	PreflightStatusPass = "Pass"
+	PreflightStatusFail = "Fail"
+	PreflightStatusSkip = "Skip"
+)
+
+// The ServiceAccount Of The Controller Whose Access Is Verified By Default
+const controllerServiceAccount = "eventing-kafka-channel-controller"
+
+// The Suffixes Of The Names Of The KafkaChannel & KafkaSource Webhook Configurations
+var webhookConfigurationSuffixes = []string{
+	".webhook.kafka.messaging.knative.dev",
+	".webhook.kafka.sources.knative.dev",
+}
+
+// The Structured Report Of The Preflight Checks Of An Installation
+type PreflightReport struct {
+	Passed bool             `json:"passed"`
+	Checks []PreflightCheck `json:"checks"`
+}
+
+// The Outcome Of A Single Preflight Check
+type PreflightCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Validate The Installation (ConfigMap, Kafka Secret & Brokers, Topic Creation, Webhook & Controller RBAC)
+func (c *CLI) preflight(ctx context.Context, args []string) error {
+
+	// Parse The Command Flags
+	flagSet := c.newFlagSet("preflight")
+	output := flagSet.String("o", "text", "The output format (text or json)")
+	serviceAccount := flagSet.String("service-account", controllerServiceAccount, "The controller ServiceAccount (in the system namespace) whose access is verified")
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrUsage)
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("invalid -o value %q, expected \"text\" or \"json\": %w", *output, ErrUsage)
+	}
+
+	// Run The Checks (Those Depending On A Failed Check Are Skipped)
+	report := c.runPreflightChecks(ctx, *serviceAccount)
+
+	// Output The Report In The Specified Format
+	if *output == "json" {
+		reportJson, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal preflight report: %w", err)
+		}
+		c.printf("%s\n", reportJson)
+	} else {
+		writer := tabwriter.NewWriter(c.out, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(writer, "CHECK\tSTATUS\tMESSAGE")
+		for _, check := range report.Checks {
+			_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\n", check.Name, check.Status, check.Message)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	// Fail The Command (Non-Zero Exit Status) If Any Check Failed
+	if !report.Passed {
+		return fmt.Errorf("%d of %d preflight checks failed", report.failures(), len(report.Checks))
+	}
+	return nil
+}
+
+// Run All The Preflight Checks & Return Their Report
+func (c *CLI) runPreflightChecks(ctx context.Context, serviceAccount string) *PreflightReport {
+
+	// The Settings Must Load & Be Valid For The Controller To Start
+	ekConfig, configCheck := c.checkConfig(ctx)
+
+	// The Kafka Secret Must Resolve & Its Brokers Accept A Connection
+	kafkaOperations, secretCheck := c.checkSecret(ctx)
+	if kafkaOperations != nil {
+		defer func() { _ = kafkaOperations.Close() }()
+	}
+
+	// Assemble The Report
+	report := &PreflightReport{
+		Checks: []PreflightCheck{
+			configCheck,
+			secretCheck,
+			checkTopics(ekConfig, kafkaOperations),
+			c.checkWebhook(ctx),
+			c.checkRBAC(ctx, serviceAccount),
+		},
+	}
+	report.Passed = report.failures() == 0
+	return report
+}
+
+// Check That The Settings ConfigMap Parses & Passes The Controller's Validation
+func (c *CLI) checkConfig(ctx context.Context) (*config.EventingKafkaConfig, PreflightCheck) {
+	check := PreflightCheck{Name: PreflightCheckConfig}
+	_, ekConfig, err := c.loadSettings(ctx)
+	if err != nil {
+		return nil, check.fail("failed to load settings: %v", err)
+	}
+	if err := controllerconfig.VerifyConfiguration(ekConfig); err != nil {
+		return nil, check.fail("%v", err)
+	}
+	return ekConfig, check.pass("settings ConfigMap %s/%s is valid", system.Namespace(), config.SettingsConfigMapName)
+}
+
+// Check That The Kafka Secret Resolves & The Brokers Accept A Connection With Its Credentials
+func (c *CLI) checkSecret(ctx context.Context) (KafkaOperations, PreflightCheck) {
+	check := PreflightCheck{Name: PreflightCheckSecret}
+	kafkaSecret, err := c.resolveKafkaSecret(ctx)
+	if err != nil {
+		return nil, check.fail("%v", err)
+	}
+	kafkaOperations, err := c.newKafkaOperations(ctx)
+	if err != nil {
+		return nil, check.fail("failed to connect to the brokers of Kafka secret %s: %v", kafkaSecret.Name, err)
+	}
+	return kafkaOperations, check.pass("connected to the brokers of Kafka secret %s", kafkaSecret.Name)
+}
+
+// Check That The Brokers Would Create A Topic With The Default Partitions & Replication Factor
+func checkTopics(ekConfig *config.EventingKafkaConfig, kafkaOperations KafkaOperations) PreflightCheck {
+	check := PreflightCheck{Name: PreflightCheckTopics}
+	switch {
+	case ekConfig == nil:
+		return check.skip("requires valid settings")
+	case kafkaOperations == nil:
+		return check.skip("requires a connection to the brokers")
+	case ekConfig.Kafka.AdminType != kafkaadmin.KafkaProvisionerName:
+		return check.skip("topics are provisioned by the %q admin type", ekConfig.Kafka.AdminType)
+	}
+	topic := system.Namespace() + ".kafka-eventing-preflight"
+	numPartitions := ekConfig.Kafka.Topic.DefaultNumPartitions
+	replicationFactor := ekConfig.Kafka.Topic.DefaultReplicationFactor
+	if err := kafkaOperations.ValidateCreateTopic(topic, numPartitions, replicationFactor); err != nil {
+		return check.fail("brokers would not create a topic with %d partitions & replication factor %d: %v", numPartitions, replicationFactor, err)
+	}
+	return check.pass("brokers would create a topic with %d partitions & replication factor %d", numPartitions, replicationFactor)
+}
+
+// Check That The Services Of The Installed KafkaChannel & KafkaSource Webhooks Have Ready Endpoints
+func (c *CLI) checkWebhook(ctx context.Context) PreflightCheck {
+	check := PreflightCheck{Name: PreflightCheckWebhook}
+	services, err := c.webhookServices(ctx)
+	if err != nil {
+		return check.fail("%v", err)
+	}
+	if len(services) == 0 {
+		return check.skip("no KafkaChannel or KafkaSource webhook is installed")
+	}
+	var unavailable []string
+	for _, service := range services {
+		parts := strings.SplitN(service, "/", 2)
+		endpoints, err := c.k8sClient.CoreV1().Endpoints(parts[0]).Get(ctx, parts[1], metav1.GetOptions{})
+		if err != nil || readyAddresses(endpoints) == 0 {
+			unavailable = append(unavailable, service)
+		}
+	}
+	if len(unavailable) > 0 {
+		return check.fail("webhook services without ready endpoints: %s", strings.Join(unavailable, ", "))
+	}
+	return check.pass("webhook services have ready endpoints: %s", strings.Join(services, ", "))
+}
+
+// Get The Sorted "<namespace>/<name>" Services Of The KafkaChannel & KafkaSource Webhook Configurations
+func (c *CLI) webhookServices(ctx context.Context) ([]string, error) {
+
+	// Collect The ClientConfigs Of The Mutating & Validating Webhooks
+	var clientConfigs []admissionregistrationv1beta1.WebhookClientConfig
+	mutatingConfigurations, err := c.k8sClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MutatingWebhookConfigurations: %w", err)
+	}
+	for _, configuration := range mutatingConfigurations.Items {
+		if isKafkaWebhookConfiguration(configuration.Name) {
+			for _, webhook := range configuration.Webhooks {
+				clientConfigs = append(clientConfigs, webhook.ClientConfig)
+			}
+		}
+	}
+	validatingConfigurations, err := c.k8sClient.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ValidatingWebhookConfigurations: %w", err)
+	}
+	for _, configuration := range validatingConfigurations.Items {
+		if isKafkaWebhookConfiguration(configuration.Name) {
+			for _, webhook := range configuration.Webhooks {
+				clientConfigs = append(clientConfigs, webhook.ClientConfig)
+			}
+		}
+	}
+
+	// Return The Distinct Services (Webhooks Addressed By URL Are Not Checked)
+	serviceSet := make(map[string]bool)
+	for _, clientConfig := range clientConfigs {
+		if clientConfig.Service != nil {
+			serviceSet[clientConfig.Service.Namespace+"/"+clientConfig.Service.Name] = true
+		}
+	}
+	services := make([]string, 0, len(serviceSet))
+	for service := range serviceSet {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// Check That The Controller's ServiceAccount Has The Access It Requires
+func (c *CLI) checkRBAC(ctx context.Context, serviceAccount string) PreflightCheck {
+	check := PreflightCheck{Name: PreflightCheckRBAC}
+	user := fmt.Sprintf("system:serviceaccount:%s:%s", system.Namespace(), serviceAccount)
+	var denied []string
+	for _, access := range controllerAccess() {
+		access := access
+		review, err := c.k8sClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{User: user, ResourceAttributes: &access},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return check.fail("failed to review the access of %s: %v", user, err)
+		}
+		if !review.Status.Allowed {
+			denied = append(denied, describeAccess(&access))
+		}
+	}
+	if len(denied) > 0 {
+		return check.fail("%s is denied: %s", user, strings.Join(denied, ", "))
+	}
+	return check.pass("%s has the required access", user)
+}
+
+// The Access Required By The Controller (A Representative Subset Of Its ClusterRole & Role)
+func controllerAccess() []authorizationv1.ResourceAttributes {
+	return []authorizationv1.ResourceAttributes{
+		{Group: "messaging.knative.dev", Resource: "kafkachannels", Verb: "watch"},
+		{Group: "messaging.knative.dev", Resource: "kafkachannels", Verb: "update"},
+		{Group: "messaging.knative.dev", Resource: "kafkachannels", Subresource: "status", Verb: "update"},
+		{Group: "apps", Resource: "deployments", Verb: "create"},
+		{Resource: "services", Verb: "create"},
+		{Resource: "events", Verb: "create"},
+		{Group: "coordination.k8s.io", Resource: "leases", Verb: "create"},
+		{Namespace: system.Namespace(), Resource: "configmaps", Verb: "watch"},
+		{Namespace: system.Namespace(), Resource: "secrets", Verb: "list"},
+	}
+}
+
+// Utility Function For Describing ResourceAttributes As "<verb> <resource>[/<subresource>][.<group>] [in <namespace>]"
+func describeAccess(access *authorizationv1.ResourceAttributes) string {
+	description := access.Verb + " " + access.Resource
+	if len(access.Subresource) > 0 {
+		description += "/" + access.Subresource
+	}
+	if len(access.Group) > 0 {
+		description += "." + access.Group
+	}
+	if len(access.Namespace) > 0 {
+		description += " in " + access.Namespace
+	}
+	return description
+}
+
+// Utility Function For Determining Whether A Webhook Configuration Belongs To The KafkaChannel Or KafkaSource
+func isKafkaWebhookConfiguration(name string) bool {
+	for _, suffix := range webhookConfigurationSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Utility Function For Counting The Ready Addresses Of Endpoints
+func readyAddresses(endpoints *corev1.Endpoints) int {
+	count := 0
+	for _, subset := range endpoints.Subsets {
+		count += len(subset.Addresses)
+	}
+	return count
+}
+
+// Utility Function For Counting The Failed Checks Of A PreflightReport
+func (r *PreflightReport) failures() int {
+	count := 0
+	for _, check := range r.Checks {
+		if check.Status == PreflightStatusFail {
+			count++
+		}
+	}
+	return count
+}
+
+// Utility Functions For Completing A PreflightCheck With A Status & Formatted Message
+func (p PreflightCheck) pass(format string, args ...interface{}) PreflightCheck {
+	return p.complete(PreflightStatusPass, format, args...)
+}
+
+func (p PreflightCheck) fail(format string, args ...interface{}) PreflightCheck {
+	return p.complete(PreflightStatusFail, format, args...)
+}
+
+func (p PreflightCheck) skip(format string, args ...interface{}) PreflightCheck {
+	return p.complete(PreflightStatusSkip, format, args...)
+}
+
+func (p PreflightCheck) complete(status string, format string, args ...interface{}) PreflightCheck {
+	p.Status = status
+	p.Message = fmt.Sprintf(format, args...)
+	return p
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	commontesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
+)
+
+// Test Data
+const (
+	testWebhookService = "kafka-webhook"
+
+	testValidEKConfig = `
+receiver:
+  cpuLimit: 200m
+  cpuRequest: 100m
+  memoryLimit: 100Mi
+  memoryRequest: 50Mi
+  replicas: 1
+dispatcher:
+  cpuLimit: 500m
+  cpuRequest: 300m
+  memoryLimit: 128Mi
+  memoryRequest: 50Mi
+  replicas: 1
+kafka:
+  adminType: kafka
+  topic:
+    defaultNumPartitions: 4
+    defaultReplicationFactor: 1
+    defaultRetentionMillis: 604800000
+`
+)
+
+// Test The preflight() Functionality
+func TestPreflight(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name             string
+		ekConfig         string
+		objects          []runtime.Object
+		createTopicErr   error
+		deniedResource   string
+		expectedStatuses map[string]string
+		errMsg           string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:     "All Checks Passed",
+			ekConfig: testValidEKConfig,
+			objects:  []runtime.Object{createTestKafkaSecret(testSecretName, testBrokers), createTestWebhookConfiguration(), createTestEndpoints(true)},
+			expectedStatuses: map[string]string{
+				PreflightCheckConfig:  PreflightStatusPass,
+				PreflightCheckSecret:  PreflightStatusPass,
+				PreflightCheckTopics:  PreflightStatusPass,
+				PreflightCheckWebhook: PreflightStatusPass,
+				PreflightCheckRBAC:    PreflightStatusPass,
+			},
+		},
+		{
+			name:     "Invalid Config & Missing Secret",
+			ekConfig: commontesting.TestEKConfig,
+			expectedStatuses: map[string]string{
+				PreflightCheckConfig:  PreflightStatusFail,
+				PreflightCheckSecret:  PreflightStatusFail,
+				PreflightCheckTopics:  PreflightStatusSkip,
+				PreflightCheckWebhook: PreflightStatusSkip,
+				PreflightCheckRBAC:    PreflightStatusPass,
+			},
+			errMsg: "2 of 5 preflight checks failed",
+		},
+		{
+			name:           "Topic Not Creatable",
+			ekConfig:       testValidEKConfig,
+			objects:        []runtime.Object{createTestKafkaSecret(testSecretName, testBrokers)},
+			createTopicErr: sarama.ErrInvalidReplicationFactor,
+			expectedStatuses: map[string]string{
+				PreflightCheckConfig:  PreflightStatusPass,
+				PreflightCheckSecret:  PreflightStatusPass,
+				PreflightCheckTopics:  PreflightStatusFail,
+				PreflightCheckWebhook: PreflightStatusSkip,
+				PreflightCheckRBAC:    PreflightStatusPass,
+			},
+			errMsg: "1 of 5 preflight checks failed",
+		},
+		{
+			name:     "Webhook Not Ready & RBAC Denied",
+			ekConfig: testValidEKConfig,
+			objects:  []runtime.Object{createTestKafkaSecret(testSecretName, testBrokers), createTestWebhookConfiguration(), createTestEndpoints(false)},
+			expectedStatuses: map[string]string{
+				PreflightCheckConfig:  PreflightStatusPass,
+				PreflightCheckSecret:  PreflightStatusPass,
+				PreflightCheckTopics:  PreflightStatusPass,
+				PreflightCheckWebhook: PreflightStatusFail,
+				PreflightCheckRBAC:    PreflightStatusFail,
+			},
+			deniedResource: "deployments",
+			errMsg:         "2 of 5 preflight checks failed",
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The KafkaOperations Creation
+			mockKafkaOperations := &MockKafkaOperations{createTopicErr: testCase.createTopicErr}
+			newKafkaOperationsWrapperPlaceholder := NewKafkaOperationsWrapper
+			NewKafkaOperationsWrapper = func(brokers []string, config *sarama.Config) (KafkaOperations, error) {
+				return mockKafkaOperations, nil
+			}
+			defer func() { NewKafkaOperationsWrapper = newKafkaOperationsWrapperPlaceholder }()
+
+			// Create The CLI With The Test Settings & Mock SubjectAccessReviews
+			cli, out := createTestCLI(t, testCase.objects...)
+			updateTestSettings(t, cli, testCase.ekConfig)
+			mockSubjectAccessReviews(cli, testCase.deniedResource)
+
+			// Perform The Test
+			err := cli.Run(context.TODO(), []string{"preflight", "-o", "json"})
+
+			// Verify The Results
+			if len(testCase.errMsg) > 0 {
+				assert.NotNil(t, err)
+				assert.False(t, errors.Is(err, ErrUsage))
+				assert.Equal(t, testCase.errMsg, err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+			report := &PreflightReport{}
+			assert.Nil(t, json.Unmarshal(out.Bytes(), report))
+			assert.Equal(t, len(testCase.errMsg) == 0, report.Passed)
+			actualStatuses := make(map[string]string)
+			for _, check := range report.Checks {
+				actualStatuses[check.Name] = check.Status
+			}
+			assert.Equal(t, testCase.expectedStatuses, actualStatuses)
+			if testCase.expectedStatuses[PreflightCheckSecret] == PreflightStatusPass {
+				assert.True(t, mockKafkaOperations.closed)
+			}
+		})
+	}
+}
+
+// Test The preflight() Functionality's Text Output & Usage Validation
+func TestPreflightOutput(t *testing.T) {
+	cli, out := createTestCLI(t)
+	mockSubjectAccessReviews(cli, "")
+	err := cli.Run(context.TODO(), []string{"preflight"})
+	assert.NotNil(t, err)
+	assert.Contains(t, out.String(), "CHECK")
+	assert.Contains(t, out.String(), "no KafkaChannel or KafkaSource webhook is installed")
+
+	cli, _ = createTestCLI(t)
+	err = cli.Run(context.TODO(), []string{"preflight", "-o", "yaml"})
+	assert.True(t, errors.Is(err, ErrUsage))
+	assert.Equal(t, "invalid -o value \"yaml\", expected \"text\" or \"json\": invalid usage", err.Error())
+}
+
+// Test The describeAccess() Functionality
+func TestDescribeAccess(t *testing.T) {
+	assert.Equal(t, "update kafkachannels/status.messaging.knative.dev", describeAccess(&authorizationv1.ResourceAttributes{Group: "messaging.knative.dev", Resource: "kafkachannels", Subresource: "status", Verb: "update"}))
+	assert.Equal(t, "list secrets in knative-eventing", describeAccess(&authorizationv1.ResourceAttributes{Namespace: "knative-eventing", Resource: "secrets", Verb: "list"}))
+}
+
+// Utility Function For Replacing The eventing-kafka Settings Of The Test CLI's ConfigMap
+func updateTestSettings(t *testing.T, cli *CLI, ekConfig string) {
+	configMap := commontesting.GetTestSaramaConfigMap(commontesting.OldSaramaConfig, ekConfig)
+	_, err := cli.k8sClient.CoreV1().ConfigMaps(configMap.Namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+}
+
+// Utility Function For Mocking The SubjectAccessReviews Of The Test CLI (Allowing All But The Denied Resource)
+func mockSubjectAccessReviews(cli *CLI, deniedResource string) {
+	cli.k8sClient.(*fake.Clientset).PrependReactor("create", "subjectaccessreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		review := action.(clientgotesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Resource != deniedResource
+		return true, review, nil
+	})
+}
+
+// Utility Function For Creating A KafkaChannel Webhook Configuration
+func createTestWebhookConfiguration() *admissionregistrationv1beta1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "validation.webhook.kafka.messaging.knative.dev"},
+		Webhooks: []admissionregistrationv1beta1.ValidatingWebhook{{
+			Name: "validation.webhook.kafka.messaging.knative.dev",
+			ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+				Service: &admissionregistrationv1beta1.ServiceReference{Name: testWebhookService, Namespace: commonconstants.KnativeEventingNamespace},
+			},
+		}},
+	}
+}
+
+// Utility Function For Creating The Webhook Service's Endpoints (With Or Without A Ready Address)
+func createTestEndpoints(ready bool) *corev1.Endpoints {
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: testWebhookService, Namespace: commonconstants.KnativeEventingNamespace},
+		Subsets:    []corev1.EndpointSubset{{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+	}
+	if ready {
+		endpoints.Subsets[0].Addresses = endpoints.Subsets[0].NotReadyAddresses
+		endpoints.Subsets[0].NotReadyAddresses = nil
+	}
+	return endpoints
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	sourcesv1beta1 "knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	controllerutil "knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/pkg/apis"
+)
+
+// Topology Node Kinds
+const (
+	TopologyKindKafkaChannel  = "KafkaChannel"
+	TopologyKindKafkaSource   = "KafkaSource"
+	TopologyKindTopic         = "Topic"
+	TopologyKindConsumerGroup = "ConsumerGroup"
+	TopologyKindSink          = "Sink"
+)
+
+// Topology Edge Relations
+const (
+	TopologyRelationProduces    = "produces"    // KafkaChannel -> Topic
+	TopologyRelationConsumes    = "consumes"    // Topic -> ConsumerGroup
+	TopologyRelationOwns        = "owns"        // KafkaSource -> ConsumerGroup
+	TopologyRelationDelivers    = "delivers"    // ConsumerGroup -> Sink / KafkaChannel
+	TopologyRelationReplies     = "replies"     // ConsumerGroup -> Sink / KafkaChannel
+	TopologyRelationDeadLetters = "deadLetters" // ConsumerGroup -> Sink / KafkaChannel / Topic
+)
+
+// The Graphviz Shapes Of The Topology Node Kinds
+var topologyShapes = map[string]string{
+	TopologyKindKafkaChannel:  "box",
+	TopologyKindKafkaSource:   "invhouse",
+	TopologyKindTopic:         "cylinder",
+	TopologyKindConsumerGroup: "ellipse",
+	TopologyKindSink:          "note",
+}
+
+// The Machine-Readable Topology Of The KafkaChannels & KafkaSources, Their Topics, ConsumerGroups & Sinks
+type Topology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// A Single Node (Resource, Topic, ConsumerGroup Or Sink) Of The Topology
+type TopologyNode struct {
+	ID         string            `json:"id"`
+	Kind       string            `json:"kind"`
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// A Single Directed Edge (In The Direction Of The Event Flow) Of The Topology
+type TopologyEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+}
+
+// Export The Topology Of The KafkaChannels & KafkaSources As JSON Or Graphviz
+func (c *CLI) topology(ctx context.Context, args []string) error {
+
+	// Parse The Command Flags
+	flagSet := c.newFlagSet("topology")
+	namespace := flagSet.String("n", metav1.NamespaceAll, "The namespace of the KafkaChannels & KafkaSources (default all namespaces)")
+	output := flagSet.String("o", "json", "The output format (json or dot)")
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrUsage)
+	}
+	if *output != "json" && *output != "dot" {
+		return fmt.Errorf("invalid -o value %q, expected \"json\" or \"dot\": %w", *output, ErrUsage)
+	}
+
+	// Get The KafkaChannels
+	kafkaChannels, err := c.kafkaClient.MessagingV1beta1().KafkaChannels(*namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list KafkaChannels: %w", err)
+	}
+
+	// Get The KafkaSources (None If The KafkaSource CRD Is Not Installed)
+	kafkaSources, err := c.kafkaClient.SourcesV1beta1().KafkaSources(*namespace).List(ctx, metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		kafkaSources, err = &sourcesv1beta1.KafkaSourceList{}, nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list KafkaSources: %w", err)
+	}
+
+	// Build The Topology
+	topology := BuildTopology(kafkaChannels.Items, kafkaSources.Items)
+
+	// Output The Topology In The Specified Format
+	if *output == "dot" {
+		c.printf("%s", topology.Dot())
+		return nil
+	}
+	topologyJson, err := json.MarshalIndent(topology, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal topology: %w", err)
+	}
+	c.printf("%s\n", topologyJson)
+	return nil
+}
+
+// Build The Topology Of The Specified KafkaChannels & KafkaSources (Sinks Addressing A KafkaChannel Resolve To Its Node)
+func BuildTopology(kafkaChannels []kafkav1beta1.KafkaChannel, kafkaSources []sourcesv1beta1.KafkaSource) *Topology {
+	builder := newTopologyBuilder()
+
+	// Each KafkaChannel Produces To Its Topic, Which (With Any ExtraTopics) Is Consumed By The ConsumerGroup Of Each Subscriber
+	for i := range kafkaChannels {
+		kafkaChannel := &kafkaChannels[i]
+		channelId := builder.kafkaChannel(kafkaChannel.Namespace, kafkaChannel.Name)
+		builder.nodes[channelId].Attributes = map[string]string{"ready": strconv.FormatBool(kafkaChannel.Status.IsReady())}
+		topicName := controllerutil.TopicName(kafkaChannel)
+		topicId := builder.topic(topicName)
+		builder.edge(channelId, topicId, TopologyRelationProduces)
+		for j := range kafkaChannel.Spec.Subscribers {
+			subscriber := &kafkaChannel.Spec.Subscribers[j]
+			groupId := builder.consumerGroup(util.GroupId(string(subscriber.UID)), map[string]string{"subscription": string(subscriber.UID)})
+			builder.edge(topicId, groupId, TopologyRelationConsumes)
+			for _, extraTopic := range kafkaChannel.Spec.ExtraTopics {
+				builder.edge(builder.topic(extraTopic), groupId, TopologyRelationConsumes)
+			}
+			builder.sinkEdge(groupId, subscriber.SubscriberURI, TopologyRelationDelivers)
+			builder.sinkEdge(groupId, subscriber.ReplyURI, TopologyRelationReplies)
+			if subscriber.Delivery != nil && subscriber.Delivery.DeadLetterSink != nil {
+				deadLetterSinkURI := subscriber.Delivery.DeadLetterSink.URI
+				if util.IsDeadLetterTopicShorthand(deadLetterSinkURI) {
+					builder.edge(groupId, builder.topic(util.DeadLetterTopicName(topicName, string(subscriber.UID))), TopologyRelationDeadLetters)
+				} else {
+					builder.sinkEdge(groupId, deadLetterSinkURI, TopologyRelationDeadLetters)
+				}
+			}
+		}
+	}
+
+	// Each KafkaSource Owns A ConsumerGroup Which Consumes Its Topics & Delivers To Its Sink
+	for i := range kafkaSources {
+		kafkaSource := &kafkaSources[i]
+		sourceId := builder.node(TopologyKindKafkaSource, kafkaSource.Namespace, kafkaSource.Name)
+		groupId := builder.consumerGroup(kafkaSource.Spec.ConsumerGroup, nil)
+		builder.edge(sourceId, groupId, TopologyRelationOwns)
+		for _, topicName := range kafkaSource.Spec.Topics {
+			builder.edge(builder.topic(topicName), groupId, TopologyRelationConsumes)
+		}
+		builder.sinkEdge(groupId, kafkaSource.Status.SinkURI, TopologyRelationDelivers)
+		if deadLetterSink, ok := kafkaSource.Annotations[sourcesv1beta1.KafkaDeadLetterSinkAnnotation]; ok {
+			if deadLetterSinkURI, err := apis.ParseURL(deadLetterSink); err == nil {
+				builder.sinkEdge(groupId, deadLetterSinkURI, TopologyRelationDeadLetters)
+			}
+		}
+	}
+
+	// Return The Topology
+	return builder.build()
+}
+
+// Render The Topology As A Graphviz (DOT) Directed Graph
+func (t *Topology) Dot() string {
+	dot := "digraph topology {\n  rankdir=LR;\n"
+	for _, node := range t.Nodes {
+		label := node.Kind + "\n" + node.Name
+		if len(node.Namespace) > 0 {
+			label = node.Kind + "\n" + node.Namespace + "/" + node.Name
+		}
+		dot += fmt.Sprintf("  %s [label=%s, shape=%s];\n", strconv.Quote(node.ID), strconv.Quote(label), topologyShapes[node.Kind])
+	}
+	for _, edge := range t.Edges {
+		dot += fmt.Sprintf("  %s -> %s [label=%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), strconv.Quote(edge.Relation))
+	}
+	return dot + "}\n"
+}
+
+//
+// Topology Builder
+//
+
+// Accumulates The Distinct Nodes & Edges Of A Topology
+type topologyBuilder struct {
+	nodes map[string]*TopologyNode
+	edges map[TopologyEdge]bool
+}
+
+// Create A New Empty topologyBuilder
+func newTopologyBuilder() *topologyBuilder {
+	return &topologyBuilder{
+		nodes: make(map[string]*TopologyNode),
+		edges: make(map[TopologyEdge]bool),
+	}
+}
+
+// Add The Specified Node (If Not Already Present) & Return Its ID
+func (b *topologyBuilder) node(kind string, namespace string, name string) string {
+	id := kind + "/" + name
+	if len(namespace) > 0 {
+		id = kind + "/" + namespace + "/" + name
+	}
+	if _, ok := b.nodes[id]; !ok {
+		b.nodes[id] = &TopologyNode{ID: id, Kind: kind, Name: name, Namespace: namespace}
+	}
+	return id
+}
+
+// Add The KafkaChannel Node & Return Its ID
+func (b *topologyBuilder) kafkaChannel(namespace string, name string) string {
+	return b.node(TopologyKindKafkaChannel, namespace, name)
+}
+
+// Add The Topic Node & Return Its ID
+func (b *topologyBuilder) topic(name string) string {
+	return b.node(TopologyKindTopic, "", name)
+}
+
+// Add The ConsumerGroup Node With The Specified Attributes & Return Its ID
+func (b *topologyBuilder) consumerGroup(name string, attributes map[string]string) string {
+	id := b.node(TopologyKindConsumerGroup, "", name)
+	if len(attributes) > 0 {
+		b.nodes[id].Attributes = attributes
+	}
+	return id
+}
+
+// Add An Edge To The Node Of The Sink URI (If Any), Which Is The KafkaChannel Node If The URI Is A KafkaChannel Address
+func (b *topologyBuilder) sinkEdge(from string, uri *apis.URL, relation string) {
+	if uri == nil {
+		return
+	}
+	if namespace, name, ok := util.KafkaChannelOfURL(uri); ok {
+		b.edge(from, b.kafkaChannel(namespace, name), relation)
+	} else {
+		b.edge(from, b.node(TopologyKindSink, "", uri.String()), relation)
+	}
+}
+
+// Add The Specified Edge (If Not Already Present)
+func (b *topologyBuilder) edge(from string, to string, relation string) {
+	b.edges[TopologyEdge{From: from, To: to, Relation: relation}] = true
+}
+
+// Build The Topology With Its Nodes & Edges In A Deterministic (Sorted) Order
+func (b *topologyBuilder) build() *Topology {
+	topology := &Topology{
+		Nodes: make([]TopologyNode, 0, len(b.nodes)),
+		Edges: make([]TopologyEdge, 0, len(b.edges)),
+	}
+	for _, node := range b.nodes {
+		topology.Nodes = append(topology.Nodes, *node)
+	}
+	sort.Slice(topology.Nodes, func(i, j int) bool { return topology.Nodes[i].ID < topology.Nodes[j].ID })
+	for edge := range b.edges {
+		topology.Edges = append(topology.Edges, edge)
+	}
+	sort.Slice(topology.Edges, func(i, j int) bool {
+		if topology.Edges[i].From != topology.Edges[j].From {
+			return topology.Edges[i].From < topology.Edges[j].From
+		}
+		if topology.Edges[i].To != topology.Edges[j].To {
+			return topology.Edges[i].To < topology.Edges[j].To
+		}
+		return topology.Edges[i].Relation < topology.Edges[j].Relation
+	})
+	return topology
+}
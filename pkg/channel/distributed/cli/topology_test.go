@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	sourcesv1beta1 "knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// Test The BuildTopology() Functionality
+func TestBuildTopology(t *testing.T) {
+
+	// Create A KafkaChannel Whose Subscriber Replies To Another KafkaChannel With A Kafka DeadLetter Topic
+	kafkaChannel := createTestKafkaChannel(testSubscriberUID)
+	kafkaChannel.Spec.Subscribers[0].SubscriberURI = apis.HTTP("subscriber.test-namespace.svc.cluster.local")
+	kafkaChannel.Spec.Subscribers[0].ReplyURI = apis.HTTP("reply-kn-channel.test-namespace.svc.cluster.local")
+	kafkaChannel.Spec.Subscribers[0].Delivery = &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "kafka"}}}
+	kafkaChannel.Spec.ExtraTopics = []string{"other-topic"}
+
+	// Create A KafkaSource Which Consumes The KafkaChannel's Topic
+	kafkaSource := createTestKafkaSource()
+
+	// Perform The Test
+	topology := BuildTopology([]kafkav1beta1.KafkaChannel{*kafkaChannel}, []sourcesv1beta1.KafkaSource{*kafkaSource})
+
+	// Verify The Nodes
+	assert.Equal(t, []TopologyNode{
+		{ID: "ConsumerGroup/kafka." + testSubscriberUID, Kind: TopologyKindConsumerGroup, Name: "kafka." + testSubscriberUID, Attributes: map[string]string{"subscription": testSubscriberUID}},
+		{ID: "ConsumerGroup/test-group", Kind: TopologyKindConsumerGroup, Name: "test-group"},
+		{ID: "KafkaChannel/test-namespace/reply", Kind: TopologyKindKafkaChannel, Name: "reply", Namespace: testNamespace},
+		{ID: "KafkaChannel/test-namespace/test-name", Kind: TopologyKindKafkaChannel, Name: testName, Namespace: testNamespace, Attributes: map[string]string{"ready": "false"}},
+		{ID: "KafkaSource/test-namespace/test-source", Kind: TopologyKindKafkaSource, Name: "test-source", Namespace: testNamespace},
+		{ID: "Sink/http://sink.test-namespace.svc.cluster.local", Kind: TopologyKindSink, Name: "http://sink.test-namespace.svc.cluster.local"},
+		{ID: "Sink/http://subscriber.test-namespace.svc.cluster.local", Kind: TopologyKindSink, Name: "http://subscriber.test-namespace.svc.cluster.local"},
+		{ID: "Topic/other-topic", Kind: TopologyKindTopic, Name: "other-topic"},
+		{ID: "Topic/" + testTopic, Kind: TopologyKindTopic, Name: testTopic},
+		{ID: "Topic/" + testTopic + "." + testSubscriberUID + ".dlq", Kind: TopologyKindTopic, Name: testTopic + "." + testSubscriberUID + ".dlq"},
+	}, topology.Nodes)
+
+	// Verify The Edges
+	assert.Equal(t, []TopologyEdge{
+		{From: "ConsumerGroup/kafka." + testSubscriberUID, To: "KafkaChannel/test-namespace/reply", Relation: TopologyRelationReplies},
+		{From: "ConsumerGroup/kafka." + testSubscriberUID, To: "Sink/http://subscriber.test-namespace.svc.cluster.local", Relation: TopologyRelationDelivers},
+		{From: "ConsumerGroup/kafka." + testSubscriberUID, To: "Topic/" + testTopic + "." + testSubscriberUID + ".dlq", Relation: TopologyRelationDeadLetters},
+		{From: "ConsumerGroup/test-group", To: "Sink/http://sink.test-namespace.svc.cluster.local", Relation: TopologyRelationDelivers},
+		{From: "KafkaChannel/test-namespace/test-name", To: "Topic/" + testTopic, Relation: TopologyRelationProduces},
+		{From: "KafkaSource/test-namespace/test-source", To: "ConsumerGroup/test-group", Relation: TopologyRelationOwns},
+		{From: "Topic/other-topic", To: "ConsumerGroup/kafka." + testSubscriberUID, Relation: TopologyRelationConsumes},
+		{From: "Topic/other-topic", To: "ConsumerGroup/test-group", Relation: TopologyRelationConsumes},
+		{From: "Topic/" + testTopic, To: "ConsumerGroup/kafka." + testSubscriberUID, Relation: TopologyRelationConsumes},
+		{From: "Topic/" + testTopic, To: "ConsumerGroup/test-group", Relation: TopologyRelationConsumes},
+	}, topology.Edges)
+}
+
+// Test The "topology" Command
+func TestTopology(t *testing.T) {
+
+	// JSON Output (The Default)
+	cli, out := createTestCLI(t, createTestKafkaChannel(testSubscriberUID), createTestKafkaSource())
+	err := cli.Run(context.TODO(), []string{"topology", "-n", testNamespace})
+	assert.Nil(t, err)
+	topology := &Topology{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), topology))
+	assert.Len(t, topology.Nodes, 7)
+	assert.Len(t, topology.Edges, 6)
+
+	// Graphviz Output
+	cli, out = createTestCLI(t, createTestKafkaChannel(testSubscriberUID))
+	err = cli.Run(context.TODO(), []string{"topology", "-o", "dot"})
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"digraph topology {\n"+
+		"  rankdir=LR;\n"+
+		"  \"ConsumerGroup/kafka.test-subscriber-uid\" [label=\"ConsumerGroup\\nkafka.test-subscriber-uid\", shape=ellipse];\n"+
+		"  \"KafkaChannel/test-namespace/test-name\" [label=\"KafkaChannel\\ntest-namespace/test-name\", shape=box];\n"+
+		"  \"Topic/test-namespace.test-name\" [label=\"Topic\\ntest-namespace.test-name\", shape=cylinder];\n"+
+		"  \"KafkaChannel/test-namespace/test-name\" -> \"Topic/test-namespace.test-name\" [label=\"produces\"];\n"+
+		"  \"Topic/test-namespace.test-name\" -> \"ConsumerGroup/kafka.test-subscriber-uid\" [label=\"consumes\"];\n"+
+		"}\n",
+		out.String())
+
+	// Invalid Output Format
+	cli, _ = createTestCLI(t)
+	err = cli.Run(context.TODO(), []string{"topology", "-o", "yaml"})
+	assert.True(t, errors.Is(err, ErrUsage))
+}
+
+// Utility Function For Creating A KafkaSource Consuming The Test KafkaChannel's Topic & Another Topic
+func createTestKafkaSource() *sourcesv1beta1.KafkaSource {
+	return &sourcesv1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-source", Namespace: testNamespace},
+		Spec: sourcesv1beta1.KafkaSourceSpec{
+			Topics:        []string{testTopic, "other-topic"},
+			ConsumerGroup: "test-group",
+		},
+		Status: sourcesv1beta1.KafkaSourceStatus{
+			SourceStatus: duckv1.SourceStatus{SinkURI: apis.HTTP("sink.test-namespace.svc.cluster.local")},
+		},
+	}
+}
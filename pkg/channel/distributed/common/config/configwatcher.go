@@ -20,9 +20,12 @@ import (
 	"context"
 
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/headers"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/injection/sharedmain"
@@ -38,16 +41,351 @@ type EKKubernetesConfig struct {
 	MemoryLimit   resource.Quantity `json:"memoryLimit,omitempty"`
 	MemoryRequest resource.Quantity `json:"memoryRequest,omitempty"`
 	Replicas      int               `json:"replicas,omitempty"`
+
+	// Optional SecurityContexts & Seccomp Profile (Secure Defaults Compliant With The "restricted" Pod Security Standard)
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	SecurityContext    *corev1.SecurityContext    `json:"securityContext,omitempty"`
+	SeccompProfile     string                     `json:"seccompProfile,omitempty"`
+
+	// Optional Image Resolution (Overrides The Image Environment Variable)
+	Images EKImagesConfig `json:"images,omitempty"`
+
+	// Optional Go Runtime Garbage Collection Tuning
+	Runtime EKRuntimeConfig `json:"runtime,omitempty"`
+}
+
+// EKRuntimeConfig tunes the garbage collection of the Receiver / Dispatcher, whose default (collecting whenever the
+// heap doubles) causes frequent collections, and latency spikes, at high event rates with small live heaps.  GOGC
+// ("off" or a percentage) and GOMemLimit (a soft memory limit, honored by images built with Go 1.19 or later) are set
+// as the environment variables of the same names on the generated Deployments, while GOGC & the Ballast (a quantity
+// of memory allocated but never touched, so not resident, raising the heap size at which collections are triggered)
+// are also applied in-process at startup.
+type EKRuntimeConfig struct {
+	GOGC       string             `json:"gogc,omitempty"`
+	GOMemLimit *resource.Quantity `json:"gomemlimit,omitempty"`
+	Ballast    *resource.Quantity `json:"ballast,omitempty"`
+}
+
+// EKImagesConfig resolves the image of the Receiver / Dispatcher Deployments from the ConfigMap rather than the
+// image environment variable of the controller.  Architectures maps node architectures (the kubernetes.io/arch
+// label) to architecture specific images, and Classes maps image classes to images (e.g. a canary image selected
+// by a subset of KafkaChannels).  Architecture is the default node architecture of the Deployments, and Canary
+// rolls an image class out to the KafkaChannels matching its selector.
+type EKImagesConfig struct {
+	Default       string            `json:"default,omitempty"`
+	Architecture  string            `json:"architecture,omitempty"`
+	Architectures map[string]string `json:"architectures,omitempty"`
+	Classes       map[string]string `json:"classes,omitempty"`
+	Canary        EKCanaryConfig    `json:"canary,omitempty"`
+}
+
+// EKCanaryConfig selects the KafkaChannels (by label) whose Dispatcher uses the image of the canary Class, unless
+// the KafkaChannel selects an image class with its annotation.  When MaxErrorRate is set (and the metrics aggregator
+// is enabled), a Dispatcher whose class image has rolled out is rolled back once the ErrorRate of its events exceeds
+// it, after it has dispatched at least MinEvents events.
+type EKCanaryConfig struct {
+	Class        string                `json:"class,omitempty"`
+	Selector     *metav1.LabelSelector `json:"selector,omitempty"`
+	MaxErrorRate float64               `json:"maxErrorRate,omitempty"`
+	MinEvents    int64                 `json:"minEvents,omitempty"`
 }
 
-// The Receiver config has nothing in it except the base Kubernetes fields (Cpu, Memory, Replicas)
+// The Receiver config has the base Kubernetes fields (Cpu, Memory, Replicas), the broker quota aware throttling,
+// the ingress validation of events, the ingress policy, the injection of hop timestamps, the graceful shutdown, the
+// Timestamp of the produced records, either "produce" (the default, the time they are produced) or "ceTime" (the
+// time attribute of their CloudEvent, which time-based retention then honors), and the Isolation of the receivers, either "secret" (the default, one receiver shared by the KafkaChannels of each
+// Kafka Secret), "channel" (a dedicated receiver per KafkaChannel) or "combined" (a dedicated receiver per
+// KafkaChannel run in the pod of its dispatcher), which KafkaChannels may also select individually with their
+// annotation
 type EKReceiverConfig struct {
 	EKKubernetesConfig
+	Throttle   EKThrottleConfig   `json:"throttle,omitempty"`
+	Validation EKValidationConfig `json:"validation,omitempty"`
+	Policy     EKPolicyConfig     `json:"policy,omitempty"`
+	Latency    EKLatencyConfig    `json:"latency,omitempty"`
+	Shutdown   EKShutdownConfig   `json:"shutdown,omitempty"`
+	Isolation  string             `json:"isolation,omitempty"`
+	Addressing string             `json:"addressing,omitempty"`
+	Timestamp  string             `json:"timestamp,omitempty"`
+
+	// Optional Maximum Request Body Size (Larger Requests Are Rejected With 413)
+	MaxBodySize *resource.Quantity `json:"maxBodySize,omitempty"`
+}
+
+// EKLatencyConfig enables the injection of the times at which the receiver received & produced each event as
+// Kafka headers, from which the dispatchers record the latency of each hop of the event's delivery (and the
+// end-to-end latency) as histograms per KafkaChannel & subscription.
+type EKLatencyConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
 }
 
-// The Dispatcher config has the base Kubernetes fields and some retry settings
+// EKThrottleConfig enables the receiver's broker quota aware throttling.  Kafka delays (or mutes) the clients
+// exceeding their quotas, so produce requests slower than LatencyThresholdMillis start an exponential backoff,
+// from InitialBackoffMillis up to MaxBackoffMillis, during which events are rejected with 503 & Retry-After.
+// Events exceeding MaxInFlight concurrent produce requests are rejected the same way rather than queued.
+type EKThrottleConfig struct {
+	Enabled                bool  `json:"enabled,omitempty"`
+	LatencyThresholdMillis int64 `json:"latencyThresholdMillis,omitempty"`
+	InitialBackoffMillis   int64 `json:"initialBackoffMillis,omitempty"`
+	MaxBackoffMillis       int64 `json:"maxBackoffMillis,omitempty"`
+	MaxInFlight            int   `json:"maxInFlight,omitempty"`
+}
+
+// EKShutdownConfig configures the receiver's graceful termination.  For DrainMillis after being signalled to
+// terminate the receiver reports itself not ready (so that its Service stops routing to it) while still serving
+// requests, then stops accepting connections and waits up to TimeoutMillis for the in-flight requests to complete
+// before closing its producer.
+type EKShutdownConfig struct {
+	DrainMillis   int64 `json:"drainMillis,omitempty"`
+	TimeoutMillis int64 `json:"timeoutMillis,omitempty"`
+}
+
+// EKValidationConfig enables the receiver's ingress validation of events, which are rejected with a 400 and an
+// application/problem+json body describing the violations.  Events are always validated against the CloudEvents
+// spec's requirements, and additionally against its recommendations when Strict.  RequiredExtensions lists the
+// extension attributes every event must carry, and when a SchemaRegistryURL is specified the data of events is
+// validated against the (JSON) schema, cached for SchemaCacheSeconds.  The SchemaRegistryType "dataschema" (the
+// default) fetches the schemas of events whose dataschema refers to the registry, while "confluent", "karapace",
+// "apicurio" (its native v2 API) & "apicurio-ccompat" (its Confluent compatible API) look up the latest schema of
+// the subject named by the SubjectNameStrategy ("topic", "record" or "topic-record").  Events whose subject has no
+// registered schema are rejected when SchemaRequired, and accepted otherwise.  SchemaRegistryAuth configures the
+// authentication of the requests to the registry.
+type EKValidationConfig struct {
+	Enabled             bool                       `json:"enabled,omitempty"`
+	Strict              bool                       `json:"strict,omitempty"`
+	RequiredExtensions  []string                   `json:"requiredExtensions,omitempty"`
+	SchemaRegistryURL   string                     `json:"schemaRegistryURL,omitempty"`
+	SchemaRegistryType  string                     `json:"schemaRegistryType,omitempty"`
+	SchemaRegistryAuth  EKSchemaRegistryAuthConfig `json:"schemaRegistryAuth,omitempty"`
+	SubjectNameStrategy string                     `json:"subjectNameStrategy,omitempty"`
+	SchemaRequired      bool                       `json:"schemaRequired,omitempty"`
+	SchemaCacheSeconds  int                        `json:"schemaCacheSeconds,omitempty"`
+}
+
+// EKPolicyConfig enables the receiver's ingress policy, which rejects (with a 403) or tags events by the identity of
+// their caller and their attributes, so that tenants cannot spoof the events of other tenants.  The caller is
+// identified by its source IP, the client certificate forwarded by a trusted proxy (when TrustForwardedHeaders) and
+// the claims of its bearer token, verified against the JWT PublicKeys.  The first of the Rules matching an event
+// allows or rejects it (with tagging rules adding their extensions and continuing), and events matching no rule
+// are subject to the DefaultAction ("allow" or "reject").  Changes to the Rules are applied without a restart.
+type EKPolicyConfig struct {
+	Enabled               bool           `json:"enabled,omitempty"`
+	TrustForwardedHeaders bool           `json:"trustForwardedHeaders,omitempty"`
+	JWT                   EKPolicyJWT    `json:"jwt,omitempty"`
+	Rules                 []EKPolicyRule `json:"rules,omitempty"`
+	DefaultAction         string         `json:"defaultAction,omitempty"`
+}
+
+// EKPolicyJWT configures the verification of the callers' bearer tokens, which must be signed (RS256/384/512 or
+// ES256/384/512) by one of the PEM encoded PublicKeys (or certificates), have an expiry (exp), be issued by the
+// Issuer & intended for one of the Audiences (when specified).  Requests without a token are rejected with a 401
+// when Required.
+type EKPolicyJWT struct {
+	Issuer     string   `json:"issuer,omitempty"`
+	Audiences  []string `json:"audiences,omitempty"`
+	PublicKeys []string `json:"publicKeys,omitempty"`
+	Required   bool     `json:"required,omitempty"`
+}
+
+// EKPolicyRule is a named rule of the ingress policy whose Action ("allow", "reject" or "tag") applies to the events
+// matching all of its conditions.  The patterns of the conditions may contain "*" wildcards & the variables
+// ${caller.namespace}, ${channel.namespace} & ${channel.name}.  Tagging rules set the Extensions on the events.
+type EKPolicyRule struct {
+	Name               string            `json:"name,omitempty"`
+	Action             string            `json:"action,omitempty"`
+	Channels           []string          `json:"channels,omitempty"`
+	SourceCIDRs        []string          `json:"sourceCIDRs,omitempty"`
+	ClientCertSubjects []string          `json:"clientCertSubjects,omitempty"`
+	Claims             map[string]string `json:"claims,omitempty"`
+	Attributes         map[string]string `json:"attributes,omitempty"`
+	Extensions         map[string]string `json:"extensions,omitempty"`
+}
+
+// EKSchemaRegistryAuthConfig configures the receiver's authentication with the schema registry, using the keys of
+// the SecretName Secret (in the knative-eventing namespace).  The Mode "basic" sends its username & password (e.g.
+// a Confluent Cloud API key & secret, or Karapace credentials), "bearer" sends its token, and "oauth2" sends tokens
+// obtained from the TokenURL with its clientId & clientSecret and any Scopes (e.g. from the Keycloak securing an
+// Apicurio Registry).  The default Mode "none" sends no credentials.
+type EKSchemaRegistryAuthConfig struct {
+	Mode       string   `json:"mode,omitempty"`
+	SecretName string   `json:"secretName,omitempty"`
+	TokenURL   string   `json:"tokenURL,omitempty"`
+	Scopes     []string `json:"scopes,omitempty"`
+}
+
+// The Dispatcher config has the base Kubernetes fields, some retry settings, the debug tail endpoint, deduplication,
+// subscription snapshots, destination re-resolution, the load balancing of Kubernetes Service endpoints, the
+// health probing of subscribers, the checking of their destinations, the scaling to zero of idle dispatchers and the
+// settings of the subscribers' ConsumerGroups
 type EKDispatcherConfig struct {
 	EKKubernetesConfig
+	Retry            EKRetryConfig            `json:"retry,omitempty"`
+	Tail             EKTailConfig             `json:"tail,omitempty"`
+	Dedupe           EKDedupeConfig           `json:"dedupe,omitempty"`
+	Snapshot         EKSnapshotConfig         `json:"snapshot,omitempty"`
+	Resolution       EKResolutionConfig       `json:"resolution,omitempty"`
+	PoisonPill       EKPoisonPillConfig       `json:"poisonPill,omitempty"`
+	Endpoints        EKEndpointsConfig        `json:"endpoints,omitempty"`
+	HealthProbe      EKHealthProbeConfig      `json:"healthProbe,omitempty"`
+	DestinationCheck EKDestinationCheckConfig `json:"destinationCheck,omitempty"`
+	ScaleToZero      EKScaleToZeroConfig      `json:"scaleToZero,omitempty"`
+	OIDC             EKOIDCConfig             `json:"oidc,omitempty"`
+	ConsumerGroup    EKConsumerGroupConfig    `json:"consumerGroup,omitempty"`
+}
+
+// EKConsumerGroupConfig sets the subscribers' ConsumerGroup settings most relevant to Knative subscriptions, each
+// leaving the Sarama config's setting as-is when unset: the OffsetsRetentionMillis requested with every offset commit
+// (brokers otherwise retaining the committed offsets of an Empty group for their offsets.retention.minutes, 7 days by
+// default), the InitialOffset ("oldest" or "newest") from which a group without committed offsets (including one whose
+// offsets expired) consumes, and the group's session, heartbeat & rebalance timeouts.  The RetentionWarning enables
+// the controller warning of subscribers whose offsets risk expiring.
+type EKConsumerGroupConfig struct {
+	OffsetsRetentionMillis  int64                    `json:"offsetsRetentionMillis,omitempty"`
+	InitialOffset           string                   `json:"initialOffset,omitempty"`
+	SessionTimeoutMillis    int64                    `json:"sessionTimeoutMillis,omitempty"`
+	HeartbeatIntervalMillis int64                    `json:"heartbeatIntervalMillis,omitempty"`
+	RebalanceTimeoutMillis  int64                    `json:"rebalanceTimeoutMillis,omitempty"`
+	RetentionWarning        EKRetentionWarningConfig `json:"retentionWarning,omitempty"`
+}
+
+// EKRetentionWarningConfig enables the controller warning (via an OffsetsRetained condition & event on the
+// KafkaChannels) of subscribers whose ConsumerGroups have been Empty (i.e. without any consumers, such as while their
+// dispatcher is down or scaled to zero) for ThresholdPercent (defaulting to 50) of the offsets retention, after which
+// their committed offsets expire and the subscribers silently resume consumption from the InitialOffset.  The retention
+// is the OffsetsRetentionMillis if set, and otherwise the RetentionMillis of the brokers (defaulting to 7 days).  The
+// ConsumerGroups are checked every CheckIntervalMillis (defaulting to 10 minutes).
+type EKRetentionWarningConfig struct {
+	Enabled             bool  `json:"enabled,omitempty"`
+	ThresholdPercent    int   `json:"thresholdPercent,omitempty"`
+	RetentionMillis     int64 `json:"retentionMillis,omitempty"`
+	CheckIntervalMillis int64 `json:"checkIntervalMillis,omitempty"`
+}
+
+// EKOIDCConfig enables the dispatcher minting OIDC tokens for the audiences declared by Subscriptions, which are sent to
+// their subscribers as Bearer Authorization headers so that events can be delivered to sinks requiring authentication.
+// The tokens are requested (via the TokenRequest API) for the ServiceAccount in the system namespace (defaulting to the
+// dispatcher's own), with a lifetime of ExpirationSeconds (defaulting to an hour and at least ten minutes), and are
+// cached per audience until half of their lifetime has elapsed.
+type EKOIDCConfig struct {
+	Enabled           bool   `json:"enabled,omitempty"`
+	ServiceAccount    string `json:"serviceAccount,omitempty"`
+	ExpirationSeconds int64  `json:"expirationSeconds,omitempty"`
+}
+
+// EKScaleToZeroConfig enables the controller scaling the dispatcher Deployments of KafkaChannels to zero replicas once
+// no records have been produced to their topics, and their subscribers have not changed, for IdleMillis (defaulting to
+// 30 minutes), as checked every CheckIntervalMillis (defaulting to 30 seconds).  Only KafkaChannels without subscribers
+// are scaled to zero unless IncludeSubscribed is set.  The dispatchers are scaled back up to their previous replicas
+// by the first check after a record is produced or the subscribers change.
+type EKScaleToZeroConfig struct {
+	Enabled             bool  `json:"enabled,omitempty"`
+	IdleMillis          int64 `json:"idleMillis,omitempty"`
+	CheckIntervalMillis int64 `json:"checkIntervalMillis,omitempty"`
+	IncludeSubscribed   bool  `json:"includeSubscribed,omitempty"`
+}
+
+// EKHealthProbeConfig enables the active health probing (every IntervalMillis) of HTTP subscribers with a Method
+// ("HEAD", "OPTIONS" or "GET") request of their subscriber URI, or of its Path if specified.  A subscriber is
+// unhealthy after FailureThreshold consecutive failed probes, during which the consumption of its subscription is
+// paused (and the KafkaChannel's SubscribersHealthy condition is False), until SuccessThreshold consecutive probes
+// succeed.  A probe fails when it times out (after TimeoutMillis), cannot connect or is answered with a 5xx, and
+// additionally with a 4xx when probing a Path.
+type EKHealthProbeConfig struct {
+	Enabled          bool   `json:"enabled,omitempty"`
+	Method           string `json:"method,omitempty"`
+	Path             string `json:"path,omitempty"`
+	IntervalMillis   int64  `json:"intervalMillis,omitempty"`
+	TimeoutMillis    int64  `json:"timeoutMillis,omitempty"`
+	FailureThreshold int    `json:"failureThreshold,omitempty"`
+	SuccessThreshold int    `json:"successThreshold,omitempty"`
+}
+
+// EKDestinationCheckConfig enables checking that the destinations (the subscriber, reply & DeadLetterSink URIs) of
+// each subscriber are absolute and that their hosts resolve before its subscription is reported Ready, and
+// optionally Probing the subscriber URI with a HEAD request (failing when it cannot connect or is answered with a
+// 5xx), each within TimeoutMillis.  Subscribers failing the check are reported not Ready, and are rechecked every
+// RecheckIntervalSeconds.
+type EKDestinationCheckConfig struct {
+	Enabled                bool  `json:"enabled,omitempty"`
+	Probe                  bool  `json:"probe,omitempty"`
+	TimeoutMillis          int64 `json:"timeoutMillis,omitempty"`
+	RecheckIntervalSeconds int   `json:"recheckIntervalSeconds,omitempty"`
+}
+
+// EKEndpointsConfig enables the delivery of events to Kubernetes Services directly at the pod IPs of their ready
+// endpoints (from their EndpointSlices), balanced round-robin per request rather than per kube-proxy connection.
+// An endpoint whose requests fail to connect (or are answered with a 502, 503 or 504) is avoided for CooldownMillis.
+type EKEndpointsConfig struct {
+	Enabled        bool  `json:"enabled,omitempty"`
+	CooldownMillis int64 `json:"cooldownMillis,omitempty"`
+}
+
+// EKPoisonPillConfig enables the detection of records which cannot be decoded into valid CloudEvents.  Decoding
+// is attempted MaxAttempts times (BackoffMillis apart) before the record is produced verbatim to the
+// QuarantineTopic, or wrapped in a CloudEvent and sent to the subscriber's DeadLetterSink if no QuarantineTopic
+// is specified, and its offset is committed so that consumption of the partition continues.
+type EKPoisonPillConfig struct {
+	Enabled         bool   `json:"enabled,omitempty"`
+	MaxAttempts     int    `json:"maxAttempts,omitempty"`
+	BackoffMillis   int64  `json:"backoffMillis,omitempty"`
+	QuarantineTopic string `json:"quarantineTopic,omitempty"`
+}
+
+// EKResolutionConfig enables the periodic re-resolution (every IntervalSeconds) of the subscribers' destinations,
+// both of the addressables referenced by their Subscriptions and of the DNS addresses of their hosts, so that
+// migrated subscriber services are delivered to without updating the Subscriptions.
+type EKResolutionConfig struct {
+	Enabled         bool `json:"enabled,omitempty"`
+	IntervalSeconds int  `json:"intervalSeconds,omitempty"`
+}
+
+// EKSnapshotConfig enables persisting a snapshot of the dispatcher's subscriptions (their resolved subscriber
+// specs, consumer groups & the KafkaChannel annotations) in a ConfigMap, from which a restarted dispatcher
+// resumes consuming before its KafkaChannel has been reconciled.
+type EKSnapshotConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// EKDedupeConfig enables the detection of duplicate events (by CloudEvent id & source) within a time window,
+// bounded to MaxEntries events per subscription.  Duplicates are counted, and are also dropped before dispatch
+// if Drop is set.
+type EKDedupeConfig struct {
+	Enabled      bool  `json:"enabled,omitempty"`
+	Drop         bool  `json:"drop,omitempty"`
+	WindowMillis int64 `json:"windowMillis,omitempty"`
+	MaxEntries   int   `json:"maxEntries,omitempty"`
+}
+
+// EKTailConfig enables the dispatcher's tail endpoint, which streams a live sample of the consumed events to
+// authorized callers for troubleshooting.  Event payloads are only streamed if AllowPayload is set.
+type EKTailConfig struct {
+	Enabled      bool `json:"enabled,omitempty"`
+	Port         int  `json:"port,omitempty"`
+	AllowPayload bool `json:"allowPayload,omitempty"`
+}
+
+// EKRetryConfig refines the retries of subscribers whose delivery spec enables retries.  The policy of each
+// category of delivery error (connection refused, timeout, 4xx & 5xx responses) is optional, and Jitter
+// randomizes every backoff delay in the range [0, delay) ("full jitter") to spread out the retries.  The
+// retryable and non-retryable (permanent failure) status codes override the default retry classification
+// of subscriber responses.
+type EKRetryConfig struct {
+	Jitter                  bool                 `json:"jitter,omitempty"`
+	RetryableStatusCodes    []int                `json:"retryableStatusCodes,omitempty"`
+	NonRetryableStatusCodes []int                `json:"nonRetryableStatusCodes,omitempty"`
+	ConnectionRefused       *EKRetryPolicyConfig `json:"connectionRefused,omitempty"`
+	Timeout                 *EKRetryPolicyConfig `json:"timeout,omitempty"`
+	ClientError             *EKRetryPolicyConfig `json:"clientError,omitempty"`
+	ServerError             *EKRetryPolicyConfig `json:"serverError,omitempty"`
+}
+
+// EKRetryPolicyConfig is the retry policy of a single category of delivery errors.  Unset fields default
+// to the subscriber's delivery spec, and the backoff policy is one of "exponential" or "linear".
+type EKRetryPolicyConfig struct {
+	MaxRetries         *int   `json:"maxRetries,omitempty"`
+	BackoffPolicy      string `json:"backoffPolicy,omitempty"`
+	BackoffDelayMillis int64  `json:"backoffDelayMillis,omitempty"`
+	BackoffMaxMillis   int64  `json:"backoffMaxMillis,omitempty"`
 }
 
 // EKKafkaTopicConfig contains some defaults that are only used if not provided by the channel spec
@@ -57,23 +395,216 @@ type EKKafkaTopicConfig struct {
 	DefaultRetentionMillis   int64 `json:"defaultRetentionMillis,omitempty"`
 }
 
+// EKWorkloadIdentityConfig enables "secret-less" authentication with the Kafka brokers (SASL/OAUTHBEARER), using
+// access tokens obtained by exchanging the projected Kubernetes ServiceAccount token of each component (with the
+// specified Audience) at the TokenEndpoint.  The Exchanger is the name of the registered token exchanger ("sts",
+// the RFC 8693 OAuth 2.0 Token Exchange, by default) and the TokenPath defaults to the projected token's path.
+type EKWorkloadIdentityConfig struct {
+	Enabled       bool   `json:"enabled,omitempty"`
+	Exchanger     string `json:"exchanger,omitempty"`
+	TokenPath     string `json:"tokenPath,omitempty"`
+	TokenEndpoint string `json:"tokenEndpoint,omitempty"`
+	Audience      string `json:"audience,omitempty"`
+	Scope         string `json:"scope,omitempty"`
+}
+
 // EKKafkaConfig contains items relevant to Kafka specifically
 type EKKafkaConfig struct {
-	Topic     EKKafkaTopicConfig `json:"topic,omitempty"`
-	AdminType string             `json:"adminType,omitempty"`
+	Topic                 EKKafkaTopicConfig             `json:"topic,omitempty"`
+	AdminType             string                         `json:"adminType,omitempty"`
+	WorkloadIdentity      EKWorkloadIdentityConfig       `json:"workloadIdentity,omitempty"`
+	AuthSpec              *bindingsv1beta1.KafkaAuthSpec `json:"authSpec,omitempty"`
+	ClientIdTemplate      string                         `json:"clientIdTemplate,omitempty"`
+	Quarantine            EKQuarantineConfig             `json:"quarantine,omitempty"`
+	Headers               headers.Policy                 `json:"headers,omitempty"`
+	Strimzi               EKStrimziConfig                `json:"strimzi,omitempty"`
+	ProvisionerConfig     map[string]string              `json:"provisionerConfig,omitempty"`
+	StartupWait           EKStartupWaitConfig            `json:"startupWait,omitempty"`
+	Encryption            EKEncryptionConfig             `json:"encryption,omitempty"`
+	BrokerAddressRewrites map[string]string              `json:"brokerAddressRewrites,omitempty"` // Advertised -> Reachable "host:port"
+	Proxy                 EKProxyConfig                  `json:"proxy,omitempty"`
+	ExtraTopics           EKExtraTopicsConfig            `json:"extraTopics,omitempty"`
+}
+
+// EKExtraTopicsConfig allows KafkaChannels to fan in the records of externally managed topics (their ExtraTopics),
+// which is disabled by default since a KafkaChannel could otherwise read any topic the eventing-kafka credentials can.
+// Only topics starting with one of the AllowedPrefixes may be fanned in (an empty prefix allowing every topic), others
+// fail the reconciliation of the KafkaChannel and the subscriptions of its dispatcher.
+type EKExtraTopicsConfig struct {
+	AllowedPrefixes []string `json:"allowedPrefixes,omitempty"`
+}
+
+// EKProxyConfig routes the Kafka client connections of the controller, receiver and dispatchers through an egress
+// proxy (a socks5:// or http:// URL with a host:port).  The proxy credentials, if any, are the "username" and
+// "password" keys of the SecretName Secret in the knative-eventing namespace.
+type EKProxyConfig struct {
+	URL        string `json:"url,omitempty"`
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// EKEncryptionConfig enables the envelope encryption of event payloads at rest.  The receiver encrypts the value of
+// each record with AES-256-GCM under a data key, which is wrapped by the key encryption key KeyId (one of the keys of
+// the SecretName Secret in the knative-eventing namespace) and carried in the record's headers.  The dispatcher
+// transparently decrypts the records with whichever key wrapped their data key, so retired keys must remain in the
+// Secret until their records have expired from Kafka.
+type EKEncryptionConfig struct {
+	Enabled    bool   `json:"enabled,omitempty"`
+	SecretName string `json:"secretName,omitempty"`
+	KeyId      string `json:"keyId,omitempty"`
+}
+
+// EKStartupWaitConfig controls how long the receiver and dispatcher wait at startup for the Kafka brokers to become
+// reachable (defaulting to 5 minutes) and the exponential backoff between the connection attempts (starting at
+// InitialBackoffMillis, defaulting to 1 second, and doubling up to MaxBackoffMillis, defaulting to 30 seconds).
+type EKStartupWaitConfig struct {
+	MaxWaitSeconds       int64 `json:"maxWaitSeconds,omitempty"`
+	InitialBackoffMillis int64 `json:"initialBackoffMillis,omitempty"`
+	MaxBackoffMillis     int64 `json:"maxBackoffMillis,omitempty"`
+}
+
+// EKStrimziConfig contains the settings of the "strimzi" AdminType, which manages topics as Strimzi KafkaTopic
+// resources (labelled with the Cluster name) in the Namespace watched by the Strimzi Topic Operator (defaulting to
+// knative-eventing), waiting up to ReadyTimeoutMillis for the operator to report them Ready.
+type EKStrimziConfig struct {
+	Cluster            string `json:"cluster,omitempty"`
+	Namespace          string `json:"namespace,omitempty"`
+	ReadyTimeoutMillis int64  `json:"readyTimeoutMillis,omitempty"`
+}
+
+// EKQuarantineConfig enables a quarantine topic per KafkaChannel, to which the dispatcher produces the events it
+// failed to deliver to subscribers without a DeadLetterSink, as well as undecodable records (poison pills) unless
+// the poisonPill config specifies its own topic.  Quarantined events are retained for RetentionMillis (defaulting
+// to the KafkaChannel's retention) and may be re-injected into the KafkaChannel with an EventRedelivery.
+type EKQuarantineConfig struct {
+	Enabled         bool  `json:"enabled,omitempty"`
+	RetentionMillis int64 `json:"retentionMillis,omitempty"`
+}
+
+// EKFaultInjectionConfig contains the (non-production) data plane fault injection settings.  Percentages
+// are expressed in the range 0-100 and are evaluated independently for every produced / consumed event.
+type EKFaultInjectionConfig struct {
+	Enabled                  bool    `json:"enabled,omitempty"`
+	ProduceFailurePercent    float64 `json:"produceFailurePercent,omitempty"`
+	RebalancePercent         float64 `json:"rebalancePercent,omitempty"`
+	SubscriberLatencyPercent float64 `json:"subscriberLatencyPercent,omitempty"`
+	SubscriberLatencyMillis  int64   `json:"subscriberLatencyMillis,omitempty"`
+}
+
+// EKMetricsAggregatorConfig enables the controller's metrics aggregator, which periodically scrapes the metrics
+// of every dispatcher pod and serves per-KafkaChannel summaries (consumer lag & error rates) on the Port.
+type EKMetricsAggregatorConfig struct {
+	Enabled              bool                     `json:"enabled,omitempty"`
+	Port                 int                      `json:"port,omitempty"`
+	ScrapeIntervalMillis int64                    `json:"scrapeIntervalMillis,omitempty"`
+	PartitionAdvisor     EKPartitionAdvisorConfig `json:"partitionAdvisor,omitempty"`
+	ResizeAdvisor        EKResizeAdvisorConfig    `json:"resizeAdvisor,omitempty"`
+}
+
+// EKPartitionAdvisorConfig enables the comparison of the aggregated dispatch throughput of each KafkaChannel with
+// its partition count, warning (via a PartitionsSufficient condition & event) of KafkaChannels whose consumer lag
+// grows because their partitions limit the dispatch concurrency.  If AutoExpand is set the partitions of such
+// KafkaChannels' topics are instead increased to the recommended count, up to MaxPartitions.
+type EKPartitionAdvisorConfig struct {
+	Enabled       bool  `json:"enabled,omitempty"`
+	AutoExpand    bool  `json:"autoExpand,omitempty"`
+	MaxPartitions int32 `json:"maxPartitions,omitempty"`
+}
+
+// EKResizeAdvisorConfig enables the comparison of the resource usage of the dispatcher & receiver containers (as
+// reported by the metrics-server) with their requests, recommending (via a ResourcesRightsized condition & event on
+// the KafkaChannels) requests of the peak usage within the last WindowMillis plus HeadroomPercent wherever a request
+// deviates from that by more than TolerancePercent.  If CreateVPA is set a VerticalPodAutoscaler with the
+// VPAUpdateMode (default "Off", only recording its own recommendations) is also created for each dispatcher.
+type EKResizeAdvisorConfig struct {
+	Enabled          bool   `json:"enabled,omitempty"`
+	WindowMillis     int64  `json:"windowMillis,omitempty"`
+	HeadroomPercent  int    `json:"headroomPercent,omitempty"`
+	TolerancePercent int    `json:"tolerancePercent,omitempty"`
+	CreateVPA        bool   `json:"createVPA,omitempty"`
+	VPAUpdateMode    string `json:"vpaUpdateMode,omitempty"`
+}
+
+// EKNamingConfig selects the strategy generating the names of the dispatcher Deployments & Services, either "truncate"
+// (the default, truncating the KafkaChannel name & namespace and appending a short hash) or the collision resistant
+// "hash".  The dispatchers of existing KafkaChannels are migrated to the new names when the strategy is changed.
+type EKNamingConfig struct {
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// EKNetworkConfig selects the IP family of the addresses to which the HTTP listeners of the receiver, dispatchers and
+// controller bind, either the default "" (all addresses of both families on dual-stack nodes), "IPv4" or "IPv6".
+type EKNetworkConfig struct {
+	IPFamily string `json:"ipFamily,omitempty"`
+}
+
+// EKJanitorConfig enables the controller's janitor, which periodically (every IntervalMillis, defaulting to 10 minutes)
+// finds the dispatcher Deployments & Services, topics and ConsumerGroups left behind by KafkaChannels (or Subscribers)
+// which no longer exist and deletes them, or only reports them (in the log and metrics) when DryRun is set.  Only the
+// topics which the controller recorded as created by it (while the janitor was enabled) are ever considered.
+type EKJanitorConfig struct {
+	Enabled        bool  `json:"enabled,omitempty"`
+	DryRun         bool  `json:"dryRun,omitempty"`
+	IntervalMillis int64 `json:"intervalMillis,omitempty"`
+}
+
+// EKAuditConfig enables the controller's audit log, which records every change it makes to the Kafka topics, the
+// dispatcher & receiver Deployments, and every rotation of the Kafka Secrets it observes, as CloudEvents produced to
+// the Topic (defaulting to "knative-eventing.kafka-audit", which is not created by the controller).  Up to BufferSize
+// (defaulting to 1000) records awaiting production are buffered, beyond which further records are dropped (and logged).
+type EKAuditConfig struct {
+	Enabled    bool   `json:"enabled,omitempty"`
+	Topic      string `json:"topic,omitempty"`
+	BufferSize int    `json:"bufferSize,omitempty"`
+}
+
+// EKOffsetExportConfig enables the export of the committed offsets & lag of the ConsumerGroup of each subscriber of the
+// KafkaChannels to their status, refreshed by the controller at most every IntervalMillis (defaulting to 1 minute) to
+// limit the load on the Kafka brokers & the Kubernetes API server.
+type EKOffsetExportConfig struct {
+	Enabled        bool  `json:"enabled,omitempty"`
+	IntervalMillis int64 `json:"intervalMillis,omitempty"`
+}
+
+// EKMiddlewareConfig loads the user-defined middleware Modules which KafkaChannels may select (by name, in order) with
+// their middleware annotation.  The modules are files of the ConfigMapName ConfigMap in the knative-eventing namespace
+// (e.g. WebAssembly modules as binaryData), executed by the named Runtime which must be compiled into the receiver and
+// dispatcher (none is in the standard images, and there is no default).
+type EKMiddlewareConfig struct {
+	Enabled       bool                 `json:"enabled,omitempty"`
+	Runtime       string               `json:"runtime,omitempty"`
+	ConfigMapName string               `json:"configMapName,omitempty"`
+	Modules       []EKMiddlewareModule `json:"modules,omitempty"`
+}
+
+// EKMiddlewareModule is a single middleware module, loaded from the File of the middleware ConfigMap with its Config
+// and applied to the events received by the receiver and/or dispatched by the dispatcher per its Phases ("receive"
+// and "dispatch", defaulting to both).
+type EKMiddlewareModule struct {
+	Name   string            `json:"name,omitempty"`
+	File   string            `json:"file,omitempty"`
+	Phases []string          `json:"phases,omitempty"`
+	Config map[string]string `json:"config,omitempty"`
 }
 
-// EventingKafkaConfig is the main struct that holds the Receiver, Dispatcher, and Kafka sub-items
+// EventingKafkaConfig is the main struct that holds the Receiver, Dispatcher, and Kafka sub-items.  The Version is
+// that of the settings layout, to which ParseEventingKafkaConfig migrates the settings of older layouts.
 type EventingKafkaConfig struct {
-	Receiver   EKReceiverConfig   `json:"receiver,omitempty"`
-	Dispatcher EKDispatcherConfig `json:"dispatcher,omitempty"`
-	Kafka      EKKafkaConfig      `json:"kafka,omitempty"`
+	Version           int                       `json:"version,omitempty"`
+	Receiver          EKReceiverConfig          `json:"receiver,omitempty"`
+	Dispatcher        EKDispatcherConfig        `json:"dispatcher,omitempty"`
+	Kafka             EKKafkaConfig             `json:"kafka,omitempty"`
+	FaultInjection    EKFaultInjectionConfig    `json:"faultInjection,omitempty"`
+	MetricsAggregator EKMetricsAggregatorConfig `json:"metricsAggregator,omitempty"`
+	Naming            EKNamingConfig            `json:"naming,omitempty"`
+	Janitor           EKJanitorConfig           `json:"janitor,omitempty"`
+	Middleware        EKMiddlewareConfig        `json:"middleware,omitempty"`
+	Network           EKNetworkConfig           `json:"network,omitempty"`
+	Audit             EKAuditConfig             `json:"audit,omitempty"`
+	OffsetExport      EKOffsetExportConfig      `json:"offsetExport,omitempty"`
 }
 
-//
 // Initialize The Specified Context With A ConfigMap Watcher
 // Much Of This Function Is Taken From The knative.dev sharedmain Package
-//
 func InitializeConfigWatcher(ctx context.Context, logger *zap.SugaredLogger, handler configmap.Observer) error {
 
 	// Create A Watcher On The Configuration Settings ConfigMap & Dynamically Update Configuration
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Verify That The Specified ExtraTopics Of A KafkaChannel May Be Fanned In, Returning An Error Listing Any Which May Not
+func (c EKExtraTopicsConfig) Verify(extraTopics []string) error {
+	var disallowed []string
+	for _, topic := range extraTopics {
+		if !c.Allowed(topic) {
+			disallowed = append(disallowed, topic)
+		}
+	}
+	if len(disallowed) > 0 {
+		return fmt.Errorf("extraTopics %v are not allowed by the kafka.extraTopics.allowedPrefixes configuration", disallowed)
+	}
+	return nil
+}
+
+// Determine Whether The Specified Topic May Be Fanned In To A KafkaChannel (Never Unless AllowedPrefixes Are Configured)
+func (c EKExtraTopicsConfig) Allowed(topic string) bool {
+	for _, prefix := range c.AllowedPrefixes {
+		if strings.HasPrefix(topic, prefix) {
+			return true
+		}
+	}
+	return false
+}
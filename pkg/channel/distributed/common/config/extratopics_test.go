@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test The EKExtraTopicsConfig Verify() Functionality
+func TestExtraTopicsVerify(t *testing.T) {
+	type TestCase struct {
+		name            string
+		allowedPrefixes []string
+		extraTopics     []string
+		wantErr         string
+	}
+	testCases := []TestCase{
+		{name: "No ExtraTopics", extraTopics: nil},
+		{name: "ExtraTopics Disabled By Default", extraTopics: []string{"external.topic"}, wantErr: "extraTopics [external.topic] are not allowed by the kafka.extraTopics.allowedPrefixes configuration"},
+		{name: "Allowed Prefix", allowedPrefixes: []string{"other.", "external."}, extraTopics: []string{"external.topic", "other.topic"}},
+		{name: "Disallowed Prefix", allowedPrefixes: []string{"external."}, extraTopics: []string{"external.topic", "knative-eventing.kafka-audit", "default.channel"}, wantErr: "extraTopics [knative-eventing.kafka-audit default.channel] are not allowed by the kafka.extraTopics.allowedPrefixes configuration"},
+		{name: "Empty Prefix Allows All", allowedPrefixes: []string{""}, extraTopics: []string{"any.topic"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := EKExtraTopicsConfig{AllowedPrefixes: testCase.allowedPrefixes}.Verify(testCase.extraTopics)
+			if len(testCase.wantErr) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.wantErr, err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+)
+
+// The dispatcher settings which are applied by the dispatcher itself (rather than the controller), and which
+// are therefore passed to the dispatcher of the namespace's KafkaChannels as overrides.
+var dispatcherDataPlaneSettings = []string{"retry", "tail", "dedupe", "consumerGroup"}
+
+// The settings which may be overridden per namespace (or class), either entirely (true) or only as far as their
+// nested allowed settings.  Any other setting is a conflict, in particular the images, security contexts & seccomp
+// profile of the dispatcher Deployments, which run in the knative-eventing namespace with the Kafka credentials.
+var overridableSettings = map[string]interface{}{
+	"dispatcher": map[string]interface{}{
+		"replicas":      true,
+		"cpuRequest":    true,
+		"cpuLimit":      true,
+		"memoryRequest": true,
+		"memoryLimit":   true,
+		"retry":         true,
+		"tail":          true,
+		"dedupe":        true,
+		"consumerGroup": true,
+	},
+	"kafka": map[string]interface{}{
+		"topic": true,
+	},
+}
+
+// NamespaceConfig is the effective configuration of the KafkaChannels in a namespace, which is the cluster-wide
+// configuration overridden by the namespace's ConfigMap.  DispatcherOverrides holds the (JSON) dispatcher settings
+// which the namespace overrides and which are applied by the dispatcher, and Conflicts lists the settings of the
+// namespace's ConfigMap which were ignored because they cannot be overridden per namespace.
+type NamespaceConfig struct {
+	*EventingKafkaConfig
+	DispatcherOverrides string
+	Conflicts           []string
+}
+
+// MergeNamespaceConfig layers the eventing-kafka settings of the specified namespace ConfigMap (which may be nil)
+// over the cluster-wide configuration, which is not modified.  Only the dispatcher's replicas, resources, retry, tail,
+// dedupe & consumer group settings and the Kafka Topic settings may be overridden, since the other settings are either
+// shared by the KafkaChannels of all namespaces or would let a namespace alter the privileges of its dispatcher.
+func MergeNamespaceConfig(clusterConfig *EventingKafkaConfig, configMap *corev1.ConfigMap) (*NamespaceConfig, error) {
+
+	// Nothing To Merge Without Namespace Settings
+	namespaceConfig := &NamespaceConfig{EventingKafkaConfig: clusterConfig}
+	if configMap == nil || len(configMap.Data[EventingKafkaSettingsConfigKey]) == 0 {
+		return namespaceConfig, nil
+	}
+
+	// Unmarshal The Namespace Settings Generically To Tell The Overridden Settings From The Unset Ones
+	overrides := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(configMap.Data[EventingKafkaSettingsConfigKey]), &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s's eventing-kafka value could not be parsed: %v", configMap.Namespace, configMap.Name, err)
+	}
+
+	// Merge The Overrides Into A Copy Of The Cluster-Wide Configuration
+	namespaceConfig, err = namespaceConfig.merge(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s's eventing-kafka value could not be converted to an EventingKafkaConfig struct: %v", configMap.Namespace, configMap.Name, err)
+	}
+
+	// Return The Merged Namespace Configuration
+	return namespaceConfig, nil
+}
+
+// MergeClassConfig layers the settings of a KafkaChannel's class (which may be nil) over the configuration of its
+// namespace, which is not modified.  The class's eventing-kafka settings are subject to the same restrictions as
+// those of the namespace's ConfigMap (reported as Conflicts), its dispatcher data plane settings are merged with the
+// namespace's, and its NumPartitions & ReplicationFactor are the defaults of the Kafka Topic.
+func MergeClassConfig(namespaceConfig *NamespaceConfig, class *messagingconfig.ChannelClass) (*NamespaceConfig, error) {
+
+	// Nothing To Merge Without Class Settings
+	classConfig := &NamespaceConfig{EventingKafkaConfig: namespaceConfig.EventingKafkaConfig, DispatcherOverrides: namespaceConfig.DispatcherOverrides}
+	if class == nil || (len(class.EventingKafka) == 0 && class.NumPartitions == 0 && class.ReplicationFactor == 0) {
+		return classConfig, nil
+	}
+
+	// Unmarshal The Class's Eventing-Kafka Settings Generically & Add The Kafka Topic Defaults
+	overrides := map[string]interface{}{}
+	if len(class.EventingKafka) > 0 {
+		err := json.Unmarshal(class.EventingKafka, &overrides)
+		if err != nil {
+			return nil, fmt.Errorf("eventingKafka settings could not be parsed: %v", err)
+		}
+	}
+	topicOverrides := map[string]interface{}{}
+	if class.NumPartitions > 0 {
+		topicOverrides["defaultNumPartitions"] = class.NumPartitions
+	}
+	if class.ReplicationFactor > 0 {
+		topicOverrides["defaultReplicationFactor"] = class.ReplicationFactor
+	}
+	if len(topicOverrides) > 0 {
+		overrides["kafka"] = mergeSetting(overrides["kafka"], map[string]interface{}{"topic": topicOverrides})
+	}
+
+	// Merge The Overrides Into A Copy Of The Namespace Configuration
+	classConfig, err := classConfig.merge(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("eventingKafka settings could not be converted to an EventingKafkaConfig struct: %v", err)
+	}
+	return classConfig, nil
+}
+
+// merge layers the specified (generically unmarshalled) overrides over a copy of the configuration, after removing
+// (& recording as Conflicts) the settings which cannot be overridden per namespace.  The dispatcher's data plane
+// overrides are merged over those of the configuration.
+func (c *NamespaceConfig) merge(overrides map[string]interface{}) (*NamespaceConfig, error) {
+	mergedConfig := &NamespaceConfig{DispatcherOverrides: c.DispatcherOverrides}
+
+	// Remove (& Record) The Settings Which Cannot Be Overridden Per Namespace
+	mergedConfig.Conflicts = removeConflicts(overrides, overridableSettings, "")
+	sort.Strings(mergedConfig.Conflicts)
+
+	// Extract The Dispatcher's Data Plane Overrides
+	if dispatcherOverrides, ok := overrides["dispatcher"].(map[string]interface{}); ok {
+		dataPlaneOverrides := map[string]interface{}{}
+		if len(c.DispatcherOverrides) > 0 {
+			if err := json.Unmarshal([]byte(c.DispatcherOverrides), &dataPlaneOverrides); err != nil {
+				return nil, err
+			}
+		}
+		for _, setting := range dispatcherDataPlaneSettings {
+			if value, ok := dispatcherOverrides[setting]; ok {
+				dataPlaneOverrides[setting] = mergeSetting(dataPlaneOverrides[setting], value)
+			}
+		}
+		if len(dataPlaneOverrides) > 0 {
+			dataPlaneOverridesJson, err := json.Marshal(dataPlaneOverrides)
+			if err != nil {
+				return nil, err
+			}
+			mergedConfig.DispatcherOverrides = string(dataPlaneOverridesJson)
+		}
+	}
+
+	// Merge The Remaining Overrides Into A Copy Of The Configuration (Overrides Take Precedence)
+	configJson, err := json.Marshal(c.EventingKafkaConfig)
+	if err != nil {
+		return nil, err
+	}
+	overridesJson, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, err
+	}
+	mergedConfig.EventingKafkaConfig = &EventingKafkaConfig{}
+	if err = json.Unmarshal(configJson, mergedConfig.EventingKafkaConfig); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(overridesJson, mergedConfig.EventingKafkaConfig); err != nil {
+		return nil, err
+	}
+	return mergedConfig, nil
+}
+
+// removeConflicts removes the (generically unmarshalled) overrides which are not allowed by the specified overridable
+// settings, recursively for nested settings, and returns their (dotted) paths.
+func removeConflicts(overrides map[string]interface{}, allowed map[string]interface{}, prefix string) []string {
+	var conflicts []string
+	for setting, override := range overrides {
+		allowedSetting, ok := allowed[setting]
+		if !ok {
+			conflicts = append(conflicts, prefix+setting)
+			delete(overrides, setting)
+			continue
+		}
+		nestedAllowed, nestedAllowedOk := allowedSetting.(map[string]interface{})
+		nestedOverrides, nestedOverridesOk := override.(map[string]interface{})
+		if nestedAllowedOk && nestedOverridesOk {
+			conflicts = append(conflicts, removeConflicts(nestedOverrides, nestedAllowed, prefix+setting+".")...)
+		}
+	}
+	return conflicts
+}
+
+// mergeSetting merges the (generically unmarshalled) override over the value of a setting, recursively for nested
+// settings, so that the overridden value keeps the nested settings which are not overridden.
+func mergeSetting(value interface{}, override interface{}) interface{} {
+	valueMap, valueIsMap := value.(map[string]interface{})
+	overrideMap, overrideIsMap := override.(map[string]interface{})
+	if !valueIsMap || !overrideIsMap {
+		return override
+	}
+	for setting, settingOverride := range overrideMap {
+		valueMap[setting] = mergeSetting(valueMap[setting], settingOverride)
+	}
+	return valueMap
+}
+
+// MergeDispatcherOverrides applies the (JSON) namespace overrides of the dispatcher's data plane settings over the
+// dispatcher configuration loaded from the cluster-wide ConfigMap.
+func MergeDispatcherOverrides(dispatcherConfig *EKDispatcherConfig, overrides string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	err := json.Unmarshal([]byte(overrides), dispatcherConfig)
+	if err != nil {
+		return fmt.Errorf("dispatcher config overrides could not be converted to an EKDispatcherConfig struct: %v", err)
+	}
+	return nil
+}
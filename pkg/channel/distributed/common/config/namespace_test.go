@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+)
+
+// Test The MergeNamespaceConfig() Functionality
+func TestMergeNamespaceConfig(t *testing.T) {
+
+	// Create The Cluster-Wide Configuration
+	clusterConfig := &EventingKafkaConfig{
+		Receiver:   EKReceiverConfig{EKKubernetesConfig: EKKubernetesConfig{Replicas: 1}},
+		Dispatcher: EKDispatcherConfig{EKKubernetesConfig: EKKubernetesConfig{Replicas: 1, CpuLimit: resource.MustParse("500m")}},
+		Kafka:      EKKafkaConfig{Topic: EKKafkaTopicConfig{DefaultNumPartitions: 4, DefaultReplicationFactor: 1}, AdminType: "kafka"},
+	}
+
+	// Without A Namespace ConfigMap The Cluster-Wide Configuration Is Used As-Is
+	namespaceConfig, err := MergeNamespaceConfig(clusterConfig, nil)
+	assert.Nil(t, err)
+	assert.Same(t, clusterConfig, namespaceConfig.EventingKafkaConfig)
+	assert.Empty(t, namespaceConfig.DispatcherOverrides)
+	assert.Empty(t, namespaceConfig.Conflicts)
+
+	// Invalid Namespace Settings Are An Error
+	_, err = MergeNamespaceConfig(clusterConfig, createTestNamespaceConfigMap("dispatcher: [invalid"))
+	assert.NotNil(t, err)
+	_, err = MergeNamespaceConfig(clusterConfig, createTestNamespaceConfigMap("dispatcher:\n  replicas: foo"))
+	assert.NotNil(t, err)
+
+	// Merge Namespace Settings Including Some Which Cannot Be Overridden
+	namespaceConfig, err = MergeNamespaceConfig(clusterConfig, createTestNamespaceConfigMap(`
+receiver:
+  replicas: 3
+dispatcher:
+  replicas: 2
+  cpuRequest: 200m
+  retry:
+    jitter: true
+  consumerGroup:
+    offsetsRetentionMillis: 1209600000
+  images:
+    default: attacker/dispatcher:latest
+  securityContext:
+    privileged: true
+  podSecurityContext:
+    runAsUser: 0
+  seccompProfile: Unconfined
+  snapshot:
+    enabled: true
+kafka:
+  adminType: azure
+  topic:
+    defaultNumPartitions: 8
+faultInjection:
+  enabled: true
+metricsAggregator:
+  enabled: true
+naming:
+  strategy: hash
+janitor:
+  enabled: true
+middleware:
+  enabled: true
+audit:
+  enabled: true
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"audit", "dispatcher.images", "dispatcher.podSecurityContext", "dispatcher.seccompProfile", "dispatcher.securityContext", "dispatcher.snapshot", "faultInjection", "janitor", "kafka.adminType", "metricsAggregator", "middleware", "naming", "receiver"}, namespaceConfig.Conflicts)
+	assert.Equal(t, `{"consumerGroup":{"offsetsRetentionMillis":1209600000},"retry":{"jitter":true}}`, namespaceConfig.DispatcherOverrides)
+	assert.Equal(t, int64(1209600000), namespaceConfig.Dispatcher.ConsumerGroup.OffsetsRetentionMillis)
+	assert.Equal(t, 1, namespaceConfig.Receiver.Replicas)
+	assert.Equal(t, 2, namespaceConfig.Dispatcher.Replicas)
+	assert.Equal(t, "500m", namespaceConfig.Dispatcher.CpuLimit.String())
+	assert.Equal(t, "200m", namespaceConfig.Dispatcher.CpuRequest.String())
+	assert.Empty(t, namespaceConfig.Dispatcher.Images.Default)
+	assert.Nil(t, namespaceConfig.Dispatcher.SecurityContext)
+	assert.Nil(t, namespaceConfig.Dispatcher.PodSecurityContext)
+	assert.Empty(t, namespaceConfig.Dispatcher.SeccompProfile)
+	assert.False(t, namespaceConfig.Dispatcher.Snapshot.Enabled)
+	assert.Equal(t, "kafka", namespaceConfig.Kafka.AdminType)
+	assert.Equal(t, int32(8), namespaceConfig.Kafka.Topic.DefaultNumPartitions)
+	assert.Equal(t, int16(1), namespaceConfig.Kafka.Topic.DefaultReplicationFactor)
+	assert.False(t, namespaceConfig.FaultInjection.Enabled)
+	assert.Empty(t, namespaceConfig.Naming.Strategy)
+
+	// Verify The Cluster-Wide Configuration Was Not Modified
+	assert.Equal(t, 1, clusterConfig.Dispatcher.Replicas)
+	assert.Equal(t, int32(4), clusterConfig.Kafka.Topic.DefaultNumPartitions)
+}
+
+// Test The MergeClassConfig() Functionality
+func TestMergeClassConfig(t *testing.T) {
+
+	// Create The Namespace Configuration
+	namespaceConfig := &NamespaceConfig{
+		EventingKafkaConfig: &EventingKafkaConfig{
+			Dispatcher: EKDispatcherConfig{EKKubernetesConfig: EKKubernetesConfig{Replicas: 1, CpuLimit: resource.MustParse("500m")}},
+			Kafka:      EKKafkaConfig{Topic: EKKafkaTopicConfig{DefaultNumPartitions: 4, DefaultReplicationFactor: 1}, AdminType: "kafka"},
+		},
+		DispatcherOverrides: `{"retry":{"jitter":true},"tail":{"enabled":true}}`,
+		Conflicts:           []string{"receiver"},
+	}
+
+	// Without Class Settings The Namespace Configuration Is Used As-Is
+	classConfig, err := MergeClassConfig(namespaceConfig, nil)
+	assert.Nil(t, err)
+	assert.Same(t, namespaceConfig.EventingKafkaConfig, classConfig.EventingKafkaConfig)
+	assert.Equal(t, namespaceConfig.DispatcherOverrides, classConfig.DispatcherOverrides)
+	assert.Empty(t, classConfig.Conflicts)
+
+	// Invalid Class Settings Are An Error
+	_, err = MergeClassConfig(namespaceConfig, &messagingconfig.ChannelClass{EventingKafka: []byte(`{"dispatcher":{"replicas":"foo"}}`)})
+	assert.NotNil(t, err)
+
+	// Merge Class Settings Including Some Which Cannot Be Overridden
+	classConfig, err = MergeClassConfig(namespaceConfig, &messagingconfig.ChannelClass{
+		NumPartitions: 12,
+		EventingKafka: []byte(`{"dispatcher":{"replicas":3,"retry":{"maxRetries":5},"images":{"classes":{"canary":"attacker/dispatcher"}}},"kafka":{"adminType":"azure","topic":{"defaultNumPartitions":8,"defaultRetentionMillis":60000}},"naming":{"strategy":"hash"}}`),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dispatcher.images", "kafka.adminType", "naming"}, classConfig.Conflicts)
+	assert.Equal(t, `{"retry":{"jitter":true,"maxRetries":5},"tail":{"enabled":true}}`, classConfig.DispatcherOverrides)
+	assert.Equal(t, 3, classConfig.Dispatcher.Replicas)
+	assert.Equal(t, "500m", classConfig.Dispatcher.CpuLimit.String())
+	assert.Equal(t, "kafka", classConfig.Kafka.AdminType)
+	assert.Equal(t, int32(12), classConfig.Kafka.Topic.DefaultNumPartitions)
+	assert.Equal(t, int16(1), classConfig.Kafka.Topic.DefaultReplicationFactor)
+	assert.Equal(t, int64(60000), classConfig.Kafka.Topic.DefaultRetentionMillis)
+	assert.Empty(t, classConfig.Naming.Strategy)
+
+	// Verify The Namespace Configuration Was Not Modified
+	assert.Equal(t, 1, namespaceConfig.Dispatcher.Replicas)
+	assert.Equal(t, int32(4), namespaceConfig.Kafka.Topic.DefaultNumPartitions)
+	assert.Equal(t, `{"retry":{"jitter":true},"tail":{"enabled":true}}`, namespaceConfig.DispatcherOverrides)
+}
+
+// Test The MergeDispatcherOverrides() Functionality
+func TestMergeDispatcherOverrides(t *testing.T) {
+	dispatcherConfig := &EKDispatcherConfig{Tail: EKTailConfig{Enabled: true, Port: 8082}}
+	assert.Nil(t, MergeDispatcherOverrides(dispatcherConfig, ""))
+	assert.Nil(t, MergeDispatcherOverrides(dispatcherConfig, `{"tail":{"allowPayload":true},"dedupe":{"enabled":true}}`))
+	assert.Equal(t, EKTailConfig{Enabled: true, Port: 8082, AllowPayload: true}, dispatcherConfig.Tail)
+	assert.True(t, dispatcherConfig.Dedupe.Enabled)
+	assert.NotNil(t, MergeDispatcherOverrides(dispatcherConfig, "{"))
+}
+
+// Utility Function For Creating A Namespace ConfigMap With The Specified Eventing-Kafka Settings
+func createTestNamespaceConfigMap(eventingKafkaSettings string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: NamespaceSettingsConfigMapName, Namespace: "test-namespace"},
+		Data:       map[string]string{EventingKafkaSettingsConfigKey: eventingKafkaSettings},
+	}
+}
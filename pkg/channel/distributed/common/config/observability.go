@@ -26,7 +26,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	commonprofiling "knative.dev/eventing-kafka/pkg/common/profiling"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/injection/sharedmain"
 	"knative.dev/pkg/metrics"
 	"knative.dev/pkg/profiling"
@@ -65,6 +67,10 @@ func InitializeObservability(ctx context.Context, logger *zap.SugaredLogger, met
 	// Initialize the memory stats, which will be added to the eventing metrics exporter every 30 seconds
 	metrics.MemStatsOrDie(ctx)
 
+	// Capture Heap Profiles On High Memory Watermarks If Configured In The Observability ConfigMap
+	heapProfiler := commonprofiling.NewHeapProfiler(logger.Desugar())
+	heapProfiler.Start(ctx)
+
 	// Create A Watcher On The Observability ConfigMap & Dynamically Update Observability Configuration
 	cmw := sharedmain.SetupConfigMapWatchOrDie(ctx, logger)
 
@@ -86,7 +92,8 @@ func InitializeObservability(ctx context.Context, logger *zap.SugaredLogger, met
 					logger.Error("Error during UpdateExporter", zap.Error(err))
 				}
 			},
-			profilingHandler.UpdateFromConfigMap)
+			profilingHandler.UpdateFromConfigMap,
+			heapProfiler.UpdateFromConfigMap)
 	} else if !apierrors.IsNotFound(err) {
 		logger.Error("Error reading ConfigMap "+metrics.ConfigMapName(), zap.Error(err))
 		return err
@@ -99,3 +106,23 @@ func InitializeObservability(ctx context.Context, logger *zap.SugaredLogger, met
 
 	return nil
 }
+
+//
+// Start A Heap Profiler Watching The Observability ConfigMap Via The Specified Watcher
+//
+// This is for the components run by the knative sharedmain (i.e. the controller), which already serves the pprof
+// endpoints per the Observability ConfigMap, but doesn't capture heap profiles on high memory watermarks.  The
+// ConfigMap is only watched if it exists, as the watcher would otherwise fail to start.
+//
+func StartHeapProfiler(ctx context.Context, logger *zap.Logger, cmw configmap.Watcher) error {
+	heapProfiler := commonprofiling.NewHeapProfiler(logger)
+	if _, err := kubeclient.Get(ctx).CoreV1().ConfigMaps(system.Namespace()).Get(ctx, metrics.ConfigMapName(),
+		metav1.GetOptions{}); err == nil {
+		cmw.Watch(metrics.ConfigMapName(), heapProfiler.UpdateFromConfigMap)
+	} else if !apierrors.IsNotFound(err) {
+		logger.Error("Error reading ConfigMap "+metrics.ConfigMapName(), zap.Error(err))
+		return err
+	}
+	heapProfiler.Start(ctx)
+	return nil
+}
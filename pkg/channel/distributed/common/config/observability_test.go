@@ -31,6 +31,7 @@ import (
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
 	commontesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
 	injectionclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
 	logtesting "knative.dev/pkg/logging/testing"
 	"knative.dev/pkg/metrics"
 	"knative.dev/pkg/system"
@@ -83,6 +84,29 @@ func TestInitializeObservability(t *testing.T) {
 	assertGet(t, fmt.Sprintf("http://localhost:%v/metrics", metricsPort), 200, 404)
 }
 
+// Test The StartHeapProfiler() Functionality
+func TestStartHeapProfiler(t *testing.T) {
+
+	// Test Data
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, constants.KnativeEventingNamespace))
+	observabilityConfigMap := &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: metrics.ConfigMapName(), Namespace: system.Namespace()},
+		Data:       map[string]string{"profiling.heap-watermark": "512Mi"},
+	}
+
+	// Verify The Observability ConfigMap Is Watched If It Exists (The StaticWatcher Panics On Watching Unknown ConfigMaps)
+	err := StartHeapProfiler(context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset(observabilityConfigMap)), logger,
+		configmap.NewStaticWatcher(observabilityConfigMap))
+	assert.Nil(t, err)
+
+	// Verify A Missing Observability ConfigMap Is Not Watched
+	err = StartHeapProfiler(context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset()), logger, configmap.NewStaticWatcher())
+	assert.Nil(t, err)
+}
+
 func assertGet(t *testing.T, url string, expected int, retryIfResponse int) {
 	resp, err := commontesting.RetryGet(url, 100*time.Millisecond, 20, retryIfResponse)
 	assert.Nil(t, err)
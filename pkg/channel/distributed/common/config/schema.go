@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// The version of the eventing-kafka settings layout understood by this release.  Settings without a version are
+// of the original (version 1) layout.
+const (
+	EventingKafkaConfigVersionInitial = 1
+	EventingKafkaConfigVersion        = 2
+)
+
+// The migrations of the eventing-kafka settings layout, the migration at index i taking the settings from version
+// i+1 to version i+2.  Each migration modifies the generically unmarshalled settings in place and returns warnings
+// describing any setting it could not carry forward.
+var schemaMigrations = []func(settings map[string]interface{}) []string{
+	migrateDispatcherRetrySettings,
+}
+
+// The version 1 dispatcher retry settings, which have been superseded by the delivery spec of the subscriptions
+// refined by the dispatcher.retry settings.
+var legacyDispatcherRetrySettings = []string{"retryInitialIntervalMillis", "retryTimeMillis", "retryExponentialBackoff"}
+
+// ParseEventingKafkaConfig parses the YAML eventing-kafka settings of the ConfigMap, migrating them from the layout
+// of their version to the current one, and returns them along with the warnings of the migration.  Settings which
+// are not part of the current layout (such as misspelled or renamed ones) are rejected with their full path, rather
+// than being silently ignored, as are versions newer than that of this release.
+func ParseEventingKafkaConfig(value string) (*EventingKafkaConfig, []string, error) {
+
+	// Unmarshal The Settings Generically
+	settings := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(value), &settings); err != nil {
+		return nil, nil, fmt.Errorf("invalid eventing-kafka settings: %v", err)
+	}
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+
+	// Determine The Version Of The Settings' Layout
+	version, err := settingsVersion(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Migrate The Settings To The Current Layout
+	var warnings []string
+	for ; version < EventingKafkaConfigVersion; version++ {
+		for _, warning := range schemaMigrations[version-EventingKafkaConfigVersionInitial](settings) {
+			warnings = append(warnings, fmt.Sprintf("version %d to %d migration: %s", version, version+1, warning))
+		}
+	}
+	settings["version"] = EventingKafkaConfigVersion
+
+	// Reject The Settings Unknown To The Current Layout
+	if unknown := unknownSettings(settings, reflect.TypeOf(EventingKafkaConfig{}), ""); len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, warnings, fmt.Errorf("unknown eventing-kafka settings: %s", strings.Join(unknown, ", "))
+	}
+
+	// Convert The Migrated Settings To An EventingKafkaConfig
+	settingsJson, err := json.Marshal(settings)
+	if err != nil {
+		return nil, warnings, fmt.Errorf("invalid eventing-kafka settings: %v", err)
+	}
+	configuration := &EventingKafkaConfig{}
+	if err := json.Unmarshal(settingsJson, configuration); err != nil {
+		return nil, warnings, fmt.Errorf("invalid eventing-kafka settings: %v", err)
+	}
+	return configuration, warnings, nil
+}
+
+// settingsVersion returns the supported version of the settings' layout (the initial version if unset).
+func settingsVersion(settings map[string]interface{}) (int, error) {
+	value, ok := settings["version"]
+	if !ok {
+		return EventingKafkaConfigVersionInitial, nil
+	}
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) {
+		return 0, fmt.Errorf("invalid eventing-kafka settings version %v: must be an integer", value)
+	}
+	version := int(number)
+	if version < EventingKafkaConfigVersionInitial || version > EventingKafkaConfigVersion {
+		return 0, fmt.Errorf("unsupported eventing-kafka settings version %d: this release supports versions %d to %d", version, EventingKafkaConfigVersionInitial, EventingKafkaConfigVersion)
+	}
+	return version, nil
+}
+
+// migrateDispatcherRetrySettings removes the version 1 dispatcher retry settings, whose backoff the dispatcher takes
+// from the delivery spec of each subscription (or the per error category dispatcher.retry policies) instead.
+func migrateDispatcherRetrySettings(settings map[string]interface{}) []string {
+	dispatcher, ok := settings["dispatcher"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var warnings []string
+	for _, name := range legacyDispatcherRetrySettings {
+		if _, ok := dispatcher[name]; ok {
+			delete(dispatcher, name)
+			warnings = append(warnings, fmt.Sprintf("removed dispatcher.%s, superseded by the delivery spec of the subscriptions & dispatcher.retry", name))
+		}
+	}
+	return warnings
+}
+
+// unknownSettings returns the paths of the (generically unmarshalled) settings which have no counterpart in the
+// specified type, following the encoding/json field naming & embedding rules.  Types which unmarshal themselves
+// (e.g. resource.Quantity) and untyped values accept any setting.
+func unknownSettings(value interface{}, valueType reflect.Type, path string) []string {
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if reflect.PtrTo(valueType).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return nil
+	}
+
+	var unknown []string
+	switch valueType.Kind() {
+	case reflect.Struct:
+		settings, ok := value.(map[string]interface{})
+		if !ok {
+			return nil // Type Mismatches Are Reported By The Unmarshalling
+		}
+		fields := jsonFields(valueType)
+		for name, setting := range settings {
+			field, ok := fields[strings.ToLower(name)]
+			if !ok {
+				unknown = append(unknown, settingPath(path, name))
+				continue
+			}
+			unknown = append(unknown, unknownSettings(setting, field.Type, settingPath(path, name))...)
+		}
+	case reflect.Map:
+		settings, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for name, setting := range settings {
+			unknown = append(unknown, unknownSettings(setting, valueType.Elem(), settingPath(path, name))...)
+		}
+	case reflect.Slice, reflect.Array:
+		settings, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, setting := range settings {
+			unknown = append(unknown, unknownSettings(setting, valueType.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return unknown
+}
+
+// jsonFields returns the fields of the struct type by lowercase JSON name (as encoding/json matches names case
+// insensitively), including those of embedded structs without a JSON name of their own.
+func jsonFields(structType reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && len(name) == 0 {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				for embeddedName, embeddedField := range jsonFields(embeddedType) {
+					if _, ok := fields[embeddedName]; !ok {
+						fields[embeddedName] = embeddedField
+					}
+				}
+				continue
+			}
+		}
+		if len(field.PkgPath) > 0 {
+			continue // Unexported
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field
+	}
+	return fields
+}
+
+// settingPath returns the dotted path of the named setting nested in the specified path.
+func settingPath(path string, name string) string {
+	if len(path) == 0 {
+		return name
+	}
+	return path + "." + name
+}
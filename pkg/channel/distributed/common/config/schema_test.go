@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Test The ParseEventingKafkaConfig() Functionality
+func TestParseEventingKafkaConfig(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name             string
+		value            string
+		expectedWarnings []string
+		errMsg           string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:  "Empty Settings",
+			value: "",
+		},
+		{
+			name: "Current Version",
+			value: `
+version: 2
+dispatcher:
+  cpuLimit: 500m
+  replicas: 2
+  retry:
+    jitter: true
+kafka:
+  adminType: kafka
+`,
+		},
+		{
+			name: "Unversioned Settings Migrated",
+			value: `
+dispatcher:
+  cpuLimit: 500m
+  replicas: 2
+  retryInitialIntervalMillis: 500
+  retryTimeMillis: 300000
+  retry:
+    jitter: true
+kafka:
+  adminType: kafka
+`,
+			expectedWarnings: []string{
+				"version 1 to 2 migration: removed dispatcher.retryInitialIntervalMillis, superseded by the delivery spec of the subscriptions & dispatcher.retry",
+				"version 1 to 2 migration: removed dispatcher.retryTimeMillis, superseded by the delivery spec of the subscriptions & dispatcher.retry",
+			},
+		},
+		{
+			name: "Legacy Settings Rejected In The Current Version",
+			value: `
+version: 2
+dispatcher:
+  retryExponentialBackoff: true
+`,
+			errMsg: "unknown eventing-kafka settings: dispatcher.retryExponentialBackoff",
+		},
+		{
+			name: "Unknown Settings Rejected With Their Paths",
+			value: `
+version: 2
+dispatcher:
+  cpuLimit: 500m
+  retry:
+    jiter: true
+receiver:
+  policy:
+    rules:
+    - name: own-sources
+      actoin: allow
+kafka:
+  provisionerConfig:
+    anything: goes
+unknown: true
+`,
+			errMsg: "unknown eventing-kafka settings: dispatcher.retry.jiter, receiver.policy.rules[0].actoin, unknown",
+		},
+		{
+			name:   "Newer Version",
+			value:  "version: 3",
+			errMsg: "unsupported eventing-kafka settings version 3: this release supports versions 1 to 2",
+		},
+		{
+			name:   "Invalid Version",
+			value:  "version: latest",
+			errMsg: "invalid eventing-kafka settings version latest: must be an integer",
+		},
+		{
+			name:   "Invalid YAML",
+			value:  "dispatcher: [invalid",
+			errMsg: "invalid eventing-kafka settings: error converting YAML to JSON: yaml: line 1: did not find expected ',' or ']'",
+		},
+		{
+			name:   "Invalid Value",
+			value:  "dispatcher:\n  replicas: foo",
+			errMsg: "invalid eventing-kafka settings: json: cannot unmarshal string into Go struct field EventingKafkaConfig.dispatcher.replicas of type int",
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			configuration, warnings, err := ParseEventingKafkaConfig(testCase.value)
+			assert.Equal(t, testCase.expectedWarnings, warnings)
+			if len(testCase.errMsg) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.errMsg, err.Error())
+				assert.Nil(t, configuration)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, EventingKafkaConfigVersion, configuration.Version)
+			}
+		})
+	}
+}
+
+// Test That ParseEventingKafkaConfig() Populates The Settings Like Unmarshalling (Embedded & Case Insensitive Names)
+func TestParseEventingKafkaConfigSettings(t *testing.T) {
+	configuration, warnings, err := ParseEventingKafkaConfig(`
+dispatcher:
+  CpuLimit: 500m
+  replicas: 2
+  retry:
+    jitter: true
+kafka:
+  adminType: kafka
+  topic:
+    defaultNumPartitions: 4
+`)
+	assert.Nil(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, resource.MustParse("500m"), configuration.Dispatcher.CpuLimit)
+	assert.Equal(t, 2, configuration.Dispatcher.Replicas)
+	assert.True(t, configuration.Dispatcher.Retry.Jitter)
+	assert.Equal(t, "kafka", configuration.Kafka.AdminType)
+	assert.Equal(t, int32(4), configuration.Kafka.Topic.DefaultNumPartitions)
+}
@@ -19,6 +19,10 @@ package constants
 // Constants
 const (
 
-	// Knative Eventing Namespace
+	// The Default Knative Eventing (System) Namespace (The Controller Uses The SYSTEM_NAMESPACE Environment Variable)
 	KnativeEventingNamespace = "knative-eventing"
+
+	// The Default Periods Of The Receiver's Graceful Shutdown (Also Used To Size The Receiver's Termination Grace Period)
+	DefaultReceiverDrainMillis   = 5000
+	DefaultReceiverTimeoutMillis = 20000
 )
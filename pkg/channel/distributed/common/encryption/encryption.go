@@ -0,0 +1,327 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/pkg/system"
+)
+
+// Kafka Headers Of The Records Encrypted By The Receiver
+const (
+	AlgorithmHeader  = "kn-encryption"        // The Algorithm Encrypting The Record's Value
+	KeyIdHeader      = "kn-encryption-key-id" // The ID Of The Key Encryption Key Wrapping The Data Key
+	WrappedKeyHeader = "kn-encryption-key"    // The Wrapped Data Key
+)
+
+// The Algorithm Of The Encrypted Records (The Value Is The Random Nonce Followed By The AES-256-GCM Ciphertext)
+const AlgorithmAES256GCM = "AES256GCM"
+
+// The Path At Which The Secret Of The Key Encryption Keys Is Mounted In The Receiver & Dispatcher
+const DefaultKeysPath = "/etc/eventing-kafka/encryption-keys"
+
+// Data Key Rotation & Caching
+const (
+	DataKeyLifetime   = 10 * time.Minute // A New Data Key Is Generated After Its Lifetime...
+	DataKeyMaxUses    = 1 << 24          // ...Or Its Maximum Number Of Records (Well Within The Limit Of Random GCM Nonces)
+	MaxCachedDataKeys = 1000             // The Maximum Number Of Unwrapped Data Keys Cached For Decryption
+)
+
+// The Error Returned When Decrypting A Record Without Encryption Enabled
+var ErrEncryptionDisabled = errors.New("the record is encrypted but encryption is not enabled")
+
+//
+// Envelope Encryption Of The Records Of The KafkaChannels
+//
+// The value of each record is encrypted with AES-256-GCM under a random data key, which is wrapped by the KMS with
+// the configured key encryption key and carried (with the ID of that key) in the record's headers, so that events
+// are never stored in plaintext in Kafka even when broker-side encryption is unavailable.  The CloudEvent attributes
+// of binary mode records remain in (plaintext) headers for routing & filtering.  Data keys are reused for up to
+// DataKeyLifetime or DataKeyMaxUses records, and unwrapped data keys are cached, so that the KMS isn't called for
+// each record.  A nil *Envelope is valid, leaving records unencrypted (and failing to decrypt encrypted ones).
+//
+type Envelope struct {
+	kms       KMS
+	keyId     string
+	dataKey   *dataKey
+	unwrapped map[string][]byte
+	lock      sync.Mutex
+	now       func() time.Time
+}
+
+// A Data Key Of The Envelope Encryption With Its Wrapped Form
+type dataKey struct {
+	key     []byte
+	wrapped []byte
+	created time.Time
+	uses    int
+}
+
+// Validate The Specified Encryption Config
+func ValidateEncryptionConfig(encryptionConfig config.EKEncryptionConfig) error {
+	if !encryptionConfig.Enabled {
+		return nil
+	}
+	if len(encryptionConfig.SecretName) == 0 {
+		return errors.New("encryption requires the secretName of the key encryption keys")
+	}
+	if len(encryptionConfig.KeyId) == 0 {
+		return errors.New("encryption requires the keyId of the key encryption key")
+	}
+	return nil
+}
+
+// Envelope Constructor - Returns nil If Encryption Is Not Enabled (Assumes A Valid Config), Otherwise Reads The Key
+// Encryption Keys Mounted At The DefaultKeysPath (Returning An Error If The Configured Key Is Not Among Them)
+func NewEnvelope(encryptionConfig config.EKEncryptionConfig) (*Envelope, error) {
+	if !encryptionConfig.Enabled {
+		return nil, nil
+	}
+	kms, err := NewLocalKMS(DefaultKeysPath)
+	if err != nil {
+		return nil, err
+	}
+	if !kms.HasKey(encryptionConfig.KeyId) {
+		return nil, fmt.Errorf("%w: keyId %s is not a key of secret %s", ErrKeyNotFound, encryptionConfig.KeyId, encryptionConfig.SecretName)
+	}
+	return NewKMSEnvelope(kms, encryptionConfig.KeyId), nil
+}
+
+// Envelope Constructor For Components Which Don't Mount The Key Encryption Keys (e.g. The Controller) - Returns nil If
+// Encryption Is Not Enabled (Assumes A Valid Config), Otherwise Reads The Keys Of The Configured Secret In The System
+// Namespace Via The API (Returning An Error If The Configured Key Is Not Among Them)
+func NewSecretEnvelope(ctx context.Context, kubeClient kubernetes.Interface, encryptionConfig config.EKEncryptionConfig) (*Envelope, error) {
+	if !encryptionConfig.Enabled {
+		return nil, nil
+	}
+	secret, err := kubeClient.CoreV1().Secrets(system.Namespace()).Get(ctx, encryptionConfig.SecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the key encryption keys secret %s: %w", encryptionConfig.SecretName, err)
+	}
+	kms, err := NewSecretKMS(secret)
+	if err != nil {
+		return nil, err
+	}
+	if !kms.HasKey(encryptionConfig.KeyId) {
+		return nil, fmt.Errorf("%w: keyId %s is not a key of secret %s", ErrKeyNotFound, encryptionConfig.KeyId, encryptionConfig.SecretName)
+	}
+	return NewKMSEnvelope(kms, encryptionConfig.KeyId), nil
+}
+
+// Create An Envelope Wrapping Its Data Keys With The Specified Key Encryption Key Of The KMS
+func NewKMSEnvelope(kms KMS, keyId string) *Envelope {
+	return &Envelope{
+		kms:       kms,
+		keyId:     keyId,
+		unwrapped: make(map[string][]byte),
+		now:       time.Now,
+	}
+}
+
+// The ProducerMessage Metadata Marking A Record Re-Produced Verbatim (Never Written To Kafka)
+type verbatimMetadata struct{}
+
+// Mark The Specified ProducerMessage As Re-Producing An Encrypted Record Verbatim (e.g. A Poison Pill Which Could Not Be
+// Decrypted Being Quarantined), So That Encrypt() Leaves It As-Is Rather Than Encrypting It Again
+func MarkVerbatim(producerMessage *sarama.ProducerMessage) {
+	producerMessage.Metadata = verbatimMetadata{}
+}
+
+// Determine Whether The Specified ProducerMessage Was Marked As Re-Producing An Encrypted Record Verbatim
+func IsVerbatim(producerMessage *sarama.ProducerMessage) bool {
+	_, ok := producerMessage.Metadata.(verbatimMetadata)
+	return ok
+}
+
+// Determine Whether The Specified Header Is Reserved For The Envelope Encryption (kn-encryption*)
+func IsReservedHeader(key string) bool {
+	return strings.HasPrefix(strings.ToLower(key), AlgorithmHeader)
+}
+
+// Remove Any Headers Reserved For The Envelope Encryption, e.g. Propagated From The Extensions Of Inbound Events Which
+// Would Otherwise Make Plaintext Records Appear Encrypted (Or Leave Records Unencrypted)
+func StripReservedHeaders(headers []sarama.RecordHeader) []sarama.RecordHeader {
+	strippedHeaders := headers[:0]
+	for _, header := range headers {
+		if !IsReservedHeader(string(header.Key)) {
+			strippedHeaders = append(strippedHeaders, header)
+		}
+	}
+	return strippedHeaders
+}
+
+// Encrypt The Value Of The Specified ProducerMessage In Place, Replacing Any Reserved Headers With The Encryption
+// Headers (Records Marked As Verbatim, e.g. Poison Pills Quarantined Without Being Decrypted, Or Without A Value Are
+// Left As-Is)
+func (e *Envelope) Encrypt(ctx context.Context, producerMessage *sarama.ProducerMessage) error {
+	if e == nil || producerMessage.Value == nil || IsVerbatim(producerMessage) {
+		return nil
+	}
+	producerMessage.Headers = StripReservedHeaders(producerMessage.Headers)
+	value, err := producerMessage.Value.Encode()
+	if err != nil {
+		return err
+	}
+	key, err := e.currentDataKey(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get data key: %w", err)
+	}
+	ciphertext, err := seal(key.key, value, nil)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt record: %w", err)
+	}
+	producerMessage.Value = sarama.ByteEncoder(ciphertext)
+	producerMessage.Headers = append(producerMessage.Headers,
+		sarama.RecordHeader{Key: []byte(AlgorithmHeader), Value: []byte(AlgorithmAES256GCM)},
+		sarama.RecordHeader{Key: []byte(KeyIdHeader), Value: []byte(e.keyId)},
+		sarama.RecordHeader{Key: []byte(WrappedKeyHeader), Value: key.wrapped})
+	return nil
+}
+
+// Decrypt The Specified ConsumerMessage, Returning A Copy With The Plaintext Value & Without The Encryption Headers
+// (Records Which Are Not Encrypted Are Returned As-Is)
+func (e *Envelope) Decrypt(ctx context.Context, consumerMessage *sarama.ConsumerMessage) (*sarama.ConsumerMessage, error) {
+
+	// Find The Encryption Headers (Records Without Them Aren't Encrypted)
+	var algorithm, keyId string
+	var wrappedKey []byte
+	headers := make([]*sarama.RecordHeader, 0, len(consumerMessage.Headers))
+	for _, header := range consumerMessage.Headers {
+		if header == nil {
+			continue
+		}
+		switch string(header.Key) {
+		case AlgorithmHeader:
+			algorithm = string(header.Value)
+		case KeyIdHeader:
+			keyId = string(header.Value)
+		case WrappedKeyHeader:
+			wrappedKey = header.Value
+		default:
+			headers = append(headers, header)
+		}
+	}
+	if len(algorithm) == 0 {
+		return consumerMessage, nil
+	}
+
+	// Decrypt The Value With The Unwrapped Data Key
+	if e == nil {
+		return nil, ErrEncryptionDisabled
+	}
+	if algorithm != AlgorithmAES256GCM {
+		return nil, fmt.Errorf("the record's encryption algorithm %s is not supported", algorithm)
+	}
+	key, err := e.unwrapDataKey(ctx, keyId, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(key, consumerMessage.Value, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the record: %w", err)
+	}
+	decryptedMessage := *consumerMessage
+	decryptedMessage.Value = plaintext
+	decryptedMessage.Headers = headers
+	return &decryptedMessage, nil
+}
+
+// Wrap The Specified SyncProducer To Encrypt The Records It Sends (Returns It As-Is If nil)
+func (e *Envelope) SyncProducer(producer sarama.SyncProducer) sarama.SyncProducer {
+	if e == nil || producer == nil {
+		return producer
+	}
+	return &encryptingSyncProducer{SyncProducer: producer, envelope: e}
+}
+
+// Get The Current Data Key, Generating & Wrapping A New One When It Expires
+func (e *Envelope) currentDataKey(ctx context.Context) (*dataKey, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	now := e.now()
+	if e.dataKey == nil || now.Sub(e.dataKey.created) >= DataKeyLifetime || e.dataKey.uses >= DataKeyMaxUses {
+		key := make([]byte, KeySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+		wrapped, err := e.kms.WrapKey(ctx, e.keyId, key)
+		if err != nil {
+			return nil, err
+		}
+		e.dataKey = &dataKey{key: key, wrapped: wrapped, created: now}
+	}
+	e.dataKey.uses++
+	return e.dataKey, nil
+}
+
+// Unwrap The Specified Data Key With The KMS (Cached)
+func (e *Envelope) unwrapDataKey(ctx context.Context, keyId string, wrappedKey []byte) ([]byte, error) {
+	cacheKey := keyId + "/" + string(wrappedKey)
+	e.lock.Lock()
+	key, ok := e.unwrapped[cacheKey]
+	e.lock.Unlock()
+	if ok {
+		return key, nil
+	}
+	key, err := e.kms.UnwrapKey(ctx, keyId, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	e.lock.Lock()
+	if len(e.unwrapped) >= MaxCachedDataKeys {
+		e.unwrapped = make(map[string][]byte)
+	}
+	e.unwrapped[cacheKey] = key
+	e.lock.Unlock()
+	return key, nil
+}
+
+// A SyncProducer Encrypting The Records It Sends
+type encryptingSyncProducer struct {
+	sarama.SyncProducer
+	envelope *Envelope
+}
+
+// Encrypt & Send The Specified Record
+func (p *encryptingSyncProducer) SendMessage(producerMessage *sarama.ProducerMessage) (int32, int64, error) {
+	if err := p.envelope.Encrypt(context.Background(), producerMessage); err != nil {
+		return -1, -1, err
+	}
+	return p.SyncProducer.SendMessage(producerMessage)
+}
+
+// Encrypt & Send The Specified Records
+func (p *encryptingSyncProducer) SendMessages(producerMessages []*sarama.ProducerMessage) error {
+	for _, producerMessage := range producerMessages {
+		if err := p.envelope.Encrypt(context.Background(), producerMessage); err != nil {
+			return err
+		}
+	}
+	return p.SyncProducer.SendMessages(producerMessages)
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/pkg/system"
+)
+
+// Utility Function For Creating A Test Envelope Of The Specified Key
+func newTestEnvelope(t *testing.T, keyId string) *Envelope {
+	kms, err := NewLocalKMS(writeKeys(t, map[string][]byte{"key1": testKey(1), "key2": testKey(2)}))
+	require.Nil(t, err)
+	return NewKMSEnvelope(kms, keyId)
+}
+
+// Utility Function For Converting A Sent ProducerMessage To The ConsumerMessage Of The Record
+func toConsumerMessage(t *testing.T, producerMessage *sarama.ProducerMessage) *sarama.ConsumerMessage {
+	value, err := producerMessage.Value.Encode()
+	require.Nil(t, err)
+	headers := make([]*sarama.RecordHeader, len(producerMessage.Headers))
+	for i := range producerMessage.Headers {
+		headers[i] = &producerMessage.Headers[i]
+	}
+	return &sarama.ConsumerMessage{Topic: producerMessage.Topic, Value: value, Headers: headers}
+}
+
+// Test The ValidateEncryptionConfig() Functionality
+func TestValidateEncryptionConfig(t *testing.T) {
+	assert.Nil(t, ValidateEncryptionConfig(config.EKEncryptionConfig{}))
+	assert.Nil(t, ValidateEncryptionConfig(config.EKEncryptionConfig{Enabled: true, SecretName: "keys", KeyId: "key1"}))
+	assert.NotNil(t, ValidateEncryptionConfig(config.EKEncryptionConfig{Enabled: true, KeyId: "key1"}))
+	assert.NotNil(t, ValidateEncryptionConfig(config.EKEncryptionConfig{Enabled: true, SecretName: "keys"}))
+}
+
+// Test The NewEnvelope() Functionality When Not Enabled
+func TestNewEnvelopeDisabled(t *testing.T) {
+	envelope, err := NewEnvelope(config.EKEncryptionConfig{SecretName: "keys", KeyId: "key1"})
+	assert.Nil(t, err)
+	assert.Nil(t, envelope)
+}
+
+// Test The NewSecretEnvelope() Functionality
+func TestNewSecretEnvelope(t *testing.T) {
+	ctx := context.TODO()
+	require.Nil(t, os.Setenv(system.NamespaceEnvKey, "knative-eventing"))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "knative-eventing"},
+		Data:       map[string][]byte{"key1": testKey(1), "key2": testKey(2)},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+
+	// Not Enabled
+	envelope, err := NewSecretEnvelope(ctx, kubeClient, config.EKEncryptionConfig{SecretName: "keys", KeyId: "key1"})
+	assert.Nil(t, err)
+	assert.Nil(t, envelope)
+
+	// Missing Secret & Key
+	_, err = NewSecretEnvelope(ctx, kubeClient, config.EKEncryptionConfig{Enabled: true, SecretName: "missing", KeyId: "key1"})
+	assert.NotNil(t, err)
+	_, err = NewSecretEnvelope(ctx, kubeClient, config.EKEncryptionConfig{Enabled: true, SecretName: "keys", KeyId: "missing"})
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+
+	// Records Encrypted By The Envelope Of The Mounted Keys Are Decrypted (& Vice Versa)
+	envelope, err = NewSecretEnvelope(ctx, kubeClient, config.EKEncryptionConfig{Enabled: true, SecretName: "keys", KeyId: "key1"})
+	require.Nil(t, err)
+	producerMessage := &sarama.ProducerMessage{Topic: "topic", Value: sarama.StringEncoder("plaintext")}
+	require.Nil(t, newTestEnvelope(t, "key2").Encrypt(ctx, producerMessage))
+	decryptedMessage, err := envelope.Decrypt(ctx, toConsumerMessage(t, producerMessage))
+	require.Nil(t, err)
+	assert.Equal(t, "plaintext", string(decryptedMessage.Value))
+	producerMessage = &sarama.ProducerMessage{Topic: "topic", Value: sarama.StringEncoder("plaintext")}
+	require.Nil(t, envelope.Encrypt(ctx, producerMessage))
+	decryptedMessage, err = newTestEnvelope(t, "key1").Decrypt(ctx, toConsumerMessage(t, producerMessage))
+	require.Nil(t, err)
+	assert.Equal(t, "plaintext", string(decryptedMessage.Value))
+}
+
+// Test A Nil Envelope Leaves Records Unencrypted
+func TestNilEnvelope(t *testing.T) {
+	var envelope *Envelope
+	producerMessage := &sarama.ProducerMessage{Value: sarama.StringEncoder("plaintext")}
+	assert.Nil(t, envelope.Encrypt(context.TODO(), producerMessage))
+	assert.Equal(t, sarama.StringEncoder("plaintext"), producerMessage.Value)
+	assert.Empty(t, producerMessage.Headers)
+
+	consumerMessage := &sarama.ConsumerMessage{Value: []byte("plaintext")}
+	decryptedMessage, err := envelope.Decrypt(context.TODO(), consumerMessage)
+	assert.Nil(t, err)
+	assert.Same(t, consumerMessage, decryptedMessage)
+
+	producer := &recordingSyncProducer{}
+	assert.Same(t, producer, envelope.SyncProducer(producer))
+}
+
+// Test The Envelope Encrypts & Decrypts Records
+func TestEnvelopeEncryptDecrypt(t *testing.T) {
+	ctx := context.TODO()
+	envelope := newTestEnvelope(t, "key1")
+
+	producerMessage := &sarama.ProducerMessage{
+		Topic:   "topic",
+		Value:   sarama.StringEncoder("plaintext"),
+		Headers: []sarama.RecordHeader{{Key: []byte("ce_type"), Value: []byte("type")}},
+	}
+	require.Nil(t, envelope.Encrypt(ctx, producerMessage))
+	ciphertext, err := producerMessage.Value.Encode()
+	require.Nil(t, err)
+	assert.NotContains(t, string(ciphertext), "plaintext")
+	require.Len(t, producerMessage.Headers, 4)
+	assert.Equal(t, "ce_type", string(producerMessage.Headers[0].Key))
+	assert.Equal(t, AlgorithmHeader, string(producerMessage.Headers[1].Key))
+	assert.Equal(t, AlgorithmAES256GCM, string(producerMessage.Headers[1].Value))
+	assert.Equal(t, KeyIdHeader, string(producerMessage.Headers[2].Key))
+	assert.Equal(t, "key1", string(producerMessage.Headers[2].Value))
+	assert.Equal(t, WrappedKeyHeader, string(producerMessage.Headers[3].Key))
+
+	// Records Marked As Re-Produced Verbatim Are Not Encrypted Again
+	verbatimMessage := &sarama.ProducerMessage{Topic: "topic", Value: producerMessage.Value, Headers: producerMessage.Headers}
+	MarkVerbatim(verbatimMessage)
+	require.Nil(t, envelope.Encrypt(ctx, verbatimMessage))
+	assert.Equal(t, producerMessage.Value, verbatimMessage.Value)
+	assert.Len(t, verbatimMessage.Headers, 4)
+
+	// Decryption Restores The Value & Removes The Encryption Headers
+	consumerMessage := toConsumerMessage(t, producerMessage)
+	decryptedMessage, err := envelope.Decrypt(ctx, consumerMessage)
+	require.Nil(t, err)
+	assert.Equal(t, "plaintext", string(decryptedMessage.Value))
+	assert.Equal(t, "topic", decryptedMessage.Topic)
+	require.Len(t, decryptedMessage.Headers, 1)
+	assert.Equal(t, "ce_type", string(decryptedMessage.Headers[0].Key))
+	assert.Equal(t, ciphertext, consumerMessage.Value)
+
+	// Envelopes Of Other Key Encryption Keys (With The Same KMS Keys) Decrypt The Record
+	decryptedMessage, err = newTestEnvelope(t, "key2").Decrypt(ctx, consumerMessage)
+	require.Nil(t, err)
+	assert.Equal(t, "plaintext", string(decryptedMessage.Value))
+
+	// A Nil Envelope Cannot Decrypt The Record
+	var nilEnvelope *Envelope
+	_, err = nilEnvelope.Decrypt(ctx, consumerMessage)
+	assert.True(t, errors.Is(err, ErrEncryptionDisabled))
+
+	// Tampered Records Fail To Decrypt
+	consumerMessage.Value[len(consumerMessage.Value)-1] ^= 0xff
+	_, err = envelope.Decrypt(ctx, consumerMessage)
+	assert.NotNil(t, err)
+}
+
+// Test The Envelope Rejects Unknown Algorithms & Keys
+func TestEnvelopeDecryptInvalid(t *testing.T) {
+	ctx := context.TODO()
+	envelope := newTestEnvelope(t, "key1")
+
+	_, err := envelope.Decrypt(ctx, &sarama.ConsumerMessage{Headers: []*sarama.RecordHeader{
+		{Key: []byte(AlgorithmHeader), Value: []byte("ROT13")},
+	}})
+	assert.NotNil(t, err)
+
+	_, err = envelope.Decrypt(ctx, &sarama.ConsumerMessage{Headers: []*sarama.RecordHeader{
+		{Key: []byte(AlgorithmHeader), Value: []byte(AlgorithmAES256GCM)},
+		{Key: []byte(KeyIdHeader), Value: []byte("missing")},
+		{Key: []byte(WrappedKeyHeader), Value: []byte("wrapped")},
+	}})
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+}
+
+// Test The Envelope Encrypts Records Carrying Spoofed Encryption Headers (e.g. Propagated From Inbound Extensions)
+func TestEnvelopeEncryptReservedHeaders(t *testing.T) {
+	ctx := context.TODO()
+	envelope := newTestEnvelope(t, "key1")
+
+	producerMessage := &sarama.ProducerMessage{
+		Topic: "topic",
+		Value: sarama.StringEncoder("plaintext"),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte("ce_type"), Value: []byte("type")},
+			{Key: []byte(AlgorithmHeader), Value: []byte(AlgorithmAES256GCM)},
+			{Key: []byte("KN-ENCRYPTION-KEY-ID"), Value: []byte("spoofed")},
+		},
+	}
+	require.Nil(t, envelope.Encrypt(ctx, producerMessage))
+	assert.False(t, IsVerbatim(producerMessage))
+	ciphertext, err := producerMessage.Value.Encode()
+	require.Nil(t, err)
+	assert.NotContains(t, string(ciphertext), "plaintext")
+	require.Len(t, producerMessage.Headers, 4)
+	assert.Equal(t, "ce_type", string(producerMessage.Headers[0].Key))
+	assert.Equal(t, KeyIdHeader, string(producerMessage.Headers[2].Key))
+	assert.Equal(t, "key1", string(producerMessage.Headers[2].Value))
+
+	decryptedMessage, err := envelope.Decrypt(ctx, toConsumerMessage(t, producerMessage))
+	require.Nil(t, err)
+	assert.Equal(t, "plaintext", string(decryptedMessage.Value))
+}
+
+// Test The Removal Of The Headers Reserved For The Envelope Encryption
+func TestStripReservedHeaders(t *testing.T) {
+	headers := StripReservedHeaders([]sarama.RecordHeader{
+		{Key: []byte("ce_type"), Value: []byte("type")},
+		{Key: []byte(AlgorithmHeader), Value: []byte(AlgorithmAES256GCM)},
+		{Key: []byte(KeyIdHeader), Value: []byte("key1")},
+		{Key: []byte("Kn-Encryption-Key"), Value: []byte("wrapped")},
+		{Key: []byte("kn-other"), Value: []byte("other")},
+	})
+	require.Len(t, headers, 2)
+	assert.Equal(t, "ce_type", string(headers[0].Key))
+	assert.Equal(t, "kn-other", string(headers[1].Key))
+}
+
+// Test The Envelope Rotates Its Data Keys
+func TestEnvelopeDataKeyRotation(t *testing.T) {
+	ctx := context.TODO()
+	envelope := newTestEnvelope(t, "key1")
+	now := time.Now()
+	envelope.now = func() time.Time { return now }
+
+	firstKey, err := envelope.currentDataKey(ctx)
+	require.Nil(t, err)
+	secondKey, err := envelope.currentDataKey(ctx)
+	require.Nil(t, err)
+	assert.Same(t, firstKey, secondKey)
+	assert.Equal(t, 2, secondKey.uses)
+
+	// Expired Data Keys Are Replaced
+	now = now.Add(DataKeyLifetime)
+	thirdKey, err := envelope.currentDataKey(ctx)
+	require.Nil(t, err)
+	assert.NotSame(t, firstKey, thirdKey)
+	assert.NotEqual(t, firstKey.key, thirdKey.key)
+
+	// Exhausted Data Keys Are Replaced
+	thirdKey.uses = DataKeyMaxUses
+	fourthKey, err := envelope.currentDataKey(ctx)
+	require.Nil(t, err)
+	assert.NotSame(t, thirdKey, fourthKey)
+}
+
+// Test The Envelope's SyncProducer Encrypts The Records It Sends
+func TestEnvelopeSyncProducer(t *testing.T) {
+	envelope := newTestEnvelope(t, "key1")
+	recordingProducer := &recordingSyncProducer{}
+	producer := envelope.SyncProducer(recordingProducer)
+
+	_, _, err := producer.SendMessage(&sarama.ProducerMessage{Value: sarama.StringEncoder("first")})
+	require.Nil(t, err)
+	require.Nil(t, producer.SendMessages([]*sarama.ProducerMessage{{Value: sarama.StringEncoder("second")}}))
+	require.Len(t, recordingProducer.messages, 2)
+
+	for i, expected := range []string{"first", "second"} {
+		decryptedMessage, err := envelope.Decrypt(context.TODO(), toConsumerMessage(t, recordingProducer.messages[i]))
+		require.Nil(t, err)
+		assert.Equal(t, expected, string(decryptedMessage.Value))
+	}
+}
+
+// A SyncProducer Recording The Records It Sends
+type recordingSyncProducer struct {
+	sarama.SyncProducer
+	messages []*sarama.ProducerMessage
+}
+
+func (p *recordingSyncProducer) SendMessage(producerMessage *sarama.ProducerMessage) (int32, int64, error) {
+	p.messages = append(p.messages, producerMessage)
+	return 0, int64(len(p.messages)), nil
+}
+
+func (p *recordingSyncProducer) SendMessages(producerMessages []*sarama.ProducerMessage) error {
+	p.messages = append(p.messages, producerMessages...)
+	return nil
+}
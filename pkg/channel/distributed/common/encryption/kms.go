@@ -0,0 +1,272 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The Size (In Bytes) Of The AES-256 Data & Key Encryption Keys
+const KeySize = 32
+
+// The Minimum Interval Between Re-Reading The Keys Of A LocalKMS For Unknown Key IDs
+const KeyRefreshInterval = 10 * time.Second
+
+// The Error Returned When A Key Encryption Key Is Not Found In The KMS
+var ErrKeyNotFound = errors.New("key encryption key not found")
+
+//
+// Key Management Service (KMS) Wrapping & Unwrapping The Data Keys Of The Envelope Encryption
+//
+// The data keys encrypting the records are never stored in plaintext, but wrapped (encrypted) by a key encryption key
+// identified by its ID.  Implementations may delegate to an external KMS (e.g. a cloud provider's KMS or Vault's
+// transit secrets engine) so that the key encryption keys never leave it.
+//
+type KMS interface {
+	WrapKey(ctx context.Context, keyId string, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, keyId string, wrappedKey []byte) ([]byte, error)
+}
+
+// Verify The LocalKMS Implements The KMS Interface
+var _ KMS = &LocalKMS{}
+
+//
+// KMS Of Local Key Encryption Keys Read From A Directory (e.g. A Mounted Secret)
+//
+// Each file of the directory is a key encryption key named by its ID, whose content is either the 32 bytes of an
+// AES-256 key or their base64 encoding.  Data keys are wrapped with AES-256-GCM (bound to the key ID).  Keys are
+// re-read when an unknown key ID is requested (at most every KeyRefreshInterval), so that keys added to the mounted
+// Secret are used without a restart.
+//
+type LocalKMS struct {
+	directory string
+	keys      map[string][]byte
+	readTime  time.Time
+	lock      sync.RWMutex
+}
+
+// LocalKMS Constructor - Reads The Key Encryption Keys Of The Specified Directory
+func NewLocalKMS(directory string) (*LocalKMS, error) {
+	kms := &LocalKMS{directory: directory}
+	if err := kms.readKeys(); err != nil {
+		return nil, err
+	}
+	return kms, nil
+}
+
+// Determine Whether The KMS Has The Specified Key Encryption Key
+func (k *LocalKMS) HasKey(keyId string) bool {
+	_, err := k.key(keyId)
+	return err == nil
+}
+
+// Wrap The Specified Data Key With The Specified Key Encryption Key
+func (k *LocalKMS) WrapKey(_ context.Context, keyId string, dataKey []byte) ([]byte, error) {
+	key, err := k.key(keyId)
+	if err != nil {
+		return nil, err
+	}
+	return wrapKey(key, keyId, dataKey)
+}
+
+// Unwrap The Specified Wrapped Data Key With The Specified Key Encryption Key
+func (k *LocalKMS) UnwrapKey(_ context.Context, keyId string, wrappedKey []byte) ([]byte, error) {
+	key, err := k.key(keyId)
+	if err != nil {
+		return nil, err
+	}
+	return unwrapKey(key, keyId, wrappedKey)
+}
+
+// Get The Specified Key Encryption Key (Re-Reading The Keys If Not Found)
+func (k *LocalKMS) key(keyId string) ([]byte, error) {
+	k.lock.RLock()
+	key, ok := k.keys[keyId]
+	stale := time.Since(k.readTime) >= KeyRefreshInterval
+	k.lock.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if stale {
+		if err := k.readKeys(); err != nil {
+			return nil, err
+		}
+	}
+	k.lock.RLock()
+	key, ok = k.keys[keyId]
+	k.lock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyId)
+	}
+	return key, nil
+}
+
+// Read The Key Encryption Keys Of The Directory (Ignoring The Hidden Files & Directories Of Mounted Secrets)
+func (k *LocalKMS) readKeys() error {
+	files, err := ioutil.ReadDir(k.directory)
+	if err != nil {
+		return fmt.Errorf("failed to read key encryption keys from %s: %w", k.directory, err)
+	}
+	keys := make(map[string][]byte, len(files))
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), ".") || file.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(k.directory, file.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read key encryption key %s: %w", file.Name(), err)
+		}
+		key, err := parseKey(content)
+		if err != nil {
+			return fmt.Errorf("key encryption key %s is invalid: %w", file.Name(), err)
+		}
+		keys[file.Name()] = key
+	}
+	k.lock.Lock()
+	k.keys = keys
+	k.readTime = time.Now()
+	k.lock.Unlock()
+	return nil
+}
+
+// Verify The SecretKMS Implements The KMS Interface
+var _ KMS = &SecretKMS{}
+
+//
+// KMS Of The Key Encryption Keys Of A Kubernetes Secret Read Via The API
+//
+// Used by components which don't mount the Secret (e.g. the controller re-encrypting redelivered records), with the
+// same format as the LocalKMS (each key of the Secret's data is a key encryption key named by its ID).  The keys are
+// only read once, so a SecretKMS should be created for each (short-lived) use.
+//
+type SecretKMS struct {
+	keys map[string][]byte
+}
+
+// SecretKMS Constructor - Parses The Key Encryption Keys Of The Specified Secret
+func NewSecretKMS(secret *corev1.Secret) (*SecretKMS, error) {
+	keys := make(map[string][]byte, len(secret.Data))
+	for keyId, content := range secret.Data {
+		key, err := parseKey(content)
+		if err != nil {
+			return nil, fmt.Errorf("key encryption key %s is invalid: %w", keyId, err)
+		}
+		keys[keyId] = key
+	}
+	return &SecretKMS{keys: keys}, nil
+}
+
+// Determine Whether The KMS Has The Specified Key Encryption Key
+func (k *SecretKMS) HasKey(keyId string) bool {
+	_, ok := k.keys[keyId]
+	return ok
+}
+
+// Wrap The Specified Data Key With The Specified Key Encryption Key
+func (k *SecretKMS) WrapKey(_ context.Context, keyId string, dataKey []byte) ([]byte, error) {
+	key, ok := k.keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyId)
+	}
+	return wrapKey(key, keyId, dataKey)
+}
+
+// Unwrap The Specified Wrapped Data Key With The Specified Key Encryption Key
+func (k *SecretKMS) UnwrapKey(_ context.Context, keyId string, wrappedKey []byte) ([]byte, error) {
+	key, ok := k.keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyId)
+	}
+	return unwrapKey(key, keyId, wrappedKey)
+}
+
+// Utility Function For Wrapping A Data Key With AES-256-GCM (Bound To The ID Of The Key Encryption Key)
+func wrapKey(key []byte, keyId string, dataKey []byte) ([]byte, error) {
+	return seal(key, dataKey, []byte(keyId))
+}
+
+// Utility Function For Unwrapping A Data Key Wrapped By wrapKey()
+func unwrapKey(key []byte, keyId string, wrappedKey []byte) ([]byte, error) {
+	dataKey, err := open(key, wrappedKey, []byte(keyId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with key %s: %w", keyId, err)
+	}
+	return dataKey, nil
+}
+
+// Utility Function For Parsing A Raw Or Base64 Encoded AES-256 Key
+func parseKey(content []byte) ([]byte, error) {
+	if len(content) == KeySize {
+		return content, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("key must be %d bytes or their base64 encoding", KeySize)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, not %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// Utility Function For Encrypting The Plaintext With AES-GCM (Returning The Random Nonce Followed By The Ciphertext)
+func seal(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Utility Function For Decrypting The Nonce & Ciphertext Of seal()
+func open(key []byte, sealed []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is shorter than the nonce")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
+
+// Utility Function For Creating An AES-GCM AEAD Of The Specified Key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
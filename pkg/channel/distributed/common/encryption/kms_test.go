@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Utility Function For Writing Key Encryption Keys To A Temporary Directory
+func writeKeys(t *testing.T, keys map[string][]byte) string {
+	directory, err := ioutil.TempDir("", "encryption-keys")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(directory) })
+	for name, content := range keys {
+		require.Nil(t, ioutil.WriteFile(filepath.Join(directory, name), content, 0600))
+	}
+	return directory
+}
+
+// Utility Function For Creating A Test Key
+func testKey(b byte) []byte {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
+
+// Test The NewLocalKMS() Functionality
+func TestNewLocalKMS(t *testing.T) {
+
+	// Raw, Base64 & Hidden Keys Are Read (Hidden Files Are Ignored)
+	directory := writeKeys(t, map[string][]byte{
+		"raw":     testKey(1),
+		"encoded": []byte(base64.StdEncoding.EncodeToString(testKey(2)) + "\n"),
+		".hidden": []byte("not a key"),
+	})
+	require.Nil(t, os.Mkdir(filepath.Join(directory, "..data"), 0700))
+	kms, err := NewLocalKMS(directory)
+	require.Nil(t, err)
+	assert.True(t, kms.HasKey("raw"))
+	assert.True(t, kms.HasKey("encoded"))
+	assert.False(t, kms.HasKey(".hidden"))
+	assert.False(t, kms.HasKey("missing"))
+
+	// Invalid Keys & Directories Are Errors
+	_, err = NewLocalKMS(writeKeys(t, map[string][]byte{"short": []byte(base64.StdEncoding.EncodeToString([]byte("short")))}))
+	assert.NotNil(t, err)
+	_, err = NewLocalKMS(filepath.Join(directory, "missing"))
+	assert.NotNil(t, err)
+}
+
+// Test The LocalKMS Wraps & Unwraps Data Keys
+func TestLocalKMSWrapUnwrap(t *testing.T) {
+	ctx := context.TODO()
+	kms, err := NewLocalKMS(writeKeys(t, map[string][]byte{"key1": testKey(1), "key2": testKey(2)}))
+	require.Nil(t, err)
+	dataKey := testKey(9)
+
+	wrappedKey, err := kms.WrapKey(ctx, "key1", dataKey)
+	require.Nil(t, err)
+	assert.NotEqual(t, dataKey, wrappedKey)
+
+	unwrappedKey, err := kms.UnwrapKey(ctx, "key1", wrappedKey)
+	require.Nil(t, err)
+	assert.Equal(t, dataKey, unwrappedKey)
+
+	// The Wrapped Key Is Bound To Its Key Encryption Key
+	_, err = kms.UnwrapKey(ctx, "key2", wrappedKey)
+	assert.NotNil(t, err)
+
+	// Unknown Keys Are Not Found
+	_, err = kms.WrapKey(ctx, "missing", dataKey)
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	_, err = kms.UnwrapKey(ctx, "missing", wrappedKey)
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+}
+
+// Test The LocalKMS Re-Reads Rotated Keys
+func TestLocalKMSKeyRotation(t *testing.T) {
+	directory := writeKeys(t, map[string][]byte{"key1": testKey(1)})
+	kms, err := NewLocalKMS(directory)
+	require.Nil(t, err)
+	assert.False(t, kms.HasKey("key2"))
+
+	require.Nil(t, ioutil.WriteFile(filepath.Join(directory, "key2"), testKey(2), 0600))
+	kms.readTime = kms.readTime.Add(-KeyRefreshInterval)
+	_, err = kms.WrapKey(context.TODO(), "key2", testKey(9))
+	assert.Nil(t, err)
+	assert.True(t, kms.HasKey("key2"))
+}
+
+// Test The SecretKMS Wraps & Unwraps Data Keys Interchangeably With The LocalKMS Of The Same Keys
+func TestSecretKMSWrapUnwrap(t *testing.T) {
+	ctx := context.TODO()
+	keys := map[string][]byte{"key1": testKey(1), "key2": []byte(base64.StdEncoding.EncodeToString(testKey(2)))}
+	kms, err := NewSecretKMS(&corev1.Secret{Data: keys})
+	require.Nil(t, err)
+	localKMS, err := NewLocalKMS(writeKeys(t, keys))
+	require.Nil(t, err)
+	assert.True(t, kms.HasKey("key1"))
+	assert.True(t, kms.HasKey("key2"))
+	assert.False(t, kms.HasKey("missing"))
+	dataKey := testKey(9)
+
+	wrappedKey, err := kms.WrapKey(ctx, "key2", dataKey)
+	require.Nil(t, err)
+	unwrappedKey, err := localKMS.UnwrapKey(ctx, "key2", wrappedKey)
+	require.Nil(t, err)
+	assert.Equal(t, dataKey, unwrappedKey)
+
+	wrappedKey, err = localKMS.WrapKey(ctx, "key1", dataKey)
+	require.Nil(t, err)
+	unwrappedKey, err = kms.UnwrapKey(ctx, "key1", wrappedKey)
+	require.Nil(t, err)
+	assert.Equal(t, dataKey, unwrappedKey)
+
+	// Unknown & Invalid Keys
+	_, err = kms.WrapKey(ctx, "missing", dataKey)
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	_, err = kms.UnwrapKey(ctx, "missing", wrappedKey)
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	_, err = NewSecretKMS(&corev1.Secret{Data: map[string][]byte{"short": []byte("short")}})
+	assert.NotNil(t, err)
+}
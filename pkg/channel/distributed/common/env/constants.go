@@ -32,6 +32,18 @@ const (
 	KafkaUsernameEnvVarKey = "KAFKA_USERNAME"
 	KafkaPasswordEnvVarKey = "KAFKA_PASSWORD"
 
+	// Remote Kafka Cluster Of The KafkaChannel's Replication
+	ReplicationKafkaBrokerEnvVarKey   = "REPLICATION_KAFKA_BROKERS"
+	ReplicationKafkaUsernameEnvVarKey = "REPLICATION_KAFKA_USERNAME"
+	ReplicationKafkaPasswordEnvVarKey = "REPLICATION_KAFKA_PASSWORD"
+
+	// Schema Registry Authorization
+	SchemaRegistryUsernameEnvVarKey     = "SCHEMA_REGISTRY_USERNAME"
+	SchemaRegistryPasswordEnvVarKey     = "SCHEMA_REGISTRY_PASSWORD"
+	SchemaRegistryTokenEnvVarKey        = "SCHEMA_REGISTRY_TOKEN"
+	SchemaRegistryClientIdEnvVarKey     = "SCHEMA_REGISTRY_CLIENT_ID"
+	SchemaRegistryClientSecretEnvVarKey = "SCHEMA_REGISTRY_CLIENT_SECRET"
+
 	// Kafka Configuration
 	KafkaTopicEnvVarKey = "KAFKA_TOPIC"
 
@@ -41,4 +53,7 @@ const (
 	// Dispatcher Configuration
 	ChannelKeyEnvVarKey  = "CHANNEL_KEY"
 	ServiceNameEnvVarKey = "SERVICE_NAME"
+
+	// Namespace Overrides Of The Dispatcher Configuration (JSON)
+	DispatcherConfigOverridesEnvVarKey = "DISPATCHER_CONFIG_OVERRIDES"
 )
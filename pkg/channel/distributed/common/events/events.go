@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/eventing-kafka/pkg/client/clientset/versioned/scheme"
+	listers "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+)
+
+// Data Plane Warning Event Reasons
+const (
+	ProduceFailed         = "ProduceFailed"
+	ConsumerGroupError    = "ConsumerGroupError"
+	SubscriberUnreachable = "SubscriberUnreachable"
+	PoisonPill            = "PoisonPill"
+	SubscriberPaused      = "SubscriberPaused"
+	DecryptionFailed      = "DecryptionFailed"
+)
+
+// The Minimum Interval Between Warning Events Of The Same Reason For A Single KafkaChannel
+const DefaultReportInterval = time.Minute
+
+//
+// Data Plane Warning Event Reporter
+//
+// The Reporter posts Kubernetes Warning Events against the KafkaChannel owning a data plane failure so
+// that users see produce / consume problems via "kubectl describe" rather than only in the pod logs.
+// Since such failures typically occur per-event, the Reporter posts at most one event per KafkaChannel
+// & reason within the ReportInterval.  A nil *Reporter is valid and never reports any events.
+//
+type Reporter struct {
+	logger         *zap.Logger
+	recorder       record.EventRecorder
+	lister         listers.KafkaChannelLister
+	reportInterval time.Duration
+	lastReported   map[string]time.Time
+	lock           sync.Mutex
+}
+
+// Create An EventRecorder Recording Events Via The Specified Kube Client Until The Stop Channel Is Closed
+func NewRecorder(kubeClient kubernetes.Interface, component string, stopChan <-chan struct{}) record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	go func() {
+		<-stopChan
+		eventBroadcaster.Shutdown()
+	}()
+	return eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: component})
+}
+
+// Reporter Constructor
+func NewReporter(logger *zap.Logger, recorder record.EventRecorder, lister listers.KafkaChannelLister) *Reporter {
+	return &Reporter{
+		logger:         logger,
+		recorder:       recorder,
+		lister:         lister,
+		reportInterval: DefaultReportInterval,
+		lastReported:   make(map[string]time.Time),
+	}
+}
+
+// Post A Warning Event Against The Specified KafkaChannel (Unless One Of The Same Reason Was Recently Posted)
+func (r *Reporter) Warning(namespace string, name string, reason string, messageFmt string, args ...interface{}) {
+	if r == nil || !r.due(namespace+"/"+name+"/"+reason) {
+		return
+	}
+
+	// Get The KafkaChannel So The Event References Its UID (Required By "kubectl describe")
+	kafkaChannel, err := r.lister.KafkaChannels(namespace).Get(name)
+	if err != nil {
+		r.logger.Debug("Unable To Report Event For Unknown KafkaChannel", zap.String("Namespace", namespace), zap.String("Name", name), zap.String("Reason", reason), zap.Error(err))
+		return
+	}
+	r.recorder.Eventf(kafkaChannel, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
+
+// Return A ChannelReporter Posting Warning Events Against The KafkaChannel With The Specified "namespace/name" Key
+func (r *Reporter) ForChannel(channelKey string) *ChannelReporter {
+	if r == nil {
+		return nil
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(channelKey)
+	if err != nil {
+		r.logger.Error("Invalid KafkaChannel Key - Not Reporting Events", zap.String("ChannelKey", channelKey), zap.Error(err))
+		return nil
+	}
+	return &ChannelReporter{reporter: r, namespace: namespace, name: name}
+}
+
+// Determine Whether An Event With The Specified Key Is Due (Tracking It As Reported If So)
+func (r *Reporter) due(key string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	now := time.Now()
+	if lastReported, ok := r.lastReported[key]; ok && now.Sub(lastReported) < r.reportInterval {
+		return false
+	}
+	r.lastReported[key] = now
+	return true
+}
+
+// ChannelReporter Posts Warning Events Against A Single KafkaChannel (A nil *ChannelReporter Never Reports Any Events)
+type ChannelReporter struct {
+	reporter  *Reporter
+	namespace string
+	name      string
+}
+
+// Post A Warning Event Against The KafkaChannel (Unless One Of The Same Reason Was Recently Posted)
+func (c *ChannelReporter) Warning(reason string, messageFmt string, args ...interface{}) {
+	if c != nil {
+		c.reporter.Warning(c.namespace, c.name, reason, messageFmt, args...)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	listers "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testNamespace = "test-namespace"
+	testName      = "test-name"
+)
+
+// Test The NewRecorder() & NewReporter() Functionality
+func TestNewReporter(t *testing.T) {
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	recorder := NewRecorder(fake.NewSimpleClientset(), "test-component", stopChan)
+	assert.NotNil(t, recorder)
+	reporter := NewReporter(logtesting.TestLogger(t).Desugar(), recorder, createTestLister(t))
+	assert.NotNil(t, reporter)
+	assert.Equal(t, DefaultReportInterval, reporter.reportInterval)
+}
+
+// Test A Nil Reporter / ChannelReporter Never Reports Events
+func TestNilReporter(t *testing.T) {
+	var reporter *Reporter
+	reporter.Warning(testNamespace, testName, ProduceFailed, "message")
+	assert.Nil(t, reporter.ForChannel(testNamespace+"/"+testName))
+	reporter.ForChannel(testNamespace+"/"+testName).Warning(ProduceFailed, "message")
+}
+
+// Test The Reporter's Warning() Functionality
+func TestReporterWarning(t *testing.T) {
+
+	// Create A Reporter With A Fake Recorder
+	recorder := record.NewFakeRecorder(10)
+	reporter := createTestReporter(t, recorder)
+
+	// Verify Events Are Reported Against Known KafkaChannels & Rate Limited Per Reason
+	reporter.Warning(testNamespace, testName, ProduceFailed, "Failed To Produce: %s", "boom")
+	reporter.Warning(testNamespace, testName, ProduceFailed, "Failed To Produce: %s", "boom")
+	reporter.Warning(testNamespace, testName, ConsumerGroupError, "ConsumerGroup Error")
+	reporter.Warning(testNamespace, "unknown", ProduceFailed, "Failed To Produce")
+	assert.Equal(t, "Warning ProduceFailed Failed To Produce: boom", <-recorder.Events)
+	assert.Equal(t, "Warning ConsumerGroupError ConsumerGroup Error", <-recorder.Events)
+	assert.Empty(t, recorder.Events)
+
+	// Verify Events Are Reported Again After The ReportInterval
+	reporter.lastReported[testNamespace+"/"+testName+"/"+ProduceFailed] = time.Now().Add(-DefaultReportInterval)
+	reporter.Warning(testNamespace, testName, ProduceFailed, "Failed To Produce")
+	assert.Equal(t, "Warning ProduceFailed Failed To Produce", <-recorder.Events)
+}
+
+// Test The ChannelReporter's Warning() Functionality
+func TestChannelReporterWarning(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	reporter := createTestReporter(t, recorder)
+	assert.Nil(t, reporter.ForChannel("invalid/channel/key"))
+	reporter.ForChannel(testNamespace+"/"+testName).Warning(SubscriberUnreachable, "Subscriber %s Unreachable", "http://subscriber")
+	assert.Equal(t, "Warning SubscriberUnreachable Subscriber http://subscriber Unreachable", <-recorder.Events)
+}
+
+// Utility Function For Creating A Reporter With The Specified Recorder
+func createTestReporter(t *testing.T, recorder record.EventRecorder) *Reporter {
+	return NewReporter(logtesting.TestLogger(t).Desugar(), recorder, createTestLister(t))
+}
+
+// Utility Function For Creating A KafkaChannel Lister Containing The Test KafkaChannel
+func createTestLister(t *testing.T) listers.KafkaChannelLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.Nil(t, indexer.Add(&kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName}}))
+	return listers.NewKafkaChannelLister(indexer)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Error Returned For Injected Produce Failures
+var ErrInjectedProduceFailure = errors.New("injected produce failure")
+
+//
+// Data Plane Fault Injector
+//
+// The Injector is intended ONLY for non-production environments where platform teams wish to verify
+// the retry / dead-letter behavior of their applications without breaking real Kafka brokers.  It is
+// configured via the "faultInjection" section of the eventing-kafka ConfigMap and is completely inert
+// unless explicitly enabled.  A nil *Injector is valid and never injects any faults.
+//
+type Injector struct {
+	logger *zap.Logger
+	config config.EKFaultInjectionConfig
+	random func() float64 // Returns A Value In The Range [0.0,100.0)
+}
+
+// Injector Constructor - Returns nil If Fault Injection Is Not Enabled
+func NewInjector(logger *zap.Logger, faultConfig config.EKFaultInjectionConfig) *Injector {
+	if !faultConfig.Enabled {
+		return nil
+	}
+	logger.Warn("Data Plane Fault Injection Enabled - Not For Production Use!", zap.Any("FaultInjection", faultConfig))
+	return &Injector{
+		logger: logger,
+		config: faultConfig,
+		random: func() float64 { return rand.Float64() * 100 },
+	}
+}
+
+// Return An Injected Error For The Configured Percentage Of Produce Attempts (Otherwise nil)
+func (i *Injector) ProduceFailure() error {
+	if i != nil && i.inject(i.config.ProduceFailurePercent) {
+		i.logger.Warn("Injecting Produce Failure")
+		return ErrInjectedProduceFailure
+	}
+	return nil
+}
+
+// Return True For The Configured Percentage Of Consumed Messages (Caller Should Force A ConsumerGroup Rebalance)
+func (i *Injector) Rebalance() bool {
+	if i != nil && i.inject(i.config.RebalancePercent) {
+		i.logger.Warn("Injecting ConsumerGroup Rebalance")
+		return true
+	}
+	return false
+}
+
+// Return The Configured Latency For The Configured Percentage Of Subscriber Deliveries (Otherwise Zero)
+func (i *Injector) SubscriberLatency() time.Duration {
+	if i != nil && i.inject(i.config.SubscriberLatencyPercent) {
+		latency := time.Duration(i.config.SubscriberLatencyMillis) * time.Millisecond
+		i.logger.Warn("Injecting Subscriber Latency", zap.Duration("Latency", latency))
+		return latency
+	}
+	return 0
+}
+
+// Determine Whether To Inject A Fault Based On The Specified Percentage
+func (i *Injector) inject(percent float64) bool {
+	return percent > 0 && i.random() < percent
+}
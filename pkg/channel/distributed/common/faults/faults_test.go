@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewInjector() Functionality
+func TestNewInjector(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Nil(t, NewInjector(logger, config.EKFaultInjectionConfig{Enabled: false, ProduceFailurePercent: 100}))
+	assert.NotNil(t, NewInjector(logger, config.EKFaultInjectionConfig{Enabled: true}))
+}
+
+// Test A Nil Injector Never Injects Faults
+func TestNilInjector(t *testing.T) {
+	var injector *Injector
+	assert.Nil(t, injector.ProduceFailure())
+	assert.False(t, injector.Rebalance())
+	assert.Equal(t, time.Duration(0), injector.SubscriberLatency())
+}
+
+// Test The Injector Faults Against The Configured Percentages
+func TestInjector(t *testing.T) {
+
+	// Test Data
+	faultConfig := config.EKFaultInjectionConfig{
+		Enabled:                  true,
+		ProduceFailurePercent:    10,
+		RebalancePercent:         20,
+		SubscriberLatencyPercent: 30,
+		SubscriberLatencyMillis:  500,
+	}
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name              string
+		random            float64
+		produceFailure    error
+		rebalance         bool
+		subscriberLatency time.Duration
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "All Faults", random: 5, produceFailure: ErrInjectedProduceFailure, rebalance: true, subscriberLatency: 500 * time.Millisecond},
+		{name: "Rebalance & Latency", random: 15, rebalance: true, subscriberLatency: 500 * time.Millisecond},
+		{name: "Latency Only", random: 25, subscriberLatency: 500 * time.Millisecond},
+		{name: "No Faults", random: 35},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			injector := NewInjector(logtesting.TestLogger(t).Desugar(), faultConfig)
+			injector.random = func() float64 { return testCase.random }
+			assert.Equal(t, testCase.produceFailure, injector.ProduceFailure())
+			assert.Equal(t, testCase.rebalance, injector.Rebalance())
+			assert.Equal(t, testCase.subscriberLatency, injector.SubscriberLatency())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gctuning
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// The Go Runtime's Garbage Collection Environment Variables
+const (
+	GOGCEnvVarKey       = "GOGC"
+	GOMemLimitEnvVarKey = "GOMEMLIMIT"
+)
+
+// The GOGC Value Disabling Garbage Collection (Until The GOMemLimit, If Any, Is Reached)
+const GOGCOff = "off"
+
+// The Memory Ballast (Allocated But Never Touched, So Only Reserving Virtual Memory)
+var (
+	ballast     []byte
+	ballastLock sync.Mutex
+)
+
+// Validate The Specified Runtime Configuration
+func Validate(runtimeConfig config.EKRuntimeConfig) error {
+	if _, err := gcPercent(runtimeConfig.GOGC); err != nil {
+		return err
+	}
+	if runtimeConfig.GOMemLimit != nil && runtimeConfig.GOMemLimit.Sign() < 0 {
+		return fmt.Errorf("invalid GOMemLimit %s: must be >= 0", runtimeConfig.GOMemLimit.String())
+	}
+	if runtimeConfig.Ballast != nil && runtimeConfig.Ballast.Sign() < 0 {
+		return fmt.Errorf("invalid Ballast %s: must be >= 0", runtimeConfig.Ballast.String())
+	}
+	return nil
+}
+
+// Get The Environment Variables Of The Specified Runtime Configuration (For The Generated Deployments)
+func EnvVars(runtimeConfig config.EKRuntimeConfig) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	if gogc := strings.TrimSpace(runtimeConfig.GOGC); len(gogc) > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: GOGCEnvVarKey, Value: strings.ToLower(gogc)})
+	}
+	if runtimeConfig.GOMemLimit != nil && runtimeConfig.GOMemLimit.Sign() > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: GOMemLimitEnvVarKey, Value: strconv.FormatInt(runtimeConfig.GOMemLimit.Value(), 10)})
+	}
+	return envVars
+}
+
+//
+// Apply The Specified Runtime Configuration In-Process
+//
+// The GOGC is applied (unless it was already set by the GOGC environment variable, which takes precedence) so that
+// it is honored even by the Deployments generated before it was configured, and the Ballast is (re)allocated.  The
+// GOMemLimit cannot be applied in-process by the Go versions this module supports, so it is only honored when set
+// by the GOMEMLIMIT environment variable of the Deployment (i.e. once the Deployment is re-created).
+//
+func Apply(logger *zap.Logger, runtimeConfig config.EKRuntimeConfig) error {
+	if err := Validate(runtimeConfig); err != nil {
+		return err
+	}
+
+	// Set The GC Percent Unless Set By The Environment
+	if gogc := strings.TrimSpace(runtimeConfig.GOGC); len(gogc) > 0 {
+		if envGOGC, ok := os.LookupEnv(GOGCEnvVarKey); ok {
+			logger.Info("GOGC Set By The Environment", zap.String("GOGC", envGOGC))
+		} else {
+			percent, _ := gcPercent(gogc)
+			debug.SetGCPercent(percent)
+			logger.Info("Applied GOGC", zap.String("GOGC", gogc))
+		}
+	}
+
+	// Warn If The GOMemLimit Is Not Set By The Environment (The Deployment Predating Its Configuration)
+	if runtimeConfig.GOMemLimit != nil && runtimeConfig.GOMemLimit.Sign() > 0 {
+		if _, ok := os.LookupEnv(GOMemLimitEnvVarKey); !ok {
+			logger.Warn("GOMemLimit Configured But Not Set By The GOMEMLIMIT Environment Variable - Re-Create The Deployment To Apply It",
+				zap.String("GOMemLimit", runtimeConfig.GOMemLimit.String()))
+		}
+	}
+
+	// (Re)Allocate The Ballast
+	ballastLock.Lock()
+	defer ballastLock.Unlock()
+	var ballastSize int64
+	if runtimeConfig.Ballast != nil {
+		ballastSize = runtimeConfig.Ballast.Value()
+	}
+	if int64(len(ballast)) != ballastSize {
+		ballast = nil
+		if ballastSize > 0 {
+			ballast = make([]byte, ballastSize)
+			logger.Info("Allocated Memory Ballast", zap.Int64("Bytes", ballastSize))
+		}
+	}
+	return nil
+}
+
+// Get The Size Of The Currently Allocated Ballast
+func BallastSize() int {
+	ballastLock.Lock()
+	defer ballastLock.Unlock()
+	return len(ballast)
+}
+
+// Parse The Specified GOGC Value As A GC Percent (Negative If "off", The Default Of 100 If Empty)
+func gcPercent(gogc string) (int, error) {
+	gogc = strings.TrimSpace(gogc)
+	if len(gogc) == 0 {
+		return 100, nil
+	} else if strings.EqualFold(gogc, GOGCOff) {
+		return -1, nil
+	}
+	percent, err := strconv.Atoi(gogc)
+	if err != nil || percent < 0 {
+		return 0, fmt.Errorf("invalid GOGC %q: expected %q or a percentage >= 0", gogc, GOGCOff)
+	}
+	return percent, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gctuning
+
+import (
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Validate() & EnvVars() Functionality
+func TestValidateAndEnvVars(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      config.EKRuntimeConfig
+		wantErr     bool
+		wantEnvVars []corev1.EnvVar
+	}{
+		{name: "Empty"},
+		{name: "Percent", config: config.EKRuntimeConfig{GOGC: "200"}, wantEnvVars: []corev1.EnvVar{{Name: GOGCEnvVarKey, Value: "200"}}},
+		{
+			name:        "Off With Limit & Ballast",
+			config:      config.EKRuntimeConfig{GOGC: "Off", GOMemLimit: resource.NewQuantity(1024*1024*1024, resource.BinarySI), Ballast: resource.NewQuantity(256*1024*1024, resource.BinarySI)},
+			wantEnvVars: []corev1.EnvVar{{Name: GOGCEnvVarKey, Value: "off"}, {Name: GOMemLimitEnvVarKey, Value: "1073741824"}},
+		},
+		{name: "Invalid GOGC", config: config.EKRuntimeConfig{GOGC: "lots"}, wantErr: true},
+		{name: "Negative GOGC", config: config.EKRuntimeConfig{GOGC: "-1"}, wantErr: true},
+		{name: "Negative GOMemLimit", config: config.EKRuntimeConfig{GOMemLimit: resource.NewQuantity(-1, resource.BinarySI)}, wantErr: true},
+		{name: "Negative Ballast", config: config.EKRuntimeConfig{Ballast: resource.NewQuantity(-1, resource.BinarySI)}, wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.wantErr, Validate(testCase.config) != nil)
+			if !testCase.wantErr {
+				assert.Equal(t, testCase.wantEnvVars, EnvVars(testCase.config))
+			}
+		})
+	}
+}
+
+// Test The Apply() Functionality
+func TestApply(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	originalGOGC, hadGOGC := os.LookupEnv(GOGCEnvVarKey)
+	assert.Nil(t, os.Unsetenv(GOGCEnvVarKey))
+	defer func() {
+		if hadGOGC {
+			_ = os.Setenv(GOGCEnvVarKey, originalGOGC)
+		}
+		debug.SetGCPercent(100)
+	}()
+
+	// Verify The GOGC & Ballast Are Applied
+	assert.Nil(t, Apply(logger, config.EKRuntimeConfig{GOGC: "300", Ballast: resource.NewQuantity(1024*1024, resource.BinarySI)}))
+	assert.Equal(t, 300, debug.SetGCPercent(100))
+	assert.Equal(t, 1024*1024, BallastSize())
+
+	// Verify The GOGC Environment Variable Takes Precedence & The Ballast Is Released
+	assert.Nil(t, os.Setenv(GOGCEnvVarKey, "150"))
+	assert.Nil(t, Apply(logger, config.EKRuntimeConfig{GOGC: "off"}))
+	assert.Equal(t, 100, debug.SetGCPercent(100))
+	assert.Equal(t, 0, BallastSize())
+
+	// Verify An Invalid Configuration Is Rejected
+	assert.NotNil(t, Apply(logger, config.EKRuntimeConfig{GOGC: "lots"}))
+}
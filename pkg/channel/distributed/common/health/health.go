@@ -24,6 +24,7 @@ import (
 	"sync"
 
 	"go.uber.org/zap"
+	commonlistener "knative.dev/eventing-kafka/pkg/channel/distributed/common/listener"
 )
 
 // Interface For Providing Overrides For Liveness And Readiness Information
@@ -34,7 +35,8 @@ type Status interface {
 
 // Structure Containing Basic Liveness Information For Health Server
 type Server struct {
-	server   *http.Server // The Golang HTTP Server Instance
+	server   *http.Server   // The Golang HTTP Server Instance
+	serveMux *http.ServeMux // The Mux Routing The Liveness, Readiness & Any Additional Endpoints
 	status   Status
 	HttpPort string // The HTTP Port The Dispatcher Server Listens On
 
@@ -83,11 +85,17 @@ func (hs *Server) initializeServer(httpPort string) {
 
 	// Set The Initialized HTTP Server
 	hs.server = server
+	hs.serveMux = serveMux
+}
+
+// Register An Additional Handler (e.g. A Status Endpoint) With The HTTP Server
+func (hs *Server) Handle(pattern string, handler http.Handler) {
+	hs.serveMux.Handle(pattern, handler)
 }
 
 // Start The HTTP Server (Blocking Call)
 func (hs *Server) Start(logger *zap.Logger) {
-	listener, err := net.Listen("tcp", ":"+hs.HttpPort)
+	listener, err := commonlistener.Listen(hs.HttpPort)
 	if err != nil {
 		logger.Error("Server HTTP Listen Returned Error", zap.Error(err))
 	}
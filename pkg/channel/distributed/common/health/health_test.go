@@ -132,6 +132,24 @@ func TestHealthHandler(t *testing.T) {
 	getEventToHandler(t, health.HandleLiveness, livenessPath, http.StatusInternalServerError)
 }
 
+// Test The Registration Of Additional Handlers
+func TestHandle(t *testing.T) {
+
+	// Create A Health Server & Register An Additional Handler
+	health := getTestHealthServer()
+	health.Handle("/status", http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		responseWriter.WriteHeader(http.StatusTeapot)
+	}))
+
+	// Verify The Additional Endpoint Is Served Alongside The Liveness Endpoint
+	responseRecorder := httptest.NewRecorder()
+	health.server.Handler.ServeHTTP(responseRecorder, createNewRequest(t, http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusTeapot, responseRecorder.Code)
+	responseRecorder = httptest.NewRecorder()
+	health.server.Handler.ServeHTTP(responseRecorder, createNewRequest(t, http.MethodGet, livenessPath, nil))
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+}
+
 // Test The Health Server Via Live HTTP Calls
 func TestHealthServer(t *testing.T) {
 
@@ -18,36 +18,184 @@ package admin
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/Shopify/sarama"
-	"knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/pkg/system"
 )
 
-// Sarama ClusterAdmin Wrapping Interface To Facilitate Other Implementations (e.g. Azure EventHubs)
-type AdminClientInterface interface {
-	CreateTopic(context.Context, string, *sarama.TopicDetail) *sarama.TopicError
-	DeleteTopic(context.Context, string) *sarama.TopicError
+//
+// TopicProvisioner Is The Extension Point Through Which The Controller Manages Kafka Topics
+//
+// All of the controller's topic administration (the KafkaChannel topics, their event type sub-topics, DeadLetter
+// and quarantine topics, and partition expansion) goes through the TopicProvisioner selected by name with the
+// kafka.adminType setting of the ConfigMap.  The built-in provisioners are "kafka" (the Kafka Admin API), "azure"
+// (Azure EventHubs), "custom" (a REST sidecar) and "strimzi" (Strimzi KafkaTopic resources).  Organizations may
+// compile in their own provisioners (e.g. ticket based provisioning workflows) by registering them from the init()
+// function of a package imported by their build of the controller.  See the .../common/kafka/README.md for details.
+//
+// The operations return a *sarama.TopicError (nil or ErrNoError indicating success) so that the reconciler handles
+// the outcome of every provisioner in the same manner as the Kafka Admin API's...
+//
+//   - CreateTopic returns ErrTopicAlreadyExists if the topic exists (which the reconciler treats as success).
+//   - AlterTopic increases the topic's partitions (when the TopicDetail's NumPartitions is non-zero, returning
+//     ErrInvalidPartitions if the topic already has at least as many) and sets its ConfigEntries (when any).
+//   - DeleteTopic returns ErrUnknownTopicOrPartition if the topic does not exist (also treated as success).
+//   - Provisioners which cannot perform an operation return ErrInvalidRequest.
+//
+type TopicProvisioner interface {
+
+	// Validate The Specified Topic Before Any Attempt To Create It (Without Side Effects)
+	Validate(ctx context.Context, topicName string, topicDetail *sarama.TopicDetail) error
+
+	// Create The Specified Topic
+	CreateTopic(ctx context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError
+
+	// Alter The Partitions And / Or Configuration Of The Specified Existing Topic
+	AlterTopic(ctx context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError
+
+	// Delete The Specified Topic
+	DeleteTopic(ctx context.Context, topicName string) *sarama.TopicError
+
+	// Release Any Resources (Connections, etc.) Held By The Provisioner
 	Close() error
+
+	// Get The Name Of The K8S Secret With The Kafka Credentials Of The Specified Topic
 	GetKafkaSecretName(topicName string) string
 }
 
-// AdminClient Type Enumeration
-type AdminClientType int
+//
+// ClusterInspector Is Optionally Implemented By TopicProvisioners Able To Enumerate The Kafka Cluster
+//
+// The controller's janitor uses it to find the topics and ConsumerGroups left behind by KafkaChannels (and
+// Subscribers) which no longer exist.  The janitor skips the Kafka cluster when the TopicProvisioner selected
+// by the kafka.adminType setting does not implement it.
+//
+type ClusterInspector interface {
+
+	// List The Names Of All Topics In The Kafka Cluster
+	ListTopics(ctx context.Context) ([]string, error)
+
+	// List The Ids Of All ConsumerGroups In The Kafka Cluster
+	ListConsumerGroups(ctx context.Context) ([]string, error)
+
+	// Delete The Specified (Empty) ConsumerGroup
+	DeleteConsumerGroup(ctx context.Context, groupId string) error
+}
+
+//
+// OffsetInspector Is Optionally Implemented By TopicProvisioners Able To Read The Offsets Of Topics
+//
+// The controller uses it to detect whether records have been produced to the topics of KafkaChannels whose
+// dispatchers may be scaled to zero, and to export the committed offsets & lag of their subscribers, neither of
+// which happens when the TopicProvisioner selected by the kafka.adminType setting does not implement it.
+//
+type OffsetInspector interface {
+
+	// Get The Sum Of The Newest Offsets Of All Partitions Of Each Of The Specified Topics (Omitting Unknown Topics)
+	TopicOffsets(ctx context.Context, topicNames []string) (map[string]int64, error)
 
+	// Get The Committed Offsets & Lag Of The Specified ConsumerGroup On The Specified Topics (Omitting Unknown Topics)
+	ConsumerGroupOffsets(ctx context.Context, groupId string, topicNames []string) (*ConsumerGroupOffsets, error)
+}
+
+//
+// ConsumerGroupInspector Is Optionally Implemented By TopicProvisioners Able To Describe ConsumerGroups
+//
+// The controller uses it to track how long the ConsumerGroups of subscribers have been without consumers, warning
+// of those whose committed offsets risk expiring, which does not happen when the TopicProvisioner selected by the
+// kafka.adminType setting does not implement it.
+//
+type ConsumerGroupInspector interface {
+
+	// Get The State ("Stable", "Empty", "Dead", etc.) Of Each Of The Specified ConsumerGroups
+	ConsumerGroupStates(ctx context.Context, groupIds []string) (map[string]string, error)
+}
+
+// The ConsumerGroup State Of A Group Without Any Members, Whose Committed Offsets Expire After The Offsets Retention
+const ConsumerGroupStateEmpty = "Empty"
+
+// ConsumerGroupOffsets Are The Committed Offsets Of A ConsumerGroup On Each Partition Of Each Topic (Omitting The
+// Partitions Without Committed Offsets), And Its Lag Behind The Newest Offsets Of Those Partitions
+type ConsumerGroupOffsets struct {
+	Committed map[string]map[int32]int64
+	Lag       int64
+}
+
+// ProvisionerOptions Are The Arguments With Which A TopicProvisioner Is Created
+type ProvisionerOptions struct {
+	SaramaConfig *sarama.Config       // The Sarama Config Loaded From The ConfigMap
+	ClientId     string               // The Kafka client.id Of The Controller
+	Namespace    string               // The K8S Namespace Of The Kafka Secret(s)
+	KafkaConfig  config.EKKafkaConfig // The Kafka Settings Of The ConfigMap (Including Any ProvisionerConfig)
+}
+
+// ProvisionerFactory Creates A TopicProvisioner (A nil TopicProvisioner & error Indicates A Non-Functional Configuration)
+type ProvisionerFactory func(ctx context.Context, options ProvisionerOptions) (TopicProvisioner, error)
+
+// The Names Of The Built-In TopicProvisioners (The kafka.adminType Values)
 const (
-	Kafka AdminClientType = iota
-	EventHub
-	Custom
-	Unknown
+	KafkaProvisionerName    = "kafka"
+	EventHubProvisionerName = "azure"
+	CustomProvisionerName   = "custom"
+	StrimziProvisionerName  = "strimzi"
 )
 
+// The Registered ProvisionerFactories By Name
+var (
+	provisionerFactories = map[string]ProvisionerFactory{
+		KafkaProvisionerName: func(ctx context.Context, options ProvisionerOptions) (TopicProvisioner, error) {
+			return NewKafkaAdminClientWrapper(ctx, options.SaramaConfig, options.ClientId, options.Namespace, options.KafkaConfig.AuthSpec)
+		},
+		EventHubProvisionerName: func(ctx context.Context, options ProvisionerOptions) (TopicProvisioner, error) {
+			return NewEventHubAdminClientWrapper(ctx, options.Namespace)
+		},
+		CustomProvisionerName: func(ctx context.Context, options ProvisionerOptions) (TopicProvisioner, error) {
+			return NewCustomAdminClientWrapper(ctx, options.Namespace)
+		},
+		StrimziProvisionerName: func(ctx context.Context, options ProvisionerOptions) (TopicProvisioner, error) {
+			return NewStrimziAdminClientWrapper(ctx, options.Namespace, options.KafkaConfig.Strimzi)
+		},
+	}
+	provisionerFactoriesLock sync.RWMutex
+)
+
+// Register A Custom TopicProvisioner Which May Then Be Selected By (Lowercase) Name With The kafka.adminType Setting
+func RegisterProvisioner(name string, factory ProvisionerFactory) {
+	provisionerFactoriesLock.Lock()
+	defer provisionerFactoriesLock.Unlock()
+	provisionerFactories[strings.ToLower(name)] = factory
+}
+
+// Get The Sorted Names Of The Registered TopicProvisioners
+func ProvisionerNames() []string {
+	provisionerFactoriesLock.RLock()
+	defer provisionerFactoriesLock.RUnlock()
+	names := make([]string, 0, len(provisionerFactories))
+	for name := range provisionerFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Determine Whether A TopicProvisioner Is Registered With The Specified Name
+func IsProvisionerRegistered(name string) bool {
+	provisionerFactoriesLock.RLock()
+	defer provisionerFactoriesLock.RUnlock()
+	_, ok := provisionerFactories[strings.ToLower(name)]
+	return ok
+}
+
 //
-// Create A New Kafka AdminClient Of Specified Type - Using Credentials From Kafka Secret(s) In Specified K8S Namespace
+// Create A New TopicProvisioner Of The Specified Kafka AdminType - Using Credentials From Kafka Secret(s) In The System Namespace
 //
-// The K8S Namespace parameter indicates the Kubernetes Namespace in which the Kafka Credentials secret(s)
-// will be found.  The secret(s) must contain the constants.KafkaSecretLabel label indicating it is a "Kafka Secret".
+// The Kafka Secret(s) must contain the constants.KafkaSecretLabel label indicating it is a "Kafka Secret".
 //
 // For the normal Kafka use case (Confluent, etc.) there should be only one Secret with the following content...
 //
@@ -66,32 +214,44 @@ const (
 //
 // * If no authorization is required (local dev instance) then specify username and password as the empty string ""
 //
-func CreateAdminClient(ctx context.Context, saramaConfig *sarama.Config, clientId string, adminClientType AdminClientType) (AdminClientInterface, error) {
-	switch adminClientType {
-	case Kafka:
-		return NewKafkaAdminClientWrapper(ctx, saramaConfig, clientId, constants.KnativeEventingNamespace)
-	case EventHub:
-		return NewEventHubAdminClientWrapper(ctx, constants.KnativeEventingNamespace)
-	case Custom:
-		return NewCustomAdminClientWrapper(ctx, constants.KnativeEventingNamespace)
-	case Unknown:
-		return nil, errors.New("received unknown AdminClientType") // Should Never Happen But...
-	default:
-		return nil, fmt.Errorf("received unsupported AdminClientType of %d", adminClientType)
+// * For the normal Kafka use case the brokers, username & password may instead be provided by the (optional)
+//   KafkaAuthSpec, in which case the Kafka Secret only identifies the Receiver and its data is ignored.
+//
+// * For the Strimzi use case the Kafka Secret only identifies the Receiver and the topics are instead managed
+//   as KafkaTopic resources of the Strimzi cluster identified by the kafka.strimzi settings.
+//
+func CreateAdminClient(ctx context.Context, saramaConfig *sarama.Config, clientId string, kafkaConfig config.EKKafkaConfig) (TopicProvisioner, error) {
+	name := strings.ToLower(kafkaConfig.AdminType)
+	provisionerFactoriesLock.RLock()
+	factory, ok := provisionerFactories[name]
+	provisionerFactoriesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown Kafka AdminType (topic provisioner) '%s' - expected one of %v", kafkaConfig.AdminType, ProvisionerNames())
 	}
+	return factory(ctx, ProvisionerOptions{
+		SaramaConfig: saramaConfig,
+		ClientId:     clientId,
+		Namespace:    system.Namespace(),
+		KafkaConfig:  kafkaConfig,
+	})
 }
 
 // New Kafka AdminClient Wrapper To Facilitate Unit Testing
-var NewKafkaAdminClientWrapper = func(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string) (AdminClientInterface, error) {
-	return NewKafkaAdminClient(ctx, saramaConfig, clientId, namespace)
+var NewKafkaAdminClientWrapper = func(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string, authSpec *bindingsv1beta1.KafkaAuthSpec) (TopicProvisioner, error) {
+	return NewKafkaAdminClient(ctx, saramaConfig, clientId, namespace, authSpec)
 }
 
 // New EventHub AdminClient Wrapper To Facilitate Unit Testing
-var NewEventHubAdminClientWrapper = func(ctx context.Context, namespace string) (AdminClientInterface, error) {
+var NewEventHubAdminClientWrapper = func(ctx context.Context, namespace string) (TopicProvisioner, error) {
 	return NewEventHubAdminClient(ctx, namespace)
 }
 
 // New Custom AdminClient Wrapper To Facilitate Unit Testing
-var NewCustomAdminClientWrapper = func(ctx context.Context, namespace string) (AdminClientInterface, error) {
+var NewCustomAdminClientWrapper = func(ctx context.Context, namespace string) (TopicProvisioner, error) {
 	return NewCustomAdminClient(ctx, namespace)
 }
+
+// New Strimzi AdminClient Wrapper To Facilitate Unit Testing
+var NewStrimziAdminClientWrapper = func(ctx context.Context, namespace string, strimziConfig config.EKStrimziConfig) (TopicProvisioner, error) {
+	return NewStrimziAdminClient(ctx, namespace, strimziConfig)
+}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 
 	"github.com/Shopify/sarama"
@@ -45,8 +46,8 @@ import (
 // See the .../common/kafka/README.md for full details.
 //
 
-// Ensure The KafkaAdminClient Struct Implements The AdminClientInterface
-var _ AdminClientInterface = &CustomAdminClient{}
+// Ensure The KafkaAdminClient Struct Implements The TopicProvisioner
+var _ TopicProvisioner = &CustomAdminClient{}
 
 // Custom AdminClient Definition
 type CustomAdminClient struct {
@@ -57,7 +58,7 @@ type CustomAdminClient struct {
 }
 
 // Create A New Custom Kafka AdminClient Based On The Kafka Secret In The Specified K8S Namespace
-func NewCustomAdminClient(ctx context.Context, namespace string) (AdminClientInterface, error) {
+func NewCustomAdminClient(ctx context.Context, namespace string) (TopicProvisioner, error) {
 
 	// Get The Logger From The Context
 	logger := logging.FromContext(ctx).Desugar()
@@ -104,6 +105,11 @@ func NewCustomAdminClient(ctx context.Context, namespace string) (AdminClientInt
 	return customAdminClient, nil
 }
 
+// Validate The Topic Against The Kafka Topic Constraints (The Sidecar Has No Validation Endpoint)
+func (c *CustomAdminClient) Validate(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) error {
+	return adminutil.ValidateTopic(topicName, topicDetail)
+}
+
 // Custom REST Pass-Through Function For Creating Topics
 func (c *CustomAdminClient) CreateTopic(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
 
@@ -187,6 +193,12 @@ func (c *CustomAdminClient) DeleteTopic(_ context.Context, topicName string) *sa
 	return c.mapHttpResponse("delete", response)
 }
 
+// Altering Topics Is Not Supported By The Custom Sidecar REST API
+func (c *CustomAdminClient) AlterTopic(_ context.Context, topicName string, _ *sarama.TopicDetail) *sarama.TopicError {
+	c.logger.Warn("Altering Topics Is Not Supported By The Custom Sidecar", zap.String("TopicName", topicName))
+	return adminutil.NewTopicError(sarama.ErrInvalidRequest, fmt.Sprintf("altering topic '%s' is not supported by the custom sidecar", topicName))
+}
+
 // Custom REST Pass-Through Function For Closing The Admin Client
 func (c *CustomAdminClient) Close() error {
 	return nil // Nothing to "close" in the Custom implementation (just a REST client) so this is just a compatibility no-op.
@@ -209,7 +221,7 @@ func (c *CustomAdminClient) safeCloseHTTPResponseBody(response *http.Response) {
 
 // Get The Expected Topics URL For The Custom Sidecar Implementation
 func (c *CustomAdminClient) sidecarTopicsUrl(topicName string) string {
-	topicsUrl := "http://" + custom.SidecarHost + ":" + custom.SidecarPort + custom.TopicsPath
+	topicsUrl := "http://" + net.JoinHostPort(custom.SidecarHost, custom.SidecarPort) + custom.TopicsPath
 	if len(topicName) > 0 {
 		topicsUrl = topicsUrl + "/" + topicName
 	}
@@ -197,6 +197,27 @@ func TestCustomAdminClientDeleteTopic(t *testing.T) {
 	}
 }
 
+// Test The Custom AdminClient Validate() Functionality
+func TestCustomAdminClientValidate(t *testing.T) {
+	adminClient := &CustomAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+	assert.Nil(t, adminClient.Validate(context.TODO(), "TestTopicName", &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 1}))
+	assert.NotNil(t, adminClient.Validate(context.TODO(), "TestTopicName", nil))
+}
+
+// Test The Custom AdminClient AlterTopic() Functionality (Not Supported)
+func TestCustomAdminClientAlterTopic(t *testing.T) {
+
+	// Create A New Custom AdminClient To Test
+	adminClient := &CustomAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+
+	// Perform The Test
+	resultTopicError := adminClient.AlterTopic(context.TODO(), "TestTopicName", &sarama.TopicDetail{NumPartitions: 8})
+
+	// Verify The Results
+	assert.NotNil(t, resultTopicError)
+	assert.Equal(t, sarama.ErrInvalidRequest, resultTopicError.Err)
+}
+
 // Test The Custom AdminClient Close() Functionality
 func TestCustomAdminClientClose(t *testing.T) {
 
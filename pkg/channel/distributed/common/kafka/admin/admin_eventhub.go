@@ -41,8 +41,8 @@ import (
 // the Namespace layer.
 //
 
-// Ensure The EventHubAdminClient Struct Implements The AdminClientInterface
-var _ AdminClientInterface = &EventHubAdminClient{}
+// Ensure The EventHubAdminClient Struct Implements The TopicProvisioner
+var _ TopicProvisioner = &EventHubAdminClient{}
 
 // EventHub AdminClient Definition
 type EventHubAdminClient struct {
@@ -60,7 +60,7 @@ var NewCacheWrapper = func(ctx context.Context, k8sNamespace string) eventhubcac
 }
 
 // Create A New Azure EventHub AdminClient Based On Kafka Secrets In The Specified K8S Namespace
-func NewEventHubAdminClient(ctx context.Context, namespace string) (AdminClientInterface, error) {
+func NewEventHubAdminClient(ctx context.Context, namespace string) (TopicProvisioner, error) {
 
 	// Get The Logger From The Context
 	logger := logging.FromContext(ctx).Desugar()
@@ -186,6 +186,17 @@ func (c *EventHubAdminClient) DeleteTopic(ctx context.Context, topicName string)
 	return adminutil.NewTopicError(sarama.ErrNoError, "successfully deleted topic")
 }
 
+// Validate The Topic (EventHub) Against The Kafka Topic Constraints
+func (c *EventHubAdminClient) Validate(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) error {
+	return adminutil.ValidateTopic(topicName, topicDetail)
+}
+
+// Altering An EventHub Is Not Supported (The Partition Count Is Fixed At Creation For Non-Dedicated Tiers)
+func (c *EventHubAdminClient) AlterTopic(_ context.Context, topicName string, _ *sarama.TopicDetail) *sarama.TopicError {
+	c.logger.Warn("Altering EventHubs Is Not Supported", zap.String("Topic", topicName))
+	return adminutil.NewTopicError(sarama.ErrInvalidRequest, fmt.Sprintf("altering EventHub '%s' is not supported", topicName))
+}
+
 // Get The K8S Secret With Kafka Credentials For The Specified Topic (EventHub)
 func (c *EventHubAdminClient) GetKafkaSecretName(topicName string) string {
 
@@ -540,6 +540,27 @@ func TestEventHubAdminClientGetKafkaSecretName(t *testing.T) {
 	mockCache.AssertExpectations(t)
 }
 
+// Test The EventHub AdminClient Validate() Functionality
+func TestEventHubAdminClientValidate(t *testing.T) {
+	adminClient := &EventHubAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+	assert.Nil(t, adminClient.Validate(context.TODO(), "TestTopicName", &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 1}))
+	assert.NotNil(t, adminClient.Validate(context.TODO(), "", &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 1}))
+}
+
+// Test The EventHub AdminClient AlterTopic() Functionality (Not Supported)
+func TestEventHubAdminClientAlterTopic(t *testing.T) {
+
+	// Create A New EventHub AdminClient To Test
+	adminClient := &EventHubAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+
+	// Perform The Test
+	resultTopicError := adminClient.AlterTopic(context.TODO(), "TestTopicName", &sarama.TopicDetail{NumPartitions: 8})
+
+	// Verify The Results
+	assert.NotNil(t, resultTopicError)
+	assert.Equal(t, sarama.ErrInvalidRequest, resultTopicError.Err)
+}
+
 // Test The EventHub AdminClient Close() Functionality
 func TestEventHubAdminClientClose(t *testing.T) {
 
@@ -20,11 +20,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
 	adminutil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/util"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
@@ -37,8 +39,11 @@ import (
 // a pass-through to the Sarama ClusterAdmin with some additional functionality layered on top.
 //
 
-// Ensure The KafkaAdminClient Struct Implements The AdminClientInterface
-var _ AdminClientInterface = &KafkaAdminClient{}
+// Ensure The KafkaAdminClient Struct Implements The TopicProvisioner, ClusterInspector, OffsetInspector & ConsumerGroupInspector
+var _ TopicProvisioner = &KafkaAdminClient{}
+var _ ClusterInspector = &KafkaAdminClient{}
+var _ OffsetInspector = &KafkaAdminClient{}
+var _ ConsumerGroupInspector = &KafkaAdminClient{}
 
 // Kafka AdminClient Definition
 type KafkaAdminClient struct {
@@ -47,10 +52,12 @@ type KafkaAdminClient struct {
 	kafkaSecret  string
 	clientId     string
 	clusterAdmin sarama.ClusterAdmin
+	brokers      []string
+	saramaConfig *sarama.Config
 }
 
-// Create A New Kafka AdminClient Based On The Kafka Secret In The Specified K8S Namespace
-func NewKafkaAdminClient(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string) (AdminClientInterface, error) {
+// Create A New Kafka AdminClient Based On The Kafka Secret (Or KafkaAuthSpec If Specified) In The Specified K8S Namespace
+func NewKafkaAdminClient(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string, authSpec *bindingsv1beta1.KafkaAuthSpec) (TopicProvisioner, error) {
 
 	// Get The Logger From The Context
 	logger := logging.FromContext(ctx).Desugar()
@@ -75,19 +82,32 @@ func NewKafkaAdminClient(ctx context.Context, saramaConfig *sarama.Config, clien
 		kafkaSecret = kafkaSecrets.Items[0]
 	}
 
-	// Validate Secret Data
-	if !adminutil.ValidateKafkaSecret(logger, &kafkaSecret) {
-		err = errors.New("invalid Kafka Secret found")
-		return nil, err
-	}
+	// Get The Brokers & Credentials From The KafkaAuthSpec (Ignoring The Kafka Secret's Data) If Specified
+	var brokers []string
+	if authSpec != nil {
+		brokers = authSpec.BootstrapServers
+		kafkasarama.UpdateSaramaConfig(saramaConfig, clientId, "", "")
+		err = kafkasarama.UpdateSaramaAuthSpec(ctx, k8sClient, namespace, saramaConfig, authSpec)
+		if err != nil {
+			logger.Error("Failed To Apply KafkaAuthSpec", zap.Error(err))
+			return nil, err
+		}
+	} else {
 
-	// Extract The Relevant Data From The Kafka Secret
-	brokers := strings.Split(string(kafkaSecret.Data[constants.KafkaSecretKeyBrokers]), ",")
-	username := string(kafkaSecret.Data[constants.KafkaSecretKeyUsername])
-	password := string(kafkaSecret.Data[constants.KafkaSecretKeyPassword])
+		// Validate Secret Data
+		if !adminutil.ValidateKafkaSecret(logger, &kafkaSecret) {
+			err = errors.New("invalid Kafka Secret found")
+			return nil, err
+		}
 
-	// Update The Sarama ClusterAdmin Configuration With Our Values
-	kafkasarama.UpdateSaramaConfig(saramaConfig, clientId, username, password)
+		// Extract The Relevant Data From The Kafka Secret
+		brokers = strings.Split(string(kafkaSecret.Data[constants.KafkaSecretKeyBrokers]), ",")
+		username := string(kafkaSecret.Data[constants.KafkaSecretKeyUsername])
+		password := string(kafkaSecret.Data[constants.KafkaSecretKeyPassword])
+
+		// Update The Sarama ClusterAdmin Configuration With Our Values
+		kafkasarama.UpdateSaramaConfig(saramaConfig, clientId, username, password)
+	}
 
 	// Create A New Sarama ClusterAdmin
 	clusterAdmin, err := NewClusterAdminWrapper(brokers, saramaConfig)
@@ -103,6 +123,8 @@ func NewKafkaAdminClient(ctx context.Context, saramaConfig *sarama.Config, clien
 		kafkaSecret:  kafkaSecret.Name,
 		clientId:     clientId,
 		clusterAdmin: clusterAdmin,
+		brokers:      brokers,
+		saramaConfig: saramaConfig,
 	}
 
 	// Return The KafkaAdminClient - Success
@@ -115,6 +137,23 @@ var NewClusterAdminWrapper = func(brokers []string, config *sarama.Config) (sara
 	return sarama.NewClusterAdmin(brokers, config)
 }
 
+// The Sarama Client Operations Required To Read The Offsets Of Topics (Interface To Facilitate Unit Testing)
+type OffsetClient interface {
+	Partitions(topic string) ([]int32, error)
+	GetOffset(topic string, partition int32, time int64) (int64, error)
+	Close() error
+}
+
+// Sarama NewClient() Wrapper Function Variable To Facilitate Unit Testing
+var NewOffsetClientWrapper = func(brokers []string, config *sarama.Config) (OffsetClient, error) {
+	return sarama.NewClient(brokers, config)
+}
+
+// Validate The Topic Against The Kafka Topic Constraints
+func (k KafkaAdminClient) Validate(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) error {
+	return adminutil.ValidateTopic(topicName, topicDetail)
+}
+
 // Sarama Pass-Through Function For Creating Topics
 func (k KafkaAdminClient) CreateTopic(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
 	if k.clusterAdmin == nil {
@@ -137,6 +176,184 @@ func (k KafkaAdminClient) DeleteTopic(_ context.Context, topicName string) *sara
 	}
 }
 
+// Sarama Pass-Through Function For Increasing The Partition Count And / Or Altering The Configuration Of Topics
+func (k KafkaAdminClient) AlterTopic(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To Alter Topic Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return adminutil.NewUnknownTopicError("unable to alter topic due to invalid ClusterAdmin - check Kafka authorization secrets")
+	} else if topicDetail == nil {
+		return adminutil.NewTopicError(sarama.ErrInvalidRequest, "received nil topic detail")
+	}
+	if topicDetail.NumPartitions > 0 {
+		err := k.clusterAdmin.CreatePartitions(topicName, topicDetail.NumPartitions, nil, false)
+		if err != nil {
+			return adminutil.PromoteErrorToTopicError(err)
+		}
+	}
+	if len(topicDetail.ConfigEntries) > 0 {
+		err := k.clusterAdmin.AlterConfig(sarama.TopicResource, topicName, topicDetail.ConfigEntries, false)
+		return adminutil.PromoteErrorToTopicError(err)
+	}
+	return nil
+}
+
+// Sarama Pass-Through Function For Listing The Names Of All Topics
+func (k KafkaAdminClient) ListTopics(_ context.Context) ([]string, error) {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To List Topics Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return nil, fmt.Errorf("unable to list topics due to invalid ClusterAdmin - check Kafka authorization secrets")
+	}
+	topicDetails, err := k.clusterAdmin.ListTopics()
+	if err != nil {
+		return nil, err
+	}
+	topicNames := make([]string, 0, len(topicDetails))
+	for topicName := range topicDetails {
+		topicNames = append(topicNames, topicName)
+	}
+	sort.Strings(topicNames)
+	return topicNames, nil
+}
+
+// Sarama Pass-Through Function For Listing The Ids Of All ConsumerGroups
+func (k KafkaAdminClient) ListConsumerGroups(_ context.Context) ([]string, error) {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To List ConsumerGroups Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return nil, fmt.Errorf("unable to list consumer groups due to invalid ClusterAdmin - check Kafka authorization secrets")
+	}
+	consumerGroups, err := k.clusterAdmin.ListConsumerGroups()
+	if err != nil {
+		return nil, err
+	}
+	groupIds := make([]string, 0, len(consumerGroups))
+	for groupId := range consumerGroups {
+		groupIds = append(groupIds, groupId)
+	}
+	sort.Strings(groupIds)
+	return groupIds, nil
+}
+
+// Sarama Pass-Through Function For Deleting ConsumerGroups (Fails With ErrNonEmptyGroup If The Group Has Members)
+func (k KafkaAdminClient) DeleteConsumerGroup(_ context.Context, groupId string) error {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To Delete ConsumerGroup Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return fmt.Errorf("unable to delete consumer group due to invalid ClusterAdmin - check Kafka authorization secrets")
+	}
+	return k.clusterAdmin.DeleteConsumerGroup(groupId)
+}
+
+// Get The Sum Of The Newest Offsets Of All Partitions Of Each Of The Specified Topics (Omitting Unknown Topics)
+func (k KafkaAdminClient) TopicOffsets(_ context.Context, topicNames []string) (map[string]int64, error) {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To Get Topic Offsets Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return nil, fmt.Errorf("unable to get topic offsets due to invalid ClusterAdmin - check Kafka authorization secrets")
+	}
+	client, err := NewOffsetClientWrapper(k.brokers, k.saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+	topicOffsets := make(map[string]int64, len(topicNames))
+	for _, topicName := range topicNames {
+		partitions, err := client.Partitions(topicName)
+		if err == sarama.ErrUnknownTopicOrPartition {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		var sum int64
+		for _, partition := range partitions {
+			offset, err := client.GetOffset(topicName, partition, sarama.OffsetNewest)
+			if err != nil {
+				return nil, err
+			}
+			sum += offset
+		}
+		topicOffsets[topicName] = sum
+	}
+	return topicOffsets, nil
+}
+
+// Get The Committed Offsets Of The Specified ConsumerGroup On The Specified Topics & Its Lag (Omitting Unknown Topics)
+func (k KafkaAdminClient) ConsumerGroupOffsets(_ context.Context, groupId string, topicNames []string) (*ConsumerGroupOffsets, error) {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To Get ConsumerGroup Offsets Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return nil, fmt.Errorf("unable to get consumergroup offsets due to invalid ClusterAdmin - check Kafka authorization secrets")
+	}
+	client, err := NewOffsetClientWrapper(k.brokers, k.saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	// Get The Partitions Of The Known Topics
+	topicPartitions := make(map[string][]int32, len(topicNames))
+	for _, topicName := range topicNames {
+		partitions, err := client.Partitions(topicName)
+		if err == sarama.ErrUnknownTopicOrPartition {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		topicPartitions[topicName] = partitions
+	}
+
+	// Get The Committed Offsets Of The ConsumerGroup & Compare Them To The Newest Offsets
+	consumerGroupOffsets := &ConsumerGroupOffsets{Committed: make(map[string]map[int32]int64, len(topicPartitions))}
+	if len(topicPartitions) == 0 {
+		return consumerGroupOffsets, nil
+	}
+	response, err := k.clusterAdmin.ListConsumerGroupOffsets(groupId, topicPartitions)
+	if err != nil {
+		return nil, err
+	}
+	for topicName, partitions := range topicPartitions {
+		for _, partition := range partitions {
+			block := response.GetBlock(topicName, partition)
+			if block != nil && block.Err != sarama.ErrNoError {
+				return nil, block.Err
+			} else if block == nil || block.Offset < 0 {
+				continue
+			}
+			newestOffset, err := client.GetOffset(topicName, partition, sarama.OffsetNewest)
+			if err != nil {
+				return nil, err
+			}
+			if consumerGroupOffsets.Committed[topicName] == nil {
+				consumerGroupOffsets.Committed[topicName] = make(map[int32]int64, len(partitions))
+			}
+			consumerGroupOffsets.Committed[topicName][partition] = block.Offset
+			if newestOffset > block.Offset {
+				consumerGroupOffsets.Lag += newestOffset - block.Offset
+			}
+		}
+	}
+	return consumerGroupOffsets, nil
+}
+
+// Sarama Pass-Through Function For Describing The State Of The Specified ConsumerGroups
+func (k KafkaAdminClient) ConsumerGroupStates(_ context.Context, groupIds []string) (map[string]string, error) {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To Describe ConsumerGroups Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return nil, fmt.Errorf("unable to describe consumer groups due to invalid ClusterAdmin - check Kafka authorization secrets")
+	}
+	states := make(map[string]string, len(groupIds))
+	if len(groupIds) == 0 {
+		return states, nil
+	}
+	groupDescriptions, err := k.clusterAdmin.DescribeConsumerGroups(groupIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, groupDescription := range groupDescriptions {
+		if groupDescription.Err != sarama.ErrNoError {
+			return nil, groupDescription.Err
+		}
+		states[groupDescription.GroupId] = groupDescription.State
+	}
+	return states, nil
+}
+
 // Sarama Pass-Through Function For Closing ClusterAdmin
 func (k KafkaAdminClient) Close() error {
 	if k.clusterAdmin == nil {
@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
@@ -92,13 +93,73 @@ Metadata:
 	}()
 
 	// Perform The Test
-	adminClient, err := NewKafkaAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, namespace)
+	adminClient, err := NewKafkaAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, namespace, nil)
 
 	// Verify The Results
 	assert.Nil(t, err)
 	assert.NotNil(t, adminClient)
 }
 
+// Test The NewKafkaAdminClient() Constructor - KafkaAuthSpec Path
+func TestNewKafkaAdminClientAuthSpec(t *testing.T) {
+
+	// Test Data
+	clientId := "TestClientId"
+	namespace := "TestNamespace"
+	authSecretName := "TestAuthSecretName"
+	authSpecBrokers := []string{"TestAuthSpecBroker1:9092", "TestAuthSpecBroker2:9092"}
+	authSpecUsername := "TestAuthSpecUsername"
+
+	// Create A Kafka Secret Without Data & A Secret Referenced By The KafkaAuthSpec
+	kafkaSecret := createKafkaSecret("TestKafkaSecretName", namespace, "", "", "")
+	authSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: authSecretName, Namespace: namespace},
+		Data:       map[string][]byte{"user": []byte(authSpecUsername)},
+	}
+	authSpec := &bindingsv1beta1.KafkaAuthSpec{
+		BootstrapServers: authSpecBrokers,
+		Net: bindingsv1beta1.KafkaNetSpec{
+			SASL: bindingsv1beta1.KafkaSASLSpec{
+				Enable: true,
+				User: bindingsv1beta1.SecretValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: authSecretName},
+					Key:                  "user",
+				}},
+			},
+		},
+	}
+
+	// Create A Context With Test Logger & K8S Client
+	ctx := logging.WithLogger(context.TODO(), logtesting.TestLogger(t))
+	ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset(kafkaSecret, authSecret))
+
+	// Mock The Sarama ClusterAdmin Creation For Testing
+	newClusterAdminWrapperPlaceholder := NewClusterAdminWrapper
+	NewClusterAdminWrapper = func(brokers []string, config *sarama.Config) (sarama.ClusterAdmin, error) {
+		assert.Equal(t, authSpecBrokers, brokers)
+		assert.Equal(t, clientId, config.ClientID)
+		assert.True(t, config.Net.SASL.Enable)
+		assert.Equal(t, authSpecUsername, config.Net.SASL.User)
+		return &MockClusterAdmin{}, nil
+	}
+	defer func() {
+		NewClusterAdminWrapper = newClusterAdminWrapperPlaceholder
+	}()
+
+	// Perform The Test
+	adminClient, err := NewKafkaAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, namespace, authSpec)
+
+	// Verify The Results (The Kafka Secret Still Identifies The Receiver)
+	assert.Nil(t, err)
+	assert.NotNil(t, adminClient)
+	assert.Equal(t, kafkaSecret.Name, adminClient.GetKafkaSecretName("TestTopicName"))
+
+	// Verify Unresolvable KafkaAuthSpecs Fail
+	authSpec.Net.SASL.User.SecretKeyRef.Name = "MissingSecretName"
+	_, err = NewKafkaAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, namespace, authSpec)
+	assert.NotNil(t, err)
+}
+
 // Test The NewKafkaAdminClient() Constructor - No Kafka Secrets Path
 func TestNewKafkaAdminClientNoSecrets(t *testing.T) {
 
@@ -111,7 +172,7 @@ func TestNewKafkaAdminClientNoSecrets(t *testing.T) {
 	ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset())
 
 	// Perform The Test
-	adminClient, err := NewKafkaAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, namespace)
+	adminClient, err := NewKafkaAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, namespace, nil)
 
 	// Verify The Results
 	assert.Nil(t, err)
@@ -262,6 +323,231 @@ func TestKafkaAdminClientDeleteTopicInvalidAdminClient(t *testing.T) {
 	assert.Equal(t, errMsg, *resultTopicError.ErrMsg)
 }
 
+// Test The Kafka AdminClient Validate() Functionality
+func TestKafkaAdminClientValidate(t *testing.T) {
+	adminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+	assert.Nil(t, adminClient.Validate(context.TODO(), "TestTopicName", &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 1}))
+	assert.NotNil(t, adminClient.Validate(context.TODO(), "Test/TopicName", &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 1}))
+}
+
+// Test The Kafka AdminClient AlterTopic() Functionality
+func TestKafkaAdminClientAlterTopic(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	topicName := "TestTopicName"
+	errMsg := "test CreatePartitions() failure"
+	retentionMillis := "3600000"
+	configEntries := map[string]*string{"retention.ms": &retentionMillis}
+
+	// Create A Mock Sarama ClusterAdmin To Test Against
+	mockClusterAdmin := &MockClusterAdmin{}
+	mockClusterAdmin.On("CreatePartitions", topicName, int32(8)).Return(nil)
+	mockClusterAdmin.On("CreatePartitions", topicName, int32(2)).Return(&sarama.TopicPartitionError{Err: sarama.ErrInvalidPartitions, ErrMsg: &errMsg})
+	mockClusterAdmin.On("AlterConfig", sarama.TopicResource, topicName, configEntries).Return(nil)
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{
+		logger:       logtesting.TestLogger(t).Desugar(),
+		clusterAdmin: mockClusterAdmin,
+	}
+
+	// Perform The Tests & Verify The Results (Retaining The Kafka Error Of Failures)
+	assert.Nil(t, adminClient.AlterTopic(ctx, topicName, &sarama.TopicDetail{NumPartitions: 8, ConfigEntries: configEntries}))
+	resultTopicError := adminClient.AlterTopic(ctx, topicName, &sarama.TopicDetail{NumPartitions: 2})
+	assert.NotNil(t, resultTopicError)
+	assert.Equal(t, sarama.ErrInvalidPartitions, resultTopicError.Err)
+	assert.Equal(t, sarama.ErrInvalidRequest, adminClient.AlterTopic(ctx, topicName, nil).Err)
+	mockClusterAdmin.AssertExpectations(t)
+
+	// Verify An Invalid ClusterAdmin Fails
+	adminClient.clusterAdmin = nil
+	resultTopicError = adminClient.AlterTopic(ctx, topicName, &sarama.TopicDetail{NumPartitions: 8})
+	assert.NotNil(t, resultTopicError)
+	assert.Equal(t, sarama.ErrUnknown, resultTopicError.Err)
+}
+
+// Test The Kafka AdminClient ListTopics() Functionality
+func TestKafkaAdminClientListTopics(t *testing.T) {
+
+	// Create A Mock Sarama ClusterAdmin To Test Against
+	mockClusterAdmin := &MockClusterAdmin{}
+	mockClusterAdmin.On("ListTopics").Return(map[string]sarama.TopicDetail{"TestTopic2": {}, "TestTopic1": {}}, nil)
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar(), clusterAdmin: mockClusterAdmin}
+
+	// Perform The Test
+	topicNames, err := adminClient.ListTopics(context.TODO())
+
+	// Verify The Results (Sorted)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"TestTopic1", "TestTopic2"}, topicNames)
+	mockClusterAdmin.AssertExpectations(t)
+
+	// Verify The Invalid ClusterAdmin Fails
+	_, err = (&KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar()}).ListTopics(context.TODO())
+	assert.NotNil(t, err)
+}
+
+// Test The Kafka AdminClient ListConsumerGroups() & DeleteConsumerGroup() Functionality
+func TestKafkaAdminClientConsumerGroups(t *testing.T) {
+
+	// Create A Mock Sarama ClusterAdmin To Test Against
+	mockClusterAdmin := &MockClusterAdmin{}
+	mockClusterAdmin.On("ListConsumerGroups").Return(map[string]string{"TestGroup2": "consumer", "TestGroup1": "consumer"}, nil)
+	mockClusterAdmin.On("DeleteConsumerGroup", "TestGroup1").Return(nil)
+	mockClusterAdmin.On("DeleteConsumerGroup", "TestGroup2").Return(sarama.ErrNonEmptyGroup)
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar(), clusterAdmin: mockClusterAdmin}
+
+	// Perform The Tests
+	groupIds, err := adminClient.ListConsumerGroups(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"TestGroup1", "TestGroup2"}, groupIds)
+	assert.Nil(t, adminClient.DeleteConsumerGroup(context.TODO(), "TestGroup1"))
+	assert.Equal(t, sarama.ErrNonEmptyGroup, adminClient.DeleteConsumerGroup(context.TODO(), "TestGroup2"))
+	mockClusterAdmin.AssertExpectations(t)
+
+	// Verify The Invalid ClusterAdmin Fails
+	invalidAdminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+	_, err = invalidAdminClient.ListConsumerGroups(context.TODO())
+	assert.NotNil(t, err)
+	assert.NotNil(t, invalidAdminClient.DeleteConsumerGroup(context.TODO(), "TestGroup1"))
+}
+
+// Test The Kafka AdminClient TopicOffsets() Functionality
+func TestKafkaAdminClientTopicOffsets(t *testing.T) {
+
+	// Test Data
+	brokers := []string{"TestBroker"}
+	saramaConfig := sarama.NewConfig()
+
+	// Create A Mock OffsetClient With One Known Topic Of Two Partitions
+	mockOffsetClient := &MockOffsetClient{}
+	mockOffsetClient.On("Partitions", "TestTopic1").Return([]int32{0, 1}, nil)
+	mockOffsetClient.On("Partitions", "TestTopic2").Return([]int32(nil), sarama.ErrUnknownTopicOrPartition)
+	mockOffsetClient.On("GetOffset", "TestTopic1", int32(0), sarama.OffsetNewest).Return(int64(3), nil)
+	mockOffsetClient.On("GetOffset", "TestTopic1", int32(1), sarama.OffsetNewest).Return(int64(4), nil)
+	mockOffsetClient.On("Close").Return(nil)
+
+	// Replace The NewOffsetClientWrapper To Provide The Mock OffsetClient & Defer Reset
+	newOffsetClientWrapperPlaceholder := NewOffsetClientWrapper
+	NewOffsetClientWrapper = func(brokersArg []string, configArg *sarama.Config) (OffsetClient, error) {
+		assert.Equal(t, brokers, brokersArg)
+		assert.Equal(t, saramaConfig, configArg)
+		return mockOffsetClient, nil
+	}
+	defer func() { NewOffsetClientWrapper = newOffsetClientWrapperPlaceholder }()
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{
+		logger:       logtesting.TestLogger(t).Desugar(),
+		clusterAdmin: &MockClusterAdmin{},
+		brokers:      brokers,
+		saramaConfig: saramaConfig,
+	}
+
+	// Perform The Test & Verify The Unknown Topic Is Omitted
+	topicOffsets, err := adminClient.TopicOffsets(context.TODO(), []string{"TestTopic1", "TestTopic2"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"TestTopic1": 7}, topicOffsets)
+	mockOffsetClient.AssertExpectations(t)
+
+	// Verify The Invalid ClusterAdmin Fails
+	invalidAdminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+	_, err = invalidAdminClient.TopicOffsets(context.TODO(), []string{"TestTopic1"})
+	assert.NotNil(t, err)
+}
+
+// Test The Kafka AdminClient ConsumerGroupOffsets() Functionality
+func TestKafkaAdminClientConsumerGroupOffsets(t *testing.T) {
+
+	// Test Data
+	brokers := []string{"TestBroker"}
+	saramaConfig := sarama.NewConfig()
+	groupId := "TestGroupId"
+
+	// Create A Mock OffsetClient With One Known Topic Of Three Partitions
+	mockOffsetClient := &MockOffsetClient{}
+	mockOffsetClient.On("Partitions", "TestTopic1").Return([]int32{0, 1, 2}, nil)
+	mockOffsetClient.On("Partitions", "TestTopic2").Return([]int32(nil), sarama.ErrUnknownTopicOrPartition)
+	mockOffsetClient.On("GetOffset", "TestTopic1", int32(0), sarama.OffsetNewest).Return(int64(10), nil)
+	mockOffsetClient.On("GetOffset", "TestTopic1", int32(1), sarama.OffsetNewest).Return(int64(20), nil)
+	mockOffsetClient.On("Close").Return(nil)
+
+	// Replace The NewOffsetClientWrapper To Provide The Mock OffsetClient & Defer Reset
+	newOffsetClientWrapperPlaceholder := NewOffsetClientWrapper
+	NewOffsetClientWrapper = func(brokersArg []string, configArg *sarama.Config) (OffsetClient, error) {
+		return mockOffsetClient, nil
+	}
+	defer func() { NewOffsetClientWrapper = newOffsetClientWrapperPlaceholder }()
+
+	// Create A Mock ClusterAdmin With Committed Offsets On Two Of The Partitions
+	offsetFetchResponse := &sarama.OffsetFetchResponse{}
+	offsetFetchResponse.AddBlock("TestTopic1", 0, &sarama.OffsetFetchResponseBlock{Offset: 7, Err: sarama.ErrNoError})
+	offsetFetchResponse.AddBlock("TestTopic1", 1, &sarama.OffsetFetchResponseBlock{Offset: 20, Err: sarama.ErrNoError})
+	offsetFetchResponse.AddBlock("TestTopic1", 2, &sarama.OffsetFetchResponseBlock{Offset: -1, Err: sarama.ErrNoError})
+	mockClusterAdmin := &MockClusterAdmin{}
+	mockClusterAdmin.On("ListConsumerGroupOffsets", groupId, map[string][]int32{"TestTopic1": {0, 1, 2}}).Return(offsetFetchResponse, nil)
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{
+		logger:       logtesting.TestLogger(t).Desugar(),
+		clusterAdmin: mockClusterAdmin,
+		brokers:      brokers,
+		saramaConfig: saramaConfig,
+	}
+
+	// Perform The Test & Verify The Unknown Topic & Uncommitted Partition Are Omitted
+	consumerGroupOffsets, err := adminClient.ConsumerGroupOffsets(context.TODO(), groupId, []string{"TestTopic1", "TestTopic2"})
+	assert.Nil(t, err)
+	assert.Equal(t, &ConsumerGroupOffsets{Committed: map[string]map[int32]int64{"TestTopic1": {0: 7, 1: 20}}, Lag: 3}, consumerGroupOffsets)
+	mockOffsetClient.AssertExpectations(t)
+	mockClusterAdmin.AssertExpectations(t)
+
+	// Verify The Invalid ClusterAdmin Fails
+	invalidAdminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+	_, err = invalidAdminClient.ConsumerGroupOffsets(context.TODO(), groupId, []string{"TestTopic1"})
+	assert.NotNil(t, err)
+}
+
+// Test The Kafka AdminClient ConsumerGroupStates() Functionality
+func TestKafkaAdminClientConsumerGroupStates(t *testing.T) {
+
+	// Create A Mock ClusterAdmin Describing One Empty & One Stable ConsumerGroup
+	mockClusterAdmin := &MockClusterAdmin{}
+	mockClusterAdmin.On("DescribeConsumerGroups", []string{"TestGroup1", "TestGroup2"}).Return([]*sarama.GroupDescription{
+		{GroupId: "TestGroup1", State: "Empty", Err: sarama.ErrNoError},
+		{GroupId: "TestGroup2", State: "Stable", Err: sarama.ErrNoError},
+	}, nil)
+	mockClusterAdmin.On("DescribeConsumerGroups", []string{"TestGroup3"}).Return([]*sarama.GroupDescription{
+		{GroupId: "TestGroup3", Err: sarama.ErrGroupAuthorizationFailed},
+	}, nil)
+	adminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar(), clusterAdmin: mockClusterAdmin}
+
+	// Perform The Test & Verify The States
+	states, err := adminClient.ConsumerGroupStates(context.TODO(), []string{"TestGroup1", "TestGroup2"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"TestGroup1": "Empty", "TestGroup2": "Stable"}, states)
+
+	// Verify No ConsumerGroups Are Described Without Any Group Ids
+	states, err = adminClient.ConsumerGroupStates(context.TODO(), nil)
+	assert.Nil(t, err)
+	assert.Empty(t, states)
+
+	// Verify The Errors Of Group Descriptions Fail
+	_, err = adminClient.ConsumerGroupStates(context.TODO(), []string{"TestGroup3"})
+	assert.Equal(t, sarama.ErrGroupAuthorizationFailed, err)
+	mockClusterAdmin.AssertExpectations(t)
+
+	// Verify The Invalid ClusterAdmin Fails
+	invalidAdminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+	_, err = invalidAdminClient.ConsumerGroupStates(context.TODO(), []string{"TestGroup1"})
+	assert.NotNil(t, err)
+}
+
 // Test The Kafka AdminClient Close() Functionality
 func TestKafkaAdminClientClose(t *testing.T) {
 
@@ -354,6 +640,33 @@ func createKafkaConfig(name string, namespace string, saramaConfig string) *core
 	return commontesting.GetTestSaramaConfigMapNamespaced(name, namespace, saramaConfig, "")
 }
 
+//
+// Mock Sarama OffsetClient
+//
+
+// Verify The Mock OffsetClient Implements The Interface
+var _ OffsetClient = &MockOffsetClient{}
+
+// The Mock OffsetClient
+type MockOffsetClient struct {
+	mock.Mock
+}
+
+func (m *MockOffsetClient) Partitions(topic string) ([]int32, error) {
+	args := m.Called(topic)
+	return args.Get(0).([]int32), args.Error(1)
+}
+
+func (m *MockOffsetClient) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	args := m.Called(topic, partition, time)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOffsetClient) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 //
 // Mock Sarama Kafka ClusterAdmin
 //
@@ -376,7 +689,8 @@ func (m *MockClusterAdmin) CreateTopic(topic string, detail *sarama.TopicDetail,
 }
 
 func (m *MockClusterAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
-	panic("implement me")
+	args := m.Called()
+	return args.Get(0).(map[string]sarama.TopicDetail), args.Error(1)
 }
 
 func (m *MockClusterAdmin) DescribeTopics(topics []string) (metadata []*sarama.TopicMetadata, err error) {
@@ -389,7 +703,8 @@ func (m *MockClusterAdmin) DeleteTopic(topic string) error {
 }
 
 func (m *MockClusterAdmin) CreatePartitions(topic string, count int32, assignment [][]int32, validateOnly bool) error {
-	panic("implement me")
+	args := m.Called(topic, count)
+	return args.Error(0)
 }
 
 func (m *MockClusterAdmin) AlterPartitionReassignments(topic string, assignment [][]int32) error {
@@ -409,7 +724,8 @@ func (m *MockClusterAdmin) DescribeConfig(resource sarama.ConfigResource) ([]sar
 }
 
 func (m *MockClusterAdmin) AlterConfig(resourceType sarama.ConfigResourceType, name string, entries map[string]*string, validateOnly bool) error {
-	panic("implement me")
+	args := m.Called(resourceType, name, entries)
+	return args.Error(0)
 }
 
 func (m *MockClusterAdmin) CreateACL(resource sarama.Resource, acl sarama.Acl) error {
@@ -425,19 +741,23 @@ func (m *MockClusterAdmin) DeleteACL(filter sarama.AclFilter, validateOnly bool)
 }
 
 func (m *MockClusterAdmin) ListConsumerGroups() (map[string]string, error) {
-	panic("implement me")
+	args := m.Called()
+	return args.Get(0).(map[string]string), args.Error(1)
 }
 
 func (m *MockClusterAdmin) DescribeConsumerGroups(groups []string) ([]*sarama.GroupDescription, error) {
-	panic("implement me")
+	args := m.Called(groups)
+	return args.Get(0).([]*sarama.GroupDescription), args.Error(1)
 }
 
 func (m *MockClusterAdmin) ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
-	panic("implement me")
+	args := m.Called(group, topicPartitions)
+	return args.Get(0).(*sarama.OffsetFetchResponse), args.Error(1)
 }
 
 func (m *MockClusterAdmin) DeleteConsumerGroup(group string) error {
-	panic("implement me")
+	args := m.Called(group)
+	return args.Error(0)
 }
 
 func (m *MockClusterAdmin) DescribeCluster() (brokers []*sarama.Broker, controllerID int32, err error) {
@@ -0,0 +1,388 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	adminutil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/util"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/injection/clients/dynamicclient"
+	"knative.dev/pkg/logging"
+)
+
+//
+// Strimzi Kafka AdminClient Implementation (KafkaTopic Custom Resources)
+//
+// Some Kafka clusters forbid the direct creation / deletion of topics via the Kafka Admin API and instead
+// require them to be managed by the Strimzi Topic Operator.  This implementation manages each topic as a
+// KafkaTopic custom resource (labelled with the Strimzi cluster name) in the namespace watched by the Topic
+// Operator, and waits for the operator to report the KafkaTopic as Ready before returning.
+//
+// See the .../common/kafka/README.md for full details.
+//
+
+// Strimzi KafkaTopic Constants
+const (
+	StrimziClusterLabel        = "strimzi.io/cluster"
+	StrimziKafkaTopicKind      = "KafkaTopic"
+	DefaultStrimziReadyTimeout = 30 * time.Second
+)
+
+// The Strimzi KafkaTopic GroupVersionResource
+var StrimziKafkaTopicGVR = schema.GroupVersionResource{Group: "kafka.strimzi.io", Version: "v1beta1", Resource: "kafkatopics"}
+
+// The Interval At Which The KafkaTopic Readiness Is Polled (Variable To Facilitate Unit Testing)
+var strimziReadyPollInterval = time.Second
+
+// Characters Not Permitted In A KafkaTopic Resource Name
+var invalidStrimziNameChars = regexp.MustCompile("[^a-z0-9.-]")
+
+// Ensure The StrimziAdminClient Struct Implements The TopicProvisioner
+var _ TopicProvisioner = &StrimziAdminClient{}
+
+// Strimzi AdminClient Definition
+type StrimziAdminClient struct {
+	logger        *zap.Logger
+	namespace     string
+	kafkaSecret   string
+	cluster       string
+	readyTimeout  time.Duration
+	dynamicClient dynamic.Interface
+}
+
+// Create A New Strimzi AdminClient Based On The Kafka Secret In The Specified K8S Namespace
+func NewStrimziAdminClient(ctx context.Context, namespace string, strimziConfig config.EKStrimziConfig) (TopicProvisioner, error) {
+
+	// Get The Logger From The Context
+	logger := logging.FromContext(ctx).Desugar()
+
+	// The Strimzi Cluster Is Required To Label The KafkaTopics
+	if len(strimziConfig.Cluster) == 0 {
+		return nil, errors.New("the strimzi AdminType requires the kafka.strimzi.cluster setting")
+	}
+
+	// Get The K8S Client From The Context
+	k8sClient := kubeclient.Get(ctx)
+
+	// Get A List Of The Kafka Secrets
+	kafkaSecrets, err := adminutil.GetKafkaSecrets(ctx, k8sClient, namespace)
+	if err != nil {
+		logger.Error("Failed To Get Kafka Authentication Secrets", zap.Error(err))
+		return nil, err
+	}
+
+	// Currently Only Support One Kafka Secret - Invalid AdminClient For All Other Cases!
+	var kafkaSecret corev1.Secret
+	if len(kafkaSecrets.Items) != 1 {
+		logger.Warn(fmt.Sprintf("Expected 1 Kafka Secret But Found %d - Kafka AdminClient Will Not Be Functional!", len(kafkaSecrets.Items)))
+		return nil, nil
+	} else {
+		logger.Info("Found 1 Kafka Secret", zap.String("Secret", kafkaSecrets.Items[0].Name))
+		kafkaSecret = kafkaSecrets.Items[0]
+	}
+
+	// Validate Secret Data
+	if !adminutil.ValidateKafkaSecret(logger, &kafkaSecret) {
+		err = errors.New("invalid Kafka Secret found")
+		return nil, err
+	}
+
+	// The KafkaTopics Are Created In The Kafka Secret's Namespace Unless Otherwise Specified
+	topicNamespace := strimziConfig.Namespace
+	if len(topicNamespace) == 0 {
+		topicNamespace = namespace
+	}
+	readyTimeout := time.Duration(strimziConfig.ReadyTimeoutMillis) * time.Millisecond
+	if readyTimeout <= 0 {
+		readyTimeout = DefaultStrimziReadyTimeout
+	}
+
+	// Create The Strimzi AdminClient
+	strimziAdminClient := &StrimziAdminClient{
+		logger:        logger.With(zap.String("StrimziCluster", strimziConfig.Cluster), zap.String("StrimziNamespace", topicNamespace)),
+		namespace:     topicNamespace,
+		kafkaSecret:   kafkaSecret.Name,
+		cluster:       strimziConfig.Cluster,
+		readyTimeout:  readyTimeout,
+		dynamicClient: dynamicclient.Get(ctx),
+	}
+
+	// Return The Strimzi AdminClient
+	logger.Debug("Successfully Created New Strimzi AdminClient")
+	return strimziAdminClient, nil
+}
+
+// Validate The Topic Against The Kafka Topic Constraints (The KafkaTopic Resource Name Is Derived From Any Valid Name)
+func (s *StrimziAdminClient) Validate(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) error {
+	return adminutil.ValidateTopic(topicName, topicDetail)
+}
+
+// Create (Or Update An Existing) KafkaTopic & Wait For The Strimzi Topic Operator To Report It Ready
+func (s *StrimziAdminClient) CreateTopic(ctx context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
+
+	// Create An Updated Logger With TopicName
+	logger := s.logger.With(zap.String("TopicName", topicName))
+
+	// Validate Topic
+	if len(topicName) <= 0 || topicDetail == nil {
+		logger.Warn("Received Empty/Nil Topic Configuration", zap.Any("TopicDetail", topicDetail))
+		return adminutil.NewTopicError(sarama.ErrInvalidRequest, "received empty/nil topic name and / or detail")
+	}
+
+	// Attempt To Create The KafkaTopic
+	kafkaTopic := s.newKafkaTopic(topicName, topicDetail)
+	_, err := s.kafkaTopics().Create(ctx, kafkaTopic, metav1.CreateOptions{})
+	if err == nil {
+		logger.Info("Created New Strimzi KafkaTopic", zap.String("KafkaTopic", kafkaTopic.GetName()))
+		return s.waitForReady(ctx, kafkaTopic.GetName(), sarama.ErrNoError)
+	} else if !k8serrors.IsAlreadyExists(err) {
+		logger.Error("Failed To Create Strimzi KafkaTopic", zap.Error(err))
+		return adminutil.NewUnknownTopicError(fmt.Sprintf("failed to create strimzi KafkaTopic for topic '%s': %v", topicName, err))
+	}
+
+	// The KafkaTopic Already Exists - Update Its Partitions (Never Decreased) & Config To Match The TopicDetail
+	topicErr := s.updateKafkaTopic(ctx, kafkaTopic.GetName(), func(existing *unstructured.Unstructured) *sarama.TopicError {
+		if partitions, _, _ := unstructured.NestedInt64(existing.Object, "spec", "partitions"); int64(topicDetail.NumPartitions) > partitions {
+			if topicErr := raiseKafkaTopicPartitions(existing, topicDetail.NumPartitions); topicErr != nil {
+				return topicErr
+			}
+		}
+		return setKafkaTopicConfig(existing, topicDetail.ConfigEntries)
+	})
+	if topicErr != nil {
+		return topicErr
+	}
+	return s.waitForReady(ctx, kafkaTopic.GetName(), sarama.ErrTopicAlreadyExists)
+}
+
+// Delete The Topic's KafkaTopic (The Strimzi Topic Operator Deletes The Kafka Topic)
+func (s *StrimziAdminClient) DeleteTopic(ctx context.Context, topicName string) *sarama.TopicError {
+
+	// Validate The Topic
+	if len(topicName) <= 0 {
+		s.logger.Warn("Received Empty/Nil Topic Configuration")
+		return adminutil.NewTopicError(sarama.ErrInvalidRequest, "received empty/nil topic name")
+	}
+
+	// Delete The KafkaTopic, Mapping NotFound To The Equivalent Kafka Error
+	err := s.kafkaTopics().Delete(ctx, StrimziKafkaTopicName(topicName), metav1.DeleteOptions{})
+	switch {
+	case err == nil:
+		return adminutil.NewTopicError(sarama.ErrNoError, fmt.Sprintf("deleted strimzi KafkaTopic for topic '%s'", topicName))
+	case k8serrors.IsNotFound(err):
+		return adminutil.NewTopicError(sarama.ErrUnknownTopicOrPartition, fmt.Sprintf("strimzi KafkaTopic for topic '%s' not found", topicName))
+	default:
+		s.logger.Error("Failed To Delete Strimzi KafkaTopic", zap.String("TopicName", topicName), zap.Error(err))
+		return adminutil.NewUnknownTopicError(fmt.Sprintf("failed to delete strimzi KafkaTopic for topic '%s': %v", topicName, err))
+	}
+}
+
+// Increase The Partitions And / Or Set The Config Of The Topic's KafkaTopic & Wait For The Strimzi Topic Operator To Report It Ready
+func (s *StrimziAdminClient) AlterTopic(ctx context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
+	if topicDetail == nil {
+		return adminutil.NewTopicError(sarama.ErrInvalidRequest, "received nil topic detail")
+	}
+	name := StrimziKafkaTopicName(topicName)
+	topicErr := s.updateKafkaTopic(ctx, name, func(existing *unstructured.Unstructured) *sarama.TopicError {
+		if topicDetail.NumPartitions > 0 {
+			if topicErr := raiseKafkaTopicPartitions(existing, topicDetail.NumPartitions); topicErr != nil {
+				return topicErr
+			}
+		}
+		return setKafkaTopicConfig(existing, topicDetail.ConfigEntries)
+	})
+	if topicErr != nil {
+		return topicErr
+	}
+	return s.waitForReady(ctx, name, sarama.ErrNoError)
+}
+
+// Close The Strimzi AdminClient
+func (s *StrimziAdminClient) Close() error {
+	return nil // Nothing to "close" in the Strimzi implementation (just a K8S client) so this is just a compatibility no-op.
+}
+
+// Get The K8S Secret With Kafka Credentials For The Specified Topic Name
+func (s *StrimziAdminClient) GetKafkaSecretName(_ string) string {
+	return s.kafkaSecret
+}
+
+// Get The Strimzi KafkaTopic Client For The AdminClient's Namespace
+func (s *StrimziAdminClient) kafkaTopics() dynamic.ResourceInterface {
+	return s.dynamicClient.Resource(StrimziKafkaTopicGVR).Namespace(s.namespace)
+}
+
+// Create A New KafkaTopic For The Specified Topic Name & Detail
+func (s *StrimziAdminClient) newKafkaTopic(topicName string, topicDetail *sarama.TopicDetail) *unstructured.Unstructured {
+	topicConfig := make(map[string]interface{}, len(topicDetail.ConfigEntries))
+	for key, value := range topicDetail.ConfigEntries {
+		if value != nil {
+			topicConfig[key] = *value
+		}
+	}
+	kafkaTopic := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"topicName":  topicName,
+			"partitions": int64(topicDetail.NumPartitions),
+			"replicas":   int64(topicDetail.ReplicationFactor),
+			"config":     topicConfig,
+		},
+	}}
+	kafkaTopic.SetAPIVersion(StrimziKafkaTopicGVR.GroupVersion().String())
+	kafkaTopic.SetKind(StrimziKafkaTopicKind)
+	kafkaTopic.SetNamespace(s.namespace)
+	kafkaTopic.SetName(StrimziKafkaTopicName(topicName))
+	kafkaTopic.SetLabels(map[string]string{StrimziClusterLabel: s.cluster})
+	return kafkaTopic
+}
+
+// Get, Modify & Update The Specified KafkaTopic
+func (s *StrimziAdminClient) updateKafkaTopic(ctx context.Context, name string, modify func(*unstructured.Unstructured) *sarama.TopicError) *sarama.TopicError {
+	logger := s.logger.With(zap.String("KafkaTopic", name))
+	existing, err := s.kafkaTopics().Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return adminutil.NewTopicError(sarama.ErrUnknownTopicOrPartition, fmt.Sprintf("strimzi KafkaTopic '%s' not found", name))
+	} else if err != nil {
+		logger.Error("Failed To Get Strimzi KafkaTopic", zap.Error(err))
+		return adminutil.NewUnknownTopicError(fmt.Sprintf("failed to get strimzi KafkaTopic '%s': %v", name, err))
+	}
+	if topicErr := modify(existing); topicErr != nil {
+		logger.Warn("Invalid Strimzi KafkaTopic Update", zap.Any("TopicError", topicErr))
+		return topicErr
+	}
+	if _, err = s.kafkaTopics().Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		logger.Error("Failed To Update Strimzi KafkaTopic", zap.Error(err))
+		return adminutil.NewUnknownTopicError(fmt.Sprintf("failed to update strimzi KafkaTopic '%s': %v", name, err))
+	}
+	logger.Info("Updated Strimzi KafkaTopic")
+	return nil
+}
+
+// Wait For The Strimzi Topic Operator To Report The Specified KafkaTopic Ready (Returning The Specified Success Error)
+func (s *StrimziAdminClient) waitForReady(ctx context.Context, name string, success sarama.KError) *sarama.TopicError {
+	var notReadyMessage string
+	err := wait.PollImmediate(strimziReadyPollInterval, s.readyTimeout, func() (bool, error) {
+		kafkaTopic, err := s.kafkaTopics().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		var ready bool
+		ready, notReadyMessage = kafkaTopicReady(kafkaTopic)
+		return ready, nil
+	})
+	switch {
+	case err == nil:
+		return adminutil.NewTopicError(success, fmt.Sprintf("strimzi KafkaTopic '%s' is ready", name))
+	case err == wait.ErrWaitTimeout:
+		s.logger.Warn("Timed Out Waiting For Strimzi KafkaTopic To Become Ready", zap.String("KafkaTopic", name), zap.String("Message", notReadyMessage))
+		return adminutil.NewTopicError(sarama.ErrRequestTimedOut, fmt.Sprintf("timed out waiting for strimzi KafkaTopic '%s' to become ready: %s", name, notReadyMessage))
+	default:
+		s.logger.Error("Failed To Get Strimzi KafkaTopic", zap.String("KafkaTopic", name), zap.Error(err))
+		return adminutil.NewUnknownTopicError(fmt.Sprintf("failed to get strimzi KafkaTopic '%s': %v", name, err))
+	}
+}
+
+// Determine Whether The Strimzi Topic Operator Has Reconciled The KafkaTopic's Current Generation (& Why Not If Not)
+func kafkaTopicReady(kafkaTopic *unstructured.Unstructured) (bool, string) {
+	observedGeneration, _, _ := unstructured.NestedInt64(kafkaTopic.Object, "status", "observedGeneration")
+	if observedGeneration < kafkaTopic.GetGeneration() {
+		return false, "the current generation has not been observed"
+	}
+	conditions, _, _ := unstructured.NestedSlice(kafkaTopic.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok || conditionMap["type"] != "Ready" {
+			continue
+		}
+		if conditionMap["status"] == "True" {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%v: %v", conditionMap["reason"], conditionMap["message"])
+	}
+	return false, "no Ready condition"
+}
+
+// Raise The KafkaTopic's Partitions To The Specified Count (Like Kafka, Failing With ErrInvalidPartitions Unless Increased)
+func raiseKafkaTopicPartitions(kafkaTopic *unstructured.Unstructured, count int32) *sarama.TopicError {
+	partitions, _, _ := unstructured.NestedInt64(kafkaTopic.Object, "spec", "partitions")
+	if int64(count) <= partitions {
+		return adminutil.NewTopicError(sarama.ErrInvalidPartitions, fmt.Sprintf("strimzi KafkaTopic '%s' already has %d partitions", kafkaTopic.GetName(), partitions))
+	}
+	return setKafkaTopicField(kafkaTopic, int64(count), "spec", "partitions")
+}
+
+// Set The Specified Config Entries Of The KafkaTopic (Retaining Any Others)
+func setKafkaTopicConfig(kafkaTopic *unstructured.Unstructured, configEntries map[string]*string) *sarama.TopicError {
+	for key, value := range configEntries {
+		if value != nil {
+			if topicErr := setKafkaTopicField(kafkaTopic, *value, "spec", "config", key); topicErr != nil {
+				return topicErr
+			}
+		}
+	}
+	return nil
+}
+
+// Set The Specified Field Of The KafkaTopic (Failing If The Existing Resource Has An Unexpected Structure)
+func setKafkaTopicField(kafkaTopic *unstructured.Unstructured, value interface{}, fields ...string) *sarama.TopicError {
+	if err := unstructured.SetNestedField(kafkaTopic.Object, value, fields...); err != nil {
+		return adminutil.NewTopicError(sarama.ErrInvalidRequest, fmt.Sprintf("invalid strimzi KafkaTopic '%s': %v", kafkaTopic.GetName(), err))
+	}
+	return nil
+}
+
+//
+// Get The Name Of The KafkaTopic Resource Of The Specified Topic
+//
+// Kafka topic names may contain characters (uppercase, underscores) which are not valid in Kubernetes resource
+// names, in which case the name is sanitized and suffixed with a hash of the topic name to remain unique.  The
+// actual topic name is always specified in the KafkaTopic's spec.topicName.
+//
+func StrimziKafkaTopicName(topicName string) string {
+	if len(validation.IsDNS1123Subdomain(topicName)) == 0 {
+		return topicName
+	}
+	hash := sha1.Sum([]byte(topicName))
+	suffix := hex.EncodeToString(hash[:])[:8]
+	name := strings.Trim(invalidStrimziNameChars.ReplaceAllString(strings.ToLower(topicName), "-"), ".-")
+	if maxLength := validation.DNS1123SubdomainMaxLength - len(suffix) - 1; len(name) > maxLength {
+		name = strings.TrimRight(name[:maxLength], ".-")
+	}
+	if len(name) == 0 {
+		return suffix
+	}
+	return name + "-" + suffix
+}
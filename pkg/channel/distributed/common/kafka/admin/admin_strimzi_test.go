@@ -0,0 +1,352 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	injectionclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/injection/clients/dynamicclient"
+	"knative.dev/pkg/logging"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewStrimziAdminClient() Functionality
+func TestNewStrimziAdminClient(t *testing.T) {
+
+	// Test Data
+	namespace := "TestNamespace"
+	kafkaSecretName := "TestKafkaSecretName"
+	kafkaSecret := createKafkaSecret(kafkaSecretName, namespace, "TestBrokers", "TestUsername", "TestPassword")
+	invalidKafkaSecret := createKafkaSecret(kafkaSecretName, namespace, "", "", "")
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name              string
+		strimziConfig     config.EKStrimziConfig
+		secrets           []runtime.Object
+		expectNil         bool
+		expectErr         bool
+		expectNamespace   string
+		expectReadyTimout time.Duration
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name:              "Defaults",
+			strimziConfig:     config.EKStrimziConfig{Cluster: "my-cluster"},
+			secrets:           []runtime.Object{kafkaSecret},
+			expectNamespace:   namespace,
+			expectReadyTimout: DefaultStrimziReadyTimeout,
+		},
+		{
+			name:              "Namespace & Timeout",
+			strimziConfig:     config.EKStrimziConfig{Cluster: "my-cluster", Namespace: "kafka", ReadyTimeoutMillis: 500},
+			secrets:           []runtime.Object{kafkaSecret},
+			expectNamespace:   "kafka",
+			expectReadyTimout: 500 * time.Millisecond,
+		},
+		{
+			name:      "No Cluster",
+			secrets:   []runtime.Object{kafkaSecret},
+			expectNil: true,
+			expectErr: true,
+		},
+		{
+			name:          "No Kafka Secret",
+			strimziConfig: config.EKStrimziConfig{Cluster: "my-cluster"},
+			expectNil:     true,
+		},
+		{
+			name:          "Invalid Kafka Secret",
+			strimziConfig: config.EKStrimziConfig{Cluster: "my-cluster"},
+			secrets:       []runtime.Object{invalidKafkaSecret},
+			expectNil:     true,
+			expectErr:     true,
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Context With Test Logger, K8S Client & Dynamic Client
+			ctx := logging.WithLogger(context.TODO(), logtesting.TestLogger(t))
+			ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset(testCase.secrets...))
+			ctx = context.WithValue(ctx, dynamicclient.Key{}, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+
+			// Perform The Test
+			adminClient, err := NewStrimziAdminClient(ctx, namespace, testCase.strimziConfig)
+
+			// Verify The Results
+			assert.Equal(t, testCase.expectErr, err != nil)
+			if testCase.expectNil {
+				assert.Nil(t, adminClient)
+			} else {
+				strimziAdminClient, ok := adminClient.(*StrimziAdminClient)
+				assert.True(t, ok)
+				assert.Equal(t, testCase.expectNamespace, strimziAdminClient.namespace)
+				assert.Equal(t, kafkaSecretName, strimziAdminClient.kafkaSecret)
+				assert.Equal(t, kafkaSecretName, strimziAdminClient.GetKafkaSecretName("AnyTopic"))
+				assert.Equal(t, testCase.strimziConfig.Cluster, strimziAdminClient.cluster)
+				assert.Equal(t, testCase.expectReadyTimout, strimziAdminClient.readyTimeout)
+				assert.Nil(t, strimziAdminClient.Close())
+			}
+		})
+	}
+}
+
+// Test The Strimzi AdminClient CreateTopic() Functionality
+func TestStrimziAdminClientCreateTopic(t *testing.T) {
+
+	// Test Data
+	topicName := "TestTopic"
+	retentionMillis := "3600000"
+	cleanupPolicy := "compact"
+	topicDetail := &sarama.TopicDetail{
+		NumPartitions:     4,
+		ReplicationFactor: 3,
+		ConfigEntries:     map[string]*string{"retention.ms": &retentionMillis},
+	}
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name             string
+		topicName        string
+		topicDetail      *sarama.TopicDetail
+		existing         *unstructured.Unstructured
+		operatorStatus   map[string]interface{}
+		expectErr        sarama.KError
+		expectPartitions int64
+		expectConfig     map[string]interface{}
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name:             "Create Ready",
+			topicName:        topicName,
+			topicDetail:      topicDetail,
+			operatorStatus:   strimziStatus("True", ""),
+			expectErr:        sarama.ErrNoError,
+			expectPartitions: 4,
+			expectConfig:     map[string]interface{}{"retention.ms": retentionMillis},
+		},
+		{
+			name:             "Update Existing",
+			topicName:        topicName,
+			topicDetail:      topicDetail,
+			existing:         strimziKafkaTopic(topicName, 2, map[string]interface{}{"cleanup.policy": cleanupPolicy}),
+			operatorStatus:   strimziStatus("True", ""),
+			expectErr:        sarama.ErrTopicAlreadyExists,
+			expectPartitions: 4,
+			expectConfig:     map[string]interface{}{"retention.ms": retentionMillis, "cleanup.policy": cleanupPolicy},
+		},
+		{
+			name:             "Update Existing With More Partitions",
+			topicName:        topicName,
+			topicDetail:      topicDetail,
+			existing:         strimziKafkaTopic(topicName, 8, nil),
+			operatorStatus:   strimziStatus("True", ""),
+			expectErr:        sarama.ErrTopicAlreadyExists,
+			expectPartitions: 8,
+			expectConfig:     map[string]interface{}{"retention.ms": retentionMillis},
+		},
+		{
+			name:             "Not Ready",
+			topicName:        topicName,
+			topicDetail:      topicDetail,
+			operatorStatus:   strimziStatus("False", "invalid config"),
+			expectErr:        sarama.ErrRequestTimedOut,
+			expectPartitions: 4,
+			expectConfig:     map[string]interface{}{"retention.ms": retentionMillis},
+		},
+		{
+			name:             "No Operator",
+			topicName:        topicName,
+			topicDetail:      topicDetail,
+			expectErr:        sarama.ErrRequestTimedOut,
+			expectPartitions: 4,
+			expectConfig:     map[string]interface{}{"retention.ms": retentionMillis},
+		},
+		{
+			name:        "Empty Topic Name",
+			topicDetail: topicDetail,
+			expectErr:   sarama.ErrInvalidRequest,
+		},
+		{
+			name:      "Nil Topic Detail",
+			topicName: topicName,
+			expectErr: sarama.ErrInvalidRequest,
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Strimzi AdminClient With A Fake Dynamic Client "Operator"
+			adminClient, dynamicClient := newTestStrimziAdminClient(t, testCase.operatorStatus, testCase.existing)
+
+			// Perform The Test
+			topicErr := adminClient.CreateTopic(context.TODO(), testCase.topicName, testCase.topicDetail)
+
+			// Verify The Results
+			assert.NotNil(t, topicErr)
+			assert.Equal(t, testCase.expectErr, topicErr.Err)
+			kafkaTopic, err := dynamicClient.Resource(StrimziKafkaTopicGVR).Namespace(adminClient.namespace).Get(context.TODO(), StrimziKafkaTopicName(testCase.topicName), metav1.GetOptions{})
+			if testCase.expectPartitions == 0 {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, "my-cluster", kafkaTopic.GetLabels()[StrimziClusterLabel])
+			partitions, _, _ := unstructured.NestedInt64(kafkaTopic.Object, "spec", "partitions")
+			assert.Equal(t, testCase.expectPartitions, partitions)
+			if testCase.expectConfig != nil {
+				specTopicName, _, _ := unstructured.NestedString(kafkaTopic.Object, "spec", "topicName")
+				assert.Equal(t, testCase.topicName, specTopicName)
+				topicConfig, _, _ := unstructured.NestedMap(kafkaTopic.Object, "spec", "config")
+				assert.Equal(t, testCase.expectConfig, topicConfig)
+			}
+		})
+	}
+}
+
+// Test The Strimzi AdminClient DeleteTopic() Functionality
+func TestStrimziAdminClientDeleteTopic(t *testing.T) {
+	topicName := "TestTopic"
+	adminClient, dynamicClient := newTestStrimziAdminClient(t, nil, strimziKafkaTopic(topicName, 4, nil))
+	assert.Equal(t, sarama.ErrInvalidRequest, adminClient.DeleteTopic(context.TODO(), "").Err)
+	assert.Equal(t, sarama.ErrNoError, adminClient.DeleteTopic(context.TODO(), topicName).Err)
+	_, err := dynamicClient.Resource(StrimziKafkaTopicGVR).Namespace(adminClient.namespace).Get(context.TODO(), StrimziKafkaTopicName(topicName), metav1.GetOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, sarama.ErrUnknownTopicOrPartition, adminClient.DeleteTopic(context.TODO(), topicName).Err)
+}
+
+// Test The Strimzi AdminClient Validate() Functionality
+func TestStrimziAdminClientValidate(t *testing.T) {
+	adminClient, _ := newTestStrimziAdminClient(t, nil, nil)
+	assert.Nil(t, adminClient.Validate(context.TODO(), "Test_Topic", &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 1}))
+	assert.NotNil(t, adminClient.Validate(context.TODO(), "Test/Topic", &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 1}))
+}
+
+// Test The Strimzi AdminClient AlterTopic() Functionality
+func TestStrimziAdminClientAlterTopic(t *testing.T) {
+	topicName := "TestTopic"
+	retentionMillis := "3600000"
+	adminClient, dynamicClient := newTestStrimziAdminClient(t, strimziStatus("True", ""), strimziKafkaTopic(topicName, 4, nil))
+	assert.Equal(t, sarama.ErrUnknownTopicOrPartition, adminClient.AlterTopic(context.TODO(), "UnknownTopic", &sarama.TopicDetail{NumPartitions: 8}).Err)
+	assert.Equal(t, sarama.ErrInvalidPartitions, adminClient.AlterTopic(context.TODO(), topicName, &sarama.TopicDetail{NumPartitions: 4}).Err)
+	assert.Equal(t, sarama.ErrInvalidRequest, adminClient.AlterTopic(context.TODO(), topicName, nil).Err)
+	assert.Equal(t, sarama.ErrNoError, adminClient.AlterTopic(context.TODO(), topicName, &sarama.TopicDetail{NumPartitions: 8}).Err)
+	assert.Equal(t, sarama.ErrNoError, adminClient.AlterTopic(context.TODO(), topicName, &sarama.TopicDetail{ConfigEntries: map[string]*string{"retention.ms": &retentionMillis}}).Err)
+	kafkaTopic, err := dynamicClient.Resource(StrimziKafkaTopicGVR).Namespace(adminClient.namespace).Get(context.TODO(), StrimziKafkaTopicName(topicName), metav1.GetOptions{})
+	assert.Nil(t, err)
+	partitions, _, _ := unstructured.NestedInt64(kafkaTopic.Object, "spec", "partitions")
+	assert.Equal(t, int64(8), partitions)
+	retention, _, _ := unstructured.NestedString(kafkaTopic.Object, "spec", "config", "retention.ms")
+	assert.Equal(t, retentionMillis, retention)
+}
+
+// Test The StrimziKafkaTopicName() Functionality
+func TestStrimziKafkaTopicName(t *testing.T) {
+	assert.Equal(t, "my-namespace.my-channel", StrimziKafkaTopicName("my-namespace.my-channel"))
+	for _, topicName := range []string{"TestTopic", "test_topic", "_", strings.Repeat("a", 300)} {
+		name := StrimziKafkaTopicName(topicName)
+		assert.Empty(t, validation.IsDNS1123Subdomain(name), name)
+		assert.Equal(t, name, StrimziKafkaTopicName(topicName))
+	}
+	assert.NotEqual(t, StrimziKafkaTopicName("test_topic"), StrimziKafkaTopicName("test.topic_"))
+}
+
+// Utility Function For Creating A StrimziAdminClient Whose Fake Dynamic Client Sets The Specified Operator Status On Every Create / Update
+func newTestStrimziAdminClient(t *testing.T, operatorStatus map[string]interface{}, existing *unstructured.Unstructured) (*StrimziAdminClient, *dynamicfake.FakeDynamicClient) {
+	strimziReadyPollInterval = time.Millisecond
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	if existing != nil {
+		_, err := dynamicClient.Resource(StrimziKafkaTopicGVR).Namespace("kafka").Create(context.TODO(), existing, metav1.CreateOptions{})
+		assert.Nil(t, err)
+	}
+	if operatorStatus != nil {
+		operatorReactor := func(action clientgotesting.Action) (bool, runtime.Object, error) {
+			var object runtime.Object
+			switch typedAction := action.(type) {
+			case clientgotesting.CreateAction:
+				object = typedAction.GetObject()
+			case clientgotesting.UpdateAction:
+				object = typedAction.GetObject()
+			}
+			object.(*unstructured.Unstructured).Object["status"] = runtime.DeepCopyJSONValue(operatorStatus)
+			return false, nil, nil
+		}
+		dynamicClient.PrependReactor("create", "kafkatopics", operatorReactor)
+		dynamicClient.PrependReactor("update", "kafkatopics", operatorReactor)
+	}
+	adminClient := &StrimziAdminClient{
+		logger:        logtesting.TestLogger(t).Desugar(),
+		namespace:     "kafka",
+		kafkaSecret:   "TestKafkaSecretName",
+		cluster:       "my-cluster",
+		readyTimeout:  50 * time.Millisecond,
+		dynamicClient: dynamicClient,
+	}
+	return adminClient, dynamicClient
+}
+
+// Utility Function For Creating A Strimzi KafkaTopic Status With The Specified Ready Condition
+func strimziStatus(ready string, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": ready, "reason": "TestReason", "message": message},
+		},
+	}
+}
+
+// Utility Function For Creating An Existing Strimzi KafkaTopic
+func strimziKafkaTopic(topicName string, partitions int64, topicConfig map[string]interface{}) *unstructured.Unstructured {
+	kafkaTopic := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"topicName":  topicName,
+			"partitions": partitions,
+			"replicas":   int64(3),
+		},
+	}}
+	if topicConfig != nil {
+		_ = unstructured.SetNestedMap(kafkaTopic.Object, topicConfig, "spec", "config")
+	}
+	kafkaTopic.SetAPIVersion(StrimziKafkaTopicGVR.GroupVersion().String())
+	kafkaTopic.SetKind(StrimziKafkaTopicKind)
+	kafkaTopic.SetNamespace("kafka")
+	kafkaTopic.SetName(StrimziKafkaTopicName(topicName))
+	kafkaTopic.SetLabels(map[string]string{StrimziClusterLabel: "my-cluster"})
+	return kafkaTopic
+}
@@ -22,12 +22,14 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/stretchr/testify/assert"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
 	commontesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
 )
 
 // Mock AdminClient Reference
-var mockAdminClient AdminClientInterface
+var mockAdminClient TopicProvisioner
 
 // Test The CreateAdminClient() Kafka Functionality
 func TestCreateAdminClientKafka(t *testing.T) {
@@ -35,22 +37,21 @@ func TestCreateAdminClientKafka(t *testing.T) {
 	// Test Data
 	ctx := context.TODO()
 	clientId := "TestClientId"
-	adminClientType := Kafka
+	kafkaConfig := config.EKKafkaConfig{AdminType: "kafka"}
 	mockAdminClient = &MockAdminClient{}
 
 	// Replace the NewKafkaAdminClientWrapper To Provide Mock AdminClient & Defer Reset
 	NewKafkaAdminClientWrapperRef := NewKafkaAdminClientWrapper
-	NewKafkaAdminClientWrapper = func(ctxArg context.Context, saramaConfig *sarama.Config, clientIdArg string, namespaceArg string) (AdminClientInterface, error) {
+	NewKafkaAdminClientWrapper = func(ctxArg context.Context, saramaConfig *sarama.Config, clientIdArg string, namespaceArg string, authSpec *bindingsv1beta1.KafkaAuthSpec) (TopicProvisioner, error) {
 		assert.Equal(t, ctx, ctxArg)
 		assert.Equal(t, clientId, clientIdArg)
 		assert.Equal(t, constants.KnativeEventingNamespace, namespaceArg)
-		assert.Equal(t, adminClientType, adminClientType)
 		return mockAdminClient, nil
 	}
 	defer func() { NewKafkaAdminClientWrapper = NewKafkaAdminClientWrapperRef }()
 
 	// Perform The Test
-	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, adminClientType)
+	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, kafkaConfig)
 
 	// Verify The Results
 	assert.Nil(t, err)
@@ -64,21 +65,20 @@ func TestCreateAdminClientEventHub(t *testing.T) {
 	// Test Data
 	ctx := context.TODO()
 	clientId := "TestClientId"
-	adminClientType := EventHub
+	kafkaConfig := config.EKKafkaConfig{AdminType: "azure"}
 	mockAdminClient = &MockAdminClient{}
 
 	// Replace the NewEventHubAdminClientWrapper To Provide Mock AdminClient & Defer Reset
 	NewEventHubAdminClientWrapperRef := NewEventHubAdminClientWrapper
-	NewEventHubAdminClientWrapper = func(ctxArg context.Context, namespaceArg string) (AdminClientInterface, error) {
+	NewEventHubAdminClientWrapper = func(ctxArg context.Context, namespaceArg string) (TopicProvisioner, error) {
 		assert.Equal(t, ctx, ctxArg)
 		assert.Equal(t, constants.KnativeEventingNamespace, namespaceArg)
-		assert.Equal(t, adminClientType, adminClientType)
 		return mockAdminClient, nil
 	}
 	defer func() { NewEventHubAdminClientWrapper = NewEventHubAdminClientWrapperRef }()
 
 	// Perform The Test
-	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, adminClientType)
+	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, kafkaConfig)
 
 	// Verify The Results
 	assert.Nil(t, err)
@@ -92,21 +92,20 @@ func TestCreateAdminClientCustom(t *testing.T) {
 	// Test Data
 	ctx := context.TODO()
 	clientId := "TestClientId"
-	adminClientType := Custom
+	kafkaConfig := config.EKKafkaConfig{AdminType: "Custom"}
 	mockAdminClient = &MockAdminClient{}
 
 	// Replace the NewPluginAdminClientWrapper To Provide Mock AdminClient & Defer Reset
 	NewCustomAdminClientWrapperRef := NewCustomAdminClientWrapper
-	NewCustomAdminClientWrapper = func(ctxArg context.Context, namespaceArg string) (AdminClientInterface, error) {
+	NewCustomAdminClientWrapper = func(ctxArg context.Context, namespaceArg string) (TopicProvisioner, error) {
 		assert.Equal(t, ctx, ctxArg)
 		assert.Equal(t, constants.KnativeEventingNamespace, namespaceArg)
-		assert.Equal(t, adminClientType, adminClientType)
 		return mockAdminClient, nil
 	}
 	defer func() { NewCustomAdminClientWrapper = NewCustomAdminClientWrapperRef }()
 
 	// Perform The Test
-	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, adminClientType)
+	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, kafkaConfig)
 
 	// Verify The Results
 	assert.Nil(t, err)
@@ -114,16 +113,45 @@ func TestCreateAdminClientCustom(t *testing.T) {
 	assert.Equal(t, mockAdminClient, adminClient)
 }
 
-// Test The CreateAdminClient Custom Functionality
+// Test The CreateAdminClient Strimzi Functionality
+func TestCreateAdminClientStrimzi(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	clientId := "TestClientId"
+	strimziConfig := config.EKStrimziConfig{Cluster: "TestCluster"}
+	kafkaConfig := config.EKKafkaConfig{AdminType: "strimzi", Strimzi: strimziConfig}
+	mockAdminClient = &MockAdminClient{}
+
+	// Replace the NewStrimziAdminClientWrapper To Provide Mock AdminClient & Defer Reset
+	NewStrimziAdminClientWrapperRef := NewStrimziAdminClientWrapper
+	NewStrimziAdminClientWrapper = func(ctxArg context.Context, namespaceArg string, strimziConfigArg config.EKStrimziConfig) (TopicProvisioner, error) {
+		assert.Equal(t, ctx, ctxArg)
+		assert.Equal(t, constants.KnativeEventingNamespace, namespaceArg)
+		assert.Equal(t, strimziConfig, strimziConfigArg)
+		return mockAdminClient, nil
+	}
+	defer func() { NewStrimziAdminClientWrapper = NewStrimziAdminClientWrapperRef }()
+
+	// Perform The Test
+	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, kafkaConfig)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.NotNil(t, adminClient)
+	assert.Equal(t, mockAdminClient, adminClient)
+}
+
+// Test The CreateAdminClient Unknown Functionality
 func TestCreateAdminClientUnknown(t *testing.T) {
 
 	// Test Data
 	ctx := context.TODO()
 	clientId := "TestClientId"
-	adminClientType := Unknown
+	kafkaConfig := config.EKKafkaConfig{AdminType: "unknown"}
 
 	// Perform The Test
-	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, adminClientType)
+	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, kafkaConfig)
 
 	// Verify The Results
 	assert.NotNil(t, err)
@@ -131,11 +159,45 @@ func TestCreateAdminClientUnknown(t *testing.T) {
 
 }
 
+// Test The CreateAdminClient Functionality With A Registered (Out-Of-Tree) TopicProvisioner
+func TestCreateAdminClientRegistered(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	clientId := "TestClientId"
+	kafkaConfig := config.EKKafkaConfig{AdminType: "TestProvisioner", ProvisionerConfig: map[string]string{"key": "value"}}
+	mockAdminClient = &MockAdminClient{}
+
+	// Register The TopicProvisioner & Defer Its Removal
+	assert.False(t, IsProvisionerRegistered("testprovisioner"))
+	RegisterProvisioner("TestProvisioner", func(ctxArg context.Context, options ProvisionerOptions) (TopicProvisioner, error) {
+		assert.Equal(t, ctx, ctxArg)
+		assert.Equal(t, clientId, options.ClientId)
+		assert.Equal(t, constants.KnativeEventingNamespace, options.Namespace)
+		assert.Equal(t, kafkaConfig, options.KafkaConfig)
+		return mockAdminClient, nil
+	})
+	defer func() {
+		provisionerFactoriesLock.Lock()
+		delete(provisionerFactories, "testprovisioner")
+		provisionerFactoriesLock.Unlock()
+	}()
+	assert.True(t, IsProvisionerRegistered("testprovisioner"))
+	assert.Equal(t, []string{"azure", "custom", "kafka", "strimzi", "testprovisioner"}, ProvisionerNames())
+
+	// Perform The Test
+	adminClient, err := CreateAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, kafkaConfig)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Equal(t, mockAdminClient, adminClient)
+}
+
 //
 // Mock AdminClient
 //
 
-var _ AdminClientInterface = &MockAdminClient{}
+var _ TopicProvisioner = &MockAdminClient{}
 
 type MockAdminClient struct {
 	kafkaSecret string
@@ -153,6 +215,14 @@ func (c MockAdminClient) DeleteTopic(context.Context, string) *sarama.TopicError
 	return nil
 }
 
+func (c MockAdminClient) Validate(context.Context, string, *sarama.TopicDetail) error {
+	return nil
+}
+
+func (c MockAdminClient) AlterTopic(context.Context, string, *sarama.TopicDetail) *sarama.TopicError {
+	return nil
+}
+
 func (c MockAdminClient) Close() error {
 	return nil
 }
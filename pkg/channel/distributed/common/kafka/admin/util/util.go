@@ -18,6 +18,8 @@ package util
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 
 	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
@@ -74,6 +76,33 @@ func ValidateKafkaSecret(logger *zap.Logger, secret *corev1.Secret) bool {
 	return valid
 }
 
+// The Maximum Length Of A Kafka Topic Name
+const MaxTopicNameLength = 249
+
+// The Characters Permitted In Kafka Topic Names
+var legalTopicNameRegexp = regexp.MustCompile("^[a-zA-Z0-9._-]+$")
+
+// Utility Function For Validating A Topic Against The Kafka Topic Name & Detail Constraints
+func ValidateTopic(topicName string, topicDetail *sarama.TopicDetail) error {
+	switch {
+	case len(topicName) == 0:
+		return fmt.Errorf("topic name must not be empty")
+	case len(topicName) > MaxTopicNameLength:
+		return fmt.Errorf("topic name '%s' exceeds the maximum length of %d", topicName, MaxTopicNameLength)
+	case topicName == "." || topicName == "..":
+		return fmt.Errorf("topic name '%s' is not permitted", topicName)
+	case !legalTopicNameRegexp.MatchString(topicName):
+		return fmt.Errorf("topic name '%s' contains characters other than ASCII alphanumerics, '.', '_' and '-'", topicName)
+	case topicDetail == nil:
+		return fmt.Errorf("topic '%s' has no detail", topicName)
+	case topicDetail.NumPartitions < 1:
+		return fmt.Errorf("topic '%s' partitions %d must be > 0", topicName, topicDetail.NumPartitions)
+	case topicDetail.ReplicationFactor < 1:
+		return fmt.Errorf("topic '%s' replication factor %d must be > 0", topicName, topicDetail.ReplicationFactor)
+	}
+	return nil
+}
+
 // Utility Function To Up-Convert Any Basic Errors Into TopicErrors
 func PromoteErrorToTopicError(err error) *sarama.TopicError {
 	if err == nil {
@@ -82,6 +111,8 @@ func PromoteErrorToTopicError(err error) *sarama.TopicError {
 		switch err := err.(type) {
 		case *sarama.TopicError:
 			return err
+		case *sarama.TopicPartitionError:
+			return NewTopicError(err.Err, err.Error())
 		default:
 			return NewUnknownTopicError(err.Error())
 		}
@@ -19,6 +19,7 @@ package util
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/Shopify/sarama"
@@ -134,10 +135,17 @@ func TestPromoteErrorToTopicError(t *testing.T) {
 		ErrMsg: &topicErrMsg,
 	}
 
-	// Perform The Test (Both Cases)
+	topicPartitionErrMsg := "test TopicPartitionError"
+	topicPartitionErr := &sarama.TopicPartitionError{
+		Err:    sarama.ErrInvalidPartitions,
+		ErrMsg: &topicPartitionErrMsg,
+	}
+
+	// Perform The Test (All Cases)
 	nilTopicError := PromoteErrorToTopicError(nil)
 	defaultTopicError := PromoteErrorToTopicError(defaultErr)
 	saramaTopicError := PromoteErrorToTopicError(topicErr)
+	saramaTopicPartitionError := PromoteErrorToTopicError(topicPartitionErr)
 
 	// Verify The Results
 	assert.Nil(t, nilTopicError)
@@ -147,6 +155,22 @@ func TestPromoteErrorToTopicError(t *testing.T) {
 	assert.NotNil(t, saramaTopicError)
 	assert.Equal(t, topicErr.Err, saramaTopicError.Err)
 	assert.Equal(t, topicErrMsg, *saramaTopicError.ErrMsg)
+	assert.NotNil(t, saramaTopicPartitionError)
+	assert.Equal(t, sarama.ErrInvalidPartitions, saramaTopicPartitionError.Err)
+	assert.Equal(t, topicPartitionErr.Error(), *saramaTopicPartitionError.ErrMsg)
+}
+
+// Test The ValidateTopic() Functionality
+func TestValidateTopic(t *testing.T) {
+	topicDetail := &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 3}
+	assert.Nil(t, ValidateTopic("my-namespace.my_channel-1", topicDetail))
+	assert.NotNil(t, ValidateTopic("", topicDetail))
+	assert.NotNil(t, ValidateTopic(strings.Repeat("a", MaxTopicNameLength+1), topicDetail))
+	assert.NotNil(t, ValidateTopic("..", topicDetail))
+	assert.NotNil(t, ValidateTopic("my/topic", topicDetail))
+	assert.NotNil(t, ValidateTopic("my-topic", nil))
+	assert.NotNil(t, ValidateTopic("my-topic", &sarama.TopicDetail{ReplicationFactor: 3}))
+	assert.NotNil(t, ValidateTopic("my-topic", &sarama.TopicDetail{NumPartitions: 4}))
 }
 
 // Test The NewUnknownTopicError() Functionality
@@ -28,18 +28,74 @@ const (
 	// Kafka Secret Label
 	KafkaSecretLabel = "eventing-kafka.knative.dev/kafka-secret"
 
+	// KafkaChannel Event Type Routing Annotations
+	EventTypesAnnotation           = "kafka.eventing.knative.dev/event-types"            // Comma Separated List Of Routed Event Types
+	SubscriberEventTypesAnnotation = "kafka.eventing.knative.dev/subscriber-event-types" // JSON Map Of Subscriber UID To Event Types
+
+	// KafkaChannel Stale Event Annotation
+	SubscriberMaxEventAgeAnnotation = "kafka.eventing.knative.dev/subscriber-max-event-age" // JSON Map Of Subscriber UID To EventAgePolicy
+
+	// KafkaChannel Subscriber Parallelism Annotation (Subscribers Are Otherwise Dispatched To Once Per Claimed Partition Concurrently)
+	SubscriberParallelismAnnotation = "kafka.eventing.knative.dev/subscriber-parallelism" // JSON Map Of Subscriber UID To Maximum Concurrently Dispatching Partitions
+
+	// KafkaChannel Subscriber Filter Annotation (Events Not Matching A Subscriber's Filter Are Skipped, e.g. For Broker Triggers)
+	SubscriberFiltersAnnotation = "kafka.eventing.knative.dev/subscriber-filters" // JSON Map Of Subscriber UID To CloudEvent Attribute Filters
+
+	// KafkaChannel Subscriber Transform Annotation (Fields Of The Event Data Removed, Redacted Or Hashed Before Dispatch To A Subscriber)
+	SubscriberTransformsAnnotation = "kafka.eventing.knative.dev/subscriber-transforms" // JSON Map Of Subscriber UID To EventTransforms
+
+	// KafkaChannel Middleware Annotation (The User-Defined Middleware Modules Applied To The KafkaChannel's Events)
+	MiddlewareAnnotation = "kafka.eventing.knative.dev/middleware" // Comma Separated Names Of The Middleware Modules (Applied In Order)
+
+	// Subscription Filter Annotation (Written On The Subscriptions Of A Channel Based Broker's Triggers To Push Their Filters Down)
+	SubscriptionFilterAnnotation = "kafka.eventing.knative.dev/filter" // JSON Object Of CloudEvent Attribute Filters
+
+	// Subscription Delivery Header Annotations (Added To The Requests Delivering Events To The Subscriber, e.g. For API Gateways)
+	SubscriptionDeliveryHeadersAnnotation    = "kafka.eventing.knative.dev/delivery-headers"     // JSON Object Of Static Header Names To Values
+	SubscriptionDeliveryAuthSecretAnnotation = "kafka.eventing.knative.dev/delivery-auth-secret" // Name Of A Secret In The Subscription's Namespace With A token Or username & password
+	SubscriptionDeliveryAudienceAnnotation   = "kafka.eventing.knative.dev/delivery-audience"    // OIDC Audience Of The Subscriber (Requires The Dispatcher's OIDC Config)
+
+	// KafkaChannel ConsumerGroup Rebalance Strategy Annotation (Overrides The Sarama ConfigMap Setting)
+	RebalanceStrategyAnnotation = "kafka.eventing.knative.dev/rebalance-strategy" // One Of range, roundrobin, sticky
+
+	// KafkaChannel Tuning Preset Annotation (Overrides The Sarama ConfigMap's Consumer Fetch Settings Of The Dispatcher)
+	TuningPresetAnnotation = "kafka.eventing.knative.dev/tuning-preset" // One Of throughput, latency, balanced
+
+	// KafkaChannel Record Key Template Annotation (Overrides The Default PartitionKey / Subject Keying Of Produced Records)
+	KeyTemplateAnnotation = "kafka.eventing.knative.dev/key-template" // Go Template Rendered Against The CloudEvent, e.g. "{{.Extensions.tenantid}}-{{.Subject}}"
+
+	// KafkaChannel gRPC Delivery Annotation (Subscribers With A grpc:// Or grpcs:// URI Are Always Delivered Via gRPC)
+	GrpcSubscribersAnnotation = "kafka.eventing.knative.dev/grpc-subscribers" // Comma Separated List Of Subscriber UIDs
+
+	// KafkaChannel Receiver Isolation Annotation (Overrides The receiver.isolation ConfigMap Setting)
+	ReceiverIsolationAnnotation = "kafka.eventing.knative.dev/receiver-isolation" // One Of secret, channel
+
+	// KafkaChannel Replication Annotation (The Dispatcher Mirrors The KafkaChannel's Topic To A Remote Kafka Cluster)
+	ReplicationSecretAnnotation = "kafka.eventing.knative.dev/replication-secret" // Name Of A Secret In The System Namespace With The Remote brokers, username & password
+
+	// KafkaChannel Dispatcher Image Annotations (Resolved Against The Dispatcher Images In The ConfigMap)
+	ImageClassAnnotation   = "kafka.eventing.knative.dev/image-class"  // Name Of An Image Class (e.g. "canary")
+	ArchitectureAnnotation = "kafka.eventing.knative.dev/architecture" // Node Architecture (e.g. "arm64")
+
 	// Kafka Secret Keys
 	KafkaSecretKeyBrokers   = "brokers"
 	KafkaSecretKeyNamespace = "namespace"
 	KafkaSecretKeyUsername  = "username"
 	KafkaSecretKeyPassword  = "password"
 
+	// Egress Proxy Secret Keys
+	ProxyUsernameKey = "username"
+	ProxyPasswordKey = "password"
+
 	// Kafka Admin/Consumer/Producer Config Values
 	ConfigNetSaslVersion = sarama.SASLHandshakeV1 // Latest version, seems to work with EventHubs as well.
 
 	// Kafka Topic Config Keys
 	TopicDetailConfigRetentionMs = "retention.ms"
 
+	// Kafka Topic Name Constraints
+	MaxTopicNameLength = 249
+
 	// EventHub Error Codes
 	EventHubErrorCodeUnknown       = -2
 	EventHubErrorCodeParseFailure  = -1
@@ -51,6 +107,26 @@ const (
 
 	// KafkaChannel Constants
 	KafkaChannelServiceNameSuffix = "kn-channel" // Specific Value For Use With Knative e2e Tests!
+
+	// KafkaChannel Addressing Modes (The receiver.addressing ConfigMap Setting)
+	ReceiverAddressingHost = "host" // http://<name>-kn-channel.<namespace>.svc.cluster.local (The Default)
+	ReceiverAddressingPath = "path" // http://<receiver>.<system namespace>.svc.cluster.local/<namespace>/<name>
+	ReceiverServiceSuffix  = "receiver"
+
+	// Kafka Record Timestamps Of The Receiver (The receiver.timestamp ConfigMap Setting)
+	ReceiverTimestampProduce = "produce" // The Time The Record Is Produced (The Default)
+	ReceiverTimestampCeTime  = "ceTime"  // The CloudEvent's time Attribute (Falling Back To The Produce Time)
+
+	// Kafka DeadLetterSink Constants
+	DeadLetterSinkKafkaScheme = "kafka" // DeadLetterSink URI Scheme Shorthand For A Convention-Named Topic Per Subscription
+	DeadLetterTopicSuffix     = "dlq"
+
+	// Kafka Quarantine Constants
+	QuarantineTopicSuffix = "quarantine"
+
+	// Kafka Replication Constants
+	ReplicatorGroupIdSuffix = "replicator"
+	CheckpointTopicSuffix   = "checkpoints"
 )
 
 // Non-Constant Constants ;)
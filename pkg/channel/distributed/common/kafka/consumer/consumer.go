@@ -21,11 +21,26 @@ import (
 	"github.com/rcrowley/go-metrics"
 )
 
+// Factory Function For Creating Sarama Kafka ConsumerGroups
+// Components Accept One To Allow An Alternate Kafka Implementation (e.g. The Conformance Cluster) To Be Injected
+type ConsumerGroupFactory func(brokers []string, groupId string, config *sarama.Config) (sarama.ConsumerGroup, error)
+
 // Create A Kafka ConsumerGroup (Optional SASL Authentication)
 func CreateConsumerGroup(brokers []string, config *sarama.Config, groupId string) (sarama.ConsumerGroup, metrics.Registry, error) {
 
+	return CreateConsumerGroupWithFactory(nil, brokers, config, groupId)
+}
+
+// Create A Kafka ConsumerGroup Via The Specified Factory (Connecting To The Brokers If nil)
+func CreateConsumerGroupWithFactory(factory ConsumerGroupFactory, brokers []string, config *sarama.Config, groupId string) (sarama.ConsumerGroup, metrics.Registry, error) {
+
+	// Default To Connecting To The Kafka Brokers
+	if factory == nil {
+		factory = NewConsumerGroupWrapper
+	}
+
 	// Create A New Sarama ConsumerGroup & Return Results
-	consumerGroup, err := NewConsumerGroupWrapper(brokers, groupId, config)
+	consumerGroup, err := factory(brokers, groupId, config)
 	return consumerGroup, config.MetricRegistry, err
 }
 
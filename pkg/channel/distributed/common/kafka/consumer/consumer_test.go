@@ -74,6 +74,32 @@ func TestCreateConsumerGroup(t *testing.T) {
 	assert.NotNil(t, registry)
 }
 
+// Test The CreateConsumerGroupWithFactory() Functionality
+func TestCreateConsumerGroupWithFactory(t *testing.T) {
+
+	// Test Data
+	brokers := []string{"TestBroker"}
+	mockConsumerGroup := kafkatesting.NewMockConsumerGroup(t)
+
+	// Create A Factory Returning The Mock ConsumerGroup
+	factory := func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+		assert.Equal(t, brokers, brokersArg)
+		assert.Equal(t, GroupId, groupIdArg)
+		assert.Equal(t, ClientId, configArg.ClientID)
+		return mockConsumerGroup, nil
+	}
+
+	// Perform The Test
+	config := commontesting.GetDefaultSaramaConfig(t)
+	kafkasarama.UpdateSaramaConfig(config, ClientId, KafkaUsername, KafkaPassword)
+	consumerGroup, registry, err := CreateConsumerGroupWithFactory(factory, brokers, config, GroupId)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Equal(t, mockConsumerGroup, consumerGroup)
+	assert.NotNil(t, registry)
+}
+
 // Test that the UpdateSaramaConfig sets values as expected
 func TestUpdateConfig(t *testing.T) {
 	config := sarama.NewConfig()
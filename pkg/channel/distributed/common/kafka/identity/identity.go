@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+)
+
+// Package Constants
+const (
+	// The Directory & File Name Of The Projected ServiceAccount Token (Mounted By The Controller)
+	DefaultTokenDirectory = "/var/run/secrets/eventing-kafka/serviceaccount"
+	DefaultTokenFileName  = "token"
+	DefaultTokenPath      = DefaultTokenDirectory + "/" + DefaultTokenFileName
+
+	// The Timeout Of A Single Token Exchange (Sarama Expects AccessTokenProviders To Not Block Indefinitely)
+	ExchangeTimeout = 10 * time.Second
+
+	// The Lifetime Assumed For Access Tokens Without An Expiry
+	DefaultTokenLifetime = 5 * time.Minute
+)
+
+// Token Is An Access Token Obtained From A TokenExchanger
+type Token struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
+
+// TokenExchanger Exchanges A (Projected ServiceAccount) Subject Token For A Kafka Access Token
+type TokenExchanger interface {
+	Exchange(ctx context.Context, subjectToken string) (*Token, error)
+}
+
+// ExchangerFactory Creates A TokenExchanger From The WorkloadIdentity Configuration
+type ExchangerFactory func(identityConfig config.EKWorkloadIdentityConfig) (TokenExchanger, error)
+
+// The Registered ExchangerFactories By Name
+var (
+	exchangerFactories     = map[string]ExchangerFactory{STSExchangerName: NewSTSExchanger}
+	exchangerFactoriesLock sync.RWMutex
+)
+
+// Register A Custom TokenExchanger (e.g. Cloud Provider Specific) Which May Then Be Selected By Name In The ConfigMap
+func RegisterExchanger(name string, factory ExchangerFactory) {
+	exchangerFactoriesLock.Lock()
+	defer exchangerFactoriesLock.Unlock()
+	exchangerFactories[name] = factory
+}
+
+// Create The TokenExchanger Selected By The Specified WorkloadIdentity Configuration (Defaulting To "sts")
+func NewExchanger(identityConfig config.EKWorkloadIdentityConfig) (TokenExchanger, error) {
+	name := identityConfig.Exchanger
+	if len(name) == 0 {
+		name = STSExchangerName
+	}
+	exchangerFactoriesLock.RLock()
+	factory, ok := exchangerFactories[name]
+	exchangerFactoriesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown workload identity token exchanger '%s'", name)
+	}
+	return factory(identityConfig)
+}
+
+//
+// TokenProvider Implements The Sarama AccessTokenProvider Interface
+//
+// The projected ServiceAccount token is (re)read from the TokenPath before every exchange, since the kubelet
+// rotates it, and the exchanged access token is reused until most of its lifetime has passed.  A failed refresh
+// falls back to the current access token until it expires.
+//
+type TokenProvider struct {
+	logger    *zap.Logger
+	tokenPath string
+	exchanger TokenExchanger
+	token     *Token
+	refreshAt time.Time
+	mutex     sync.Mutex
+}
+
+// Verify The TokenProvider Implements The Sarama AccessTokenProvider Interface
+var _ sarama.AccessTokenProvider = &TokenProvider{}
+
+// Create A New TokenProvider Reading The Subject Token From The Specified Path
+func NewTokenProvider(logger *zap.Logger, tokenPath string, exchanger TokenExchanger) *TokenProvider {
+	if len(tokenPath) == 0 {
+		tokenPath = DefaultTokenPath
+	}
+	return &TokenProvider{logger: logger, tokenPath: tokenPath, exchanger: exchanger}
+}
+
+// Token Returns An Unexpired Access Token, Exchanging A New One When Due
+func (p *TokenProvider) Token() (*sarama.AccessToken, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// Exchange A New Access Token When Due For Refresh
+	now := time.Now()
+	if p.token == nil || !now.Before(p.refreshAt) {
+		token, err := p.exchange()
+		if err != nil {
+			if p.token != nil && now.Before(p.token.ExpiresAt) {
+				p.logger.Warn("Failed To Refresh Access Token - Using Current Token", zap.Time("ExpiresAt", p.token.ExpiresAt), zap.Error(err))
+				return &sarama.AccessToken{Token: p.token.AccessToken}, nil
+			}
+			p.logger.Error("Failed To Exchange Access Token", zap.Error(err))
+			return nil, err
+		}
+		if token.ExpiresAt.IsZero() {
+			token.ExpiresAt = now.Add(DefaultTokenLifetime)
+		}
+		p.token = token
+		p.refreshAt = now.Add(token.ExpiresAt.Sub(now) * 4 / 5)
+		p.logger.Debug("Exchanged New Access Token", zap.Time("ExpiresAt", token.ExpiresAt))
+	}
+
+	// Return The Current Access Token
+	return &sarama.AccessToken{Token: p.token.AccessToken}, nil
+}
+
+// Exchange The Current Projected ServiceAccount Token For A New Access Token
+func (p *TokenProvider) exchange() (*Token, error) {
+	subjectToken, err := ioutil.ReadFile(p.tokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subject token: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ExchangeTimeout)
+	defer cancel()
+	return p.exchanger.Exchange(ctx, strings.TrimSpace(string(subjectToken)))
+}
+
+// Update The Sarama Config To Authenticate Via The WorkloadIdentity (No-Op Unless Enabled)
+func UpdateSaramaConfig(saramaConfig *sarama.Config, identityConfig config.EKWorkloadIdentityConfig, logger *zap.Logger) error {
+	if !identityConfig.Enabled {
+		return nil
+	}
+	exchanger, err := NewExchanger(identityConfig)
+	if err != nil {
+		return err
+	}
+	kafkasarama.UpdateSaramaTokenProvider(saramaConfig, NewTokenProvider(logger, identityConfig.TokenPath, exchanger))
+	logger.Info("Authenticating With Kafka Via Workload Identity", zap.String("TokenEndpoint", identityConfig.TokenEndpoint))
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testSubjectToken = "TestSubjectToken"
+	testAccessToken  = "TestAccessToken"
+	testAudience     = "TestAudience"
+	testScope        = "kafka"
+)
+
+// Mock TokenExchanger
+type mockExchanger struct {
+	subjectTokens []string
+	tokens        []*Token
+	errs          []error
+}
+
+func (m *mockExchanger) Exchange(_ context.Context, subjectToken string) (*Token, error) {
+	m.subjectTokens = append(m.subjectTokens, subjectToken)
+	token, err := m.tokens[0], m.errs[0]
+	m.tokens, m.errs = m.tokens[1:], m.errs[1:]
+	return token, err
+}
+
+// Test The TokenProvider's Token() Functionality
+func TestTokenProvider(t *testing.T) {
+
+	// Write A Test Subject Token
+	tokenPath := writeTestSubjectToken(t)
+
+	// Create A TokenProvider With A Mock Exchanger
+	now := time.Now()
+	exchanger := &mockExchanger{
+		tokens: []*Token{
+			{AccessToken: "token-1", ExpiresAt: now.Add(-time.Second)},
+			{AccessToken: "token-2", ExpiresAt: now.Add(time.Hour)},
+			nil,
+		},
+		errs: []error{nil, nil, errors.New("exchange failed")},
+	}
+	provider := NewTokenProvider(logtesting.TestLogger(t).Desugar(), tokenPath, exchanger)
+
+	// Verify Expired Access Tokens Are Refreshed & Unexpired Ones Are Reused
+	accessToken, err := provider.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, &sarama.AccessToken{Token: "token-1"}, accessToken)
+	accessToken, err = provider.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, &sarama.AccessToken{Token: "token-2"}, accessToken)
+	accessToken, err = provider.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, &sarama.AccessToken{Token: "token-2"}, accessToken)
+	assert.Equal(t, []string{testSubjectToken, testSubjectToken}, exchanger.subjectTokens)
+
+	// Verify A Failed Refresh Falls Back To The Current Access Token Until It Expires
+	provider.refreshAt = now
+	accessToken, err = provider.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, &sarama.AccessToken{Token: "token-2"}, accessToken)
+
+	// Verify Unreadable Subject Tokens Fail Without A Current Access Token
+	_, err = NewTokenProvider(logtesting.TestLogger(t).Desugar(), filepath.Join(filepath.Dir(tokenPath), "missing"), exchanger).Token()
+	assert.NotNil(t, err)
+}
+
+// Test The STSExchanger's Exchange() Functionality
+func TestSTSExchanger(t *testing.T) {
+
+	// Create A Test STS
+	var expiresIn = 3600
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, http.MethodPost, request.Method)
+		assert.Nil(t, request.ParseForm())
+		assert.Equal(t, tokenExchangeGrantType, request.PostForm.Get("grant_type"))
+		assert.Equal(t, jwtTokenType, request.PostForm.Get("subject_token_type"))
+		assert.Equal(t, testAudience, request.PostForm.Get("audience"))
+		assert.Equal(t, testScope, request.PostForm.Get("scope"))
+		if request.PostForm.Get("subject_token") != testSubjectToken {
+			writer.WriteHeader(http.StatusUnauthorized)
+			_, _ = writer.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		if expiresIn > 0 {
+			_, _ = writer.Write([]byte(`{"access_token":"` + testAccessToken + `","token_type":"Bearer","expires_in":3600}`))
+		} else {
+			_, _ = writer.Write([]byte(`{"access_token":"` + testAccessToken + `","token_type":"Bearer"}`))
+		}
+	}))
+	defer server.Close()
+
+	// Verify Invalid Token Endpoints Are Rejected
+	_, err := NewSTSExchanger(config.EKWorkloadIdentityConfig{TokenEndpoint: "not-a-url"})
+	assert.NotNil(t, err)
+
+	// Verify Successful Exchanges
+	exchanger, err := NewSTSExchanger(config.EKWorkloadIdentityConfig{TokenEndpoint: server.URL, Audience: testAudience, Scope: testScope})
+	assert.Nil(t, err)
+	token, err := exchanger.Exchange(context.TODO(), testSubjectToken)
+	assert.Nil(t, err)
+	assert.Equal(t, testAccessToken, token.AccessToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+	expiresIn = 0
+	token, err = exchanger.Exchange(context.TODO(), testSubjectToken)
+	assert.Nil(t, err)
+	assert.True(t, token.ExpiresAt.IsZero())
+
+	// Verify Failed Exchanges
+	token, err = exchanger.Exchange(context.TODO(), "InvalidSubjectToken")
+	assert.Nil(t, token)
+	assert.Contains(t, err.Error(), "invalid_grant")
+}
+
+// Test The UpdateSaramaConfig() & Exchanger Registration Functionality
+func TestUpdateSaramaConfig(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Disabled WorkloadIdentity Leaves The Config As-Is
+	saramaConfig := sarama.NewConfig()
+	assert.Nil(t, UpdateSaramaConfig(saramaConfig, config.EKWorkloadIdentityConfig{}, logger))
+	assert.Nil(t, saramaConfig.Net.SASL.TokenProvider)
+
+	// Unknown Exchangers Are An Error
+	assert.NotNil(t, UpdateSaramaConfig(saramaConfig, config.EKWorkloadIdentityConfig{Enabled: true, Exchanger: "unknown"}, logger))
+
+	// Registered Exchangers Are Used
+	exchanger := &mockExchanger{tokens: []*Token{{AccessToken: testAccessToken}}, errs: []error{nil}}
+	RegisterExchanger("mock", func(config.EKWorkloadIdentityConfig) (TokenExchanger, error) { return exchanger, nil })
+	tokenPath := writeTestSubjectToken(t)
+	assert.Nil(t, UpdateSaramaConfig(saramaConfig, config.EKWorkloadIdentityConfig{Enabled: true, Exchanger: "mock", TokenPath: tokenPath}, logger))
+	assert.True(t, saramaConfig.Net.SASL.Enable)
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeOAuth), saramaConfig.Net.SASL.Mechanism)
+	accessToken, err := saramaConfig.Net.SASL.TokenProvider.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, testAccessToken, accessToken.Token)
+}
+
+// Utility Function For Writing The Test Subject Token To A Temporary File
+func writeTestSubjectToken(t *testing.T) string {
+	directory, err := ioutil.TempDir("", "identity")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(directory) })
+	tokenPath := filepath.Join(directory, DefaultTokenFileName)
+	assert.Nil(t, ioutil.WriteFile(tokenPath, []byte(testSubjectToken+"\n"), 0600))
+	return tokenPath
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// RFC 8693 OAuth 2.0 Token Exchange Constants
+const (
+	STSExchangerName = "sts"
+
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	jwtTokenType           = "urn:ietf:params:oauth:token-type:jwt"
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+
+	maxErrorBodyLength = 1024
+)
+
+// STSExchanger Exchanges Subject Tokens At A Security Token Service Per RFC 8693 (OAuth 2.0 Token Exchange)
+type STSExchanger struct {
+	endpoint   string
+	audience   string
+	scope      string
+	httpClient *http.Client
+}
+
+// Verify The STSExchanger Implements The TokenExchanger Interface
+var _ TokenExchanger = &STSExchanger{}
+
+// The STS Token Exchange Response
+type stsResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Create A New STSExchanger For The TokenEndpoint Of The Specified WorkloadIdentity Configuration
+func NewSTSExchanger(identityConfig config.EKWorkloadIdentityConfig) (TokenExchanger, error) {
+	endpoint, err := url.Parse(identityConfig.TokenEndpoint)
+	if err != nil || !endpoint.IsAbs() {
+		return nil, fmt.Errorf("invalid workload identity token endpoint '%s'", identityConfig.TokenEndpoint)
+	}
+	return &STSExchanger{
+		endpoint:   endpoint.String(),
+		audience:   identityConfig.Audience,
+		scope:      identityConfig.Scope,
+		httpClient: &http.Client{},
+	}, nil
+}
+
+// Exchange The Subject Token For An Access Token At The STS
+func (e *STSExchanger) Exchange(ctx context.Context, subjectToken string) (*Token, error) {
+
+	// Create The Token Exchange Request
+	form := url.Values{
+		"grant_type":           {tokenExchangeGrantType},
+		"subject_token":        {subjectToken},
+		"subject_token_type":   {jwtTokenType},
+		"requested_token_type": {accessTokenType},
+	}
+	if len(e.audience) > 0 {
+		form.Set("audience", e.audience)
+	}
+	if len(e.scope) > 0 {
+		form.Set("scope", e.scope)
+	}
+	request, err := http.NewRequest(http.MethodPost, e.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	// Perform The Token Exchange
+	response, err := e.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange failed: %v", err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token exchange response: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		if len(body) > maxErrorBodyLength {
+			body = body[:maxErrorBodyLength]
+		}
+		return nil, fmt.Errorf("token exchange failed with status %d: %s", response.StatusCode, string(body))
+	}
+
+	// Parse The Access Token From The Response
+	stsResponse := &stsResponse{}
+	err = json.Unmarshal(body, stsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token exchange response: %v", err)
+	}
+	if len(stsResponse.AccessToken) == 0 {
+		return nil, errors.New("token exchange response contains no access token")
+	}
+	token := &Token{AccessToken: stsResponse.AccessToken}
+	if stsResponse.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(stsResponse.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -21,11 +21,25 @@ import (
 	"github.com/rcrowley/go-metrics"
 )
 
+// Factory Function For Creating Sarama Kafka SyncProducers
+// Components Accept One To Allow An Alternate Kafka Implementation (e.g. The Conformance Cluster) To Be Injected
+type SyncProducerFactory func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error)
+
 // Create A Sarama Kafka SyncProducer (Optional Authentication)
 func CreateSyncProducer(brokers []string, config *sarama.Config) (sarama.SyncProducer, metrics.Registry, error) {
+	return CreateSyncProducerWithFactory(nil, brokers, config)
+}
+
+// Create A Sarama Kafka SyncProducer Via The Specified Factory (Connecting To The Brokers If nil)
+func CreateSyncProducerWithFactory(factory SyncProducerFactory, brokers []string, config *sarama.Config) (sarama.SyncProducer, metrics.Registry, error) {
+
+	// Default To Connecting To The Kafka Brokers
+	if factory == nil {
+		factory = newSyncProducerWrapper
+	}
 
 	// Create A New Sarama SyncProducer & Return Results
-	syncProducer, err := newSyncProducerWrapper(brokers, config)
+	syncProducer, err := factory(brokers, config)
 	return syncProducer, config.MetricRegistry, err
 }
 
@@ -33,3 +47,30 @@ func CreateSyncProducer(brokers []string, config *sarama.Config) (sarama.SyncPro
 var newSyncProducerWrapper = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
 	return sarama.NewSyncProducer(brokers, config)
 }
+
+// Create A Sarama Kafka SyncProducer From A New Sarama Client (Which Remains Accessible For Broker Connection States)
+// The SyncProducer Does Not Close The Client, So The Caller Must Close It After Closing The SyncProducer
+func CreateClientSyncProducer(brokers []string, config *sarama.Config) (sarama.Client, sarama.SyncProducer, metrics.Registry, error) {
+
+	// Create A New Sarama Client
+	client, err := newClientWrapper(brokers, config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Create A New Sarama SyncProducer From The Client (Closing The Client On Failure) & Return Results
+	syncProducer, err := newSyncProducerFromClientWrapper(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, nil, err
+	}
+	return client, syncProducer, config.MetricRegistry, nil
+}
+
+// Function Reference Variables To Facilitate Mocking In Unit Tests
+var newClientWrapper = func(brokers []string, config *sarama.Config) (sarama.Client, error) {
+	return sarama.NewClient(brokers, config)
+}
+var newSyncProducerFromClientWrapper = func(client sarama.Client) (sarama.SyncProducer, error) {
+	return sarama.NewSyncProducerFromClient(client)
+}
@@ -74,6 +74,83 @@ func performCreateSyncProducerTest(t *testing.T, username string, password strin
 	assert.NotNil(t, registry)
 }
 
+// Test The CreateSyncProducerWithFactory() Functionality
+func TestCreateSyncProducerWithFactory(t *testing.T) {
+
+	// Stub The Kafka SyncProducer Creation Wrapper To Verify It Is Not Used When A Factory Is Specified
+	newSyncProducerWrapperPlaceholder := newSyncProducerWrapper
+	newSyncProducerWrapper = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+		t.Fatal("Unexpected Connection To The Kafka Brokers")
+		return nil, nil
+	}
+	defer func() { newSyncProducerWrapper = newSyncProducerWrapperPlaceholder }()
+
+	// Create A Factory Returning A Mock SyncProducer
+	mockSyncProducer := &MockSyncProducer{}
+	factory := func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+		assert.Equal(t, []string{KafkaBrokers}, brokers)
+		verifySaramaConfig(t, config, ClientId, "", "")
+		return mockSyncProducer, nil
+	}
+
+	// Perform The Test
+	config := commontesting.GetDefaultSaramaConfig(t)
+	kafkasarama.UpdateSaramaConfig(config, ClientId, "", "")
+	producer, registry, err := CreateSyncProducerWithFactory(factory, []string{KafkaBrokers}, config)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Equal(t, mockSyncProducer, producer)
+	assert.NotNil(t, registry)
+}
+
+// Test The CreateClientSyncProducer() Functionality
+func TestCreateClientSyncProducer(t *testing.T) {
+
+	// Create A Mock Client & SyncProducer
+	mockClient := &MockClient{}
+	mockSyncProducer := &MockSyncProducer{}
+
+	// Stub The Kafka Client & SyncProducer Creation Wrappers With Test Versions Returning The Mocks
+	newClientWrapperPlaceholder := newClientWrapper
+	newSyncProducerFromClientWrapperPlaceholder := newSyncProducerFromClientWrapper
+	newClientWrapper = func(brokers []string, config *sarama.Config) (sarama.Client, error) {
+		assert.Equal(t, []string{KafkaBrokers}, brokers)
+		verifySaramaConfig(t, config, ClientId, KafkaUsername, KafkaPassword)
+		return mockClient, nil
+	}
+	newSyncProducerFromClientWrapper = func(client sarama.Client) (sarama.SyncProducer, error) {
+		assert.Equal(t, mockClient, client)
+		return mockSyncProducer, nil
+	}
+	defer func() {
+		newClientWrapper = newClientWrapperPlaceholder
+		newSyncProducerFromClientWrapper = newSyncProducerFromClientWrapperPlaceholder
+	}()
+
+	// Perform The Test
+	config := commontesting.GetDefaultSaramaConfig(t)
+	kafkasarama.UpdateSaramaConfig(config, ClientId, KafkaUsername, KafkaPassword)
+	client, producer, registry, err := CreateClientSyncProducer([]string{KafkaBrokers}, config)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Equal(t, mockClient, client)
+	assert.Equal(t, mockSyncProducer, producer)
+	assert.NotNil(t, registry)
+	assert.False(t, mockClient.closed)
+
+	// Verify The Client Is Closed If The SyncProducer Cannot Be Created
+	newSyncProducerFromClientWrapper = func(client sarama.Client) (sarama.SyncProducer, error) {
+		return nil, sarama.ErrOutOfBrokers
+	}
+	client, producer, _, err = CreateClientSyncProducer([]string{KafkaBrokers}, config)
+	assert.Equal(t, sarama.ErrOutOfBrokers, err)
+	assert.Nil(t, client)
+	assert.Nil(t, producer)
+	assert.True(t, mockClient.closed)
+}
+
 // Test that the UpdateSaramaConfig sets values as expected
 func TestUpdateConfig(t *testing.T) {
 	config := sarama.NewConfig()
@@ -119,3 +196,17 @@ func (p *MockSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
 func (p *MockSyncProducer) Close() error {
 	return nil
 }
+
+//
+// Mock Sarama Client Implementation (Only Close Is Supported)
+//
+
+type MockClient struct {
+	sarama.Client
+	closed bool
+}
+
+func (c *MockClient) Close() error {
+	c.closed = true
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Regular Expression Matching Characters Which Are Not Valid In Kafka Topic Names
+var invalidTopicCharsRegexp = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+//
+// Event Type Routing Configuration Of A Single KafkaChannel
+//
+// When a KafkaChannel is annotated with a list of event types (constants.EventTypesAnnotation) the receiver
+// produces events of those types to a dedicated "sub-topic" (the channel's Topic name suffixed with the sanitized
+// event type) instead of the channel's main Topic.  Subscribers may then declare interest in a subset of the routed
+// event types (constants.SubscriberEventTypesAnnotation) in which case the dispatcher only consumes the relevant
+// sub-topics.  Subscribers which do not declare any interest consume the main Topic and ALL the sub-topics.
+//
+// A nil *EventTypeRouting is valid and represents the default single Topic behavior.
+//
+type EventTypeRouting struct {
+	eventTypes           map[string]bool
+	subscriberEventTypes map[string][]string
+}
+
+// Create The EventTypeRouting From The Specified KafkaChannel Annotations (nil If Not Enabled)
+func NewEventTypeRouting(annotations map[string]string) (*EventTypeRouting, error) {
+
+	// Parse The Routed Event Types - Routing Is Not Enabled If None Are Specified
+	eventTypes := make(map[string]bool)
+	for _, eventType := range strings.Split(annotations[constants.EventTypesAnnotation], ",") {
+		eventType = strings.TrimSpace(eventType)
+		if len(eventType) > 0 {
+			eventTypes[eventType] = true
+		}
+	}
+	if len(eventTypes) == 0 {
+		return nil, nil
+	}
+
+	// Parse The Optional Subscriber Event Types & Verify They Are All Routed
+	subscriberEventTypes := make(map[string][]string)
+	if subscriberEventTypesJson := annotations[constants.SubscriberEventTypesAnnotation]; len(subscriberEventTypesJson) > 0 {
+		err := json.Unmarshal([]byte(subscriberEventTypesJson), &subscriberEventTypes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", constants.SubscriberEventTypesAnnotation, err)
+		}
+		for subscriberUID, subscriberTypes := range subscriberEventTypes {
+			for _, eventType := range subscriberTypes {
+				if !eventTypes[eventType] {
+					return nil, fmt.Errorf("subscriber %s declares event type %q which is not listed in the %s annotation", subscriberUID, eventType, constants.EventTypesAnnotation)
+				}
+			}
+		}
+	}
+
+	// Return The EventTypeRouting
+	return &EventTypeRouting{eventTypes: eventTypes, subscriberEventTypes: subscriberEventTypes}, nil
+}
+
+// Get The Sub-Topic Name For The Specified Topic & Event Type
+func EventTypeTopicName(topicName string, eventType string) string {
+	eventTypeTopicName := topicName + "." + invalidTopicCharsRegexp.ReplaceAllString(eventType, "_")
+	if len(eventTypeTopicName) > constants.MaxTopicNameLength {
+		eventTypeTopicName = eventTypeTopicName[:constants.MaxTopicNameLength]
+	}
+	return eventTypeTopicName
+}
+
+// Get The Topic To Which An Event Of The Specified Type Should Be Produced
+func (r *EventTypeRouting) ProducerTopic(topicName string, eventType string) string {
+	if r != nil && r.eventTypes[eventType] {
+		return EventTypeTopicName(topicName, eventType)
+	}
+	return topicName
+}
+
+// Get All The (Sorted) Sub-Topics Of The Specified Topic
+func (r *EventTypeRouting) Topics(topicName string) []string {
+	if r == nil {
+		return nil
+	}
+	topicNames := make([]string, 0, len(r.eventTypes))
+	for eventType := range r.eventTypes {
+		topicNames = append(topicNames, EventTypeTopicName(topicName, eventType))
+	}
+	sort.Strings(topicNames)
+	return topicNames
+}
+
+// Get The (Sorted) Topics Which The Specified Subscriber Should Consume
+func (r *EventTypeRouting) ConsumerTopics(topicName string, subscriberUID string) []string {
+	if r == nil {
+		return []string{topicName}
+	}
+	subscriberEventTypes := r.subscriberEventTypes[subscriberUID]
+	if len(subscriberEventTypes) == 0 {
+		return append([]string{topicName}, r.Topics(topicName)...)
+	}
+	topicNameSet := make(map[string]bool, len(subscriberEventTypes))
+	for _, eventType := range subscriberEventTypes {
+		topicNameSet[EventTypeTopicName(topicName, eventType)] = true
+	}
+	topicNames := make([]string, 0, len(topicNameSet))
+	for eventTypeTopicName := range topicNameSet {
+		topicNames = append(topicNames, eventTypeTopicName)
+	}
+	sort.Strings(topicNames)
+	return topicNames
+}
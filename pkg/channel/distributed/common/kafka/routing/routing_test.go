@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Test Data
+const (
+	testTopicName = "test-namespace.test-name"
+	testTypeA     = "com.example.a"
+	testTypeB     = "com.example/b"
+	testTopicA    = testTopicName + ".com.example.a"
+	testTopicB    = testTopicName + ".com.example_b"
+)
+
+// Test The NewEventTypeRouting() Functionality
+func TestNewEventTypeRouting(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		annotations map[string]string
+		expectNil   bool
+		errMsg      string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "No Annotations", annotations: nil, expectNil: true},
+		{name: "Empty Event Types", annotations: map[string]string{constants.EventTypesAnnotation: " , "}, expectNil: true},
+		{name: "Event Types Only", annotations: map[string]string{constants.EventTypesAnnotation: testTypeA + "," + testTypeB}},
+		{
+			name: "Valid Subscriber Event Types",
+			annotations: map[string]string{
+				constants.EventTypesAnnotation:           testTypeA + "," + testTypeB,
+				constants.SubscriberEventTypesAnnotation: `{"uid1":["` + testTypeA + `"]}`,
+			},
+		},
+		{
+			name: "Invalid Subscriber Event Types JSON",
+			annotations: map[string]string{
+				constants.EventTypesAnnotation:           testTypeA,
+				constants.SubscriberEventTypesAnnotation: `["` + testTypeA + `"]`,
+			},
+			expectNil: true,
+			errMsg:    "invalid kafka.eventing.knative.dev/subscriber-event-types annotation",
+		},
+		{
+			name: "Unrouted Subscriber Event Type",
+			annotations: map[string]string{
+				constants.EventTypesAnnotation:           testTypeA,
+				constants.SubscriberEventTypesAnnotation: `{"uid1":["` + testTypeB + `"]}`,
+			},
+			expectNil: true,
+			errMsg:    "subscriber uid1 declares event type \"com.example/b\" which is not listed in the kafka.eventing.knative.dev/event-types annotation",
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eventTypeRouting, err := NewEventTypeRouting(testCase.annotations)
+			assert.Equal(t, testCase.expectNil, eventTypeRouting == nil)
+			if len(testCase.errMsg) > 0 {
+				assert.NotNil(t, err)
+				assert.True(t, strings.HasPrefix(err.Error(), testCase.errMsg))
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// Test The EventTypeTopicName() Functionality
+func TestEventTypeTopicName(t *testing.T) {
+	assert.Equal(t, testTopicA, EventTypeTopicName(testTopicName, testTypeA))
+	assert.Equal(t, testTopicB, EventTypeTopicName(testTopicName, testTypeB))
+	assert.Len(t, EventTypeTopicName(testTopicName, strings.Repeat("x", 300)), constants.MaxTopicNameLength)
+}
+
+// Test The EventTypeRouting Topic Functions
+func TestEventTypeRoutingTopics(t *testing.T) {
+
+	// Test The Nil (Disabled) EventTypeRouting
+	var disabledRouting *EventTypeRouting
+	assert.Equal(t, testTopicName, disabledRouting.ProducerTopic(testTopicName, testTypeA))
+	assert.Nil(t, disabledRouting.Topics(testTopicName))
+	assert.Equal(t, []string{testTopicName}, disabledRouting.ConsumerTopics(testTopicName, "uid1"))
+
+	// Test An Enabled EventTypeRouting
+	eventTypeRouting, err := NewEventTypeRouting(map[string]string{
+		constants.EventTypesAnnotation:           testTypeA + "," + testTypeB,
+		constants.SubscriberEventTypesAnnotation: `{"uid1":["` + testTypeB + `","` + testTypeB + `"],"uid2":[]}`,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testTopicA, eventTypeRouting.ProducerTopic(testTopicName, testTypeA))
+	assert.Equal(t, testTopicName, eventTypeRouting.ProducerTopic(testTopicName, "com.example.unrouted"))
+	assert.Equal(t, []string{testTopicA, testTopicB}, eventTypeRouting.Topics(testTopicName))
+	assert.Equal(t, []string{testTopicB}, eventTypeRouting.ConsumerTopics(testTopicName, "uid1"))
+	assert.Equal(t, []string{testTopicName, testTopicA, testTopicB}, eventTypeRouting.ConsumerTopics(testTopicName, "uid2"))
+	assert.Equal(t, []string{testTopicName, testTopicA, testTopicB}, eventTypeRouting.ConsumerTopics(testTopicName, "uid3"))
+}
@@ -22,19 +22,28 @@ import (
 	"crypto/x509"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/ghodss/yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
+	"knative.dev/eventing-kafka/pkg/common/client"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/logging"
 	"knative.dev/pkg/system"
 )
 
@@ -63,6 +72,119 @@ func UpdateSaramaConfig(config *sarama.Config, clientId string, username string,
 	config.Producer.Return.Successes = true
 }
 
+// Update The Sarama Config To Authenticate With Access Tokens From The Specified Provider (SASL/OAUTHBEARER)
+// A nil TokenProvider Leaves The Sarama Config As-Is (e.g. Username / Password Authentication)
+func UpdateSaramaTokenProvider(config *sarama.Config, tokenProvider sarama.AccessTokenProvider) {
+	if tokenProvider != nil {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+		config.Net.SASL.TokenProvider = tokenProvider
+	}
+}
+
+// Update The Sarama Config With The SASL / TLS Settings Of The KafkaAuthSpec, Whose Secrets Are Read From The
+// Specified Namespace.  A nil KafkaAuthSpec Leaves The Sarama Config As-Is (e.g. Kafka Secret Authentication)
+func UpdateSaramaAuthSpec(ctx context.Context, kubeClient kubernetes.Interface, namespace string, config *sarama.Config, authSpec *bindingsv1beta1.KafkaAuthSpec) error {
+	if authSpec == nil {
+		return nil
+	}
+	return client.UpdateConfigFromSpec(ctx, kubeClient, namespace, *authSpec, config)
+}
+
+// Update The Sarama Config To Dial The Kafka Brokers At The Reachable Addresses To Which The Kafka Config's
+// BrokerAddressRewrites Map Their Advertised Addresses.  No BrokerAddressRewrites Leaves The Sarama Config As-Is.
+func UpdateSaramaBrokerAddressRewrites(config *sarama.Config, kafkaConfig commonconfig.EKKafkaConfig) error {
+	return client.UpdateConfigBrokerAddressRewrites(config, kafkaConfig.BrokerAddressRewrites)
+}
+
+// Update The Sarama Config With The Dispatcher Config's ConsumerGroup Settings (Unset Settings Leave The Sarama Config
+// As-Is), Failing If Any Setting Is Invalid
+func UpdateSaramaConsumerGroup(config *sarama.Config, consumerGroupConfig commonconfig.EKConsumerGroupConfig) error {
+	switch strings.ToLower(consumerGroupConfig.InitialOffset) {
+	case "":
+	case "oldest":
+		config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	case "newest":
+		config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	default:
+		return fmt.Errorf("invalid consumer group initial offset %q (expected \"oldest\" or \"newest\")", consumerGroupConfig.InitialOffset)
+	}
+	for name, setting := range map[string]struct {
+		millis int64
+		target *time.Duration
+	}{
+		"offsets retention":  {consumerGroupConfig.OffsetsRetentionMillis, &config.Consumer.Offsets.Retention},
+		"session timeout":    {consumerGroupConfig.SessionTimeoutMillis, &config.Consumer.Group.Session.Timeout},
+		"heartbeat interval": {consumerGroupConfig.HeartbeatIntervalMillis, &config.Consumer.Group.Heartbeat.Interval},
+		"rebalance timeout":  {consumerGroupConfig.RebalanceTimeoutMillis, &config.Consumer.Group.Rebalance.Timeout},
+	} {
+		if setting.millis < 0 {
+			return fmt.Errorf("invalid consumer group %s %dms (must not be negative)", name, setting.millis)
+		} else if setting.millis > 0 {
+			*setting.target = time.Duration(setting.millis) * time.Millisecond
+		}
+	}
+	if config.Consumer.Group.Heartbeat.Interval >= config.Consumer.Group.Session.Timeout {
+		return fmt.Errorf("consumer group heartbeat interval %v must be less than the session timeout %v", config.Consumer.Group.Heartbeat.Interval, config.Consumer.Group.Session.Timeout)
+	}
+	return nil
+}
+
+// Update The Sarama Config To Connect To The Kafka Brokers Through The Kafka Config's Egress Proxy, Whose Credentials
+// (If Any) Are Read From The Proxy Secret In The Specified Namespace.  No Proxy URL Leaves The Sarama Config As-Is.
+// Must Precede UpdateSaramaBrokerAddressRewrites() So That The Proxy Is Asked For The Rewritten Addresses.
+func UpdateSaramaProxy(ctx context.Context, kubeClient kubernetes.Interface, namespace string, config *sarama.Config, kafkaConfig commonconfig.EKKafkaConfig) error {
+	proxyConfig := kafkaConfig.Proxy
+	if len(proxyConfig.URL) == 0 {
+		return nil
+	}
+	var username, password string
+	if len(proxyConfig.SecretName) > 0 {
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, proxyConfig.SecretName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to read proxy secret %s/%s: %w", namespace, proxyConfig.SecretName, err)
+		}
+		username, password = string(secret.Data[constants.ProxyUsernameKey]), string(secret.Data[constants.ProxyPasswordKey])
+	}
+	return client.UpdateConfigProxy(config, proxyConfig.URL, username, password)
+}
+
+// Carry Forward The Egress Proxy & Broker Address Rewrites (If Any) Of The Current Sarama Config Into A New Sarama
+// Config (The Dialers Are Rebuilt From The New Sarama Config's Net Settings)
+func CarryForwardSaramaDialer(newConfig *sarama.Config, currentConfig *sarama.Config) {
+	client.CarryForwardDialer(newConfig, currentConfig)
+}
+
+// Utility Function For Rendering The ClientID Of A Component From The Kafka ClientIdTemplate (Defaults To The Component)
+// The ChannelKey ("namespace/name") And PodName Are Optional & Left Empty In The Template If Not Applicable
+func NewClientId(kafkaConfig commonconfig.EKKafkaConfig, component string, channelKey string, podName string) (string, error) {
+	fields := client.ClientIDFields{Component: component, Pod: podName}
+	if len(channelKey) > 0 {
+		channelKeyParts := strings.SplitN(channelKey, "/", 2)
+		if len(channelKeyParts) == 2 {
+			fields.Namespace, fields.Channel = channelKeyParts[0], channelKeyParts[1]
+		} else {
+			fields.Channel = channelKey
+		}
+	}
+	return client.NewClientID(kafkaConfig.ClientIdTemplate, fields)
+}
+
+// Utility Function For Blocking Until The Kafka Brokers Are Reachable, Probing With The Exponential Backoff Of The
+// Kafka StartupWait Config (Unset Values Are Defaulted).  Returns An Error If They Remain Unreachable After MaxWait.
+func WaitForBrokers(ctx context.Context, logger *zap.Logger, kafkaConfig commonconfig.EKKafkaConfig, config *sarama.Config, brokers []string) error {
+	return client.WaitForBrokers(ctx, logger, brokers, config, NewBrokerWaitConfig(kafkaConfig.StartupWait))
+}
+
+// Utility Function For Converting The StartupWait Config Into The Durations Of A BrokerWaitConfig
+func NewBrokerWaitConfig(startupWait commonconfig.EKStartupWaitConfig) client.BrokerWaitConfig {
+	return client.BrokerWaitConfig{
+		MaxWait:        time.Duration(startupWait.MaxWaitSeconds) * time.Second,
+		InitialBackoff: time.Duration(startupWait.InitialBackoffMillis) * time.Millisecond,
+		MaxBackoff:     time.Duration(startupWait.MaxBackoffMillis) * time.Millisecond,
+	}
+}
+
 //
 // Extract (Parse & Remove) Top Level Kafka Version From Specified Sarama Confirm YAML String
 //
@@ -108,6 +230,53 @@ func extractKafkaVersion(saramaConfigYamlString string) (string, sarama.KafkaVer
 	}
 }
 
+//
+// Extract (Parse & Remove) The Consumer.Group.Rebalance.Strategy From Specified Sarama Config YAML String
+//
+// The Sarama.Config struct contains a Consumer.Group.Rebalance.Strategy field of type sarama.BalanceStrategy
+// which is an interface that we cannot unmarshal the yaml into.  Therefore, we instead support the user
+// providing the name of the strategy (e.g. "range", "roundrobin" or "sticky") which we will "extract" out
+// of the YAML string and return as an actual sarama.BalanceStrategy.  In the case where the user has NOT
+// specified a Strategy we will return nil (leaving the Sarama default in place).
+//
+func extractRebalanceStrategy(saramaConfigYamlString string) (string, sarama.BalanceStrategy, error) {
+
+	// Define Inline Struct To Marshall The Rebalance Strategy Name Into
+	type rebalanceStrategyShell struct {
+		Consumer struct {
+			Group struct {
+				Rebalance struct {
+					Strategy string
+				}
+			}
+		}
+	}
+
+	// Unmarshal The Rebalance Strategy Into The Shell
+	shell := &rebalanceStrategyShell{}
+	err := yaml.Unmarshal([]byte(saramaConfigYamlString), shell)
+	if err != nil {
+		return saramaConfigYamlString, nil, err
+	}
+
+	// Exit Early If No Rebalance Strategy
+	strategyName := shell.Consumer.Group.Rebalance.Strategy
+	if len(strategyName) <= 0 {
+		return saramaConfigYamlString, nil, nil
+	}
+
+	// Attempt To Parse The Strategy Name Into A Sarama.BalanceStrategy
+	strategy, err := consumer.NewBalanceStrategy(strategyName)
+	if err != nil {
+		return saramaConfigYamlString, nil, err
+	}
+
+	// Remove The Strategy Line From The Sarama Config YAML String (Preserving The Surrounding Lines)
+	regex := regexp.MustCompile(`(?m)^[ \t]*Strategy:[ \t]*"?` + regexp.QuoteMeta(strategyName) + `"?[ \t]*(\r?\n|$)`)
+	updatedSaramaConfigYamlBytes := regex.ReplaceAll([]byte(saramaConfigYamlString), []byte{})
+	return string(updatedSaramaConfigYamlBytes), strategy, nil
+}
+
 /* Extract (Parse & Remove) TLS.Config Level RootPEMs From Specified Sarama Confirm YAML String
 
 The Sarama.Config struct contains Net.TLS.Config which is a *tls.Config which cannot be parsed.
@@ -224,12 +393,25 @@ func ConfigEqual(config1, config2 *sarama.Config, ignore ...interface{}) bool {
 	// have those fields, and results in a nil pointer panic if used in the IgnoreUnexported list indirectly
 	// like config1.Version is (Version is required to be present in a sarama.Config struct).
 
-	ignoredUnexported := cmpopts.IgnoreUnexported(config1.Version, x509.CertPool{}, tls.Config{})
+	ignoredUnexported := cmpopts.IgnoreUnexported(config1.Version, x509.CertPool{}, tls.Config{}, net.Dialer{})
+
+	// The rebalance strategies are ignored by type above, so compare them by name instead
+	if rebalanceStrategyName(config1) != rebalanceStrategyName(config2) {
+		return false
+	}
 
 	// Compare the two sarama config structs, ignoring types and unexported fields as specified
 	return cmp.Equal(config1, config2, ignoredTypes, ignoredUnexported)
 }
 
+// rebalanceStrategyName returns the name of the consumer group rebalance strategy of the config, if any.
+func rebalanceStrategyName(config *sarama.Config) string {
+	if config.Consumer.Group.Rebalance.Strategy == nil {
+		return ""
+	}
+	return config.Consumer.Group.Rebalance.Strategy.Name()
+}
+
 // Extract The Sarama-Specific Settings From A ConfigMap And Merge Them With Existing Settings
 // If config Is nil, A New sarama.Config Struct Will Be Created With Default Values
 func MergeSaramaSettings(config *sarama.Config, configMap *corev1.ConfigMap) (*sarama.Config, error) {
@@ -260,6 +442,12 @@ func MergeSaramaSettings(config *sarama.Config, configMap *corev1.ConfigMap) (*s
 		return nil, fmt.Errorf("failed to extract KafkaVersion from Sarama Config YAML: err=%s : config=%+v", err, saramaSettingsYamlString)
 	}
 
+	// Extract (Remove) Any Consumer.Group.Rebalance.Strategy Name
+	saramaSettingsYamlString, rebalanceStrategy, err := extractRebalanceStrategy(saramaSettingsYamlString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract Consumer.Group.Rebalance.Strategy from Sarama Config YAML: err=%s : config=%+v", err, saramaSettingsYamlString)
+	}
+
 	// Extract (Remove) Any TLS.Config RootCAs & Set In Sarama.Config
 	saramaSettingsYamlString, certPool, err := extractRootCerts(saramaSettingsYamlString)
 	if err != nil {
@@ -275,6 +463,11 @@ func MergeSaramaSettings(config *sarama.Config, configMap *corev1.ConfigMap) (*s
 	// Override The Custom Parsed KafkaVersion
 	config.Version = kafkaVersion
 
+	// Override Any Custom Parsed Consumer.Group.Rebalance.Strategy
+	if rebalanceStrategy != nil {
+		config.Consumer.Group.Rebalance.Strategy = rebalanceStrategy
+	}
+
 	// Override Any Custom Parsed TLS.Config.RootCAs
 	if certPool != nil && len(certPool.Subjects()) > 0 {
 		config.Net.TLS.Config = &tls.Config{RootCAs: certPool}
@@ -301,10 +494,12 @@ func LoadSettings(ctx context.Context) (*sarama.Config, *commonconfig.EventingKa
 		return nil, nil, fmt.Errorf("attempted to load configuration from empty configmap")
 	}
 
-	// Unmarshal The Eventing-Kafka ConfigMap YAML Into A EventingKafkaSettings Struct
+	// Parse & Migrate The Eventing-Kafka ConfigMap YAML Into A EventingKafkaSettings Struct
 	eventingKafkaConfigString := configMap.Data[commonconfig.EventingKafkaSettingsConfigKey]
-	eventingKafkaConfig := &commonconfig.EventingKafkaConfig{}
-	err = yaml.Unmarshal([]byte(eventingKafkaConfigString), &eventingKafkaConfig)
+	eventingKafkaConfig, warnings, err := commonconfig.ParseEventingKafkaConfig(eventingKafkaConfigString)
+	for _, warning := range warnings {
+		logging.FromContext(ctx).Warnw("Migrated Eventing-Kafka Settings Of An Older Version", zap.String("Migration", warning))
+	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("ConfigMap's eventing-kafka value could not be converted to an EventingKafkaConfig struct: %s : %v", err, eventingKafkaConfigString)
 	}
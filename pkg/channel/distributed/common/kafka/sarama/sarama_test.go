@@ -28,11 +28,16 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	commontesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
+	"knative.dev/eventing-kafka/pkg/common/client"
 	injectionclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/system"
 )
@@ -132,6 +137,19 @@ Consumer:
     Retention: 604800000000000
   Return:
     Errors: true
+`
+	EKDefaultSaramaConfigWithRebalanceStrategy = `
+Net:
+  SASL:
+    Mechanism: PLAIN
+    Version: 1
+Consumer:
+  Group:
+    Rebalance:
+      Strategy: sticky
+      Timeout: 90000000000
+  Return:
+    Errors: true
 `
 	EKDefaultSaramaConfigWithInsecureSkipVerify = `
 Net:
@@ -190,6 +208,202 @@ func TestUpdateSaramaConfig(t *testing.T) {
 	assert.Nil(t, config.Net.TLS.Config)
 }
 
+// Test The UpdateSaramaTokenProvider() Functionality
+func TestUpdateSaramaTokenProvider(t *testing.T) {
+
+	// A nil TokenProvider Leaves The Config As-Is
+	config := sarama.NewConfig()
+	UpdateSaramaTokenProvider(config, nil)
+	assert.False(t, config.Net.SASL.Enable)
+	assert.Nil(t, config.Net.SASL.TokenProvider)
+
+	// A TokenProvider Enables SASL/OAUTHBEARER
+	tokenProvider := &testTokenProvider{}
+	UpdateSaramaTokenProvider(config, tokenProvider)
+	assert.True(t, config.Net.SASL.Enable)
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeOAuth), config.Net.SASL.Mechanism)
+	assert.Equal(t, tokenProvider, config.Net.SASL.TokenProvider)
+}
+
+// Test The UpdateSaramaBrokerAddressRewrites() & CarryForwardSaramaDialer() Functionality
+func TestUpdateSaramaBrokerAddressRewrites(t *testing.T) {
+
+	// No BrokerAddressRewrites Leaves The Config As-Is
+	config := sarama.NewConfig()
+	assert.Nil(t, UpdateSaramaBrokerAddressRewrites(config, commonconfig.EKKafkaConfig{}))
+	assert.False(t, config.Net.Proxy.Enable)
+
+	// Invalid BrokerAddressRewrites Are Rejected
+	assert.NotNil(t, UpdateSaramaBrokerAddressRewrites(config, commonconfig.EKKafkaConfig{BrokerAddressRewrites: map[string]string{"kafka-0": "localhost"}}))
+
+	// BrokerAddressRewrites Dial Via A BrokerAddressDialer
+	rewrites := map[string]string{"kafka-0.internal:9092": "localhost:19092"}
+	assert.Nil(t, UpdateSaramaBrokerAddressRewrites(config, commonconfig.EKKafkaConfig{BrokerAddressRewrites: rewrites}))
+	assert.True(t, config.Net.Proxy.Enable)
+	assert.Equal(t, rewrites, config.Net.Proxy.Dialer.(*client.BrokerAddressDialer).Rewrites)
+
+	// The Rewrites Are Carried Forward Into A New Config, Which Is Then Equal To The Current Config
+	newConfig := sarama.NewConfig()
+	assert.False(t, ConfigEqual(newConfig, config))
+	CarryForwardSaramaDialer(newConfig, config)
+	assert.Equal(t, rewrites, newConfig.Net.Proxy.Dialer.(*client.BrokerAddressDialer).Rewrites)
+	assert.True(t, ConfigEqual(newConfig, config))
+
+	// A Config Without Rewrites Carries Nothing Forward
+	newConfig = sarama.NewConfig()
+	CarryForwardSaramaDialer(newConfig, sarama.NewConfig())
+	assert.False(t, newConfig.Net.Proxy.Enable)
+}
+
+// Test The UpdateSaramaConsumerGroup() Functionality
+func TestUpdateSaramaConsumerGroup(t *testing.T) {
+
+	// Unset Settings Leave The Config As-Is
+	config := sarama.NewConfig()
+	assert.Nil(t, UpdateSaramaConsumerGroup(config, commonconfig.EKConsumerGroupConfig{}))
+	assert.True(t, ConfigEqual(sarama.NewConfig(), config))
+
+	// All Settings Are Applied
+	assert.Nil(t, UpdateSaramaConsumerGroup(config, commonconfig.EKConsumerGroupConfig{
+		OffsetsRetentionMillis:  1209600000,
+		InitialOffset:           "Oldest",
+		SessionTimeoutMillis:    30000,
+		HeartbeatIntervalMillis: 5000,
+		RebalanceTimeoutMillis:  120000,
+	}))
+	assert.Equal(t, 14*24*time.Hour, config.Consumer.Offsets.Retention)
+	assert.Equal(t, sarama.OffsetOldest, config.Consumer.Offsets.Initial)
+	assert.Equal(t, 30*time.Second, config.Consumer.Group.Session.Timeout)
+	assert.Equal(t, 5*time.Second, config.Consumer.Group.Heartbeat.Interval)
+	assert.Equal(t, 2*time.Minute, config.Consumer.Group.Rebalance.Timeout)
+	assert.Nil(t, config.Validate())
+
+	// Invalid Settings Are Rejected
+	assert.NotNil(t, UpdateSaramaConsumerGroup(sarama.NewConfig(), commonconfig.EKConsumerGroupConfig{InitialOffset: "latest"}))
+	assert.NotNil(t, UpdateSaramaConsumerGroup(sarama.NewConfig(), commonconfig.EKConsumerGroupConfig{OffsetsRetentionMillis: -1}))
+	assert.NotNil(t, UpdateSaramaConsumerGroup(sarama.NewConfig(), commonconfig.EKConsumerGroupConfig{HeartbeatIntervalMillis: 10000}))
+}
+
+// Test The UpdateSaramaProxy() Functionality
+func TestUpdateSaramaProxy(t *testing.T) {
+
+	// Create A Secret With The Proxy Credentials
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: commonconstants.KnativeEventingNamespace, Name: "kafka-proxy"},
+		Data:       map[string][]byte{constants.ProxyUsernameKey: []byte("TestUser"), constants.ProxyPasswordKey: []byte("TestPassword")},
+	})
+
+	// No Proxy URL Leaves The Config As-Is
+	config := sarama.NewConfig()
+	assert.Nil(t, UpdateSaramaProxy(context.TODO(), kubeClient, commonconstants.KnativeEventingNamespace, config, commonconfig.EKKafkaConfig{}))
+	assert.False(t, config.Net.Proxy.Enable)
+
+	// A Missing Secret Or Invalid Proxy URL Is An Error
+	kafkaConfig := commonconfig.EKKafkaConfig{Proxy: commonconfig.EKProxyConfig{URL: "socks5://proxy.example.com:1080", SecretName: "missing"}}
+	assert.NotNil(t, UpdateSaramaProxy(context.TODO(), kubeClient, commonconstants.KnativeEventingNamespace, config, kafkaConfig))
+	kafkaConfig = commonconfig.EKKafkaConfig{Proxy: commonconfig.EKProxyConfig{URL: "ftp://proxy.example.com:21"}}
+	assert.NotNil(t, UpdateSaramaProxy(context.TODO(), kubeClient, commonconstants.KnativeEventingNamespace, config, kafkaConfig))
+	assert.False(t, config.Net.Proxy.Enable)
+
+	// The Proxy Is Authenticated With The Secret's Credentials
+	kafkaConfig = commonconfig.EKKafkaConfig{Proxy: commonconfig.EKProxyConfig{URL: "socks5://proxy.example.com:1080", SecretName: "kafka-proxy"}}
+	assert.Nil(t, UpdateSaramaProxy(context.TODO(), kubeClient, commonconstants.KnativeEventingNamespace, config, kafkaConfig))
+	assert.True(t, config.Net.Proxy.Enable)
+	proxyDialer, ok := config.Net.Proxy.Dialer.(*client.ProxyDialer)
+	assert.True(t, ok)
+	assert.Equal(t, "proxy.example.com:1080", proxyDialer.Address)
+	assert.Equal(t, "TestUser", proxyDialer.Username)
+	assert.Equal(t, "TestPassword", proxyDialer.Password)
+
+	// The Proxy Is Carried Forward Into A New Config, Which Is Then Equal To The Current Config
+	newConfig := sarama.NewConfig()
+	CarryForwardSaramaDialer(newConfig, config)
+	assert.True(t, ConfigEqual(newConfig, config))
+}
+
+// Test The UpdateSaramaAuthSpec() Functionality
+func TestUpdateSaramaAuthSpec(t *testing.T) {
+
+	// Create A Secret Referenced By The KafkaAuthSpec
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: commonconstants.KnativeEventingNamespace, Name: "kafka-auth"},
+		Data:       map[string][]byte{"user": []byte("TestUser"), "password": []byte("TestPassword")},
+	})
+	authSpec := &bindingsv1beta1.KafkaAuthSpec{
+		BootstrapServers: []string{"TestBroker:9092"},
+		Net: bindingsv1beta1.KafkaNetSpec{
+			SASL: bindingsv1beta1.KafkaSASLSpec{
+				Enable: true,
+				User: bindingsv1beta1.SecretValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-auth"},
+					Key:                  "user",
+				}},
+				Password: bindingsv1beta1.SecretValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-auth"},
+					Key:                  "password",
+				}},
+			},
+		},
+	}
+
+	// A nil KafkaAuthSpec Leaves The Config As-Is
+	config := sarama.NewConfig()
+	assert.Nil(t, UpdateSaramaAuthSpec(context.TODO(), kubeClient, commonconstants.KnativeEventingNamespace, config, nil))
+	assert.False(t, config.Net.SASL.Enable)
+
+	// A KafkaAuthSpec Applies The SASL Settings From The Referenced Secret
+	assert.Nil(t, UpdateSaramaAuthSpec(context.TODO(), kubeClient, commonconstants.KnativeEventingNamespace, config, authSpec))
+	assert.True(t, config.Net.SASL.Enable)
+	assert.Equal(t, "TestUser", config.Net.SASL.User)
+	assert.Equal(t, "TestPassword", config.Net.SASL.Password)
+
+	// Secrets Are Read From The Specified Namespace
+	assert.NotNil(t, UpdateSaramaAuthSpec(context.TODO(), kubeClient, "other-namespace", config, authSpec))
+}
+
+// Test The NewClientId() Functionality
+func TestNewClientId(t *testing.T) {
+
+	// Without A ClientIdTemplate The Component Is Used
+	clientId, err := NewClientId(commonconfig.EKKafkaConfig{}, "TestComponent", "TestNamespace/TestChannel", "TestPod")
+	assert.Nil(t, err)
+	assert.Equal(t, "TestComponent", clientId)
+
+	// The ChannelKey Is Split Into Namespace & Channel
+	kafkaConfig := commonconfig.EKKafkaConfig{ClientIdTemplate: "{{.Component}}.{{.Namespace}}.{{.Channel}}.{{.Pod}}"}
+	clientId, err = NewClientId(kafkaConfig, "TestComponent", "TestNamespace/TestChannel", "TestPod")
+	assert.Nil(t, err)
+	assert.Equal(t, "TestComponent.TestNamespace.TestChannel.TestPod", clientId)
+
+	// Components Without A Channel Leave Those Fields Empty
+	clientId, err = NewClientId(kafkaConfig, "TestComponent", "", "TestPod")
+	assert.Nil(t, err)
+	assert.Equal(t, "TestComponent...TestPod", clientId)
+
+	// Invalid Templates Are An Error
+	_, err = NewClientId(commonconfig.EKKafkaConfig{ClientIdTemplate: "{{.Component"}, "TestComponent", "", "")
+	assert.NotNil(t, err)
+}
+
+func TestNewBrokerWaitConfig(t *testing.T) {
+
+	// Unset Values Are Left For The BrokerWaitConfig To Default
+	assert.Equal(t, client.BrokerWaitConfig{}, NewBrokerWaitConfig(commonconfig.EKStartupWaitConfig{}))
+
+	// Seconds & Milliseconds Are Converted To Durations
+	waitConfig := NewBrokerWaitConfig(commonconfig.EKStartupWaitConfig{MaxWaitSeconds: 600, InitialBackoffMillis: 500, MaxBackoffMillis: 10000})
+	assert.Equal(t, 10*time.Minute, waitConfig.MaxWait)
+	assert.Equal(t, 500*time.Millisecond, waitConfig.InitialBackoff)
+	assert.Equal(t, 10*time.Second, waitConfig.MaxBackoff)
+}
+
+// Test AccessTokenProvider Implementation
+type testTokenProvider struct{}
+
+func (p *testTokenProvider) Token() (*sarama.AccessToken, error) {
+	return &sarama.AccessToken{Token: "TestToken"}, nil
+}
+
 // This test is specifically to validate that our default settings (used in 200-eventing-kafka-configmap.yaml)
 // are valid.  If the defaults in the file change, change this test to match for verification purposes.
 func TestLoadDefaultSaramaSettings(t *testing.T) {
@@ -325,6 +539,12 @@ func TestSaramaConfigEqual(t *testing.T) {
 	config2.RackID = "New Rack ID"
 	assert.True(t, ConfigEqual(config1, config2))
 
+	config1.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategySticky
+	assert.False(t, ConfigEqual(config1, config2))
+
+	config2.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategySticky
+	assert.True(t, ConfigEqual(config1, config2))
+
 	// Change a boolean flag in the TLS.Config struct (which is not Sarama-specific) and make sure the compare function
 	// works with those sub-structs as well.
 	config1.Net.TLS.Config = &tls.Config{}
@@ -441,3 +661,39 @@ func TestExtractRootCerts(t *testing.T) {
 	assert.Nil(t, certPool)
 	assert.Nil(t, err)
 }
+
+// Test The Extraction Of The Consumer.Group.Rebalance.Strategy From The Sarama Config YAML
+func TestExtractRebalanceStrategy(t *testing.T) {
+
+	// The Sarama Config YAML String To Test
+	beforeSaramaConfigYaml := EKDefaultSaramaConfigWithRebalanceStrategy
+
+	// Perform The Test (Extract The Rebalance Strategy)
+	afterSaramaConfigYaml, strategy, err := extractRebalanceStrategy(beforeSaramaConfigYaml)
+
+	// Verify The Strategy Was Extracted Successfully Without Affecting The Surrounding Settings
+	assert.Nil(t, err)
+	assert.Equal(t, sarama.BalanceStrategySticky, strategy)
+	assert.False(t, strings.Contains(afterSaramaConfigYaml, "Strategy"))
+	assert.True(t, strings.Contains(afterSaramaConfigYaml, "Rebalance:\n      Timeout: 90000000000\n"))
+
+	// Attempt To Extract Again (Now That There Isn't Any Strategy)
+	finalSaramaConfigYaml, strategy, err := extractRebalanceStrategy(afterSaramaConfigYaml)
+
+	// Verify The YAML String Is Unchanged And Strategy Is Nil
+	assert.Equal(t, afterSaramaConfigYaml, finalSaramaConfigYaml)
+	assert.Nil(t, strategy)
+	assert.Nil(t, err)
+
+	// Verify An Unsupported Strategy Is Rejected
+	_, strategy, err = extractRebalanceStrategy(strings.Replace(beforeSaramaConfigYaml, "sticky", "cooperative-sticky", 1))
+	assert.NotNil(t, err)
+	assert.Nil(t, strategy)
+
+	// Verify The Strategy Is Merged Into The Sarama Config
+	config, err := MergeSaramaSettings(nil, commontesting.GetTestSaramaConfigMap(beforeSaramaConfigYaml, commontesting.TestEKConfig))
+	assert.Nil(t, err)
+	assert.Equal(t, sarama.BalanceStrategySticky, config.Consumer.Group.Rebalance.Strategy)
+	assert.Equal(t, 90*time.Second, config.Consumer.Group.Rebalance.Timeout)
+	assert.True(t, config.Consumer.Return.Errors)
+}
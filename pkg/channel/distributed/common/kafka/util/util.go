@@ -20,7 +20,10 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
 )
 
 // Get The Formatted Kafka Topic Name From The Specified Components
@@ -28,6 +31,11 @@ func TopicName(namespace string, name string) string {
 	return fmt.Sprintf("%s.%s", namespace, name)
 }
 
+// Get The Formatted Kafka ConsumerGroup Id For The Specified Subscriber UID
+func GroupId(subscriberUID string) string {
+	return fmt.Sprintf("kafka.%s", subscriberUID)
+}
+
 // Append The KafkaChannel Service Name Suffix To The Specified String
 func AppendKafkaChannelServiceNameSuffix(channelName string) string {
 	return fmt.Sprintf("%s-%s", channelName, constants.KafkaChannelServiceNameSuffix)
@@ -37,3 +45,120 @@ func AppendKafkaChannelServiceNameSuffix(channelName string) string {
 func TrimKafkaChannelServiceNameSuffix(serviceName string) string {
 	return strings.TrimSuffix(serviceName, "-"+constants.KafkaChannelServiceNameSuffix)
 }
+
+// Get The Convention-Named Kafka DeadLetter Topic Of The Specified Subscriber
+func DeadLetterTopicName(topicName string, subscriberUID string) string {
+	return fmt.Sprintf("%s.%s.%s", topicName, subscriberUID, constants.DeadLetterTopicSuffix)
+}
+
+// Get The Convention-Named Kafka Quarantine Topic Of The Specified KafkaChannel Topic
+func QuarantineTopicName(topicName string) string {
+	return fmt.Sprintf("%s.%s", topicName, constants.QuarantineTopicSuffix)
+}
+
+// Get The ConsumerGroup Id Of The Replicator Of The Specified KafkaChannel Topic
+func ReplicatorGroupId(topicName string) string {
+	return fmt.Sprintf("kafka.%s.%s", topicName, constants.ReplicatorGroupIdSuffix)
+}
+
+// Get The Convention-Named Kafka Checkpoint Topic (Of The Remote Cluster) Of The Specified Replicated KafkaChannel Topic
+func CheckpointTopicName(topicName string) string {
+	return fmt.Sprintf("%s.%s", topicName, constants.CheckpointTopicSuffix)
+}
+
+// Determine Whether The Specified DeadLetterSink URI Is The "kafka:" Shorthand For A Convention-Named DeadLetter Topic
+func IsDeadLetterTopicShorthand(deadLetterSinkURI *apis.URL) bool {
+	return deadLetterSinkURI != nil && deadLetterSinkURI.Scheme == constants.DeadLetterSinkKafkaScheme
+}
+
+//
+// Get The Kafka Topic Which The Subscriber's DeadLetterSink Is Backed By (If Any)
+//
+// The DeadLetterSink is backed by a Kafka Topic when it is either the "kafka:" shorthand (a convention-named
+// Topic per Subscriber), or the address of a KafkaChannel (<name>-kn-channel.<namespace>.svc...) in which case
+// failed events can be produced directly to the KafkaChannel's Topic rather than via HTTP.
+//
+func DeadLetterTopic(topicName string, subscriber *eventingduck.SubscriberSpec) (string, bool) {
+
+	// Get The Subscriber's DeadLetterSink URI (If Any)
+	if subscriber == nil || subscriber.Delivery == nil || subscriber.Delivery.DeadLetterSink == nil || subscriber.Delivery.DeadLetterSink.URI == nil {
+		return "", false
+	}
+	deadLetterSinkURI := subscriber.Delivery.DeadLetterSink.URI
+
+	// The Shorthand Maps To The Convention-Named Topic Of The Subscriber
+	if IsDeadLetterTopicShorthand(deadLetterSinkURI) {
+		return DeadLetterTopicName(topicName, string(subscriber.UID)), true
+	}
+
+	// KafkaChannel Addresses Map To The KafkaChannel's Topic
+	if namespace, name, ok := KafkaChannelOfURL(deadLetterSinkURI); ok {
+		return TopicName(namespace, name), true
+	}
+
+	// Otherwise The DeadLetterSink Is Not Backed By A Kafka Topic
+	return "", false
+}
+
+// Get The Namespace & Name Of The KafkaChannel Whose Address Has The Specified Host (<name>-kn-channel.<namespace>.svc...)
+func KafkaChannelOfHost(host string) (string, string, bool) {
+	hostParts := strings.Split(host, ".")
+	if len(hostParts) >= 3 && hostParts[2] == "svc" && strings.HasSuffix(hostParts[0], "-"+constants.KafkaChannelServiceNameSuffix) {
+		return hostParts[1], TrimKafkaChannelServiceNameSuffix(hostParts[0]), true
+	}
+	return "", "", false
+}
+
+// Get The Path Of The Specified KafkaChannel In Path-Based Addresses (/<namespace>/<name>)
+func KafkaChannelPath(namespace string, name string) string {
+	return fmt.Sprintf("/%s/%s", namespace, name)
+}
+
+// Get The Namespace & Name Of The KafkaChannel Addressed By The Specified Path (/<namespace>/<name>)
+func KafkaChannelOfPath(path string) (string, string, bool) {
+	pathParts := strings.Split(path, "/")
+	if len(pathParts) != 3 || len(pathParts[0]) > 0 {
+		return "", "", false
+	}
+	namespace, name := pathParts[1], pathParts[2]
+	if len(validation.IsDNS1123Label(namespace)) > 0 || len(validation.IsDNS1123Subdomain(name)) > 0 {
+		return "", "", false
+	}
+	return namespace, name, true
+}
+
+// Get The Namespace & Name Of The KafkaChannel Whose Host-Based Or Path-Based Address Is The Specified URL (The Latter
+// Being Recognized By The Host Of A Shared Or Isolated Receiver Service, <...>-receiver.<namespace>.svc...)
+func KafkaChannelOfURL(uri *apis.URL) (string, string, bool) {
+	if uri == nil {
+		return "", "", false
+	}
+	if namespace, name, ok := KafkaChannelOfHost(uri.Host); ok {
+		return namespace, name, true
+	}
+	hostParts := strings.Split(uri.Host, ".")
+	if len(hostParts) >= 3 && hostParts[2] == "svc" && strings.HasSuffix(hostParts[0], "-"+constants.ReceiverServiceSuffix) {
+		return KafkaChannelOfPath(uri.Path)
+	}
+	return "", "", false
+}
+
+// Utility Function For Determining Whether The Specified Receiver Timestamp Is Supported (Empty Is The Default)
+func IsValidReceiverTimestamp(timestamp string) bool {
+	switch timestamp {
+	case "", constants.ReceiverTimestampProduce, constants.ReceiverTimestampCeTime:
+		return true
+	default:
+		return false
+	}
+}
+
+// Utility Function For Determining Whether The Specified Receiver Addressing Is Supported (Empty Is The Default)
+func IsValidReceiverAddressing(addressing string) bool {
+	switch addressing {
+	case "", constants.ReceiverAddressingHost, constants.ReceiverAddressingPath:
+		return true
+	default:
+		return false
+	}
+}
@@ -22,6 +22,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 // Test The TopicName() Functionality
@@ -39,6 +42,11 @@ func TestTopicName(t *testing.T) {
 	assert.Equal(t, expectedTopicName, actualTopicName)
 }
 
+// Test The GroupId() Functionality
+func TestGroupId(t *testing.T) {
+	assert.Equal(t, "kafka.TestSubscriberUID", GroupId("TestSubscriberUID"))
+}
+
 // Test The AppendChannelServiceNameSuffix() Functionality
 func TestAppendChannelServiceNameSuffix(t *testing.T) {
 
@@ -67,3 +75,126 @@ func TestTrimKafkaChannelServiceNameSuffix(t *testing.T) {
 	expectedResult := channelName
 	assert.Equal(t, expectedResult, actualResult)
 }
+
+// Test The QuarantineTopicName() Functionality
+func TestQuarantineTopicName(t *testing.T) {
+	assert.Equal(t, "TestNamespace.TestName.quarantine", QuarantineTopicName(TopicName("TestNamespace", "TestName")))
+}
+
+// Test The ReplicatorGroupId() & CheckpointTopicName() Functionality
+func TestReplication(t *testing.T) {
+	topicName := TopicName("TestNamespace", "TestName")
+	assert.Equal(t, "kafka.TestNamespace.TestName.replicator", ReplicatorGroupId(topicName))
+	assert.Equal(t, "TestNamespace.TestName.checkpoints", CheckpointTopicName(topicName))
+}
+
+// Test The DeadLetterTopic() Functionality
+func TestDeadLetterTopic(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name              string
+		deadLetterSinkURI string
+		expectedTopic     string
+		expectedOk        bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "No DeadLetterSink"},
+		{name: "Kafka Shorthand", deadLetterSinkURI: "kafka:///", expectedTopic: "TestNamespace.TestName.TestSubscriberUID.dlq", expectedOk: true},
+		{name: "KafkaChannel", deadLetterSinkURI: "http://dlq-kn-channel.dlq-namespace.svc.cluster.local", expectedTopic: "dlq-namespace.dlq", expectedOk: true},
+		{name: "Path-Based KafkaChannel", deadLetterSinkURI: "http://kafka-abc123-receiver.knative-eventing.svc.cluster.local/dlq-namespace/dlq", expectedTopic: "dlq-namespace.dlq", expectedOk: true},
+		{name: "Other Service", deadLetterSinkURI: "http://dlq.dlq-namespace.svc.cluster.local"},
+		{name: "Other Service Path", deadLetterSinkURI: "http://dlq.dlq-namespace.svc.cluster.local/dlq-namespace/dlq"},
+		{name: "External", deadLetterSinkURI: "https://dlq-kn-channel.example.com"},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			subscriber := &eventingduck.SubscriberSpec{UID: "TestSubscriberUID"}
+			if len(testCase.deadLetterSinkURI) > 0 {
+				deadLetterSinkURI, err := apis.ParseURL(testCase.deadLetterSinkURI)
+				assert.Nil(t, err)
+				subscriber.Delivery = &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: deadLetterSinkURI}}
+			}
+			topic, ok := DeadLetterTopic(TopicName("TestNamespace", "TestName"), subscriber)
+			assert.Equal(t, testCase.expectedTopic, topic)
+			assert.Equal(t, testCase.expectedOk, ok)
+		})
+	}
+}
+
+// Test The KafkaChannelOfHost() Functionality
+func TestKafkaChannelOfHost(t *testing.T) {
+	namespace, name, ok := KafkaChannelOfHost("my-channel-kn-channel.my-namespace.svc.cluster.local")
+	assert.True(t, ok)
+	assert.Equal(t, "my-namespace", namespace)
+	assert.Equal(t, "my-channel", name)
+	_, _, ok = KafkaChannelOfHost("my-service.my-namespace.svc.cluster.local")
+	assert.False(t, ok)
+	_, _, ok = KafkaChannelOfHost("my-channel-kn-channel.example.com")
+	assert.False(t, ok)
+}
+
+// Test The KafkaChannelPath() & KafkaChannelOfPath() Functionality
+func TestKafkaChannelOfPath(t *testing.T) {
+	assert.Equal(t, "/my-namespace/my-channel", KafkaChannelPath("my-namespace", "my-channel"))
+	namespace, name, ok := KafkaChannelOfPath("/my-namespace/my-channel")
+	assert.True(t, ok)
+	assert.Equal(t, "my-namespace", namespace)
+	assert.Equal(t, "my-channel", name)
+	for _, path := range []string{"", "/", "/my-namespace", "/my-namespace/", "//my-channel", "/my-namespace/my-channel/", "/my-namespace/my-channel/more", "my-namespace/my-channel", "/My_Namespace/my-channel", "/my-namespace/My_Channel"} {
+		_, _, ok = KafkaChannelOfPath(path)
+		assert.False(t, ok, path)
+	}
+}
+
+// Test The KafkaChannelOfURL() Functionality
+func TestKafkaChannelOfURL(t *testing.T) {
+
+	// Define The TestCases
+	testCases := []struct {
+		uri               string
+		expectedNamespace string
+		expectedName      string
+		expectedOk        bool
+	}{
+		{uri: "http://my-channel-kn-channel.my-namespace.svc.cluster.local", expectedNamespace: "my-namespace", expectedName: "my-channel", expectedOk: true},
+		{uri: "http://kafka-abc123-receiver.knative-eventing.svc.cluster.local/my-namespace/my-channel", expectedNamespace: "my-namespace", expectedName: "my-channel", expectedOk: true},
+		{uri: "http://kafka-abc123-receiver.knative-eventing.svc.cluster.local/"},
+		{uri: "http://my-service.my-namespace.svc.cluster.local/my-namespace/my-channel"},
+		{uri: "https://kafka-receiver.example.com/my-namespace/my-channel"},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.uri, func(t *testing.T) {
+			uri, err := apis.ParseURL(testCase.uri)
+			assert.Nil(t, err)
+			namespace, name, ok := KafkaChannelOfURL(uri)
+			assert.Equal(t, testCase.expectedNamespace, namespace)
+			assert.Equal(t, testCase.expectedName, name)
+			assert.Equal(t, testCase.expectedOk, ok)
+		})
+	}
+	_, _, ok := KafkaChannelOfURL(nil)
+	assert.False(t, ok)
+}
+
+// Test The IsValidReceiverTimestamp() Functionality
+func TestIsValidReceiverTimestamp(t *testing.T) {
+	assert.True(t, IsValidReceiverTimestamp(""))
+	assert.True(t, IsValidReceiverTimestamp(constants.ReceiverTimestampProduce))
+	assert.True(t, IsValidReceiverTimestamp(constants.ReceiverTimestampCeTime))
+	assert.False(t, IsValidReceiverTimestamp("invalid"))
+}
+
+// Test The IsValidReceiverAddressing() Functionality
+func TestIsValidReceiverAddressing(t *testing.T) {
+	assert.True(t, IsValidReceiverAddressing(""))
+	assert.True(t, IsValidReceiverAddressing(constants.ReceiverAddressingHost))
+	assert.True(t, IsValidReceiverAddressing(constants.ReceiverAddressingPath))
+	assert.False(t, IsValidReceiverAddressing("invalid"))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package latency
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+)
+
+// Kafka Headers Holding The Hop Timestamps (Unix Nanoseconds) Injected By The Receiver
+const (
+	ReceivedTimeHeader = "kn-received-time" // The Receiver Received The Event
+	ProducedTimeHeader = "kn-produced-time" // The Receiver Produced The Event To Kafka
+)
+
+// Context Key Of The Time The Receiver Received An Event
+type receivedTimeKey struct{}
+
+// Return A Context Carrying The Time The Receiver Received An Event
+func WithReceivedTime(ctx context.Context, receivedTime time.Time) context.Context {
+	return context.WithValue(ctx, receivedTimeKey{}, receivedTime)
+}
+
+// Get The Time The Receiver Received An Event From The Context (Zero If Not Present)
+func ReceivedTime(ctx context.Context) time.Time {
+	receivedTime, _ := ctx.Value(receivedTimeKey{}).(time.Time)
+	return receivedTime
+}
+
+// Create The Hop Timestamp Headers Of An Event Produced By The Receiver (The Received Time Is Omitted If Zero)
+func ProducerHeaders(receivedTime time.Time, producedTime time.Time) []sarama.RecordHeader {
+	headers := make([]sarama.RecordHeader, 0, 2)
+	if !receivedTime.IsZero() {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(ReceivedTimeHeader), Value: formatTime(receivedTime)})
+	}
+	return append(headers, sarama.RecordHeader{Key: []byte(ProducedTimeHeader), Value: formatTime(producedTime)})
+}
+
+//
+// Hop Timestamps Of A Single Event
+//
+// The receiver (when enabled) injects the times at which it received and produced each event as Kafka headers, and
+// the dispatcher adds the times at which it consumed and successfully dispatched the event.  The latency of each hop
+// between two known timestamps, and the end-to-end latency, are then recorded as histograms per topic (KafkaChannel)
+// & subscription.  Records produced without the headers fall back to their Kafka timestamp as the produced time, so
+// that only the produce & end-to-end latencies are unavailable.
+//
+type Hops struct {
+	Received   time.Time
+	Produced   time.Time
+	Consumed   time.Time
+	Dispatched time.Time
+}
+
+// Create The Hops Of The Specified ConsumerMessage, Consumed & Dispatched At The Specified Times
+func NewHops(consumerMessage *sarama.ConsumerMessage, consumedTime time.Time, dispatchedTime time.Time) Hops {
+	hops := Hops{Produced: consumerMessage.Timestamp, Consumed: consumedTime, Dispatched: dispatchedTime}
+	for _, header := range consumerMessage.Headers {
+		if header == nil {
+			continue
+		}
+		switch string(header.Key) {
+		case ReceivedTimeHeader:
+			hops.Received = parseTime(header.Value)
+		case ProducedTimeHeader:
+			if producedTime := parseTime(header.Value); !producedTime.IsZero() {
+				hops.Produced = producedTime
+			}
+		}
+	}
+	return hops
+}
+
+// Get The Latencies Of The Hops Between Known Timestamps (By Hop Label Value)
+func (h Hops) Latencies() map[string]time.Duration {
+	latencies := make(map[string]time.Duration, 4)
+	addLatency(latencies, metrics.HopProduce, h.Received, h.Produced)
+	addLatency(latencies, metrics.HopConsume, h.Produced, h.Consumed)
+	addLatency(latencies, metrics.HopDispatch, h.Consumed, h.Dispatched)
+	addLatency(latencies, metrics.HopEndToEnd, h.Received, h.Dispatched)
+	return latencies
+}
+
+// Record The Latencies Of The Hops Between Known Timestamps For The Specified Topic & Subscription
+func (h Hops) Record(logger *zap.Logger, topicName string, subscriptionUID string) {
+	for hop, latency := range h.Latencies() {
+		metrics.RecordEventLatency(logger, topicName, subscriptionUID, hop, latency)
+	}
+}
+
+// Utility Function For Adding The Latency Between Two Timestamps (Skipped If Either Is Unknown)
+func addLatency(latencies map[string]time.Duration, hop string, from time.Time, to time.Time) {
+	if from.IsZero() || to.IsZero() {
+		return
+	}
+	latency := to.Sub(from)
+	if latency < 0 {
+		latency = 0 // Clock Skew Between The Receiver & Dispatcher Nodes
+	}
+	latencies[hop] = latency
+}
+
+// Utility Function For Formatting A Hop Timestamp Header Value
+func formatTime(t time.Time) []byte {
+	return []byte(strconv.FormatInt(t.UnixNano(), 10))
+}
+
+// Utility Function For Parsing A Hop Timestamp Header Value (Zero If Invalid)
+func parseTime(value []byte) time.Time {
+	nanos, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil || nanos <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package latency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+)
+
+// Test The WithReceivedTime() & ReceivedTime() Functionality
+func TestReceivedTime(t *testing.T) {
+	receivedTime := time.Now()
+	assert.True(t, ReceivedTime(context.TODO()).IsZero())
+	assert.Equal(t, receivedTime, ReceivedTime(WithReceivedTime(context.TODO(), receivedTime)))
+}
+
+// Test The ProducerHeaders() & NewHops() Functionality
+func TestNewHops(t *testing.T) {
+
+	// Test Data
+	receivedTime := time.Unix(100, 0)
+	producedTime := time.Unix(100, int64(5*time.Millisecond))
+	recordTime := time.Unix(101, 0)
+	consumedTime := time.Unix(102, 0)
+	dispatchedTime := time.Unix(103, 0)
+
+	// Create A ConsumerMessage With The Hop Timestamp Headers Of The Receiver
+	consumerMessage := &sarama.ConsumerMessage{Timestamp: recordTime}
+	for _, header := range ProducerHeaders(receivedTime, producedTime) {
+		header := header
+		consumerMessage.Headers = append(consumerMessage.Headers, &header)
+	}
+
+	// Verify The Hops Are Parsed From The Headers
+	hops := NewHops(consumerMessage, consumedTime, dispatchedTime)
+	assert.Equal(t, Hops{Received: receivedTime, Produced: producedTime, Consumed: consumedTime, Dispatched: dispatchedTime}, hops)
+
+	// Verify The Produced Time Falls Back To The Record Timestamp Without Headers
+	hops = NewHops(&sarama.ConsumerMessage{Timestamp: recordTime, Headers: []*sarama.RecordHeader{nil, {Key: []byte(ReceivedTimeHeader), Value: []byte("invalid")}}}, consumedTime, dispatchedTime)
+	assert.Equal(t, Hops{Produced: recordTime, Consumed: consumedTime, Dispatched: dispatchedTime}, hops)
+
+	// Verify The Received Header Is Omitted If Unknown
+	assert.Len(t, ProducerHeaders(time.Time{}, producedTime), 1)
+}
+
+// Test The Hops' Latencies() Functionality
+func TestHopsLatencies(t *testing.T) {
+
+	// All Hops Are Known
+	hops := Hops{Received: time.Unix(100, 0), Produced: time.Unix(101, 0), Consumed: time.Unix(103, 0), Dispatched: time.Unix(106, 0)}
+	assert.Equal(t, map[string]time.Duration{
+		metrics.HopProduce:  time.Second,
+		metrics.HopConsume:  2 * time.Second,
+		metrics.HopDispatch: 3 * time.Second,
+		metrics.HopEndToEnd: 6 * time.Second,
+	}, hops.Latencies())
+
+	// The Received Time Is Unknown & The Consumed Time Is Skewed
+	hops = Hops{Produced: time.Unix(100, 0), Consumed: time.Unix(99, 0), Dispatched: time.Unix(99, int64(20*time.Millisecond))}
+	assert.Equal(t, map[string]time.Duration{metrics.HopConsume: 0, metrics.HopDispatch: 20 * time.Millisecond}, hops.Latencies())
+}
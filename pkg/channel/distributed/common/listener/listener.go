@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listener
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// The Supported IP Families (The Empty Default Binds To The Addresses Of Both Families On Dual-Stack Nodes)
+const (
+	IPFamilyDualStack = ""
+	IPFamilyIPv4      = "IPv4"
+	IPFamilyIPv6      = "IPv6"
+)
+
+// Package Variables
+var (
+	ipFamily     = IPFamilyDualStack
+	ipFamilyLock sync.RWMutex
+)
+
+// Validate The Network Configuration
+func ValidateNetworkConfig(networkConfig config.EKNetworkConfig) error {
+	switch networkConfig.IPFamily {
+	case IPFamilyDualStack, IPFamilyIPv4, IPFamilyIPv6:
+		return nil
+	default:
+		return fmt.Errorf("invalid network ipFamily %q (must be empty, %s or %s)", networkConfig.IPFamily, IPFamilyIPv4, IPFamilyIPv6)
+	}
+}
+
+// Set The IP Family To Which Subsequently Created Listeners Bind (Assumes A Valid Config)
+func SetIPFamily(networkConfig config.EKNetworkConfig) {
+	ipFamilyLock.Lock()
+	defer ipFamilyLock.Unlock()
+	ipFamily = networkConfig.IPFamily
+}
+
+// Get The Network & Address On Which To Listen For The Specified Port In The Configured IP Family
+func ListenAddress(port string) (string, string) {
+	ipFamilyLock.RLock()
+	defer ipFamilyLock.RUnlock()
+	switch ipFamily {
+	case IPFamilyIPv4:
+		return "tcp4", net.JoinHostPort(net.IPv4zero.String(), port)
+	case IPFamilyIPv6:
+		return "tcp6", net.JoinHostPort(net.IPv6unspecified.String(), port)
+	default:
+		return "tcp", ":" + port
+	}
+}
+
+// Listen For TCP Connections On The Specified Port (All Addresses Of The Configured IP Family)
+func Listen(port string) (net.Listener, error) {
+	network, address := ListenAddress(port)
+	return net.Listen(network, address)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listener
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Test The ValidateNetworkConfig() Functionality
+func TestValidateNetworkConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		ipFamily string
+		valid    bool
+	}{
+		{name: "Dual-Stack", ipFamily: IPFamilyDualStack, valid: true},
+		{name: "IPv4", ipFamily: IPFamilyIPv4, valid: true},
+		{name: "IPv6", ipFamily: IPFamilyIPv6, valid: true},
+		{name: "Lowercase", ipFamily: "ipv6", valid: false},
+		{name: "Unknown", ipFamily: "IPv5", valid: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateNetworkConfig(config.EKNetworkConfig{IPFamily: test.ipFamily})
+			assert.Equal(t, test.valid, err == nil)
+		})
+	}
+}
+
+// Test The ListenAddress() & Listen() Functionality
+func TestListen(t *testing.T) {
+	defer SetIPFamily(config.EKNetworkConfig{})
+	tests := []struct {
+		name            string
+		ipFamily        string
+		expectedNetwork string
+		expectedAddress string
+		expectedIPv4    bool
+	}{
+		{name: "Dual-Stack", ipFamily: IPFamilyDualStack, expectedNetwork: "tcp", expectedAddress: ":8080"},
+		{name: "IPv4", ipFamily: IPFamilyIPv4, expectedNetwork: "tcp4", expectedAddress: "0.0.0.0:8080", expectedIPv4: true},
+		{name: "IPv6", ipFamily: IPFamilyIPv6, expectedNetwork: "tcp6", expectedAddress: "[::]:8080"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetIPFamily(config.EKNetworkConfig{IPFamily: test.ipFamily})
+			network, address := ListenAddress("8080")
+			assert.Equal(t, test.expectedNetwork, network)
+			assert.Equal(t, test.expectedAddress, address)
+
+			listener, err := Listen("0")
+			if err != nil {
+				t.Skipf("IP family %q not available: %v", test.ipFamily, err)
+			}
+			defer listener.Close()
+			tcpAddr, ok := listener.Addr().(*net.TCPAddr)
+			assert.True(t, ok)
+			assert.True(t, tcpAddr.IP.IsUnspecified())
+			assert.NotZero(t, tcpAddr.Port)
+			if test.ipFamily != IPFamilyDualStack {
+				assert.Equal(t, test.expectedIPv4, tcpAddr.IP.To4() != nil)
+			}
+		})
+	}
+}
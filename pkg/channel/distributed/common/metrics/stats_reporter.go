@@ -19,7 +19,9 @@ package metrics
 import (
 	"context"
 	"log"
+	"strconv"
 	"strings"
+	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
@@ -33,6 +35,57 @@ const (
 	// LabelTopic is the label for the immutable name of the topic.
 	LabelTopic = "topic"
 
+	// LabelSubscription is the label for the UID of the subscription.
+	LabelSubscription = "subscription"
+
+	// LabelPartition is the label for the partition of the topic.
+	LabelPartition = "partition"
+
+	// LabelResult is the label for the result of dispatching an event to a subscriber (one of the Result values).
+	LabelResult = "result"
+	// LabelReason is the label for the reason the receiver rejected a throttled request (one of the Reason values).
+	LabelReason = "reason"
+
+	// Values Of The LabelResult
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+
+	// Values Of The LabelReason
+	ReasonBackoff  = "backoff"
+	ReasonInFlight = "inflight"
+
+	// LabelHop is the label for the hop of an event's delivery whose latency is measured (one of the Hop values).
+	LabelHop = "hop"
+
+	// Values Of The LabelHop
+	HopProduce  = "produce"    // Received By The Receiver -> Produced To Kafka
+	HopConsume  = "consume"    // Produced To Kafka -> Consumed By The Dispatcher
+	HopDispatch = "dispatch"   // Consumed By The Dispatcher -> Dispatched To The Subscriber
+	HopEndToEnd = "end_to_end" // Received By The Receiver -> Dispatched To The Subscriber
+
+	// LabelKind is the label for the kind of an orphaned resource found by the controller's janitor (one of the Kind values).
+	LabelKind = "kind"
+
+	// Values Of The LabelKind
+	KindDeployment    = "deployment"
+	KindService       = "service"
+	KindTopic         = "topic"
+	KindConsumerGroup = "consumer_group"
+
+	// Dispatcher Metric Names (The METRICS_DOMAIN Based Prefix Is Prepended By The Exporter)
+	DispatchedEventCountName = "dispatched_event_count"
+	ConsumerLagName          = "consumer_lag"
+	EventLatencyName         = "event_latency_ms"
+
+	// Receiver Metric Names (The METRICS_DOMAIN Based Prefix Is Prepended By The Exporter)
+	ThrottledProduceCountName = "throttled_produce_count"
+	ThrottledRequestCountName = "throttled_request_count"
+	ThrottleBackoffName       = "throttle_backoff_ms"
+
+	// Controller Metric Names (The METRICS_DOMAIN Based Prefix Is Prepended By The Exporter)
+	OrphanedResourcesName  = "orphaned_resources"
+	DeletedOrphanCountName = "deleted_orphan_count"
+
 	// Sarama Metrics
 	RecordSendRateForTopicPrefix = "record-send-rate-for-topic-"
 )
@@ -45,23 +98,147 @@ var (
 		stats.UnitDimensionless,
 	)
 
+	// Counter For The Number Of Duplicate Events Detected By The Dispatcher (Per Topic & Subscription)
+	duplicateEventCount = stats.Int64(
+		"duplicate_event_count", // The METRICS_DOMAIN will be prepended to the name.
+		"Duplicate Event Count",
+		stats.UnitDimensionless,
+	)
+
+	// Counter For The Number Of Undecodable (Poison Pill) Records Skipped By The Dispatcher (Per Topic & Subscription)
+	poisonPillCount = stats.Int64(
+		"poison_pill_count", // The METRICS_DOMAIN will be prepended to the name.
+		"Poison Pill Count",
+		stats.UnitDimensionless,
+	)
+
+	// Counter For The Number Of Events Dispatched To Subscribers (Per Topic, Subscription & Result)
+	dispatchedEventCount = stats.Int64(
+		DispatchedEventCountName, // The METRICS_DOMAIN will be prepended to the name.
+		"Dispatched Event Count",
+		stats.UnitDimensionless,
+	)
+
+	// Gauge For The Number Of Events Not Yet Consumed By A Subscription (Per Topic, Subscription & Partition)
+	consumerLag = stats.Int64(
+		ConsumerLagName, // The METRICS_DOMAIN will be prepended to the name.
+		"Consumer Lag",
+		stats.UnitDimensionless,
+	)
+
+	// Histogram Of The Latency Of The Hops Of The Events Dispatched To Subscribers (Per Topic, Subscription & Hop)
+	eventLatency = stats.Float64(
+		EventLatencyName, // The METRICS_DOMAIN will be prepended to the name.
+		"Event Latency",
+		stats.UnitMilliseconds,
+	)
+
+	// Counter For The Number Of Produce Requests Exceeding The Receiver's Throttle Latency Threshold
+	throttledProduceCount = stats.Int64(
+		ThrottledProduceCountName, // The METRICS_DOMAIN will be prepended to the name.
+		"Throttled Produce Count",
+		stats.UnitDimensionless,
+	)
+
+	// Counter For The Number Of Requests Rejected By The Receiver While Throttled (Per Reason)
+	throttledRequestCount = stats.Int64(
+		ThrottledRequestCountName, // The METRICS_DOMAIN will be prepended to the name.
+		"Throttled Request Count",
+		stats.UnitDimensionless,
+	)
+
+	// Gauge For The Current Backoff Of The Receiver's Throttle (Zero When Not Throttled)
+	throttleBackoff = stats.Int64(
+		ThrottleBackoffName, // The METRICS_DOMAIN will be prepended to the name.
+		"Throttle Backoff",
+		stats.UnitMilliseconds,
+	)
+
+	// Gauge For The Number Of Orphaned Resources Found By The Controller's Latest Janitor Sweep (Per Kind)
+	orphanedResources = stats.Int64(
+		OrphanedResourcesName, // The METRICS_DOMAIN will be prepended to the name.
+		"Orphaned Resources",
+		stats.UnitDimensionless,
+	)
+
+	// Counter For The Number Of Orphaned Resources Deleted By The Controller's Janitor (Per Kind)
+	deletedOrphanCount = stats.Int64(
+		DeletedOrphanCountName, // The METRICS_DOMAIN will be prepended to the name.
+		"Deleted Orphan Count",
+		stats.UnitDimensionless,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements in order to validate
 	// that they conform to the restrictions described in go.opencensus.io/tag/validate.go.
 	// Currently those restrictions are...
 	//   - Length between 1 and 255 inclusive
 	//   - Characters are printable US-ASCII
-	topic = tag.MustNewKey(LabelTopic)
+	topic        = tag.MustNewKey(LabelTopic)
+	subscription = tag.MustNewKey(LabelSubscription)
+	partition    = tag.MustNewKey(LabelPartition)
+	result       = tag.MustNewKey(LabelResult)
+	reason       = tag.MustNewKey(LabelReason)
+	hop          = tag.MustNewKey(LabelHop)
+	kind         = tag.MustNewKey(LabelKind)
 )
 
 // Register the OpenCensus View Structures
 func init() {
 
-	// Create A View To See Our Metric
+	// Create Views To See Our Metrics
 	err := view.Register(&view.View{
 		Description: producedMessageCount.Description(),
 		Measure:     producedMessageCount,
 		Aggregation: view.LastValue(),
 		TagKeys:     []tag.Key{topic},
+	}, &view.View{
+		Description: duplicateEventCount.Description(),
+		Measure:     duplicateEventCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{topic, subscription},
+	}, &view.View{
+		Description: poisonPillCount.Description(),
+		Measure:     poisonPillCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{topic, subscription},
+	}, &view.View{
+		Description: dispatchedEventCount.Description(),
+		Measure:     dispatchedEventCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{topic, subscription, result},
+	}, &view.View{
+		Description: consumerLag.Description(),
+		Measure:     consumerLag,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{topic, subscription, partition},
+	}, &view.View{
+		Description: eventLatency.Description(),
+		Measure:     eventLatency,
+		Aggregation: view.Distribution(1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000),
+		TagKeys:     []tag.Key{topic, subscription, hop},
+	}, &view.View{
+		Description: throttledProduceCount.Description(),
+		Measure:     throttledProduceCount,
+		Aggregation: view.Count(),
+	}, &view.View{
+		Description: throttledRequestCount.Description(),
+		Measure:     throttledRequestCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{reason},
+	}, &view.View{
+		Description: throttleBackoff.Description(),
+		Measure:     throttleBackoff,
+		Aggregation: view.LastValue(),
+	}, &view.View{
+		Description: orphanedResources.Description(),
+		Measure:     orphanedResources,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{kind},
+	}, &view.View{
+		Description: deletedOrphanCount.Description(),
+		Measure:     deletedOrphanCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{kind},
 	})
 	if err != nil {
 		log.Printf("failed to register opencensus views, %v", err)
@@ -139,3 +316,129 @@ func (r *Reporter) Report(stats map[string]map[string]interface{}) {
 		}
 	}
 }
+
+// Record A Duplicate Event Detected For The Specified Topic & Subscription
+func RecordDuplicateEvent(logger *zap.Logger, topicName string, subscriptionUID string) {
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(topic, topicName),
+		tag.Insert(subscription, subscriptionUID),
+	)
+	if err != nil {
+		logger.Error("Failed To Create New OpenCensus Tags For Duplicate Event", zap.String("Topic", topicName), zap.String("Subscription", subscriptionUID))
+		return
+	}
+	metrics.Record(ctx, duplicateEventCount.M(1))
+}
+
+// Record An Undecodable (Poison Pill) Record Skipped For The Specified Topic & Subscription
+func RecordPoisonPill(logger *zap.Logger, topicName string, subscriptionUID string) {
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(topic, topicName),
+		tag.Insert(subscription, subscriptionUID),
+	)
+	if err != nil {
+		logger.Error("Failed To Create New OpenCensus Tags For Poison Pill", zap.String("Topic", topicName), zap.String("Subscription", subscriptionUID))
+		return
+	}
+	metrics.Record(ctx, poisonPillCount.M(1))
+}
+
+// Record The Result Of Dispatching An Event Of The Specified Topic To The Specified Subscription
+func RecordDispatchedEvent(logger *zap.Logger, topicName string, subscriptionUID string, success bool) {
+	resultValue := ResultSuccess
+	if !success {
+		resultValue = ResultFailure
+	}
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(topic, topicName),
+		tag.Insert(subscription, subscriptionUID),
+		tag.Insert(result, resultValue),
+	)
+	if err != nil {
+		logger.Error("Failed To Create New OpenCensus Tags For Dispatched Event", zap.String("Topic", topicName), zap.String("Subscription", subscriptionUID))
+		return
+	}
+	metrics.Record(ctx, dispatchedEventCount.M(1))
+}
+
+// Record The Consumer Lag Of The Specified Subscription For The Specified Topic Partition
+func RecordConsumerLag(logger *zap.Logger, topicName string, subscriptionUID string, partitionId int32, lag int64) {
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(topic, topicName),
+		tag.Insert(subscription, subscriptionUID),
+		tag.Insert(partition, strconv.Itoa(int(partitionId))),
+	)
+	if err != nil {
+		logger.Error("Failed To Create New OpenCensus Tags For Consumer Lag", zap.String("Topic", topicName), zap.String("Subscription", subscriptionUID))
+		return
+	}
+	metrics.Record(ctx, consumerLag.M(lag))
+}
+
+// Record The Latency Of The Specified Hop Of An Event Of The Specified Topic Dispatched To The Specified Subscription
+func RecordEventLatency(logger *zap.Logger, topicName string, subscriptionUID string, hopValue string, latency time.Duration) {
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(topic, topicName),
+		tag.Insert(subscription, subscriptionUID),
+		tag.Insert(hop, hopValue),
+	)
+	if err != nil {
+		logger.Error("Failed To Create New OpenCensus Tags For Event Latency", zap.String("Topic", topicName), zap.String("Subscription", subscriptionUID), zap.String("Hop", hopValue))
+		return
+	}
+	metrics.Record(ctx, eventLatency.M(float64(latency)/float64(time.Millisecond)))
+}
+
+// Record A Produce Request Exceeding The Receiver's Throttle Latency Threshold
+func RecordThrottledProduce() {
+	metrics.Record(context.Background(), throttledProduceCount.M(1))
+}
+
+// Record A Request Rejected By The Receiver While Throttled For The Specified Reason
+func RecordThrottledRequest(logger *zap.Logger, reasonValue string) {
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(reason, reasonValue),
+	)
+	if err != nil {
+		logger.Error("Failed To Create New OpenCensus Tags For Throttled Request", zap.String("Reason", reasonValue))
+		return
+	}
+	metrics.Record(ctx, throttledRequestCount.M(1))
+}
+
+// Record The Current Backoff Of The Receiver's Throttle
+func RecordThrottleBackoff(backoff time.Duration) {
+	metrics.Record(context.Background(), throttleBackoff.M(backoff.Milliseconds()))
+}
+
+// Record The Number Of Orphaned Resources Of The Specified Kind Found By The Latest Janitor Sweep
+func RecordOrphanedResources(logger *zap.Logger, kindValue string, count int) {
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(kind, kindValue),
+	)
+	if err != nil {
+		logger.Error("Failed To Create New OpenCensus Tags For Orphaned Resources", zap.String("Kind", kindValue))
+		return
+	}
+	metrics.Record(ctx, orphanedResources.M(int64(count)))
+}
+
+// Record An Orphaned Resource Of The Specified Kind Deleted By The Janitor
+func RecordDeletedOrphan(logger *zap.Logger, kindValue string) {
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(kind, kindValue),
+	)
+	if err != nil {
+		logger.Error("Failed To Create New OpenCensus Tags For Deleted Orphan", zap.String("Kind", kindValue))
+		return
+	}
+	metrics.Record(ctx, deletedOrphanCount.M(1))
+}
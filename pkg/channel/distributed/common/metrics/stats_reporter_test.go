@@ -29,6 +29,7 @@ import (
 	"io/ioutil"
 
 	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -103,6 +104,125 @@ func TestMetricsServer_Report(t *testing.T) {
 	assert.True(t, verifyMetric(bodyStrings, "eventing_kafka_produced_msg_count", topicName, strconv.Itoa(msgCount)))
 }
 
+// Test The RecordDispatchedEvent() & RecordConsumerLag() Functionality
+func TestRecordDispatcherMetrics(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Perform The Test
+	RecordDispatchedEvent(logger, "test-topic", "test-subscription", true)
+	RecordDispatchedEvent(logger, "test-topic", "test-subscription", false)
+	RecordDispatchedEvent(logger, "test-topic", "test-subscription", false)
+	RecordConsumerLag(logger, "test-topic", "test-subscription", 1, 5)
+	RecordConsumerLag(logger, "test-topic", "test-subscription", 1, 3)
+
+	// Verify The Results
+	dispatchedRows, err := view.RetrieveData(DispatchedEventCountName)
+	assert.Nil(t, err)
+	dispatchedCounts := map[string]int64{}
+	for _, row := range dispatchedRows {
+		for _, rowTag := range row.Tags {
+			if rowTag.Key == result {
+				dispatchedCounts[rowTag.Value] = row.Data.(*view.CountData).Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{ResultSuccess: 1, ResultFailure: 2}, dispatchedCounts)
+	lagRows, err := view.RetrieveData(ConsumerLagName)
+	assert.Nil(t, err)
+	assert.Len(t, lagRows, 1)
+	assert.Equal(t, float64(3), lagRows[0].Data.(*view.LastValueData).Value)
+}
+
+// Test The RecordEventLatency() Functionality
+func TestRecordEventLatency(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Perform The Test
+	RecordEventLatency(logger, "test-topic", "test-subscription", HopDispatch, 20*time.Millisecond)
+	RecordEventLatency(logger, "test-topic", "test-subscription", HopDispatch, 40*time.Millisecond)
+	RecordEventLatency(logger, "test-topic", "test-subscription", HopEndToEnd, 100*time.Millisecond)
+
+	// Verify The Results
+	latencyRows, err := view.RetrieveData(EventLatencyName)
+	assert.Nil(t, err)
+	latencies := map[string]*view.DistributionData{}
+	for _, row := range latencyRows {
+		for _, rowTag := range row.Tags {
+			if rowTag.Key == hop {
+				latencies[rowTag.Value] = row.Data.(*view.DistributionData)
+			}
+		}
+	}
+	assert.Len(t, latencies, 2)
+	assert.Equal(t, int64(2), latencies[HopDispatch].Count)
+	assert.Equal(t, float64(30), latencies[HopDispatch].Mean)
+	assert.Equal(t, int64(1), latencies[HopEndToEnd].Count)
+}
+
+// Test The RecordThrottledProduce(), RecordThrottledRequest() & RecordThrottleBackoff() Functionality
+func TestRecordThrottleMetrics(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Perform The Test
+	RecordThrottledProduce()
+	RecordThrottledProduce()
+	RecordThrottledRequest(logger, ReasonBackoff)
+	RecordThrottledRequest(logger, ReasonInFlight)
+	RecordThrottledRequest(logger, ReasonInFlight)
+	RecordThrottleBackoff(2 * time.Second)
+	RecordThrottleBackoff(time.Second)
+
+	// Verify The Results
+	produceRows, err := view.RetrieveData(ThrottledProduceCountName)
+	assert.Nil(t, err)
+	assert.Len(t, produceRows, 1)
+	assert.Equal(t, int64(2), produceRows[0].Data.(*view.CountData).Value)
+	requestRows, err := view.RetrieveData(ThrottledRequestCountName)
+	assert.Nil(t, err)
+	requestCounts := map[string]int64{}
+	for _, row := range requestRows {
+		for _, rowTag := range row.Tags {
+			if rowTag.Key == reason {
+				requestCounts[rowTag.Value] = row.Data.(*view.CountData).Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{ReasonBackoff: 1, ReasonInFlight: 2}, requestCounts)
+	backoffRows, err := view.RetrieveData(ThrottleBackoffName)
+	assert.Nil(t, err)
+	assert.Len(t, backoffRows, 1)
+	assert.Equal(t, float64(1000), backoffRows[0].Data.(*view.LastValueData).Value)
+}
+
+// Test The RecordOrphanedResources() & RecordDeletedOrphan() Functionality
+func TestRecordJanitorMetrics(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Perform The Test
+	RecordOrphanedResources(logger, KindTopic, 3)
+	RecordOrphanedResources(logger, KindTopic, 2)
+	RecordOrphanedResources(logger, KindService, 1)
+	RecordDeletedOrphan(logger, KindTopic)
+	RecordDeletedOrphan(logger, KindTopic)
+
+	// Verify The Results
+	orphanedRows, err := view.RetrieveData(OrphanedResourcesName)
+	assert.Nil(t, err)
+	orphaned := map[string]float64{}
+	for _, row := range orphanedRows {
+		for _, rowTag := range row.Tags {
+			if rowTag.Key == kind {
+				orphaned[rowTag.Value] = row.Data.(*view.LastValueData).Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{KindTopic: 2, KindService: 1}, orphaned)
+	deletedRows, err := view.RetrieveData(DeletedOrphanCountName)
+	assert.Nil(t, err)
+	assert.Len(t, deletedRows, 1)
+	assert.Equal(t, int64(2), deletedRows[0].Data.(*view.CountData).Value)
+}
+
 // Utility Function For Creating Sample Test Metrics  (Representative Data From Sarama Metrics Trace - With Custom Test Data)
 func createTestMetrics(topic string, count int64) map[string]map[string]interface{} {
 	testMetrics := make(map[string]map[string]interface{})
@@ -0,0 +1,282 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Middleware Phases
+const (
+	ReceivePhase  = "receive"  // Applied By The Receiver Before Producing An Event To Kafka
+	DispatchPhase = "dispatch" // Applied By The Dispatcher Before Delivering An Event To Each Subscriber
+)
+
+// The Path At Which The ConfigMap Of The Middleware Modules Is Mounted In The Receiver & Dispatcher
+const DefaultModulesPath = "/etc/eventing-kafka/middleware"
+
+// Valid Middleware Module Names (Separated By Commas In The KafkaChannel Annotation)
+var moduleNameRegExp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// The Event Processed By Middleware, And Where
+type Target struct {
+	Phase      string
+	Namespace  string // The KafkaChannel's Namespace
+	Channel    string // The KafkaChannel's Name
+	Subscriber string // The Subscriber's UID (Dispatch Phase Only)
+}
+
+// Middleware Processes The Events Of The KafkaChannels Selecting It (e.g. Enrichment, Validation Or Routing Hints)
+type Middleware interface {
+
+	// Process The Event, Returning The (Possibly Modified) Event To Continue With, nil To Drop It, Or An Error To
+	// Reject It
+	Process(ctx context.Context, target Target, cloudEvent *event.Event) (*event.Event, error)
+}
+
+// MiddlewareFunc Adapts An Ordinary Function To The Middleware Interface
+type MiddlewareFunc func(ctx context.Context, target Target, cloudEvent *event.Event) (*event.Event, error)
+
+// Process The Event By Calling The Function
+func (f MiddlewareFunc) Process(ctx context.Context, target Target, cloudEvent *event.Event) (*event.Event, error) {
+	return f(ctx, target, cloudEvent)
+}
+
+// Runtime Loads Middleware Modules (e.g. A WebAssembly Runtime Instantiating Their Compiled Code)
+type Runtime interface {
+	Load(name string, module []byte, moduleConfig map[string]string) (Middleware, error)
+}
+
+// RuntimeFactory Creates A Runtime
+type RuntimeFactory func(logger *zap.Logger) (Runtime, error)
+
+// The Registered RuntimeFactories By Name (None Are Built In)
+var (
+	runtimeFactories     = map[string]RuntimeFactory{}
+	runtimeFactoriesLock sync.RWMutex
+)
+
+// Register A Middleware Runtime (e.g. A WebAssembly Runtime) Which May Then Be Selected By Name In The ConfigMap
+func RegisterRuntime(name string, factory RuntimeFactory) {
+	runtimeFactoriesLock.Lock()
+	defer runtimeFactoriesLock.Unlock()
+	runtimeFactories[name] = factory
+}
+
+// Get The Sorted Names Of The Registered Runtimes
+func registeredRuntimes() []string {
+	runtimeFactoriesLock.RLock()
+	defer runtimeFactoriesLock.RUnlock()
+	names := make([]string, 0, len(runtimeFactories))
+	for name := range runtimeFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get The Named RuntimeFactory, Or An Error If It Isn't Registered
+func runtimeFactory(name string) (RuntimeFactory, error) {
+	runtimeFactoriesLock.RLock()
+	factory, ok := runtimeFactories[name]
+	runtimeFactoriesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("middleware runtime '%s' is not supported, it must be one of the runtimes compiled into the receiver & dispatcher %v", name, registeredRuntimes())
+	}
+	return factory, nil
+}
+
+// Validate The Specified Middleware Config (Including That Its Runtime Is Registered)
+func ValidateMiddlewareConfig(middlewareConfig config.EKMiddlewareConfig) error {
+	if !middlewareConfig.Enabled {
+		return nil
+	}
+	if len(middlewareConfig.Runtime) == 0 {
+		return errors.New("middleware requires the runtime of the modules (none is compiled into the standard receiver & dispatcher)")
+	}
+	if _, err := runtimeFactory(middlewareConfig.Runtime); err != nil {
+		return err
+	}
+	if len(middlewareConfig.ConfigMapName) == 0 {
+		return errors.New("middleware requires the configMapName of the modules")
+	}
+	names := make(map[string]bool, len(middlewareConfig.Modules))
+	for _, module := range middlewareConfig.Modules {
+		if !moduleNameRegExp.MatchString(module.Name) {
+			return fmt.Errorf("invalid middleware module name '%s'", module.Name)
+		}
+		if names[module.Name] {
+			return fmt.Errorf("duplicate middleware module name '%s'", module.Name)
+		}
+		names[module.Name] = true
+		if len(module.File) == 0 {
+			return fmt.Errorf("middleware module '%s' requires a file", module.Name)
+		}
+		for _, phase := range module.Phases {
+			if phase != ReceivePhase && phase != DispatchPhase {
+				return fmt.Errorf("invalid phase '%s' of middleware module '%s'", phase, module.Name)
+			}
+		}
+	}
+	return nil
+}
+
+//
+// The Middleware Modules Which KafkaChannels May Select
+//
+// The modules are loaded once at startup by the configured Runtime, from the files of the ConfigMap mounted at the
+// DefaultModulesPath, so changes to the modules require a restart of the receiver & dispatchers.  A KafkaChannel
+// selects the modules applied to its events, in order, with its middleware annotation.
+//
+// A nil *Registry is valid and has no modules.
+//
+type Registry struct {
+	modules map[string]*module
+}
+
+// A Loaded Middleware Module & The Phases It Is Applied In
+type module struct {
+	Stage
+	phases map[string]bool
+}
+
+// Registry Constructor - Returns nil If Not Enabled (Assumes A Valid Config), Otherwise Loads The Modules Mounted At
+// The DefaultModulesPath (Returning An Error If The Runtime Isn't Registered Or Any Module Fails To Load)
+func NewRegistry(logger *zap.Logger, middlewareConfig config.EKMiddlewareConfig) (*Registry, error) {
+	return newRegistry(logger, middlewareConfig, DefaultModulesPath)
+}
+
+// Create A Registry Loading The Modules From The Specified Directory
+func newRegistry(logger *zap.Logger, middlewareConfig config.EKMiddlewareConfig, directory string) (*Registry, error) {
+	if !middlewareConfig.Enabled {
+		return nil, nil
+	}
+
+	// Create The Configured Runtime
+	runtimeName := middlewareConfig.Runtime
+	factory, err := runtimeFactory(runtimeName)
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := factory(logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create middleware runtime '%s': %w", runtimeName, err)
+	}
+
+	// Load Each Module (Applied In Both Phases Unless Specified)
+	registry := &Registry{modules: make(map[string]*module, len(middlewareConfig.Modules))}
+	for _, moduleConfig := range middlewareConfig.Modules {
+		content, err := ioutil.ReadFile(filepath.Join(directory, moduleConfig.File))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read middleware module '%s': %w", moduleConfig.Name, err)
+		}
+		middleware, err := runtime.Load(moduleConfig.Name, content, moduleConfig.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load middleware module '%s': %w", moduleConfig.Name, err)
+		}
+		phases := moduleConfig.Phases
+		if len(phases) == 0 {
+			phases = []string{ReceivePhase, DispatchPhase}
+		}
+		loaded := &module{Stage: Stage{Name: moduleConfig.Name, Middleware: middleware}, phases: make(map[string]bool, len(phases))}
+		for _, phase := range phases {
+			loaded.phases[phase] = true
+		}
+		registry.modules[moduleConfig.Name] = loaded
+		logger.Info("Loaded Middleware Module", zap.String("Name", moduleConfig.Name), zap.Strings("Phases", phases))
+	}
+	return registry, nil
+}
+
+// Get The Pipeline Of The Named Modules Applied In The Specified Phase (Modules Of The Other Phase Are Skipped)
+func (r *Registry) Pipeline(phase string, names []string) (Pipeline, error) {
+	var pipeline Pipeline
+	for _, name := range names {
+		if r == nil {
+			return nil, fmt.Errorf("middleware module '%s' is selected but middleware is not enabled", name)
+		}
+		module, ok := r.modules[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown middleware module '%s'", name)
+		}
+		if module.phases[phase] {
+			pipeline = append(pipeline, module.Stage)
+		}
+	}
+	return pipeline, nil
+}
+
+// Get The Names Of The Middleware Modules Selected By The Specified KafkaChannel Annotations (nil If None)
+func ChannelMiddleware(annotations map[string]string) []string {
+	var names []string
+	for _, name := range strings.Split(annotations[constants.MiddlewareAnnotation], ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// A Named Middleware Of A Pipeline
+type Stage struct {
+	Name       string
+	Middleware Middleware
+}
+
+// The Middleware Applied In Order To The Events Of A KafkaChannel (A nil Pipeline Leaves Events Unchanged)
+type Pipeline []Stage
+
+// Get The Names Of The Pipeline's Middleware
+func (p Pipeline) Names() []string {
+	var names []string
+	for _, stage := range p {
+		names = append(names, stage.Name)
+	}
+	return names
+}
+
+// Process The Event With Each Middleware In Order, Returning The Resulting Event (nil If Dropped) Or The Error Of
+// The Middleware Rejecting It
+func (p Pipeline) Process(ctx context.Context, target Target, cloudEvent *event.Event) (*event.Event, error) {
+	for _, stage := range p {
+		processedEvent, err := stage.Middleware.Process(ctx, target, cloudEvent)
+		if err != nil {
+			return nil, fmt.Errorf("middleware '%s' rejected the event: %w", stage.Name, err)
+		}
+		if processedEvent == nil {
+			return nil, nil
+		}
+		if err = processedEvent.Validate(); err != nil {
+			return nil, fmt.Errorf("middleware '%s' returned an invalid event: %w", stage.Name, err)
+		}
+		cloudEvent = processedEvent
+	}
+	return cloudEvent, nil
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// The Name Of The Test Runtime
+const testRuntime = "test"
+
+// Register The Test Runtime Whose Modules Are Simply The Name Of An Action
+func init() {
+	RegisterRuntime(testRuntime, func(logger *zap.Logger) (Runtime, error) { return &TestRuntime{}, nil })
+}
+
+// Test Runtime Loading TestMiddleware
+type TestRuntime struct{}
+
+func (r *TestRuntime) Load(name string, module []byte, moduleConfig map[string]string) (Middleware, error) {
+	action := string(module)
+	if action != "extend" && action != "drop" && action != "reject" && action != "invalidate" {
+		return nil, errors.New("unknown action")
+	}
+	return &TestMiddleware{action: action, config: moduleConfig}, nil
+}
+
+// Test Middleware Performing A Single Action
+type TestMiddleware struct {
+	action string
+	config map[string]string
+}
+
+func (m *TestMiddleware) Process(_ context.Context, target Target, cloudEvent *event.Event) (*event.Event, error) {
+	switch m.action {
+	case "extend":
+		processedEvent := cloudEvent.Clone()
+		processedEvent.SetExtension(m.config["extension"], target.Phase)
+		return &processedEvent, nil
+	case "drop":
+		return nil, nil
+	case "reject":
+		return nil, errors.New("rejected")
+	default:
+		processedEvent := cloudEvent.Clone()
+		processedEvent.SetID("")
+		return &processedEvent, nil
+	}
+}
+
+// Utility Function For Writing Modules To A Temporary Directory
+func writeModules(t *testing.T, modules map[string]string) string {
+	directory, err := ioutil.TempDir("", "middleware")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(directory) })
+	for name, content := range modules {
+		require.Nil(t, ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0600))
+	}
+	return directory
+}
+
+// Utility Function For Creating A Test Event
+func testEvent() *event.Event {
+	cloudEvent := event.New()
+	cloudEvent.SetID("id")
+	cloudEvent.SetSource("source")
+	cloudEvent.SetType("type")
+	return &cloudEvent
+}
+
+// Test The ValidateMiddlewareConfig() Functionality
+func TestValidateMiddlewareConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.EKMiddlewareConfig
+		valid  bool
+	}{
+		{name: "Disabled", config: config.EKMiddlewareConfig{Modules: []config.EKMiddlewareModule{{Name: "Invalid Name"}}}, valid: true},
+		{name: "Valid", config: config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, ConfigMapName: "middleware", Modules: []config.EKMiddlewareModule{{Name: "enrich", File: "enrich.wasm", Phases: []string{ReceivePhase}}, {Name: "validate", File: "validate.wasm"}}}, valid: true},
+		{name: "Missing Runtime", config: config.EKMiddlewareConfig{Enabled: true, ConfigMapName: "middleware"}},
+		{name: "Unsupported Runtime", config: config.EKMiddlewareConfig{Enabled: true, Runtime: "wasm", ConfigMapName: "middleware"}},
+		{name: "Missing ConfigMapName", config: config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime}},
+		{name: "Invalid Name", config: config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, ConfigMapName: "middleware", Modules: []config.EKMiddlewareModule{{Name: "a,b", File: "enrich.wasm"}}}},
+		{name: "Duplicate Name", config: config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, ConfigMapName: "middleware", Modules: []config.EKMiddlewareModule{{Name: "enrich", File: "a.wasm"}, {Name: "enrich", File: "b.wasm"}}}},
+		{name: "Missing File", config: config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, ConfigMapName: "middleware", Modules: []config.EKMiddlewareModule{{Name: "enrich"}}}},
+		{name: "Invalid Phase", config: config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, ConfigMapName: "middleware", Modules: []config.EKMiddlewareModule{{Name: "enrich", File: "enrich.wasm", Phases: []string{"produce"}}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateMiddlewareConfig(test.config)
+			assert.Equal(t, test.valid, err == nil, err)
+		})
+	}
+}
+
+// Test The NewRegistry() Functionality
+func TestNewRegistry(t *testing.T) {
+	logger := zap.NewNop()
+	directory := writeModules(t, map[string]string{"extend.wasm": "extend", "unknown.wasm": "unknown"})
+
+	// Disabled
+	registry, err := NewRegistry(logger, config.EKMiddlewareConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, registry)
+
+	// Unregistered Runtime (None Are Built In)
+	registry, err = newRegistry(logger, config.EKMiddlewareConfig{Enabled: true, Runtime: "wasm"}, directory)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "[test]")
+	assert.Nil(t, registry)
+
+	// Missing Module File
+	registry, err = newRegistry(logger, config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, Modules: []config.EKMiddlewareModule{{Name: "missing", File: "missing.wasm"}}}, directory)
+	assert.NotNil(t, err)
+	assert.Nil(t, registry)
+
+	// Module Failing To Load
+	registry, err = newRegistry(logger, config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, Modules: []config.EKMiddlewareModule{{Name: "unknown", File: "unknown.wasm"}}}, directory)
+	assert.NotNil(t, err)
+	assert.Nil(t, registry)
+
+	// Loaded Module Applied In Both Phases By Default
+	registry, err = newRegistry(logger, config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, Modules: []config.EKMiddlewareModule{{Name: "extend", File: "extend.wasm"}}}, directory)
+	assert.Nil(t, err)
+	require.NotNil(t, registry)
+	for _, phase := range []string{ReceivePhase, DispatchPhase} {
+		pipeline, err := registry.Pipeline(phase, []string{"extend"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"extend"}, pipeline.Names())
+	}
+}
+
+// Test The Registry.Pipeline() Functionality
+func TestRegistryPipeline(t *testing.T) {
+	directory := writeModules(t, map[string]string{"extend.wasm": "extend", "drop.wasm": "drop"})
+	registry, err := newRegistry(zap.NewNop(), config.EKMiddlewareConfig{Enabled: true, Runtime: testRuntime, Modules: []config.EKMiddlewareModule{
+		{Name: "extend", File: "extend.wasm"},
+		{Name: "drop", File: "drop.wasm", Phases: []string{DispatchPhase}},
+	}}, directory)
+	require.Nil(t, err)
+
+	// Modules Are Applied In The Selected Order, Skipping Those Of Other Phases
+	pipeline, err := registry.Pipeline(DispatchPhase, []string{"drop", "extend"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"drop", "extend"}, pipeline.Names())
+	pipeline, err = registry.Pipeline(ReceivePhase, []string{"drop", "extend"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"extend"}, pipeline.Names())
+
+	// No Modules Selected
+	pipeline, err = registry.Pipeline(ReceivePhase, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, pipeline)
+
+	// Unknown Module
+	pipeline, err = registry.Pipeline(ReceivePhase, []string{"extend", "unknown"})
+	assert.NotNil(t, err)
+	assert.Nil(t, pipeline)
+
+	// A nil Registry Has No Modules
+	var nilRegistry *Registry
+	pipeline, err = nilRegistry.Pipeline(ReceivePhase, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, pipeline)
+	pipeline, err = nilRegistry.Pipeline(ReceivePhase, []string{"extend"})
+	assert.NotNil(t, err)
+	assert.Nil(t, pipeline)
+}
+
+// Test The ChannelMiddleware() Functionality
+func TestChannelMiddleware(t *testing.T) {
+	assert.Nil(t, ChannelMiddleware(nil))
+	assert.Nil(t, ChannelMiddleware(map[string]string{constants.MiddlewareAnnotation: " , "}))
+	assert.Equal(t, []string{"enrich", "validate"}, ChannelMiddleware(map[string]string{constants.MiddlewareAnnotation: "enrich, validate,"}))
+}
+
+// Test The Pipeline.Process() Functionality
+func TestPipelineProcess(t *testing.T) {
+	target := Target{Phase: ReceivePhase, Namespace: "namespace", Channel: "channel"}
+	extend := func(extension string) Stage {
+		return Stage{Name: extension, Middleware: &TestMiddleware{action: "extend", config: map[string]string{"extension": extension}}}
+	}
+
+	// A nil Pipeline Leaves Events Unchanged
+	var pipeline Pipeline
+	cloudEvent := testEvent()
+	processedEvent, err := pipeline.Process(context.TODO(), target, cloudEvent)
+	assert.Nil(t, err)
+	assert.Equal(t, cloudEvent, processedEvent)
+
+	// Each Middleware Processes The Result Of The Previous One
+	pipeline = Pipeline{extend("first"), extend("second")}
+	processedEvent, err = pipeline.Process(context.TODO(), target, testEvent())
+	assert.Nil(t, err)
+	require.NotNil(t, processedEvent)
+	assert.Equal(t, ReceivePhase, processedEvent.Extensions()["first"])
+	assert.Equal(t, ReceivePhase, processedEvent.Extensions()["second"])
+
+	// Dropped Events Skip The Remaining Middleware
+	pipeline = Pipeline{{Name: "drop", Middleware: &TestMiddleware{action: "drop"}}, {Name: "reject", Middleware: &TestMiddleware{action: "reject"}}}
+	processedEvent, err = pipeline.Process(context.TODO(), target, testEvent())
+	assert.Nil(t, err)
+	assert.Nil(t, processedEvent)
+
+	// Rejected Events
+	pipeline = Pipeline{extend("first"), {Name: "reject", Middleware: &TestMiddleware{action: "reject"}}}
+	processedEvent, err = pipeline.Process(context.TODO(), target, testEvent())
+	assert.NotNil(t, err)
+	assert.Nil(t, processedEvent)
+
+	// Invalid Events Returned By Middleware
+	pipeline = Pipeline{{Name: "invalidate", Middleware: &TestMiddleware{action: "invalidate"}}}
+	processedEvent, err = pipeline.Process(context.TODO(), target, testEvent())
+	assert.NotNil(t, err)
+	assert.Nil(t, processedEvent)
+}
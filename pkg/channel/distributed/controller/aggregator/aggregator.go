@@ -0,0 +1,534 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonlistener "knative.dev/eventing-kafka/pkg/channel/distributed/common/listener"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/pkg/system"
+)
+
+// Aggregator Constants
+const (
+	DefaultPort           = 8082
+	DefaultScrapeInterval = 30 * time.Second
+	ScrapeTimeout         = 5 * time.Second
+	Path                  = "/channels/"
+)
+
+// SubscriptionSummary Summarizes The Dispatcher Metrics Of A Subscription (Or Of All Subscriptions Of A KafkaChannel)
+type SubscriptionSummary struct {
+	ConsumerLag      int64   `json:"consumerLag"`
+	DispatchedEvents int64   `json:"dispatchedEvents"`
+	FailedEvents     int64   `json:"failedEvents"`
+	ErrorRate        float64 `json:"errorRate"`
+	EventsPerSecond  float64 `json:"eventsPerSecond,omitempty"` // Dispatch Rate Since The Previous Scrape
+	LagPerSecond     float64 `json:"lagPerSecond,omitempty"`    // ConsumerLag Growth (Or Shrinkage) Rate Since The Previous Scrape
+}
+
+// ChannelSummary Summarizes The Dispatcher Metrics Of A KafkaChannel, Aggregated Across Its Dispatcher Pods
+type ChannelSummary struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	Pods         int    `json:"pods"`
+	ScrapeErrors int    `json:"scrapeErrors,omitempty"`
+	Partitions   int    `json:"partitions,omitempty"` // The Most Partitions Reporting ConsumerLag For Any Subscription
+	SubscriptionSummary
+	Subscriptions map[string]*SubscriptionSummary `json:"subscriptions,omitempty"`
+	ScrapedAt     time.Time                       `json:"scrapedAt"`
+}
+
+//
+// Dispatcher Metrics Aggregator
+//
+// The Aggregator periodically scrapes the Prometheus metrics of every dispatcher pod (found via the Endpoints of
+// the dispatcher Services) and serves a per-KafkaChannel summary at a stable endpoint, so that dashboards need not
+// discover the per-KafkaChannel dispatcher Deployments.  The ErrorRate is the ratio of failed to dispatched events
+// since the previous scrape (or since the dispatcher started if it has not been scraped before), and the rates
+// are those since the previous scrape (zero until a KafkaChannel has been scraped twice).  If the resize advisor is
+// enabled the resource usage of the dispatcher & receiver pods is also observed at each scrape and served per
+// Deployment along with any recommended resizes of their requests (see observeResources()).
+//
+type Aggregator struct {
+	logger            *zap.Logger
+	kubeClient        kubernetes.Interface
+	httpClient        *http.Client
+	scrapeInterval    time.Duration
+	summaries         map[string]*ChannelSummary
+	bottleneckHandler func(namespace string, name string)
+	maxErrorRate      float64
+	errorRateHandler  func(namespace string, name string)
+	resizeAdvisor     config.EKResizeAdvisorConfig
+	resizeWindow      time.Duration
+	podMetrics        func(ctx context.Context) ([]podMetrics, error)
+	resourceSamples   map[string][]resourceSample
+	resources         map[string]*DeploymentResources
+	resizeHandler     func(labels map[string]string)
+	lock              sync.RWMutex
+	server            *http.Server
+	Port              string
+}
+
+// Aggregator Constructor - Returns nil If The Metrics Aggregator Is Not Enabled
+func NewAggregator(logger *zap.Logger, kubeClient kubernetes.Interface, aggregatorConfig config.EKMetricsAggregatorConfig) *Aggregator {
+	if !aggregatorConfig.Enabled {
+		return nil
+	}
+
+	// Default The Port & ScrapeInterval
+	port := aggregatorConfig.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	scrapeInterval := time.Duration(aggregatorConfig.ScrapeIntervalMillis) * time.Millisecond
+	if scrapeInterval <= 0 {
+		scrapeInterval = DefaultScrapeInterval
+	}
+
+	// Create The Aggregator
+	aggregator := &Aggregator{
+		logger:         logger,
+		kubeClient:     kubeClient,
+		httpClient:     &http.Client{Timeout: ScrapeTimeout},
+		scrapeInterval: scrapeInterval,
+		summaries:      make(map[string]*ChannelSummary),
+		Port:           strconv.Itoa(port),
+	}
+	aggregator.resizeAdvisor, aggregator.resizeWindow = defaultResizeAdvisorConfig(aggregatorConfig.ResizeAdvisor)
+	aggregator.podMetrics = aggregator.getPodMetrics
+	serveMux := http.NewServeMux()
+	serveMux.HandleFunc(Path, aggregator.HandleChannels)
+	serveMux.HandleFunc(DeploymentsPath, aggregator.HandleDeployments)
+	aggregator.server = &http.Server{Handler: serveMux}
+	return aggregator
+}
+
+// Set The Function Called After Each Scrape With Every KafkaChannel Whose Partitions Are (Or Were Previously) A Bottleneck (Must Precede Start())
+func (a *Aggregator) SetBottleneckHandler(bottleneckHandler func(namespace string, name string)) {
+	if a == nil {
+		return
+	}
+	a.bottleneckHandler = bottleneckHandler
+}
+
+// Set The Function Called After Each Scrape With Every KafkaChannel Whose ErrorRate Exceeds The Maximum (Must Precede Start())
+func (a *Aggregator) SetErrorRateHandler(maxErrorRate float64, errorRateHandler func(namespace string, name string)) {
+	if a == nil {
+		return
+	}
+	a.maxErrorRate = maxErrorRate
+	a.errorRateHandler = errorRateHandler
+}
+
+// Get The Latest ChannelSummary Of The Specified KafkaChannel (nil If Not Scraped Or The Aggregator Is Not Enabled)
+func (a *Aggregator) Summary(namespace string, name string) *ChannelSummary {
+	if a == nil {
+		return nil
+	}
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.summaries[namespace+"/"+name]
+}
+
+// Start Serving The Summaries & Scraping The Dispatchers Until The Stop Channel Is Closed
+func (a *Aggregator) Start(stopChan <-chan struct{}) error {
+	if a == nil {
+		return nil
+	}
+	listener, err := commonlistener.Listen(a.Port)
+	if err != nil {
+		a.logger.Error("Metrics Aggregator HTTP Listen Returned Error", zap.Error(err))
+		return err
+	}
+	a.Port = strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	go func() {
+		a.logger.Info("Starting Metrics Aggregator HTTP Server", zap.String("Port", a.Port), zap.Duration("ScrapeInterval", a.scrapeInterval))
+		err := a.server.Serve(listener)
+		if err != nil {
+			a.logger.Info("Metrics Aggregator HTTP Serve Returned Error", zap.Error(err)) // Info log since it could just be normal shutdown
+		}
+	}()
+	go func() {
+		ticker := time.NewTicker(a.scrapeInterval)
+		defer ticker.Stop()
+		for {
+			a.Scrape(context.Background())
+			select {
+			case <-ticker.C:
+			case <-stopChan:
+				a.Stop()
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop Serving The Summaries
+func (a *Aggregator) Stop() {
+	if a == nil {
+		return
+	}
+	a.logger.Info("Stopping Metrics Aggregator HTTP Server")
+	if err := a.server.Shutdown(context.TODO()); err != nil {
+		a.logger.Error("Metrics Aggregator Failed To Shutdown HTTP Server", zap.Error(err))
+	}
+}
+
+// Scrape The Metrics Of All Dispatcher Pods & Replace The ChannelSummaries
+func (a *Aggregator) Scrape(ctx context.Context) {
+
+	// Get The Endpoints Of All Dispatcher Services (Which Carry The Services' KafkaChannel Labels)
+	endpointsList, err := a.kubeClient.CoreV1().Endpoints(system.Namespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: constants.KafkaChannelDispatcherLabel + "=true",
+	})
+	if err != nil {
+		a.logger.Error("Failed To List Dispatcher Endpoints", zap.Error(err))
+		return
+	}
+
+	// Scrape The Dispatcher Pods Of Each KafkaChannel
+	a.lock.RLock()
+	previousSummaries := a.summaries
+	a.lock.RUnlock()
+	summaries := make(map[string]*ChannelSummary)
+	for _, endpoints := range endpointsList.Items {
+		namespace := endpoints.Labels[constants.KafkaChannelNamespaceLabel]
+		name := endpoints.Labels[constants.KafkaChannelNameLabel]
+		if len(namespace) == 0 || len(name) == 0 {
+			continue
+		}
+		summary := &ChannelSummary{Namespace: namespace, Name: name, ScrapedAt: time.Now()}
+		channelMetrics := newChannelMetrics()
+		for _, subset := range endpoints.Subsets {
+			var port int32
+			for _, endpointPort := range subset.Ports {
+				if endpointPort.Name == constants.MetricsPortName {
+					port = endpointPort.Port
+				}
+			}
+			if port == 0 {
+				continue
+			}
+			for _, address := range subset.Addresses {
+				err = a.scrapePod(ctx, address.IP, port, channelMetrics)
+				if err != nil {
+					a.logger.Warn("Failed To Scrape Dispatcher Pod Metrics", zap.String("Namespace", namespace), zap.String("Name", name), zap.String("IP", address.IP), zap.Error(err))
+					summary.ScrapeErrors++
+				} else {
+					summary.Pods++
+				}
+			}
+		}
+		channelMetrics.summarize(summary, previousSummaries[namespace+"/"+name])
+		summaries[namespace+"/"+name] = summary
+	}
+
+	// Replace The ChannelSummaries
+	a.lock.Lock()
+	a.summaries = summaries
+	a.lock.Unlock()
+
+	// Notify The Handler Of KafkaChannels Whose Partitions Are A Bottleneck (Or No Longer Are)
+	if a.bottleneckHandler != nil {
+		for key, summary := range summaries {
+			previous := previousSummaries[key]
+			if summary.PartitionsBottleneck() || (previous != nil && previous.PartitionsBottleneck()) {
+				a.bottleneckHandler(summary.Namespace, summary.Name)
+			}
+		}
+	}
+
+	// Notify The Handler Of KafkaChannels Whose ErrorRate Exceeds The Maximum
+	if a.errorRateHandler != nil {
+		for _, summary := range summaries {
+			if summary.ErrorRate > a.maxErrorRate {
+				a.errorRateHandler(summary.Namespace, summary.Name)
+			}
+		}
+	}
+
+	// Observe The Resource Usage Of The Dispatchers & Receivers If The Resize Advisor Is Enabled
+	if a.resizeAdvisor.Enabled {
+		a.observeResources(ctx)
+	}
+}
+
+// Scrape The Metrics Of A Single Dispatcher Pod Into The Specified ChannelMetrics
+func (a *Aggregator) scrapePod(ctx context.Context, ip string, port int32, channelMetrics *channelMetrics) error {
+	request, err := http.NewRequest(http.MethodGet, "http://"+net.JoinHostPort(ip, strconv.Itoa(int(port)))+"/metrics", nil)
+	if err != nil {
+		return err
+	}
+	response, err := a.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected metrics response status %d", response.StatusCode)
+	}
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		name, labels, value, ok := parseSample(scanner.Text())
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, metrics.DispatchedEventCountName):
+			channelMetrics.addDispatched(labels[metrics.LabelSubscription], labels[metrics.LabelResult] == metrics.ResultFailure, int64(value))
+		case strings.HasSuffix(name, metrics.ConsumerLagName):
+			channelMetrics.addLag(labels[metrics.LabelSubscription], labels[metrics.LabelPartition], int64(value))
+		}
+	}
+	return scanner.Err()
+}
+
+// Serve The ChannelSummaries Of All KafkaChannels (/channels/), A Namespace (/channels/<namespace>) Or A Single KafkaChannel (/channels/<namespace>/<name>)
+func (a *Aggregator) HandleChannels(responseWriter http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		http.Error(responseWriter, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	pathSegments := strings.Split(strings.Trim(strings.TrimPrefix(request.URL.Path, Path), "/"), "/")
+	if len(pathSegments) > 2 {
+		http.NotFound(responseWriter, request)
+		return
+	}
+
+	// Serve A Single KafkaChannel's ChannelSummary
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	if len(pathSegments) == 2 {
+		summary, ok := a.summaries[pathSegments[0]+"/"+pathSegments[1]]
+		if !ok {
+			http.NotFound(responseWriter, request)
+			return
+		}
+		writeJson(responseWriter, summary)
+		return
+	}
+
+	// Otherwise Serve The (Sorted) ChannelSummaries Of All KafkaChannels Or Those Of A Namespace
+	summaries := make([]*ChannelSummary, 0, len(a.summaries))
+	for _, summary := range a.summaries {
+		if len(pathSegments[0]) == 0 || pathSegments[0] == summary.Namespace {
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Namespace+"/"+summaries[i].Name < summaries[j].Namespace+"/"+summaries[j].Name
+	})
+	writeJson(responseWriter, summaries)
+}
+
+// Utility Function For Writing A JSON Response
+func writeJson(responseWriter http.ResponseWriter, value interface{}) {
+	responseWriter.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(responseWriter).Encode(value)
+}
+
+//
+// Parse A Sample Line Of The Prometheus Text Exposition Format (e.g. 'name{label="value",...} 123 [timestamp]')
+//
+// Only the samples are of interest, so comment (# HELP / # TYPE) and blank lines are skipped, as are any lines
+// which cannot be parsed.
+//
+func parseSample(line string) (string, map[string]string, float64, bool) {
+	line = strings.TrimSpace(line)
+	if len(line) == 0 || strings.HasPrefix(line, "#") {
+		return "", nil, 0, false
+	}
+
+	// Parse The Metric Name & Any Labels
+	labels := make(map[string]string)
+	nameEnd := strings.IndexAny(line, "{ \t")
+	if nameEnd <= 0 {
+		return "", nil, 0, false
+	}
+	name := line[:nameEnd]
+	rest := line[nameEnd:]
+	if strings.HasPrefix(rest, "{") {
+		var ok bool
+		rest, ok = parseLabels(rest[1:], labels)
+		if !ok {
+			return "", nil, 0, false
+		}
+	}
+
+	// Parse The Value (Ignoring Any Timestamp)
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, false
+	}
+	return name, labels, value, true
+}
+
+// Parse The Labels Following The Opening Brace Into The Specified Map, Returning The Remainder After The Closing Brace
+func parseLabels(rest string, labels map[string]string) (string, bool) {
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		if strings.HasPrefix(rest, "}") {
+			return rest[1:], true
+		}
+		equals := strings.Index(rest, "=\"")
+		if equals <= 0 {
+			return "", false
+		}
+		labelName := strings.TrimSpace(rest[:equals])
+		rest = rest[equals+2:]
+		var labelValue strings.Builder
+		closed := false
+		for i := 0; i < len(rest); i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+				switch rest[i] {
+				case 'n':
+					labelValue.WriteByte('\n')
+				default:
+					labelValue.WriteByte(rest[i])
+				}
+			} else if rest[i] == '"' {
+				rest = rest[i+1:]
+				closed = true
+				break
+			} else {
+				labelValue.WriteByte(rest[i])
+			}
+		}
+		if !closed {
+			return "", false
+		}
+		labels[labelName] = labelValue.String()
+	}
+}
+
+// The Raw Dispatcher Metrics Of A KafkaChannel, Accumulated Across Its Dispatcher Pods
+type channelMetrics struct {
+	dispatched map[string]int64            // By Subscription
+	failed     map[string]int64            // By Subscription
+	lag        map[string]map[string]int64 // By Subscription & Partition
+}
+
+// ChannelMetrics Constructor
+func newChannelMetrics() *channelMetrics {
+	return &channelMetrics{
+		dispatched: make(map[string]int64),
+		failed:     make(map[string]int64),
+		lag:        make(map[string]map[string]int64),
+	}
+}
+
+// Accumulate A Subscription's Dispatched Event Count
+func (c *channelMetrics) addDispatched(subscription string, failed bool, count int64) {
+	c.dispatched[subscription] += count
+	if failed {
+		c.failed[subscription] += count
+	}
+}
+
+// Accumulate A Subscription's Partition Lag (Taking The Largest If Reported By Multiple Pods Around A Rebalance)
+func (c *channelMetrics) addLag(subscription string, partition string, lag int64) {
+	if _, ok := c.lag[subscription]; !ok {
+		c.lag[subscription] = make(map[string]int64)
+	}
+	if previousLag, ok := c.lag[subscription][partition]; !ok || lag > previousLag {
+		c.lag[subscription][partition] = lag
+	}
+}
+
+// Summarize The ChannelMetrics Into The Specified ChannelSummary (The Previous ChannelSummary May Be nil)
+func (c *channelMetrics) summarize(summary *ChannelSummary, previous *ChannelSummary) {
+	summary.Subscriptions = make(map[string]*SubscriptionSummary)
+	for subscription, dispatched := range c.dispatched {
+		summary.Subscriptions[subscription] = &SubscriptionSummary{DispatchedEvents: dispatched, FailedEvents: c.failed[subscription]}
+	}
+	for subscription, partitionLags := range c.lag {
+		if _, ok := summary.Subscriptions[subscription]; !ok {
+			summary.Subscriptions[subscription] = &SubscriptionSummary{}
+		}
+		for _, lag := range partitionLags {
+			summary.Subscriptions[subscription].ConsumerLag += lag
+		}
+		if len(partitionLags) > summary.Partitions {
+			summary.Partitions = len(partitionLags)
+		}
+	}
+	for subscription, subscriptionSummary := range summary.Subscriptions {
+		var previousSubscriptionSummary *SubscriptionSummary
+		if previous != nil {
+			previousSubscriptionSummary = previous.Subscriptions[subscription]
+		}
+		subscriptionSummary.ErrorRate = errorRate(subscriptionSummary, previousSubscriptionSummary)
+		if previous != nil {
+			subscriptionSummary.EventsPerSecond, subscriptionSummary.LagPerSecond = rates(subscriptionSummary, previousSubscriptionSummary, summary.ScrapedAt.Sub(previous.ScrapedAt))
+		}
+		summary.ConsumerLag += subscriptionSummary.ConsumerLag
+		summary.DispatchedEvents += subscriptionSummary.DispatchedEvents
+		summary.FailedEvents += subscriptionSummary.FailedEvents
+		summary.EventsPerSecond += subscriptionSummary.EventsPerSecond
+		summary.LagPerSecond += subscriptionSummary.LagPerSecond
+	}
+	var previousChannelSummary *SubscriptionSummary
+	if previous != nil {
+		previousChannelSummary = &previous.SubscriptionSummary
+	}
+	summary.ErrorRate = errorRate(&summary.SubscriptionSummary, previousChannelSummary)
+}
+
+// Utility Function For Calculating The Dispatch & ConsumerLag Growth Rates Since The Previous Summary (Zero If None Or The Counters Were Reset)
+func rates(current *SubscriptionSummary, previous *SubscriptionSummary, elapsed time.Duration) (float64, float64) {
+	if previous == nil || elapsed <= 0 || previous.DispatchedEvents > current.DispatchedEvents {
+		return 0, 0
+	}
+	seconds := elapsed.Seconds()
+	return float64(current.DispatchedEvents-previous.DispatchedEvents) / seconds, float64(current.ConsumerLag-previous.ConsumerLag) / seconds
+}
+
+// Utility Function For Calculating The Error Rate Since The Previous Summary (Or Overall If None Or The Counters Were Reset)
+func errorRate(current *SubscriptionSummary, previous *SubscriptionSummary) float64 {
+	dispatched, failed := current.DispatchedEvents, current.FailedEvents
+	if previous != nil && previous.DispatchedEvents <= dispatched && previous.FailedEvents <= failed {
+		dispatched -= previous.DispatchedEvents
+		failed -= previous.FailedEvents
+	}
+	if dispatched <= 0 {
+		return 0
+	}
+	return float64(failed) / float64(dispatched)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
+)
+
+// Test Data
+const (
+	testNamespace = "test-namespace"
+	testName      = "test-name"
+)
+
+// Test The NewAggregator() Functionality
+func TestNewAggregator(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Nil(t, NewAggregator(logger, fake.NewSimpleClientset(), config.EKMetricsAggregatorConfig{}))
+	aggregator := NewAggregator(logger, fake.NewSimpleClientset(), config.EKMetricsAggregatorConfig{Enabled: true})
+	assert.NotNil(t, aggregator)
+	assert.Equal(t, strconv.Itoa(DefaultPort), aggregator.Port)
+	assert.Equal(t, DefaultScrapeInterval, aggregator.scrapeInterval)
+	aggregator = NewAggregator(logger, fake.NewSimpleClientset(), config.EKMetricsAggregatorConfig{Enabled: true, Port: 9999, ScrapeIntervalMillis: 1000})
+	assert.Equal(t, "9999", aggregator.Port)
+	assert.Equal(t, time.Second, aggregator.scrapeInterval)
+}
+
+// Test A Nil Aggregator Is Inert
+func TestNilAggregator(t *testing.T) {
+	var aggregator *Aggregator
+	assert.Nil(t, aggregator.Start(make(chan struct{})))
+	aggregator.SetErrorRateHandler(0.1, nil)
+	aggregator.Stop()
+}
+
+// Test The parseSample() Functionality
+func TestParseSample(t *testing.T) {
+	name, labels, value, ok := parseSample(`eventing_kafka_consumer_lag{partition="1",subscription="sub-1",topic="a \"quoted\" topic"} 42`)
+	assert.True(t, ok)
+	assert.Equal(t, "eventing_kafka_consumer_lag", name)
+	assert.Equal(t, map[string]string{"partition": "1", "subscription": "sub-1", "topic": `a "quoted" topic`}, labels)
+	assert.Equal(t, float64(42), value)
+	name, labels, value, ok = parseSample("process_open_fds 7 1600000000000")
+	assert.True(t, ok)
+	assert.Equal(t, "process_open_fds", name)
+	assert.Empty(t, labels)
+	assert.Equal(t, float64(7), value)
+	for _, line := range []string{"", "# TYPE eventing_kafka_consumer_lag gauge", `broken{label="value 1`, "novalue", "name NaN-ish"} {
+		_, _, _, ok = parseSample(line)
+		assert.False(t, ok, line)
+	}
+}
+
+// Test The Aggregator's Scrape() & HandleChannels() Functionality
+func TestAggregator(t *testing.T) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace))
+
+	// Create A Test Dispatcher Pod Serving Metrics (Whose Counters Increase Between Scrapes)
+	dispatched := 10
+	pod := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/metrics", request.URL.Path)
+		_, _ = writer.Write([]byte(`# HELP eventing_kafka_dispatched_event_count Dispatched Event Count
+# TYPE eventing_kafka_dispatched_event_count counter
+eventing_kafka_dispatched_event_count{result="success",subscription="sub-1",topic="test-topic"} ` + strconv.Itoa(dispatched) + `
+eventing_kafka_dispatched_event_count{result="failure",subscription="sub-1",topic="test-topic"} 10
+# HELP eventing_kafka_consumer_lag Consumer Lag
+# TYPE eventing_kafka_consumer_lag gauge
+eventing_kafka_consumer_lag{partition="0",subscription="sub-1",topic="test-topic"} 3
+eventing_kafka_consumer_lag{partition="1",subscription="sub-1",topic="test-topic"} 4
+eventing_kafka_consumer_lag{partition="0",subscription="sub-2",topic="test-topic"} 5
+`))
+	}))
+	defer pod.Close()
+	podURL, err := url.Parse(pod.URL)
+	assert.Nil(t, err)
+	podPort, err := strconv.Atoi(podURL.Port())
+	assert.Nil(t, err)
+
+	// Create The Endpoints Of The Dispatcher Service (One Reachable & One Unreachable Pod)
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-dispatcher",
+			Namespace: commonconstants.KnativeEventingNamespace,
+			Labels: map[string]string{
+				constants.KafkaChannelDispatcherLabel: "true",
+				constants.KafkaChannelNamespaceLabel:  testNamespace,
+				constants.KafkaChannelNameLabel:       testName,
+			},
+		},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: podURL.Hostname()}, {IP: "127.0.0.2"}},
+			Ports:     []corev1.EndpointPort{{Name: constants.MetricsPortName, Port: int32(podPort)}},
+		}},
+	}
+	aggregator := NewAggregator(logtesting.TestLogger(t).Desugar(), fake.NewSimpleClientset(endpoints), config.EKMetricsAggregatorConfig{Enabled: true})
+	aggregator.httpClient.Timeout = 100 * time.Millisecond
+
+	// Track The KafkaChannels Passed To The ErrorRate Handler
+	var errorRateChannels []string
+	aggregator.SetErrorRateHandler(0.1, func(namespace string, name string) {
+		errorRateChannels = append(errorRateChannels, namespace+"/"+name)
+	})
+
+	// Verify The First Scrape Summarizes The Overall Counts
+	aggregator.Scrape(context.TODO())
+	assert.Equal(t, []string{testNamespace + "/" + testName}, errorRateChannels)
+	summary := getChannelSummary(t, aggregator, "/channels/"+testNamespace+"/"+testName, http.StatusOK)
+	assert.Equal(t, 1, summary.Pods)
+	assert.Equal(t, 1, summary.ScrapeErrors)
+	assert.Equal(t, int64(12), summary.ConsumerLag)
+	assert.Equal(t, int64(20), summary.DispatchedEvents)
+	assert.Equal(t, int64(10), summary.FailedEvents)
+	assert.Equal(t, 0.5, summary.ErrorRate)
+	assert.Equal(t, &SubscriptionSummary{ConsumerLag: 7, DispatchedEvents: 20, FailedEvents: 10, ErrorRate: 0.5}, summary.Subscriptions["sub-1"])
+	assert.Equal(t, &SubscriptionSummary{ConsumerLag: 5}, summary.Subscriptions["sub-2"])
+
+	// Verify Subsequent Scrapes Summarize The Error Rate Since The Previous Scrape
+	dispatched = 20
+	aggregator.Scrape(context.TODO())
+	summary = getChannelSummary(t, aggregator, "/channels/"+testNamespace+"/"+testName, http.StatusOK)
+	assert.Equal(t, int64(30), summary.DispatchedEvents)
+	assert.Equal(t, float64(0), summary.ErrorRate)
+	assert.Len(t, errorRateChannels, 1)
+
+	// Verify The Listing Of All KafkaChannels & Those Of A Namespace
+	for _, path := range []string{"/channels/", "/channels/" + testNamespace} {
+		recorder := httptest.NewRecorder()
+		aggregator.HandleChannels(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var summaries []*ChannelSummary
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &summaries))
+		assert.Len(t, summaries, 1)
+	}
+	recorder := httptest.NewRecorder()
+	aggregator.HandleChannels(recorder, httptest.NewRequest(http.MethodGet, "/channels/other-namespace", nil))
+	assert.Equal(t, "[]\n", recorder.Body.String())
+
+	// Verify Unknown KafkaChannels & Invalid Requests
+	getChannelSummary(t, aggregator, "/channels/"+testNamespace+"/unknown", http.StatusNotFound)
+	getChannelSummary(t, aggregator, "/channels/a/b/c", http.StatusNotFound)
+	recorder = httptest.NewRecorder()
+	aggregator.HandleChannels(recorder, httptest.NewRequest(http.MethodPost, "/channels/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+// Test The Aggregator's Start() Functionality
+func TestAggregatorStart(t *testing.T) {
+	aggregator := NewAggregator(logtesting.TestLogger(t).Desugar(), fake.NewSimpleClientset(), config.EKMetricsAggregatorConfig{Enabled: true})
+	aggregator.Port = "0"
+	stopChan := make(chan struct{})
+	assert.Nil(t, aggregator.Start(stopChan))
+	response, err := http.Get("http://localhost:" + aggregator.Port + "/channels/")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Nil(t, response.Body.Close())
+	close(stopChan)
+}
+
+// Utility Function For Getting A ChannelSummary From The Aggregator
+func getChannelSummary(t *testing.T, aggregator *Aggregator, path string, expectedStatus int) *ChannelSummary {
+	recorder := httptest.NewRecorder()
+	aggregator.HandleChannels(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, expectedStatus, recorder.Code)
+	if expectedStatus != http.StatusOK {
+		return nil
+	}
+	summary := &ChannelSummary{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), summary))
+	return summary
+}
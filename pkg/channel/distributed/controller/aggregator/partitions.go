@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import "math"
+
+// The Ratio By Which A Subscription's Incoming Event Rate Must Exceed Its Dispatch Rate For The Partitions To Be A Bottleneck
+const BottleneckRatio = 1.1
+
+//
+// Get The Largest Ratio Of Any Subscription's Incoming Event Rate To Its Dispatch Rate (1 If None Is Falling Behind)
+//
+// The dispatcher delivers the events of each partition sequentially, so a subscription's dispatch concurrency
+// (and thereby its throughput) is bounded by the partition count.  A subscription which is dispatching, but whose
+// consumer lag nonetheless grows, receives events faster than its partitions can deliver them.  The incoming rate
+// is estimated as the dispatch rate plus the lag growth rate.  Subscriptions which are not dispatching at all are
+// ignored, as more partitions would not help them.
+//
+func (s *ChannelSummary) IncomingRatio() float64 {
+	ratio := 1.0
+	for _, subscription := range s.Subscriptions {
+		if subscription.EventsPerSecond > 0 && subscription.LagPerSecond > 0 {
+			subscriptionRatio := (subscription.EventsPerSecond + subscription.LagPerSecond) / subscription.EventsPerSecond
+			if subscriptionRatio > ratio {
+				ratio = subscriptionRatio
+			}
+		}
+	}
+	return ratio
+}
+
+// Determine Whether The KafkaChannel's Partitions Are A Bottleneck Of Any Of Its Subscriptions
+func (s *ChannelSummary) PartitionsBottleneck() bool {
+	return s.IncomingRatio() >= BottleneckRatio
+}
+
+// Recommend The Partition Count At Which The Dispatch Rate Of Every Subscription Would Keep Up With Its Incoming Rate (At Least The Current Partitions)
+func (s *ChannelSummary) RecommendedPartitions(partitions int32) int32 {
+	if !s.PartitionsBottleneck() {
+		return partitions
+	}
+	return int32(math.Ceil(float64(partitions) * s.IncomingRatio()))
+}
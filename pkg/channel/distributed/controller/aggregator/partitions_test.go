@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ChannelSummary's Partition Recommendation Functionality
+func TestRecommendedPartitions(t *testing.T) {
+	summary := &ChannelSummary{Subscriptions: map[string]*SubscriptionSummary{
+		"keeping-up":  {EventsPerSecond: 100, LagPerSecond: -5},
+		"not-started": {LagPerSecond: 50},
+	}}
+	assert.Equal(t, 1.0, summary.IncomingRatio())
+	assert.False(t, summary.PartitionsBottleneck())
+	assert.Equal(t, int32(4), summary.RecommendedPartitions(4))
+
+	summary.Subscriptions["slightly-behind"] = &SubscriptionSummary{EventsPerSecond: 100, LagPerSecond: 5}
+	assert.False(t, summary.PartitionsBottleneck())
+	assert.Equal(t, int32(4), summary.RecommendedPartitions(4))
+
+	summary.Subscriptions["falling-behind"] = &SubscriptionSummary{EventsPerSecond: 100, LagPerSecond: 50}
+	assert.Equal(t, 1.5, summary.IncomingRatio())
+	assert.True(t, summary.PartitionsBottleneck())
+	assert.Equal(t, int32(6), summary.RecommendedPartitions(4))
+	assert.Equal(t, int32(5), summary.RecommendedPartitions(3))
+}
+
+// Test The rates() Functionality
+func TestRates(t *testing.T) {
+	current := &SubscriptionSummary{DispatchedEvents: 300, ConsumerLag: 50}
+	eventsPerSecond, lagPerSecond := rates(current, nil, time.Second)
+	assert.Equal(t, float64(0), eventsPerSecond)
+	assert.Equal(t, float64(0), lagPerSecond)
+	eventsPerSecond, lagPerSecond = rates(current, &SubscriptionSummary{DispatchedEvents: 100, ConsumerLag: 70}, 2*time.Second)
+	assert.Equal(t, float64(100), eventsPerSecond)
+	assert.Equal(t, float64(-10), lagPerSecond)
+	eventsPerSecond, _ = rates(current, &SubscriptionSummary{DispatchedEvents: 500}, 2*time.Second)
+	assert.Equal(t, float64(0), eventsPerSecond)
+	eventsPerSecond, _ = rates(current, &SubscriptionSummary{DispatchedEvents: 100}, 0)
+	assert.Equal(t, float64(0), eventsPerSecond)
+}
+
+// Test The Aggregator's Bottleneck Handler & Summary() Functionality
+func TestAggregatorBottleneck(t *testing.T) {
+
+	// Create A Test Dispatcher Pod Whose Consumer Lag Grows Between Scrapes
+	dispatched, lag := 0, 0
+	pod := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`eventing_kafka_dispatched_event_count{result="success",subscription="sub-1",topic="test-topic"} ` + strconv.Itoa(dispatched) + `
+eventing_kafka_consumer_lag{partition="0",subscription="sub-1",topic="test-topic"} ` + strconv.Itoa(lag) + `
+eventing_kafka_consumer_lag{partition="1",subscription="sub-1",topic="test-topic"} 0
+`))
+	}))
+	defer pod.Close()
+	podURL, err := url.Parse(pod.URL)
+	assert.Nil(t, err)
+	podPort, err := strconv.Atoi(podURL.Port())
+	assert.Nil(t, err)
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-dispatcher",
+			Namespace: commonconstants.KnativeEventingNamespace,
+			Labels: map[string]string{
+				constants.KafkaChannelDispatcherLabel: "true",
+				constants.KafkaChannelNamespaceLabel:  testNamespace,
+				constants.KafkaChannelNameLabel:       testName,
+			},
+		},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: podURL.Hostname()}},
+			Ports:     []corev1.EndpointPort{{Name: constants.MetricsPortName, Port: int32(podPort)}},
+		}},
+	}
+
+	// Create An Aggregator Tracking The KafkaChannels Passed To The Bottleneck Handler
+	aggregator := NewAggregator(logtesting.TestLogger(t).Desugar(), fake.NewSimpleClientset(endpoints), config.EKMetricsAggregatorConfig{Enabled: true})
+	var bottlenecks []string
+	aggregator.SetBottleneckHandler(func(namespace string, name string) {
+		bottlenecks = append(bottlenecks, namespace+"/"+name)
+	})
+	assert.Nil(t, aggregator.Summary(testNamespace, testName))
+
+	// Utility Function For Scraping As Though The Previous Scrape Occurred One Second Earlier
+	scrape := func() *ChannelSummary {
+		if previous := aggregator.Summary(testNamespace, testName); previous != nil {
+			previous.ScrapedAt = previous.ScrapedAt.Add(-time.Second)
+		}
+		aggregator.Scrape(context.TODO())
+		return aggregator.Summary(testNamespace, testName)
+	}
+
+	// The First Scrape Has No Rates
+	summary := scrape()
+	assert.Equal(t, 2, summary.Partitions)
+	assert.False(t, summary.PartitionsBottleneck())
+	assert.Empty(t, bottlenecks)
+
+	// Lag Growing Faster Than The Dispatch Rate Is A Bottleneck
+	dispatched, lag = 100, 100
+	summary = scrape()
+	assert.InDelta(t, 100, summary.EventsPerSecond, 1)
+	assert.InDelta(t, 100, summary.LagPerSecond, 1)
+	assert.True(t, summary.PartitionsBottleneck())
+	assert.Equal(t, []string{testNamespace + "/" + testName}, bottlenecks)
+
+	// The Handler Is Also Called Once The Bottleneck Is Resolved, But Not Thereafter
+	dispatched, lag = 200, 0
+	assert.False(t, scrape().PartitionsBottleneck())
+	assert.Len(t, bottlenecks, 2)
+	dispatched = 300
+	scrape()
+	assert.Len(t, bottlenecks, 2)
+
+	// A Nil Aggregator Has No Summaries
+	var nilAggregator *Aggregator
+	nilAggregator.SetBottleneckHandler(nil)
+	assert.Nil(t, nilAggregator.Summary(testNamespace, testName))
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/pkg/system"
+)
+
+// Resize Advisor Constants
+const (
+	PodMetricsPath          = "/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods" // The metrics-server's Resource Metrics API
+	DeploymentsPath         = "/deployments/"
+	DefaultResizeWindow     = time.Hour
+	DefaultHeadroomPercent  = 20
+	DefaultTolerancePercent = 30
+	MinResizeSamples        = 10
+	cpuRoundingMillis       = 10
+	memoryRoundingBytes     = 1024 * 1024
+)
+
+// ContainerResources Compares The Resource Requests Of A Dispatcher Or Receiver Container With Its Peak Usage
+type ContainerResources struct {
+	Name                 string             `json:"name"`
+	CpuRequest           resource.Quantity  `json:"cpuRequest"`
+	MemoryRequest        resource.Quantity  `json:"memoryRequest"`
+	CpuUsage             resource.Quantity  `json:"cpuUsage"`                       // The Peak Usage Of Any Pod Within The Window
+	MemoryUsage          resource.Quantity  `json:"memoryUsage"`                    // The Peak Usage Of Any Pod Within The Window
+	CpuRecommendation    *resource.Quantity `json:"cpuRecommendation,omitempty"`    // Only If The Request Should Be Resized
+	MemoryRecommendation *resource.Quantity `json:"memoryRecommendation,omitempty"` // Only If The Request Should Be Resized
+}
+
+// DeploymentResources Summarizes The ContainerResources Of A Dispatcher Or Receiver Deployment
+type DeploymentResources struct {
+	Name       string               `json:"name"`
+	Pods       int                  `json:"pods"`    // The Pods Reporting Usage In The Latest Sample
+	Samples    int                  `json:"samples"` // The Samples Within The Window (No Recommendations Before MinResizeSamples)
+	Containers []ContainerResources `json:"containers"`
+	ObservedAt time.Time            `json:"observedAt"`
+	labels     map[string]string
+}
+
+// Get Descriptions Of The Recommended Resizes Of The Deployment's Containers (e.g. "dispatcher-abc/dispatcher-abc cpu 500m -> 120m")
+func (d *DeploymentResources) ResizeRecommendations() []string {
+	if d == nil {
+		return nil
+	}
+	var recommendations []string
+	for _, container := range d.Containers {
+		if container.CpuRecommendation != nil {
+			recommendations = append(recommendations, fmt.Sprintf("%s/%s cpu %s -> %s", d.Name, container.Name, container.CpuRequest.String(), container.CpuRecommendation.String()))
+		}
+		if container.MemoryRecommendation != nil {
+			recommendations = append(recommendations, fmt.Sprintf("%s/%s memory %s -> %s", d.Name, container.Name, container.MemoryRequest.String(), container.MemoryRecommendation.String()))
+		}
+	}
+	return recommendations
+}
+
+// The Subset Of The metrics-server's PodMetrics Needed By The Resize Advisor (Avoiding A Dependency On k8s.io/metrics)
+type podMetrics struct {
+	Metadata   metav1.ObjectMeta  `json:"metadata"`
+	Containers []containerMetrics `json:"containers"`
+}
+
+// The Subset Of The metrics-server's ContainerMetrics Needed By The Resize Advisor
+type containerMetrics struct {
+	Name  string              `json:"name"`
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+// The Peak Usage Of Each Container Of A Deployment (Across Its Pods) At A Point In Time
+type resourceSample struct {
+	time        time.Time
+	cpuMillis   map[string]int64
+	memoryBytes map[string]int64
+}
+
+// Set The Function Called After Each Scrape With The Labels Of Every Deployment Whose Recommended Resizes Changed (Must Precede Start())
+func (a *Aggregator) SetResizeHandler(resizeHandler func(labels map[string]string)) {
+	if a == nil {
+		return
+	}
+	a.resizeHandler = resizeHandler
+}
+
+// Get The Latest DeploymentResources Of The Specified Dispatcher Or Receiver Deployment (nil If Not Observed Or The Resize Advisor Is Not Enabled)
+func (a *Aggregator) Resources(deploymentName string) *DeploymentResources {
+	if a == nil {
+		return nil
+	}
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.resources[deploymentName]
+}
+
+//
+// Observe The Resource Usage Of All Dispatcher & Receiver Pods & Replace The DeploymentResources
+//
+// The usage of each container is sampled from the metrics-server's resource metrics API, whose PodMetrics are
+// matched to the Deployments by their selectors.  Each sample records the peak usage of any of the Deployment's
+// pods, and the samples are retained for the window.  Once enough samples have been taken a request is resized to
+// the peak usage within the window plus the headroom (rounded up) if it deviates from that by more than the
+// tolerance.  Clusters without a metrics-server are logged but otherwise unaffected.
+//
+func (a *Aggregator) observeResources(ctx context.Context) {
+
+	// List The Dispatcher & Receiver Deployments
+	deploymentList, err := a.kubeClient.AppsV1().Deployments(system.Namespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		a.logger.Error("Failed To List Deployments", zap.Error(err))
+		return
+	}
+
+	// Get The Current Resource Usage Of All Pods
+	podMetricsList, err := a.podMetrics(ctx)
+	if err != nil {
+		a.logger.Warn("Failed To Get Pod Metrics (Is The metrics-server Installed?)", zap.Error(err))
+		return
+	}
+
+	// Sample The Peak Usage Of Each Deployment's Containers & Summarize Them Against The Requests
+	now := time.Now()
+	resourceSamples := make(map[string][]resourceSample)
+	resources := make(map[string]*DeploymentResources)
+	for i := range deploymentList.Items {
+		deployment := &deploymentList.Items[i]
+		if deployment.Labels[constants.KafkaChannelDispatcherLabel] != "true" && deployment.Labels[constants.KafkaChannelReceiverLabel] != "true" {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		sample := resourceSample{time: now, cpuMillis: make(map[string]int64), memoryBytes: make(map[string]int64)}
+		pods := 0
+		for _, pod := range podMetricsList {
+			if !selector.Matches(labels.Set(pod.Metadata.Labels)) {
+				continue
+			}
+			pods++
+			for _, container := range pod.Containers {
+				if cpuMillis := container.Usage.Cpu().MilliValue(); cpuMillis > sample.cpuMillis[container.Name] {
+					sample.cpuMillis[container.Name] = cpuMillis
+				}
+				if memoryBytes := container.Usage.Memory().Value(); memoryBytes > sample.memoryBytes[container.Name] {
+					sample.memoryBytes[container.Name] = memoryBytes
+				}
+			}
+		}
+		samples := a.resourceSamples[deployment.Name]
+		if pods > 0 {
+			samples = append(samples, sample)
+		}
+		for len(samples) > 0 && samples[0].time.Before(now.Add(-a.resizeWindow)) {
+			samples = samples[1:]
+		}
+		resourceSamples[deployment.Name] = samples
+		resources[deployment.Name] = a.summarizeResources(deployment, pods, samples, now)
+	}
+	a.resourceSamples = resourceSamples
+
+	// Replace The DeploymentResources
+	a.lock.Lock()
+	previousResources := a.resources
+	a.resources = resources
+	a.lock.Unlock()
+
+	// Notify The Handler Of Deployments Whose Recommended Resizes Changed
+	if a.resizeHandler != nil {
+		for name, deploymentResources := range resources {
+			recommendations := strings.Join(deploymentResources.ResizeRecommendations(), ", ")
+			if recommendations != strings.Join(previousResources[name].ResizeRecommendations(), ", ") {
+				a.resizeHandler(deploymentResources.labels)
+			}
+		}
+	}
+}
+
+// Summarize The Resource Samples Of The Specified Deployment's Containers Against Their Requests
+func (a *Aggregator) summarizeResources(deployment *appsv1.Deployment, pods int, samples []resourceSample, now time.Time) *DeploymentResources {
+	deploymentResources := &DeploymentResources{
+		Name:       deployment.Name,
+		Pods:       pods,
+		Samples:    len(samples),
+		ObservedAt: now,
+		labels:     deployment.Labels,
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		var peakCpuMillis, peakMemoryBytes int64
+		for _, sample := range samples {
+			if sample.cpuMillis[container.Name] > peakCpuMillis {
+				peakCpuMillis = sample.cpuMillis[container.Name]
+			}
+			if sample.memoryBytes[container.Name] > peakMemoryBytes {
+				peakMemoryBytes = sample.memoryBytes[container.Name]
+			}
+		}
+		containerResources := ContainerResources{
+			Name:          container.Name,
+			CpuRequest:    *container.Resources.Requests.Cpu(),
+			MemoryRequest: *container.Resources.Requests.Memory(),
+			CpuUsage:      *resource.NewMilliQuantity(peakCpuMillis, resource.DecimalSI),
+			MemoryUsage:   *resource.NewQuantity(peakMemoryBytes, resource.BinarySI),
+		}
+		if len(samples) >= MinResizeSamples {
+			recommendedCpuMillis := a.recommendedRequest(peakCpuMillis, cpuRoundingMillis)
+			if a.resizeRecommended(containerResources.CpuRequest.MilliValue(), recommendedCpuMillis) {
+				containerResources.CpuRecommendation = resource.NewMilliQuantity(recommendedCpuMillis, resource.DecimalSI)
+			}
+			recommendedMemoryBytes := a.recommendedRequest(peakMemoryBytes, memoryRoundingBytes)
+			if a.resizeRecommended(containerResources.MemoryRequest.Value(), recommendedMemoryBytes) {
+				containerResources.MemoryRecommendation = resource.NewQuantity(recommendedMemoryBytes, resource.BinarySI)
+			}
+		}
+		deploymentResources.Containers = append(deploymentResources.Containers, containerResources)
+	}
+	return deploymentResources
+}
+
+// Get The Recommended Request For The Specified Peak Usage (Plus The Headroom, Rounded Up To A Multiple Of The Rounding)
+func (a *Aggregator) recommendedRequest(peakUsage int64, rounding int64) int64 {
+	request := peakUsage * int64(100+a.resizeAdvisor.HeadroomPercent) / 100
+	if request < rounding {
+		return rounding
+	}
+	return (request + rounding - 1) / rounding * rounding
+}
+
+// Determine Whether The Specified Request Deviates From The Recommended Request By More Than The Tolerance
+func (a *Aggregator) resizeRecommended(request int64, recommendedRequest int64) bool {
+	if request <= 0 {
+		return true
+	}
+	deviation := recommendedRequest - request
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	return deviation*100 > request*int64(a.resizeAdvisor.TolerancePercent)
+}
+
+// Get The Resource Usage Of All Pods In The System Namespace From The metrics-server's Resource Metrics API
+func (a *Aggregator) getPodMetrics(ctx context.Context) ([]podMetrics, error) {
+	restClient := a.kubeClient.Discovery().RESTClient()
+	if restClient == nil {
+		return nil, fmt.Errorf("no REST client for the resource metrics API")
+	}
+	body, err := restClient.Get().AbsPath(fmt.Sprintf(PodMetricsPath, system.Namespace())).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	podMetricsList := struct {
+		Items []podMetrics `json:"items"`
+	}{}
+	err = json.Unmarshal(body, &podMetricsList)
+	if err != nil {
+		return nil, err
+	}
+	return podMetricsList.Items, nil
+}
+
+// Serve The DeploymentResources Of All Dispatchers & Receivers (/deployments/) Or A Single Deployment (/deployments/<name>)
+func (a *Aggregator) HandleDeployments(responseWriter http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		http.Error(responseWriter, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.Trim(strings.TrimPrefix(request.URL.Path, DeploymentsPath), "/")
+	if strings.Contains(name, "/") {
+		http.NotFound(responseWriter, request)
+		return
+	}
+
+	// Serve A Single Deployment's DeploymentResources
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	if len(name) > 0 {
+		deploymentResources, ok := a.resources[name]
+		if !ok {
+			http.NotFound(responseWriter, request)
+			return
+		}
+		writeJson(responseWriter, deploymentResources)
+		return
+	}
+
+	// Otherwise Serve The (Sorted) DeploymentResources Of All Deployments
+	resources := make([]*DeploymentResources, 0, len(a.resources))
+	for _, deploymentResources := range a.resources {
+		resources = append(resources, deploymentResources)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+	writeJson(responseWriter, resources)
+}
+
+// Utility Function For Defaulting The Resize Advisor Configuration
+func defaultResizeAdvisorConfig(resizeAdvisorConfig config.EKResizeAdvisorConfig) (config.EKResizeAdvisorConfig, time.Duration) {
+	if resizeAdvisorConfig.HeadroomPercent == 0 {
+		resizeAdvisorConfig.HeadroomPercent = DefaultHeadroomPercent
+	}
+	if resizeAdvisorConfig.TolerancePercent == 0 {
+		resizeAdvisorConfig.TolerancePercent = DefaultTolerancePercent
+	}
+	resizeWindow := time.Duration(resizeAdvisorConfig.WindowMillis) * time.Millisecond
+	if resizeWindow <= 0 {
+		resizeWindow = DefaultResizeWindow
+	}
+	return resizeAdvisorConfig, resizeWindow
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Aggregator's observeResources() & HandleDeployments() Functionality
+func TestAggregatorResources(t *testing.T) {
+
+	// Create A Dispatcher Deployment Requesting 500m CPU & 64Mi Memory, And An Unrelated Deployment
+	dispatcher := newTestDeployment("test-dispatcher", map[string]string{
+		constants.KafkaChannelDispatcherLabel: "true",
+		constants.KafkaChannelNamespaceLabel:  testNamespace,
+		constants.KafkaChannelNameLabel:       testName,
+	}, "500m", "64Mi")
+	unrelated := newTestDeployment("unrelated", map[string]string{}, "100m", "32Mi")
+	aggregator := NewAggregator(logtesting.TestLogger(t).Desugar(), fake.NewSimpleClientset(dispatcher, unrelated),
+		config.EKMetricsAggregatorConfig{Enabled: true, ResizeAdvisor: config.EKResizeAdvisorConfig{Enabled: true}})
+	assert.Equal(t, DefaultHeadroomPercent, aggregator.resizeAdvisor.HeadroomPercent)
+	assert.Equal(t, DefaultTolerancePercent, aggregator.resizeAdvisor.TolerancePercent)
+	assert.Equal(t, DefaultResizeWindow, aggregator.resizeWindow)
+
+	// Report The Usage Of Two Dispatcher Pods (The Peak Of Which Is 100m CPU & 60Mi Memory) & An Unrelated Pod
+	aggregator.podMetrics = func(ctx context.Context) ([]podMetrics, error) {
+		return []podMetrics{
+			newTestPodMetrics("test-dispatcher", "test-dispatcher", "100m", "40Mi"),
+			newTestPodMetrics("test-dispatcher", "test-dispatcher", "80m", "60Mi"),
+			newTestPodMetrics("unrelated", "unrelated", "900m", "900Mi"),
+		}, nil
+	}
+	var resizedLabels []map[string]string
+	aggregator.SetResizeHandler(func(labels map[string]string) {
+		resizedLabels = append(resizedLabels, labels)
+	})
+
+	// Verify No Resizes Are Recommended Before Enough Samples Have Been Taken
+	for i := 1; i < MinResizeSamples; i++ {
+		aggregator.Scrape(context.TODO())
+	}
+	resources := aggregator.Resources("test-dispatcher")
+	assert.NotNil(t, resources)
+	assert.Equal(t, 2, resources.Pods)
+	assert.Equal(t, MinResizeSamples-1, resources.Samples)
+	assert.Empty(t, resources.ResizeRecommendations())
+	assert.Empty(t, resizedLabels)
+	assert.Nil(t, aggregator.Resources("unrelated"))
+
+	// Verify The CPU Request Is Resized To The Peak Plus Headroom Once Enough Samples Have Been Taken (The Memory Is Within Tolerance)
+	aggregator.Scrape(context.TODO())
+	resources = aggregator.Resources("test-dispatcher")
+	assert.Len(t, resources.Containers, 1)
+	container := resources.Containers[0]
+	assert.Equal(t, "100m", container.CpuUsage.String())
+	assert.Equal(t, "60Mi", container.MemoryUsage.String())
+	assert.Equal(t, "120m", container.CpuRecommendation.String())
+	assert.Nil(t, container.MemoryRecommendation)
+	assert.Equal(t, []string{"test-dispatcher/test-dispatcher cpu 500m -> 120m"}, resources.ResizeRecommendations())
+	assert.Equal(t, []map[string]string{dispatcher.Labels}, resizedLabels)
+
+	// Verify The Handler Is Not Called Again While The Recommendations Remain Unchanged
+	aggregator.Scrape(context.TODO())
+	assert.Len(t, resizedLabels, 1)
+
+	// Verify The Serving Of The DeploymentResources
+	recorder := httptest.NewRecorder()
+	aggregator.HandleDeployments(recorder, httptest.NewRequest(http.MethodGet, "/deployments/test-dispatcher", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	served := &DeploymentResources{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), served))
+	assert.Equal(t, "test-dispatcher", served.Name)
+	assert.Equal(t, resources.ResizeRecommendations(), served.ResizeRecommendations())
+	recorder = httptest.NewRecorder()
+	aggregator.HandleDeployments(recorder, httptest.NewRequest(http.MethodGet, "/deployments/", nil))
+	var servedList []*DeploymentResources
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &servedList))
+	assert.Len(t, servedList, 1)
+	for path, expectedStatus := range map[string]int{"/deployments/unknown": http.StatusNotFound, "/deployments/a/b": http.StatusNotFound} {
+		recorder = httptest.NewRecorder()
+		aggregator.HandleDeployments(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, expectedStatus, recorder.Code)
+	}
+	recorder = httptest.NewRecorder()
+	aggregator.HandleDeployments(recorder, httptest.NewRequest(http.MethodPost, "/deployments/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+// Test The Aggregator's Recommended Requests & Tolerance
+func TestAggregatorResizeRecommended(t *testing.T) {
+	aggregator := NewAggregator(logtesting.TestLogger(t).Desugar(), fake.NewSimpleClientset(),
+		config.EKMetricsAggregatorConfig{Enabled: true, ResizeAdvisor: config.EKResizeAdvisorConfig{Enabled: true, HeadroomPercent: 50, TolerancePercent: 10}})
+	assert.Equal(t, int64(10), aggregator.recommendedRequest(0, cpuRoundingMillis))
+	assert.Equal(t, int64(150), aggregator.recommendedRequest(100, cpuRoundingMillis))
+	assert.Equal(t, int64(160), aggregator.recommendedRequest(101, cpuRoundingMillis))
+	assert.True(t, aggregator.resizeRecommended(0, 10))
+	assert.False(t, aggregator.resizeRecommended(100, 110))
+	assert.True(t, aggregator.resizeRecommended(100, 111))
+	assert.True(t, aggregator.resizeRecommended(100, 89))
+}
+
+// Test The Aggregator Tolerates A Missing metrics-server
+func TestAggregatorResourcesUnavailable(t *testing.T) {
+	aggregator := NewAggregator(logtesting.TestLogger(t).Desugar(), fake.NewSimpleClientset(),
+		config.EKMetricsAggregatorConfig{Enabled: true, ResizeAdvisor: config.EKResizeAdvisorConfig{Enabled: true}})
+	_, err := aggregator.getPodMetrics(context.TODO())
+	assert.NotNil(t, err)
+	aggregator.Scrape(context.TODO())
+	assert.Nil(t, aggregator.Resources("test-dispatcher"))
+	var nilResources *DeploymentResources
+	assert.Nil(t, nilResources.ResizeRecommendations())
+}
+
+// Utility Function For Creating A Test Deployment With A Single Container
+func newTestDeployment(name string, labels map[string]string, cpuRequest string, memoryRequest string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: commonconstants.KnativeEventingNamespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{constants.AppLabel: name}},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: name,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse(cpuRequest),
+								corev1.ResourceMemory: resource.MustParse(memoryRequest),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// Utility Function For Creating The Test PodMetrics Of A Deployment's Pod
+func newTestPodMetrics(deploymentName string, containerName string, cpuUsage string, memoryUsage string) podMetrics {
+	return podMetrics{
+		Metadata: metav1.ObjectMeta{Labels: map[string]string{constants.AppLabel: deploymentName}},
+		Containers: []containerMetrics{{
+			Name: containerName,
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpuUsage),
+				corev1.ResourceMemory: resource.MustParse(memoryUsage),
+			},
+		}},
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+)
+
+// Audit Log Constants
+const (
+	DefaultTopic      = "knative-eventing.kafka-audit"
+	DefaultBufferSize = 1000
+	EventTypePrefix   = "dev.knative.kafka.audit."
+)
+
+// The Kinds Of Audited Resources
+const (
+	KindTopic      = "Topic"
+	KindDeployment = "Deployment"
+	KindSecret     = "Secret"
+)
+
+// The Audited Actions
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+	ActionRotated = "rotated"
+)
+
+// The Interval Between Attempts To Produce A Record After A Failure (Variable For Testing)
+var RetryInterval = 5 * time.Second
+
+// Entry Is The Data Of An Audit Record Describing An Action Of The Controller On A Resource
+type Entry struct {
+	Kind      string            `json:"kind"`
+	Action    string            `json:"action"`
+	Namespace string            `json:"namespace,omitempty"`
+	Name      string            `json:"name"`
+	Channel   string            `json:"channel,omitempty"` // The <namespace>/<name> Of The KafkaChannel Acted On Behalf Of
+	Details   map[string]string `json:"details,omitempty"`
+	Time      time.Time         `json:"time"`
+}
+
+// Recorder Records Audit Entries (Implemented By The Log)
+type Recorder interface {
+	Record(entry Entry)
+}
+
+// Verify The Log Implements The Recorder Interface
+var _ Recorder = &Log{}
+
+// The Sarama SyncProducer Creation Function Variable To Facilitate Unit Testing
+var NewSyncProducerWrapper = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+	return sarama.NewSyncProducer(brokers, config)
+}
+
+//
+// Control Plane Audit Log
+//
+// The Log records the actions of the controller on the Kafka topics, dispatcher & receiver Deployments and Kafka
+// Secrets as CloudEvents (of type dev.knative.kafka.audit.<kind>.<action>, whose data is the JSON Entry) produced to
+// the audit topic, keyed by the KafkaChannel (or resource) acted upon so that the records of each are ordered.
+// Records are buffered and produced in the background so that reconciliation is never blocked by the audit topic, and
+// a record which fails to be produced is retried (with a new SyncProducer) until it succeeds.  Records are only
+// dropped if the buffer is full, which is logged.
+//
+type Log struct {
+	logger      *zap.Logger
+	topic       string
+	source      string
+	entries     chan Entry
+	newProducer func() (sarama.SyncProducer, error)
+}
+
+// Log Constructor - Returns nil If The Audit Log Is Not Enabled (All Methods Are No-Ops On A nil Log)
+func NewLog(logger *zap.Logger, auditConfig config.EKAuditConfig, podName string, newProducer func() (sarama.SyncProducer, error)) *Log {
+	if !auditConfig.Enabled {
+		return nil
+	}
+	topic := auditConfig.Topic
+	if len(topic) == 0 {
+		topic = DefaultTopic
+	}
+	bufferSize := auditConfig.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Log{
+		logger:      logger,
+		topic:       topic,
+		source:      "/" + constants.ControllerComponentName + "/" + podName,
+		entries:     make(chan Entry, bufferSize),
+		newProducer: newProducer,
+	}
+}
+
+// Record The Specified Entry (Timestamped Now), Dropping It If The Buffer Is Full
+func (l *Log) Record(entry Entry) {
+	if l == nil {
+		return
+	}
+	entry.Time = time.Now().UTC()
+	select {
+	case l.entries <- entry:
+	default:
+		l.logger.Error("Audit Log Buffer Full - Dropping Record", zap.Any("Entry", entry))
+	}
+}
+
+// Start Producing The Recorded Entries To The Audit Topic Until The Stop Channel Is Closed
+func (l *Log) Start(stopChan <-chan struct{}) {
+	if l == nil {
+		return
+	}
+	l.logger.Info("Starting Audit Log", zap.String("Topic", l.topic), zap.Int("BufferSize", cap(l.entries)))
+	go func() {
+		var producer sarama.SyncProducer
+		defer func() {
+			if producer != nil {
+				_ = producer.Close()
+			}
+		}()
+		for {
+			select {
+			case entry := <-l.entries:
+				producer = l.produce(producer, entry, stopChan)
+			case <-stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Produce The Specified Entry To The Audit Topic, Retrying Until It Succeeds Or The Stop Channel Is Closed
+// (Returning The SyncProducer To Use For The Next Entry, Which Is Created If nil)
+func (l *Log) produce(producer sarama.SyncProducer, entry Entry, stopChan <-chan struct{}) sarama.SyncProducer {
+	message, err := l.newMessage(entry)
+	if err != nil {
+		l.logger.Error("Failed To Create Audit Record - Dropping Record", zap.Any("Entry", entry), zap.Error(err))
+		return producer
+	}
+	for {
+		if producer == nil {
+			producer, err = l.newProducer()
+		}
+		if err == nil {
+			_, _, err = producer.SendMessage(message)
+			if err == nil {
+				return producer
+			}
+			_ = producer.Close()
+			producer = nil
+		}
+		l.logger.Warn("Failed To Produce Audit Record - Retrying", zap.Any("Entry", entry), zap.Duration("RetryInterval", RetryInterval), zap.Error(err))
+		select {
+		case <-time.After(RetryInterval):
+		case <-stopChan:
+			return producer
+		}
+	}
+}
+
+// Create The ProducerMessage Of The CloudEvent Recording The Specified Entry
+func (l *Log) newMessage(entry Entry) (*sarama.ProducerMessage, error) {
+	auditEvent := event.New()
+	auditEvent.SetID(uuid.New().String())
+	auditEvent.SetType(EventTypePrefix + strings.ToLower(entry.Kind) + "." + entry.Action)
+	auditEvent.SetSource(l.source)
+	auditEvent.SetSubject(entryKey(entry))
+	auditEvent.SetTime(entry.Time)
+	err := auditEvent.SetData(event.ApplicationJSON, entry)
+	if err != nil {
+		return nil, err
+	}
+	key := entry.Channel
+	if len(key) == 0 {
+		key = entryKey(entry)
+	}
+	message := &sarama.ProducerMessage{Topic: l.topic, Key: sarama.StringEncoder(key)}
+	err = kafkasaramaprotocol.WriteProducerMessage(context.Background(), binding.ToMessage(&auditEvent), message)
+	return message, err
+}
+
+// Get The <kind>/[<namespace>/]<name> Of The Resource Of The Specified Entry
+func entryKey(entry Entry) string {
+	if len(entry.Namespace) > 0 {
+		return entry.Kind + "/" + entry.Namespace + "/" + entry.Name
+	}
+	return entry.Kind + "/" + entry.Name
+}
+
+// Create & Start The Audit Log Of The Controller (nil If Not Enabled), Producing Via The Controller's Kafka Credentials
+func StartControllerLog(ctx context.Context, logger *zap.Logger, kubeClientset kubernetes.Interface, configuration *config.EventingKafkaConfig, saramaConfig *sarama.Config, podName string) Recorder {
+	log := NewLog(logger, configuration.Audit, podName, func() (sarama.SyncProducer, error) {
+		brokers, producerConfig, err := util.ControllerKafkaClientConfig(ctx, logger, kubeClientset, configuration, saramaConfig, podName)
+		if err != nil {
+			return nil, err
+		}
+		producerConfig.Producer.Return.Successes = true
+		producerConfig.Producer.RequiredAcks = sarama.WaitForAll
+		return NewSyncProducerWrapper(brokers, producerConfig)
+	})
+	if log == nil {
+		return nil
+	}
+	log.Start(ctx.Done())
+	return log
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Log Produces The Recorded Entries As CloudEvents, Retrying Failures
+func TestLog(t *testing.T) {
+
+	// Reduce The RetryInterval & Restore After The Test
+	retryIntervalPlaceholder := RetryInterval
+	RetryInterval = time.Millisecond
+	defer func() { RetryInterval = retryIntervalPlaceholder }()
+
+	// Create The Mock SyncProducers, The First Of Which Fails
+	producers := []*mockSyncProducer{{err: errors.New("test error")}, {}}
+	producersCreated := 0
+	newProducer := func() (sarama.SyncProducer, error) {
+		producer := producers[producersCreated]
+		producersCreated++
+		return producer, nil
+	}
+
+	// Create & Start The Log
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	log := NewLog(logtesting.TestLogger(t).Desugar(), config.EKAuditConfig{Enabled: true}, "TestPodName", newProducer)
+	log.Start(stopChan)
+
+	// Record An Entry & Wait For It To Be Produced
+	log.Record(Entry{Kind: KindTopic, Action: ActionCreated, Name: "TestTopic", Channel: "TestNamespace/TestChannel", Details: map[string]string{"partitions": "4"}})
+	assert.Eventually(t, func() bool { return len(producers[1].getMessages()) == 1 }, time.Second, time.Millisecond)
+
+	// Verify The Failing Producer Was Closed & Replaced
+	assert.True(t, producers[0].closed)
+	assert.Equal(t, 2, producersCreated)
+
+	// Verify The Produced CloudEvent
+	message := producers[1].getMessages()[0]
+	assert.Equal(t, DefaultTopic, message.Topic)
+	assert.Equal(t, sarama.StringEncoder("TestNamespace/TestChannel"), message.Key)
+	headers := make(map[string]string)
+	for _, header := range message.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	assert.Equal(t, "dev.knative.kafka.audit.topic.created", headers["ce_type"])
+	assert.Equal(t, "/eventing-kafka-channel-controller/TestPodName", headers["ce_source"])
+	assert.Equal(t, "Topic/TestTopic", headers["ce_subject"])
+	value, err := message.Value.Encode()
+	assert.Nil(t, err)
+	entry := Entry{}
+	assert.Nil(t, json.Unmarshal(value, &entry))
+	assert.Equal(t, ActionCreated, entry.Action)
+	assert.Equal(t, "4", entry.Details["partitions"])
+	assert.False(t, entry.Time.IsZero())
+}
+
+// Test The Log Drops Entries When Its Buffer Is Full
+func TestLogBufferFull(t *testing.T) {
+	log := NewLog(logtesting.TestLogger(t).Desugar(), config.EKAuditConfig{Enabled: true, Topic: "TestTopic", BufferSize: 1}, "TestPodName", nil)
+	assert.Equal(t, "TestTopic", log.topic)
+	log.Record(Entry{Kind: KindSecret, Action: ActionRotated, Name: "TestSecret1"})
+	log.Record(Entry{Kind: KindSecret, Action: ActionRotated, Name: "TestSecret2"})
+	assert.Len(t, log.entries, 1)
+	assert.Equal(t, "TestSecret1", (<-log.entries).Name)
+}
+
+// Test The Disabled (nil) Log Is A No-Op
+func TestLogDisabled(t *testing.T) {
+	log := NewLog(logtesting.TestLogger(t).Desugar(), config.EKAuditConfig{}, "TestPodName", nil)
+	assert.Nil(t, log)
+	log.Record(Entry{Kind: KindTopic, Action: ActionDeleted, Name: "TestTopic"})
+	log.Start(make(chan struct{}))
+}
+
+// Test The entryKey() Functionality
+func TestEntryKey(t *testing.T) {
+	assert.Equal(t, "Topic/TestTopic", entryKey(Entry{Kind: KindTopic, Name: "TestTopic"}))
+	assert.Equal(t, "Deployment/TestNamespace/TestName", entryKey(Entry{Kind: KindDeployment, Namespace: "TestNamespace", Name: "TestName"}))
+}
+
+// Mock Sarama SyncProducer Recording The Sent Messages (Or Failing With The Specified Error)
+type mockSyncProducer struct {
+	err      error
+	messages []*sarama.ProducerMessage
+	closed   bool
+	lock     sync.Mutex
+}
+
+func (p *mockSyncProducer) SendMessage(message *sarama.ProducerMessage) (int32, int64, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.err != nil {
+		return 0, 0, p.err
+	}
+	p.messages = append(p.messages, message)
+	return 0, int64(len(p.messages)), nil
+}
+
+func (p *mockSyncProducer) SendMessages(messages []*sarama.ProducerMessage) error {
+	for _, message := range messages {
+		if _, _, err := p.SendMessage(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *mockSyncProducer) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.closed = true
+	return nil
+}
+
+func (p *mockSyncProducer) getMessages() []*sarama.ProducerMessage {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.messages
+}
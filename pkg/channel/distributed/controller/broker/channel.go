@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// Get The Name Of The KafkaChannel Backing The Specified Broker
+func ChannelName(brokerName string) string {
+	return brokerName + constants.BrokerChannelSuffix
+}
+
+// Create The KafkaChannel Backing The Specified Broker, With A Subscriber & Filter Per Trigger
+func newChannel(broker *eventingv1.Broker, subscribers []eventingduck.SubscriberSpec, filters map[string]eventingv1.TriggerFilterAttributes, configuration *commonconfig.EventingKafkaConfig) (*kafkav1beta1.KafkaChannel, error) {
+	channel := &kafkav1beta1.KafkaChannel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kafkav1beta1.SchemeGroupVersion.String(),
+			Kind:       constants.KafkaChannelKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ChannelName(broker.Name),
+			Namespace:       broker.Namespace,
+			Labels:          map[string]string{constants.BrokerNameLabel: broker.Name},
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(broker)},
+		},
+		Spec: kafkav1beta1.KafkaChannelSpec{
+			NumPartitions:     configuration.Kafka.Topic.DefaultNumPartitions,
+			ReplicationFactor: configuration.Kafka.Topic.DefaultReplicationFactor,
+			ChannelableSpec: eventingduck.ChannelableSpec{
+				SubscribableSpec: eventingduck.SubscribableSpec{Subscribers: subscribers},
+			},
+		},
+	}
+	channel.Spec.SetDefaults(nil)
+	err := setSubscriberFilters(channel, filters)
+	return channel, err
+}
+
+// Set (Or Remove) The Subscriber Filters Annotation Of The Specified KafkaChannel
+func setSubscriberFilters(channel *kafkav1beta1.KafkaChannel, filters map[string]eventingv1.TriggerFilterAttributes) error {
+	if len(filters) == 0 {
+		delete(channel.Annotations, kafkaconstants.SubscriberFiltersAnnotation)
+		return nil
+	}
+	filtersJson, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	if channel.Annotations == nil {
+		channel.Annotations = make(map[string]string)
+	}
+	channel.Annotations[kafkaconstants.SubscriberFiltersAnnotation] = string(filtersJson)
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/triggerinformer"
+	kafkaclientsetinjection "knative.dev/eventing-kafka/pkg/client/injection/client"
+	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	"knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker"
+	brokerreconciler "knative.dev/eventing/pkg/client/injection/reconciler/eventing/v1/broker"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+)
+
+// Create A New Broker Controller (Reconciling Only The Brokers Of The RetentionBackedBroker Class)
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+
+	// Get A Logger
+	logger := logging.FromContext(ctx).Desugar()
+
+	// Get The Needed Informers
+	brokerInformer := broker.Get(ctx)
+	triggerInformer := triggerinformer.Get(ctx)
+	kafkachannelInformer := kafkachannel.Get(ctx)
+
+	// Load The Eventing-Kafka Settings (For The Backing KafkaChannels' Default Topic Configuration)
+	_, configuration, err := sarama.LoadSettings(ctx)
+	if err != nil {
+		logger.Fatal("Failed To Load Eventing-Kafka Settings", zap.Error(err))
+	}
+
+	// Create The Broker Reconciler
+	r := &Reconciler{
+		logger:             logger,
+		config:             configuration,
+		kafkaClientSet:     kafkaclientsetinjection.Get(ctx),
+		eventingClientSet:  eventingclient.Get(ctx),
+		kafkachannelLister: kafkachannelInformer.Lister(),
+		triggerLister:      triggerInformer.Lister(),
+	}
+
+	// Create A New Broker Controller Impl With The Reconciler
+	controllerImpl := brokerreconciler.NewImpl(ctx, r, constants.BrokerClass)
+
+	// Re-Reconcile Brokers Whose Trigger Subscribers Or DeadLetterSink Addresses Change
+	r.uriResolver = resolver.NewURIResolver(ctx, controllerImpl.EnqueueKey)
+
+	// Configure The Informers' EventHandlers
+	r.logger.Info("Setting Up EventHandlers")
+	brokerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: pkgreconciler.AnnotationFilterFunc(brokerreconciler.ClassAnnotationKey, constants.BrokerClass, false),
+		Handler:    controller.HandleAll(controllerImpl.Enqueue),
+	})
+	triggerInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+		if key, ok := triggerBrokerKey(obj); ok {
+			controllerImpl.EnqueueKey(key)
+		}
+	}))
+	kafkachannelInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGK(eventingv1.Kind("Broker")),
+		Handler:    controller.HandleAll(controllerImpl.EnqueueControllerOf),
+	})
+
+	// Return The Broker Controller Impl
+	return controllerImpl
+}
+
+// Utility Function For Getting The Key Of The Broker Of A Trigger (Or The Tombstone Of A Deleted Trigger)
+func triggerBrokerKey(obj interface{}) (types.NamespacedName, bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	trigger, ok := obj.(*eventingv1.Trigger)
+	if !ok || len(trigger.Spec.Broker) == 0 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Spec.Broker}, true
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	kafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned"
+	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	brokerreconciler "knative.dev/eventing/pkg/client/injection/reconciler/eventing/v1/broker"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+)
+
+//
+// Reconciler Implements controller.Reconciler For Brokers Of The RetentionBackedBroker Class
+//
+// Each such Broker is backed by a KafkaChannel owned by the Broker, whose receiver is the Broker's ingress and whose
+// topic retains the Broker's events.  Every Trigger of the Broker becomes a subscriber of that KafkaChannel (with the
+// Trigger's UID, resolved subscriber, the Broker as reply & the Broker's delivery), and the Trigger's filter attributes
+// are passed to the dispatcher in the subscriber-filters annotation so that events are filtered without another hop.
+//
+type Reconciler struct {
+	logger             *zap.Logger
+	config             *commonconfig.EventingKafkaConfig
+	kafkaClientSet     kafkaclientset.Interface
+	eventingClientSet  eventingclientset.Interface
+	kafkachannelLister kafkalisters.KafkaChannelLister
+	triggerLister      eventinglisters.TriggerLister
+	uriResolver        *resolver.URIResolver
+}
+
+var (
+	_ brokerreconciler.Interface = (*Reconciler)(nil) // Verify Reconciler Implements Interface
+)
+
+// ReconcileKind Implements The Reconciler Interface & Is Responsible For Reconciling The Backing KafkaChannel & Triggers
+func (r *Reconciler) ReconcileKind(ctx context.Context, broker *eventingv1.Broker) reconciler.Event {
+
+	// Reset The Broker's Status Conditions To Unknown
+	broker.Status.InitializeConditions()
+
+	// Get The Broker's Triggers (Sorted For A Stable Subscriber Ordering)
+	triggers, err := r.brokerTriggers(broker)
+	if err != nil {
+		r.logger.Error("Failed To List Broker's Triggers", zap.String("Broker", broker.Name), zap.Error(err))
+		return err
+	}
+
+	// Get Any Existing Backing KafkaChannel (The Broker's Address Is That Of The KafkaChannel)
+	channel, err := r.kafkachannelLister.KafkaChannels(broker.Namespace).Get(ChannelName(broker.Name))
+	if err != nil && !errors.IsNotFound(err) {
+		r.logger.Error("Failed To Get Broker's KafkaChannel", zap.String("Broker", broker.Name), zap.Error(err))
+		return err
+	}
+	var brokerURI *apis.URL
+	if channel != nil && channel.Status.Address != nil {
+		brokerURI = channel.Status.Address.URL
+	}
+
+	// Resolve The Broker's Delivery (The Dispatcher Requires A Resolved DeadLetterSink URI)
+	delivery, err := r.resolveDelivery(ctx, broker)
+	if err != nil {
+		broker.Status.MarkTriggerChannelFailed("DeadLetterSinkResolveFailed", "Failed To Resolve DeadLetterSink: %v", err)
+		return reconciler.NewEvent(corev1.EventTypeWarning, event.BrokerReconciliationFailed.String(), "Failed To Resolve Broker DeadLetterSink: %v", err)
+	}
+
+	// Build A Subscriber (And Any Filter) For Each Trigger Whose Subscriber Can Be Resolved
+	subscribers := make([]eventingduck.SubscriberSpec, 0, len(triggers))
+	filters := make(map[string]eventingv1.TriggerFilterAttributes)
+	resolvedTriggers := make(map[string]bool, len(triggers))
+	for _, trigger := range triggers {
+		subscriberURI, resolveErr := r.uriResolver.URIFromDestinationV1(ctx, trigger.Spec.Subscriber, trigger)
+		if resolveErr != nil {
+			r.logger.Warn("Failed To Resolve Trigger Subscriber", zap.String("Trigger", trigger.Name), zap.Error(resolveErr))
+			continue
+		}
+		resolvedTriggers[trigger.Name] = true
+		subscribers = append(subscribers, eventingduck.SubscriberSpec{
+			UID:           trigger.UID,
+			Generation:    trigger.Generation,
+			SubscriberURI: subscriberURI,
+			ReplyURI:      brokerURI,
+			Delivery:      delivery,
+		})
+		if trigger.Spec.Filter != nil && len(trigger.Spec.Filter.Attributes) > 0 {
+			filters[string(trigger.UID)] = trigger.Spec.Filter.Attributes
+		}
+	}
+
+	// Create Or Update The Backing KafkaChannel
+	channel, err = r.reconcileChannel(ctx, broker, channel, subscribers, filters)
+	if err != nil {
+		broker.Status.MarkTriggerChannelFailed("ChannelReconcileFailed", "Failed To Reconcile KafkaChannel: %v", err)
+		return reconciler.NewEvent(corev1.EventTypeWarning, event.BrokerReconciliationFailed.String(), "Failed To Reconcile Broker KafkaChannel: %v", err)
+	}
+
+	// Propagate The KafkaChannel's Readiness & Address To The Broker (The Dispatcher Filters, So There Is No Filter Hop)
+	broker.Status.PropagateTriggerChannelReadiness(&channel.Status.ChannelableStatus)
+	if channel.Status.IsReady() {
+		broker.Status.GetConditionSet().Manage(&broker.Status).MarkTrue(eventingv1.BrokerConditionIngress)
+		broker.Status.GetConditionSet().Manage(&broker.Status).MarkTrue(eventingv1.BrokerConditionFilter)
+	} else {
+		broker.Status.MarkIngressFailed("ChannelNotReady", "KafkaChannel %s Is Not Ready", channel.Name)
+		broker.Status.MarkFilterFailed("ChannelNotReady", "KafkaChannel %s Is Not Ready", channel.Name)
+	}
+	if channel.Status.Address != nil {
+		broker.Status.SetAddress(channel.Status.Address.URL)
+	} else {
+		broker.Status.SetAddress(nil)
+	}
+
+	// Update The Status Of The Broker's Triggers
+	for _, trigger := range triggers {
+		if err = r.reconcileTriggerStatus(ctx, broker, channel, trigger, resolvedTriggers[trigger.Name]); err != nil {
+			r.logger.Error("Failed To Update Trigger Status", zap.String("Trigger", trigger.Name), zap.Error(err))
+			return err
+		}
+	}
+
+	// Return Success
+	broker.Status.ObservedGeneration = broker.Generation
+	return reconciler.NewEvent(corev1.EventTypeNormal, event.BrokerReconciled.String(), "Broker Reconciled Successfully: \"%s/%s\"", broker.Namespace, broker.Name)
+}
+
+// Get The Triggers Of The Specified Broker, Sorted By Name
+func (r *Reconciler) brokerTriggers(broker *eventingv1.Broker) ([]*eventingv1.Trigger, error) {
+	namespaceTriggers, err := r.triggerLister.Triggers(broker.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	triggers := make([]*eventingv1.Trigger, 0, len(namespaceTriggers))
+	for _, trigger := range namespaceTriggers {
+		if trigger.Spec.Broker == broker.Name && trigger.DeletionTimestamp == nil {
+			triggers = append(triggers, trigger)
+		}
+	}
+	sort.Slice(triggers, func(i, j int) bool { return triggers[i].Name < triggers[j].Name })
+	return triggers, nil
+}
+
+// Resolve The Broker's Delivery Into One Whose DeadLetterSink (If Any) Is A URI
+func (r *Reconciler) resolveDelivery(ctx context.Context, broker *eventingv1.Broker) (*eventingduck.DeliverySpec, error) {
+	if broker.Spec.Delivery == nil {
+		return nil, nil
+	}
+	delivery := broker.Spec.Delivery.DeepCopy()
+	if delivery.DeadLetterSink != nil {
+		deadLetterURI, err := r.uriResolver.URIFromDestinationV1(ctx, *delivery.DeadLetterSink, broker)
+		if err != nil {
+			return nil, err
+		}
+		delivery.DeadLetterSink = &duckv1.Destination{URI: deadLetterURI}
+	}
+	return delivery, nil
+}
+
+// Create The Backing KafkaChannel Or Update The Subscribers & Filters Of The Existing One
+func (r *Reconciler) reconcileChannel(ctx context.Context, broker *eventingv1.Broker, existing *kafkav1beta1.KafkaChannel, subscribers []eventingduck.SubscriberSpec, filters map[string]eventingv1.TriggerFilterAttributes) (*kafkav1beta1.KafkaChannel, error) {
+
+	// Create The KafkaChannel If It Doesn't Exist
+	if existing == nil {
+		channel, err := newChannel(broker, subscribers, filters, r.config)
+		if err != nil {
+			return nil, err
+		}
+		r.logger.Info("Creating Broker KafkaChannel", zap.String("Broker", broker.Name), zap.String("KafkaChannel", channel.Name))
+		return r.kafkaClientSet.MessagingV1beta1().KafkaChannels(channel.Namespace).Create(ctx, channel, metav1.CreateOptions{})
+	}
+
+	// Refuse To Adopt A KafkaChannel Not Owned By The Broker
+	if !metav1.IsControlledBy(existing, broker) {
+		return nil, fmt.Errorf("KafkaChannel %s/%s is not owned by Broker %s", existing.Namespace, existing.Name, broker.Name)
+	}
+
+	// Update Only The Subscribers & Filters Of The Existing KafkaChannel (If Changed)
+	channel := existing.DeepCopy()
+	channel.Spec.Subscribers = subscribers
+	if err := setSubscriberFilters(channel, filters); err != nil {
+		return nil, err
+	}
+	if equality.Semantic.DeepEqual(existing.Spec, channel.Spec) && equality.Semantic.DeepEqual(existing.Annotations, channel.Annotations) {
+		return existing, nil
+	}
+	r.logger.Info("Updating Broker KafkaChannel", zap.String("Broker", broker.Name), zap.String("KafkaChannel", channel.Name))
+	return r.kafkaClientSet.MessagingV1beta1().KafkaChannels(channel.Namespace).Update(ctx, channel, metav1.UpdateOptions{})
+}
+
+// Update The Status Of A Trigger From Its Broker & The Readiness Of Its Subscriber In The Backing KafkaChannel
+func (r *Reconciler) reconcileTriggerStatus(ctx context.Context, broker *eventingv1.Broker, channel *kafkav1beta1.KafkaChannel, trigger *eventingv1.Trigger, resolved bool) error {
+
+	// Determine The Trigger's New Status
+	updated := trigger.DeepCopy()
+	updated.Status.InitializeConditions()
+	updated.Status.PropagateBrokerCondition(broker.Status.GetTopLevelCondition())
+	updated.Status.MarkDependencySucceeded()
+	if resolved {
+		updated.Status.MarkSubscriberResolvedSucceeded()
+		for _, subscriber := range channel.Spec.Subscribers {
+			if subscriber.UID == trigger.UID {
+				updated.Status.SubscriberURI = subscriber.SubscriberURI
+			}
+		}
+		updated.Status.PropagateSubscriptionCondition(subscriberCondition(channel, trigger))
+	} else {
+		updated.Status.SubscriberURI = nil
+		updated.Status.MarkSubscriberResolvedFailed("Unable to get the Subscriber's URI", "Failed To Resolve Subscriber Of Trigger %s", trigger.Name)
+		updated.Status.MarkSubscriptionNotConfigured()
+	}
+	updated.Status.ObservedGeneration = trigger.Generation
+
+	// Only Update The Trigger If Its Status Changed (Ignoring Condition Transition Times)
+	if triggerStatusEqual(trigger.Status, updated.Status) {
+		return nil
+	}
+	_, err := r.eventingClientSet.EventingV1().Triggers(trigger.Namespace).UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// Get The Subscription Condition Of A Trigger From The Status Of Its Subscriber In The Backing KafkaChannel
+func subscriberCondition(channel *kafkav1beta1.KafkaChannel, trigger *eventingv1.Trigger) *apis.Condition {
+	for _, subscriberStatus := range channel.Status.Subscribers {
+		if subscriberStatus.UID == trigger.UID {
+			return &apis.Condition{
+				Type:    apis.ConditionReady,
+				Status:  subscriberStatus.Ready,
+				Reason:  "SubscriberNotReady",
+				Message: subscriberStatus.Message,
+			}
+		}
+	}
+	return &apis.Condition{
+		Type:    apis.ConditionReady,
+		Status:  corev1.ConditionUnknown,
+		Reason:  "SubscriberNotReady",
+		Message: "KafkaChannel Has Not Reported The Subscriber's Status",
+	}
+}
+
+// Utility Function For Comparing Trigger Statuses Without Their Condition Transition Times
+func triggerStatusEqual(a, b eventingv1.TriggerStatus) bool {
+	a = *a.DeepCopy()
+	b = *b.DeepCopy()
+	for i := range a.Conditions {
+		a.Conditions[i].LastTransitionTime = apis.VolatileTime{}
+	}
+	for i := range b.Conditions {
+		b.Conditions[i].LastTransitionTime = apis.VolatileTime{}
+	}
+	return equality.Semantic.DeepEqual(a, b)
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	fakekafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned/fake"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	fakeeventingclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+)
+
+// Test Data
+const (
+	testNamespace  = "test-namespace"
+	testBrokerName = "test-broker"
+)
+
+var (
+	testBrokerURI     = apis.HTTP("test-broker-kn-broker-kn-channel.test-namespace.svc.cluster.local")
+	testSubscriberURI = apis.HTTP("test-subscriber.test-namespace.svc.cluster.local")
+	testDeadLetterURI = apis.HTTP("test-dls.test-namespace.svc.cluster.local")
+)
+
+// Test The ReconcileKind() Functionality
+func TestReconcileKind(t *testing.T) {
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name               string
+		channel            *kafkav1beta1.KafkaChannel
+		triggers           []*eventingv1.Trigger
+		expectEventReason  string
+		expectSubscribers  []eventingduck.SubscriberSpec
+		expectFilters      string
+		expectReady        bool
+		expectTriggerReady map[string]corev1.ConditionStatus
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name:              "Create Channel Without Triggers",
+			expectEventReason: event.BrokerReconciled.String(),
+			expectSubscribers: []eventingduck.SubscriberSpec{},
+		},
+		{
+			name:              "Create Channel With Triggers",
+			triggers:          []*eventingv1.Trigger{newTestTrigger("b-trigger", map[string]string{"type": "b"}), newTestTrigger("a-trigger", nil), newTestTrigger("other-broker-trigger", nil)},
+			expectEventReason: event.BrokerReconciled.String(),
+			expectSubscribers: []eventingduck.SubscriberSpec{newTestSubscriber("a-trigger", nil), newTestSubscriber("b-trigger", nil)},
+			expectFilters:     `{"b-trigger-uid":{"type":"b"}}`,
+		},
+		{
+			name:               "Update Ready Channel",
+			channel:            newTestChannel(true),
+			triggers:           []*eventingv1.Trigger{newTestTrigger("a-trigger", map[string]string{"source": "a"})},
+			expectEventReason:  event.BrokerReconciled.String(),
+			expectSubscribers:  []eventingduck.SubscriberSpec{newTestSubscriber("a-trigger", testBrokerURI)},
+			expectFilters:      `{"a-trigger-uid":{"source":"a"}}`,
+			expectReady:        true,
+			expectTriggerReady: map[string]corev1.ConditionStatus{"a-trigger": corev1.ConditionTrue},
+		},
+		{
+			name:               "Unresolvable Trigger Subscriber",
+			channel:            newTestChannel(true),
+			triggers:           []*eventingv1.Trigger{newTestTrigger("a-trigger", nil), newTestTrigger("unresolvable-trigger", nil)},
+			expectEventReason:  event.BrokerReconciled.String(),
+			expectSubscribers:  []eventingduck.SubscriberSpec{newTestSubscriber("a-trigger", testBrokerURI)},
+			expectReady:        true,
+			expectTriggerReady: map[string]corev1.ConditionStatus{"a-trigger": corev1.ConditionTrue, "unresolvable-trigger": corev1.ConditionFalse},
+		},
+		{
+			name: "Channel Not Owned By Broker",
+			channel: func() *kafkav1beta1.KafkaChannel {
+				channel := newTestChannel(true)
+				channel.OwnerReferences = nil
+				return channel
+			}(),
+			expectEventReason: event.BrokerReconciliationFailed.String(),
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Reconciler
+			var objects []runtime.Object
+			var kafkaObjects []runtime.Object
+			var eventingObjects []runtime.Object
+			if testCase.channel != nil {
+				objects = append(objects, testCase.channel)
+				kafkaObjects = append(kafkaObjects, testCase.channel)
+			}
+			for _, trigger := range testCase.triggers {
+				if trigger.Name == "unresolvable-trigger" {
+					trigger.Spec.Subscriber = duckv1.Destination{}
+				}
+				objects = append(objects, trigger)
+				eventingObjects = append(eventingObjects, trigger)
+			}
+			listers := controllertesting.NewListers(objects)
+			kafkaClientSet := fakekafkaclientset.NewSimpleClientset(kafkaObjects...)
+			eventingClientSet := fakeeventingclientset.NewSimpleClientset(eventingObjects...)
+			r := &Reconciler{
+				logger:             logtesting.TestLogger(t).Desugar(),
+				config:             controllertesting.NewConfig(),
+				kafkaClientSet:     kafkaClientSet,
+				eventingClientSet:  eventingClientSet,
+				kafkachannelLister: listers.GetKafkaChannelLister(),
+				triggerLister:      listers.GetTriggerLister(),
+				uriResolver:        &resolver.URIResolver{},
+			}
+
+			// Perform The Test
+			broker := newTestBroker()
+			reconcileEvent := r.ReconcileKind(context.TODO(), broker)
+
+			// Verify The Reconciler Event
+			var reconcilerEvent *reconciler.ReconcilerEvent
+			assert.True(t, reconciler.EventAs(reconcileEvent, &reconcilerEvent))
+			assert.Equal(t, testCase.expectEventReason, reconcilerEvent.Reason)
+			if testCase.expectEventReason == event.BrokerReconciliationFailed.String() {
+				assert.False(t, broker.Status.IsReady())
+				return
+			}
+
+			// Verify The Backing KafkaChannel
+			channel, err := kafkaClientSet.MessagingV1beta1().KafkaChannels(testNamespace).Get(context.TODO(), ChannelName(testBrokerName), metav1.GetOptions{})
+			assert.Nil(t, err)
+			assert.True(t, metav1.IsControlledBy(channel, broker))
+			assert.Equal(t, testCase.expectSubscribers, channel.Spec.Subscribers)
+			assert.Equal(t, testCase.expectFilters, channel.Annotations[kafkaconstants.SubscriberFiltersAnnotation])
+			if testCase.channel == nil {
+				assert.Equal(t, testBrokerName, channel.Labels[constants.BrokerNameLabel])
+				assert.Equal(t, controllertesting.NewConfig().Kafka.Topic.DefaultNumPartitions, channel.Spec.NumPartitions)
+			}
+
+			// Verify The Broker Status
+			assert.Equal(t, testCase.expectReady, broker.Status.IsReady())
+			if testCase.expectReady {
+				assert.Equal(t, testBrokerURI, broker.Status.Address.URL)
+			}
+
+			// Verify The Trigger Statuses
+			for triggerName, expectReady := range testCase.expectTriggerReady {
+				trigger, err := eventingClientSet.EventingV1().Triggers(testNamespace).Get(context.TODO(), triggerName, metav1.GetOptions{})
+				assert.Nil(t, err)
+				assert.Equal(t, expectReady, trigger.Status.GetTopLevelCondition().Status)
+			}
+		})
+	}
+}
+
+// Test The triggerBrokerKey() Functionality
+func TestTriggerBrokerKey(t *testing.T) {
+	trigger := newTestTrigger("a-trigger", nil)
+	expectedKey := types.NamespacedName{Namespace: testNamespace, Name: testBrokerName}
+
+	key, ok := triggerBrokerKey(trigger)
+	assert.True(t, ok)
+	assert.Equal(t, expectedKey, key)
+
+	key, ok = triggerBrokerKey(cache.DeletedFinalStateUnknown{Obj: trigger})
+	assert.True(t, ok)
+	assert.Equal(t, expectedKey, key)
+
+	_, ok = triggerBrokerKey(newTestBroker())
+	assert.False(t, ok)
+}
+
+// Test The setSubscriberFilters() Functionality
+func TestSetSubscriberFilters(t *testing.T) {
+	channel := &kafkav1beta1.KafkaChannel{}
+	filters := map[string]eventingv1.TriggerFilterAttributes{"uid": {"type": "a"}}
+	assert.Nil(t, setSubscriberFilters(channel, filters))
+	var actualFilters map[string]eventingv1.TriggerFilterAttributes
+	assert.Nil(t, json.Unmarshal([]byte(channel.Annotations[kafkaconstants.SubscriberFiltersAnnotation]), &actualFilters))
+	assert.Equal(t, filters, actualFilters)
+	assert.Nil(t, setSubscriberFilters(channel, nil))
+	assert.NotContains(t, channel.Annotations, kafkaconstants.SubscriberFiltersAnnotation)
+}
+
+// Utility Function For Creating A Test Broker Of The RetentionBackedBroker Class
+func newTestBroker() *eventingv1.Broker {
+	return &eventingv1.Broker{
+		TypeMeta: metav1.TypeMeta{APIVersion: eventingv1.SchemeGroupVersion.String(), Kind: "Broker"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testBrokerName,
+			Namespace:   testNamespace,
+			UID:         "test-broker-uid",
+			Annotations: map[string]string{eventingv1.BrokerClassAnnotationKey: constants.BrokerClass},
+		},
+		Spec: eventingv1.BrokerSpec{
+			Delivery: &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: testDeadLetterURI}},
+		},
+	}
+}
+
+// Utility Function For Creating A Test Trigger (Of The Test Broker Unless Named "other-broker-trigger")
+func newTestTrigger(name string, attributes map[string]string) *eventingv1.Trigger {
+	trigger := &eventingv1.Trigger{
+		TypeMeta:   metav1.TypeMeta{APIVersion: eventingv1.SchemeGroupVersion.String(), Kind: "Trigger"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, UID: types.UID(name + "-uid"), Generation: 1},
+		Spec: eventingv1.TriggerSpec{
+			Broker:     testBrokerName,
+			Subscriber: duckv1.Destination{URI: testSubscriberURI},
+		},
+	}
+	if name == "other-broker-trigger" {
+		trigger.Spec.Broker = "other-broker"
+	}
+	if attributes != nil {
+		trigger.Spec.Filter = &eventingv1.TriggerFilter{Attributes: attributes}
+	}
+	return trigger
+}
+
+// Utility Function For Creating The Expected KafkaChannel Subscriber Of A Test Trigger
+func newTestSubscriber(triggerName string, replyURI *apis.URL) eventingduck.SubscriberSpec {
+	return eventingduck.SubscriberSpec{
+		UID:           types.UID(triggerName + "-uid"),
+		Generation:    1,
+		SubscriberURI: testSubscriberURI,
+		ReplyURI:      replyURI,
+		Delivery:      &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: testDeadLetterURI}},
+	}
+}
+
+// Utility Function For Creating A Test Broker's Backing KafkaChannel (Optionally Ready With Ready Subscribers)
+func newTestChannel(ready bool) *kafkav1beta1.KafkaChannel {
+	channel, _ := newChannel(newTestBroker(), nil, nil, controllertesting.NewConfig())
+	if ready {
+		channel.Status.InitializeConditions()
+		channel.Status.MarkConfigTrue()
+		channel.Status.MarkTopicTrue()
+		channel.Status.MarkChannelServiceTrue()
+		channel.Status.MarkServiceTrue()
+		channel.Status.MarkEndpointsTrue()
+		channel.Status.PropagateDispatcherStatus(&appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}})
+		channel.Status.SetAddress(testBrokerURI)
+		channel.Status.Subscribers = []eventingduck.SubscriberStatus{{UID: "a-trigger-uid", Ready: corev1.ConditionTrue}}
+	}
+	return channel
+}
@@ -21,7 +21,10 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
-	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/gctuning"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
 )
 
 // ConfigurationError is the type of error returned from VerifyConfiguration
@@ -36,14 +39,19 @@ func (err ControllerConfigurationError) Error() string {
 // via the external configmap or the internal variables.
 func VerifyConfiguration(configuration *config.EventingKafkaConfig) error {
 
-	// Verify & Lowercase The Kafka AdminType
-	lowercaseKafkaAdminType := strings.ToLower(configuration.Kafka.AdminType)
-	switch lowercaseKafkaAdminType {
-	case constants.KafkaAdminTypeValueKafka, constants.KafkaAdminTypeValueAzure, constants.KafkaAdminTypeValueCustom:
-		configuration.Kafka.AdminType = lowercaseKafkaAdminType
-	default:
+	// Verify & Lowercase The Kafka AdminType (The Name Of A Built-In Or Registered TopicProvisioner)
+	if !kafkaadmin.IsProvisionerRegistered(configuration.Kafka.AdminType) {
 		return ControllerConfigurationError("Invalid / Unknown Kafka Admin Type: " + configuration.Kafka.AdminType)
 	}
+	configuration.Kafka.AdminType = strings.ToLower(configuration.Kafka.AdminType)
+
+	// Verify The Go Runtime Tuning Of The Receiver & Dispatcher
+	if err := gctuning.Validate(configuration.Dispatcher.Runtime); err != nil {
+		return ControllerConfigurationError("Dispatcher.Runtime: " + err.Error())
+	}
+	if err := gctuning.Validate(configuration.Receiver.Runtime); err != nil {
+		return ControllerConfigurationError("Receiver.Runtime: " + err.Error())
+	}
 
 	// Verify mandatory configuration settings
 	switch {
@@ -73,6 +81,40 @@ func VerifyConfiguration(configuration *config.EventingKafkaConfig) error {
 		return ControllerConfigurationError("Receiver.MemoryRequest must be nonzero")
 	case configuration.Receiver.Replicas < 1:
 		return ControllerConfigurationError("Receiver.Replicas must be > 0")
+	case configuration.Kafka.AdminType == kafkaadmin.StrimziProvisionerName && len(configuration.Kafka.Strimzi.Cluster) == 0:
+		return ControllerConfigurationError("Kafka.Strimzi.Cluster must be specified for the strimzi AdminType")
+	case configuration.MetricsAggregator.PartitionAdvisor.Enabled && !configuration.MetricsAggregator.Enabled:
+		return ControllerConfigurationError("MetricsAggregator.PartitionAdvisor requires the MetricsAggregator to be enabled")
+	case configuration.MetricsAggregator.PartitionAdvisor.AutoExpand && configuration.MetricsAggregator.PartitionAdvisor.MaxPartitions < 1:
+		return ControllerConfigurationError("MetricsAggregator.PartitionAdvisor.MaxPartitions must be > 0 when AutoExpand is enabled")
+	case configuration.MetricsAggregator.ResizeAdvisor.Enabled && !configuration.MetricsAggregator.Enabled:
+		return ControllerConfigurationError("MetricsAggregator.ResizeAdvisor requires the MetricsAggregator to be enabled")
+	case configuration.MetricsAggregator.ResizeAdvisor.WindowMillis < 0:
+		return ControllerConfigurationError("MetricsAggregator.ResizeAdvisor.WindowMillis must be >= 0")
+	case configuration.MetricsAggregator.ResizeAdvisor.HeadroomPercent < 0:
+		return ControllerConfigurationError("MetricsAggregator.ResizeAdvisor.HeadroomPercent must be >= 0")
+	case configuration.MetricsAggregator.ResizeAdvisor.TolerancePercent < 0:
+		return ControllerConfigurationError("MetricsAggregator.ResizeAdvisor.TolerancePercent must be >= 0")
+	case !util.IsValidVPAUpdateMode(configuration.MetricsAggregator.ResizeAdvisor.VPAUpdateMode):
+		return ControllerConfigurationError("Invalid / Unknown VPA Update Mode: " + configuration.MetricsAggregator.ResizeAdvisor.VPAUpdateMode)
+	case !util.IsValidNameStrategy(configuration.Naming.Strategy):
+		return ControllerConfigurationError("Invalid / Unknown Naming Strategy: " + configuration.Naming.Strategy)
+	case !util.IsValidReceiverIsolation(configuration.Receiver.Isolation):
+		return ControllerConfigurationError("Invalid / Unknown Receiver Isolation: " + configuration.Receiver.Isolation)
+	case !kafkautil.IsValidReceiverAddressing(configuration.Receiver.Addressing):
+		return ControllerConfigurationError("Invalid / Unknown Receiver Addressing: " + configuration.Receiver.Addressing)
+	case !kafkautil.IsValidReceiverTimestamp(configuration.Receiver.Timestamp):
+		return ControllerConfigurationError("Invalid / Unknown Receiver Timestamp: " + configuration.Receiver.Timestamp)
+	case configuration.Janitor.IntervalMillis < 0:
+		return ControllerConfigurationError("Janitor.IntervalMillis must be >= 0")
+	case configuration.Dispatcher.ScaleToZero.IdleMillis < 0:
+		return ControllerConfigurationError("Dispatcher.ScaleToZero.IdleMillis must be >= 0")
+	case configuration.Dispatcher.ScaleToZero.CheckIntervalMillis < 0:
+		return ControllerConfigurationError("Dispatcher.ScaleToZero.CheckIntervalMillis must be >= 0")
+	case configuration.Audit.BufferSize < 0:
+		return ControllerConfigurationError("Audit.BufferSize must be >= 0")
+	case configuration.OffsetExport.IntervalMillis < 0:
+		return ControllerConfigurationError("OffsetExport.IntervalMillis must be >= 0")
 	}
 	return nil // no problems found
 }
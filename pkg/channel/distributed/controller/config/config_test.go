@@ -64,6 +64,20 @@ type TestCase struct {
 	channelMemoryLimit                 resource.Quantity
 	channelMemoryRequest               resource.Quantity
 	channelReplicas                    int
+	metricsAggregatorEnabled           bool
+	partitionAdvisor                   config.EKPartitionAdvisorConfig
+	resizeAdvisor                      config.EKResizeAdvisorConfig
+	strimzi                            config.EKStrimziConfig
+	namingStrategy                     string
+	janitorIntervalMillis              int64
+	scaleToZero                        config.EKScaleToZeroConfig
+	audit                              config.EKAuditConfig
+	offsetExport                       config.EKOffsetExportConfig
+	receiverIsolation                  string
+	receiverAddressing                 string
+	receiverTimestamp                  string
+	dispatcherRuntime                  config.EKRuntimeConfig
+	receiverRuntime                    config.EKRuntimeConfig
 
 	expectedError error
 }
@@ -164,6 +178,142 @@ func TestVerifyConfiguration(t *testing.T) {
 	testCase.expectedError = ControllerConfigurationError("Receiver.Replicas must be > 0")
 	testCases = append(testCases, testCase)
 
+	testCase = getValidTestCase("Valid Config - MetricsAggregator.PartitionAdvisor")
+	testCase.metricsAggregatorEnabled = true
+	testCase.partitionAdvisor = config.EKPartitionAdvisorConfig{Enabled: true, AutoExpand: true, MaxPartitions: 32}
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - MetricsAggregator.PartitionAdvisor Without MetricsAggregator")
+	testCase.partitionAdvisor = config.EKPartitionAdvisorConfig{Enabled: true}
+	testCase.expectedError = ControllerConfigurationError("MetricsAggregator.PartitionAdvisor requires the MetricsAggregator to be enabled")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - MetricsAggregator.PartitionAdvisor.MaxPartitions")
+	testCase.metricsAggregatorEnabled = true
+	testCase.partitionAdvisor = config.EKPartitionAdvisorConfig{Enabled: true, AutoExpand: true}
+	testCase.expectedError = ControllerConfigurationError("MetricsAggregator.PartitionAdvisor.MaxPartitions must be > 0 when AutoExpand is enabled")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - MetricsAggregator.ResizeAdvisor")
+	testCase.metricsAggregatorEnabled = true
+	testCase.resizeAdvisor = config.EKResizeAdvisorConfig{Enabled: true, HeadroomPercent: 25, CreateVPA: true, VPAUpdateMode: "Initial"}
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - MetricsAggregator.ResizeAdvisor Without MetricsAggregator")
+	testCase.resizeAdvisor = config.EKResizeAdvisorConfig{Enabled: true}
+	testCase.expectedError = ControllerConfigurationError("MetricsAggregator.ResizeAdvisor requires the MetricsAggregator to be enabled")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - MetricsAggregator.ResizeAdvisor.HeadroomPercent")
+	testCase.metricsAggregatorEnabled = true
+	testCase.resizeAdvisor = config.EKResizeAdvisorConfig{Enabled: true, HeadroomPercent: -1}
+	testCase.expectedError = ControllerConfigurationError("MetricsAggregator.ResizeAdvisor.HeadroomPercent must be >= 0")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - MetricsAggregator.ResizeAdvisor.VPAUpdateMode")
+	testCase.metricsAggregatorEnabled = true
+	testCase.resizeAdvisor = config.EKResizeAdvisorConfig{Enabled: true, CreateVPA: true, VPAUpdateMode: "Sometimes"}
+	testCase.expectedError = ControllerConfigurationError("Invalid / Unknown VPA Update Mode: Sometimes")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - Kafka.Strimzi")
+	testCase.kafkaAdminType = "strimzi"
+	testCase.strimzi = config.EKStrimziConfig{Cluster: "my-cluster"}
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Kafka.Strimzi.Cluster")
+	testCase.kafkaAdminType = "strimzi"
+	testCase.expectedError = ControllerConfigurationError("Kafka.Strimzi.Cluster must be specified for the strimzi AdminType")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - Naming.Strategy")
+	testCase.namingStrategy = "hash"
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Naming.Strategy")
+	testCase.namingStrategy = "invalidstrategy"
+	testCase.expectedError = ControllerConfigurationError("Invalid / Unknown Naming Strategy: invalidstrategy")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - Receiver.Isolation")
+	testCase.receiverIsolation = "channel"
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Receiver.Isolation")
+	testCase.receiverIsolation = "invalidisolation"
+	testCase.expectedError = ControllerConfigurationError("Invalid / Unknown Receiver Isolation: invalidisolation")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - Receiver.Addressing")
+	testCase.receiverAddressing = "path"
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Receiver.Addressing")
+	testCase.receiverAddressing = "invalidaddressing"
+	testCase.expectedError = ControllerConfigurationError("Invalid / Unknown Receiver Addressing: invalidaddressing")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - Receiver.Timestamp")
+	testCase.receiverTimestamp = "ceTime"
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Receiver.Timestamp")
+	testCase.receiverTimestamp = "invalidtimestamp"
+	testCase.expectedError = ControllerConfigurationError("Invalid / Unknown Receiver Timestamp: invalidtimestamp")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - Runtime")
+	testCase.dispatcherRuntime = config.EKRuntimeConfig{GOGC: "400", GOMemLimit: resource.NewQuantity(45*1024*1024, resource.BinarySI), Ballast: resource.NewQuantity(10*1024*1024, resource.BinarySI)}
+	testCase.receiverRuntime = config.EKRuntimeConfig{GOGC: "off", GOMemLimit: resource.NewQuantity(18*1024*1024, resource.BinarySI)}
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Dispatcher.Runtime")
+	testCase.dispatcherRuntime = config.EKRuntimeConfig{GOGC: "lots"}
+	testCase.expectedError = ControllerConfigurationError(`Dispatcher.Runtime: invalid GOGC "lots": expected "off" or a percentage >= 0`)
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Receiver.Runtime")
+	testCase.receiverRuntime = config.EKRuntimeConfig{Ballast: resource.NewQuantity(-1, resource.BinarySI)}
+	testCase.expectedError = ControllerConfigurationError("Receiver.Runtime: invalid Ballast -1: must be >= 0")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Janitor.IntervalMillis")
+	testCase.janitorIntervalMillis = -1
+	testCase.expectedError = ControllerConfigurationError("Janitor.IntervalMillis must be >= 0")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - ScaleToZero")
+	testCase.scaleToZero = config.EKScaleToZeroConfig{Enabled: true, IdleMillis: 60000, IncludeSubscribed: true}
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Dispatcher.ScaleToZero.IdleMillis")
+	testCase.scaleToZero = config.EKScaleToZeroConfig{Enabled: true, IdleMillis: -1}
+	testCase.expectedError = ControllerConfigurationError("Dispatcher.ScaleToZero.IdleMillis must be >= 0")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Dispatcher.ScaleToZero.CheckIntervalMillis")
+	testCase.scaleToZero = config.EKScaleToZeroConfig{Enabled: true, CheckIntervalMillis: -1}
+	testCase.expectedError = ControllerConfigurationError("Dispatcher.ScaleToZero.CheckIntervalMillis must be >= 0")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - Audit")
+	testCase.audit = config.EKAuditConfig{Enabled: true, Topic: "audit-topic", BufferSize: 10}
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Audit.BufferSize")
+	testCase.audit = config.EKAuditConfig{Enabled: true, BufferSize: -1}
+	testCase.expectedError = ControllerConfigurationError("Audit.BufferSize must be >= 0")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Valid Config - OffsetExport")
+	testCase.offsetExport = config.EKOffsetExportConfig{Enabled: true, IntervalMillis: 30000}
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - OffsetExport.IntervalMillis")
+	testCase.offsetExport = config.EKOffsetExportConfig{Enabled: true, IntervalMillis: -1}
+	testCase.expectedError = ControllerConfigurationError("OffsetExport.IntervalMillis must be >= 0")
+	testCases = append(testCases, testCase)
+
 	testCase = getValidTestCase("Invalid Config - Kafka.Provider")
 	testCase.kafkaAdminType = "invalidadmintype"
 	testCase.expectedError = ControllerConfigurationError("Invalid / Unknown Kafka Admin Type: invalidadmintype")
@@ -177,6 +327,7 @@ func TestVerifyConfiguration(t *testing.T) {
 		testConfig.Kafka.Topic.DefaultReplicationFactor = testCase.kafkaTopicDefaultReplicationFactor
 		testConfig.Kafka.Topic.DefaultRetentionMillis = testCase.kafkaTopicDefaultRetentionMillis
 		testConfig.Kafka.AdminType = testCase.kafkaAdminType
+		testConfig.Kafka.Strimzi = testCase.strimzi
 		testConfig.Dispatcher.CpuLimit = testCase.dispatcherCpuLimit
 		testConfig.Dispatcher.CpuRequest = testCase.dispatcherCpuRequest
 		testConfig.Dispatcher.MemoryLimit = testCase.dispatcherMemoryLimit
@@ -187,6 +338,19 @@ func TestVerifyConfiguration(t *testing.T) {
 		testConfig.Receiver.MemoryLimit = testCase.channelMemoryLimit
 		testConfig.Receiver.MemoryRequest = testCase.channelMemoryRequest
 		testConfig.Receiver.Replicas = testCase.channelReplicas
+		testConfig.MetricsAggregator.Enabled = testCase.metricsAggregatorEnabled
+		testConfig.MetricsAggregator.PartitionAdvisor = testCase.partitionAdvisor
+		testConfig.MetricsAggregator.ResizeAdvisor = testCase.resizeAdvisor
+		testConfig.Naming.Strategy = testCase.namingStrategy
+		testConfig.Janitor.IntervalMillis = testCase.janitorIntervalMillis
+		testConfig.Dispatcher.ScaleToZero = testCase.scaleToZero
+		testConfig.Audit = testCase.audit
+		testConfig.OffsetExport = testCase.offsetExport
+		testConfig.Receiver.Isolation = testCase.receiverIsolation
+		testConfig.Receiver.Addressing = testCase.receiverAddressing
+		testConfig.Receiver.Timestamp = testCase.receiverTimestamp
+		testConfig.Dispatcher.Runtime = testCase.dispatcherRuntime
+		testConfig.Receiver.Runtime = testCase.receiverRuntime
 
 		// Perform The Test
 		err := VerifyConfiguration(testConfig)
@@ -18,11 +18,6 @@ package constants
 
 const (
 
-	// Kafka Admin Type Types
-	KafkaAdminTypeValueKafka  = "kafka"
-	KafkaAdminTypeValueAzure  = "azure"
-	KafkaAdminTypeValueCustom = "custom"
-
 	// The Controller's Component Name (Needs To Be DNS Safe!)
 	ControllerComponentName = "eventing-kafka-channel-controller"
 
@@ -52,6 +47,13 @@ const (
 	KafkaSecretDataKeyUsername = "username"
 	KafkaSecretDataKeyPassword = "password"
 
+	// Schema Registry Secret Data Keys
+	SchemaRegistrySecretDataKeyUsername     = "username"
+	SchemaRegistrySecretDataKeyPassword     = "password"
+	SchemaRegistrySecretDataKeyToken        = "token"
+	SchemaRegistrySecretDataKeyClientId     = "clientId"
+	SchemaRegistrySecretDataKeyClientSecret = "clientSecret"
+
 	// Prometheus MetricsPort
 	MetricsPortName = "metrics"
 
@@ -65,6 +67,49 @@ const (
 	DispatcherContainerName = "kafkachannel-dispatcher"
 	ReceiverContainerName   = "kafkachannel-receiver"
 
+	// Seccomp Annotation (The Vendored Kubernetes API Predates The PodSecurityContext SeccompProfile Field, Which
+	// The API Server Populates From This Annotation On Pod Creation)
+	SeccompPodAnnotation  = "seccomp.security.alpha.kubernetes.io/pod"
+	DefaultSeccompProfile = "runtime/default"
+
+	// Dispatcher Deployment Annotation Recording The (Canary) Image Rolled Back After Failing To Roll Out
+	RolledBackImageAnnotation = "kafka.eventing.knative.dev/rolled-back-image"
+
+	// Dispatcher Deployment Annotations Recording The Activity & Previous Replicas Of A Dispatcher Scaled To Zero
+	IdleActivityAnnotation = "kafka.eventing.knative.dev/idle-activity"
+	IdleReplicasAnnotation = "kafka.eventing.knative.dev/idle-replicas"
+
+	// KafkaChannel Status Annotation Recording The (JSON) Configuration Applied By The Controller
+	EffectiveConfigAnnotation = "kafka.eventing.knative.dev/effective-config"
+
+	// KafkaChannel Status Annotation Recording The (JSON) Committed Offsets & Lag Of Each Subscriber's ConsumerGroup
+	SubscriberOffsetsAnnotation = "kafka.eventing.knative.dev/subscriber-offsets"
+
+	// KafkaChannel Status Annotation Recording (As JSON) Since When Each Subscriber's ConsumerGroup Has Been Empty
+	SubscriberEmptySinceAnnotation = "kafka.eventing.knative.dev/subscriber-empty-since"
+
+	// The Volume Of The Projected ServiceAccount Token Exchanged For Kafka Access Tokens (Workload Identity)
+	WorkloadIdentityVolumeName             = "workload-identity-token"
+	WorkloadIdentityTokenExpirationSeconds = 3600
+
+	// The Volume Of The Secret Of The Key Encryption Keys Wrapping The Data Keys Of Encrypted Records
+	EncryptionKeysVolumeName = "encryption-keys"
+
+	// The Volume Of The ConfigMap Of The Middleware Modules Loaded By The Receiver & Dispatchers
+	MiddlewareVolumeName = "middleware"
+
+	// The Class Of The Brokers Backed By A KafkaChannel (eventing.knative.dev/broker.class Annotation Value)
+	BrokerClass = "RetentionBackedBroker"
+
+	// The Suffix Appended To A Broker's Name To Name Its Backing KafkaChannel
+	BrokerChannelSuffix = "-kn-broker"
+
+	// The Label Of A Broker's Backing KafkaChannel Indicating The Broker's Name
+	BrokerNameLabel = "eventing.knative.dev/broker"
+
+	// The Maximum Number Of KafkaChannels Whose Status Is Updated Concurrently When Reconciling A Kafka Secret
+	KafkaChannelStatusWorkers = 16
+
 	// Labels
 	AppLabel                    = "app"
 	KafkaChannelNameLabel       = "kafkachannel-name"
@@ -74,6 +119,9 @@ const (
 	KafkaSecretLabel            = "kafkasecret"             // Secret Label - Indicates The Kafka Secret Of The KafkaChannel
 	KafkaTopicLabel             = "kafkaTopic"              // Topic Label - Indicates The Kafka Topic Of The KnativeChannel
 
+	// Isolated Receiver Label - Used To Mark Deployment As The Dedicated Receiver Of A Single KafkaChannel
+	KafkaChannelIsolatedReceiverLabel = "kafkachannel-isolated-receiver"
+
 	// Prometheus ServiceMonitor Selector Labels / Values
 	K8sAppChannelSelectorLabel    = "k8s-app"
 	K8sAppChannelSelectorValue    = "eventing-kafka-channels"
@@ -81,7 +129,11 @@ const (
 	K8sAppDispatcherSelectorValue = "eventing-kafka-dispatchers"
 
 	// Kafka Topic Configuration
-	KafkaTopicConfigRetentionMs = "retention.ms"
+	KafkaTopicConfigRetentionMs   = "retention.ms"
+	KafkaTopicConfigCleanupPolicy = "cleanup.policy"
+
+	// The ConfigMap (In The System Namespace) Recording The Topics Created By The Controller For The Janitor
+	ManagedTopicsConfigMapName = "eventing-kafka-managed-topics"
 
 	// Health Configuration
 	HealthPort                = 8082
@@ -93,4 +145,9 @@ const (
 	DispatcherLivenessPeriod  = 5
 	DispatcherReadinessDelay  = 10
 	DispatcherReadinessPeriod = 5
+
+	// Combined Receiver Configuration (The Alternate Ports Of A Receiver Sharing The Pod Of A Dispatcher)
+	CombinedReceiverHealthPort      = 8083
+	CombinedReceiverMetricsPort     = 8084
+	CombinedReceiverMetricsPortName = "receiver-metrics"
 )
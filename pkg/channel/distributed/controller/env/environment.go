@@ -38,6 +38,7 @@ type Environment struct {
 	ServiceAccount string // Required
 	MetricsPort    int    // Required
 	MetricsDomain  string // Required
+	PodName        string // Optional
 
 	// Dispatcher Configuration
 	DispatcherImage string // Required
@@ -73,6 +74,9 @@ func GetEnvironment(logger *zap.Logger) (*Environment, error) {
 		return nil, err
 	}
 
+	// Get The Optional PodName Config Value (Available To The Kafka ClientIdTemplate)
+	environment.PodName = env.GetOptionalConfigValue(logger, env.PodNameEnvVarKey, "")
+
 	//
 	// Dispatcher Configuration
 	//
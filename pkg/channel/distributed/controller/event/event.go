@@ -42,14 +42,44 @@ const (
 
 	// Kafka Topic Reconciliation
 	KafkaTopicReconciliationFailed
+	KafkaTopicPartitionsInsufficient
+	KafkaTopicPartitionsExpanded
+	KafkaTopicPartitionsExpansionFailed
 
 	// Dispatcher (Kafka Consumer) Reconciliation
 	DispatcherServiceReconciliationFailed
 	DispatcherDeploymentReconciliationFailed
+	DispatcherMigrationFailed
+
+	// Dispatcher & Receiver Resource Requests
+	DataPlaneResizeRecommended
+	VerticalPodAutoscalerReconciliationFailed
+	DispatcherScaledToZero
+	DispatcherActivated
+	DispatcherScaleFailed
 
 	// Kafka Secret Reconciliation
 	KafkaSecretReconciled
 	KafkaSecretFinalized
+
+	// Namespace ConfigMap Overrides
+	NamespaceConfigConflict
+	NamespaceConfigInvalid
+
+	// KafkaChannel Class Settings
+	ChannelClassConflict
+	ChannelClassInvalid
+
+	// EventRedelivery Reconciliation
+	EventRedeliveryCompleted
+	EventRedeliveryFailed
+
+	// RetentionBackedBroker Reconciliation
+	BrokerReconciled
+	BrokerReconciliationFailed
+
+	// Subscriber ConsumerGroup Offsets Retention
+	SubscriberOffsetsExpiring
 )
 
 // CoreV1 EventType String Value
@@ -80,14 +110,50 @@ func (et CoreV1EventType) String() string {
 		eventTypeString = "ChannelStatusReconciliationFailed"
 	case KafkaTopicReconciliationFailed:
 		eventTypeString = "KafkaTopicReconciliationFailed"
+	case KafkaTopicPartitionsInsufficient:
+		eventTypeString = "KafkaTopicPartitionsInsufficient"
+	case KafkaTopicPartitionsExpanded:
+		eventTypeString = "KafkaTopicPartitionsExpanded"
+	case KafkaTopicPartitionsExpansionFailed:
+		eventTypeString = "KafkaTopicPartitionsExpansionFailed"
 	case DispatcherServiceReconciliationFailed:
 		eventTypeString = "DispatcherServiceReconciliationFailed"
 	case DispatcherDeploymentReconciliationFailed:
 		eventTypeString = "DispatcherDeploymentReconciliationFailed"
+	case DispatcherMigrationFailed:
+		eventTypeString = "DispatcherMigrationFailed"
+	case DataPlaneResizeRecommended:
+		eventTypeString = "DataPlaneResizeRecommended"
+	case VerticalPodAutoscalerReconciliationFailed:
+		eventTypeString = "VerticalPodAutoscalerReconciliationFailed"
+	case DispatcherScaledToZero:
+		eventTypeString = "DispatcherScaledToZero"
+	case DispatcherActivated:
+		eventTypeString = "DispatcherActivated"
+	case DispatcherScaleFailed:
+		eventTypeString = "DispatcherScaleFailed"
 	case KafkaSecretReconciled:
 		eventTypeString = "KafkaSecretReconciled"
 	case KafkaSecretFinalized:
 		eventTypeString = "KafkaSecretFinalized"
+	case NamespaceConfigConflict:
+		eventTypeString = "NamespaceConfigConflict"
+	case NamespaceConfigInvalid:
+		eventTypeString = "NamespaceConfigInvalid"
+	case ChannelClassConflict:
+		eventTypeString = "ChannelClassConflict"
+	case ChannelClassInvalid:
+		eventTypeString = "ChannelClassInvalid"
+	case EventRedeliveryCompleted:
+		eventTypeString = "EventRedeliveryCompleted"
+	case EventRedeliveryFailed:
+		eventTypeString = "EventRedeliveryFailed"
+	case BrokerReconciled:
+		eventTypeString = "BrokerReconciled"
+	case BrokerReconciliationFailed:
+		eventTypeString = "BrokerReconciliationFailed"
+	case SubscriberOffsetsExpiring:
+		eventTypeString = "SubscriberOffsetsExpiring"
 	}
 
 	// Return The EventType String Value
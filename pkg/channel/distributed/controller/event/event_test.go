@@ -32,10 +32,28 @@ func TestEventTypes(t *testing.T) {
 	performEventTypeStringTest(t, ReceiverServiceReconciliationFailed, "ReceiverServiceReconciliationFailed")
 	performEventTypeStringTest(t, ReceiverDeploymentReconciliationFailed, "ReceiverDeploymentReconciliationFailed")
 	performEventTypeStringTest(t, KafkaTopicReconciliationFailed, "KafkaTopicReconciliationFailed")
+	performEventTypeStringTest(t, KafkaTopicPartitionsInsufficient, "KafkaTopicPartitionsInsufficient")
+	performEventTypeStringTest(t, KafkaTopicPartitionsExpanded, "KafkaTopicPartitionsExpanded")
+	performEventTypeStringTest(t, KafkaTopicPartitionsExpansionFailed, "KafkaTopicPartitionsExpansionFailed")
 	performEventTypeStringTest(t, DispatcherServiceReconciliationFailed, "DispatcherServiceReconciliationFailed")
 	performEventTypeStringTest(t, DispatcherDeploymentReconciliationFailed, "DispatcherDeploymentReconciliationFailed")
+	performEventTypeStringTest(t, DispatcherMigrationFailed, "DispatcherMigrationFailed")
+	performEventTypeStringTest(t, DataPlaneResizeRecommended, "DataPlaneResizeRecommended")
+	performEventTypeStringTest(t, VerticalPodAutoscalerReconciliationFailed, "VerticalPodAutoscalerReconciliationFailed")
+	performEventTypeStringTest(t, DispatcherScaledToZero, "DispatcherScaledToZero")
+	performEventTypeStringTest(t, DispatcherActivated, "DispatcherActivated")
+	performEventTypeStringTest(t, DispatcherScaleFailed, "DispatcherScaleFailed")
 	performEventTypeStringTest(t, KafkaSecretReconciled, "KafkaSecretReconciled")
 	performEventTypeStringTest(t, KafkaSecretFinalized, "KafkaSecretFinalized")
+	performEventTypeStringTest(t, NamespaceConfigConflict, "NamespaceConfigConflict")
+	performEventTypeStringTest(t, NamespaceConfigInvalid, "NamespaceConfigInvalid")
+	performEventTypeStringTest(t, ChannelClassConflict, "ChannelClassConflict")
+	performEventTypeStringTest(t, ChannelClassInvalid, "ChannelClassInvalid")
+	performEventTypeStringTest(t, EventRedeliveryCompleted, "EventRedeliveryCompleted")
+	performEventTypeStringTest(t, EventRedeliveryFailed, "EventRedeliveryFailed")
+	performEventTypeStringTest(t, BrokerReconciled, "BrokerReconciled")
+	performEventTypeStringTest(t, BrokerReconciliationFailed, "BrokerReconciliationFailed")
+	performEventTypeStringTest(t, SubscriberOffsetsExpiring, "SubscriberOffsetsExpiring")
 }
 
 // Perform A Single Instance Of The CoreV1 EventType String Test
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventredelivery
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/identity"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
+	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/eventredelivery"
+	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
+	eventredeliveryreconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/eventredelivery"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+// Create A New EventRedelivery Controller
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+
+	// Get A Logger
+	logger := logging.FromContext(ctx).Desugar()
+
+	// Get The Needed Informers
+	eventRedeliveryInformer := eventredelivery.Get(ctx)
+	kafkachannelInformer := kafkachannel.Get(ctx)
+
+	// Load The Environment Variables
+	environment, err := env.GetEnvironment(logger)
+	if err != nil {
+		logger.Fatal("Failed To Load Environment Variables - Terminating!", zap.Error(err))
+	}
+
+	// Load the Sarama and other eventing-kafka settings from our configmap
+	saramaConfig, configuration, err := sarama.LoadSettings(ctx)
+	if err != nil {
+		logger.Fatal("Failed To Load Eventing-Kafka Settings", zap.Error(err))
+	}
+
+	// Authenticate The Kafka Clients Via The Workload Identity If Enabled
+	err = identity.UpdateSaramaConfig(saramaConfig, configuration.Kafka.WorkloadIdentity, logger)
+	if err != nil {
+		logger.Fatal("Failed To Configure Workload Identity", zap.Error(err))
+	}
+
+	// Connect To The Kafka Brokers Through The Egress Proxy If Configured
+	err = sarama.UpdateSaramaProxy(ctx, kubeclient.Get(ctx), system.Namespace(), saramaConfig, configuration.Kafka)
+	if err != nil {
+		logger.Fatal("Failed To Configure Kafka Egress Proxy", zap.Error(err))
+	}
+
+	// Dial The Kafka Brokers At Their Rewritten Addresses (e.g. Split-Horizon DNS) If Configured
+	err = sarama.UpdateSaramaBrokerAddressRewrites(saramaConfig, configuration.Kafka)
+	if err != nil {
+		logger.Fatal("Invalid Kafka Broker Address Rewrites", zap.Error(err))
+	}
+
+	// Create The EventRedelivery Reconciler
+	r := &Reconciler{
+		logger:             logger,
+		kubeClientset:      kubeclient.Get(ctx),
+		environment:        environment,
+		config:             configuration,
+		saramaConfig:       saramaConfig,
+		kafkachannelLister: kafkachannelInformer.Lister(),
+	}
+
+	// Create A New EventRedelivery Controller Impl With The Reconciler
+	controllerImpl := eventredeliveryreconciler.NewImpl(ctx, r)
+
+	// Configure The Informers' EventHandlers
+	r.logger.Info("Setting Up EventHandlers")
+	eventRedeliveryInformer.Informer().AddEventHandler(
+		controller.HandleAll(controllerImpl.Enqueue),
+	)
+
+	// Return The EventRedelivery Controller Impl
+	return controllerImpl
+}
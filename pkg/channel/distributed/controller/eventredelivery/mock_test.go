@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventredelivery
+
+import (
+	"github.com/Shopify/sarama"
+)
+
+//
+// Mock RedeliveryClient
+//
+
+// Verify The Mock RedeliveryClient Implements The Interface
+var _ RedeliveryClient = &MockRedeliveryClient{}
+
+// Mock RedeliveryClient Of A Single Quarantine Topic Whose Partitions Contain The Specified Messages
+type MockRedeliveryClient struct {
+	partitions map[int32][]*sarama.ConsumerMessage
+	produceErr error
+	produced   []*sarama.ProducerMessage
+	closed     bool
+}
+
+// Create A New Mock RedeliveryClient (The Oldest Offset Of Each Partition Is That Of Its First Message)
+func NewMockRedeliveryClient(partitions map[int32][]*sarama.ConsumerMessage, produceErr error) *MockRedeliveryClient {
+	return &MockRedeliveryClient{partitions: partitions, produceErr: produceErr}
+}
+
+func (c *MockRedeliveryClient) Partitions(_ string) ([]int32, error) {
+	partitions := make([]int32, 0, len(c.partitions))
+	for partition := range c.partitions {
+		partitions = append(partitions, partition)
+	}
+	return partitions, nil
+}
+
+func (c *MockRedeliveryClient) GetOffset(_ string, partition int32, time int64) (int64, error) {
+	messages := c.partitions[partition]
+	if len(messages) == 0 {
+		return 0, nil
+	}
+	if time == sarama.OffsetOldest {
+		return messages[0].Offset, nil
+	}
+	return messages[len(messages)-1].Offset + 1, nil
+}
+
+func (c *MockRedeliveryClient) ConsumePartition(_ string, partition int32, offset int64) (sarama.PartitionConsumer, error) {
+	messages := make(chan *sarama.ConsumerMessage, len(c.partitions[partition]))
+	for _, message := range c.partitions[partition] {
+		if message.Offset >= offset {
+			messages <- message
+		}
+	}
+	return &MockPartitionConsumer{messages: messages, errors: make(chan *sarama.ConsumerError)}, nil
+}
+
+func (c *MockRedeliveryClient) SendMessage(message *sarama.ProducerMessage) (int32, int64, error) {
+	if c.produceErr != nil {
+		return 0, 0, c.produceErr
+	}
+	c.produced = append(c.produced, message)
+	return 0, int64(len(c.produced) - 1), nil
+}
+
+func (c *MockRedeliveryClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+// Get The Messages Produced By The Mock RedeliveryClient
+func (c *MockRedeliveryClient) Produced() []*sarama.ProducerMessage {
+	return c.produced
+}
+
+// Check Whether The Mock RedeliveryClient Was Closed
+func (c *MockRedeliveryClient) Closed() bool {
+	return c.closed
+}
+
+//
+// Mock PartitionConsumer
+//
+
+// Verify The Mock PartitionConsumer Implements The Interface
+var _ sarama.PartitionConsumer = &MockPartitionConsumer{}
+
+// Mock PartitionConsumer Of The Buffered Messages
+type MockPartitionConsumer struct {
+	messages chan *sarama.ConsumerMessage
+	errors   chan *sarama.ConsumerError
+}
+
+func (c *MockPartitionConsumer) AsyncClose() {}
+
+func (c *MockPartitionConsumer) Close() error {
+	return nil
+}
+
+func (c *MockPartitionConsumer) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func (c *MockPartitionConsumer) Errors() <-chan *sarama.ConsumerError {
+	return c.errors
+}
+
+func (c *MockPartitionConsumer) HighWaterMarkOffset() int64 {
+	return 0
+}
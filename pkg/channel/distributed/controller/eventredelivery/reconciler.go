@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventredelivery
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	eventredeliveryreconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/eventredelivery"
+	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/reconciler"
+)
+
+// Reconciler Implements controller.Reconciler for EventRedelivery Resources
+type Reconciler struct {
+	logger             *zap.Logger
+	kubeClientset      kubernetes.Interface
+	environment        *env.Environment
+	config             *config.EventingKafkaConfig
+	saramaConfig       *sarama.Config
+	kafkachannelLister kafkalisters.KafkaChannelLister
+}
+
+var (
+	_ eventredeliveryreconciler.Interface = (*Reconciler)(nil) // Verify Reconciler Implements Interface
+)
+
+// ReconcileKind Implements The Reconciler Interface & Is Responsible For Performing The Redelivery
+func (r *Reconciler) ReconcileKind(ctx context.Context, redelivery *kafkav1beta1.EventRedelivery) reconciler.Event {
+
+	// Setup Logger & Debug Log Separator
+	r.logger.Debug("<==========  START EVENT-REDELIVERY RECONCILIATION  ==========>")
+	logger := r.logger.With(zap.String("EventRedelivery", redelivery.Namespace+"/"+redelivery.Name))
+
+	// An EventRedelivery Is Only Performed Once
+	if redelivery.Status.IsSucceeded() {
+		logger.Debug("EventRedelivery Already Completed - Skipping")
+		return nil
+	}
+
+	// Events Can Only Be Redelivered From The Quarantine Topics Of KafkaChannels If They Are Enabled
+	if !r.config.Kafka.Quarantine.Enabled {
+		logger.Warn("KafkaChannel Quarantine Topics Are Not Enabled - Unable To Redeliver Events")
+		redelivery.Status.MarkChannelFailed("QuarantineDisabled", "KafkaChannel quarantine topics are not enabled")
+		return controller.NewPermanentError(errors.New("kafkachannel quarantine topics are not enabled"))
+	}
+
+	// Get The KafkaChannel Whose Quarantined Events Are To Be Redelivered
+	channel, err := r.kafkachannelLister.KafkaChannels(redelivery.Namespace).Get(redelivery.Spec.Channel)
+	if err != nil {
+		logger.Error("Failed To Get KafkaChannel", zap.String("Channel", redelivery.Spec.Channel), zap.Error(err))
+		redelivery.Status.MarkChannelFailed("ChannelUnavailable", "Failed to get KafkaChannel %q: %v", redelivery.Spec.Channel, err)
+		return err
+	}
+	redelivery.Status.MarkChannelReady()
+
+	// Redeliver The Selected Quarantined Events (Resuming From The Progress Recorded In The Status)
+	err = r.redeliver(ctx, logger, redelivery, channel)
+	if err != nil {
+		logger.Error("Failed To Redeliver Quarantined Events", zap.Error(err))
+		redelivery.Status.MarkRedeliveryFailed("RedeliveryFailed", "Failed to redeliver quarantined events: %v", err)
+		return reconciler.NewEvent(corev1.EventTypeWarning, event.EventRedeliveryFailed.String(), "Failed To Redeliver Quarantined Events: %v", err)
+	}
+
+	// Mark The EventRedelivery As Completed
+	now := metav1.Now()
+	redelivery.Status.CompletionTime = &now
+	redelivery.Status.MarkRedelivered()
+
+	// Return Success
+	logger.Info("Successfully Redelivered Quarantined Events",
+		zap.Int64("ScannedEvents", redelivery.Status.ScannedEvents),
+		zap.Int64("RedeliveredEvents", redelivery.Status.RedeliveredEvents))
+	return reconciler.NewEvent(corev1.EventTypeNormal, event.EventRedeliveryCompleted.String(),
+		"Redelivered %d Of %d Quarantined Events To KafkaChannel: \"%s/%s\"",
+		redelivery.Status.RedeliveredEvents, redelivery.Status.ScannedEvents, channel.Namespace, channel.Name)
+}
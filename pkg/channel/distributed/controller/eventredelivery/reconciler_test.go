@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventredelivery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/reconciler"
+)
+
+// Test The ReconcileKind() Functionality
+func TestReconcileKind(t *testing.T) {
+
+	// Test Data
+	testErr := errors.New("test create client error")
+	messages := []*sarama.ConsumerMessage{
+		newTestEventMessage(t, 0, "id-0", testEventType),
+		newTestPoisonPillMessage(1),
+		newTestEventMessage(t, 2, "id-2", testOtherEventType),
+	}
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name              string
+		quarantine        bool
+		channel           bool
+		succeeded         bool
+		clientErr         error
+		filter            kafkav1beta1.EventRedeliveryFilter
+		expectChannel     bool
+		expectPermanent   bool
+		expectSucceeded   bool
+		expectEventReason string
+		expectRedelivered int
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name:              "Redeliver All",
+			quarantine:        true,
+			channel:           true,
+			expectChannel:     true,
+			expectSucceeded:   true,
+			expectEventReason: event.EventRedeliveryCompleted.String(),
+			expectRedelivered: 3,
+		},
+		{
+			name:              "Redeliver Filtered",
+			quarantine:        true,
+			channel:           true,
+			filter:            kafkav1beta1.EventRedeliveryFilter{Types: []string{testEventType}},
+			expectChannel:     true,
+			expectSucceeded:   true,
+			expectEventReason: event.EventRedeliveryCompleted.String(),
+			expectRedelivered: 1,
+		},
+		{
+			name:       "Already Succeeded",
+			quarantine: true,
+			channel:    true,
+			succeeded:  true,
+		},
+		{
+			name:            "Quarantine Disabled",
+			channel:         true,
+			expectPermanent: true,
+		},
+		{
+			name:       "KafkaChannel Not Found",
+			quarantine: true,
+		},
+		{
+			name:              "Client Creation Failure",
+			quarantine:        true,
+			channel:           true,
+			clientErr:         testErr,
+			expectChannel:     true,
+			expectEventReason: event.EventRedeliveryFailed.String(),
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The RedeliveryClient Creation & Restore After The Test
+			client := NewMockRedeliveryClient(map[int32][]*sarama.ConsumerMessage{0: messages}, nil)
+			clientCreated := false
+			newRedeliveryClientWrapperPlaceholder := NewRedeliveryClientWrapper
+			NewRedeliveryClientWrapper = func(brokers []string, config *sarama.Config) (RedeliveryClient, error) {
+				clientCreated = true
+				assert.Equal(t, []string{controllertesting.KafkaSecretDataValueBrokers}, brokers)
+				assert.Equal(t, controllertesting.KafkaSecretDataValueUsername, config.Net.SASL.User)
+				if testCase.clientErr != nil {
+					return nil, testCase.clientErr
+				}
+				return client, nil
+			}
+			defer func() { NewRedeliveryClientWrapper = newRedeliveryClientWrapperPlaceholder }()
+
+			// Create The Reconciler
+			var objects []runtime.Object
+			if testCase.channel {
+				objects = append(objects, controllertesting.NewKafkaChannel())
+			}
+			listers := controllertesting.NewListers(objects)
+			kafkaSecret := controllertesting.NewKafkaSecret()
+			kafkaSecret.Labels = map[string]string{kafkaconstants.KafkaSecretLabel: "true"}
+			configuration := controllertesting.NewConfig()
+			configuration.Kafka.Quarantine.Enabled = testCase.quarantine
+			r := &Reconciler{
+				logger:             logtesting.TestLogger(t).Desugar(),
+				kubeClientset:      fakekubeclientset.NewSimpleClientset(kafkaSecret),
+				environment:        controllertesting.NewEnvironment(),
+				config:             configuration,
+				saramaConfig:       sarama.NewConfig(),
+				kafkachannelLister: listers.GetKafkaChannelLister(),
+			}
+
+			// Create The EventRedelivery
+			redelivery := newTestEventRedelivery(testCase.filter)
+			redelivery.Status.InitializeConditions()
+			if testCase.succeeded {
+				redelivery.Status.MarkChannelReady()
+				redelivery.Status.MarkRedelivered()
+			}
+
+			// Perform The Test
+			reconcileEvent := r.ReconcileKind(context.TODO(), redelivery)
+
+			// Verify The Results
+			assert.Equal(t, testCase.expectPermanent, controller.IsPermanentError(reconcileEvent))
+			if len(testCase.expectEventReason) > 0 {
+				var reconcilerEvent *reconciler.ReconcilerEvent
+				assert.True(t, reconciler.EventAs(reconcileEvent, &reconcilerEvent))
+				assert.Equal(t, testCase.expectEventReason, reconcilerEvent.Reason)
+			}
+			if testCase.succeeded {
+				assert.Nil(t, reconcileEvent)
+				assert.False(t, clientCreated)
+				return
+			}
+			assert.Equal(t, testCase.expectChannel, redelivery.Status.GetCondition(kafkav1beta1.EventRedeliveryConditionChannelReady).IsTrue())
+			assert.Equal(t, testCase.expectSucceeded, redelivery.Status.IsSucceeded())
+			assert.Len(t, client.Produced(), testCase.expectRedelivered)
+			if testCase.expectSucceeded {
+				assert.Equal(t, corev1.EventTypeNormal, reconcileEvent.(*reconciler.ReconcilerEvent).EventType)
+				assert.Equal(t, int64(len(messages)), redelivery.Status.ScannedEvents)
+				assert.Equal(t, int64(testCase.expectRedelivered), redelivery.Status.RedeliveredEvents)
+				assert.Equal(t, []kafkav1beta1.EventRedeliveryPartition{{Partition: 0, Offset: 3, EndOffset: 3}}, redelivery.Status.Partitions)
+				assert.NotNil(t, redelivery.Status.CompletionTime)
+				assert.True(t, client.Closed())
+			} else {
+				assert.NotNil(t, reconcileEvent)
+				assert.Nil(t, redelivery.Status.CompletionTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventredelivery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+)
+
+// The Maximum Time To Wait For The Next Quarantined Event Of A Partition Before The Attempt Is Abandoned (Variable For Testing)
+var ConsumeTimeout = 30 * time.Second
+
+// The Extensions (Headers Of Poison Pills) Describing Why An Event Was Quarantined, Which Are Removed When Redelivered
+var errorExtensions = []string{
+	deadletter.ErrorDestExtension,
+	deadletter.ErrorCodeExtension,
+	deadletter.ErrorDataExtension,
+	deadletter.ErrorRetriesExtension,
+	deadletter.ErrorTopicExtension,
+	deadletter.ErrorPartitionExtension,
+	deadletter.ErrorOffsetExtension,
+}
+
+// The Kafka Client Operations Required To Redeliver Quarantined Events (Interface To Facilitate Unit Testing)
+type RedeliveryClient interface {
+	Partitions(topic string) ([]int32, error)
+	GetOffset(topic string, partition int32, time int64) (int64, error)
+	ConsumePartition(topic string, partition int32, offset int64) (sarama.PartitionConsumer, error)
+	SendMessage(message *sarama.ProducerMessage) (partition int32, offset int64, err error)
+	Close() error
+}
+
+// New RedeliveryClient Wrapper To Facilitate Unit Testing
+var NewRedeliveryClientWrapper = func(brokers []string, config *sarama.Config) (RedeliveryClient, error) {
+	return newSaramaRedeliveryClient(brokers, config)
+}
+
+// Redeliver The Selected Quarantined Events Of The KafkaChannel, Recording The Progress In The EventRedelivery's Status
+func (r *Reconciler) redeliver(ctx context.Context, logger *zap.Logger, redelivery *kafkav1beta1.EventRedelivery, channel *kafkav1beta1.KafkaChannel) error {
+
+	// Get The KafkaChannel's Topic & Quarantine Topic
+	topicName := util.TopicName(channel)
+	quarantineTopicName := kafkautil.QuarantineTopicName(topicName)
+
+	// Create A Kafka Client For The Duration Of The Redelivery
+	client, err := r.newRedeliveryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Warn("Failed To Close Kafka Client", zap.Error(closeErr))
+		}
+	}()
+
+	// Get The Envelope Decrypting The Quarantined Records & Encrypting The Redelivered Ones (nil Unless Encryption Is Enabled)
+	envelope, err := encryption.NewSecretEnvelope(ctx, r.kubeClientset, r.config.Kafka.Encryption)
+	if err != nil {
+		return fmt.Errorf("failed to create encryption envelope: %w", err)
+	}
+
+	// Snapshot The Quarantine Topic's Partitions On The First Attempt (Events Quarantined Afterwards Are Not Redelivered)
+	if len(redelivery.Status.Partitions) == 0 {
+		redelivery.Status.Partitions, err = snapshotPartitions(client, quarantineTopicName)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot quarantine topic %s: %w", quarantineTopicName, err)
+		}
+		logger.Info("Snapshot Quarantine Topic Partitions", zap.String("Topic", quarantineTopicName), zap.Any("Partitions", redelivery.Status.Partitions))
+	}
+	redelivery.Status.MarkRedeliveryInProgress("RedeliveryInProgress", "Redelivering quarantined events")
+
+	// Redeliver The Selected Events Of Each Partition
+	for index := range redelivery.Status.Partitions {
+		err = redeliverPartition(ctx, client, envelope, redelivery, &redelivery.Status.Partitions[index], quarantineTopicName, topicName)
+		if err != nil {
+			return fmt.Errorf("failed to redeliver partition %d of quarantine topic %s: %w", redelivery.Status.Partitions[index].Partition, quarantineTopicName, err)
+		}
+	}
+
+	// Return Success
+	return nil
+}
+
+// Create A RedeliveryClient Authenticated Via The KafkaAuthSpec If Specified, Otherwise The Kafka Secret
+func (r *Reconciler) newRedeliveryClient(ctx context.Context) (RedeliveryClient, error) {
+	brokers, saramaConfig, err := util.ControllerKafkaClientConfig(ctx, r.logger, r.kubeClientset, r.config, r.saramaConfig, r.environment.PodName)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedeliveryClientWrapper(brokers, saramaConfig)
+}
+
+// Utility Function For Getting The Current Oldest & Newest Offsets Of Each Partition Of The Specified Topic
+func snapshotPartitions(client RedeliveryClient, topicName string) ([]kafkav1beta1.EventRedeliveryPartition, error) {
+	partitionIds, err := client.Partitions(topicName)
+	if err != nil {
+		return nil, err
+	}
+	partitions := make([]kafkav1beta1.EventRedeliveryPartition, 0, len(partitionIds))
+	for _, partitionId := range partitionIds {
+		oldestOffset, err := client.GetOffset(topicName, partitionId, sarama.OffsetOldest)
+		if err != nil {
+			return nil, err
+		}
+		newestOffset, err := client.GetOffset(topicName, partitionId, sarama.OffsetNewest)
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, kafkav1beta1.EventRedeliveryPartition{Partition: partitionId, Offset: oldestOffset, EndOffset: newestOffset})
+	}
+	return partitions, nil
+}
+
+// Redeliver The Selected Quarantined Events Of A Single Partition Up To Its EndOffset, Advancing Its Offset As They Are Examined
+func redeliverPartition(ctx context.Context, client RedeliveryClient, envelope *encryption.Envelope, redelivery *kafkav1beta1.EventRedelivery, partition *kafkav1beta1.EventRedeliveryPartition, quarantineTopicName string, topicName string) error {
+
+	// Skip Any Events Which Have Expired From The Quarantine Topic Since The Snapshot
+	if partition.Offset < partition.EndOffset {
+		oldestOffset, err := client.GetOffset(quarantineTopicName, partition.Partition, sarama.OffsetOldest)
+		if err != nil {
+			return err
+		}
+		if oldestOffset > partition.Offset {
+			partition.Offset = oldestOffset
+		}
+	}
+
+	// Nothing To Do If All Of The Partition's Events Have Been Examined
+	if partition.Offset >= partition.EndOffset {
+		return nil
+	}
+
+	// Consume The Partition From The Next Event To Examine
+	partitionConsumer, err := client.ConsumePartition(quarantineTopicName, partition.Partition, partition.Offset)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = partitionConsumer.Close() }()
+
+	// Examine Each Event Up To The EndOffset, Redelivering Those Selected By The Filter
+	for partition.Offset < partition.EndOffset {
+		select {
+		case message := <-partitionConsumer.Messages():
+			if message.Offset >= partition.EndOffset {
+				partition.Offset = partition.EndOffset
+				break
+			}
+			redelivery.Status.ScannedEvents++
+			producerMessage, err := newEncryptedRedeliveryMessage(ctx, envelope, topicName, message, redelivery)
+			if err != nil {
+				return err
+			}
+			if producerMessage != nil {
+				_, _, err = client.SendMessage(producerMessage)
+				if err != nil {
+					return err
+				}
+				redelivery.Status.RedeliveredEvents++
+			}
+			partition.Offset = message.Offset + 1
+		case consumerErr := <-partitionConsumer.Errors():
+			return consumerErr
+		case <-time.After(ConsumeTimeout):
+			return fmt.Errorf("timed out waiting for the event at offset %d", partition.Offset)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Return Success
+	return nil
+}
+
+// Utility Function For Creating The Redelivered Copy Of A Quarantined Record If Selected By The Filter (Otherwise nil)
+// Records Are Decrypted To Be Decoded & Encrypted Again When Redelivered, Except Those Which Could Not Be Decrypted
+// (Poison Pills Quarantined Still Encrypted) Which Are Redelivered Verbatim Without Being Decoded
+func newEncryptedRedeliveryMessage(ctx context.Context, envelope *encryption.Envelope, topicName string, message *sarama.ConsumerMessage, redelivery *kafkav1beta1.EventRedelivery) (*sarama.ProducerMessage, error) {
+	decryptedMessage, decryptErr := envelope.Decrypt(ctx, message)
+	var quarantinedEvent *event.Event
+	if decryptErr == nil {
+		quarantinedEvent = decodeEvent(ctx, decryptedMessage)
+	} else {
+		decryptedMessage = message
+	}
+	if !matchesFilter(redelivery.Spec.Filter, decryptedMessage, quarantinedEvent) {
+		return nil, nil
+	}
+	producerMessage, err := newRedeliveryMessage(ctx, topicName, decryptedMessage, quarantinedEvent, redelivery)
+	if err != nil {
+		return nil, err
+	}
+	if decryptErr != nil {
+		encryption.MarkVerbatim(producerMessage)
+		return producerMessage, nil
+	}
+	err = envelope.Encrypt(ctx, producerMessage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt redelivered event: %w", err)
+	}
+	return producerMessage, nil
+}
+
+// Utility Function For Decoding A Quarantined Event (nil For Poison Pills Which Are Not Valid CloudEvents)
+func decodeEvent(ctx context.Context, message *sarama.ConsumerMessage) *event.Event {
+	cloudEventMessage := kafkasaramaprotocol.NewMessageFromConsumerMessage(message)
+	if cloudEventMessage.ReadEncoding() == binding.EncodingUnknown {
+		return nil
+	}
+	quarantinedEvent, err := binding.ToEvent(ctx, cloudEventMessage)
+	if err != nil || quarantinedEvent.Validate() != nil {
+		return nil
+	}
+	return quarantinedEvent
+}
+
+// Utility Function For Determining Whether A Quarantined Event Is Selected By The Filter (Poison Pills Have No ID Or Type)
+func matchesFilter(filter kafkav1beta1.EventRedeliveryFilter, message *sarama.ConsumerMessage, quarantinedEvent *event.Event) bool {
+	if filter.Since != nil && message.Timestamp.Before(filter.Since.Time) {
+		return false
+	}
+	if filter.Until != nil && message.Timestamp.After(filter.Until.Time) {
+		return false
+	}
+	if len(filter.IDs) > 0 && (quarantinedEvent == nil || !contains(filter.IDs, quarantinedEvent.ID())) {
+		return false
+	}
+	if len(filter.Types) > 0 && (quarantinedEvent == nil || !contains(filter.Types, quarantinedEvent.Type())) {
+		return false
+	}
+	return true
+}
+
+// Utility Function For Creating The Redelivered Copy Of A Quarantined Event (Without Error Extensions, With Audit Extensions)
+func newRedeliveryMessage(ctx context.Context, topicName string, message *sarama.ConsumerMessage, quarantinedEvent *event.Event, redelivery *kafkav1beta1.EventRedelivery) (*sarama.ProducerMessage, error) {
+
+	// Create The ProducerMessage, Retaining The Original Partition Key
+	producerMessage := &sarama.ProducerMessage{Topic: topicName}
+	if message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(message.Key)
+	}
+
+	// The Audit Values Identifying The EventRedelivery
+	redeliveredBy := redelivery.Namespace + "/" + redelivery.Name
+	redeliveredAt := time.Now().UTC().Format(time.RFC3339)
+
+	// Poison Pills Are Redelivered Verbatim With Their Error Headers Replaced By The Audit Headers
+	if quarantinedEvent == nil {
+		headers := make([]sarama.RecordHeader, 0, len(message.Headers)+2)
+		for _, header := range message.Headers {
+			if header != nil && !contains(errorExtensions, string(header.Key)) {
+				headers = append(headers, *header)
+			}
+		}
+		producerMessage.Headers = append(headers,
+			sarama.RecordHeader{Key: []byte(kafkav1beta1.RedeliveryExtension), Value: []byte(redeliveredBy)},
+			sarama.RecordHeader{Key: []byte(kafkav1beta1.RedeliveredAtExtension), Value: []byte(redeliveredAt)})
+		if message.Value != nil {
+			producerMessage.Value = sarama.ByteEncoder(message.Value)
+		}
+		return producerMessage, nil
+	}
+
+	// CloudEvents Are Redelivered With Their Error Extensions Replaced By The Audit Extensions
+	redeliveredEvent := quarantinedEvent.Clone()
+	for _, extension := range errorExtensions {
+		redeliveredEvent.SetExtension(extension, nil)
+	}
+	redeliveredEvent.SetExtension(kafkav1beta1.RedeliveryExtension, redeliveredBy)
+	redeliveredEvent.SetExtension(kafkav1beta1.RedeliveredAtExtension, redeliveredAt)
+	err := kafkasaramaprotocol.WriteProducerMessage(ctx, binding.ToMessage(&redeliveredEvent), producerMessage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create redelivered event: %w", err)
+	}
+	return producerMessage, nil
+}
+
+// Utility Function For Determining Whether A String Slice Contains The Specified Value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// Sarama RedeliveryClient
+//
+
+// RedeliveryClient Implementation Based On A Single Sarama Client Shared By A Consumer & SyncProducer
+type saramaRedeliveryClient struct {
+	client   sarama.Client
+	consumer sarama.Consumer
+	producer sarama.SyncProducer
+}
+
+// Create A New Sarama RedeliveryClient Connected To The Specified Brokers
+func newSaramaRedeliveryClient(brokers []string, config *sarama.Config) (RedeliveryClient, error) {
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return nil, err
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		_ = consumer.Close()
+		_ = client.Close()
+		return nil, err
+	}
+	return &saramaRedeliveryClient{client: client, consumer: consumer, producer: producer}, nil
+}
+
+func (c *saramaRedeliveryClient) Partitions(topic string) ([]int32, error) {
+	return c.client.Partitions(topic)
+}
+
+func (c *saramaRedeliveryClient) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	return c.client.GetOffset(topic, partition, time)
+}
+
+func (c *saramaRedeliveryClient) ConsumePartition(topic string, partition int32, offset int64) (sarama.PartitionConsumer, error) {
+	return c.consumer.ConsumePartition(topic, partition, offset)
+}
+
+func (c *saramaRedeliveryClient) SendMessage(message *sarama.ProducerMessage) (int32, int64, error) {
+	return c.producer.SendMessage(message)
+}
+
+func (c *saramaRedeliveryClient) Close() error {
+	_ = c.producer.Close()
+	_ = c.consumer.Close()
+	return c.client.Close()
+}
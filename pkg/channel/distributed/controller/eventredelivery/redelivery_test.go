@@ -0,0 +1,265 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventredelivery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+)
+
+// Test Data
+const (
+	testQuarantineTopic = controllertesting.TopicName + ".quarantine"
+	testEventType       = "test.event.type"
+	testOtherEventType  = "test.other.event.type"
+)
+
+var testTimestamp = time.Date(2020, 11, 1, 12, 0, 0, 0, time.UTC)
+
+// Test The matchesFilter() Functionality
+func TestMatchesFilter(t *testing.T) {
+
+	// Test Data
+	message := &sarama.ConsumerMessage{Timestamp: testTimestamp}
+	quarantinedEvent := newTestEvent(t, "id-1", testEventType)
+	before := metav1.NewTime(testTimestamp.Add(-time.Minute))
+	after := metav1.NewTime(testTimestamp.Add(time.Minute))
+
+	// The Empty Filter Matches Everything
+	assert.True(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{}, message, quarantinedEvent))
+	assert.True(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{}, message, nil))
+
+	// Time Range
+	assert.True(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{Since: &before, Until: &after}, message, quarantinedEvent))
+	assert.False(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{Since: &after}, message, quarantinedEvent))
+	assert.False(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{Until: &before}, message, quarantinedEvent))
+
+	// IDs & Types (Never Matching Poison Pills)
+	assert.True(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{IDs: []string{"id-0", "id-1"}}, message, quarantinedEvent))
+	assert.False(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{IDs: []string{"id-0"}}, message, quarantinedEvent))
+	assert.False(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{IDs: []string{"id-1"}}, message, nil))
+	assert.True(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{Types: []string{testEventType}}, message, quarantinedEvent))
+	assert.False(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{Types: []string{testOtherEventType}}, message, quarantinedEvent))
+	assert.False(t, matchesFilter(kafkav1beta1.EventRedeliveryFilter{Types: []string{testEventType}}, message, nil))
+}
+
+// Test The redeliverPartition() Functionality
+func TestRedeliverPartition(t *testing.T) {
+
+	// Create A Quarantine Topic Partition Whose First Event Has Expired
+	messages := []*sarama.ConsumerMessage{
+		newTestEventMessage(t, 1, "id-1", testEventType),
+		newTestPoisonPillMessage(2),
+		newTestEventMessage(t, 3, "id-3", testOtherEventType),
+		newTestEventMessage(t, 4, "id-4", testEventType), // Quarantined After The Snapshot
+	}
+	client := NewMockRedeliveryClient(map[int32][]*sarama.ConsumerMessage{0: messages}, nil)
+	redelivery := newTestEventRedelivery(kafkav1beta1.EventRedeliveryFilter{})
+	partition := &kafkav1beta1.EventRedeliveryPartition{Partition: 0, Offset: 0, EndOffset: 4}
+
+	// Perform The Test
+	err := redeliverPartition(context.TODO(), client, nil, redelivery, partition, testQuarantineTopic, controllertesting.TopicName)
+
+	// Verify The Events Up To The EndOffset Were Redelivered
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), partition.Offset)
+	assert.Equal(t, int64(3), redelivery.Status.ScannedEvents)
+	assert.Equal(t, int64(3), redelivery.Status.RedeliveredEvents)
+	assert.Len(t, client.Produced(), 3)
+	for _, producerMessage := range client.Produced() {
+		assert.Equal(t, controllertesting.TopicName, producerMessage.Topic)
+	}
+
+	// Verify A Partition Which Has Been Completely Examined Is Not Consumed Again
+	err = redeliverPartition(context.TODO(), client, nil, redelivery, partition, testQuarantineTopic, controllertesting.TopicName)
+	assert.Nil(t, err)
+	assert.Len(t, client.Produced(), 3)
+
+	// Verify Produce Failures Are Returned Without Advancing The Offset
+	client = NewMockRedeliveryClient(map[int32][]*sarama.ConsumerMessage{0: messages}, errors.New("test produce error"))
+	partition = &kafkav1beta1.EventRedeliveryPartition{Partition: 0, Offset: 1, EndOffset: 4}
+	err = redeliverPartition(context.TODO(), client, nil, newTestEventRedelivery(kafkav1beta1.EventRedeliveryFilter{}), partition, testQuarantineTopic, controllertesting.TopicName)
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(1), partition.Offset)
+}
+
+// Test The newRedeliveryMessage() Functionality
+func TestNewRedeliveryMessage(t *testing.T) {
+	redelivery := newTestEventRedelivery(kafkav1beta1.EventRedeliveryFilter{})
+
+	// Quarantined CloudEvents Have Their Error Extensions Replaced By The Audit Extensions
+	message := newTestEventMessage(t, 1, "id-1", testEventType)
+	producerMessage, err := newRedeliveryMessage(context.TODO(), controllertesting.TopicName, message, decodeEvent(context.TODO(), message), redelivery)
+	assert.Nil(t, err)
+	assert.Equal(t, controllertesting.TopicName, producerMessage.Topic)
+	assert.Equal(t, sarama.ByteEncoder(message.Key), producerMessage.Key)
+	redeliveredEvent := toEvent(t, producerMessage)
+	assert.NotNil(t, redeliveredEvent)
+	assert.Equal(t, "id-1", redeliveredEvent.ID())
+	assert.Equal(t, testEventType, redeliveredEvent.Type())
+	assert.Nil(t, redeliveredEvent.Extensions()[deadletter.ErrorDestExtension])
+	assert.Nil(t, redeliveredEvent.Extensions()[deadletter.ErrorCodeExtension])
+	assert.Equal(t, redelivery.Namespace+"/"+redelivery.Name, redeliveredEvent.Extensions()[kafkav1beta1.RedeliveryExtension])
+	assert.NotNil(t, redeliveredEvent.Extensions()[kafkav1beta1.RedeliveredAtExtension])
+
+	// Quarantined Poison Pills Are Redelivered Verbatim With Their Error Headers Replaced By The Audit Headers
+	message = newTestPoisonPillMessage(2)
+	producerMessage, err = newRedeliveryMessage(context.TODO(), controllertesting.TopicName, message, nil, redelivery)
+	assert.Nil(t, err)
+	assert.Equal(t, sarama.ByteEncoder(message.Value), producerMessage.Value)
+	headers := make(map[string]string)
+	for _, header := range producerMessage.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	assert.Equal(t, "TestHeaderValue", headers["TestHeader"])
+	assert.NotContains(t, headers, deadletter.ErrorDataExtension)
+	assert.NotContains(t, headers, deadletter.ErrorOffsetExtension)
+	assert.Equal(t, redelivery.Namespace+"/"+redelivery.Name, headers[kafkav1beta1.RedeliveryExtension])
+	assert.Contains(t, headers, kafkav1beta1.RedeliveredAtExtension)
+}
+
+// Test The newEncryptedRedeliveryMessage() Functionality
+func TestNewEncryptedRedeliveryMessage(t *testing.T) {
+	ctx := context.TODO()
+	kms, err := encryption.NewSecretKMS(&corev1.Secret{Data: map[string][]byte{"key1": []byte("0123456789abcdef0123456789abcdef")}})
+	assert.Nil(t, err)
+	envelope := encryption.NewKMSEnvelope(kms, "key1")
+
+	// Encrypted Quarantined CloudEvents Are Decrypted To Be Filtered & Are Encrypted Again When Redelivered
+	eventMessage := newTestEventMessage(t, 1, "id-1", testEventType)
+	encryptedMessage := encryptTestMessage(t, envelope, eventMessage)
+	producerMessage, err := newEncryptedRedeliveryMessage(ctx, envelope, controllertesting.TopicName, encryptedMessage, newTestEventRedelivery(kafkav1beta1.EventRedeliveryFilter{IDs: []string{"id-1"}}))
+	assert.Nil(t, err)
+	assert.NotNil(t, producerMessage)
+	assert.False(t, encryption.IsVerbatim(producerMessage))
+	value, err := producerMessage.Value.Encode()
+	assert.Nil(t, err)
+	assert.NotContains(t, string(value), "data")
+	decryptedMessage, err := envelope.Decrypt(ctx, toConsumerMessage(t, producerMessage))
+	assert.Nil(t, err)
+	redeliveredEvent := decodeEvent(ctx, decryptedMessage)
+	assert.NotNil(t, redeliveredEvent)
+	assert.Equal(t, "id-1", redeliveredEvent.ID())
+	assert.Nil(t, redeliveredEvent.Extensions()[deadletter.ErrorDestExtension])
+
+	// Quarantined CloudEvents Not Selected By The Filter Are Not Redelivered
+	producerMessage, err = newEncryptedRedeliveryMessage(ctx, envelope, controllertesting.TopicName, encryptedMessage, newTestEventRedelivery(kafkav1beta1.EventRedeliveryFilter{IDs: []string{"id-2"}}))
+	assert.Nil(t, err)
+	assert.Nil(t, producerMessage)
+
+	// Records Which Can't Be Decrypted (e.g. Without Encryption Enabled) Are Redelivered Verbatim
+	producerMessage, err = newEncryptedRedeliveryMessage(ctx, nil, controllertesting.TopicName, encryptedMessage, newTestEventRedelivery(kafkav1beta1.EventRedeliveryFilter{}))
+	assert.Nil(t, err)
+	assert.NotNil(t, producerMessage)
+	assert.True(t, encryption.IsVerbatim(producerMessage))
+	assert.Equal(t, sarama.ByteEncoder(encryptedMessage.Value), producerMessage.Value)
+	decryptedMessage, err = envelope.Decrypt(ctx, toConsumerMessage(t, producerMessage))
+	assert.Nil(t, err)
+	assert.Equal(t, "id-1", decodeEvent(ctx, decryptedMessage).ID())
+}
+
+// Utility Function For Encrypting A Quarantined Test Message With The Specified Envelope
+func encryptTestMessage(t *testing.T, envelope *encryption.Envelope, message *sarama.ConsumerMessage) *sarama.ConsumerMessage {
+	producerMessage := &sarama.ProducerMessage{Topic: message.Topic, Value: sarama.ByteEncoder(message.Value)}
+	for _, header := range message.Headers {
+		producerMessage.Headers = append(producerMessage.Headers, *header)
+	}
+	assert.Nil(t, envelope.Encrypt(context.TODO(), producerMessage))
+	encryptedMessage := toConsumerMessage(t, producerMessage)
+	encryptedMessage.Key = message.Key
+	encryptedMessage.Offset = message.Offset
+	return encryptedMessage
+}
+
+// Utility Function For Creating A Test EventRedelivery
+func newTestEventRedelivery(filter kafkav1beta1.EventRedeliveryFilter) *kafkav1beta1.EventRedelivery {
+	return &kafkav1beta1.EventRedelivery{
+		ObjectMeta: metav1.ObjectMeta{Namespace: controllertesting.KafkaChannelNamespace, Name: "redelivery-name"},
+		Spec:       kafkav1beta1.EventRedeliverySpec{Channel: controllertesting.KafkaChannelName, Filter: filter},
+	}
+}
+
+// Utility Function For Creating A Test CloudEvent
+func newTestEvent(t *testing.T, id string, eventType string) *event.Event {
+	testEvent := event.New()
+	testEvent.SetID(id)
+	testEvent.SetType(eventType)
+	testEvent.SetSource("/test/source")
+	assert.Nil(t, testEvent.SetData("application/json", map[string]string{"test": "data"}))
+	return &testEvent
+}
+
+// Utility Function For Creating A Quarantined CloudEvent Message (With Delivery Error Extensions) At The Specified Offset
+func newTestEventMessage(t *testing.T, offset int64, id string, eventType string) *sarama.ConsumerMessage {
+	testEvent := newTestEvent(t, id, eventType)
+	testEvent.SetExtension(deadletter.ErrorDestExtension, "http://subscriber.example.com")
+	testEvent.SetExtension(deadletter.ErrorCodeExtension, "500")
+	producerMessage := &sarama.ProducerMessage{Topic: testQuarantineTopic}
+	assert.Nil(t, kafkasaramaprotocol.WriteProducerMessage(context.TODO(), binding.ToMessage(testEvent), producerMessage))
+	consumerMessage := toConsumerMessage(t, producerMessage)
+	consumerMessage.Key = []byte("TestKey")
+	consumerMessage.Offset = offset
+	return consumerMessage
+}
+
+// Utility Function For Creating A Quarantined Poison Pill Message (With Decode Error Headers) At The Specified Offset
+func newTestPoisonPillMessage(offset int64) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Topic:     testQuarantineTopic,
+		Offset:    offset,
+		Timestamp: testTimestamp,
+		Value:     []byte("garbage"),
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte("TestHeader"), Value: []byte("TestHeaderValue")},
+			{Key: []byte(deadletter.ErrorDataExtension), Value: []byte("test decode error")},
+			{Key: []byte(deadletter.ErrorOffsetExtension), Value: []byte("123")},
+		},
+	}
+}
+
+// Utility Function For Converting A ProducerMessage Into The ConsumerMessage Which Would Be Received
+func toConsumerMessage(t *testing.T, producerMessage *sarama.ProducerMessage) *sarama.ConsumerMessage {
+	consumerMessage := &sarama.ConsumerMessage{Topic: producerMessage.Topic, Timestamp: testTimestamp}
+	for index := range producerMessage.Headers {
+		consumerMessage.Headers = append(consumerMessage.Headers, &producerMessage.Headers[index])
+	}
+	if producerMessage.Value != nil {
+		value, err := producerMessage.Value.Encode()
+		assert.Nil(t, err)
+		consumerMessage.Value = value
+	}
+	return consumerMessage
+}
+
+// Utility Function For Decoding The CloudEvent Of A ProducerMessage
+func toEvent(t *testing.T, producerMessage *sarama.ProducerMessage) *event.Event {
+	return decodeEvent(context.TODO(), toConsumerMessage(t, producerMessage))
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"strconv"
+
+	"github.com/Shopify/sarama"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
+	"knative.dev/pkg/system"
+)
+
+// Record The Specified Action On The Specified Kafka Topic In The Audit Log (If Enabled)
+func (r *Reconciler) auditTopic(action string, topicName string, details map[string]string) {
+	if r.auditRecorder != nil {
+		r.auditRecorder.Record(audit.Entry{Kind: audit.KindTopic, Action: action, Name: topicName, Details: details})
+	}
+}
+
+// Get The Audited Details Of The Specified TopicDetail (Its Partitions, Replication Factor & Config Entries)
+func topicDetails(topicDetail *sarama.TopicDetail) map[string]string {
+	details := make(map[string]string, len(topicDetail.ConfigEntries)+2)
+	if topicDetail.NumPartitions > 0 {
+		details["partitions"] = strconv.Itoa(int(topicDetail.NumPartitions))
+	}
+	if topicDetail.ReplicationFactor > 0 {
+		details["replicationFactor"] = strconv.Itoa(int(topicDetail.ReplicationFactor))
+	}
+	for name, value := range topicDetail.ConfigEntries {
+		if value != nil {
+			details[name] = *value
+		}
+	}
+	return details
+}
+
+// Record The Specified Action On The Specified Deployment (Of The Channel, If Known) In The Audit Log (If Enabled)
+func (r *Reconciler) auditDeployment(action string, deploymentName string, channel *kafkav1beta1.KafkaChannel, details map[string]string) {
+	if r.auditRecorder != nil {
+		entry := audit.Entry{Kind: audit.KindDeployment, Action: action, Namespace: system.Namespace(), Name: deploymentName, Details: details}
+		if channel != nil {
+			entry.Channel = channel.Namespace + "/" + channel.Name
+		}
+		r.auditRecorder.Record(entry)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+)
+
+// Test The Reconciler's auditTopic() & auditDeployment() Functionality
+func TestAudit(t *testing.T) {
+
+	// Verify Nothing Is Recorded (Or Panics) Without An Audit Recorder
+	r := &Reconciler{}
+	r.auditTopic(audit.ActionCreated, controllertesting.TopicName, nil)
+	r.auditDeployment(audit.ActionCreated, "TestDeployment", nil, nil)
+
+	// Verify The Entries Recorded With An Audit Recorder
+	recorder := &controllertesting.MockAuditRecorder{}
+	r.auditRecorder = recorder
+	r.auditTopic(audit.ActionDeleted, controllertesting.TopicName, nil)
+	r.auditDeployment(audit.ActionUpdated, "TestDeployment", controllertesting.NewKafkaChannel(), map[string]string{"replicas": "0"})
+	r.auditDeployment(audit.ActionDeleted, "TestOrphan", nil, nil)
+	assert.Equal(t, []audit.Entry{
+		{Kind: audit.KindTopic, Action: audit.ActionDeleted, Name: controllertesting.TopicName},
+		{Kind: audit.KindDeployment, Action: audit.ActionUpdated, Namespace: commonconstants.KnativeEventingNamespace, Name: "TestDeployment",
+			Channel: controllertesting.KafkaChannelNamespace + "/" + controllertesting.KafkaChannelName, Details: map[string]string{"replicas": "0"}},
+		{Kind: audit.KindDeployment, Action: audit.ActionDeleted, Namespace: commonconstants.KnativeEventingNamespace, Name: "TestOrphan"},
+	}, recorder.Entries)
+}
+
+// Test The topicDetails() Functionality
+func TestTopicDetails(t *testing.T) {
+	retentionMillis := "604800000"
+	details := topicDetails(&sarama.TopicDetail{
+		NumPartitions:     4,
+		ReplicationFactor: 3,
+		ConfigEntries:     map[string]*string{constants.KafkaTopicConfigRetentionMs: &retentionMillis, "ignored": nil},
+	})
+	assert.Equal(t, map[string]string{"partitions": "4", "replicationFactor": "3", constants.KafkaTopicConfigRetentionMs: retentionMillis}, details)
+	assert.Empty(t, topicDetails(&sarama.TopicDetail{}))
+}
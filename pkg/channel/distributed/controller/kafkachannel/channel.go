@@ -25,7 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
-	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
@@ -33,6 +33,7 @@ import (
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/network"
+	"knative.dev/pkg/system"
 )
 
 // Reconcile The "Channel" Inbound For The Specified Channel
@@ -57,7 +58,7 @@ func (r *Reconciler) reconcileChannel(ctx context.Context, channel *kafkav1beta1
 // KafkaChannel Kafka Channel Service
 //
 // One K8S Service per KafkaChannel, in the same namespace as the KafkaChannel, with an
-// ExternalName reference to the single K8S Service in the knative-eventing namespace
+// ExternalName reference to the single K8S Service in the system namespace
 // for the Channel Deployment/Pods.
 //
 
@@ -87,21 +88,62 @@ func (r *Reconciler) reconcileKafkaChannelService(ctx context.Context, channel *
 			return err
 		}
 	} else {
+
+		// Repoint The ExternalName Of The Existing Service If Its Receiver Changed (e.g. Receiver Isolation Toggled)
+		service, err = r.updateKafkaChannelServiceExternalName(ctx, channel, service)
+		if err != nil {
+			channel.Status.MarkChannelServiceFailed(event.KafkaChannelServiceReconciliationFailed.String(), "Failed To Update KafkaChannel Service: %v", err)
+			return err
+		}
 		r.logger.Info("Successfully Verified KafkaChannel Service")
 		// Continue To Update Channel Status
 	}
 
 	// Update Channel Status
 	channel.Status.MarkChannelServiceTrue()
-	channel.Status.SetAddress(&apis.URL{
-		Scheme: "http",
-		Host:   network.GetServiceHostname(service.Name, service.Namespace),
-	})
+	channel.Status.SetAddress(r.channelAddress(channel, service))
 
 	// Return Success
 	return nil
 }
 
+// Get The Address Of The Specified KafkaChannel, Either The Host Of Its KafkaChannel Service Or (With Path-Based
+// Addressing, Where Wildcard DNS Or Per-Channel Hosts Are Not Feasible) Its Path On Its Receiver's Service
+func (r *Reconciler) channelAddress(channel *kafkav1beta1.KafkaChannel, service *corev1.Service) *apis.URL {
+	if r.config.Receiver.Addressing == kafkaconstants.ReceiverAddressingPath {
+		return &apis.URL{
+			Scheme: "http",
+			Host:   network.GetServiceHostname(r.receiverName(channel), system.Namespace()),
+			Path:   kafkautil.KafkaChannelPath(channel.Namespace, channel.Name),
+		}
+	}
+	return &apis.URL{
+		Scheme: "http",
+		Host:   network.GetServiceHostname(service.Name, service.Namespace),
+	}
+}
+
+// Update The ExternalName Of The Specified KafkaChannel Service If It Differs From The Channel's Receiver Service
+func (r *Reconciler) updateKafkaChannelServiceExternalName(ctx context.Context, channel *kafkav1beta1.KafkaChannel, service *corev1.Service) (*corev1.Service, error) {
+
+	// Nothing To Do If The Service Already References The Receiver
+	externalName := r.newKafkaChannelService(channel).Spec.ExternalName
+	if service.Spec.ExternalName == externalName {
+		return service, nil
+	}
+
+	// Update The ExternalName Of A Copy Of The Service
+	updatedService := service.DeepCopy()
+	updatedService.Spec.ExternalName = externalName
+	updatedService, err := r.kubeClientset.CoreV1().Services(updatedService.Namespace).Update(ctx, updatedService, metav1.UpdateOptions{})
+	if err != nil {
+		r.logger.Error("Failed To Update KafkaChannel Service ExternalName", zap.Error(err))
+		return nil, err
+	}
+	r.logger.Info("Successfully Updated KafkaChannel Service ExternalName", zap.String("ExternalName", externalName))
+	return updatedService, nil
+}
+
 // Get The KafkaChannel Service Associated With The Specified Channel
 func (r *Reconciler) getKafkaChannelService(channel *kafkav1beta1.KafkaChannel) (*corev1.Service, error) {
 
@@ -121,9 +163,8 @@ func (r *Reconciler) newKafkaChannelService(channel *kafkav1beta1.KafkaChannel)
 	// Get The KafkaChannel Service Name
 	serviceName := kafkautil.AppendKafkaChannelServiceNameSuffix(channel.Name)
 
-	// Get The Receiver Service Name For The Kafka Secret (One Receiver Service Per Kafka Secret)
-	deploymentName := util.ReceiverDnsSafeName(r.kafkaSecretName(channel))
-	serviceAddress := network.GetServiceHostname(deploymentName, commonconstants.KnativeEventingNamespace)
+	// Get The Receiver Service Name (One Per Kafka Secret, Or One Per KafkaChannel If Its Receiver Is Isolated)
+	serviceAddress := network.GetServiceHostname(r.receiverName(channel), system.Namespace())
 
 	// Create & Return The Service Model
 	return &corev1.Service{
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/system"
+)
+
+// Get The Effective Configuration Of The Specified KafkaChannel, Which Is Its Namespace's Configuration Overridden
+// By The Settings Of Its Class (If Any)
+func (r *Reconciler) channelConfig(ctx context.Context, channel *kafkav1beta1.KafkaChannel) (*config.NamespaceConfig, error) {
+	namespaceConfig, err := r.namespaceConfig(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	return r.classConfig(ctx, channel, namespaceConfig)
+}
+
+// Get The Configuration Of The Specified KafkaChannel's Namespace
+//
+// The optional ConfigMap in the KafkaChannel's namespace overrides the cluster-wide configuration, which in turn is
+// overridden by the KafkaChannel's spec (e.g. NumPartitions).  Invalid namespace settings, and settings which cannot
+// be overridden per namespace, are ignored and surfaced as warning events on the KafkaChannel.
+func (r *Reconciler) namespaceConfig(ctx context.Context, channel *kafkav1beta1.KafkaChannel) (*config.NamespaceConfig, error) {
+
+	// Get Channel Specific Logger
+	logger := util.ChannelLogger(r.logger, channel)
+
+	// Get The Namespace ConfigMap (If Any) From The Informer's Cache
+	configMap, err := r.namespaceSettings.ConfigMaps(channel.Namespace).Get(config.NamespaceSettingsConfigMapName)
+	if errors.IsNotFound(err) {
+		return &config.NamespaceConfig{EventingKafkaConfig: r.config}, nil
+	} else if err != nil {
+		logger.Error("Failed To Get Namespace ConfigMap", zap.Error(err))
+		return nil, err
+	}
+
+	// Merge The Namespace Settings, Falling Back To The Cluster-Wide Configuration If They're Invalid
+	namespaceConfig, err := config.MergeNamespaceConfig(r.config, configMap)
+	if err != nil {
+		logger.Warn("Ignoring Invalid Namespace ConfigMap", zap.Error(err))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.NamespaceConfigInvalid.String(), "Ignoring Invalid Namespace ConfigMap: %v", err)
+		return &config.NamespaceConfig{EventingKafkaConfig: r.config}, nil
+	}
+
+	// Surface Any Settings Which Cannot Be Overridden Per Namespace
+	if len(namespaceConfig.Conflicts) > 0 {
+		logger.Warn("Ignoring Namespace ConfigMap Settings Which Cannot Be Overridden", zap.Strings("Settings", namespaceConfig.Conflicts))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.NamespaceConfigConflict.String(),
+			"Ignoring Namespace ConfigMap Settings Which Cannot Be Overridden Per Namespace: %s", strings.Join(namespaceConfig.Conflicts, ", "))
+	}
+
+	// Return The Namespace Configuration
+	return namespaceConfig, nil
+}
+
+// Layer The Settings Of The Specified KafkaChannel's Class (If Any) Over The Configuration Of Its Namespace
+//
+// The classes are defined by the optional ConfigMap in the system namespace, which is shared with the webhook
+// applying them to the spec of new KafkaChannels.  The KafkaChannel's class is the one selected by its annotation, or
+// else the default class.  Unknown classes, invalid class settings, and settings which cannot be overridden per class,
+// are ignored and surfaced as warning events on the KafkaChannel.
+func (r *Reconciler) classConfig(ctx context.Context, channel *kafkav1beta1.KafkaChannel, namespaceConfig *config.NamespaceConfig) (*config.NamespaceConfig, error) {
+
+	// Get Channel Specific Logger
+	logger := util.ChannelLogger(r.logger, channel)
+
+	// Get The Channel Classes ConfigMap (If Any) From The Informer's Cache
+	configMap, err := r.channelClasses.ConfigMaps(system.Namespace()).Get(messagingconfig.ChannelClassesConfigName)
+	if errors.IsNotFound(err) {
+		return namespaceConfig, nil
+	} else if err != nil {
+		logger.Error("Failed To Get Channel Classes ConfigMap", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The Channel Classes, Ignoring Them If They're Invalid
+	classes, err := messagingconfig.NewChannelClassesConfigFromConfigMap(configMap)
+	if err != nil {
+		logger.Warn("Ignoring Invalid Channel Classes ConfigMap", zap.Error(err))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.ChannelClassInvalid.String(), "Ignoring Invalid Channel Classes ConfigMap: %v", err)
+		return namespaceConfig, nil
+	}
+
+	// Get The KafkaChannel's Class (If Any)
+	className := classes.ClassName(channel.Annotations)
+	if len(className) == 0 {
+		return namespaceConfig, nil
+	}
+	class := classes.GetClass(className)
+	if class == nil {
+		logger.Warn("Ignoring Unknown Channel Class", zap.String("Class", className))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.ChannelClassInvalid.String(), "Ignoring Unknown Channel Class %q", className)
+		return namespaceConfig, nil
+	}
+
+	// Merge The Class Settings, Falling Back To The Namespace Configuration If They're Invalid
+	classConfig, err := config.MergeClassConfig(namespaceConfig, class)
+	if err != nil {
+		logger.Warn("Ignoring Invalid Channel Class Settings", zap.String("Class", className), zap.Error(err))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.ChannelClassInvalid.String(), "Ignoring Invalid Settings Of Channel Class %q: %v", className, err)
+		return namespaceConfig, nil
+	}
+
+	// Surface Any Settings Which Cannot Be Overridden Per Class
+	if len(classConfig.Conflicts) > 0 {
+		logger.Warn("Ignoring Channel Class Settings Which Cannot Be Overridden", zap.String("Class", className), zap.Strings("Settings", classConfig.Conflicts))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.ChannelClassConflict.String(),
+			"Ignoring Settings Of Channel Class %q Which Cannot Be Overridden Per Class: %s", className, strings.Join(classConfig.Conflicts, ", "))
+	}
+
+	// Return The Class Configuration
+	return classConfig, nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciler's namespaceConfig() Functionality
+func TestNamespaceConfig(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name                string
+		settings            *string
+		wantReplicas        int
+		wantPartitions      int32
+		wantOverrides       string
+		wantEvent           string
+		wantClusterWideOnly bool
+	}
+
+	// Test Data
+	noConfigMap := (*string)(nil)
+	validSettings := "dispatcher:\n  replicas: 3\n  dedupe:\n    enabled: true\nkafka:\n  topic:\n    defaultNumPartitions: 12\n"
+	conflictingSettings := "receiver:\n  replicas: 3\ndispatcher:\n  images:\n    default: attacker/dispatcher\nkafka:\n  adminType: azure\n"
+	invalidSettings := "dispatcher: ["
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "No Namespace ConfigMap", settings: noConfigMap, wantClusterWideOnly: true},
+		{name: "Namespace Overrides", settings: &validSettings, wantReplicas: 3, wantPartitions: 12, wantOverrides: `{"dedupe":{"enabled":true}}`},
+		{name: "Conflicting Namespace Settings", settings: &conflictingSettings, wantEvent: "Warning NamespaceConfigConflict Ignoring Namespace ConfigMap Settings Which Cannot Be Overridden Per Namespace: dispatcher.images, kafka.adminType, receiver"},
+		{name: "Invalid Namespace Settings", settings: &invalidSettings, wantClusterWideOnly: true, wantEvent: "Warning NamespaceConfigInvalid"},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The KafkaChannel & The Namespace ConfigMap Per The TestCase
+			channel := controllertesting.NewKafkaChannel()
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if testCase.settings != nil {
+				configMap := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: config.NamespaceSettingsConfigMapName, Namespace: channel.Namespace},
+					Data:       map[string]string{config.EventingKafkaSettingsConfigKey: *testCase.settings},
+				}
+				assert.Nil(t, indexer.Add(configMap))
+			}
+
+			// Create The Reconciler
+			clusterConfig := controllertesting.NewConfig()
+			r := &Reconciler{
+				logger:            logtesting.TestLogger(t).Desugar(),
+				namespaceSettings: corev1listers.NewConfigMapLister(indexer),
+				config:            clusterConfig,
+			}
+
+			// Perform The Test
+			recorder := record.NewFakeRecorder(1)
+			namespaceConfig, err := r.namespaceConfig(controller.WithEventRecorder(context.TODO(), recorder), channel)
+
+			// Verify The Results
+			assert.Nil(t, err)
+			assert.NotNil(t, namespaceConfig)
+			if testCase.wantClusterWideOnly {
+				assert.Same(t, clusterConfig, namespaceConfig.EventingKafkaConfig)
+			}
+			if testCase.wantReplicas > 0 {
+				assert.Equal(t, testCase.wantReplicas, namespaceConfig.Dispatcher.Replicas)
+				assert.Equal(t, testCase.wantPartitions, namespaceConfig.Kafka.Topic.DefaultNumPartitions)
+			}
+			assert.Equal(t, clusterConfig.Receiver, namespaceConfig.Receiver)
+			assert.Equal(t, clusterConfig.Kafka.AdminType, namespaceConfig.Kafka.AdminType)
+			assert.Equal(t, testCase.wantOverrides, namespaceConfig.DispatcherOverrides)
+			if len(testCase.wantEvent) > 0 {
+				assert.Contains(t, <-recorder.Events, testCase.wantEvent)
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
+
+// Test The Reconciler's classConfig() Functionality
+func TestClassConfig(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name              string
+		classes           *string
+		class             string
+		wantReplicas      int
+		wantPartitions    int32
+		wantEvent         string
+		wantNamespaceOnly bool
+	}
+
+	// Test Data
+	noConfigMap := (*string)(nil)
+	validClasses := "defaultClass: bronze\nclasses:\n  gold:\n    numPartitions: 12\n    eventingKafka:\n      dispatcher:\n        replicas: 3\n  bronze:\n    numPartitions: 1\n"
+	conflictingClasses := "classes:\n  gold:\n    eventingKafka:\n      receiver:\n        replicas: 3\n"
+	invalidClasses := "classes: ["
+	invalidClassSettings := "classes:\n  gold:\n    eventingKafka:\n      dispatcher:\n        replicas: foo\n"
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "No Channel Classes ConfigMap", classes: noConfigMap, class: "gold", wantNamespaceOnly: true},
+		{name: "Selected Class", classes: &validClasses, class: "gold", wantReplicas: 3, wantPartitions: 12},
+		{name: "Default Class", classes: &validClasses, wantReplicas: 1, wantPartitions: 1},
+		{name: "No Class", classes: &conflictingClasses, wantNamespaceOnly: true},
+		{name: "Unknown Class", classes: &validClasses, class: "silver", wantNamespaceOnly: true, wantEvent: "Warning ChannelClassInvalid Ignoring Unknown Channel Class \"silver\""},
+		{name: "Conflicting Class Settings", classes: &conflictingClasses, class: "gold", wantEvent: "Warning ChannelClassConflict Ignoring Settings Of Channel Class \"gold\" Which Cannot Be Overridden Per Class: receiver"},
+		{name: "Invalid Channel Classes ConfigMap", classes: &invalidClasses, class: "gold", wantNamespaceOnly: true, wantEvent: "Warning ChannelClassInvalid Ignoring Invalid Channel Classes ConfigMap"},
+		{name: "Invalid Class Settings", classes: &invalidClassSettings, class: "gold", wantNamespaceOnly: true, wantEvent: "Warning ChannelClassInvalid Ignoring Invalid Settings Of Channel Class \"gold\""},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The KafkaChannel & The Channel Classes ConfigMap Per The TestCase
+			channel := controllertesting.NewKafkaChannel()
+			if len(testCase.class) > 0 {
+				channel.Annotations = map[string]string{messagingconfig.ChannelClassAnnotation: testCase.class}
+			}
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if testCase.classes != nil {
+				configMap := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: messagingconfig.ChannelClassesConfigName, Namespace: commonconstants.KnativeEventingNamespace},
+					Data:       map[string]string{messagingconfig.ChannelClassesKey: *testCase.classes},
+				}
+				assert.Nil(t, indexer.Add(configMap))
+			}
+
+			// Create The Reconciler
+			namespaceConfig := &config.NamespaceConfig{EventingKafkaConfig: controllertesting.NewConfig()}
+			r := &Reconciler{
+				logger:         logtesting.TestLogger(t).Desugar(),
+				channelClasses: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			// Perform The Test
+			recorder := record.NewFakeRecorder(1)
+			classConfig, err := r.classConfig(controller.WithEventRecorder(context.TODO(), recorder), channel, namespaceConfig)
+
+			// Verify The Results
+			assert.Nil(t, err)
+			assert.NotNil(t, classConfig)
+			if testCase.wantNamespaceOnly {
+				assert.Same(t, namespaceConfig, classConfig)
+			}
+			if testCase.wantReplicas > 0 {
+				assert.Equal(t, testCase.wantReplicas, classConfig.Dispatcher.Replicas)
+				assert.Equal(t, testCase.wantPartitions, classConfig.Kafka.Topic.DefaultNumPartitions)
+			}
+			assert.Equal(t, namespaceConfig.Receiver, classConfig.Receiver)
+			if len(testCase.wantEvent) > 0 {
+				assert.Contains(t, <-recorder.Events, testCase.wantEvent)
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
@@ -21,37 +21,48 @@ import (
 	"sync"
 
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 	kafkachannelv1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
-	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/identity"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/listener"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/aggregator"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/managedinformer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/settingsinformer"
 	kafkaclientsetinjection "knative.dev/eventing-kafka/pkg/client/injection/client"
 	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
 	kafkachannelreconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/kafkachannel"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
-	"knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
-	"knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/clients/dynamicclient"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/tracker"
 )
 
 // Track The Reconciler For Shutdown() Usage
 var rec *Reconciler
 
 // Create A New KafkaChannel Controller
-func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 
 	// Get A Logger
 	logger := logging.FromContext(ctx).Desugar()
 
 	// Get The Needed Informers
 	kafkachannelInformer := kafkachannel.Get(ctx)
-	deploymentInformer := deployment.Get(ctx)
-	serviceInformer := service.Get(ctx)
+	deploymentInformer := managedinformer.GetChannelDeployments(ctx)
+	serviceInformer := managedinformer.GetChannelServices(ctx)
+	namespaceSettingsInformer := settingsinformer.GetNamespaceSettings(ctx)
+	channelClassesInformer := settingsinformer.GetChannelClasses(ctx)
 
 	// Load The Environment Variables
 	environment, err := env.GetEnvironment(logger)
@@ -65,18 +76,22 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 		logger.Fatal("Failed To Load Eventing-Kafka Settings", zap.Error(err))
 	}
 
-	// Determine The Kafka AdminClient Type (Assume Kafka Unless Otherwise Specified)
-	var kafkaAdminClientType kafkaadmin.AdminClientType
-	switch configuration.Kafka.AdminType {
-	case constants.KafkaAdminTypeValueKafka:
-		kafkaAdminClientType = kafkaadmin.Kafka
-	case constants.KafkaAdminTypeValueAzure:
-		kafkaAdminClientType = kafkaadmin.EventHub
-	case constants.KafkaAdminTypeValueCustom:
-		kafkaAdminClientType = kafkaadmin.Custom
-	default:
-		logger.Warn("Encountered Unexpected Kafka AdminType - Defaulting To 'kafka'", zap.String("AdminType", configuration.Kafka.AdminType))
-		kafkaAdminClientType = kafkaadmin.Kafka
+	// Authenticate The Kafka AdminClient Via The Workload Identity If Enabled
+	err = identity.UpdateSaramaConfig(saramaConfig, configuration.Kafka.WorkloadIdentity, logger)
+	if err != nil {
+		logger.Fatal("Failed To Configure Workload Identity", zap.Error(err))
+	}
+
+	// Connect To The Kafka Brokers Through The Egress Proxy If Configured
+	err = sarama.UpdateSaramaProxy(ctx, kubeclient.Get(ctx), system.Namespace(), saramaConfig, configuration.Kafka)
+	if err != nil {
+		logger.Fatal("Failed To Configure Kafka Egress Proxy", zap.Error(err))
+	}
+
+	// Dial The Kafka Brokers At Their Rewritten Addresses (e.g. Split-Horizon DNS) If Configured
+	err = sarama.UpdateSaramaBrokerAddressRewrites(saramaConfig, configuration.Kafka)
+	if err != nil {
+		logger.Fatal("Invalid Kafka Broker Address Rewrites", zap.Error(err))
 	}
 
 	// Create A KafkaChannel Reconciler & Track As Package Variable
@@ -87,25 +102,92 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 		config:               configuration,
 		saramaConfig:         saramaConfig,
 		kafkaClientSet:       kafkaclientsetinjection.Get(ctx),
+		dynamicClient:        dynamicclient.Get(ctx),
 		kafkachannelLister:   kafkachannelInformer.Lister(),
 		kafkachannelInformer: kafkachannelInformer.Informer(),
 		deploymentLister:     deploymentInformer.Lister(),
 		serviceLister:        serviceInformer.Lister(),
-		adminClientType:      kafkaAdminClientType,
+		namespaceSettings:    namespaceSettingsInformer.Lister(),
+		channelClasses:       channelClassesInformer.Lister(),
 		adminClient:          nil,
 		adminMutex:           &sync.Mutex{},
 		configObserver:       rec.configMapObserver, // Maintains a reference so that the ConfigWatcher can call it
 	}
 
+	// Start The Audit Log (nil If Not Enabled) Recording The Changes To Kafka Topics & Deployments
+	rec.auditRecorder = audit.StartControllerLog(ctx, logger, rec.kubeClientset, configuration, saramaConfig, environment.PodName)
+
 	// Watch The Settings ConfigMap For Changes
 	err = commonconfig.InitializeConfigWatcher(ctx, logger.Sugar(), rec.configMapObserver)
 	if err != nil {
 		logger.Fatal("Failed To Initialize ConfigMap Watcher", zap.Error(err))
 	}
 
+	// Capture Heap Profiles On High Memory Watermarks If Configured In The Observability ConfigMap
+	if cmw != nil {
+		if err = commonconfig.StartHeapProfiler(ctx, logger, cmw); err != nil {
+			logger.Fatal("Failed To Start Heap Profiler", zap.Error(err))
+		}
+	}
+
+	// Validate The Network Configuration & Bind The Aggregator To The Configured IP Family (Both On Dual-Stack Nodes By Default)
+	if err = listener.ValidateNetworkConfig(configuration.Network); err != nil {
+		logger.Fatal("Invalid Network Configuration", zap.Error(err))
+	}
+	listener.SetIPFamily(configuration.Network)
+
+	// Create The Dispatcher Metrics Aggregator (nil If Not Enabled) Whose Summaries Inform The Partition & Resize Advisors
+	metricsAggregator := aggregator.NewAggregator(logger, rec.kubeClientset, configuration.MetricsAggregator)
+	rec.channelSummary = metricsAggregator.Summary
+	rec.deploymentResources = metricsAggregator.Resources
+
 	// Create A New KafkaChannel Controller Impl With The Reconciler
 	controllerImpl := kafkachannelreconciler.NewImpl(ctx, rec)
 
+	// Re-Reconcile KafkaChannels Whose Partitions Become (Or Cease To Be) A Bottleneck If The Partition Advisor Is Enabled
+	if configuration.MetricsAggregator.PartitionAdvisor.Enabled {
+		metricsAggregator.SetBottleneckHandler(func(namespace string, name string) {
+			controllerImpl.EnqueueKey(types.NamespacedName{Namespace: namespace, Name: name})
+		})
+	}
+
+	// Re-Reconcile KafkaChannels Whose Dispatcher ErrorRate Exceeds The Canary's Maximum (So Failing Class Images Are Rolled Back)
+	if configuration.Dispatcher.Images.Canary.MaxErrorRate > 0 {
+		metricsAggregator.SetErrorRateHandler(configuration.Dispatcher.Images.Canary.MaxErrorRate, func(namespace string, name string) {
+			controllerImpl.EnqueueKey(types.NamespacedName{Namespace: namespace, Name: name})
+		})
+	}
+
+	// Track The Resources Not Labelled With A Single KafkaChannel (i.e. The Shared Receivers) Which KafkaChannels Depend Upon
+	rec.tracker = tracker.New(controllerImpl.EnqueueKey, controller.GetTrackerLease(ctx))
+
+	// Re-Reconcile KafkaChannels Whose Dispatcher Or Receiver Resize Recommendations Change If The Resize Advisor Is Enabled
+	if configuration.MetricsAggregator.ResizeAdvisor.Enabled {
+		metricsAggregator.SetResizeHandler(enqueueChannelsOfDeployment(controllerImpl.EnqueueKey, rec.tracker))
+	}
+
+	// Start The Dispatcher Metrics Aggregator If Enabled
+	err = metricsAggregator.Start(ctx.Done())
+	if err != nil {
+		logger.Fatal("Failed To Start Metrics Aggregator", zap.Error(err))
+	}
+
+	// Start The Orphaned Resource Janitor If Enabled
+	rec.startJanitor(ctx)
+
+	// Start The Scale-To-Zero Idle Watcher If Enabled
+	rec.startIdleWatcher(ctx)
+
+	// Start Periodically Refreshing The Subscriber Offsets Exported To The KafkaChannels' Status If Enabled
+	rec.startOffsetExporter(ctx, func() {
+		controllerImpl.GlobalResync(kafkachannelInformer.Informer())
+	})
+
+	// Start Periodically Checking The Retention Of The Subscribers' ConsumerGroup Offsets If Enabled
+	rec.startRetentionWatcher(ctx, func() {
+		controllerImpl.GlobalResync(kafkachannelInformer.Informer())
+	})
+
 	//
 	// Configure The Informers' EventHandlers
 	//
@@ -118,6 +200,9 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	kafkachannelInformer.Informer().AddEventHandler(
 		controller.HandleAll(controllerImpl.Enqueue),
 	)
+	kafkachannelInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: rec.tracker.OnDeletedObserver,
+	})
 	serviceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.FilterControllerGVK(kafkachannelv1beta1.SchemeGroupVersion.WithKind(constants.KafkaChannelKind)),
 		Handler:    controller.HandleAll(controllerImpl.EnqueueLabelOfNamespaceScopedResource(constants.KafkaChannelNamespaceLabel, constants.KafkaChannelNameLabel)),
@@ -135,3 +220,20 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 func Shutdown() {
 	rec.ClearKafkaAdminClient()
 }
+
+// Enqueue The KafkaChannel Labelled On The Specified Deployment, Or Those Tracking It If It Is Not Labelled With A
+// Single KafkaChannel (i.e. Only The KafkaChannels Of A Shared Receiver Rather Than All Of Them)
+func enqueueChannelsOfDeployment(enqueueKey func(types.NamespacedName), channelTracker tracker.Interface) func(labels map[string]string) {
+	return func(labels map[string]string) {
+		namespace := labels[constants.KafkaChannelNamespaceLabel]
+		name := labels[constants.KafkaChannelNameLabel]
+		if len(namespace) > 0 && len(name) > 0 {
+			enqueueKey(types.NamespacedName{Namespace: namespace, Name: name})
+		} else if deploymentName := labels[constants.AppLabel]; len(deploymentName) > 0 {
+			channelTracker.OnChanged(&appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: constants.DeploymentKind},
+				ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: deploymentName},
+			})
+		}
+	}
+}
@@ -21,23 +21,31 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
 	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	commontesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	controllerenv "knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
+	_ "knative.dev/eventing-kafka/pkg/channel/distributed/controller/managedinformer/fake"  // Fake Managed Informer Injection
+	_ "knative.dev/eventing-kafka/pkg/channel/distributed/controller/settingsinformer/fake" // Fake Settings Informer Injection
 	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
 	fakeKafkaClient "knative.dev/eventing-kafka/pkg/client/injection/client/fake"
 	_ "knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel/fake" // Knative Fake Informer Injection
 	"knative.dev/pkg/client/injection/kube/client/fake"
-	_ "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment/fake" // Knative Fake Informer Injection
-	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/service/fake"    // Knative Fake Informer Injection
 	"knative.dev/pkg/injection"
+	fakedynamicclient "knative.dev/pkg/injection/clients/dynamicclient/fake"
 	"knative.dev/pkg/logging"
 	logtesting "knative.dev/pkg/logging/testing"
 	"knative.dev/pkg/system"
+	"knative.dev/pkg/tracker"
 )
 
 // Test The NewController() Functionality
@@ -62,6 +70,10 @@ func TestNewController(t *testing.T) {
 	ctx, fakeKafkaClientset := fakeKafkaClient.With(ctx)
 	assert.NotNil(t, fakeKafkaClientset)
 
+	// Add The Fake Dynamic Client To The Context (Empty)
+	ctx, fakeDynamicClient := fakedynamicclient.With(ctx, runtime.NewScheme())
+	assert.NotNil(t, fakeDynamicClient)
+
 	// Perform The Test (Create The KafkaChannel Controller)
 	controller := NewController(ctx, nil)
 
@@ -87,6 +99,43 @@ func TestShutdown(t *testing.T) {
 	assert.True(t, mockAdminClient.CloseCalled())
 }
 
+// Test The enqueueChannelsOfDeployment() Functionality
+func TestEnqueueChannelsOfDeployment(t *testing.T) {
+
+	// Record The Enqueued KafkaChannel Keys
+	var enqueued []types.NamespacedName
+	enqueueKey := func(key types.NamespacedName) { enqueued = append(enqueued, key) }
+	channelTracker := tracker.New(enqueueKey, time.Hour)
+
+	// Track The Shared Receiver Of One KafkaChannel (But Not That Of An Isolated One)
+	r := &Reconciler{logger: logtesting.TestLogger(t).Desugar(), adminClient: &controllertesting.MockAdminClient{}, config: controllertesting.NewConfig(), tracker: channelTracker}
+	sharedChannel := controllertesting.NewKafkaChannel()
+	r.trackSharedReceiver(sharedChannel)
+	isolatedChannel := controllertesting.NewKafkaChannel()
+	isolatedChannel.Name = "isolated-channel"
+	isolatedChannel.Annotations = map[string]string{kafkaconstants.ReceiverIsolationAnnotation: util.ReceiverIsolationChannel}
+	r.trackSharedReceiver(isolatedChannel)
+	sharedReceiverName := r.receiverName(sharedChannel)
+	handler := enqueueChannelsOfDeployment(enqueueKey, channelTracker)
+	assert.Len(t, enqueued, 1) // The Tracker Enqueues A KafkaChannel When It Starts Tracking
+	enqueued = nil
+
+	// A Deployment Labelled With A KafkaChannel Enqueues Only That KafkaChannel
+	handler(map[string]string{constants.KafkaChannelNamespaceLabel: "test-namespace", constants.KafkaChannelNameLabel: "test-name"})
+	assert.Equal(t, []types.NamespacedName{{Namespace: "test-namespace", Name: "test-name"}}, enqueued)
+
+	// The Shared Receiver Enqueues Only The KafkaChannels Tracking It
+	enqueued = nil
+	handler(map[string]string{constants.AppLabel: sharedReceiverName, constants.KafkaChannelReceiverLabel: "true"})
+	assert.Equal(t, []types.NamespacedName{{Namespace: sharedChannel.Namespace, Name: sharedChannel.Name}}, enqueued)
+
+	// Untracked & Unlabelled Deployments Enqueue Nothing
+	enqueued = nil
+	handler(map[string]string{constants.AppLabel: "other-receiver"})
+	handler(map[string]string{})
+	assert.Empty(t, enqueued)
+}
+
 // Utility Function For Populating Required Environment Variables For Testing
 func populateEnvironmentVariables(t *testing.T) {
 	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace))
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
@@ -28,11 +29,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
-	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/gctuning"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/health"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/receiver"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
@@ -42,12 +47,12 @@ import (
 //
 // Reconcile The Dispatcher (Kafka Consumer) For The Specified KafkaChannel
 //
-func (r *Reconciler) reconcileDispatcher(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
+func (r *Reconciler) reconcileDispatcher(ctx context.Context, channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) error {
 
 	// Get Channel Specific Logger
 	logger := util.ChannelLogger(r.logger, channel)
 
-	// Reconcile The Dispatcher's Service (For Prometheus Only)
+	// Reconcile The Dispatcher's Service (For Prometheus, And The Receiver If Combined With The Dispatcher)
 	serviceErr := r.reconcileDispatcherService(ctx, channel)
 	if serviceErr != nil {
 		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DispatcherServiceReconciliationFailed.String(), "Failed To Reconcile Dispatcher Service: %v", serviceErr)
@@ -57,7 +62,7 @@ func (r *Reconciler) reconcileDispatcher(ctx context.Context, channel *kafkav1be
 	}
 
 	// Reconcile The Dispatcher's Deployment
-	deploymentErr := r.reconcileDispatcherDeployment(ctx, channel)
+	deploymentErr := r.reconcileDispatcherDeployment(ctx, channel, configuration)
 	if deploymentErr != nil {
 		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Reconcile Dispatcher Deployment: %v", deploymentErr)
 		logger.Error("Failed To Reconcile Dispatcher Deployment", zap.Error(deploymentErr))
@@ -65,23 +70,94 @@ func (r *Reconciler) reconcileDispatcher(ctx context.Context, channel *kafkav1be
 		logger.Info("Successfully Reconciled Dispatcher Deployment")
 	}
 
+	// Reflect The Dispatcher's Service & Deployment In The Channel's Receiver Status If The Receiver Is Combined With It
+	if r.isReceiverCombined(channel) {
+		if serviceErr != nil {
+			channel.Status.MarkServiceFailed(event.DispatcherServiceReconciliationFailed.String(), "Failed To Reconcile Combined Dispatcher Service: %v", serviceErr)
+		} else {
+			channel.Status.MarkServiceTrue()
+		}
+		if deploymentErr != nil {
+			channel.Status.MarkEndpointsFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Reconcile Combined Dispatcher Deployment: %v", deploymentErr)
+		} else {
+			channel.Status.MarkEndpointsTrue()
+		}
+	}
+
+	// Remove The Dispatcher Left Over Under A Former NameStrategy Once Its Replacement Has Been Reconciled
+	var migrationErr error
+	if serviceErr == nil && deploymentErr == nil {
+		migrationErr = r.deleteFormerDispatcher(ctx, channel)
+		if migrationErr != nil {
+			controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DispatcherMigrationFailed.String(), "Failed To Delete Former Dispatcher: %v", migrationErr)
+			logger.Error("Failed To Delete Former Dispatcher", zap.Error(migrationErr))
+		}
+	}
+
 	// Return Results
-	if serviceErr != nil || deploymentErr != nil {
+	if serviceErr != nil || deploymentErr != nil || migrationErr != nil {
 		return fmt.Errorf("failed to reconcile dispatcher resources")
 	} else {
 		return nil
 	}
 }
 
+// Get The Name Of The Dispatcher Service & Deployment Of The Specified Channel Using The Configured NameStrategy
+func (r *Reconciler) dispatcherName(channel *kafkav1beta1.KafkaChannel) string {
+	return util.DispatcherDnsSafeName(channel, r.config.Naming.Strategy)
+}
+
+//
+// Dispatcher Migration (NameStrategy Changes)
+//
+
+// Delete The Dispatcher Service & Deployment Of The Specified Channel Named By Any Other NameStrategy Than The
+// Configured One (Only Those Labelled As The Channel's Dispatcher, In Case Of A Name Collision With Another Channel)
+func (r *Reconciler) deleteFormerDispatcher(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
+	for _, formerName := range util.FormerDispatcherDnsSafeNames(channel, r.config.Naming.Strategy) {
+
+		service, err := r.serviceLister.Services(system.Namespace()).Get(formerName)
+		if err == nil && isChannelDispatcher(service.Labels, channel) {
+			err = r.kubeClientset.CoreV1().Services(service.Namespace).Delete(ctx, service.Name, metav1.DeleteOptions{})
+			if err == nil {
+				r.logger.Info("Deleted Former Dispatcher Service", zap.String("Service", service.Name))
+			}
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+
+		deployment, err := r.deploymentLister.Deployments(system.Namespace()).Get(formerName)
+		if err == nil && isChannelDispatcher(deployment.Labels, channel) {
+			err = r.kubeClientset.AppsV1().Deployments(deployment.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
+			if err == nil {
+				r.logger.Info("Deleted Former Dispatcher Deployment", zap.String("Deployment", deployment.Name))
+				r.auditDeployment(audit.ActionDeleted, deployment.Name, channel, map[string]string{"reason": "NameStrategy changed"})
+			}
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Determine Whether The Specified Labels Identify A Resource As The Dispatcher Of The Specified Channel
+func isChannelDispatcher(labels map[string]string, channel *kafkav1beta1.KafkaChannel) bool {
+	return labels[constants.KafkaChannelDispatcherLabel] == "true" &&
+		labels[constants.KafkaChannelNameLabel] == channel.Name &&
+		labels[constants.KafkaChannelNamespaceLabel] == channel.Namespace
+}
+
 //
-// Dispatcher Service (For Prometheus Only)
+// Dispatcher Service (For Prometheus, And The Receiver If Combined With The Dispatcher)
 //
 
 // Reconcile The Dispatcher Service
 func (r *Reconciler) reconcileDispatcherService(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
 
 	// Attempt To Get The Dispatcher Service Associated With The Specified Channel
-	_, err := r.getDispatcherService(channel)
+	service, err := r.getDispatcherService(channel)
 	if err != nil {
 
 		// If The Service Was Not Found - Then Create A New One For The Channel
@@ -101,19 +177,55 @@ func (r *Reconciler) reconcileDispatcherService(ctx context.Context, channel *ka
 			return err
 		}
 	} else {
+
+		// Update The Ports Of The Existing Service If The Receiver Was Combined With / Separated From The Dispatcher
+		err = r.updateDispatcherServicePorts(ctx, channel, service)
+		if err != nil {
+			return err
+		}
 		r.logger.Info("Successfully Verified Dispatcher Service")
 		return nil
 	}
 }
 
+// Update The Ports Of The Specified Dispatcher Service If They Differ From Those Of The Channel's Dispatcher Service
+func (r *Reconciler) updateDispatcherServicePorts(ctx context.Context, channel *kafkav1beta1.KafkaChannel, service *corev1.Service) error {
+
+	// Nothing To Do If The Service Has The Expected (Named) Ports
+	expectedPorts := r.newDispatcherService(channel).Spec.Ports
+	if servicePortNames(service.Spec.Ports) == servicePortNames(expectedPorts) {
+		return nil
+	}
+
+	// Update The Ports Of A Copy Of The Service
+	updatedService := service.DeepCopy()
+	updatedService.Spec.Ports = expectedPorts
+	_, err := r.kubeClientset.CoreV1().Services(updatedService.Namespace).Update(ctx, updatedService, metav1.UpdateOptions{})
+	if err != nil {
+		r.logger.Error("Failed To Update Dispatcher Service Ports", zap.Error(err))
+		return err
+	}
+	r.logger.Info("Successfully Updated Dispatcher Service Ports", zap.String("Ports", servicePortNames(expectedPorts)))
+	return nil
+}
+
+// Get The Comma Separated Names Of The Specified Service Ports
+func servicePortNames(ports []corev1.ServicePort) string {
+	names := make([]string, len(ports))
+	for index, port := range ports {
+		names[index] = port.Name
+	}
+	return strings.Join(names, ",")
+}
+
 // Get The Dispatcher Service Associated With The Specified Channel
 func (r *Reconciler) getDispatcherService(channel *kafkav1beta1.KafkaChannel) (*corev1.Service, error) {
 
 	// Get The Dispatcher Service Name
-	serviceName := util.DispatcherDnsSafeName(channel)
+	serviceName := r.dispatcherName(channel)
 
 	// Get The Service By Namespace / Name
-	service, err := r.serviceLister.Services(commonconstants.KnativeEventingNamespace).Get(serviceName)
+	service, err := r.serviceLister.Services(system.Namespace()).Get(serviceName)
 
 	// Return The Results
 	return service, err
@@ -123,7 +235,29 @@ func (r *Reconciler) getDispatcherService(channel *kafkav1beta1.KafkaChannel) (*
 func (r *Reconciler) newDispatcherService(channel *kafkav1beta1.KafkaChannel) *corev1.Service {
 
 	// Get The Dispatcher Service Name For The Channel
-	serviceName := util.DispatcherDnsSafeName(channel)
+	serviceName := r.dispatcherName(channel)
+
+	// Create The Service Ports (Including The Receiver's If Combined With The Dispatcher)
+	ports := []corev1.ServicePort{
+		{
+			Name:       constants.MetricsPortName,
+			Port:       int32(r.environment.MetricsPort),
+			TargetPort: intstr.FromInt(r.environment.MetricsPort),
+		},
+	}
+	if r.isReceiverCombined(channel) {
+		ports = append(ports,
+			corev1.ServicePort{
+				Name:       constants.HttpPortName,
+				Port:       constants.HttpServicePortNumber,
+				TargetPort: intstr.FromInt(constants.HttpContainerPortNumber),
+			},
+			corev1.ServicePort{
+				Name:       constants.CombinedReceiverMetricsPortName,
+				Port:       constants.CombinedReceiverMetricsPort,
+				TargetPort: intstr.FromInt(constants.CombinedReceiverMetricsPort),
+			})
+	}
 
 	// Create & Return The Service Model
 	return &corev1.Service{
@@ -133,7 +267,7 @@ func (r *Reconciler) newDispatcherService(channel *kafkav1beta1.KafkaChannel) *c
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      serviceName,
-			Namespace: commonconstants.KnativeEventingNamespace,
+			Namespace: system.Namespace(),
 			Labels: map[string]string{
 				constants.KafkaChannelDispatcherLabel:   "true",                                  // Identifies the Service as being a KafkaChannel "Dispatcher"
 				constants.KafkaChannelNameLabel:         channel.Name,                            // Identifies the Service's Owning KafkaChannel's Name
@@ -145,13 +279,7 @@ func (r *Reconciler) newDispatcherService(channel *kafkav1beta1.KafkaChannel) *c
 			},
 		},
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
-				{
-					Name:       constants.MetricsPortName,
-					Port:       int32(r.environment.MetricsPort),
-					TargetPort: intstr.FromInt(r.environment.MetricsPort),
-				},
-			},
+			Ports: ports,
 			Selector: map[string]string{
 				constants.AppLabel: serviceName, // Matches Deployment Label Key/Value
 			},
@@ -164,7 +292,7 @@ func (r *Reconciler) newDispatcherService(channel *kafkav1beta1.KafkaChannel) *c
 //
 
 // Reconcile The Dispatcher Deployment
-func (r *Reconciler) reconcileDispatcherDeployment(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
+func (r *Reconciler) reconcileDispatcherDeployment(ctx context.Context, channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) error {
 
 	// Attempt To Get The Dispatcher Deployment Associated With The Specified Channel
 	deployment, err := r.getDispatcherDeployment(channel)
@@ -175,7 +303,7 @@ func (r *Reconciler) reconcileDispatcherDeployment(ctx context.Context, channel
 
 			// Then Create The New Deployment
 			r.logger.Info("Dispatcher Deployment Not Found - Creating New One")
-			deployment, err = r.newDispatcherDeployment(channel)
+			deployment, err = r.newDispatcherDeployment(channel, configuration)
 			if err != nil {
 				r.logger.Error("Failed To Create Dispatcher Deployment YAML", zap.Error(err))
 				channel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Generate Dispatcher Deployment: %v", err)
@@ -188,6 +316,7 @@ func (r *Reconciler) reconcileDispatcherDeployment(ctx context.Context, channel
 					return err
 				} else {
 					r.logger.Info("Successfully Created Dispatcher Deployment")
+					r.auditDeployment(audit.ActionCreated, deployment.Name, channel, map[string]string{"image": deployment.Spec.Template.Spec.Containers[0].Image})
 					channel.Status.PropagateDispatcherStatus(&deployment.Status)
 					return nil
 				}
@@ -199,6 +328,24 @@ func (r *Reconciler) reconcileDispatcherDeployment(ctx context.Context, channel
 			return err
 		}
 	} else {
+
+		// Update The Image & Node Architecture Of The Existing Deployment If Their Resolution Changed (e.g. Canary Rollout)
+		deployment, err = r.updateDispatcherDeploymentImage(ctx, channel, configuration, deployment)
+		if err != nil {
+			channel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Update Dispatcher Deployment Image: %v", err)
+			return err
+		}
+
+		// Add / Remove The Receiver Container Of The Existing Deployment If The Receiver Was Combined With / Separated From It
+		deployment, err = r.updateDispatcherDeploymentReceiver(ctx, channel, configuration, deployment)
+		if err != nil {
+			channel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Update Dispatcher Deployment Receiver: %v", err)
+			return err
+		}
+
+		// Activate The Existing Deployment If Scaled To Zero While Idle And Its Subscribers Changed (Or It's No Longer Eligible)
+		deployment = r.reconcileIdleDispatcher(ctx, channel, deployment)
+
 		// Successfully Verified Dispatcher Deployment
 		r.logger.Info("Successfully Verified Dispatcher Deployment")
 		channel.Status.PropagateDispatcherStatus(&deployment.Status)
@@ -206,35 +353,192 @@ func (r *Reconciler) reconcileDispatcherDeployment(ctx context.Context, channel
 	}
 }
 
+// Update The Image & Node Selector Of The Specified Dispatcher Deployment If They Differ From The Resolved Ones
+//
+// A class (e.g. canary) image which failed to roll out within the Deployment's progress deadline, or whose events
+// failed at more than the canary's maximum error rate once rolled out, is rolled back to the default image, and
+// recorded on the Deployment so that it is not rolled out again.
+func (r *Reconciler) updateDispatcherDeploymentImage(ctx context.Context, channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+
+	// Nothing To Do Without A Container
+	podSpec := deployment.Spec.Template.Spec
+	if len(podSpec.Containers) == 0 {
+		return deployment, nil
+	}
+
+	// Resolve The Expected Dispatcher Deployment
+	expectedDeployment, err := r.newDispatcherDeployment(channel, configuration)
+	if err != nil {
+		r.logger.Error("Failed To Create Dispatcher Deployment YAML", zap.Error(err))
+		return nil, err
+	}
+	expectedPodSpec := expectedDeployment.Spec.Template.Spec
+	expectedImage := expectedPodSpec.Containers[0].Image
+
+	// Resolve The Default (Class-less) Image Of The Same Architecture To Roll Back To
+	defaultImage, _, err := util.ResolveImage(configuration.Dispatcher.Images, r.environment.DispatcherImage, "", expectedPodSpec.NodeSelector[corev1.LabelArchStable])
+	if err != nil {
+		r.logger.Error("Failed To Resolve Dispatcher Image", zap.Error(err))
+		return nil, err
+	}
+
+	// Keep A Previously Rolled Back Image From Rolling Out Again, Or Roll Back A Failed Rollout Of A Class Image
+	currentImage := podSpec.Containers[0].Image
+	rolledBackImage := deployment.Annotations[constants.RolledBackImageAnnotation]
+	if expectedImage != defaultImage {
+		if expectedImage == rolledBackImage {
+			expectedImage = defaultImage
+		} else if currentImage == expectedImage && util.DeploymentProgressDeadlineExceeded(deployment) {
+			r.logger.Warn("Dispatcher Image Failed To Roll Out - Rolling Back", zap.String("Image", expectedImage), zap.String("DefaultImage", defaultImage))
+			rolledBackImage = expectedImage
+			expectedImage = defaultImage
+		} else if currentImage == expectedImage && r.dispatcherErrorRateExceeded(channel, configuration.Dispatcher.Images.Canary, deployment) {
+			r.logger.Warn("Dispatcher Image Exceeded The Canary's Maximum Error Rate - Rolling Back", zap.String("Image", expectedImage), zap.String("DefaultImage", defaultImage))
+			rolledBackImage = expectedImage
+			expectedImage = defaultImage
+		}
+	}
+
+	// Nothing To Do If The Image, Node Selector & Rolled Back Image Are Up To Date
+	if currentImage == expectedImage &&
+		podSpec.NodeSelector[corev1.LabelArchStable] == expectedPodSpec.NodeSelector[corev1.LabelArchStable] &&
+		deployment.Annotations[constants.RolledBackImageAnnotation] == rolledBackImage {
+		return deployment, nil
+	}
+
+	// Update The Image & Node Architecture Of A Copy Of The Deployment (Leaving Any Other Node Selectors As-Is)
+	updatedDeployment := deployment.DeepCopy()
+	updatedPodSpec := &updatedDeployment.Spec.Template.Spec
+	updatedPodSpec.Containers[0].Image = expectedImage
+	if architecture, ok := expectedPodSpec.NodeSelector[corev1.LabelArchStable]; ok {
+		if updatedPodSpec.NodeSelector == nil {
+			updatedPodSpec.NodeSelector = map[string]string{}
+		}
+		updatedPodSpec.NodeSelector[corev1.LabelArchStable] = architecture
+	} else {
+		delete(updatedPodSpec.NodeSelector, corev1.LabelArchStable)
+	}
+	if rolledBackImage != "" {
+		if updatedDeployment.Annotations == nil {
+			updatedDeployment.Annotations = map[string]string{}
+		}
+		updatedDeployment.Annotations[constants.RolledBackImageAnnotation] = rolledBackImage
+	}
+
+	updatedDeployment, err = r.kubeClientset.AppsV1().Deployments(updatedDeployment.Namespace).Update(ctx, updatedDeployment, metav1.UpdateOptions{})
+	if err != nil {
+		r.logger.Error("Failed To Update Dispatcher Deployment Image", zap.Error(err))
+		return nil, err
+	}
+	r.logger.Info("Successfully Updated Dispatcher Deployment Image", zap.String("Image", expectedImage))
+	r.auditDeployment(audit.ActionUpdated, updatedDeployment.Name, channel, map[string]string{"image": expectedImage, "rolledBackImage": rolledBackImage})
+	return updatedDeployment, nil
+}
+
+// Determine Whether The Events Dispatched Since The Latest Rollout Of The Specified Dispatcher Deployment Failed At
+// More Than The Canary's Maximum Error Rate (Per The Metrics Aggregator's Summary Of The Channel, Which Must Have Been
+// Scraped After The Rollout Completed So That It Only Reflects The Pods Of The Rolled Out Image)
+func (r *Reconciler) dispatcherErrorRateExceeded(channel *kafkav1beta1.KafkaChannel, canaryConfig config.EKCanaryConfig, deployment *appsv1.Deployment) bool {
+	if canaryConfig.MaxErrorRate <= 0 || r.channelSummary == nil {
+		return false
+	}
+	rolloutCompleted, ok := util.DeploymentRolloutCompleted(deployment)
+	if !ok {
+		return false
+	}
+	summary := r.channelSummary(channel.Namespace, channel.Name)
+	if summary == nil || !summary.ScrapedAt.After(rolloutCompleted) {
+		return false
+	}
+	return util.CanaryErrorRateExceeded(canaryConfig, summary.ErrorRate, summary.DispatchedEvents)
+}
+
+// Add Or Remove The Combined Receiver Container Of The Specified Dispatcher Deployment So That It Matches The Channel's
+// Receiver Isolation (Only The Presence Of The Container Is Reconciled, Not Its Spec)
+func (r *Reconciler) updateDispatcherDeploymentReceiver(ctx context.Context, channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+
+	// Nothing To Do If The Deployment Runs The Receiver Exactly When Combined
+	podSpec := deployment.Spec.Template.Spec
+	if hasCombinedReceiver(podSpec) == r.isReceiverCombined(channel) || len(podSpec.Containers) == 0 {
+		return deployment, nil
+	}
+
+	// Resolve The Expected Dispatcher Deployment
+	expectedDeployment, err := r.newDispatcherDeployment(channel, configuration)
+	if err != nil {
+		r.logger.Error("Failed To Create Dispatcher Deployment YAML", zap.Error(err))
+		return nil, err
+	}
+	expectedPodSpec := expectedDeployment.Spec.Template.Spec
+
+	// Replace The Containers Following The Dispatcher's & The Termination Grace Period Of A Copy Of The Deployment
+	updatedDeployment := deployment.DeepCopy()
+	updatedPodSpec := &updatedDeployment.Spec.Template.Spec
+	updatedPodSpec.Containers = append(updatedPodSpec.Containers[:1], expectedPodSpec.Containers[1:]...)
+	updatedPodSpec.TerminationGracePeriodSeconds = expectedPodSpec.TerminationGracePeriodSeconds
+
+	updatedDeployment, err = r.kubeClientset.AppsV1().Deployments(updatedDeployment.Namespace).Update(ctx, updatedDeployment, metav1.UpdateOptions{})
+	if err != nil {
+		r.logger.Error("Failed To Update Dispatcher Deployment Receiver", zap.Error(err))
+		return nil, err
+	}
+	r.logger.Info("Successfully Updated Dispatcher Deployment Receiver", zap.Bool("Combined", r.isReceiverCombined(channel)))
+	r.auditDeployment(audit.ActionUpdated, updatedDeployment.Name, channel, map[string]string{"combinedReceiver": strconv.FormatBool(r.isReceiverCombined(channel))})
+	return updatedDeployment, nil
+}
+
+// Determine Whether The Specified Dispatcher Pod Spec Runs A Combined Receiver Container
+func hasCombinedReceiver(podSpec corev1.PodSpec) bool {
+	for _, container := range podSpec.Containers {
+		if container.Name == constants.ReceiverContainerName {
+			return true
+		}
+	}
+	return false
+}
+
 // Get The Dispatcher Deployment Associated With The Specified Channel
 func (r *Reconciler) getDispatcherDeployment(channel *kafkav1beta1.KafkaChannel) (*appsv1.Deployment, error) {
 
 	// Get The Dispatcher Deployment Name For The Channel
-	deploymentName := util.DispatcherDnsSafeName(channel)
+	deploymentName := r.dispatcherName(channel)
 
 	// Get The Dispatcher Deployment By Namespace / Name
-	deployment, err := r.deploymentLister.Deployments(commonconstants.KnativeEventingNamespace).Get(deploymentName)
+	deployment, err := r.deploymentLister.Deployments(system.Namespace()).Get(deploymentName)
 
 	// Return The Results
 	return deployment, err
 }
 
 // Create Dispatcher Deployment Model For The Specified Channel
-func (r *Reconciler) newDispatcherDeployment(channel *kafkav1beta1.KafkaChannel) (*appsv1.Deployment, error) {
+func (r *Reconciler) newDispatcherDeployment(channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) (*appsv1.Deployment, error) {
 
 	// Get The Dispatcher Deployment Name For The Channel
-	deploymentName := util.DispatcherDnsSafeName(channel)
+	deploymentName := r.dispatcherName(channel)
 
 	// Replicas Int Value For De-Referencing
-	replicas := int32(r.config.Dispatcher.Replicas)
+	replicas := int32(configuration.Dispatcher.Replicas)
 
 	// Create The Dispatcher Container Environment Variables
-	envVars, err := r.dispatcherDeploymentEnvVars(channel)
+	envVars, err := r.dispatcherDeploymentEnvVars(channel, configuration)
 	if err != nil {
 		r.logger.Error("Failed To Create Dispatcher Deployment Environment Variables", zap.Error(err))
 		return nil, err
 	}
 
+	// Resolve The Dispatcher Image & Node Architecture From The Channel's Annotations / Canary Selector
+	imageClass, err := util.DispatcherImageClass(configuration.Dispatcher.Images, channel)
+	if err != nil {
+		r.logger.Error("Failed To Resolve Dispatcher Image Class", zap.Error(err))
+		return nil, err
+	}
+	image, architecture, err := util.ResolveImage(configuration.Dispatcher.Images, r.environment.DispatcherImage,
+		imageClass, channel.Annotations[kafkaconstants.ArchitectureAnnotation])
+	if err != nil {
+		r.logger.Error("Failed To Resolve Dispatcher Image", zap.Error(err))
+		return nil, err
+	}
+
 	// Create The Dispatcher's Deployment
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -243,7 +547,7 @@ func (r *Reconciler) newDispatcherDeployment(channel *kafkav1beta1.KafkaChannel)
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      deploymentName,
-			Namespace: commonconstants.KnativeEventingNamespace,
+			Namespace: system.Namespace(),
 			Labels: map[string]string{
 				constants.AppLabel:                    deploymentName,    // Matches K8S Service Selector Key/Value Below
 				constants.KafkaChannelDispatcherLabel: "true",            // Identifies the Deployment as being a KafkaChannel "Dispatcher"
@@ -266,9 +570,13 @@ func (r *Reconciler) newDispatcherDeployment(channel *kafkav1beta1.KafkaChannel)
 					Labels: map[string]string{
 						constants.AppLabel: deploymentName, // Matched By Deployment Selector Above
 					},
+					Annotations: util.SeccompPodAnnotations(configuration.Dispatcher.EKKubernetesConfig),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: r.environment.ServiceAccount,
+					SecurityContext:    util.PodSecurityContext(configuration.Dispatcher.EKKubernetesConfig),
+					NodeSelector:       util.ArchitectureNodeSelector(architecture),
+					Volumes:            append(append(util.WorkloadIdentityVolumes(configuration.Kafka.WorkloadIdentity), util.EncryptionKeysVolumes(configuration.Kafka.Encryption)...), util.MiddlewareVolumes(configuration.Middleware)...),
 					Containers: []corev1.Container{
 						{
 							Name: deploymentName,
@@ -292,17 +600,19 @@ func (r *Reconciler) newDispatcherDeployment(channel *kafkav1beta1.KafkaChannel)
 								InitialDelaySeconds: constants.DispatcherReadinessDelay,
 								PeriodSeconds:       constants.DispatcherReadinessPeriod,
 							},
-							Image:           r.environment.DispatcherImage,
+							Image:           image,
 							Env:             envVars,
 							ImagePullPolicy: corev1.PullIfNotPresent,
+							SecurityContext: util.ContainerSecurityContext(configuration.Dispatcher.EKKubernetesConfig),
+							VolumeMounts:    append(append(util.WorkloadIdentityVolumeMounts(configuration.Kafka.WorkloadIdentity), util.EncryptionKeysVolumeMounts(configuration.Kafka.Encryption)...), util.MiddlewareVolumeMounts(configuration.Middleware)...),
 							Resources: corev1.ResourceRequirements{
 								Limits: corev1.ResourceList{
-									corev1.ResourceMemory: r.config.Dispatcher.MemoryLimit,
-									corev1.ResourceCPU:    r.config.Dispatcher.CpuLimit,
+									corev1.ResourceMemory: configuration.Dispatcher.MemoryLimit,
+									corev1.ResourceCPU:    configuration.Dispatcher.CpuLimit,
 								},
 								Requests: corev1.ResourceList{
-									corev1.ResourceMemory: r.config.Dispatcher.MemoryRequest,
-									corev1.ResourceCPU:    r.config.Dispatcher.CpuRequest,
+									corev1.ResourceMemory: configuration.Dispatcher.MemoryRequest,
+									corev1.ResourceCPU:    configuration.Dispatcher.CpuRequest,
 								},
 							},
 						},
@@ -312,12 +622,25 @@ func (r *Reconciler) newDispatcherDeployment(channel *kafkav1beta1.KafkaChannel)
 		},
 	}
 
+	// Run The Channel's Receiver In The Dispatcher's Pod If Combined (Allowing For The Receiver's Graceful Shutdown)
+	if r.isReceiverCombined(channel) {
+		combinedReceiver := receiver.Receiver{Name: deploymentName, KafkaSecretName: r.kafkaSecretName(channel)}
+		receiverContainer, err := receiver.NewCombinedContainer(combinedReceiver, architecture, r.config, r.environment)
+		if err != nil {
+			r.logger.Error("Failed To Create Combined Receiver Container", zap.Error(err))
+			return nil, err
+		}
+		podSpec := &deployment.Spec.Template.Spec
+		podSpec.Containers = append(podSpec.Containers, receiverContainer)
+		podSpec.TerminationGracePeriodSeconds = receiver.TerminationGracePeriodSeconds(r.config.Receiver.Shutdown)
+	}
+
 	// Return The Dispatcher's Deployment
 	return deployment, nil
 }
 
 // Create The Dispatcher Container's Env Vars
-func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChannel) ([]corev1.EnvVar, error) {
+func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) ([]corev1.EnvVar, error) {
 
 	// Get The TopicName For Specified Channel
 	topicName := util.TopicName(channel)
@@ -326,7 +649,7 @@ func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChan
 	envVars := []corev1.EnvVar{
 		{
 			Name:  system.NamespaceEnvKey,
-			Value: commonconstants.KnativeEventingNamespace,
+			Value: system.Namespace(),
 		},
 		{
 			Name: commonenv.PodNameEnvVarKey,
@@ -362,7 +685,7 @@ func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChan
 		},
 		{
 			Name:  commonenv.ServiceNameEnvVarKey,
-			Value: util.DispatcherDnsSafeName(channel),
+			Value: r.dispatcherName(channel),
 		},
 		{
 			Name:  commonenv.KafkaTopicEnvVarKey,
@@ -381,37 +704,23 @@ func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChan
 
 	} else {
 
-		// Append The Kafka Brokers As Env Var
-		envVars = append(envVars, corev1.EnvVar{
-			Name: commonenv.KafkaBrokerEnvVarKey,
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{Name: kafkaSecret},
-					Key:                  constants.KafkaSecretDataKeyBrokers,
-				},
-			},
-		})
+		// Append The Kafka Brokers / Username / Password (Or KafkaAuthSpec Brokers) As Env Vars
+		envVars = append(envVars, util.KafkaSecretEnvVars(kafkaSecret, configuration.Kafka.AuthSpec)...)
+	}
 
-		// Append The Kafka Username As Env Var
-		envVars = append(envVars, corev1.EnvVar{
-			Name: commonenv.KafkaUsernameEnvVarKey,
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{Name: kafkaSecret},
-					Key:                  constants.KafkaSecretDataKeyUsername,
-				},
-			},
-		})
+	// Append The Remote Kafka Cluster Of Any Replication Of The KafkaChannel's Topic As Env Vars
+	if replicationSecret := channel.Annotations[kafkaconstants.ReplicationSecretAnnotation]; len(replicationSecret) > 0 {
+		envVars = append(envVars, util.ReplicationSecretEnvVars(replicationSecret)...)
+	}
 
-		// Append The Kafka Password As Env Var
+	// Append The Go Runtime's Garbage Collection Tuning (GOGC / GOMEMLIMIT) As Env Vars
+	envVars = append(envVars, gctuning.EnvVars(configuration.Dispatcher.Runtime)...)
+
+	// Append Any Namespace Overrides Of The Dispatcher's (Data Plane) Configuration As Env Var
+	if len(configuration.DispatcherOverrides) > 0 {
 		envVars = append(envVars, corev1.EnvVar{
-			Name: commonenv.KafkaPasswordEnvVarKey,
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{Name: kafkaSecret},
-					Key:                  constants.KafkaSecretDataKeyPassword,
-				},
-			},
+			Name:  commonenv.DispatcherConfigOverridesEnvVarKey,
+			Value: configuration.DispatcherOverrides,
 		})
 	}
 
@@ -0,0 +1,296 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/gctuning"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/aggregator"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/receiver"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciler's deleteFormerDispatcher() Functionality
+func TestDeleteFormerDispatcher(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name          string
+		strategy      string
+		otherChannel  bool
+		expectDeleted bool
+	}
+
+	// Create The TestCases (The Existing Dispatcher Is Named By The "truncate" Strategy)
+	testCases := []TestCase{
+		{name: "Unchanged Strategy", strategy: util.NameStrategyTruncate},
+		{name: "Default Strategy", strategy: ""},
+		{name: "Changed Strategy", strategy: util.NameStrategyHash, expectDeleted: true},
+		{name: "Changed Strategy Other Channel's Dispatcher", strategy: util.NameStrategyHash, otherChannel: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Existing Dispatcher Service & Deployment
+			service := controllertesting.NewKafkaChannelDispatcherService()
+			deployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+			if testCase.otherChannel {
+				service.Labels[constants.KafkaChannelNameLabel] = "other-channel"
+				deployment.Labels[constants.KafkaChannelNameLabel] = "other-channel"
+			}
+			serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			assert.Nil(t, serviceIndexer.Add(service))
+			deploymentIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			assert.Nil(t, deploymentIndexer.Add(deployment))
+
+			// Create The Reconciler With The TestCase's NameStrategy
+			configuration := controllertesting.NewConfig()
+			configuration.Naming.Strategy = testCase.strategy
+			kubeClientset := fake.NewSimpleClientset([]runtime.Object{service, deployment}...)
+			r := &Reconciler{
+				logger:           logtesting.TestLogger(t).Desugar(),
+				kubeClientset:    kubeClientset,
+				config:           configuration,
+				serviceLister:    corev1listers.NewServiceLister(serviceIndexer),
+				deploymentLister: appsv1listers.NewDeploymentLister(deploymentIndexer),
+			}
+
+			// Perform The Test
+			err := r.deleteFormerDispatcher(context.TODO(), controllertesting.NewKafkaChannel())
+
+			// Verify The Results
+			assert.Nil(t, err)
+			_, serviceErr := kubeClientset.CoreV1().Services(commonconstants.KnativeEventingNamespace).Get(context.TODO(), service.Name, metav1.GetOptions{})
+			_, deploymentErr := kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), deployment.Name, metav1.GetOptions{})
+			assert.Equal(t, testCase.expectDeleted, serviceErr != nil)
+			assert.Equal(t, testCase.expectDeleted, deploymentErr != nil)
+		})
+	}
+}
+
+// Test The Reconciler's Combining Of The Receiver With The Dispatcher Of A Channel
+func TestCombinedReceiver(t *testing.T) {
+
+	// Create The Existing (Separated) Dispatcher Service & Deployment
+	channel := controllertesting.NewKafkaChannel()
+	service := controllertesting.NewKafkaChannelDispatcherService()
+	deployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+
+	// Create The Reconciler With The Combined Receiver Isolation
+	configuration := controllertesting.NewConfig()
+	configuration.Receiver.Isolation = util.ReceiverIsolationCombined
+	namespaceConfig := &config.NamespaceConfig{EventingKafkaConfig: configuration}
+	kubeClientset := fake.NewSimpleClientset([]runtime.Object{service, deployment}...)
+	r := &Reconciler{
+		logger:        logtesting.TestLogger(t).Desugar(),
+		kubeClientset: kubeClientset,
+		adminClient:   &controllertesting.MockAdminClient{},
+		environment:   controllertesting.NewEnvironment(),
+		config:        configuration,
+	}
+
+	// Verify The Channel's Receiver Is The Dispatcher
+	assert.Equal(t, r.dispatcherName(channel), r.receiverName(channel))
+
+	// Verify The Receiver's Ports Are Added To The Dispatcher Service
+	err := r.updateDispatcherServicePorts(context.TODO(), channel, service)
+	assert.Nil(t, err)
+	updatedService, err := kubeClientset.CoreV1().Services(service.Namespace).Get(context.TODO(), service.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "metrics,http,receiver-metrics", servicePortNames(updatedService.Spec.Ports))
+
+	// Verify The Receiver Container (On Alternate Ports) Is Added To The Dispatcher Deployment
+	updatedDeployment, err := r.updateDispatcherDeploymentReceiver(context.TODO(), channel, namespaceConfig, deployment)
+	assert.Nil(t, err)
+	containers := updatedDeployment.Spec.Template.Spec.Containers
+	assert.Len(t, containers, 2)
+	assert.Equal(t, deployment.Spec.Template.Spec.Containers[0], containers[0])
+	assert.Equal(t, constants.ReceiverContainerName, containers[1].Name)
+	assert.Equal(t, intstr.FromInt(constants.CombinedReceiverHealthPort), containers[1].ReadinessProbe.HTTPGet.Port)
+	assert.Equal(t, receiver.TerminationGracePeriodSeconds(configuration.Receiver.Shutdown), updatedDeployment.Spec.Template.Spec.TerminationGracePeriodSeconds)
+
+	// Verify An Up To Date Deployment Is Not Updated Again
+	unchangedDeployment, err := r.updateDispatcherDeploymentReceiver(context.TODO(), channel, namespaceConfig, updatedDeployment)
+	assert.Nil(t, err)
+	assert.Same(t, updatedDeployment, unchangedDeployment)
+
+	// Verify Separating The Receiver Removes Its Container
+	configuration.Receiver.Isolation = util.ReceiverIsolationSecret
+	separatedDeployment, err := r.updateDispatcherDeploymentReceiver(context.TODO(), channel, namespaceConfig, updatedDeployment)
+	assert.Nil(t, err)
+	assert.Len(t, separatedDeployment.Spec.Template.Spec.Containers, 1)
+	assert.Nil(t, separatedDeployment.Spec.Template.Spec.TerminationGracePeriodSeconds)
+}
+
+// Test The Dispatcher Env Vars Of A KafkaChannel Replicated To A Remote Kafka Cluster
+func TestDispatcherReplicationEnvVars(t *testing.T) {
+
+	// Create The Reconciler
+	configuration := controllertesting.NewConfig()
+	namespaceConfig := &config.NamespaceConfig{EventingKafkaConfig: configuration}
+	r := &Reconciler{
+		logger:      logtesting.TestLogger(t).Desugar(),
+		adminClient: &controllertesting.MockAdminClient{},
+		environment: controllertesting.NewEnvironment(),
+		config:      configuration,
+	}
+
+	// Verify A KafkaChannel Is Not Replicated By Default
+	channel := controllertesting.NewKafkaChannel()
+	envVars, err := r.dispatcherDeploymentEnvVars(channel, namespaceConfig)
+	assert.Nil(t, err)
+	assert.Nil(t, findEnvVar(envVars, commonenv.ReplicationKafkaBrokerEnvVarKey))
+
+	// Verify The Remote Kafka Cluster Of A Replicated KafkaChannel Is Referenced From Its Replication Secret
+	channel.Annotations = map[string]string{kafkaconstants.ReplicationSecretAnnotation: "remote-kafka"}
+	envVars, err = r.dispatcherDeploymentEnvVars(channel, namespaceConfig)
+	assert.Nil(t, err)
+	for _, envVarKey := range []string{commonenv.ReplicationKafkaBrokerEnvVarKey, commonenv.ReplicationKafkaUsernameEnvVarKey, commonenv.ReplicationKafkaPasswordEnvVarKey} {
+		envVar := findEnvVar(envVars, envVarKey)
+		if assert.NotNil(t, envVar, envVarKey) {
+			assert.Equal(t, "remote-kafka", envVar.ValueFrom.SecretKeyRef.Name)
+		}
+	}
+}
+
+// Test The Dispatcher Env Vars Of The Go Runtime Tuning
+func TestDispatcherRuntimeEnvVars(t *testing.T) {
+
+	// Create The Reconciler With A Configured GOGC (But No GOMemLimit)
+	configuration := controllertesting.NewConfig()
+	configuration.Dispatcher.Runtime = config.EKRuntimeConfig{GOGC: "off"}
+	r := &Reconciler{
+		logger:      logtesting.TestLogger(t).Desugar(),
+		adminClient: &controllertesting.MockAdminClient{},
+		environment: controllertesting.NewEnvironment(),
+		config:      configuration,
+	}
+
+	// Verify Only The GOGC Is Set
+	envVars, err := r.dispatcherDeploymentEnvVars(controllertesting.NewKafkaChannel(), &config.NamespaceConfig{EventingKafkaConfig: configuration})
+	assert.Nil(t, err)
+	if envVar := findEnvVar(envVars, gctuning.GOGCEnvVarKey); assert.NotNil(t, envVar) {
+		assert.Equal(t, "off", envVar.Value)
+	}
+	assert.Nil(t, findEnvVar(envVars, gctuning.GOMemLimitEnvVarKey))
+}
+
+// Utility Function For Finding The EnvVar With The Specified Name
+func findEnvVar(envVars []corev1.EnvVar, name string) *corev1.EnvVar {
+	for index := range envVars {
+		if envVars[index].Name == name {
+			return &envVars[index]
+		}
+	}
+	return nil
+}
+
+// Test The Reconciler's Rollback Of A Class Image Whose Dispatcher Exceeds The Canary's Maximum Error Rate
+func TestDispatcherErrorRateRollback(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name           string
+		canaryConfig   config.EKCanaryConfig
+		rolledOut      bool
+		summary        *aggregator.ChannelSummary
+		wantRolledBack bool
+	}
+
+	// Test Data (The Rollout Completed A Minute Ago)
+	rolloutCompleted := time.Now().Add(-time.Minute)
+	canaryConfig := config.EKCanaryConfig{MaxErrorRate: 0.1, MinEvents: 100}
+	failing := &aggregator.ChannelSummary{SubscriptionSummary: aggregator.SubscriptionSummary{DispatchedEvents: 200, FailedEvents: 50, ErrorRate: 0.25}, ScrapedAt: time.Now()}
+	healthy := &aggregator.ChannelSummary{SubscriptionSummary: aggregator.SubscriptionSummary{DispatchedEvents: 200, FailedEvents: 2, ErrorRate: 0.01}, ScrapedAt: time.Now()}
+	tooFewEvents := &aggregator.ChannelSummary{SubscriptionSummary: aggregator.SubscriptionSummary{DispatchedEvents: 20, FailedEvents: 10, ErrorRate: 0.5}, ScrapedAt: time.Now()}
+	scrapedBeforeRollout := &aggregator.ChannelSummary{SubscriptionSummary: failing.SubscriptionSummary, ScrapedAt: rolloutCompleted.Add(-time.Second)}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Maximum Error Rate Not Configured", rolledOut: true, summary: failing},
+		{name: "Not Yet Scraped", canaryConfig: canaryConfig, rolledOut: true},
+		{name: "Rollout Not Completed", canaryConfig: canaryConfig, summary: failing},
+		{name: "Scraped Before Rollout Completed", canaryConfig: canaryConfig, rolledOut: true, summary: scrapedBeforeRollout},
+		{name: "Too Few Events", canaryConfig: canaryConfig, rolledOut: true, summary: tooFewEvents},
+		{name: "Error Rate Below Maximum", canaryConfig: canaryConfig, rolledOut: true, summary: healthy},
+		{name: "Error Rate Exceeded", canaryConfig: canaryConfig, rolledOut: true, summary: failing, wantRolledBack: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Existing Dispatcher Deployment Of The Canary Image
+			channel := controllertesting.NewKafkaChannel(controllertesting.WithImageClassAnnotation)
+			deployment := controllertesting.NewKafkaChannelDispatcherDeployment(controllertesting.WithDeploymentCanaryImage)
+			if testCase.rolledOut {
+				deployment.Status.Conditions = []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable", LastUpdateTime: metav1.NewTime(rolloutCompleted)},
+				}
+			}
+
+			// Create The Reconciler With The TestCase's Canary Config & ChannelSummary
+			configuration := controllertesting.NewConfig()
+			configuration.Dispatcher.Images.Canary = testCase.canaryConfig
+			kubeClientset := fake.NewSimpleClientset(deployment)
+			r := &Reconciler{
+				logger:        logtesting.TestLogger(t).Desugar(),
+				kubeClientset: kubeClientset,
+				adminClient:   &controllertesting.MockAdminClient{},
+				environment:   controllertesting.NewEnvironment(),
+				config:        configuration,
+				channelSummary: func(namespace string, name string) *aggregator.ChannelSummary {
+					return testCase.summary
+				},
+			}
+
+			// Perform The Test
+			updatedDeployment, err := r.updateDispatcherDeploymentImage(context.TODO(), channel, &config.NamespaceConfig{EventingKafkaConfig: configuration}, deployment)
+
+			// Verify The Results
+			assert.Nil(t, err)
+			if testCase.wantRolledBack {
+				assert.Equal(t, controllertesting.DispatcherImage, updatedDeployment.Spec.Template.Spec.Containers[0].Image)
+				assert.Equal(t, controllertesting.CanaryDispatcherImage, updatedDeployment.Annotations[constants.RolledBackImageAnnotation])
+			} else {
+				assert.Same(t, deployment, updatedDeployment)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/controller"
+)
+
+// The Default Time Without Activity After Which Dispatchers Are Scaled To Zero & Interval Between Idle Checks
+const (
+	DefaultIdleTime          = 30 * time.Minute
+	DefaultIdleCheckInterval = 30 * time.Second
+)
+
+// The Activity Of A KafkaChannel & When It Was First Observed (From Which The KafkaChannel Has Been Idle)
+type idleChannel struct {
+	activity string
+	since    time.Time
+}
+
+//
+// Scale-To-Zero Idle Watcher
+//
+// Dispatchers consume (and so keep their resources & Kafka connections) regardless of whether their KafkaChannel
+// receives any records.  The idle watcher periodically summarizes the activity of each KafkaChannel as the sum of the
+// newest offsets of its topics (including any event type sub-topics) & the UIDs & generations of its subscribers, and
+// scales the dispatcher Deployment of a KafkaChannel whose activity has not changed for the configured idle time to
+// zero replicas.  Its activity & previous replicas are recorded in annotations of the Deployment, whose replicas are
+// restored (i.e. the dispatcher is activated) by...
+//
+//   - The first check after a record is produced to the KafkaChannel's topics, or its subscribers change.
+//   - The reconciliation of the KafkaChannel after its subscribers change (without waiting for the next check), or
+//     when scale-to-zero is no longer enabled or applicable to the KafkaChannel.
+//
+// Only the KafkaChannels without subscribers are scaled to zero unless IncludeSubscribed is set, and never those
+// whose receiver is combined with the dispatcher (which would then no longer accept events).  Records produced while
+// a dispatcher is scaled to zero are delivered after its activation, from the committed offsets of its subscribers.
+// The watcher requires a TopicProvisioner implementing the OffsetInspector (e.g. "kafka").
+//
+
+// Start The Idle Watcher Checking For Idle KafkaChannels Until The Context Is Done (No-Op If Scale-To-Zero Is Not Enabled)
+func (r *Reconciler) startIdleWatcher(ctx context.Context) {
+	scaleToZeroConfig := r.config.Dispatcher.ScaleToZero
+	if !scaleToZeroConfig.Enabled {
+		return
+	}
+	interval := time.Duration(scaleToZeroConfig.CheckIntervalMillis) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultIdleCheckInterval
+	}
+	r.logger.Info("Starting Scale-To-Zero Idle Watcher", zap.Duration("Interval", interval), zap.Duration("IdleTime", r.idleTime()))
+	ctx = controller.WithEventRecorder(ctx, r.idleEventRecorder(ctx))
+	go func() {
+		ticker := time.NewTicker(interval) // The First Check Waits An Interval So That The Informers Have Synced
+		defer ticker.Stop()
+		idleChannels := make(map[string]idleChannel)
+		for {
+			select {
+			case now := <-ticker.C:
+				idleChannels = r.checkIdleChannels(ctx, idleChannels, now)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Get The EventRecorder Of The Specified Context, Or A New One Recording To K8S If None (Outside Of Reconciliation)
+func (r *Reconciler) idleEventRecorder(ctx context.Context) record.EventRecorder {
+	recorder := controller.GetEventRecorder(ctx)
+	if recorder == nil {
+		eventBroadcaster := record.NewBroadcaster()
+		recorderWatch := eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: r.kubeClientset.CoreV1().Events("")})
+		recorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: constants.ControllerComponentName})
+		go func(recorderWatch watch.Interface) {
+			<-ctx.Done()
+			recorderWatch.Stop()
+		}(recorderWatch)
+	}
+	return recorder
+}
+
+// Get The Configured Time Without Activity After Which Dispatchers Are Scaled To Zero
+func (r *Reconciler) idleTime() time.Duration {
+	idleTime := time.Duration(r.config.Dispatcher.ScaleToZero.IdleMillis) * time.Millisecond
+	if idleTime <= 0 {
+		idleTime = DefaultIdleTime
+	}
+	return idleTime
+}
+
+// Check The Activity Of The KafkaChannels, Scaling The Dispatchers Of Idle Ones To Zero & Activating Those Of Active Ones
+// (Returning The KafkaChannels Which Are Being Tracked As Idle For The Next Check)
+func (r *Reconciler) checkIdleChannels(ctx context.Context, idleChannels map[string]idleChannel, now time.Time) map[string]idleChannel {
+
+	// Add The K8S ClientSet To The Check Context (Needed By The Kafka AdminClient)
+	ctx = context.WithValue(ctx, kubeclient.Key{}, r.kubeClientset)
+
+	// Get The KafkaChannels & The Topics Whose Offsets Reveal Their Activity
+	channels, err := r.kafkachannelLister.List(labels.Everything())
+	if err != nil {
+		r.logger.Error("Idle Watcher Failed To List KafkaChannels", zap.Error(err))
+		return idleChannels
+	}
+	var topicNames []string
+	for _, channel := range channels {
+		topicNames = append(topicNames, channelTopicNames(channel)...)
+	}
+
+	// Get The Newest Offsets Of The Topics
+	topicOffsets, err := r.topicOffsets(ctx, topicNames)
+	if err != nil {
+		r.logger.Error("Idle Watcher Failed To Get Kafka Topic Offsets", zap.Error(err))
+		return idleChannels
+	} else if topicOffsets == nil {
+		return idleChannels
+	}
+
+	// Scale The Dispatchers Of The KafkaChannels Idle For The Idle Time To Zero, Or Activate Them If No Longer Idle
+	nextIdleChannels := make(map[string]idleChannel, len(channels))
+	for _, channel := range channels {
+		if channel.DeletionTimestamp != nil {
+			continue
+		}
+		key := channel.Namespace + "/" + channel.Name
+		activity := channelActivity(channel, topicOffsets)
+		deployment, err := r.getDispatcherDeployment(channel)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				r.logger.Error("Idle Watcher Failed To Get Dispatcher Deployment", zap.String("Channel", key), zap.Error(err))
+			}
+			continue
+		}
+
+		// Activate A Dispatcher Scaled To Zero Once Its KafkaChannel Is Active Again (Or No Longer Eligible)
+		if idleActivity, ok := deployment.Annotations[constants.IdleActivityAnnotation]; ok {
+			if idleActivity != activity || !r.isScaleToZeroEligible(channel) {
+				r.activateDispatcher(ctx, channel, deployment, "New Records Or Subscribers")
+			}
+			continue
+		}
+
+		// Track The Activity Of Eligible KafkaChannels, Scaling Their Dispatchers To Zero Once Idle For The Idle Time
+		if !r.isScaleToZeroEligible(channel) {
+			continue
+		}
+		idle, ok := idleChannels[key]
+		if !ok || idle.activity != activity {
+			idle = idleChannel{activity: activity, since: now}
+		}
+		nextIdleChannels[key] = idle
+		if now.Sub(idle.since) >= r.idleTime() {
+			r.scaleDispatcherToZero(ctx, channel, deployment, activity)
+		}
+	}
+	return nextIdleChannels
+}
+
+// Get The Sum Of The Newest Offsets Of The Specified Topics (nil Without Error If The Kafka AdminClient Can't Get Them)
+func (r *Reconciler) topicOffsets(ctx context.Context, topicNames []string) (map[string]int64, error) {
+
+	// Don't let another goroutine clear out the admin client while we're using it in this one
+	r.adminMutex.Lock()
+	defer r.adminMutex.Unlock()
+
+	// Create A New Kafka AdminClient For The Check (Skipping The Check If It Can't Get Offsets)
+	r.SetKafkaAdminClient(ctx)
+	defer r.ClearKafkaAdminClient()
+	inspector, ok := r.adminClient.(kafkaadmin.OffsetInspector)
+	if !ok {
+		r.logger.Debug("Kafka AdminClient Can't Get Topic Offsets - Skipping Idle Check", zap.String("AdminType", r.config.Kafka.AdminType))
+		return nil, nil
+	}
+	return inspector.TopicOffsets(ctx, topicNames)
+}
+
+// Determine Whether The Dispatcher Of The Specified KafkaChannel May Be Scaled To Zero When Idle
+func (r *Reconciler) isScaleToZeroEligible(channel *kafkav1beta1.KafkaChannel) bool {
+	scaleToZeroConfig := r.config.Dispatcher.ScaleToZero
+	if !scaleToZeroConfig.Enabled || r.isReceiverCombined(channel) {
+		return false
+	}
+	return len(channel.Spec.Subscribers) == 0 || scaleToZeroConfig.IncludeSubscribed
+}
+
+// Scale The Specified Idle Dispatcher Deployment To Zero, Recording Its Activity & Replicas In Its Annotations
+func (r *Reconciler) scaleDispatcherToZero(ctx context.Context, channel *kafkav1beta1.KafkaChannel, deployment *appsv1.Deployment, activity string) {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	if replicas == 0 {
+		return // Scaled To Zero By Someone Else, Which Is Left Alone
+	}
+	zero := int32(0)
+	updatedDeployment := deployment.DeepCopy()
+	if updatedDeployment.Annotations == nil {
+		updatedDeployment.Annotations = make(map[string]string)
+	}
+	updatedDeployment.Annotations[constants.IdleActivityAnnotation] = activity
+	updatedDeployment.Annotations[constants.IdleReplicasAnnotation] = strconv.Itoa(int(replicas))
+	updatedDeployment.Spec.Replicas = &zero
+	logger := util.ChannelLogger(r.logger, channel)
+	_, err := r.kubeClientset.AppsV1().Deployments(updatedDeployment.Namespace).Update(ctx, updatedDeployment, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error("Failed To Scale Idle Dispatcher Deployment To Zero", zap.Error(err))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DispatcherScaleFailed.String(), "Failed To Scale Idle Dispatcher To Zero: %v", err)
+		return
+	}
+	logger.Info("Scaled Idle Dispatcher Deployment To Zero", zap.Int32("Replicas", replicas), zap.Duration("IdleTime", r.idleTime()))
+	r.auditDeployment(audit.ActionUpdated, updatedDeployment.Name, channel, map[string]string{"replicas": "0", "reason": "idle"})
+	controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeNormal, event.DispatcherScaledToZero.String(),
+		"Scaled Dispatcher From %d Replicas To Zero After %v Without Activity", replicas, r.idleTime())
+}
+
+// Activate The Specified Dispatcher Deployment Scaled To Zero, Restoring Its Replicas & Removing Its Idle Annotations
+func (r *Reconciler) activateDispatcher(ctx context.Context, channel *kafkav1beta1.KafkaChannel, deployment *appsv1.Deployment, reason string) *appsv1.Deployment {
+	replicas, err := strconv.Atoi(deployment.Annotations[constants.IdleReplicasAnnotation])
+	if err != nil || replicas < 1 {
+		replicas = r.config.Dispatcher.Replicas
+	}
+	restoredReplicas := int32(replicas)
+	updatedDeployment := deployment.DeepCopy()
+	delete(updatedDeployment.Annotations, constants.IdleActivityAnnotation)
+	delete(updatedDeployment.Annotations, constants.IdleReplicasAnnotation)
+	updatedDeployment.Spec.Replicas = &restoredReplicas
+	logger := util.ChannelLogger(r.logger, channel)
+	updatedDeployment, err = r.kubeClientset.AppsV1().Deployments(updatedDeployment.Namespace).Update(ctx, updatedDeployment, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error("Failed To Activate Dispatcher Deployment", zap.Error(err))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DispatcherScaleFailed.String(), "Failed To Activate Dispatcher: %v", err)
+		return deployment
+	}
+	logger.Info("Activated Dispatcher Deployment", zap.Int32("Replicas", restoredReplicas), zap.String("Reason", reason))
+	r.auditDeployment(audit.ActionUpdated, updatedDeployment.Name, channel, map[string]string{"replicas": strconv.Itoa(int(restoredReplicas)), "reason": reason})
+	controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeNormal, event.DispatcherActivated.String(),
+		"Scaled Dispatcher From Zero To %d Replicas (%s)", restoredReplicas, reason)
+	return updatedDeployment
+}
+
+// Activate The Specified Channel's Dispatcher Deployment If Scaled To Zero And Either Its Subscribers Changed Or
+// Scale-To-Zero No Longer Applies To The Channel (New Records Are Only Detected By The Idle Watcher)
+func (r *Reconciler) reconcileIdleDispatcher(ctx context.Context, channel *kafkav1beta1.KafkaChannel, deployment *appsv1.Deployment) *appsv1.Deployment {
+	idleActivity, ok := deployment.Annotations[constants.IdleActivityAnnotation]
+	if !ok {
+		return deployment
+	}
+	if !r.isScaleToZeroEligible(channel) {
+		return r.activateDispatcher(ctx, channel, deployment, "Scale-To-Zero No Longer Applies")
+	}
+	if subscribersActivity(channel) != idleActivity[strings.Index(idleActivity, "/")+1:] {
+		return r.activateDispatcher(ctx, channel, deployment, "Subscribers Changed")
+	}
+	return deployment
+}
+
+// Get The Names Of The Specified Channel's Kafka Topic & Any Event Type Sub-Topics
+func channelTopicNames(channel *kafkav1beta1.KafkaChannel) []string {
+	topicName := util.TopicName(channel)
+	eventTypeRouting, _ := routing.NewEventTypeRouting(channel.Annotations) // Invalid Routing Annotations Never Created Any Sub-Topics
+	return append([]string{topicName}, eventTypeRouting.Topics(topicName)...)
+}
+
+// Summarize The Activity Of The Specified Channel As "<sum of topic offsets>/<hash of subscribers>"
+func channelActivity(channel *kafkav1beta1.KafkaChannel, topicOffsets map[string]int64) string {
+	var offsets int64
+	for _, topicName := range channelTopicNames(channel) {
+		offsets += topicOffsets[topicName]
+	}
+	return fmt.Sprintf("%d/%s", offsets, subscribersActivity(channel))
+}
+
+// Summarize The Subscribers Of The Specified Channel As A Hash Of Their UIDs & Generations
+func subscribersActivity(channel *kafkav1beta1.KafkaChannel) string {
+	subscribers := make([]string, 0, len(channel.Spec.Subscribers))
+	for _, subscriber := range channel.Spec.Subscribers {
+		subscribers = append(subscribers, fmt.Sprintf("%s:%d", subscriber.UID, subscriber.Generation))
+	}
+	sort.Strings(subscribers)
+	hash := sha256.Sum256([]byte(strings.Join(subscribers, ",")))
+	return hex.EncodeToString(hash[:8])
+}
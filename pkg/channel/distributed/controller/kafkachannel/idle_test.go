@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciler's checkIdleChannels() Functionality Scaling An Idle Dispatcher To Zero & Activating It
+func TestCheckIdleChannels(t *testing.T) {
+
+	// Test Data
+	idleTime := time.Minute
+	start := time.Now()
+	replicas := int32(3)
+
+	// Create The KafkaChannel (Without Subscribers) & Its Dispatcher Deployment
+	channel := controllertesting.NewKafkaChannel()
+	deployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+	deployment.Spec.Replicas = &replicas
+
+	// Mock The Kafka AdminClient With The Offsets Of The KafkaChannel's Topic
+	mockAdminClient := &controllertesting.MockAdminClient{MockTopicOffsets: map[string]int64{controllertesting.TopicName: 5}}
+	newKafkaAdminClientWrapperPlaceholder := kafkaadmin.NewKafkaAdminClientWrapper
+	kafkaadmin.NewKafkaAdminClientWrapper = func(_ context.Context, _ *sarama.Config, _ string, _ string, _ *bindingsv1beta1.KafkaAuthSpec) (kafkaadmin.TopicProvisioner, error) {
+		return mockAdminClient, nil
+	}
+	defer func() {
+		kafkaadmin.NewKafkaAdminClientWrapper = newKafkaAdminClientWrapperPlaceholder
+	}()
+
+	// Create The Reconciler With Scale-To-Zero Enabled
+	r, kubeClientset, deploymentIndexer := newIdleTestReconciler(t, channel, deployment)
+	r.config.Dispatcher.ScaleToZero.IdleMillis = idleTime.Milliseconds()
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.TODO(), recorder)
+
+	// Verify The Dispatcher Is Not Scaled To Zero Before The Idle Time
+	idleChannels := r.checkIdleChannels(ctx, map[string]idleChannel{}, start)
+	assert.Len(t, idleChannels, 1)
+	idleChannels = r.checkIdleChannels(ctx, idleChannels, start.Add(idleTime/2))
+	assert.Equal(t, replicas, *getIdleTestDeployment(t, kubeClientset, deployment.Name).Spec.Replicas)
+
+	// Verify New Records Restart The Idle Time
+	mockAdminClient.MockTopicOffsets[controllertesting.TopicName] = 6
+	idleChannels = r.checkIdleChannels(ctx, idleChannels, start.Add(idleTime))
+	assert.Equal(t, replicas, *getIdleTestDeployment(t, kubeClientset, deployment.Name).Spec.Replicas)
+	assert.Equal(t, start.Add(idleTime), idleChannels[channel.Namespace+"/"+channel.Name].since)
+
+	// Verify The Dispatcher Is Scaled To Zero Once Idle For The Idle Time
+	r.checkIdleChannels(ctx, idleChannels, start.Add(2*idleTime))
+	scaledDeployment := getIdleTestDeployment(t, kubeClientset, deployment.Name)
+	assert.Equal(t, int32(0), *scaledDeployment.Spec.Replicas)
+	assert.Equal(t, "3", scaledDeployment.Annotations[constants.IdleReplicasAnnotation])
+	assert.Equal(t, channelActivity(channel, mockAdminClient.MockTopicOffsets), scaledDeployment.Annotations[constants.IdleActivityAnnotation])
+	assert.Contains(t, <-recorder.Events, "DispatcherScaledToZero")
+	assert.True(t, mockAdminClient.CloseCalled())
+
+	// Verify The Dispatcher Remains Scaled To Zero Without New Records
+	assert.Nil(t, deploymentIndexer.Update(scaledDeployment))
+	r.checkIdleChannels(ctx, map[string]idleChannel{}, start.Add(3*idleTime))
+	assert.Equal(t, int32(0), *getIdleTestDeployment(t, kubeClientset, deployment.Name).Spec.Replicas)
+
+	// Verify The Dispatcher Is Activated With Its Previous Replicas By New Records
+	mockAdminClient.MockTopicOffsets[controllertesting.TopicName] = 7
+	r.checkIdleChannels(ctx, map[string]idleChannel{}, start.Add(4*idleTime))
+	activatedDeployment := getIdleTestDeployment(t, kubeClientset, deployment.Name)
+	assert.Equal(t, replicas, *activatedDeployment.Spec.Replicas)
+	assert.NotContains(t, activatedDeployment.Annotations, constants.IdleReplicasAnnotation)
+	assert.NotContains(t, activatedDeployment.Annotations, constants.IdleActivityAnnotation)
+	assert.Contains(t, <-recorder.Events, "DispatcherActivated")
+}
+
+// Test The Reconciler's reconcileIdleDispatcher() Functionality
+func TestReconcileIdleDispatcher(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name              string
+		enabled           bool
+		includeSubscribed bool
+		subscribed        bool
+		annotated         bool
+		expectActivated   bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Not Scaled To Zero", enabled: true, annotated: false, expectActivated: false},
+		{name: "Unchanged Subscribers", enabled: true, annotated: true, expectActivated: false},
+		{name: "Changed Subscribers", enabled: true, includeSubscribed: true, subscribed: true, annotated: true, expectActivated: true},
+		{name: "Subscribed Not Included", enabled: true, subscribed: true, annotated: true, expectActivated: true},
+		{name: "Scale-To-Zero Disabled", enabled: false, annotated: true, expectActivated: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The KafkaChannel (With Its Subscribers When Idle Recorded In The Annotations) & Its Dispatcher Deployment
+			idleChannel := controllertesting.NewKafkaChannel()
+			channel := controllertesting.NewKafkaChannel(func(channel *kafkav1beta1.KafkaChannel) {
+				if testCase.subscribed {
+					channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: types.UID("11111111-1111-1111-1111-111111111111"), Generation: 1}}
+				}
+			})
+			deployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+			if testCase.annotated {
+				zero := int32(0)
+				deployment.Spec.Replicas = &zero
+				deployment.Annotations = map[string]string{
+					constants.IdleActivityAnnotation: channelActivity(idleChannel, map[string]int64{}),
+					constants.IdleReplicasAnnotation: "2",
+				}
+			}
+
+			// Create The Reconciler With The TestCase's Scale-To-Zero Config
+			r, kubeClientset, _ := newIdleTestReconciler(t, channel, deployment)
+			r.config.Dispatcher.ScaleToZero.Enabled = testCase.enabled
+			r.config.Dispatcher.ScaleToZero.IncludeSubscribed = testCase.includeSubscribed
+			ctx := controller.WithEventRecorder(context.TODO(), record.NewFakeRecorder(10))
+
+			// Perform The Test
+			reconciledDeployment := r.reconcileIdleDispatcher(ctx, channel, deployment)
+
+			// Verify The Results
+			storedDeployment := getIdleTestDeployment(t, kubeClientset, deployment.Name)
+			if testCase.expectActivated {
+				assert.Equal(t, int32(2), *reconciledDeployment.Spec.Replicas)
+				assert.Equal(t, int32(2), *storedDeployment.Spec.Replicas)
+				assert.NotContains(t, storedDeployment.Annotations, constants.IdleActivityAnnotation)
+			} else {
+				assert.Equal(t, deployment, reconciledDeployment)
+				assert.Equal(t, deployment.Spec.Replicas, storedDeployment.Spec.Replicas)
+			}
+		})
+	}
+}
+
+// Test The isScaleToZeroEligible() Functionality
+func TestIsScaleToZeroEligible(t *testing.T) {
+	subscribedChannel := controllertesting.NewKafkaChannel(func(channel *kafkav1beta1.KafkaChannel) {
+		channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: types.UID("11111111-1111-1111-1111-111111111111")}}
+	})
+	combinedChannel := controllertesting.NewKafkaChannel()
+	r := &Reconciler{config: controllertesting.NewConfig()}
+	assert.False(t, r.isScaleToZeroEligible(controllertesting.NewKafkaChannel()))
+	r.config.Dispatcher.ScaleToZero.Enabled = true
+	assert.True(t, r.isScaleToZeroEligible(controllertesting.NewKafkaChannel()))
+	assert.False(t, r.isScaleToZeroEligible(subscribedChannel))
+	r.config.Dispatcher.ScaleToZero.IncludeSubscribed = true
+	assert.True(t, r.isScaleToZeroEligible(subscribedChannel))
+	r.config.Receiver.Isolation = util.ReceiverIsolationCombined
+	assert.False(t, r.isScaleToZeroEligible(combinedChannel))
+}
+
+// Test The channelActivity() Functionality
+func TestChannelActivity(t *testing.T) {
+	channel := controllertesting.NewKafkaChannel()
+	activity := channelActivity(channel, map[string]int64{controllertesting.TopicName: 5, "other-topic": 7})
+	assert.Equal(t, "5/"+subscribersActivity(channel), activity)
+	channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: types.UID("11111111-1111-1111-1111-111111111111"), Generation: 1}}
+	assert.NotEqual(t, activity, channelActivity(channel, map[string]int64{controllertesting.TopicName: 5}))
+}
+
+// Create A Reconciler With Scale-To-Zero Enabled For The Specified KafkaChannel & Dispatcher Deployment
+func newIdleTestReconciler(t *testing.T, channel *kafkav1beta1.KafkaChannel, deployment *appsv1.Deployment) (*Reconciler, *fake.Clientset, cache.Indexer) {
+	channelIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.Nil(t, channelIndexer.Add(channel))
+	deploymentIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.Nil(t, deploymentIndexer.Add(deployment))
+	configuration := controllertesting.NewConfig()
+	configuration.Dispatcher.ScaleToZero.Enabled = true
+	kubeClientset := fake.NewSimpleClientset(deployment)
+	r := &Reconciler{
+		logger:             logtesting.TestLogger(t).Desugar(),
+		kubeClientset:      kubeClientset,
+		environment:        controllertesting.NewEnvironment(),
+		config:             configuration,
+		kafkachannelLister: kafkalisters.NewKafkaChannelLister(channelIndexer),
+		deploymentLister:   appsv1listers.NewDeploymentLister(deploymentIndexer),
+		adminMutex:         &sync.Mutex{},
+	}
+	return r, kubeClientset, deploymentIndexer
+}
+
+// Get The Specified Dispatcher Deployment From The K8S ClientSet
+func getIdleTestDeployment(t *testing.T, kubeClientset *fake.Clientset, name string) *appsv1.Deployment {
+	deployment, err := kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	return deployment
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/system"
+)
+
+// The Default Interval Between Janitor Sweeps
+const DefaultJanitorInterval = 10 * time.Minute
+
+// OrphanReport Lists The Orphaned Resources Found (And Unless DryRun Deleted) By A Janitor Sweep
+type OrphanReport struct {
+	DryRun         bool     `json:"dryRun"`
+	Deployments    []string `json:"deployments,omitempty"`
+	Services       []string `json:"services,omitempty"`
+	Topics         []string `json:"topics,omitempty"`
+	ConsumerGroups []string `json:"consumerGroups,omitempty"`
+}
+
+//
+// Orphaned Resource Janitor
+//
+// KafkaChannels whose finalization failed (or was bypassed by removing the finalizer) leave behind their dispatcher
+// (and isolated receiver) Deployments & Services (which can't be garbage collected by Kubernetes since OwnerReferences
+// are not intended to be cross-namespace), their topics, and the ConsumerGroups of their Subscribers.  The janitor
+// periodically finds...
+//
+//   - Dispatcher & isolated receiver Deployments & Services whose kafkachannel-namespace / kafkachannel-name labels
+//     identify a KafkaChannel which no longer exists.
+//   - Topics recorded in the managed topics registry (see managedtopics.go) as created by the controller for a
+//     KafkaChannel which no longer exists (the KafkaChannel topics and their event type sub-topics and DeadLetter
+//     topics).  Topics which merely look like those of a KafkaChannel are never considered, nor are the ExtraTopics of
+//     any KafkaChannel, the audit topic or the quarantine topics (which are only deleted by finalizing their channel).
+//   - ConsumerGroups with the kafka.<subscriber-uid> ids of the dispatchers whose Subscriber is no longer in the
+//     spec of any KafkaChannel.  Kafka refuses to delete groups which still have members, which are retried by the
+//     next sweep.
+//
+// ...and deletes them, or when DryRun is set only reports them.  The orphans found by every sweep are logged and
+// recorded in the orphaned_resources metric, and the deletions in the deleted_orphan_count metric.  The topics and
+// ConsumerGroups are only swept when the TopicProvisioner implements the ClusterInspector (e.g. "kafka").
+//
+
+// Start The Janitor Sweeping Orphaned Resources Until The Context Is Done (No-Op If The Janitor Is Not Enabled)
+func (r *Reconciler) startJanitor(ctx context.Context) {
+	janitorConfig := r.config.Janitor
+	if !janitorConfig.Enabled {
+		return
+	}
+	interval := time.Duration(janitorConfig.IntervalMillis) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultJanitorInterval
+	}
+	r.logger.Info("Starting Orphaned Resource Janitor", zap.Duration("Interval", interval), zap.Bool("DryRun", janitorConfig.DryRun))
+	go func() {
+		ticker := time.NewTicker(interval) // The First Sweep Waits An Interval So That The Informers Have Synced
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.sweepOrphans(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Find (And Unless DryRun Delete) The Orphaned Dispatchers, Isolated Receivers, Topics & ConsumerGroups
+func (r *Reconciler) sweepOrphans(ctx context.Context) *OrphanReport {
+
+	// Add The K8S ClientSet To The Sweep Context (Needed By The Kafka AdminClient)
+	ctx = context.WithValue(ctx, kubeclient.Key{}, r.kubeClientset)
+
+	// Sweep The Dispatchers, Isolated Receivers & The Kafka Cluster
+	report := &OrphanReport{DryRun: r.config.Janitor.DryRun}
+	report.Deployments = r.sweepOrphanedDeployments(ctx, report.DryRun)
+	report.Services = r.sweepOrphanedServices(ctx, report.DryRun)
+	report.Topics, report.ConsumerGroups = r.sweepOrphanedKafkaResources(ctx, report.DryRun)
+
+	// Record The Number Of Orphans Of Each Kind & Report Them
+	metrics.RecordOrphanedResources(r.logger, metrics.KindDeployment, len(report.Deployments))
+	metrics.RecordOrphanedResources(r.logger, metrics.KindService, len(report.Services))
+	metrics.RecordOrphanedResources(r.logger, metrics.KindTopic, len(report.Topics))
+	metrics.RecordOrphanedResources(r.logger, metrics.KindConsumerGroup, len(report.ConsumerGroups))
+	if len(report.Deployments)+len(report.Services)+len(report.Topics)+len(report.ConsumerGroups) > 0 {
+		r.logger.Info("Janitor Found Orphaned Resources", zap.Any("Report", report))
+	} else {
+		r.logger.Debug("Janitor Found No Orphaned Resources")
+	}
+	return report
+}
+
+// Find (And Unless DryRun Delete) The Dispatcher & Isolated Receiver Deployments Whose KafkaChannel No Longer Exists
+func (r *Reconciler) sweepOrphanedDeployments(ctx context.Context, dryRun bool) []string {
+	var orphans []string
+	for _, selector := range channelResourceSelectors() {
+		deployments, err := r.deploymentLister.Deployments(system.Namespace()).List(selector)
+		if err != nil {
+			r.logger.Error("Janitor Failed To List Deployments", zap.Stringer("Selector", selector), zap.Error(err))
+			continue
+		}
+		for _, deployment := range deployments {
+			if !r.isOrphanedChannelResource(deployment.Labels) {
+				continue
+			}
+			orphans = append(orphans, deployment.Name)
+			if !dryRun {
+				err = r.kubeClientset.AppsV1().Deployments(deployment.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
+				r.logOrphanDeletion(err, metrics.KindDeployment, deployment.Name)
+				if err == nil {
+					r.auditDeployment(audit.ActionDeleted, deployment.Name, nil, map[string]string{"reason": "orphaned"})
+				}
+			}
+		}
+	}
+	return orphans
+}
+
+// Find (And Unless DryRun Delete) The Dispatcher & Isolated Receiver Services Whose KafkaChannel No Longer Exists
+func (r *Reconciler) sweepOrphanedServices(ctx context.Context, dryRun bool) []string {
+	var orphans []string
+	for _, selector := range channelResourceSelectors() {
+		services, err := r.serviceLister.Services(system.Namespace()).List(selector)
+		if err != nil {
+			r.logger.Error("Janitor Failed To List Services", zap.Stringer("Selector", selector), zap.Error(err))
+			continue
+		}
+		for _, service := range services {
+			if !r.isOrphanedChannelResource(service.Labels) {
+				continue
+			}
+			orphans = append(orphans, service.Name)
+			if !dryRun {
+				err = r.kubeClientset.CoreV1().Services(service.Namespace).Delete(ctx, service.Name, metav1.DeleteOptions{})
+				r.logOrphanDeletion(err, metrics.KindService, service.Name)
+			}
+		}
+	}
+	return orphans
+}
+
+// Find (And Unless DryRun Delete) The Topics & ConsumerGroups Whose KafkaChannel / Subscriber No Longer Exists
+func (r *Reconciler) sweepOrphanedKafkaResources(ctx context.Context, dryRun bool) ([]string, []string) {
+
+	// Don't let another goroutine clear out the admin client while we're using it in this one
+	r.adminMutex.Lock()
+	defer r.adminMutex.Unlock()
+
+	// Create A New Kafka AdminClient For The Sweep (Skipping The Kafka Cluster If It Can't Be Inspected)
+	r.SetKafkaAdminClient(ctx)
+	defer r.ClearKafkaAdminClient()
+	inspector, ok := r.adminClient.(kafkaadmin.ClusterInspector)
+	if !ok {
+		r.logger.Debug("Kafka AdminClient Can't List Topics & ConsumerGroups - Skipping", zap.String("AdminType", r.config.Kafka.AdminType))
+		return nil, nil
+	}
+
+	// Get The Existing KafkaChannels, Their ExtraTopics & The UIDs Of Their Subscribers
+	channels, err := r.kafkachannelLister.List(labels.Everything())
+	if err != nil {
+		r.logger.Error("Janitor Failed To List KafkaChannels", zap.Error(err))
+		return nil, nil
+	}
+	channelKeys := make(map[string]bool, len(channels))
+	extraTopics := make(map[string]bool)
+	subscriberUIDs := make(map[string]bool)
+	for _, channel := range channels {
+		channelKeys[channel.Namespace+"/"+channel.Name] = true
+		for _, extraTopic := range channel.Spec.ExtraTopics {
+			extraTopics[extraTopic] = true
+		}
+		for _, subscriber := range channel.Spec.Subscribers {
+			subscriberUIDs[string(subscriber.UID)] = true
+		}
+	}
+
+	return r.sweepOrphanedTopics(ctx, dryRun, inspector, channelKeys, extraTopics), r.sweepOrphanedConsumerGroups(ctx, dryRun, inspector, subscriberUIDs)
+}
+
+// Find (And Unless DryRun Delete) The Topics Created By The Controller Whose KafkaChannel No Longer Exists
+func (r *Reconciler) sweepOrphanedTopics(ctx context.Context, dryRun bool, inspector kafkaadmin.ClusterInspector, channelKeys map[string]bool, extraTopics map[string]bool) []string {
+
+	// Get The Topics Recorded As Created By The Controller (Only Which Are Considered)
+	managedTopics, err := r.getManagedTopics(ctx)
+	if err != nil {
+		r.logger.Error("Janitor Failed To Get The Managed Topics", zap.Error(err))
+		return nil
+	}
+
+	// Get The Topics Of The Kafka Cluster
+	topicNames, err := inspector.ListTopics(ctx)
+	if err != nil {
+		r.logger.Error("Janitor Failed To List Kafka Topics", zap.Error(err))
+		return nil
+	}
+
+	// Find The Managed Topics Whose KafkaChannel No Longer Exists (Never The Excluded Topics)
+	var orphans []string
+	for _, topicName := range topicNames {
+		owner, managed := managedTopics[topicName]
+		if !managed || channelKeys[owner] || r.isExcludedTopic(topicName, extraTopics) {
+			continue
+		}
+		orphans = append(orphans, topicName)
+		if !dryRun {
+			r.logOrphanDeletion(r.deleteTopic(ctx, topicName), metrics.KindTopic, topicName)
+		}
+	}
+	return orphans
+}
+
+// Determine Whether The Specified Topic Must Never Be Deleted By The Janitor (Even If Recorded As Managed)
+func (r *Reconciler) isExcludedTopic(topicName string, extraTopics map[string]bool) bool {
+
+	// The Externally Managed Topics Fanned In To Any Existing KafkaChannel
+	if extraTopics[topicName] {
+		return true
+	}
+
+	// The Audit Topic
+	auditTopic := r.config.Audit.Topic
+	if len(auditTopic) == 0 {
+		auditTopic = audit.DefaultTopic
+	}
+	if topicName == auditTopic {
+		return true
+	}
+
+	// The Quarantine Topics (Whose Records May Still Await Redelivery)
+	return topicName == r.config.Dispatcher.PoisonPill.QuarantineTopic || strings.HasSuffix(topicName, "."+kafkaconstants.QuarantineTopicSuffix)
+}
+
+// Find (And Unless DryRun Delete) The Dispatcher ConsumerGroups Whose Subscriber No Longer Exists
+func (r *Reconciler) sweepOrphanedConsumerGroups(ctx context.Context, dryRun bool, inspector kafkaadmin.ClusterInspector, subscriberUIDs map[string]bool) []string {
+
+	// Get The ConsumerGroups Of The Kafka Cluster
+	groupIds, err := inspector.ListConsumerGroups(ctx)
+	if err != nil {
+		r.logger.Error("Janitor Failed To List Kafka ConsumerGroups", zap.Error(err))
+		return nil
+	}
+
+	// Find The Dispatcher ConsumerGroups (kafka.<subscriber-uid>) Of Subscribers Which No Longer Exist
+	var orphans []string
+	for _, groupId := range groupIds {
+		subscriberUID := strings.TrimPrefix(groupId, kafkautil.GroupId(""))
+		if subscriberUID == groupId || subscriberUIDs[subscriberUID] {
+			continue
+		}
+		if _, err := uuid.Parse(subscriberUID); err != nil {
+			continue
+		}
+		orphans = append(orphans, groupId)
+		if !dryRun {
+			err = inspector.DeleteConsumerGroup(ctx, groupId)
+			if err == sarama.ErrNonEmptyGroup {
+				r.logger.Info("Orphaned ConsumerGroup Still Has Members - Retrying Next Sweep", zap.String("ConsumerGroup", groupId))
+				continue
+			}
+			r.logOrphanDeletion(err, metrics.KindConsumerGroup, groupId)
+		}
+	}
+	return orphans
+}
+
+// Determine Whether The Specified Dispatcher / Isolated Receiver Labels Identify A KafkaChannel Which No Longer Exists
+func (r *Reconciler) isOrphanedChannelResource(resourceLabels map[string]string) bool {
+	namespace := resourceLabels[constants.KafkaChannelNamespaceLabel]
+	name := resourceLabels[constants.KafkaChannelNameLabel]
+	if len(namespace) == 0 || len(name) == 0 {
+		return false
+	}
+	_, err := r.kafkachannelLister.KafkaChannels(namespace).Get(name)
+	return errors.IsNotFound(err)
+}
+
+// Log The Result Of Deleting An Orphaned Resource & Record Successful Deletions
+func (r *Reconciler) logOrphanDeletion(err error, kind string, name string) {
+	if err != nil && !errors.IsNotFound(err) {
+		r.logger.Error("Janitor Failed To Delete Orphaned Resource", zap.String("Kind", kind), zap.String("Name", name), zap.Error(err))
+		return
+	}
+	r.logger.Info("Janitor Deleted Orphaned Resource", zap.String("Kind", kind), zap.String("Name", name))
+	metrics.RecordDeletedOrphan(r.logger, kind)
+}
+
+// Get The Selectors Of The Per-KafkaChannel Deployments & Services (The Dispatchers & Isolated Receivers)
+func channelResourceSelectors() []labels.Selector {
+	return []labels.Selector{
+		labels.SelectorFromSet(labels.Set{constants.KafkaChannelDispatcherLabel: "true"}),
+		labels.SelectorFromSet(labels.Set{constants.KafkaChannelIsolatedReceiverLabel: "true"}),
+	}
+}
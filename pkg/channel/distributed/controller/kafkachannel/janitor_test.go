@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciler's sweepOrphans() Functionality
+func TestSweepOrphans(t *testing.T) {
+
+	// Test Data
+	deletedChannelName := "deleted-channel"
+	subscriberUID := "11111111-1111-1111-1111-111111111111"
+	orphanedGroupId := "kafka.22222222-2222-2222-2222-222222222222"
+	nonEmptyGroupId := "kafka.33333333-3333-3333-3333-333333333333"
+	deletedChannelKey := controllertesting.KafkaChannelNamespace + "/" + deletedChannelName
+	extraTopic := "external.extra-topic"
+	poisonPillQuarantineTopic := "poison-pill-quarantine"
+	orphanedTopics := []string{
+		controllertesting.KafkaChannelNamespace + "." + deletedChannelName,
+		controllertesting.KafkaChannelNamespace + "." + deletedChannelName + ".uid-1.dlq",
+	}
+	managedTopics := map[string]string{
+		controllertesting.TopicName:         controllertesting.KafkaChannelNamespace + "/" + controllertesting.KafkaChannelName,
+		orphanedTopics[0]:                   deletedChannelKey,
+		orphanedTopics[1]:                   deletedChannelKey,
+		orphanedTopics[0] + ".quarantine":   deletedChannelKey, // Excluded Quarantine Topic
+		extraTopic:                          deletedChannelKey, // Excluded ExtraTopic Of The Existing KafkaChannel
+		audit.DefaultTopic:                  deletedChannelKey, // Excluded Audit Topic
+		poisonPillQuarantineTopic:           deletedChannelKey, // Excluded Poison Pill Quarantine Topic
+		"deleted-namespace.deleted-channel": "deleted-namespace/deleted-channel",
+	}
+	topics := append([]string{
+		controllertesting.TopicName,
+		orphanedTopics[0] + ".quarantine",
+		controllertesting.KafkaChannelNamespace + ".unmanaged-channel", // Looks Like A KafkaChannel Topic But Not Managed
+		extraTopic,
+		audit.DefaultTopic,
+		poisonPillQuarantineTopic,
+		"__consumer_offsets",
+	}, orphanedTopics...)
+	consumerGroups := []string{"kafka." + subscriberUID, orphanedGroupId, nonEmptyGroupId, "kafka.not-a-uid", "other-group"}
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name   string
+		dryRun bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Dry Run", dryRun: true},
+		{name: "Delete Orphans", dryRun: false},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Existing KafkaChannel (With A Subscriber) & The Dispatchers Of It And Of A Deleted KafkaChannel (Which Also Had An Isolated Receiver)
+			channel := controllertesting.NewKafkaChannel(func(channel *kafkav1beta1.KafkaChannel) {
+				channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: types.UID(subscriberUID)}}
+				channel.Spec.ExtraTopics = []string{extraTopic}
+			})
+			service := controllertesting.NewKafkaChannelDispatcherService()
+			deployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+			orphanedService := controllertesting.NewKafkaChannelDispatcherService()
+			orphanedService.Name = deletedChannelName + "-dispatcher"
+			orphanedService.Labels[constants.KafkaChannelNameLabel] = deletedChannelName
+			orphanedDeployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+			orphanedDeployment.Name = deletedChannelName + "-dispatcher"
+			orphanedDeployment.Labels[constants.KafkaChannelNameLabel] = deletedChannelName
+			orphanedReceiverDeployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+			orphanedReceiverDeployment.Name = deletedChannelName + "-receiver"
+			orphanedReceiverDeployment.Labels[constants.KafkaChannelNameLabel] = deletedChannelName
+			delete(orphanedReceiverDeployment.Labels, constants.KafkaChannelDispatcherLabel)
+			orphanedReceiverDeployment.Labels[constants.KafkaChannelIsolatedReceiverLabel] = "true"
+			managedTopicsConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.ManagedTopicsConfigMapName, Namespace: commonconstants.KnativeEventingNamespace},
+				Data:       managedTopics,
+			}
+
+			// Create The Listers
+			channelIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			assert.Nil(t, channelIndexer.Add(channel))
+			serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			assert.Nil(t, serviceIndexer.Add(service))
+			assert.Nil(t, serviceIndexer.Add(orphanedService))
+			deploymentIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			assert.Nil(t, deploymentIndexer.Add(deployment))
+			assert.Nil(t, deploymentIndexer.Add(orphanedDeployment))
+			assert.Nil(t, deploymentIndexer.Add(orphanedReceiverDeployment))
+
+			// Mock The Kafka AdminClient, Tracking The Deleted Topics & ConsumerGroups
+			var deletedTopics []string
+			var deletedGroupIds []string
+			mockAdminClient := &controllertesting.MockAdminClient{
+				MockTopics:         topics,
+				MockConsumerGroups: consumerGroups,
+				MockDeleteTopicFunc: func(_ context.Context, topicName string) *sarama.TopicError {
+					deletedTopics = append(deletedTopics, topicName)
+					return nil
+				},
+				MockDeleteConsumerGroupFunc: func(_ context.Context, groupId string) error {
+					if groupId == nonEmptyGroupId {
+						return sarama.ErrNonEmptyGroup
+					}
+					deletedGroupIds = append(deletedGroupIds, groupId)
+					return nil
+				},
+			}
+			newKafkaAdminClientWrapperPlaceholder := kafkaadmin.NewKafkaAdminClientWrapper
+			kafkaadmin.NewKafkaAdminClientWrapper = func(_ context.Context, _ *sarama.Config, _ string, _ string, _ *bindingsv1beta1.KafkaAuthSpec) (kafkaadmin.TopicProvisioner, error) {
+				return mockAdminClient, nil
+			}
+			defer func() {
+				kafkaadmin.NewKafkaAdminClientWrapper = newKafkaAdminClientWrapperPlaceholder
+			}()
+
+			// Create The Reconciler With The TestCase's DryRun
+			configuration := controllertesting.NewConfig()
+			configuration.Janitor.Enabled = true
+			configuration.Janitor.DryRun = testCase.dryRun
+			configuration.Dispatcher.PoisonPill.QuarantineTopic = poisonPillQuarantineTopic
+			kubeClientset := fake.NewSimpleClientset([]runtime.Object{service, deployment, orphanedService, orphanedDeployment, orphanedReceiverDeployment, managedTopicsConfigMap}...)
+			r := &Reconciler{
+				logger:             logtesting.TestLogger(t).Desugar(),
+				kubeClientset:      kubeClientset,
+				environment:        controllertesting.NewEnvironment(),
+				config:             configuration,
+				kafkachannelLister: kafkalisters.NewKafkaChannelLister(channelIndexer),
+				serviceLister:      corev1listers.NewServiceLister(serviceIndexer),
+				deploymentLister:   appsv1listers.NewDeploymentLister(deploymentIndexer),
+				adminMutex:         &sync.Mutex{},
+			}
+
+			// Perform The Test
+			report := r.sweepOrphans(context.TODO())
+
+			// Verify The Reported Orphans
+			assert.Equal(t, testCase.dryRun, report.DryRun)
+			assert.Equal(t, []string{orphanedDeployment.Name, orphanedReceiverDeployment.Name}, report.Deployments)
+			assert.Equal(t, []string{orphanedService.Name}, report.Services)
+			assert.Equal(t, orphanedTopics, report.Topics)
+			assert.Equal(t, []string{orphanedGroupId, nonEmptyGroupId}, report.ConsumerGroups)
+			assert.True(t, mockAdminClient.CloseCalled())
+
+			// Verify The Orphans Were Deleted Unless DryRun (And The Other Resources Never)
+			_, serviceErr := kubeClientset.CoreV1().Services(commonconstants.KnativeEventingNamespace).Get(context.TODO(), service.Name, metav1.GetOptions{})
+			_, deploymentErr := kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), deployment.Name, metav1.GetOptions{})
+			assert.Nil(t, serviceErr)
+			assert.Nil(t, deploymentErr)
+			_, orphanedServiceErr := kubeClientset.CoreV1().Services(commonconstants.KnativeEventingNamespace).Get(context.TODO(), orphanedService.Name, metav1.GetOptions{})
+			_, orphanedDeploymentErr := kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), orphanedDeployment.Name, metav1.GetOptions{})
+			assert.Equal(t, !testCase.dryRun, orphanedServiceErr != nil)
+			assert.Equal(t, !testCase.dryRun, orphanedDeploymentErr != nil)
+			_, orphanedReceiverDeploymentErr := kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), orphanedReceiverDeployment.Name, metav1.GetOptions{})
+			assert.Equal(t, !testCase.dryRun, orphanedReceiverDeploymentErr != nil)
+			if testCase.dryRun {
+				assert.Empty(t, deletedTopics)
+				assert.Empty(t, deletedGroupIds)
+			} else {
+				assert.Equal(t, orphanedTopics, deletedTopics)
+				assert.Equal(t, []string{orphanedGroupId}, deletedGroupIds)
+			}
+
+			// Verify The Deleted Topics Were Forgotten By The Managed Topics Registry (And The Others Never)
+			registry, err := kubeClientset.CoreV1().ConfigMaps(commonconstants.KnativeEventingNamespace).Get(context.TODO(), constants.ManagedTopicsConfigMapName, metav1.GetOptions{})
+			assert.Nil(t, err)
+			for topicName := range managedTopics {
+				_, recorded := registry.Data[topicName]
+				isOrphan := topicName == orphanedTopics[0] || topicName == orphanedTopics[1]
+				assert.Equal(t, testCase.dryRun || !isOrphan, recorded, topicName)
+			}
+		})
+	}
+}
@@ -18,11 +18,13 @@ package kafkachannel
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	commonk8s "knative.dev/eventing-kafka/pkg/channel/distributed/common/k8s"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
@@ -138,3 +140,22 @@ func (r *Reconciler) reconcileLabels(channel *kafkav1beta1.KafkaChannel) bool {
 	// Return The Modified Status
 	return modified
 }
+
+// Record The Effective Configuration Of The KafkaChannel (Spec Merged With The ConfigMap Settings) As A Status Annotation
+func (r *Reconciler) reconcileEffectiveConfig(channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) error {
+
+	// Resolve & Marshal The Effective Configuration
+	effectiveConfig := util.NewEffectiveConfig(channel, configuration.EventingKafkaConfig, r.logger)
+	effectiveConfigJson, err := json.Marshal(effectiveConfig)
+	if err != nil {
+		r.logger.Error("Failed To Marshal KafkaChannel Effective Config", zap.Error(err))
+		return err
+	}
+
+	// Update The Status Annotation (Persisted Along With The Rest Of The Status)
+	if channel.Status.Annotations == nil {
+		channel.Status.Annotations = make(map[string]string)
+	}
+	channel.Status.Annotations[constants.EffectiveConfigAnnotation] = string(effectiveConfigJson)
+	return nil
+}
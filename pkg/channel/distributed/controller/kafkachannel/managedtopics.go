@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/pkg/system"
+)
+
+//
+// Managed Topics Registry
+//
+// The janitor may only delete topics which the controller positively knows it created, since a shared Kafka cluster
+// may well contain topics which merely look like those of a KafkaChannel.  While the janitor is enabled the controller
+// therefore records every topic it actually creates (never those which already existed) in the data of the
+// eventing-kafka-managed-topics ConfigMap, keyed by the topic name (whose legal characters are also those of
+// ConfigMap keys) with the <namespace>/<name> of the owning KafkaChannel as the value, and forgets it again once the
+// topic has been deleted.
+//
+
+// Record The Specified Topic As Created By The Controller For The Specified KafkaChannel (No-Op Unless The Janitor Is Enabled)
+func (r *Reconciler) recordManagedTopic(ctx context.Context, topicName string, channel *kafkav1beta1.KafkaChannel) {
+	if !r.config.Janitor.Enabled {
+		return
+	}
+	owner := channel.Namespace + "/" + channel.Name
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMaps := r.kubeClientset.CoreV1().ConfigMaps(system.Namespace())
+		configMap, err := configMaps.Get(ctx, constants.ManagedTopicsConfigMapName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.ManagedTopicsConfigMapName, Namespace: system.Namespace()},
+				Data:       map[string]string{topicName: owner},
+			}
+			_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+			if errors.IsAlreadyExists(err) {
+				return errors.NewConflict(corev1.Resource("configmaps"), constants.ManagedTopicsConfigMapName, err) // Retry As An Update
+			}
+			return err
+		} else if err != nil {
+			return err
+		}
+		if configMap.Data[topicName] == owner {
+			return nil
+		}
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[topicName] = owner
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		// The Topic Is Then Simply Never Considered By The Janitor
+		r.logger.Error("Failed To Record Managed Topic", zap.String("Topic", topicName), zap.String("Owner", owner), zap.Error(err))
+	}
+}
+
+// Forget The Specified (Deleted) Topic In The Managed Topics Registry (No-Op Unless The Janitor Is Enabled)
+func (r *Reconciler) forgetManagedTopic(ctx context.Context, topicName string) {
+	if !r.config.Janitor.Enabled {
+		return
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMaps := r.kubeClientset.CoreV1().ConfigMaps(system.Namespace())
+		configMap, err := configMaps.Get(ctx, constants.ManagedTopicsConfigMapName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if _, ok := configMap.Data[topicName]; !ok {
+			return nil
+		}
+		delete(configMap.Data, topicName)
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		r.logger.Error("Failed To Forget Managed Topic", zap.String("Topic", topicName), zap.Error(err))
+	}
+}
+
+// Get The Topics Recorded In The Managed Topics Registry, Mapped To The <namespace>/<name> Of Their KafkaChannel
+func (r *Reconciler) getManagedTopics(ctx context.Context) (map[string]string, error) {
+	configMap, err := r.kubeClientset.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, constants.ManagedTopicsConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return configMap.Data, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+)
+
+// The Default Interval Between Refreshes Of The Subscriber Offsets Exported To The KafkaChannels' Status
+const DefaultOffsetExportInterval = time.Minute
+
+// SubscriberOffsets Is The Snapshot Of The Subscribers' ConsumerGroups Exported To A KafkaChannel's Status
+type SubscriberOffsets struct {
+	UpdatedAt   time.Time                           `json:"updatedAt"`
+	Subscribers map[string]*SubscriberConsumerGroup `json:"subscribers,omitempty"` // Keyed By Subscriber UID
+}
+
+// SubscriberConsumerGroup Is The Committed Offset Of Each Partition Of Each Topic, & The Total Lag, Of A Subscriber
+type SubscriberConsumerGroup struct {
+	ConsumerGroup string                     `json:"consumerGroup"`
+	Committed     map[string]map[int32]int64 `json:"committed,omitempty"`
+	Lag           int64                      `json:"lag"`
+}
+
+// Start Re-Reconciling All KafkaChannels Every Offset Export Interval (No-Op If The Offset Export Is Not Enabled)
+func (r *Reconciler) startOffsetExporter(ctx context.Context, resync func()) {
+	if !r.config.OffsetExport.Enabled {
+		return
+	}
+	interval := r.offsetExportInterval()
+	r.logger.Info("Starting Subscriber Offset Exporter", zap.Duration("Interval", interval))
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				resync()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Get The Configured Interval Between Refreshes Of The Exported Subscriber Offsets
+func (r *Reconciler) offsetExportInterval() time.Duration {
+	interval := time.Duration(r.config.OffsetExport.IntervalMillis) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultOffsetExportInterval
+	}
+	return interval
+}
+
+//
+// Reconcile The Committed Offsets & Lag Of The Specified KafkaChannel's Subscribers Exported To Its Status
+//
+// The snapshot is recorded in the SubscriberOffsetsAnnotation of the KafkaChannel's status so that users can tell
+// whether a subscriber is keeping up with "kubectl get" alone.  It is refreshed when the KafkaChannels are resynced
+// every interval, and otherwise no more than every half interval however often the KafkaChannel is reconciled, in
+// order to limit the load on the Kafka brokers & the status updates.  The lag is that of the partitions on which the
+// subscriber's ConsumerGroup has committed offsets.
+//
+// Failures are logged but never fail the reconciliation, and the previous snapshot is kept.
+//
+func (r *Reconciler) reconcileSubscriberOffsets(ctx context.Context, channel *kafkav1beta1.KafkaChannel) {
+
+	// Remove Any Previously Exported Offsets Unless The Offset Export Is Enabled
+	if !r.config.OffsetExport.Enabled {
+		delete(channel.Status.Annotations, constants.SubscriberOffsetsAnnotation)
+		return
+	}
+
+	// Throttle The Refreshes (Keeping The Previous Snapshot)
+	now := time.Now()
+	previousSnapshot := &SubscriberOffsets{}
+	if err := json.Unmarshal([]byte(channel.Status.Annotations[constants.SubscriberOffsetsAnnotation]), previousSnapshot); err == nil {
+		if now.Sub(previousSnapshot.UpdatedAt) < r.offsetExportInterval()/2 {
+			return
+		}
+	}
+
+	// Skip The Export If The Kafka AdminClient Can't Get Offsets
+	logger := util.ChannelLogger(r.logger, channel)
+	inspector, ok := r.adminClient.(kafkaadmin.OffsetInspector)
+	if !ok {
+		logger.Debug("Kafka AdminClient Can't Get ConsumerGroup Offsets - Skipping Offset Export", zap.String("AdminType", r.config.Kafka.AdminType))
+		return
+	}
+
+	// Get The Committed Offsets & Lag Of Each Subscriber's ConsumerGroup On The KafkaChannel's Topics
+	topicNames := append(channelTopicNames(channel), channel.Spec.ExtraTopics...)
+	snapshot := &SubscriberOffsets{UpdatedAt: now.UTC().Truncate(time.Second), Subscribers: make(map[string]*SubscriberConsumerGroup, len(channel.Spec.Subscribers))}
+	for _, subscriber := range channel.Spec.Subscribers {
+		groupId := kafkautil.GroupId(string(subscriber.UID))
+		consumerGroupOffsets, err := inspector.ConsumerGroupOffsets(ctx, groupId, topicNames)
+		if err != nil {
+			logger.Warn("Failed To Get ConsumerGroup Offsets - Skipping Offset Export", zap.String("ConsumerGroup", groupId), zap.Error(err))
+			return
+		}
+		snapshot.Subscribers[string(subscriber.UID)] = &SubscriberConsumerGroup{
+			ConsumerGroup: groupId,
+			Committed:     consumerGroupOffsets.Committed,
+			Lag:           consumerGroupOffsets.Lag,
+		}
+	}
+
+	// Update The Status Annotation (Persisted Along With The Rest Of The Status)
+	snapshotJson, err := json.Marshal(snapshot)
+	if err != nil {
+		logger.Error("Failed To Marshal Subscriber Offsets", zap.Error(err))
+		return
+	}
+	if channel.Status.Annotations == nil {
+		channel.Status.Annotations = make(map[string]string)
+	}
+	channel.Status.Annotations[constants.SubscriberOffsetsAnnotation] = string(snapshotJson)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciler's reconcileSubscriberOffsets() Functionality
+func TestReconcileSubscriberOffsets(t *testing.T) {
+
+	// Test Data
+	subscriberUID := types.UID("test-subscriber-uid")
+	groupId := kafkautil.GroupId(string(subscriberUID))
+	newChannel := func(snapshot string) *kafkav1beta1.KafkaChannel {
+		channel := controllertesting.NewKafkaChannel()
+		channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: subscriberUID}}
+		if len(snapshot) > 0 {
+			channel.Status.Annotations = map[string]string{constants.SubscriberOffsetsAnnotation: snapshot}
+		}
+		return channel
+	}
+	topicName := util.TopicName(newChannel(""))
+	mockAdminClient := &controllertesting.MockAdminClient{
+		MockConsumerGroupOffsets: map[string]*kafkaadmin.ConsumerGroupOffsets{
+			groupId: {Committed: map[string]map[int32]int64{topicName: {0: 5, 1: 7}}, Lag: 3},
+		},
+	}
+	recentSnapshot := `{"updatedAt":"` + time.Now().UTC().Format(time.RFC3339) + `"}`
+	staleSnapshot := `{"updatedAt":"` + time.Now().Add(-time.Hour).UTC().Format(time.RFC3339) + `"}`
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name          string
+		enabled       bool
+		adminClient   kafkaadmin.TopicProvisioner
+		channel       *kafkav1beta1.KafkaChannel
+		wantSnapshot  bool
+		wantUnchanged bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Disabled", adminClient: mockAdminClient, channel: newChannel(staleSnapshot)},
+		{name: "No Previous Snapshot", enabled: true, adminClient: mockAdminClient, channel: newChannel(""), wantSnapshot: true},
+		{name: "Stale Snapshot", enabled: true, adminClient: mockAdminClient, channel: newChannel(staleSnapshot), wantSnapshot: true},
+		{name: "Recent Snapshot", enabled: true, adminClient: mockAdminClient, channel: newChannel(recentSnapshot), wantUnchanged: true},
+		{name: "No Offset Inspector", enabled: true, adminClient: nil, channel: newChannel(staleSnapshot), wantUnchanged: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Reconciler
+			configuration := controllertesting.NewConfig()
+			configuration.OffsetExport.Enabled = testCase.enabled
+			r := &Reconciler{
+				logger:      logtesting.TestLogger(t).Desugar(),
+				config:      configuration,
+				adminClient: testCase.adminClient,
+			}
+			previousSnapshot, hadSnapshot := testCase.channel.Status.Annotations[constants.SubscriberOffsetsAnnotation]
+
+			// Perform The Test
+			r.reconcileSubscriberOffsets(context.TODO(), testCase.channel)
+
+			// Verify The Results
+			snapshotJson, ok := testCase.channel.Status.Annotations[constants.SubscriberOffsetsAnnotation]
+			switch {
+			case testCase.wantUnchanged:
+				assert.Equal(t, hadSnapshot, ok)
+				assert.Equal(t, previousSnapshot, snapshotJson)
+			case testCase.wantSnapshot:
+				assert.True(t, ok)
+				snapshot := &SubscriberOffsets{}
+				assert.Nil(t, json.Unmarshal([]byte(snapshotJson), snapshot))
+				assert.WithinDuration(t, time.Now(), snapshot.UpdatedAt, time.Minute)
+				assert.Equal(t, map[string]*SubscriberConsumerGroup{
+					string(subscriberUID): {ConsumerGroup: groupId, Committed: map[string]map[int32]int64{topicName: {0: 5, 1: 7}}, Lag: 3},
+				}, snapshot.Subscribers)
+			default:
+				assert.False(t, ok)
+			}
+		})
+	}
+}
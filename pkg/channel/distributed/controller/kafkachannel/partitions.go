@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/pkg/controller"
+)
+
+// KafkaChannel PartitionsSufficient Condition Reasons
+const (
+	PartitionsBottleneckReason = "PartitionsBottleneck"
+	PartitionsExpandedReason   = "PartitionsExpanded"
+)
+
+//
+// Reconcile The Partition Count Of The Specified Channel's Kafka Topic Against Its Observed Throughput
+//
+// The dispatcher metrics summarized by the metrics aggregator reveal whether the channel's partitions are limiting
+// the dispatch throughput of any subscription (see aggregator.ChannelSummary.IncomingRatio()).  If so, a warning
+// condition & event recommending a partition count are produced, and when AutoExpand is enabled the partitions of
+// the topic (and of any event type sub-topics) are expanded up to the configured MaxPartitions.  Partitions can
+// never be reduced, and expanding them changes the partition to which keyed events are routed, which is why the
+// expansion is opt-in.  The channel's spec is not modified, so the partition count is the greater of the spec's &
+// the number of partitions observed by the dispatchers.
+//
+// Failures are reported on the channel but never fail the reconciliation, as the channel remains functional.
+//
+func (r *Reconciler) reconcilePartitions(ctx context.Context, channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) {
+
+	// Nothing To Do Unless The Partition Advisor Is Enabled
+	advisorConfig := configuration.MetricsAggregator.PartitionAdvisor
+	if !advisorConfig.Enabled {
+		channel.Status.ClearPartitionsCondition()
+		return
+	}
+
+	// Get The Channel's Latest Dispatcher Metrics Summary (None Until Its Dispatcher Has Been Scraped)
+	if r.channelSummary == nil {
+		return
+	}
+	summary := r.channelSummary(channel.Namespace, channel.Name)
+	if summary == nil {
+		return
+	}
+
+	// Determine The Current & Recommended Partition Counts
+	partitions := util.NumPartitions(channel, configuration.EventingKafkaConfig, r.logger)
+	if int32(summary.Partitions) > partitions {
+		partitions = int32(summary.Partitions)
+	}
+	recommendedPartitions := summary.RecommendedPartitions(partitions)
+	if recommendedPartitions <= partitions {
+		channel.Status.MarkPartitionsSufficient()
+		return
+	}
+
+	// Get Channel Specific Logger
+	topicName := util.TopicName(channel)
+	logger := util.ChannelLogger(r.logger, channel).With(zap.String("TopicName", topicName),
+		zap.Int32("Partitions", partitions), zap.Int32("RecommendedPartitions", recommendedPartitions))
+
+	// Only Warn Of The Bottleneck Unless AutoExpand Is Enabled & The Partitions Are Below The Maximum
+	if !advisorConfig.AutoExpand || partitions >= advisorConfig.MaxPartitions {
+		logger.Warn("Kafka Topic Partitions Are A Bottleneck")
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.KafkaTopicPartitionsInsufficient.String(),
+			"Consumer Lag Is Growing With %d Partitions - %d Partitions Recommended", partitions, recommendedPartitions)
+		channel.Status.MarkPartitionsInsufficient(PartitionsBottleneckReason, "Consumer lag is growing with %d partitions - %d partitions recommended", partitions, recommendedPartitions)
+		return
+	}
+
+	// Expand The Partitions Up To The Configured Maximum
+	targetPartitions := recommendedPartitions
+	if targetPartitions > advisorConfig.MaxPartitions {
+		targetPartitions = advisorConfig.MaxPartitions
+	}
+	err := r.expandPartitions(ctx, channel, topicName, targetPartitions)
+	if err != nil {
+		logger.Error("Failed To Expand Kafka Topic Partitions", zap.Int32("TargetPartitions", targetPartitions), zap.Error(err))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.KafkaTopicPartitionsExpansionFailed.String(),
+			"Failed To Expand Kafka Topic Partitions From %d To %d: %v", partitions, targetPartitions, err)
+		channel.Status.MarkPartitionsInsufficient(PartitionsBottleneckReason, "Consumer lag is growing with %d partitions - failed to expand to %d partitions: %v", partitions, targetPartitions, err)
+		return
+	}
+	logger.Info("Successfully Expanded Kafka Topic Partitions", zap.Int32("TargetPartitions", targetPartitions))
+	controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeNormal, event.KafkaTopicPartitionsExpanded.String(),
+		"Expanded Kafka Topic Partitions From %d To %d", partitions, targetPartitions)
+	channel.Status.MarkPartitionsInsufficient(PartitionsExpandedReason, "Consumer lag was growing with %d partitions - expanded to %d partitions", partitions, targetPartitions)
+}
+
+// Expand The Partitions Of The Specified Channel's Kafka Topic & Any Event Type Sub-Topics (Never Reducing Them)
+func (r *Reconciler) expandPartitions(ctx context.Context, channel *kafkav1beta1.KafkaChannel, topicName string, partitions int32) error {
+
+	// Get The Channel's EventTypeRouting From Its Annotations
+	eventTypeRouting, err := routing.NewEventTypeRouting(channel.Annotations)
+	if err != nil {
+		return err
+	}
+
+	// Expand Each Topic (Already Having At Least The Partitions Results In ErrInvalidPartitions)
+	for _, name := range append([]string{topicName}, eventTypeRouting.Topics(topicName)...) {
+		topicErr := r.adminClient.AlterTopic(ctx, name, &sarama.TopicDetail{NumPartitions: partitions})
+		if topicErr != nil && topicErr.Err != sarama.ErrNoError && topicErr.Err != sarama.ErrInvalidPartitions {
+			return topicErr
+		} else if topicErr == nil || topicErr.Err == sarama.ErrNoError {
+			r.auditTopic(audit.ActionUpdated, name, map[string]string{"partitions": strconv.Itoa(int(partitions))})
+		}
+	}
+	return nil
+}
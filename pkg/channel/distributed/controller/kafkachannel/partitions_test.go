@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/aggregator"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciler's reconcilePartitions() Functionality
+func TestReconcilePartitions(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name             string
+		advisorConfig    config.EKPartitionAdvisorConfig
+		summary          *aggregator.ChannelSummary
+		createPartitions *sarama.TopicError
+		wantPartitions   int32
+		wantStatus       corev1.ConditionStatus
+		wantReason       string
+		wantEvent        string
+		wantNoCondition  bool
+	}
+
+	// Test Data
+	advisorConfig := config.EKPartitionAdvisorConfig{Enabled: true}
+	autoExpandConfig := config.EKPartitionAdvisorConfig{Enabled: true, AutoExpand: true, MaxPartitions: 150}
+	keepingUp := &aggregator.ChannelSummary{Subscriptions: map[string]*aggregator.SubscriptionSummary{"sub-1": {EventsPerSecond: 100}}}
+	fallingBehind := &aggregator.ChannelSummary{Subscriptions: map[string]*aggregator.SubscriptionSummary{"sub-1": {EventsPerSecond: 100, LagPerSecond: 50}}}
+	fallingBehindMore := &aggregator.ChannelSummary{Partitions: 150, Subscriptions: fallingBehind.Subscriptions}
+
+	// Create The TestCases (The Test KafkaChannel Has 123 Partitions)
+	testCases := []TestCase{
+		{name: "Advisor Disabled", summary: fallingBehind, wantNoCondition: true},
+		{name: "Not Yet Scraped", advisorConfig: advisorConfig, wantNoCondition: true},
+		{name: "Partitions Sufficient", advisorConfig: advisorConfig, summary: keepingUp, wantStatus: corev1.ConditionTrue},
+		{name: "Partitions Bottleneck", advisorConfig: advisorConfig, summary: fallingBehind, wantStatus: corev1.ConditionFalse, wantReason: PartitionsBottleneckReason,
+			wantEvent: "Warning " + event.KafkaTopicPartitionsInsufficient.String() + " Consumer Lag Is Growing With 123 Partitions - 185 Partitions Recommended"},
+		{name: "Partitions Expanded", advisorConfig: autoExpandConfig, summary: fallingBehind, wantPartitions: 150, wantStatus: corev1.ConditionFalse, wantReason: PartitionsExpandedReason,
+			wantEvent: "Normal " + event.KafkaTopicPartitionsExpanded.String() + " Expanded Kafka Topic Partitions From 123 To 150"},
+		{name: "Partitions Already Expanded", advisorConfig: autoExpandConfig, summary: fallingBehind, createPartitions: &sarama.TopicError{Err: sarama.ErrInvalidPartitions}, wantPartitions: 150,
+			wantStatus: corev1.ConditionFalse, wantReason: PartitionsExpandedReason, wantEvent: "Normal " + event.KafkaTopicPartitionsExpanded.String()},
+		{name: "Partitions Expansion Failed", advisorConfig: autoExpandConfig, summary: fallingBehind, createPartitions: &sarama.TopicError{Err: sarama.ErrClusterAuthorizationFailed}, wantPartitions: 150,
+			wantStatus: corev1.ConditionFalse, wantReason: PartitionsBottleneckReason, wantEvent: "Warning " + event.KafkaTopicPartitionsExpansionFailed.String()},
+		{name: "Partitions At Maximum", advisorConfig: autoExpandConfig, summary: fallingBehindMore, wantStatus: corev1.ConditionFalse, wantReason: PartitionsBottleneckReason,
+			wantEvent: "Warning " + event.KafkaTopicPartitionsInsufficient.String() + " Consumer Lag Is Growing With 150 Partitions - 225 Partitions Recommended"},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Mock AdminClient Tracking The Requested Partitions
+			var requestedPartitions int32
+			mockAdminClient := &controllertesting.MockAdminClient{
+				MockAlterTopicFunc: func(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
+					assert.Equal(t, controllertesting.TopicName, topicName)
+					requestedPartitions = topicDetail.NumPartitions
+					return testCase.createPartitions
+				},
+			}
+
+			// Create The Reconciler With The TestCase's ChannelSummary
+			configuration := controllertesting.NewConfig()
+			configuration.MetricsAggregator.PartitionAdvisor = testCase.advisorConfig
+			r := &Reconciler{
+				logger:      logtesting.TestLogger(t).Desugar(),
+				adminClient: mockAdminClient,
+				config:      configuration,
+				channelSummary: func(namespace string, name string) *aggregator.ChannelSummary {
+					return testCase.summary
+				},
+			}
+
+			// Perform The Test
+			channel := controllertesting.NewKafkaChannel()
+			recorder := record.NewFakeRecorder(1)
+			r.reconcilePartitions(controller.WithEventRecorder(context.TODO(), recorder), channel, &config.NamespaceConfig{EventingKafkaConfig: configuration})
+
+			// Verify The Results
+			assert.Equal(t, testCase.wantPartitions > 0, mockAdminClient.AlterTopicCalled())
+			assert.Equal(t, testCase.wantPartitions, requestedPartitions)
+			condition := channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionPartitionsSufficient)
+			if testCase.wantNoCondition {
+				assert.Nil(t, condition)
+			} else {
+				assert.NotNil(t, condition)
+				assert.Equal(t, testCase.wantStatus, condition.Status)
+				assert.Equal(t, testCase.wantReason, condition.Reason)
+			}
+			if len(testCase.wantEvent) > 0 {
+				assert.Contains(t, <-recorder.Events, testCase.wantEvent)
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/receiver"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/system"
+)
+
+//
+// Reconcile The Isolated Receiver (Kafka Producer) For The Specified KafkaChannel
+//
+// KafkaChannels normally share the Receiver of their Kafka Secret (reconciled by the KafkaSecret controller), but may
+// be isolated in a dedicated Receiver of their own so that the ingress load and failures of one channel cannot affect
+// the others.  Any isolated Receiver of a channel which is no longer isolated (or was named by a former NameStrategy)
+// is removed.  A Receiver combined with the channel's Dispatcher is reconciled along with the Dispatcher instead.
+//
+func (r *Reconciler) reconcileReceiver(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
+
+	// Get Channel Specific Logger
+	logger := util.ChannelLogger(r.logger, channel)
+
+	// Reconcile The Isolated Receiver's Service & Deployment
+	var serviceErr, deploymentErr error
+	receiverName := ""
+	if r.isReceiverIsolated(channel) && !r.isReceiverCombined(channel) {
+		receiverName = r.receiverName(channel)
+
+		serviceErr = r.reconcileReceiverService(ctx, channel)
+		if serviceErr != nil {
+			controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.ReceiverServiceReconciliationFailed.String(), "Failed To Reconcile Receiver Service: %v", serviceErr)
+			logger.Error("Failed To Reconcile Receiver Service", zap.Error(serviceErr))
+			channel.Status.MarkServiceFailed(event.ReceiverServiceReconciliationFailed.String(), "Failed To Reconcile Receiver Service: %v", serviceErr)
+		} else {
+			logger.Info("Successfully Reconciled Receiver Service")
+			channel.Status.MarkServiceTrue()
+		}
+
+		deploymentErr = r.reconcileReceiverDeployment(ctx, channel)
+		if deploymentErr != nil {
+			controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.ReceiverDeploymentReconciliationFailed.String(), "Failed To Reconcile Receiver Deployment: %v", deploymentErr)
+			logger.Error("Failed To Reconcile Receiver Deployment", zap.Error(deploymentErr))
+			channel.Status.MarkEndpointsFailed(event.ReceiverDeploymentReconciliationFailed.String(), "Failed To Reconcile Receiver Deployment: %v", deploymentErr)
+		} else {
+			logger.Info("Successfully Reconciled Receiver Deployment")
+			channel.Status.MarkEndpointsTrue()
+		}
+	}
+
+	// Remove Any Other Isolated Receiver Of The Channel
+	removalErr := r.deleteIsolatedReceivers(ctx, channel, receiverName)
+	if removalErr != nil {
+		logger.Error("Failed To Delete Former Isolated Receiver", zap.Error(removalErr))
+	}
+
+	// Return Results
+	if serviceErr != nil || deploymentErr != nil || removalErr != nil {
+		return fmt.Errorf("failed to reconcile receiver resources")
+	} else {
+		return nil
+	}
+}
+
+// Determine Whether The Specified Channel Has An Isolated Receiver Of Its Own
+func (r *Reconciler) isReceiverIsolated(channel *kafkav1beta1.KafkaChannel) bool {
+	return util.IsReceiverIsolated(channel, r.config.Receiver.Isolation)
+}
+
+// Determine Whether The Specified Channel's Isolated Receiver Runs In The Pod Of Its Dispatcher
+func (r *Reconciler) isReceiverCombined(channel *kafkav1beta1.KafkaChannel) bool {
+	return util.IsReceiverCombined(channel, r.config.Receiver.Isolation)
+}
+
+// Get The Name Of The Receiver Service & Deployment Of The Specified Channel (Shared Per Kafka Secret Unless Isolated,
+// And The Dispatcher's If Combined With It)
+func (r *Reconciler) receiverName(channel *kafkav1beta1.KafkaChannel) string {
+	if r.isReceiverCombined(channel) {
+		return r.dispatcherName(channel)
+	}
+	if r.isReceiverIsolated(channel) {
+		return util.ChannelReceiverDnsSafeName(channel, r.config.Naming.Strategy)
+	}
+	return util.ReceiverDnsSafeName(r.kafkaSecretName(channel))
+}
+
+// Create The Isolated Receiver Model Of The Specified Channel
+func (r *Reconciler) newIsolatedReceiver(channel *kafkav1beta1.KafkaChannel) receiver.Receiver {
+	return receiver.Receiver{
+		Name:            util.ChannelReceiverDnsSafeName(channel, r.config.Naming.Strategy),
+		KafkaSecretName: r.kafkaSecretName(channel),
+		OwnerReference:  util.NewChannelOwnerReference(channel),
+		Labels: map[string]string{
+			constants.KafkaChannelIsolatedReceiverLabel: "true",            // Identifies the Receiver as being Isolated to a single KafkaChannel
+			constants.KafkaChannelNameLabel:             channel.Name,      // Identifies the Receiver's Owning KafkaChannel's Name
+			constants.KafkaChannelNamespaceLabel:        channel.Namespace, // Identifies the Receiver's Owning KafkaChannel's Namespace
+		},
+	}
+}
+
+// Reconcile The Isolated Receiver Service
+func (r *Reconciler) reconcileReceiverService(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
+
+	// Attempt To Get The Isolated Receiver Service Associated With The Specified Channel
+	_, err := r.serviceLister.Services(system.Namespace()).Get(r.receiverName(channel))
+	if err != nil {
+
+		// If The Service Was Not Found - Then Create A New One For The Channel
+		if errors.IsNotFound(err) {
+			r.logger.Info("Receiver Service Not Found - Creating New One")
+			service := receiver.NewService(r.newIsolatedReceiver(channel), r.environment)
+			_, err = r.kubeClientset.CoreV1().Services(service.Namespace).Create(ctx, service, metav1.CreateOptions{})
+			if err != nil {
+				r.logger.Error("Failed To Create Receiver Service", zap.Error(err))
+				return err
+			}
+			r.logger.Info("Successfully Created Receiver Service")
+			return nil
+		}
+		r.logger.Error("Failed To Get Receiver Service", zap.Error(err))
+		return err
+	}
+	r.logger.Info("Successfully Verified Receiver Service")
+	return nil
+}
+
+// Reconcile The Isolated Receiver Deployment
+func (r *Reconciler) reconcileReceiverDeployment(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
+
+	// Attempt To Get The Isolated Receiver Deployment Associated With The Specified Channel
+	_, err := r.deploymentLister.Deployments(system.Namespace()).Get(r.receiverName(channel))
+	if err != nil {
+
+		// If The Deployment Was Not Found - Then Create A New One For The Channel
+		if errors.IsNotFound(err) {
+			r.logger.Info("Receiver Deployment Not Found - Creating New One")
+			deployment, err := receiver.NewDeployment(r.newIsolatedReceiver(channel), r.config, r.environment)
+			if err != nil {
+				r.logger.Error("Failed To Create Receiver Deployment YAML", zap.Error(err))
+				return err
+			}
+			_, err = r.kubeClientset.AppsV1().Deployments(deployment.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+			if err != nil {
+				r.logger.Error("Failed To Create Receiver Deployment", zap.Error(err))
+				return err
+			}
+			r.logger.Info("Successfully Created Receiver Deployment")
+			r.auditDeployment(audit.ActionCreated, deployment.Name, channel, map[string]string{"image": deployment.Spec.Template.Spec.Containers[0].Image})
+			return nil
+		}
+		r.logger.Error("Failed To Get Receiver Deployment", zap.Error(err))
+		return err
+	}
+	r.logger.Info("Successfully Verified Receiver Deployment")
+	return nil
+}
+
+// Delete The Isolated Receiver Services & Deployments Of The Specified Channel Other Than The Named One (All Of Them
+// If The Name Is Empty), Left Over From Before The Receiver Isolation Or NameStrategy Was Changed
+func (r *Reconciler) deleteIsolatedReceivers(ctx context.Context, channel *kafkav1beta1.KafkaChannel, receiverName string) error {
+
+	// Select The Isolated Receivers Of The Channel
+	selector := labels.SelectorFromSet(map[string]string{
+		constants.KafkaChannelIsolatedReceiverLabel: "true",
+		constants.KafkaChannelNameLabel:             channel.Name,
+		constants.KafkaChannelNamespaceLabel:        channel.Namespace,
+	})
+
+	services, err := r.serviceLister.Services(system.Namespace()).List(selector)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		if service.Name != receiverName {
+			err = r.kubeClientset.CoreV1().Services(service.Namespace).Delete(ctx, service.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			r.logger.Info("Deleted Former Isolated Receiver Service", zap.String("Service", service.Name))
+		}
+	}
+
+	deployments, err := r.deploymentLister.Deployments(system.Namespace()).List(selector)
+	if err != nil {
+		return err
+	}
+	for _, deployment := range deployments {
+		if deployment.Name != receiverName {
+			err = r.kubeClientset.AppsV1().Deployments(deployment.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			r.logger.Info("Deleted Former Isolated Receiver Deployment", zap.String("Deployment", deployment.Name))
+			r.auditDeployment(audit.ActionDeleted, deployment.Name, channel, map[string]string{"reason": "receiver isolation or NameStrategy changed"})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/network"
+)
+
+// Test The Reconciler's reconcileReceiver() Functionality
+func TestReconcileReceiver(t *testing.T) {
+
+	// Test Data
+	channel := controllertesting.NewKafkaChannel()
+	truncateName := util.ChannelReceiverDnsSafeName(channel, util.NameStrategyTruncate)
+	hashName := util.ChannelReceiverDnsSafeName(channel, util.NameStrategyHash)
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name            string
+		isolation       string
+		annotation      string
+		expectedCreated bool
+		expectedDeleted bool
+	}
+
+	// Create The TestCases (An Isolated Receiver Named By The "hash" Strategy Exists, The "truncate" Strategy Is Configured)
+	testCases := []TestCase{
+		{name: "Not Isolated", isolation: util.ReceiverIsolationSecret, expectedDeleted: true},
+		{name: "Isolated By Config", isolation: util.ReceiverIsolationChannel, expectedCreated: true, expectedDeleted: true},
+		{name: "Isolated By Annotation", annotation: util.ReceiverIsolationChannel, expectedCreated: true, expectedDeleted: true},
+		{name: "Not Isolated By Annotation", isolation: util.ReceiverIsolationChannel, annotation: util.ReceiverIsolationSecret, expectedDeleted: true},
+		{name: "Combined With Dispatcher", isolation: util.ReceiverIsolationCombined, expectedDeleted: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Channel With The TestCase's Annotation
+			channel := controllertesting.NewKafkaChannel()
+			if testCase.annotation != "" {
+				channel.Annotations = map[string]string{kafkaconstants.ReceiverIsolationAnnotation: testCase.annotation}
+			}
+
+			// Create The Former (Hash Named) Isolated Receiver Service & Deployment Of The Channel
+			formerService := controllertesting.NewKafkaChannelReceiverService()
+			formerService.Name = hashName
+			formerService.Labels = isolatedReceiverLabels(channel)
+			formerDeployment := controllertesting.NewKafkaChannelReceiverDeployment()
+			formerDeployment.Name = hashName
+			formerDeployment.Labels = isolatedReceiverLabels(channel)
+			serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			assert.Nil(t, serviceIndexer.Add(formerService))
+			deploymentIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			assert.Nil(t, deploymentIndexer.Add(formerDeployment))
+
+			// Create The Reconciler With The TestCase's Isolation
+			configuration := controllertesting.NewConfig()
+			configuration.Naming.Strategy = util.NameStrategyTruncate
+			configuration.Receiver.Isolation = testCase.isolation
+			kubeClientset := fake.NewSimpleClientset([]runtime.Object{formerService, formerDeployment}...)
+			r := &Reconciler{
+				logger:           logtesting.TestLogger(t).Desugar(),
+				kubeClientset:    kubeClientset,
+				adminClient:      &controllertesting.MockAdminClient{},
+				environment:      controllertesting.NewEnvironment(),
+				config:           configuration,
+				serviceLister:    corev1listers.NewServiceLister(serviceIndexer),
+				deploymentLister: appsv1listers.NewDeploymentLister(deploymentIndexer),
+			}
+
+			// Perform The Test
+			err := r.reconcileReceiver(context.TODO(), channel)
+
+			// Verify The Isolated Receiver Was Created (Labelled For The Channel) & The Status Updated
+			assert.Nil(t, err)
+			service, serviceErr := kubeClientset.CoreV1().Services(commonconstants.KnativeEventingNamespace).Get(context.TODO(), truncateName, metav1.GetOptions{})
+			deployment, deploymentErr := kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), truncateName, metav1.GetOptions{})
+			assert.Equal(t, testCase.expectedCreated, serviceErr == nil)
+			assert.Equal(t, testCase.expectedCreated, deploymentErr == nil)
+			if testCase.expectedCreated {
+				assert.Equal(t, "true", service.Labels[constants.KafkaChannelIsolatedReceiverLabel])
+				assert.Equal(t, channel.Name, deployment.Labels[constants.KafkaChannelNameLabel])
+				assert.Equal(t, []metav1.OwnerReference{util.NewChannelOwnerReference(channel)}, deployment.OwnerReferences)
+				assert.True(t, channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionServiceReady).IsTrue())
+				assert.True(t, channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionEndpointsReady).IsTrue())
+			}
+
+			// Verify The Former Isolated Receiver Was Deleted
+			_, formerServiceErr := kubeClientset.CoreV1().Services(commonconstants.KnativeEventingNamespace).Get(context.TODO(), hashName, metav1.GetOptions{})
+			_, formerDeploymentErr := kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), hashName, metav1.GetOptions{})
+			assert.Equal(t, testCase.expectedDeleted, formerServiceErr != nil)
+			assert.Equal(t, testCase.expectedDeleted, formerDeploymentErr != nil)
+		})
+	}
+}
+
+// Test The Reconciler's updateKafkaChannelServiceExternalName() Functionality
+func TestUpdateKafkaChannelServiceExternalName(t *testing.T) {
+
+	// Create The Existing KafkaChannel Service (Referencing The Kafka Secret's Receiver)
+	channel := controllertesting.NewKafkaChannel()
+	service := controllertesting.NewKafkaChannelService()
+
+	// Create The Reconciler With An Isolated Receiver
+	configuration := controllertesting.NewConfig()
+	configuration.Receiver.Isolation = util.ReceiverIsolationChannel
+	kubeClientset := fake.NewSimpleClientset([]runtime.Object{service}...)
+	r := &Reconciler{
+		logger:        logtesting.TestLogger(t).Desugar(),
+		kubeClientset: kubeClientset,
+		adminClient:   &controllertesting.MockAdminClient{},
+		config:        configuration,
+	}
+
+	// Perform The Test
+	updatedService, err := r.updateKafkaChannelServiceExternalName(context.TODO(), channel, service)
+
+	// Verify The Service Now References The Isolated Receiver
+	assert.Nil(t, err)
+	expectedExternalName := network.GetServiceHostname(util.ChannelReceiverDnsSafeName(channel, configuration.Naming.Strategy), commonconstants.KnativeEventingNamespace)
+	assert.Equal(t, expectedExternalName, updatedService.Spec.ExternalName)
+	storedService, err := kubeClientset.CoreV1().Services(service.Namespace).Get(context.TODO(), service.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, expectedExternalName, storedService.Spec.ExternalName)
+	assert.Equal(t, corev1.ServiceTypeExternalName, storedService.Spec.Type)
+
+	// Verify An Up To Date Service Is Not Updated Again
+	unchangedService, err := r.updateKafkaChannelServiceExternalName(context.TODO(), channel, updatedService)
+	assert.Nil(t, err)
+	assert.Same(t, updatedService, unchangedService)
+}
+
+// Test The Reconciler's channelAddress() Functionality With Host-Based & Path-Based Addressing
+func TestChannelAddress(t *testing.T) {
+	channel := controllertesting.NewKafkaChannel()
+	service := controllertesting.NewKafkaChannelService()
+	configuration := controllertesting.NewConfig()
+	r := &Reconciler{
+		logger:      logtesting.TestLogger(t).Desugar(),
+		adminClient: &controllertesting.MockAdminClient{},
+		config:      configuration,
+	}
+
+	// Host-Based Addressing Is The Default
+	for _, addressing := range []string{"", kafkaconstants.ReceiverAddressingHost} {
+		configuration.Receiver.Addressing = addressing
+		address := r.channelAddress(channel, service)
+		assert.Equal(t, "http://"+network.GetServiceHostname(service.Name, service.Namespace), address.String())
+	}
+
+	// Path-Based Addresses Are On The Channel's Receiver Service
+	configuration.Receiver.Addressing = kafkaconstants.ReceiverAddressingPath
+	address := r.channelAddress(channel, service)
+	expectedHost := network.GetServiceHostname(controllertesting.ReceiverServiceName, commonconstants.KnativeEventingNamespace)
+	assert.Equal(t, "http://"+expectedHost+"/"+channel.Namespace+"/"+channel.Name, address.String())
+	namespace, name, ok := kafkautil.KafkaChannelOfURL(address)
+	assert.True(t, ok)
+	assert.Equal(t, channel.Namespace, namespace)
+	assert.Equal(t, channel.Name, name)
+	configuration.Receiver.Isolation = util.ReceiverIsolationChannel
+	address = r.channelAddress(channel, service)
+	assert.Equal(t, network.GetServiceHostname(util.ChannelReceiverDnsSafeName(channel, configuration.Naming.Strategy), commonconstants.KnativeEventingNamespace), address.Host)
+}
+
+// Get The Labels Identifying An Isolated Receiver Of The Specified Channel
+func isolatedReceiverLabels(channel *kafkav1beta1.KafkaChannel) map[string]string {
+	return map[string]string{
+		constants.KafkaChannelIsolatedReceiverLabel: "true",
+		constants.KafkaChannelNameLabel:             channel.Name,
+		constants.KafkaChannelNamespaceLabel:        channel.Namespace,
+	}
+}
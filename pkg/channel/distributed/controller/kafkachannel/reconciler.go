@@ -24,6 +24,7 @@ import (
 	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
@@ -31,7 +32,11 @@ import (
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
 	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/aggregator"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
@@ -41,6 +46,7 @@ import (
 	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/tracker"
 )
 
 // Reconciler Implements controller.Reconciler for KafkaChannel Resources
@@ -48,8 +54,7 @@ type Reconciler struct {
 	logger               *zap.Logger
 	kubeClientset        kubernetes.Interface
 	kafkaClientSet       kafkaclientset.Interface
-	adminClientType      kafkaadmin.AdminClientType
-	adminClient          kafkaadmin.AdminClientInterface
+	adminClient          kafkaadmin.TopicProvisioner
 	environment          *env.Environment
 	config               *config.EventingKafkaConfig
 	saramaConfig         *sarama.Config
@@ -57,7 +62,14 @@ type Reconciler struct {
 	kafkachannelInformer cache.SharedIndexInformer
 	deploymentLister     appsv1listers.DeploymentLister
 	serviceLister        corev1listers.ServiceLister
+	namespaceSettings    corev1listers.ConfigMapLister
+	channelClasses       corev1listers.ConfigMapLister
 	configObserver       func(configMap *corev1.ConfigMap)
+	channelSummary       func(namespace string, name string) *aggregator.ChannelSummary
+	deploymentResources  func(deploymentName string) *aggregator.DeploymentResources
+	dynamicClient        dynamic.Interface
+	auditRecorder        audit.Recorder
+	tracker              tracker.Interface
 	adminMutex           *sync.Mutex
 }
 
@@ -81,8 +93,12 @@ var (
 //
 func (r *Reconciler) SetKafkaAdminClient(ctx context.Context) {
 	r.ClearKafkaAdminClient()
-	var err error
-	r.adminClient, err = kafkaadmin.CreateAdminClient(ctx, r.saramaConfig, constants.ControllerComponentName, r.adminClientType)
+	clientId, err := kafkasarama.NewClientId(r.config.Kafka, constants.ControllerComponentName, "", r.environment.PodName)
+	if err != nil {
+		r.logger.Error("Invalid Kafka ClientIdTemplate - Using Controller Component Name", zap.Error(err))
+		clientId = constants.ControllerComponentName
+	}
+	r.adminClient, err = kafkaadmin.CreateAdminClient(ctx, r.saramaConfig, clientId, r.config.Kafka)
 	if err != nil {
 		r.logger.Error("Failed To Create Kafka AdminClient", zap.Error(err))
 	}
@@ -121,6 +137,10 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, channel *kafkav1beta1.Ka
 	// Perform The KafkaChannel Reconciliation & Handle Error Response
 	r.logger.Info("Channel Owned By Controller - Reconciling", zap.Any("Channel.Spec", channel.Spec))
 	err := r.reconcile(ctx, channel)
+
+	// Summarize The Channel's Ready Condition By Its Most Fundamental Failure (If Any)
+	channel.Status.SummarizeReady()
+
 	if err != nil {
 		r.logger.Error("Failed To Reconcile KafkaChannel", zap.Any("Channel", channel), zap.Error(err))
 		return err
@@ -151,8 +171,37 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, channel *kafkav1beta1.Kaf
 	// Get The Kafka Topic Name For Specified Channel
 	topicName := util.TopicName(channel)
 
+	// Delete Any Event Type Sub-Topics (Ignoring Invalid Routing Annotations Which Never Created Any)
+	eventTypeRouting, _ := routing.NewEventTypeRouting(channel.Annotations)
+	for _, eventTypeTopicName := range eventTypeRouting.Topics(topicName) {
+		err := r.deleteTopic(ctx, eventTypeTopicName)
+		if err != nil {
+			r.logger.Error("Failed To Finalize KafkaChannel", zap.Any("Channel", channel), zap.Error(err))
+			return err
+		}
+	}
+
+	// Delete The Convention-Named DeadLetter Topics Of Current Subscribers (Never Those Of DeadLetter KafkaChannels)
+	for i := range channel.Spec.Subscribers {
+		subscriber := &channel.Spec.Subscribers[i]
+		if subscriber.Delivery != nil && subscriber.Delivery.DeadLetterSink != nil && kafkautil.IsDeadLetterTopicShorthand(subscriber.Delivery.DeadLetterSink.URI) {
+			err := r.deleteTopic(ctx, kafkautil.DeadLetterTopicName(topicName, string(subscriber.UID)))
+			if err != nil {
+				r.logger.Error("Failed To Finalize KafkaChannel", zap.Any("Channel", channel), zap.Error(err))
+				return err
+			}
+		}
+	}
+
+	// Delete Any Quarantine Topic (Which May Exist Even If Quarantine Has Since Been Disabled)
+	err := r.deleteTopic(ctx, kafkautil.QuarantineTopicName(topicName))
+	if err != nil {
+		r.logger.Error("Failed To Finalize KafkaChannel", zap.Any("Channel", channel), zap.Error(err))
+		return err
+	}
+
 	// Delete The Kafka Topic & Handle Error Response
-	err := r.deleteTopic(ctx, topicName)
+	err = r.deleteTopic(ctx, topicName)
 	if err != nil {
 		r.logger.Error("Failed To Finalize KafkaChannel", zap.Any("Channel", channel), zap.Error(err))
 		return err
@@ -169,12 +218,30 @@ func (r *Reconciler) reconcile(ctx context.Context, channel *kafkav1beta1.KafkaC
 	// NOTE - The sequential order of reconciliation must be "Topic" then "Channel / Dispatcher" in order for the
 	//        EventHub Cache to know the dynamically determined EventHub Namespace / Kafka Secret selected for the topic.
 
+	// Merge Any Namespace & Class Overrides Into The Cluster-Wide Configuration
+	configuration, err := r.channelConfig(ctx, channel)
+	if err != nil {
+		return fmt.Errorf(constants.ReconciliationFailedError)
+	}
+
+	// Record The Effective Configuration In The KafkaChannel's Status
+	err = r.reconcileEffectiveConfig(channel, configuration)
+	if err != nil {
+		return fmt.Errorf(constants.ReconciliationFailedError)
+	}
+
 	// Reconcile The KafkaChannel's Kafka Topic
-	err := r.reconcileTopic(ctx, channel)
+	err = r.reconcileTopic(ctx, channel, configuration)
 	if err != nil {
 		return fmt.Errorf(constants.ReconciliationFailedError)
 	}
 
+	// Reconcile The Kafka Topic's Partitions Against The Observed Throughput (Never Fails The Reconciliation)
+	r.reconcilePartitions(ctx, channel, configuration)
+
+	// Reconcile The Dispatcher & Receiver Resource Requests Against The Observed Usage (Never Fails The Reconciliation)
+	r.reconcileResources(ctx, channel, configuration)
+
 	//
 	// This implementation is based on the "consolidated" KafkaChannel, and thus we're using
 	// their Status tracking even though it does not align with our architecture.  We get our
@@ -189,13 +256,20 @@ func (r *Reconciler) reconcile(ctx context.Context, channel *kafkav1beta1.KafkaC
 		return fmt.Errorf(constants.ReconciliationFailedError)
 	}
 
-	// Reconcile The KafkaChannel's Channel & Dispatcher Deployment/Service
+	// Reconcile The KafkaChannel's Channel, Isolated Receiver & Dispatcher Deployment/Service
 	channelError := r.reconcileChannel(ctx, channel)
-	dispatcherError := r.reconcileDispatcher(ctx, channel)
-	if channelError != nil || dispatcherError != nil {
+	receiverError := r.reconcileReceiver(ctx, channel)
+	dispatcherError := r.reconcileDispatcher(ctx, channel, configuration)
+	if channelError != nil || receiverError != nil || dispatcherError != nil {
 		return fmt.Errorf(constants.ReconciliationFailedError)
 	}
 
+	// Export The Committed Offsets & Lag Of The KafkaChannel's Subscribers To Its Status (Never Fails The Reconciliation)
+	r.reconcileSubscriberOffsets(ctx, channel)
+
+	// Warn Of Subscribers Whose ConsumerGroup Offsets Risk Expiring (Never Fails The Reconciliation)
+	r.reconcileOffsetsRetention(ctx, channel, configuration)
+
 	// Reconcile The KafkaChannel Itself (MetaData, etc...)
 	err = r.reconcileKafkaChannel(ctx, channel)
 	if err != nil {
@@ -234,6 +308,12 @@ func (r *Reconciler) configMapObserver(configMap *corev1.ConfigMap) {
 	// Note - We're not calling UpdateSaramaConfig() here because we load the Kafka Secret
 	//        from inside the AdminClient, which is currently done for every reconciliation.
 
+	// Carry Forward Any Workload Identity TokenProvider, Egress Proxy & Broker Address Rewrites
+	if r.saramaConfig != nil {
+		kafkasarama.UpdateSaramaTokenProvider(saramaConfig, r.saramaConfig.Net.SASL.TokenProvider)
+		kafkasarama.CarryForwardSaramaDialer(saramaConfig, r.saramaConfig)
+	}
+
 	r.logger.Info("ConfigMap Changed; Updating Sarama Configuration")
 	r.saramaConfig = saramaConfig
 }
@@ -27,8 +27,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
 	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
 	fakekafkaclient "knative.dev/eventing-kafka/pkg/client/injection/client/fake"
@@ -51,9 +53,6 @@ func init() {
 // Test The Reconciler's SetKafkaAdminClient() Functionality
 func TestSetKafkaAdminClient(t *testing.T) {
 
-	// Test Data
-	clientType := kafkaadmin.Kafka
-
 	// Create A Test Logger
 	logger := logtesting.TestLogger(t).Desugar()
 
@@ -62,8 +61,10 @@ func TestSetKafkaAdminClient(t *testing.T) {
 	mockAdminClient2 := &controllertesting.MockAdminClient{}
 
 	// Mock The Creation Of Kafka ClusterAdmin
+	var adminClientId string
 	newKafkaAdminClientWrapperPlaceholder := kafkaadmin.NewKafkaAdminClientWrapper
-	kafkaadmin.NewKafkaAdminClientWrapper = func(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string) (kafkaadmin.AdminClientInterface, error) {
+	kafkaadmin.NewKafkaAdminClientWrapper = func(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string, authSpec *bindingsv1beta1.KafkaAuthSpec) (kafkaadmin.TopicProvisioner, error) {
+		adminClientId = clientId
 		return mockAdminClient2, nil
 	}
 	defer func() {
@@ -71,10 +72,15 @@ func TestSetKafkaAdminClient(t *testing.T) {
 	}()
 
 	// Create A Reconciler To Test
+	environment := controllertesting.NewEnvironment()
+	environment.PodName = "TestPodName"
+	config := controllertesting.NewConfig()
+	config.Kafka.ClientIdTemplate = "{{.Component}}.{{.Pod}}"
 	reconciler := &Reconciler{
-		logger:          logger,
-		adminClientType: clientType,
-		adminClient:     mockAdminClient1,
+		logger:      logger,
+		environment: environment,
+		config:      config,
+		adminClient: mockAdminClient1,
 	}
 
 	// Perform The Test
@@ -84,14 +90,12 @@ func TestSetKafkaAdminClient(t *testing.T) {
 	assert.True(t, mockAdminClient1.CloseCalled())
 	assert.NotNil(t, reconciler.adminClient)
 	assert.Equal(t, mockAdminClient2, reconciler.adminClient)
+	assert.Equal(t, constants.ControllerComponentName+".TestPodName", adminClientId)
 }
 
 // Test The Reconciler's ClearKafkaAdminClient() Functionality
 func TestClearKafkaAdminClient(t *testing.T) {
 
-	// Test Data
-	clientType := kafkaadmin.Kafka
-
 	// Create A Test Logger
 	logger := logtesting.TestLogger(t).Desugar()
 
@@ -100,9 +104,8 @@ func TestClearKafkaAdminClient(t *testing.T) {
 
 	// Create A Reconciler To Test
 	reconciler := &Reconciler{
-		logger:          logger,
-		adminClientType: clientType,
-		adminClient:     mockAdminClient,
+		logger:      logger,
+		adminClient: mockAdminClient,
 	}
 
 	// Perform The Test
@@ -160,6 +163,7 @@ func TestReconcile(t *testing.T) {
 					Object: controllertesting.NewKafkaChannel(
 						controllertesting.WithAddress,
 						controllertesting.WithInitializedConditions,
+						controllertesting.WithEffectiveConfig,
 						controllertesting.WithKafkaChannelServiceReady,
 						controllertesting.WithDispatcherDeploymentReady,
 						controllertesting.WithTopicReady,
@@ -173,6 +177,7 @@ func TestReconcile(t *testing.T) {
 						controllertesting.WithMetaData,
 						controllertesting.WithAddress,
 						controllertesting.WithInitializedConditions,
+						controllertesting.WithEffectiveConfig,
 						controllertesting.WithKafkaChannelServiceReady,
 						controllertesting.WithDispatcherDeploymentReady,
 						controllertesting.WithTopicReady,
@@ -219,6 +224,7 @@ func TestReconcile(t *testing.T) {
 					controllertesting.WithMetaData,
 					controllertesting.WithAddress,
 					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
 					controllertesting.WithKafkaChannelServiceReady,
 					controllertesting.WithDispatcherDeploymentReady,
 					controllertesting.WithTopicReady,
@@ -238,6 +244,7 @@ func TestReconcile(t *testing.T) {
 					controllertesting.WithFinalizer,
 					controllertesting.WithAddress,
 					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
 					controllertesting.WithKafkaChannelServiceReady,
 					controllertesting.WithDispatcherDeploymentReady,
 					controllertesting.WithTopicReady,
@@ -254,6 +261,7 @@ func TestReconcile(t *testing.T) {
 						controllertesting.WithFinalizer,
 						controllertesting.WithAddress,
 						controllertesting.WithInitializedConditions,
+						controllertesting.WithEffectiveConfig,
 						controllertesting.WithKafkaChannelServiceFailed,
 						controllertesting.WithDispatcherDeploymentReady,
 						controllertesting.WithTopicReady,
@@ -280,6 +288,7 @@ func TestReconcile(t *testing.T) {
 					controllertesting.WithMetaData,
 					controllertesting.WithAddress,
 					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
 					controllertesting.WithKafkaChannelServiceReady,
 					controllertesting.WithReceiverServiceReady,
 					controllertesting.WithReceiverDeploymentReady,
@@ -304,6 +313,7 @@ func TestReconcile(t *testing.T) {
 					controllertesting.WithMetaData,
 					controllertesting.WithAddress,
 					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
 					controllertesting.WithKafkaChannelServiceReady,
 					controllertesting.WithReceiverServiceReady,
 					controllertesting.WithReceiverDeploymentReady,
@@ -341,6 +351,7 @@ func TestReconcile(t *testing.T) {
 					controllertesting.WithMetaData,
 					controllertesting.WithAddress,
 					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
 					controllertesting.WithKafkaChannelServiceReady,
 					controllertesting.WithReceiverServiceReady,
 					controllertesting.WithReceiverDeploymentReady,
@@ -365,6 +376,7 @@ func TestReconcile(t *testing.T) {
 					controllertesting.WithMetaData,
 					controllertesting.WithAddress,
 					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
 					controllertesting.WithKafkaChannelServiceReady,
 					controllertesting.WithReceiverServiceReady,
 					controllertesting.WithReceiverDeploymentReady,
@@ -386,6 +398,7 @@ func TestReconcile(t *testing.T) {
 						controllertesting.WithMetaData,
 						controllertesting.WithAddress,
 						controllertesting.WithInitializedConditions,
+						controllertesting.WithEffectiveConfig,
 						controllertesting.WithKafkaChannelServiceReady,
 						controllertesting.WithReceiverServiceReady,
 						controllertesting.WithReceiverDeploymentReady,
@@ -399,11 +412,175 @@ func TestReconcile(t *testing.T) {
 				controllertesting.NewKafkaChannelFailedReconciliationEvent(),
 			},
 		},
+		{
+			Name:                    "Reconcile Dispatcher Deployment Architecture Update",
+			SkipNamespaceValidation: true,
+			Key:                     controllertesting.KafkaChannelKey,
+			Objects: []runtime.Object{
+				controllertesting.NewKafkaChannel(
+					controllertesting.WithFinalizer,
+					controllertesting.WithMetaData,
+					controllertesting.WithArchitectureAnnotation,
+					controllertesting.WithAddress,
+					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
+					controllertesting.WithKafkaChannelServiceReady,
+					controllertesting.WithReceiverServiceReady,
+					controllertesting.WithReceiverDeploymentReady,
+					controllertesting.WithDispatcherDeploymentReady,
+					controllertesting.WithTopicReady,
+				),
+				controllertesting.NewKafkaChannelService(),
+				controllertesting.NewKafkaChannelReceiverService(),
+				controllertesting.NewKafkaChannelReceiverDeployment(),
+				controllertesting.NewKafkaChannelDispatcherService(),
+				controllertesting.NewKafkaChannelDispatcherDeployment(),
+			},
+			WantUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: controllertesting.NewKafkaChannelDispatcherDeployment(controllertesting.WithDeploymentArchitecture)},
+			},
+			WantEvents: []string{controllertesting.NewKafkaChannelSuccessfulReconciliationEvent()},
+		},
+		{
+			Name:                    "Reconcile Dispatcher Deployment Architecture Update Error(Update)",
+			SkipNamespaceValidation: true,
+			Key:                     controllertesting.KafkaChannelKey,
+			Objects: []runtime.Object{
+				controllertesting.NewKafkaChannel(
+					controllertesting.WithFinalizer,
+					controllertesting.WithMetaData,
+					controllertesting.WithArchitectureAnnotation,
+					controllertesting.WithAddress,
+					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
+					controllertesting.WithKafkaChannelServiceReady,
+					controllertesting.WithReceiverServiceReady,
+					controllertesting.WithReceiverDeploymentReady,
+					controllertesting.WithDispatcherDeploymentReady,
+					controllertesting.WithTopicReady,
+				),
+				controllertesting.NewKafkaChannelService(),
+				controllertesting.NewKafkaChannelReceiverService(),
+				controllertesting.NewKafkaChannelReceiverDeployment(),
+				controllertesting.NewKafkaChannelDispatcherService(),
+				controllertesting.NewKafkaChannelDispatcherDeployment(),
+			},
+			WithReactors: []clientgotesting.ReactionFunc{InduceFailure("update", "deployments")},
+			WantErr:      true,
+			WantUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: controllertesting.NewKafkaChannelDispatcherDeployment(controllertesting.WithDeploymentArchitecture)},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{
+					Object: controllertesting.NewKafkaChannel(
+						controllertesting.WithFinalizer,
+						controllertesting.WithMetaData,
+						controllertesting.WithArchitectureAnnotation,
+						controllertesting.WithAddress,
+						controllertesting.WithInitializedConditions,
+						controllertesting.WithEffectiveConfig,
+						controllertesting.WithKafkaChannelServiceReady,
+						controllertesting.WithReceiverServiceReady,
+						controllertesting.WithReceiverDeploymentReady,
+						controllertesting.WithDispatcherUpdateFailed,
+						controllertesting.WithTopicReady,
+					),
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeWarning, event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Reconcile Dispatcher Deployment: inducing failure for update deployments"),
+				controllertesting.NewKafkaChannelFailedReconciliationEvent(),
+			},
+		},
+		{
+			Name:                    "Reconcile Dispatcher Deployment Canary Image Rollout",
+			SkipNamespaceValidation: true,
+			Key:                     controllertesting.KafkaChannelKey,
+			Objects: []runtime.Object{
+				controllertesting.NewKafkaChannel(
+					controllertesting.WithFinalizer,
+					controllertesting.WithMetaData,
+					controllertesting.WithImageClassAnnotation,
+					controllertesting.WithAddress,
+					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
+					controllertesting.WithKafkaChannelServiceReady,
+					controllertesting.WithReceiverServiceReady,
+					controllertesting.WithReceiverDeploymentReady,
+					controllertesting.WithDispatcherDeploymentReady,
+					controllertesting.WithTopicReady,
+				),
+				controllertesting.NewKafkaChannelService(),
+				controllertesting.NewKafkaChannelReceiverService(),
+				controllertesting.NewKafkaChannelReceiverDeployment(),
+				controllertesting.NewKafkaChannelDispatcherService(),
+				controllertesting.NewKafkaChannelDispatcherDeployment(),
+			},
+			WantUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: controllertesting.NewKafkaChannelDispatcherDeployment(controllertesting.WithDeploymentCanaryImage)},
+			},
+			WantEvents: []string{controllertesting.NewKafkaChannelSuccessfulReconciliationEvent()},
+		},
+		{
+			Name:                    "Reconcile Dispatcher Deployment Canary Image Rollback",
+			SkipNamespaceValidation: true,
+			Key:                     controllertesting.KafkaChannelKey,
+			Objects: []runtime.Object{
+				controllertesting.NewKafkaChannel(
+					controllertesting.WithFinalizer,
+					controllertesting.WithMetaData,
+					controllertesting.WithImageClassAnnotation,
+					controllertesting.WithAddress,
+					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
+					controllertesting.WithKafkaChannelServiceReady,
+					controllertesting.WithReceiverServiceReady,
+					controllertesting.WithReceiverDeploymentReady,
+					controllertesting.WithDispatcherDeploymentReady,
+					controllertesting.WithTopicReady,
+				),
+				controllertesting.NewKafkaChannelService(),
+				controllertesting.NewKafkaChannelReceiverService(),
+				controllertesting.NewKafkaChannelReceiverDeployment(),
+				controllertesting.NewKafkaChannelDispatcherService(),
+				controllertesting.NewKafkaChannelDispatcherDeployment(controllertesting.WithDeploymentCanaryImage, controllertesting.WithDeploymentProgressDeadlineExceeded),
+			},
+			WantUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: controllertesting.NewKafkaChannelDispatcherDeployment(controllertesting.WithDeploymentProgressDeadlineExceeded, controllertesting.WithDeploymentRolledBackCanaryImage)},
+			},
+			WantEvents: []string{controllertesting.NewKafkaChannelSuccessfulReconciliationEvent()},
+		},
+		{
+			Name:                    "Reconcile Dispatcher Deployment Canary Image Rolled Back",
+			SkipNamespaceValidation: true,
+			Key:                     controllertesting.KafkaChannelKey,
+			Objects: []runtime.Object{
+				controllertesting.NewKafkaChannel(
+					controllertesting.WithFinalizer,
+					controllertesting.WithMetaData,
+					controllertesting.WithImageClassAnnotation,
+					controllertesting.WithAddress,
+					controllertesting.WithInitializedConditions,
+					controllertesting.WithEffectiveConfig,
+					controllertesting.WithKafkaChannelServiceReady,
+					controllertesting.WithReceiverServiceReady,
+					controllertesting.WithReceiverDeploymentReady,
+					controllertesting.WithDispatcherDeploymentReady,
+					controllertesting.WithTopicReady,
+				),
+				controllertesting.NewKafkaChannelService(),
+				controllertesting.NewKafkaChannelReceiverService(),
+				controllertesting.NewKafkaChannelReceiverDeployment(),
+				controllertesting.NewKafkaChannelDispatcherService(),
+				controllertesting.NewKafkaChannelDispatcherDeployment(controllertesting.WithDeploymentRolledBackCanaryImage),
+			},
+			WantEvents: []string{controllertesting.NewKafkaChannelSuccessfulReconciliationEvent()},
+		},
 	}
 
 	// Mock The Common Kafka AdminClient Creation For Test
 	newKafkaAdminClientWrapperPlaceholder := kafkaadmin.NewKafkaAdminClientWrapper
-	kafkaadmin.NewKafkaAdminClientWrapper = func(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string) (kafkaadmin.AdminClientInterface, error) {
+	kafkaadmin.NewKafkaAdminClientWrapper = func(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string, authSpec *bindingsv1beta1.KafkaAuthSpec) (kafkaadmin.TopicProvisioner, error) {
 		return &controllertesting.MockAdminClient{}, nil
 	}
 	defer func() {
@@ -416,7 +593,6 @@ func TestReconcile(t *testing.T) {
 		r := &Reconciler{
 			logger:               logging.FromContext(ctx).Desugar(),
 			kubeClientset:        kubeclient.Get(ctx),
-			adminClientType:      kafkaadmin.Kafka,
 			adminClient:          nil,
 			environment:          controllertesting.NewEnvironment(),
 			config:               controllertesting.NewConfig(),
@@ -424,6 +600,8 @@ func TestReconcile(t *testing.T) {
 			kafkachannelInformer: nil,
 			deploymentLister:     listers.GetDeploymentLister(),
 			serviceLister:        listers.GetServiceLister(),
+			namespaceSettings:    listers.GetConfigMapLister(),
+			channelClasses:       listers.GetConfigMapLister(),
 			kafkaClientSet:       fakekafkaclient.Get(ctx),
 			adminMutex:           &sync.Mutex{},
 		}
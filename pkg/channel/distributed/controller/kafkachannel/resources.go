@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/aggregator"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/tracker"
+)
+
+// KafkaChannel ResourcesRightsized Condition Reasons
+const ResizeRecommendedReason = "ResizeRecommended"
+
+//
+// Reconcile The Resource Requests Of The Specified Channel's Dispatcher & Receiver Against Their Observed Usage
+//
+// The resource usage observed by the metrics aggregator reveals whether the requests of the containers of the
+// channel's dispatcher & receiver Deployments deviate from their peak usage (see aggregator.observeResources()).  If
+// so, a warning condition & event recommending the requests are produced.  The recommendations are never applied by
+// the controller itself, as the requests are configured cluster-wide (or per namespace), but a VerticalPodAutoscaler
+// of the dispatcher may be created to apply its own recommendations according to the configured update mode.  The
+// receiver is shared by the channels of a Kafka Secret unless isolated, in which case its recommendations appear on
+// each of those channels.
+//
+// Failures are reported on the channel but never fail the reconciliation, as the channel remains functional.
+//
+func (r *Reconciler) reconcileResources(ctx context.Context, channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) {
+
+	// Nothing To Do Unless The Resize Advisor Is Enabled
+	advisorConfig := configuration.MetricsAggregator.ResizeAdvisor
+	if !advisorConfig.Enabled {
+		channel.Status.ClearResourcesCondition()
+		return
+	}
+
+	// Create (Or Remove) The Dispatcher's VerticalPodAutoscaler
+	r.reconcileVerticalPodAutoscaler(ctx, channel, advisorConfig)
+
+	// Track The Shared Receiver So That Only Its Channels Are Re-Reconciled When Its Recommended Resizes Change
+	r.trackSharedReceiver(channel)
+
+	// Get The Recommended Resizes Of The Dispatcher & Receiver (None Until Enough Of Their Usage Has Been Observed)
+	if r.deploymentResources == nil {
+		return
+	}
+	var recommendations []string
+	observed := false
+	deploymentNames := []string{r.dispatcherName(channel)}
+	if receiverName := r.receiverName(channel); receiverName != deploymentNames[0] {
+		deploymentNames = append(deploymentNames, receiverName)
+	}
+	for _, deploymentName := range deploymentNames {
+		resources := r.deploymentResources(deploymentName)
+		if resources == nil || resources.Samples < aggregator.MinResizeSamples {
+			continue
+		}
+		observed = true
+		recommendations = append(recommendations, resources.ResizeRecommendations()...)
+	}
+	if !observed {
+		return
+	}
+	if len(recommendations) == 0 {
+		channel.Status.MarkResourcesRightsized()
+		return
+	}
+
+	// Warn Of The Recommended Resizes (Only Producing An Event When They Change)
+	message := "Resource requests deviate from the observed usage - recommended " + strings.Join(recommendations, ", ")
+	condition := channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionResourcesRightsized)
+	if condition == nil || condition.Message != message {
+		util.ChannelLogger(r.logger, channel).Warn("Dispatcher / Receiver Resize Recommended", zap.Strings("Recommendations", recommendations))
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DataPlaneResizeRecommended.String(),
+			"Resource Requests Deviate From The Observed Usage - Recommended %s", strings.Join(recommendations, ", "))
+	}
+	channel.Status.MarkResourcesNotRightsized(ResizeRecommendedReason, message)
+}
+
+// Track The Shared Receiver Deployment Of The Specified Channel (If Neither Isolated Nor Combined With The Dispatcher)
+func (r *Reconciler) trackSharedReceiver(channel *kafkav1beta1.KafkaChannel) {
+	if r.tracker == nil || r.isReceiverIsolated(channel) || r.isReceiverCombined(channel) {
+		return
+	}
+	err := r.tracker.TrackReference(tracker.Reference{
+		APIVersion: appsv1.SchemeGroupVersion.String(),
+		Kind:       constants.DeploymentKind,
+		Namespace:  system.Namespace(),
+		Name:       r.receiverName(channel),
+	}, channel)
+	if err != nil {
+		util.ChannelLogger(r.logger, channel).Warn("Failed To Track The Shared Receiver", zap.Error(err))
+	}
+}
+
+// Reconcile The VerticalPodAutoscaler Of The Specified Channel's Dispatcher, Creating It If CreateVPA Is Set & Otherwise Removing It
+func (r *Reconciler) reconcileVerticalPodAutoscaler(ctx context.Context, channel *kafkav1beta1.KafkaChannel, advisorConfig config.EKResizeAdvisorConfig) {
+	if r.dynamicClient == nil {
+		return
+	}
+
+	// Get The Dispatcher's Current VerticalPodAutoscaler (NotFound If The VPA CRDs Are Not Installed)
+	logger := util.ChannelLogger(r.logger, channel)
+	deploymentName := r.dispatcherName(channel)
+	vpaClient := r.dynamicClient.Resource(util.VerticalPodAutoscalerGVR).Namespace(system.Namespace())
+	vpa, err := vpaClient.Get(ctx, deploymentName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		vpa = nil
+	} else if err != nil {
+		r.verticalPodAutoscalerFailed(ctx, channel, logger, "Failed To Get VerticalPodAutoscaler", err)
+		return
+	}
+
+	// Remove Any VerticalPodAutoscaler Of The Channel's Dispatcher Unless CreateVPA Is Set
+	if !advisorConfig.CreateVPA {
+		if vpa != nil && isChannelDispatcher(vpa.GetLabels(), channel) {
+			err = vpaClient.Delete(ctx, deploymentName, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				r.verticalPodAutoscalerFailed(ctx, channel, logger, "Failed To Delete VerticalPodAutoscaler", err)
+				return
+			}
+			logger.Info("Successfully Deleted VerticalPodAutoscaler", zap.String("Name", deploymentName))
+		}
+		return
+	}
+
+	// Create The VerticalPodAutoscaler Or Update Its UpdateMode
+	updateMode := util.VPAUpdateMode(advisorConfig.VPAUpdateMode)
+	if vpa == nil {
+		_, err = vpaClient.Create(ctx, util.NewDispatcherVerticalPodAutoscaler(channel, deploymentName, updateMode), metav1.CreateOptions{})
+		if err != nil {
+			r.verticalPodAutoscalerFailed(ctx, channel, logger, "Failed To Create VerticalPodAutoscaler", err)
+			return
+		}
+		logger.Info("Successfully Created VerticalPodAutoscaler", zap.String("Name", deploymentName), zap.String("UpdateMode", updateMode))
+	} else if util.VerticalPodAutoscalerUpdateMode(vpa) != updateMode {
+		vpa = vpa.DeepCopy()
+		err = unstructured.SetNestedField(vpa.Object, updateMode, "spec", "updatePolicy", "updateMode")
+		if err == nil {
+			_, err = vpaClient.Update(ctx, vpa, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			r.verticalPodAutoscalerFailed(ctx, channel, logger, "Failed To Update VerticalPodAutoscaler", err)
+			return
+		}
+		logger.Info("Successfully Updated VerticalPodAutoscaler", zap.String("Name", deploymentName), zap.String("UpdateMode", updateMode))
+	}
+}
+
+// Utility Function For Reporting A VerticalPodAutoscaler Failure (Which Never Fails The Reconciliation)
+func (r *Reconciler) verticalPodAutoscalerFailed(ctx context.Context, channel *kafkav1beta1.KafkaChannel, logger *zap.Logger, message string, err error) {
+	logger.Warn(message, zap.Error(err))
+	controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.VerticalPodAutoscalerReconciliationFailed.String(), "%s: %v", message, err)
+}
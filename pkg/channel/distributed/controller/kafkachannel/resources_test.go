@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/aggregator"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciler's reconcileResources() Functionality
+func TestReconcileResources(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name            string
+		advisorConfig   config.EKResizeAdvisorConfig
+		resources       *aggregator.DeploymentResources
+		previousMessage string
+		wantStatus      corev1.ConditionStatus
+		wantEvent       string
+		wantNoCondition bool
+		wantNoEvent     bool
+	}
+
+	// Test Data
+	advisorConfig := config.EKResizeAdvisorConfig{Enabled: true}
+	cpuRecommendation := resource.MustParse("120m")
+	rightsized := &aggregator.DeploymentResources{Name: "test-dispatcher", Samples: aggregator.MinResizeSamples,
+		Containers: []aggregator.ContainerResources{{Name: "test-dispatcher", CpuRequest: resource.MustParse("100m")}}}
+	tooFewSamples := &aggregator.DeploymentResources{Name: "test-dispatcher", Samples: aggregator.MinResizeSamples - 1,
+		Containers: []aggregator.ContainerResources{{Name: "test-dispatcher", CpuRequest: resource.MustParse("500m"), CpuRecommendation: &cpuRecommendation}}}
+	overprovisioned := &aggregator.DeploymentResources{Name: "test-dispatcher", Samples: aggregator.MinResizeSamples, Containers: tooFewSamples.Containers}
+	message := "Resource requests deviate from the observed usage - recommended test-dispatcher/test-dispatcher cpu 500m -> 120m"
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Advisor Disabled", resources: overprovisioned, wantNoCondition: true, wantNoEvent: true},
+		{name: "Not Yet Observed", advisorConfig: advisorConfig, wantNoCondition: true, wantNoEvent: true},
+		{name: "Too Few Samples", advisorConfig: advisorConfig, resources: tooFewSamples, wantNoCondition: true, wantNoEvent: true},
+		{name: "Rightsized", advisorConfig: advisorConfig, resources: rightsized, wantStatus: corev1.ConditionTrue, wantNoEvent: true},
+		{name: "Resize Recommended", advisorConfig: advisorConfig, resources: overprovisioned, wantStatus: corev1.ConditionFalse,
+			wantEvent: "Warning " + event.DataPlaneResizeRecommended.String() + " Resource Requests Deviate From The Observed Usage - Recommended test-dispatcher/test-dispatcher cpu 500m -> 120m"},
+		{name: "Resize Recommendation Unchanged", advisorConfig: advisorConfig, resources: overprovisioned, previousMessage: message, wantStatus: corev1.ConditionFalse, wantNoEvent: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Reconciler With The TestCase's DeploymentResources (For The Dispatcher Only)
+			configuration := controllertesting.NewConfig()
+			configuration.MetricsAggregator.ResizeAdvisor = testCase.advisorConfig
+			r := &Reconciler{
+				logger:      logtesting.TestLogger(t).Desugar(),
+				adminClient: &controllertesting.MockAdminClient{},
+				config:      configuration,
+			}
+			channel := controllertesting.NewKafkaChannel()
+			r.deploymentResources = func(deploymentName string) *aggregator.DeploymentResources {
+				if deploymentName == r.dispatcherName(channel) {
+					return testCase.resources
+				}
+				return nil
+			}
+			if len(testCase.previousMessage) > 0 {
+				channel.Status.MarkResourcesNotRightsized(ResizeRecommendedReason, testCase.previousMessage)
+			}
+
+			// Perform The Test
+			recorder := record.NewFakeRecorder(1)
+			r.reconcileResources(controller.WithEventRecorder(context.TODO(), recorder), channel, &config.NamespaceConfig{EventingKafkaConfig: configuration})
+
+			// Verify The Results
+			condition := channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionResourcesRightsized)
+			if testCase.wantNoCondition {
+				assert.Nil(t, condition)
+			} else {
+				assert.NotNil(t, condition)
+				assert.Equal(t, testCase.wantStatus, condition.Status)
+				if testCase.wantStatus == corev1.ConditionFalse {
+					assert.Equal(t, ResizeRecommendedReason, condition.Reason)
+					assert.Equal(t, message, condition.Message)
+				}
+			}
+			if testCase.wantNoEvent {
+				assert.Empty(t, recorder.Events)
+			} else {
+				assert.Equal(t, testCase.wantEvent, <-recorder.Events)
+			}
+		})
+	}
+}
+
+// Test The Reconciler's reconcileVerticalPodAutoscaler() Functionality
+func TestReconcileVerticalPodAutoscaler(t *testing.T) {
+
+	// Test Data
+	channel := controllertesting.NewKafkaChannel()
+	configuration := controllertesting.NewConfig()
+	deploymentName := util.DispatcherDnsSafeName(channel, configuration.Naming.Strategy)
+	offVPA := util.NewDispatcherVerticalPodAutoscaler(channel, deploymentName, util.VPAUpdateModeOff)
+	foreignVPA := util.NewDispatcherVerticalPodAutoscaler(channel, deploymentName, util.VPAUpdateModeOff)
+	foreignVPA.SetLabels(map[string]string{})
+
+	// Define The TestCases
+	testCases := []struct {
+		name          string
+		advisorConfig config.EKResizeAdvisorConfig
+		existing      []runtime.Object
+		createError   error
+		wantMode      string
+		wantDeleted   bool
+		wantEvent     string
+	}{
+		{name: "Create", advisorConfig: config.EKResizeAdvisorConfig{Enabled: true, CreateVPA: true}, wantMode: util.VPAUpdateModeOff},
+		{name: "Update Mode", advisorConfig: config.EKResizeAdvisorConfig{Enabled: true, CreateVPA: true, VPAUpdateMode: util.VPAUpdateModeAuto},
+			existing: []runtime.Object{offVPA}, wantMode: util.VPAUpdateModeAuto},
+		{name: "Delete", advisorConfig: config.EKResizeAdvisorConfig{Enabled: true}, existing: []runtime.Object{offVPA}, wantDeleted: true},
+		{name: "Keep Foreign", advisorConfig: config.EKResizeAdvisorConfig{Enabled: true}, existing: []runtime.Object{foreignVPA}, wantMode: util.VPAUpdateModeOff},
+		{name: "Absent", advisorConfig: config.EKResizeAdvisorConfig{Enabled: true}, wantDeleted: true},
+		{name: "Create Failed", advisorConfig: config.EKResizeAdvisorConfig{Enabled: true, CreateVPA: true}, createError: fmt.Errorf("the server could not find the requested resource"),
+			wantDeleted: true, wantEvent: "Warning " + event.VerticalPodAutoscalerReconciliationFailed.String() + " Failed To Create VerticalPodAutoscaler"},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Reconciler With A Fake Dynamic Client
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), testCase.existing...)
+			if testCase.createError != nil {
+				dynamicClient.PrependReactor("create", "verticalpodautoscalers", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					return true, nil, testCase.createError
+				})
+			}
+			r := &Reconciler{
+				logger:        logtesting.TestLogger(t).Desugar(),
+				config:        configuration,
+				dynamicClient: dynamicClient,
+			}
+
+			// Perform The Test
+			recorder := record.NewFakeRecorder(1)
+			r.reconcileVerticalPodAutoscaler(controller.WithEventRecorder(context.TODO(), recorder), channel, testCase.advisorConfig)
+
+			// Verify The Results
+			vpa, err := dynamicClient.Resource(util.VerticalPodAutoscalerGVR).Namespace(commonconstants.KnativeEventingNamespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
+			if testCase.wantDeleted {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.wantMode, util.VerticalPodAutoscalerUpdateMode(vpa))
+			}
+			if len(testCase.wantEvent) > 0 {
+				assert.Contains(t, <-recorder.Events, testCase.wantEvent)
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/pkg/controller"
+)
+
+// The Defaults Of The Offsets Retention Warning
+const (
+	DefaultOffsetsRetention              = 7 * 24 * time.Hour // The Brokers' Default offsets.retention.minutes
+	DefaultRetentionWarningThreshold     = 50                 // Percent Of The Offsets Retention
+	DefaultRetentionWarningCheckInterval = 10 * time.Minute
+)
+
+// KafkaChannel OffsetsRetained Condition Reasons
+const (
+	OffsetsExpiringReason = "OffsetsExpiring"
+)
+
+// Start Re-Reconciling All KafkaChannels Every Retention Warning Check Interval (No-Op If The Warning Is Not Enabled)
+func (r *Reconciler) startRetentionWatcher(ctx context.Context, resync func()) {
+	if !r.config.Dispatcher.ConsumerGroup.RetentionWarning.Enabled {
+		return
+	}
+	interval := retentionWarningCheckInterval(r.config.Dispatcher.ConsumerGroup.RetentionWarning)
+	r.logger.Info("Starting Subscriber Offsets Retention Watcher", zap.Duration("Interval", interval))
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				resync()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+//
+// Reconcile The Retention Of The Committed Offsets Of The Specified KafkaChannel's Subscribers
+//
+// Once the ConsumerGroup of a subscriber has no consumers (i.e. is Empty, such as while the subscriber's dispatcher
+// is down or scaled to zero) the brokers expire its committed offsets after the offsets retention, after which the
+// subscriber silently resumes consuming from the initial offset (re-processing or skipping events).  The time at
+// which each subscriber's ConsumerGroup was first observed to be Empty is recorded in the SubscriberEmptySince
+// annotation of the KafkaChannel's status, and once any has been Empty for the configured threshold percentage of
+// the retention a Warning event is produced and the OffsetsRetained condition is set to False (with a Warning
+// severity, so that the channel remains Ready).  The condition is True while no subscriber's offsets are at risk.
+//
+// Failures are logged but never fail the reconciliation, and the previously recorded times are kept.
+//
+func (r *Reconciler) reconcileOffsetsRetention(ctx context.Context, channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) {
+
+	// Nothing To Do Unless The Retention Warning Is Enabled
+	consumerGroupConfig := configuration.Dispatcher.ConsumerGroup
+	if !consumerGroupConfig.RetentionWarning.Enabled {
+		delete(channel.Status.Annotations, constants.SubscriberEmptySinceAnnotation)
+		channel.Status.ClearOffsetsRetainedCondition()
+		return
+	}
+
+	// Skip The Check If The Kafka AdminClient Can't Describe ConsumerGroups
+	logger := util.ChannelLogger(r.logger, channel)
+	inspector, ok := r.adminClient.(kafkaadmin.ConsumerGroupInspector)
+	if !ok {
+		logger.Debug("Kafka AdminClient Can't Describe ConsumerGroups - Skipping Offsets Retention Check", zap.String("AdminType", configuration.Kafka.AdminType))
+		return
+	}
+
+	// Get The States Of The Subscribers' ConsumerGroups
+	groupIds := make([]string, 0, len(channel.Spec.Subscribers))
+	for _, subscriber := range channel.Spec.Subscribers {
+		groupIds = append(groupIds, kafkautil.GroupId(string(subscriber.UID)))
+	}
+	states, err := inspector.ConsumerGroupStates(ctx, groupIds)
+	if err != nil {
+		logger.Warn("Failed To Describe ConsumerGroups - Skipping Offsets Retention Check", zap.Error(err))
+		return
+	}
+
+	// Determine Since When Each Empty ConsumerGroup Has Been Empty & Which Offsets Are At Risk Of Expiring
+	previousEmptySince := make(map[string]time.Time)
+	_ = json.Unmarshal([]byte(channel.Status.Annotations[constants.SubscriberEmptySinceAnnotation]), &previousEmptySince)
+	now := time.Now().UTC().Truncate(time.Second)
+	retention := offsetsRetention(consumerGroupConfig)
+	threshold := retention * time.Duration(retentionWarningThreshold(consumerGroupConfig.RetentionWarning)) / 100
+	emptySince := make(map[string]time.Time)
+	var expiringSubscribers []string
+	var earliestExpiry time.Time
+	for _, subscriber := range channel.Spec.Subscribers {
+		uid := string(subscriber.UID)
+		if states[kafkautil.GroupId(uid)] != kafkaadmin.ConsumerGroupStateEmpty {
+			continue
+		}
+		since, ok := previousEmptySince[uid]
+		if !ok {
+			since = now
+		}
+		emptySince[uid] = since
+		if now.Sub(since) >= threshold {
+			expiringSubscribers = append(expiringSubscribers, uid)
+			if expiry := since.Add(retention); earliestExpiry.IsZero() || expiry.Before(earliestExpiry) {
+				earliestExpiry = expiry
+			}
+		}
+	}
+
+	// Update The Status Annotation (Persisted Along With The Rest Of The Status)
+	if len(emptySince) == 0 {
+		delete(channel.Status.Annotations, constants.SubscriberEmptySinceAnnotation)
+	} else if emptySinceJson, err := json.Marshal(emptySince); err != nil {
+		logger.Error("Failed To Marshal Subscriber Empty Since Times", zap.Error(err))
+	} else {
+		if channel.Status.Annotations == nil {
+			channel.Status.Annotations = make(map[string]string)
+		}
+		channel.Status.Annotations[constants.SubscriberEmptySinceAnnotation] = string(emptySinceJson)
+	}
+
+	// Warn Of Any Subscribers Whose Offsets Are At Risk Of Expiring
+	if len(expiringSubscribers) == 0 {
+		channel.Status.MarkOffsetsRetained()
+		return
+	}
+	sort.Strings(expiringSubscribers)
+	subscribers := strings.Join(expiringSubscribers, ", ")
+	logger.Warn("Subscriber ConsumerGroup Offsets At Risk Of Expiring", zap.Strings("Subscribers", expiringSubscribers),
+		zap.Duration("Retention", retention), zap.Time("EarliestExpiry", earliestExpiry))
+	controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.SubscriberOffsetsExpiring.String(),
+		"ConsumerGroups Of Subscribers %s Have Been Without Consumers For Over %d%% Of The %v Offsets Retention - Offsets Expire As Of %s",
+		subscribers, retentionWarningThreshold(consumerGroupConfig.RetentionWarning), retention, earliestExpiry.Format(time.RFC3339))
+	channel.Status.MarkOffsetsAtRisk(OffsetsExpiringReason, "The consumer groups of subscribers %s have been without consumers for over %d%% of the %v offsets retention - their committed offsets expire as of %s",
+		subscribers, retentionWarningThreshold(consumerGroupConfig.RetentionWarning), retention, earliestExpiry.Format(time.RFC3339))
+}
+
+// Get The Offsets Retention Of The Subscribers' ConsumerGroups (As Requested By The Dispatcher, Or Of The Brokers)
+func offsetsRetention(consumerGroupConfig config.EKConsumerGroupConfig) time.Duration {
+	if consumerGroupConfig.OffsetsRetentionMillis > 0 {
+		return time.Duration(consumerGroupConfig.OffsetsRetentionMillis) * time.Millisecond
+	} else if consumerGroupConfig.RetentionWarning.RetentionMillis > 0 {
+		return time.Duration(consumerGroupConfig.RetentionWarning.RetentionMillis) * time.Millisecond
+	}
+	return DefaultOffsetsRetention
+}
+
+// Get The Configured Percentage Of The Offsets Retention After Which Empty ConsumerGroups Are Warned Of
+func retentionWarningThreshold(retentionWarningConfig config.EKRetentionWarningConfig) int {
+	if retentionWarningConfig.ThresholdPercent <= 0 || retentionWarningConfig.ThresholdPercent > 100 {
+		return DefaultRetentionWarningThreshold
+	}
+	return retentionWarningConfig.ThresholdPercent
+}
+
+// Get The Configured Interval Between The Periodic Offsets Retention Checks
+func retentionWarningCheckInterval(retentionWarningConfig config.EKRetentionWarningConfig) time.Duration {
+	interval := time.Duration(retentionWarningConfig.CheckIntervalMillis) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultRetentionWarningCheckInterval
+	}
+	return interval
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciler's reconcileOffsetsRetention() Functionality
+func TestReconcileOffsetsRetention(t *testing.T) {
+
+	// Test Data (A Stable, An Empty & A Dead Subscriber ConsumerGroup)
+	stableUID, emptyUID, deadUID := types.UID("stable-uid"), types.UID("empty-uid"), types.UID("dead-uid")
+	mockAdminClient := &controllertesting.MockAdminClient{
+		MockConsumerGroupStates: map[string]string{
+			kafkautil.GroupId(string(stableUID)): "Stable",
+			kafkautil.GroupId(string(emptyUID)):  kafkaadmin.ConsumerGroupStateEmpty,
+		},
+	}
+	retentionWarning := config.EKRetentionWarningConfig{Enabled: true}
+	newEmptySince := func(age time.Duration) string {
+		return `{"` + string(emptyUID) + `":"` + time.Now().Add(-age).UTC().Format(time.RFC3339) + `","removed-uid":"2020-01-01T00:00:00Z"}`
+	}
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name            string
+		consumerGroup   config.EKConsumerGroupConfig
+		adminClient     kafkaadmin.TopicProvisioner
+		emptySince      string
+		wantEmptySince  time.Duration // The Expected Age Of The Empty Subscriber's Recorded Time (-1 For None)
+		wantStatus      corev1.ConditionStatus
+		wantNoCondition bool
+		wantEvent       string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Disabled", adminClient: mockAdminClient, emptySince: newEmptySince(time.Hour), wantEmptySince: -1, wantNoCondition: true},
+		{name: "No ConsumerGroup Inspector", consumerGroup: config.EKConsumerGroupConfig{RetentionWarning: retentionWarning}, adminClient: nil,
+			emptySince: newEmptySince(time.Hour), wantEmptySince: time.Hour, wantNoCondition: true},
+		{name: "Newly Empty", consumerGroup: config.EKConsumerGroupConfig{RetentionWarning: retentionWarning}, adminClient: mockAdminClient,
+			wantEmptySince: 0, wantStatus: corev1.ConditionTrue},
+		{name: "Empty Below Threshold", consumerGroup: config.EKConsumerGroupConfig{RetentionWarning: retentionWarning}, adminClient: mockAdminClient,
+			emptySince: newEmptySince(72 * time.Hour), wantEmptySince: 72 * time.Hour, wantStatus: corev1.ConditionTrue},
+		{name: "Empty Beyond Default Threshold Of Default Retention", consumerGroup: config.EKConsumerGroupConfig{RetentionWarning: retentionWarning}, adminClient: mockAdminClient,
+			emptySince: newEmptySince(96 * time.Hour), wantEmptySince: 96 * time.Hour, wantStatus: corev1.ConditionFalse,
+			wantEvent: "Warning " + event.SubscriberOffsetsExpiring.String() + " ConsumerGroups Of Subscribers empty-uid Have Been Without Consumers For Over 50% Of The 168h0m0s Offsets Retention"},
+		{name: "Empty Beyond Threshold Of Configured Retention", adminClient: mockAdminClient, emptySince: newEmptySince(2 * time.Hour), wantEmptySince: 2 * time.Hour,
+			consumerGroup: config.EKConsumerGroupConfig{OffsetsRetentionMillis: 10800000, RetentionWarning: config.EKRetentionWarningConfig{Enabled: true, ThresholdPercent: 60}},
+			wantStatus:    corev1.ConditionFalse,
+			wantEvent:     "Warning " + event.SubscriberOffsetsExpiring.String() + " ConsumerGroups Of Subscribers empty-uid Have Been Without Consumers For Over 60% Of The 3h0m0s Offsets Retention"},
+		{name: "Empty Below Threshold Of Broker Retention", adminClient: mockAdminClient, emptySince: newEmptySince(2 * time.Hour), wantEmptySince: 2 * time.Hour,
+			consumerGroup: config.EKConsumerGroupConfig{RetentionWarning: config.EKRetentionWarningConfig{Enabled: true, RetentionMillis: 86400000}},
+			wantStatus:    corev1.ConditionTrue},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Reconciler & The KafkaChannel With The TestCase's Recorded Times
+			configuration := controllertesting.NewConfig()
+			configuration.Dispatcher.ConsumerGroup = testCase.consumerGroup
+			r := &Reconciler{
+				logger:      logtesting.TestLogger(t).Desugar(),
+				config:      configuration,
+				adminClient: testCase.adminClient,
+			}
+			channel := controllertesting.NewKafkaChannel()
+			channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: stableUID}, {UID: emptyUID}, {UID: deadUID}}
+			if len(testCase.emptySince) > 0 {
+				channel.Status.Annotations = map[string]string{constants.SubscriberEmptySinceAnnotation: testCase.emptySince}
+			}
+
+			// Perform The Test
+			recorder := record.NewFakeRecorder(1)
+			r.reconcileOffsetsRetention(controller.WithEventRecorder(context.TODO(), recorder), channel, &config.NamespaceConfig{EventingKafkaConfig: configuration})
+
+			// Verify The Recorded Times (Only The Empty Subscriber's Being Kept Once Checked)
+			emptySinceJson, ok := channel.Status.Annotations[constants.SubscriberEmptySinceAnnotation]
+			if testCase.wantEmptySince < 0 {
+				assert.False(t, ok)
+			} else {
+				emptySince := make(map[string]time.Time)
+				assert.Nil(t, json.Unmarshal([]byte(emptySinceJson), &emptySince))
+				assert.WithinDuration(t, time.Now().Add(-testCase.wantEmptySince), emptySince[string(emptyUID)], time.Minute)
+				assert.Equal(t, testCase.adminClient == nil, len(emptySince) == 2)
+			}
+
+			// Verify The Condition & Event
+			condition := channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionOffsetsRetained)
+			if testCase.wantNoCondition {
+				assert.Nil(t, condition)
+			} else {
+				assert.NotNil(t, condition)
+				assert.Equal(t, testCase.wantStatus, condition.Status)
+			}
+			if len(testCase.wantEvent) > 0 {
+				assert.Contains(t, <-recorder.Events, testCase.wantEvent)
+				assert.Equal(t, OffsetsExpiringReason, condition.Reason)
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
@@ -25,6 +25,11 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
@@ -32,7 +37,7 @@ import (
 )
 
 // Reconcile The Kafka Topic Associated With The Specified Channel & Return The Kafka Secret
-func (r *Reconciler) reconcileTopic(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
+func (r *Reconciler) reconcileTopic(ctx context.Context, channel *kafkav1beta1.KafkaChannel, configuration *config.NamespaceConfig) error {
 
 	// Get The TopicName For Specified Channel
 	topicName := util.TopicName(channel)
@@ -40,13 +45,34 @@ func (r *Reconciler) reconcileTopic(ctx context.Context, channel *kafkav1beta1.K
 	// Get Channel Specific Logger & Add Topic Name
 	logger := util.ChannelLogger(r.logger, channel).With(zap.String("TopicName", topicName))
 
-	// Get The Topic Configuration (First From Channel With Failover To Environment)
-	numPartitions := util.NumPartitions(channel, r.config, r.logger)
-	replicationFactor := util.ReplicationFactor(channel, r.config, r.logger)
-	retentionMillis := util.RetentionMillis(channel, r.config, r.logger)
+	// Get The Topic Configuration (First From Channel With Failover To The Namespace / Cluster-Wide ConfigMap)
+	numPartitions := util.NumPartitions(channel, configuration.EventingKafkaConfig, r.logger)
+	replicationFactor := util.ReplicationFactor(channel, configuration.EventingKafkaConfig, r.logger)
+	retentionMillis := util.RetentionMillis(channel, configuration.EventingKafkaConfig, r.logger)
+	cleanupPolicy := channel.Spec.CleanupPolicy
+
+	// Reject Any ExtraTopics Not Allowed By The Configuration (Fanning In ExtraTopics Is Disabled By Default)
+	err := configuration.EventingKafkaConfig.Kafka.ExtraTopics.Verify(channel.Spec.ExtraTopics)
 
 	// Create The Topic (Handles Case Where Already Exists)
-	err := r.createTopic(ctx, topicName, numPartitions, replicationFactor, retentionMillis)
+	if err == nil {
+		err = r.createTopic(ctx, channel, topicName, numPartitions, replicationFactor, retentionMillis, cleanupPolicy)
+	}
+
+	// Create Any Event Type Sub-Topics With The Same Configuration
+	if err == nil {
+		err = r.createEventTypeTopics(ctx, channel, topicName, numPartitions, replicationFactor, retentionMillis, cleanupPolicy)
+	}
+
+	// Ensure Any Kafka Backed Subscriber DeadLetter Topics Exist With The Same Configuration (Never Compacted)
+	if err == nil {
+		err = r.createDeadLetterTopics(ctx, channel, topicName, numPartitions, replicationFactor, retentionMillis)
+	}
+
+	// Ensure The Channel's Quarantine Topic Exists If Enabled (Never Compacted)
+	if err == nil {
+		err = r.createQuarantineTopic(ctx, channel, topicName, numPartitions, replicationFactor, retentionMillis, configuration.EventingKafkaConfig)
+	}
 
 	// Log Results & Return Status
 	if err != nil {
@@ -56,12 +82,13 @@ func (r *Reconciler) reconcileTopic(ctx context.Context, channel *kafkav1beta1.K
 	} else {
 		logger.Info("Successfully Reconciled Topic")
 		channel.Status.MarkTopicTrue()
+		channel.Status.Topic = topicName
 	}
 	return err
 }
 
-// Create The Specified Kafka Topic
-func (r *Reconciler) createTopic(ctx context.Context, topicName string, partitions int32, replicationFactor int16, retentionMillis int64) error {
+// Create The Specified Kafka Topic Of The Specified Channel (CleanupPolicy Is Optional & Defaults To The Kafka Broker's)
+func (r *Reconciler) createTopic(ctx context.Context, channel *kafkav1beta1.KafkaChannel, topicName string, partitions int32, replicationFactor int16, retentionMillis int64, cleanupPolicy string) error {
 
 	// Setup The Logger
 	logger := r.logger.With(zap.String("Topic", topicName))
@@ -76,6 +103,16 @@ func (r *Reconciler) createTopic(ctx context.Context, topicName string, partitio
 			constants.KafkaTopicConfigRetentionMs: &retentionMillisString,
 		},
 	}
+	if len(cleanupPolicy) > 0 {
+		topicDetail.ConfigEntries[constants.KafkaTopicConfigCleanupPolicy] = &cleanupPolicy
+	}
+
+	// Reject Topics Which The TopicProvisioner Cannot Create Before Attempting To
+	validationErr := r.adminClient.Validate(ctx, topicName, topicDetail)
+	if validationErr != nil {
+		logger.Error("Invalid Topic", zap.Error(validationErr))
+		return validationErr
+	}
 
 	// Attempt To Create The Topic & Process TopicError Results (Including Success ;)
 	err := r.adminClient.CreateTopic(ctx, topicName, topicDetail)
@@ -83,6 +120,8 @@ func (r *Reconciler) createTopic(ctx context.Context, topicName string, partitio
 		switch err.Err {
 		case sarama.ErrNoError:
 			logger.Info("Successfully Created New Kafka Topic (ErrNoError)")
+			r.auditTopic(audit.ActionCreated, topicName, topicDetails(topicDetail))
+			r.recordManagedTopic(ctx, topicName, channel)
 			return nil
 		case sarama.ErrTopicAlreadyExists:
 			logger.Info("Kafka Topic Already Exists - No Creation Required")
@@ -93,8 +132,56 @@ func (r *Reconciler) createTopic(ctx context.Context, topicName string, partitio
 		}
 	} else {
 		logger.Info("Successfully Created New Kafka Topic (Nil TopicError)")
+		r.auditTopic(audit.ActionCreated, topicName, topicDetails(topicDetail))
+		r.recordManagedTopic(ctx, topicName, channel)
+		return nil
+	}
+}
+
+// Create The Event Type Sub-Topics Of The Specified Channel (If Event Type Routing Is Enabled)
+func (r *Reconciler) createEventTypeTopics(ctx context.Context, channel *kafkav1beta1.KafkaChannel, topicName string, partitions int32, replicationFactor int16, retentionMillis int64, cleanupPolicy string) error {
+
+	// Get The Channel's EventTypeRouting From Its Annotations
+	eventTypeRouting, err := routing.NewEventTypeRouting(channel.Annotations)
+	if err != nil {
+		return err
+	}
+
+	// Create Each Of The Sub-Topics (Handles Case Where Already Exists)
+	for _, eventTypeTopicName := range eventTypeRouting.Topics(topicName) {
+		err = r.createTopic(ctx, channel, eventTypeTopicName, partitions, replicationFactor, retentionMillis, cleanupPolicy)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ensure The DeadLetter Topics Of The Specified Channel's Subscribers Exist (For DeadLetterSinks Backed By Kafka)
+func (r *Reconciler) createDeadLetterTopics(ctx context.Context, channel *kafkav1beta1.KafkaChannel, topicName string, partitions int32, replicationFactor int16, retentionMillis int64) error {
+
+	// Create The Topic Of Each Kafka Backed DeadLetterSink (Handles Case Where Already Exists, Such As KafkaChannels)
+	for i := range channel.Spec.Subscribers {
+		deadLetterTopicName, ok := kafkautil.DeadLetterTopic(topicName, &channel.Spec.Subscribers[i])
+		if ok && deadLetterTopicName != topicName {
+			err := r.createTopic(ctx, channel, deadLetterTopicName, partitions, replicationFactor, retentionMillis, "")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Ensure The Quarantine Topic Of The Specified Channel Topic Exists (If Quarantine Is Enabled, With Any Quarantine Specific Retention)
+func (r *Reconciler) createQuarantineTopic(ctx context.Context, channel *kafkav1beta1.KafkaChannel, topicName string, partitions int32, replicationFactor int16, retentionMillis int64, configuration *config.EventingKafkaConfig) error {
+	if !configuration.Kafka.Quarantine.Enabled {
 		return nil
 	}
+	if configuration.Kafka.Quarantine.RetentionMillis > 0 {
+		retentionMillis = configuration.Kafka.Quarantine.RetentionMillis
+	}
+	return r.createTopic(ctx, channel, kafkautil.QuarantineTopicName(topicName), partitions, replicationFactor, retentionMillis, "")
 }
 
 // Delete The Specified Kafka Topic
@@ -109,12 +196,15 @@ func (r *Reconciler) deleteTopic(ctx context.Context, topicName string) error {
 		switch err.Err {
 		case sarama.ErrNoError:
 			logger.Info("Successfully Deleted Existing Kafka Topic (ErrNoError)")
+			r.auditTopic(audit.ActionDeleted, topicName, nil)
+			r.forgetManagedTopic(ctx, topicName)
 			return nil
 		case sarama.ErrUnknownTopicOrPartition, sarama.ErrInvalidTopic, sarama.ErrInvalidPartitions:
 			logger.Info("Kafka Topic or Partition Not Found - No Deletion Required")
+			r.forgetManagedTopic(ctx, topicName)
 			return nil
 		case sarama.ErrInvalidConfig:
-			if r.config.Kafka.AdminType == constants.KafkaAdminTypeValueAzure {
+			if r.config.Kafka.AdminType == kafkaadmin.EventHubProvisionerName {
 				// While this could be a valid Kafka error, this most likely is coming from our custom EventHub AdminClient
 				// implementation and represents the fact that the EventHub Cache does not contain this topic.  This can
 				// happen when an EventHub could not be created due to exceeding the number of allowable EventHubs.  The
@@ -132,6 +222,8 @@ func (r *Reconciler) deleteTopic(ctx context.Context, topicName string) error {
 		}
 	} else {
 		logger.Info("Successfully Deleted Existing Kafka Topic (Nil TopicError)")
+		r.auditTopic(audit.ActionDeleted, topicName, nil)
+		r.forgetManagedTopic(ctx, topicName)
 		return nil
 	}
 }
@@ -18,16 +18,24 @@ package kafkachannel
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/Shopify/sarama"
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/controller"
 	logtesting "knative.dev/pkg/logging/testing"
 )
@@ -52,6 +60,9 @@ type TopicTestCase struct {
 //
 func TestReconcileTopic(t *testing.T) {
 
+	// Test Data
+	compactCleanupPolicy := kafkav1beta1.CleanupPolicyCompact
+
 	// Define & Initialize The TopicTestCases
 	topicTestCases := []TopicTestCase{
 		{
@@ -88,6 +99,28 @@ func TestReconcileTopic(t *testing.T) {
 				ConfigEntries:     map[string]*string{constants.KafkaTopicConfigRetentionMs: &controllertesting.DefaultRetentionMillisString},
 			},
 		},
+		{
+			Name: "Create New Compacted Topic",
+			Channel: func() *kafkav1beta1.KafkaChannel {
+				channel := controllertesting.NewKafkaChannel(
+					controllertesting.WithFinalizer,
+					controllertesting.WithAddress,
+					controllertesting.WithInitializedConditions,
+				)
+				channel.Spec.CleanupPolicy = kafkav1beta1.CleanupPolicyCompact
+				return channel
+			}(),
+			WantCreate: true,
+			WantDelete: false,
+			WantTopicDetail: &sarama.TopicDetail{
+				NumPartitions:     controllertesting.NumPartitions,
+				ReplicationFactor: controllertesting.ReplicationFactor,
+				ConfigEntries: map[string]*string{
+					constants.KafkaTopicConfigRetentionMs:   &controllertesting.DefaultRetentionMillisString,
+					constants.KafkaTopicConfigCleanupPolicy: &compactCleanupPolicy,
+				},
+			},
+		},
 		{
 			Name: "Create Preexisting Topic",
 			Channel: controllertesting.NewKafkaChannel(
@@ -205,7 +238,7 @@ func topicTestCaseFactory(tc TopicTestCase) func(t *testing.T) {
 
 		// Perform The Test (Create) - Normal Topic Reconciliation Called Indirectly From ReconcileKind()
 		if tc.WantCreate {
-			err = r.reconcileTopic(ctx, tc.Channel)
+			err = r.reconcileTopic(ctx, tc.Channel, &config.NamespaceConfig{EventingKafkaConfig: r.config})
 			if !mockAdminClient.CreateTopicsCalled() {
 				t.Errorf("expected CreateTopics() called to be %t", tc.WantCreate)
 			}
@@ -284,3 +317,255 @@ func createMockAdminClientForTestCase(t *testing.T, tc TopicTestCase) *controlle
 		},
 	}
 }
+
+// Test The Kafka Topic Reconciliation Of A KafkaChannel With Event Type Routing Enabled
+func TestReconcileEventTypeTopics(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		annotations map[string]string
+		wantTopics  []string
+		wantError   string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:        "Event Type Topics Created",
+			annotations: map[string]string{kafkaconstants.EventTypesAnnotation: "com.example.b,com.example.a"},
+			wantTopics:  []string{controllertesting.TopicName, controllertesting.TopicName + ".com.example.a", controllertesting.TopicName + ".com.example.b"},
+		},
+		{
+			name: "Invalid Event Type Routing",
+			annotations: map[string]string{
+				kafkaconstants.EventTypesAnnotation:           "com.example.a",
+				kafkaconstants.SubscriberEventTypesAnnotation: "invalid",
+			},
+			wantTopics: []string{controllertesting.TopicName},
+			wantError:  "invalid kafka.eventing.knative.dev/subscriber-event-types annotation: invalid character 'i' looking for beginning of value",
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Mock AdminClient Which Tracks The Created Topics
+			var createdTopics []string
+			mockAdminClient := &controllertesting.MockAdminClient{
+				MockCreateTopicFunc: func(_ context.Context, topicName string, _ *sarama.TopicDetail) *sarama.TopicError {
+					createdTopics = append(createdTopics, topicName)
+					return nil
+				},
+			}
+
+			// Create The Reconciler & KafkaChannel With The Event Type Routing Annotations
+			r := &Reconciler{
+				logger:      logtesting.TestLogger(t).Desugar(),
+				adminClient: mockAdminClient,
+				config:      controllertesting.NewConfig(),
+			}
+			channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+			channel.Annotations = testCase.annotations
+
+			// Perform The Test
+			recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+			err := r.reconcileTopic(controller.WithEventRecorder(context.TODO(), recorder), channel, &config.NamespaceConfig{EventingKafkaConfig: r.config})
+
+			// Verify The Results
+			assert.Equal(t, testCase.wantTopics, createdTopics)
+			if len(testCase.wantError) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.wantError, err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// Test The Provisioning Of Subscriber DeadLetter Topics
+func TestReconcileDeadLetterTopics(t *testing.T) {
+
+	// Create A Mock AdminClient Which Tracks The Created Topics
+	var createdTopics []string
+	mockAdminClient := &controllertesting.MockAdminClient{
+		MockCreateTopicFunc: func(_ context.Context, topicName string, _ *sarama.TopicDetail) *sarama.TopicError {
+			createdTopics = append(createdTopics, topicName)
+			return nil
+		},
+	}
+
+	// Create The Reconciler
+	r := &Reconciler{
+		logger:      logtesting.TestLogger(t).Desugar(),
+		adminClient: mockAdminClient,
+		config:      controllertesting.NewConfig(),
+	}
+
+	// Create A KafkaChannel With Shorthand, KafkaChannel & HTTP DeadLetterSink Subscribers
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+	channel.Spec.Subscribers = []eventingduck.SubscriberSpec{
+		{UID: "uid-1", Delivery: &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: kafkaconstants.DeadLetterSinkKafkaScheme}}}},
+		{UID: "uid-2", Delivery: &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "dlq-kn-channel.dlq-namespace.svc.cluster.local"}}}},
+		{UID: "uid-3", Delivery: &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "dlq-service.dlq-namespace.svc.cluster.local"}}}},
+		{UID: "uid-4"},
+	}
+
+	// Perform The Test
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+	err := r.reconcileTopic(controller.WithEventRecorder(context.TODO(), recorder), channel, &config.NamespaceConfig{EventingKafkaConfig: r.config})
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Equal(t, []string{controllertesting.TopicName, controllertesting.TopicName + ".uid-1.dlq", "dlq-namespace.dlq"}, createdTopics)
+}
+
+// Test The reconcileTopic() Functionality With Quarantine Topics Enabled
+func TestReconcileQuarantineTopic(t *testing.T) {
+
+	// Create A Mock AdminClient Which Tracks The Created Topics & Their Retention
+	createdTopics := make(map[string]string)
+	mockAdminClient := &controllertesting.MockAdminClient{
+		MockCreateTopicFunc: func(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
+			createdTopics[topicName] = *topicDetail.ConfigEntries[constants.KafkaTopicConfigRetentionMs]
+			return nil
+		},
+	}
+
+	// Create The Reconciler With Quarantine Topics Enabled
+	r := &Reconciler{
+		logger:      logtesting.TestLogger(t).Desugar(),
+		adminClient: mockAdminClient,
+		config:      controllertesting.NewConfig(),
+	}
+	r.config.Kafka.Quarantine = config.EKQuarantineConfig{Enabled: true, RetentionMillis: 12345}
+
+	// Perform The Test
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+	err := r.reconcileTopic(controller.WithEventRecorder(context.TODO(), recorder), channel, &config.NamespaceConfig{EventingKafkaConfig: r.config})
+
+	// Verify The Quarantine Topic Was Created With Its Own Retention
+	assert.Nil(t, err)
+	assert.Len(t, createdTopics, 2)
+	assert.Contains(t, createdTopics, controllertesting.TopicName)
+	assert.Equal(t, "12345", createdTopics[controllertesting.TopicName+".quarantine"])
+}
+
+// Test The reconcileTopic() Functionality With A Topic Rejected By The TopicProvisioner's Validation
+func TestReconcileTopicInvalid(t *testing.T) {
+
+	// Create A Mock AdminClient Which Rejects All Topics
+	validationErr := errors.New("test validation error")
+	mockAdminClient := &controllertesting.MockAdminClient{
+		MockValidateFunc: func(_ context.Context, topicName string, _ *sarama.TopicDetail) error {
+			assert.Equal(t, controllertesting.TopicName, topicName)
+			return validationErr
+		},
+	}
+
+	// Create The Reconciler
+	r := &Reconciler{
+		logger:      logtesting.TestLogger(t).Desugar(),
+		adminClient: mockAdminClient,
+		config:      controllertesting.NewConfig(),
+	}
+
+	// Perform The Test
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+	err := r.reconcileTopic(controller.WithEventRecorder(context.TODO(), recorder), channel, &config.NamespaceConfig{EventingKafkaConfig: r.config})
+
+	// Verify The Topic Was Never Created & The Channel's Topic Failed
+	assert.Equal(t, validationErr, err)
+	assert.False(t, mockAdminClient.CreateTopicsCalled())
+	assert.False(t, channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionTopicReady).IsTrue())
+}
+
+// Test The Recording Of The Topics Created (And Forgetting Of Those Deleted) By The Controller While The Janitor Is Enabled
+func TestReconcileTopicManagedTopics(t *testing.T) {
+
+	// Create A Mock AdminClient For Which The Channel's Quarantine Topic Already Exists
+	mockAdminClient := &controllertesting.MockAdminClient{
+		MockCreateTopicFunc: func(_ context.Context, topicName string, _ *sarama.TopicDetail) *sarama.TopicError {
+			if topicName == controllertesting.TopicName+".quarantine" {
+				return &sarama.TopicError{Err: sarama.ErrTopicAlreadyExists}
+			}
+			return nil
+		},
+	}
+
+	// Create The Reconciler With The Janitor & Quarantine Topics Enabled
+	kubeClientset := fake.NewSimpleClientset()
+	r := &Reconciler{
+		logger:        logtesting.TestLogger(t).Desugar(),
+		kubeClientset: kubeClientset,
+		adminClient:   mockAdminClient,
+		config:        controllertesting.NewConfig(),
+	}
+	r.config.Janitor.Enabled = true
+	r.config.Kafka.Quarantine = config.EKQuarantineConfig{Enabled: true}
+
+	// Perform The Test
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+	ctx := controller.WithEventRecorder(context.TODO(), recorder)
+	assert.Nil(t, r.reconcileTopic(ctx, channel, &config.NamespaceConfig{EventingKafkaConfig: r.config}))
+
+	// Verify Only The Topic Actually Created Was Recorded (With Its KafkaChannel)
+	managedTopics, err := r.getManagedTopics(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{controllertesting.TopicName: controllertesting.KafkaChannelNamespace + "/" + controllertesting.KafkaChannelName}, managedTopics)
+
+	// Verify The Topic Is Forgotten Once Deleted
+	assert.Nil(t, r.deleteTopic(ctx, controllertesting.TopicName))
+	managedTopics, err = r.getManagedTopics(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, managedTopics)
+}
+
+// Test The reconcileTopic() Functionality With ExtraTopics Allowed / Not Allowed By The Configuration
+func TestReconcileTopicExtraTopics(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name            string
+		allowedPrefixes []string
+		wantErr         bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Disabled By Default", wantErr: true},
+		{name: "Not Allowed Prefix", allowedPrefixes: []string{"other."}, wantErr: true},
+		{name: "Allowed Prefix", allowedPrefixes: []string{"external."}, wantErr: false},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Reconciler With The TestCase's Allowed ExtraTopics Prefixes
+			mockAdminClient := &controllertesting.MockAdminClient{}
+			r := &Reconciler{
+				logger:      logtesting.TestLogger(t).Desugar(),
+				adminClient: mockAdminClient,
+				config:      controllertesting.NewConfig(),
+			}
+			r.config.Kafka.ExtraTopics.AllowedPrefixes = testCase.allowedPrefixes
+
+			// Perform The Test With A KafkaChannel Fanning In An ExtraTopic
+			channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+			channel.Spec.ExtraTopics = []string{"external.topic"}
+			recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+			err := r.reconcileTopic(controller.WithEventRecorder(context.TODO(), recorder), channel, &config.NamespaceConfig{EventingKafkaConfig: r.config})
+
+			// Verify Disallowed ExtraTopics Fail The Topic Before Any Topic Is Created
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, !testCase.wantErr, mockAdminClient.CreateTopicsCalled())
+			assert.Equal(t, !testCase.wantErr, channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionTopicReady).IsTrue())
+		})
+	}
+}
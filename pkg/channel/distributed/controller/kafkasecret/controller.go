@@ -18,27 +18,28 @@ package kafkasecret
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
-	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/config"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkasecretinformer"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkasecretinjection"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/managedinformer"
 	injectionclient "knative.dev/eventing-kafka/pkg/client/injection/client"
 	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
-	"knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
-	"knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
 )
 
 // Create A New KafkaSecret Controller
@@ -50,8 +51,8 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	// Get The Needed Informers
 	kafkaSecretInformer := kafkasecretinformer.Get(ctx)
 	kafkachannelInformer := kafkachannel.Get(ctx)
-	deploymentInformer := deployment.Get(ctx)
-	serviceInformer := service.Get(ctx)
+	deploymentInformer := managedinformer.GetReceiverDeployments(ctx)
+	serviceInformer := managedinformer.GetReceiverServices(ctx)
 
 	// Load The Environment Variables
 	environment, err := env.GetEnvironment(logger)
@@ -60,8 +61,8 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	}
 
 	// Load the Sarama and other eventing-kafka settings from our configmap
-	// (though we don't need the Sarama settings here; the AdminClient loads them from the configmap each time it needs them)
-	_, configuration, err := sarama.LoadSettings(ctx)
+	// (the Sarama settings are only needed here by the audit log; the AdminClient loads them from the configmap each time it needs them)
+	saramaConfig, configuration, err := sarama.LoadSettings(ctx)
 	if err != nil {
 		logger.Fatal("Failed To Load Eventing-Kafka Settings", zap.Error(err))
 	}
@@ -81,8 +82,12 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 		kafkachannelLister: kafkachannelInformer.Lister(),
 		deploymentLister:   deploymentInformer.Lister(),
 		serviceLister:      serviceInformer.Lister(),
+		now:                time.Now,
 	}
 
+	// Start The Audit Log (nil If Not Enabled) Recording The Rotations Of Kafka Secrets & Changes To The Receivers
+	r.auditRecorder = audit.StartControllerLog(ctx, logger, r.kubeClientset, configuration, saramaConfig, environment.PodName)
+
 	// Create A New KafkaSecret Controller Impl With The Reconciler
 	controllerImpl := kafkasecretinjection.NewImpl(ctx, r)
 
@@ -121,7 +126,7 @@ func enqueueSecretOfKafkaChannel(controller *controller.Impl) func(obj interface
 				secretName := labels[constants.KafkaSecretLabel]
 				if len(secretName) > 0 {
 					controller.EnqueueKey(types.NamespacedName{
-						Namespace: commonconstants.KnativeEventingNamespace,
+						Namespace: system.Namespace(),
 						Name:      secretName,
 					})
 				}
@@ -18,7 +18,12 @@ package kafkasecret
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -26,10 +31,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
-	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 //
@@ -61,27 +68,62 @@ func (r *Reconciler) reconcileKafkaChannelStatus(ctx context.Context,
 		return err
 	}
 
-	// Update All The KafkaChannels Status As Specified (Process All Regardless Of Error)
-	statusUpdateErrors := false
+	//
+	// Update All The KafkaChannels Status As Specified (Process All Regardless Of Error, Skipping Those With Their Own
+	// Isolated Receiver Whose Status Is Reconciled By The KafkaChannel Controller)
+	//
+	// The KafkaChannels are independent of one another, so their status is updated by a bounded number of concurrent
+	// workers rather than serially (which takes minutes for the hundreds of KafkaChannels a Kafka Secret may serve).
+	// The reconciliation of each Kafka Secret remains serialized by the controller's work queue.
+	//
+	var waitGroup sync.WaitGroup
+	var failuresLock sync.Mutex
+	var failures []string
+	workers := make(chan struct{}, constants.KafkaChannelStatusWorkers)
+	updateCount := 0
 	for _, kafkaChannel := range kafkaChannels {
-		if kafkaChannel != nil {
+		if kafkaChannel == nil || util.IsReceiverIsolated(kafkaChannel, r.config.Receiver.Isolation) {
+			continue
+		}
+		updateCount++
+		waitGroup.Add(1)
+		workers <- struct{}{}
+		go func(kafkaChannel *kafkav1beta1.KafkaChannel) {
+			defer func() {
+				<-workers
+				waitGroup.Done()
+			}()
 			err := r.updateKafkaChannelStatus(ctx, kafkaChannel, serviceValid, serviceReason, serviceMessage, deploymentValid, deploymentReason, deploymentMessage)
 			if err != nil {
-				logger.Error("Failed To Update KafkaChannel Status", zap.Error(err))
-				statusUpdateErrors = true
+				logger.Error("Failed To Update KafkaChannel Status", zap.String("KafkaChannel", kafkaChannel.Namespace+"/"+kafkaChannel.Name), zap.Error(err))
+				failuresLock.Lock()
+				failures = append(failures, fmt.Sprintf("%s/%s: %v", kafkaChannel.Namespace, kafkaChannel.Name, err))
+				failuresLock.Unlock()
 			}
-		}
+		}(kafkaChannel)
 	}
+	waitGroup.Wait()
 
-	// Return Status Update Error
-	if statusUpdateErrors {
-		return fmt.Errorf("failed to update Status of one or more KafkaChannels")
+	// Return The Aggregated Status Update Errors (Sorted For Stable Reporting)
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("failed to update Status of %d of %d KafkaChannels: %s", len(failures), updateCount, strings.Join(failures, "; "))
 	} else {
 		return nil
 	}
 }
 
+//
 // Update A Single KafkaChannel's Status To Reflect The Specified Channel Service/Deployment State
+//
+// Rather than updating the whole status, which conflicts with the KafkaChannel controller's concurrent updates of
+// the other conditions (and so requires reloading & retrying the update), only the conditions changed here (the
+// ServiceReady & EndpointsReady conditions, and the Ready condition summarizing them) are patched.  Each replaced
+// condition is guarded by a test of its type at its index, so the patch only fails if the conditions have been
+// re-ordered in the meantime (in which case the Kafka Secret is re-reconciled).  Should the KafkaChannel's other
+// conditions have changed, the Ready condition is corrected by the KafkaChannel controller's reconciliation which
+// the patch triggers.
+//
 func (r *Reconciler) updateKafkaChannelStatus(ctx context.Context, originalChannel *kafkav1beta1.KafkaChannel,
 	serviceValid bool, serviceReason string, serviceMessage string,
 	deploymentValid bool, deploymentReason string, deploymentMessage string) error {
@@ -89,61 +131,95 @@ func (r *Reconciler) updateKafkaChannelStatus(ctx context.Context, originalChann
 	// Get A KafkaChannel Logger
 	logger := util.ChannelLogger(r.logger, originalChannel)
 
-	// Update The KafkaChannel (Retry On Conflict - KafkaChannel Controller Will Also Be Updating KafkaChannel Status)
-	return reconciler.RetryUpdateConflicts(func(attempts int) error {
+	// Clone The KafkaChannel So As Not To Perturb Informers Copy
+	updatedChannel := originalChannel.DeepCopy()
 
-		var err error
+	// Update Service Status Based On Specified State
+	if serviceValid {
+		updatedChannel.Status.MarkServiceTrue()
+	} else {
+		updatedChannel.Status.MarkServiceFailed(serviceReason, serviceMessage)
+	}
 
-		// After First Attempt - Reload The Original KafkaChannel From K8S
-		if attempts > 0 {
-			originalChannel, err = r.kafkachannelLister.KafkaChannels(originalChannel.Namespace).Get(originalChannel.Name)
-			if err != nil {
-				logger.Error("Failed To Reload KafkaChannel For Status Update", zap.Error(err))
-				return err
-			}
-		}
+	//
+	// Update Deployment Status Based On Specified State
+	//
+	// TODO - As part of the conversion to the eventing-contrib KafkaChannel CRD and its associated
+	//        Status, we've not yet implemented Endpoint tracking.  Until this is done we'll track
+	//        the Deployments As Endpoints (since they will result in the Endpoints being up anyway).
+	//
+	if deploymentValid {
+		updatedChannel.Status.MarkEndpointsTrue()
+	} else {
+		updatedChannel.Status.MarkEndpointsFailed(deploymentReason, deploymentMessage)
+	}
 
-		// Clone The KafkaChannel So As Not To Perturb Informers Copy
-		updatedChannel := originalChannel.DeepCopy()
+	// Create The JSON Patch Of The Changed Conditions
+	patch, err := newConditionsPatch(originalChannel.Status.Conditions, updatedChannel.Status.Conditions, r.now())
+	if err != nil {
+		logger.Error("Failed To Create KafkaChannel Status Patch", zap.Error(err)) // Should Never Happen
+		return err
+	}
 
-		// Update Service Status Based On Specified State
-		if serviceValid {
-			updatedChannel.Status.MarkServiceTrue()
-		} else {
-			updatedChannel.Status.MarkServiceFailed(serviceReason, serviceMessage)
-		}
+	// If No Conditions Changed - Return Success
+	if patch == nil {
+		logger.Info("Successfully Verified KafkaChannel Status")
+		return nil
+	}
 
-		//
-		// Update Deployment Status Based On Specified State
-		//
-		// TODO - As part of the conversion to the eventing-contrib KafkaChannel CRD and its associated
-		//        Status, we've not yet implemented Endpoint tracking.  Until this is done we'll track
-		//        the Deployments As Endpoints (since they will result in the Endpoints being up anyway).
-		//
-		if deploymentValid {
-			updatedChannel.Status.MarkEndpointsTrue()
-		} else {
-			updatedChannel.Status.MarkEndpointsFailed(deploymentReason, deploymentMessage)
-		}
+	// Otherwise Attempt To Patch The KafkaChannel Status
+	_, err = r.kafkaChannelClient.MessagingV1beta1().KafkaChannels(originalChannel.Namespace).Patch(ctx, originalChannel.Name, types.JSONPatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		logger.Error("Failed To Patch KafkaChannel Status", zap.Error(err))
+		return err
+	}
+	logger.Info("Successfully Patched KafkaChannel Status")
+	return nil
+}
 
-		// If The KafkaChannel Status Changed
-		if !equality.Semantic.DeepEqual(originalChannel.Status, updatedChannel.Status) {
+// A Single JSON Patch (RFC 6902) Operation
+type jsonPatchOperation struct {
+	Operation string      `json:"op"`
+	Path      string      `json:"path"`
+	Value     interface{} `json:"value"`
+}
 
-			// Then Attempt To Update The KafkaChannel Status
-			_, err = r.kafkaChannelClient.MessagingV1beta1().KafkaChannels(updatedChannel.Namespace).UpdateStatus(ctx, updatedChannel, metav1.UpdateOptions{})
-			if err != nil {
-				logger.Error("Failed To Update KafkaChannel Status", zap.Error(err))
-				return err
-			} else {
-				logger.Info("Successfully Updated KafkaChannel Status")
-				return nil
-			}
+// Create A JSON Patch Of The Updated Conditions Which Differ From The Original Conditions (nil If None Differ), Whose
+// LastTransitionTime Is The Specified Time
+func newConditionsPatch(originalConditions duckv1.Conditions, updatedConditions duckv1.Conditions, now time.Time) ([]byte, error) {
 
-		} else {
+	// Index The Original Conditions By Type
+	originalIndexes := make(map[apis.ConditionType]int, len(originalConditions))
+	for index, condition := range originalConditions {
+		originalIndexes[condition.Type] = index
+	}
 
-			// Otherwise No Change To Status - Return Success
-			logger.Info("Successfully Verified KafkaChannel Status")
-			return nil
+	// Replace The Changed Conditions At Their Index (Testing Their Type First) & Append The New Conditions
+	var operations []jsonPatchOperation
+	var changedConditions duckv1.Conditions
+	for _, condition := range updatedConditions {
+		index, exists := originalIndexes[condition.Type]
+		if exists && equality.Semantic.DeepEqual(originalConditions[index], condition) {
+			continue
+		}
+		condition.LastTransitionTime = apis.VolatileTime{Inner: metav1.NewTime(now)}
+		changedConditions = append(changedConditions, condition)
+		if exists {
+			path := fmt.Sprintf("/status/conditions/%d", index)
+			operations = append(operations,
+				jsonPatchOperation{Operation: "test", Path: path + "/type", Value: condition.Type},
+				jsonPatchOperation{Operation: "replace", Path: path, Value: condition})
+		} else {
+			operations = append(operations, jsonPatchOperation{Operation: "add", Path: "/status/conditions/-", Value: condition})
 		}
-	})
+	}
+	if len(changedConditions) == 0 {
+		return nil, nil
+	}
+
+	// Add All The Conditions At Once If There Were None
+	if len(originalConditions) == 0 {
+		operations = []jsonPatchOperation{{Operation: "add", Path: "/status/conditions", Value: changedConditions}}
+	}
+	return json.Marshal(operations)
 }
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkasecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	kafkafake "knative.dev/eventing-kafka/pkg/client/clientset/versioned/fake"
+	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// The Fixed LastTransitionTime Of The Patched KafkaChannel Conditions
+var testNow = time.Date(2020, time.November, 1, 12, 0, 0, 0, time.UTC)
+
+// Test The Parallel Status Updates Of The reconcileKafkaChannelStatus() Functionality
+func TestReconcileKafkaChannelStatus(t *testing.T) {
+
+	// Create Many KafkaChannels Of The Kafka Secret (A Few Of Which Fail To Update, And One Of Which Is Isolated)
+	channelCount := 5 * constants.KafkaChannelStatusWorkers
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	var objects []runtime.Object
+	for i := 0; i < channelCount; i++ {
+		channel := controllertesting.NewKafkaChannel(controllertesting.WithLabels)
+		channel.Name = fmt.Sprintf("channel-%03d", i)
+		if i%25 == 0 {
+			channel.Name = fmt.Sprintf("failing-%03d", i)
+		}
+		if i == 1 {
+			channel.Annotations = map[string]string{kafkaconstants.ReceiverIsolationAnnotation: util.ReceiverIsolationChannel}
+		}
+		assert.Nil(t, indexer.Add(channel))
+		objects = append(objects, channel)
+	}
+
+	// Record The Updated KafkaChannels (The Fake Clientset Serializes The Reactors, So Only The Results Are Verified)
+	var lock sync.Mutex
+	var updated []string
+	kafkaClient := kafkafake.NewSimpleClientset(objects...)
+	kafkaClient.PrependReactor("patch", "kafkachannels", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		name := action.(clientgotesting.PatchAction).GetName()
+		lock.Lock()
+		updated = append(updated, name)
+		lock.Unlock()
+		if strings.HasPrefix(name, "failing-") {
+			return true, nil, errors.New("forbidden")
+		}
+		return false, nil, nil
+	})
+
+	// Create The Reconciler
+	r := &Reconciler{
+		logger:             logtesting.TestLogger(t).Desugar(),
+		config:             controllertesting.NewConfig(),
+		kafkaChannelClient: kafkaClient,
+		kafkachannelLister: kafkalisters.NewKafkaChannelLister(indexer),
+		now:                time.Now,
+	}
+
+	// Perform The Test
+	err := r.reconcileKafkaChannelStatus(context.TODO(), controllertesting.NewKafkaSecret(),
+		false, "ServiceReason", "Service Message", false, "DeploymentReason", "Deployment Message")
+
+	// Verify Every Non-Isolated KafkaChannel Was Updated
+	assert.Len(t, updated, channelCount-1)
+	assert.NotContains(t, updated, "channel-001")
+
+	// Verify The Failures Are Aggregated Into A Single Error
+	assert.EqualError(t, err, fmt.Sprintf("failed to update Status of 4 of %d KafkaChannels: "+
+		"kafkachannel-namespace/failing-000: forbidden; kafkachannel-namespace/failing-025: forbidden; "+
+		"kafkachannel-namespace/failing-050: forbidden; kafkachannel-namespace/failing-075: forbidden", channelCount-1))
+}
+
+// Test The Patching Of A Single KafkaChannel's Status By The updateKafkaChannelStatus() Functionality
+func TestUpdateKafkaChannelStatus(t *testing.T) {
+
+	// Create A KafkaChannel Whose Receiver Is Ready, Alongside A Condition Of The KafkaChannel Controller
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithReceiverServiceReady, controllertesting.WithReceiverDeploymentReady)
+	channel.Status.MarkTopicTrue()
+	kafkaClient := kafkafake.NewSimpleClientset(channel)
+	r := &Reconciler{
+		logger:             logtesting.TestLogger(t).Desugar(),
+		kafkaChannelClient: kafkaClient,
+		now:                func() time.Time { return testNow },
+	}
+
+	// The KafkaChannel Controller Concurrently Updates Its Own Condition (The Informer's Copy Is Now Outdated)
+	concurrentChannel := channel.DeepCopy()
+	concurrentChannel.Status.MarkTopicFailed("TopicFailed", "Topic Failed")
+	_, err := kafkaClient.MessagingV1beta1().KafkaChannels(channel.Namespace).UpdateStatus(context.TODO(), concurrentChannel, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+
+	// Unchanged Conditions Are Not Patched
+	err = r.updateKafkaChannelStatus(context.TODO(), channel, true, "", "", true, "", "")
+	assert.Nil(t, err)
+	assert.Len(t, kafkaClient.Actions(), 1)
+
+	// Only The Receiver's Condition Is Patched, Without Conflicting With The KafkaChannel Controller's Update
+	err = r.updateKafkaChannelStatus(context.TODO(), channel, false, "ServiceReason", "Service Message", true, "", "")
+	assert.Nil(t, err)
+	patchedChannel, err := kafkaClient.MessagingV1beta1().KafkaChannels(channel.Namespace).Get(context.TODO(), channel.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	serviceCondition := patchedChannel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionServiceReady)
+	assert.Equal(t, corev1.ConditionFalse, serviceCondition.Status)
+	assert.Equal(t, "ServiceReason", serviceCondition.Reason)
+	assert.True(t, testNow.Equal(serviceCondition.LastTransitionTime.Inner.Time))
+	assert.True(t, patchedChannel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionEndpointsReady).IsTrue())
+	assert.True(t, patchedChannel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionTopicReady).IsFalse())
+
+	// A Patch Of Re-Ordered Conditions Fails
+	reorderedChannel := channel.DeepCopy()
+	reorderedChannel.Status.Conditions[0], reorderedChannel.Status.Conditions[1] = reorderedChannel.Status.Conditions[1], reorderedChannel.Status.Conditions[0]
+	_, err = kafkaClient.MessagingV1beta1().KafkaChannels(channel.Namespace).UpdateStatus(context.TODO(), reorderedChannel, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+	err = r.updateKafkaChannelStatus(context.TODO(), channel, true, "", "", false, "DeploymentReason", "Deployment Message")
+	assert.NotNil(t, err)
+}
+
+// Test The newConditionsPatch() Functionality
+func TestNewConditionsPatch(t *testing.T) {
+	ready := apis.Condition{Type: apis.ConditionReady, Status: corev1.ConditionTrue}
+	serviceReady := apis.Condition{Type: kafkav1beta1.KafkaChannelConditionServiceReady, Status: corev1.ConditionTrue}
+	serviceFailed := apis.Condition{Type: kafkav1beta1.KafkaChannelConditionServiceReady, Status: corev1.ConditionFalse, Reason: "Failed"}
+	endpointsReady := apis.Condition{Type: kafkav1beta1.KafkaChannelConditionEndpointsReady, Status: corev1.ConditionTrue}
+	transitionTime := `"lastTransitionTime":"` + testNow.Format(time.RFC3339) + `"`
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name      string
+		original  duckv1.Conditions
+		updated   duckv1.Conditions
+		wantPatch string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Unchanged", original: duckv1.Conditions{ready, serviceReady}, updated: duckv1.Conditions{ready, serviceReady}},
+		{name: "No Original Conditions", updated: duckv1.Conditions{serviceReady},
+			wantPatch: `[{"op":"add","path":"/status/conditions","value":[{"type":"ServiceReady","status":"True",` + transitionTime + `}]}]`},
+		{name: "Changed Condition", original: duckv1.Conditions{ready, serviceReady}, updated: duckv1.Conditions{ready, serviceFailed},
+			wantPatch: `[{"op":"test","path":"/status/conditions/1/type","value":"ServiceReady"},` +
+				`{"op":"replace","path":"/status/conditions/1","value":{"type":"ServiceReady","status":"False",` + transitionTime + `,"reason":"Failed"}}]`},
+		{name: "New Condition", original: duckv1.Conditions{ready, serviceReady}, updated: duckv1.Conditions{endpointsReady, ready, serviceReady},
+			wantPatch: `[{"op":"add","path":"/status/conditions/-","value":{"type":"EndpointsReady","status":"True",` + transitionTime + `}}]`},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			patch, err := newConditionsPatch(testCase.original, testCase.updated, testNow)
+			assert.Nil(t, err)
+			if len(testCase.wantPatch) == 0 {
+				assert.Nil(t, patch)
+			} else {
+				assert.Equal(t, testCase.wantPatch, string(patch))
+			}
+		})
+	}
+}
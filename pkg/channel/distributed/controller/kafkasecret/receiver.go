@@ -19,22 +19,17 @@ package kafkasecret
 import (
 	"context"
 	"fmt"
-	"strconv"
 
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
-	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
-	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
-	"knative.dev/eventing-kafka/pkg/channel/distributed/common/health"
-	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/receiver"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
 	"knative.dev/pkg/controller"
-	"knative.dev/pkg/logging"
 	"knative.dev/pkg/system"
 )
 
@@ -129,7 +124,7 @@ func (r *Reconciler) getReceiverService(secret *corev1.Secret) (*corev1.Service,
 	deploymentName := util.ReceiverDnsSafeName(secret.Name)
 
 	// Get The Receiver Service By Namespace / Name
-	service, err := r.serviceLister.Services(commonconstants.KnativeEventingNamespace).Get(deploymentName)
+	service, err := r.serviceLister.Services(system.Namespace()).Get(deploymentName)
 
 	// Return The Results
 	return service, err
@@ -137,45 +132,7 @@ func (r *Reconciler) getReceiverService(secret *corev1.Secret) (*corev1.Service,
 
 // Create Receiver Service Model For The Specified Secret
 func (r *Reconciler) newReceiverService(secret *corev1.Secret) *corev1.Service {
-
-	// Get The Receiver Deployment Name For The Secret - Use Same For Service
-	deploymentName := util.ReceiverDnsSafeName(secret.Name)
-
-	// Create & Return The Receiver Service Model
-	return &corev1.Service{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: corev1.SchemeGroupVersion.String(),
-			Kind:       constants.ServiceKind,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      deploymentName,
-			Namespace: commonconstants.KnativeEventingNamespace,
-			Labels: map[string]string{
-				constants.KafkaChannelReceiverLabel:  "true",                               // Allows for identification of Receivers
-				constants.K8sAppChannelSelectorLabel: constants.K8sAppChannelSelectorValue, // Prometheus ServiceMonitor
-			},
-			OwnerReferences: []metav1.OwnerReference{
-				util.NewSecretOwnerReference(secret),
-			},
-		},
-		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
-				{
-					Name:       constants.HttpPortName,
-					Port:       constants.HttpServicePortNumber,
-					TargetPort: intstr.FromInt(constants.HttpContainerPortNumber),
-				},
-				{
-					Name:       constants.MetricsPortName,
-					Port:       int32(r.environment.MetricsPort),
-					TargetPort: intstr.FromInt(r.environment.MetricsPort),
-				},
-			},
-			Selector: map[string]string{
-				constants.AppLabel: deploymentName, // Matches Deployment Label Key/Value
-			},
-		},
-	}
+	return receiver.NewService(r.newReceiver(secret), r.environment)
 }
 
 //
@@ -205,6 +162,10 @@ func (r *Reconciler) reconcileReceiverDeployment(ctx context.Context, secret *co
 					return err
 				} else {
 					r.logger.Info("Successfully Created Receiver Deployment")
+					if r.auditRecorder != nil {
+						r.auditRecorder.Record(audit.Entry{Kind: audit.KindDeployment, Action: audit.ActionCreated, Namespace: deployment.Namespace, Name: deployment.Name,
+							Details: map[string]string{"secret": secret.Name, "image": deployment.Spec.Template.Spec.Containers[0].Image}})
+					}
 					return nil
 				}
 			}
@@ -230,7 +191,7 @@ func (r *Reconciler) getReceiverDeployment(secret *corev1.Secret) (*appsv1.Deplo
 	deploymentName := util.ReceiverDnsSafeName(secret.Name)
 
 	// Get The Receiver Deployment By Namespace / Name
-	deployment, err := r.deploymentLister.Deployments(commonconstants.KnativeEventingNamespace).Get(deploymentName)
+	deployment, err := r.deploymentLister.Deployments(system.Namespace()).Get(deploymentName)
 
 	// Return The Results
 	return deployment, err
@@ -238,181 +199,19 @@ func (r *Reconciler) getReceiverDeployment(secret *corev1.Secret) (*appsv1.Deplo
 
 // Create Receiver Deployment Model For The Specified Secret
 func (r *Reconciler) newReceiverDeployment(secret *corev1.Secret) (*appsv1.Deployment, error) {
-
-	// Get The Receiver Deployment Name (One Receiver Deployment Per Kafka Auth Secret)
-	deploymentName := util.ReceiverDnsSafeName(secret.Name)
-
-	// Replicas Int Value For De-Referencing
-	replicas := int32(r.config.Receiver.Replicas)
-
-	// Create The Receiver Container Environment Variables
-	channelEnvVars, err := r.receiverDeploymentEnvVars(secret)
+	deployment, err := receiver.NewDeployment(r.newReceiver(secret), r.config, r.environment)
 	if err != nil {
-		r.logger.Error("Failed To Create Receiver Deployment Environment Variables", zap.Error(err))
+		r.logger.Error("Failed To Resolve Receiver Image", zap.Error(err))
 		return nil, err
 	}
-
-	// Create The Receiver Deployment
-	deployment := &appsv1.Deployment{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: appsv1.SchemeGroupVersion.String(),
-			Kind:       constants.DeploymentKind,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      deploymentName,
-			Namespace: commonconstants.KnativeEventingNamespace,
-			Labels: map[string]string{
-				constants.AppLabel:                  deploymentName, // Matches Service Selector Key/Value Below
-				constants.KafkaChannelReceiverLabel: "true",         // Allows for identification of Receivers
-			},
-			OwnerReferences: []metav1.OwnerReference{
-				util.NewSecretOwnerReference(secret),
-			},
-		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					constants.AppLabel: deploymentName, // Matches Template ObjectMeta Pods
-				},
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						constants.AppLabel: deploymentName, // Matched By Deployment Selector Above
-					},
-				},
-				Spec: corev1.PodSpec{
-					ServiceAccountName: r.environment.ServiceAccount,
-					Containers: []corev1.Container{
-						{
-							Name: deploymentName,
-							LivenessProbe: &corev1.Probe{
-								Handler: corev1.Handler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Port: intstr.FromInt(constants.HealthPort),
-										Path: health.LivenessPath,
-									},
-								},
-								InitialDelaySeconds: constants.ChannelLivenessDelay,
-								PeriodSeconds:       constants.ChannelLivenessPeriod,
-							},
-							ReadinessProbe: &corev1.Probe{
-								Handler: corev1.Handler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Port: intstr.FromInt(constants.HealthPort),
-										Path: health.ReadinessPath,
-									},
-								},
-								InitialDelaySeconds: constants.ChannelReadinessDelay,
-								PeriodSeconds:       constants.ChannelReadinessPeriod,
-							},
-							Image: r.environment.ReceiverImage,
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "server",
-									ContainerPort: int32(constants.HttpContainerPortNumber),
-								},
-							},
-							Env:             channelEnvVars,
-							ImagePullPolicy: corev1.PullIfNotPresent,
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    r.config.Receiver.CpuRequest,
-									corev1.ResourceMemory: r.config.Receiver.MemoryRequest,
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    r.config.Receiver.CpuLimit,
-									corev1.ResourceMemory: r.config.Receiver.MemoryLimit,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// Return Receiver Deployment
 	return deployment, nil
 }
 
-// Create The Receiver Deployment's Env Vars
-func (r *Reconciler) receiverDeploymentEnvVars(secret *corev1.Secret) ([]corev1.EnvVar, error) {
-
-	// Create The Receiver Deployment EnvVars
-	envVars := []corev1.EnvVar{
-		{
-			Name:  system.NamespaceEnvKey,
-			Value: commonconstants.KnativeEventingNamespace,
-		},
-		{
-			Name: commonenv.PodNameEnvVarKey,
-			ValueFrom: &corev1.EnvVarSource{
-				FieldRef: &corev1.ObjectFieldSelector{
-					FieldPath: "metadata.name",
-				},
-			},
-		},
-		{
-			Name:  commonenv.ContainerNameEnvVarKEy,
-			Value: constants.ReceiverContainerName,
-		},
-		{
-			Name:  commonenv.KnativeLoggingConfigMapNameEnvVarKey,
-			Value: logging.ConfigMapName(),
-		},
-		{
-			Name:  commonenv.ServiceNameEnvVarKey,
-			Value: util.ReceiverDnsSafeName(secret.Name),
-		},
-		{
-			Name:  commonenv.MetricsPortEnvVarKey,
-			Value: strconv.Itoa(r.environment.MetricsPort),
-		},
-		{
-			Name:  commonenv.MetricsDomainEnvVarKey,
-			Value: r.environment.MetricsDomain,
-		},
-		{
-			Name:  commonenv.HealthPortEnvVarKey,
-			Value: strconv.Itoa(constants.HealthPort),
-		},
+// Identify The Receiver Of The Specified Secret (One Receiver Service & Deployment Per Kafka Auth Secret)
+func (r *Reconciler) newReceiver(secret *corev1.Secret) receiver.Receiver {
+	return receiver.Receiver{
+		Name:            util.ReceiverDnsSafeName(secret.Name),
+		KafkaSecretName: secret.Name,
+		OwnerReference:  util.NewSecretOwnerReference(secret),
 	}
-
-	// Append The Kafka Brokers As Env Var
-	envVars = append(envVars, corev1.EnvVar{
-		Name: commonenv.KafkaBrokerEnvVarKey,
-		ValueFrom: &corev1.EnvVarSource{
-			SecretKeyRef: &corev1.SecretKeySelector{
-				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
-				Key:                  constants.KafkaSecretDataKeyBrokers,
-			},
-		},
-	})
-
-	// Append The Kafka Username As Env Var
-	envVars = append(envVars, corev1.EnvVar{
-		Name: commonenv.KafkaUsernameEnvVarKey,
-		ValueFrom: &corev1.EnvVarSource{
-			SecretKeyRef: &corev1.SecretKeySelector{
-				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
-				Key:                  constants.KafkaSecretDataKeyUsername,
-			},
-		},
-	})
-
-	// Append The Kafka Password As Env Var
-	envVars = append(envVars, corev1.EnvVar{
-		Name: commonenv.KafkaPasswordEnvVarKey,
-		ValueFrom: &corev1.EnvVarSource{
-			SecretKeyRef: &corev1.SecretKeySelector{
-				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
-				Key:                  constants.KafkaSecretDataKeyPassword,
-			},
-		},
-	})
-
-	// Return The Receiver Deployment EnvVars Array
-	return envVars, nil
 }
@@ -19,6 +19,8 @@ package kafkasecret
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -26,10 +28,12 @@ import (
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkasecretinjection"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
 	"knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
 	"knative.dev/pkg/reconciler"
@@ -45,6 +49,9 @@ type Reconciler struct {
 	kafkachannelLister kafkalisters.KafkaChannelLister
 	deploymentLister   appsv1listers.DeploymentLister
 	serviceLister      corev1listers.ServiceLister
+	auditRecorder      audit.Recorder
+	secretHashes       sync.Map         // The Hashes Of The Data Of The Secrets When Last Reconciled (By Secret Name)
+	now                func() time.Time // The LastTransitionTime Of The Patched KafkaChannel Conditions
 }
 
 var (
@@ -59,6 +66,9 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, secret *corev1.Secret) r
 	r.logger.Debug("<==========  START KAFKA-SECRET RECONCILIATION  ==========>")
 	logger := r.logger.With(zap.String("Secret", secret.Name))
 
+	// Audit Any Rotation Of The Secret's Data Since It Was Last Reconciled
+	r.auditSecretRotation(secret)
+
 	// Perform The Secret Reconciliation & Handle Error Response
 	logger.Info("Secret Owned By Controller - Reconciling", zap.String("Secret", secret.Name))
 	err := r.reconcile(ctx, secret)
@@ -94,6 +104,22 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, secret *corev1.Secret) re
 	return reconciler.NewEvent(corev1.EventTypeNormal, event.KafkaSecretFinalized.String(), "Kafka Secret Finalized Successfully: \"%s/%s\"", secret.Namespace, secret.Name)
 }
 
+// Record The Rotation Of The Specified Secret's Data In The Audit Log (If Enabled), Which Is Detected By A Change In The
+// Hash Of Its Data Since It Was Last Reconciled (Secrets Rotated While The Controller Was Not Running Are Not Detected)
+func (r *Reconciler) auditSecretRotation(secret *corev1.Secret) {
+	if r.auditRecorder == nil {
+		return
+	}
+	dataHash := util.GenerateHash(fmt.Sprint(secret.Data), 32)
+	previousHash, loaded := r.secretHashes.Load(secret.Name) // Secrets Are Never Reconciled Concurrently
+	r.secretHashes.Store(secret.Name, dataHash)
+	if loaded && previousHash != dataHash {
+		r.logger.Info("Kafka Secret Rotated", zap.String("Secret", secret.Name))
+		r.auditRecorder.Record(audit.Entry{Kind: audit.KindSecret, Action: audit.ActionRotated, Namespace: secret.Namespace, Name: secret.Name,
+			Details: map[string]string{"resourceVersion": secret.ResourceVersion}})
+	}
+}
+
 // Perform The Actual Secret Reconciliation
 func (r *Reconciler) reconcile(ctx context.Context, secret *corev1.Secret) error {
 
@@ -19,12 +19,17 @@ package kafkasecret
 import (
 	"context"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkasecretinjection"
 	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
@@ -56,6 +61,7 @@ func TestReconcile(t *testing.T) {
 	// Note - Knative reconciler framework expects Events (not errors) from ReconcileKind()
 	//        so WantErr is only for higher level failures in the injected Reconcile() function.
 	//
+	readyKafkaChannel := controllertesting.NewKafkaChannel(controllertesting.WithReceiverServiceReady, controllertesting.WithReceiverDeploymentReady)
 	tableTest := TableTest{
 
 		//
@@ -102,15 +108,14 @@ func TestReconcile(t *testing.T) {
 				controllertesting.NewKafkaChannelReceiverService(),
 				controllertesting.NewKafkaChannelReceiverDeployment(),
 			},
-			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
-				{
-					Object: controllertesting.NewKafkaChannel(
-						controllertesting.WithReceiverServiceReady,
-						controllertesting.WithReceiverDeploymentReady,
-					),
-				},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				newKafkaChannelStatusPatch(t, controllertesting.NewKafkaChannel(),
+					controllertesting.WithReceiverServiceReady,
+					controllertesting.WithReceiverDeploymentReady,
+				),
+				controllertesting.NewKafkaSecretFinalizerPatchActionImpl(),
 			},
-			WantPatches: []clientgotesting.PatchActionImpl{controllertesting.NewKafkaSecretFinalizerPatchActionImpl()},
+			SkipNamespaceValidation: true,
 			WantEvents: []string{
 				controllertesting.NewKafkaSecretFinalizerUpdateEvent(),
 				controllertesting.NewKafkaSecretSuccessfulReconciliationEvent(),
@@ -126,19 +131,15 @@ func TestReconcile(t *testing.T) {
 			Key:  controllertesting.KafkaSecretKey,
 			Objects: []runtime.Object{
 				controllertesting.NewKafkaSecret(controllertesting.WithKafkaSecretDeleted),
-				controllertesting.NewKafkaChannel(
-					controllertesting.WithReceiverServiceReady,
-					controllertesting.WithReceiverDeploymentReady,
-				),
+				readyKafkaChannel,
 			},
-			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
-				{
-					Object: controllertesting.NewKafkaChannel(
-						controllertesting.WithReceiverServiceFinalized,
-						controllertesting.WithReceiverDeploymentFinalized,
-					),
-				},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				newKafkaChannelStatusPatch(t, readyKafkaChannel,
+					controllertesting.WithReceiverServiceFinalized,
+					controllertesting.WithReceiverDeploymentFinalized,
+				),
 			},
+			SkipNamespaceValidation: true,
 			WantEvents: []string{
 				controllertesting.NewKafkaSecretSuccessfulFinalizedEvent(),
 			},
@@ -181,14 +182,10 @@ func TestReconcile(t *testing.T) {
 			WithReactors: []clientgotesting.ReactionFunc{InduceFailure("create", "services")},
 			WantErr:      true,
 			WantCreates:  []runtime.Object{controllertesting.NewKafkaChannelReceiverService()},
-			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
-				{
-					Object: controllertesting.NewKafkaChannel(
-						controllertesting.WithReceiverServiceFailed,
-						controllertesting.WithReceiverDeploymentReady,
-					),
-				},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				newKafkaChannelStatusPatch(t, readyKafkaChannel, controllertesting.WithReceiverServiceFailed),
 			},
+			SkipNamespaceValidation: true,
 			WantEvents: []string{
 				Eventf(corev1.EventTypeWarning, event.ReceiverServiceReconciliationFailed.String(), "Failed To Reconcile Receiver Service: inducing failure for create services"),
 				controllertesting.NewKafkaSecretFailedReconciliationEvent(),
@@ -229,14 +226,10 @@ func TestReconcile(t *testing.T) {
 			WithReactors: []clientgotesting.ReactionFunc{InduceFailure("create", "deployments")},
 			WantErr:      true,
 			WantCreates:  []runtime.Object{controllertesting.NewKafkaChannelReceiverDeployment()},
-			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
-				{
-					Object: controllertesting.NewKafkaChannel(
-						controllertesting.WithReceiverServiceReady,
-						controllertesting.WithReceiverDeploymentFailed,
-					),
-				},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				newKafkaChannelStatusPatch(t, readyKafkaChannel, controllertesting.WithReceiverDeploymentFailed),
 			},
+			SkipNamespaceValidation: true,
 			WantEvents: []string{
 				Eventf(corev1.EventTypeWarning, event.ReceiverDeploymentReconciliationFailed.String(), "Failed To Reconcile Receiver Deployment: inducing failure for create deployments"),
 				controllertesting.NewKafkaSecretFailedReconciliationEvent(),
@@ -256,7 +249,50 @@ func TestReconcile(t *testing.T) {
 			kafkachannelLister: listers.GetKafkaChannelLister(),
 			deploymentLister:   listers.GetDeploymentLister(),
 			serviceLister:      listers.GetServiceLister(),
+			now:                func() time.Time { return testNow },
 		}
 		return kafkasecretinjection.NewReconciler(ctx, r.logger.Sugar(), r.kubeClientset.CoreV1(), listers.GetSecretLister(), controller.GetEventRecorder(ctx), r)
 	}, logger.Desugar()))
 }
+
+// Utility Function For Creating The Expected Status Patch Of The Specified KafkaChannel Updated With The Specified Options
+func newKafkaChannelStatusPatch(t *testing.T, channel *kafkav1beta1.KafkaChannel, options ...controllertesting.KafkaChannelOption) clientgotesting.PatchActionImpl {
+	updatedChannel := channel.DeepCopy()
+	for _, option := range options {
+		option(updatedChannel)
+	}
+	patch, err := newConditionsPatch(channel.Status.Conditions, updatedChannel.Status.Conditions, testNow)
+	assert.Nil(t, err)
+	return clientgotesting.PatchActionImpl{
+		ActionImpl: clientgotesting.ActionImpl{
+			Namespace:   channel.Namespace,
+			Verb:        "patch",
+			Resource:    kafkav1beta1.SchemeGroupVersion.WithResource("kafkachannels"),
+			Subresource: "status",
+		},
+		Name:      channel.Name,
+		PatchType: types.JSONPatchType,
+		Patch:     patch,
+	}
+}
+
+// Test The Kafka Secret Rotation Auditing
+func TestAuditSecretRotation(t *testing.T) {
+	mockAuditRecorder := &controllertesting.MockAuditRecorder{}
+	r := &Reconciler{logger: logtesting.TestLogger(t).Desugar(), auditRecorder: mockAuditRecorder}
+	secret := controllertesting.NewKafkaSecret()
+
+	// The First Observation Only Remembers The Secret Data
+	r.auditSecretRotation(secret)
+	r.auditSecretRotation(secret)
+	assert.Empty(t, mockAuditRecorder.Entries)
+
+	// Changed Secret Data Is Recorded As A Rotation
+	rotatedSecret := secret.DeepCopy()
+	rotatedSecret.Data = map[string][]byte{"password": []byte("rotated")}
+	r.auditSecretRotation(rotatedSecret)
+	assert.Len(t, mockAuditRecorder.Entries, 1)
+	assert.Equal(t, audit.KindSecret, mockAuditRecorder.Entries[0].Kind)
+	assert.Equal(t, audit.ActionRotated, mockAuditRecorder.Entries[0].Action)
+	assert.Equal(t, secret.Name, mockAuditRecorder.Entries[0].Name)
+}
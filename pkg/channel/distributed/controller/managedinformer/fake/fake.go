@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"k8s.io/client-go/informers"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/managedinformer"
+	"knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+)
+
+var GetChannelDeployments = managedinformer.GetChannelDeployments
+var GetChannelServices = managedinformer.GetChannelServices
+var GetReceiverDeployments = managedinformer.GetReceiverDeployments
+var GetReceiverServices = managedinformer.GetReceiverServices
+
+func init() {
+	injection.Fake.RegisterInformer(withChannelDeploymentInformer)
+	injection.Fake.RegisterInformer(withChannelServiceInformer)
+	injection.Fake.RegisterInformer(withReceiverDeploymentInformer)
+	injection.Fake.RegisterInformer(withReceiverServiceInformer)
+}
+
+func withChannelDeploymentInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := informers.NewSharedInformerFactory(fake.Get(ctx), 0).Apps().V1().Deployments() // Using The Fake Kube Client
+	return context.WithValue(ctx, managedinformer.ChannelDeploymentKey{}, inf), inf.Informer()
+}
+
+func withChannelServiceInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := informers.NewSharedInformerFactory(fake.Get(ctx), 0).Core().V1().Services() // Using The Fake Kube Client
+	return context.WithValue(ctx, managedinformer.ChannelServiceKey{}, inf), inf.Informer()
+}
+
+func withReceiverDeploymentInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := informers.NewSharedInformerFactory(fake.Get(ctx), 0).Apps().V1().Deployments() // Using The Fake Kube Client
+	return context.WithValue(ctx, managedinformer.ReceiverDeploymentKey{}, inf), inf.Informer()
+}
+
+func withReceiverServiceInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := informers.NewSharedInformerFactory(fake.Get(ctx), 0).Core().V1().Services() // Using The Fake Kube Client
+	return context.WithValue(ctx, managedinformer.ReceiverServiceKey{}, inf), inf.Informer()
+}
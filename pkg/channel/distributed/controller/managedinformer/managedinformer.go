@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedinformer
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/informers"
+	informersappsv1 "k8s.io/client-go/informers/apps/v1"
+	informerscorev1 "k8s.io/client-go/informers/core/v1"
+	listersappsv1 "k8s.io/client-go/listers/apps/v1"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+//
+// Custom Deployment & Service Informers - Label Restricted
+//
+// Note:  The generic Knative Deployment & Service informers cache (and deliver the events of) every Deployment and
+//        Service in the cluster, which on busy clusters with thousands of unrelated workloads costs the controller
+//        a great deal of memory & CPU just to discard them in the FilterFuncs of the EventHandlers.  The controller
+//        labels all of the Deployments & Services it manages though, so the following informers restrict the
+//        watches with label selectors such that the API server only sends the managed resources...
+//
+//          - Channel:  The resources labelled with their owning KafkaChannel's name (i.e. the KafkaChannel Services
+//                      in all namespaces, and the Dispatchers & Isolated Receivers in the system namespace)
+//                      as watched by the KafkaChannel controller.
+//
+//          - Receiver: The receivers in the system namespace (i.e. the shared Receivers of the Kafka
+//                      Secrets, as well as the Isolated Receivers) as watched by the KafkaSecret controller.
+//
+//        The Deployment & Service informers of each kind share a SharedInformerFactory with the same options.
+//
+
+// Add The InformerInjector Functions With The Knative Injection Framework
+func init() {
+	injection.Default.RegisterInformerFactory(withChannelInformerFactory)
+	injection.Default.RegisterInformerFactory(withReceiverInformerFactory)
+	injection.Default.RegisterInformer(withChannelDeploymentInformer)
+	injection.Default.RegisterInformer(withChannelServiceInformer)
+	injection.Default.RegisterInformer(withReceiverDeploymentInformer)
+	injection.Default.RegisterInformer(withReceiverServiceInformer)
+}
+
+// Keys Used To Associate The Factories & Informers Inside The Context
+type ChannelFactoryKey struct{}
+type ReceiverFactoryKey struct{}
+type ChannelDeploymentKey struct{}
+type ChannelServiceKey struct{}
+type ReceiverDeploymentKey struct{}
+type ReceiverServiceKey struct{}
+
+// The Label Selector Of The Resources Owned By A KafkaChannel (Any KafkaChannel Name)
+func ChannelSelector() labels.Selector {
+	requirement, _ := labels.NewRequirement(constants.KafkaChannelNameLabel, selection.Exists, nil)
+	return labels.NewSelector().Add(*requirement)
+}
+
+// The Label Selector Of The Receivers
+func ReceiverSelector() labels.Selector {
+	return labels.SelectorFromSet(map[string]string{constants.KafkaChannelReceiverLabel: "true"})
+}
+
+// Custom InformerFactory Injector For The Resources Owned By A KafkaChannel (In All Namespaces)
+func withChannelInformerFactory(ctx context.Context) context.Context {
+	factory := newFactory(ctx, metav1.NamespaceAll, ChannelSelector())
+	return context.WithValue(ctx, ChannelFactoryKey{}, factory)
+}
+
+// Custom InformerFactory Injector For The Receivers (In The System Namespace)
+func withReceiverInformerFactory(ctx context.Context) context.Context {
+	factory := newFactory(ctx, system.Namespace(), ReceiverSelector())
+	return context.WithValue(ctx, ReceiverFactoryKey{}, factory)
+}
+
+// Custom InformerInjector For The Deployments Owned By A KafkaChannel
+func withChannelDeploymentInformer(ctx context.Context) (context.Context, controller.Informer) {
+	deploymentInformer := DeploymentInformer{factory: getFactory(ctx, ChannelFactoryKey{})}
+	return context.WithValue(ctx, ChannelDeploymentKey{}, deploymentInformer), deploymentInformer.Informer()
+}
+
+// Custom InformerInjector For The Services Owned By A KafkaChannel
+func withChannelServiceInformer(ctx context.Context) (context.Context, controller.Informer) {
+	serviceInformer := ServiceInformer{factory: getFactory(ctx, ChannelFactoryKey{})}
+	return context.WithValue(ctx, ChannelServiceKey{}, serviceInformer), serviceInformer.Informer()
+}
+
+// Custom InformerInjector For The Receiver Deployments
+func withReceiverDeploymentInformer(ctx context.Context) (context.Context, controller.Informer) {
+	deploymentInformer := DeploymentInformer{factory: getFactory(ctx, ReceiverFactoryKey{})}
+	return context.WithValue(ctx, ReceiverDeploymentKey{}, deploymentInformer), deploymentInformer.Informer()
+}
+
+// Custom InformerInjector For The Receiver Services
+func withReceiverServiceInformer(ctx context.Context) (context.Context, controller.Informer) {
+	serviceInformer := ServiceInformer{factory: getFactory(ctx, ReceiverFactoryKey{})}
+	return context.WithValue(ctx, ReceiverServiceKey{}, serviceInformer), serviceInformer.Informer()
+}
+
+// Create A SharedInformerFactory Of The Resources Matching The Specified Label Selector In The Specified Namespace (Or All Namespaces)
+func newFactory(ctx context.Context, namespace string, selector labels.Selector) informers.SharedInformerFactory {
+
+	// Define The SharedInformerOptions To Restrict To Specified Namespace & Labels
+	sharedInformerOptions := []informers.SharedInformerOption{
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.LabelSelector = selector.String()
+		}),
+	}
+
+	// Create A SharedInformerFactory With The Namespaced / Labelled Options
+	return informers.NewSharedInformerFactoryWithOptions(client.Get(ctx), controller.DefaultResyncPeriod, sharedInformerOptions...)
+}
+
+// Extract The SharedInformerFactory With The Specified Key From The Specified Context
+func getFactory(ctx context.Context, key interface{}) informers.SharedInformerFactory {
+	untyped := ctx.Value(key)
+	if untyped == nil {
+		logging.FromContext(ctx).Panicf("Unable to fetch eventing-kafka/pkg/controller/managedinformer/%T from context.", key)
+	}
+	return untyped.(informers.SharedInformerFactory)
+}
+
+// Extract The Typed DeploymentInformer Of The Resources Owned By A KafkaChannel From The Specified Context
+func GetChannelDeployments(ctx context.Context) informersappsv1.DeploymentInformer {
+	untyped := ctx.Value(ChannelDeploymentKey{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch eventing-kafka/pkg/controller/managedinformer/ChannelDeployments from context.")
+	}
+	return untyped.(informersappsv1.DeploymentInformer)
+}
+
+// Extract The Typed ServiceInformer Of The Resources Owned By A KafkaChannel From The Specified Context
+func GetChannelServices(ctx context.Context) informerscorev1.ServiceInformer {
+	untyped := ctx.Value(ChannelServiceKey{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch eventing-kafka/pkg/controller/managedinformer/ChannelServices from context.")
+	}
+	return untyped.(informerscorev1.ServiceInformer)
+}
+
+// Extract The Typed DeploymentInformer Of The Receivers From The Specified Context
+func GetReceiverDeployments(ctx context.Context) informersappsv1.DeploymentInformer {
+	untyped := ctx.Value(ReceiverDeploymentKey{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch eventing-kafka/pkg/controller/managedinformer/ReceiverDeployments from context.")
+	}
+	return untyped.(informersappsv1.DeploymentInformer)
+}
+
+// Extract The Typed ServiceInformer Of The Receivers From The Specified Context
+func GetReceiverServices(ctx context.Context) informerscorev1.ServiceInformer {
+	untyped := ctx.Value(ReceiverServiceKey{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch eventing-kafka/pkg/controller/managedinformer/ReceiverServices from context.")
+	}
+	return untyped.(informerscorev1.ServiceInformer)
+}
+
+// Verify The Custom Informers Implement The K8S DeploymentInformer & ServiceInformer Interfaces
+var _ informersappsv1.DeploymentInformer = DeploymentInformer{}
+var _ informerscorev1.ServiceInformer = ServiceInformer{}
+
+// Custom Label Restricted DeploymentInformer Implementation
+type DeploymentInformer struct {
+	factory informers.SharedInformerFactory
+}
+
+// Implement The K8S DeploymentInformer's Informer() Interface Function
+func (i DeploymentInformer) Informer() cache.SharedIndexInformer {
+	return i.factory.Apps().V1().Deployments().Informer()
+}
+
+// Implement The K8S DeploymentInformer's Lister() Interface Function
+func (i DeploymentInformer) Lister() listersappsv1.DeploymentLister {
+	return listersappsv1.NewDeploymentLister(i.Informer().GetIndexer())
+}
+
+// Custom Label Restricted ServiceInformer Implementation
+type ServiceInformer struct {
+	factory informers.SharedInformerFactory
+}
+
+// Implement The K8S ServiceInformer's Informer() Interface Function
+func (i ServiceInformer) Informer() cache.SharedIndexInformer {
+	return i.factory.Core().V1().Services().Informer()
+}
+
+// Implement The K8S ServiceInformer's Lister() Interface Function
+func (i ServiceInformer) Lister() listerscorev1.ServiceLister {
+	return listerscorev1.NewServiceLister(i.Informer().GetIndexer())
+}
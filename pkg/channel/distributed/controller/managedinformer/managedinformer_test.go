@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedinformer
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	injectionclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
+)
+
+// Test The Get*() Functionality Of The Managed Deployment & Service Informers
+func TestGet(t *testing.T) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace))
+
+	// Create A Context With Test Logger & K8S Client
+	ctx := logging.WithLogger(context.TODO(), logtesting.TestLogger(t))
+	ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset())
+
+	// Verify The Factories & Informers Were Added To Knative Injection
+	assert.Len(t, injection.Default.GetInformerFactories(), 2)
+	assert.Len(t, injection.Default.GetInformers(), 4)
+
+	// Add The Factories & Informers To The Test Context
+	ctx = withChannelInformerFactory(ctx)
+	ctx = withReceiverInformerFactory(ctx)
+	ctx, channelDeploymentInformer := withChannelDeploymentInformer(ctx)
+	assert.NotNil(t, channelDeploymentInformer)
+	ctx, channelServiceInformer := withChannelServiceInformer(ctx)
+	assert.NotNil(t, channelServiceInformer)
+	ctx, receiverDeploymentInformer := withReceiverDeploymentInformer(ctx)
+	assert.NotNil(t, receiverDeploymentInformer)
+	ctx, receiverServiceInformer := withReceiverServiceInformer(ctx)
+	assert.NotNil(t, receiverServiceInformer)
+
+	// Perform The Test & Verify Results
+	assert.NotNil(t, GetChannelDeployments(ctx).Lister())
+	assert.NotNil(t, GetChannelServices(ctx).Lister())
+	assert.NotNil(t, GetReceiverDeployments(ctx).Lister())
+	assert.NotNil(t, GetReceiverServices(ctx).Lister())
+
+	// The Deployment & Service Informers Of Each Kind Share A Factory
+	assert.Same(t, channelDeploymentInformer, GetChannelDeployments(ctx).Informer())
+	assert.Same(t, receiverServiceInformer, GetReceiverServices(ctx).Informer())
+	assert.NotSame(t, channelDeploymentInformer, receiverDeploymentInformer)
+}
+
+// Test The Label Selectors Of The Managed Deployment & Service Informers
+func TestSelectors(t *testing.T) {
+	assert.Equal(t, constants.KafkaChannelNameLabel, ChannelSelector().String())
+	assert.Equal(t, constants.KafkaChannelReceiverLabel+"=true", ReceiverSelector().String())
+
+	channelLabels := labels.Set{constants.KafkaChannelNameLabel: "kc", constants.KafkaChannelNamespaceLabel: "ns"}
+	assert.True(t, ChannelSelector().Matches(channelLabels))
+	assert.False(t, ReceiverSelector().Matches(channelLabels))
+
+	receiverLabels := labels.Set{constants.AppLabel: "kafka-receiver", constants.KafkaChannelReceiverLabel: "true"}
+	assert.False(t, ChannelSelector().Matches(receiverLabels))
+	assert.True(t, ReceiverSelector().Matches(receiverLabels))
+
+	assert.False(t, ChannelSelector().Matches(labels.Set{constants.AppLabel: "unrelated"}))
+	assert.False(t, ReceiverSelector().Matches(labels.Set{constants.AppLabel: "unrelated"}))
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/gctuning"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/health"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+//
+// Receiver Service & Deployment Models
+//
+// Receivers are normally shared by all the KafkaChannels of a Kafka Secret (owned by the Secret), but may instead
+// be dedicated to a single KafkaChannel (owned by the KafkaChannel) when its receiver isolation is "channel".  Both
+// are created from the same models, differing only in their name, owner and identifying labels.  When the receiver
+// isolation is "combined" only the receiver container is created, to be run in the pod of the KafkaChannel's
+// dispatcher.
+//
+
+// Receiver Identifies The Receiver Service & Deployment To Be Created
+type Receiver struct {
+	Name            string                // The Name Of The Receiver Service & Deployment
+	KafkaSecretName string                // The Kafka Secret Providing The Brokers & Credentials
+	OwnerReference  metav1.OwnerReference // The Kafka Secret Or KafkaChannel Owning The Receiver
+	Labels          map[string]string     // Additional Labels Identifying The Receiver's Owner (Optional)
+}
+
+// Create The Receiver Service Model
+func NewService(receiver Receiver, environment *env.Environment) *corev1.Service {
+
+	// Create The Receiver Service Labels
+	labels := map[string]string{
+		constants.KafkaChannelReceiverLabel:  "true",                               // Allows for identification of Receivers
+		constants.K8sAppChannelSelectorLabel: constants.K8sAppChannelSelectorValue, // Prometheus ServiceMonitor
+	}
+	for key, value := range receiver.Labels {
+		labels[key] = value
+	}
+
+	// Create & Return The Receiver Service Model
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       constants.ServiceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      receiver.Name,
+			Namespace: system.Namespace(),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				receiver.OwnerReference,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       constants.HttpPortName,
+					Port:       constants.HttpServicePortNumber,
+					TargetPort: intstr.FromInt(constants.HttpContainerPortNumber),
+				},
+				{
+					Name:       constants.MetricsPortName,
+					Port:       int32(environment.MetricsPort),
+					TargetPort: intstr.FromInt(environment.MetricsPort),
+				},
+			},
+			Selector: map[string]string{
+				constants.AppLabel: receiver.Name, // Matches Deployment Label Key/Value
+			},
+		},
+	}
+}
+
+// Create The Receiver Deployment Model
+func NewDeployment(receiver Receiver, configuration *config.EventingKafkaConfig, environment *env.Environment) (*appsv1.Deployment, error) {
+
+	// Replicas Int Value For De-Referencing
+	replicas := int32(configuration.Receiver.Replicas)
+
+	// Resolve The Receiver Image & Node Architecture
+	image, architecture, err := util.ResolveImage(configuration.Receiver.Images, environment.ReceiverImage, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Create The Receiver Deployment Labels
+	labels := map[string]string{
+		constants.AppLabel:                  receiver.Name, // Matches Service Selector Key/Value Below
+		constants.KafkaChannelReceiverLabel: "true",        // Allows for identification of Receivers
+	}
+	for key, value := range receiver.Labels {
+		labels[key] = value
+	}
+
+	// Create The Receiver Container Env Vars
+	envVars := deploymentEnvVars(receiver, configuration, environment, constants.HealthPort, environment.MetricsPort)
+
+	// Create The Receiver Deployment
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       constants.DeploymentKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      receiver.Name,
+			Namespace: system.Namespace(),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				receiver.OwnerReference,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					constants.AppLabel: receiver.Name, // Matches Template ObjectMeta Pods
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						constants.AppLabel: receiver.Name, // Matched By Deployment Selector Above
+					},
+					Annotations: util.SeccompPodAnnotations(configuration.Receiver.EKKubernetesConfig),
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName:            environment.ServiceAccount,
+					SecurityContext:               util.PodSecurityContext(configuration.Receiver.EKKubernetesConfig),
+					NodeSelector:                  util.ArchitectureNodeSelector(architecture),
+					Volumes:                       append(append(util.WorkloadIdentityVolumes(configuration.Kafka.WorkloadIdentity), util.EncryptionKeysVolumes(configuration.Kafka.Encryption)...), util.MiddlewareVolumes(configuration.Middleware)...),
+					TerminationGracePeriodSeconds: TerminationGracePeriodSeconds(configuration.Receiver.Shutdown),
+					Containers: []corev1.Container{
+						newContainer(receiver.Name, image, constants.HealthPort, envVars, configuration),
+					},
+				},
+			},
+		},
+	}
+
+	// Return Receiver Deployment
+	return deployment, nil
+}
+
+// Create The Receiver Container Model Listening For Health Requests On The Specified Port
+func newContainer(name string, image string, healthPort int, envVars []corev1.EnvVar, configuration *config.EventingKafkaConfig) corev1.Container {
+	return corev1.Container{
+		Name: name,
+		LivenessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Port: intstr.FromInt(healthPort),
+					Path: health.LivenessPath,
+				},
+			},
+			InitialDelaySeconds: constants.ChannelLivenessDelay,
+			PeriodSeconds:       constants.ChannelLivenessPeriod,
+		},
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Port: intstr.FromInt(healthPort),
+					Path: health.ReadinessPath,
+				},
+			},
+			InitialDelaySeconds: constants.ChannelReadinessDelay,
+			PeriodSeconds:       constants.ChannelReadinessPeriod,
+		},
+		Image: image,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "server",
+				ContainerPort: int32(constants.HttpContainerPortNumber),
+			},
+		},
+		Env:             envVars,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		SecurityContext: util.ContainerSecurityContext(configuration.Receiver.EKKubernetesConfig),
+		VolumeMounts:    append(append(util.WorkloadIdentityVolumeMounts(configuration.Kafka.WorkloadIdentity), util.EncryptionKeysVolumeMounts(configuration.Kafka.Encryption)...), util.MiddlewareVolumeMounts(configuration.Middleware)...),
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    configuration.Receiver.CpuRequest,
+				corev1.ResourceMemory: configuration.Receiver.MemoryRequest,
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    configuration.Receiver.CpuLimit,
+				corev1.ResourceMemory: configuration.Receiver.MemoryLimit,
+			},
+		},
+	}
+}
+
+// Create The Model Of The Receiver Container Run In The Pod Of A KafkaChannel's Dispatcher (Combined Receiver
+// Isolation), Whose Image Is Resolved For The Dispatcher's Node Architecture & Which Listens For Health & Metrics
+// Requests On Alternate Ports (So As Not To Conflict With The Dispatcher)
+func NewCombinedContainer(receiver Receiver, architecture string, configuration *config.EventingKafkaConfig, environment *env.Environment) (corev1.Container, error) {
+	image, _, err := util.ResolveImage(configuration.Receiver.Images, environment.ReceiverImage, "", architecture)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	envVars := deploymentEnvVars(receiver, configuration, environment, constants.CombinedReceiverHealthPort, constants.CombinedReceiverMetricsPort)
+	return newContainer(constants.ReceiverContainerName, image, constants.CombinedReceiverHealthPort, envVars, configuration), nil
+}
+
+// The Seconds Allowed For Closing The Producer Once The In-Flight Requests Have Completed
+const producerCloseSeconds = 5
+
+// Get The Termination Grace Period Of The Receiver Pods (Or Dispatcher Pods Combined With A Receiver), Covering Their
+// Graceful Shutdown (The Drain Period, The Timeout Of The In-Flight Requests & The Closing Of The Producer) So That
+// They Aren't Killed Part Way Through
+func TerminationGracePeriodSeconds(shutdownConfig config.EKShutdownConfig) *int64 {
+	drainMillis := shutdownConfig.DrainMillis
+	if drainMillis <= 0 {
+		drainMillis = commonconstants.DefaultReceiverDrainMillis
+	}
+	timeoutMillis := shutdownConfig.TimeoutMillis
+	if timeoutMillis <= 0 {
+		timeoutMillis = commonconstants.DefaultReceiverTimeoutMillis
+	}
+	seconds := (drainMillis+timeoutMillis+999)/1000 + producerCloseSeconds
+	return &seconds
+}
+
+// Create The Receiver Container's Env Vars (With The Specified Health & Metrics Ports)
+func deploymentEnvVars(receiver Receiver, configuration *config.EventingKafkaConfig, environment *env.Environment, healthPort int, metricsPort int) []corev1.EnvVar {
+
+	// Create The Receiver Deployment EnvVars
+	envVars := []corev1.EnvVar{
+		{
+			Name:  system.NamespaceEnvKey,
+			Value: system.Namespace(),
+		},
+		{
+			Name: commonenv.PodNameEnvVarKey,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		},
+		{
+			Name:  commonenv.ContainerNameEnvVarKEy,
+			Value: constants.ReceiverContainerName,
+		},
+		{
+			Name:  commonenv.KnativeLoggingConfigMapNameEnvVarKey,
+			Value: logging.ConfigMapName(),
+		},
+		{
+			Name:  commonenv.ServiceNameEnvVarKey,
+			Value: receiver.Name,
+		},
+		{
+			Name:  commonenv.MetricsPortEnvVarKey,
+			Value: strconv.Itoa(metricsPort),
+		},
+		{
+			Name:  commonenv.MetricsDomainEnvVarKey,
+			Value: environment.MetricsDomain,
+		},
+		{
+			Name:  commonenv.HealthPortEnvVarKey,
+			Value: strconv.Itoa(healthPort),
+		},
+	}
+
+	// Append The Kafka Brokers / Username / Password (Or KafkaAuthSpec Brokers) As Env Vars
+	envVars = append(envVars, util.KafkaSecretEnvVars(receiver.KafkaSecretName, configuration.Kafka.AuthSpec)...)
+
+	// Append The Schema Registry Credentials As Env Vars (If Its Authentication Is Configured)
+	if secretName := configuration.Receiver.Validation.SchemaRegistryAuth.SecretName; len(secretName) > 0 {
+		envVars = append(envVars, util.SchemaRegistrySecretEnvVars(secretName)...)
+	}
+
+	// Append The Go Runtime's Garbage Collection Tuning (GOGC / GOMEMLIMIT) As Env Vars
+	envVars = append(envVars, gctuning.EnvVars(configuration.Receiver.Runtime)...)
+
+	// Return The Receiver Deployment EnvVars Array
+	return envVars
+}
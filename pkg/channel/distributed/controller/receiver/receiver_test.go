@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/gctuning"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
+	"knative.dev/pkg/system"
+)
+
+// Test The TerminationGracePeriodSeconds() Functionality
+func TestTerminationGracePeriodSeconds(t *testing.T) {
+	assert.Equal(t, int64(30), *TerminationGracePeriodSeconds(config.EKShutdownConfig{}))
+	assert.Equal(t, int64(11), *TerminationGracePeriodSeconds(config.EKShutdownConfig{DrainMillis: 1000, TimeoutMillis: 5000}))
+	assert.Equal(t, int64(67), *TerminationGracePeriodSeconds(config.EKShutdownConfig{DrainMillis: 1500, TimeoutMillis: 60000}))
+	assert.Equal(t, int64(70), *TerminationGracePeriodSeconds(config.EKShutdownConfig{TimeoutMillis: 60000}))
+}
+
+// Test The Schema Registry Credentials Of The deploymentEnvVars() Functionality
+func TestDeploymentEnvVarsSchemaRegistry(t *testing.T) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace))
+	receiver := Receiver{Name: "TestReceiver", KafkaSecretName: "TestKafkaSecret"}
+	environment := &env.Environment{MetricsDomain: "TestMetricsDomain"}
+	configuration := &config.EventingKafkaConfig{}
+
+	// Without Schema Registry Authentication The Credentials Are Not Referenced
+	for _, envVar := range deploymentEnvVars(receiver, configuration, environment, 8082, 8081) {
+		assert.NotEqual(t, commonenv.SchemaRegistryUsernameEnvVarKey, envVar.Name)
+	}
+
+	// With Schema Registry Authentication The Credentials Reference Its Secret
+	configuration.Receiver.Validation.SchemaRegistryAuth = config.EKSchemaRegistryAuthConfig{Mode: "basic", SecretName: "TestRegistrySecret"}
+	secretNames := map[string]string{}
+	for _, envVar := range deploymentEnvVars(receiver, configuration, environment, 8082, 8081) {
+		if envVar.ValueFrom != nil && envVar.ValueFrom.SecretKeyRef != nil {
+			secretNames[envVar.Name] = envVar.ValueFrom.SecretKeyRef.Name
+		}
+	}
+	assert.Equal(t, "TestRegistrySecret", secretNames[commonenv.SchemaRegistryUsernameEnvVarKey])
+	assert.Equal(t, "TestRegistrySecret", secretNames[commonenv.SchemaRegistryClientSecretEnvVarKey])
+	assert.Equal(t, "TestKafkaSecret", secretNames[commonenv.KafkaUsernameEnvVarKey])
+}
+
+// Test The Go Runtime Tuning Of The deploymentEnvVars() Functionality
+func TestDeploymentEnvVarsRuntime(t *testing.T) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace))
+	receiver := Receiver{Name: "TestReceiver", KafkaSecretName: "TestKafkaSecret"}
+	environment := &env.Environment{MetricsDomain: "TestMetricsDomain"}
+	configuration := &config.EventingKafkaConfig{}
+	configuration.Receiver.Runtime = config.EKRuntimeConfig{GOGC: "200", GOMemLimit: resource.NewQuantity(100*1024*1024, resource.BinarySI)}
+	values := map[string]string{}
+	for _, envVar := range deploymentEnvVars(receiver, configuration, environment, 8082, 8081) {
+		values[envVar.Name] = envVar.Value
+	}
+	assert.Equal(t, "200", values[gctuning.GOGCEnvVarKey])
+	assert.Equal(t, "104857600", values[gctuning.GOMemLimitEnvVarKey])
+}
+
+// Test The Receiver Service & Deployment Are Created In (And Configured With) A Custom System Namespace
+func TestCustomSystemNamespace(t *testing.T) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, "custom-eventing"))
+	defer func() { _ = os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace) }()
+
+	receiver := Receiver{Name: "TestReceiver", KafkaSecretName: "TestKafkaSecret"}
+	environment := &env.Environment{MetricsDomain: "TestMetricsDomain"}
+
+	service := NewService(receiver, environment)
+	assert.Equal(t, "custom-eventing", service.Namespace)
+
+	deployment, err := NewDeployment(receiver, &config.EventingKafkaConfig{}, environment)
+	assert.Nil(t, err)
+	assert.Equal(t, "custom-eventing", deployment.Namespace)
+	namespaceEnvVars := 0
+	for _, envVar := range deployment.Spec.Template.Spec.Containers[0].Env {
+		if envVar.Name == system.NamespaceEnvKey {
+			assert.Equal(t, "custom-eventing", envVar.Value)
+			namespaceEnvVars++
+		}
+	}
+	assert.Equal(t, 1, namespaceEnvVars)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"k8s.io/client-go/informers"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/settingsinformer"
+	"knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+)
+
+var GetNamespaceSettings = settingsinformer.GetNamespaceSettings
+var GetChannelClasses = settingsinformer.GetChannelClasses
+
+func init() {
+	injection.Fake.RegisterInformer(withNamespaceSettingsInformer)
+	injection.Fake.RegisterInformer(withChannelClassesInformer)
+}
+
+func withNamespaceSettingsInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := informers.NewSharedInformerFactory(fake.Get(ctx), 0).Core().V1().ConfigMaps() // Using The Fake Kube Client
+	return context.WithValue(ctx, settingsinformer.NamespaceSettingsKey{}, inf), inf.Informer()
+}
+
+func withChannelClassesInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := informers.NewSharedInformerFactory(fake.Get(ctx), 0).Core().V1().ConfigMaps() // Using The Fake Kube Client
+	return context.WithValue(ctx, settingsinformer.ChannelClassesKey{}, inf), inf.Informer()
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settingsinformer
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	informerscorev1 "k8s.io/client-go/informers/core/v1"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	messagingconfig "knative.dev/eventing-kafka/pkg/apis/messaging/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+//
+// Custom Settings ConfigMapInformers - Name Restricted
+//
+// Note:  The KafkaChannel reconciler layers the optional namespace settings ConfigMap (in the KafkaChannel's
+//        namespace) and the channel classes ConfigMap (in the "System" namespace) over the cluster-wide settings
+//        on every reconciliation.  Rather than getting them from the API server each time, they are watched by
+//        the following informers, each based on a separate factory whose field selector restricts it to the
+//        ConfigMaps of a single name (so that the other ConfigMaps of the cluster are not cached).
+//
+
+// Add The InformerInjector Functions With The Knative Injection Framework
+func init() {
+	injection.Default.RegisterInformer(withNamespaceSettingsInformer)
+	injection.Default.RegisterInformer(withChannelClassesInformer)
+}
+
+// Keys Used To Associate The Informers Inside The Context
+type NamespaceSettingsKey struct{}
+type ChannelClassesKey struct{}
+
+// Custom InformerInjector For The Namespace Settings ConfigMaps (In All Namespaces)
+func withNamespaceSettingsInformer(ctx context.Context) (context.Context, controller.Informer) {
+	settingsInformer := newSettingsInformer(ctx, metav1.NamespaceAll, config.NamespaceSettingsConfigMapName)
+	return context.WithValue(ctx, NamespaceSettingsKey{}, settingsInformer), settingsInformer.Informer()
+}
+
+// Custom InformerInjector For The Channel Classes ConfigMap (In The "System" Namespace)
+func withChannelClassesInformer(ctx context.Context) (context.Context, controller.Informer) {
+	settingsInformer := newSettingsInformer(ctx, system.Namespace(), messagingconfig.ChannelClassesConfigName)
+	return context.WithValue(ctx, ChannelClassesKey{}, settingsInformer), settingsInformer.Informer()
+}
+
+// Create A SettingsInformer Of The ConfigMaps With The Specified Name In The Specified Namespace (Or All Namespaces)
+func newSettingsInformer(ctx context.Context, namespace string, name string) SettingsInformer {
+
+	// Define The SharedInformerOptions To Restrict To Specified Namespace & ConfigMap Name
+	sharedInformerOptions := []informers.SharedInformerOption{
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.FieldSelector = fmt.Sprintf("metadata.name=%s", name)
+		}),
+	}
+
+	// Create A SharedInformerFactory With The Namespaced / Named Options
+	factory := informers.NewSharedInformerFactoryWithOptions(client.Get(ctx), controller.DefaultResyncPeriod, sharedInformerOptions...)
+	return SettingsInformer{factory: factory}
+}
+
+// Extract The Typed Namespace Settings ConfigMapInformer From The Specified Context
+func GetNamespaceSettings(ctx context.Context) informerscorev1.ConfigMapInformer {
+	untyped := ctx.Value(NamespaceSettingsKey{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch eventing-kafka/pkg/controller/settingsinformer/NamespaceSettings from context.")
+	}
+	return untyped.(informerscorev1.ConfigMapInformer)
+}
+
+// Extract The Typed Channel Classes ConfigMapInformer From The Specified Context
+func GetChannelClasses(ctx context.Context) informerscorev1.ConfigMapInformer {
+	untyped := ctx.Value(ChannelClassesKey{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch eventing-kafka/pkg/controller/settingsinformer/ChannelClasses from context.")
+	}
+	return untyped.(informerscorev1.ConfigMapInformer)
+}
+
+// Verify The SettingsInformer Implements The K8S ConfigMapInformer Interface
+var _ informerscorev1.ConfigMapInformer = SettingsInformer{}
+
+// Custom Settings ConfigMapInformer Implementation
+type SettingsInformer struct {
+	factory informers.SharedInformerFactory
+}
+
+// Implement The K8S ConfigMapInformer's Informer() Interface Function
+func (i SettingsInformer) Informer() cache.SharedIndexInformer {
+	return i.factory.Core().V1().ConfigMaps().Informer()
+}
+
+// Implement The K8S ConfigMapInformer's Lister() Interface Function
+func (i SettingsInformer) Lister() listerscorev1.ConfigMapLister {
+	return listerscorev1.NewConfigMapLister(i.Informer().GetIndexer())
+}
@@ -18,6 +18,7 @@ package testing
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"time"
 
@@ -46,6 +47,12 @@ import (
 	"knative.dev/pkg/system"
 )
 
+// The Test Data Models A Default Installation In The knative-eventing System Namespace (Overriding The
+// knative-testing Namespace Set By The Knative Reconciler Testing Package)
+func init() {
+	_ = os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace)
+}
+
 // Constants
 const (
 	// Prometheus MetricsPort
@@ -61,6 +68,9 @@ const (
 	ReceiverReplicas         = 1
 	DispatcherImage          = "TestDispatcherImage"
 	DispatcherReplicas       = 1
+	Architecture             = "arm64"
+	ImageClass               = "canary"
+	CanaryDispatcherImage    = "TestCanaryDispatcherImage"
 	DefaultNumPartitions     = 4
 	DefaultReplicationFactor = 1
 	DefaultRetentionMillis   = 99999
@@ -69,7 +79,7 @@ const (
 	KafkaChannelNamespace  = "kafkachannel-namespace"
 	KafkaChannelName       = "kafkachannel-name"
 	KafkaChannelKey        = KafkaChannelNamespace + "/" + KafkaChannelName
-	KafkaSecretNamespace   = commonconstants.KnativeEventingNamespace // Needs To Match Hardcoded Value In Reconciliation
+	KafkaSecretNamespace   = commonconstants.KnativeEventingNamespace // Needs To Match The System Namespace In Reconciliation
 	KafkaSecretName        = "kafkasecret-name"
 	KafkaSecretKey         = KafkaSecretNamespace + "/" + KafkaSecretName
 	ReceiverDeploymentName = KafkaSecretName + "-b9176d5f-receiver" // Truncated MD5 Hash Of KafkaSecretName
@@ -178,6 +188,9 @@ func NewConfig() *config.EventingKafkaConfig {
 				CpuRequest:    resource.MustParse(DispatcherCpuRequest),
 				MemoryLimit:   resource.MustParse(DispatcherMemoryLimit),
 				MemoryRequest: resource.MustParse(DispatcherMemoryRequest),
+				Images: config.EKImagesConfig{
+					Classes: map[string]string{ImageClass: CanaryDispatcherImage},
+				},
 			},
 		},
 		Receiver: config.EKReceiverConfig{
@@ -350,6 +363,22 @@ func WithAnnotations(kafkachannel *kafkav1beta1.KafkaChannel) {
 	}
 }
 
+// Set The KafkaChannel's Architecture Annotation (After Any Other Annotations)
+func WithArchitectureAnnotation(kafkachannel *kafkav1beta1.KafkaChannel) {
+	if kafkachannel.ObjectMeta.Annotations == nil {
+		kafkachannel.ObjectMeta.Annotations = map[string]string{}
+	}
+	kafkachannel.ObjectMeta.Annotations[kafkaconstants.ArchitectureAnnotation] = Architecture
+}
+
+// Set The KafkaChannel's Image Class Annotation (After Any Other Annotations)
+func WithImageClassAnnotation(kafkachannel *kafkav1beta1.KafkaChannel) {
+	if kafkachannel.ObjectMeta.Annotations == nil {
+		kafkachannel.ObjectMeta.Annotations = map[string]string{}
+	}
+	kafkachannel.ObjectMeta.Annotations[kafkaconstants.ImageClassAnnotation] = ImageClass
+}
+
 // Set The KafkaChannel's Labels
 func WithLabels(kafkachannel *kafkav1beta1.KafkaChannel) {
 	kafkachannel.ObjectMeta.Labels = map[string]string{
@@ -418,9 +447,24 @@ func WithDispatcherFailed(kafkachannel *kafkav1beta1.KafkaChannel) {
 	kafkachannel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Create Dispatcher Deployment: inducing failure for create deployments")
 }
 
+// Set The KafkaChannel's Dispatcher Deployment As Failed To Update
+func WithDispatcherUpdateFailed(kafkachannel *kafkav1beta1.KafkaChannel) {
+	kafkachannel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Update Dispatcher Deployment Image: inducing failure for update deployments")
+}
+
+// Set The KafkaChannel's Effective Config Status Annotation
+func WithEffectiveConfig(kafkachannel *kafkav1beta1.KafkaChannel) {
+	if kafkachannel.Status.Annotations == nil {
+		kafkachannel.Status.Annotations = make(map[string]string)
+	}
+	kafkachannel.Status.Annotations[constants.EffectiveConfigAnnotation] = fmt.Sprintf(`{"numPartitions":%d,"replicationFactor":%d,"retentionMillis":%d,"dispatcherReplicas":%d}`,
+		NumPartitions, ReplicationFactor, DefaultRetentionMillis, DispatcherReplicas)
+}
+
 // Set The KafkaChannel's Topic READY
 func WithTopicReady(kafkachannel *kafkav1beta1.KafkaChannel) {
 	kafkachannel.Status.MarkTopicTrue()
+	kafkachannel.Status.Topic = util.TopicName(kafkachannel)
 }
 
 // Utility Function For Creating A Custom KafkaChannel "Channel" Service For Testing
@@ -488,6 +532,32 @@ func NewKafkaChannelReceiverService() *corev1.Service {
 	}
 }
 
+// Utility Function For Creating The Default (Restricted) Pod SecurityContext Of The Receiver / Dispatcher Deployments
+func NewPodSecurityContext() *corev1.PodSecurityContext {
+	runAsNonRoot := true
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot: &runAsNonRoot,
+	}
+}
+
+// Utility Function For Creating The Default (Restricted) Container SecurityContext Of The Receiver / Dispatcher Deployments
+func NewContainerSecurityContext() *corev1.SecurityContext {
+	allowPrivilegeEscalation := false
+	readOnlyRootFilesystem := true
+	runAsNonRoot := true
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		RunAsNonRoot:             &runAsNonRoot,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
+// The Termination Grace Period Of The Receiver Pods With The Default Shutdown Config
+var receiverTerminationGracePeriodSeconds int64 = 30
+
 // Utility Function For Creating A Receiver Deployment For The Test Channel
 func NewKafkaChannelReceiverDeployment() *appsv1.Deployment {
 	replicas := int32(ReceiverReplicas)
@@ -519,9 +589,14 @@ func NewKafkaChannelReceiverDeployment() *appsv1.Deployment {
 					Labels: map[string]string{
 						"app": ReceiverDeploymentName,
 					},
+					Annotations: map[string]string{
+						"seccomp.security.alpha.kubernetes.io/pod": "runtime/default",
+					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: ServiceAccount,
+					ServiceAccountName:            ServiceAccount,
+					SecurityContext:               NewPodSecurityContext(),
+					TerminationGracePeriodSeconds: &receiverTerminationGracePeriodSeconds,
 					Containers: []corev1.Container{
 						{
 							Name: ReceiverDeploymentName,
@@ -618,6 +693,7 @@ func NewKafkaChannelReceiverDeployment() *appsv1.Deployment {
 								},
 							},
 							ImagePullPolicy: corev1.PullIfNotPresent,
+							SecurityContext: NewContainerSecurityContext(),
 							Resources: corev1.ResourceRequirements{
 								Requests: corev1.ResourceList{
 									corev1.ResourceCPU:    resource.MustParse(ReceiverCpuRequest),
@@ -642,7 +718,7 @@ func NewKafkaChannelDispatcherService() *corev1.Service {
 	// Get The Expected Service Name For The Test KafkaChannel
 	serviceName := util.DispatcherDnsSafeName(&kafkav1beta1.KafkaChannel{
 		ObjectMeta: metav1.ObjectMeta{Namespace: KafkaChannelNamespace, Name: KafkaChannelName},
-	})
+	}, util.NameStrategyTruncate)
 
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -677,18 +753,21 @@ func NewKafkaChannelDispatcherService() *corev1.Service {
 	}
 }
 
+// DeploymentOption Enables Customization Of A Deployment
+type DeploymentOption func(*appsv1.Deployment)
+
 // Utility Function For Creating A Custom KafkaChannel Dispatcher Deployment For Testing
-func NewKafkaChannelDispatcherDeployment() *appsv1.Deployment {
+func NewKafkaChannelDispatcherDeployment(options ...DeploymentOption) *appsv1.Deployment {
 
 	// Get The Expected Dispatcher & Topic Names For The Test KafkaChannel
 	sparseKafkaChannel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Namespace: KafkaChannelNamespace, Name: KafkaChannelName}}
-	dispatcherName := util.DispatcherDnsSafeName(sparseKafkaChannel)
+	dispatcherName := util.DispatcherDnsSafeName(sparseKafkaChannel, util.NameStrategyTruncate)
 	topicName := util.TopicName(sparseKafkaChannel)
 
 	// Replicas Int Reference
 	replicas := int32(DispatcherReplicas)
 
-	return &appsv1.Deployment{
+	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: appsv1.SchemeGroupVersion.String(),
 			Kind:       constants.DeploymentKind,
@@ -718,9 +797,13 @@ func NewKafkaChannelDispatcherDeployment() *appsv1.Deployment {
 					Labels: map[string]string{
 						"app": dispatcherName,
 					},
+					Annotations: map[string]string{
+						"seccomp.security.alpha.kubernetes.io/pod": "runtime/default",
+					},
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: ServiceAccount,
+					SecurityContext:    NewPodSecurityContext(),
 					Containers: []corev1.Container{
 						{
 							Name:  dispatcherName,
@@ -819,6 +902,7 @@ func NewKafkaChannelDispatcherDeployment() *appsv1.Deployment {
 								},
 							},
 							ImagePullPolicy: corev1.PullIfNotPresent,
+							SecurityContext: NewContainerSecurityContext(),
 							Resources: corev1.ResourceRequirements{
 								Limits: corev1.ResourceList{
 									corev1.ResourceMemory: resource.MustParse(DispatcherMemoryLimit),
@@ -835,6 +919,36 @@ func NewKafkaChannelDispatcherDeployment() *appsv1.Deployment {
 			},
 		},
 	}
+
+	// Apply The Specified Deployment Customizations
+	for _, option := range options {
+		option(deployment)
+	}
+
+	// Return The Test Dispatcher Deployment
+	return deployment
+}
+
+// Constrain The Deployment To Nodes Of The Test Architecture
+func WithDeploymentArchitecture(deployment *appsv1.Deployment) {
+	deployment.Spec.Template.Spec.NodeSelector = map[string]string{corev1.LabelArchStable: Architecture}
+}
+
+// Set The Deployment's Image To The Canary Image
+func WithDeploymentCanaryImage(deployment *appsv1.Deployment) {
+	deployment.Spec.Template.Spec.Containers[0].Image = CanaryDispatcherImage
+}
+
+// Set The Deployment's Progressing Condition To A Rollout Which Exceeded Its Progress Deadline
+func WithDeploymentProgressDeadlineExceeded(deployment *appsv1.Deployment) {
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+	}
+}
+
+// Set The Deployment's Rolled Back Image To The Canary Image
+func WithDeploymentRolledBackCanaryImage(deployment *appsv1.Deployment) {
+	deployment.Annotations = map[string]string{constants.RolledBackImageAnnotation: CanaryDispatcherImage}
 }
 
 // Utility Function For Creating A New OwnerReference Model For The Test Kafka Secret
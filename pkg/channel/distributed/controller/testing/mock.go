@@ -21,22 +21,43 @@ import (
 
 	"github.com/Shopify/sarama"
 	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/audit"
 )
 
 //
 // Mock Kafka AdminClient
 //
 
-// Verify The Mock AdminClient Implements The KafkaAdminClient Interface
-var _ kafkaadmin.AdminClientInterface = &MockAdminClient{}
+// Verify The Mock AdminClient Implements The KafkaAdminClient, ClusterInspector, OffsetInspector & ConsumerGroupInspector Interfaces
+var _ kafkaadmin.TopicProvisioner = &MockAdminClient{}
+var _ kafkaadmin.ClusterInspector = &MockAdminClient{}
+var _ kafkaadmin.OffsetInspector = &MockAdminClient{}
+var _ kafkaadmin.ConsumerGroupInspector = &MockAdminClient{}
 
 // Mock Kafka AdminClient Implementation
 type MockAdminClient struct {
-	closeCalled         bool
-	createTopicsCalled  bool
-	deleteTopicsCalled  bool
-	MockCreateTopicFunc func(context.Context, string, *sarama.TopicDetail) *sarama.TopicError
-	MockDeleteTopicFunc func(context.Context, string) *sarama.TopicError
+	closeCalled                 bool
+	createTopicsCalled          bool
+	deleteTopicsCalled          bool
+	alterTopicCalled            bool
+	MockValidateFunc            func(context.Context, string, *sarama.TopicDetail) error
+	MockCreateTopicFunc         func(context.Context, string, *sarama.TopicDetail) *sarama.TopicError
+	MockAlterTopicFunc          func(context.Context, string, *sarama.TopicDetail) *sarama.TopicError
+	MockDeleteTopicFunc         func(context.Context, string) *sarama.TopicError
+	MockTopics                  []string
+	MockConsumerGroups          []string
+	MockDeleteConsumerGroupFunc func(context.Context, string) error
+	MockTopicOffsets            map[string]int64
+	MockConsumerGroupOffsets    map[string]*kafkaadmin.ConsumerGroupOffsets
+	MockConsumerGroupStates     map[string]string
+}
+
+// Mock Kafka AdminClient Validate() Function - Calls Custom Validate() If Specified, Otherwise Returns Success
+func (m *MockAdminClient) Validate(ctx context.Context, topicName string, topicDetail *sarama.TopicDetail) error {
+	if m.MockValidateFunc != nil {
+		return m.MockValidateFunc(ctx, topicName, topicDetail)
+	}
+	return nil
 }
 
 // Mock Kafka AdminClient CreateTopic() Function - Calls Custom CreateTopic() If Specified, Otherwise Returns Success
@@ -69,6 +90,70 @@ func (m *MockAdminClient) DeleteTopicsCalled() bool {
 	return m.deleteTopicsCalled
 }
 
+// Mock Kafka AdminClient AlterTopic() Function - Calls Custom AlterTopic() If Specified, Otherwise Returns Success
+func (m *MockAdminClient) AlterTopic(ctx context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
+	m.alterTopicCalled = true
+	if m.MockAlterTopicFunc != nil {
+		return m.MockAlterTopicFunc(ctx, topicName, topicDetail)
+	}
+	return nil
+}
+
+// Check On Calls To AlterTopic()
+func (m *MockAdminClient) AlterTopicCalled() bool {
+	return m.alterTopicCalled
+}
+
+// Mock Kafka AdminClient ListTopics() Function - Returns The MockTopics
+func (m *MockAdminClient) ListTopics(_ context.Context) ([]string, error) {
+	return m.MockTopics, nil
+}
+
+// Mock Kafka AdminClient ListConsumerGroups() Function - Returns The MockConsumerGroups
+func (m *MockAdminClient) ListConsumerGroups(_ context.Context) ([]string, error) {
+	return m.MockConsumerGroups, nil
+}
+
+// Mock Kafka AdminClient DeleteConsumerGroup() Function - Calls Custom DeleteConsumerGroup() If Specified, Otherwise Returns Success
+func (m *MockAdminClient) DeleteConsumerGroup(ctx context.Context, groupId string) error {
+	if m.MockDeleteConsumerGroupFunc != nil {
+		return m.MockDeleteConsumerGroupFunc(ctx, groupId)
+	}
+	return nil
+}
+
+// Mock Kafka AdminClient TopicOffsets() Function - Returns The MockTopicOffsets Of The Specified Topics
+func (m *MockAdminClient) TopicOffsets(_ context.Context, topicNames []string) (map[string]int64, error) {
+	topicOffsets := make(map[string]int64, len(topicNames))
+	for _, topicName := range topicNames {
+		if offset, ok := m.MockTopicOffsets[topicName]; ok {
+			topicOffsets[topicName] = offset
+		}
+	}
+	return topicOffsets, nil
+}
+
+// Mock Kafka AdminClient ConsumerGroupOffsets() Function - Returns The MockConsumerGroupOffsets Of The ConsumerGroup
+func (m *MockAdminClient) ConsumerGroupOffsets(_ context.Context, groupId string, _ []string) (*kafkaadmin.ConsumerGroupOffsets, error) {
+	if consumerGroupOffsets, ok := m.MockConsumerGroupOffsets[groupId]; ok {
+		return consumerGroupOffsets, nil
+	}
+	return &kafkaadmin.ConsumerGroupOffsets{Committed: map[string]map[int32]int64{}}, nil
+}
+
+// Mock Kafka AdminClient ConsumerGroupStates() Function - Returns The MockConsumerGroupStates Of The ConsumerGroups (Default "Dead")
+func (m *MockAdminClient) ConsumerGroupStates(_ context.Context, groupIds []string) (map[string]string, error) {
+	states := make(map[string]string, len(groupIds))
+	for _, groupId := range groupIds {
+		if state, ok := m.MockConsumerGroupStates[groupId]; ok {
+			states[groupId] = state
+		} else {
+			states[groupId] = "Dead"
+		}
+	}
+	return states, nil
+}
+
 // Mock Kafka AdminClient Close Function - NoOp
 func (m *MockAdminClient) Close() error {
 	m.closeCalled = true
@@ -84,3 +169,20 @@ func (m *MockAdminClient) CloseCalled() bool {
 func (m *MockAdminClient) GetKafkaSecretName(_ string) string {
 	return KafkaSecretName
 }
+
+//
+// Mock Audit Recorder
+//
+
+// Verify The Mock Audit Recorder Implements The Interface
+var _ audit.Recorder = &MockAuditRecorder{}
+
+// Mock Audit Recorder Tracking The Recorded Entries
+type MockAuditRecorder struct {
+	Entries []audit.Entry
+}
+
+// Mock Audit Recorder Record() Function - Tracks The Entry
+func (m *MockAuditRecorder) Record(entry audit.Entry) {
+	m.Entries = append(m.Entries, entry)
+}
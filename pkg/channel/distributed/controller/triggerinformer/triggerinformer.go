@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggerinformer
+
+import (
+	"context"
+
+	v1 "knative.dev/eventing/pkg/client/informers/externalversions/eventing/v1"
+	"knative.dev/eventing/pkg/client/injection/informers/factory"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+)
+
+//
+// Trigger Informer
+//
+// Note:  The vendored Knative Eventing injection packages only include the Broker informer, so the Trigger
+//        informer is injected here in the same manner from the shared Knative Eventing informer factory.
+//
+
+// Add The InformerInjector Function With The Knative Injection Framework
+func init() {
+	injection.Default.RegisterInformer(withInformer)
+}
+
+// Key Used To Associate The Informer Inside The Context
+type Key struct{}
+
+// InformerInjector For The Trigger Informer
+func withInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := factory.Get(ctx).Eventing().V1().Triggers()
+	return context.WithValue(ctx, Key{}, inf), inf.Informer()
+}
+
+// Get Extracts The Trigger Informer From The Context
+func Get(ctx context.Context) v1.TriggerInformer {
+	untyped := ctx.Value(Key{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch TriggerInformer from context.")
+	}
+	return untyped.(v1.TriggerInformer)
+}
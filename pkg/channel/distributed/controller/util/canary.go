@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// The Deployment Controller's Reasons For A Rollout Which Failed To Progress Within Its Deadline Or Completed
+const (
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+	newReplicaSetAvailableReason   = "NewReplicaSetAvailable"
+)
+
+// Get The Image Class Of The Specified KafkaChannel's Dispatcher ("" For The Default Image)
+//
+// The image class annotation of the KafkaChannel takes precedence over the canary class of the config, which
+// applies to the KafkaChannels matching the canary selector.
+func DispatcherImageClass(imagesConfig config.EKImagesConfig, channel *kafkav1beta1.KafkaChannel) (string, error) {
+
+	// The KafkaChannel's Annotation Takes Precedence
+	if imageClass := channel.Annotations[kafkaconstants.ImageClassAnnotation]; imageClass != "" {
+		return imageClass, nil
+	}
+
+	// Otherwise Use The Canary Class If The KafkaChannel Matches The Canary Selector
+	canary := imagesConfig.Canary
+	if canary.Class == "" || canary.Selector == nil {
+		return "", nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(canary.Selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid canary selector: %v", err)
+	}
+	if selector.Empty() || !selector.Matches(labels.Set(channel.Labels)) {
+		return "", nil
+	}
+	return canary.Class, nil
+}
+
+// Determine Whether The Latest Rollout Of The Specified Deployment Failed To Progress Within Its Deadline
+func DeploymentProgressDeadlineExceeded(deployment *appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == progressDeadlineExceededReason
+		}
+	}
+	return false
+}
+
+// Get The Time At Which The Latest Rollout Of The Specified Deployment Completed (False If It Hasn't)
+func DeploymentRolloutCompleted(deployment *appsv1.Deployment) (time.Time, bool) {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing {
+			if condition.Status == corev1.ConditionTrue && condition.Reason == newReplicaSetAvailableReason {
+				return condition.LastUpdateTime.Time, true
+			}
+			return time.Time{}, false
+		}
+	}
+	return time.Time{}, false
+}
+
+// Determine Whether The Error Rate Of A Dispatcher's Events Exceeds The Canary's Maximum (Never If Not Configured Or
+// Fewer Than The Minimum Events Were Dispatched)
+func CanaryErrorRateExceeded(canaryConfig config.EKCanaryConfig, errorRate float64, dispatchedEvents int64) bool {
+	if canaryConfig.MaxErrorRate <= 0 || dispatchedEvents == 0 || dispatchedEvents < canaryConfig.MinEvents {
+		return false
+	}
+	return errorRate > canaryConfig.MaxErrorRate
+}
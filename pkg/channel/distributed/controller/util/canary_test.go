@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Test The DispatcherImageClass() Functionality
+func TestDispatcherImageClass(t *testing.T) {
+
+	// Test Data
+	canaryConfig := config.EKImagesConfig{
+		Canary: config.EKCanaryConfig{
+			Class:    "canary",
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "canary"}},
+		},
+	}
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		Name          string
+		ImagesConfig  config.EKImagesConfig
+		Labels        map[string]string
+		Annotations   map[string]string
+		ExpectedClass string
+		ExpectedErr   bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{Name: "No Class"},
+		{Name: "Annotation", Annotations: map[string]string{kafkaconstants.ImageClassAnnotation: "beta"}, ExpectedClass: "beta"},
+		{Name: "Canary Selected", ImagesConfig: canaryConfig, Labels: map[string]string{"tier": "canary"}, ExpectedClass: "canary"},
+		{Name: "Canary Not Selected", ImagesConfig: canaryConfig, Labels: map[string]string{"tier": "production"}},
+		{Name: "Annotation Overrides Canary", ImagesConfig: canaryConfig, Labels: map[string]string{"tier": "canary"}, Annotations: map[string]string{kafkaconstants.ImageClassAnnotation: "beta"}, ExpectedClass: "beta"},
+		{Name: "Empty Canary Selector", ImagesConfig: config.EKImagesConfig{Canary: config.EKCanaryConfig{Class: "canary", Selector: &metav1.LabelSelector{}}}},
+		{
+			Name: "Invalid Canary Selector",
+			ImagesConfig: config.EKImagesConfig{Canary: config.EKCanaryConfig{Class: "canary", Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Bogus"}},
+			}}},
+			ExpectedErr: true,
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			channel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Labels: testCase.Labels, Annotations: testCase.Annotations}}
+			imageClass, err := DispatcherImageClass(testCase.ImagesConfig, channel)
+			assert.Equal(t, testCase.ExpectedErr, err != nil)
+			assert.Equal(t, testCase.ExpectedClass, imageClass)
+		})
+	}
+}
+
+// Test The DeploymentProgressDeadlineExceeded() Functionality
+func TestDeploymentProgressDeadlineExceeded(t *testing.T) {
+	newDeployment := func(conditions ...appsv1.DeploymentCondition) *appsv1.Deployment {
+		return &appsv1.Deployment{Status: appsv1.DeploymentStatus{Conditions: conditions}}
+	}
+	assert.False(t, DeploymentProgressDeadlineExceeded(newDeployment()))
+	assert.False(t, DeploymentProgressDeadlineExceeded(newDeployment(
+		appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"})))
+	assert.True(t, DeploymentProgressDeadlineExceeded(newDeployment(
+		appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+		appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"})))
+}
+
+// Test The DeploymentRolloutCompleted() Functionality
+func TestDeploymentRolloutCompleted(t *testing.T) {
+	completed := metav1.NewTime(time.Unix(1600000000, 0))
+	newDeployment := func(conditions ...appsv1.DeploymentCondition) *appsv1.Deployment {
+		return &appsv1.Deployment{Status: appsv1.DeploymentStatus{Conditions: conditions}}
+	}
+	_, ok := DeploymentRolloutCompleted(newDeployment())
+	assert.False(t, ok)
+	_, ok = DeploymentRolloutCompleted(newDeployment(
+		appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated"}))
+	assert.False(t, ok)
+	_, ok = DeploymentRolloutCompleted(newDeployment(
+		appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"}))
+	assert.False(t, ok)
+	rolloutCompleted, ok := DeploymentRolloutCompleted(newDeployment(
+		appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+		appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable", LastUpdateTime: completed}))
+	assert.True(t, ok)
+	assert.Equal(t, completed.Time, rolloutCompleted)
+}
+
+// Test The CanaryErrorRateExceeded() Functionality
+func TestCanaryErrorRateExceeded(t *testing.T) {
+	canaryConfig := config.EKCanaryConfig{MaxErrorRate: 0.1, MinEvents: 100}
+	assert.False(t, CanaryErrorRateExceeded(config.EKCanaryConfig{}, 1, 1000))
+	assert.False(t, CanaryErrorRateExceeded(canaryConfig, 0.5, 0))
+	assert.False(t, CanaryErrorRateExceeded(canaryConfig, 0.5, 99))
+	assert.False(t, CanaryErrorRateExceeded(canaryConfig, 0.1, 100))
+	assert.True(t, CanaryErrorRateExceeded(canaryConfig, 0.11, 100))
+	assert.True(t, CanaryErrorRateExceeded(config.EKCanaryConfig{MaxErrorRate: 0.1}, 1, 1))
+}
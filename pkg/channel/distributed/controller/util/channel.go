@@ -24,7 +24,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/network"
 )
 
@@ -70,9 +72,7 @@ func ReceiverDnsSafeName(kafkaSecretName string) string {
 	// In order for the resulting name to be a valid DNS component it's length must be no more than 63 characters.
 	// We are consuming 18 chars for the component separators, hash, and Receiver suffix, which reduces the
 	// available length to 45. We will allocate 40 characters to the kafka secret name leaving an extra buffer.
-	safeSecretName := GenerateValidDnsName(kafkaSecretName, 40, true, false)
-
-	return fmt.Sprintf("%s-%s-receiver", safeSecretName, GenerateHash(kafkaSecretName, 8))
+	return DnsSafeName(NameStrategyTruncate, kafkaconstants.ReceiverServiceSuffix, NamePart{Value: kafkaSecretName, MaxLength: 40})
 }
 
 // Channel Host Naming Utility
@@ -114,3 +114,26 @@ func RetentionMillis(channel *kafkav1beta1.KafkaChannel, configuration *config.E
 	//return value
 	return configuration.Kafka.Topic.DefaultRetentionMillis
 }
+
+// EffectiveConfig is the configuration applied to a KafkaChannel by the controller, as resolved from the KafkaChannel's
+// spec and the (namespace / cluster-wide) ConfigMap settings.  An empty CleanupPolicy defaults to the Kafka broker's.
+type EffectiveConfig struct {
+	NumPartitions      int32                      `json:"numPartitions"`
+	ReplicationFactor  int16                      `json:"replicationFactor"`
+	RetentionMillis    int64                      `json:"retentionMillis"`
+	CleanupPolicy      string                     `json:"cleanupPolicy,omitempty"`
+	DispatcherReplicas int                        `json:"dispatcherReplicas"`
+	Delivery           *eventingduck.DeliverySpec `json:"delivery,omitempty"`
+}
+
+// Utility Function To Resolve The EffectiveConfig Of The Specified KafkaChannel
+func NewEffectiveConfig(channel *kafkav1beta1.KafkaChannel, configuration *config.EventingKafkaConfig, logger *zap.Logger) EffectiveConfig {
+	return EffectiveConfig{
+		NumPartitions:      NumPartitions(channel, configuration, logger),
+		ReplicationFactor:  ReplicationFactor(channel, configuration, logger),
+		RetentionMillis:    RetentionMillis(channel, configuration, logger),
+		CleanupPolicy:      channel.Spec.CleanupPolicy,
+		DispatcherReplicas: configuration.Dispatcher.Replicas,
+		Delivery:           channel.Spec.Delivery,
+	}
+}
@@ -26,6 +26,7 @@ import (
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	logtesting "knative.dev/pkg/logging/testing"
 )
 
@@ -173,3 +174,39 @@ func TestRetentionMillis(t *testing.T) {
 	//actualRetentionMillis = RetentionMillis(channel, environment, logger)
 	//assert.Equal(t, retentionMillis, actualRetentionMillis)
 }
+
+// Test The NewEffectiveConfig() Functionality
+func TestNewEffectiveConfig(t *testing.T) {
+
+	// Test Logger
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Test Data
+	configuration := &config.EventingKafkaConfig{
+		Dispatcher: config.EKDispatcherConfig{EKKubernetesConfig: config.EKKubernetesConfig{Replicas: 2}},
+		Kafka: config.EKKafkaConfig{Topic: config.EKKafkaTopicConfig{
+			DefaultNumPartitions:     defaultNumPartitions,
+			DefaultReplicationFactor: defaultReplicationFactor,
+			DefaultRetentionMillis:   defaultRetentionMillis,
+		}},
+	}
+	retry := int32(5)
+	channel := &kafkav1beta1.KafkaChannel{Spec: kafkav1beta1.KafkaChannelSpec{
+		NumPartitions: numPartitions,
+		CleanupPolicy: kafkav1beta1.CleanupPolicyCompact,
+	}}
+	channel.Spec.Delivery = &eventingduck.DeliverySpec{Retry: &retry}
+
+	// Perform The Test
+	effectiveConfig := NewEffectiveConfig(channel, configuration, logger)
+
+	// Verify The Results
+	assert.Equal(t, EffectiveConfig{
+		NumPartitions:      numPartitions,
+		ReplicationFactor:  defaultReplicationFactor,
+		RetentionMillis:    defaultRetentionMillis,
+		CleanupPolicy:      kafkav1beta1.CleanupPolicyCompact,
+		DispatcherReplicas: 2,
+		Delivery:           &eventingduck.DeliverySpec{Retry: &retry},
+	}, effectiveConfig)
+}
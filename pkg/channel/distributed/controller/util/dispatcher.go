@@ -17,20 +17,32 @@ limitations under the License.
 package util
 
 import (
-	"fmt"
-
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 )
 
-// Create A DNS Safe Name For The Specified KafkaChannel Suitable For Use With K8S Services
-func DispatcherDnsSafeName(channel *kafkav1beta1.KafkaChannel) string {
+// Create A DNS Safe Name For The Specified KafkaChannel Suitable For Use With K8S Services, Using The Specified
+// NameStrategy (The Service & Deployment Of A Dispatcher Share This Name)
+func DispatcherDnsSafeName(channel *kafkav1beta1.KafkaChannel, strategy string) string {
 
 	// In order for the resulting name to be a valid DNS component is 63 characters.  We are appending 13 characters to
 	// separate the components and to indicate this is a Dispatcher, and adding 8 hash characters, which further reduces
 	// the available length to 42.
 	// We will allocate 26 characters to the channel and 16 to the namespace, leaving some extra buffer.
-	safeChannelName := GenerateValidDnsName(channel.Name, 26, true, false)
-	safeChannelNamespace := GenerateValidDnsName(channel.Namespace, 16, false, false)
-	hash := GenerateHash(channel.Name+channel.Namespace, 8)
-	return fmt.Sprintf("%s-%s-%s-dispatcher", safeChannelName, safeChannelNamespace, hash)
+	// (The "hash" NameStrategy ignores these allocations, using the whole length & a longer hash instead.)
+	return DnsSafeName(strategy, "dispatcher",
+		NamePart{Value: channel.Name, MaxLength: 26},
+		NamePart{Value: channel.Namespace, MaxLength: 16})
+}
+
+// Get The DNS Safe Names The Other NameStrategies Would Give The Dispatcher Of The Specified KafkaChannel, Whose
+// Resources Are Left Over From Before The NameStrategy Was Changed
+func FormerDispatcherDnsSafeNames(channel *kafkav1beta1.KafkaChannel, strategy string) []string {
+	currentName := DispatcherDnsSafeName(channel, strategy)
+	var formerNames []string
+	for _, formerStrategy := range []string{NameStrategyTruncate, NameStrategyHash} {
+		if formerName := DispatcherDnsSafeName(channel, formerStrategy); formerName != currentName {
+			formerNames = append(formerNames, formerName)
+		}
+	}
+	return formerNames
 }
@@ -18,6 +18,7 @@ package util
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -67,7 +68,7 @@ func TestDispatcherDnsSafeName(t *testing.T) {
 		channel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: testCase.Name, Namespace: testCase.Namespace}}
 
 		// Perform The Test
-		actualResult := DispatcherDnsSafeName(channel)
+		actualResult := DispatcherDnsSafeName(channel, NameStrategyTruncate)
 		hash := GenerateHash(testCase.Name+testCase.Namespace, 8)
 		truncateName := fmt.Sprintf("%.26s", testCase.Name)
 		truncateNamespace := fmt.Sprintf("%.16s", testCase.Namespace)
@@ -138,13 +139,13 @@ func TestDispatcherDnsSafeName_LongNamesDifferent(t *testing.T) {
 		channel2 := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: testCase.Name2, Namespace: testCase.Namespace2}}
 
 		// Perform The Test
-		actualResult1 := DispatcherDnsSafeName(channel1)
+		actualResult1 := DispatcherDnsSafeName(channel1, NameStrategyTruncate)
 		hash1 := GenerateHash(testCase.Name1+testCase.Namespace1, 8)
 		truncateName1 := fmt.Sprintf("%.26s", testCase.Name1)
 		truncateNamespace1 := fmt.Sprintf("%.16s", testCase.Namespace1)
 		expectedResult1 := fmt.Sprintf("%s-%s-%s-dispatcher", truncateName1, truncateNamespace1, hash1)
 
-		actualResult2 := DispatcherDnsSafeName(channel2)
+		actualResult2 := DispatcherDnsSafeName(channel2, NameStrategyTruncate)
 		hash2 := GenerateHash(testCase.Name2+testCase.Namespace2, 8)
 		truncateName2 := fmt.Sprintf("%.26s", testCase.Name2)
 		truncateNamespace2 := fmt.Sprintf("%.16s", testCase.Namespace2)
@@ -156,3 +157,34 @@ func TestDispatcherDnsSafeName_LongNamesDifferent(t *testing.T) {
 		assert.NotEqual(t, actualResult1, actualResult2)
 	}
 }
+
+// Test The DispatcherDnsSafeName() Functionality Of The "hash" NameStrategy
+func TestDispatcherDnsSafeName_Hash(t *testing.T) {
+
+	// Channels Whose Truncated Names & Concatenated Name+Namespace Are Identical Collide With The "truncate" Strategy
+	channel1 := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: "kafkachannel-with-long-name-a", Namespace: "aaaaaaaaaaaaaaaaaaaa"}}
+	channel2 := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: "kafkachannel-with-long-name-", Namespace: "aaaaaaaaaaaaaaaaaaaaa"}}
+	assert.Equal(t, DispatcherDnsSafeName(channel1, NameStrategyTruncate), DispatcherDnsSafeName(channel2, NameStrategyTruncate))
+
+	// But Not With The "hash" Strategy
+	actualResult1 := DispatcherDnsSafeName(channel1, NameStrategyHash)
+	actualResult2 := DispatcherDnsSafeName(channel2, NameStrategyHash)
+	assert.NotEqual(t, actualResult1, actualResult2)
+	assert.LessOrEqual(t, len(actualResult1), 63)
+	assert.True(t, strings.HasPrefix(actualResult1, "kafkachannel-with-long-name-a-aaaaa-"))
+	assert.True(t, strings.HasSuffix(actualResult1, "-dispatcher"))
+
+	// Short Names Are Not Truncated
+	channel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace"}}
+	assert.Regexp(t, "^name-namespace-[0-9a-f]{16}-dispatcher$", DispatcherDnsSafeName(channel, NameStrategyHash))
+}
+
+// Test The FormerDispatcherDnsSafeNames() Functionality
+func TestFormerDispatcherDnsSafeNames(t *testing.T) {
+	channel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: channelName, Namespace: channelNamespace}}
+	truncatedName := DispatcherDnsSafeName(channel, NameStrategyTruncate)
+	hashedName := DispatcherDnsSafeName(channel, NameStrategyHash)
+	assert.Equal(t, []string{hashedName}, FormerDispatcherDnsSafeNames(channel, ""))
+	assert.Equal(t, []string{hashedName}, FormerDispatcherDnsSafeNames(channel, NameStrategyTruncate))
+	assert.Equal(t, []string{truncatedName}, FormerDispatcherDnsSafeNames(channel, NameStrategyHash))
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Create The Secret Volumes Of The Key Encryption Keys (nil Unless Encryption Is Enabled)
+func EncryptionKeysVolumes(encryptionConfig config.EKEncryptionConfig) []corev1.Volume {
+	if !encryptionConfig.Enabled {
+		return nil
+	}
+	return []corev1.Volume{
+		{
+			Name: constants.EncryptionKeysVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: encryptionConfig.SecretName,
+				},
+			},
+		},
+	}
+}
+
+// Create The Secret VolumeMounts Of The Key Encryption Keys At The Envelope's Default Path (nil Unless Encryption Is Enabled)
+func EncryptionKeysVolumeMounts(encryptionConfig config.EKEncryptionConfig) []corev1.VolumeMount {
+	if !encryptionConfig.Enabled {
+		return nil
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      constants.EncryptionKeysVolumeName,
+			MountPath: encryption.DefaultKeysPath,
+			ReadOnly:  true,
+		},
+	}
+}
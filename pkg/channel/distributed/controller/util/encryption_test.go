@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Test The EncryptionKeysVolumes() & EncryptionKeysVolumeMounts() Functionality
+func TestEncryptionKeysVolumes(t *testing.T) {
+
+	// Disabled Encryption Has No Volumes
+	assert.Nil(t, EncryptionKeysVolumes(config.EKEncryptionConfig{SecretName: "keys"}))
+	assert.Nil(t, EncryptionKeysVolumeMounts(config.EKEncryptionConfig{SecretName: "keys"}))
+
+	// Enabled Encryption Mounts The Secret At The Default Keys Path
+	encryptionConfig := config.EKEncryptionConfig{Enabled: true, SecretName: "keys", KeyId: "key1"}
+	volumes := EncryptionKeysVolumes(encryptionConfig)
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, constants.EncryptionKeysVolumeName, volumes[0].Name)
+	assert.Equal(t, "keys", volumes[0].Secret.SecretName)
+	volumeMounts := EncryptionKeysVolumeMounts(encryptionConfig)
+	assert.Len(t, volumeMounts, 1)
+	assert.Equal(t, constants.EncryptionKeysVolumeName, volumeMounts[0].Name)
+	assert.Equal(t, encryption.DefaultKeysPath, volumeMounts[0].MountPath)
+	assert.True(t, volumeMounts[0].ReadOnly)
+}
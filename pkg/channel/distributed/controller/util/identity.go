@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/identity"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Create The Projected ServiceAccount Token Volumes Of The Workload Identity (nil Unless Enabled)
+func WorkloadIdentityVolumes(identityConfig config.EKWorkloadIdentityConfig) []corev1.Volume {
+	if !identityConfig.Enabled {
+		return nil
+	}
+	expirationSeconds := int64(constants.WorkloadIdentityTokenExpirationSeconds)
+	return []corev1.Volume{
+		{
+			Name: constants.WorkloadIdentityVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          identityConfig.Audience,
+								ExpirationSeconds: &expirationSeconds,
+								Path:              filepath.Base(workloadIdentityTokenPath(identityConfig)),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Create The Projected ServiceAccount Token VolumeMounts Of The Workload Identity (nil Unless Enabled)
+func WorkloadIdentityVolumeMounts(identityConfig config.EKWorkloadIdentityConfig) []corev1.VolumeMount {
+	if !identityConfig.Enabled {
+		return nil
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      constants.WorkloadIdentityVolumeName,
+			MountPath: filepath.Dir(workloadIdentityTokenPath(identityConfig)),
+			ReadOnly:  true,
+		},
+	}
+}
+
+// Get The Path Of The Projected ServiceAccount Token (Defaulting To The Workload Identity's Default Path)
+func workloadIdentityTokenPath(identityConfig config.EKWorkloadIdentityConfig) string {
+	if len(identityConfig.TokenPath) > 0 {
+		return identityConfig.TokenPath
+	}
+	return identity.DefaultTokenPath
+}
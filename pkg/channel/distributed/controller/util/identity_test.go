@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/identity"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Test The WorkloadIdentityVolumes() & WorkloadIdentityVolumeMounts() Functionality
+func TestWorkloadIdentityVolumes(t *testing.T) {
+
+	// Disabled Workload Identity Has No Volumes
+	assert.Nil(t, WorkloadIdentityVolumes(config.EKWorkloadIdentityConfig{}))
+	assert.Nil(t, WorkloadIdentityVolumeMounts(config.EKWorkloadIdentityConfig{}))
+
+	// Default Token Path
+	identityConfig := config.EKWorkloadIdentityConfig{Enabled: true, Audience: "kafka"}
+	volumes := WorkloadIdentityVolumes(identityConfig)
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, constants.WorkloadIdentityVolumeName, volumes[0].Name)
+	tokenProjection := volumes[0].Projected.Sources[0].ServiceAccountToken
+	assert.Equal(t, "kafka", tokenProjection.Audience)
+	assert.Equal(t, identity.DefaultTokenFileName, tokenProjection.Path)
+	assert.Equal(t, int64(constants.WorkloadIdentityTokenExpirationSeconds), *tokenProjection.ExpirationSeconds)
+	volumeMounts := WorkloadIdentityVolumeMounts(identityConfig)
+	assert.Len(t, volumeMounts, 1)
+	assert.Equal(t, identity.DefaultTokenDirectory, volumeMounts[0].MountPath)
+	assert.True(t, volumeMounts[0].ReadOnly)
+
+	// Custom Token Path
+	identityConfig.TokenPath = "/var/run/identity/kafka-token"
+	assert.Equal(t, "kafka-token", WorkloadIdentityVolumes(identityConfig)[0].Projected.Sources[0].ServiceAccountToken.Path)
+	assert.Equal(t, "/var/run/identity", WorkloadIdentityVolumeMounts(identityConfig)[0].MountPath)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Resolve The Image & Node Architecture Of A Deployment From The Specified Images Config
+//
+// The image class (if specified) takes precedence, followed by the image of the architecture, the default image of
+// the config, and finally the specified environment image.  The architecture defaults to that of the config, and
+// is returned so that the Deployment may be constrained to nodes of that architecture ("" if unconstrained).
+func ResolveImage(imagesConfig config.EKImagesConfig, environmentImage string, imageClass string, architecture string) (string, string, error) {
+
+	// Default The Architecture
+	if architecture == "" {
+		architecture = imagesConfig.Architecture
+	}
+
+	// An Image Class Must Be Configured
+	if imageClass != "" {
+		image, ok := imagesConfig.Classes[imageClass]
+		if !ok || image == "" {
+			return "", "", fmt.Errorf("unknown image class %q", imageClass)
+		}
+		return image, architecture, nil
+	}
+
+	// Architecture Specific Image (Otherwise The Image Is Assumed To Be Multi-Architecture)
+	if image := imagesConfig.Architectures[architecture]; architecture != "" && image != "" {
+		return image, architecture, nil
+	}
+
+	// Default Image
+	if imagesConfig.Default != "" {
+		return imagesConfig.Default, architecture, nil
+	}
+	return environmentImage, architecture, nil
+}
+
+// Create The NodeSelector Constraining A Deployment To The Specified Node Architecture (nil If Unconstrained)
+func ArchitectureNodeSelector(architecture string) map[string]string {
+	if architecture == "" {
+		return nil
+	}
+	return map[string]string{
+		corev1.LabelArchStable: architecture,
+	}
+}
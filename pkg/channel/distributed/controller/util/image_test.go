@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Test The ResolveImage() Functionality
+func TestResolveImage(t *testing.T) {
+
+	// Test Data
+	environmentImage := "environment-image"
+	imagesConfig := config.EKImagesConfig{
+		Default:       "default-image",
+		Architectures: map[string]string{"arm64": "arm64-image"},
+		Classes:       map[string]string{"canary": "canary-image"},
+	}
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		Name                 string
+		ImagesConfig         config.EKImagesConfig
+		ImageClass           string
+		Architecture         string
+		ExpectedImage        string
+		ExpectedArchitecture string
+		ExpectedErr          string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{Name: "Environment Image", ExpectedImage: environmentImage},
+		{Name: "Environment Image With Architecture", Architecture: "amd64", ExpectedImage: environmentImage, ExpectedArchitecture: "amd64"},
+		{Name: "Default Image", ImagesConfig: imagesConfig, ExpectedImage: "default-image"},
+		{Name: "Default Image Of Unmapped Architecture", ImagesConfig: imagesConfig, Architecture: "amd64", ExpectedImage: "default-image", ExpectedArchitecture: "amd64"},
+		{Name: "Architecture Image", ImagesConfig: imagesConfig, Architecture: "arm64", ExpectedImage: "arm64-image", ExpectedArchitecture: "arm64"},
+		{Name: "Default Architecture Image", ImagesConfig: config.EKImagesConfig{Architecture: "arm64", Architectures: imagesConfig.Architectures}, ExpectedImage: "arm64-image", ExpectedArchitecture: "arm64"},
+		{Name: "Class Image", ImagesConfig: imagesConfig, ImageClass: "canary", Architecture: "arm64", ExpectedImage: "canary-image", ExpectedArchitecture: "arm64"},
+		{Name: "Unknown Class", ImagesConfig: imagesConfig, ImageClass: "unknown", ExpectedErr: `unknown image class "unknown"`},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			image, architecture, err := ResolveImage(testCase.ImagesConfig, environmentImage, testCase.ImageClass, testCase.Architecture)
+			if testCase.ExpectedErr != "" {
+				assert.EqualError(t, err, testCase.ExpectedErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.ExpectedImage, image)
+			assert.Equal(t, testCase.ExpectedArchitecture, architecture)
+		})
+	}
+}
+
+// Test The ArchitectureNodeSelector() Functionality
+func TestArchitectureNodeSelector(t *testing.T) {
+	assert.Nil(t, ArchitectureNodeSelector(""))
+	assert.Equal(t, map[string]string{"kubernetes.io/arch": "arm64"}, ArchitectureNodeSelector("arm64"))
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	adminutil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/util"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/pkg/system"
+)
+
+// Get The Brokers & A Copy Of The Specified Sarama Config With The Controller's ClientId & Credentials, Authenticated
+// Via The KafkaAuthSpec If Specified, Otherwise The (Single) Kafka Secret, For Kafka Clients Created By The Controller
+func ControllerKafkaClientConfig(ctx context.Context, logger *zap.Logger, kubeClientset kubernetes.Interface, configuration *config.EventingKafkaConfig, saramaConfig *sarama.Config, podName string) ([]string, *sarama.Config, error) {
+
+	// Copy The Sarama Config So That The Client's Settings Are Not Shared
+	clientConfig := *saramaConfig
+
+	// Determine The ClientId Of The Controller
+	clientId, err := kafkasarama.NewClientId(configuration.Kafka, constants.ControllerComponentName, "", podName)
+	if err != nil {
+		logger.Error("Invalid Kafka ClientIdTemplate - Using Controller Component Name", zap.Error(err))
+		clientId = constants.ControllerComponentName
+	}
+
+	// Get The Brokers & Credentials From The KafkaAuthSpec If Specified
+	authSpec := configuration.Kafka.AuthSpec
+	if authSpec != nil {
+		kafkasarama.UpdateSaramaConfig(&clientConfig, clientId, "", "")
+		err = kafkasarama.UpdateSaramaAuthSpec(ctx, kubeClientset, system.Namespace(), &clientConfig, authSpec)
+		if err != nil {
+			return nil, nil, err
+		}
+		return authSpec.BootstrapServers, &clientConfig, nil
+	}
+
+	// Otherwise Get Them From The Kafka Secret
+	kafkaSecrets, err := adminutil.GetKafkaSecrets(ctx, kubeClientset, system.Namespace())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(kafkaSecrets.Items) != 1 {
+		return nil, nil, fmt.Errorf("expected 1 kafka secret but found %d", len(kafkaSecrets.Items))
+	}
+	kafkaSecret := kafkaSecrets.Items[0]
+	if !adminutil.ValidateKafkaSecret(logger, &kafkaSecret) {
+		return nil, nil, fmt.Errorf("invalid kafka secret %s", kafkaSecret.Name)
+	}
+	brokers := strings.Split(string(kafkaSecret.Data[kafkaconstants.KafkaSecretKeyBrokers]), ",")
+	username := string(kafkaSecret.Data[kafkaconstants.KafkaSecretKeyUsername])
+	password := string(kafkaSecret.Data[kafkaconstants.KafkaSecretKeyPassword])
+	kafkasarama.UpdateSaramaConfig(&clientConfig, clientId, username, password)
+	return brokers, &clientConfig, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
+)
+
+// Test The ControllerKafkaClientConfig() Functionality
+func TestControllerKafkaClientConfig(t *testing.T) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace))
+
+	// Test Data
+	logger := logtesting.TestLogger(t).Desugar()
+	saramaConfig := sarama.NewConfig()
+	configuration := &config.EventingKafkaConfig{}
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: commonconstants.KnativeEventingNamespace,
+				Labels:    map[string]string{kafkaconstants.KafkaSecretLabel: "true"},
+			},
+			Data: map[string][]byte{
+				kafkaconstants.KafkaSecretKeyBrokers:  []byte("broker1:9092,broker2:9092"),
+				kafkaconstants.KafkaSecretKeyUsername: []byte("TestUsername"),
+				kafkaconstants.KafkaSecretKeyPassword: []byte("TestPassword"),
+			},
+		}
+	}
+
+	// Verify The Brokers & Credentials Of The Kafka Secret (Without Modifying The Specified Sarama Config)
+	kubeClientset := fake.NewSimpleClientset(newSecret("kafka-secret"))
+	brokers, clientConfig, err := ControllerKafkaClientConfig(context.TODO(), logger, kubeClientset, configuration, saramaConfig, "TestPodName")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"broker1:9092", "broker2:9092"}, brokers)
+	assert.Equal(t, "TestUsername", clientConfig.Net.SASL.User)
+	assert.Empty(t, saramaConfig.Net.SASL.User)
+
+	// Verify Multiple Kafka Secrets Fail
+	kubeClientset = fake.NewSimpleClientset(newSecret("kafka-secret-1"), newSecret("kafka-secret-2"))
+	_, _, err = ControllerKafkaClientConfig(context.TODO(), logger, kubeClientset, configuration, saramaConfig, "TestPodName")
+	assert.NotNil(t, err)
+}
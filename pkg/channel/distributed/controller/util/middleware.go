@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Create The ConfigMap Volumes Of The Middleware Modules (nil Unless Middleware Is Enabled)
+func MiddlewareVolumes(middlewareConfig config.EKMiddlewareConfig) []corev1.Volume {
+	if !middlewareConfig.Enabled {
+		return nil
+	}
+	return []corev1.Volume{
+		{
+			Name: constants.MiddlewareVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: middlewareConfig.ConfigMapName},
+				},
+			},
+		},
+	}
+}
+
+// Create The ConfigMap VolumeMounts Of The Middleware Modules At The Registry's Default Path (nil Unless Middleware Is Enabled)
+func MiddlewareVolumeMounts(middlewareConfig config.EKMiddlewareConfig) []corev1.VolumeMount {
+	if !middlewareConfig.Enabled {
+		return nil
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      constants.MiddlewareVolumeName,
+			MountPath: middleware.DefaultModulesPath,
+			ReadOnly:  true,
+		},
+	}
+}
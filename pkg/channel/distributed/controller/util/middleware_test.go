@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Test The MiddlewareVolumes() & MiddlewareVolumeMounts() Functionality
+func TestMiddlewareVolumes(t *testing.T) {
+
+	// Disabled Middleware Has No Volumes
+	assert.Nil(t, MiddlewareVolumes(config.EKMiddlewareConfig{ConfigMapName: "modules"}))
+	assert.Nil(t, MiddlewareVolumeMounts(config.EKMiddlewareConfig{ConfigMapName: "modules"}))
+
+	// Enabled Middleware Mounts The ConfigMap At The Default Modules Path
+	middlewareConfig := config.EKMiddlewareConfig{Enabled: true, ConfigMapName: "modules"}
+	volumes := MiddlewareVolumes(middlewareConfig)
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, constants.MiddlewareVolumeName, volumes[0].Name)
+	assert.Equal(t, "modules", volumes[0].ConfigMap.Name)
+	volumeMounts := MiddlewareVolumeMounts(middlewareConfig)
+	assert.Len(t, volumeMounts, 1)
+	assert.Equal(t, constants.MiddlewareVolumeName, volumeMounts[0].Name)
+	assert.Equal(t, middleware.DefaultModulesPath, volumeMounts[0].MountPath)
+	assert.True(t, volumeMounts[0].ReadOnly)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// The Strategies For Generating DNS Safe Resource Names (e.g. The Dispatcher Deployment & Service Of A KafkaChannel)
+const (
+	// NameStrategyTruncate Truncates Each Name Part To Its MaxLength & Appends An 8 Character MD5 Hash Of The
+	// Concatenated Parts (The Default, Compatible With The Names Of Existing Resources)
+	NameStrategyTruncate = "truncate"
+
+	// NameStrategyHash Appends A 16 Character SHA-256 Hash Of The Unambiguously Joined Parts, Truncating The Readable
+	// Portion Only As Far As Needed To Fit The 63 Character Limit (Collision Resistant)
+	NameStrategyHash = "hash"
+)
+
+// The Max Length Of A DNS Label (e.g. A K8S Service Name)
+const maxDnsNameLength = 63
+
+// NamePart Is A Component Of A Generated Name (e.g. The KafkaChannel Name) Along With The Length It Is Truncated To
+// By The "truncate" NameStrategy
+type NamePart struct {
+	Value     string
+	MaxLength int
+}
+
+// Utility Function For Determining Whether The Specified Name Strategy Is Supported (Empty Is The Default)
+func IsValidNameStrategy(strategy string) bool {
+	switch strategy {
+	case "", NameStrategyTruncate, NameStrategyHash:
+		return true
+	default:
+		return false
+	}
+}
+
+// Utility Function For Generating A DNS Safe Name From The Specified Parts & Suffix Using The Specified Strategy
+// (Defaulting To The "truncate" Strategy), Shared By All Generated Deployment & Service Names
+func DnsSafeName(strategy string, suffix string, parts ...NamePart) string {
+	if strategy == NameStrategyHash {
+		return hashedDnsSafeName(suffix, parts)
+	}
+	return truncatedDnsSafeName(suffix, parts)
+}
+
+// The "truncate" Strategy - Each Part Is Truncated Separately, Then Hashed Together Without A Separator
+func truncatedDnsSafeName(suffix string, parts []NamePart) string {
+	safeParts := make([]string, 0, len(parts)+2)
+	var hashInput strings.Builder
+	for index, part := range parts {
+		safeParts = append(safeParts, GenerateValidDnsName(part.Value, part.MaxLength, index == 0, false))
+		hashInput.WriteString(part.Value)
+	}
+	safeParts = append(safeParts, GenerateHash(hashInput.String(), 8), suffix)
+	return strings.Join(safeParts, "-")
+}
+
+// The "hash" Strategy - The Parts Are Joined With "/" (Which K8S Names Cannot Contain) Before Hashing, So That
+// Distinct Parts Never Produce The Same Hash Input, And The Readable Portion Is Truncated To The Remaining Length
+func hashedDnsSafeName(suffix string, parts []NamePart) string {
+	values := make([]string, len(parts))
+	for index, part := range parts {
+		values[index] = part.Value
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(values, "/"))))[:16]
+
+	// Reserve Room For The Hash & Suffix (And Their Separators), Leaving The Rest To The Readable Portion
+	readableLength := maxDnsNameLength - len(hash) - len(suffix) - 2
+	readable := GenerateValidDnsName(strings.Join(values, "-"), readableLength, true, false)
+	readable = strings.TrimRight(readable, "-")
+	return fmt.Sprintf("%s-%s-%s", readable, hash, suffix)
+}
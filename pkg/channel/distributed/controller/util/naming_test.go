@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test The IsValidNameStrategy() Functionality
+func TestIsValidNameStrategy(t *testing.T) {
+	assert.True(t, IsValidNameStrategy(""))
+	assert.True(t, IsValidNameStrategy(NameStrategyTruncate))
+	assert.True(t, IsValidNameStrategy(NameStrategyHash))
+	assert.False(t, IsValidNameStrategy("unknown"))
+}
+
+// Test The DnsSafeName() Functionality
+func TestDnsSafeName(t *testing.T) {
+
+	// The Default Strategy Is "truncate"
+	parts := []NamePart{{Value: "Name", MaxLength: 2}, {Value: "1namespace", MaxLength: 4}}
+	assert.Equal(t, "kk-1-"+GenerateHash("1namespace", 8)+"-suffix", DnsSafeName("", "suffix", parts[1:]...))
+	assert.Equal(t, "na-1nam-"+GenerateHash("Name1namespace", 8)+"-suffix", DnsSafeName(NameStrategyTruncate, "suffix", parts...))
+
+	// The "hash" Strategy Ignores The MaxLengths, Only Truncating To Fit 63 Characters
+	assert.Regexp(t, "^name-1namespace-[0-9a-f]{16}-suffix$", DnsSafeName(NameStrategyHash, "suffix", parts...))
+	longParts := []NamePart{{Value: "kubernetes-maximum-length-of-channel-name-is-sixty-three-chars", MaxLength: 26}}
+	longName := DnsSafeName(NameStrategyHash, "suffix", longParts...)
+	assert.Len(t, longName, 63)
+	assert.Regexp(t, "^kubernetes-maximum-length-of-channel-na-[0-9a-f]{16}-suffix$", longName)
+
+	// Parts Are Hashed Unambiguously
+	assert.NotEqual(t,
+		DnsSafeName(NameStrategyHash, "suffix", NamePart{Value: "ab"}, NamePart{Value: "c"}),
+		DnsSafeName(NameStrategyHash, "suffix", NamePart{Value: "a"}, NamePart{Value: "bc"}))
+}
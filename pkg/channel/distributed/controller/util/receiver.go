@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// The Isolation Modes Of The Receivers
+const (
+	// ReceiverIsolationSecret Shares One Receiver Between All The KafkaChannels Of A Kafka Secret (The Default)
+	ReceiverIsolationSecret = "secret"
+
+	// ReceiverIsolationChannel Runs A Dedicated Receiver For Each KafkaChannel
+	ReceiverIsolationChannel = "channel"
+
+	// ReceiverIsolationCombined Runs The Dedicated Receiver Of Each KafkaChannel In The Pod Of Its Dispatcher
+	ReceiverIsolationCombined = "combined"
+)
+
+// Utility Function For Determining Whether The Specified Receiver Isolation Is Supported (Empty Is The Default)
+func IsValidReceiverIsolation(isolation string) bool {
+	switch isolation {
+	case "", ReceiverIsolationSecret, ReceiverIsolationChannel, ReceiverIsolationCombined:
+		return true
+	default:
+		return false
+	}
+}
+
+// Get The Receiver Isolation Of The Specified KafkaChannel, Either Its Valid Isolation Annotation Or (Absent One) The
+// Specified Configured Isolation
+func ReceiverIsolation(channel *kafkav1beta1.KafkaChannel, isolation string) string {
+	if annotation := channel.Annotations[kafkaconstants.ReceiverIsolationAnnotation]; annotation != "" && IsValidReceiverIsolation(annotation) {
+		return annotation
+	}
+	return isolation
+}
+
+// Determine Whether The Specified KafkaChannel Has A Dedicated Receiver (Whether Or Not Combined With Its Dispatcher)
+func IsReceiverIsolated(channel *kafkav1beta1.KafkaChannel, isolation string) bool {
+	switch ReceiverIsolation(channel, isolation) {
+	case ReceiverIsolationChannel, ReceiverIsolationCombined:
+		return true
+	default:
+		return false
+	}
+}
+
+// Determine Whether The Specified KafkaChannel's Dedicated Receiver Runs In The Pod Of Its Dispatcher
+func IsReceiverCombined(channel *kafkav1beta1.KafkaChannel, isolation string) bool {
+	return ReceiverIsolation(channel, isolation) == ReceiverIsolationCombined
+}
+
+// Create A DNS Safe Name For The Dedicated Receiver Of The Specified KafkaChannel Suitable For Use With K8S Services,
+// Using The Specified NameStrategy (The Service & Deployment Of A Dedicated Receiver Share This Name)
+func ChannelReceiverDnsSafeName(channel *kafkav1beta1.KafkaChannel, strategy string) string {
+
+	// Same allocations as the Dispatcher, whose suffix is two characters longer.
+	return DnsSafeName(strategy, kafkaconstants.ReceiverServiceSuffix,
+		NamePart{Value: channel.Name, MaxLength: 26},
+		NamePart{Value: channel.Namespace, MaxLength: 16})
+}
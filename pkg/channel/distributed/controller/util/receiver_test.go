@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Test The IsValidReceiverIsolation() Functionality
+func TestIsValidReceiverIsolation(t *testing.T) {
+	assert.True(t, IsValidReceiverIsolation(""))
+	assert.True(t, IsValidReceiverIsolation(ReceiverIsolationSecret))
+	assert.True(t, IsValidReceiverIsolation(ReceiverIsolationChannel))
+	assert.True(t, IsValidReceiverIsolation(ReceiverIsolationCombined))
+	assert.False(t, IsValidReceiverIsolation("namespace"))
+}
+
+// Test The IsReceiverIsolated() Functionality
+func TestIsReceiverIsolated(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		Name             string
+		Annotation       string
+		Isolation        string
+		Expected         bool
+		ExpectedCombined bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{Name: "Default", Expected: false},
+		{Name: "Configured Secret", Isolation: ReceiverIsolationSecret, Expected: false},
+		{Name: "Configured Channel", Isolation: ReceiverIsolationChannel, Expected: true},
+		{Name: "Annotated Channel", Annotation: ReceiverIsolationChannel, Isolation: ReceiverIsolationSecret, Expected: true},
+		{Name: "Annotated Secret", Annotation: ReceiverIsolationSecret, Isolation: ReceiverIsolationChannel, Expected: false},
+		{Name: "Invalid Annotation", Annotation: "namespace", Isolation: ReceiverIsolationChannel, Expected: true},
+		{Name: "Configured Combined", Isolation: ReceiverIsolationCombined, Expected: true, ExpectedCombined: true},
+		{Name: "Annotated Combined", Annotation: ReceiverIsolationCombined, Isolation: ReceiverIsolationChannel, Expected: true, ExpectedCombined: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			channel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: channelName, Namespace: channelNamespace}}
+			if testCase.Annotation != "" {
+				channel.Annotations = map[string]string{kafkaconstants.ReceiverIsolationAnnotation: testCase.Annotation}
+			}
+			assert.Equal(t, testCase.Expected, IsReceiverIsolated(channel, testCase.Isolation))
+			assert.Equal(t, testCase.ExpectedCombined, IsReceiverCombined(channel, testCase.Isolation))
+		})
+	}
+}
+
+// Test The ChannelReceiverDnsSafeName() Functionality
+func TestChannelReceiverDnsSafeName(t *testing.T) {
+
+	// Test Data
+	name := "kubernetes-maximum-length-of-channel-name-is-sixty-three-chars"
+	namespace := "kubernetes-maximum-length-for-namespace-with-sixty-three-chars"
+	channel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+
+	// Perform The Test
+	actualResult := ChannelReceiverDnsSafeName(channel, NameStrategyTruncate)
+
+	// Verify The Results
+	expectedResult := fmt.Sprintf("%.26s-%.16s-%s-receiver", name, namespace, GenerateHash(name+namespace, 8))
+	assert.Equal(t, expectedResult, actualResult)
+	assert.NotEqual(t, DispatcherDnsSafeName(channel, NameStrategyTruncate), actualResult)
+	assert.LessOrEqual(t, len(ChannelReceiverDnsSafeName(channel, NameStrategyHash)), maxDnsNameLength)
+}
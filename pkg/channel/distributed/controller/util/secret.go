@@ -17,10 +17,14 @@ limitations under the License.
 package util
 
 import (
+	"strings"
+
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 )
 
@@ -49,3 +53,83 @@ func NewSecretOwnerReference(secret *corev1.Secret) metav1.OwnerReference {
 		Controller:         &controller,
 	}
 }
+
+//
+// Create The Kafka Brokers / Username / Password EnvVars Of The Receiver & Dispatcher Deployments
+//
+// The values are referenced from the data of the specified Kafka Secret, unless a KafkaAuthSpec is configured, in
+// which case the Brokers are its BootstrapServers and the Receiver / Dispatcher apply its SASL & TLS settings from
+// the referenced Secrets themselves (so there is no Username / Password).
+//
+func KafkaSecretEnvVars(secretName string, authSpec *bindingsv1beta1.KafkaAuthSpec) []corev1.EnvVar {
+	if authSpec != nil {
+		return []corev1.EnvVar{
+			{
+				Name:  commonenv.KafkaBrokerEnvVarKey,
+				Value: strings.Join(authSpec.BootstrapServers, ","),
+			},
+		}
+	}
+	return []corev1.EnvVar{
+		{
+			Name:      commonenv.KafkaBrokerEnvVarKey,
+			ValueFrom: secretKeyRefEnvVarSource(secretName, constants.KafkaSecretDataKeyBrokers),
+		},
+		{
+			Name:      commonenv.KafkaUsernameEnvVarKey,
+			ValueFrom: secretKeyRefEnvVarSource(secretName, constants.KafkaSecretDataKeyUsername),
+		},
+		{
+			Name:      commonenv.KafkaPasswordEnvVarKey,
+			ValueFrom: secretKeyRefEnvVarSource(secretName, constants.KafkaSecretDataKeyPassword),
+		},
+	}
+}
+
+// Get The EnvVars Of The Remote Kafka Cluster Of The KafkaChannel's Replication From The Specified Secret (Credentials Optional)
+func ReplicationSecretEnvVars(secretName string) []corev1.EnvVar {
+	envVars := []corev1.EnvVar{
+		{
+			Name:      commonenv.ReplicationKafkaBrokerEnvVarKey,
+			ValueFrom: secretKeyRefEnvVarSource(secretName, constants.KafkaSecretDataKeyBrokers),
+		},
+	}
+	for _, envVar := range []struct{ name, key string }{
+		{commonenv.ReplicationKafkaUsernameEnvVarKey, constants.KafkaSecretDataKeyUsername},
+		{commonenv.ReplicationKafkaPasswordEnvVarKey, constants.KafkaSecretDataKeyPassword},
+	} {
+		envVarSource := secretKeyRefEnvVarSource(secretName, envVar.key)
+		optional := true
+		envVarSource.SecretKeyRef.Optional = &optional
+		envVars = append(envVars, corev1.EnvVar{Name: envVar.name, ValueFrom: envVarSource})
+	}
+	return envVars
+}
+
+// Get The EnvVars Of The Schema Registry Credentials From The Specified Secret (Only The Keys Of Its Auth Mode Are Required)
+func SchemaRegistrySecretEnvVars(secretName string) []corev1.EnvVar {
+	envVars := make([]corev1.EnvVar, 0, 5)
+	for _, envVar := range []struct{ name, key string }{
+		{commonenv.SchemaRegistryUsernameEnvVarKey, constants.SchemaRegistrySecretDataKeyUsername},
+		{commonenv.SchemaRegistryPasswordEnvVarKey, constants.SchemaRegistrySecretDataKeyPassword},
+		{commonenv.SchemaRegistryTokenEnvVarKey, constants.SchemaRegistrySecretDataKeyToken},
+		{commonenv.SchemaRegistryClientIdEnvVarKey, constants.SchemaRegistrySecretDataKeyClientId},
+		{commonenv.SchemaRegistryClientSecretEnvVarKey, constants.SchemaRegistrySecretDataKeyClientSecret},
+	} {
+		envVarSource := secretKeyRefEnvVarSource(secretName, envVar.key)
+		optional := true
+		envVarSource.SecretKeyRef.Optional = &optional
+		envVars = append(envVars, corev1.EnvVar{Name: envVar.name, ValueFrom: envVarSource})
+	}
+	return envVars
+}
+
+// Create An EnvVarSource Referencing The Specified Key Of The Specified Secret
+func secretKeyRefEnvVarSource(secretName string, key string) *corev1.EnvVarSource {
+	return &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			Key:                  key,
+		},
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	logtesting "knative.dev/pkg/logging/testing"
 )
@@ -64,3 +66,75 @@ func TestNewSecretOwnerReference(t *testing.T) {
 	assert.True(t, *controllerRef.BlockOwnerDeletion)
 	assert.True(t, *controllerRef.Controller)
 }
+
+// Test The KafkaSecretEnvVars() Functionality
+func TestKafkaSecretEnvVars(t *testing.T) {
+
+	// Test Data
+	const secretName = "TestSecretName"
+
+	// Without A KafkaAuthSpec The EnvVars Reference The Kafka Secret's Data
+	envVars := KafkaSecretEnvVars(secretName, nil)
+	assert.Len(t, envVars, 3)
+	for index, expected := range []struct{ name, key string }{
+		{commonenv.KafkaBrokerEnvVarKey, constants.KafkaSecretDataKeyBrokers},
+		{commonenv.KafkaUsernameEnvVarKey, constants.KafkaSecretDataKeyUsername},
+		{commonenv.KafkaPasswordEnvVarKey, constants.KafkaSecretDataKeyPassword},
+	} {
+		assert.Equal(t, expected.name, envVars[index].Name)
+		assert.Equal(t, secretName, envVars[index].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, expected.key, envVars[index].ValueFrom.SecretKeyRef.Key)
+	}
+
+	// With A KafkaAuthSpec Only The Brokers Are Provided (From Its BootstrapServers)
+	authSpec := &bindingsv1beta1.KafkaAuthSpec{BootstrapServers: []string{"TestBroker1:9092", "TestBroker2:9092"}}
+	envVars = KafkaSecretEnvVars(secretName, authSpec)
+	assert.Equal(t, []corev1.EnvVar{{Name: commonenv.KafkaBrokerEnvVarKey, Value: "TestBroker1:9092,TestBroker2:9092"}}, envVars)
+}
+
+// Test The SchemaRegistrySecretEnvVars() Functionality
+func TestSchemaRegistrySecretEnvVars(t *testing.T) {
+
+	// Test Data
+	const secretName = "TestSchemaRegistrySecretName"
+
+	// The EnvVars Optionally Reference Each Of The Secret's Credentials
+	envVars := SchemaRegistrySecretEnvVars(secretName)
+	assert.Len(t, envVars, 5)
+	for index, expected := range []struct{ name, key string }{
+		{commonenv.SchemaRegistryUsernameEnvVarKey, constants.SchemaRegistrySecretDataKeyUsername},
+		{commonenv.SchemaRegistryPasswordEnvVarKey, constants.SchemaRegistrySecretDataKeyPassword},
+		{commonenv.SchemaRegistryTokenEnvVarKey, constants.SchemaRegistrySecretDataKeyToken},
+		{commonenv.SchemaRegistryClientIdEnvVarKey, constants.SchemaRegistrySecretDataKeyClientId},
+		{commonenv.SchemaRegistryClientSecretEnvVarKey, constants.SchemaRegistrySecretDataKeyClientSecret},
+	} {
+		assert.Equal(t, expected.name, envVars[index].Name)
+		assert.Equal(t, secretName, envVars[index].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, expected.key, envVars[index].ValueFrom.SecretKeyRef.Key)
+		assert.True(t, *envVars[index].ValueFrom.SecretKeyRef.Optional)
+	}
+}
+
+// Test The ReplicationSecretEnvVars() Functionality
+func TestReplicationSecretEnvVars(t *testing.T) {
+
+	// Test Data
+	const secretName = "TestReplicationSecretName"
+
+	// The EnvVars Reference The Secret's Brokers & Optionally Its Credentials
+	envVars := ReplicationSecretEnvVars(secretName)
+	assert.Len(t, envVars, 3)
+	for index, expected := range []struct {
+		name, key string
+		optional  bool
+	}{
+		{commonenv.ReplicationKafkaBrokerEnvVarKey, constants.KafkaSecretDataKeyBrokers, false},
+		{commonenv.ReplicationKafkaUsernameEnvVarKey, constants.KafkaSecretDataKeyUsername, true},
+		{commonenv.ReplicationKafkaPasswordEnvVarKey, constants.KafkaSecretDataKeyPassword, true},
+	} {
+		assert.Equal(t, expected.name, envVars[index].Name)
+		assert.Equal(t, secretName, envVars[index].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, expected.key, envVars[index].ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, expected.optional, envVars[index].ValueFrom.SecretKeyRef.Optional != nil && *envVars[index].ValueFrom.SecretKeyRef.Optional)
+	}
+}
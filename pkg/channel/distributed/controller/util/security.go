@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Create The Pod SecurityContext For The Specified Kubernetes Config (Defaults To Running As Non-Root)
+func PodSecurityContext(kubernetesConfig config.EKKubernetesConfig) *corev1.PodSecurityContext {
+	if kubernetesConfig.PodSecurityContext != nil {
+		return kubernetesConfig.PodSecurityContext.DeepCopy()
+	}
+	runAsNonRoot := true
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot: &runAsNonRoot,
+	}
+}
+
+// Create The Container SecurityContext For The Specified Kubernetes Config (Defaults To The Restricted Pod Security Standard)
+func ContainerSecurityContext(kubernetesConfig config.EKKubernetesConfig) *corev1.SecurityContext {
+	if kubernetesConfig.SecurityContext != nil {
+		return kubernetesConfig.SecurityContext.DeepCopy()
+	}
+	allowPrivilegeEscalation := false
+	readOnlyRootFilesystem := true
+	runAsNonRoot := true
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		RunAsNonRoot:             &runAsNonRoot,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
+// Create The Pod Annotations Specifying The Seccomp Profile Of The Specified Kubernetes Config (Defaults To "runtime/default")
+func SeccompPodAnnotations(kubernetesConfig config.EKKubernetesConfig) map[string]string {
+	seccompProfile := kubernetesConfig.SeccompProfile
+	if seccompProfile == "" {
+		seccompProfile = constants.DefaultSeccompProfile
+	}
+	return map[string]string{
+		constants.SeccompPodAnnotation: seccompProfile,
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Test The PodSecurityContext() Functionality
+func TestPodSecurityContext(t *testing.T) {
+
+	// Verify The Secure Default
+	podSecurityContext := PodSecurityContext(config.EKKubernetesConfig{})
+	assert.NotNil(t, podSecurityContext)
+	assert.True(t, *podSecurityContext.RunAsNonRoot)
+
+	// Verify A Configured PodSecurityContext Is Used As-Is (But Copied)
+	runAsUser := int64(1000)
+	kubernetesConfig := config.EKKubernetesConfig{PodSecurityContext: &corev1.PodSecurityContext{RunAsUser: &runAsUser}}
+	podSecurityContext = PodSecurityContext(kubernetesConfig)
+	assert.Equal(t, kubernetesConfig.PodSecurityContext, podSecurityContext)
+	assert.NotSame(t, kubernetesConfig.PodSecurityContext, podSecurityContext)
+	assert.Nil(t, podSecurityContext.RunAsNonRoot)
+}
+
+// Test The ContainerSecurityContext() Functionality
+func TestContainerSecurityContext(t *testing.T) {
+
+	// Verify The Secure Default
+	securityContext := ContainerSecurityContext(config.EKKubernetesConfig{})
+	assert.NotNil(t, securityContext)
+	assert.False(t, *securityContext.AllowPrivilegeEscalation)
+	assert.True(t, *securityContext.ReadOnlyRootFilesystem)
+	assert.True(t, *securityContext.RunAsNonRoot)
+	assert.Equal(t, []corev1.Capability{"ALL"}, securityContext.Capabilities.Drop)
+
+	// Verify A Configured SecurityContext Is Used As-Is (But Copied)
+	readOnlyRootFilesystem := false
+	kubernetesConfig := config.EKKubernetesConfig{SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnlyRootFilesystem}}
+	securityContext = ContainerSecurityContext(kubernetesConfig)
+	assert.Equal(t, kubernetesConfig.SecurityContext, securityContext)
+	assert.NotSame(t, kubernetesConfig.SecurityContext, securityContext)
+	assert.Nil(t, securityContext.Capabilities)
+}
+
+// Test The SeccompPodAnnotations() Functionality
+func TestSeccompPodAnnotations(t *testing.T) {
+	assert.Equal(t, map[string]string{constants.SeccompPodAnnotation: "runtime/default"}, SeccompPodAnnotations(config.EKKubernetesConfig{}))
+	assert.Equal(t, map[string]string{constants.SeccompPodAnnotation: "localhost/profile.json"}, SeccompPodAnnotations(config.EKKubernetesConfig{SeccompProfile: "localhost/profile.json"}))
+}
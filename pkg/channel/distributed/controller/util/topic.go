@@ -21,7 +21,10 @@ import (
 	commonkafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
 )
 
-// Get The TopicName For Specified KafkaChannel (ChannelNamespace.ChannelName)
+// Get The TopicName For Specified KafkaChannel (ChannelNamespace.ChannelName Unless Overridden By The Topic Annotation)
 func TopicName(channel *kafkav1beta1.KafkaChannel) string {
+	if topicName := channel.Annotations[kafkav1beta1.TopicAnnotation]; topicName != "" {
+		return topicName
+	}
 	return commonkafkautil.TopicName(channel.Namespace, channel.Name)
 }
@@ -45,3 +45,15 @@ func TestTopicName(t *testing.T) {
 	expectedTopicName := channelNamespace + "." + channelName
 	assert.Equal(t, expectedTopicName, actualTopicName)
 }
+
+// Test The TopicName() Functionality With The Topic Annotation
+func TestTopicNameAnnotation(t *testing.T) {
+	channel := &kafkav1beta1.KafkaChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "TestChannelName",
+			Namespace:   "TestChannelNamespace",
+			Annotations: map[string]string{kafkav1beta1.TopicAnnotation: "knative-messaging-kafka.TestChannelNamespace.TestChannelName"},
+		},
+	}
+	assert.Equal(t, "knative-messaging-kafka.TestChannelNamespace.TestChannelName", TopicName(channel))
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/pkg/system"
+)
+
+// The VerticalPodAutoscaler Resource (Managed Via The Dynamic Client As The VPA Types Are Not A Dependency)
+var VerticalPodAutoscalerGVR = schema.GroupVersionResource{
+	Group:    "autoscaling.k8s.io",
+	Version:  "v1",
+	Resource: "verticalpodautoscalers",
+}
+
+// The Update Modes Of The VerticalPodAutoscalers
+const (
+	// VPAUpdateModeOff Only Records The Recommendations Of The VerticalPodAutoscaler (The Default)
+	VPAUpdateModeOff = "Off"
+
+	// VPAUpdateModeInitial Applies The Recommendations When Pods Are Created
+	VPAUpdateModeInitial = "Initial"
+
+	// VPAUpdateModeRecreate Applies The Recommendations By Evicting & Recreating Pods
+	VPAUpdateModeRecreate = "Recreate"
+
+	// VPAUpdateModeAuto Applies The Recommendations Using The Best Available Mechanism (Currently Recreate)
+	VPAUpdateModeAuto = "Auto"
+)
+
+// Utility Function For Determining Whether The Specified VPA Update Mode Is Supported (Empty Is The Default)
+func IsValidVPAUpdateMode(updateMode string) bool {
+	switch updateMode {
+	case "", VPAUpdateModeOff, VPAUpdateModeInitial, VPAUpdateModeRecreate, VPAUpdateModeAuto:
+		return true
+	default:
+		return false
+	}
+}
+
+// Utility Function For Getting The VPA Update Mode Of The Specified Configuration (Defaulting To Off)
+func VPAUpdateMode(updateMode string) string {
+	if len(updateMode) == 0 {
+		return VPAUpdateModeOff
+	}
+	return updateMode
+}
+
+// Create The VerticalPodAutoscaler Model Of The Specified KafkaChannel's Dispatcher Deployment
+func NewDispatcherVerticalPodAutoscaler(channel *kafkav1beta1.KafkaChannel, deploymentName string, updateMode string) *unstructured.Unstructured {
+	vpa := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"targetRef": map[string]interface{}{
+					"apiVersion": appsv1.SchemeGroupVersion.String(),
+					"kind":       constants.DeploymentKind,
+					"name":       deploymentName,
+				},
+				"updatePolicy": map[string]interface{}{
+					"updateMode": VPAUpdateMode(updateMode),
+				},
+			},
+		},
+	}
+	vpa.SetAPIVersion(VerticalPodAutoscalerGVR.GroupVersion().String())
+	vpa.SetKind("VerticalPodAutoscaler")
+	vpa.SetName(deploymentName)
+	vpa.SetNamespace(system.Namespace())
+	vpa.SetLabels(map[string]string{
+		constants.KafkaChannelDispatcherLabel: "true",            // Identifies the VPA as being that of a KafkaChannel "Dispatcher"
+		constants.KafkaChannelNameLabel:       channel.Name,      // Identifies the VPA's Owning KafkaChannel's Name
+		constants.KafkaChannelNamespaceLabel:  channel.Namespace, // Identifies the VPA's Owning KafkaChannel's Namespace
+	})
+	vpa.SetOwnerReferences([]metav1.OwnerReference{NewChannelOwnerReference(channel)})
+	return vpa
+}
+
+// Utility Function For Getting The Update Mode Of The Specified VerticalPodAutoscaler
+func VerticalPodAutoscalerUpdateMode(vpa *unstructured.Unstructured) string {
+	updateMode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+	return updateMode
+}
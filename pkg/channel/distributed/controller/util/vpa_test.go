@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Test The IsValidVPAUpdateMode() Functionality
+func TestIsValidVPAUpdateMode(t *testing.T) {
+	assert.True(t, IsValidVPAUpdateMode(""))
+	assert.True(t, IsValidVPAUpdateMode(VPAUpdateModeOff))
+	assert.True(t, IsValidVPAUpdateMode(VPAUpdateModeInitial))
+	assert.True(t, IsValidVPAUpdateMode(VPAUpdateModeRecreate))
+	assert.True(t, IsValidVPAUpdateMode(VPAUpdateModeAuto))
+	assert.False(t, IsValidVPAUpdateMode("auto"))
+}
+
+// Test The NewDispatcherVerticalPodAutoscaler() Functionality
+func TestNewDispatcherVerticalPodAutoscaler(t *testing.T) {
+	channel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-name"}}
+
+	vpa := NewDispatcherVerticalPodAutoscaler(channel, "test-dispatcher", "")
+	assert.Equal(t, "autoscaling.k8s.io/v1", vpa.GetAPIVersion())
+	assert.Equal(t, "VerticalPodAutoscaler", vpa.GetKind())
+	assert.Equal(t, "test-dispatcher", vpa.GetName())
+	assert.Equal(t, commonconstants.KnativeEventingNamespace, vpa.GetNamespace())
+	assert.Equal(t, "test-name", vpa.GetLabels()[constants.KafkaChannelNameLabel])
+	assert.Equal(t, "test-namespace", vpa.GetLabels()[constants.KafkaChannelNamespaceLabel])
+	assert.Equal(t, []metav1.OwnerReference{NewChannelOwnerReference(channel)}, vpa.GetOwnerReferences())
+	targetName, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+	assert.Equal(t, "test-dispatcher", targetName)
+	assert.Equal(t, VPAUpdateModeOff, VerticalPodAutoscalerUpdateMode(vpa))
+
+	vpa = NewDispatcherVerticalPodAutoscaler(channel, "test-dispatcher", VPAUpdateModeAuto)
+	assert.Equal(t, VPAUpdateModeAuto, VerticalPodAutoscalerUpdateMode(vpa))
+}
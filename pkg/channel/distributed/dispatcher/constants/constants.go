@@ -16,7 +16,20 @@ limitations under the License.
 
 package constants
 
+import "time"
+
 // Global Constants
 const (
 	Component = "eventing-kafka-channel-dispatcher"
+
+	// Knative Eventing Data Plane Contract Headers
+	PreferHeader      = "Prefer"
+	PreferReplyHeader = "reply"
+
+	// The Interval At Which The Delivery Auth Secrets Referenced By Subscriptions Are Re-Read (Picking Up Rotated Credentials)
+	// And The OIDC Tokens Of Their Audiences Checked For Rotation
+	DeliveryAuthRefreshInterval = 5 * time.Minute
+
+	// The Default Name Of The ServiceAccount For Which OIDC Tokens Are Minted (The Dispatcher's Own)
+	DefaultOIDCServiceAccount = "eventing-kafka-channel-controller"
 )
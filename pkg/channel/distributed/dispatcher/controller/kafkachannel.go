@@ -20,23 +20,34 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/dispatcher"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/snapshot"
 	"knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	"knative.dev/eventing-kafka/pkg/client/clientset/versioned/scheme"
 	informers "knative.dev/eventing-kafka/pkg/client/informers/externalversions/messaging/v1beta1"
 	listers "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	messaginginformers "knative.dev/eventing/pkg/client/informers/externalversions/messaging/v1"
+	messaginglisters "knative.dev/eventing/pkg/client/listers/messaging/v1"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 )
@@ -46,11 +57,16 @@ const (
 	ReconcilerName = "KafkaChannels"
 
 	// corev1.Events emitted
-	channelReconciled         = "ChannelReconciled"
-	channelReconcileFailed    = "ChannelReconcileFailed"
-	channelUpdateStatusFailed = "ChannelUpdateStatusFailed"
+	channelReconciled                = "ChannelReconciled"
+	channelReconcileFailed           = "ChannelReconcileFailed"
+	channelUpdateStatusFailed        = "ChannelUpdateStatusFailed"
+	subscriberDestinationCheckFailed = "SubscriberDestinationCheckFailed"
+	subscriberAccessDenied           = "SubscriberAccessDenied"
 )
 
+// The Reason Of The KafkaChannel's SubscribersHealthy Condition While Subscribers Are Paused
+const SubscriberOutageReason = "SubscriberOutage"
+
 // Reconciler reconciles KafkaChannels.
 type Reconciler struct {
 	logger               *zap.Logger
@@ -58,9 +74,16 @@ type Reconciler struct {
 	dispatcher           dispatcher.Dispatcher
 	kafkachannelInformer cache.SharedIndexInformer
 	kafkachannelLister   listers.KafkaChannelLister
+	subscriptionLister   messaginglisters.SubscriptionLister
 	impl                 *controller.Impl
 	recorder             record.EventRecorder
 	kafkaClientSet       versioned.Interface
+	kubeClient           kubernetes.Interface
+	snapshotStore        *snapshot.Store
+	subscriberHealth     *dispatcher.SubscriberHealth
+	destinationCheck     *dispatcher.DestinationCheck
+	oidcTokens           *dispatcher.OIDCTokens
+	accessControl        *dispatcher.AccessControl
 }
 
 var _ controller.Reconciler = Reconciler{}
@@ -72,8 +95,14 @@ func NewController(
 	channelKey string,
 	dispatcher dispatcher.Dispatcher,
 	kafkachannelInformer informers.KafkaChannelInformer,
+	subscriptionInformer messaginginformers.SubscriptionInformer,
 	kubeClient kubernetes.Interface,
 	kafkaClientSet versioned.Interface,
+	snapshotStore *snapshot.Store,
+	subscriberHealth *dispatcher.SubscriberHealth,
+	destinationCheck *dispatcher.DestinationCheck,
+	oidcTokens *dispatcher.OIDCTokens,
+	accessControl *dispatcher.AccessControl,
 	stopChannel <-chan struct{},
 ) *controller.Impl {
 
@@ -84,6 +113,12 @@ func NewController(
 		kafkachannelInformer: kafkachannelInformer.Informer(),
 		kafkachannelLister:   kafkachannelInformer.Lister(),
 		kafkaClientSet:       kafkaClientSet,
+		kubeClient:           kubeClient,
+		snapshotStore:        snapshotStore,
+		subscriberHealth:     subscriberHealth,
+		destinationCheck:     destinationCheck,
+		oidcTokens:           oidcTokens,
+		accessControl:        accessControl,
 	}
 	reconciler.impl = controller.NewImpl(reconciler, reconciler.logger.Sugar(), ReconcilerName)
 
@@ -91,6 +126,20 @@ func NewController(
 
 	// Watch for kafka channels.
 	kafkachannelInformer.Informer().AddEventHandler(controller.HandleAll(reconciler.impl.Enqueue))
+
+	// Watch For The Subscriptions Of The KafkaChannel, Whose Annotations May Push Their Filters & Delivery Headers Down To The Dispatcher
+	if subscriptionInformer != nil {
+		reconciler.subscriptionLister = subscriptionInformer.Lister()
+		subscriptionInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: reconciler.isChannelSubscription,
+			Handler:    controller.HandleAll(func(interface{}) { reconciler.impl.EnqueueKey(channelNamespacedName(channelKey)) }),
+		})
+	}
+	// Update The KafkaChannel's SubscribersHealthy Condition Whenever A Subscriber Is Paused Or Resumed
+	subscriberHealth.SetChangedHandler(func() { reconciler.impl.EnqueueKey(channelNamespacedName(channelKey)) })
+	// Re-Check The Access Of The Subscribers Of Other Namespaces Whenever The ChannelAccessPolicies Change
+	accessControl.SetChangedHandler(func() { reconciler.impl.EnqueueKey(channelNamespacedName(channelKey)) })
+
 	logger.Debug("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	watches := []watch.Interface{
@@ -144,13 +193,18 @@ func (r Reconciler) Reconcile(ctx context.Context, key string) error {
 	// Don't modify the informers copy
 	channel := original.DeepCopy()
 
-	reconcileError := r.reconcile(channel)
+	reconcileError := r.reconcile(ctx, channel)
 	if reconcileError != nil {
 		r.logger.Error("Error Reconciling KafkaChannel", zap.Error(reconcileError))
 		r.recorder.Eventf(channel, corev1.EventTypeWarning, channelReconcileFailed, "KafkaChannel Reconciliation Failed: %v", reconcileError)
 	} else {
 		r.logger.Debug("KafkaChannel Reconciled Successfully")
 		r.recorder.Event(channel, corev1.EventTypeNormal, channelReconciled, "KafkaChannel Reconciled")
+
+		// Persist The Reconciled Subscriptions For Restarted Dispatchers (No-Op Unless Enabled)
+		if err = r.snapshotStore.Save(ctx, r.snapshotChannel(channel)); err != nil {
+			r.logger.Warn("Failed To Save Subscription Snapshot", zap.Error(err))
+		}
 	}
 
 	_, updateStatusErr := r.updateStatus(ctx, channel)
@@ -167,7 +221,7 @@ func (r Reconciler) Reconcile(ctx context.Context, key string) error {
 }
 
 // Reconcile The Specified KafkaChannel
-func (r Reconciler) reconcile(channel *kafkav1beta1.KafkaChannel) error {
+func (r Reconciler) reconcile(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
 
 	// The KafkaChannel's Subscribers
 	var subscribers []eventingduck.SubscriberSpec
@@ -179,11 +233,48 @@ func (r Reconciler) reconcile(channel *kafkav1beta1.KafkaChannel) error {
 		subscribers = make([]eventingduck.SubscriberSpec, 0)
 	}
 
+	// Resolve The Delivery Headers Of The Subscribers, Which Aren't Dispatched To Until Their Headers Can Be Resolved
+	deliveryHeaders, subscriberErrors := r.subscriptionDeliveryHeaders(ctx, channel)
+
+	// Check The Access Of The Subscribers Of Other Namespaces, Which Aren't Dispatched To Unless Allowed By A ChannelAccessPolicy
+	for uid, accessErr := range r.checkAccess(ctx, channel) {
+		if subscriberErrors == nil {
+			subscriberErrors = make(map[types.UID]error)
+		}
+		subscriberErrors[uid] = accessErr
+	}
+	if len(subscriberErrors) > 0 {
+		dispatchedSubscribers := make([]eventingduck.SubscriberSpec, 0, len(subscribers))
+		for _, subscriber := range subscribers {
+			if _, ok := subscriberErrors[subscriber.UID]; !ok {
+				dispatchedSubscribers = append(dispatchedSubscribers, subscriber)
+			}
+		}
+		subscribers = dispatchedSubscribers
+	}
+
 	// Update The ConsumerGroups To Align With Current KafkaChannel Subscribers
-	failedSubscriptions := r.dispatcher.UpdateSubscriptions(subscribers)
+	failedSubscriptions, err := updateSubscriptions(r.logger, r.dispatcher, subscribers, channel.Annotations, r.subscriptionFilters(channel), deliveryHeaders, channel.Spec.ExtraTopics)
+	if err != nil {
+		return err
+	}
+	for _, subscriber := range channel.Spec.Subscribers {
+		if subscriberErr, ok := subscriberErrors[subscriber.UID]; ok {
+			if failedSubscriptions == nil {
+				failedSubscriptions = make(map[eventingduck.SubscriberSpec]error)
+			}
+			failedSubscriptions[subscriber] = subscriberErr
+		}
+	}
+
+	// Check The Destinations Of The Subscribers (No-Op Unless Enabled)
+	unreachableSubscriptions := r.checkDestinations(ctx, channel)
 
-	// Update The KafkaChannel Subscribable Status Based On ConsumerGroup Creation Status
-	channel.Status.SubscribableStatus = r.createSubscribableStatus(channel.Spec.Subscribers, failedSubscriptions)
+	// Update The KafkaChannel Subscribable Status Based On ConsumerGroup Creation Status & Destination Checks
+	channel.Status.SubscribableStatus = r.createSubscribableStatus(channel.Spec.Subscribers, failedSubscriptions, unreachableSubscriptions)
+
+	// Update The SubscribersHealthy Condition Based On The Subscribers Paused Due To Failing Health Probes
+	r.updateSubscribersHealthyCondition(channel)
 
 	// Log Failed Subscriptions & Return Error
 	if len(failedSubscriptions) > 0 {
@@ -195,8 +286,294 @@ func (r Reconciler) reconcile(channel *kafkav1beta1.KafkaChannel) error {
 	return nil
 }
 
-// Create The SubscribableStatus Block Based On The Updated Subscriptions
-func (r *Reconciler) createSubscribableStatus(subscribers []eventingduck.SubscriberSpec, failedSubscriptions map[eventingduck.SubscriberSpec]error) eventingduck.SubscribableStatus {
+// Restore The Subscriptions Of The Persisted Snapshot (If Any) So That A Restarted Dispatcher Resumes Consuming
+// Before The KafkaChannel Is Reconciled, Which Then Updates The Subscriptions As Usual
+func RestoreSnapshot(ctx context.Context, logger *zap.Logger, channelKey string, dispatcher dispatcher.Dispatcher, snapshotStore *snapshot.Store) error {
+
+	// Load The Persisted Snapshot, If Any
+	subscriptionSnapshot, err := snapshotStore.Load(ctx, channelKey)
+	if err != nil || subscriptionSnapshot == nil {
+		return err
+	}
+
+	// Update The ConsumerGroups To Align With The Snapshot's Subscribers
+	logger.Info("Restoring Subscriptions From Snapshot", zap.Int("Subscribers", len(subscriptionSnapshot.Subscribers)))
+	failedSubscriptions, err := updateSubscriptions(logger, dispatcher, subscriptionSnapshot.Subscribers, subscriptionSnapshot.Annotations, nil, nil, subscriptionSnapshot.ExtraTopics)
+	if err != nil {
+		return err
+	}
+	if len(failedSubscriptions) > 0 {
+		return fmt.Errorf("%d snapshot subscribers failed to subscribe", len(failedSubscriptions))
+	}
+	return nil
+}
+
+// Utility Function For Updating The Dispatcher's Subscriptions, Parsing Their Configuration From The KafkaChannel Annotations
+// (The Filters Of The KafkaChannel's Annotation Taking Precedence Over Those Pushed Down By The Subscriptions' Annotations)
+func updateSubscriptions(logger *zap.Logger, kafkaDispatcher dispatcher.Dispatcher, subscribers []eventingduck.SubscriberSpec, annotations map[string]string, subscriptionFilters dispatcher.SubscriberFilters, deliveryHeaders dispatcher.SubscriberDeliveryHeaders, extraTopics []string) (map[eventingduck.SubscriberSpec]error, error) {
+
+	// Parse The Optional EventType Routing From The KafkaChannel Annotations
+	eventTypeRouting, err := routing.NewEventTypeRouting(annotations)
+	if err != nil {
+		logger.Error("Failed To Parse KafkaChannel EventType Routing", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The Optional Subscriber EventAgePolicies From The KafkaChannel Annotations
+	eventAgePolicies, err := dispatcher.NewEventAgePolicies(annotations)
+	if err != nil {
+		logger.Error("Failed To Parse KafkaChannel EventAgePolicies", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The Optional ConsumerGroup RebalanceStrategy From The KafkaChannel Annotations
+	rebalanceStrategy, err := dispatcher.NewRebalanceStrategy(annotations)
+	if err != nil {
+		logger.Error("Failed To Parse KafkaChannel RebalanceStrategy", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The Optional ConsumerGroup TuningPreset From The KafkaChannel Annotations
+	tuningPreset, err := dispatcher.NewTuningPreset(annotations)
+	if err != nil {
+		logger.Error("Failed To Parse KafkaChannel TuningPreset", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The Optional gRPC Subscribers From The KafkaChannel Annotations
+	grpcSubscribers := dispatcher.NewGrpcSubscribers(annotations)
+
+	// Parse The Optional SubscriberParallelism From The KafkaChannel Annotations
+	subscriberParallelism, err := dispatcher.NewSubscriberParallelism(annotations)
+	if err != nil {
+		logger.Error("Failed To Parse KafkaChannel SubscriberParallelism", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The Optional SubscriberFilters From The KafkaChannel Annotations
+	subscriberFilters, err := dispatcher.NewSubscriberFilters(annotations)
+	if err != nil {
+		logger.Error("Failed To Parse KafkaChannel SubscriberFilters", zap.Error(err))
+		return nil, err
+	}
+	subscriberFilters = subscriberFilters.Merge(subscriptionFilters)
+
+	// Parse The Optional SubscriberTransforms From The KafkaChannel Annotations
+	subscriberTransforms, err := dispatcher.NewSubscriberTransforms(annotations)
+	if err != nil {
+		logger.Error("Failed To Parse KafkaChannel SubscriberTransforms", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The Optional Middleware Modules Applied To The Events From The KafkaChannel Annotations
+	channelMiddleware := middleware.ChannelMiddleware(annotations)
+
+	// Update The ConsumerGroups To Align With The Subscribers (Also Consuming The KafkaChannel's ExtraTopics)
+	return kafkaDispatcher.UpdateSubscriptions(subscribers, dispatcher.SubscriptionConfig{
+		EventTypeRouting:  eventTypeRouting,
+		EventAgePolicies:  eventAgePolicies,
+		RebalanceStrategy: rebalanceStrategy,
+		TuningPreset:      tuningPreset,
+		GrpcSubscribers:   grpcSubscribers,
+		Parallelism:       subscriberParallelism,
+		Filters:           subscriberFilters,
+		Transforms:        subscriberTransforms,
+		DeliveryHeaders:   deliveryHeaders,
+		Middleware:        channelMiddleware,
+		ExtraTopics:       extraTopics,
+	}), nil
+}
+
+// Get The Filters Pushed Down By The Annotations Of The KafkaChannel's Subscriptions, Keyed By Subscriber UID
+//
+// A channel based Broker writes the attribute filter of each Trigger on the Subscription of its trigger channel, so
+// that events not matching the Trigger are skipped here rather than after an HTTP hop to the Broker's filter.  The
+// filter is still applied by the Broker, so a Subscription whose filter cannot be parsed is merely left unfiltered.
+func (r Reconciler) subscriptionFilters(channel *kafkav1beta1.KafkaChannel) dispatcher.SubscriberFilters {
+
+	// Nothing To Push Down Without A Subscription Lister
+	if r.subscriptionLister == nil {
+		return nil
+	}
+
+	// Get The Subscriptions Of The KafkaChannel's Namespace
+	subscriptions, err := r.subscriptionLister.Subscriptions(channel.Namespace).List(labels.Everything())
+	if err != nil {
+		r.logger.Warn("Failed To List Subscriptions - Not Pushing Down Subscription Filters", zap.Error(err))
+		return nil
+	}
+
+	// Parse The Filters Of The Subscriptions Which Are Subscribers Of The KafkaChannel
+	subscriberUIDs := make(map[string]bool, len(channel.Spec.Subscribers))
+	for _, subscriber := range channel.Spec.Subscribers {
+		subscriberUIDs[string(subscriber.UID)] = true
+	}
+	subscriptionFilters := make(dispatcher.SubscriberFilters)
+	for _, subscription := range subscriptions {
+		if !subscriberUIDs[string(subscription.UID)] {
+			continue
+		}
+		filter, err := dispatcher.NewSubscriptionFilter(subscription.Annotations)
+		if err != nil {
+			r.logger.Warn("Invalid Subscription Filter - Not Filtering Subscription", zap.String("Subscription", subscription.Name), zap.Error(err))
+			continue
+		}
+		if len(filter) > 0 {
+			subscriptionFilters[string(subscription.UID)] = filter
+		}
+	}
+	return subscriptionFilters
+}
+
+// Get The Delivery Headers Of The KafkaChannel's Subscribers From Their Subscriptions' Annotations, Keyed By Subscriber UID,
+// Along With The Errors Of The Subscribers Whose Headers Can't Be Resolved (e.g. Due To A Missing Auth Secret)
+//
+// The auth Secrets are read from the Subscription's namespace on every reconciliation, and the KafkaChannel is
+// re-reconciled after the DeliveryAuthRefreshInterval while any Subscription references one (or declares an OIDC
+// audience) so that rotated credentials (and OIDC tokens) are picked up without the Secrets having to be watched.
+func (r Reconciler) subscriptionDeliveryHeaders(ctx context.Context, channel *kafkav1beta1.KafkaChannel) (dispatcher.SubscriberDeliveryHeaders, map[types.UID]error) {
+
+	// Get The Annotations Of The KafkaChannel's Subscriptions Which Declare Delivery Headers
+	subscriptions := r.deliveryHeaderSubscriptions(channel)
+	if len(subscriptions) == 0 {
+		return nil, nil
+	}
+
+	// Resolve The Delivery Headers Of Each Subscription (Reading Any Auth Secret)
+	deliveryHeaders := make(dispatcher.SubscriberDeliveryHeaders, len(subscriptions))
+	deliveryHeaderErrors := make(map[types.UID]error)
+	refresh := false
+	for _, subscription := range subscriptions {
+		refresh = refresh || dispatcher.HasDeliveryAuthSecret(subscription.Annotations) || dispatcher.HasDeliveryAudience(subscription.Annotations)
+		headers, err := dispatcher.NewSubscriptionDeliveryHeaders(subscription.Annotations, func(name string) (*corev1.Secret, error) {
+			if r.kubeClient == nil {
+				return nil, fmt.Errorf("secrets can't be read by the dispatcher")
+			}
+			return r.kubeClient.CoreV1().Secrets(subscription.Namespace).Get(ctx, name, metav1.GetOptions{})
+		}, func(audience string) (string, error) {
+			return r.oidcTokens.Token(ctx, audience)
+		})
+		if err != nil {
+			r.logger.Warn("Failed To Resolve Subscription Delivery Headers - Not Dispatching To Subscriber", zap.String("Subscription", subscription.Name), zap.Error(err))
+			deliveryHeaderErrors[subscription.UID] = err
+			continue
+		}
+		deliveryHeaders[string(subscription.UID)] = headers
+	}
+
+	// Re-Read The Auth Secrets Periodically To Pick Up Rotated Credentials
+	if refresh && r.impl != nil {
+		r.impl.EnqueueKeyAfter(channelNamespacedName(r.channelKey), constants.DeliveryAuthRefreshInterval)
+	}
+	return deliveryHeaders, deliveryHeaderErrors
+}
+
+// Get The Subscriptions Of The KafkaChannel's Subscribers Which Declare Delivery Headers
+func (r Reconciler) deliveryHeaderSubscriptions(channel *kafkav1beta1.KafkaChannel) []*messagingv1.Subscription {
+
+	// Nothing To Push Down Without A Subscription Lister
+	if r.subscriptionLister == nil {
+		return nil
+	}
+
+	// Get The Subscriptions Of The KafkaChannel's Namespace
+	subscriptions, err := r.subscriptionLister.Subscriptions(channel.Namespace).List(labels.Everything())
+	if err != nil {
+		r.logger.Warn("Failed To List Subscriptions - Not Resolving Subscription Delivery Headers", zap.Error(err))
+		return nil
+	}
+
+	// Filter The Subscriptions Of The KafkaChannel's Subscribers Which Declare Delivery Headers
+	subscriberUIDs := make(map[types.UID]bool, len(channel.Spec.Subscribers))
+	for _, subscriber := range channel.Spec.Subscribers {
+		subscriberUIDs[subscriber.UID] = true
+	}
+	var deliveryHeaderSubscriptions []*messagingv1.Subscription
+	for _, subscription := range subscriptions {
+		if subscriberUIDs[subscription.UID] && dispatcher.HasDeliveryHeaders(subscription.Annotations) {
+			deliveryHeaderSubscriptions = append(deliveryHeaderSubscriptions, subscription)
+		}
+	}
+	return deliveryHeaderSubscriptions
+}
+
+// Get The KafkaChannel Whose Subscriptions Are Persisted In The Snapshot, Excluding Any Subscribers With Delivery Headers
+// (A Restored Subscriber Would Otherwise Be Dispatched To Without Its Headers Until The KafkaChannel Is Reconciled)
+func (r Reconciler) snapshotChannel(channel *kafkav1beta1.KafkaChannel) *kafkav1beta1.KafkaChannel {
+	subscriptions := r.deliveryHeaderSubscriptions(channel)
+	if len(subscriptions) == 0 {
+		return channel
+	}
+	excludedUIDs := make(map[types.UID]bool, len(subscriptions))
+	for _, subscription := range subscriptions {
+		excludedUIDs[subscription.UID] = true
+	}
+	snapshotChannel := channel.DeepCopy()
+	snapshotChannel.Spec.Subscribers = nil
+	for _, subscriber := range channel.Spec.Subscribers {
+		if !excludedUIDs[subscriber.UID] {
+			snapshotChannel.Spec.Subscribers = append(snapshotChannel.Spec.Subscribers, subscriber)
+		}
+	}
+	return snapshotChannel
+}
+
+// Determine Whether The Specified Object Is A Subscription Of This Dispatcher's KafkaChannel (Or Of The Channel It Backs)
+func (r Reconciler) isChannelSubscription(obj interface{}) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	subscription, ok := obj.(*messagingv1.Subscription)
+	if !ok {
+		return false
+	}
+	channelName := channelNamespacedName(r.channelKey)
+	return subscription.Namespace == channelName.Namespace && subscription.Spec.Channel.Name == channelName.Name
+}
+
+// Utility Function For Converting A KafkaChannel Key Into Its NamespacedName
+func channelNamespacedName(channelKey string) types.NamespacedName {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(channelKey)
+	return types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+// Check The Destinations Of The KafkaChannel's Subscribers, Returning The Errors Of Those Which Can't Be Reached
+//
+// Subscribers failing the check are reported not Ready with a SubscriberDestinationCheckFailed warning event, and the
+// KafkaChannel is rechecked after the DestinationCheck's RecheckInterval so that they become Ready once fixed.
+func (r *Reconciler) checkDestinations(ctx context.Context, channel *kafkav1beta1.KafkaChannel) map[eventingduck.SubscriberSpec]error {
+	if r.destinationCheck == nil {
+		return nil
+	}
+	grpcSubscribers := dispatcher.NewGrpcSubscribers(channel.Annotations)
+	unreachableSubscriptions := make(map[eventingduck.SubscriberSpec]error)
+	for _, subscriber := range channel.Spec.Subscribers {
+		if err := r.destinationCheck.Check(ctx, &subscriber, grpcSubscribers.Enabled(&subscriber)); err != nil {
+			r.logger.Warn("Subscriber Destination Check Failed", zap.String("UID", string(subscriber.UID)), zap.Error(err))
+			r.recorder.Eventf(channel, corev1.EventTypeWarning, subscriberDestinationCheckFailed, "Subscriber %s Destination Check Failed: %v", subscriber.UID, err)
+			unreachableSubscriptions[subscriber] = err
+		}
+	}
+	if len(unreachableSubscriptions) > 0 && r.impl != nil {
+		r.impl.EnqueueKeyAfter(channelNamespacedName(r.channelKey), r.destinationCheck.RecheckInterval)
+	}
+	return unreachableSubscriptions
+}
+
+// Check The Access Of The KafkaChannel's Subscribers Of Other Namespaces, Returning The Errors Of Those Which Are Denied
+//
+// Denied subscribers are reported not Ready with a SubscriberAccessDenied warning event, and are re-checked whenever
+// the ChannelAccessPolicies of the KafkaChannel's namespace change.
+func (r *Reconciler) checkAccess(ctx context.Context, channel *kafkav1beta1.KafkaChannel) map[types.UID]error {
+	accessErrors := r.accessControl.Check(ctx, channel)
+	for uid, err := range accessErrors {
+		r.recorder.Eventf(channel, corev1.EventTypeWarning, subscriberAccessDenied, "Subscriber %s Access Denied: %v", uid, err)
+	}
+	return accessErrors
+}
+
+// Create The SubscribableStatus Block Based On The Updated Subscriptions & Any Failed Destination Checks
+func (r *Reconciler) createSubscribableStatus(subscribers []eventingduck.SubscriberSpec, failedSubscriptions map[eventingduck.SubscriberSpec]error, unreachableSubscriptions map[eventingduck.SubscriberSpec]error) eventingduck.SubscribableStatus {
 
 	subscriberStatus := make([]eventingduck.SubscriberStatus, 0)
 
@@ -209,6 +586,9 @@ func (r *Reconciler) createSubscribableStatus(subscribers []eventingduck.Subscri
 		if err, ok := failedSubscriptions[subscriber]; ok {
 			status.Ready = corev1.ConditionFalse
 			status.Message = err.Error()
+		} else if err, ok = unreachableSubscriptions[subscriber]; ok {
+			status.Ready = corev1.ConditionFalse
+			status.Message = err.Error()
 		}
 		subscriberStatus = append(subscriberStatus, status)
 	}
@@ -218,6 +598,25 @@ func (r *Reconciler) createSubscribableStatus(subscribers []eventingduck.Subscri
 	}
 }
 
+// Update The KafkaChannel's SubscribersHealthy Condition (Removed Unless Subscribers Are Health Probed)
+func (r *Reconciler) updateSubscribersHealthyCondition(channel *kafkav1beta1.KafkaChannel) {
+	if r.subscriberHealth == nil {
+		channel.Status.ClearSubscribersHealthyCondition()
+		return
+	}
+	outages := r.subscriberHealth.Outages()
+	if len(outages) == 0 {
+		channel.Status.MarkSubscribersHealthy()
+		return
+	}
+	descriptions := make([]string, 0, len(outages))
+	for uid, reason := range outages {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s)", uid, reason))
+	}
+	sort.Strings(descriptions)
+	channel.Status.MarkSubscribersUnhealthy(SubscriberOutageReason, "Consumption paused for %d unhealthy subscriber(s): %s", len(outages), strings.Join(descriptions, ", "))
+}
+
 func (r *Reconciler) updateStatus(ctx context.Context, desired *kafkav1beta1.KafkaChannel) (*kafkav1beta1.KafkaChannel, error) {
 	kc, err := r.kafkachannelLister.KafkaChannels(desired.Namespace).Get(desired.Name)
 	if err != nil {
@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing-kafka/pkg/apis/messaging"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkainformers "knative.dev/eventing-kafka/pkg/client/informers/externalversions/messaging/v1beta1"
+	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	eventingmessagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	messagingv1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/messaging/v1"
+	messaginglisters "knative.dev/eventing/pkg/client/listers/messaging/v1"
+	"knative.dev/pkg/apis"
+)
+
+// The Namespace & Creator Of A Subscription Of Another Namespace
+type subscriptionIdentity struct {
+	namespace string
+	creator   string
+}
+
+//
+// Access Control Of The Subscriptions Of Other Namespaces To A KafkaChannel
+//
+// A Subscription may reference a KafkaChannel of another namespace (e.g. an organization's shared event hub), in
+// which case its subscriber is only dispatched to if a ChannelAccessPolicy of the KafkaChannel's namespace allows
+// the Subscription's namespace and/or creator (the messaging.knative.dev/creator annotation, which the webhook
+// prevents from being altered).  Denied subscribers are reported not Ready until a policy allows them, and the
+// KafkaChannel is re-reconciled whenever the policies of its namespace change.  The Subscriptions of other
+// namespaces aren't watched, but are looked up (cluster-wide) whenever an unknown subscriber is added and then
+// cached by UID.  A nil *AccessControl is valid and never denies any subscribers.
+//
+type AccessControl struct {
+	logger             *zap.Logger
+	namespace          string
+	policyInformer     cache.SharedIndexInformer
+	policyLister       kafkalisters.ChannelAccessPolicyLister
+	subscriptionLister messaginglisters.SubscriptionLister
+	subscriptionClient messagingv1.SubscriptionsGetter
+	subscriptions      map[types.UID]subscriptionIdentity
+	lock               sync.Mutex
+}
+
+// AccessControl Constructor - The SubscriptionLister Lists The Subscriptions Of The KafkaChannel's Namespace
+func NewAccessControl(logger *zap.Logger,
+	namespace string,
+	policyInformer kafkainformers.ChannelAccessPolicyInformer,
+	subscriptionLister messaginglisters.SubscriptionLister,
+	subscriptionClient messagingv1.SubscriptionsGetter) *AccessControl {
+
+	return &AccessControl{
+		logger:             logger,
+		namespace:          namespace,
+		policyInformer:     policyInformer.Informer(),
+		policyLister:       policyInformer.Lister(),
+		subscriptionLister: subscriptionLister,
+		subscriptionClient: subscriptionClient,
+		subscriptions:      make(map[types.UID]subscriptionIdentity),
+	}
+}
+
+// Set The Function Called Whenever A ChannelAccessPolicy Of The KafkaChannel's Namespace Changes
+func (a *AccessControl) SetChangedHandler(onChanged func()) {
+	if a == nil {
+		return
+	}
+	a.policyInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: a.isNamespacePolicy,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { onChanged() },
+			UpdateFunc: func(interface{}, interface{}) { onChanged() },
+			DeleteFunc: func(interface{}) { onChanged() },
+		},
+	})
+}
+
+// Check The Subscribers Of The Specified KafkaChannel Against The ChannelAccessPolicies Of Its Namespace, Returning
+// The Errors Of Those Which Are Denied (Or Whose Subscriptions Can't Be Looked Up), Keyed By Subscriber UID
+func (a *AccessControl) Check(ctx context.Context, channel *kafkav1beta1.KafkaChannel) map[types.UID]error {
+	if a == nil || len(channel.Spec.Subscribers) == 0 {
+		return nil
+	}
+
+	// The Subscribers Of The KafkaChannel's Own Namespace Are Always Allowed
+	localSubscriptions, err := a.subscriptionLister.Subscriptions(channel.Namespace).List(labels.Everything())
+	if err != nil {
+		return a.denyAll(channel, nil, fmt.Errorf("failed to list the subscriptions of namespace %s: %w", channel.Namespace, err))
+	}
+	localUIDs := make(map[types.UID]bool, len(localSubscriptions))
+	for _, subscription := range localSubscriptions {
+		localUIDs[subscription.UID] = true
+	}
+
+	// Identify The Subscriptions Of The Other Subscribers (Looking Up Those Not Yet Cached)
+	subscriptions, err := a.identifySubscriptions(ctx, channel, localUIDs)
+	if err != nil {
+		return a.denyAll(channel, localUIDs, err)
+	}
+
+	// Deny The Subscriptions Of Other Namespaces Not Allowed By Any ChannelAccessPolicy
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	policies, err := a.policyLister.ChannelAccessPolicies(channel.Namespace).List(labels.Everything())
+	if err != nil {
+		return a.denyAll(channel, localUIDs, fmt.Errorf("failed to list the channel access policies of namespace %s: %w", channel.Namespace, err))
+	}
+	deniedSubscriptions := make(map[types.UID]error)
+	for uid, subscription := range subscriptions {
+		if !allowed(policies, channel.Name, subscription) {
+			a.logger.Warn("Subscription Of Another Namespace Denied Access To KafkaChannel", zap.String("UID", string(uid)), zap.String("Namespace", subscription.namespace), zap.String("Creator", subscription.creator))
+			deniedSubscriptions[uid] = fmt.Errorf("no channel access policy of namespace %s allows the subscriptions of namespace %s created by %q", channel.Namespace, subscription.namespace, subscription.creator)
+		}
+	}
+	return deniedSubscriptions
+}
+
+// Get The Namespace & Creator Of The Subscriptions Of The KafkaChannel's Subscribers Which Aren't In The Specified
+// Local UIDs, Keyed By Subscriber UID (Subscribers Without Any Subscription Being Omitted)
+func (a *AccessControl) identifySubscriptions(ctx context.Context, channel *kafkav1beta1.KafkaChannel, localUIDs map[types.UID]bool) (map[types.UID]subscriptionIdentity, error) {
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// Get The Cached Subscriptions Of The Subscribers, Dropping Those Of Removed Subscribers
+	subscriptions := make(map[types.UID]subscriptionIdentity)
+	lookup := false
+	for _, subscriber := range channel.Spec.Subscribers {
+		if localUIDs[subscriber.UID] {
+			continue
+		}
+		if subscription, ok := a.subscriptions[subscriber.UID]; ok {
+			subscriptions[subscriber.UID] = subscription
+		} else {
+			lookup = true
+		}
+	}
+	a.subscriptions = subscriptions
+	if !lookup {
+		return subscriptions, nil
+	}
+
+	// Look Up The Subscriptions Referencing The KafkaChannel Across All Namespaces
+	subscriptionList, err := a.subscriptionClient.Subscriptions(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the subscriptions of other namespaces: %w", err)
+	}
+	subscriberUIDs := make(map[types.UID]bool, len(channel.Spec.Subscribers))
+	for _, subscriber := range channel.Spec.Subscribers {
+		subscriberUIDs[subscriber.UID] = !localUIDs[subscriber.UID]
+	}
+	for i := range subscriptionList.Items {
+		subscription := &subscriptionList.Items[i]
+		if subscriberUIDs[subscription.UID] && referencesChannel(subscription, channel) {
+			subscriptions[subscription.UID] = subscriptionIdentity{
+				namespace: subscription.Namespace,
+				creator:   subscription.Annotations[messaging.GroupName+apis.CreatorAnnotationSuffix],
+			}
+		}
+	}
+	return subscriptions, nil
+}
+
+// Deny All The Subscribers Of The KafkaChannel Other Than Those Of The Specified Local UIDs With The Specified Error
+func (a *AccessControl) denyAll(channel *kafkav1beta1.KafkaChannel, localUIDs map[types.UID]bool, err error) map[types.UID]error {
+	a.logger.Warn("Failed To Check The Access Of The Subscriptions Of Other Namespaces - Not Dispatching To Them", zap.Error(err))
+	deniedSubscriptions := make(map[types.UID]error)
+	for _, subscriber := range channel.Spec.Subscribers {
+		if !localUIDs[subscriber.UID] {
+			deniedSubscriptions[subscriber.UID] = err
+		}
+	}
+	return deniedSubscriptions
+}
+
+// Determine Whether The Specified Object Is A ChannelAccessPolicy Of The KafkaChannel's Namespace
+func (a *AccessControl) isNamespacePolicy(obj interface{}) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	policy, ok := obj.(*kafkav1beta1.ChannelAccessPolicy)
+	return ok && policy.Namespace == a.namespace
+}
+
+// Determine Whether Any Of The Specified ChannelAccessPolicies Allows The Specified Subscription Access To The Named KafkaChannel
+func allowed(policies []*kafkav1beta1.ChannelAccessPolicy, channelName string, subscription subscriptionIdentity) bool {
+	for _, policy := range policies {
+		if policy.AppliesTo(channelName) && policy.Allows(subscription.namespace, subscription.creator) {
+			return true
+		}
+	}
+	return false
+}
+
+// Determine Whether The Specified Subscription References The Specified KafkaChannel (Of Its Own Or Another Namespace)
+func referencesChannel(subscription *eventingmessagingv1.Subscription, channel *kafkav1beta1.KafkaChannel) bool {
+	channelNamespace := subscription.Spec.Channel.Namespace
+	if len(channelNamespace) == 0 {
+		channelNamespace = subscription.Namespace
+	}
+	return subscription.Spec.Channel.Kind == "KafkaChannel" && subscription.Spec.Channel.Name == channel.Name && channelNamespace == channel.Namespace
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkafake "knative.dev/eventing-kafka/pkg/client/clientset/versioned/fake"
+	"knative.dev/eventing-kafka/pkg/client/informers/externalversions"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingmessagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	eventingfake "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	messaginglisters "knative.dev/eventing/pkg/client/listers/messaging/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testAccessNamespace = "hub"
+	testAccessChannel   = "events"
+	testDeployer        = "system:serviceaccount:team-b:deployer"
+)
+
+// Test The AccessControl's Check() Functionality
+func TestAccessControlCheck(t *testing.T) {
+
+	// Create A Local Subscription & The Subscriptions Of Other Namespaces (One Of Which References Another KafkaChannel)
+	subscriptions := []runtime.Object{
+		newTestAccessSubscription(testAccessNamespace, "local", ""),
+		newTestAccessSubscription("team-a", "team-a", ""),
+		newTestAccessSubscription("team-b", "team-b-deployer", testDeployer),
+		newTestAccessSubscription("team-b", "team-b-admin", "system:serviceaccount:team-b:admin"),
+		newTestAccessSubscription("team-c", "team-c", ""),
+	}
+	subscriptions[4].(*eventingmessagingv1.Subscription).Spec.Channel.Name = "other"
+
+	// Allow The Namespace team-a & The Service Account team-b/deployer (Plus Another KafkaChannel To Everyone)
+	accessControl, eventingClient := newTestAccessControl(t, subscriptions,
+		kafkav1beta1.ChannelAccessPolicySpec{AllowedNamespaces: []string{"team-a"}},
+		kafkav1beta1.ChannelAccessPolicySpec{AllowedServiceAccounts: []string{"team-b/deployer"}},
+		kafkav1beta1.ChannelAccessPolicySpec{Channels: []string{"other"}, AllowedNamespaces: []string{kafkav1beta1.AllNamespaces}})
+	channel := newTestAccessChannel("local", "team-a", "team-b-deployer", "team-b-admin", "team-c", "manual")
+
+	// Verify Only The Subscriptions Of Other Namespaces Not Allowed By Any Policy Are Denied
+	deniedSubscriptions := accessControl.Check(context.TODO(), channel)
+	assert.Len(t, deniedSubscriptions, 1)
+	assert.NotNil(t, deniedSubscriptions["team-b-admin"])
+	assert.Len(t, eventingClient.Actions(), 1)
+
+	// Verify The Subscriptions Of Other Namespaces Are Cached (Only Unknown Subscribers Being Looked Up Again)
+	channel = newTestAccessChannel("local", "team-a", "team-b-admin")
+	assert.Len(t, accessControl.Check(context.TODO(), channel), 1)
+	assert.Len(t, eventingClient.Actions(), 1)
+	assert.Len(t, accessControl.subscriptions, 2)
+
+	// Verify A KafkaChannel Without Subscribers Of Other Namespaces Denies None
+	assert.Empty(t, accessControl.Check(context.TODO(), newTestAccessChannel("local")))
+	assert.Len(t, eventingClient.Actions(), 1)
+	assert.Empty(t, accessControl.subscriptions)
+}
+
+// Test The AccessControl's Check() Functionality When The Subscriptions Of Other Namespaces Can't Be Listed
+func TestAccessControlCheckListFailure(t *testing.T) {
+	accessControl, eventingClient := newTestAccessControl(t, []runtime.Object{newTestAccessSubscription(testAccessNamespace, "local", "")},
+		kafkav1beta1.ChannelAccessPolicySpec{AllowedNamespaces: []string{kafkav1beta1.AllNamespaces}})
+	eventingClient.PrependReactor("list", "subscriptions", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("test forbidden")
+	})
+
+	// Verify All The Subscribers Other Than Those Of The KafkaChannel's Namespace Are Denied
+	deniedSubscriptions := accessControl.Check(context.TODO(), newTestAccessChannel("local", "team-a"))
+	assert.Len(t, deniedSubscriptions, 1)
+	assert.Contains(t, deniedSubscriptions["team-a"].Error(), "test forbidden")
+}
+
+// Test A nil AccessControl
+func TestNilAccessControl(t *testing.T) {
+	var accessControl *AccessControl
+	accessControl.SetChangedHandler(func() { t.Fatal("unexpected change") })
+	assert.Nil(t, accessControl.Check(context.TODO(), newTestAccessChannel("team-a")))
+}
+
+// Test The AccessControl's isNamespacePolicy() Functionality
+func TestAccessControlIsNamespacePolicy(t *testing.T) {
+	accessControl, _ := newTestAccessControl(t, nil)
+	policy := &kafkav1beta1.ChannelAccessPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: testAccessNamespace}}
+	assert.True(t, accessControl.isNamespacePolicy(policy))
+	assert.True(t, accessControl.isNamespacePolicy(cache.DeletedFinalStateUnknown{Obj: policy}))
+	assert.False(t, accessControl.isNamespacePolicy(&kafkav1beta1.ChannelAccessPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "team-a"}}))
+	assert.False(t, accessControl.isNamespacePolicy(&eventingmessagingv1.Subscription{}))
+}
+
+// Utility Function For Creating A Test AccessControl Of The Test Namespace With The Specified Subscriptions & Policies
+func newTestAccessControl(t *testing.T, subscriptions []runtime.Object, policySpecs ...kafkav1beta1.ChannelAccessPolicySpec) (*AccessControl, *eventingfake.Clientset) {
+	subscriptionIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, subscription := range subscriptions {
+		if subscription.(*eventingmessagingv1.Subscription).Namespace == testAccessNamespace {
+			assert.Nil(t, subscriptionIndexer.Add(subscription))
+		}
+	}
+	policyInformer := externalversions.NewSharedInformerFactory(kafkafake.NewSimpleClientset(), 0).Messaging().V1beta1().ChannelAccessPolicies()
+	for index, policySpec := range policySpecs {
+		assert.Nil(t, policyInformer.Informer().GetIndexer().Add(&kafkav1beta1.ChannelAccessPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: string(rune('a' + index)), Namespace: testAccessNamespace},
+			Spec:       policySpec,
+		}))
+	}
+	eventingClient := eventingfake.NewSimpleClientset(subscriptions...)
+	accessControl := NewAccessControl(logtesting.TestLogger(t).Desugar(), testAccessNamespace, policyInformer,
+		messaginglisters.NewSubscriptionLister(subscriptionIndexer), eventingClient.MessagingV1())
+	return accessControl, eventingClient
+}
+
+// Utility Function For Creating A Test Subscription (Whose UID Is Its Name) Of The Test KafkaChannel
+func newTestAccessSubscription(namespace string, name string, creator string) *eventingmessagingv1.Subscription {
+	subscription := &eventingmessagingv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name)},
+		Spec: eventingmessagingv1.SubscriptionSpec{
+			Channel: corev1.ObjectReference{APIVersion: "messaging.knative.dev/v1beta1", Kind: "KafkaChannel", Name: testAccessChannel},
+		},
+	}
+	if namespace != testAccessNamespace {
+		subscription.Spec.Channel.Namespace = testAccessNamespace
+	}
+	if len(creator) > 0 {
+		subscription.Annotations = map[string]string{"messaging.knative.dev/creator": creator}
+	}
+	return subscription
+}
+
+// Utility Function For Creating The Test KafkaChannel With The Specified Subscriber UIDs
+func newTestAccessChannel(subscriberUIDs ...types.UID) *kafkav1beta1.KafkaChannel {
+	channel := &kafkav1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Name: testAccessChannel, Namespace: testAccessNamespace}}
+	for _, uid := range subscriberUIDs {
+		channel.Spec.Subscribers = append(channel.Spec.Subscribers, eventingduck.SubscriberSpec{UID: uid})
+	}
+	return channel
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/conformance"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+	receiverhealth "knative.dev/eventing-kafka/pkg/channel/distributed/receiver/health"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/producer"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingchannel "knative.dev/eventing/pkg/channel"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Run The Channel Contract Suite Against The Distributed Receiver (Producer) & Dispatcher
+func TestConformance(t *testing.T) {
+	runConformance(t, nil)
+}
+
+// Run The Channel Contract Suite With The Records Encrypted By The Receiver & Decrypted By The Dispatcher
+func TestConformanceEncrypted(t *testing.T) {
+	runConformance(t, encryption.NewKMSEnvelope(&conformanceKMS{}, "conformance-key"))
+}
+
+// Run The Channel Contract Suite With The Specified (Optional) Envelope Encryption
+func runConformance(t *testing.T, envelope *encryption.Envelope) {
+	conformance.RunChannelContract(t, func(t *testing.T, cluster *conformance.Cluster) conformance.Channel {
+		return newConformanceChannel(t, cluster, envelope)
+	})
+}
+
+// The Distributed KafkaChannel Under Test
+type conformanceChannel struct {
+	channelReference eventingchannel.ChannelReference
+	producer         *producer.Producer
+	dispatcher       Dispatcher
+}
+
+// Create The Receiver's Producer & The Dispatcher For A Single Test KafkaChannel
+func newConformanceChannel(t *testing.T, cluster *conformance.Cluster, envelope *encryption.Envelope) *conformanceChannel {
+	logger := logtesting.TestLogger(t).Desugar()
+	channelReference := eventingchannel.ChannelReference{Namespace: "conformance-namespace", Name: "conformance-channel"}
+	statsReporter := metrics.NewStatsReporter(logger)
+
+	saramaConfig := sarama.NewConfig()
+	kafkaProducer, err := producer.NewProducer(logger, saramaConfig, []string{"conformance"}, statsReporter, receiverhealth.NewChannelHealthServer("0"), nil, nil, nil, envelope, false, false, nil, cluster.SyncProducerFactory())
+	assert.Nil(t, err)
+
+	dispatcher := NewDispatcher(DispatcherConfig{
+		Logger:        logger,
+		ClientId:      "conformance",
+		Brokers:       []string{"conformance"},
+		Topic:         util.TopicName(channelReference.Namespace, channelReference.Name),
+		ChannelKey:    channelReference.String(),
+		StatsReporter: statsReporter,
+		SaramaConfig:  saramaConfig,
+		Envelope:      envelope,
+
+		// Inject The Conformance Cluster In Place Of The Kafka Brokers
+		ProducerFactory:      cluster.SyncProducerFactory(),
+		ConsumerGroupFactory: cluster.ConsumerGroupFactory(),
+	})
+
+	return &conformanceChannel{channelReference: channelReference, producer: kafkaProducer, dispatcher: dispatcher}
+}
+
+func (c *conformanceChannel) Send(ctx context.Context, event cloudevents.Event) error {
+	return c.producer.ProduceKafkaMessage(ctx, util.TopicName(c.channelReference.Namespace, c.channelReference.Name), nil, false, nil, binding.ToMessage(&event))
+}
+
+func (c *conformanceChannel) Subscribe(_ context.Context, subscribers []eventingduck.SubscriberSpec) error {
+	for _, err := range c.dispatcher.UpdateSubscriptions(subscribers, SubscriptionConfig{}) {
+		return err
+	}
+	return nil
+}
+
+func (c *conformanceChannel) Close() error {
+	c.dispatcher.Shutdown()
+	c.producer.Close()
+	return nil
+}
+
+// A KMS "Wrapping" Data Keys As-Is (The Wrapping Itself Is Tested By The LocalKMS)
+type conformanceKMS struct{}
+
+func (k *conformanceKMS) WrapKey(_ context.Context, _ string, dataKey []byte) ([]byte, error) {
+	return append([]byte(nil), dataKey...), nil
+}
+
+func (k *conformanceKMS) UnwrapKey(_ context.Context, _ string, wrappedKey []byte) ([]byte, error) {
+	return append([]byte(nil), wrappedKey...), nil
+}
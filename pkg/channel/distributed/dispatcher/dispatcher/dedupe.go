@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Deduplication Defaults
+const (
+	DefaultDedupeWindow     = 10 * time.Minute
+	DefaultDedupeMaxEntries = 10000
+)
+
+// The CloudEvent Id & Source Headers Of Binary Mode Kafka Messages
+const (
+	ceIdHeader     = "ce_id"
+	ceSourceHeader = "ce_source"
+)
+
+//
+// Duplicate Event Detection Of A Single Subscriber
+//
+// The Deduplicator remembers the CloudEvent id & source of the events consumed within the window, evicting
+// the least recently seen events once MaxEntries is reached, so that duplicates re-emitted by a source can be
+// counted and optionally dropped.  The cache is local to the dispatcher replica, but since duplicates normally
+// share a partition key they are consumed by the same replica.  A nil *Deduplicator is valid and never detects
+// any duplicates.
+//
+type Deduplicator struct {
+	Drop       bool
+	window     time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // Least Recently Seen At The Front
+	lock       sync.Mutex
+	now        func() time.Time
+}
+
+// A Single Deduplicator Cache Entry
+type dedupeEntry struct {
+	key  string
+	seen time.Time
+}
+
+// Validate The Specified Dedupe Config
+func ValidateDedupeConfig(dedupeConfig config.EKDedupeConfig) error {
+	if dedupeConfig.WindowMillis < 0 {
+		return fmt.Errorf("windowMillis %d must not be negative", dedupeConfig.WindowMillis)
+	}
+	if dedupeConfig.MaxEntries < 0 {
+		return fmt.Errorf("maxEntries %d must not be negative", dedupeConfig.MaxEntries)
+	}
+	return nil
+}
+
+// Deduplicator Constructor - Returns nil If Deduplication Is Not Enabled (Assumes A Valid Config)
+func NewDeduplicator(dedupeConfig config.EKDedupeConfig) *Deduplicator {
+	if !dedupeConfig.Enabled {
+		return nil
+	}
+	window := time.Duration(dedupeConfig.WindowMillis) * time.Millisecond
+	if window <= 0 {
+		window = DefaultDedupeWindow
+	}
+	maxEntries := dedupeConfig.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultDedupeMaxEntries
+	}
+	return &Deduplicator{
+		Drop:       dedupeConfig.Drop,
+		window:     window,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// Determine Whether The Specified Message Duplicates An Event Seen Within The Window (Recording It As Seen)
+func (d *Deduplicator) Duplicate(consumerMessage *sarama.ConsumerMessage) bool {
+
+	// Nothing To Detect Without A Deduplicator Or Without A CloudEvent Id
+	if d == nil {
+		return false
+	}
+	id, source := messageIdAndSource(consumerMessage)
+	if len(id) == 0 {
+		return false
+	}
+	key := source + "\x00" + id
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	// Evict Expired Entries From The Front (Least Recently Seen)
+	now := d.now()
+	for front := d.order.Front(); front != nil && now.Sub(front.Value.(*dedupeEntry).seen) > d.window; front = d.order.Front() {
+		delete(d.entries, front.Value.(*dedupeEntry).key)
+		d.order.Remove(front)
+	}
+
+	// A Duplicate If Seen Within The Window (Refreshing The Entry)
+	if element, ok := d.entries[key]; ok {
+		element.Value.(*dedupeEntry).seen = now
+		d.order.MoveToBack(element)
+		return true
+	}
+
+	// Otherwise Record The Event, Evicting The Least Recently Seen Entry If Full
+	if d.order.Len() >= d.maxEntries {
+		front := d.order.Front()
+		delete(d.entries, front.Value.(*dedupeEntry).key)
+		d.order.Remove(front)
+	}
+	d.entries[key] = d.order.PushBack(&dedupeEntry{key: key, seen: now})
+	return false
+}
+
+// Utility Function For Getting The CloudEvent Id & Source Of A Binary Or Structured Mode Message
+func messageIdAndSource(consumerMessage *sarama.ConsumerMessage) (string, string) {
+
+	// Binary Mode Messages Carry The Attributes As Headers
+	var id, source string
+	for _, header := range consumerMessage.Headers {
+		if header == nil {
+			continue
+		}
+		switch string(header.Key) {
+		case ceIdHeader:
+			id = string(header.Value)
+		case ceSourceHeader:
+			source = string(header.Value)
+		}
+	}
+	if len(id) > 0 {
+		return id, source
+	}
+
+	// Structured Mode Messages Carry The Attributes In The JSON Value
+	var structuredEvent struct {
+		Id     string `json:"id"`
+		Source string `json:"source"`
+	}
+	if err := json.Unmarshal(consumerMessage.Value, &structuredEvent); err != nil {
+		return "", ""
+	}
+	return structuredEvent.Id, structuredEvent.Source
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Test The ValidateDedupeConfig() Functionality
+func TestValidateDedupeConfig(t *testing.T) {
+	assert.Nil(t, ValidateDedupeConfig(config.EKDedupeConfig{}))
+	assert.Nil(t, ValidateDedupeConfig(config.EKDedupeConfig{Enabled: true, WindowMillis: 1000, MaxEntries: 10}))
+	assert.Equal(t, "windowMillis -1 must not be negative", ValidateDedupeConfig(config.EKDedupeConfig{WindowMillis: -1}).Error())
+	assert.Equal(t, "maxEntries -1 must not be negative", ValidateDedupeConfig(config.EKDedupeConfig{MaxEntries: -1}).Error())
+}
+
+// Test The NewDeduplicator() Functionality
+func TestNewDeduplicator(t *testing.T) {
+	assert.Nil(t, NewDeduplicator(config.EKDedupeConfig{}))
+	deduplicator := NewDeduplicator(config.EKDedupeConfig{Enabled: true, Drop: true})
+	assert.NotNil(t, deduplicator)
+	assert.True(t, deduplicator.Drop)
+	assert.Equal(t, DefaultDedupeWindow, deduplicator.window)
+	assert.Equal(t, DefaultDedupeMaxEntries, deduplicator.maxEntries)
+}
+
+// Test The Deduplicator's Duplicate() Functionality
+func TestDeduplicatorDuplicate(t *testing.T) {
+
+	// A nil Deduplicator Never Detects Duplicates
+	var nilDeduplicator *Deduplicator
+	assert.False(t, nilDeduplicator.Duplicate(createDedupeTestMessage("id-1", "source-1")))
+
+	// Create A Deduplicator With A Controllable Clock
+	now := time.Now()
+	deduplicator := NewDeduplicator(config.EKDedupeConfig{Enabled: true, WindowMillis: 60000, MaxEntries: 2})
+	deduplicator.now = func() time.Time { return now }
+
+	// Verify Duplicates Are Detected By Id & Source
+	assert.False(t, deduplicator.Duplicate(createDedupeTestMessage("id-1", "source-1")))
+	assert.True(t, deduplicator.Duplicate(createDedupeTestMessage("id-1", "source-1")))
+	assert.False(t, deduplicator.Duplicate(createDedupeTestMessage("id-1", "source-2")))
+
+	// Verify Structured Mode Messages & Messages Without An Id
+	assert.True(t, deduplicator.Duplicate(&sarama.ConsumerMessage{Value: []byte(`{"id":"id-1","source":"source-2","specversion":"1.0"}`)}))
+	assert.False(t, deduplicator.Duplicate(&sarama.ConsumerMessage{Value: []byte("not json")}))
+	assert.False(t, deduplicator.Duplicate(&sarama.ConsumerMessage{Value: []byte("not json")}))
+
+	// Verify The Least Recently Seen Entry Is Evicted Once MaxEntries Is Reached
+	assert.False(t, deduplicator.Duplicate(createDedupeTestMessage("id-3", "source-1"))) // Evicts id-1/source-1
+	assert.False(t, deduplicator.Duplicate(createDedupeTestMessage("id-1", "source-1")))
+
+	// Verify Entries Expire After The Window
+	now = now.Add(61 * time.Second)
+	assert.False(t, deduplicator.Duplicate(createDedupeTestMessage("id-3", "source-1")))
+	assert.Equal(t, 1, deduplicator.order.Len())
+	assert.Len(t, deduplicator.entries, 1)
+}
+
+// Utility Function For Creating A Binary Mode Message With The Specified CloudEvent Id & Source
+func createDedupeTestMessage(id string, source string) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(ceIdHeader), Value: []byte(id)},
+			{Key: []byte(ceSourceHeader), Value: []byte(source)},
+		},
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// The Keys Of A Subscription's Delivery Auth Secret (Either A Bearer Token Or A Username & Password For Basic Auth)
+const (
+	DeliveryAuthTokenKey    = "token"
+	DeliveryAuthUsernameKey = "username"
+	DeliveryAuthPasswordKey = "password"
+)
+
+// The Headers Which The Dispatcher (Or The CloudEvents Binding) Sets & Which Subscriptions May Therefore Not Override
+var reservedDeliveryHeaders = map[string]bool{
+	"Content-Type":   true,
+	"Content-Length": true,
+	"Host":           true,
+	"Prefer":         true,
+	"Traceparent":    true,
+	"Tracestate":     true,
+}
+
+// The Delivery Headers Of A KafkaChannel's Subscribers Keyed By Subscriber UID
+type SubscriberDeliveryHeaders map[string]http.Header
+
+// Determine Whether The Specified Subscription Annotations Declare Any Delivery Headers
+func HasDeliveryHeaders(annotations map[string]string) bool {
+	return len(annotations[constants.SubscriptionDeliveryHeadersAnnotation]) > 0 || HasDeliveryAuthSecret(annotations) || HasDeliveryAudience(annotations)
+}
+
+// Determine Whether The Specified Subscription Annotations Reference A Delivery Auth Secret
+func HasDeliveryAuthSecret(annotations map[string]string) bool {
+	return len(annotations[constants.SubscriptionDeliveryAuthSecretAnnotation]) > 0
+}
+
+// Determine Whether The Specified Subscription Annotations Declare An OIDC Audience
+func HasDeliveryAudience(annotations map[string]string) bool {
+	return len(annotations[constants.SubscriptionDeliveryAudienceAnnotation]) > 0
+}
+
+//
+// Create The Delivery Headers Of A Subscription From Its Annotations (nil If None)
+//
+// The static headers annotation is a JSON object of header names to values, and the auth Secret annotation names a
+// Secret in the Subscription's namespace (got via the specified function) containing either a "token", which is sent
+// as a Bearer Authorization header, or a "username" & "password" which are sent as a Basic Authorization header.
+// The audience annotation instead sends the OIDC token minted for that audience (got via the specified function) as
+// a Bearer Authorization header.  Headers set by the dispatcher itself (e.g. Content-Type & the ce- attributes) can't
+// be specified, nor can more than one of an Authorization header, an auth Secret or an audience.
+//
+func NewSubscriptionDeliveryHeaders(annotations map[string]string, getSecret func(name string) (*corev1.Secret, error), getToken func(audience string) (string, error)) (http.Header, error) {
+
+	// No Headers If Neither Annotation Is Specified
+	if !HasDeliveryHeaders(annotations) {
+		return nil, nil
+	}
+	deliveryHeaders := make(http.Header)
+
+	// Parse The Optional Annotation's JSON Map Of Static Header Names To Values
+	if headersJson := annotations[constants.SubscriptionDeliveryHeadersAnnotation]; len(headersJson) > 0 {
+		var staticHeaders map[string]string
+		err := json.Unmarshal([]byte(headersJson), &staticHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", constants.SubscriptionDeliveryHeadersAnnotation, err)
+		}
+		for name, value := range staticHeaders {
+			canonicalName := http.CanonicalHeaderKey(name)
+			if !validHeaderName(name) || !validHeaderValue(value) {
+				return nil, fmt.Errorf("invalid %s annotation: invalid header %q", constants.SubscriptionDeliveryHeadersAnnotation, name)
+			} else if reservedDeliveryHeaders[canonicalName] || strings.HasPrefix(canonicalName, "Ce-") {
+				return nil, fmt.Errorf("invalid %s annotation: header %q is set by the dispatcher", constants.SubscriptionDeliveryHeadersAnnotation, name)
+			}
+			deliveryHeaders.Set(canonicalName, value)
+		}
+	}
+
+	// Add The Authorization Header Of The Optional Auth Secret
+	if secretName := annotations[constants.SubscriptionDeliveryAuthSecretAnnotation]; len(secretName) > 0 {
+		if len(deliveryHeaders.Get("Authorization")) > 0 {
+			return nil, fmt.Errorf("the Authorization header of the %s annotation conflicts with the %s annotation", constants.SubscriptionDeliveryHeadersAnnotation, constants.SubscriptionDeliveryAuthSecretAnnotation)
+		}
+		secret, err := getSecret(secretName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get delivery auth secret %s: %w", secretName, err)
+		}
+		authorization, err := deliveryAuthorization(secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delivery auth secret %s: %w", secretName, err)
+		}
+		deliveryHeaders.Set("Authorization", authorization)
+	}
+
+	// Add The Authorization Header Of The Optional Audience's OIDC Token
+	if audience := annotations[constants.SubscriptionDeliveryAudienceAnnotation]; len(audience) > 0 {
+		if len(deliveryHeaders.Get("Authorization")) > 0 {
+			return nil, fmt.Errorf("the %s annotation conflicts with the Authorization header of the %s or %s annotations", constants.SubscriptionDeliveryAudienceAnnotation, constants.SubscriptionDeliveryHeadersAnnotation, constants.SubscriptionDeliveryAuthSecretAnnotation)
+		}
+		token, err := getToken(audience)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get oidc token for audience %s: %w", audience, err)
+		}
+		deliveryHeaders.Set("Authorization", "Bearer "+token)
+	}
+
+	// Return The Delivery Headers
+	return deliveryHeaders, nil
+}
+
+// Utility Function For Getting The Authorization Header Value From A Delivery Auth Secret
+func deliveryAuthorization(secret *corev1.Secret) (string, error) {
+	token := strings.TrimSpace(string(secret.Data[DeliveryAuthTokenKey]))
+	username := string(secret.Data[DeliveryAuthUsernameKey])
+	password := string(secret.Data[DeliveryAuthPasswordKey])
+	switch {
+	case len(token) > 0 && len(username) > 0:
+		return "", fmt.Errorf("only one of the %q or %q keys may be specified", DeliveryAuthTokenKey, DeliveryAuthUsernameKey)
+	case len(token) > 0:
+		if !validHeaderValue(token) {
+			return "", fmt.Errorf("the %q key is not a valid bearer token", DeliveryAuthTokenKey)
+		}
+		return "Bearer " + token, nil
+	case len(username) > 0:
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	default:
+		return "", fmt.Errorf("either the %q or %q & %q keys are required", DeliveryAuthTokenKey, DeliveryAuthUsernameKey, DeliveryAuthPasswordKey)
+	}
+}
+
+// Utility Function For Determining Whether The Specified Header Name Is A Valid (RFC 7230) Token
+func validHeaderName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for _, char := range name {
+		if !strings.ContainsRune("!#$%&'*+-.^_`|~", char) && !('0' <= char && char <= '9') && !('a' <= char && char <= 'z') && !('A' <= char && char <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// Utility Function For Determining Whether The Specified Header Value Is Free Of Control Characters (Other Than Tabs)
+func validHeaderValue(value string) bool {
+	for _, char := range value {
+		if (char < ' ' && char != '\t') || char == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+//
+// The Delivery Headers Of The Dispatcher's Subscribers
+//
+// The headers are replaced on every update of the dispatcher's subscriptions, without recreating the subscribers'
+// ConsumerGroups, so that rotated credentials take effect with the next event delivered.  They are only added to
+// the HTTP requests delivering events to the subscriber itself, and never to its reply or DeadLetterSink.
+// A nil *DeliveryHeaders is valid and never adds any headers.
+//
+type DeliveryHeaders struct {
+	headers SubscriberDeliveryHeaders
+	lock    sync.RWMutex
+}
+
+// DeliveryHeaders Constructor
+func NewDeliveryHeaders() *DeliveryHeaders {
+	return &DeliveryHeaders{}
+}
+
+// Replace The Delivery Headers Of The Subscribers
+func (d *DeliveryHeaders) Set(headers SubscriberDeliveryHeaders) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.headers = headers
+}
+
+// Add The Delivery Headers Of The Specified Subscriber To A Copy Of The Specified (Optional) Headers
+func (d *DeliveryHeaders) Apply(subscriberUID string, headers http.Header) http.Header {
+	if d == nil {
+		return headers
+	}
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	deliveryHeaders := d.headers[subscriberUID]
+	if len(deliveryHeaders) == 0 {
+		return headers
+	}
+	appliedHeaders := headers.Clone()
+	if appliedHeaders == nil {
+		appliedHeaders = make(http.Header, len(deliveryHeaders))
+	}
+	for name, values := range deliveryHeaders {
+		appliedHeaders[name] = values
+	}
+	return appliedHeaders
+}
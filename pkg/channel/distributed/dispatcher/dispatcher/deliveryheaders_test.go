@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/constants"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewSubscriptionDeliveryHeaders() Functionality
+func TestNewSubscriptionDeliveryHeaders(t *testing.T) {
+
+	// Test Data
+	secrets := map[string]*corev1.Secret{
+		"token":    {ObjectMeta: metav1.ObjectMeta{Name: "token"}, Data: map[string][]byte{DeliveryAuthTokenKey: []byte("test-token\n")}},
+		"basic":    {ObjectMeta: metav1.ObjectMeta{Name: "basic"}, Data: map[string][]byte{DeliveryAuthUsernameKey: []byte("user"), DeliveryAuthPasswordKey: []byte("pass")}},
+		"both":     {ObjectMeta: metav1.ObjectMeta{Name: "both"}, Data: map[string][]byte{DeliveryAuthTokenKey: []byte("test-token"), DeliveryAuthUsernameKey: []byte("user")}},
+		"empty":    {ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+		"newlines": {ObjectMeta: metav1.ObjectMeta{Name: "newlines"}, Data: map[string][]byte{DeliveryAuthTokenKey: []byte("test\ntoken")}},
+	}
+	getSecret := func(name string) (*corev1.Secret, error) {
+		if secret, ok := secrets[name]; ok {
+			return secret, nil
+		}
+		return nil, errors.New("not found")
+	}
+	getToken := func(audience string) (string, error) {
+		if audience == "unauthorized" {
+			return "", errors.New("forbidden")
+		}
+		return "oidc-token", nil
+	}
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		annotations map[string]string
+		wantHeaders http.Header
+		wantError   string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name: "No Annotations",
+		},
+		{
+			name:        "Static Headers",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryHeadersAnnotation: `{"x-api-key":"key","X-Tenant":"acme"}`},
+			wantHeaders: http.Header{"X-Api-Key": {"key"}, "X-Tenant": {"acme"}},
+		},
+		{
+			name:        "Bearer Token Secret",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryAuthSecretAnnotation: "token"},
+			wantHeaders: http.Header{"Authorization": {"Bearer test-token"}},
+		},
+		{
+			name: "Basic Auth Secret With Static Headers",
+			annotations: map[string]string{
+				kafkaconstants.SubscriptionDeliveryHeadersAnnotation:    `{"x-api-key":"key"}`,
+				kafkaconstants.SubscriptionDeliveryAuthSecretAnnotation: "basic",
+			},
+			wantHeaders: http.Header{"X-Api-Key": {"key"}, "Authorization": {"Basic dXNlcjpwYXNz"}},
+		},
+		{
+			name: "OIDC Audience With Static Headers",
+			annotations: map[string]string{
+				kafkaconstants.SubscriptionDeliveryHeadersAnnotation:  `{"x-api-key":"key"}`,
+				kafkaconstants.SubscriptionDeliveryAudienceAnnotation: "test-audience",
+			},
+			wantHeaders: http.Header{"X-Api-Key": {"key"}, "Authorization": {"Bearer oidc-token"}},
+		},
+		{
+			name:        "Invalid JSON",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryHeadersAnnotation: "invalid"},
+			wantError:   "invalid kafka.eventing.knative.dev/delivery-headers annotation: invalid character 'i' looking for beginning of value",
+		},
+		{
+			name:        "Invalid Header Name",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryHeadersAnnotation: `{"x api key":"key"}`},
+			wantError:   `invalid kafka.eventing.knative.dev/delivery-headers annotation: invalid header "x api key"`,
+		},
+		{
+			name:        "Invalid Header Value",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryHeadersAnnotation: `{"x-api-key":"key\r\nHost: evil"}`},
+			wantError:   `invalid kafka.eventing.knative.dev/delivery-headers annotation: invalid header "x-api-key"`,
+		},
+		{
+			name:        "Reserved Header",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryHeadersAnnotation: `{"content-type":"text/plain"}`},
+			wantError:   `invalid kafka.eventing.knative.dev/delivery-headers annotation: header "content-type" is set by the dispatcher`,
+		},
+		{
+			name:        "CloudEvent Attribute Header",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryHeadersAnnotation: `{"ce-type":"spoofed"}`},
+			wantError:   `invalid kafka.eventing.knative.dev/delivery-headers annotation: header "ce-type" is set by the dispatcher`,
+		},
+		{
+			name: "Conflicting Authorization",
+			annotations: map[string]string{
+				kafkaconstants.SubscriptionDeliveryHeadersAnnotation:    `{"authorization":"Bearer static"}`,
+				kafkaconstants.SubscriptionDeliveryAuthSecretAnnotation: "token",
+			},
+			wantError: "the Authorization header of the kafka.eventing.knative.dev/delivery-headers annotation conflicts with the kafka.eventing.knative.dev/delivery-auth-secret annotation",
+		},
+		{
+			name: "Conflicting OIDC Audience",
+			annotations: map[string]string{
+				kafkaconstants.SubscriptionDeliveryAuthSecretAnnotation: "token",
+				kafkaconstants.SubscriptionDeliveryAudienceAnnotation:   "test-audience",
+			},
+			wantError: "the kafka.eventing.knative.dev/delivery-audience annotation conflicts with the Authorization header of the kafka.eventing.knative.dev/delivery-headers or kafka.eventing.knative.dev/delivery-auth-secret annotations",
+		},
+		{
+			name:        "Unauthorized OIDC Audience",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryAudienceAnnotation: "unauthorized"},
+			wantError:   "failed to get oidc token for audience unauthorized: forbidden",
+		},
+		{
+			name:        "Missing Secret",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryAuthSecretAnnotation: "missing"},
+			wantError:   "failed to get delivery auth secret missing: not found",
+		},
+		{
+			name:        "Secret With Token & Username",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryAuthSecretAnnotation: "both"},
+			wantError:   `invalid delivery auth secret both: only one of the "token" or "username" keys may be specified`,
+		},
+		{
+			name:        "Secret Without Credentials",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryAuthSecretAnnotation: "empty"},
+			wantError:   `invalid delivery auth secret empty: either the "token" or "username" & "password" keys are required`,
+		},
+		{
+			name:        "Secret With Invalid Token",
+			annotations: map[string]string{kafkaconstants.SubscriptionDeliveryAuthSecretAnnotation: "newlines"},
+			wantError:   `invalid delivery auth secret newlines: the "token" key is not a valid bearer token`,
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			headers, err := NewSubscriptionDeliveryHeaders(testCase.annotations, getSecret, getToken)
+			if len(testCase.wantError) > 0 {
+				assert.EqualError(t, err, testCase.wantError)
+				assert.Nil(t, headers)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.wantHeaders, headers)
+			}
+		})
+	}
+}
+
+// Test The DeliveryHeaders' Set() & Apply() Functionality
+func TestDeliveryHeaders(t *testing.T) {
+
+	// A nil DeliveryHeaders Adds No Headers
+	var nilDeliveryHeaders *DeliveryHeaders
+	nilDeliveryHeaders.Set(SubscriberDeliveryHeaders{"uid-1": {"Authorization": {"Bearer token"}}})
+	assert.Nil(t, nilDeliveryHeaders.Apply("uid-1", nil))
+
+	// Subscribers Without Delivery Headers Get The Specified Headers Unchanged
+	deliveryHeaders := NewDeliveryHeaders()
+	preferHeaders := http.Header{constants.PreferHeader: {constants.PreferReplyHeader}}
+	assert.Nil(t, deliveryHeaders.Apply("uid-1", nil))
+	deliveryHeaders.Set(SubscriberDeliveryHeaders{"uid-1": {"Authorization": {"Bearer token"}}})
+	assert.Equal(t, preferHeaders, deliveryHeaders.Apply("uid-2", preferHeaders))
+
+	// Subscribers With Delivery Headers Get A Copy Of The Specified Headers With Them Added
+	assert.Equal(t, http.Header{"Authorization": {"Bearer token"}}, deliveryHeaders.Apply("uid-1", nil))
+	applied := deliveryHeaders.Apply("uid-1", preferHeaders)
+	assert.Equal(t, http.Header{constants.PreferHeader: {constants.PreferReplyHeader}, "Authorization": {"Bearer token"}}, applied)
+	assert.Len(t, preferHeaders, 1)
+
+	// Replaced Delivery Headers (e.g. Rotated Credentials) Are Applied Immediately
+	deliveryHeaders.Set(SubscriberDeliveryHeaders{"uid-1": {"Authorization": {"Bearer rotated"}}})
+	assert.Equal(t, http.Header{"Authorization": {"Bearer rotated"}}, deliveryHeaders.Apply("uid-1", nil))
+}
+
+// Test The Handler's consumeMessage() Functionality With Subscriber Delivery Headers
+func TestHandlerConsumeMessageDeliveryHeaders(t *testing.T) {
+
+	// Test Data
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+	replyUrl := testReplyURI.URL()
+	deliveryHeaders := NewDeliveryHeaders()
+	deliveryHeaders.Set(SubscriberDeliveryHeaders{string(testSubscriberUID): {"Authorization": {"Bearer token"}}})
+
+	// Verify The Delivery Headers Are Sent To The Subscriber Along With The Prefer Header
+	expectedHeaders := http.Header{constants.PreferHeader: {constants.PreferReplyHeader}, "Authorization": {"Bearer token"}}
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, expectedHeaders, destinationUrl, replyUrl, nil, &retryConfig, nil)
+	handler := &Handler{
+		Logger:            logtesting.TestLogger(t).Desugar(),
+		Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+		MessageDispatcher: mockMessageDispatcher,
+		DeliveryHeaders:   deliveryHeaders,
+	}
+	assert.Nil(t, handler.consumeMessage(context.TODO(), createConsumerMessage(t), destinationUrl, replyUrl, nil, &retryConfig))
+	assert.NotNil(t, mockMessageDispatcher.Message())
+
+	// Verify The Delivery Headers Are Not Sent To The Reply Of A Subscription Without A Subscriber
+	mockMessageDispatcher = dispatchertesting.NewMockMessageDispatcher(t, http.Header{constants.PreferHeader: {constants.PreferReplyHeader}}, nil, replyUrl, nil, &retryConfig, nil)
+	handler.MessageDispatcher = mockMessageDispatcher
+	assert.Nil(t, handler.consumeMessage(context.TODO(), createConsumerMessage(t), nil, replyUrl, nil, &retryConfig))
+	assert.NotNil(t, mockMessageDispatcher.Message())
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	nethttp "net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Destination Check Defaults
+const (
+	DefaultDestinationCheckTimeout         = 2 * time.Second
+	DefaultDestinationCheckRecheckInterval = 30 * time.Second
+)
+
+//
+// Checking Of The Destinations Of The Subscribers Of A KafkaChannel
+//
+// A misconfigured subscriber (e.g. an addressable whose Service doesn't exist) is otherwise only noticed once the
+// delivery of its events fails.  The DestinationCheck instead checks that the destinations of each subscriber are
+// absolute URIs whose hosts resolve (and optionally that the subscriber URI is serving) before its subscription is
+// reported Ready, so that misconfigured subscribers are flagged in the KafkaChannel's SubscribableStatus when they
+// are created.  Failing subscribers are still consumed, and their events retried as usual.  A nil *DestinationCheck
+// is valid and never fails any subscribers.
+//
+type DestinationCheck struct {
+	logger          *zap.Logger
+	probe           bool
+	timeout         time.Duration
+	RecheckInterval time.Duration
+	client          *nethttp.Client
+	lookupHost      func(ctx context.Context, host string) ([]string, error)
+}
+
+// Validate The Specified DestinationCheck Config
+func ValidateDestinationCheckConfig(destinationCheckConfig config.EKDestinationCheckConfig) error {
+	if destinationCheckConfig.TimeoutMillis < 0 {
+		return fmt.Errorf("timeoutMillis %d must not be negative", destinationCheckConfig.TimeoutMillis)
+	}
+	if destinationCheckConfig.RecheckIntervalSeconds < 0 {
+		return fmt.Errorf("recheckIntervalSeconds %d must not be negative", destinationCheckConfig.RecheckIntervalSeconds)
+	}
+	return nil
+}
+
+// DestinationCheck Constructor - Returns nil If Destination Checking Is Not Enabled (Assumes A Valid Config)
+func NewDestinationCheck(logger *zap.Logger, destinationCheckConfig config.EKDestinationCheckConfig) *DestinationCheck {
+	if !destinationCheckConfig.Enabled {
+		return nil
+	}
+
+	destinationCheck := &DestinationCheck{
+		logger:          logger,
+		probe:           destinationCheckConfig.Probe,
+		timeout:         time.Duration(destinationCheckConfig.TimeoutMillis) * time.Millisecond,
+		RecheckInterval: time.Duration(destinationCheckConfig.RecheckIntervalSeconds) * time.Second,
+		lookupHost:      net.DefaultResolver.LookupHost,
+	}
+	if destinationCheck.timeout <= 0 {
+		destinationCheck.timeout = DefaultDestinationCheckTimeout
+	}
+	if destinationCheck.RecheckInterval <= 0 {
+		destinationCheck.RecheckInterval = DefaultDestinationCheckRecheckInterval
+	}
+	destinationCheck.client = &nethttp.Client{Timeout: destinationCheck.timeout}
+	return destinationCheck
+}
+
+// Check The Destinations Of The Specified Subscriber, Returning The Reason The First Failing One Can't Be Reached
+// (nil If All Pass) - The Subscriber URI Is Only Probed If Enabled & The Subscriber Is Delivered To Via HTTP
+func (c *DestinationCheck) Check(ctx context.Context, subscriber *eventingduck.SubscriberSpec, grpc bool) error {
+	if c == nil || subscriber == nil {
+		return nil
+	}
+
+	// Check The Subscriber & Reply URIs, And Any DeadLetterSink Which Isn't A Convention-Named Kafka Topic
+	kafkaSubscriber := IsKafkaSubscriber(subscriber)
+	if kafkaSubscriber {
+		if err := c.resolveKafkaDestination(ctx, subscriber.SubscriberURI); err != nil {
+			return err
+		}
+	} else if err := c.resolve(ctx, "subscriber", subscriber.SubscriberURI); err != nil {
+		return err
+	}
+	if err := c.resolve(ctx, "reply", subscriber.ReplyURI); err != nil {
+		return err
+	}
+	if subscriber.Delivery != nil && subscriber.Delivery.DeadLetterSink != nil && !kafkautil.IsDeadLetterTopicShorthand(subscriber.Delivery.DeadLetterSink.URI) {
+		if err := c.resolve(ctx, "deadLetterSink", subscriber.Delivery.DeadLetterSink.URI); err != nil {
+			return err
+		}
+	}
+
+	// Probe The Subscriber URI If Enabled (gRPC & Kafka Subscribers Don't Serve HTTP Requests)
+	if c.probe && !grpc && !kafkaSubscriber && subscriber.SubscriberURI != nil {
+		return c.probeURI(ctx, subscriber.SubscriberURI)
+	}
+	return nil
+}
+
+// Check That The Specified Destination (If Any) Is An Absolute URI Whose Host Resolves
+func (c *DestinationCheck) resolve(ctx context.Context, destination string, uri *apis.URL) error {
+	if uri == nil {
+		return nil
+	}
+	host := uri.URL().Hostname()
+	if !uri.URL().IsAbs() || len(host) == 0 {
+		return fmt.Errorf("%s URI %q is not an absolute URI", destination, uri.String())
+	}
+	return c.lookup(ctx, destination, host)
+}
+
+// Check That The Specified Kafka Subscriber URI Names A Topic And That The Hosts Of Its Bootstrap Servers Resolve
+func (c *DestinationCheck) resolveKafkaDestination(ctx context.Context, uri *apis.URL) error {
+	kafkaDestination, err := ParseKafkaDestination(uri.URL())
+	if err != nil {
+		return err
+	}
+	for _, broker := range kafkaDestination.Brokers {
+		host, _, _ := net.SplitHostPort(broker)
+		if err := c.lookup(ctx, "subscriber", host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check That The Specified Host (Unless An IP Address) Of The Specified Destination Resolves
+func (c *DestinationCheck) lookup(ctx context.Context, destination string, host string) error {
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	if _, err := c.lookupHost(lookupCtx, host); err != nil {
+		c.logger.Debug("Failed To Resolve Subscriber Destination", zap.String("Destination", destination), zap.String("Host", host), zap.Error(err))
+		return fmt.Errorf("%s host %q could not be resolved: %v", destination, host, err)
+	}
+	return nil
+}
+
+// Probe The Specified Subscriber URI With A HEAD Request (Any Response Other Than A 5xx Shows It Is Serving)
+func (c *DestinationCheck) probeURI(ctx context.Context, uri *apis.URL) error {
+	request, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodHead, uri.String(), nil)
+	if err != nil {
+		return err
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("subscriber URI %q could not be probed: %v", uri.String(), err)
+	}
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	_ = response.Body.Close()
+	if response.StatusCode >= nethttp.StatusInternalServerError {
+		return fmt.Errorf("subscriber URI %q responded with %d", uri.String(), response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ValidateDestinationCheckConfig() Functionality
+func TestValidateDestinationCheckConfig(t *testing.T) {
+	assert.Nil(t, ValidateDestinationCheckConfig(config.EKDestinationCheckConfig{}))
+	assert.Nil(t, ValidateDestinationCheckConfig(config.EKDestinationCheckConfig{Enabled: true, Probe: true, TimeoutMillis: 100, RecheckIntervalSeconds: 10}))
+	assert.NotNil(t, ValidateDestinationCheckConfig(config.EKDestinationCheckConfig{TimeoutMillis: -1}))
+	assert.NotNil(t, ValidateDestinationCheckConfig(config.EKDestinationCheckConfig{RecheckIntervalSeconds: -1}))
+}
+
+// Test The NewDestinationCheck() Functionality
+func TestNewDestinationCheck(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Disabled Destination Checking Returns nil (Which Never Fails Any Subscribers)
+	destinationCheck := NewDestinationCheck(logger, config.EKDestinationCheckConfig{})
+	assert.Nil(t, destinationCheck)
+	assert.Nil(t, destinationCheck.Check(context.TODO(), &eventingduck.SubscriberSpec{SubscriberURI: apis.HTTP("invalid.invalid")}, false))
+
+	// Unset Values Are Defaulted
+	destinationCheck = NewDestinationCheck(logger, config.EKDestinationCheckConfig{Enabled: true})
+	assert.Equal(t, DefaultDestinationCheckTimeout, destinationCheck.timeout)
+	assert.Equal(t, DefaultDestinationCheckRecheckInterval, destinationCheck.RecheckInterval)
+	assert.False(t, destinationCheck.probe)
+
+	// Specified Values Are Used
+	destinationCheck = NewDestinationCheck(logger, config.EKDestinationCheckConfig{Enabled: true, Probe: true, TimeoutMillis: 50, RecheckIntervalSeconds: 10})
+	assert.Equal(t, 50*time.Millisecond, destinationCheck.timeout)
+	assert.Equal(t, 50*time.Millisecond, destinationCheck.client.Timeout)
+	assert.Equal(t, 10*time.Second, destinationCheck.RecheckInterval)
+	assert.True(t, destinationCheck.probe)
+}
+
+// Test The DestinationCheck's Check() Functionality
+func TestDestinationCheck(t *testing.T) {
+
+	// Create A Test Subscriber Answering With The Current StatusCode
+	var statusCode int32 = nethttp.StatusMethodNotAllowed
+	server := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		writer.WriteHeader(int(atomic.LoadInt32(&statusCode)))
+	}))
+	defer server.Close()
+	serverURI, _ := apis.ParseURL(server.URL + "/events")
+
+	// Create A DestinationCheck Resolving Only The Known Hosts
+	destinationCheck := NewDestinationCheck(logtesting.TestLogger(t).Desugar(), config.EKDestinationCheckConfig{Enabled: true})
+	destinationCheck.lookupHost = func(_ context.Context, host string) ([]string, error) {
+		if host == "known.svc.cluster.local" {
+			return []string{"10.0.0.1"}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	known := apis.HTTP("known.svc.cluster.local")
+	unknown := apis.HTTP("unknown.svc.cluster.local")
+	deadLetter := func(uri *apis.URL) *eventingduck.DeliverySpec {
+		return &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: uri}}
+	}
+	kafkaURI := func(rawURI string) *apis.URL {
+		uri, err := apis.ParseURL(rawURI)
+		assert.Nil(t, err)
+		return uri
+	}
+
+	// Define The TestCases
+	tests := []struct {
+		name       string
+		subscriber eventingduck.SubscriberSpec
+		wantErr    string
+	}{
+		{name: "No Destinations", subscriber: eventingduck.SubscriberSpec{}},
+		{name: "Resolvable Destinations", subscriber: eventingduck.SubscriberSpec{SubscriberURI: known, ReplyURI: known, Delivery: deadLetter(known)}},
+		{name: "IP Address", subscriber: eventingduck.SubscriberSpec{SubscriberURI: serverURI}},
+		{name: "Kafka DeadLetterSink Shorthand", subscriber: eventingduck.SubscriberSpec{SubscriberURI: known, Delivery: deadLetter(&apis.URL{Scheme: "kafka"})}},
+		{name: "Relative Subscriber", subscriber: eventingduck.SubscriberSpec{SubscriberURI: &apis.URL{Path: "/events"}}, wantErr: "subscriber URI"},
+		{name: "Unresolvable Subscriber", subscriber: eventingduck.SubscriberSpec{SubscriberURI: unknown}, wantErr: "subscriber host"},
+		{name: "Unresolvable Reply", subscriber: eventingduck.SubscriberSpec{SubscriberURI: known, ReplyURI: unknown}, wantErr: "reply host"},
+		{name: "Unresolvable DeadLetterSink", subscriber: eventingduck.SubscriberSpec{SubscriberURI: known, Delivery: deadLetter(unknown)}, wantErr: "deadLetterSink host"},
+		{name: "Kafka Subscriber Of Own Cluster", subscriber: eventingduck.SubscriberSpec{SubscriberURI: kafkaURI("kafka:///target-topic")}},
+		{name: "Kafka Subscriber", subscriber: eventingduck.SubscriberSpec{SubscriberURI: kafkaURI("kafka://10.0.0.2,known.svc.cluster.local:9092/target-topic")}},
+		{name: "Unresolvable Kafka Subscriber", subscriber: eventingduck.SubscriberSpec{SubscriberURI: kafkaURI("kafka://known.svc.cluster.local,unknown.svc.cluster.local/target-topic")}, wantErr: "subscriber host \"unknown.svc.cluster.local\""},
+		{name: "Kafka Subscriber Without Topic", subscriber: eventingduck.SubscriberSpec{SubscriberURI: kafkaURI("kafka://known.svc.cluster.local")}, wantErr: "invalid kafka subscriber URI"},
+	}
+
+	// Run The TestCases
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := destinationCheck.Check(context.TODO(), &test.subscriber, false)
+			if len(test.wantErr) == 0 {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+
+	// Any Non-5xx Response Of A Probed Subscriber URI Passes
+	destinationCheck.probe = true
+	subscriber := &eventingduck.SubscriberSpec{SubscriberURI: serverURI}
+	assert.Nil(t, destinationCheck.Check(context.TODO(), subscriber, false))
+
+	// A 5xx Response Fails (But gRPC & Kafka Subscribers Aren't Probed)
+	atomic.StoreInt32(&statusCode, nethttp.StatusServiceUnavailable)
+	err := destinationCheck.Check(context.TODO(), subscriber, false)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Nil(t, destinationCheck.Check(context.TODO(), subscriber, true))
+	assert.Nil(t, destinationCheck.Check(context.TODO(), &eventingduck.SubscriberSpec{SubscriberURI: kafkaURI("kafka:///target-topic")}, false))
+
+	// A Subscriber Which Can't Be Connected To Fails
+	server.Close()
+	assert.NotNil(t, destinationCheck.Check(context.TODO(), subscriber, false))
+}
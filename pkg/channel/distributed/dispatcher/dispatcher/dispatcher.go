@@ -18,60 +18,121 @@ package dispatcher
 
 import (
 	"context"
-	"fmt"
+	"net/url"
+	"reflect"
 	"sync"
 
 	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/events"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/consumer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/producer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
 	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/tail"
+	"knative.dev/eventing-kafka/pkg/common/headers"
+	"knative.dev/eventing-kafka/pkg/common/tuning"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/channel"
 )
 
 // Define A Dispatcher Config Struct To Hold Configuration
 type DispatcherConfig struct {
-	Logger          *zap.Logger
-	ClientId        string
-	Brokers         []string
-	Topic           string
-	Username        string
-	Password        string
-	ChannelKey      string
-	StatsReporter   metrics.StatsReporter
-	SaramaConfig    *sarama.Config
-	SubscriberSpecs []eventingduck.SubscriberSpec
+	Logger           *zap.Logger
+	ClientId         string
+	Brokers          []string
+	Topic            string
+	Username         string
+	Password         string
+	ChannelKey       string
+	StatsReporter    metrics.StatsReporter
+	SaramaConfig     *sarama.Config
+	SubscriberSpecs  []eventingduck.SubscriberSpec
+	RetryPolicies    *RetryPolicies
+	FaultInjector    *faults.Injector
+	Tap              *tail.Tap
+	Dedupe           config.EKDedupeConfig
+	ConsumerGroup    config.EKConsumerGroupConfig
+	PoisonPill       config.EKPoisonPillConfig
+	ExtraTopics      config.EKExtraTopicsConfig
+	QuarantineTopic  string
+	HeadersPolicy    *headers.Policy
+	EventReporter    *events.ChannelReporter
+	Resolver         *DestinationResolver
+	Balancer         *EndpointBalancer
+	SubscriberHealth *SubscriberHealth
+	Envelope         *encryption.Envelope
+	Middleware       *middleware.Registry
+
+	// Optional Kafka Client Factories (nil Connects To The Brokers) Used To Inject An Alternate Kafka (e.g. Conformance Tests)
+	ProducerFactory      producer.SyncProducerFactory
+	ConsumerGroupFactory consumer.ConsumerGroupFactory
 }
 
 // Knative Eventing SubscriberSpec Wrapper Enhanced With Sarama ConsumerGroup
 type SubscriberWrapper struct {
 	eventingduck.SubscriberSpec
-	GroupId       string
-	ConsumerGroup sarama.ConsumerGroup
-	StopChan      chan struct{}
+	GroupId           string
+	Topics            []string
+	EventAgePolicy    *EventAgePolicy
+	RebalanceStrategy sarama.BalanceStrategy
+	TuningPreset      tuning.Preset
+	Grpc              bool
+	Parallelism       int
+	Filter            EventFilter
+	Transform         *EventTransform
+	Middleware        middleware.Pipeline
+	ConsumerGroup     sarama.ConsumerGroup
+	StopChan          chan struct{}
 }
 
 // SubscriberWrapper Constructor
-func NewSubscriberWrapper(subscriberSpec eventingduck.SubscriberSpec, groupId string, consumerGroup sarama.ConsumerGroup) *SubscriberWrapper {
-	return &SubscriberWrapper{subscriberSpec, groupId, consumerGroup, make(chan struct{})}
+func NewSubscriberWrapper(subscriberSpec eventingduck.SubscriberSpec, groupId string, topics []string, eventAgePolicy *EventAgePolicy, rebalanceStrategy sarama.BalanceStrategy, tuningPreset tuning.Preset, grpc bool, parallelism int, filter EventFilter, transform *EventTransform, pipeline middleware.Pipeline, consumerGroup sarama.ConsumerGroup) *SubscriberWrapper {
+	return &SubscriberWrapper{subscriberSpec, groupId, topics, eventAgePolicy, rebalanceStrategy, tuningPreset, grpc, parallelism, filter, transform, pipeline, consumerGroup, make(chan struct{})}
+}
+
+// The KafkaChannel's Configuration Of Its Subscriptions (Parsed From Its Annotations & Spec - Each Is nil Unless Enabled)
+type SubscriptionConfig struct {
+	EventTypeRouting  *routing.EventTypeRouting
+	EventAgePolicies  EventAgePolicies
+	RebalanceStrategy sarama.BalanceStrategy
+	TuningPreset      tuning.Preset
+	GrpcSubscribers   GrpcSubscribers
+	Parallelism       SubscriberParallelism
+	Filters           SubscriberFilters
+	Transforms        SubscriberTransforms
+	DeliveryHeaders   SubscriberDeliveryHeaders // The Resolved Headers Of The Subscribers' Subscriptions
+	Middleware        []string                  // The Names Of The KafkaChannel's Middleware Modules
+	ExtraTopics       []string // The Topics Fanned In To The KafkaChannel
 }
 
 //  Dispatcher Interface
 type Dispatcher interface {
 	ConfigChanged(*v1.ConfigMap) Dispatcher
 	Shutdown()
-	UpdateSubscriptions(subscriberSpecs []eventingduck.SubscriberSpec) map[eventingduck.SubscriberSpec]error
+	UpdateSubscriptions(subscriberSpecs []eventingduck.SubscriberSpec, subscriptionConfig SubscriptionConfig) map[eventingduck.SubscriberSpec]error
 }
 
 // Define A DispatcherImpl Struct With Configuration & ConsumerGroup State
 type DispatcherImpl struct {
 	DispatcherConfig
 	subscribers        map[types.UID]*SubscriberWrapper
+	subscriptionConfig SubscriptionConfig
 	consumerUpdateLock sync.Mutex
 	messageDispatcher  channel.MessageDispatcher
+	deadLetterProducer sarama.SyncProducer
+	grpcClient         *GrpcClient
+	kafkaProducers     *KafkaProducers
+	deliveryHeaders    *DeliveryHeaders
 }
 
 // Verify The DispatcherImpl Implements The Dispatcher Interface
@@ -85,8 +146,12 @@ func NewDispatcher(dispatcherConfig DispatcherConfig) Dispatcher {
 		DispatcherConfig:  dispatcherConfig,
 		subscribers:       make(map[types.UID]*SubscriberWrapper),
 		messageDispatcher: channel.NewMessageDispatcher(dispatcherConfig.Logger),
+		deliveryHeaders:   NewDeliveryHeaders(),
 	}
 
+	// Drop The gRPC Connections Of Any Subscriber Host Whose Addresses Change
+	dispatcher.Resolver.setHostChangedHandler(dispatcher.invalidateGrpcConnections)
+
 	// Return The DispatcherImpl
 	return dispatcher
 }
@@ -98,10 +163,27 @@ func (d *DispatcherImpl) Shutdown() {
 	for _, subscriber := range d.subscribers {
 		d.closeConsumerGroup(subscriber)
 	}
+
+	// Close Any DeadLetter Producer
+	if d.deadLetterProducer != nil {
+		err := d.deadLetterProducer.Close()
+		if err != nil {
+			d.Logger.Error("Failed To Close DeadLetter Producer", zap.Error(err))
+		}
+		d.deadLetterProducer = nil
+	}
+
+	// Close Any gRPC Subscriber Connections
+	d.grpcClient.Close()
+	d.grpcClient = nil
+
+	// Close Any Kafka Subscriber Producers
+	d.kafkaProducers.Close()
+	d.kafkaProducers = nil
 }
 
-// Update The Dispatcher's Subscriptions To Align With New State
-func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.SubscriberSpec) map[eventingduck.SubscriberSpec]error {
+// Update The Dispatcher's Subscriptions To Align With New State (Configured Per The KafkaChannel's SubscriptionConfig)
+func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.SubscriberSpec, subscriptionConfig SubscriptionConfig) map[eventingduck.SubscriberSpec]error {
 
 	if d.SaramaConfig == nil {
 		d.Logger.Error("Dispatcher has no config!")
@@ -116,20 +198,93 @@ func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.Subs
 	d.consumerUpdateLock.Lock()
 	defer d.consumerUpdateLock.Unlock()
 
+	// Track The SubscriptionConfig So That ConfigChanged() Can Recreate The Dispatcher With It
+	d.subscriptionConfig = subscriptionConfig
+	channelMiddleware := subscriptionConfig.Middleware
+	extraTopics := subscriptionConfig.ExtraTopics
+	rebalanceStrategy := subscriptionConfig.RebalanceStrategy
+	tuningPreset := subscriptionConfig.TuningPreset
+
+	// Replace The Subscribers' Delivery Headers In Place (Changed Credentials Don't Recreate The ConsumerGroups)
+	d.deliveryHeaders.Set(subscriptionConfig.DeliveryHeaders)
+
+	// Get The Dispatch Phase Pipeline Of The KafkaChannel's Middleware, Failing Every Subscription If It's Unavailable
+	pipeline, err := d.Middleware.Pipeline(middleware.DispatchPhase, channelMiddleware)
+	if err != nil {
+		d.Logger.Error("Failed To Get KafkaChannel Middleware", zap.Strings("Middleware", channelMiddleware), zap.Error(err))
+		for _, subscriberSpec := range subscriberSpecs {
+			failedSubscriptions[subscriberSpec] = err
+		}
+		return failedSubscriptions
+	}
+
+	// Fail Every Subscription If Any ExtraTopics Are Not Allowed By The Configuration (Also Rejected By The Controller)
+	if err = d.ExtraTopics.Verify(extraTopics); err != nil {
+		d.Logger.Error("KafkaChannel ExtraTopics Not Allowed", zap.Strings("ExtraTopics", extraTopics), zap.Error(err))
+		for _, subscriberSpec := range subscriberSpecs {
+			failedSubscriptions[subscriberSpec] = err
+		}
+		return failedSubscriptions
+	}
+
+	// Determine The ConsumerGroup Sarama Config (The KafkaChannel's RebalanceStrategy & TuningPreset Override The ConfigMap's)
+	consumerConfig := d.SaramaConfig
+	if rebalanceStrategy != nil || len(tuningPreset) > 0 {
+		configCopy := *d.SaramaConfig
+		tuningPreset.Apply(&configCopy)
+		if rebalanceStrategy != nil {
+			configCopy.Consumer.Group.Rebalance.Strategy = rebalanceStrategy
+		}
+		consumerConfig = &configCopy
+	}
+
 	// Loop Over All All The Specified Subscribers
 	for _, subscriberSpec := range subscriberSpecs {
 
+		// Get The Topics The Subscriber Should Consume (Just The Channel's Topic Unless EventTypeRouting Is Enabled, Plus Any Fanned In ExtraTopics)
+		topics := append(subscriptionConfig.EventTypeRouting.ConsumerTopics(d.Topic, string(subscriberSpec.UID)), extraTopics...)
+
+		// Get The Subscriber's Optional Maximum Event Age Policy
+		eventAgePolicy := subscriptionConfig.EventAgePolicies.Policy(string(subscriberSpec.UID))
+
+		// Determine Whether The Subscriber Is Delivered To Via gRPC
+		grpc := subscriptionConfig.GrpcSubscribers.Enabled(&subscriberSpec)
+
+		// Get The Subscriber's Optional Parallelism (0 Dispatches To Every Claimed Partition Concurrently)
+		parallelism := subscriptionConfig.Parallelism.Parallelism(string(subscriberSpec.UID))
+
+		// Get The Subscriber's Optional EventFilter (nil Dispatches Every Event)
+		filter := subscriptionConfig.Filters.Filter(string(subscriberSpec.UID))
+
+		// Get The Subscriber's Optional EventTransform (nil Dispatches Events Unchanged)
+		transform := subscriptionConfig.Transforms.Transform(string(subscriberSpec.UID))
+
+		// Close The ConsumerGroup Of Any Existing Subscriber Wrapper With A Different Spec (e.g. Resolved URIs Restored From A Stale Snapshot) Or Consuming Different Topics Or With A Different EventAgePolicy / RebalanceStrategy / TuningPreset / Protocol / Parallelism / Filter / Transform / Middleware (So It Is Recreated Below)
+		if subscriber, ok := d.subscribers[subscriberSpec.UID]; ok && (!reflect.DeepEqual(subscriber.SubscriberSpec, subscriberSpec) || !reflect.DeepEqual(subscriber.Topics, topics) || !reflect.DeepEqual(subscriber.EventAgePolicy, eventAgePolicy) || !rebalanceStrategyEqual(subscriber.RebalanceStrategy, rebalanceStrategy) || subscriber.TuningPreset != tuningPreset || subscriber.Grpc != grpc || subscriber.Parallelism != parallelism || !reflect.DeepEqual(subscriber.Filter, filter) || !reflect.DeepEqual(subscriber.Transform, transform) || !reflect.DeepEqual(subscriber.Middleware.Names(), pipeline.Names())) {
+			d.Logger.Info("Subscriber Spec, Topics, EventAgePolicy, RebalanceStrategy, TuningPreset, Protocol, Parallelism, Filter, Transform Or Middleware Changed - Recreating ConsumerGroup", zap.String("GroupId", subscriber.GroupId), zap.Strings("Topics", topics))
+			d.closeConsumerGroup(subscriber)
+		}
+
 		// If The Subscriber Wrapper For The SubscriberSpec Does Not Exist Then Create One
 		if _, ok := d.subscribers[subscriberSpec.UID]; !ok {
 
 			// Format The GroupId For The Specified Subscriber
-			groupId := fmt.Sprintf("kafka.%s", subscriberSpec.UID)
+			groupId := util.GroupId(string(subscriberSpec.UID))
 
 			// Create A ConsumerGroup Logger
 			logger := d.Logger.With(zap.String("GroupId", groupId))
 
+			// Ensure The DeadLetter Producer Exists If The Subscriber's DeadLetterSink Is Backed By Kafka Or Events Are Quarantined
+			var err error
+			if _, ok := util.DeadLetterTopic(d.Topic, &subscriberSpec); ok || d.quarantines() {
+				err = d.createDeadLetterProducer()
+			}
+
 			// Attempt To Create A Kafka ConsumerGroup
-			consumerGroup, _, err := consumer.CreateConsumerGroup(d.Brokers, d.SaramaConfig, groupId)
+			var consumerGroup sarama.ConsumerGroup
+			if err == nil {
+				consumerGroup, _, err = consumer.CreateConsumerGroupWithFactory(d.ConsumerGroupFactory, d.Brokers, consumerConfig, groupId)
+			}
 			if err != nil {
 
 				// Log & Return Failure
@@ -139,7 +294,7 @@ func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.Subs
 			} else {
 
 				// Create A New SubscriberWrapper With The ConsumerGroup
-				subscriber := NewSubscriberWrapper(subscriberSpec, groupId, consumerGroup)
+				subscriber := NewSubscriberWrapper(subscriberSpec, groupId, topics, eventAgePolicy, rebalanceStrategy, tuningPreset, grpc, parallelism, filter, transform, pipeline, consumerGroup)
 
 				// Should start observing metrics from Sarama Config.MetricsRegistry from CreateConsumerGroup() above ; )
 
@@ -171,6 +326,9 @@ func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.Subs
 		}
 	}
 
+	// Re-Resolve The Destinations Of The Active Subscribers (No-Op Unless Enabled)
+	d.Resolver.SetSubscribers(d.SubscriberSpecs)
+
 	// Return Any Failed Subscriber Errors
 	return failedSubscriptions
 }
@@ -189,12 +347,80 @@ func (d *DispatcherImpl) startConsuming(subscriber *SubscriberWrapper) {
 			logger.Info("ConsumerGroup Error Processing Initiated")
 			for err := range subscriber.ConsumerGroup.Errors() { // Closing ConsumerGroup Will Break Out Of This
 				logger.Error("ConsumerGroup Error", zap.Error(err))
+				d.EventReporter.Warning(events.ConsumerGroupError, "ConsumerGroup %s Error: %v", subscriber.GroupId, err)
 			}
 			logger.Info("ConsumerGroup Error Processing Terminated")
 		}()
 
-		// Create A New ConsumerGroupHandler To Consume Messages With
-		handler := NewHandler(logger, &subscriber.SubscriberSpec)
+		// Produce Directly To Any Kafka Backed DeadLetterSink Rather Than Via HTTP
+		var deadLetterProducer sarama.SyncProducer
+		deadLetterTopic, ok := util.DeadLetterTopic(d.Topic, &subscriber.SubscriberSpec)
+		if ok {
+			deadLetterProducer = d.deadLetterProducer
+		}
+
+		// Deliver To gRPC Subscribers Via The Shared GrpcClient (Lazily Created To Reuse Connections Across Subscribers)
+		var grpcClient *GrpcClient
+		if subscriber.Grpc {
+			if d.grpcClient == nil {
+				d.grpcClient = NewGrpcClient(d.Logger)
+			}
+			grpcClient = d.grpcClient
+		}
+
+		// Deliver To Kafka Subscribers Via The Shared KafkaProducers (Lazily Created To Reuse Producers Across Subscribers)
+		var kafkaProducers *KafkaProducers
+		kafkaSubscriber := IsKafkaSubscriber(&subscriber.SubscriberSpec)
+		if kafkaSubscriber {
+			if d.kafkaProducers == nil {
+				d.kafkaProducers = NewKafkaProducers(d.Logger, d.createKafkaSubscriberProducer)
+			}
+			kafkaProducers = d.kafkaProducers
+		}
+
+		// Probe The Health Of HTTP Subscribers Until The Subscriber Is Stopped (No-Op Unless Enabled)
+		var healthProbe *HealthProbe
+		if !subscriber.Grpc && !kafkaSubscriber {
+			subscriberSpec := subscriber.SubscriberSpec
+			healthProbe = d.SubscriberHealth.NewProbe(subscriberSpec.UID, func() *url.URL {
+				subscriberURI, _, _ := d.Resolver.Destinations(&subscriberSpec)
+				return optionalURL(subscriberURI)
+			})
+			healthProbe.Start(subscriber.StopChan)
+		}
+
+		// Apply The KafkaChannel's Middleware To The Subscriber's Events
+		channelNamespace, channelName, _ := cache.SplitMetaNamespaceKey(d.ChannelKey)
+		target := middleware.Target{Phase: middleware.DispatchPhase, Namespace: channelNamespace, Channel: channelName, Subscriber: string(subscriber.UID)}
+
+		// Create A New ConsumerGroupHandler To Consume Messages With (Each Subscriber Deduplicates & Limits Its Parallelism Independently)
+		handler := NewHandler(HandlerOptions{
+			Logger:             logger,
+			Subscriber:         &subscriber.SubscriberSpec,
+			DeadLetterProducer: deadLetterProducer,
+			DeadLetterTopic:    deadLetterTopic,
+			EventAgePolicy:     subscriber.EventAgePolicy,
+			RetryPolicies:      d.RetryPolicies,
+			FaultInjector:      d.FaultInjector,
+			GrpcClient:         grpcClient,
+			KafkaProducers:     kafkaProducers,
+			Tap:                d.Tap,
+			Deduplicator:       NewDeduplicator(d.Dedupe),
+			PoisonPillPolicy:   NewPoisonPillPolicy(d.PoisonPill, d.deadLetterProducer),
+			Quarantine:         NewQuarantine(d.QuarantineTopic, d.deadLetterProducer),
+			Limiter:            NewParallelismLimiter(subscriber.Parallelism),
+			Filter:             subscriber.Filter,
+			Transform:          subscriber.Transform,
+			Middleware:         subscriber.Middleware,
+			MiddlewareTarget:   target,
+			HeadersPolicy:      d.HeadersPolicy,
+			EventReporter:      d.EventReporter,
+			Resolver:           d.Resolver,
+			Balancer:           d.Balancer,
+			HealthProbe:        healthProbe,
+			Envelope:           d.Envelope,
+			DeliveryHeaders:    d.deliveryHeaders,
+		})
 
 		// Consume Messages Asynchronously
 		go func() {
@@ -212,13 +438,14 @@ func (d *DispatcherImpl) startConsuming(subscriber *SubscriberWrapper) {
 				// Start ConsumerGroup Consumption
 				default:
 					logger.Info("ConsumerGroup Message Consumption Initiated")
-					err := subscriber.ConsumerGroup.Consume(ctx, []string{d.Topic}, handler)
+					err := subscriber.ConsumerGroup.Consume(ctx, subscriber.Topics, handler)
 					if err != nil {
 						if err == sarama.ErrClosedConsumerGroup {
 							logger.Info("ConsumerGroup Closed Error - Ceasing Consumption") // Should be caught above but here as added precaution.
 							break
 						} else {
 							logger.Error("ConsumerGroup Failed To Consume Messages", zap.Error(err))
+							d.EventReporter.Warning(events.ConsumerGroupError, "ConsumerGroup %s Failed To Consume Messages: %v", subscriber.GroupId, err)
 						}
 					}
 				}
@@ -227,6 +454,65 @@ func (d *DispatcherImpl) startConsuming(subscriber *SubscriberWrapper) {
 	}
 }
 
+// Close The gRPC Connections Of The Specified Subscriber Host (Recreated On Demand)
+func (d *DispatcherImpl) invalidateGrpcConnections(host string) {
+	d.consumerUpdateLock.Lock()
+	grpcClient := d.grpcClient
+	d.consumerUpdateLock.Unlock()
+	grpcClient.Invalidate(host)
+}
+
+// Lazily Create The SyncProducer Shared By All Subscribers With Kafka Backed DeadLetterSinks
+func (d *DispatcherImpl) createDeadLetterProducer() error {
+
+	// Nothing To Do If The DeadLetter Producer Already Exists
+	if d.deadLetterProducer != nil {
+		return nil
+	}
+
+	// SyncProducers Require Successes To Be Returned
+	producerConfig := *d.SaramaConfig
+	producerConfig.Producer.Return.Successes = true
+
+	// Create The DeadLetter Producer
+	deadLetterProducer, _, err := producer.CreateSyncProducerWithFactory(d.ProducerFactory, d.Brokers, &producerConfig)
+	if err != nil {
+		d.Logger.Error("Failed To Create DeadLetter Producer", zap.Error(err))
+		return err
+	}
+
+	// Track The DeadLetter Producer (Encrypting Its Records If Enabled) & Return Success
+	d.deadLetterProducer = d.Envelope.SyncProducer(deadLetterProducer)
+	return nil
+}
+
+// Create A SyncProducer For Kafka Subscribers Of The Specified Kafka Cluster (The KafkaChannel's Own Cluster If None)
+func (d *DispatcherImpl) createKafkaSubscriberProducer(brokers []string) (sarama.SyncProducer, error) {
+
+	// SyncProducers Require Successes To Be Returned
+	producerConfig := *d.SaramaConfig
+	producerConfig.Producer.Return.Successes = true
+
+	// Other Kafka Clusters Are Produced To Without The KafkaChannel's SASL Credentials Or Record Encryption
+	if len(brokers) > 0 {
+		producerConfig.Net.SASL = sarama.NewConfig().Net.SASL
+		kafkaProducer, _, err := producer.CreateSyncProducerWithFactory(d.ProducerFactory, brokers, &producerConfig)
+		return kafkaProducer, err
+	}
+
+	// The KafkaChannel's Own Cluster Is Produced To With Its Brokers (Encrypting The Records If Enabled)
+	kafkaProducer, _, err := producer.CreateSyncProducerWithFactory(d.ProducerFactory, d.Brokers, &producerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return d.Envelope.SyncProducer(kafkaProducer), nil
+}
+
+// Determine Whether Events Are Produced To The KafkaChannel's Or A Poison Pill Quarantine Topic (Via The DeadLetter Producer)
+func (d *DispatcherImpl) quarantines() bool {
+	return len(d.QuarantineTopic) > 0 || (d.PoisonPill.Enabled && len(d.PoisonPill.QuarantineTopic) > 0)
+}
+
 // Close The ConsumerGroup Associated With A Single Subscriber
 func (d *DispatcherImpl) closeConsumerGroup(subscriber *SubscriberWrapper) {
 
@@ -278,11 +564,19 @@ func (d *DispatcherImpl) ConfigChanged(configMap *v1.ConfigMap) Dispatcher {
 		return nil
 	}
 
+	// The ConsumerGroup Settings Of The Dispatcher Config Take Precedence Over The ConfigMap's Sarama Settings
+	if err = kafkasarama.UpdateSaramaConsumerGroup(newConfig, d.ConsumerGroup); err != nil {
+		d.Logger.Error("Unable to apply consumer group settings", zap.Error(err))
+		return nil
+	}
+
 	// Validate Configuration (Should Always Be Present)
 	if d.SaramaConfig != nil {
 
 		// Some of the current config settings may not be overridden by the configmap (username, password, etc.)
 		kafkasarama.UpdateSaramaConfig(newConfig, d.SaramaConfig.ClientID, d.SaramaConfig.Net.SASL.User, d.SaramaConfig.Net.SASL.Password)
+		kafkasarama.UpdateSaramaTokenProvider(newConfig, d.SaramaConfig.Net.SASL.TokenProvider)
+		kafkasarama.CarryForwardSaramaDialer(newConfig, d.SaramaConfig)
 
 		// Ignore the "Producer" section as changes to that do not require recreating the Dispatcher
 		if kafkasarama.ConfigEqual(newConfig, d.SaramaConfig, newConfig.Producer) {
@@ -296,7 +590,7 @@ func (d *DispatcherImpl) ConfigChanged(configMap *v1.ConfigMap) Dispatcher {
 	d.Shutdown()
 	d.DispatcherConfig.SaramaConfig = newConfig
 	newDispatcher := NewDispatcher(d.DispatcherConfig)
-	failedSubscriptions := newDispatcher.UpdateSubscriptions(d.SubscriberSpecs)
+	failedSubscriptions := newDispatcher.UpdateSubscriptions(d.SubscriberSpecs, d.subscriptionConfig)
 	if len(failedSubscriptions) > 0 {
 		d.Logger.Fatal("Failed To Subscribe Kafka Subscriptions For New Dispatcher", zap.Int("Count", len(failedSubscriptions)))
 		return nil
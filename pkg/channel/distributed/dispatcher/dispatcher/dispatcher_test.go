@@ -18,6 +18,7 @@ package dispatcher
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
@@ -29,10 +30,16 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	kafkaconsumer "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/consumer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
 	kafkatesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/testing"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	"knative.dev/eventing-kafka/pkg/common/tuning"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/channel"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	logtesting "knative.dev/pkg/logging/testing"
 	"knative.dev/pkg/system"
 
@@ -111,13 +118,14 @@ func TestNewSubscriberWrapper(t *testing.T) {
 	consumerGroup := kafkatesting.NewMockConsumerGroup(t)
 
 	// Perform The Test
-	subscriberWrapper := NewSubscriberWrapper(subscriber, groupId, consumerGroup)
+	subscriberWrapper := NewSubscriberWrapper(subscriber, groupId, []string{testTopic}, nil, nil, "", false, 0, nil, nil, nil, consumerGroup)
 
 	// Verify Results
 	assert.NotNil(t, subscriberWrapper)
 	assert.Equal(t, subscriber.UID, subscriberWrapper.UID)
 	assert.Equal(t, consumerGroup, subscriberWrapper.ConsumerGroup)
 	assert.Equal(t, groupId, subscriberWrapper.GroupId)
+	assert.Equal(t, []string{testTopic}, subscriberWrapper.Topics)
 	assert.NotNil(t, subscriberWrapper.StopChan)
 }
 
@@ -156,9 +164,9 @@ func TestShutdown(t *testing.T) {
 			Logger: logtesting.TestLogger(t).Desugar(),
 		},
 		subscribers: map[types.UID]*SubscriberWrapper{
-			subscriber1.UID: NewSubscriberWrapper(subscriber1, groupId1, consumerGroup1),
-			subscriber2.UID: NewSubscriberWrapper(subscriber2, groupId2, consumerGroup2),
-			subscriber3.UID: NewSubscriberWrapper(subscriber3, groupId3, consumerGroup3),
+			subscriber1.UID: NewSubscriberWrapper(subscriber1, groupId1, []string{testTopic}, nil, nil, "", false, 0, nil, nil, nil, consumerGroup1),
+			subscriber2.UID: NewSubscriberWrapper(subscriber2, groupId2, []string{testTopic}, nil, nil, "", false, 0, nil, nil, nil, consumerGroup2),
+			subscriber3.UID: NewSubscriberWrapper(subscriber3, groupId3, []string{testTopic}, nil, nil, "", false, 0, nil, nil, nil, consumerGroup3),
 		},
 	}
 
@@ -206,6 +214,7 @@ func TestUpdateSubscriptions(t *testing.T) {
 				DispatcherConfig: DispatcherConfig{
 					SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
 					Logger:       logtesting.TestLogger(t).Desugar(),
+					Topic:        testTopic,
 				},
 				subscribers: map[types.UID]*SubscriberWrapper{},
 			},
@@ -222,6 +231,7 @@ func TestUpdateSubscriptions(t *testing.T) {
 				DispatcherConfig: DispatcherConfig{
 					SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
 					Logger:       logtesting.TestLogger(t).Desugar(),
+					Topic:        testTopic,
 				},
 				subscribers: map[types.UID]*SubscriberWrapper{
 					uid123: createSubscriberWrapper(t, uid123),
@@ -241,6 +251,7 @@ func TestUpdateSubscriptions(t *testing.T) {
 				DispatcherConfig: DispatcherConfig{
 					SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
 					Logger:       logtesting.TestLogger(t).Desugar(),
+					Topic:        testTopic,
 				},
 				subscribers: map[types.UID]*SubscriberWrapper{
 					uid123: createSubscriberWrapper(t, uid123),
@@ -261,6 +272,7 @@ func TestUpdateSubscriptions(t *testing.T) {
 				DispatcherConfig: DispatcherConfig{
 					SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
 					Logger:       logtesting.TestLogger(t).Desugar(),
+					Topic:        testTopic,
 				},
 				subscribers: map[types.UID]*SubscriberWrapper{
 					uid123: createSubscriberWrapper(t, uid123),
@@ -280,6 +292,7 @@ func TestUpdateSubscriptions(t *testing.T) {
 				DispatcherConfig: DispatcherConfig{
 					SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
 					Logger:       logtesting.TestLogger(t).Desugar(),
+					Topic:        testTopic,
 				},
 				subscribers: map[types.UID]*SubscriberWrapper{
 					uid123: createSubscriberWrapper(t, uid123),
@@ -315,7 +328,7 @@ func TestUpdateSubscriptions(t *testing.T) {
 			}
 
 			// Perform The Test
-			got := dispatcher.UpdateSubscriptions(tt.args.subscriberSpecs)
+			got := dispatcher.UpdateSubscriptions(tt.args.subscriberSpecs, SubscriptionConfig{})
 
 			// Verify Results
 			assert.Equal(t, tt.want, got)
@@ -336,9 +349,360 @@ func TestUpdateSubscriptions(t *testing.T) {
 	}
 }
 
+// Test The UpdateSubscriptions() Functionality With EventType Routing
+func TestUpdateSubscriptionsEventTypeRouting(t *testing.T) {
+
+	// Test Data
+	subscriberUID := types.UID("test-subscriber-uid")
+	subscriberSpecs := []eventingduck.SubscriberSpec{{UID: subscriberUID}}
+	eventTypeRouting, err := routing.NewEventTypeRouting(map[string]string{
+		kafkaconstants.EventTypesAnnotation:           "type.a,type.b",
+		kafkaconstants.SubscriberEventTypesAnnotation: `{"test-subscriber-uid":["type.b"]}`,
+	})
+	assert.Nil(t, err)
+
+	// Replace The NewConsumerGroupWrapper With Mock For Testing & Restore After Test
+	newConsumerGroupWrapperPlaceholder := kafkaconsumer.NewConsumerGroupWrapper
+	kafkaconsumer.NewConsumerGroupWrapper = func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+		return kafkatesting.NewMockConsumerGroup(t), nil
+	}
+	defer func() {
+		kafkaconsumer.NewConsumerGroupWrapper = newConsumerGroupWrapperPlaceholder
+	}()
+
+	// Create A New DispatcherImpl To Test
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
+			Logger:       logtesting.TestLogger(t).Desugar(),
+			Topic:        testTopic,
+			ExtraTopics:  commonconfig.EKExtraTopicsConfig{AllowedPrefixes: []string{"external-"}},
+		},
+		subscribers: make(map[types.UID]*SubscriberWrapper),
+	}
+	defer dispatcher.Shutdown()
+
+	// Verify The Subscriber Initially Consumes Only The Channel's Topic
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{}))
+	originalSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.Equal(t, []string{testTopic}, originalSubscriber.Topics)
+
+	// Verify The Subscriber Is Recreated To Consume Only Its Declared EventType Topic
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting}))
+	routedSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, originalSubscriber, routedSubscriber)
+	assert.Equal(t, []string{routing.EventTypeTopicName(testTopic, "type.b")}, routedSubscriber.Topics)
+	assert.Equal(t, eventTypeRouting, dispatcher.subscriptionConfig.EventTypeRouting)
+
+	// Verify The Subscriber Is Retained When The Topics Are Unchanged
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting}))
+	assert.Same(t, routedSubscriber, dispatcher.subscribers[subscriberUID])
+
+	// Verify The Subscriber Is Recreated When Its EventAgePolicy Changes
+	eventAgePolicies := EventAgePolicies{string(subscriberUID): {MaxEventAge: time.Hour}}
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting, EventAgePolicies: eventAgePolicies}))
+	policySubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, routedSubscriber, policySubscriber)
+	assert.Equal(t, eventAgePolicies.Policy(string(subscriberUID)), policySubscriber.EventAgePolicy)
+	assert.Equal(t, eventAgePolicies, dispatcher.subscriptionConfig.EventAgePolicies)
+
+	// Verify The Subscriber Is Recreated When Its Spec Changes (e.g. A Re-Resolved SubscriberURI Replacing A Snapshot's)
+	updatedSpecs := []eventingduck.SubscriberSpec{{UID: subscriberUID, SubscriberURI: apis.HTTP("updated-subscriber")}}
+	assert.Empty(t, dispatcher.UpdateSubscriptions(updatedSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting, EventAgePolicies: eventAgePolicies}))
+	updatedSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, policySubscriber, updatedSubscriber)
+	assert.Equal(t, updatedSpecs[0], updatedSubscriber.SubscriberSpec)
+
+	// Verify The Subscriber Is Recreated When Its Parallelism Changes
+	subscriberParallelism := SubscriberParallelism{string(subscriberUID): 2}
+	assert.Empty(t, dispatcher.UpdateSubscriptions(updatedSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting, EventAgePolicies: eventAgePolicies, Parallelism: subscriberParallelism}))
+	parallelSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, updatedSubscriber, parallelSubscriber)
+	assert.Equal(t, 2, parallelSubscriber.Parallelism)
+	assert.Equal(t, subscriberParallelism, dispatcher.subscriptionConfig.Parallelism)
+
+	// Verify Every Subscription Fails (Retaining The Existing Subscriber) When Any ExtraTopics Are Not Allowed By The Configuration
+	assert.Len(t, dispatcher.UpdateSubscriptions(updatedSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting, EventAgePolicies: eventAgePolicies, Parallelism: subscriberParallelism, ExtraTopics: []string{"external-topic-1", "other-topic"}}), len(updatedSpecs))
+	assert.Same(t, parallelSubscriber, dispatcher.subscribers[subscriberUID])
+
+	// Verify The Subscriber Is Recreated To Also Consume The KafkaChannel's ExtraTopics
+	extraTopics := []string{"external-topic-1", "external-topic-2"}
+	assert.Empty(t, dispatcher.UpdateSubscriptions(updatedSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting, EventAgePolicies: eventAgePolicies, Parallelism: subscriberParallelism, ExtraTopics: extraTopics}))
+	fanInSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, parallelSubscriber, fanInSubscriber)
+	assert.Equal(t, []string{routing.EventTypeTopicName(testTopic, "type.b"), "external-topic-1", "external-topic-2"}, fanInSubscriber.Topics)
+	assert.Equal(t, extraTopics, dispatcher.subscriptionConfig.ExtraTopics)
+
+	// Verify The Subscriber Is Recreated When Its Filter Changes
+	subscriberFilters := SubscriberFilters{string(subscriberUID): {"type": "type.b"}}
+	assert.Empty(t, dispatcher.UpdateSubscriptions(updatedSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting, EventAgePolicies: eventAgePolicies, Parallelism: subscriberParallelism, Filters: subscriberFilters, ExtraTopics: extraTopics}))
+	filteredSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, fanInSubscriber, filteredSubscriber)
+	assert.Equal(t, EventFilter{"type": "type.b"}, filteredSubscriber.Filter)
+	assert.Equal(t, subscriberFilters, dispatcher.subscriptionConfig.Filters)
+
+	// Verify The Subscriber Is Recreated When Its Transform Changes
+	subscriberTransforms := SubscriberTransforms{string(subscriberUID): {Redact: []string{"email"}}}
+	assert.Empty(t, dispatcher.UpdateSubscriptions(updatedSpecs, SubscriptionConfig{EventTypeRouting: eventTypeRouting, EventAgePolicies: eventAgePolicies, Parallelism: subscriberParallelism, Filters: subscriberFilters, Transforms: subscriberTransforms, ExtraTopics: extraTopics}))
+	transformedSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, filteredSubscriber, transformedSubscriber)
+	assert.Equal(t, &EventTransform{Redact: []string{"email"}}, transformedSubscriber.Transform)
+	assert.Equal(t, subscriberTransforms, dispatcher.subscriptionConfig.Transforms)
+}
+
+// Test The UpdateSubscriptions() Functionality With A KafkaChannel RebalanceStrategy
+func TestUpdateSubscriptionsRebalanceStrategy(t *testing.T) {
+
+	// Test Data
+	subscriberUID := types.UID("test-subscriber-uid")
+	subscriberSpecs := []eventingduck.SubscriberSpec{{UID: subscriberUID}}
+	saramaConfig := getSaramaConfigFromYaml(t, TestConfigBase)
+	defaultStrategy := saramaConfig.Consumer.Group.Rebalance.Strategy
+
+	// Replace The NewConsumerGroupWrapper With Mock Tracking The RebalanceStrategy & Restore After Test
+	var consumerGroupStrategy sarama.BalanceStrategy
+	newConsumerGroupWrapperPlaceholder := kafkaconsumer.NewConsumerGroupWrapper
+	kafkaconsumer.NewConsumerGroupWrapper = func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+		consumerGroupStrategy = configArg.Consumer.Group.Rebalance.Strategy
+		return kafkatesting.NewMockConsumerGroup(t), nil
+	}
+	defer func() {
+		kafkaconsumer.NewConsumerGroupWrapper = newConsumerGroupWrapperPlaceholder
+	}()
+
+	// Create A New DispatcherImpl To Test
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: saramaConfig,
+			Logger:       logtesting.TestLogger(t).Desugar(),
+			Topic:        testTopic,
+		},
+		subscribers: make(map[types.UID]*SubscriberWrapper),
+	}
+	defer dispatcher.Shutdown()
+
+	// Verify The ConsumerGroup Initially Uses The ConfigMap's RebalanceStrategy
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{}))
+	originalSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.Equal(t, defaultStrategy, consumerGroupStrategy)
+
+	// Verify The ConsumerGroup Is Recreated With The KafkaChannel's RebalanceStrategy (Without Altering The Dispatcher's Config)
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{RebalanceStrategy: sarama.BalanceStrategySticky}))
+	stickySubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, originalSubscriber, stickySubscriber)
+	assert.Equal(t, sarama.BalanceStrategySticky, consumerGroupStrategy)
+	assert.Equal(t, sarama.BalanceStrategySticky, stickySubscriber.RebalanceStrategy)
+	assert.Equal(t, sarama.BalanceStrategySticky, dispatcher.subscriptionConfig.RebalanceStrategy)
+	assert.Equal(t, defaultStrategy, dispatcher.SaramaConfig.Consumer.Group.Rebalance.Strategy)
+
+	// Verify The Subscriber Is Retained When The RebalanceStrategy Is Unchanged
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{RebalanceStrategy: sarama.BalanceStrategySticky}))
+	assert.Same(t, stickySubscriber, dispatcher.subscribers[subscriberUID])
+
+	// Verify The ConsumerGroup Is Recreated With The ConfigMap's RebalanceStrategy When The Override Is Removed
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{}))
+	assert.NotSame(t, stickySubscriber, dispatcher.subscribers[subscriberUID])
+	assert.Equal(t, defaultStrategy, consumerGroupStrategy)
+}
+
+// Test The UpdateSubscriptions() Functionality With A KafkaChannel TuningPreset
+func TestUpdateSubscriptionsTuningPreset(t *testing.T) {
+
+	// Test Data
+	subscriberUID := types.UID("test-subscriber-uid")
+	subscriberSpecs := []eventingduck.SubscriberSpec{{UID: subscriberUID}}
+	saramaConfig := getSaramaConfigFromYaml(t, TestConfigBase)
+	defaultMaxWaitTime := saramaConfig.Consumer.MaxWaitTime
+
+	// Create A New DispatcherImpl To Test, Tracking The Config Of The Created ConsumerGroups
+	var consumerGroupConfig *sarama.Config
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: saramaConfig,
+			Logger:       logtesting.TestLogger(t).Desugar(),
+			Topic:        testTopic,
+			ConsumerGroupFactory: func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+				consumerGroupConfig = configArg
+				return kafkatesting.NewMockConsumerGroup(t), nil
+			},
+		},
+		subscribers: make(map[types.UID]*SubscriberWrapper),
+	}
+	defer dispatcher.Shutdown()
+
+	// Verify The ConsumerGroup Initially Uses The ConfigMap's Settings
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{}))
+	originalSubscriber := dispatcher.subscribers[subscriberUID]
+	assert.Equal(t, defaultMaxWaitTime, consumerGroupConfig.Consumer.MaxWaitTime)
+
+	// Verify The ConsumerGroup Is Recreated With The Preset's Settings (Without Altering The Dispatcher's Config)
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{TuningPreset: tuning.Latency, RebalanceStrategy: sarama.BalanceStrategySticky}))
+	latencySubscriber := dispatcher.subscribers[subscriberUID]
+	assert.NotSame(t, originalSubscriber, latencySubscriber)
+	assert.Equal(t, tuning.Latency, latencySubscriber.TuningPreset)
+	assert.Equal(t, 10*time.Millisecond, consumerGroupConfig.Consumer.MaxWaitTime)
+	assert.Equal(t, sarama.BalanceStrategySticky, consumerGroupConfig.Consumer.Group.Rebalance.Strategy)
+	assert.Equal(t, defaultMaxWaitTime, dispatcher.SaramaConfig.Consumer.MaxWaitTime)
+
+	// Verify The Subscriber Is Retained When The TuningPreset Is Unchanged
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{TuningPreset: tuning.Latency, RebalanceStrategy: sarama.BalanceStrategySticky}))
+	assert.Same(t, latencySubscriber, dispatcher.subscribers[subscriberUID])
+
+	// Verify The ConsumerGroup Is Recreated With The ConfigMap's Settings When The Preset Is Removed
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{}))
+	assert.NotSame(t, latencySubscriber, dispatcher.subscribers[subscriberUID])
+	assert.Equal(t, defaultMaxWaitTime, consumerGroupConfig.Consumer.MaxWaitTime)
+}
+
+// Test The UpdateSubscriptions() Functionality With KafkaChannel Middleware
+func TestUpdateSubscriptionsMiddleware(t *testing.T) {
+
+	// Test Data
+	subscriberUID := types.UID("test-subscriber-uid")
+	subscriberSpecs := []eventingduck.SubscriberSpec{{UID: subscriberUID}}
+
+	// Replace The NewConsumerGroupWrapper With A Mock For Testing & Restore After Test
+	newConsumerGroupWrapperPlaceholder := kafkaconsumer.NewConsumerGroupWrapper
+	kafkaconsumer.NewConsumerGroupWrapper = func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+		return kafkatesting.NewMockConsumerGroup(t), nil
+	}
+	defer func() {
+		kafkaconsumer.NewConsumerGroupWrapper = newConsumerGroupWrapperPlaceholder
+	}()
+
+	// Create A New DispatcherImpl To Test (Without Middleware Enabled)
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
+			Logger:       logtesting.TestLogger(t).Desugar(),
+			Topic:        testTopic,
+		},
+		subscribers: make(map[types.UID]*SubscriberWrapper),
+	}
+	defer dispatcher.Shutdown()
+
+	// Verify The Subscriber Is Created Without Middleware
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{}))
+	subscriber := dispatcher.subscribers[subscriberUID]
+	assert.Nil(t, subscriber.Middleware)
+
+	// Verify Every Subscription Fails (Retaining The Existing Subscriber) When The Selected Middleware Is Unavailable
+	failedSubscriptions := dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{Middleware: []string{"enrich"}})
+	assert.Len(t, failedSubscriptions, 1)
+	assert.Contains(t, failedSubscriptions, subscriberSpecs[0])
+	assert.Same(t, subscriber, dispatcher.subscribers[subscriberUID])
+	assert.Equal(t, []string{"enrich"}, dispatcher.subscriptionConfig.Middleware)
+}
+
+// Test The UpdateSubscriptions() Functionality With A Kafka Backed DeadLetterSink
+func TestUpdateSubscriptionsDeadLetterTopic(t *testing.T) {
+
+	// Test Data
+	subscriberSpecs := []eventingduck.SubscriberSpec{
+		{UID: uid123, Delivery: &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: kafkaconstants.DeadLetterSinkKafkaScheme}}}},
+		{UID: uid456},
+	}
+
+	// Create A New DispatcherImpl To Test With Mock ConsumerGroup & SyncProducer Factories
+	mockSyncProducer := dispatchertesting.NewMockSyncProducer(nil)
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
+			Logger:       logtesting.TestLogger(t).Desugar(),
+			Topic:        testTopic,
+			ProducerFactory: func(brokersArg []string, configArg *sarama.Config) (sarama.SyncProducer, error) {
+				assert.True(t, configArg.Producer.Return.Successes)
+				return mockSyncProducer, nil
+			},
+			ConsumerGroupFactory: func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+				return kafkatesting.NewMockConsumerGroup(t), nil
+			},
+		},
+		subscribers: make(map[types.UID]*SubscriberWrapper),
+	}
+
+	// Perform The Test
+	failedSubscriptions := dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{})
+
+	// Verify The DeadLetter Producer Was Created & Is Closed Upon Shutdown
+	assert.Empty(t, failedSubscriptions)
+	assert.Len(t, dispatcher.subscribers, 2)
+	assert.Same(t, mockSyncProducer, dispatcher.deadLetterProducer)
+	dispatcher.Shutdown()
+	assert.True(t, mockSyncProducer.Closed())
+	assert.Nil(t, dispatcher.deadLetterProducer)
+
+	// Verify A Failure To Create The DeadLetter Producer Fails Only The Kafka Backed Subscription
+	dispatcher.ProducerFactory = func(brokersArg []string, configArg *sarama.Config) (sarama.SyncProducer, error) {
+		return nil, errors.New("test producer error")
+	}
+	dispatcher.subscribers = make(map[types.UID]*SubscriberWrapper)
+	failedSubscriptions = dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{})
+	assert.Len(t, failedSubscriptions, 1)
+	assert.Contains(t, failedSubscriptions, subscriberSpecs[0])
+	assert.Len(t, dispatcher.subscribers, 1)
+	dispatcher.Shutdown()
+}
+
+// Test The UpdateSubscriptions() Functionality With A Kafka Subscriber
+func TestUpdateSubscriptionsKafkaSubscriber(t *testing.T) {
+
+	// Test Data
+	kafkaURI, _ := apis.ParseURL("kafka:///target-topic")
+	subscriberSpecs := []eventingduck.SubscriberSpec{{UID: uid123, SubscriberURI: kafkaURI}}
+
+	// Create A New DispatcherImpl To Test With Mock ConsumerGroup & SyncProducer Factories (Recording The Producer Configs)
+	saramaConfig := getSaramaConfigFromYaml(t, TestConfigBase)
+	saramaConfig.Net.SASL.Enable = true
+	saramaConfig.Net.SASL.User = "TestUser"
+	producerBrokers := make(map[string]*sarama.Config)
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: saramaConfig,
+			Logger:       logtesting.TestLogger(t).Desugar(),
+			Brokers:      []string{"own-broker:9092"},
+			Topic:        testTopic,
+			ProducerFactory: func(brokersArg []string, configArg *sarama.Config) (sarama.SyncProducer, error) {
+				assert.True(t, configArg.Producer.Return.Successes)
+				producerBrokers[fmt.Sprint(brokersArg)] = configArg
+				return dispatchertesting.NewMockSyncProducer(nil), nil
+			},
+			ConsumerGroupFactory: func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+				return kafkatesting.NewMockConsumerGroup(t), nil
+			},
+		},
+		subscribers: make(map[types.UID]*SubscriberWrapper),
+	}
+
+	// Perform The Test
+	failedSubscriptions := dispatcher.UpdateSubscriptions(subscriberSpecs, SubscriptionConfig{})
+
+	// Verify The KafkaProducers Were Created (Without Any gRPC Client)
+	assert.Empty(t, failedSubscriptions)
+	assert.Len(t, dispatcher.subscribers, 1)
+	assert.False(t, dispatcher.subscribers[uid123].Grpc)
+	assert.NotNil(t, dispatcher.kafkaProducers)
+	assert.Nil(t, dispatcher.grpcClient)
+
+	// Verify The Own Cluster Is Produced To With Its Credentials, But Other Clusters Without
+	_, err := dispatcher.createKafkaSubscriberProducer(nil)
+	assert.Nil(t, err)
+	_, err = dispatcher.createKafkaSubscriberProducer([]string{"other-broker:9092"})
+	assert.Nil(t, err)
+	assert.True(t, producerBrokers["[own-broker:9092]"].Net.SASL.Enable)
+	assert.Equal(t, "TestUser", producerBrokers["[own-broker:9092]"].Net.SASL.User)
+	assert.False(t, producerBrokers["[other-broker:9092]"].Net.SASL.Enable)
+	assert.Empty(t, producerBrokers["[other-broker:9092]"].Net.SASL.User)
+	assert.True(t, saramaConfig.Net.SASL.Enable)
+
+	// Verify The KafkaProducers Are Closed Upon Shutdown
+	dispatcher.Shutdown()
+	assert.Nil(t, dispatcher.kafkaProducers)
+}
+
 // Utility Function For Creating A SubscriberWrapper With Specified UID & Mock ConsumerGroup
 func createSubscriberWrapper(t *testing.T, uid types.UID) *SubscriberWrapper {
-	return NewSubscriberWrapper(eventingduck.SubscriberSpec{UID: uid}, fmt.Sprintf("kafka.%s", string(uid)), kafkatesting.NewMockConsumerGroup(t))
+	return NewSubscriberWrapper(eventingduck.SubscriberSpec{UID: uid}, fmt.Sprintf("kafka.%s", string(uid)), []string{testTopic}, nil, nil, "", false, 0, nil, nil, nil, kafkatesting.NewMockConsumerGroup(t))
 }
 
 func getBaseConfigMap() *corev1.ConfigMap {
@@ -389,6 +753,27 @@ func TestConfigChanged(t *testing.T) {
 	assert.NotNil(t, dispatcher)
 }
 
+// Test The Dispatcher's ConfigChanged() Functionality Retaining The ConsumerGroup Settings
+func TestConfigChangedConsumerGroup(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, constants.KnativeEventingNamespace))
+
+	// Create A Dispatcher With An Offsets Retention
+	var dispatcher Dispatcher = &DispatcherImpl{
+		DispatcherConfig:  DispatcherConfig{Logger: logger, ConsumerGroup: commonconfig.EKConsumerGroupConfig{OffsetsRetentionMillis: 1209600000}},
+		subscribers:       make(map[types.UID]*SubscriberWrapper),
+		messageDispatcher: channel.NewMessageDispatcher(logger),
+	}
+
+	// Verify The Offsets Retention Is Applied Over The ConfigMap's Sarama Settings
+	dispatcher = dispatcher.ConfigChanged(getBaseConfigMap())
+	assert.NotNil(t, dispatcher)
+	assert.Equal(t, 14*24*time.Hour, dispatcher.(*DispatcherImpl).SaramaConfig.Consumer.Offsets.Retention)
+
+	// Verify The Unchanged ConfigMap Doesn't Recreate The Dispatcher
+	assert.Nil(t, dispatcher.ConfigChanged(getBaseConfigMap()))
+}
+
 func runConfigChangedTest(t *testing.T, originalDispatcher Dispatcher, base *corev1.ConfigMap, changed string, expectedNewDispatcher bool) Dispatcher {
 	// Change the Consumer settings to the base config
 	newDispatcher := originalDispatcher.ConfigChanged(base)
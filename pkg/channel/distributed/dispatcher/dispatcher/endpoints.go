@@ -0,0 +1,275 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/network"
+)
+
+// Endpoint Load Balancing Defaults
+const (
+	DefaultEndpointCooldown = 10 * time.Second
+)
+
+//
+// Load Balancing Of Kubernetes Service Endpoints
+//
+// Deliveries to a Kubernetes Service are otherwise balanced by kube-proxy per connection, so that the long-lived
+// keep-alive connections of a busy dispatcher remain pinned to whichever pods they were first opened to.  The
+// EndpointBalancer instead delivers the HTTP requests to "<service>.<namespace>.svc" hosts directly at the pod IPs
+// of the ready endpoints in the Service's EndpointSlices, choosing the next endpoint (round-robin) for every request
+// and avoiding any endpoint whose requests failed to connect (or were answered with a 502, 503 or 504) for the
+// cooldown period.  Requests to any other host (or to a Service without ready endpoints) are sent unchanged, as are
+// gRPC deliveries.  A nil *EndpointBalancer is valid and never balances any requests.
+//
+type EndpointBalancer struct {
+	logger              *zap.Logger
+	cooldown            time.Duration
+	clusterDomain       string
+	serviceLister       corelisters.ServiceLister
+	endpointSliceLister discoverylisters.EndpointSliceLister
+	transport           nethttp.RoundTripper
+	sender              *kncloudevents.HTTPMessageSender
+	next                map[string]int       // Round-Robin Position Per Service Port
+	failed              map[string]time.Time // Endpoint Address -> End Of Cooldown
+	lock                sync.Mutex
+}
+
+// Verify The EndpointBalancer Implements The HTTP RoundTripper
+var _ nethttp.RoundTripper = &EndpointBalancer{}
+
+// Validate The Specified Endpoints Config
+func ValidateEndpointsConfig(endpointsConfig config.EKEndpointsConfig) error {
+	if endpointsConfig.CooldownMillis < 0 {
+		return fmt.Errorf("cooldownMillis %d must not be negative", endpointsConfig.CooldownMillis)
+	}
+	return nil
+}
+
+// EndpointBalancer Constructor - Returns nil If Endpoint Load Balancing Is Not Enabled (Assumes A Valid Config)
+func NewEndpointBalancer(logger *zap.Logger, endpointsConfig config.EKEndpointsConfig, serviceLister corelisters.ServiceLister, endpointSliceLister discoverylisters.EndpointSliceLister) *EndpointBalancer {
+	if !endpointsConfig.Enabled {
+		return nil
+	}
+
+	cooldown := time.Duration(endpointsConfig.CooldownMillis) * time.Millisecond
+	if cooldown <= 0 {
+		cooldown = DefaultEndpointCooldown
+	}
+
+	balancer := &EndpointBalancer{
+		logger:              logger,
+		cooldown:            cooldown,
+		clusterDomain:       network.GetClusterDomainName(),
+		serviceLister:       serviceLister,
+		endpointSliceLister: endpointSliceLister,
+		transport:           newSharedTransport(),
+		next:                make(map[string]int),
+		failed:              make(map[string]time.Time),
+	}
+
+	// Create The Sender Shared By All Subscribers (Sending Via The Balancer Itself)
+	balancer.sender = newSharedSender(balancer)
+	return balancer
+}
+
+// Create A Knative MessageDispatcher Sending Via The Balancer (Or Via The Resolver's Shared Transport If nil)
+func (b *EndpointBalancer) messageDispatcher(logger *zap.Logger, resolver *DestinationResolver) channel.MessageDispatcher {
+	if b == nil {
+		return resolver.messageDispatcher(logger)
+	}
+	return channel.NewMessageDispatcherFromSender(logger, b.sender)
+}
+
+// Send The Request To The Next Healthy Endpoint Of Its Service (Unchanged If It Is Not Addressed To A Balanced Service)
+func (b *EndpointBalancer) RoundTrip(request *nethttp.Request) (*nethttp.Response, error) {
+	address, ok := b.pick(request.URL.Scheme, request.URL.Host)
+	if !ok {
+		return b.transport.RoundTrip(request)
+	}
+
+	// Address The Endpoint Directly, Retaining The Service Host In The Host Header
+	endpointRequest := request.Clone(request.Context())
+	endpointRequest.URL.Host = address
+	if len(endpointRequest.Host) == 0 {
+		endpointRequest.Host = request.URL.Host
+	}
+
+	// Avoid The Endpoint For The Cooldown Period If It Is Unreachable Or Unavailable
+	response, err := b.transport.RoundTrip(endpointRequest)
+	if err != nil || unavailableStatusCode(response.StatusCode) {
+		b.markFailed(address, err)
+	}
+	return response, err
+}
+
+// Choose The Next Healthy Endpoint Address ("ip:port") Of The Service Addressed By The Specified Host (false If None)
+func (b *EndpointBalancer) pick(scheme string, host string) (string, bool) {
+	if b == nil || scheme != "http" {
+		return "", false
+	}
+	name, namespace, port, ok := b.parseServiceHost(host)
+	if !ok {
+		return "", false
+	}
+	addresses := b.endpointAddresses(name, namespace, port)
+	if len(addresses) == 0 {
+		return "", false
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	// Prefer The Endpoints Not Cooling Down From Failures (All Of Them If None Are Healthy)
+	now := time.Now()
+	healthy := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if cooldownEnd, failed := b.failed[address]; failed {
+			if now.Before(cooldownEnd) {
+				continue
+			}
+			delete(b.failed, address)
+		}
+		healthy = append(healthy, address)
+	}
+	if len(healthy) == 0 {
+		healthy = addresses
+	}
+
+	// Advance The Service Port's Round-Robin Position
+	key := fmt.Sprintf("%s/%s:%d", namespace, name, port)
+	position := b.next[key] % len(healthy)
+	b.next[key] = position + 1
+	return healthy[position], true
+}
+
+// Avoid The Specified Endpoint Address For The Cooldown Period
+func (b *EndpointBalancer) markFailed(address string, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, failed := b.failed[address]; !failed {
+		b.logger.Debug("Endpoint Unavailable - Cooling Down", zap.String("Address", address), zap.Duration("Cooldown", b.cooldown), zap.Error(err))
+	}
+	b.failed[address] = time.Now().Add(b.cooldown)
+}
+
+// Parse The Service Name, Namespace & Port Of A "<service>.<namespace>.svc[.<cluster-domain>][:<port>]" Host
+func (b *EndpointBalancer) parseServiceHost(host string) (string, string, int32, bool) {
+	hostname := host
+	port := int32(80)
+	if splitHost, splitPort, err := net.SplitHostPort(host); err == nil {
+		parsedPort, err := strconv.ParseInt(splitPort, 10, 32)
+		if err != nil {
+			return "", "", 0, false
+		}
+		hostname = splitHost
+		port = int32(parsedPort)
+	}
+	hostname = strings.TrimSuffix(strings.TrimSuffix(hostname, "."), "."+b.clusterDomain)
+	if !strings.HasSuffix(hostname, ".svc") {
+		return "", "", 0, false
+	}
+	parts := strings.Split(strings.TrimSuffix(hostname, ".svc"), ".")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", 0, false
+	}
+	return parts[0], parts[1], port, true
+}
+
+// Get The (Sorted) "ip:port" Addresses Of The Ready Endpoints Serving The Specified Service Port
+func (b *EndpointBalancer) endpointAddresses(name string, namespace string, port int32) []string {
+
+	// Find The Name Of The Service Port (By Which The EndpointSlices Identify Its Target Port)
+	service, err := b.serviceLister.Services(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	var portName string
+	found := false
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Port == port && (servicePort.Protocol == "" || servicePort.Protocol == corev1.ProtocolTCP) {
+			portName = servicePort.Name
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	// Collect The Ready Endpoints Of The Service's IP EndpointSlices
+	endpointSlices, err := b.endpointSliceLister.EndpointSlices(namespace).List(labels.SelectorFromSet(labels.Set{discoveryv1beta1.LabelServiceName: name}))
+	if err != nil {
+		b.logger.Warn("Failed To List EndpointSlices", zap.String("Service", namespace+"/"+name), zap.Error(err))
+		return nil
+	}
+	var addresses []string
+	for _, endpointSlice := range endpointSlices {
+		if endpointSlice.AddressType == discoveryv1beta1.AddressTypeFQDN {
+			continue
+		}
+		targetPort, ok := endpointSlicePort(endpointSlice, portName)
+		if !ok {
+			continue
+		}
+		for _, endpoint := range endpointSlice.Endpoints {
+			if len(endpoint.Addresses) == 0 || (endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready) {
+				continue
+			}
+			addresses = append(addresses, net.JoinHostPort(endpoint.Addresses[0], strconv.Itoa(int(targetPort))))
+		}
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// Utility Function For Getting The Target Port Of The Named Service Port From An EndpointSlice
+func endpointSlicePort(endpointSlice *discoveryv1beta1.EndpointSlice, portName string) (int32, bool) {
+	for _, endpointPort := range endpointSlice.Ports {
+		name := ""
+		if endpointPort.Name != nil {
+			name = *endpointPort.Name
+		}
+		if name == portName && endpointPort.Port != nil {
+			return *endpointPort.Port, true
+		}
+	}
+	return 0, false
+}
+
+// Utility Function For Determining Whether A Response StatusCode Indicates An Unavailable Endpoint
+func unavailableStatusCode(statusCode int) bool {
+	return statusCode == nethttp.StatusBadGateway || statusCode == nethttp.StatusServiceUnavailable || statusCode == nethttp.StatusGatewayTimeout
+}
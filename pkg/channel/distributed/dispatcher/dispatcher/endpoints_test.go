@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1beta1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ValidateEndpointsConfig() Functionality
+func TestValidateEndpointsConfig(t *testing.T) {
+	assert.Nil(t, ValidateEndpointsConfig(config.EKEndpointsConfig{}))
+	assert.Nil(t, ValidateEndpointsConfig(config.EKEndpointsConfig{Enabled: true, CooldownMillis: 100}))
+	assert.NotNil(t, ValidateEndpointsConfig(config.EKEndpointsConfig{CooldownMillis: -1}))
+}
+
+// Test The NewEndpointBalancer() Functionality
+func TestNewEndpointBalancer(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Disabled Load Balancing Returns nil (Which Sends Via The Resolver)
+	balancer := NewEndpointBalancer(logger, config.EKEndpointsConfig{}, nil, nil)
+	assert.Nil(t, balancer)
+	assert.NotNil(t, balancer.messageDispatcher(logger, nil))
+	_, ok := balancer.pick("http", "test-service.test-namespace.svc.cluster.local")
+	assert.False(t, ok)
+
+	// Unset Values Are Defaulted
+	balancer = NewEndpointBalancer(logger, config.EKEndpointsConfig{Enabled: true}, nil, nil)
+	assert.Equal(t, DefaultEndpointCooldown, balancer.cooldown)
+	assert.NotNil(t, balancer.messageDispatcher(logger, nil))
+
+	// Specified Values Are Used
+	balancer = NewEndpointBalancer(logger, config.EKEndpointsConfig{Enabled: true, CooldownMillis: 100}, nil, nil)
+	assert.Equal(t, 100*time.Millisecond, balancer.cooldown)
+}
+
+// Test The EndpointBalancer's Parsing Of Service Hosts
+func TestEndpointBalancerParseServiceHost(t *testing.T) {
+	balancer := &EndpointBalancer{clusterDomain: "cluster.local"}
+
+	// Define The TestCase Type
+	type TestCase struct {
+		host      string
+		name      string
+		namespace string
+		port      int32
+		ok        bool
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{host: "test-service.test-namespace.svc.cluster.local", name: "test-service", namespace: "test-namespace", port: 80, ok: true},
+		{host: "test-service.test-namespace.svc.cluster.local.:8080", name: "test-service", namespace: "test-namespace", port: 8080, ok: true},
+		{host: "test-service.test-namespace.svc", name: "test-service", namespace: "test-namespace", port: 80, ok: true},
+		{host: "test-service.test-namespace.svc.other.domain"},
+		{host: "test-service.test-namespace"},
+		{host: "sub.test-service.test-namespace.svc.cluster.local"},
+		{host: "example.com"},
+		{host: "10.0.0.1:8080"},
+	}
+
+	// Execute The TestCases
+	for _, testCase := range testCases {
+		name, namespace, port, ok := balancer.parseServiceHost(testCase.host)
+		assert.Equal(t, testCase.ok, ok, testCase.host)
+		assert.Equal(t, testCase.name, name, testCase.host)
+		assert.Equal(t, testCase.namespace, namespace, testCase.host)
+		assert.Equal(t, testCase.port, port, testCase.host)
+	}
+}
+
+// Test The EndpointBalancer's Round-Robin & Health-Aware Selection Of Endpoints
+func TestEndpointBalancerPick(t *testing.T) {
+	balancer := createTestEndpointBalancer(t, 8080,
+		newTestEndpoint("10.0.0.2", nil),
+		newTestEndpoint("10.0.0.1", boolPtr(true)),
+		newTestEndpoint("10.0.0.3", boolPtr(false)))
+
+	// Ready Endpoints Are Chosen Round-Robin (Unready Endpoints Never)
+	host := "test-service.test-namespace.svc.cluster.local"
+	for _, expected := range []string{"10.0.0.1:8080", "10.0.0.2:8080", "10.0.0.1:8080"} {
+		address, ok := balancer.pick("http", host)
+		assert.True(t, ok)
+		assert.Equal(t, expected, address)
+	}
+
+	// Failed Endpoints Are Avoided During Their Cooldown
+	balancer.markFailed("10.0.0.1:8080", nil)
+	for i := 0; i < 3; i++ {
+		address, _ := balancer.pick("http", host)
+		assert.Equal(t, "10.0.0.2:8080", address)
+	}
+
+	// All Endpoints Are Chosen If None Are Healthy
+	balancer.markFailed("10.0.0.2:8080", nil)
+	address, ok := balancer.pick("http", host)
+	assert.True(t, ok)
+	assert.Contains(t, []string{"10.0.0.1:8080", "10.0.0.2:8080"}, address)
+
+	// Endpoints Are Chosen Again Once Their Cooldown Ends
+	balancer.failed["10.0.0.1:8080"] = time.Now().Add(-time.Second)
+	address, _ = balancer.pick("http", host)
+	assert.Equal(t, "10.0.0.1:8080", address)
+	assert.NotContains(t, balancer.failed, "10.0.0.1:8080")
+
+	// Other Schemes, Hosts & Ports Are Not Balanced
+	_, ok = balancer.pick("https", host)
+	assert.False(t, ok)
+	_, ok = balancer.pick("http", "unknown.test-namespace.svc.cluster.local")
+	assert.False(t, ok)
+	_, ok = balancer.pick("http", host+":9090")
+	assert.False(t, ok)
+	_, ok = balancer.pick("http", "example.com")
+	assert.False(t, ok)
+}
+
+// Test The EndpointBalancer's RoundTrip() Functionality
+func TestEndpointBalancerRoundTrip(t *testing.T) {
+
+	// Create A Test Server Recording The Host Header & Answering With The Requested StatusCode
+	var receivedHost string
+	server := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		receivedHost = request.Host
+		statusCode, _ := strconv.Atoi(request.URL.Query().Get("status"))
+		writer.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	serverPort, _ := strconv.Atoi(serverURL.Port())
+
+	// Create A Balancer Whose Service Is Served By The Test Server
+	balancer := createTestEndpointBalancer(t, int32(serverPort), newTestEndpoint(serverURL.Hostname(), nil))
+	host := "test-service.test-namespace.svc.cluster.local"
+
+	// Requests Are Sent To The Endpoint With The Service Host
+	request, _ := nethttp.NewRequest(nethttp.MethodPost, "http://"+host+"/?status=202", nil)
+	response, err := balancer.RoundTrip(request)
+	assert.Nil(t, err)
+	assert.Equal(t, nethttp.StatusAccepted, response.StatusCode)
+	assert.Equal(t, host, receivedHost)
+	assert.Equal(t, host, request.URL.Host)
+	assert.Empty(t, balancer.failed)
+
+	// Unavailable Responses Cool Down The Endpoint
+	request, _ = nethttp.NewRequest(nethttp.MethodPost, "http://"+host+"/?status=503", nil)
+	response, err = balancer.RoundTrip(request)
+	assert.Nil(t, err)
+	assert.Equal(t, nethttp.StatusServiceUnavailable, response.StatusCode)
+	assert.Contains(t, balancer.failed, serverURL.Host)
+
+	// Requests To Other Hosts Are Sent Unchanged
+	request, _ = nethttp.NewRequest(nethttp.MethodPost, server.URL+"/?status=200", nil)
+	response, err = balancer.RoundTrip(request)
+	assert.Nil(t, err)
+	assert.Equal(t, nethttp.StatusOK, response.StatusCode)
+	assert.Equal(t, serverURL.Host, receivedHost)
+}
+
+// Utility Function For Creating An EndpointBalancer Of A "test-service" Whose Port 80 Targets The Specified Port Of The Endpoints
+func createTestEndpointBalancer(t *testing.T, targetPort int32, endpoints ...discoveryv1beta1.Endpoint) *EndpointBalancer {
+	portName := "http"
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: portName, Port: 80}}},
+	}
+	endpointSlice := &discoveryv1beta1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-service-abcde",
+			Namespace: "test-namespace",
+			Labels:    map[string]string{discoveryv1beta1.LabelServiceName: "test-service"},
+		},
+		AddressType: discoveryv1beta1.AddressTypeIPv4,
+		Endpoints:   endpoints,
+		Ports:       []discoveryv1beta1.EndpointPort{{Name: &portName, Port: &targetPort}},
+	}
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.Nil(t, serviceIndexer.Add(service))
+	endpointSliceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.Nil(t, endpointSliceIndexer.Add(endpointSlice))
+	balancer := NewEndpointBalancer(logtesting.TestLogger(t).Desugar(), config.EKEndpointsConfig{Enabled: true},
+		corelisters.NewServiceLister(serviceIndexer), discoverylisters.NewEndpointSliceLister(endpointSliceIndexer))
+	balancer.clusterDomain = "cluster.local"
+	return balancer
+}
+
+// Utility Function For Creating An Endpoint With The Specified Address & Ready Condition
+func newTestEndpoint(address string, ready *bool) discoveryv1beta1.Endpoint {
+	return discoveryv1beta1.Endpoint{Addresses: []string{address}, Conditions: discoveryv1beta1.EndpointConditions{Ready: ready}}
+}
+
+// Utility Function For Getting A Pointer To A bool
+func boolPtr(value bool) *bool {
+	return &value
+}
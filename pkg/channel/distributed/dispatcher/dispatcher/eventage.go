@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Stale Event Policies
+const (
+	StaleEventPolicySkip       = "skip"       // Stale Events Are Skipped (Default)
+	StaleEventPolicyDeadLetter = "deadletter" // Stale Events Are Sent To The Subscriber's DeadLetterSink
+)
+
+// The CloudEvent Time Header Of Binary Mode Kafka Messages
+const ceTimeHeader = "ce_time"
+
+//
+// Maximum Event Age Policy Of A Single Subscriber
+//
+// Events older than the MaxEventAge (based on the CloudEvent time, or the Kafka record timestamp if there
+// is none) are not dispatched to the subscriber, but are instead skipped or sent to the DeadLetterSink.
+// This allows backlogged subscribers to recover without processing large amounts of stale data.
+//
+// A nil *EventAgePolicy is valid and represents the default behavior of dispatching all events.
+//
+type EventAgePolicy struct {
+	MaxEventAge time.Duration
+	DeadLetter  bool
+}
+
+// The EventAgePolicies Of A KafkaChannel's Subscribers Keyed By Subscriber UID
+type EventAgePolicies map[string]*EventAgePolicy
+
+// The JSON Representation Of An EventAgePolicy In The KafkaChannel Annotation
+type eventAgePolicyJson struct {
+	MaxEventAge string `json:"maxEventAge"`
+	Policy      string `json:"policy,omitempty"`
+}
+
+// Create The EventAgePolicies From The Specified KafkaChannel Annotations (nil If None)
+func NewEventAgePolicies(annotations map[string]string) (EventAgePolicies, error) {
+
+	// No Policies If The Annotation Is Not Specified
+	policiesJson := annotations[constants.SubscriberMaxEventAgeAnnotation]
+	if len(policiesJson) == 0 {
+		return nil, nil
+	}
+
+	// Parse The Annotation's JSON Map Of Subscriber UID To Policy
+	var subscriberPolicies map[string]eventAgePolicyJson
+	err := json.Unmarshal([]byte(policiesJson), &subscriberPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", constants.SubscriberMaxEventAgeAnnotation, err)
+	}
+
+	// Validate & Convert Each Subscriber's Policy
+	eventAgePolicies := make(EventAgePolicies, len(subscriberPolicies))
+	for subscriberUID, subscriberPolicy := range subscriberPolicies {
+		maxEventAge, err := time.ParseDuration(subscriberPolicy.MaxEventAge)
+		if err != nil || maxEventAge <= 0 {
+			return nil, fmt.Errorf("subscriber %s declares invalid maxEventAge %q in the %s annotation", subscriberUID, subscriberPolicy.MaxEventAge, constants.SubscriberMaxEventAgeAnnotation)
+		}
+		switch subscriberPolicy.Policy {
+		case "", StaleEventPolicySkip, StaleEventPolicyDeadLetter:
+		default:
+			return nil, fmt.Errorf("subscriber %s declares unknown policy %q in the %s annotation", subscriberUID, subscriberPolicy.Policy, constants.SubscriberMaxEventAgeAnnotation)
+		}
+		eventAgePolicies[subscriberUID] = &EventAgePolicy{
+			MaxEventAge: maxEventAge,
+			DeadLetter:  subscriberPolicy.Policy == StaleEventPolicyDeadLetter,
+		}
+	}
+
+	// Return The EventAgePolicies
+	return eventAgePolicies, nil
+}
+
+// Get The EventAgePolicy Of The Specified Subscriber (nil If None)
+func (p EventAgePolicies) Policy(subscriberUID string) *EventAgePolicy {
+	return p[subscriberUID]
+}
+
+// Determine Whether The Specified Message Is Older Than The MaxEventAge As Of The Specified Time (Returning Its Age)
+func (p *EventAgePolicy) Exceeded(consumerMessage *sarama.ConsumerMessage, now time.Time) (time.Duration, bool) {
+	if p == nil {
+		return 0, false
+	}
+	eventTime := messageTime(consumerMessage)
+	if eventTime.IsZero() {
+		return 0, false
+	}
+	eventAge := now.Sub(eventTime)
+	return eventAge, eventAge > p.MaxEventAge
+}
+
+// Utility Function For Getting The CloudEvent Time Of A Binary Mode Message, Falling Back To The Kafka Record Timestamp
+func messageTime(consumerMessage *sarama.ConsumerMessage) time.Time {
+	for _, header := range consumerMessage.Headers {
+		if header != nil && string(header.Key) == ceTimeHeader {
+			if eventTime, err := time.Parse(time.RFC3339Nano, string(header.Value)); err == nil {
+				return eventTime
+			}
+		}
+	}
+	return consumerMessage.Timestamp
+}
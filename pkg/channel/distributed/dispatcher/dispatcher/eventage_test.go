@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Test The NewEventAgePolicies() Functionality
+func TestNewEventAgePolicies(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name         string
+		annotations  map[string]string
+		wantPolicies EventAgePolicies
+		wantError    string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name: "No Annotation",
+		},
+		{
+			name:        "Valid Policies",
+			annotations: map[string]string{kafkaconstants.SubscriberMaxEventAgeAnnotation: `{"uid-1":{"maxEventAge":"1h"},"uid-2":{"maxEventAge":"90s","policy":"deadletter"}}`},
+			wantPolicies: EventAgePolicies{
+				"uid-1": {MaxEventAge: time.Hour},
+				"uid-2": {MaxEventAge: 90 * time.Second, DeadLetter: true},
+			},
+		},
+		{
+			name:        "Invalid JSON",
+			annotations: map[string]string{kafkaconstants.SubscriberMaxEventAgeAnnotation: "invalid"},
+			wantError:   "invalid kafka.eventing.knative.dev/subscriber-max-event-age annotation: invalid character 'i' looking for beginning of value",
+		},
+		{
+			name:        "Invalid MaxEventAge",
+			annotations: map[string]string{kafkaconstants.SubscriberMaxEventAgeAnnotation: `{"uid-1":{"maxEventAge":"-1h"}}`},
+			wantError:   `subscriber uid-1 declares invalid maxEventAge "-1h" in the kafka.eventing.knative.dev/subscriber-max-event-age annotation`,
+		},
+		{
+			name:        "Unknown Policy",
+			annotations: map[string]string{kafkaconstants.SubscriberMaxEventAgeAnnotation: `{"uid-1":{"maxEventAge":"1h","policy":"drop"}}`},
+			wantError:   `subscriber uid-1 declares unknown policy "drop" in the kafka.eventing.knative.dev/subscriber-max-event-age annotation`,
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			policies, err := NewEventAgePolicies(testCase.annotations)
+			assert.Equal(t, testCase.wantPolicies, policies)
+			if len(testCase.wantError) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.wantError, err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+
+	// Verify Policy Lookup (Including On nil EventAgePolicies)
+	var nilPolicies EventAgePolicies
+	assert.Nil(t, nilPolicies.Policy("uid-1"))
+	policies := EventAgePolicies{"uid-1": {MaxEventAge: time.Hour}}
+	assert.Equal(t, &EventAgePolicy{MaxEventAge: time.Hour}, policies.Policy("uid-1"))
+	assert.Nil(t, policies.Policy("uid-2"))
+}
+
+// Test The EventAgePolicy's Exceeded() Functionality
+func TestEventAgePolicyExceeded(t *testing.T) {
+
+	// Test Data
+	now := time.Now()
+	policy := &EventAgePolicy{MaxEventAge: time.Minute}
+	ceTimeMessage := func(ceTime time.Time) *sarama.ConsumerMessage {
+		return &sarama.ConsumerMessage{
+			Headers:   []*sarama.RecordHeader{{Key: []byte("ce_time"), Value: []byte(ceTime.Format(time.RFC3339Nano))}},
+			Timestamp: now,
+		}
+	}
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name         string
+		policy       *EventAgePolicy
+		message      *sarama.ConsumerMessage
+		wantAge      time.Duration
+		wantExceeded bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:    "Nil Policy",
+			message: ceTimeMessage(now.Add(-time.Hour)),
+		},
+		{
+			name:    "Fresh CloudEvent Time",
+			policy:  policy,
+			message: ceTimeMessage(now.Add(-time.Second)),
+			wantAge: time.Second,
+		},
+		{
+			name:         "Stale CloudEvent Time (Preferred Over Record Timestamp)",
+			policy:       policy,
+			message:      ceTimeMessage(now.Add(-time.Hour)),
+			wantAge:      time.Hour,
+			wantExceeded: true,
+		},
+		{
+			name:         "Stale Record Timestamp",
+			policy:       policy,
+			message:      &sarama.ConsumerMessage{Timestamp: now.Add(-2 * time.Minute)},
+			wantAge:      2 * time.Minute,
+			wantExceeded: true,
+		},
+		{
+			name:    "No Time",
+			policy:  policy,
+			message: &sarama.ConsumerMessage{},
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			age, exceeded := testCase.policy.Exceeded(testCase.message, now)
+			assert.Equal(t, testCase.wantExceeded, exceeded)
+			assert.Equal(t, testCase.wantAge, age)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// The EventFilters Of A KafkaChannel's Subscribers Keyed By Subscriber UID
+type SubscriberFilters map[string]EventFilter
+
+// Create The SubscriberFilters From The Specified KafkaChannel Annotations (nil If None)
+func NewSubscriberFilters(annotations map[string]string) (SubscriberFilters, error) {
+
+	// No Filters If The Annotation Is Not Specified
+	filtersJson := annotations[constants.SubscriberFiltersAnnotation]
+	if len(filtersJson) == 0 {
+		return nil, nil
+	}
+
+	// Parse The Annotation's JSON Map Of Subscriber UID To Attribute Filter
+	var subscriberFilters SubscriberFilters
+	err := json.Unmarshal([]byte(filtersJson), &subscriberFilters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", constants.SubscriberFiltersAnnotation, err)
+	}
+
+	// Return The SubscriberFilters
+	return subscriberFilters, nil
+}
+
+// Create The EventFilter Of A Subscription From Its Annotations (nil If None)
+func NewSubscriptionFilter(annotations map[string]string) (EventFilter, error) {
+	filterJson := annotations[constants.SubscriptionFilterAnnotation]
+	if len(filterJson) == 0 {
+		return nil, nil
+	}
+	var eventFilter EventFilter
+	err := json.Unmarshal([]byte(filterJson), &eventFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", constants.SubscriptionFilterAnnotation, err)
+	}
+	return eventFilter, nil
+}
+
+// Add The Specified Filters Of Subscribers Without A Filter (The Existing Filters Take Precedence)
+func (f SubscriberFilters) Merge(filters SubscriberFilters) SubscriberFilters {
+	if len(filters) == 0 {
+		return f
+	}
+	merged := make(SubscriberFilters, len(f)+len(filters))
+	for subscriberUID, eventFilter := range filters {
+		merged[subscriberUID] = eventFilter
+	}
+	for subscriberUID, eventFilter := range f {
+		if len(eventFilter) > 0 {
+			merged[subscriberUID] = eventFilter
+		}
+	}
+	return merged
+}
+
+// Get The EventFilter Of The Specified Subscriber (nil If Unfiltered)
+func (f SubscriberFilters) Filter(subscriberUID string) EventFilter {
+	if len(f[subscriberUID]) == 0 {
+		return nil
+	}
+	return f[subscriberUID]
+}
+
+//
+// CloudEvent Attribute Filter Of A Single Subscriber
+//
+// The filter is a map of CloudEvent context attribute (or extension) names to values, with the same semantics as
+// the attribute filter of a Knative Trigger - an event matches if each of its attributes equals the specified value,
+// and an empty value matches any value (including a missing attribute).  Events which do not match are skipped,
+// so that each of a Broker's Triggers can be dispatched to directly from the Broker's Topic.
+//
+// A nil EventFilter is valid and matches every event.
+//
+type EventFilter map[string]string
+
+// Determine Whether The Specified Record's CloudEvent Matches The Filter (Records Which Cannot Be Decoded Never Match)
+func (f EventFilter) Matches(ctx context.Context, message *recordMessage) bool {
+	if len(f) == 0 {
+		return true
+	}
+	filteredEvent, err := message.decode(ctx)
+	if err != nil {
+		return false
+	}
+	for name, value := range f {
+		if len(value) == 0 {
+			continue
+		}
+		if attribute, ok := eventAttribute(filteredEvent, name); !ok || attribute != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Utility Function For Getting The String Value Of A CloudEvent Context Attribute Or Extension
+func eventAttribute(cloudEvent *event.Event, name string) (string, bool) {
+	switch name {
+	case "specversion":
+		return cloudEvent.SpecVersion(), true
+	case "id":
+		return cloudEvent.ID(), true
+	case "type":
+		return cloudEvent.Type(), true
+	case "source":
+		return cloudEvent.Source(), true
+	case "subject":
+		return cloudEvent.Subject(), len(cloudEvent.Subject()) > 0
+	case "datacontenttype":
+		return cloudEvent.DataContentType(), len(cloudEvent.DataContentType()) > 0
+	case "dataschema", "schemaurl":
+		return cloudEvent.DataSchema(), len(cloudEvent.DataSchema()) > 0
+	case "time":
+		return cloudEvent.Time().UTC().Format(time.RFC3339Nano), !cloudEvent.Time().IsZero()
+	}
+	extension, ok := cloudEvent.Extensions()[name]
+	if !ok {
+		return "", false
+	}
+	value, err := types.ToString(extension)
+	return value, err == nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewSubscriberFilters() Functionality
+func TestNewSubscriberFilters(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		annotations map[string]string
+		wantFilters SubscriberFilters
+		wantError   string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name: "No Annotation",
+		},
+		{
+			name:        "Valid Filters",
+			annotations: map[string]string{kafkaconstants.SubscriberFiltersAnnotation: `{"uid-1":{"type":"type.a"},"uid-2":{"source":"","myextension":"value"}}`},
+			wantFilters: SubscriberFilters{"uid-1": {"type": "type.a"}, "uid-2": {"source": "", "myextension": "value"}},
+		},
+		{
+			name:        "Invalid JSON",
+			annotations: map[string]string{kafkaconstants.SubscriberFiltersAnnotation: "invalid"},
+			wantError:   "invalid kafka.eventing.knative.dev/subscriber-filters annotation: invalid character 'i' looking for beginning of value",
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := NewSubscriberFilters(testCase.annotations)
+			assert.Equal(t, testCase.wantFilters, filters)
+			if len(testCase.wantError) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.wantError, err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// Test The NewSubscriptionFilter() Functionality
+func TestNewSubscriptionFilter(t *testing.T) {
+	filter, err := NewSubscriptionFilter(nil)
+	assert.Nil(t, filter)
+	assert.Nil(t, err)
+
+	filter, err = NewSubscriptionFilter(map[string]string{kafkaconstants.SubscriptionFilterAnnotation: `{"type":"type.a","source":""}`})
+	assert.Equal(t, EventFilter{"type": "type.a", "source": ""}, filter)
+	assert.Nil(t, err)
+
+	filter, err = NewSubscriptionFilter(map[string]string{kafkaconstants.SubscriptionFilterAnnotation: "invalid"})
+	assert.Nil(t, filter)
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid kafka.eventing.knative.dev/filter annotation: invalid character 'i' looking for beginning of value", err.Error())
+}
+
+// Test The SubscriberFilters' Merge() Functionality
+func TestSubscriberFiltersMerge(t *testing.T) {
+	filters := SubscriberFilters{"uid-1": {"type": "type.a"}, "uid-2": {}}
+	assert.Equal(t, filters, filters.Merge(nil))
+	assert.Equal(t, SubscriberFilters{"uid-3": {"type": "type.c"}}, SubscriberFilters(nil).Merge(SubscriberFilters{"uid-3": {"type": "type.c"}}))
+	merged := filters.Merge(SubscriberFilters{"uid-1": {"type": "type.b"}, "uid-2": {"type": "type.b"}, "uid-3": {"type": "type.c"}})
+	assert.Equal(t, SubscriberFilters{"uid-1": {"type": "type.a"}, "uid-2": {"type": "type.b"}, "uid-3": {"type": "type.c"}}, merged)
+}
+
+// Test The SubscriberFilters' Filter() Functionality
+func TestSubscriberFiltersFilter(t *testing.T) {
+	var nilFilters SubscriberFilters
+	assert.Nil(t, nilFilters.Filter("uid-1"))
+	subscriberFilters := SubscriberFilters{"uid-1": {"type": "type.a"}, "uid-2": {}}
+	assert.Equal(t, EventFilter{"type": "type.a"}, subscriberFilters.Filter("uid-1"))
+	assert.Nil(t, subscriberFilters.Filter("uid-2"))
+	assert.Nil(t, subscriberFilters.Filter("uid-3"))
+}
+
+// Test The EventFilter's Matches() Functionality
+func TestEventFilterMatches(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name   string
+		filter EventFilter
+		want   bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Nil Filter", want: true},
+		{name: "Matching Type", filter: EventFilter{"type": testMsgType}, want: true},
+		{name: "Matching Type & Source", filter: EventFilter{"type": testMsgType, "source": testMsgSource}, want: true},
+		{name: "Matching Extension", filter: EventFilter{"eventtypeversion": testMsgEventTypeVersion}, want: true},
+		{name: "Any Value Of Missing Attribute", filter: EventFilter{"subject": ""}, want: true},
+		{name: "Different Type", filter: EventFilter{"type": "other.type"}, want: false},
+		{name: "Different Extension", filter: EventFilter{"eventtypeversion": "other"}, want: false},
+		{name: "Missing Attribute", filter: EventFilter{"subject": "test-subject"}, want: false},
+		{name: "Missing Extension", filter: EventFilter{"missingextension": "value"}, want: false},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.filter.Matches(context.TODO(), newRecordMessage(createConsumerMessage(t))))
+		})
+	}
+
+	// Records Which Cannot Be Decoded Never Match A Filter
+	assert.False(t, EventFilter{"type": testMsgType}.Matches(context.TODO(), newRecordMessage(&sarama.ConsumerMessage{Value: []byte("garbage")})))
+}
+
+// Test The Handler's consumeMessage() Functionality With A Subscriber Filter
+func TestHandlerConsumeMessageFilter(t *testing.T) {
+
+	// Test Data
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+
+	for _, filter := range []EventFilter{{"type": testMsgType}, {"type": "other.type"}} {
+
+		// Create A Handler With Mock MessageDispatcher & The EventFilter
+		mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, nil)
+		handler := &Handler{
+			Logger:            logtesting.TestLogger(t).Desugar(),
+			Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+			MessageDispatcher: mockMessageDispatcher,
+			Filter:            filter,
+		}
+
+		// Verify The Event Is Only Dispatched If It Matches The Filter (Filtered Events Are Skipped Without Error)
+		assert.Nil(t, handler.consumeMessage(context.TODO(), createConsumerMessage(t), destinationUrl, nil, nil, &retryConfig))
+		assert.Equal(t, filter["type"] == testMsgType, mockMessageDispatcher.Message() != nil)
+	}
+}
@@ -0,0 +1,412 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+// Subscriber URI Schemes Implying gRPC Delivery
+const (
+	GrpcScheme       = "grpc"  // Plaintext (h2c) gRPC
+	GrpcSecureScheme = "grpcs" // gRPC Over TLS
+)
+
+// The CloudEvents gRPC Service Method Used To Deliver Events
+const grpcPublishMethod = "/io.cloudevents.v1.CloudEventService/Publish"
+
+// The Set Of Subscriber UIDs Opted Into gRPC Delivery Via The KafkaChannel Annotation
+type GrpcSubscribers map[string]bool
+
+// Create The GrpcSubscribers From The Specified KafkaChannel Annotations (nil If None)
+func NewGrpcSubscribers(annotations map[string]string) GrpcSubscribers {
+	var grpcSubscribers GrpcSubscribers
+	for _, subscriberUID := range strings.Split(annotations[constants.GrpcSubscribersAnnotation], ",") {
+		if subscriberUID = strings.TrimSpace(subscriberUID); len(subscriberUID) > 0 {
+			if grpcSubscribers == nil {
+				grpcSubscribers = make(GrpcSubscribers)
+			}
+			grpcSubscribers[subscriberUID] = true
+		}
+	}
+	return grpcSubscribers
+}
+
+// Determine Whether Events Are Delivered To The Specified Subscriber Via gRPC (Opted In Or A gRPC SubscriberURI Scheme - Never For Kafka Subscribers)
+func (s GrpcSubscribers) Enabled(subscriberSpec *eventingduck.SubscriberSpec) bool {
+	if subscriberSpec.SubscriberURI.IsEmpty() {
+		return false
+	}
+	switch subscriberSpec.SubscriberURI.Scheme {
+	case GrpcScheme, GrpcSecureScheme:
+		return true
+	case KafkaSubscriberScheme:
+		return false
+	}
+	return s[string(subscriberSpec.UID)]
+}
+
+//
+// gRPC Client For Delivering CloudEvents To Subscribers
+//
+// Events are delivered with the Publish method of the CloudEvents gRPC service, encoded in the CloudEvents
+// protobuf format.  A single ClientConn is maintained per subscriber host and reused by all subscribers
+// (and partitions) delivering to it, with concurrent deliveries multiplexed as streams over its HTTP/2
+// connection.  A nil *GrpcClient is valid and simply fails all deliveries.
+//
+type GrpcClient struct {
+	logger          *zap.Logger
+	connections     map[string]*grpc.ClientConn
+	connectionsLock sync.Mutex
+}
+
+// GrpcClient Constructor
+func NewGrpcClient(logger *zap.Logger) *GrpcClient {
+	return &GrpcClient{
+		logger:      logger,
+		connections: make(map[string]*grpc.ClientConn),
+	}
+}
+
+// Publish The Specified Event To The Specified gRPC Destination
+func (c *GrpcClient) Publish(ctx context.Context, event *event.Event, destinationURL *url.URL) error {
+
+	// Validate The GrpcClient
+	if c == nil {
+		return fmt.Errorf("no grpc client available for delivery to %s", destinationURL)
+	}
+
+	// Encode The Event As A CloudEvents gRPC PublishRequest
+	request, err := encodeGrpcPublishRequest(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event for grpc delivery: %w", err)
+	}
+
+	// Get The (Shared) ClientConn For The Destination
+	connection, err := c.connection(ctx, destinationURL)
+	if err != nil {
+		return err
+	}
+
+	// Invoke The Publish Method (The Response Is An Empty Message)
+	var response []byte
+	return connection.Invoke(ctx, grpcPublishMethod, request, &response, grpc.ForceCodec(grpcRawCodec{}))
+}
+
+// Close All ClientConns Of The GrpcClient
+func (c *GrpcClient) Close() {
+	if c == nil {
+		return
+	}
+	c.connectionsLock.Lock()
+	defer c.connectionsLock.Unlock()
+	for target, connection := range c.connections {
+		if err := connection.Close(); err != nil {
+			c.logger.Warn("Failed To Close gRPC Connection", zap.String("Target", target), zap.Error(err))
+		}
+		delete(c.connections, target)
+	}
+}
+
+// Close The ClientConns Of The Specified Host (e.g. Whose Addresses Changed), Which Are Recreated On Demand
+func (c *GrpcClient) Invalidate(host string) {
+	if c == nil {
+		return
+	}
+	c.connectionsLock.Lock()
+	defer c.connectionsLock.Unlock()
+	for key, connection := range c.connections {
+		target := strings.TrimPrefix(key, GrpcSecureScheme+"://")
+		if targetHost, _, err := net.SplitHostPort(target); err != nil || targetHost != host {
+			continue
+		}
+		if err := connection.Close(); err != nil {
+			c.logger.Warn("Failed To Close gRPC Connection", zap.String("Target", target), zap.Error(err))
+		}
+		c.logger.Info("Closed gRPC Connection Of Changed Host", zap.String("Target", target))
+		delete(c.connections, key)
+	}
+}
+
+// Get Or Lazily Create The ClientConn For The Specified Destination
+func (c *GrpcClient) connection(ctx context.Context, destinationURL *url.URL) (*grpc.ClientConn, error) {
+
+	// Determine The Dial Target & Transport Security Of The Destination
+	target, secure := grpcTarget(destinationURL)
+	key := target
+	if secure {
+		key = GrpcSecureScheme + "://" + target
+	}
+
+	// Thread Safe ;)
+	c.connectionsLock.Lock()
+	defer c.connectionsLock.Unlock()
+
+	// Reuse Any Existing ClientConn
+	if connection, ok := c.connections[key]; ok {
+		return connection, nil
+	}
+
+	// Otherwise Create A New ClientConn (Non-Blocking - Connects In The Background)
+	transportOption := grpc.WithInsecure()
+	if secure {
+		transportOption = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+	connection, err := grpc.DialContext(ctx, target, transportOption)
+	if err != nil {
+		c.logger.Error("Failed To Create gRPC Connection", zap.String("Target", target), zap.Error(err))
+		return nil, fmt.Errorf("failed to create grpc connection to %s: %w", target, err)
+	}
+	c.logger.Info("Created gRPC Connection", zap.String("Target", target), zap.Bool("Secure", secure))
+	c.connections[key] = connection
+	return connection, nil
+}
+
+// Utility Function For Determining The gRPC Dial Target (host:port) & Transport Security Of A Destination
+func grpcTarget(destinationURL *url.URL) (string, bool) {
+	secure := destinationURL.Scheme == GrpcSecureScheme || destinationURL.Scheme == "https"
+	port := destinationURL.Port()
+	if len(port) == 0 {
+		port = "80"
+		if secure {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(destinationURL.Hostname(), port), secure
+}
+
+// Utility Function For Mapping A gRPC Delivery Error To The Equivalent HTTP StatusCode (For Retry & DeadLetter Handling)
+func grpcStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	switch status.Code(err) {
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError // Unknown, Internal & DataLoss
+	}
+}
+
+// gRPC Codec Passing Through Pre-Encoded Protobuf Messages (Avoids Requiring Generated CloudEvents Protobuf Types)
+type grpcRawCodec struct{}
+
+func (grpcRawCodec) Name() string { return "proto" }
+
+func (grpcRawCodec) Marshal(v interface{}) ([]byte, error) {
+	if data, ok := v.([]byte); ok {
+		return data, nil
+	}
+	return nil, fmt.Errorf("unsupported grpc message type %T", v)
+}
+
+func (grpcRawCodec) Unmarshal(data []byte, v interface{}) error {
+	if message, ok := v.(*[]byte); ok {
+		*message = data
+		return nil
+	}
+	return fmt.Errorf("unsupported grpc message type %T", v)
+}
+
+// Field Numbers Of The CloudEvents Protobuf Format (io.cloudevents.v1.CloudEvent)
+const (
+	ceProtoFieldId          protowire.Number = 1
+	ceProtoFieldSource      protowire.Number = 2
+	ceProtoFieldSpecVersion protowire.Number = 3
+	ceProtoFieldType        protowire.Number = 4
+	ceProtoFieldAttributes  protowire.Number = 5
+	ceProtoFieldBinaryData  protowire.Number = 6
+	ceProtoFieldTextData    protowire.Number = 7
+
+	ceProtoAttrBoolean   protowire.Number = 1
+	ceProtoAttrInteger   protowire.Number = 2
+	ceProtoAttrString    protowire.Number = 3
+	ceProtoAttrBytes     protowire.Number = 4
+	ceProtoAttrUri       protowire.Number = 5
+	ceProtoAttrUriRef    protowire.Number = 6
+	ceProtoAttrTimestamp protowire.Number = 7
+
+	grpcPublishRequestFieldEvent protowire.Number = 1
+)
+
+// Encode The Specified Event As A CloudEvents gRPC PublishRequest
+func encodeGrpcPublishRequest(event *event.Event) ([]byte, error) {
+	cloudEvent, err := encodeCloudEventProto(event)
+	if err != nil {
+		return nil, err
+	}
+	request := protowire.AppendTag(nil, grpcPublishRequestFieldEvent, protowire.BytesType)
+	return protowire.AppendBytes(request, cloudEvent), nil
+}
+
+// Encode The Specified Event In The CloudEvents Protobuf Format
+func encodeCloudEventProto(event *event.Event) ([]byte, error) {
+
+	// Append The Required Attributes
+	var b []byte
+	b = appendProtoString(b, ceProtoFieldId, event.ID())
+	b = appendProtoString(b, ceProtoFieldSource, event.Source())
+	b = appendProtoString(b, ceProtoFieldSpecVersion, event.SpecVersion())
+	b = appendProtoString(b, ceProtoFieldType, event.Type())
+
+	// Append The Optional Attributes & Extensions To The Attributes Map
+	if contentType := event.DataContentType(); len(contentType) > 0 {
+		b = appendProtoAttribute(b, "datacontenttype", ceProtoAttrString, protowire.AppendString(nil, contentType))
+	}
+	if dataSchema := event.DataSchema(); len(dataSchema) > 0 {
+		b = appendProtoAttribute(b, "dataschema", ceProtoAttrUri, protowire.AppendString(nil, dataSchema))
+	}
+	if subject := event.Subject(); len(subject) > 0 {
+		b = appendProtoAttribute(b, "subject", ceProtoAttrString, protowire.AppendString(nil, subject))
+	}
+	if eventTime := event.Time(); !eventTime.IsZero() {
+		b = appendProtoAttribute(b, "time", ceProtoAttrTimestamp, encodeProtoTimestamp(eventTime))
+	}
+	for name, value := range event.Extensions() {
+		attrField, attrValue, err := encodeProtoExtension(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extension %s: %w", name, err)
+		}
+		b = appendProtoAttribute(b, name, attrField, attrValue)
+	}
+
+	// Append Any Data (Textual Data As text_data, Otherwise binary_data)
+	if data := event.Data(); len(data) > 0 {
+		if isTextContentType(event.DataMediaType()) {
+			b = appendProtoString(b, ceProtoFieldTextData, string(data))
+		} else {
+			b = protowire.AppendTag(b, ceProtoFieldBinaryData, protowire.BytesType)
+			b = protowire.AppendBytes(b, data)
+		}
+	}
+
+	// Return The Encoded CloudEvent
+	return b, nil
+}
+
+// Utility Function For Appending A String Field
+func appendProtoString(b []byte, field protowire.Number, value string) []byte {
+	b = protowire.AppendTag(b, field, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+// Utility Function For Appending An Attributes Map Entry (The Value Is The Encoded CloudEventAttributeValue Field Payload)
+func appendProtoAttribute(b []byte, name string, attrField protowire.Number, attrValue []byte) []byte {
+
+	// Encode The CloudEventAttributeValue (Varint Fields Are Appended As Is, Others As Length Delimited)
+	var value []byte
+	switch attrField {
+	case ceProtoAttrBoolean, ceProtoAttrInteger:
+		value = protowire.AppendTag(nil, attrField, protowire.VarintType)
+		value = append(value, attrValue...)
+	default:
+		value = protowire.AppendTag(nil, attrField, protowire.BytesType)
+		if attrField == ceProtoAttrTimestamp {
+			value = protowire.AppendBytes(value, attrValue)
+		} else {
+			value = append(value, attrValue...) // Already Length Prefixed
+		}
+	}
+
+	// Encode The Map Entry (Key = 1, Value = 2)
+	var entry []byte
+	entry = appendProtoString(entry, 1, name)
+	entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+	entry = protowire.AppendBytes(entry, value)
+
+	// Append The Map Entry
+	b = protowire.AppendTag(b, ceProtoFieldAttributes, protowire.BytesType)
+	return protowire.AppendBytes(b, entry)
+}
+
+// Utility Function For Encoding A google.protobuf.Timestamp
+func encodeProtoTimestamp(t time.Time) []byte {
+	var b []byte
+	if seconds := t.Unix(); seconds != 0 {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(seconds))
+	}
+	if nanos := t.Nanosecond(); nanos != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(nanos))
+	}
+	return b
+}
+
+// Utility Function For Encoding An Extension Value As A CloudEventAttributeValue Field
+func encodeProtoExtension(value interface{}) (protowire.Number, []byte, error) {
+	switch v := value.(type) {
+	case bool:
+		return ceProtoAttrBoolean, protowire.AppendVarint(nil, protowire.EncodeBool(v)), nil
+	case int32:
+		return ceProtoAttrInteger, protowire.AppendVarint(nil, uint64(v)), nil
+	case string:
+		return ceProtoAttrString, protowire.AppendString(nil, v), nil
+	case []byte:
+		return ceProtoAttrBytes, protowire.AppendBytes(nil, v), nil
+	case types.URI:
+		return ceProtoAttrUri, protowire.AppendString(nil, v.String()), nil
+	case types.URIRef:
+		return ceProtoAttrUriRef, protowire.AppendString(nil, v.String()), nil
+	case types.Timestamp:
+		return ceProtoAttrTimestamp, encodeProtoTimestamp(v.Time), nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported extension type %T", value)
+	}
+}
+
+// Utility Function For Determining Whether A Media Type Is Textual (Encoded As text_data)
+func isTextContentType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") || strings.HasSuffix(mediaType, "xml")
+}
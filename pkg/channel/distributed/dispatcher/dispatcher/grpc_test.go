@@ -0,0 +1,362 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewGrpcSubscribers() & Enabled() Functionality
+func TestGrpcSubscribers(t *testing.T) {
+
+	httpURI, _ := apis.ParseURL("http://subscriber.ns.svc.cluster.local")
+	grpcURI, _ := apis.ParseURL("grpc://subscriber.ns.svc.cluster.local:8080")
+	grpcsURI, _ := apis.ParseURL("grpcs://subscriber.example.com")
+
+	assert.Nil(t, NewGrpcSubscribers(nil))
+	assert.Nil(t, NewGrpcSubscribers(map[string]string{kafkaconstants.GrpcSubscribersAnnotation: " , "}))
+
+	grpcSubscribers := NewGrpcSubscribers(map[string]string{kafkaconstants.GrpcSubscribersAnnotation: "uid-1, uid-2"})
+	assert.Equal(t, GrpcSubscribers{"uid-1": true, "uid-2": true}, grpcSubscribers)
+
+	assert.True(t, grpcSubscribers.Enabled(&eventingduck.SubscriberSpec{UID: "uid-1", SubscriberURI: httpURI}))
+	assert.False(t, grpcSubscribers.Enabled(&eventingduck.SubscriberSpec{UID: "uid-3", SubscriberURI: httpURI}))
+	assert.False(t, grpcSubscribers.Enabled(&eventingduck.SubscriberSpec{UID: "uid-1"}))
+	assert.True(t, grpcSubscribers.Enabled(&eventingduck.SubscriberSpec{UID: "uid-3", SubscriberURI: grpcURI}))
+	assert.True(t, GrpcSubscribers(nil).Enabled(&eventingduck.SubscriberSpec{UID: "uid-3", SubscriberURI: grpcsURI}))
+}
+
+// Test The grpcTarget() Functionality
+func TestGrpcTarget(t *testing.T) {
+	for rawURL, want := range map[string]struct {
+		target string
+		secure bool
+	}{
+		"grpc://subscriber.ns.svc:8080/path":  {target: "subscriber.ns.svc:8080"},
+		"grpc://subscriber.ns.svc":            {target: "subscriber.ns.svc:80"},
+		"http://subscriber.ns.svc":            {target: "subscriber.ns.svc:80"},
+		"grpcs://subscriber.example.com":      {target: "subscriber.example.com:443", secure: true},
+		"https://subscriber.example.com:8443": {target: "subscriber.example.com:8443", secure: true},
+		"grpc://[::1]:9090":                   {target: "[::1]:9090"},
+	} {
+		destinationURL, err := url.Parse(rawURL)
+		assert.Nil(t, err)
+		target, secure := grpcTarget(destinationURL)
+		assert.Equal(t, want.target, target, rawURL)
+		assert.Equal(t, want.secure, secure, rawURL)
+	}
+}
+
+// Test The grpcStatusCode() Functionality
+func TestGrpcStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusOK, grpcStatusCode(nil))
+	assert.Equal(t, http.StatusServiceUnavailable, grpcStatusCode(status.Error(codes.Unavailable, "down")))
+	assert.Equal(t, http.StatusTooManyRequests, grpcStatusCode(status.Error(codes.ResourceExhausted, "slow down")))
+	assert.Equal(t, http.StatusBadRequest, grpcStatusCode(status.Error(codes.InvalidArgument, "bad")))
+	assert.Equal(t, http.StatusGatewayTimeout, grpcStatusCode(status.Error(codes.DeadlineExceeded, "timeout")))
+	assert.Equal(t, http.StatusInternalServerError, grpcStatusCode(errors.New("not a grpc status")))
+}
+
+// Test The encodeGrpcPublishRequest() Functionality
+func TestEncodeGrpcPublishRequest(t *testing.T) {
+
+	// Create A Test Event With Optional Attributes, Extensions & JSON Data
+	eventTime := time.Unix(1600000000, 123)
+	event := cloudevents.New()
+	event.SetID(testMsgId)
+	event.SetSource(testMsgSource)
+	event.SetType(testMsgType)
+	event.SetSubject("TestSubject")
+	event.SetTime(eventTime)
+	event.SetExtension("partitionkey", "TestKey")
+	event.SetExtension("retries", 3)
+	assert.Nil(t, event.SetData(testMsgContentType, []byte(testMsgJsonContentString)))
+
+	// Encode & Decode The PublishRequest
+	request, err := encodeGrpcPublishRequest(&event)
+	assert.Nil(t, err)
+	decodedEvent := decodeTestCloudEventProto(t, request)
+
+	// Verify The Decoded CloudEvent
+	assert.Equal(t, testMsgId, decodedEvent.strings[ceProtoFieldId])
+	assert.Equal(t, testMsgSource, decodedEvent.strings[ceProtoFieldSource])
+	assert.Equal(t, "1.0", decodedEvent.strings[ceProtoFieldSpecVersion])
+	assert.Equal(t, testMsgType, decodedEvent.strings[ceProtoFieldType])
+	assert.Equal(t, testMsgJsonContentString, decodedEvent.strings[ceProtoFieldTextData])
+	assert.Equal(t, map[string]testProtoAttribute{
+		"datacontenttype": {field: ceProtoAttrString, value: testMsgContentType},
+		"subject":         {field: ceProtoAttrString, value: "TestSubject"},
+		"time":            {field: ceProtoAttrTimestamp, value: string(encodeProtoTimestamp(eventTime))},
+		"partitionkey":    {field: ceProtoAttrString, value: "TestKey"},
+		"retries":         {field: ceProtoAttrInteger, value: string(protowire.AppendVarint(nil, 3))},
+	}, decodedEvent.attributes)
+
+	// Verify Non-Textual Data Is Encoded As binary_data
+	assert.Nil(t, event.SetData("application/octet-stream", []byte{0x01, 0x02}))
+	request, err = encodeGrpcPublishRequest(&event)
+	assert.Nil(t, err)
+	decodedEvent = decodeTestCloudEventProto(t, request)
+	assert.Equal(t, string([]byte{0x01, 0x02}), decodedEvent.strings[ceProtoFieldBinaryData])
+	assert.Empty(t, decodedEvent.strings[ceProtoFieldTextData])
+}
+
+// Test The GrpcClient Publish() Functionality Against A Test CloudEvents gRPC Server
+func TestGrpcClientPublish(t *testing.T) {
+
+	logger := logtesting.TestLogger(t).Desugar()
+	server, destinationURL := startTestGrpcServer(t, nil)
+	defer server.stop()
+
+	// Publish Multiple Events Over A Single (Reused) Connection
+	grpcClient := NewGrpcClient(logger)
+	defer grpcClient.Close()
+	event := createTestGrpcEvent(t)
+	assert.Nil(t, grpcClient.Publish(context.Background(), event, destinationURL))
+	assert.Nil(t, grpcClient.Publish(context.Background(), event, destinationURL))
+	assert.Len(t, grpcClient.connections, 1)
+
+	// Verify The Server Received The Events
+	requests := server.received()
+	assert.Len(t, requests, 2)
+	assert.Equal(t, testMsgId, decodeTestCloudEventProto(t, requests[0]).strings[ceProtoFieldId])
+
+	// Verify A nil GrpcClient Fails Deliveries
+	var nilGrpcClient *GrpcClient
+	assert.NotNil(t, nilGrpcClient.Publish(context.Background(), event, destinationURL))
+	nilGrpcClient.Close()
+}
+
+// Test The GrpcClient's Invalidate() Functionality
+func TestGrpcClientInvalidate(t *testing.T) {
+
+	// Create ClientConns For Two Hosts (Connecting In The Background)
+	grpcClient := NewGrpcClient(logtesting.TestLogger(t).Desugar())
+	defer grpcClient.Close()
+	for _, rawURL := range []string{"grpc://host-a.ns.svc:8080", "grpcs://host-a.ns.svc", "grpc://host-b.ns.svc:8080"} {
+		destinationURL, err := url.Parse(rawURL)
+		assert.Nil(t, err)
+		_, err = grpcClient.connection(context.Background(), destinationURL)
+		assert.Nil(t, err)
+	}
+	assert.Len(t, grpcClient.connections, 3)
+
+	// Verify Only The ClientConns Of The Specified Host Are Closed
+	grpcClient.Invalidate("host-a.ns.svc")
+	assert.Len(t, grpcClient.connections, 1)
+	assert.Contains(t, grpcClient.connections, "host-b.ns.svc:8080")
+
+	// Verify A nil GrpcClient Does Not Panic
+	var nilGrpcClient *GrpcClient
+	nilGrpcClient.Invalidate("host-b.ns.svc")
+}
+
+// Test The Handler's publishWithRetries() Functionality
+func TestHandlerPublishWithRetries(t *testing.T) {
+
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Create A Test Server Failing The First Two Publish Requests As Unavailable
+	var failures int
+	server, destinationURL := startTestGrpcServer(t, func() error {
+		if failures < 2 {
+			failures++
+			return status.Error(codes.Unavailable, "test unavailable")
+		}
+		return nil
+	})
+	defer server.stop()
+
+	// Create A Handler For The gRPC Subscriber
+	grpcClient := NewGrpcClient(logger)
+	defer grpcClient.Close()
+	subscriber := &eventingduck.SubscriberSpec{UID: testSubscriberUID}
+	handler := NewHandler(HandlerOptions{Logger: logger, Subscriber: subscriber, GrpcClient: grpcClient})
+	message := binding.ToMessage(createTestGrpcEvent(t))
+
+	// Verify Retryable Failures Are Retried Until Success
+	retryConfig := kncloudevents.RetryConfig{
+		RetryMax:   3,
+		CheckRetry: handler.checkRetry,
+		Backoff:    func(int, *http.Response) time.Duration { return time.Millisecond },
+	}
+	statusCode, err := handler.publishWithRetries(context.Background(), message, destinationURL, &retryConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, server.received(), 3)
+
+	// Verify Failures Are Not Retried Without A RetryConfig & Return The Equivalent StatusCode
+	failures = 0
+	statusCode, err = handler.publishWithRetries(context.Background(), message, destinationURL, &kncloudevents.RetryConfig{})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+}
+
+// Test CloudEvents gRPC Server Recording The Raw Publish Requests
+type testGrpcServer struct {
+	server   *grpc.Server
+	publish  func() error
+	lock     sync.Mutex
+	requests [][]byte
+}
+
+// gRPC Server Codec For The Test Server (Legacy grpc.Codec Interface)
+type testGrpcServerCodec struct{ grpcRawCodec }
+
+func (testGrpcServerCodec) String() string { return "proto" }
+
+// Start A Test CloudEvents gRPC Server On A Local Port (The Optional publish Function Determines Each Request's Result)
+func startTestGrpcServer(t *testing.T, publish func() error) (*testGrpcServer, *url.URL) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	testServer := &testGrpcServer{server: grpc.NewServer(grpc.CustomCodec(testGrpcServerCodec{})), publish: publish}
+	testServer.server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "io.cloudevents.v1.CloudEventService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Publish",
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				var request []byte
+				if err := dec(&request); err != nil {
+					return nil, err
+				}
+				testServer.lock.Lock()
+				defer testServer.lock.Unlock()
+				testServer.requests = append(testServer.requests, request)
+				if testServer.publish != nil {
+					if err := testServer.publish(); err != nil {
+						return nil, err
+					}
+				}
+				return []byte{}, nil
+			},
+		}},
+	}, struct{}{})
+	go func() { _ = testServer.server.Serve(listener) }()
+
+	return testServer, &url.URL{Scheme: GrpcScheme, Host: listener.Addr().String()}
+}
+
+// Get The Publish Requests Received By The Test Server
+func (s *testGrpcServer) received() [][]byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([][]byte{}, s.requests...)
+}
+
+// Stop The Test Server
+func (s *testGrpcServer) stop() {
+	s.server.Stop()
+}
+
+// Create A Test Event For gRPC Delivery
+func createTestGrpcEvent(t *testing.T) *cloudevents.Event {
+	event := cloudevents.New()
+	event.SetID(testMsgId)
+	event.SetSource(testMsgSource)
+	event.SetType(testMsgType)
+	assert.Nil(t, event.SetData(testMsgContentType, []byte(testMsgJsonContentString)))
+	return &event
+}
+
+// A Decoded CloudEventAttributeValue (Field Number & Raw Value)
+type testProtoAttribute struct {
+	field protowire.Number
+	value string
+}
+
+// A Decoded CloudEvent Protobuf (Length Delimited Fields As Strings & The Attributes Map)
+type testCloudEventProto struct {
+	strings    map[protowire.Number]string
+	attributes map[string]testProtoAttribute
+}
+
+// Decode A PublishRequest's CloudEvent Protobuf For Verification
+func decodeTestCloudEventProto(t *testing.T, request []byte) testCloudEventProto {
+
+	// Unwrap The CloudEvent From The PublishRequest
+	fields := decodeTestProtoFields(t, request)
+	assert.Len(t, fields, 1)
+	assert.Equal(t, grpcPublishRequestFieldEvent, fields[0].number)
+
+	// Decode The CloudEvent's Fields
+	decoded := testCloudEventProto{strings: make(map[protowire.Number]string), attributes: make(map[string]testProtoAttribute)}
+	for _, field := range decodeTestProtoFields(t, fields[0].value) {
+		if field.number != ceProtoFieldAttributes {
+			decoded.strings[field.number] = string(field.value)
+			continue
+		}
+		entry := decodeTestProtoFields(t, field.value)
+		assert.Len(t, entry, 2)
+		attrValue := decodeTestProtoFields(t, entry[1].value)
+		assert.Len(t, attrValue, 1)
+		decoded.attributes[string(entry[0].value)] = testProtoAttribute{field: attrValue[0].number, value: string(attrValue[0].value)}
+	}
+	return decoded
+}
+
+// A Single Decoded Protobuf Field (Varint Values Remain Varint Encoded)
+type testProtoField struct {
+	number protowire.Number
+	value  []byte
+}
+
+// Decode The Top Level Fields Of A Protobuf Message
+func decodeTestProtoFields(t *testing.T, b []byte) []testProtoField {
+	var fields []testProtoField
+	for len(b) > 0 {
+		number, wireType, n := protowire.ConsumeTag(b)
+		assert.True(t, n > 0)
+		b = b[n:]
+		switch wireType {
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(b)
+			assert.True(t, n > 0)
+			fields = append(fields, testProtoField{number: number, value: value})
+			b = b[n:]
+		case protowire.VarintType:
+			_, n := protowire.ConsumeVarint(b)
+			assert.True(t, n > 0)
+			fields = append(fields, testProtoField{number: number, value: b[:n]})
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %d", wireType)
+		}
+	}
+	return fields
+}
@@ -19,18 +19,32 @@ package dispatcher
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
-
+	"time"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/events"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/latency"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/tail"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	"knative.dev/eventing-kafka/pkg/common/headers"
 	"knative.dev/eventing-kafka/pkg/common/tracing"
 
 	"github.com/Shopify/sarama"
 	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
 	"go.uber.org/zap"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
 )
 
 // Verify The Handler Implements The Sarama ConsumerGroupHandler
@@ -38,17 +52,90 @@ var _ sarama.ConsumerGroupHandler = &Handler{}
 
 // Define A Sarama ConsumerGroupHandler Implementation
 type Handler struct {
-	Logger            *zap.Logger
-	Subscriber        *eventingduck.SubscriberSpec
-	MessageDispatcher channel.MessageDispatcher
+	Logger             *zap.Logger
+	Subscriber         *eventingduck.SubscriberSpec
+	MessageDispatcher  channel.MessageDispatcher
+	DeadLetterProducer sarama.SyncProducer
+	DeadLetterTopic    string
+	EventAgePolicy     *EventAgePolicy
+	RetryPolicies      *RetryPolicies
+	FaultInjector      *faults.Injector
+	GrpcClient         *GrpcClient
+	KafkaProducers     *KafkaProducers
+	Tap                *tail.Tap
+	Deduplicator       *Deduplicator
+	PoisonPillPolicy   *PoisonPillPolicy
+	Quarantine         *Quarantine
+	Limiter            *ParallelismLimiter
+	Filter             EventFilter
+	Transform          *EventTransform
+	Middleware         middleware.Pipeline
+	MiddlewareTarget   middleware.Target
+	HeadersPolicy      *headers.Policy
+	EventReporter      *events.ChannelReporter
+	Resolver           *DestinationResolver
+	HealthProbe        *HealthProbe
+	Envelope           *encryption.Envelope
+	DeliveryHeaders    *DeliveryHeaders
+}
+
+// The Options Of A New Handler (The DeadLetter Producer & Topic Are Only Specified For DeadLetterSinks Backed By Kafka, The GrpcClient Only For gRPC Subscribers, The KafkaProducers Only For Kafka Subscribers, The Quarantine Only For Channels With A Quarantine Topic - Any Other Unspecified Options Are Disabled)
+type HandlerOptions struct {
+	Logger             *zap.Logger
+	Subscriber         *eventingduck.SubscriberSpec
+	DeadLetterProducer sarama.SyncProducer
+	DeadLetterTopic    string
+	EventAgePolicy     *EventAgePolicy
+	RetryPolicies      *RetryPolicies
+	FaultInjector      *faults.Injector
+	GrpcClient         *GrpcClient
+	KafkaProducers     *KafkaProducers
+	Tap                *tail.Tap
+	Deduplicator       *Deduplicator
+	PoisonPillPolicy   *PoisonPillPolicy
+	Quarantine         *Quarantine
+	Limiter            *ParallelismLimiter
+	Filter             EventFilter
+	Transform          *EventTransform
+	Middleware         middleware.Pipeline
+	MiddlewareTarget   middleware.Target
+	HeadersPolicy      *headers.Policy
+	EventReporter      *events.ChannelReporter
+	Resolver           *DestinationResolver
+	Balancer           *EndpointBalancer
+	HealthProbe        *HealthProbe
+	Envelope           *encryption.Envelope
+	DeliveryHeaders    *DeliveryHeaders
 }
 
-// Create A New Handler
-func NewHandler(logger *zap.Logger, subscriber *eventingduck.SubscriberSpec) *Handler {
+// Create A New Handler With The Specified Options
+func NewHandler(options HandlerOptions) *Handler {
 	return &Handler{
-		Logger:            logger,
-		Subscriber:        subscriber,
-		MessageDispatcher: newMessageDispatcherWrapper(logger),
+		Logger:             options.Logger,
+		Subscriber:         options.Subscriber,
+		MessageDispatcher:  options.Balancer.messageDispatcher(options.Logger, options.Resolver),
+		DeadLetterProducer: options.DeadLetterProducer,
+		DeadLetterTopic:    options.DeadLetterTopic,
+		EventAgePolicy:     options.EventAgePolicy,
+		RetryPolicies:      options.RetryPolicies,
+		FaultInjector:      options.FaultInjector,
+		GrpcClient:         options.GrpcClient,
+		KafkaProducers:     options.KafkaProducers,
+		Tap:                options.Tap,
+		Deduplicator:       options.Deduplicator,
+		PoisonPillPolicy:   options.PoisonPillPolicy,
+		Quarantine:         options.Quarantine,
+		Limiter:            options.Limiter,
+		Filter:             options.Filter,
+		Transform:          options.Transform,
+		Middleware:         options.Middleware,
+		MiddlewareTarget:   options.MiddlewareTarget,
+		HeadersPolicy:      options.HeadersPolicy,
+		EventReporter:      options.EventReporter,
+		Resolver:           options.Resolver,
+		HealthProbe:        options.HealthProbe,
+		Envelope:           options.Envelope,
+		DeliveryHeaders:    options.DeliveryHeaders,
 	}
 }
 
@@ -70,30 +157,10 @@ func (h *Handler) Cleanup(_ sarama.ConsumerGroupSession) error {
 // ConsumerGroupHandler Lifecycle Method (Main processing loop, must finish when claim.Messages() channel closes.)
 func (h *Handler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 
-	// Extract The Destination URL From The Subscriber
-	var destinationURL *url.URL
-	if !h.Subscriber.SubscriberURI.IsEmpty() {
-		destinationURL = h.Subscriber.SubscriberURI.URL()
-	}
-
-	// Extract The Reply URL From The Subscriber
-	var replyURL *url.URL
-	if !h.Subscriber.ReplyURI.IsEmpty() {
-		replyURL = h.Subscriber.ReplyURI.URL()
-	}
-
 	// Validate The Subscriber's Delivery (Optional)
-	var deadLetterURL *url.URL
 	retryConfig := kncloudevents.NoRetries()
 	if h.Subscriber.Delivery != nil {
 
-		// Extract The DeadLetterSink From The Subscriber.Delivery
-		if h.Subscriber.Delivery.DeadLetterSink != nil &&
-			h.Subscriber.Delivery.DeadLetterSink.URI != nil &&
-			!h.Subscriber.Delivery.DeadLetterSink.URI.IsEmpty() {
-			deadLetterURL = h.Subscriber.Delivery.DeadLetterSink.URI.URL()
-		}
-
 		// Extract The RetryConfig From The Subscriber.Delivery (Defaults To NoRetries)
 		var err error
 		retryConfig, err = kncloudevents.RetryConfigFromDeliverySpec(*h.Subscriber.Delivery)
@@ -108,42 +175,449 @@ func (h *Handler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama
 	// Pull Any Available Messages From The ConsumerGroupClaim (Until The Channel Closes)
 	for message := range claim.Messages() {
 
+		// Sample The Message For Any Watchers Of The Tail Endpoint
+		h.Tap.Publish(string(h.Subscriber.UID), message)
+
+		// Record The Subscription's Consumer Lag (The Messages Remaining In The Partition After This One)
+		metrics.RecordConsumerLag(h.Logger, message.Topic, string(h.Subscriber.UID), message.Partition, claim.HighWaterMarkOffset()-message.Offset-1)
+
+		// Get The Subscriber's Current (Possibly Re-Resolved) Destination, Reply & DeadLetterSink URLs
+		destinationURL, replyURL, deadLetterURL := h.destinationURLs()
+
+		// Wait While The Subscriber Is Unhealthy (Pausing Consumption) Unless The Session Ends First
+		if !h.HealthProbe.Wait(session.Context()) {
+			return nil
+		}
+
+		// Wait For One Of The Subscriber's Dispatch Slots (Shared Across Its Claimed Partitions) Unless The Session Ends First
+		if !h.Limiter.Acquire(session.Context()) {
+			return nil
+		}
+
 		// Consume The Message (Ignore Errors - Will have already been retried and we're moving on so as not to block further Topic processing.)
-		_ = h.consumeMessage(session.Context(), message, destinationURL, replyURL, deadLetterURL, &retryConfig)
+		err := h.consumeMessage(session.Context(), message, destinationURL, replyURL, deadLetterURL, &retryConfig)
+		h.Limiter.Release()
+
+		// Leave A Message Unmarked If The Session Ended While Still Attempting To Decode It (It Is Re-Consumed After The Rebalance)
+		if err == errDecodeInterrupted {
+			return nil
+		}
 
 		// Mark The Message As Having Been Consumed (Does Not Imply Successful Delivery - Only Full Retry Attempts Made)
 		session.MarkMessage(message, "")
+
+		// Inject Any Configured ConsumerGroup Rebalance By Ending The Session (Non-Production Only)
+		if h.FaultInjector.Rebalance() {
+			return nil
+		}
 	}
 
 	// Return Success
 	return nil
 }
 
+// Get The Destination, Reply & DeadLetterSink URLs Of The Subscriber (nil If Not Specified)
+func (h *Handler) destinationURLs() (*url.URL, *url.URL, *url.URL) {
+	subscriberURI, replyURI, deadLetterURI := h.Resolver.Destinations(h.Subscriber)
+	return optionalURL(subscriberURI), optionalURL(replyURI), optionalURL(deadLetterURI)
+}
+
+// Utility Function For Converting An Optional URI Into A URL (nil If Empty)
+func optionalURL(uri *apis.URL) *url.URL {
+	if uri.IsEmpty() {
+		return nil
+	}
+	return uri.URL()
+}
+
 // Consume A Single Message
 func (h *Handler) consumeMessage(context context.Context, consumerMessage *sarama.ConsumerMessage, destinationURL *url.URL, replyURL *url.URL, deadLetterURL *url.URL, retryConfig *kncloudevents.RetryConfig) error {
 
-	// Debug Log Kafka ConsumerMessage
-	h.Logger.Debug("Consuming Kafka Message",
-		zap.Any("Headers", consumerMessage.Headers),
-		zap.ByteString("Key", consumerMessage.Key),
-		zap.ByteString("Value", consumerMessage.Value),
-		zap.String("Topic", consumerMessage.Topic),
-		zap.Int32("Partition", consumerMessage.Partition),
-		zap.Int64("Offset", consumerMessage.Offset))
-
-	// Convert The Sarama ConsumerMessage Into A CloudEvents Message
-	message := kafkasaramaprotocol.NewMessageFromConsumerMessage(consumerMessage)
+	// Record The Time The Message Was Consumed (For Tracking The Latency Of Its Delivery)
+	consumedTime := time.Now()
+
+	// Debug Log Kafka ConsumerMessage (Checking The Level First To Avoid Creating The Fields Of Every Message)
+	if checkedEntry := h.Logger.Check(zap.DebugLevel, "Consuming Kafka Message"); checkedEntry != nil {
+		checkedEntry.Write(
+			zap.Any("Headers", consumerMessage.Headers),
+			zap.ByteString("Key", consumerMessage.Key),
+			zap.ByteString("Value", consumerMessage.Value),
+			zap.String("Topic", consumerMessage.Topic),
+			zap.Int32("Partition", consumerMessage.Partition),
+			zap.Int64("Offset", consumerMessage.Offset))
+	}
+
+	// Decrypt Any Record Encrypted By The Receiver, Quarantining Those Which Can't Be Decrypted If Poison Pills Are Handled
+	decryptedMessage, decryptErr := h.Envelope.Decrypt(context, consumerMessage)
+	if decryptErr != nil {
+		if h.PoisonPillPolicy != nil {
+			return h.handlePoisonPill(context, consumerMessage, destinationURL, replyURL, deadLetterURL, retryConfig, decryptErr)
+		}
+		h.Logger.Warn("Failed To Decrypt Message - Skipping", zap.String("Topic", consumerMessage.Topic), zap.Int32("Partition", consumerMessage.Partition), zap.Int64("Offset", consumerMessage.Offset), zap.Error(decryptErr))
+		h.EventReporter.Warning(events.DecryptionFailed, "Skipping Undecryptable Message At Offset %d Of Partition %d Of Topic %s: %v", consumerMessage.Offset, consumerMessage.Partition, consumerMessage.Topic, decryptErr)
+		return decryptErr
+	}
+	consumerMessage = decryptedMessage
+
+	// Read The Sarama ConsumerMessage In Place As A CloudEvents Message (Decoding It Only If The Event Is Needed)
+	message := newRecordMessage(consumerMessage)
+
+	// Quarantine Any Record Which Repeatedly Fails To Decode Into A Valid CloudEvent (Poison Pill)
+	if decodeErr := h.PoisonPillPolicy.Decode(context, message); decodeErr != nil {
+		if decodeErr == errDecodeInterrupted {
+			return decodeErr
+		}
+		return h.handlePoisonPill(context, consumerMessage, destinationURL, replyURL, deadLetterURL, retryConfig, decodeErr)
+	}
+
+	// Skip Any Record Which Is Not A CloudEvent
 	if message.ReadEncoding() == binding.EncodingUnknown {
 		h.Logger.Warn("Received A Message With Unknown Encoding - Skipping")
 		return errors.New("received a message with unknown encoding - skipping")
 	}
 
-	ctx, span := tracing.StartTraceFromMessage(h.Logger.Sugar(), context, message, consumerMessage.Topic)
+	ctx, span := tracing.StartTraceFromRecordHeaders(h.Logger.Sugar(), context, consumerMessage.Headers, consumerMessage.Topic)
 	defer span.End()
 
-	// Dispatch The Message With Configured Retries & Return Any Errors
-	_, dispatchError := h.MessageDispatcher.DispatchMessageWithRetries(ctx, message, nil, destinationURL, replyURL, deadLetterURL, retryConfig)
-	return dispatchError
+	// Count (And Optionally Drop) Duplicates Of Events Already Consumed Within The Dedupe Window
+	if h.Deduplicator.Duplicate(consumerMessage) {
+		metrics.RecordDuplicateEvent(h.Logger, consumerMessage.Topic, string(h.Subscriber.UID))
+		if h.Deduplicator.Drop {
+			h.Logger.Debug("Dropping Duplicate Message", zap.Int32("Partition", consumerMessage.Partition), zap.Int64("Offset", consumerMessage.Offset))
+			return nil
+		}
+	}
+
+	// Skip Any Event Not Matching The Subscriber's Filter (e.g. The Attribute Filter Of A Broker's Trigger)
+	if !h.Filter.Matches(ctx, message) {
+		h.Logger.Debug("Skipping Filtered Message", zap.Int32("Partition", consumerMessage.Partition), zap.Int64("Offset", consumerMessage.Offset))
+		return nil
+	}
+
+	// Skip Or DeadLetter Any Event Older Than The Subscriber's Maximum Event Age
+	if eventAge, exceeded := h.EventAgePolicy.Exceeded(consumerMessage, time.Now()); exceeded {
+		staleError := fmt.Errorf("event age %s exceeds the maximum event age %s", eventAge, h.EventAgePolicy.MaxEventAge)
+		if !h.EventAgePolicy.DeadLetter || deadLetterURL == nil {
+			h.Logger.Warn("Skipping Stale Message", zap.Duration("EventAge", eventAge), zap.Duration("MaxEventAge", h.EventAgePolicy.MaxEventAge))
+			return nil
+		}
+		return h.handleDeadLetter(ctx, message, deadLetterURL, retryConfig, newDeliveryError(destinationURL, replyURL, staleError, consumerMessage))
+	}
+
+	// Inject Any Configured Subscriber Latency (Non-Production Only)
+	if latency := h.FaultInjector.SubscriberLatency(); latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+		}
+	}
+
+	// Propagate Any Allowed Kafka Record Headers Into CloudEvent Extensions (Per The Headers Policy)
+	var dispatchMessage binding.Message = message
+	if transformers := h.HeadersPolicy.Transformers(consumerMessage.Headers); len(transformers) > 0 {
+		headersEvent, err := message.decode(ctx)
+		if err == nil {
+			headersEvent, err = binding.ToEvent(ctx, binding.ToMessage(headersEvent), transformers...)
+		}
+		if err != nil {
+			h.Logger.Warn("Failed To Propagate Kafka Headers Into CloudEvent Extensions", zap.Error(err))
+			return err
+		}
+		dispatchMessage = binding.ToMessage(headersEvent)
+	}
+
+	// Apply The KafkaChannel's Middleware (If Any), Skipping Events Which It Drops Or Rejects (The Quarantine Receives
+	// The Event Without Middleware Or Transform Since It Is Redelivered To All The KafkaChannel's Subscribers)
+	quarantineMessage := dispatchMessage
+	if len(h.Middleware) > 0 {
+		middlewareEvent, err := toEvent(ctx, dispatchMessage)
+		if err != nil {
+			h.Logger.Warn("Failed To Convert Message To Event For Middleware", zap.Error(err))
+			return err
+		}
+		middlewareEvent, err = h.Middleware.Process(ctx, h.MiddlewareTarget, middlewareEvent)
+		if err != nil {
+			h.Logger.Warn("Middleware Rejected Message - Skipping", zap.Int32("Partition", consumerMessage.Partition), zap.Int64("Offset", consumerMessage.Offset), zap.Error(err))
+			return err
+		} else if middlewareEvent == nil {
+			h.Logger.Debug("Middleware Dropped Message", zap.Int32("Partition", consumerMessage.Partition), zap.Int64("Offset", consumerMessage.Offset))
+			return nil
+		}
+		dispatchMessage = binding.ToMessage(middlewareEvent)
+	}
+
+	// Remove, Redact Or Hash The Fields The Subscriber Must Not Receive, Skipping Events Which Can't Be Transformed
+	dispatchMessage, err := h.Transform.Apply(ctx, dispatchMessage)
+	if err != nil {
+		h.Logger.Warn("Failed To Transform Message For Subscriber - Skipping", zap.Int32("Partition", consumerMessage.Partition), zap.Int64("Offset", consumerMessage.Offset), zap.Error(err))
+		return err
+	}
+
+	// Request That The Subscriber Include Any Response Event In Its Reply (Per The Knative Eventing Data Plane Contract)
+	var additionalHeaders http.Header
+	if replyURL != nil {
+		additionalHeaders = http.Header{constants.PreferHeader: []string{constants.PreferReplyHeader}}
+	}
+
+	// Add The Subscription's Static & Auth Headers (Only Sent To The Subscriber - Without One They'd Be Sent To The Reply)
+	if destinationURL != nil {
+		additionalHeaders = h.DeliveryHeaders.Apply(string(h.Subscriber.UID), additionalHeaders)
+	}
+
+	// Apply Any Error Category RetryPolicies & Count The Retries Of The Message (Reported To The DeadLetterSink Upon Failure)
+	retries := 0
+	policyRetryConfig := h.RetryPolicies.messageRetryConfig(retryConfig)
+	messageRetryConfig := countingRetryConfig(&policyRetryConfig, &retries)
+
+	// Dispatch The Message With Configured Retries (DeadLetterSink Handled Below In Order To Include Delivery Error Extensions)
+	var responseCode int
+	var dispatchError error
+	if h.KafkaProducers != nil && destinationURL != nil {
+		responseCode, dispatchError = h.produceWithRetries(ctx, dispatchMessage, consumerMessage.Key, destinationURL, &messageRetryConfig)
+	} else if h.GrpcClient != nil && destinationURL != nil {
+		responseCode, dispatchError = h.publishWithRetries(ctx, dispatchMessage, destinationURL, &messageRetryConfig)
+	} else {
+		var dispatchExecutionInfo *channel.DispatchExecutionInfo
+		dispatchExecutionInfo, dispatchError = h.MessageDispatcher.DispatchMessageWithRetries(ctx, dispatchMessage, additionalHeaders, destinationURL, replyURL, nil, &messageRetryConfig)
+		responseCode = channel.NoResponse
+		if dispatchExecutionInfo != nil {
+			responseCode = dispatchExecutionInfo.ResponseCode
+		}
+	}
+	metrics.RecordDispatchedEvent(h.Logger, consumerMessage.Topic, string(h.Subscriber.UID), dispatchError == nil)
+	if dispatchError == nil {
+		latency.NewHops(consumerMessage, consumedTime, time.Now()).Record(h.Logger, consumerMessage.Topic, string(h.Subscriber.UID))
+		return nil
+	}
+
+	// Report The Failed Delivery Against The KafkaChannel (Regardless Of Any DeadLetterSink)
+	h.EventReporter.Warning(events.SubscriberUnreachable, "Failed To Deliver Event To Subscriber %s (ResponseCode %d): %v", subscriberDescription(destinationURL, replyURL), responseCode, dispatchError)
+	if deadLetterURL == nil && h.Quarantine == nil {
+		return dispatchError
+	}
+
+	// Describe The Delivery Error For The DeadLetterSink / Quarantine
+	deliveryError := newDeliveryError(destinationURL, replyURL, dispatchError, consumerMessage)
+	deliveryError.Retries = retries
+	deliveryError.ResponseCode = responseCode
+
+	// Quarantine The Message If The Subscriber Has No DeadLetterSink
+	if deadLetterURL == nil {
+		err := h.Quarantine.Produce(ctx, quarantineMessage, deliveryError)
+		if err != nil {
+			h.Logger.Error("Failed To Produce Message To Quarantine Topic", zap.String("QuarantineTopic", h.Quarantine.Topic), zap.Error(err))
+			return err
+		}
+		h.Logger.Warn("Failed To Dispatch Message - Quarantined", zap.String("QuarantineTopic", h.Quarantine.Topic), zap.Error(dispatchError))
+		return nil
+	}
+
+	// Send The Message To The DeadLetterSink Along With The Delivery Error Extensions
+	return h.handleDeadLetter(ctx, dispatchMessage, deadLetterURL, retryConfig, deliveryError)
+}
+
+// Utility Function For Converting A Message To An Event (Reusing A Record's Decoded Event Rather Than Decoding It Again)
+func toEvent(ctx context.Context, message binding.Message) (*event.Event, error) {
+	if recordMessage, ok := message.(*recordMessage); ok {
+		return recordMessage.decode(ctx)
+	}
+	return binding.ToEvent(ctx, message)
+}
+
+//
+// Publish The Message To A gRPC Subscriber With Configured Retries, Returning The Equivalent HTTP StatusCode
+//
+// The gRPC status of each failed attempt is mapped to the equivalent HTTP StatusCode so that the RetryConfig's
+// CheckRetry & Backoff (including any RetryPolicies) apply to gRPC subscribers exactly as they do to HTTP ones.
+// The CloudEvents gRPC Publish method does not return events, so gRPC subscribers never produce replies.
+//
+func (h *Handler) publishWithRetries(ctx context.Context, message binding.Message, destinationURL *url.URL, retryConfig *kncloudevents.RetryConfig) (int, error) {
+
+	// Convert The Message To An Event For Protobuf Encoding
+	event, err := toEvent(ctx, message)
+	if err != nil {
+		return channel.NoResponse, fmt.Errorf("failed to convert message to event for grpc delivery: %w", err)
+	}
+
+	// Publish Until Success, A Non-Retryable Failure Or The Retries Are Exhausted
+	return h.deliverWithRetries(ctx, "Failed To Publish Message To gRPC Subscriber", retryConfig, func() (int, error) {
+		err := h.GrpcClient.Publish(ctx, event, destinationURL)
+		return grpcStatusCode(err), err
+	})
+}
+
+//
+// Produce The Message To A Kafka Subscriber's Topic With Configured Retries, Returning The Equivalent HTTP StatusCode
+//
+// As with gRPC subscribers the produce errors are mapped to the equivalent HTTP StatusCode so that the RetryConfig
+// (including any RetryPolicies) applies.  The record retains the partition key of the consumed record so that the
+// ordering of the events of each key is preserved, and Kafka subscribers never produce replies.
+//
+func (h *Handler) produceWithRetries(ctx context.Context, message binding.Message, key []byte, destinationURL *url.URL, retryConfig *kncloudevents.RetryConfig) (int, error) {
+
+	// Convert The Message To An Event (Re-Encoded For Each Attempt)
+	event, err := toEvent(ctx, message)
+	if err != nil {
+		return channel.NoResponse, fmt.Errorf("failed to convert message to event for kafka delivery: %w", err)
+	}
+
+	// Produce Until Success, A Non-Retryable Failure Or The Retries Are Exhausted
+	return h.deliverWithRetries(ctx, "Failed To Produce Message To Kafka Subscriber", retryConfig, func() (int, error) {
+		err := h.KafkaProducers.Produce(ctx, event, key, destinationURL)
+		return kafkaStatusCode(err), err
+	})
+}
+
+// Attempt A (Non-HTTP) Delivery Until Success, A Non-Retryable Failure Or The Retries Are Exhausted
+func (h *Handler) deliverWithRetries(ctx context.Context, failureMessage string, retryConfig *kncloudevents.RetryConfig, deliver func() (int, error)) (int, error) {
+	for attempt := 0; ; attempt++ {
+		statusCode, err := deliver()
+		if err == nil {
+			return http.StatusOK, nil
+		}
+		h.Logger.Warn(failureMessage, zap.Int("StatusCode", statusCode), zap.Int("Attempt", attempt), zap.Error(err))
+		if retryConfig.CheckRetry == nil || retryConfig.Backoff == nil || attempt >= retryConfig.RetryMax {
+			return statusCode, err
+		}
+		response := &http.Response{StatusCode: statusCode}
+		if retry, _ := retryConfig.CheckRetry(ctx, response, nil); !retry {
+			return statusCode, err
+		}
+		select {
+		case <-time.After(retryConfig.Backoff(attempt, response)):
+		case <-ctx.Done():
+			return statusCode, err
+		}
+	}
+}
+
+// Utility Function For Describing The Failed Destination Of A Delivery (The Subscriber Unless Only A Reply Was Configured)
+func subscriberDescription(destinationURL *url.URL, replyURL *url.URL) string {
+	if destinationURL != nil {
+		return destinationURL.String()
+	} else if replyURL != nil {
+		return replyURL.String()
+	}
+	return ""
+}
+
+// Utility Function For Describing A Delivery Error (The Failed Destination Is The Subscriber Unless Only A Reply Was Configured)
+func newDeliveryError(destinationURL *url.URL, replyURL *url.URL, err error, consumerMessage *sarama.ConsumerMessage) *deadletter.DeliveryError {
+	return &deadletter.DeliveryError{
+		ResponseCode: channel.NoResponse,
+		Err:          err,
+		Message:      consumerMessage,
+		Destination:  subscriberDescription(destinationURL, replyURL),
+	}
+}
+
+// Send A Failed Message To The DeadLetterSink (Produced Directly To Any Kafka Backed DeadLetterSink, Otherwise Dispatched)
+func (h *Handler) handleDeadLetter(ctx context.Context, message binding.Message, deadLetterURL *url.URL, retryConfig *kncloudevents.RetryConfig, deliveryError *deadletter.DeliveryError) error {
+	if h.DeadLetterProducer != nil && len(h.DeadLetterTopic) > 0 {
+		return h.produceToDeadLetterTopic(ctx, message, deliveryError)
+	}
+	return h.dispatchToDeadLetterSink(ctx, message, deadLetterURL, retryConfig, deliveryError)
+}
+
+// Produce A Failed Message Directly To The Kafka DeadLetter Topic With CloudEvent Extensions Describing The Delivery Error
+func (h *Handler) produceToDeadLetterTopic(ctx context.Context, message binding.Message, deliveryError *deadletter.DeliveryError) error {
+
+	// Log The Delivery Failure
+	logger := h.Logger.With(zap.Int("ResponseCode", deliveryError.ResponseCode), zap.Int("Retries", deliveryError.Retries), zap.String("DeadLetterTopic", h.DeadLetterTopic))
+	logger.Warn("Failed To Dispatch Message - Producing To DeadLetter Topic", zap.Error(deliveryError.Err))
+
+	// Create The Sarama ProducerMessage, Retaining The Original Partition Key
+	producerMessage := &sarama.ProducerMessage{Topic: h.DeadLetterTopic}
+	if deliveryError.Message != nil && deliveryError.Message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(deliveryError.Message.Key)
+	}
+
+	// Populate The ProducerMessage From The Message Including The Delivery Error Extensions
+	err := kafkasaramaprotocol.WriteProducerMessage(ctx, message, producerMessage, deliveryError.Transformers()...)
+	if err != nil {
+		logger.Error("Failed To Add Delivery Error Extensions To Message", zap.Error(err))
+		return fmt.Errorf("failed to add delivery error extensions to message (%v) after dispatch failure: %w", err, deliveryError.Err)
+	}
+
+	// Produce The Message To The DeadLetter Topic
+	_, _, err = h.DeadLetterProducer.SendMessage(producerMessage)
+	if err != nil {
+		logger.Error("Failed To Produce Message To DeadLetter Topic", zap.Error(err))
+		return fmt.Errorf("unable to complete request to either %s (%v) or kafka topic %s (%v)", deliveryError.Destination, deliveryError.Err, h.DeadLetterTopic, err)
+	}
+
+	// Return Success (Message Successfully Produced To The DeadLetter Topic)
+	return nil
+}
+
+// Dispatch A Failed Message To The DeadLetterSink With CloudEvent Extensions Describing The Delivery Error
+func (h *Handler) dispatchToDeadLetterSink(ctx context.Context, message binding.Message, deadLetterURL *url.URL, retryConfig *kncloudevents.RetryConfig, deliveryError *deadletter.DeliveryError) error {
+
+	// Log The Delivery Failure
+	logger := h.Logger.With(zap.Int("ResponseCode", deliveryError.ResponseCode), zap.Int("Retries", deliveryError.Retries), zap.String("DeadLetterSink", deadLetterURL.String()))
+	logger.Warn("Failed To Dispatch Message - Sending To DeadLetterSink", zap.Error(deliveryError.Err))
+
+	// Convert The Message To An Event Including The Delivery Error Extensions
+	event, err := binding.ToEvent(ctx, message, deliveryError.Transformers()...)
+	if err != nil {
+		logger.Error("Failed To Add Delivery Error Extensions To Message", zap.Error(err))
+		return fmt.Errorf("failed to add delivery error extensions to message (%v) after dispatch failure: %w", err, deliveryError.Err)
+	}
+
+	// Dispatch The Event To The DeadLetterSink With Configured Retries
+	_, deadLetterError := h.MessageDispatcher.DispatchMessageWithRetries(ctx, binding.ToMessage(event), nil, deadLetterURL, nil, nil, retryConfig)
+	if deadLetterError != nil {
+		logger.Error("Failed To Dispatch Message To DeadLetterSink", zap.Error(deadLetterError))
+		return fmt.Errorf("unable to complete request to either %s (%v) or %s (%v)", deliveryError.Destination, deliveryError.Err, deadLetterURL, deadLetterError)
+	}
+
+	// Return Success (Message Successfully Handled By The DeadLetterSink)
+	return nil
+}
+
+// Quarantine A Poison Pill Record (Verbatim To The QuarantineTopic, Else Wrapped In A CloudEvent To The DeadLetterSink) So That Its Offset Can Be Committed
+func (h *Handler) handlePoisonPill(ctx context.Context, consumerMessage *sarama.ConsumerMessage, destinationURL *url.URL, replyURL *url.URL, deadLetterURL *url.URL, retryConfig *kncloudevents.RetryConfig, decodeErr error) error {
+
+	// Log, Count & Report The Poison Pill Against The KafkaChannel
+	logger := h.Logger.With(zap.String("Topic", consumerMessage.Topic), zap.Int32("Partition", consumerMessage.Partition), zap.Int64("Offset", consumerMessage.Offset), zap.Error(decodeErr))
+	logger.Warn("Failed To Decode Message - Skipping Poison Pill", zap.Int("Attempts", h.PoisonPillPolicy.MaxAttempts))
+	metrics.RecordPoisonPill(h.Logger, consumerMessage.Topic, string(h.Subscriber.UID))
+	h.EventReporter.Warning(events.PoisonPill, "Skipping Undecodable Message At Offset %d Of Partition %d Of Topic %s: %v", consumerMessage.Offset, consumerMessage.Partition, consumerMessage.Topic, decodeErr)
+
+	// Produce The Record Verbatim To The Poison Pill Specific Quarantine Topic, Otherwise To The KafkaChannel's
+	quarantine := h.PoisonPillPolicy.Quarantine
+	if quarantine == nil {
+		quarantine = h.Quarantine
+	}
+	if quarantine != nil {
+		err := quarantine.ProduceRaw(consumerMessage, decodeErr)
+		if err != nil {
+			logger.Error("Failed To Produce Poison Pill To Quarantine Topic", zap.String("QuarantineTopic", quarantine.Topic), zap.Error(err))
+		}
+		return err
+	}
+
+	// Otherwise Send The Record, Wrapped In A CloudEvent, To Any DeadLetterSink
+	if deadLetterURL == nil {
+		return decodeErr
+	}
+	poisonPillEvent := newPoisonPillEvent(consumerMessage)
+	return h.handleDeadLetter(ctx, binding.ToMessage(&poisonPillEvent), deadLetterURL, retryConfig, newDeliveryError(destinationURL, replyURL, decodeErr, consumerMessage))
+}
+
+// Utility Function For Wrapping A RetryConfig's CheckRetry To Count The Retries Actually Performed
+func countingRetryConfig(retryConfig *kncloudevents.RetryConfig, retries *int) kncloudevents.RetryConfig {
+	countingRetryConfig := *retryConfig
+	if retryConfig.CheckRetry != nil {
+		countingRetryConfig.CheckRetry = func(ctx context.Context, response *http.Response, err error) (bool, error) {
+			retry, checkRetryErr := retryConfig.CheckRetry(ctx, response, err)
+			if retry && *retries < retryConfig.RetryMax {
+				*retries++
+			}
+			return retry, checkRetryErr
+		}
+	}
+	return countingRetryConfig
 }
 
 //
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	protocolhttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// Run with go test ./pkg/channel/distributed/dispatcher/dispatcher/ -run=^$ -bench=. -benchmem
+
+// The Payload Sizes Of The Benchmarked Records
+var benchmarkPayloadSizes = []int{256, 4096, 65536}
+
+// Benchmark Writing A Record To An HTTP Request Via The CloudEvents kafka_sarama Message & The recordMessage
+func BenchmarkMessageWriteRequest(b *testing.B) {
+	for _, payloadSize := range benchmarkPayloadSizes {
+		record := createBenchmarkConsumerMessage(payloadSize)
+		b.Run(fmt.Sprintf("kafka_sarama/%d", payloadSize), func(b *testing.B) {
+			benchmarkWriteRequest(b, func() binding.Message { return kafkasaramaprotocol.NewMessageFromConsumerMessage(record) })
+		})
+		b.Run(fmt.Sprintf("recordMessage/%d", payloadSize), func(b *testing.B) {
+			benchmarkWriteRequest(b, func() binding.Message { return newRecordMessage(record) })
+		})
+	}
+}
+
+// Benchmark Decoding A Record For Two Policies (e.g. The Filter & Transform) Separately & Sharing The recordMessage's Event
+func BenchmarkMessageDecode(b *testing.B) {
+	for _, payloadSize := range benchmarkPayloadSizes {
+		record := createBenchmarkConsumerMessage(payloadSize)
+		b.Run(fmt.Sprintf("kafka_sarama/%d", payloadSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for policy := 0; policy < 2; policy++ {
+					if _, err := binding.ToEvent(context.Background(), kafkasaramaprotocol.NewMessageFromConsumerMessage(record)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("recordMessage/%d", payloadSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				message := newRecordMessage(record)
+				for policy := 0; policy < 2; policy++ {
+					if _, err := toEvent(context.Background(), message); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// Benchmark Consuming Records Dispatched As-Is & Records Which Are Filtered & Transformed (Decoding The Event)
+func BenchmarkConsumeMessage(b *testing.B) {
+	handler := &Handler{
+		Logger:     zap.NewNop(),
+		Subscriber: &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+		MessageDispatcher: channel.NewMessageDispatcherFromSender(zap.NewNop(), &kncloudevents.HTTPMessageSender{
+			Client: &http.Client{Transport: benchmarkRoundTripper{}},
+		}),
+	}
+	transformedHandler := *handler
+	transformedHandler.Filter = EventFilter{"type": testMsgType}
+	transformedHandler.Transform = &EventTransform{RemoveExtensions: []string{"eventtypeversion"}}
+	destinationURL, _ := url.Parse("http://subscriber.example.com")
+	retryConfig := kncloudevents.NoRetries()
+
+	for _, payloadSize := range benchmarkPayloadSizes {
+		record := createBenchmarkConsumerMessage(payloadSize)
+		for _, benchmarkHandler := range []*Handler{handler, &transformedHandler} {
+			benchmarkHandler := benchmarkHandler
+			name := "as-is"
+			if benchmarkHandler.Transform != nil {
+				name = "transformed"
+			}
+			b.Run(fmt.Sprintf("%s/%d", name, payloadSize), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(payloadSize))
+				for i := 0; i < b.N; i++ {
+					if err := benchmarkHandler.consumeMessage(context.Background(), record, destinationURL, nil, nil, &retryConfig); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Utility Function For Benchmarking Writing The Created Messages To HTTP Requests
+func benchmarkWriteRequest(b *testing.B, newMessage func() binding.Message) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		request, err := http.NewRequest(http.MethodPost, "http://subscriber.example.com", nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err = protocolhttp.WriteRequest(context.Background(), newMessage(), request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Utility Function For Creating A Binary Mode Record With A JSON Payload Of (About) The Specified Size
+func createBenchmarkConsumerMessage(payloadSize int) *sarama.ConsumerMessage {
+	record := &sarama.ConsumerMessage{Topic: "benchmark-topic"}
+	for _, header := range [][2]string{
+		{"content-type", "application/json"},
+		{"ce_specversion", testMsgSpecVersion},
+		{"ce_time", testMsgTime},
+		{"ce_id", testMsgId},
+		{"ce_source", testMsgSource},
+		{"ce_type", testMsgType},
+		{"ce_eventtypeversion", "v1"},
+		{"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	} {
+		record.Headers = append(record.Headers, &sarama.RecordHeader{Key: []byte(header[0]), Value: []byte(header[1])})
+	}
+	record.Value = []byte(fmt.Sprintf(`{"payload":%q}`, strings.Repeat("x", payloadSize)))
+	return record
+}
+
+// A RoundTripper Which Reads & Accepts Every Request (Without Any Network I/O Or Copying Of The Body)
+type benchmarkRoundTripper struct{}
+
+func (benchmarkRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Body != nil {
+		_, _ = io.Copy(ioutil.Discard, request.Body)
+		_ = request.Body.Close()
+	}
+	return &http.Response{
+		StatusCode: http.StatusAccepted,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		Request:    request,
+	}, nil
+}
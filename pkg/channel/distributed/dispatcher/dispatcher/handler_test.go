@@ -18,19 +18,35 @@ package dispatcher
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/Shopify/sarama"
 	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/events"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/constants"
 	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	"knative.dev/eventing-kafka/pkg/common/headers"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/kncloudevents"
@@ -190,10 +206,16 @@ func performHandlerConsumeClaimTest(t *testing.T, destinationUri, replyUri, dead
 		assert.Nil(t, err)
 	}
 
+	// Create The Expected Headers (Prefer: reply Only When A Reply Is Configured)
+	var expectedHeaders http.Header
+	if replyUrl != nil {
+		expectedHeaders = http.Header{constants.PreferHeader: []string{constants.PreferReplyHeader}}
+	}
+
 	// Create Mocks For Testing
 	mockConsumerGroupSession := dispatchertesting.NewMockConsumerGroupSession(t)
 	mockConsumerGroupClaim := dispatchertesting.NewMockConsumerGroupClaim(t)
-	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, replyUrl, deadLetterUrl, &retryConfig, nil)
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, expectedHeaders, destinationUrl, replyUrl, deadLetterUrl, &retryConfig, nil)
 
 	// Mock The newMessageDispatcherWrapper Function (And Restore Post-Test)
 	newMessageDispatcherWrapperPlaceholder := newMessageDispatcherWrapper
@@ -225,6 +247,139 @@ func performHandlerConsumeClaimTest(t *testing.T, destinationUri, replyUri, dead
 	assert.Equal(t, consumerMessage, markedMessage)
 	assert.NotNil(t, mockMessageDispatcher.Message())
 	verifyDispatchedMessage(t, mockMessageDispatcher.Message())
+	assert.Nil(t, mockMessageDispatcher.DeadLetterMessage())
+}
+
+// Test The Handler's consumeMessage() Functionality When Dispatching To The Subscriber Fails
+func TestHandlerConsumeMessageDispatchFailure(t *testing.T) {
+
+	// Test Data
+	dispatchErr := errors.New("unexpected HTTP response, expected 2xx, got 500")
+	retryConfig := kncloudevents.NoRetries()
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name           string
+		destinationUri *apis.URL
+		replyUri       *apis.URL
+		deadLetterUri  *apis.URL
+		expectedDest   string
+		expectErr      bool
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name:           "Subscriber Failure With DeadLetterSink",
+			destinationUri: testSubscriberURI,
+			replyUri:       testReplyURI,
+			deadLetterUri:  testDeadLetterURI,
+			expectedDest:   testSubscriberURIString,
+		},
+		{
+			name:          "Reply Failure With DeadLetterSink",
+			replyUri:      testReplyURI,
+			deadLetterUri: testDeadLetterURI,
+			expectedDest:  testReplyURIString,
+		},
+		{
+			name:           "Subscriber Failure Without DeadLetterSink",
+			destinationUri: testSubscriberURI,
+			expectedDest:   testSubscriberURIString,
+			expectErr:      true,
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Initialize The URLs As Specified
+			var destinationUrl, replyUrl, deadLetterUrl *url.URL
+			var expectedHeaders http.Header
+			if testCase.destinationUri != nil {
+				destinationUrl = testCase.destinationUri.URL()
+			}
+			if testCase.replyUri != nil {
+				replyUrl = testCase.replyUri.URL()
+				expectedHeaders = http.Header{constants.PreferHeader: []string{constants.PreferReplyHeader}}
+			}
+			if testCase.deadLetterUri != nil {
+				deadLetterUrl = testCase.deadLetterUri.URL()
+			}
+
+			// Create A Mock MessageDispatcher Which Fails To Dispatch To The Subscriber
+			mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, expectedHeaders, destinationUrl, replyUrl, deadLetterUrl, &retryConfig, dispatchErr)
+			mockMessageDispatcher.ResponseCode = http.StatusInternalServerError
+			recorder := record.NewFakeRecorder(1)
+			handler := &Handler{
+				Logger:            logtesting.TestLogger(t).Desugar(),
+				Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+				MessageDispatcher: mockMessageDispatcher,
+				EventReporter:     createTestEventReporter(t, recorder),
+			}
+
+			// Perform The Test
+			err := handler.consumeMessage(context.TODO(), createConsumerMessage(t), destinationUrl, replyUrl, deadLetterUrl, &retryConfig)
+
+			// Verify The Results
+			verifyDispatchedMessage(t, mockMessageDispatcher.Message())
+			assert.Equal(t, fmt.Sprintf("Warning %s Failed To Deliver Event To Subscriber %s (ResponseCode 500): %v", events.SubscriberUnreachable, testCase.expectedDest, dispatchErr), <-recorder.Events)
+			if testCase.expectErr {
+				assert.Equal(t, dispatchErr, err)
+				assert.Nil(t, mockMessageDispatcher.DeadLetterMessage())
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, mockMessageDispatcher.DeadLetterMessage())
+				verifyDispatchedMessage(t, mockMessageDispatcher.DeadLetterMessage())
+				deadLetterEvent, err := binding.ToEvent(context.TODO(), mockMessageDispatcher.DeadLetterMessage())
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.expectedDest, deadLetterEvent.Extensions()[deadletter.ErrorDestExtension])
+				assert.Equal(t, "500", deadLetterEvent.Extensions()[deadletter.ErrorCodeExtension])
+				assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(dispatchErr.Error())), deadLetterEvent.Extensions()[deadletter.ErrorDataExtension])
+				assert.Equal(t, "0", deadLetterEvent.Extensions()[deadletter.ErrorRetriesExtension])
+				assert.Equal(t, testTopic, deadLetterEvent.Extensions()[deadletter.ErrorTopicExtension])
+				assert.Equal(t, strconv.Itoa(testPartition), deadLetterEvent.Extensions()[deadletter.ErrorPartitionExtension])
+				assert.Equal(t, strconv.Itoa(testOffset), deadLetterEvent.Extensions()[deadletter.ErrorOffsetExtension])
+			}
+		})
+	}
+}
+
+// Test The Handler's ConsumeClaim() Functionality With Injected Rebalance Faults
+func TestHandlerConsumeClaimFaultInjection(t *testing.T) {
+
+	// Create Mocks For Testing
+	retryConfig := kncloudevents.NoRetries()
+	mockConsumerGroupSession := dispatchertesting.NewMockConsumerGroupSession(t)
+	mockConsumerGroupClaim := dispatchertesting.NewMockConsumerGroupClaim(t)
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, testSubscriberURI.URL(), nil, nil, &retryConfig, nil)
+
+	// Mock The newMessageDispatcherWrapper Function (And Restore Post-Test)
+	newMessageDispatcherWrapperPlaceholder := newMessageDispatcherWrapper
+	newMessageDispatcherWrapper = func(logger *zap.Logger) channel.MessageDispatcher {
+		return mockMessageDispatcher
+	}
+	defer func() { newMessageDispatcherWrapper = newMessageDispatcherWrapperPlaceholder }()
+
+	// Create The Handler To Test With A FaultInjector Which Always Rebalances
+	handler := createTestHandler(t, testSubscriberURI, nil, nil)
+	handler.FaultInjector = faults.NewInjector(handler.Logger, config.EKFaultInjectionConfig{Enabled: true, RebalancePercent: 100})
+
+	// Background Start Consuming Claims
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- handler.ConsumeClaim(mockConsumerGroupSession, mockConsumerGroupClaim)
+	}()
+
+	// Perform The Test (Add ConsumerMessages To Claims)
+	consumerMessage := createConsumerMessage(t)
+	mockConsumerGroupClaim.MessageChan <- consumerMessage
+
+	// Verify The Message Was Marked & ConsumeClaim() Exited Without The Claim Channel Being Closed
+	assert.Equal(t, consumerMessage, <-mockConsumerGroupSession.MarkMessageChan)
+	assert.Nil(t, <-errChan)
+	close(mockConsumerGroupClaim.MessageChan)
 }
 
 // Test The Custom CheckRetry() Implementation
@@ -356,6 +511,23 @@ func TestCheckRetry(t *testing.T) {
 	}
 }
 
+// Test The countingRetryConfig() Functionality
+func TestCountingRetryConfig(t *testing.T) {
+	retries := 0
+	retryConfig := kncloudevents.RetryConfig{
+		RetryMax:   2,
+		CheckRetry: func(_ context.Context, _ *http.Response, _ error) (bool, error) { return true, nil },
+	}
+	countingRetryConfig := countingRetryConfig(&retryConfig, &retries)
+	assert.Equal(t, retryConfig.RetryMax, countingRetryConfig.RetryMax)
+	for i := 0; i < 3; i++ {
+		retry, err := countingRetryConfig.CheckRetry(context.TODO(), nil, nil)
+		assert.True(t, retry)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 2, retries)
+}
+
 // Verify The Dispatched Message Contains Test Message Contents (Was Not Corrupted)
 func verifyDispatchedMessage(t *testing.T, message binding.MessageReader) {
 	dispatchedEvent, err := binding.ToEvent(context.TODO(), message)
@@ -413,7 +585,7 @@ func createTestHandler(t *testing.T, subscriberURL *apis.URL, replyUrl *apis.URL
 	}
 
 	// Perform The Test Create The Test Handler
-	handler := NewHandler(logger, testSubscriber)
+	handler := NewHandler(HandlerOptions{Logger: logger, Subscriber: testSubscriber})
 
 	// Verify The Results
 	assert.NotNil(t, handler)
@@ -425,6 +597,12 @@ func createTestHandler(t *testing.T, subscriberURL *apis.URL, replyUrl *apis.URL
 	return handler
 }
 
+// Utility Function For Creating A ChannelReporter Recording Events Against A Test KafkaChannel With The Specified Recorder
+func createTestEventReporter(t *testing.T, recorder record.EventRecorder) *events.ChannelReporter {
+	listers := dispatchertesting.NewListers([]runtime.Object{dispatchertesting.NewKafkaChannel("test-name", "test-namespace")})
+	return events.NewReporter(logtesting.TestLogger(t).Desugar(), recorder, listers.GetKafkaChannelLister()).ForChannel("test-namespace/test-name")
+}
+
 // Utility Function For Creating Valid ConsumerMessages
 func createConsumerMessage(t *testing.T) *sarama.ConsumerMessage {
 
@@ -479,3 +657,376 @@ func createConsumerMessage(t *testing.T) *sarama.ConsumerMessage {
 	// Return The Test ConsumerMessage
 	return consumerMessage
 }
+
+// Test The Handler's consumeMessage() Functionality When Producing Directly To A Kafka DeadLetter Topic
+func TestHandlerConsumeMessageDeadLetterTopic(t *testing.T) {
+
+	// Test Data
+	dispatchErr := errors.New("unexpected HTTP response, expected 2xx, got 500")
+	produceErr := errors.New("test produce error")
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+	deadLetterUrl := &url.URL{Scheme: kafkaconstants.DeadLetterSinkKafkaScheme}
+	deadLetterTopic := kafkautil.DeadLetterTopicName(testTopic, string(testSubscriberUID))
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name       string
+		produceErr error
+		expectErr  bool
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name: "Produce Success",
+		},
+		{
+			name:       "Produce Failure",
+			produceErr: produceErr,
+			expectErr:  true,
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Mock MessageDispatcher Which Fails To Dispatch To The Subscriber & A Mock DeadLetter Producer
+			mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, dispatchErr)
+			mockMessageDispatcher.ResponseCode = http.StatusInternalServerError
+			mockSyncProducer := dispatchertesting.NewMockSyncProducer(testCase.produceErr)
+			handler := &Handler{
+				Logger:             logtesting.TestLogger(t).Desugar(),
+				Subscriber:         &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+				MessageDispatcher:  mockMessageDispatcher,
+				DeadLetterProducer: mockSyncProducer,
+				DeadLetterTopic:    deadLetterTopic,
+			}
+
+			// Perform The Test
+			consumerMessage := createConsumerMessage(t)
+			consumerMessage.Key = []byte("TestKey")
+			err := handler.consumeMessage(context.TODO(), consumerMessage, destinationUrl, nil, deadLetterUrl, &retryConfig)
+
+			// Verify The Message Was Produced To The DeadLetter Topic (Not Dispatched Via HTTP)
+			verifyDispatchedMessage(t, mockMessageDispatcher.Message())
+			assert.Nil(t, mockMessageDispatcher.DeadLetterMessage())
+			assert.Len(t, mockSyncProducer.Messages(), 1)
+			producerMessage := mockSyncProducer.Messages()[0]
+			assert.Equal(t, deadLetterTopic, producerMessage.Topic)
+			assert.Equal(t, sarama.ByteEncoder(consumerMessage.Key), producerMessage.Key)
+			deadLetterEvent, eventErr := binding.ToEvent(context.TODO(), kafkasaramaprotocol.NewMessageFromConsumerMessage(toConsumerMessage(t, producerMessage)))
+			assert.Nil(t, eventErr)
+			assert.Equal(t, testMsgId, deadLetterEvent.ID())
+			assert.Equal(t, testSubscriberURIString, deadLetterEvent.Extensions()[deadletter.ErrorDestExtension])
+			assert.Equal(t, "500", deadLetterEvent.Extensions()[deadletter.ErrorCodeExtension])
+			assert.Equal(t, testTopic, deadLetterEvent.Extensions()[deadletter.ErrorTopicExtension])
+			if testCase.expectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// Test The Handler's consumeMessage() Functionality With Stale Events
+func TestHandlerConsumeMessageStaleEvent(t *testing.T) {
+
+	// Test Data
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+	deadLetterUrl := &url.URL{Scheme: kafkaconstants.DeadLetterSinkKafkaScheme}
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name           string
+		eventAgePolicy *EventAgePolicy
+		deadLetterUrl  *url.URL
+		wantDispatched bool
+		wantDeadLetter bool
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name:           "Fresh Event Dispatched",
+			eventAgePolicy: &EventAgePolicy{MaxEventAge: time.Hour},
+			deadLetterUrl:  deadLetterUrl,
+			wantDispatched: true,
+		},
+		{
+			name:           "Stale Event Skipped",
+			eventAgePolicy: &EventAgePolicy{MaxEventAge: time.Millisecond},
+			deadLetterUrl:  deadLetterUrl,
+		},
+		{
+			name:           "Stale Event Skipped Without DeadLetterSink",
+			eventAgePolicy: &EventAgePolicy{MaxEventAge: time.Millisecond, DeadLetter: true},
+		},
+		{
+			name:           "Stale Event Sent To DeadLetterSink",
+			eventAgePolicy: &EventAgePolicy{MaxEventAge: time.Millisecond, DeadLetter: true},
+			deadLetterUrl:  deadLetterUrl,
+			wantDeadLetter: true,
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Handler With Mock MessageDispatcher & DeadLetter Producer
+			mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, nil)
+			mockSyncProducer := dispatchertesting.NewMockSyncProducer(nil)
+			handler := &Handler{
+				Logger:             logtesting.TestLogger(t).Desugar(),
+				Subscriber:         &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+				MessageDispatcher:  mockMessageDispatcher,
+				DeadLetterProducer: mockSyncProducer,
+				DeadLetterTopic:    testTopic + ".dlq",
+				EventAgePolicy:     testCase.eventAgePolicy,
+			}
+
+			// Create A ConsumerMessage Which Is A Second Old
+			consumerMessage := createConsumerMessage(t)
+			for _, header := range consumerMessage.Headers {
+				if string(header.Key) == "ce_time" {
+					header.Value = []byte(time.Now().Add(-time.Second).Format(time.RFC3339Nano))
+				}
+			}
+
+			// Perform The Test
+			err := handler.consumeMessage(context.TODO(), consumerMessage, destinationUrl, nil, testCase.deadLetterUrl, &retryConfig)
+
+			// Verify The Results
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.wantDispatched, mockMessageDispatcher.Message() != nil)
+			if testCase.wantDeadLetter {
+				assert.Len(t, mockSyncProducer.Messages(), 1)
+				deadLetterEvent, eventErr := binding.ToEvent(context.TODO(), kafkasaramaprotocol.NewMessageFromConsumerMessage(toConsumerMessage(t, mockSyncProducer.Messages()[0])))
+				assert.Nil(t, eventErr)
+				assert.Equal(t, testSubscriberURIString, deadLetterEvent.Extensions()[deadletter.ErrorDestExtension])
+				assert.Equal(t, "-1", deadLetterEvent.Extensions()[deadletter.ErrorCodeExtension])
+				assert.NotEmpty(t, deadLetterEvent.Extensions()[deadletter.ErrorDataExtension])
+			} else {
+				assert.Empty(t, mockSyncProducer.Messages())
+			}
+		})
+	}
+}
+
+// Test The Handler's consumeMessage() Functionality With Duplicate Events
+func TestHandlerConsumeMessageDuplicateEvent(t *testing.T) {
+
+	// Test Data
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+
+	for _, drop := range []bool{false, true} {
+
+		// Create A Handler With Mock MessageDispatcher & Deduplicator
+		mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, nil)
+		handler := &Handler{
+			Logger:            logtesting.TestLogger(t).Desugar(),
+			Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+			MessageDispatcher: mockMessageDispatcher,
+			Deduplicator:      NewDeduplicator(config.EKDedupeConfig{Enabled: true, Drop: drop}),
+		}
+
+		// Consume The Same Event Twice
+		assert.Nil(t, handler.consumeMessage(context.TODO(), createConsumerMessage(t), destinationUrl, nil, nil, &retryConfig))
+		assert.NotNil(t, mockMessageDispatcher.Message())
+		mockMessageDispatcher = dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, nil)
+		handler.MessageDispatcher = mockMessageDispatcher
+		assert.Nil(t, handler.consumeMessage(context.TODO(), createConsumerMessage(t), destinationUrl, nil, nil, &retryConfig))
+
+		// Verify The Duplicate Is Only Dispatched If Not Dropped
+		assert.Equal(t, !drop, mockMessageDispatcher.Message() != nil)
+	}
+}
+
+// Test The Handler's consumeMessage() Functionality With Records Which Can't Be Decrypted
+func TestHandlerConsumeMessageUndecryptable(t *testing.T) {
+
+	// Test Data
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+
+	for _, quarantine := range []bool{false, true} {
+
+		// Create A Handler Without Encryption (nil Envelope) & Optionally A Poison Pill Quarantine
+		mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, nil)
+		mockSyncProducer := dispatchertesting.NewMockSyncProducer(nil)
+		handler := &Handler{
+			Logger:            logtesting.TestLogger(t).Desugar(),
+			Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+			MessageDispatcher: mockMessageDispatcher,
+		}
+		if quarantine {
+			handler.PoisonPillPolicy = &PoisonPillPolicy{MaxAttempts: 1, Quarantine: NewQuarantine("quarantine", mockSyncProducer)}
+		}
+
+		// Consume An Encrypted Record
+		consumerMessage := createConsumerMessage(t)
+		consumerMessage.Headers = append(consumerMessage.Headers, &sarama.RecordHeader{Key: []byte(encryption.AlgorithmHeader), Value: []byte(encryption.AlgorithmAES256GCM)})
+		err := handler.consumeMessage(context.TODO(), consumerMessage, destinationUrl, nil, nil, &retryConfig)
+
+		// Verify The Record Was Never Dispatched, Only Quarantined (Verbatim) If Poison Pills Are Handled
+		assert.Nil(t, mockMessageDispatcher.Message())
+		if quarantine {
+			assert.Nil(t, err)
+			assert.Len(t, mockSyncProducer.Messages(), 1)
+			assert.Equal(t, sarama.ByteEncoder(consumerMessage.Value), mockSyncProducer.Messages()[0].Value)
+		} else {
+			assert.True(t, errors.Is(err, encryption.ErrEncryptionDisabled))
+			assert.Empty(t, mockSyncProducer.Messages())
+		}
+	}
+}
+
+// Test The Handler's consumeMessage() Functionality With A Headers Policy
+func TestHandlerConsumeMessageHeadersPolicy(t *testing.T) {
+
+	// Test Data
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+
+	// Create A Handler With A Mock MessageDispatcher & A Headers Policy Allowing "x-" Headers
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, nil)
+	handler := &Handler{
+		Logger:            logtesting.TestLogger(t).Desugar(),
+		Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+		MessageDispatcher: mockMessageDispatcher,
+		HeadersPolicy:     &headers.Policy{Enabled: true, Allow: []string{"x-*"}, Deny: []string{"x-secret"}, StripPrefix: "x-"},
+	}
+
+	// Create A ConsumerMessage With Plain Kafka Headers
+	consumerMessage := createConsumerMessage(t)
+	consumerMessage.Headers = append(consumerMessage.Headers,
+		&sarama.RecordHeader{Key: []byte("x-tenant"), Value: []byte("acme")},
+		&sarama.RecordHeader{Key: []byte("x-secret"), Value: []byte("hidden")},
+		&sarama.RecordHeader{Key: []byte("other"), Value: []byte("ignored")})
+
+	// Perform The Test
+	err := handler.consumeMessage(context.TODO(), consumerMessage, destinationUrl, nil, nil, &retryConfig)
+
+	// Verify Only The Allowed Header Was Propagated As A CloudEvent Extension
+	assert.Nil(t, err)
+	dispatchedEvent, eventErr := binding.ToEvent(context.TODO(), mockMessageDispatcher.Message())
+	assert.Nil(t, eventErr)
+	assert.Equal(t, testMsgId, dispatchedEvent.ID())
+	assert.Equal(t, "acme", dispatchedEvent.Extensions()["tenant"])
+	assert.NotContains(t, dispatchedEvent.Extensions(), "secret")
+	assert.NotContains(t, dispatchedEvent.Extensions(), "other")
+}
+
+// Test The Handler's consumeMessage() Functionality With A Subscriber Transform
+func TestHandlerConsumeMessageTransform(t *testing.T) {
+
+	// Test Data
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+
+	// Create A Handler Redacting The Content, With A Subscriber Failing Deliveries & A Quarantine
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, errors.New("delivery failed"))
+	mockSyncProducer := dispatchertesting.NewMockSyncProducer(nil)
+	handler := &Handler{
+		Logger:            logtesting.TestLogger(t).Desugar(),
+		Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+		MessageDispatcher: mockMessageDispatcher,
+		Transform:         &EventTransform{Redact: []string{"content"}},
+		Quarantine:        NewQuarantine("quarantine", mockSyncProducer),
+	}
+
+	// Perform The Test
+	err := handler.consumeMessage(context.TODO(), createConsumerMessage(t), destinationUrl, nil, nil, &retryConfig)
+	assert.Nil(t, err)
+
+	// Verify The Subscriber Received The Redacted Event
+	dispatchedEvent, eventErr := binding.ToEvent(context.TODO(), mockMessageDispatcher.Message())
+	assert.Nil(t, eventErr)
+	assert.Equal(t, testMsgId, dispatchedEvent.ID())
+	assert.JSONEq(t, `{"content":"REDACTED"}`, string(dispatchedEvent.Data()))
+
+	// Verify The Quarantine Received The Untransformed Event (For Redelivery To All Subscribers)
+	assert.Len(t, mockSyncProducer.Messages(), 1)
+	quarantinedValue, encodeErr := mockSyncProducer.Messages()[0].Value.Encode()
+	assert.Nil(t, encodeErr)
+	assert.JSONEq(t, testMsgJsonContentString, string(quarantinedValue))
+
+	// Verify Events Which Can't Be Transformed Are Never Dispatched
+	mockMessageDispatcher = dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, nil)
+	handler.MessageDispatcher = mockMessageDispatcher
+	consumerMessage := createConsumerMessage(t)
+	consumerMessage.Value = []byte("not json")
+	assert.NotNil(t, handler.consumeMessage(context.TODO(), consumerMessage, destinationUrl, nil, nil, &retryConfig))
+	assert.Nil(t, mockMessageDispatcher.Message())
+}
+
+// Test The Handler's consumeMessage() Functionality With The KafkaChannel's Middleware
+func TestHandlerConsumeMessageMiddleware(t *testing.T) {
+
+	// Test Data
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+	target := middleware.Target{Phase: middleware.DispatchPhase, Namespace: "namespace", Channel: "channel", Subscriber: string(testSubscriberUID)}
+	var processedTarget middleware.Target
+	enrich := middleware.MiddlewareFunc(func(_ context.Context, target middleware.Target, cloudEvent *event.Event) (*event.Event, error) {
+		processedTarget = target
+		processedEvent := cloudEvent.Clone()
+		processedEvent.SetExtension("enriched", "true")
+		return &processedEvent, nil
+	})
+	drop := middleware.MiddlewareFunc(func(context.Context, middleware.Target, *event.Event) (*event.Event, error) { return nil, nil })
+	reject := middleware.MiddlewareFunc(func(context.Context, middleware.Target, *event.Event) (*event.Event, error) {
+		return nil, errors.New("rejected")
+	})
+
+	// Create A Handler Enriching Events, With A Subscriber Failing Deliveries & A Quarantine
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, errors.New("delivery failed"))
+	mockSyncProducer := dispatchertesting.NewMockSyncProducer(nil)
+	handler := &Handler{
+		Logger:            logtesting.TestLogger(t).Desugar(),
+		Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+		MessageDispatcher: mockMessageDispatcher,
+		Middleware:        middleware.Pipeline{{Name: "enrich", Middleware: enrich}},
+		MiddlewareTarget:  target,
+		Quarantine:        NewQuarantine("quarantine", mockSyncProducer),
+	}
+
+	// Verify The Subscriber Received The Enriched Event
+	assert.Nil(t, handler.consumeMessage(context.TODO(), createConsumerMessage(t), destinationUrl, nil, nil, &retryConfig))
+	assert.Equal(t, target, processedTarget)
+	dispatchedEvent, eventErr := binding.ToEvent(context.TODO(), mockMessageDispatcher.Message())
+	assert.Nil(t, eventErr)
+	assert.Equal(t, "true", dispatchedEvent.Extensions()["enriched"])
+
+	// Verify The Quarantine Received The Event Without Middleware (For Redelivery To All Subscribers)
+	assert.Len(t, mockSyncProducer.Messages(), 1)
+	for _, header := range mockSyncProducer.Messages()[0].Headers {
+		assert.NotEqual(t, "ce_enriched", string(header.Key))
+	}
+
+	// Verify Dropped & Rejected Events Are Never Dispatched
+	for _, pipeline := range []middleware.Pipeline{{{Name: "drop", Middleware: drop}}, {{Name: "reject", Middleware: reject}}} {
+		mockMessageDispatcher = dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, nil)
+		handler.MessageDispatcher = mockMessageDispatcher
+		handler.Middleware = pipeline
+		err := handler.consumeMessage(context.TODO(), createConsumerMessage(t), destinationUrl, nil, nil, &retryConfig)
+		assert.Equal(t, pipeline[0].Name == "reject", err != nil)
+		assert.Nil(t, mockMessageDispatcher.Message())
+	}
+}
+
+// Utility Function For Converting A Produced Sarama ProducerMessage Into The Equivalent ConsumerMessage
+func toConsumerMessage(t *testing.T, producerMessage *sarama.ProducerMessage) *sarama.ConsumerMessage {
+	value, err := producerMessage.Value.Encode()
+	assert.Nil(t, err)
+	consumerMessage := &sarama.ConsumerMessage{Topic: producerMessage.Topic, Value: value}
+	for i := range producerMessage.Headers {
+		consumerMessage.Headers = append(consumerMessage.Headers, &producerMessage.Headers[i])
+	}
+	return consumerMessage
+}
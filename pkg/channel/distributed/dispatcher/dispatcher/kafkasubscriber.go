@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+// Subscriber URI Scheme Implying Delivery By Producing To A Kafka Topic (kafka://<bootstrap>/<topic>)
+const KafkaSubscriberScheme = "kafka"
+
+// The Port Of Any Bootstrap Server Of A Kafka Subscriber URI Without One
+const defaultKafkaPort = "9092"
+
+// The Error Of Kafka Subscriber URIs Which Can't Be Produced To (Not Retried)
+var errInvalidKafkaDestination = errors.New("invalid kafka subscriber URI")
+
+// The Legal Kafka Topic Names (Max 249 Characters Of ASCII Alphanumerics, '.', '_' & '-')
+var kafkaTopicNameRegExp = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// The Kafka Topic (And Bootstrap Servers) Of A Kafka Subscriber URI
+type KafkaDestination struct {
+	Brokers []string // Empty For The KafkaChannel's Own Kafka Cluster (kafka:///<topic>)
+	Topic   string
+}
+
+// Determine Whether Events Are Delivered To The Specified Subscriber By Producing To A Kafka Topic
+func IsKafkaSubscriber(subscriberSpec *eventingduck.SubscriberSpec) bool {
+	return subscriberSpec != nil && !subscriberSpec.SubscriberURI.IsEmpty() && subscriberSpec.SubscriberURI.Scheme == KafkaSubscriberScheme
+}
+
+//
+// Parse The KafkaDestination Of A Kafka Subscriber URI
+//
+// The host of the URI is a comma separated list of the bootstrap servers of the Kafka cluster (the port defaulting
+// to 9092), and its path is the name of the Topic.  The host may be omitted (kafka:///<topic>) in order to produce to
+// a Topic of the KafkaChannel's own Kafka cluster, with its credentials.
+//
+func ParseKafkaDestination(destinationURL *url.URL) (*KafkaDestination, error) {
+	if destinationURL == nil || destinationURL.Scheme != KafkaSubscriberScheme {
+		return nil, fmt.Errorf("%w: %q is not a %s:// URI", errInvalidKafkaDestination, destinationURL, KafkaSubscriberScheme)
+	}
+
+	// Parse & Validate The Topic Name
+	topic := strings.TrimPrefix(destinationURL.Path, "/")
+	if !kafkaTopicNameRegExp.MatchString(topic) {
+		return nil, fmt.Errorf("%w: %q does not specify a valid Kafka topic name", errInvalidKafkaDestination, destinationURL)
+	}
+
+	// Parse The Bootstrap Servers (If Any)
+	var brokers []string
+	for _, broker := range strings.Split(destinationURL.Host, ",") {
+		if broker = strings.TrimSpace(broker); len(broker) == 0 {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			broker = net.JoinHostPort(strings.Trim(broker, "[]"), defaultKafkaPort)
+		}
+		brokers = append(brokers, broker)
+	}
+	return &KafkaDestination{Brokers: brokers, Topic: topic}, nil
+}
+
+//
+// Kafka Producers For Delivering CloudEvents To Kafka Subscribers
+//
+// Events are produced to the subscriber's Topic in the CloudEvents Kafka binary content mode, keyed by the partition
+// key of the consumed record.  A single SyncProducer is maintained per Kafka cluster (set of bootstrap servers) and
+// reused by all subscribers (and partitions) producing to it.  A nil *KafkaProducers is valid and simply fails all
+// deliveries.
+//
+type KafkaProducers struct {
+	logger        *zap.Logger
+	newProducer   func(brokers []string) (sarama.SyncProducer, error)
+	producers     map[string]sarama.SyncProducer
+	producersLock sync.Mutex
+}
+
+// KafkaProducers Constructor (The SyncProducer Of The KafkaChannel's Own Kafka Cluster Is Created With No Brokers)
+func NewKafkaProducers(logger *zap.Logger, newProducer func(brokers []string) (sarama.SyncProducer, error)) *KafkaProducers {
+	return &KafkaProducers{
+		logger:      logger,
+		newProducer: newProducer,
+		producers:   make(map[string]sarama.SyncProducer),
+	}
+}
+
+// Produce The Specified Event (With The Specified Partition Key) To The Topic Of The Specified Kafka Destination
+func (p *KafkaProducers) Produce(ctx context.Context, event *event.Event, key []byte, destinationURL *url.URL) error {
+
+	// Validate The KafkaProducers
+	if p == nil {
+		return fmt.Errorf("no kafka producer available for delivery to %s", destinationURL)
+	}
+
+	// Parse The Destination's Topic & Bootstrap Servers
+	destination, err := ParseKafkaDestination(destinationURL)
+	if err != nil {
+		return err
+	}
+
+	// Get The (Shared) SyncProducer Of The Destination's Kafka Cluster
+	producer, err := p.producer(destination.Brokers)
+	if err != nil {
+		return err
+	}
+
+	// Create The Sarama ProducerMessage, Retaining The Original Partition Key
+	producerMessage := &sarama.ProducerMessage{Topic: destination.Topic}
+	if key != nil {
+		producerMessage.Key = sarama.ByteEncoder(key)
+	}
+	err = kafkasaramaprotocol.WriteProducerMessage(ctx, binding.ToMessage(event), producerMessage)
+	if err != nil {
+		return fmt.Errorf("failed to encode event for kafka delivery: %w", err)
+	}
+
+	// Produce The Message To The Destination Topic
+	_, _, err = producer.SendMessage(producerMessage)
+	return err
+}
+
+// Close All SyncProducers Of The KafkaProducers
+func (p *KafkaProducers) Close() {
+	if p == nil {
+		return
+	}
+	p.producersLock.Lock()
+	defer p.producersLock.Unlock()
+	for key, producer := range p.producers {
+		if err := producer.Close(); err != nil {
+			p.logger.Warn("Failed To Close Kafka Subscriber Producer", zap.String("Brokers", key), zap.Error(err))
+		}
+		delete(p.producers, key)
+	}
+}
+
+// Get Or Lazily Create The SyncProducer Of The Specified Kafka Cluster
+func (p *KafkaProducers) producer(brokers []string) (sarama.SyncProducer, error) {
+	key := strings.Join(brokers, ",")
+
+	// Thread Safe ;)
+	p.producersLock.Lock()
+	defer p.producersLock.Unlock()
+
+	// Reuse Any Existing SyncProducer
+	if producer, ok := p.producers[key]; ok {
+		return producer, nil
+	}
+
+	// Otherwise Create A New SyncProducer
+	producer, err := p.newProducer(brokers)
+	if err != nil {
+		p.logger.Error("Failed To Create Kafka Subscriber Producer", zap.Strings("Brokers", brokers), zap.Error(err))
+		return nil, fmt.Errorf("failed to create kafka producer for %v: %w", brokers, err)
+	}
+	p.logger.Info("Created Kafka Subscriber Producer", zap.Strings("Brokers", brokers))
+	p.producers[key] = producer
+	return producer, nil
+}
+
+// Utility Function For Mapping A Kafka Produce Error To The Equivalent HTTP StatusCode (For Retry & DeadLetter Handling)
+func kafkaStatusCode(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, errInvalidKafkaDestination):
+		return http.StatusBadRequest
+	case errors.Is(err, sarama.ErrMessageSizeTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, sarama.ErrTopicAuthorizationFailed), errors.Is(err, sarama.ErrClusterAuthorizationFailed):
+		return http.StatusForbidden
+	case errors.Is(err, sarama.ErrUnknownTopicOrPartition):
+		return http.StatusNotFound
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/stretchr/testify/assert"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The IsKafkaSubscriber() Functionality
+func TestIsKafkaSubscriber(t *testing.T) {
+	httpURI, _ := apis.ParseURL("http://subscriber.ns.svc.cluster.local")
+	kafkaURI, _ := apis.ParseURL("kafka://my-cluster-kafka-bootstrap.kafka:9092/target-topic")
+
+	assert.True(t, IsKafkaSubscriber(&eventingduck.SubscriberSpec{SubscriberURI: kafkaURI}))
+	assert.False(t, IsKafkaSubscriber(&eventingduck.SubscriberSpec{SubscriberURI: httpURI}))
+	assert.False(t, IsKafkaSubscriber(&eventingduck.SubscriberSpec{}))
+	assert.False(t, IsKafkaSubscriber(nil))
+
+	// Kafka Subscribers Are Never Delivered To Via gRPC (Even If Opted In)
+	assert.False(t, GrpcSubscribers{"uid-1": true}.Enabled(&eventingduck.SubscriberSpec{UID: "uid-1", SubscriberURI: kafkaURI}))
+}
+
+// Test The ParseKafkaDestination() Functionality
+func TestParseKafkaDestination(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		uri         string
+		destination *KafkaDestination
+		wantErr     bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Own Cluster", uri: "kafka:///target-topic", destination: &KafkaDestination{Topic: "target-topic"}},
+		{name: "Single Broker", uri: "kafka://bootstrap.kafka:9093/target.topic_1", destination: &KafkaDestination{Brokers: []string{"bootstrap.kafka:9093"}, Topic: "target.topic_1"}},
+		{name: "Multiple Brokers", uri: "kafka://broker-1:9092,broker-2:9093/target-topic", destination: &KafkaDestination{Brokers: []string{"broker-1:9092", "broker-2:9093"}, Topic: "target-topic"}},
+		{name: "Default Port", uri: "kafka://bootstrap.kafka/target-topic", destination: &KafkaDestination{Brokers: []string{"bootstrap.kafka:9092"}, Topic: "target-topic"}},
+		{name: "Missing Topic", uri: "kafka://bootstrap.kafka:9092", wantErr: true},
+		{name: "Invalid Topic", uri: "kafka://bootstrap.kafka:9092/target/topic", wantErr: true},
+		{name: "Not Kafka", uri: "http://bootstrap.kafka:9092/target-topic", wantErr: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			destinationURL, err := url.Parse(testCase.uri)
+			assert.Nil(t, err)
+			destination, err := ParseKafkaDestination(destinationURL)
+			assert.Equal(t, testCase.destination, destination)
+			if testCase.wantErr {
+				assert.True(t, errors.Is(err, errInvalidKafkaDestination))
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// Test The KafkaProducers Produce() & Close() Functionality
+func TestKafkaProducersProduce(t *testing.T) {
+
+	// Create KafkaProducers Recording The Brokers Of Each Created Mock SyncProducer
+	producers := make(map[string]*dispatchertesting.MockSyncProducer)
+	kafkaProducers := NewKafkaProducers(logtesting.TestLogger(t).Desugar(), func(brokers []string) (sarama.SyncProducer, error) {
+		producer := dispatchertesting.NewMockSyncProducer(nil)
+		producers[fmt.Sprint(brokers)] = producer
+		return producer, nil
+	})
+	event := createTestGrpcEvent(t)
+
+	// Produce Multiple Events To The Own Cluster (Reusing Its Producer) & To Another Cluster
+	ownClusterURL, _ := url.Parse("kafka:///target-topic")
+	otherClusterURL, _ := url.Parse("kafka://broker-1,broker-2:9093/other-topic")
+	assert.Nil(t, kafkaProducers.Produce(context.TODO(), event, []byte("TestKey"), ownClusterURL))
+	assert.Nil(t, kafkaProducers.Produce(context.TODO(), event, nil, ownClusterURL))
+	assert.Nil(t, kafkaProducers.Produce(context.TODO(), event, nil, otherClusterURL))
+	assert.Len(t, producers, 2)
+	assert.Len(t, producers["[]"].Messages(), 2)
+	assert.Len(t, producers["[broker-1:9092 broker-2:9093]"].Messages(), 1)
+
+	// Verify The Produced Record Retains The Key & Carries The Event
+	producerMessage := producers["[]"].Messages()[0]
+	assert.Equal(t, "target-topic", producerMessage.Topic)
+	assert.Equal(t, sarama.ByteEncoder("TestKey"), producerMessage.Key)
+	producedEvent, err := binding.ToEvent(context.TODO(), kafkasaramaprotocol.NewMessageFromConsumerMessage(toConsumerMessage(t, producerMessage)))
+	assert.Nil(t, err)
+	assert.Equal(t, testMsgId, producedEvent.ID())
+	assert.Nil(t, producers["[]"].Messages()[1].Key)
+
+	// Verify An Invalid Destination Fails Without Creating A Producer
+	invalidURL, _ := url.Parse("kafka://broker-1")
+	assert.True(t, errors.Is(kafkaProducers.Produce(context.TODO(), event, nil, invalidURL), errInvalidKafkaDestination))
+	assert.Len(t, producers, 2)
+
+	// Verify The Producers Are Closed
+	kafkaProducers.Close()
+	assert.True(t, producers["[]"].Closed())
+	assert.True(t, producers["[broker-1:9092 broker-2:9093]"].Closed())
+	assert.Empty(t, kafkaProducers.producers)
+
+	// Verify nil KafkaProducers Fail Deliveries
+	var nilKafkaProducers *KafkaProducers
+	assert.NotNil(t, nilKafkaProducers.Produce(context.TODO(), event, nil, ownClusterURL))
+	nilKafkaProducers.Close()
+}
+
+// Test The kafkaStatusCode() Functionality
+func TestKafkaStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusOK, kafkaStatusCode(nil))
+	assert.Equal(t, http.StatusBadRequest, kafkaStatusCode(fmt.Errorf("%w: test", errInvalidKafkaDestination)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, kafkaStatusCode(sarama.ErrMessageSizeTooLarge))
+	assert.Equal(t, http.StatusForbidden, kafkaStatusCode(sarama.ErrTopicAuthorizationFailed))
+	assert.Equal(t, http.StatusNotFound, kafkaStatusCode(sarama.ErrUnknownTopicOrPartition))
+	assert.Equal(t, http.StatusServiceUnavailable, kafkaStatusCode(sarama.ErrNotEnoughReplicas))
+}
+
+// Test The Handler's produceWithRetries() Functionality
+func TestHandlerProduceWithRetries(t *testing.T) {
+
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Create KafkaProducers Failing To Create The First Two Producers
+	var failures int
+	producer := dispatchertesting.NewMockSyncProducer(nil)
+	kafkaProducers := NewKafkaProducers(logger, func(brokers []string) (sarama.SyncProducer, error) {
+		if failures < 2 {
+			failures++
+			return nil, errors.New("test unavailable")
+		}
+		return producer, nil
+	})
+
+	// Create A Handler For The Kafka Subscriber
+	destinationURL, _ := url.Parse("kafka:///target-topic")
+	subscriber := &eventingduck.SubscriberSpec{UID: testSubscriberUID}
+	handler := NewHandler(HandlerOptions{Logger: logger, Subscriber: subscriber, KafkaProducers: kafkaProducers})
+	message := binding.ToMessage(createTestGrpcEvent(t))
+
+	// Verify Retryable Failures Are Retried Until Success
+	retryConfig := kncloudevents.RetryConfig{
+		RetryMax:   3,
+		CheckRetry: handler.checkRetry,
+		Backoff:    func(int, *http.Response) time.Duration { return time.Millisecond },
+	}
+	statusCode, err := handler.produceWithRetries(context.Background(), message, []byte("TestKey"), destinationURL, &retryConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 2, failures)
+	assert.Len(t, producer.Messages(), 1)
+
+	// Verify Invalid Destinations Are Not Retried
+	invalidURL, _ := url.Parse("kafka:///")
+	statusCode, err = handler.produceWithRetries(context.Background(), message, nil, invalidURL, &retryConfig)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Len(t, producer.Messages(), 1)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// The CloudEvent Prefix & Content Type Header Of Kafka Messages (As In The CloudEvents Kafka Protocol Binding)
+const (
+	recordHeaderPrefix      = "ce_"
+	recordContentTypeHeader = "content-type"
+)
+
+// The CloudEvent Spec Versions Of Binary Mode Kafka Messages
+var recordSpecs = spec.WithPrefix(recordHeaderPrefix)
+
+// The Error Decoding A Record Which Is Neither A Binary Nor Structured Mode CloudEvent
+var errUnknownEncoding = errors.New("unknown encoding (the record is not a CloudEvent)")
+
+// Verify The recordMessage Implements The CloudEvents binding.Message & MessageMetadataReader
+var _ binding.Message = &recordMessage{}
+var _ binding.MessageMetadataReader = &recordMessage{}
+
+//
+// CloudEvents Message Reading A Consumed Kafka Record In Place
+//
+// Unlike the CloudEvents kafka_sarama Message, which copies the record's headers into a map of lower case keys
+// when it is created, the recordMessage reads the headers where they are (only converting those being written)
+// and hands the record's value to the writer without copying it, so that a record dispatched as-is (the common
+// case) reaches the HTTP request with only the copies the HTTP client makes.  When the event is needed (by the
+// PoisonPillPolicy, Filter, HeadersPolicy, Middleware, Transform, or a gRPC or Kafka subscriber) the record is
+// decoded once and that event is shared, the transformations modifying it in place (as they would any event
+// message) rather than each decoding its own copy of the record's data.
+//
+// The recordMessage may be read several times, and (like the record) may be retained once it has been consumed.
+//
+type recordMessage struct {
+	record  *sarama.ConsumerMessage
+	version spec.Version  // Binary Mode Records Only
+	format  format.Format // Structured Mode Records Only
+	event   *event.Event  // The Decoded Event (Once Decoded Successfully)
+}
+
+// Create A recordMessage Reading The Specified Record
+func newRecordMessage(record *sarama.ConsumerMessage) *recordMessage {
+	message := &recordMessage{record: record}
+	if contentType, ok := message.header("", recordContentTypeHeader); ok {
+		message.format = format.Lookup(string(contentType))
+	}
+	if message.format == nil {
+		if specVersion, ok := message.header(recordHeaderPrefix, "specversion"); ok {
+			message.version = recordSpecs.Version(string(specVersion))
+		}
+	}
+	return message
+}
+
+// Decode The Record Into A CloudEvent, Returning The Same Event Once It Has Been Decoded Successfully
+func (m *recordMessage) decode(ctx context.Context) (*event.Event, error) {
+	if m.event != nil {
+		return m.event, nil
+	}
+	if m.ReadEncoding() == binding.EncodingUnknown {
+		return nil, errUnknownEncoding
+	}
+	decodedEvent, err := binding.ToEvent(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	m.event = decodedEvent
+	return decodedEvent, nil
+}
+
+// Implement The binding.Message ReadEncoding() Function
+func (m *recordMessage) ReadEncoding() binding.Encoding {
+	if m.version != nil {
+		return binding.EncodingBinary
+	}
+	if m.format != nil {
+		return binding.EncodingStructured
+	}
+	return binding.EncodingUnknown
+}
+
+// Implement The binding.Message ReadStructured() Function
+func (m *recordMessage) ReadStructured(ctx context.Context, encoder binding.StructuredWriter) error {
+	if m.format == nil {
+		return binding.ErrNotStructured
+	}
+	return encoder.SetStructuredEvent(ctx, m.format, bytes.NewReader(m.record.Value))
+}
+
+// Implement The binding.Message ReadBinary() Function (Later Headers Replacing Earlier Ones With The Same Key)
+func (m *recordMessage) ReadBinary(ctx context.Context, encoder binding.BinaryWriter) error {
+	if m.version == nil {
+		return binding.ErrNotBinary
+	}
+	for index, recordHeader := range m.record.Headers {
+		if recordHeader == nil || m.replaced(index) {
+			continue
+		}
+		var err error
+		if hasPrefixFold(recordHeader.Key, recordHeaderPrefix) {
+			key := lowerKey(recordHeader.Key)
+			if attribute := m.version.Attribute(key); attribute != nil {
+				err = encoder.SetAttribute(attribute, string(recordHeader.Value))
+			} else {
+				err = encoder.SetExtension(strings.TrimPrefix(key, recordHeaderPrefix), string(recordHeader.Value))
+			}
+		} else if equalFold(recordHeader.Key, "", recordContentTypeHeader) {
+			err = encoder.SetAttribute(m.version.AttributeFromKind(spec.DataContentType), string(recordHeader.Value))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if m.record.Value != nil {
+		return encoder.SetData(bytes.NewReader(m.record.Value))
+	}
+	return nil
+}
+
+// Implement The binding.MessageMetadataReader GetAttribute() Function
+func (m *recordMessage) GetAttribute(kind spec.Kind) (spec.Attribute, interface{}) {
+	if m.version == nil {
+		return nil, nil
+	}
+	attribute := m.version.AttributeFromKind(kind)
+	if attribute == nil {
+		return nil, nil
+	}
+	value, _ := m.header("", attribute.PrefixedName())
+	return attribute, string(value)
+}
+
+// Implement The binding.MessageMetadataReader GetExtension() Function
+func (m *recordMessage) GetExtension(name string) interface{} {
+	value, _ := m.header(recordHeaderPrefix, name)
+	return string(value)
+}
+
+// Implement The binding.Message Finish() Function (Nothing To Do As The Record Is Marked By The Handler)
+func (m *recordMessage) Finish(error) error {
+	return nil
+}
+
+// Get The Value Of The Last Record Header With The Specified (Lower Case) Key Prefix & Name
+func (m *recordMessage) header(prefix string, name string) (value []byte, ok bool) {
+	for _, recordHeader := range m.record.Headers {
+		if recordHeader != nil && equalFold(recordHeader.Key, prefix, name) {
+			value, ok = recordHeader.Value, true
+		}
+	}
+	return value, ok
+}
+
+// Determine Whether The Record Header At The Specified Index Is Replaced By A Later One With The Same Key
+func (m *recordMessage) replaced(index int) bool {
+	key := m.record.Headers[index].Key
+	for _, recordHeader := range m.record.Headers[index+1:] {
+		if recordHeader != nil && bytes.EqualFold(recordHeader.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Utility Function For Comparing A Header Key To A (Lower Case) Prefix & Name Without Concatenating Them
+func equalFold(key []byte, prefix string, name string) bool {
+	return len(key) == len(prefix)+len(name) && hasPrefixFold(key, prefix) && strings.EqualFold(string(key[len(prefix):]), name)
+}
+
+// Utility Function For Determining Whether A Header Key Has The Specified (Lower Case) Prefix
+func hasPrefixFold(key []byte, prefix string) bool {
+	return len(key) >= len(prefix) && strings.EqualFold(string(key[:len(prefix)]), prefix)
+}
+
+// Utility Function For Converting A Header Key To A Lower Case String (Not Lower-Casing Keys Which Already Are)
+func lowerKey(key []byte) string {
+	for _, b := range key {
+		if 'A' <= b && b <= 'Z' {
+			return strings.ToLower(string(key))
+		}
+	}
+	return string(key)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	protocolhttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test That The recordMessage Writes The Same HTTP Requests As The CloudEvents kafka_sarama Message
+func TestRecordMessageWriteRequest(t *testing.T) {
+
+	// Create A Binary Mode Record With Mixed Case & Replaced Headers
+	mixedCaseRecord := createConsumerMessage(t)
+	mixedCaseRecord.Headers = append(mixedCaseRecord.Headers,
+		&sarama.RecordHeader{Key: []byte("CE_Type"), Value: []byte("replaced.type")},
+		&sarama.RecordHeader{Key: []byte("ce_MyExtension"), Value: []byte("myvalue")})
+
+	// Create A Structured Mode Record
+	structuredRecord := &sarama.ConsumerMessage{
+		Headers: []*sarama.RecordHeader{{Key: []byte("Content-Type"), Value: []byte("application/cloudevents+json; charset=UTF-8")}},
+		Value:   []byte(`{"specversion":"1.0","id":"structured-id","source":"/structured","type":"structured.type","data":{"a":"b"}}`),
+	}
+
+	// Define The TestCases
+	testCases := []struct {
+		name     string
+		record   *sarama.ConsumerMessage
+		encoding binding.Encoding
+	}{
+		{name: "Binary", record: createConsumerMessage(t), encoding: binding.EncodingBinary},
+		{name: "Binary Mixed Case & Replaced Headers", record: mixedCaseRecord, encoding: binding.EncodingBinary},
+		{name: "Structured", record: structuredRecord, encoding: binding.EncodingStructured},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			message := newRecordMessage(testCase.record)
+			assert.Equal(t, testCase.encoding, message.ReadEncoding())
+			wantHeader, wantBody := writeTestRequest(t, kafkasaramaprotocol.NewMessageFromConsumerMessage(testCase.record))
+			header, body := writeTestRequest(t, message)
+			assert.Equal(t, wantHeader, header)
+			assert.Equal(t, wantBody, body)
+
+			// The Message May Be Read Again
+			header, body = writeTestRequest(t, message)
+			assert.Equal(t, wantHeader, header)
+			assert.Equal(t, wantBody, body)
+		})
+	}
+}
+
+// Test The recordMessage's Metadata & Decode Functionality
+func TestRecordMessageDecode(t *testing.T) {
+	record := createConsumerMessage(t)
+	record.Headers = append(record.Headers, &sarama.RecordHeader{Key: []byte("ce_myextension"), Value: []byte("myvalue")})
+	message := newRecordMessage(record)
+
+	// The Metadata Is Read From The Record Headers
+	attribute, value := message.GetAttribute(spec.ID)
+	assert.Equal(t, "ce_id", attribute.PrefixedName())
+	assert.Equal(t, testMsgId, value)
+	assert.Equal(t, "myvalue", message.GetExtension("myextension"))
+	assert.Equal(t, "", message.GetExtension("missing"))
+
+	// The Record Is Decoded Once
+	decodedEvent, err := message.decode(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, testMsgId, decodedEvent.ID())
+	assert.Equal(t, testMsgType, decodedEvent.Type())
+	assert.Equal(t, "myvalue", decodedEvent.Extensions()["myextension"])
+	redecodedEvent, err := message.decode(context.TODO())
+	assert.Nil(t, err)
+	assert.True(t, decodedEvent == redecodedEvent)
+	sharedEvent, err := toEvent(context.TODO(), message)
+	assert.Nil(t, err)
+	assert.True(t, decodedEvent == sharedEvent)
+
+	// Records Which Are Not CloudEvents Fail To Decode
+	unknownMessage := newRecordMessage(&sarama.ConsumerMessage{Value: []byte("garbage")})
+	assert.Equal(t, binding.EncodingUnknown, unknownMessage.ReadEncoding())
+	attribute, value = unknownMessage.GetAttribute(spec.ID)
+	assert.Nil(t, attribute)
+	assert.Nil(t, value)
+	_, err = unknownMessage.decode(context.TODO())
+	assert.Equal(t, errUnknownEncoding, err)
+}
+
+// Utility Function For Writing A Message To An HTTP Request, Returning Its Header & Body
+func writeTestRequest(t *testing.T, message binding.Message) (http.Header, []byte) {
+	request, err := http.NewRequest(http.MethodPost, "http://localhost", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, protocolhttp.WriteRequest(context.TODO(), message, request))
+	assert.NotZero(t, request.ContentLength)
+	body, err := ioutil.ReadAll(request.Body)
+	assert.Nil(t, err)
+	return request.Header, body
+}
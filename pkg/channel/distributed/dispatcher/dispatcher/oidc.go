@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/constants"
+)
+
+// The Default & Minimum (Per The TokenRequest API) Lifetimes Of The Minted OIDC Tokens
+const (
+	DefaultOIDCTokenExpiration = time.Hour
+	MinOIDCTokenExpiration     = 10 * time.Minute
+)
+
+// A Minted OIDC Token & Its Lifetime
+type oidcToken struct {
+	token   string
+	issued  time.Time
+	expires time.Time
+}
+
+//
+// The OIDC Tokens Minted For The Audiences Of The Dispatcher's Subscribers
+//
+// The tokens are minted for the configured ServiceAccount via the TokenRequest API and cached per audience.  A token
+// is rotated once half of its lifetime has elapsed (or less than two DeliveryAuthRefreshIntervals of it remain, since
+// the subscribers' delivery headers are only refreshed that often), and a failure to rotate it falls back to the
+// cached token for as long as it is still valid.  A nil *OIDCTokens is valid and fails to mint any token.
+//
+type OIDCTokens struct {
+	logger         *zap.Logger
+	kubeClient     kubernetes.Interface
+	namespace      string
+	serviceAccount string
+	expiration     time.Duration
+	tokens         map[string]*oidcToken
+	lock           sync.Mutex
+	now            func() time.Time
+}
+
+// OIDCTokens Constructor - Returns nil If OIDC Tokens Are Not Enabled
+func NewOIDCTokens(logger *zap.Logger, kubeClient kubernetes.Interface, namespace string, oidcConfig config.EKOIDCConfig) *OIDCTokens {
+	if !oidcConfig.Enabled {
+		return nil
+	}
+
+	oidcTokens := &OIDCTokens{
+		logger:         logger,
+		kubeClient:     kubeClient,
+		namespace:      namespace,
+		serviceAccount: oidcConfig.ServiceAccount,
+		expiration:     time.Duration(oidcConfig.ExpirationSeconds) * time.Second,
+		tokens:         make(map[string]*oidcToken),
+		now:            time.Now,
+	}
+	if len(oidcTokens.serviceAccount) == 0 {
+		oidcTokens.serviceAccount = constants.DefaultOIDCServiceAccount
+	}
+	if oidcTokens.expiration <= 0 {
+		oidcTokens.expiration = DefaultOIDCTokenExpiration
+	} else if oidcTokens.expiration < MinOIDCTokenExpiration {
+		oidcTokens.expiration = MinOIDCTokenExpiration
+	}
+	return oidcTokens
+}
+
+// Get The OIDC Token For The Specified Audience, Minting A New One If None Is Cached Or It Is Due For Rotation
+func (o *OIDCTokens) Token(ctx context.Context, audience string) (string, error) {
+	if o == nil {
+		return "", fmt.Errorf("oidc tokens are not enabled in the dispatcher configuration")
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	// Return The Cached Token Unless It Is Due For Rotation
+	now := o.now()
+	cachedToken := o.tokens[audience]
+	if cachedToken != nil && !cachedToken.rotate(now) {
+		return cachedToken.token, nil
+	}
+
+	// Mint A New Token For The Audience, Falling Back To Any Still Valid Cached Token
+	token, err := o.mint(ctx, audience, now)
+	if err != nil {
+		if cachedToken != nil && now.Before(cachedToken.expires) {
+			o.logger.Warn("Failed To Rotate OIDC Token - Using Cached Token", zap.String("Audience", audience), zap.Time("Expires", cachedToken.expires), zap.Error(err))
+			return cachedToken.token, nil
+		}
+		return "", err
+	}
+	o.tokens[audience] = token
+	return token.token, nil
+}
+
+// Mint A New OIDC Token For The Specified Audience Via The ServiceAccount's TokenRequest
+func (o *OIDCTokens) mint(ctx context.Context, audience string, now time.Time) (*oidcToken, error) {
+	expirationSeconds := int64(o.expiration / time.Second)
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{audience},
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	tokenRequest, err := o.kubeClient.CoreV1().ServiceAccounts(o.namespace).CreateToken(ctx, o.serviceAccount, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint oidc token for audience %s: %w", audience, err)
+	}
+	if len(tokenRequest.Status.Token) == 0 || !validHeaderValue(tokenRequest.Status.Token) {
+		return nil, fmt.Errorf("invalid oidc token minted for audience %s", audience)
+	}
+
+	// Use The Expiration Reported By The API Server (Which May Shorten The Requested Lifetime)
+	expires := tokenRequest.Status.ExpirationTimestamp.Time
+	if expires.IsZero() {
+		expires = now.Add(o.expiration)
+	}
+	o.logger.Debug("Minted OIDC Token", zap.String("Audience", audience), zap.Time("Expires", expires))
+	return &oidcToken{token: tokenRequest.Status.Token, issued: now, expires: expires}, nil
+}
+
+// Determine Whether The Token Is Due For Rotation At The Specified Time
+func (t *oidcToken) rotate(now time.Time) bool {
+	remaining := t.expires.Sub(now)
+	return remaining < t.expires.Sub(t.issued)/2 || remaining < 2*constants.DeliveryAuthRefreshInterval
+}
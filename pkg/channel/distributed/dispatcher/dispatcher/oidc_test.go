@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/constants"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewOIDCTokens() Constructor
+func TestNewOIDCTokens(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Disabled OIDC Tokens Are nil & Fail To Mint Any Token
+	oidcTokens := NewOIDCTokens(logger, nil, "test-namespace", config.EKOIDCConfig{})
+	assert.Nil(t, oidcTokens)
+	token, err := oidcTokens.Token(context.TODO(), "test-audience")
+	assert.Empty(t, token)
+	assert.EqualError(t, err, "oidc tokens are not enabled in the dispatcher configuration")
+
+	// The ServiceAccount & Expiration Are Defaulted
+	oidcTokens = NewOIDCTokens(logger, nil, "test-namespace", config.EKOIDCConfig{Enabled: true})
+	assert.Equal(t, constants.DefaultOIDCServiceAccount, oidcTokens.serviceAccount)
+	assert.Equal(t, DefaultOIDCTokenExpiration, oidcTokens.expiration)
+
+	// The Expiration Can't Be Less Than The TokenRequest API's Minimum
+	oidcTokens = NewOIDCTokens(logger, nil, "test-namespace", config.EKOIDCConfig{Enabled: true, ServiceAccount: "test-sa", ExpirationSeconds: 60})
+	assert.Equal(t, "test-sa", oidcTokens.serviceAccount)
+	assert.Equal(t, MinOIDCTokenExpiration, oidcTokens.expiration)
+}
+
+// Test The OIDCTokens' Minting, Caching & Rotation Of Tokens
+func TestOIDCTokensToken(t *testing.T) {
+
+	// Mint Numbered Tokens For The Requested Audience, Unless Failing
+	var mints int
+	var mintErr error
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "serviceaccounts", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		createAction := action.(clientgotesting.CreateAction)
+		assert.Equal(t, "test-namespace", createAction.GetNamespace())
+		assert.Equal(t, "test-sa", createAction.(clientgotesting.CreateActionImpl).Name)
+		assert.Equal(t, "token", createAction.GetSubresource())
+		if mintErr != nil {
+			return true, nil, mintErr
+		}
+		mints++
+		tokenRequest := createAction.GetObject().(*authenticationv1.TokenRequest).DeepCopy()
+		assert.Equal(t, int64(3600), *tokenRequest.Spec.ExpirationSeconds)
+		tokenRequest.Status.Token = fmt.Sprintf("%s-%d", tokenRequest.Spec.Audiences[0], mints)
+		return true, tokenRequest, nil
+	})
+	oidcTokens := NewOIDCTokens(logtesting.TestLogger(t).Desugar(), kubeClient, "test-namespace", config.EKOIDCConfig{Enabled: true, ServiceAccount: "test-sa"})
+	now := time.Now()
+	oidcTokens.now = func() time.Time { return now }
+
+	// Tokens Are Minted Per Audience & Cached
+	token, err := oidcTokens.Token(context.TODO(), "audience-a")
+	assert.Nil(t, err)
+	assert.Equal(t, "audience-a-1", token)
+	token, err = oidcTokens.Token(context.TODO(), "audience-b")
+	assert.Nil(t, err)
+	assert.Equal(t, "audience-b-2", token)
+	now = now.Add(29 * time.Minute)
+	token, err = oidcTokens.Token(context.TODO(), "audience-a")
+	assert.Nil(t, err)
+	assert.Equal(t, "audience-a-1", token)
+
+	// A Token Is Rotated Once Half Of Its Lifetime Has Elapsed
+	now = now.Add(2 * time.Minute)
+	token, err = oidcTokens.Token(context.TODO(), "audience-a")
+	assert.Nil(t, err)
+	assert.Equal(t, "audience-a-3", token)
+
+	// A Failure To Rotate A Token Falls Back To The Cached Token While It Is Valid
+	mintErr = errors.New("forbidden")
+	now = now.Add(45 * time.Minute)
+	token, err = oidcTokens.Token(context.TODO(), "audience-a")
+	assert.Nil(t, err)
+	assert.Equal(t, "audience-a-3", token)
+
+	// A Failure To Mint A Token Without A Valid Cached Token Is Returned
+	now = now.Add(time.Hour)
+	token, err = oidcTokens.Token(context.TODO(), "audience-a")
+	assert.Empty(t, token)
+	assert.EqualError(t, err, "failed to mint oidc token for audience audience-a: forbidden")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// The Parallelism Of A KafkaChannel's Subscribers Keyed By Subscriber UID
+type SubscriberParallelism map[string]int
+
+// Create The SubscriberParallelism From The Specified KafkaChannel Annotations (nil If None)
+func NewSubscriberParallelism(annotations map[string]string) (SubscriberParallelism, error) {
+
+	// No Parallelism Limits If The Annotation Is Not Specified
+	parallelismJson := annotations[constants.SubscriberParallelismAnnotation]
+	if len(parallelismJson) == 0 {
+		return nil, nil
+	}
+
+	// Parse The Annotation's JSON Map Of Subscriber UID To Parallelism
+	var subscriberParallelism SubscriberParallelism
+	err := json.Unmarshal([]byte(parallelismJson), &subscriberParallelism)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", constants.SubscriberParallelismAnnotation, err)
+	}
+
+	// Validate Each Subscriber's Parallelism
+	for subscriberUID, parallelism := range subscriberParallelism {
+		if parallelism <= 0 {
+			return nil, fmt.Errorf("subscriber %s declares invalid parallelism %d in the %s annotation", subscriberUID, parallelism, constants.SubscriberParallelismAnnotation)
+		}
+	}
+
+	// Return The SubscriberParallelism
+	return subscriberParallelism, nil
+}
+
+// Get The Parallelism Of The Specified Subscriber (0 If Unlimited)
+func (p SubscriberParallelism) Parallelism(subscriberUID string) int {
+	return p[subscriberUID]
+}
+
+//
+// Parallelism Limiter Of A Single Subscriber
+//
+// Sarama consumes each partition claimed by a ConsumerGroup member in its own goroutine, so by default a
+// subscriber is dispatched to with a concurrency equal to the number of partitions claimed by the dispatcher.
+// The limiter is shared by all of a subscriber's ConsumeClaim goroutines and bounds how many of them may be
+// dispatching a message at any one time.  Messages within a partition are still dispatched in order, and
+// the effective parallelism can never exceed the number of claimed partitions.
+//
+// A nil *ParallelismLimiter is valid and represents the default behavior of one dispatch per claimed partition.
+//
+type ParallelismLimiter struct {
+	slots chan struct{}
+}
+
+// ParallelismLimiter Constructor - Returns nil If The Parallelism Is Unlimited
+func NewParallelismLimiter(parallelism int) *ParallelismLimiter {
+	if parallelism <= 0 {
+		return nil
+	}
+	return &ParallelismLimiter{slots: make(chan struct{}, parallelism)}
+}
+
+// Wait For A Dispatch Slot (Returns false If The Context Is Done First, In Which Case Nothing Must Be Released)
+func (l *ParallelismLimiter) Acquire(ctx context.Context) bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Release A Previously Acquired Dispatch Slot
+func (l *ParallelismLimiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// Test The NewSubscriberParallelism() Functionality
+func TestNewSubscriberParallelism(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name            string
+		annotations     map[string]string
+		wantParallelism SubscriberParallelism
+		wantError       string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name: "No Annotation",
+		},
+		{
+			name:            "Valid Parallelism",
+			annotations:     map[string]string{kafkaconstants.SubscriberParallelismAnnotation: `{"uid-1":1,"uid-2":4}`},
+			wantParallelism: SubscriberParallelism{"uid-1": 1, "uid-2": 4},
+		},
+		{
+			name:        "Invalid JSON",
+			annotations: map[string]string{kafkaconstants.SubscriberParallelismAnnotation: "invalid"},
+			wantError:   "invalid kafka.eventing.knative.dev/subscriber-parallelism annotation: invalid character 'i' looking for beginning of value",
+		},
+		{
+			name:        "Invalid Parallelism",
+			annotations: map[string]string{kafkaconstants.SubscriberParallelismAnnotation: `{"uid-1":0}`},
+			wantError:   "subscriber uid-1 declares invalid parallelism 0 in the kafka.eventing.knative.dev/subscriber-parallelism annotation",
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parallelism, err := NewSubscriberParallelism(testCase.annotations)
+			assert.Equal(t, testCase.wantParallelism, parallelism)
+			if len(testCase.wantError) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.wantError, err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// Test The SubscriberParallelism's Parallelism() Functionality
+func TestSubscriberParallelismParallelism(t *testing.T) {
+	var nilParallelism SubscriberParallelism
+	assert.Zero(t, nilParallelism.Parallelism("uid-1"))
+	subscriberParallelism := SubscriberParallelism{"uid-1": 3}
+	assert.Equal(t, 3, subscriberParallelism.Parallelism("uid-1"))
+	assert.Zero(t, subscriberParallelism.Parallelism("uid-2"))
+}
+
+// Test The Nil ParallelismLimiter Functionality
+func TestNilParallelismLimiter(t *testing.T) {
+	limiter := NewParallelismLimiter(0)
+	assert.Nil(t, limiter)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, limiter.Acquire(ctx))
+	limiter.Release()
+}
+
+// Test The ParallelismLimiter's Acquire() & Release() Functionality
+func TestParallelismLimiter(t *testing.T) {
+	limiter := NewParallelismLimiter(2)
+
+	// Slots Are Available Up To The Parallelism
+	assert.True(t, limiter.Acquire(context.Background()))
+	assert.True(t, limiter.Acquire(context.Background()))
+
+	// Further Acquires Wait Until The Context Is Done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, limiter.Acquire(ctx))
+
+	// Released Slots Are Available Again
+	limiter.Release()
+	assert.True(t, limiter.Acquire(context.Background()))
+}
+
+// Test The Handler's ConsumeClaim() Functionality With A Limited Parallelism
+func TestHandlerConsumeClaimParallelism(t *testing.T) {
+
+	// Create Mocks For Testing
+	retryConfig := kncloudevents.NoRetries()
+	mockConsumerGroupSession := dispatchertesting.NewMockConsumerGroupSession(t)
+	mockConsumerGroupClaim := dispatchertesting.NewMockConsumerGroupClaim(t)
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, testSubscriberURI.URL(), nil, nil, &retryConfig, nil)
+
+	// Mock The newMessageDispatcherWrapper Function (And Restore Post-Test)
+	newMessageDispatcherWrapperPlaceholder := newMessageDispatcherWrapper
+	newMessageDispatcherWrapper = func(logger *zap.Logger) channel.MessageDispatcher {
+		return mockMessageDispatcher
+	}
+	defer func() { newMessageDispatcherWrapper = newMessageDispatcherWrapperPlaceholder }()
+
+	// Create The Handler To Test With A Single Dispatch Slot Held By Another (Simulated) Claim
+	handler := createTestHandler(t, testSubscriberURI, nil, nil)
+	handler.Limiter = NewParallelismLimiter(1)
+	assert.True(t, handler.Limiter.Acquire(context.Background()))
+
+	// Background Start Consuming Claims
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- handler.ConsumeClaim(mockConsumerGroupSession, mockConsumerGroupClaim)
+	}()
+
+	// Perform The Test (Add ConsumerMessages To Claims)
+	consumerMessage := createConsumerMessage(t)
+	mockConsumerGroupClaim.MessageChan <- consumerMessage
+
+	// Verify The Message Is Not Dispatched While The Other Claim Holds The Slot
+	select {
+	case <-mockConsumerGroupSession.MarkMessageChan:
+		assert.Fail(t, "message consumed without a dispatch slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Verify The Message Is Dispatched & Marked Once The Slot Is Released (And That The Slot Is Released Again)
+	handler.Limiter.Release()
+	assert.Equal(t, consumerMessage, <-mockConsumerGroupSession.MarkMessageChan)
+	close(mockConsumerGroupClaim.MessageChan)
+	assert.Nil(t, <-errChan)
+	assert.True(t, handler.Limiter.Acquire(context.Background()))
+}
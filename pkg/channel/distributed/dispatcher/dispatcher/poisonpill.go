@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Poison Pill Defaults
+const (
+	DefaultPoisonPillMaxAttempts = 3
+	DefaultPoisonPillBackoff     = 100 * time.Millisecond
+)
+
+// The CloudEvent Type Of The Events Wrapping Poison Pill Records Sent To A DeadLetterSink
+const PoisonPillEventType = "dev.knative.kafka.poisonpill"
+
+// The Error Returned When The Session Ends Before A Record's Decode Attempts Are Exhausted (The Record Must Not Be Marked)
+var errDecodeInterrupted = errors.New("consumer group session ended before the decode attempts were exhausted")
+
+//
+// Poison Pill Detection Of A Single Subscriber
+//
+// A record which cannot be decoded into a valid CloudEvent (unknown encoding, malformed headers, missing required
+// attributes, etc.) is otherwise skipped with nothing but a log message.  The PoisonPillPolicy re-attempts the
+// decoding MaxAttempts times, so that only records which fail deterministically are considered poison pills, and
+// then quarantines them - verbatim to its own Quarantine topic if configured (otherwise to the KafkaChannel's), or
+// else wrapped in a CloudEvent and sent to the subscriber's DeadLetterSink.  A nil *PoisonPillPolicy is valid and
+// never detects any poison pills.
+//
+type PoisonPillPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	Quarantine  *Quarantine // nil Unless A Poison Pill Specific Quarantine Topic Is Configured
+}
+
+// Validate The Specified PoisonPill Config
+func ValidatePoisonPillConfig(poisonPillConfig config.EKPoisonPillConfig) error {
+	if poisonPillConfig.MaxAttempts < 0 {
+		return fmt.Errorf("maxAttempts %d must not be negative", poisonPillConfig.MaxAttempts)
+	}
+	if poisonPillConfig.BackoffMillis < 0 {
+		return fmt.Errorf("backoffMillis %d must not be negative", poisonPillConfig.BackoffMillis)
+	}
+	return nil
+}
+
+// PoisonPillPolicy Constructor - Returns nil If Poison Pill Detection Is Not Enabled (Assumes A Valid Config)
+func NewPoisonPillPolicy(poisonPillConfig config.EKPoisonPillConfig, producer sarama.SyncProducer) *PoisonPillPolicy {
+	if !poisonPillConfig.Enabled {
+		return nil
+	}
+	policy := &PoisonPillPolicy{
+		MaxAttempts: poisonPillConfig.MaxAttempts,
+		Backoff:     time.Duration(poisonPillConfig.BackoffMillis) * time.Millisecond,
+		Quarantine:  NewQuarantine(poisonPillConfig.QuarantineTopic, producer),
+	}
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultPoisonPillMaxAttempts
+	}
+	if policy.Backoff == 0 {
+		policy.Backoff = DefaultPoisonPillBackoff
+	}
+	return policy
+}
+
+// Attempt To Decode The Specified Record Until It Succeeds Or The Attempts Are Exhausted (Returns The Last Decode Error)
+func (p *PoisonPillPolicy) Decode(ctx context.Context, message *recordMessage) error {
+	if p == nil {
+		return nil
+	}
+	var err error
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		if err = decodeRecordMessage(ctx, message); err == nil || attempt == p.MaxAttempts {
+			break
+		}
+		select {
+		case <-time.After(p.Backoff):
+		case <-ctx.Done():
+			return errDecodeInterrupted
+		}
+	}
+	return err
+}
+
+// Utility Function For Decoding A Record Into A Valid CloudEvent (Which The Handler Then Reuses Rather Than Decoding Again)
+func decodeRecordMessage(ctx context.Context, message *recordMessage) error {
+	decodedEvent, err := message.decode(ctx)
+	if err != nil {
+		return err
+	}
+	return decodedEvent.Validate()
+}
+
+// Utility Function For Wrapping A Poison Pill Record's Value In A CloudEvent (Identified By Its Topic, Partition & Offset)
+func newPoisonPillEvent(consumerMessage *sarama.ConsumerMessage) event.Event {
+	poisonPillEvent := event.New()
+	poisonPillEvent.SetID(fmt.Sprintf("%s-%d-%d", consumerMessage.Topic, consumerMessage.Partition, consumerMessage.Offset))
+	poisonPillEvent.SetType(PoisonPillEventType)
+	poisonPillEvent.SetSource("/topics/" + consumerMessage.Topic)
+	if !consumerMessage.Timestamp.IsZero() {
+		poisonPillEvent.SetTime(consumerMessage.Timestamp)
+	}
+	_ = poisonPillEvent.SetData("application/octet-stream", consumerMessage.Value)
+	return poisonPillEvent
+}
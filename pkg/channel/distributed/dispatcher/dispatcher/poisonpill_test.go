@@ -0,0 +1,242 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ValidatePoisonPillConfig() Functionality
+func TestValidatePoisonPillConfig(t *testing.T) {
+	assert.Nil(t, ValidatePoisonPillConfig(config.EKPoisonPillConfig{}))
+	assert.Nil(t, ValidatePoisonPillConfig(config.EKPoisonPillConfig{Enabled: true, MaxAttempts: 5, BackoffMillis: 10, QuarantineTopic: "quarantine"}))
+	assert.NotNil(t, ValidatePoisonPillConfig(config.EKPoisonPillConfig{MaxAttempts: -1}))
+	assert.NotNil(t, ValidatePoisonPillConfig(config.EKPoisonPillConfig{BackoffMillis: -1}))
+}
+
+// Test The NewPoisonPillPolicy() Functionality
+func TestNewPoisonPillPolicy(t *testing.T) {
+
+	// Disabled Poison Pill Detection Returns nil
+	assert.Nil(t, NewPoisonPillPolicy(config.EKPoisonPillConfig{}, nil))
+
+	// Unset Values Are Defaulted
+	policy := NewPoisonPillPolicy(config.EKPoisonPillConfig{Enabled: true}, nil)
+	assert.Equal(t, DefaultPoisonPillMaxAttempts, policy.MaxAttempts)
+	assert.Equal(t, DefaultPoisonPillBackoff, policy.Backoff)
+	assert.Nil(t, policy.Quarantine)
+
+	// Specified Values Are Used
+	producer := dispatchertesting.NewMockSyncProducer(nil)
+	policy = NewPoisonPillPolicy(config.EKPoisonPillConfig{Enabled: true, MaxAttempts: 5, BackoffMillis: 10, QuarantineTopic: "quarantine"}, producer)
+	assert.Equal(t, 5, policy.MaxAttempts)
+	assert.Equal(t, 10*time.Millisecond, policy.Backoff)
+	assert.Equal(t, "quarantine", policy.Quarantine.Topic)
+	assert.Equal(t, producer, policy.Quarantine.Producer)
+}
+
+// Test The PoisonPillPolicy's Decode() Functionality
+func TestPoisonPillPolicyDecode(t *testing.T) {
+	policy := &PoisonPillPolicy{MaxAttempts: 2, Backoff: time.Millisecond}
+
+	// Valid CloudEvents Decode (As Does Anything Without A Policy)
+	assert.Nil(t, policy.Decode(context.TODO(), newRecordMessage(createConsumerMessage(t))))
+	var nilPolicy *PoisonPillPolicy
+	assert.Nil(t, nilPolicy.Decode(context.TODO(), newRecordMessage(createPoisonPillConsumerMessage(t))))
+
+	// Records Of Unknown Encoding Or Missing Required Attributes Fail To Decode
+	assert.NotNil(t, policy.Decode(context.TODO(), newRecordMessage(&sarama.ConsumerMessage{Value: []byte("garbage")})))
+	assert.NotNil(t, policy.Decode(context.TODO(), newRecordMessage(createPoisonPillConsumerMessage(t))))
+
+	// Attempts Are Abandoned Once The Context Is Done
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	assert.Equal(t, errDecodeInterrupted, policy.Decode(ctx, newRecordMessage(createPoisonPillConsumerMessage(t))))
+}
+
+// Test The Handler's consumeMessage() Functionality With Poison Pill Records
+func TestHandlerConsumeMessagePoisonPill(t *testing.T) {
+
+	// Test Data
+	produceErr := errors.New("test produce error")
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+	deadLetterUrl := testDeadLetterURI.URL()
+	kafkaDeadLetterUrl := &url.URL{Scheme: kafkaconstants.DeadLetterSinkKafkaScheme}
+	deadLetterTopic := kafkautil.DeadLetterTopicName(testTopic, string(testSubscriberUID))
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name                   string
+		quarantineTopic        string
+		channelQuarantineTopic string
+		deadLetterTopic        string
+		deadLetterUrl          *url.URL
+		produceErr             error
+		expectProduced         string
+		expectRaw              bool
+		expectDispatch         bool
+		expectErr              bool
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name:                   "Quarantine Topic",
+			quarantineTopic:        "quarantine",
+			channelQuarantineTopic: "channel-quarantine",
+			deadLetterUrl:          deadLetterUrl,
+			expectProduced:         "quarantine",
+			expectRaw:              true,
+		},
+		{
+			name:            "Quarantine Topic Produce Failure",
+			quarantineTopic: "quarantine",
+			produceErr:      produceErr,
+			expectProduced:  "quarantine",
+			expectRaw:       true,
+			expectErr:       true,
+		},
+		{
+			name:                   "Channel Quarantine Topic",
+			channelQuarantineTopic: "channel-quarantine",
+			deadLetterUrl:          deadLetterUrl,
+			expectProduced:         "channel-quarantine",
+			expectRaw:              true,
+		},
+		{
+			name:            "Kafka DeadLetterSink",
+			deadLetterTopic: deadLetterTopic,
+			deadLetterUrl:   kafkaDeadLetterUrl,
+			expectProduced:  deadLetterTopic,
+		},
+		{
+			name:           "HTTP DeadLetterSink",
+			deadLetterUrl:  deadLetterUrl,
+			expectDispatch: true,
+		},
+		{
+			name:      "No DeadLetterSink",
+			expectErr: true,
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Handler With A Mock MessageDispatcher (Only Expecting DeadLetterSink Dispatches) & Mock Producer
+			mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, deadLetterUrl, nil, nil, &retryConfig, nil)
+			mockSyncProducer := dispatchertesting.NewMockSyncProducer(testCase.produceErr)
+			handler := &Handler{
+				Logger:            logtesting.TestLogger(t).Desugar(),
+				Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+				MessageDispatcher: mockMessageDispatcher,
+				PoisonPillPolicy:  &PoisonPillPolicy{MaxAttempts: 2, Backoff: time.Millisecond, Quarantine: NewQuarantine(testCase.quarantineTopic, mockSyncProducer)},
+				Quarantine:        NewQuarantine(testCase.channelQuarantineTopic, mockSyncProducer),
+			}
+			if len(testCase.deadLetterTopic) > 0 {
+				handler.DeadLetterProducer = mockSyncProducer
+				handler.DeadLetterTopic = testCase.deadLetterTopic
+			}
+
+			// Perform The Test
+			consumerMessage := createPoisonPillConsumerMessage(t)
+			consumerMessage.Key = []byte("TestKey")
+			err := handler.consumeMessage(context.TODO(), consumerMessage, destinationUrl, nil, testCase.deadLetterUrl, &retryConfig)
+
+			// Verify The Poison Pill Was Never Dispatched To The Subscriber
+			if testCase.expectDispatch {
+				poisonPillEvent, eventErr := binding.ToEvent(context.TODO(), mockMessageDispatcher.Message())
+				assert.Nil(t, eventErr)
+				assert.Equal(t, PoisonPillEventType, poisonPillEvent.Type())
+				assert.Equal(t, consumerMessage.Value, poisonPillEvent.Data())
+				assert.Equal(t, testTopic, poisonPillEvent.Extensions()[deadletter.ErrorTopicExtension])
+			} else {
+				assert.Nil(t, mockMessageDispatcher.Message())
+			}
+
+			// Verify Any Produced Record
+			if len(testCase.expectProduced) > 0 {
+				assert.Len(t, mockSyncProducer.Messages(), 1)
+				producerMessage := mockSyncProducer.Messages()[0]
+				assert.Equal(t, testCase.expectProduced, producerMessage.Topic)
+				assert.Equal(t, sarama.ByteEncoder(consumerMessage.Key), producerMessage.Key)
+				producedMessage := toConsumerMessage(t, producerMessage)
+				if testCase.expectRaw {
+					assert.Equal(t, consumerMessage.Value, producedMessage.Value)
+					assert.Equal(t, []byte(strconv.FormatInt(testOffset, 10)), headerValue(producedMessage, deadletter.ErrorOffsetExtension))
+				} else {
+					deadLetterEvent, eventErr := binding.ToEvent(context.TODO(), kafkasaramaprotocol.NewMessageFromConsumerMessage(producedMessage))
+					assert.Nil(t, eventErr)
+					assert.Equal(t, PoisonPillEventType, deadLetterEvent.Type())
+					assert.Equal(t, consumerMessage.Value, deadLetterEvent.Data())
+				}
+			} else {
+				assert.Empty(t, mockSyncProducer.Messages())
+			}
+
+			// Verify The Returned Error
+			if testCase.expectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// Utility Function For Creating A ConsumerMessage Which Cannot Be Decoded Into A Valid CloudEvent (Missing Its Source)
+func createPoisonPillConsumerMessage(t *testing.T) *sarama.ConsumerMessage {
+	consumerMessage := createConsumerMessage(t)
+	headers := make([]*sarama.RecordHeader, 0, len(consumerMessage.Headers))
+	for _, header := range consumerMessage.Headers {
+		if string(header.Key) != "ce_source" {
+			headers = append(headers, header)
+		}
+	}
+	consumerMessage.Headers = headers
+	return consumerMessage
+}
+
+// Utility Function For Getting The Value Of A ConsumerMessage Header (nil If Not Present)
+func headerValue(consumerMessage *sarama.ConsumerMessage, key string) []byte {
+	for _, header := range consumerMessage.Headers {
+		if string(header.Key) == key {
+			return header.Value
+		}
+	}
+	return nil
+}
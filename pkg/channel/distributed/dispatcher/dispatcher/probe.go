@@ -0,0 +1,342 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/events"
+)
+
+// Health Probe Defaults
+const (
+	DefaultHealthProbeMethod           = nethttp.MethodHead
+	DefaultHealthProbeInterval         = 5 * time.Second
+	DefaultHealthProbeTimeout          = 2 * time.Second
+	DefaultHealthProbeFailureThreshold = 3
+	DefaultHealthProbeSuccessThreshold = 1
+)
+
+//
+// Health Probing Of The Subscribers Of A KafkaChannel
+//
+// A subscriber outage is otherwise only noticed once events fail to be delivered, at which point every consumed
+// event is retried (and then sent to the DeadLetterSink or quarantined) until the subscriber recovers.  The
+// SubscriberHealth instead actively probes each HTTP subscriber, pausing the consumption of its subscription while
+// it is unhealthy so that its events remain in the topic until it recovers, and tracks the paused subscribers for
+// the KafkaChannel's SubscribersHealthy condition.  It is shared by successive Dispatchers (which are recreated
+// upon configuration changes), and a nil *SubscriberHealth is valid and never probes any subscribers.
+//
+type SubscriberHealth struct {
+	logger           *zap.Logger
+	method           string
+	path             string
+	interval         time.Duration
+	failureThreshold int
+	successThreshold int
+	client           *nethttp.Client
+	eventReporter    *events.ChannelReporter
+	paused           map[types.UID]*HealthProbe
+	onChanged        func()
+	lock             sync.RWMutex
+}
+
+// Validate The Specified HealthProbe Config
+func ValidateHealthProbeConfig(healthProbeConfig config.EKHealthProbeConfig) error {
+	switch strings.ToUpper(healthProbeConfig.Method) {
+	case "", nethttp.MethodHead, nethttp.MethodOptions, nethttp.MethodGet:
+	default:
+		return fmt.Errorf("method %q must be one of HEAD, OPTIONS or GET", healthProbeConfig.Method)
+	}
+	if len(healthProbeConfig.Path) > 0 && !strings.HasPrefix(healthProbeConfig.Path, "/") {
+		return fmt.Errorf("path %q must begin with a '/'", healthProbeConfig.Path)
+	}
+	if healthProbeConfig.IntervalMillis < 0 {
+		return fmt.Errorf("intervalMillis %d must not be negative", healthProbeConfig.IntervalMillis)
+	}
+	if healthProbeConfig.TimeoutMillis < 0 {
+		return fmt.Errorf("timeoutMillis %d must not be negative", healthProbeConfig.TimeoutMillis)
+	}
+	if healthProbeConfig.FailureThreshold < 0 {
+		return fmt.Errorf("failureThreshold %d must not be negative", healthProbeConfig.FailureThreshold)
+	}
+	if healthProbeConfig.SuccessThreshold < 0 {
+		return fmt.Errorf("successThreshold %d must not be negative", healthProbeConfig.SuccessThreshold)
+	}
+	return nil
+}
+
+// SubscriberHealth Constructor - Returns nil If Health Probing Is Not Enabled (Assumes A Valid Config)
+func NewSubscriberHealth(logger *zap.Logger, healthProbeConfig config.EKHealthProbeConfig, eventReporter *events.ChannelReporter) *SubscriberHealth {
+	if !healthProbeConfig.Enabled {
+		return nil
+	}
+
+	subscriberHealth := &SubscriberHealth{
+		logger:           logger,
+		method:           strings.ToUpper(healthProbeConfig.Method),
+		path:             healthProbeConfig.Path,
+		interval:         time.Duration(healthProbeConfig.IntervalMillis) * time.Millisecond,
+		failureThreshold: healthProbeConfig.FailureThreshold,
+		successThreshold: healthProbeConfig.SuccessThreshold,
+		client:           &nethttp.Client{Timeout: time.Duration(healthProbeConfig.TimeoutMillis) * time.Millisecond},
+		eventReporter:    eventReporter,
+		paused:           make(map[types.UID]*HealthProbe),
+	}
+	if len(subscriberHealth.method) == 0 {
+		subscriberHealth.method = DefaultHealthProbeMethod
+	}
+	if subscriberHealth.interval <= 0 {
+		subscriberHealth.interval = DefaultHealthProbeInterval
+	}
+	if subscriberHealth.client.Timeout <= 0 {
+		subscriberHealth.client.Timeout = DefaultHealthProbeTimeout
+	}
+	if subscriberHealth.failureThreshold == 0 {
+		subscriberHealth.failureThreshold = DefaultHealthProbeFailureThreshold
+	}
+	if subscriberHealth.successThreshold == 0 {
+		subscriberHealth.successThreshold = DefaultHealthProbeSuccessThreshold
+	}
+	return subscriberHealth
+}
+
+// Set The Function Called Whenever A Subscriber Is Paused Or Resumed (e.g. Enqueuing The KafkaChannel For Reconciliation)
+func (s *SubscriberHealth) SetChangedHandler(onChanged func()) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.onChanged = onChanged
+}
+
+// Get The Reasons The Consumption Of The Currently Paused Subscribers Was Paused, Keyed By Subscriber UID
+func (s *SubscriberHealth) Outages() map[types.UID]string {
+	if s == nil {
+		return nil
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	outages := make(map[types.UID]string, len(s.paused))
+	for uid, probe := range s.paused {
+		outages[uid] = probe.reason()
+	}
+	return outages
+}
+
+// Create The (Unstarted) HealthProbe Of A Subscriber, Probing The Target Returned By The Specified Function (nil If Not Probed)
+func (s *SubscriberHealth) NewProbe(subscriberUID types.UID, target func() *url.URL) *HealthProbe {
+	if s == nil {
+		return nil
+	}
+	return &HealthProbe{
+		logger:        s.logger.With(zap.String("SubscriberUID", string(subscriberUID))),
+		health:        s,
+		subscriberUID: subscriberUID,
+		target:        target,
+	}
+}
+
+// Track The Specified HealthProbe's Subscriber As Paused
+func (s *SubscriberHealth) pause(probe *HealthProbe) {
+	s.lock.Lock()
+	s.paused[probe.subscriberUID] = probe
+	onChanged := s.onChanged
+	s.lock.Unlock()
+	s.eventReporter.Warning(events.SubscriberPaused, "Paused Consumption For Unhealthy Subscriber %s: %s", probe.subscriberUID, probe.reason())
+	if onChanged != nil {
+		onChanged()
+	}
+}
+
+// Stop Tracking The Specified HealthProbe's Subscriber As Paused (Unless Superseded By Another HealthProbe)
+func (s *SubscriberHealth) resume(probe *HealthProbe) {
+	s.lock.Lock()
+	if s.paused[probe.subscriberUID] != probe {
+		s.lock.Unlock()
+		return
+	}
+	delete(s.paused, probe.subscriberUID)
+	onChanged := s.onChanged
+	s.lock.Unlock()
+	if onChanged != nil {
+		onChanged()
+	}
+}
+
+//
+// Health Probe Of A Single Subscriber
+//
+// The HealthProbe is shared by all of a subscriber's ConsumeClaim goroutines, which Wait() before dispatching each
+// message for as long as the subscriber is unhealthy.  A nil *HealthProbe is valid and never pauses consumption.
+//
+type HealthProbe struct {
+	logger        *zap.Logger
+	health        *SubscriberHealth
+	subscriberUID types.UID
+	target        func() *url.URL
+	failures      int // Only Accessed By The Probing Goroutine
+	successes     int // Only Accessed By The Probing Goroutine
+	resumed       chan struct{}
+	lastErr       error
+	lock          sync.Mutex
+}
+
+// Probe The Subscriber Periodically Until The Specified Stop Channel Is Closed (Resuming Consumption Upon Stopping)
+func (p *HealthProbe) Start(stopChan <-chan struct{}) {
+	if p == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(p.health.interval)
+		defer ticker.Stop()
+		for {
+			p.Probe(context.Background())
+			select {
+			case <-stopChan:
+				p.resume()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait While The Subscriber Is Unhealthy (Returns false If The Context Is Done First)
+func (p *HealthProbe) Wait(ctx context.Context) bool {
+	if p == nil {
+		return true
+	}
+	p.lock.Lock()
+	resumed := p.resumed
+	p.lock.Unlock()
+	if resumed == nil {
+		return true
+	}
+	select {
+	case <-resumed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Probe The Subscriber Once, Pausing Or Resuming Consumption Once The Failure Or Success Threshold Is Reached
+func (p *HealthProbe) Probe(ctx context.Context) {
+	err := p.probe(ctx)
+	if err != nil {
+		p.failures++
+		p.successes = 0
+		p.logger.Debug("Subscriber Health Probe Failed", zap.Int("Failures", p.failures), zap.Error(err))
+		if p.failures >= p.health.failureThreshold && !p.isPaused() {
+			p.pause(err)
+		}
+	} else {
+		p.successes++
+		p.failures = 0
+		if p.successes >= p.health.successThreshold && p.isPaused() {
+			p.resume()
+		}
+	}
+}
+
+// Perform A Single Health Probe Request Of The Subscriber (nil If Healthy Or There Is Nothing To Probe)
+func (p *HealthProbe) probe(ctx context.Context) error {
+	target := p.target()
+	if target == nil {
+		return nil
+	}
+
+	// Probe The Configured Health Path Rather Than The Subscriber URI If Specified
+	probeURL := *target
+	if len(p.health.path) > 0 {
+		probeURL.Path = p.health.path
+		probeURL.RawPath = ""
+		probeURL.RawQuery = ""
+	}
+
+	request, err := nethttp.NewRequestWithContext(ctx, p.health.method, probeURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	response, err := p.health.client.Do(request)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	_ = response.Body.Close()
+
+	// Any Response Other Than A 5xx Shows An Event Endpoint Is Serving, Whereas A Health Path Must Not Answer With A 4xx Either
+	if response.StatusCode >= nethttp.StatusInternalServerError || (len(p.health.path) > 0 && response.StatusCode >= nethttp.StatusBadRequest) {
+		return fmt.Errorf("%s %s responded with %d", p.health.method, probeURL.String(), response.StatusCode)
+	}
+	return nil
+}
+
+// Determine Whether Consumption Is Currently Paused
+func (p *HealthProbe) isPaused() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.resumed != nil
+}
+
+// Get The Reason Consumption Was Paused (The Last Probe Error)
+func (p *HealthProbe) reason() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.lastErr == nil {
+		return ""
+	}
+	return p.lastErr.Error()
+}
+
+// Pause Consumption Due To The Specified Probe Error
+func (p *HealthProbe) pause(err error) {
+	p.lock.Lock()
+	p.resumed = make(chan struct{})
+	p.lastErr = err
+	p.lock.Unlock()
+	p.logger.Warn("Subscriber Unhealthy - Pausing Consumption", zap.Int("Failures", p.failures), zap.Error(err))
+	p.health.pause(p)
+}
+
+// Resume Any Paused Consumption
+func (p *HealthProbe) resume() {
+	p.lock.Lock()
+	resumed := p.resumed
+	p.resumed = nil
+	p.lastErr = nil
+	p.lock.Unlock()
+	if resumed == nil {
+		return
+	}
+	close(resumed)
+	p.logger.Info("Subscriber Healthy - Resuming Consumption")
+	p.health.resume(p)
+}
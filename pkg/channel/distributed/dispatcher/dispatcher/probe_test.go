@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ValidateHealthProbeConfig() Functionality
+func TestValidateHealthProbeConfig(t *testing.T) {
+	assert.Nil(t, ValidateHealthProbeConfig(config.EKHealthProbeConfig{}))
+	assert.Nil(t, ValidateHealthProbeConfig(config.EKHealthProbeConfig{Enabled: true, Method: "options", Path: "/healthz", IntervalMillis: 100, TimeoutMillis: 50, FailureThreshold: 2, SuccessThreshold: 2}))
+	assert.NotNil(t, ValidateHealthProbeConfig(config.EKHealthProbeConfig{Method: "POST"}))
+	assert.NotNil(t, ValidateHealthProbeConfig(config.EKHealthProbeConfig{Path: "healthz"}))
+	assert.NotNil(t, ValidateHealthProbeConfig(config.EKHealthProbeConfig{IntervalMillis: -1}))
+	assert.NotNil(t, ValidateHealthProbeConfig(config.EKHealthProbeConfig{TimeoutMillis: -1}))
+	assert.NotNil(t, ValidateHealthProbeConfig(config.EKHealthProbeConfig{FailureThreshold: -1}))
+	assert.NotNil(t, ValidateHealthProbeConfig(config.EKHealthProbeConfig{SuccessThreshold: -1}))
+}
+
+// Test The NewSubscriberHealth() Functionality
+func TestNewSubscriberHealth(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Disabled Health Probing Returns nil (Which Never Probes Or Pauses)
+	subscriberHealth := NewSubscriberHealth(logger, config.EKHealthProbeConfig{}, nil)
+	assert.Nil(t, subscriberHealth)
+	assert.Nil(t, subscriberHealth.Outages())
+	subscriberHealth.SetChangedHandler(func() {})
+	probe := subscriberHealth.NewProbe(testSubscriberUID, nil)
+	assert.Nil(t, probe)
+	probe.Start(nil)
+	assert.True(t, probe.Wait(context.TODO()))
+
+	// Unset Values Are Defaulted
+	subscriberHealth = NewSubscriberHealth(logger, config.EKHealthProbeConfig{Enabled: true}, nil)
+	assert.Equal(t, DefaultHealthProbeMethod, subscriberHealth.method)
+	assert.Equal(t, DefaultHealthProbeInterval, subscriberHealth.interval)
+	assert.Equal(t, DefaultHealthProbeTimeout, subscriberHealth.client.Timeout)
+	assert.Equal(t, DefaultHealthProbeFailureThreshold, subscriberHealth.failureThreshold)
+	assert.Equal(t, DefaultHealthProbeSuccessThreshold, subscriberHealth.successThreshold)
+
+	// Specified Values Are Used
+	subscriberHealth = NewSubscriberHealth(logger, config.EKHealthProbeConfig{Enabled: true, Method: "options", Path: "/healthz", IntervalMillis: 100, TimeoutMillis: 50, FailureThreshold: 2, SuccessThreshold: 3}, nil)
+	assert.Equal(t, nethttp.MethodOptions, subscriberHealth.method)
+	assert.Equal(t, "/healthz", subscriberHealth.path)
+	assert.Equal(t, 100*time.Millisecond, subscriberHealth.interval)
+	assert.Equal(t, 50*time.Millisecond, subscriberHealth.client.Timeout)
+	assert.Equal(t, 2, subscriberHealth.failureThreshold)
+	assert.Equal(t, 3, subscriberHealth.successThreshold)
+}
+
+// Test The HealthProbe's Pausing & Resuming Of Consumption
+func TestHealthProbe(t *testing.T) {
+
+	// Create A Test Subscriber Answering With The Current StatusCode & Recording The Probe Requests
+	var statusCode int32 = nethttp.StatusMethodNotAllowed
+	var method, path atomic.Value
+	server := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		method.Store(request.Method)
+		path.Store(request.URL.Path)
+		writer.WriteHeader(int(atomic.LoadInt32(&statusCode)))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL + "/events")
+
+	// Create The HealthProbe Of The Subscriber (Tracking The Changed Notifications)
+	subscriberHealth := NewSubscriberHealth(logtesting.TestLogger(t).Desugar(), config.EKHealthProbeConfig{Enabled: true, FailureThreshold: 2, SuccessThreshold: 2}, nil)
+	var changes int32
+	subscriberHealth.SetChangedHandler(func() { atomic.AddInt32(&changes, 1) })
+	probe := subscriberHealth.NewProbe(testSubscriberUID, func() *url.URL { return serverURL })
+
+	// Any Non-5xx Response Of The Subscriber URI Is Healthy
+	probe.Probe(context.TODO())
+	assert.Equal(t, nethttp.MethodHead, method.Load())
+	assert.Equal(t, "/events", path.Load())
+	assert.False(t, probe.isPaused())
+
+	// Consumption Is Paused Once The Failure Threshold Is Reached
+	atomic.StoreInt32(&statusCode, nethttp.StatusServiceUnavailable)
+	probe.Probe(context.TODO())
+	assert.False(t, probe.isPaused())
+	probe.Probe(context.TODO())
+	assert.True(t, probe.isPaused())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&changes))
+	outages := subscriberHealth.Outages()
+	assert.Len(t, outages, 1)
+	assert.Contains(t, outages[testSubscriberUID], "503")
+
+	// Waiting While Paused Ends With The Context
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, probe.Wait(ctx))
+
+	// Consumption Is Resumed Once The Success Threshold Is Reached (Releasing Any Waiters)
+	waited := make(chan bool)
+	go func() { waited <- probe.Wait(context.TODO()) }()
+	atomic.StoreInt32(&statusCode, nethttp.StatusOK)
+	probe.Probe(context.TODO())
+	assert.True(t, probe.isPaused())
+	probe.Probe(context.TODO())
+	assert.False(t, probe.isPaused())
+	assert.True(t, <-waited)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&changes))
+	assert.Empty(t, subscriberHealth.Outages())
+
+	// Unreachable Subscribers Are Unhealthy
+	server.Close()
+	probe.Probe(context.TODO())
+	probe.Probe(context.TODO())
+	assert.True(t, probe.isPaused())
+
+	// Stopping The Probe Resumes Consumption
+	stopChan := make(chan struct{})
+	probe.Start(stopChan)
+	close(stopChan)
+	assert.True(t, probe.Wait(context.TODO()))
+	assert.Eventually(t, func() bool { return len(subscriberHealth.Outages()) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+// Test The HealthProbe's Probing Of A Configured Health Path
+func TestHealthProbePath(t *testing.T) {
+
+	// Create A Test Subscriber Whose Health Path Is Not Found
+	var method, path atomic.Value
+	server := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		method.Store(request.Method)
+		path.Store(request.URL.Path)
+		writer.WriteHeader(nethttp.StatusNotFound)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL + "/events?query=value")
+
+	// A 4xx Response Of A Health Path Is Unhealthy
+	subscriberHealth := NewSubscriberHealth(logtesting.TestLogger(t).Desugar(), config.EKHealthProbeConfig{Enabled: true, Method: "GET", Path: "/healthz", FailureThreshold: 1}, nil)
+	probe := subscriberHealth.NewProbe(testSubscriberUID, func() *url.URL { return serverURL })
+	probe.Probe(context.TODO())
+	assert.Equal(t, nethttp.MethodGet, method.Load())
+	assert.Equal(t, "/healthz", path.Load())
+	assert.True(t, probe.isPaused())
+
+	// Subscribers Without A Subscriber URI Are Healthy
+	probe = subscriberHealth.NewProbe(types.UID("no-subscriber-uri"), func() *url.URL { return nil })
+	probe.Probe(context.TODO())
+	assert.False(t, probe.isPaused())
+}
+
+// Test That A Superseded HealthProbe Does Not Resume The Outage Of Its Successor
+func TestSubscriberHealthSupersededProbe(t *testing.T) {
+	subscriberHealth := NewSubscriberHealth(logtesting.TestLogger(t).Desugar(), config.EKHealthProbeConfig{Enabled: true}, nil)
+	previousProbe := subscriberHealth.NewProbe(testSubscriberUID, nil)
+	currentProbe := subscriberHealth.NewProbe(testSubscriberUID, nil)
+	previousProbe.pause(assert.AnError)
+	currentProbe.pause(assert.AnError)
+	previousProbe.resume()
+	assert.Len(t, subscriberHealth.Outages(), 1)
+	currentProbe.resume()
+	assert.Empty(t, subscriberHealth.Outages())
+}
+
+// Test The Handler's ConsumeClaim() Functionality With A Paused (Unhealthy) Subscriber
+func TestHandlerConsumeClaimPaused(t *testing.T) {
+
+	// Create Mocks For Testing
+	retryConfig := kncloudevents.NoRetries()
+	mockConsumerGroupSession := dispatchertesting.NewMockConsumerGroupSession(t)
+	mockConsumerGroupClaim := dispatchertesting.NewMockConsumerGroupClaim(t)
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, testSubscriberURI.URL(), nil, nil, &retryConfig, nil)
+
+	// Mock The newMessageDispatcherWrapper Function (And Restore Post-Test)
+	newMessageDispatcherWrapperPlaceholder := newMessageDispatcherWrapper
+	newMessageDispatcherWrapper = func(logger *zap.Logger) channel.MessageDispatcher {
+		return mockMessageDispatcher
+	}
+	defer func() { newMessageDispatcherWrapper = newMessageDispatcherWrapperPlaceholder }()
+
+	// Create The Handler To Test With A Paused Subscriber
+	handler := createTestHandler(t, testSubscriberURI, nil, nil)
+	subscriberHealth := NewSubscriberHealth(logtesting.TestLogger(t).Desugar(), config.EKHealthProbeConfig{Enabled: true}, nil)
+	handler.HealthProbe = subscriberHealth.NewProbe(testSubscriberUID, nil)
+	handler.HealthProbe.pause(assert.AnError)
+
+	// Background Start Consuming Claims
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- handler.ConsumeClaim(mockConsumerGroupSession, mockConsumerGroupClaim)
+	}()
+
+	// Perform The Test (Add ConsumerMessages To Claims)
+	consumerMessage := createConsumerMessage(t)
+	mockConsumerGroupClaim.MessageChan <- consumerMessage
+
+	// Verify The Message Is Not Dispatched While The Subscriber Is Paused
+	select {
+	case <-mockConsumerGroupSession.MarkMessageChan:
+		assert.Fail(t, "message consumed while the subscriber was paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Verify The Message Is Dispatched & Marked Once The Subscriber Is Resumed
+	handler.HealthProbe.resume()
+	assert.Equal(t, consumerMessage, <-mockConsumerGroupSession.MarkMessageChan)
+	close(mockConsumerGroupClaim.MessageChan)
+	assert.Nil(t, <-errChan)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+)
+
+//
+// Quarantine Topic Of A KafkaChannel
+//
+// Events which could not be delivered to a subscriber without a DeadLetterSink are otherwise dropped, and poison
+// pills are otherwise only sent to a DeadLetterSink.  The Quarantine instead retains them in the KafkaChannel's
+// quarantine topic (events with the usual delivery error extensions, poison pills verbatim with equivalent headers)
+// from which they can be re-injected into the KafkaChannel by an EventRedelivery once the cause has been fixed.
+// A nil *Quarantine is valid and represents a KafkaChannel without a quarantine topic.
+//
+type Quarantine struct {
+	Topic    string
+	Producer sarama.SyncProducer
+}
+
+// Quarantine Constructor - Returns nil If The KafkaChannel Has No Quarantine Topic
+func NewQuarantine(topic string, producer sarama.SyncProducer) *Quarantine {
+	if len(topic) == 0 || producer == nil {
+		return nil
+	}
+	return &Quarantine{Topic: topic, Producer: producer}
+}
+
+// Produce An Undeliverable Message To The Quarantine Topic With CloudEvent Extensions Describing The Delivery Error
+func (q *Quarantine) Produce(ctx context.Context, message binding.Message, deliveryError *deadletter.DeliveryError) error {
+
+	// Create The Sarama ProducerMessage, Retaining The Original Partition Key
+	producerMessage := &sarama.ProducerMessage{Topic: q.Topic}
+	if deliveryError.Message != nil && deliveryError.Message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(deliveryError.Message.Key)
+	}
+
+	// Populate The ProducerMessage From The Message Including The Delivery Error Extensions
+	err := kafkasaramaprotocol.WriteProducerMessage(ctx, message, producerMessage, deliveryError.Transformers()...)
+	if err != nil {
+		return fmt.Errorf("failed to add delivery error extensions to message (%v) after dispatch failure: %w", err, deliveryError.Err)
+	}
+
+	// Produce The Message To The Quarantine Topic
+	_, _, err = q.Producer.SendMessage(producerMessage)
+	if err != nil {
+		return fmt.Errorf("unable to complete request to either %s (%v) or quarantine topic %s (%v)", deliveryError.Destination, deliveryError.Err, q.Topic, err)
+	}
+	return nil
+}
+
+// Produce A Poison Pill Record Verbatim To The Quarantine Topic With Headers Describing The Decode Error
+func (q *Quarantine) ProduceRaw(consumerMessage *sarama.ConsumerMessage, decodeErr error) error {
+	_, _, err := q.Producer.SendMessage(newQuarantineMessage(q.Topic, consumerMessage, decodeErr))
+	if err != nil {
+		return fmt.Errorf("failed to produce poison pill (%v) to quarantine topic %s: %w", decodeErr, q.Topic, err)
+	}
+	return nil
+}
+
+// Utility Function For Creating The Quarantine Copy Of A Poison Pill Record (Original Key, Value & Headers Plus The Decode Error Headers)
+// Records Which Could Not Be Decrypted Are Still Encrypted, & Are Therefore Marked To Be Produced Verbatim
+func newQuarantineMessage(topic string, consumerMessage *sarama.ConsumerMessage, decodeErr error) *sarama.ProducerMessage {
+	headers := make([]sarama.RecordHeader, 0, len(consumerMessage.Headers)+4)
+	encrypted := false
+	for _, header := range consumerMessage.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+			encrypted = encrypted || string(header.Key) == encryption.AlgorithmHeader
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(deadletter.ErrorDataExtension), Value: []byte(decodeErr.Error())},
+		sarama.RecordHeader{Key: []byte(deadletter.ErrorTopicExtension), Value: []byte(consumerMessage.Topic)},
+		sarama.RecordHeader{Key: []byte(deadletter.ErrorPartitionExtension), Value: []byte(strconv.FormatInt(int64(consumerMessage.Partition), 10))},
+		sarama.RecordHeader{Key: []byte(deadletter.ErrorOffsetExtension), Value: []byte(strconv.FormatInt(consumerMessage.Offset, 10))})
+	producerMessage := &sarama.ProducerMessage{Topic: topic, Headers: headers}
+	if consumerMessage.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(consumerMessage.Key)
+	}
+	if consumerMessage.Value != nil {
+		producerMessage.Value = sarama.ByteEncoder(consumerMessage.Value)
+	}
+	if encrypted {
+		encryption.MarkVerbatim(producerMessage)
+	}
+	return producerMessage
+}
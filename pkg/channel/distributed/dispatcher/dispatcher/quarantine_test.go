@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewQuarantine() Functionality
+func TestNewQuarantine(t *testing.T) {
+	producer := dispatchertesting.NewMockSyncProducer(nil)
+	assert.Nil(t, NewQuarantine("", producer))
+	assert.Nil(t, NewQuarantine("quarantine", nil))
+	quarantine := NewQuarantine("quarantine", producer)
+	assert.NotNil(t, quarantine)
+	assert.Equal(t, "quarantine", quarantine.Topic)
+	assert.Equal(t, producer, quarantine.Producer)
+}
+
+// Test The Quarantine's ProduceRaw() Functionality
+func TestQuarantineProduceRaw(t *testing.T) {
+	decodeErr := errors.New("test decode error")
+
+	// Successfully Produced Records Retain Their Key, Value & Headers Plus The Decode Error Headers
+	producer := dispatchertesting.NewMockSyncProducer(nil)
+	consumerMessage := createConsumerMessage(t)
+	consumerMessage.Key = []byte("TestKey")
+	assert.Nil(t, NewQuarantine("quarantine", producer).ProduceRaw(consumerMessage, decodeErr))
+	assert.Len(t, producer.Messages(), 1)
+	producerMessage := producer.Messages()[0]
+	assert.Equal(t, "quarantine", producerMessage.Topic)
+	assert.Equal(t, sarama.ByteEncoder(consumerMessage.Key), producerMessage.Key)
+	producedMessage := toConsumerMessage(t, producerMessage)
+	assert.Equal(t, consumerMessage.Value, producedMessage.Value)
+	assert.Len(t, producedMessage.Headers, len(consumerMessage.Headers)+4)
+	assert.Equal(t, []byte(decodeErr.Error()), headerValue(producedMessage, deadletter.ErrorDataExtension))
+	assert.Equal(t, []byte(testTopic), headerValue(producedMessage, deadletter.ErrorTopicExtension))
+	assert.Equal(t, []byte(strconv.Itoa(testPartition)), headerValue(producedMessage, deadletter.ErrorPartitionExtension))
+	assert.Equal(t, []byte(strconv.Itoa(testOffset)), headerValue(producedMessage, deadletter.ErrorOffsetExtension))
+	assert.False(t, encryption.IsVerbatim(producerMessage))
+
+	// Records Which Are Still Encrypted (Could Not Be Decrypted) Are Marked To Be Produced Verbatim
+	producer = dispatchertesting.NewMockSyncProducer(nil)
+	encryptedMessage := createConsumerMessage(t)
+	encryptedMessage.Headers = append(encryptedMessage.Headers, &sarama.RecordHeader{Key: []byte(encryption.AlgorithmHeader), Value: []byte(encryption.AlgorithmAES256GCM)})
+	assert.Nil(t, NewQuarantine("quarantine", producer).ProduceRaw(encryptedMessage, decodeErr))
+	assert.Len(t, producer.Messages(), 1)
+	assert.True(t, encryption.IsVerbatim(producer.Messages()[0]))
+
+	// Produce Failures Are Returned
+	producer = dispatchertesting.NewMockSyncProducer(errors.New("test produce error"))
+	assert.NotNil(t, NewQuarantine("quarantine", producer).ProduceRaw(consumerMessage, decodeErr))
+}
+
+// Test The Handler's consumeMessage() Functionality With Undeliverable Events Quarantined (No DeadLetterSink)
+func TestHandlerConsumeMessageQuarantine(t *testing.T) {
+
+	// Test Data
+	dispatchErr := errors.New("unexpected HTTP response, expected 2xx, got 500")
+	produceErr := errors.New("test produce error")
+	retryConfig := kncloudevents.NoRetries()
+	destinationUrl := testSubscriberURI.URL()
+	quarantineTopic := "quarantine"
+
+	// Define The TestCase Type
+	type TestCase struct {
+		name       string
+		produceErr error
+		expectErr  bool
+	}
+
+	// Define The TestCases
+	testCases := []TestCase{
+		{
+			name: "Produce Success",
+		},
+		{
+			name:       "Produce Failure",
+			produceErr: produceErr,
+			expectErr:  true,
+		},
+	}
+
+	// Execute The Individual Test Cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Mock MessageDispatcher Which Fails To Dispatch To The Subscriber & A Mock Quarantine Producer
+			mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, nil, nil, &retryConfig, dispatchErr)
+			mockMessageDispatcher.ResponseCode = http.StatusInternalServerError
+			mockSyncProducer := dispatchertesting.NewMockSyncProducer(testCase.produceErr)
+			handler := &Handler{
+				Logger:            logtesting.TestLogger(t).Desugar(),
+				Subscriber:        &eventingduck.SubscriberSpec{UID: testSubscriberUID},
+				MessageDispatcher: mockMessageDispatcher,
+				Quarantine:        NewQuarantine(quarantineTopic, mockSyncProducer),
+			}
+
+			// Perform The Test
+			consumerMessage := createConsumerMessage(t)
+			consumerMessage.Key = []byte("TestKey")
+			err := handler.consumeMessage(context.TODO(), consumerMessage, destinationUrl, nil, nil, &retryConfig)
+
+			// Verify The Message Was Produced To The Quarantine Topic With The Delivery Error Extensions
+			verifyDispatchedMessage(t, mockMessageDispatcher.Message())
+			assert.Nil(t, mockMessageDispatcher.DeadLetterMessage())
+			assert.Len(t, mockSyncProducer.Messages(), 1)
+			producerMessage := mockSyncProducer.Messages()[0]
+			assert.Equal(t, quarantineTopic, producerMessage.Topic)
+			assert.Equal(t, sarama.ByteEncoder(consumerMessage.Key), producerMessage.Key)
+			quarantinedEvent, eventErr := binding.ToEvent(context.TODO(), kafkasaramaprotocol.NewMessageFromConsumerMessage(toConsumerMessage(t, producerMessage)))
+			assert.Nil(t, eventErr)
+			assert.Equal(t, testMsgId, quarantinedEvent.ID())
+			assert.Equal(t, testSubscriberURIString, quarantinedEvent.Extensions()[deadletter.ErrorDestExtension])
+			assert.Equal(t, "500", quarantinedEvent.Extensions()[deadletter.ErrorCodeExtension])
+			assert.Equal(t, testTopic, quarantinedEvent.Extensions()[deadletter.ErrorTopicExtension])
+			if testCase.expectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/tuning"
+)
+
+// Create The ConsumerGroup RebalanceStrategy From The Specified KafkaChannel Annotations (nil If None, To Use The ConfigMap's)
+func NewRebalanceStrategy(annotations map[string]string) (sarama.BalanceStrategy, error) {
+
+	// No Override If The Annotation Is Not Specified
+	strategyName := annotations[constants.RebalanceStrategyAnnotation]
+	if len(strategyName) == 0 {
+		return nil, nil
+	}
+
+	// Parse The Named RebalanceStrategy
+	rebalanceStrategy, err := consumer.NewBalanceStrategy(strategyName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", constants.RebalanceStrategyAnnotation, err)
+	}
+	return rebalanceStrategy, nil
+}
+
+// Create The ConsumerGroup Tuning Preset From The Specified KafkaChannel Annotations (Empty If None, To Use The ConfigMap's Settings)
+func NewTuningPreset(annotations map[string]string) (tuning.Preset, error) {
+	preset, err := tuning.NewPreset(annotations[constants.TuningPresetAnnotation])
+	if err != nil {
+		return "", fmt.Errorf("invalid %s annotation: %w", constants.TuningPresetAnnotation, err)
+	}
+	return preset, nil
+}
+
+// Determine Whether Two (Possibly nil) RebalanceStrategies Are Equivalent
+func rebalanceStrategyEqual(strategy1 sarama.BalanceStrategy, strategy2 sarama.BalanceStrategy) bool {
+	if strategy1 == nil || strategy2 == nil {
+		return strategy1 == nil && strategy2 == nil
+	}
+	return strategy1.Name() == strategy2.Name()
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/common/tuning"
+)
+
+// Test The NewRebalanceStrategy() Functionality
+func TestNewRebalanceStrategy(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name         string
+		annotations  map[string]string
+		wantStrategy sarama.BalanceStrategy
+		wantError    string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name: "No Annotation",
+		},
+		{
+			name:         "Sticky",
+			annotations:  map[string]string{kafkaconstants.RebalanceStrategyAnnotation: "sticky"},
+			wantStrategy: sarama.BalanceStrategySticky,
+		},
+		{
+			name:         "RoundRobin",
+			annotations:  map[string]string{kafkaconstants.RebalanceStrategyAnnotation: "RoundRobin"},
+			wantStrategy: sarama.BalanceStrategyRoundRobin,
+		},
+		{
+			name:        "Unknown",
+			annotations: map[string]string{kafkaconstants.RebalanceStrategyAnnotation: "foo"},
+			wantError:   `invalid kafka.eventing.knative.dev/rebalance-strategy annotation: unknown rebalance strategy "foo", use one of [range roundrobin sticky]`,
+		},
+		{
+			name:        "Cooperative Sticky",
+			annotations: map[string]string{kafkaconstants.RebalanceStrategyAnnotation: "cooperative-sticky"},
+			wantError:   `invalid kafka.eventing.knative.dev/rebalance-strategy annotation: rebalance strategy "cooperative-sticky" is not supported by the Kafka client, use one of [range roundrobin sticky]`,
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			strategy, err := NewRebalanceStrategy(testCase.annotations)
+			if testCase.wantError != "" {
+				assert.EqualError(t, err, testCase.wantError)
+				assert.Nil(t, strategy)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.wantStrategy, strategy)
+			}
+		})
+	}
+}
+
+// Test The rebalanceStrategyEqual() Functionality
+func TestRebalanceStrategyEqual(t *testing.T) {
+	assert.True(t, rebalanceStrategyEqual(nil, nil))
+	assert.True(t, rebalanceStrategyEqual(sarama.BalanceStrategySticky, sarama.BalanceStrategySticky))
+	assert.False(t, rebalanceStrategyEqual(nil, sarama.BalanceStrategySticky))
+	assert.False(t, rebalanceStrategyEqual(sarama.BalanceStrategyRange, nil))
+	assert.False(t, rebalanceStrategyEqual(sarama.BalanceStrategyRange, sarama.BalanceStrategySticky))
+}
+
+// Test The NewTuningPreset() Functionality
+func TestNewTuningPreset(t *testing.T) {
+
+	// No Annotation Uses The ConfigMap's Settings
+	preset, err := NewTuningPreset(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, preset)
+
+	// A Named Preset Is Parsed Case Insensitively
+	preset, err = NewTuningPreset(map[string]string{kafkaconstants.TuningPresetAnnotation: "Throughput"})
+	assert.Nil(t, err)
+	assert.Equal(t, tuning.Throughput, preset)
+
+	// An Unknown Preset Is An Error
+	preset, err = NewTuningPreset(map[string]string{kafkaconstants.TuningPresetAnnotation: "fast"})
+	assert.EqualError(t, err, `invalid kafka.eventing.knative.dev/tuning-preset annotation: unknown tuning preset "fast", use one of [throughput latency balanced]`)
+	assert.Empty(t, preset)
+}
@@ -0,0 +1,426 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/plugin/ochttp"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/channel"
+	messagingv1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/messaging/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/network"
+	"knative.dev/pkg/tracing/propagation/tracecontextb3"
+)
+
+// Destination Resolution Defaults
+const (
+	DefaultResolutionInterval = 30 * time.Second
+)
+
+// The HTTP Connection Pool Limits Of The Shared Transports (Matching Those Of The Knative MessageDispatcher)
+const (
+	sharedMaxIdleConns        = 1000
+	sharedMaxIdleConnsPerHost = 100
+)
+
+// The Re-Resolved Destinations Of A Single Subscriber (Differing From Those Of Its SubscriberSpec)
+type resolvedDestinations struct {
+	subscriberURI *apis.URL
+	replyURI      *apis.URL
+	deadLetterURI *apis.URL
+}
+
+//
+// Periodic Re-Resolution Of The Subscribers' Destinations
+//
+// The destinations of the SubscriberSpecs are resolved by the Subscription controller, and are otherwise static
+// until the KafkaChannel is updated.  The DestinationResolver periodically re-resolves the addressables (and
+// Kubernetes Services) referenced by the Subscriptions, delivering to any changed addresses in the meantime, and
+// looks up the DNS addresses of the destination hosts, closing the idle HTTP connections (and dropping the gRPC
+// connections) of any host whose addresses changed so that deliveries are not pinned to migrated services.  The
+// HTTP connection pool is therefore shared by all subscribers.  A nil *DestinationResolver is valid and never
+// re-resolves any destinations.
+//
+type DestinationResolver struct {
+	logger             *zap.Logger
+	interval           time.Duration
+	namespace          string
+	subscriptionClient messagingv1.SubscriptionsGetter
+	dynamicClient      dynamic.Interface
+	transport          *nethttp.Transport
+	sender             *kncloudevents.HTTPMessageSender
+	lookupHost         func(ctx context.Context, host string) ([]string, error)
+	onHostChanged      func(host string)
+	subscribers        map[types.UID]eventingduck.SubscriberSpec
+	overrides          map[types.UID]*resolvedDestinations
+	addresses          map[string][]string // Only Accessed By Resolve()
+	lock               sync.RWMutex
+}
+
+// Validate The Specified Resolution Config
+func ValidateResolutionConfig(resolutionConfig config.EKResolutionConfig) error {
+	if resolutionConfig.IntervalSeconds < 0 {
+		return fmt.Errorf("intervalSeconds %d must not be negative", resolutionConfig.IntervalSeconds)
+	}
+	return nil
+}
+
+// DestinationResolver Constructor - Returns nil If Resolution Is Not Enabled (Assumes A Valid Config)
+func NewDestinationResolver(logger *zap.Logger, resolutionConfig config.EKResolutionConfig, channelKey string, subscriptionClient messagingv1.SubscriptionsGetter, dynamicClient dynamic.Interface) *DestinationResolver {
+	if !resolutionConfig.Enabled {
+		return nil
+	}
+
+	interval := time.Duration(resolutionConfig.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = DefaultResolutionInterval
+	}
+
+	// Create The Shared HTTP Transport & Sender (As The Knative MessageDispatcher Would, But Retaining The Transport)
+	transport := newSharedTransport()
+	sender := newSharedSender(transport)
+
+	return &DestinationResolver{
+		logger:             logger,
+		interval:           interval,
+		namespace:          strings.SplitN(channelKey, "/", 2)[0],
+		subscriptionClient: subscriptionClient,
+		dynamicClient:      dynamicClient,
+		transport:          transport,
+		sender:             sender,
+		lookupHost:         net.DefaultResolver.LookupHost,
+		subscribers:        make(map[types.UID]eventingduck.SubscriberSpec),
+		overrides:          make(map[types.UID]*resolvedDestinations),
+		addresses:          make(map[string][]string),
+	}
+}
+
+// Periodically Re-Resolve The Destinations Until The Specified Stop Channel Is Closed
+func (r *DestinationResolver) Start(stopChan <-chan struct{}) {
+	if r == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopChan:
+				return
+			case <-ticker.C:
+				r.Resolve(context.Background())
+			}
+		}
+	}()
+}
+
+// Track The Specified (Current) SubscriberSpecs, Discarding The Re-Resolved Destinations Of Any Changed Subscribers
+func (r *DestinationResolver) SetSubscribers(subscriberSpecs []eventingduck.SubscriberSpec) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	subscribers := make(map[types.UID]eventingduck.SubscriberSpec, len(subscriberSpecs))
+	overrides := make(map[types.UID]*resolvedDestinations)
+	for _, subscriberSpec := range subscriberSpecs {
+		subscribers[subscriberSpec.UID] = subscriberSpec
+		if override, ok := r.overrides[subscriberSpec.UID]; ok && reflect.DeepEqual(r.subscribers[subscriberSpec.UID], subscriberSpec) {
+			overrides[subscriberSpec.UID] = override
+		}
+	}
+	r.subscribers = subscribers
+	r.overrides = overrides
+}
+
+// Get The Subscriber, Reply & DeadLetterSink Destinations Of The Specified Subscriber (Re-Resolved If Changed)
+func (r *DestinationResolver) Destinations(subscriberSpec *eventingduck.SubscriberSpec) (*apis.URL, *apis.URL, *apis.URL) {
+	if r != nil {
+		r.lock.RLock()
+		override, ok := r.overrides[subscriberSpec.UID]
+		r.lock.RUnlock()
+		if ok {
+			return override.subscriberURI, override.replyURI, override.deadLetterURI
+		}
+	}
+	return subscriberSpec.SubscriberURI, subscriberSpec.ReplyURI, deadLetterSinkURI(subscriberSpec)
+}
+
+// Re-Resolve The Subscriptions' Destinations & The DNS Addresses Of Their Hosts
+func (r *DestinationResolver) Resolve(ctx context.Context) {
+	if r == nil {
+		return
+	}
+
+	// Get The Current Subscribers
+	r.lock.RLock()
+	subscribers := make(map[types.UID]eventingduck.SubscriberSpec, len(r.subscribers))
+	for uid, subscriberSpec := range r.subscribers {
+		subscribers[uid] = subscriberSpec
+	}
+	previousOverrides := r.overrides
+	r.lock.RUnlock()
+
+	// Re-Resolve The Destinations Referenced By Their Subscriptions
+	overrides := r.resolveSubscriptions(ctx, subscribers, previousOverrides)
+
+	// Track The Re-Resolved Destinations Of Subscribers Which Have Not Changed In The Meantime
+	r.lock.Lock()
+	for uid := range overrides {
+		if current, ok := r.subscribers[uid]; !ok || !reflect.DeepEqual(current, subscribers[uid]) {
+			delete(overrides, uid)
+		}
+	}
+	r.overrides = overrides
+	r.lock.Unlock()
+
+	// Lookup The DNS Addresses Of The Destination Hosts & Invalidate The Connections Of Any Which Changed
+	changedHosts := r.resolveHosts(ctx, r.hosts(subscribers))
+	if len(changedHosts) > 0 {
+		r.transport.CloseIdleConnections()
+		r.lock.RLock()
+		onHostChanged := r.onHostChanged
+		r.lock.RUnlock()
+		for _, host := range changedHosts {
+			if onHostChanged != nil {
+				onHostChanged(host)
+			}
+		}
+	}
+}
+
+// Set The Function Invalidating Any Other Connections Of A Host Whose Addresses Changed (e.g. gRPC Connections)
+func (r *DestinationResolver) setHostChangedHandler(onHostChanged func(host string)) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.onHostChanged = onHostChanged
+}
+
+// Create A Knative MessageDispatcher Sending Via The Shared Transport (Or A Dedicated One If nil)
+func (r *DestinationResolver) messageDispatcher(logger *zap.Logger) channel.MessageDispatcher {
+	if r == nil {
+		return newMessageDispatcherWrapper(logger)
+	}
+	return channel.NewMessageDispatcherFromSender(logger, r.sender)
+}
+
+// Re-Resolve The Destinations Referenced By The Subscriptions Of The Specified Subscribers
+func (r *DestinationResolver) resolveSubscriptions(ctx context.Context, subscribers map[types.UID]eventingduck.SubscriberSpec, previousOverrides map[types.UID]*resolvedDestinations) map[types.UID]*resolvedDestinations {
+
+	// Get The Subscriptions Of The KafkaChannel's Namespace (Retaining The Previous Destinations Upon Failure)
+	overrides := make(map[types.UID]*resolvedDestinations)
+	if r.subscriptionClient == nil {
+		return overrides
+	}
+	subscriptionList, err := r.subscriptionClient.Subscriptions(r.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		r.logger.Warn("Failed To List Subscriptions - Retaining Previously Resolved Destinations", zap.Error(err))
+		for uid, override := range previousOverrides {
+			overrides[uid] = override
+		}
+		return overrides
+	}
+
+	// Re-Resolve The Ref Based Destinations Of The Subscriptions Of The Subscribers
+	for i := range subscriptionList.Items {
+		subscription := &subscriptionList.Items[i]
+		subscriberSpec, ok := subscribers[subscription.UID]
+		if !ok {
+			continue
+		}
+
+		var subscriberURI, replyURI, deadLetterURI *apis.URL
+		subscriberURI, err = r.resolveDestination(ctx, subscription.Spec.Subscriber, subscription.Namespace, subscriberSpec.SubscriberURI)
+		if err == nil {
+			replyURI, err = r.resolveDestination(ctx, subscription.Spec.Reply, subscription.Namespace, subscriberSpec.ReplyURI)
+		}
+		if err == nil {
+			var deadLetterSink *duckv1.Destination
+			if subscription.Spec.Delivery != nil {
+				deadLetterSink = subscription.Spec.Delivery.DeadLetterSink
+			}
+			deadLetterURI, err = r.resolveDestination(ctx, deadLetterSink, subscription.Namespace, deadLetterSinkURI(&subscriberSpec))
+		}
+		if err != nil {
+			r.logger.Warn("Failed To Re-Resolve Subscription Destinations - Retaining Previously Resolved Destinations", zap.String("Subscription", subscription.Name), zap.Error(err))
+			if override, ok := previousOverrides[subscription.UID]; ok {
+				overrides[subscription.UID] = override
+			}
+			continue
+		}
+
+		// Track The Destinations If Any Differ From The SubscriberSpec's
+		if subscriberURI.String() != subscriberSpec.SubscriberURI.String() || replyURI.String() != subscriberSpec.ReplyURI.String() || deadLetterURI.String() != deadLetterSinkURI(&subscriberSpec).String() {
+			if _, ok := previousOverrides[subscription.UID]; !ok {
+				r.logger.Info("Subscription Destinations Re-Resolved",
+					zap.String("Subscription", subscription.Name),
+					zap.String("SubscriberURI", subscriberURI.String()),
+					zap.String("ReplyURI", replyURI.String()),
+					zap.String("DeadLetterSinkURI", deadLetterURI.String()))
+			}
+			overrides[subscription.UID] = &resolvedDestinations{subscriberURI: subscriberURI, replyURI: replyURI, deadLetterURI: deadLetterURI}
+		}
+	}
+	return overrides
+}
+
+// Resolve The Specified Destination If It References An Addressable (Otherwise Returns The Current URI)
+func (r *DestinationResolver) resolveDestination(ctx context.Context, destination *duckv1.Destination, namespace string, currentURI *apis.URL) (*apis.URL, error) {
+	if destination == nil || destination.Ref == nil {
+		return currentURI, nil
+	}
+
+	// Default The Namespace Of The Referenced Object To The Subscription's
+	ref := destination.Ref
+	if len(ref.Namespace) > 0 {
+		namespace = ref.Namespace
+	}
+	groupVersion, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion %q of %s %s: %w", ref.APIVersion, ref.Kind, ref.Name, err)
+	}
+
+	// Resolve Kubernetes Services Directly, Otherwise Use The Addressable's Status Address
+	var address *apis.URL
+	if groupVersion.Group == "" && ref.Kind == "Service" {
+		address = &apis.URL{Scheme: "http", Host: network.GetServiceHostname(ref.Name, namespace), Path: "/"}
+	} else {
+		if r.dynamicClient == nil {
+			return currentURI, nil
+		}
+		groupVersionResource, _ := meta.UnsafeGuessKindToResource(groupVersion.WithKind(ref.Kind))
+		object, err := r.dynamicClient.Resource(groupVersionResource).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %s/%s: %w", ref.Kind, namespace, ref.Name, err)
+		}
+		addressURL, _, _ := unstructured.NestedString(object.Object, "status", "address", "url")
+		if len(addressURL) == 0 {
+			return nil, fmt.Errorf("%s %s/%s does not have an address", ref.Kind, namespace, ref.Name)
+		}
+		address, err = apis.ParseURL(addressURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q of %s %s/%s: %w", addressURL, ref.Kind, namespace, ref.Name, err)
+		}
+	}
+
+	// Resolve Any (Relative) URI Against The Address
+	if destination.URI != nil {
+		address = address.ResolveReference(destination.URI)
+	}
+	return address, nil
+}
+
+// Get The (Sorted & Distinct) Hosts Of The Current Destinations Of The Specified Subscribers (Excluding Kafka Subscribers)
+func (r *DestinationResolver) hosts(subscribers map[types.UID]eventingduck.SubscriberSpec) []string {
+	hostSet := make(map[string]bool)
+	for _, subscriberSpec := range subscribers {
+		subscriberSpec := subscriberSpec
+		subscriberURI, replyURI, deadLetterURI := r.Destinations(&subscriberSpec)
+		for _, uri := range []*apis.URL{subscriberURI, replyURI, deadLetterURI} {
+			if !uri.IsEmpty() && uri.Scheme != KafkaSubscriberScheme && len(uri.URL().Hostname()) > 0 && net.ParseIP(uri.URL().Hostname()) == nil {
+				hostSet[uri.URL().Hostname()] = true
+			}
+		}
+	}
+	hosts := make([]string, 0, len(hostSet))
+	for host := range hostSet {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// Lookup The DNS Addresses Of The Specified Hosts, Returning Those Whose Addresses Changed Since The Last Lookup
+func (r *DestinationResolver) resolveHosts(ctx context.Context, hosts []string) []string {
+	var changedHosts []string
+	addresses := make(map[string][]string, len(hosts))
+	for _, host := range hosts {
+		previousAddresses, known := r.addresses[host]
+		lookupCtx, cancel := context.WithTimeout(ctx, r.interval)
+		hostAddresses, err := r.lookupHost(lookupCtx, host)
+		cancel()
+		if err != nil {
+			r.logger.Debug("Failed To Lookup Subscriber Host", zap.String("Host", host), zap.Error(err))
+			if known {
+				addresses[host] = previousAddresses
+			}
+			continue
+		}
+		sort.Strings(hostAddresses)
+		if known && !reflect.DeepEqual(previousAddresses, hostAddresses) {
+			r.logger.Info("Subscriber Host Addresses Changed - Invalidating Connections",
+				zap.String("Host", host),
+				zap.Strings("PreviousAddresses", previousAddresses),
+				zap.Strings("Addresses", hostAddresses))
+			changedHosts = append(changedHosts, host)
+		}
+		addresses[host] = hostAddresses
+	}
+	r.addresses = addresses
+	return changedHosts
+}
+
+// Utility Function For Creating An HTTP Transport Shared By All Subscribers (Configured As The Knative MessageDispatcher's)
+func newSharedTransport() *nethttp.Transport {
+	transport := nethttp.DefaultTransport.(*nethttp.Transport).Clone()
+	connectionArgs := kncloudevents.ConnectionArgs{MaxIdleConns: sharedMaxIdleConns, MaxIdleConnsPerHost: sharedMaxIdleConnsPerHost}
+	connectionArgs.ConfigureTransport(transport)
+	return transport
+}
+
+// Utility Function For Creating A (Traced) HTTP MessageSender Sending Via The Specified Base RoundTripper
+func newSharedSender(base nethttp.RoundTripper) *kncloudevents.HTTPMessageSender {
+	return &kncloudevents.HTTPMessageSender{
+		Client: &nethttp.Client{
+			Transport: &ochttp.Transport{
+				Base:        base,
+				Propagation: tracecontextb3.TraceContextEgress,
+			},
+		},
+	}
+}
+
+// Utility Function For Getting The DeadLetterSink URI Of A SubscriberSpec (nil If None)
+func deadLetterSinkURI(subscriberSpec *eventingduck.SubscriberSpec) *apis.URL {
+	if subscriberSpec.Delivery == nil || subscriberSpec.Delivery.DeadLetterSink == nil {
+		return nil
+	}
+	return subscriberSpec.Delivery.DeadLetterSink.URI
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	eventingfake "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/network"
+)
+
+// Test The ValidateResolutionConfig() Functionality
+func TestValidateResolutionConfig(t *testing.T) {
+	assert.Nil(t, ValidateResolutionConfig(config.EKResolutionConfig{}))
+	assert.Nil(t, ValidateResolutionConfig(config.EKResolutionConfig{Enabled: true, IntervalSeconds: 10}))
+	assert.NotNil(t, ValidateResolutionConfig(config.EKResolutionConfig{IntervalSeconds: -1}))
+}
+
+// Test The NewDestinationResolver() Functionality
+func TestNewDestinationResolver(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Disabled Resolution Returns nil
+	assert.Nil(t, NewDestinationResolver(logger, config.EKResolutionConfig{}, "test-namespace/test-kc", nil, nil))
+
+	// Unset Values Are Defaulted
+	resolver := NewDestinationResolver(logger, config.EKResolutionConfig{Enabled: true}, "test-namespace/test-kc", nil, nil)
+	assert.Equal(t, DefaultResolutionInterval, resolver.interval)
+	assert.Equal(t, "test-namespace", resolver.namespace)
+	assert.NotNil(t, resolver.messageDispatcher(logger))
+
+	// Specified Values Are Used
+	resolver = NewDestinationResolver(logger, config.EKResolutionConfig{Enabled: true, IntervalSeconds: 5}, "test-namespace/test-kc", nil, nil)
+	assert.Equal(t, 5*time.Second, resolver.interval)
+}
+
+// Test The Nil DestinationResolver Functionality
+func TestNilDestinationResolver(t *testing.T) {
+	var resolver *DestinationResolver
+	subscriberSpec := &eventingduck.SubscriberSpec{
+		UID:           testSubscriberUID,
+		SubscriberURI: testSubscriberURI,
+		ReplyURI:      testReplyURI,
+		Delivery:      &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: testDeadLetterURI}},
+	}
+	resolver.SetSubscribers([]eventingduck.SubscriberSpec{*subscriberSpec})
+	resolver.Resolve(context.TODO())
+	resolver.Start(nil)
+	resolver.setHostChangedHandler(func(string) {})
+	subscriberURI, replyURI, deadLetterURI := resolver.Destinations(subscriberSpec)
+	assert.Equal(t, testSubscriberURI, subscriberURI)
+	assert.Equal(t, testReplyURI, replyURI)
+	assert.Equal(t, testDeadLetterURI, deadLetterURI)
+	assert.NotNil(t, resolver.messageDispatcher(logtesting.TestLogger(t).Desugar()))
+}
+
+// Test The DestinationResolver's Re-Resolution Of Subscription Refs
+func TestDestinationResolverSubscriptions(t *testing.T) {
+	ctx := context.TODO()
+
+	// Test Data - A Subscription Referencing A Kubernetes Service & An Addressable Whose Addresses Changed
+	staleURI, _ := apis.ParseURL("http://stale.test-namespace.svc.cluster.local/")
+	subscriberSpec := eventingduck.SubscriberSpec{
+		UID:           testSubscriberUID,
+		Generation:    1,
+		SubscriberURI: staleURI,
+		ReplyURI:      testReplyURI,
+		Delivery:      &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: testDeadLetterURI}},
+	}
+	subscription := &messagingv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-subscription", Namespace: "test-namespace", UID: testSubscriberUID},
+		Spec: messagingv1.SubscriptionSpec{
+			Subscriber: &duckv1.Destination{
+				Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Name: "migrated"},
+				URI: &apis.URL{Path: "/events"},
+			},
+			Reply: &duckv1.Destination{
+				Ref: &duckv1.KReference{APIVersion: "test.knative.dev/v1", Kind: "Sink", Name: "reply", Namespace: "other-namespace"},
+			},
+		},
+	}
+	sink := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "test.knative.dev/v1",
+		"kind":       "Sink",
+		"metadata":   map[string]interface{}{"name": "reply", "namespace": "other-namespace"},
+		"status":     map[string]interface{}{"address": map[string]interface{}{"url": "http://reply.other-namespace.svc.cluster.local"}},
+	}}
+
+	// Create A DestinationResolver With Fake Clients
+	resolver := NewDestinationResolver(logtesting.TestLogger(t).Desugar(), config.EKResolutionConfig{Enabled: true}, "test-namespace/test-kc",
+		eventingfake.NewSimpleClientset(subscription).MessagingV1(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), sink))
+	resolver.lookupHost = func(context.Context, string) ([]string, error) { return []string{"10.0.0.1"}, nil }
+	resolver.SetSubscribers([]eventingduck.SubscriberSpec{subscriberSpec})
+
+	// Verify The Changed Destinations Are Re-Resolved (Retaining The Unreferenced DeadLetterSink URI)
+	resolver.Resolve(ctx)
+	subscriberURI, replyURI, deadLetterURI := resolver.Destinations(&subscriberSpec)
+	assert.Equal(t, "http://"+network.GetServiceHostname("migrated", "test-namespace")+"/events", subscriberURI.String())
+	assert.Equal(t, "http://reply.other-namespace.svc.cluster.local", replyURI.String())
+	assert.Equal(t, testDeadLetterURI, deadLetterURI)
+
+	// Verify The Re-Resolved Destinations Are Discarded Once The SubscriberSpec Changes
+	updatedSpec := subscriberSpec
+	updatedSpec.Generation = 2
+	resolver.SetSubscribers([]eventingduck.SubscriberSpec{updatedSpec})
+	subscriberURI, replyURI, _ = resolver.Destinations(&updatedSpec)
+	assert.Equal(t, staleURI, subscriberURI)
+	assert.Equal(t, testReplyURI, replyURI)
+
+	// Verify Destinations Matching The SubscriberSpec Are Not Overridden
+	resolvedSpec := subscriberSpec
+	resolvedSpec.SubscriberURI, _ = apis.ParseURL("http://" + network.GetServiceHostname("migrated", "test-namespace") + "/events")
+	resolvedSpec.ReplyURI, _ = apis.ParseURL("http://reply.other-namespace.svc.cluster.local")
+	resolver.SetSubscribers([]eventingduck.SubscriberSpec{resolvedSpec})
+	resolver.Resolve(ctx)
+	assert.Empty(t, resolver.overrides)
+
+	// Verify Unresolvable Refs Retain The Previously Resolved Destinations
+	resolver.SetSubscribers([]eventingduck.SubscriberSpec{subscriberSpec})
+	resolver.Resolve(ctx)
+	assert.Len(t, resolver.overrides, 1)
+	resolver.dynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	resolver.Resolve(ctx)
+	_, replyURI, _ = resolver.Destinations(&subscriberSpec)
+	assert.Equal(t, "http://reply.other-namespace.svc.cluster.local", replyURI.String())
+}
+
+// Test The DestinationResolver's DNS Change Detection
+func TestDestinationResolverHosts(t *testing.T) {
+
+	// Create A DestinationResolver With A Controllable DNS Lookup
+	addresses := map[string][]string{"www.foo.bar": {"10.0.0.2", "10.0.0.1"}, "www.something.com": {"10.0.1.1"}}
+	var lookupErr error
+	resolver := NewDestinationResolver(logtesting.TestLogger(t).Desugar(), config.EKResolutionConfig{Enabled: true}, "test-namespace/test-kc", nil, nil)
+	resolver.lookupHost = func(_ context.Context, host string) ([]string, error) {
+		return addresses[host], lookupErr
+	}
+	var changedHosts []string
+	resolver.setHostChangedHandler(func(host string) { changedHosts = append(changedHosts, host) })
+	ipURI, _ := apis.ParseURL("http://10.1.2.3:8080")
+	resolver.SetSubscribers([]eventingduck.SubscriberSpec{
+		{UID: "uid-1", SubscriberURI: testSubscriberURI, ReplyURI: testReplyURI},
+		{UID: "uid-2", SubscriberURI: ipURI},
+	})
+
+	// The Initial Lookup Does Not Invalidate Any Connections (IP Addresses Are Not Looked Up)
+	resolver.Resolve(context.TODO())
+	assert.Empty(t, changedHosts)
+	assert.Equal(t, map[string][]string{"www.foo.bar": {"10.0.0.1", "10.0.0.2"}, "www.something.com": {"10.0.1.1"}}, resolver.addresses)
+
+	// Reordered Addresses Are Unchanged
+	addresses["www.foo.bar"] = []string{"10.0.0.1", "10.0.0.2"}
+	resolver.Resolve(context.TODO())
+	assert.Empty(t, changedHosts)
+
+	// Changed Addresses Invalidate The Connections Of The Host
+	addresses["www.foo.bar"] = []string{"10.0.0.3"}
+	resolver.Resolve(context.TODO())
+	assert.Equal(t, []string{"www.foo.bar"}, changedHosts)
+
+	// Failed Lookups Retain The Previous Addresses
+	lookupErr = errors.New("lookup failed")
+	resolver.Resolve(context.TODO())
+	assert.Equal(t, []string{"www.foo.bar"}, changedHosts)
+	assert.Equal(t, []string{"10.0.0.3"}, resolver.addresses["www.foo.bar"])
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// Subscriber Delivery Error Categories
+type ErrorCategory string
+
+const (
+	ErrorCategoryConnectionRefused ErrorCategory = "connectionRefused" // The Subscriber Refused The Connection
+	ErrorCategoryTimeout           ErrorCategory = "timeout"           // The Delivery Timed Out
+	ErrorCategoryClientError       ErrorCategory = "clientError"       // The Subscriber Responded With A 4XX StatusCode
+	ErrorCategoryServerError       ErrorCategory = "serverError"       // The Subscriber Responded With A 5XX StatusCode
+	ErrorCategoryOther             ErrorCategory = "other"             // Any Other Delivery Error (Always Uses The Subscriber's Delivery Spec)
+)
+
+// Classify The Specified Delivery Response / Error Into An ErrorCategory
+func classifyError(response *http.Response, err error) ErrorCategory {
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return ErrorCategoryConnectionRefused
+		} else if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return ErrorCategoryTimeout
+		}
+		return ErrorCategoryOther
+	}
+	if response != nil {
+		if response.StatusCode >= 400 && response.StatusCode <= 499 {
+			return ErrorCategoryClientError
+		} else if response.StatusCode >= 500 && response.StatusCode <= 599 {
+			return ErrorCategoryServerError
+		}
+	}
+	return ErrorCategoryOther
+}
+
+// The Retry Policy Of A Single ErrorCategory (A nil RetryMax / Backoff Defaults To The Subscriber's Delivery Spec)
+type RetryPolicy struct {
+	RetryMax *int
+	Backoff  func(retryNum int) time.Duration // The Delay Before The Category's Nth (1-Based) Retry
+}
+
+//
+// The Retry Policies Of The Dispatcher's Subscribers
+//
+// The policies refine the retries of subscribers whose delivery spec enables retries, allowing each category of
+// delivery error to be retried a distinct number of times with a distinct backoff.  Whether a delivery error is
+// retryable at all is determined by the Handler's checkRetry(), unless the response StatusCode is explicitly
+// configured as retryable or non-retryable (a permanent failure).  A nil *RetryPolicies is valid and simply
+// uses the subscriber's delivery spec for all errors.
+//
+type RetryPolicies struct {
+	Jitter                  bool
+	RetryableStatusCodes    map[int]bool
+	NonRetryableStatusCodes map[int]bool
+	Categories              map[ErrorCategory]*RetryPolicy
+	random                  func(n int64) int64 // Returns A Value In The Range [0,n)
+}
+
+// Create The RetryPolicies From The Specified Dispatcher Retry Config (nil If None)
+func NewRetryPolicies(retryConfig config.EKRetryConfig) (*RetryPolicies, error) {
+
+	// Map The Configured Policies By ErrorCategory
+	policyConfigs := map[ErrorCategory]*config.EKRetryPolicyConfig{
+		ErrorCategoryConnectionRefused: retryConfig.ConnectionRefused,
+		ErrorCategoryTimeout:           retryConfig.Timeout,
+		ErrorCategoryClientError:       retryConfig.ClientError,
+		ErrorCategoryServerError:       retryConfig.ServerError,
+	}
+
+	// Validate & Convert Each Configured Policy
+	categories := make(map[ErrorCategory]*RetryPolicy)
+	for category, policyConfig := range policyConfigs {
+		if policyConfig != nil {
+			policy, err := newRetryPolicy(policyConfig)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s retry policy: %w", category, err)
+			}
+			categories[category] = policy
+		}
+	}
+
+	// Validate & Convert The Retryable / Non-Retryable StatusCodes
+	retryableStatusCodes, err := newStatusCodeSet(retryConfig.RetryableStatusCodes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retryableStatusCodes: %w", err)
+	}
+	nonRetryableStatusCodes, err := newStatusCodeSet(retryConfig.NonRetryableStatusCodes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonRetryableStatusCodes: %w", err)
+	}
+	for statusCode := range retryableStatusCodes {
+		if nonRetryableStatusCodes[statusCode] {
+			return nil, fmt.Errorf("status code %d is both retryable and non-retryable", statusCode)
+		}
+	}
+
+	// No RetryPolicies If Nothing Is Configured
+	if !retryConfig.Jitter && len(categories) == 0 && len(retryableStatusCodes) == 0 && len(nonRetryableStatusCodes) == 0 {
+		return nil, nil
+	}
+
+	// Return The RetryPolicies
+	return &RetryPolicies{
+		Jitter:                  retryConfig.Jitter,
+		RetryableStatusCodes:    retryableStatusCodes,
+		NonRetryableStatusCodes: nonRetryableStatusCodes,
+		Categories:              categories,
+		random:                  rand.Int63n,
+	}, nil
+}
+
+// Create A Set Of The Specified (Failure) StatusCodes
+func newStatusCodeSet(statusCodes []int) (map[int]bool, error) {
+	statusCodeSet := make(map[int]bool, len(statusCodes))
+	for _, statusCode := range statusCodes {
+		if statusCode < 300 || statusCode > 599 {
+			return nil, fmt.Errorf("status code %d is not a failure status code (300-599)", statusCode)
+		}
+		statusCodeSet[statusCode] = true
+	}
+	return statusCodeSet, nil
+}
+
+// Create A Single RetryPolicy From The Specified Config
+func newRetryPolicy(policyConfig *config.EKRetryPolicyConfig) (*RetryPolicy, error) {
+
+	// Validate The Config
+	if policyConfig.MaxRetries != nil && *policyConfig.MaxRetries < 0 {
+		return nil, fmt.Errorf("maxRetries %d must not be negative", *policyConfig.MaxRetries)
+	}
+	if policyConfig.BackoffDelayMillis < 0 || policyConfig.BackoffMaxMillis < 0 {
+		return nil, fmt.Errorf("backoffDelayMillis %d and backoffMaxMillis %d must not be negative", policyConfig.BackoffDelayMillis, policyConfig.BackoffMaxMillis)
+	}
+
+	// Determine The Backoff Function (Unless Defaulting To The Subscriber's Delivery Spec)
+	var backoff func(retryNum int) time.Duration
+	delay := time.Duration(policyConfig.BackoffDelayMillis) * time.Millisecond
+	switch eventingduck.BackoffPolicyType(policyConfig.BackoffPolicy) {
+	case "":
+		if delay > 0 {
+			return nil, fmt.Errorf("backoffDelayMillis requires a backoffPolicy")
+		}
+	case eventingduck.BackoffPolicyExponential:
+		backoff = func(retryNum int) time.Duration {
+			return delay * time.Duration(math.Exp2(float64(retryNum-1)))
+		}
+	case eventingduck.BackoffPolicyLinear:
+		backoff = func(retryNum int) time.Duration {
+			return delay * time.Duration(retryNum)
+		}
+	default:
+		return nil, fmt.Errorf("unknown backoffPolicy %q", policyConfig.BackoffPolicy)
+	}
+
+	// Cap The Backoff Delay If Configured
+	if backoff != nil && policyConfig.BackoffMaxMillis > 0 {
+		maxDelay := time.Duration(policyConfig.BackoffMaxMillis) * time.Millisecond
+		uncappedBackoff := backoff
+		backoff = func(retryNum int) time.Duration {
+			if delay := uncappedBackoff(retryNum); delay > 0 && delay < maxDelay {
+				return delay
+			}
+			return maxDelay // Includes Overflow Of Large Exponential Delays
+		}
+	}
+
+	// Return The RetryPolicy
+	return &RetryPolicy{RetryMax: policyConfig.MaxRetries, Backoff: backoff}, nil
+}
+
+// Return The RetryPolicy Of The Specified ErrorCategory (nil If None)
+func (p *RetryPolicies) policy(category ErrorCategory) *RetryPolicy {
+	if p == nil {
+		return nil
+	}
+	return p.Categories[category]
+}
+
+//
+// Create The RetryConfig For A Single Message Delivery From The Subscriber's RetryConfig
+//
+// The returned RetryConfig tracks the retries of each ErrorCategory, and so must not be shared across
+// messages.  Subscribers whose delivery spec does not enable retries are never retried.
+//
+func (p *RetryPolicies) messageRetryConfig(retryConfig *kncloudevents.RetryConfig) kncloudevents.RetryConfig {
+
+	// Use The Subscriber's RetryConfig As Is Unless Policies Apply
+	messageRetryConfig := *retryConfig
+	if p == nil || retryConfig.RetryMax <= 0 || retryConfig.CheckRetry == nil || retryConfig.Backoff == nil {
+		return messageRetryConfig
+	}
+
+	// The Overall RetryMax Must Allow For The Largest Category RetryMax
+	for _, policy := range p.Categories {
+		if policy.RetryMax != nil && *policy.RetryMax > messageRetryConfig.RetryMax {
+			messageRetryConfig.RetryMax = *policy.RetryMax
+		}
+	}
+
+	// Track The Retries Of Each ErrorCategory & The Category Of The Latest Error (CheckRetry Precedes Backoff)
+	retries := 0
+	categoryRetries := make(map[ErrorCategory]int)
+	var lastCategory ErrorCategory
+
+	// Limit The Retries Of Each ErrorCategory To Its Policy's RetryMax (Otherwise The Subscriber's)
+	messageRetryConfig.CheckRetry = func(ctx context.Context, response *http.Response, err error) (bool, error) {
+		retry, checkRetryErr := retryConfig.CheckRetry(ctx, response, err)
+		if err == nil && response != nil {
+			if p.NonRetryableStatusCodes[response.StatusCode] {
+				return false, checkRetryErr // Permanent Failure
+			} else if p.RetryableStatusCodes[response.StatusCode] {
+				retry = true
+			}
+		}
+		if !retry {
+			return retry, checkRetryErr
+		}
+		lastCategory = classifyError(response, err)
+		if policy := p.policy(lastCategory); policy != nil && policy.RetryMax != nil {
+			retry = categoryRetries[lastCategory] < *policy.RetryMax
+		} else {
+			retry = retries < retryConfig.RetryMax
+		}
+		if retry {
+			retries++
+			categoryRetries[lastCategory]++
+		}
+		return retry, checkRetryErr
+	}
+
+	// Backoff Per The Latest ErrorCategory's Policy (Otherwise The Subscriber's) With Optional Full Jitter
+	messageRetryConfig.Backoff = func(attemptNum int, response *http.Response) time.Duration {
+		var delay time.Duration
+		if policy := p.policy(lastCategory); policy != nil && policy.Backoff != nil {
+			delay = policy.Backoff(categoryRetries[lastCategory])
+		} else {
+			delay = retryConfig.Backoff(attemptNum, response)
+		}
+		if p.Jitter && delay > 0 {
+			delay = time.Duration(p.random(int64(delay)))
+		}
+		return delay
+	}
+
+	// Return The Message's RetryConfig
+	return messageRetryConfig
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// Test The classifyError() Functionality
+func TestClassifyError(t *testing.T) {
+	connectionRefused := &url.Error{Op: "Post", URL: "http://subscriber", Err: &net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}}}
+	timeout := &url.Error{Op: "Post", URL: "http://subscriber", Err: &net.DNSError{IsTimeout: true}}
+	assert.Equal(t, ErrorCategoryConnectionRefused, classifyError(nil, connectionRefused))
+	assert.Equal(t, ErrorCategoryTimeout, classifyError(nil, timeout))
+	assert.Equal(t, ErrorCategoryTimeout, classifyError(nil, context.DeadlineExceeded))
+	assert.Equal(t, ErrorCategoryOther, classifyError(nil, errors.New("test error")))
+	assert.Equal(t, ErrorCategoryClientError, classifyError(&http.Response{StatusCode: http.StatusTooManyRequests}, nil))
+	assert.Equal(t, ErrorCategoryServerError, classifyError(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	assert.Equal(t, ErrorCategoryOther, classifyError(&http.Response{StatusCode: http.StatusMovedPermanently}, nil))
+	assert.Equal(t, ErrorCategoryOther, classifyError(nil, nil))
+}
+
+// Test The NewRetryPolicies() Functionality
+func TestNewRetryPolicies(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		retryConfig config.EKRetryConfig
+		wantNil     bool
+		wantError   string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:    "Not Configured",
+			wantNil: true,
+		},
+		{
+			name:        "Jitter Only",
+			retryConfig: config.EKRetryConfig{Jitter: true},
+		},
+		{
+			name: "Valid Policies",
+			retryConfig: config.EKRetryConfig{
+				ConnectionRefused: &config.EKRetryPolicyConfig{MaxRetries: intPtr(10), BackoffPolicy: "exponential", BackoffDelayMillis: 100, BackoffMaxMillis: 5000},
+				ClientError:       &config.EKRetryPolicyConfig{MaxRetries: intPtr(0)},
+				ServerError:       &config.EKRetryPolicyConfig{BackoffPolicy: "linear", BackoffDelayMillis: 500},
+			},
+		},
+		{
+			name:        "StatusCodes Only",
+			retryConfig: config.EKRetryConfig{RetryableStatusCodes: []int{401}, NonRetryableStatusCodes: []int{400, 409}},
+		},
+		{
+			name:        "Invalid Retryable StatusCode",
+			retryConfig: config.EKRetryConfig{RetryableStatusCodes: []int{200}},
+			wantError:   "invalid retryableStatusCodes: status code 200 is not a failure status code (300-599)",
+		},
+		{
+			name:        "Invalid NonRetryable StatusCode",
+			retryConfig: config.EKRetryConfig{NonRetryableStatusCodes: []int{600}},
+			wantError:   "invalid nonRetryableStatusCodes: status code 600 is not a failure status code (300-599)",
+		},
+		{
+			name:        "Conflicting StatusCodes",
+			retryConfig: config.EKRetryConfig{RetryableStatusCodes: []int{409}, NonRetryableStatusCodes: []int{400, 409}},
+			wantError:   "status code 409 is both retryable and non-retryable",
+		},
+		{
+			name:        "Negative MaxRetries",
+			retryConfig: config.EKRetryConfig{Timeout: &config.EKRetryPolicyConfig{MaxRetries: intPtr(-1)}},
+			wantError:   "invalid timeout retry policy: maxRetries -1 must not be negative",
+		},
+		{
+			name:        "Negative Backoff",
+			retryConfig: config.EKRetryConfig{Timeout: &config.EKRetryPolicyConfig{BackoffPolicy: "linear", BackoffDelayMillis: -1}},
+			wantError:   "invalid timeout retry policy: backoffDelayMillis -1 and backoffMaxMillis 0 must not be negative",
+		},
+		{
+			name:        "Missing BackoffPolicy",
+			retryConfig: config.EKRetryConfig{ServerError: &config.EKRetryPolicyConfig{BackoffDelayMillis: 100}},
+			wantError:   "invalid serverError retry policy: backoffDelayMillis requires a backoffPolicy",
+		},
+		{
+			name:        "Unknown BackoffPolicy",
+			retryConfig: config.EKRetryConfig{ServerError: &config.EKRetryPolicyConfig{BackoffPolicy: "random"}},
+			wantError:   `invalid serverError retry policy: unknown backoffPolicy "random"`,
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			retryPolicies, err := NewRetryPolicies(testCase.retryConfig)
+			if testCase.wantError != "" {
+				assert.EqualError(t, err, testCase.wantError)
+				assert.Nil(t, retryPolicies)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.wantNil, retryPolicies == nil)
+			}
+		})
+	}
+}
+
+// Test The Backoff Of A Single RetryPolicy
+func TestRetryPolicyBackoff(t *testing.T) {
+	exponential, err := newRetryPolicy(&config.EKRetryPolicyConfig{BackoffPolicy: "exponential", BackoffDelayMillis: 100, BackoffMaxMillis: 500})
+	assert.Nil(t, err)
+	assert.Equal(t, 100*time.Millisecond, exponential.Backoff(1))
+	assert.Equal(t, 400*time.Millisecond, exponential.Backoff(3))
+	assert.Equal(t, 500*time.Millisecond, exponential.Backoff(4))
+	assert.Equal(t, 500*time.Millisecond, exponential.Backoff(100)) // Overflow
+
+	linear, err := newRetryPolicy(&config.EKRetryPolicyConfig{BackoffPolicy: "linear", BackoffDelayMillis: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, 300*time.Millisecond, linear.Backoff(3))
+
+	retriesOnly, err := newRetryPolicy(&config.EKRetryPolicyConfig{MaxRetries: intPtr(3)})
+	assert.Nil(t, err)
+	assert.Nil(t, retriesOnly.Backoff)
+	assert.Equal(t, 3, *retriesOnly.RetryMax)
+}
+
+// Test The messageRetryConfig() Functionality
+func TestMessageRetryConfig(t *testing.T) {
+
+	// The Subscriber's RetryConfig (Always Retry, Linear 1s Backoff)
+	subscriberRetryConfig := kncloudevents.RetryConfig{
+		RetryMax:   2,
+		CheckRetry: func(_ context.Context, _ *http.Response, _ error) (bool, error) { return true, nil },
+		Backoff:    func(attemptNum int, _ *http.Response) time.Duration { return time.Duration(attemptNum+1) * time.Second },
+	}
+
+	// Verify A nil RetryPolicies Uses The Subscriber's RetryConfig As Is
+	var nilRetryPolicies *RetryPolicies
+	retryConfig := nilRetryPolicies.messageRetryConfig(&subscriberRetryConfig)
+	assert.Equal(t, 2, retryConfig.RetryMax)
+
+	// Create RetryPolicies Retrying Refused Connections More Often & Never Retrying 4XX Responses
+	retryPolicies, err := NewRetryPolicies(config.EKRetryConfig{
+		ConnectionRefused: &config.EKRetryPolicyConfig{MaxRetries: intPtr(4), BackoffPolicy: "linear", BackoffDelayMillis: 10},
+		ClientError:       &config.EKRetryPolicyConfig{MaxRetries: intPtr(0)},
+	})
+	assert.Nil(t, err)
+	connectionRefused := &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}
+	serverError := &http.Response{StatusCode: http.StatusInternalServerError}
+	clientError := &http.Response{StatusCode: http.StatusBadRequest}
+
+	// Verify The Overall RetryMax Allows For The Largest Category RetryMax
+	retryConfig = retryPolicies.messageRetryConfig(&subscriberRetryConfig)
+	assert.Equal(t, 4, retryConfig.RetryMax)
+
+	// Verify Refused Connections Are Retried Per Their Policy
+	for i := 1; i <= 4; i++ {
+		assert.True(t, checkRetry(t, retryConfig, nil, connectionRefused))
+		assert.Equal(t, time.Duration(i)*10*time.Millisecond, retryConfig.Backoff(i-1, nil))
+	}
+	assert.False(t, checkRetry(t, retryConfig, nil, connectionRefused))
+
+	// Verify 4XX Responses Are Never Retried
+	retryConfig = retryPolicies.messageRetryConfig(&subscriberRetryConfig)
+	assert.False(t, checkRetry(t, retryConfig, clientError, nil))
+
+	// Verify Uncategorized Errors Are Retried Per The Subscriber's RetryConfig (Including Prior Category Retries)
+	retryConfig = retryPolicies.messageRetryConfig(&subscriberRetryConfig)
+	assert.True(t, checkRetry(t, retryConfig, nil, connectionRefused))
+	assert.True(t, checkRetry(t, retryConfig, serverError, nil))
+	assert.Equal(t, 2*time.Second, retryConfig.Backoff(1, serverError))
+	assert.False(t, checkRetry(t, retryConfig, serverError, nil))
+
+	// Verify Errors Which The Subscriber's CheckRetry Does Not Retry Are Never Retried
+	noCheckRetryConfig := subscriberRetryConfig
+	noCheckRetryConfig.CheckRetry = func(_ context.Context, _ *http.Response, _ error) (bool, error) { return false, nil }
+	retryConfig = retryPolicies.messageRetryConfig(&noCheckRetryConfig)
+	assert.False(t, checkRetry(t, retryConfig, nil, connectionRefused))
+
+	// Verify Subscribers Without Retries Are Never Retried
+	noRetryConfig := subscriberRetryConfig
+	noRetryConfig.RetryMax = 0
+	retryConfig = retryPolicies.messageRetryConfig(&noRetryConfig)
+	assert.Equal(t, 0, retryConfig.RetryMax)
+}
+
+// Test The Retryable / Non-Retryable StatusCodes Of The messageRetryConfig()
+func TestMessageRetryConfigStatusCodes(t *testing.T) {
+
+	// The Subscriber's RetryConfig (Retry 5XX Responses & Errors)
+	subscriberRetryConfig := kncloudevents.RetryConfig{
+		RetryMax: 1,
+		CheckRetry: func(_ context.Context, response *http.Response, err error) (bool, error) {
+			return err != nil || response.StatusCode >= 500, nil
+		},
+		Backoff: func(_ int, _ *http.Response) time.Duration { return 0 },
+	}
+	retryPolicies, err := NewRetryPolicies(config.EKRetryConfig{RetryableStatusCodes: []int{401}, NonRetryableStatusCodes: []int{501}})
+	assert.Nil(t, err)
+
+	// Verify The Configured StatusCodes Override The Subscriber's CheckRetry
+	assert.True(t, checkRetry(t, retryPolicies.messageRetryConfig(&subscriberRetryConfig), &http.Response{StatusCode: http.StatusUnauthorized}, nil))
+	assert.False(t, checkRetry(t, retryPolicies.messageRetryConfig(&subscriberRetryConfig), &http.Response{StatusCode: http.StatusNotImplemented}, nil))
+
+	// Verify Other StatusCodes & Errors Use The Subscriber's CheckRetry
+	assert.True(t, checkRetry(t, retryPolicies.messageRetryConfig(&subscriberRetryConfig), &http.Response{StatusCode: http.StatusInternalServerError}, nil))
+	assert.False(t, checkRetry(t, retryPolicies.messageRetryConfig(&subscriberRetryConfig), &http.Response{StatusCode: http.StatusForbidden}, nil))
+	assert.True(t, checkRetry(t, retryPolicies.messageRetryConfig(&subscriberRetryConfig), nil, errors.New("test error")))
+
+	// Verify Retryable StatusCodes Are Still Limited By The RetryMax
+	retryConfig := retryPolicies.messageRetryConfig(&subscriberRetryConfig)
+	assert.True(t, checkRetry(t, retryConfig, &http.Response{StatusCode: http.StatusUnauthorized}, nil))
+	assert.False(t, checkRetry(t, retryConfig, &http.Response{StatusCode: http.StatusUnauthorized}, nil))
+}
+
+// Test The Full Jitter Of The messageRetryConfig() Backoff
+func TestMessageRetryConfigJitter(t *testing.T) {
+	subscriberRetryConfig := kncloudevents.RetryConfig{
+		RetryMax:   1,
+		CheckRetry: func(_ context.Context, _ *http.Response, _ error) (bool, error) { return true, nil },
+		Backoff:    func(_ int, _ *http.Response) time.Duration { return time.Second },
+	}
+	retryPolicies, err := NewRetryPolicies(config.EKRetryConfig{Jitter: true})
+	assert.Nil(t, err)
+	retryPolicies.random = func(n int64) int64 { return n / 4 }
+
+	retryConfig := retryPolicies.messageRetryConfig(&subscriberRetryConfig)
+	assert.True(t, checkRetry(t, retryConfig, nil, errors.New("test error")))
+	assert.Equal(t, 250*time.Millisecond, retryConfig.Backoff(0, nil))
+
+	// Verify The Jitter Is Within [0, Backoff)
+	retryPolicies, err = NewRetryPolicies(config.EKRetryConfig{Jitter: true})
+	assert.Nil(t, err)
+	retryConfig = retryPolicies.messageRetryConfig(&subscriberRetryConfig)
+	for i := 0; i < 100; i++ {
+		delay := retryConfig.Backoff(0, nil)
+		assert.True(t, delay >= 0 && delay < time.Second)
+	}
+}
+
+// Utility Function For Invoking The CheckRetry Of A RetryConfig
+func checkRetry(t *testing.T, retryConfig kncloudevents.RetryConfig, response *http.Response, err error) bool {
+	retry, checkRetryErr := retryConfig.CheckRetry(context.TODO(), response, err)
+	assert.Nil(t, checkRetryErr)
+	return retry
+}
+
+// Utility Function For Getting A Pointer To An int
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// The Default Replacement Of Redacted Fields
+const DefaultRedactedValue = "REDACTED"
+
+// The Wildcard Path Segment Matching Every Field Of An Object Or Element Of An Array
+const pathWildcard = "*"
+
+// The EventTransforms Of A KafkaChannel's Subscribers Keyed By Subscriber UID
+type SubscriberTransforms map[string]*EventTransform
+
+// Create The SubscriberTransforms From The Specified KafkaChannel Annotations (nil If None)
+func NewSubscriberTransforms(annotations map[string]string) (SubscriberTransforms, error) {
+
+	// No Transforms If The Annotation Is Not Specified
+	transformsJson := annotations[constants.SubscriberTransformsAnnotation]
+	if len(transformsJson) == 0 {
+		return nil, nil
+	}
+
+	// Parse The Annotation's JSON Map Of Subscriber UID To EventTransform
+	var subscriberTransforms SubscriberTransforms
+	err := json.Unmarshal([]byte(transformsJson), &subscriberTransforms)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", constants.SubscriberTransformsAnnotation, err)
+	}
+
+	// Validate Each Subscriber's EventTransform
+	for subscriberUID, eventTransform := range subscriberTransforms {
+		if err = eventTransform.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation for subscriber %s: %w", constants.SubscriberTransformsAnnotation, subscriberUID, err)
+		}
+	}
+
+	// Return The SubscriberTransforms
+	return subscriberTransforms, nil
+}
+
+// Get The EventTransform Of The Specified Subscriber (nil If Untransformed)
+func (t SubscriberTransforms) Transform(subscriberUID string) *EventTransform {
+	return t[subscriberUID]
+}
+
+//
+// Field-Level Transformation Of The Events Dispatched To A Single Subscriber
+//
+// Supports data minimization by removing, redacting (replacing with the Replacement) or hashing (replacing with the
+// hex SHA-256 of the value, so that events can still be correlated) the fields of the JSON event data at the specified
+// paths, and removing the specified CloudEvent extensions, before an event is delivered to the subscriber.  Paths are
+// dot separated field names or array indices, with "*" matching every field or element.  Events whose data is not
+// JSON can't be transformed and are therefore never delivered to a subscriber with data paths.  Only the delivered
+// event is transformed - the record in Kafka, and any copy quarantined for redelivery, are unchanged.
+//
+// A nil *EventTransform is valid and leaves every event unchanged.
+//
+type EventTransform struct {
+	Remove           []string `json:"remove,omitempty"`
+	Redact           []string `json:"redact,omitempty"`
+	Hash             []string `json:"hash,omitempty"`
+	Replacement      string   `json:"replacement,omitempty"`
+	RemoveExtensions []string `json:"removeExtensions,omitempty"`
+}
+
+// Validate The EventTransform's Paths & Extensions
+func (t *EventTransform) Validate() error {
+	if t == nil {
+		return nil
+	}
+	for _, paths := range [][]string{t.Remove, t.Redact, t.Hash} {
+		for _, path := range paths {
+			for _, segment := range strings.Split(path, ".") {
+				if len(segment) == 0 {
+					return fmt.Errorf("invalid data path %q", path)
+				}
+			}
+		}
+	}
+	for _, extension := range t.RemoveExtensions {
+		if !event.IsAlphaNumeric(extension) || isContextAttribute(extension) {
+			return fmt.Errorf("invalid extension %q", extension)
+		}
+	}
+	return nil
+}
+
+// Apply The EventTransform To The Specified Message (Returning An Error If Its Data Can't Be Transformed)
+func (t *EventTransform) Apply(ctx context.Context, message binding.Message) (binding.Message, error) {
+	if t == nil {
+		return message, nil
+	}
+	transformedEvent, err := toEvent(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	for _, extension := range t.RemoveExtensions {
+		transformedEvent.SetExtension(extension, nil)
+	}
+	if err = t.transformData(transformedEvent); err != nil {
+		return nil, err
+	}
+	return binding.ToMessage(transformedEvent), nil
+}
+
+// Transform The Fields Of The Event's JSON Data
+func (t *EventTransform) transformData(transformedEvent *event.Event) error {
+
+	// Nothing To Transform Without Data Paths Or Data
+	if len(t.Remove)+len(t.Redact)+len(t.Hash) == 0 || len(transformedEvent.Data()) == 0 {
+		return nil
+	}
+
+	// Only JSON Data Can Be Transformed
+	if !isJSONMediaType(transformedEvent.DataMediaType()) {
+		return fmt.Errorf("data of content type %s can't be transformed", transformedEvent.DataContentType())
+	}
+	var data interface{}
+	if err := json.Unmarshal(transformedEvent.Data(), &data); err != nil {
+		return fmt.Errorf("data can't be transformed: %w", err)
+	}
+
+	// Hash, Redact & Then Remove The Fields At Each Path
+	replacement := t.Replacement
+	if len(replacement) == 0 {
+		replacement = DefaultRedactedValue
+	}
+	for _, path := range t.Hash {
+		data = transformPath(data, strings.Split(path, "."), hashValue)
+	}
+	for _, path := range t.Redact {
+		data = transformPath(data, strings.Split(path, "."), func(interface{}) (interface{}, bool) { return replacement, true })
+	}
+	for _, path := range t.Remove {
+		data = transformPath(data, strings.Split(path, "."), func(interface{}) (interface{}, bool) { return nil, false })
+	}
+
+	// Replace The Event's Data (Preserving Its Content Type)
+	transformedData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	transformedEvent.DataEncoded = transformedData
+	return nil
+}
+
+// The Operation Applied To The Value Of A Field, Returning Its Replacement Or false If The Field Is Removed
+type fieldOperation func(value interface{}) (interface{}, bool)
+
+// Apply The Operation To The Fields At The Path Below The Specified JSON Value (Returning The Updated Value)
+func transformPath(value interface{}, segments []string, operation fieldOperation) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if segments[0] != pathWildcard && segments[0] != key {
+				continue
+			}
+			if len(segments) > 1 {
+				typed[key] = transformPath(child, segments[1:], operation)
+			} else if replacement, keep := operation(child); keep {
+				typed[key] = replacement
+			} else {
+				delete(typed, key)
+			}
+		}
+		return typed
+	case []interface{}:
+		transformed := make([]interface{}, 0, len(typed))
+		for index, child := range typed {
+			if segments[0] != pathWildcard && segments[0] != strconv.Itoa(index) {
+				transformed = append(transformed, child)
+			} else if len(segments) > 1 {
+				transformed = append(transformed, transformPath(child, segments[1:], operation))
+			} else if replacement, keep := operation(child); keep {
+				transformed = append(transformed, replacement)
+			}
+		}
+		return transformed
+	}
+	return value
+}
+
+// Utility Function For Replacing A Value With The Hex SHA-256 Of Its String (Or Otherwise JSON) Form
+func hashValue(value interface{}) (interface{}, bool) {
+	var content []byte
+	if stringValue, ok := value.(string); ok {
+		content = []byte(stringValue)
+	} else {
+		content, _ = json.Marshal(value)
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), true
+}
+
+// Utility Function For Determining Whether A Media Type Is JSON (A Missing Media Type Defaults To JSON)
+func isJSONMediaType(mediaType string) bool {
+	return len(mediaType) == 0 || mediaType == event.ApplicationJSON || mediaType == event.TextJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// Utility Function For Determining Whether The Name Is Of A CloudEvent Context Attribute (Rather Than An Extension)
+func isContextAttribute(name string) bool {
+	switch name {
+	case "specversion", "id", "type", "source", "subject", "datacontenttype", "dataschema", "time":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Test The NewSubscriberTransforms() Functionality
+func TestNewSubscriberTransforms(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name           string
+		annotations    map[string]string
+		wantTransforms SubscriberTransforms
+		wantError      string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name: "No Annotation",
+		},
+		{
+			name:        "Valid Transforms",
+			annotations: map[string]string{kafkaconstants.SubscriberTransformsAnnotation: `{"uid-1":{"remove":["card"],"redact":["customer.*.email"],"replacement":"***"},"uid-2":{"hash":["items.0.sku"],"removeExtensions":["tenant"]}}`},
+			wantTransforms: SubscriberTransforms{
+				"uid-1": {Remove: []string{"card"}, Redact: []string{"customer.*.email"}, Replacement: "***"},
+				"uid-2": {Hash: []string{"items.0.sku"}, RemoveExtensions: []string{"tenant"}},
+			},
+		},
+		{
+			name:        "Invalid JSON",
+			annotations: map[string]string{kafkaconstants.SubscriberTransformsAnnotation: "invalid"},
+			wantError:   "invalid kafka.eventing.knative.dev/subscriber-transforms annotation: invalid character 'i' looking for beginning of value",
+		},
+		{
+			name:        "Invalid Path",
+			annotations: map[string]string{kafkaconstants.SubscriberTransformsAnnotation: `{"uid-1":{"redact":["customer..email"]}}`},
+			wantError:   `invalid kafka.eventing.knative.dev/subscriber-transforms annotation for subscriber uid-1: invalid data path "customer..email"`,
+		},
+		{
+			name:        "Invalid Extension",
+			annotations: map[string]string{kafkaconstants.SubscriberTransformsAnnotation: `{"uid-1":{"removeExtensions":["type"]}}`},
+			wantError:   `invalid kafka.eventing.knative.dev/subscriber-transforms annotation for subscriber uid-1: invalid extension "type"`,
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			transforms, err := NewSubscriberTransforms(testCase.annotations)
+			assert.Equal(t, testCase.wantTransforms, transforms)
+			if len(testCase.wantError) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, testCase.wantError, err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// Test The SubscriberTransforms' Transform() Functionality
+func TestSubscriberTransformsTransform(t *testing.T) {
+	var nilTransforms SubscriberTransforms
+	assert.Nil(t, nilTransforms.Transform("uid-1"))
+	transforms := SubscriberTransforms{"uid-1": {Redact: []string{"email"}}}
+	assert.Equal(t, &EventTransform{Redact: []string{"email"}}, transforms.Transform("uid-1"))
+	assert.Nil(t, transforms.Transform("uid-2"))
+}
+
+// Test The EventTransform's Apply() Functionality
+func TestEventTransformApply(t *testing.T) {
+
+	// Test Data
+	data := `{"customer":{"name":"Jane","email":"jane@example.com","phone":"555-0100"},"items":[{"sku":"sku-1","price":10},{"sku":"sku-2","price":20}],"card":"4111111111111111"}`
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name           string
+		transform      *EventTransform
+		contentType    string
+		data           string
+		wantData       string
+		wantExtensions map[string]interface{}
+		wantError      bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:           "Nil Transform",
+			data:           data,
+			wantData:       data,
+			wantExtensions: map[string]interface{}{"tenant": "acme"},
+		},
+		{
+			name:           "Remove",
+			transform:      &EventTransform{Remove: []string{"card", "customer.phone", "items.*.price", "missing.field"}},
+			data:           data,
+			wantData:       `{"customer":{"name":"Jane","email":"jane@example.com"},"items":[{"sku":"sku-1"},{"sku":"sku-2"}]}`,
+			wantExtensions: map[string]interface{}{"tenant": "acme"},
+		},
+		{
+			name:           "Remove Array Element",
+			transform:      &EventTransform{Remove: []string{"items.0"}},
+			data:           data,
+			wantData:       `{"customer":{"name":"Jane","email":"jane@example.com","phone":"555-0100"},"items":[{"sku":"sku-2","price":20}],"card":"4111111111111111"}`,
+			wantExtensions: map[string]interface{}{"tenant": "acme"},
+		},
+		{
+			name:           "Redact",
+			transform:      &EventTransform{Redact: []string{"customer.*", "card"}},
+			data:           data,
+			wantData:       `{"customer":{"name":"REDACTED","email":"REDACTED","phone":"REDACTED"},"items":[{"sku":"sku-1","price":10},{"sku":"sku-2","price":20}],"card":"REDACTED"}`,
+			wantExtensions: map[string]interface{}{"tenant": "acme"},
+		},
+		{
+			name:           "Redact With Replacement",
+			transform:      &EventTransform{Redact: []string{"card"}, Replacement: "****"},
+			contentType:    "application/cloudevents+json",
+			data:           `{"card":"4111111111111111"}`,
+			wantData:       `{"card":"****"}`,
+			wantExtensions: map[string]interface{}{"tenant": "acme"},
+		},
+		{
+			name:           "Hash",
+			transform:      &EventTransform{Hash: []string{"customer.email", "items.1.price"}},
+			data:           `{"customer":{"email":"jane@example.com"},"items":[{"price":10},{"price":20}]}`,
+			wantData:       `{"customer":{"email":"8c87b489ce35cf2e2f39f80e282cb2e804932a56a213983eeeb428407d43b52d"},"items":[{"price":10},{"price":"f5ca38f748a1d6eaf726b8a42fb575c3c71f1864a8143301782de13da2d9202b"}]}`,
+			wantExtensions: map[string]interface{}{"tenant": "acme"},
+		},
+		{
+			name:      "Remove Extensions",
+			transform: &EventTransform{RemoveExtensions: []string{"tenant", "missing"}},
+			data:      data,
+			wantData:  data,
+		},
+		{
+			name:           "Non-JSON Data Without Paths",
+			transform:      &EventTransform{RemoveExtensions: []string{"missing"}},
+			contentType:    "text/plain",
+			data:           "plain text",
+			wantData:       "plain text",
+			wantExtensions: map[string]interface{}{"tenant": "acme"},
+		},
+		{
+			name:        "Non-JSON Data",
+			transform:   &EventTransform{Redact: []string{"card"}},
+			contentType: "text/plain",
+			data:        "plain text",
+			wantError:   true,
+		},
+		{
+			name:      "Invalid JSON Data",
+			transform: &EventTransform{Redact: []string{"card"}},
+			data:      "{invalid",
+			wantError: true,
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Test Event
+			testEvent := cloudevents.New()
+			testEvent.SetID("id")
+			testEvent.SetType("type")
+			testEvent.SetSource("source")
+			testEvent.SetExtension("tenant", "acme")
+			contentType := testCase.contentType
+			if len(contentType) == 0 {
+				contentType = cloudevents.ApplicationJSON
+			}
+			require.Nil(t, testEvent.SetData(contentType, []byte(testCase.data)))
+
+			// Perform The Test
+			message, err := testCase.transform.Apply(context.TODO(), binding.ToMessage(&testEvent))
+			if testCase.wantError {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			transformedEvent, err := binding.ToEvent(context.TODO(), message)
+			require.Nil(t, err)
+
+			// Verify The Results
+			if testCase.contentType == "text/plain" {
+				assert.Equal(t, testCase.wantData, string(transformedEvent.Data()))
+			} else {
+				assert.JSONEq(t, testCase.wantData, string(transformedEvent.Data()))
+			}
+			assert.Equal(t, contentType, transformedEvent.DataContentType())
+			assert.Equal(t, testCase.wantExtensions, transformedEvent.Extensions())
+			assert.Equal(t, "id", transformedEvent.ID())
+		})
+	}
+}
+
+// Test The hashValue() Functionality
+func TestHashValue(t *testing.T) {
+	hash, keep := hashValue("jane@example.com")
+	assert.True(t, keep)
+	assert.Len(t, hash, 64)
+	otherHash, _ := hashValue("jane@example.com")
+	assert.Equal(t, hash, otherHash)
+	numberHash, _ := hashValue(float64(20))
+	stringHash, _ := hashValue("20")
+	assert.Equal(t, stringHash, numberHash)
+}
@@ -44,6 +44,14 @@ type Environment struct {
 	// Kafka Authorization
 	KafkaUsername string // Optional
 	KafkaPassword string // Optional
+
+	// Remote Kafka Cluster Of The KafkaChannel's Replication
+	ReplicationKafkaBrokers  string // Optional
+	ReplicationKafkaUsername string // Optional
+	ReplicationKafkaPassword string // Optional
+
+	// Namespace Overrides Of The Dispatcher Configuration
+	DispatcherConfigOverrides string // Optional
 }
 
 // Get The Environment
@@ -115,11 +123,22 @@ func GetEnvironment(logger *zap.Logger) (*Environment, error) {
 	// Get The Optional KafkaPassword Config Value
 	environment.KafkaPassword = env.GetOptionalConfigValue(logger, env.KafkaPasswordEnvVarKey, "")
 
+	// Get The Optional Replication Kafka Brokers / Username / Password Config Values
+	environment.ReplicationKafkaBrokers = env.GetOptionalConfigValue(logger, env.ReplicationKafkaBrokerEnvVarKey, "")
+	environment.ReplicationKafkaUsername = env.GetOptionalConfigValue(logger, env.ReplicationKafkaUsernameEnvVarKey, "")
+	environment.ReplicationKafkaPassword = env.GetOptionalConfigValue(logger, env.ReplicationKafkaPasswordEnvVarKey, "")
+
+	// Get The Optional DispatcherConfigOverrides Config Value
+	environment.DispatcherConfigOverrides = env.GetOptionalConfigValue(logger, env.DispatcherConfigOverridesEnvVarKey, "")
+
 	// Clone The Environment & Mask The Password For Safe Logging
 	safeEnvironment := *environment
 	if len(safeEnvironment.KafkaPassword) > 0 {
 		safeEnvironment.KafkaPassword = "*************"
 	}
+	if len(safeEnvironment.ReplicationKafkaPassword) > 0 {
+		safeEnvironment.ReplicationKafkaPassword = "*************"
+	}
 
 	// Log The Dispatcher Configuration Loaded From Environment Variables
 	logger.Info("Environment Variables", zap.Any("Environment", safeEnvironment))
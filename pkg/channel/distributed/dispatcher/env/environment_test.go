@@ -41,6 +41,11 @@ const (
 	kafkaPassword = "TestKafkaPassword"
 	podName       = "TestPod"
 	containerName = "TestContainer"
+	overrides     = `{"dedupe":{"enabled":true}}`
+
+	replicationKafkaBrokers  = "TestReplicationKafkaBrokers"
+	replicationKafkaUsername = "TestReplicationKafkaUsername"
+	replicationKafkaPassword = "TestReplicationKafkaPassword"
 )
 
 // Define The TestCase Struct
@@ -57,6 +62,12 @@ type TestCase struct {
 	kafkaPassword string
 	podName       string
 	containerName string
+	overrides     string
+
+	replicationKafkaBrokers  string
+	replicationKafkaUsername string
+	replicationKafkaPassword string
+
 	expectedError error
 }
 
@@ -71,6 +82,12 @@ func TestGetEnvironment(t *testing.T) {
 	testCase := getValidTestCase("Valid Complete Config")
 	testCases = append(testCases, testCase)
 
+	testCase = getValidTestCase("Valid Config - No Replication")
+	testCase.replicationKafkaBrokers = ""
+	testCase.replicationKafkaUsername = ""
+	testCase.replicationKafkaPassword = ""
+	testCases = append(testCases, testCase)
+
 	testCase = getValidTestCase("Missing Required Config - MetricsDomain")
 	testCase.metricsDomain = ""
 	testCase.expectedError = getMissingRequiredEnvironmentVariableError(commonenv.MetricsDomainEnvVarKey)
@@ -142,6 +159,10 @@ func TestGetEnvironment(t *testing.T) {
 		assertSetenv(t, commonenv.KafkaPasswordEnvVarKey, testCase.kafkaPassword)
 		assertSetenv(t, commonenv.PodNameEnvVarKey, testCase.podName)
 		assertSetenv(t, commonenv.ContainerNameEnvVarKEy, testCase.containerName)
+		assertSetenv(t, commonenv.DispatcherConfigOverridesEnvVarKey, testCase.overrides)
+		assertSetenv(t, commonenv.ReplicationKafkaBrokerEnvVarKey, testCase.replicationKafkaBrokers)
+		assertSetenv(t, commonenv.ReplicationKafkaUsernameEnvVarKey, testCase.replicationKafkaUsername)
+		assertSetenv(t, commonenv.ReplicationKafkaPasswordEnvVarKey, testCase.replicationKafkaPassword)
 
 		// Perform The Test
 		environment, err := GetEnvironment(logger)
@@ -161,6 +182,10 @@ func TestGetEnvironment(t *testing.T) {
 			assert.Equal(t, testCase.kafkaPassword, environment.KafkaPassword)
 			assert.Equal(t, testCase.podName, environment.PodName)
 			assert.Equal(t, testCase.containerName, environment.ContainerName)
+			assert.Equal(t, testCase.overrides, environment.DispatcherConfigOverrides)
+			assert.Equal(t, testCase.replicationKafkaBrokers, environment.ReplicationKafkaBrokers)
+			assert.Equal(t, testCase.replicationKafkaUsername, environment.ReplicationKafkaUsername)
+			assert.Equal(t, testCase.replicationKafkaPassword, environment.ReplicationKafkaPassword)
 
 		} else {
 			assert.Equal(t, testCase.expectedError, err)
@@ -195,6 +220,12 @@ func getValidTestCase(name string) TestCase {
 		kafkaPassword: kafkaPassword,
 		podName:       podName,
 		containerName: containerName,
+		overrides:     overrides,
+
+		replicationKafkaBrokers:  replicationKafkaBrokers,
+		replicationKafkaUsername: replicationKafkaUsername,
+		replicationKafkaPassword: replicationKafkaPassword,
+
 		expectedError: nil,
 	}
 }
@@ -0,0 +1,302 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/consumer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/producer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+)
+
+// The Default Minimum Interval Between The Checkpoints Of Each Replicated Partition
+const DefaultCheckpointInterval = 5 * time.Second
+
+// The Bounds Of The Backoff Between Attempts To Replicate A Record To The Remote Kafka Cluster
+const (
+	minReplicationBackoff = 100 * time.Millisecond
+	maxReplicationBackoff = 10 * time.Second
+	consumeRetryInterval  = time.Second
+)
+
+// The Configuration Of A Replicator
+type ReplicatorConfig struct {
+	Logger               *zap.Logger
+	Topic                string   // The KafkaChannel's Topic
+	Brokers              []string // The KafkaChannel's (Local) Kafka Brokers
+	RemoteBrokers        []string // The Remote Kafka Brokers (Replication Is Disabled If Empty)
+	RemoteUsername       string   // Optional SASL Username Of The Remote Kafka Cluster
+	RemotePassword       string   // Optional SASL Password Of The Remote Kafka Cluster
+	SaramaConfig         *sarama.Config
+	CheckpointInterval   time.Duration // Defaults To DefaultCheckpointInterval
+	ConsumerGroupFactory consumer.ConsumerGroupFactory
+	ProducerFactory      producer.SyncProducerFactory
+}
+
+// The Checkpoint Of A Replicated Partition, Mapping The Offset Of A Source Record To Its Replica's Offset
+type Checkpoint struct {
+	Topic        string    `json:"topic"`
+	Partition    int32     `json:"partition"`
+	SourceOffset int64     `json:"sourceOffset"`
+	TargetOffset int64     `json:"targetOffset"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+//
+// Replicator Mirroring A KafkaChannel's Topic To A Remote Kafka Cluster (MirrorMaker Style)
+//
+// The records of the Topic are consumed by a dedicated ConsumerGroup and produced verbatim (key, value, headers and
+// timestamp) to the same partition of the same-named Topic of the remote Kafka cluster, which must exist with at least
+// as many partitions.  The records are thus replicated as-is, and remain encrypted if record encryption is enabled.
+// The offset of each source record is only committed once its replica has been acknowledged, so replication is
+// at-least-once and resumes where it left off.  The offset mapping of each replicated partition is periodically
+// written to the first partition of the remote cluster's checkpoint Topic (<topic>.checkpoints, keyed by
+// <topic>:<partition>) so that consumers failing over to the remote cluster can translate their committed offsets.
+// A nil *Replicator is valid and simply does nothing.
+//
+type Replicator struct {
+	logger             *zap.Logger
+	topic              string
+	checkpointTopic    string
+	checkpointInterval time.Duration
+	consumerGroup      sarama.ConsumerGroup
+	producer           sarama.SyncProducer
+	cancel             context.CancelFunc
+	doneChan           chan struct{}
+	now                func() time.Time
+}
+
+// Verify The Replicator Implements The ConsumerGroupHandler Interface
+var _ sarama.ConsumerGroupHandler = &Replicator{}
+
+// Replicator Constructor (Returns nil If No Remote Kafka Brokers Are Configured)
+func NewReplicator(config ReplicatorConfig) (*Replicator, error) {
+
+	// Nothing To Replicate To Without Remote Kafka Brokers
+	if len(config.RemoteBrokers) == 0 {
+		return nil, nil
+	}
+
+	// Default The Checkpoint Interval
+	checkpointInterval := config.CheckpointInterval
+	if checkpointInterval <= 0 {
+		checkpointInterval = DefaultCheckpointInterval
+	}
+
+	// Consume The Topic From The Oldest Retained Record (The Remote Cluster Replicates The Topic's Whole History)
+	groupId := util.ReplicatorGroupId(config.Topic)
+	consumerConfig := *config.SaramaConfig
+	consumerConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	consumerGroup, _, err := consumer.CreateConsumerGroupWithFactory(config.ConsumerGroupFactory, config.Brokers, &consumerConfig, groupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replication consumer group %s: %w", groupId, err)
+	}
+
+	// Produce To The Source Partition Of The Remote Cluster With Its Own SASL Credentials (SyncProducers Require Successes)
+	producerConfig := *config.SaramaConfig
+	producerConfig.Producer.Return.Successes = true
+	producerConfig.Producer.Partitioner = sarama.NewManualPartitioner
+	producerConfig.Net.SASL.Enable = len(config.RemoteUsername) > 0
+	producerConfig.Net.SASL.User = config.RemoteUsername
+	producerConfig.Net.SASL.Password = config.RemotePassword
+	remoteProducer, _, err := producer.CreateSyncProducerWithFactory(config.ProducerFactory, config.RemoteBrokers, &producerConfig)
+	if err != nil {
+		_ = consumerGroup.Close()
+		return nil, fmt.Errorf("failed to create replication producer for %v: %w", config.RemoteBrokers, err)
+	}
+
+	// Create & Return The Replicator
+	return &Replicator{
+		logger:             config.Logger.With(zap.String("GroupId", groupId), zap.Strings("RemoteBrokers", config.RemoteBrokers)),
+		topic:              config.Topic,
+		checkpointTopic:    util.CheckpointTopicName(config.Topic),
+		checkpointInterval: checkpointInterval,
+		consumerGroup:      consumerGroup,
+		producer:           remoteProducer,
+		doneChan:           make(chan struct{}),
+		now:                time.Now,
+	}, nil
+}
+
+// Start Replicating The Topic Asynchronously (Until Stopped)
+func (r *Replicator) Start() {
+	if r == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	// Asynchronously Process The ConsumerGroup's Error Channel (Closing The ConsumerGroup Will Break Out Of This)
+	go func() {
+		for err := range r.consumerGroup.Errors() {
+			r.logger.Error("Replication ConsumerGroup Error", zap.Error(err))
+		}
+	}()
+
+	// Consume The Topic Asynchronously, Re-Joining The ConsumerGroup After Each Re-Balance
+	go func() {
+		defer close(r.doneChan)
+		r.logger.Info("Topic Replication Initiated", zap.String("Topic", r.topic))
+		for ctx.Err() == nil {
+			err := r.consumerGroup.Consume(ctx, []string{r.topic}, r)
+			if err == sarama.ErrClosedConsumerGroup {
+				break
+			} else if err != nil {
+				r.logger.Error("Replication ConsumerGroup Failed To Consume Messages", zap.Error(err))
+				select {
+				case <-ctx.Done():
+				case <-time.After(consumeRetryInterval):
+				}
+			}
+		}
+		r.logger.Info("Topic Replication Terminated", zap.String("Topic", r.topic))
+	}()
+}
+
+// Stop Replicating The Topic & Close The ConsumerGroup & Remote Producer
+func (r *Replicator) Stop() {
+	if r == nil {
+		return
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if err := r.consumerGroup.Close(); err != nil {
+		r.logger.Warn("Failed To Close Replication ConsumerGroup", zap.Error(err))
+	}
+	if r.cancel != nil {
+		<-r.doneChan
+	}
+	if err := r.producer.Close(); err != nil {
+		r.logger.Warn("Failed To Close Replication Producer", zap.Error(err))
+	}
+}
+
+// ConsumerGroupHandler Lifecycle Method (Runs Before Any ConsumeClaim Goroutines)
+func (r *Replicator) Setup(_ sarama.ConsumerGroupSession) error {
+	r.logger.Debug("Replication ConsumerGroup Setup")
+	return nil
+}
+
+// ConsumerGroupHandler Lifecycle Method (Runs After All ConsumeClaim Goroutines Have Exited)
+func (r *Replicator) Cleanup(_ sarama.ConsumerGroupSession) error {
+	r.logger.Debug("Replication ConsumerGroup Cleanup")
+	return nil
+}
+
+// ConsumerGroupHandler Lifecycle Method Replicating The Records Of A Single Partition
+func (r *Replicator) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+
+	// The Latest Un-Checkpointed Offset Mapping Of The Partition & The Time Of Its Last Checkpoint
+	var pending *Checkpoint
+	lastCheckpoint := r.now()
+
+	// Checkpoint Any Pending Offset Mapping When The Claim Ends (e.g. Re-Balance)
+	defer func() {
+		if pending != nil {
+			r.checkpoint(pending, r.now())
+		}
+	}()
+
+	for message := range claim.Messages() {
+
+		// Replicate The Record To The Remote Cluster (Ceasing Without Committing Its Offset If The Session Ends)
+		targetOffset, ok := r.replicate(session.Context(), message)
+		if !ok {
+			return nil
+		}
+		session.MarkMessage(message, "")
+
+		// Periodically Checkpoint The Offset Mapping Of The Partition
+		pending = &Checkpoint{Topic: message.Topic, Partition: message.Partition, SourceOffset: message.Offset, TargetOffset: targetOffset}
+		if now := r.now(); now.Sub(lastCheckpoint) >= r.checkpointInterval {
+			r.checkpoint(pending, now)
+			pending = nil
+			lastCheckpoint = now
+		}
+	}
+	return nil
+}
+
+// Produce A Replica Of The Specified Record To The Remote Cluster, Retrying With Backoff Until Success Or The Context Ends
+func (r *Replicator) replicate(ctx context.Context, message *sarama.ConsumerMessage) (int64, bool) {
+
+	// Copy The Record Verbatim To The Same Partition Of The Remote Topic
+	producerMessage := &sarama.ProducerMessage{
+		Topic:     r.topic,
+		Partition: message.Partition,
+		Value:     sarama.ByteEncoder(message.Value),
+		Timestamp: message.Timestamp,
+	}
+	if message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(message.Key)
+	}
+	for _, header := range message.Headers {
+		if header != nil {
+			producerMessage.Headers = append(producerMessage.Headers, *header)
+		}
+	}
+
+	// Produce The Replica Until Acknowledged By The Remote Cluster
+	backoff := minReplicationBackoff
+	for {
+		_, offset, err := r.producer.SendMessage(producerMessage)
+		if err == nil {
+			return offset, true
+		}
+		r.logger.Warn("Failed To Replicate Record - Retrying",
+			zap.Int32("Partition", message.Partition), zap.Int64("Offset", message.Offset), zap.Duration("Backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return 0, false
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxReplicationBackoff {
+			backoff = maxReplicationBackoff
+		}
+	}
+}
+
+// Produce The Specified Offset Mapping To The Remote Cluster's Checkpoint Topic (Failures Are Superseded By Later Checkpoints)
+func (r *Replicator) checkpoint(checkpoint *Checkpoint, now time.Time) {
+	checkpoint.Timestamp = now.UTC()
+	value, err := json.Marshal(checkpoint)
+	if err != nil {
+		r.logger.Error("Failed To Marshal Replication Checkpoint", zap.Error(err))
+		return
+	}
+	_, _, err = r.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: r.checkpointTopic,
+		Key:   sarama.StringEncoder(CheckpointKey(checkpoint.Topic, checkpoint.Partition)),
+		Value: sarama.ByteEncoder(value),
+	})
+	if err != nil {
+		r.logger.Warn("Failed To Produce Replication Checkpoint", zap.Int32("Partition", checkpoint.Partition), zap.Error(err))
+	}
+}
+
+// Get The Key Of The Checkpoints Of The Specified Topic Partition (A Compacted Checkpoint Topic Retains The Latest)
+func CheckpointKey(topic string, partition int32) string {
+	return fmt.Sprintf("%s:%d", topic, partition)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	kafkatesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/testing"
+	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testTopic          = "TestNamespace.TestName"
+	testRemoteUsername = "TestRemoteUsername"
+	testRemotePassword = "TestRemotePassword"
+)
+
+// Test The NewReplicator() Functionality
+func TestNewReplicator(t *testing.T) {
+
+	// Create The Test Sarama Config (Authenticating With The Local Cluster's Credentials)
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Net.SASL.Enable = true
+	saramaConfig.Net.SASL.User = "TestLocalUsername"
+
+	// Record The Configs Of The Created ConsumerGroup & SyncProducer
+	var consumerGroupId string
+	var consumerConfig, producerConfig *sarama.Config
+	consumerGroup := kafkatesting.NewMockConsumerGroup(t)
+	replicatorConfig := ReplicatorConfig{
+		Logger:         logtesting.TestLogger(t).Desugar(),
+		Topic:          testTopic,
+		Brokers:        []string{"local:9092"},
+		RemoteBrokers:  []string{"remote:9092"},
+		RemoteUsername: testRemoteUsername,
+		RemotePassword: testRemotePassword,
+		SaramaConfig:   saramaConfig,
+		ConsumerGroupFactory: func(brokers []string, groupId string, config *sarama.Config) (sarama.ConsumerGroup, error) {
+			assert.Equal(t, []string{"local:9092"}, brokers)
+			consumerGroupId, consumerConfig = groupId, config
+			return consumerGroup, nil
+		},
+		ProducerFactory: func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+			assert.Equal(t, []string{"remote:9092"}, brokers)
+			producerConfig = config
+			return dispatchertesting.NewMockSyncProducer(nil), nil
+		},
+	}
+
+	// Verify Replication Is Disabled Without Remote Brokers
+	replicator, err := NewReplicator(ReplicatorConfig{Logger: replicatorConfig.Logger, Topic: testTopic})
+	assert.Nil(t, err)
+	assert.Nil(t, replicator)
+
+	// Verify The Topic Is Consumed From The Oldest Record & Replicated To The Source Partition With The Remote Credentials
+	replicator, err = NewReplicator(replicatorConfig)
+	assert.Nil(t, err)
+	assert.NotNil(t, replicator)
+	assert.Equal(t, "kafka."+testTopic+".replicator", consumerGroupId)
+	assert.Equal(t, sarama.OffsetOldest, consumerConfig.Consumer.Offsets.Initial)
+	assert.True(t, producerConfig.Producer.Return.Successes)
+	assert.True(t, producerConfig.Net.SASL.Enable)
+	assert.Equal(t, testRemoteUsername, producerConfig.Net.SASL.User)
+	assert.Equal(t, testRemotePassword, producerConfig.Net.SASL.Password)
+	assert.Equal(t, testTopic+".checkpoints", replicator.checkpointTopic)
+	assert.Equal(t, DefaultCheckpointInterval, replicator.checkpointInterval)
+	assert.Equal(t, "TestLocalUsername", saramaConfig.Net.SASL.User) // The Shared Sarama Config Is Unchanged
+
+	// Verify The Remote Cluster Is Produced To Without SASL If It Has No Credentials
+	replicatorConfig.RemoteUsername, replicatorConfig.RemotePassword = "", ""
+	_, err = NewReplicator(replicatorConfig)
+	assert.Nil(t, err)
+	assert.False(t, producerConfig.Net.SASL.Enable)
+
+	// Verify The ConsumerGroup Is Closed If The SyncProducer Can't Be Created
+	replicatorConfig.ProducerFactory = func(_ []string, _ *sarama.Config) (sarama.SyncProducer, error) {
+		return nil, errors.New("test unreachable")
+	}
+	replicator, err = NewReplicator(replicatorConfig)
+	assert.NotNil(t, err)
+	assert.Nil(t, replicator)
+	assert.True(t, consumerGroup.Closed)
+}
+
+// Test The Replicator's ConsumeClaim() Functionality
+func TestReplicatorConsumeClaim(t *testing.T) {
+
+	// Create A Replicator Whose Clock Advances A Second Per Reading
+	producer := dispatchertesting.NewMockSyncProducer(nil)
+	replicator := newTestReplicator(t, producer)
+	now := time.Date(2020, time.November, 1, 12, 0, 0, 0, time.UTC)
+	replicator.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	replicator.checkpointInterval = 2 * time.Second
+
+	// Create Test Records With Keys & Headers
+	timestamp := time.Date(2020, time.October, 1, 12, 0, 0, 0, time.UTC)
+	messages := []*sarama.ConsumerMessage{
+		{Topic: testTopic, Partition: 2, Offset: 10, Key: []byte("TestKey"), Value: []byte("TestValue1"), Timestamp: timestamp,
+			Headers: []*sarama.RecordHeader{{Key: []byte("ce_id"), Value: []byte("TestId")}}},
+		{Topic: testTopic, Partition: 2, Offset: 11, Value: []byte("TestValue2"), Timestamp: timestamp},
+		{Topic: testTopic, Partition: 2, Offset: 12, Value: []byte("TestValue3"), Timestamp: timestamp},
+	}
+
+	// Consume The Records Asynchronously
+	session := dispatchertesting.NewMockConsumerGroupSession(t)
+	claim := dispatchertesting.NewMockConsumerGroupClaim(t)
+	errChan := make(chan error)
+	go func() { errChan <- replicator.ConsumeClaim(session, claim) }()
+
+	// Verify Each Record's Offset Is Marked Once Replicated
+	for _, message := range messages {
+		claim.MessageChan <- message
+		assert.Equal(t, message, <-session.MarkMessageChan)
+	}
+	close(claim.MessageChan)
+	assert.Nil(t, <-errChan)
+
+	// Verify The Records Were Replicated Verbatim To The Same Partition, Interleaved With The Periodic & Final Checkpoints
+	producerMessages := producer.Messages()
+	assert.Len(t, producerMessages, 5)
+	replica := producerMessages[0]
+	assert.Equal(t, testTopic, replica.Topic)
+	assert.Equal(t, int32(2), replica.Partition)
+	assert.Equal(t, sarama.ByteEncoder("TestKey"), replica.Key)
+	assert.Equal(t, sarama.ByteEncoder("TestValue1"), replica.Value)
+	assert.Equal(t, timestamp, replica.Timestamp)
+	assert.Equal(t, []sarama.RecordHeader{{Key: []byte("ce_id"), Value: []byte("TestId")}}, replica.Headers)
+	assert.Nil(t, producerMessages[1].Key)
+	assert.Equal(t, sarama.ByteEncoder("TestValue2"), producerMessages[1].Value)
+	assert.Equal(t, sarama.ByteEncoder("TestValue3"), producerMessages[3].Value)
+
+	// Verify The Checkpoints Map The Source Offsets To The (Mock) Offsets Of Their Replicas
+	for index, expected := range map[int]Checkpoint{
+		2: {Topic: testTopic, Partition: 2, SourceOffset: 11, TargetOffset: 2},
+		4: {Topic: testTopic, Partition: 2, SourceOffset: 12, TargetOffset: 4},
+	} {
+		checkpointMessage := producerMessages[index]
+		assert.Equal(t, testTopic+".checkpoints", checkpointMessage.Topic)
+		assert.Equal(t, sarama.StringEncoder(testTopic+":2"), checkpointMessage.Key)
+		value, err := checkpointMessage.Value.Encode()
+		assert.Nil(t, err)
+		checkpoint := Checkpoint{}
+		assert.Nil(t, json.Unmarshal(value, &checkpoint))
+		assert.True(t, checkpoint.Timestamp.After(timestamp))
+		checkpoint.Timestamp = time.Time{}
+		assert.Equal(t, expected, checkpoint)
+	}
+}
+
+// Test The Replicator's replicate() Functionality Ceasing When The Session Ends
+func TestReplicatorReplicateCancelled(t *testing.T) {
+	producer := dispatchertesting.NewMockSyncProducer(sarama.ErrNotEnoughReplicas)
+	replicator := newTestReplicator(t, producer)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok := replicator.replicate(ctx, &sarama.ConsumerMessage{Topic: testTopic, Value: []byte("TestValue")})
+	assert.False(t, ok)
+	assert.Len(t, producer.Messages(), 1)
+}
+
+// Test The Replicator's Start() & Stop() Functionality
+func TestReplicatorStartStop(t *testing.T) {
+
+	// Verify A nil Replicator Does Nothing
+	var nilReplicator *Replicator
+	nilReplicator.Start()
+	nilReplicator.Stop()
+
+	// Verify The ConsumerGroup & SyncProducer Are Closed When Stopped
+	producer := dispatchertesting.NewMockSyncProducer(nil)
+	replicator := newTestReplicator(t, producer)
+	replicator.Start()
+	replicator.Stop()
+	assert.True(t, replicator.consumerGroup.(*kafkatesting.MockConsumerGroup).Closed)
+	assert.True(t, producer.Closed())
+}
+
+// Utility Function For Creating A Test Replicator Producing Via The Specified SyncProducer
+func newTestReplicator(t *testing.T, producer sarama.SyncProducer) *Replicator {
+	replicator, err := NewReplicator(ReplicatorConfig{
+		Logger:        logtesting.TestLogger(t).Desugar(),
+		Topic:         testTopic,
+		RemoteBrokers: []string{"remote:9092"},
+		SaramaConfig:  sarama.NewConfig(),
+		ConsumerGroupFactory: func(_ []string, _ string, _ *sarama.Config) (sarama.ConsumerGroup, error) {
+			return kafkatesting.NewMockConsumerGroup(t), nil
+		},
+		ProducerFactory: func(_ []string, _ *sarama.Config) (sarama.SyncProducer, error) {
+			return producer, nil
+		},
+	})
+	assert.Nil(t, err)
+	return replicator
+}
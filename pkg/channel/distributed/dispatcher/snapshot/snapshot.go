@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+// Snapshot Constants
+const (
+	ConfigMapSuffix = "-snapshot"     // The Suffix Appended To The Dispatcher's ServiceName To Name The Snapshot ConfigMap
+	DataKey         = "snapshot.json" // The ConfigMap Data Key Of The Serialized Snapshot
+)
+
+// The Persisted Snapshot Of A Dispatcher's Subscriptions
+type Snapshot struct {
+	ChannelKey     string                        `json:"channelKey"`
+	ChannelUID     types.UID                     `json:"channelUid"`
+	Annotations    map[string]string             `json:"annotations,omitempty"` // EventType Routing, EventAgePolicies, RebalanceStrategy, gRPC Subscribers, Parallelism, Filters & Transforms
+	ExtraTopics    []string                      `json:"extraTopics,omitempty"` // The Externally Managed Topics Fanned In To The KafkaChannel
+	Subscribers    []eventingduck.SubscriberSpec `json:"subscribers"`           // Including The Resolved Subscriber / Reply / DeadLetterSink URIs
+	ConsumerGroups map[types.UID]string          `json:"consumerGroups"`        // Subscription UID -> Kafka ConsumerGroup Id
+}
+
+// Snapshot Constructor - Captures The Subscriptions Of The Specified (Reconciled) KafkaChannel
+func NewSnapshot(channel *kafkav1beta1.KafkaChannel) *Snapshot {
+	snapshot := &Snapshot{
+		ChannelKey:     channel.Namespace + "/" + channel.Name,
+		ChannelUID:     channel.UID,
+		Annotations:    channel.Annotations,
+		ExtraTopics:    channel.Spec.ExtraTopics,
+		Subscribers:    make([]eventingduck.SubscriberSpec, 0, len(channel.Spec.Subscribers)),
+		ConsumerGroups: make(map[types.UID]string, len(channel.Spec.Subscribers)),
+	}
+	for _, subscriber := range channel.Spec.Subscribers {
+		snapshot.Subscribers = append(snapshot.Subscribers, subscriber)
+		snapshot.ConsumerGroups[subscriber.UID] = util.GroupId(string(subscriber.UID))
+	}
+	return snapshot
+}
+
+//
+// Subscription Snapshot Store
+//
+// The Store persists the subscriptions of the dispatcher's KafkaChannel in a ConfigMap (owned by the
+// KafkaChannel) after every successful reconciliation, so that a restarted dispatcher can rejoin its
+// ConsumerGroups immediately rather than waiting for its informers to sync and the KafkaChannel to be
+// reconciled.  Unchanged snapshots are not re-written.  A nil *Store is valid and never loads or saves
+// any snapshots.
+//
+type Store struct {
+	logger     *zap.Logger
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+	saved      *Snapshot
+	lock       sync.Mutex
+}
+
+// Utility Function For Getting The Name Of The Snapshot ConfigMap Of The Dispatcher With The Specified ServiceName
+func ConfigMapName(serviceName string) string {
+	return serviceName + ConfigMapSuffix
+}
+
+// Store Constructor - Returns nil If Snapshots Are Not Enabled
+func NewStore(logger *zap.Logger, kubeClient kubernetes.Interface, namespace string, serviceName string, snapshotConfig config.EKSnapshotConfig) *Store {
+	if !snapshotConfig.Enabled {
+		return nil
+	}
+	return &Store{
+		logger:     logger,
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		name:       ConfigMapName(serviceName),
+	}
+}
+
+// Load The Persisted Snapshot Of The Specified KafkaChannel (Returns nil If There Is None)
+func (s *Store) Load(ctx context.Context, channelKey string) (*Snapshot, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	configMap, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	data, ok := configMap.Data[DataKey]
+	if !ok {
+		return nil, nil
+	}
+	snapshot := &Snapshot{}
+	err = json.Unmarshal([]byte(data), snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	// Ignore Snapshots Of Other KafkaChannels (Should Not Happen As The ConfigMap Is Named After The Dispatcher)
+	if snapshot.ChannelKey != channelKey {
+		s.logger.Warn("Ignoring Snapshot Of Another KafkaChannel", zap.String("ChannelKey", snapshot.ChannelKey))
+		return nil, nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.saved = snapshot
+	return snapshot, nil
+}
+
+// Save The Snapshot Of The Specified (Reconciled) KafkaChannel, Unless Unchanged Since Last Loaded / Saved
+func (s *Store) Save(ctx context.Context, channel *kafkav1beta1.KafkaChannel) error {
+	if s == nil {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot := NewSnapshot(channel)
+	if reflect.DeepEqual(s.saved, snapshot) {
+		return nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to serialize snapshot: %w", err)
+	}
+
+	// Update The Snapshot ConfigMap, Creating It (Owned By The KafkaChannel For Garbage Collection) If Necessary
+	configMaps := s.kubeClient.CoreV1().ConfigMaps(s.namespace)
+	configMap, err := configMaps.Get(ctx, s.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            s.name,
+				Namespace:       s.namespace,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(channel, kafkav1beta1.SchemeGroupVersion.WithKind("KafkaChannel"))},
+			},
+			Data: map[string]string{DataKey: string(data)},
+		}
+		_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+	} else if err == nil {
+		configMap = configMap.DeepCopy()
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[DataKey] = string(data)
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	s.saved = snapshot
+	s.logger.Debug("Saved Subscription Snapshot", zap.String("ConfigMap", s.name), zap.Int("Subscribers", len(snapshot.Subscribers)))
+	return nil
+}
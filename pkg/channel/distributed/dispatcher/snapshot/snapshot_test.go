@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testNamespace    = "knative-eventing"
+	testServiceName  = "test-kc-dispatcher"
+	testChannelKey   = "test-namespace/test-kc"
+	testSubscriberId = "test-subscriber-uid"
+)
+
+// Test The NewSnapshot() Functionality
+func TestNewSnapshot(t *testing.T) {
+	snapshot := NewSnapshot(createTestChannel("subscriber"))
+	assert.Equal(t, testChannelKey, snapshot.ChannelKey)
+	assert.Equal(t, "test-channel-uid", string(snapshot.ChannelUID))
+	assert.Equal(t, map[string]string{"foo": "bar"}, snapshot.Annotations)
+	assert.Equal(t, []string{"external-topic"}, snapshot.ExtraTopics)
+	assert.Len(t, snapshot.Subscribers, 1)
+	assert.Equal(t, "http://subscriber", snapshot.Subscribers[0].SubscriberURI.String())
+	assert.Equal(t, util.GroupId(testSubscriberId), snapshot.ConsumerGroups[testSubscriberId])
+}
+
+// Test The Nil Store Functionality
+func TestNilStore(t *testing.T) {
+	store := NewStore(logtesting.TestLogger(t).Desugar(), fake.NewSimpleClientset(), testNamespace, testServiceName, config.EKSnapshotConfig{})
+	assert.Nil(t, store)
+	snapshot, err := store.Load(context.TODO(), testChannelKey)
+	assert.Nil(t, snapshot)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(context.TODO(), createTestChannel("subscriber")))
+}
+
+// Test The Store's Save() & Load() Functionality
+func TestStore(t *testing.T) {
+	ctx := context.TODO()
+	kubeClient := fake.NewSimpleClientset()
+	logger := logtesting.TestLogger(t).Desugar()
+	store := NewStore(logger, kubeClient, testNamespace, testServiceName, config.EKSnapshotConfig{Enabled: true})
+
+	// Without A Snapshot ConfigMap There Is Nothing To Load
+	snapshot, err := store.Load(ctx, testChannelKey)
+	assert.Nil(t, snapshot)
+	assert.Nil(t, err)
+
+	// Saving Creates The Snapshot ConfigMap Owned By The KafkaChannel
+	channel := createTestChannel("subscriber")
+	assert.Nil(t, store.Save(ctx, channel))
+	configMap, err := kubeClient.CoreV1().ConfigMaps(testNamespace).Get(ctx, ConfigMapName(testServiceName), metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, configMap.OwnerReferences, 1)
+	assert.Equal(t, channel.UID, configMap.OwnerReferences[0].UID)
+	assert.Contains(t, configMap.Data[DataKey], "http://subscriber")
+
+	// Unchanged Snapshots Are Not Re-Written
+	kubeClient.ClearActions()
+	assert.Nil(t, store.Save(ctx, channel))
+	assert.Empty(t, kubeClient.Actions())
+
+	// Changed Snapshots Update The ConfigMap
+	assert.Nil(t, store.Save(ctx, createTestChannel("other-subscriber")))
+	assert.Len(t, kubeClient.Actions(), 2)
+
+	// A Restarted Dispatcher Loads The Latest Snapshot
+	snapshot, err = NewStore(logger, kubeClient, testNamespace, testServiceName, config.EKSnapshotConfig{Enabled: true}).Load(ctx, testChannelKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://other-subscriber", snapshot.Subscribers[0].SubscriberURI.String())
+	assert.Equal(t, util.GroupId(testSubscriberId), snapshot.ConsumerGroups[testSubscriberId])
+	assert.Equal(t, map[string]string{"foo": "bar"}, snapshot.Annotations)
+
+	// Snapshots Of Other KafkaChannels Are Ignored
+	snapshot, err = store.Load(ctx, "other-namespace/other-kc")
+	assert.Nil(t, snapshot)
+	assert.Nil(t, err)
+
+	// Invalid Snapshots Are An Error
+	configMap.Data[DataKey] = "{"
+	_, err = kubeClient.CoreV1().ConfigMaps(testNamespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+	_, err = store.Load(ctx, testChannelKey)
+	assert.NotNil(t, err)
+}
+
+// Utility Function For Creating A Test KafkaChannel With A Single Subscriber
+func createTestChannel(subscriberHost string) *kafkav1beta1.KafkaChannel {
+	return &kafkav1beta1.KafkaChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-kc",
+			Namespace:   "test-namespace",
+			UID:         "test-channel-uid",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Spec: kafkav1beta1.KafkaChannelSpec{
+			ExtraTopics: []string{"external-topic"},
+			ChannelableSpec: eventingduck.ChannelableSpec{
+				SubscribableSpec: eventingduck.SubscribableSpec{
+					Subscribers: []eventingduck.SubscriberSpec{
+						{UID: testSubscriberId, Generation: 1, SubscriberURI: apis.HTTP(subscriberHost)},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,376 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonlistener "knative.dev/eventing-kafka/pkg/channel/distributed/common/listener"
+)
+
+// Tail Constants
+const (
+	Path         = "/tail" // The Path Of The Tail Endpoint
+	DefaultPort  = 8082    // The Default Port Of The Tail Server
+	Subresource  = "tail"  // The KafkaChannel Subresource Callers Must Be Authorized To "get"
+	BufferSize   = 100     // The Number Of Events Buffered Per Watcher (Further Events Are Dropped)
+	bearerPrefix = "Bearer "
+)
+
+// The Sampled Event Sent To Watchers As The Data Of A Server-Sent Event
+type Event struct {
+	Subscription string            `json:"subscription"`
+	Topic        string            `json:"topic"`
+	Partition    int32             `json:"partition"`
+	Offset       int64             `json:"offset"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Key          string            `json:"key,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Payload      string            `json:"payload,omitempty"` // Only When Requested & Allowed
+}
+
+// A Single Watcher Of The Tap (Optionally Filtered To A Single Subscription)
+type watcher struct {
+	subscription string
+	payload      bool
+	events       chan Event
+}
+
+//
+// Data Plane Event Tap
+//
+// The Tap samples the events consumed by the dispatcher for the watchers of the tail endpoint, allowing
+// operators to troubleshoot a KafkaChannel without consuming its Topic with Kafka credentials.  Events are
+// never blocked on slow watchers, which instead miss events once their buffer is full.  A nil *Tap is valid
+// and never samples any events.
+//
+type Tap struct {
+	watchers     map[*watcher]bool
+	watcherCount int32
+	lock         sync.RWMutex
+}
+
+// Tap Constructor - Returns nil If The Tail Endpoint Is Not Enabled
+func NewTap(tailConfig config.EKTailConfig) *Tap {
+	if !tailConfig.Enabled {
+		return nil
+	}
+	return &Tap{watchers: make(map[*watcher]bool)}
+}
+
+// Sample The Specified ConsumerMessage Of The Specified Subscription For Any Interested Watchers
+func (t *Tap) Publish(subscription string, consumerMessage *sarama.ConsumerMessage) {
+
+	// Quick Exit When Nobody Is Watching
+	if t == nil || atomic.LoadInt32(&t.watcherCount) == 0 {
+		return
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	for w := range t.watchers {
+		if len(w.subscription) > 0 && w.subscription != subscription {
+			continue
+		}
+		select {
+		case w.events <- newEvent(subscription, consumerMessage, w.payload):
+		default: // Drop The Event Rather Than Block Dispatching
+		}
+	}
+}
+
+// Add A Watcher To The Tap
+func (t *Tap) watch(subscription string, payload bool) *watcher {
+	w := &watcher{subscription: subscription, payload: payload, events: make(chan Event, BufferSize)}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.watchers[w] = true
+	atomic.AddInt32(&t.watcherCount, 1)
+	return w
+}
+
+// Remove A Watcher From The Tap
+func (t *Tap) unwatch(w *watcher) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.watchers[w] {
+		delete(t.watchers, w)
+		atomic.AddInt32(&t.watcherCount, -1)
+	}
+}
+
+// Utility Function For Creating The Sampled Event Of A ConsumerMessage
+func newEvent(subscription string, consumerMessage *sarama.ConsumerMessage, payload bool) Event {
+	event := Event{
+		Subscription: subscription,
+		Topic:        consumerMessage.Topic,
+		Partition:    consumerMessage.Partition,
+		Offset:       consumerMessage.Offset,
+		Timestamp:    consumerMessage.Timestamp,
+		Key:          string(consumerMessage.Key),
+	}
+	if len(consumerMessage.Headers) > 0 {
+		event.Headers = make(map[string]string, len(consumerMessage.Headers))
+		for _, header := range consumerMessage.Headers {
+			if header != nil {
+				event.Headers[string(header.Key)] = string(header.Value)
+			}
+		}
+	}
+	if payload {
+		event.Payload = string(consumerMessage.Value)
+	}
+	return event
+}
+
+//
+// Tail Server Streaming Sampled Events As Server-Sent Events
+//
+// Callers authenticate with a Kubernetes bearer token, and must be authorized (via RBAC) to "get" the
+// "tail" subresource of the dispatcher's KafkaChannel.  The optional query parameters are...
+//
+//   subscription - The UID of a single Subscription to tail (otherwise all of the KafkaChannel's Subscriptions)
+//   payload      - Include the event payloads ("true"), only if allowed by the configuration
+//   limit        - The maximum number of events to stream before closing the stream
+//
+type Server struct {
+	logger       *zap.Logger
+	tap          *Tap
+	kubeClient   kubernetes.Interface
+	namespace    string
+	name         string
+	allowPayload bool
+	server       *http.Server
+	done         chan struct{} // Closed Once The HTTP Server Has Stopped Serving (nil Until Started)
+	Port         string
+}
+
+// Server Constructor - Returns nil If The Tap Is nil (Tail Endpoint Not Enabled)
+func NewServer(logger *zap.Logger, tap *Tap, kubeClient kubernetes.Interface, channelKey string, tailConfig config.EKTailConfig) (*Server, error) {
+
+	// Nothing To Serve Without A Tap
+	if tap == nil {
+		return nil, nil
+	}
+
+	// Parse The KafkaChannel Key (Used For Authorization)
+	namespace, name, err := cache.SplitMetaNamespaceKey(channelKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid channel key %q: %w", channelKey, err)
+	}
+
+	// Default The Port
+	port := tailConfig.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+
+	// Create The Server
+	server := &Server{
+		logger:       logger,
+		tap:          tap,
+		kubeClient:   kubeClient,
+		namespace:    namespace,
+		name:         name,
+		allowPayload: tailConfig.AllowPayload,
+		Port:         strconv.Itoa(port),
+	}
+	serveMux := http.NewServeMux()
+	serveMux.HandleFunc(Path, server.HandleTail)
+	server.server = &http.Server{Handler: serveMux}
+
+	// Return The Server
+	return server, nil
+}
+
+// Start The HTTP Server (Non-Blocking)
+func (s *Server) Start() error {
+	listener, err := commonlistener.Listen(s.Port)
+	if err != nil {
+		s.logger.Error("Tail Server HTTP Listen Returned Error", zap.Error(err))
+		return err
+	}
+	s.Port = strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	s.logger.Warn("Starting Tail Server HTTP Server - Events Are Exposed To Authorized Callers", zap.String("Port", s.Port), zap.Bool("AllowPayload", s.allowPayload))
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		err := s.server.Serve(listener)
+		if err != nil {
+			s.logger.Info("Tail Server HTTP Serve Returned Error", zap.Error(err)) // Info log since it could just be normal shutdown
+		}
+	}()
+	return nil
+}
+
+// Stop The HTTP Server (Blocking Until It Has Stopped Serving)
+func (s *Server) Stop() {
+	if s == nil {
+		return
+	}
+	s.logger.Info("Stopping Tail Server HTTP Server")
+	if err := s.server.Shutdown(context.TODO()); err != nil {
+		s.logger.Error("Tail Server Failed To Shutdown HTTP Server", zap.Error(err))
+	}
+	if s.done != nil {
+		<-s.done
+	}
+}
+
+// HTTP Request Handler For Tail Requests (/tail)
+func (s *Server) HandleTail(responseWriter http.ResponseWriter, request *http.Request) {
+
+	// Only GET Is Supported
+	if request.Method != http.MethodGet {
+		responseWriter.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Parse The Query Parameters
+	query := request.URL.Query()
+	subscription := query.Get("subscription")
+	payload := query.Get("payload") == "true"
+	if payload && !s.allowPayload {
+		http.Error(responseWriter, "event payloads are not allowed by the tail configuration", http.StatusForbidden)
+		return
+	}
+	limit := 0
+	if limitString := query.Get("limit"); len(limitString) > 0 {
+		var err error
+		if limit, err = strconv.Atoi(limitString); err != nil || limit < 0 {
+			http.Error(responseWriter, fmt.Sprintf("invalid limit %q", limitString), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Authenticate & Authorize The Caller
+	user, statusCode, err := s.authorize(request)
+	if err != nil {
+		s.logger.Warn("Rejected Tail Request", zap.Int("StatusCode", statusCode), zap.Error(err))
+		http.Error(responseWriter, err.Error(), statusCode)
+		return
+	}
+
+	// Server-Sent Events Require Flushing
+	flusher, ok := responseWriter.(http.Flusher)
+	if !ok {
+		http.Error(responseWriter, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Watch The Tap Until The Caller Disconnects Or The Limit Is Reached
+	logger := s.logger.With(zap.String("User", user), zap.String("Subscription", subscription), zap.Bool("Payload", payload))
+	logger.Info("Tail Started")
+	w := s.tap.watch(subscription, payload)
+	defer s.tap.unwatch(w)
+
+	// Stream The Events
+	responseWriter.Header().Set("Content-Type", "text/event-stream")
+	responseWriter.Header().Set("Cache-Control", "no-cache")
+	responseWriter.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for count := 0; limit == 0 || count < limit; count++ {
+		select {
+		case event := <-w.events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				logger.Error("Failed To Marshal Tail Event", zap.Error(err))
+				return
+			}
+			if _, err = fmt.Fprintf(responseWriter, "event: message\ndata: %s\n\n", data); err != nil {
+				logger.Info("Tail Ended - Failed To Write Event", zap.Error(err))
+				return
+			}
+			flusher.Flush()
+		case <-request.Context().Done():
+			logger.Info("Tail Ended - Caller Disconnected")
+			return
+		}
+	}
+	logger.Info("Tail Ended - Limit Reached")
+}
+
+// Authenticate The Caller's Bearer Token & Authorize Access To The KafkaChannel's Tail Subresource (Returns The User)
+func (s *Server) authorize(request *http.Request) (string, int, error) {
+
+	// Extract The Bearer Token
+	authorization := request.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, bearerPrefix) {
+		return "", http.StatusUnauthorized, fmt.Errorf("missing bearer token")
+	}
+	token := strings.TrimPrefix(authorization, bearerPrefix)
+
+	// Authenticate The Token
+	tokenReview, err := s.kubeClient.AuthenticationV1().TokenReviews().Create(request.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", http.StatusInternalServerError, fmt.Errorf("failed to review token: %w", err)
+	}
+	if !tokenReview.Status.Authenticated {
+		return "", http.StatusUnauthorized, fmt.Errorf("invalid bearer token")
+	}
+	userInfo := tokenReview.Status.User
+
+	// Authorize The User
+	extra := make(map[string]authorizationv1.ExtraValue, len(userInfo.Extra))
+	for key, value := range userInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	accessReview, err := s.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(request.Context(), &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   s.namespace,
+				Verb:        "get",
+				Group:       "messaging.knative.dev",
+				Resource:    "kafkachannels",
+				Subresource: Subresource,
+				Name:        s.name,
+			},
+			User:   userInfo.Username,
+			Groups: userInfo.Groups,
+			Extra:  extra,
+			UID:    userInfo.UID,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", http.StatusInternalServerError, fmt.Errorf("failed to review access: %w", err)
+	}
+	if !accessReview.Status.Allowed {
+		return "", http.StatusForbidden, fmt.Errorf("user %s is not allowed to get kafkachannels/%s of %s/%s", userInfo.Username, Subresource, s.namespace, s.name)
+	}
+
+	// Return The Authorized User
+	return userInfo.Username, http.StatusOK, nil
+}
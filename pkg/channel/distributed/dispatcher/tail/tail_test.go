@@ -0,0 +1,236 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tail
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testChannelKey = "test-namespace/test-channel"
+	testToken      = "TestToken"
+	testUser       = "TestUser"
+	testTopic      = "TestTopic"
+	testValue      = "TestValue"
+)
+
+// Test The Tap's Publish() Functionality
+func TestTapPublish(t *testing.T) {
+
+	// A nil Tap Is Valid
+	assert.Nil(t, NewTap(config.EKTailConfig{}))
+	var nilTap *Tap
+	nilTap.Publish("uid-1", createTestConsumerMessage(0))
+
+	// Create A Tap With A Filtered & An Unfiltered Watcher
+	tap := NewTap(config.EKTailConfig{Enabled: true})
+	assert.NotNil(t, tap)
+	tap.Publish("uid-1", createTestConsumerMessage(0)) // No Watchers
+	filteredWatcher := tap.watch("uid-1", false)
+	unfilteredWatcher := tap.watch("", true)
+
+	// Verify Events Are Sampled Per Watcher
+	tap.Publish("uid-1", createTestConsumerMessage(1))
+	tap.Publish("uid-2", createTestConsumerMessage(2))
+	assert.Len(t, filteredWatcher.events, 1)
+	assert.Len(t, unfilteredWatcher.events, 2)
+	event := <-filteredWatcher.events
+	assert.Equal(t, Event{Subscription: "uid-1", Topic: testTopic, Offset: 1, Timestamp: event.Timestamp, Key: "TestKey", Headers: map[string]string{"ce_type": "TestType"}}, event)
+	event = <-unfilteredWatcher.events
+	assert.Equal(t, testValue, event.Payload)
+
+	// Verify Events Are Dropped Rather Than Blocking Once A Watcher's Buffer Is Full
+	for i := 0; i < BufferSize*2; i++ {
+		tap.Publish("uid-1", createTestConsumerMessage(int64(i)))
+	}
+	assert.Len(t, filteredWatcher.events, BufferSize)
+
+	// Verify Removed Watchers No Longer Receive Events
+	tap.unwatch(filteredWatcher)
+	tap.unwatch(unfilteredWatcher)
+	tap.unwatch(unfilteredWatcher)
+	assert.Equal(t, int32(0), tap.watcherCount)
+}
+
+// Test The Server's HandleTail() Functionality
+func TestHandleTail(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name          string
+		method        string
+		query         string
+		token         string
+		allowPayload  bool
+		authenticated bool
+		allowed       bool
+		wantStatus    int
+		wantPayload   bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Method Not Allowed", method: http.MethodPost, wantStatus: http.StatusMethodNotAllowed},
+		{name: "Missing Token", wantStatus: http.StatusUnauthorized},
+		{name: "Invalid Token", token: testToken, wantStatus: http.StatusUnauthorized},
+		{name: "Not Allowed", token: testToken, authenticated: true, wantStatus: http.StatusForbidden},
+		{name: "Payload Not Allowed", query: "payload=true", token: testToken, authenticated: true, allowed: true, wantStatus: http.StatusForbidden},
+		{name: "Invalid Limit", query: "limit=foo", token: testToken, authenticated: true, allowed: true, wantStatus: http.StatusBadRequest},
+		{name: "Metadata", query: "limit=1&subscription=uid-1", token: testToken, authenticated: true, allowed: true, wantStatus: http.StatusOK},
+		{name: "Payload", query: "limit=1&payload=true", token: testToken, allowPayload: true, authenticated: true, allowed: true, wantStatus: http.StatusOK, wantPayload: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Fake Kube Client Reviewing Tokens & Access Per The TestCase
+			var accessReview *authorizationv1.SubjectAccessReview
+			kubeClient := fake.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "tokenreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				tokenReview := action.(clientgotesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				assert.Equal(t, testToken, tokenReview.Spec.Token)
+				tokenReview.Status.Authenticated = testCase.authenticated
+				tokenReview.Status.User.Username = testUser
+				return true, tokenReview, nil
+			})
+			kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				accessReview = action.(clientgotesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				accessReview.Status.Allowed = testCase.allowed
+				return true, accessReview, nil
+			})
+
+			// Create The Tail Server
+			tailConfig := config.EKTailConfig{Enabled: true, AllowPayload: testCase.allowPayload}
+			tap := NewTap(tailConfig)
+			server, err := NewServer(logtesting.TestLogger(t).Desugar(), tap, kubeClient, testChannelKey, tailConfig)
+			assert.Nil(t, err)
+			httpServer := httptest.NewServer(http.HandlerFunc(server.HandleTail))
+			defer httpServer.Close()
+
+			// Publish Events Once The Tail Is Watching
+			stopChan := make(chan struct{})
+			defer close(stopChan)
+			go func() {
+				for {
+					select {
+					case <-stopChan:
+						return
+					case <-time.After(10 * time.Millisecond):
+						tap.Publish("uid-1", createTestConsumerMessage(1))
+					}
+				}
+			}()
+
+			// Perform The Tail Request
+			method := testCase.method
+			if len(method) == 0 {
+				method = http.MethodGet
+			}
+			request, err := http.NewRequest(method, httpServer.URL+Path+"?"+testCase.query, nil)
+			assert.Nil(t, err)
+			if len(testCase.token) > 0 {
+				request.Header.Set("Authorization", "Bearer "+testCase.token)
+			}
+			response, err := http.DefaultClient.Do(request)
+			assert.Nil(t, err)
+			defer response.Body.Close()
+
+			// Verify The Response
+			assert.Equal(t, testCase.wantStatus, response.StatusCode)
+			if testCase.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+			assert.Equal(t, &authorizationv1.ResourceAttributes{
+				Namespace:   "test-namespace",
+				Verb:        "get",
+				Group:       "messaging.knative.dev",
+				Resource:    "kafkachannels",
+				Subresource: Subresource,
+				Name:        "test-channel",
+			}, accessReview.Spec.ResourceAttributes)
+			assert.Equal(t, testUser, accessReview.Spec.User)
+
+			// Verify The Single (Limited) Server-Sent Event
+			var dataLines []string
+			scanner := bufio.NewScanner(response.Body)
+			for scanner.Scan() {
+				if strings.HasPrefix(scanner.Text(), "data: ") {
+					dataLines = append(dataLines, strings.TrimPrefix(scanner.Text(), "data: "))
+				}
+			}
+			assert.Len(t, dataLines, 1)
+			var event Event
+			assert.Nil(t, json.Unmarshal([]byte(dataLines[0]), &event))
+			assert.Equal(t, "uid-1", event.Subscription)
+			assert.Equal(t, testCase.wantPayload, event.Payload == testValue)
+		})
+	}
+}
+
+// Test The NewServer() Functionality
+func TestNewServer(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	server, err := NewServer(logger, nil, nil, testChannelKey, config.EKTailConfig{})
+	assert.Nil(t, server)
+	assert.Nil(t, err)
+	server.Stop()
+
+	tap := NewTap(config.EKTailConfig{Enabled: true})
+	server, err = NewServer(logger, tap, nil, "a/b/c", config.EKTailConfig{Enabled: true})
+	assert.Nil(t, server)
+	assert.NotNil(t, err)
+
+	server, err = NewServer(logger, tap, nil, testChannelKey, config.EKTailConfig{Enabled: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "8082", server.Port)
+	server.Port = "0"
+	assert.Nil(t, server.Start())
+	assert.NotEqual(t, "0", server.Port)
+	server.Stop()
+}
+
+// Utility Function For Creating A Test ConsumerMessage
+func createTestConsumerMessage(offset int64) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Topic:     testTopic,
+		Offset:    offset,
+		Timestamp: time.Now(),
+		Key:       []byte("TestKey"),
+		Value:     []byte(testValue),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("ce_type"), Value: []byte("TestType")}},
+	}
+}
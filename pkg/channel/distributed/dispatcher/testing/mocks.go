@@ -45,7 +45,9 @@ type MockMessageDispatcher struct {
 	expectedDeadLetterUrl  *url.URL
 	expectedRetryConfig    *kncloudevents.RetryConfig
 	message                cloudevents.Message
+	deadLetterMessage      cloudevents.Message
 	response               error
+	ResponseCode           int
 }
 
 // Mock MessageDispatcher Constructor
@@ -75,26 +77,40 @@ func (m *MockMessageDispatcher) DispatchMessage(ctx context.Context, message clo
 
 func (m *MockMessageDispatcher) DispatchMessageWithRetries(ctx context.Context, message cloudevents.Message, headers http.Header, destinationUrl *url.URL, replyUrl *url.URL, deadLetterUrl *url.URL, retryConfig *kncloudevents.RetryConfig) (*channel.DispatchExecutionInfo, error) {
 
-	// Validate The Expected Args
+	// Validate The Common Expected Args (The DeadLetterSink Is Dispatched To Directly Rather Than Via The deadLetterUrl)
 	assert.NotNil(m.t, ctx)
 	assert.NotNil(m.t, message)
+	assert.Nil(m.t, deadLetterUrl)
+	assert.Equal(m.t, m.expectedRetryConfig.RetryMax, retryConfig.RetryMax)
+
+	// Track The Received DeadLetterSink Message (Following A Failed Dispatch) & Return Success
+	if m.message != nil && m.expectedDeadLetterUrl != nil && assert.ObjectsAreEqual(m.expectedDeadLetterUrl, destinationUrl) {
+		assert.Nil(m.t, headers)
+		assert.Nil(m.t, replyUrl)
+		m.deadLetterMessage = message
+		return &channel.DispatchExecutionInfo{ResponseCode: http.StatusAccepted}, nil
+	}
+
+	// Validate The Expected Args
 	assert.Equal(m.t, m.expectedHeaders, headers)
 	assert.Equal(m.t, m.expectedDestinationUrl, destinationUrl)
 	assert.Equal(m.t, m.expectedReplyUrl, replyUrl)
-	assert.Equal(m.t, m.expectedDeadLetterUrl, deadLetterUrl)
-	assert.Equal(m.t, m.expectedRetryConfig.RetryMax, retryConfig.RetryMax)
 
 	// Track The Received Message
 	m.message = message
 
 	// Return The Desired Error Response
-	return &channel.DispatchExecutionInfo{}, m.response
+	return &channel.DispatchExecutionInfo{ResponseCode: m.ResponseCode}, m.response
 }
 
 func (m *MockMessageDispatcher) Message() cloudevents.Message {
 	return m.message
 }
 
+func (m *MockMessageDispatcher) DeadLetterMessage() cloudevents.Message {
+	return m.deadLetterMessage
+}
+
 //
 // Mock ConsumerGroupSession Implementation
 //
@@ -177,9 +193,50 @@ func (m MockConsumerGroupClaim) InitialOffset() int64 {
 }
 
 func (m MockConsumerGroupClaim) HighWaterMarkOffset() int64 {
-	panic("implement me")
+	return 0
 }
 
 func (m MockConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage {
 	return m.MessageChan
 }
+
+//
+// Mock SyncProducer Implementation
+//
+
+// Verify The Mock SyncProducer Implements The Interface
+var _ sarama.SyncProducer = &MockSyncProducer{}
+
+// Define The Mock SyncProducer
+type MockSyncProducer struct {
+	producerMessages []*sarama.ProducerMessage
+	response         error
+	closed           bool
+}
+
+// Mock SyncProducer Constructor
+func NewMockSyncProducer(response error) *MockSyncProducer {
+	return &MockSyncProducer{response: response}
+}
+
+func (p *MockSyncProducer) SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error) {
+	p.producerMessages = append(p.producerMessages, msg)
+	return 0, int64(len(p.producerMessages)), p.response
+}
+
+func (p *MockSyncProducer) SendMessages(_ []*sarama.ProducerMessage) error {
+	panic("implement me")
+}
+
+func (p *MockSyncProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+func (p *MockSyncProducer) Messages() []*sarama.ProducerMessage {
+	return p.producerMessages
+}
+
+func (p *MockSyncProducer) Closed() bool {
+	return p.closed
+}
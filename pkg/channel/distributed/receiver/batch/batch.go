@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	nethttp "net/http"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+	eventingchannel "knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/utils"
+)
+
+// The Maximum Number Of Events Accepted In A Single Batched Request
+const MaxBatchSize = 1000
+
+// The Result Of A Single Event Of A Batched Request
+type Result struct {
+	Id     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+//
+// Handler For CloudEvents Batched HTTP Requests (application/cloudevents-batch+json)
+//
+// The Events Of A Batch Are Validated Up Front (Nothing Is Produced If Any Is Invalid) And Then Produced
+// Individually, Sequentially & In Order, So That Events Sharing A Kafka Record Key Keep Their Relative Order.
+// Producing Stops At The First Failure, For The Same Reason, And The Remaining Events Are Reported As Not
+// Produced.  All Other (Binary & Structured) Requests Are Delegated To The Next Handler.
+//
+type Handler struct {
+	logger            *zap.Logger
+	next              nethttp.Handler
+	hostToChannelFunc eventingchannel.ResolveChannelFromHostFunc
+	receiverFunc      eventingchannel.UnbufferedMessageReceiverFunc
+	reporter          eventingchannel.StatsReporter
+}
+
+// Verify The Handler Implements The http.Handler Interface
+var _ nethttp.Handler = &Handler{}
+
+// Create A New Batch Handler
+func NewHandler(logger *zap.Logger,
+	next nethttp.Handler,
+	hostToChannelFunc eventingchannel.ResolveChannelFromHostFunc,
+	receiverFunc eventingchannel.UnbufferedMessageReceiverFunc,
+	reporter eventingchannel.StatsReporter) *Handler {
+
+	return &Handler{
+		logger:            logger,
+		next:              next,
+		hostToChannelFunc: hostToChannelFunc,
+		receiverFunc:      receiverFunc,
+		reporter:          reporter,
+	}
+}
+
+// Handle Batched Requests & Delegate All Others To The Next Handler
+func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
+
+	// Delegate Non-Batched Requests
+	if !IsBatch(request) {
+		h.next.ServeHTTP(response, request)
+		return
+	}
+
+	// Validate The Request Method & Path (Same As The MessageReceiver)
+	if request.Method != nethttp.MethodPost {
+		response.WriteHeader(nethttp.StatusMethodNotAllowed)
+		return
+	}
+	if request.URL.Path != "/" {
+		response.WriteHeader(nethttp.StatusNotFound)
+		return
+	}
+
+	// Resolve The Channel From The Host Header
+	channelReference, err := h.hostToChannelFunc(request.Host)
+	if err != nil {
+		h.logger.Info("Could Not Extract Channel From Batched Request", zap.String("Host", request.Host), zap.Error(err))
+		if _, ok := err.(eventingchannel.UnknownHostError); ok {
+			response.WriteHeader(nethttp.StatusNotFound)
+		} else {
+			response.WriteHeader(nethttp.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Parse The Batch Of Events
+	events, results, err := parseBatch(request)
+	if err != nil {
+		h.logger.Info("Invalid Batched Request", zap.Error(err))
+		h.writeResults(response, nethttp.StatusBadRequest, results, err)
+		return
+	}
+
+	// Produce The Events In Order, Stopping At The First Failure
+	status := nethttp.StatusAccepted
+	headers := utils.PassThroughHeaders(request.Header)
+	for index := range events {
+		if status != nethttp.StatusAccepted {
+			results[index].Status = nethttp.StatusFailedDependency
+			results[index].Error = "not produced after a previous event of the batch failed"
+			continue
+		}
+		err = h.receiverFunc(request.Context(), channelReference, binding.ToMessage(&events[index]), []binding.Transformer{}, headers)
+		if err != nil {
+			h.logger.Info("Failed To Produce Event Of Batched Request", zap.String("Id", events[index].ID()), zap.Error(err))
+			results[index].Status = nethttp.StatusInternalServerError
+			if _, ok := err.(*eventingchannel.UnknownChannelError); ok {
+				results[index].Status = nethttp.StatusNotFound
+			}
+			results[index].Error = err.Error()
+			status = nethttp.StatusMultiStatus
+		} else {
+			results[index].Status = nethttp.StatusAccepted
+		}
+		h.reportEvent(channelReference, &events[index], results[index].Status)
+	}
+
+	// Respond With The Per-Event Results
+	h.writeResults(response, status, results, nil)
+}
+
+// Determine Whether The Request Uses The CloudEvents Batched Content Mode
+func IsBatch(request *nethttp.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return err == nil && mediaType == event.ApplicationCloudEventsBatchJSON
+}
+
+// Parse & Validate The Events Of A Batched Request (Results Are Returned For Every Parsed Event)
+func parseBatch(request *nethttp.Request) ([]event.Event, []Result, error) {
+
+	// Decode The JSON Array Of Events
+	var rawEvents []json.RawMessage
+	if err := json.NewDecoder(request.Body).Decode(&rawEvents); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode the batch: %w", err)
+	}
+	if len(rawEvents) == 0 {
+		return nil, nil, fmt.Errorf("empty batch")
+	}
+	if len(rawEvents) > MaxBatchSize {
+		return nil, nil, fmt.Errorf("batch of %d events exceeds the maximum of %d", len(rawEvents), MaxBatchSize)
+	}
+
+	// Unmarshal & Validate Each Event
+	var invalid error
+	events := make([]event.Event, len(rawEvents))
+	results := make([]Result, len(rawEvents))
+	for index, rawEvent := range rawEvents {
+		err := json.Unmarshal(rawEvent, &events[index])
+		if err == nil {
+			err = events[index].Validate()
+		}
+		results[index].Id = events[index].ID()
+		if err != nil {
+			results[index].Status = nethttp.StatusBadRequest
+			results[index].Error = err.Error()
+			if invalid == nil {
+				invalid = fmt.Errorf("invalid event at index %d: %w", index, err)
+			}
+		}
+	}
+
+	// None Of The Valid Events Are Produced When The Batch Contains Invalid Events
+	if invalid != nil {
+		for index := range results {
+			if results[index].Status == 0 {
+				results[index].Status = nethttp.StatusFailedDependency
+				results[index].Error = "not produced because the batch contains invalid events"
+			}
+		}
+	}
+	return events, results, invalid
+}
+
+// Write The Per-Event Results (Or The Error Alone If There Are None)
+func (h *Handler) writeResults(response nethttp.ResponseWriter, status int, results []Result, err error) {
+	var body interface{} = results
+	if results == nil && err != nil {
+		body = Result{Status: status, Error: err.Error()}
+	}
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(status)
+	if encodeErr := json.NewEncoder(response).Encode(body); encodeErr != nil {
+		h.logger.Warn("Failed To Write Batched Request Results", zap.Error(encodeErr))
+	}
+}
+
+// Report The Event Count Metric For A Single Event Of A Batch
+func (h *Handler) reportEvent(channelReference eventingchannel.ChannelReference, cloudEvent *event.Event, status int) {
+	if h.reporter != nil {
+		args := &eventingchannel.ReportArgs{Ns: channelReference.Namespace, EventType: cloudEvent.Type()}
+		_ = h.reporter.ReportEventCount(args, status)
+	}
+}
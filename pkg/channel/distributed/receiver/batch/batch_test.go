@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	eventingchannel "knative.dev/eventing/pkg/channel"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testHost      = "test-channel-kn-channel.test-namespace.svc.cluster.local"
+	testEvent1    = `{"specversion":"1.0","id":"id-1","source":"test-source","type":"test-type","data":{"n":1}}`
+	testEvent2    = `{"specversion":"1.0","id":"id-2","source":"test-source","type":"test-type","data":{"n":2}}`
+	testEvent3    = `{"specversion":"1.0","id":"id-3","source":"test-source","type":"test-type","data":{"n":3}}`
+	invalidEvent  = `{"specversion":"1.0","id":"id-4","type":"test-type"}`
+	batchMimeType = "application/cloudevents-batch+json; charset=UTF-8"
+)
+
+// Test The Handler's Handling Of Batched & Non-Batched Requests
+func TestServeHTTP(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name            string
+		method          string
+		host            string
+		contentType     string
+		body            string
+		failIds         []string
+		expectedStatus  int
+		expectedResults []Result
+		expectedIds     []string
+		expectDelegated bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{
+			name:            "Non-Batched Request",
+			method:          nethttp.MethodPost,
+			host:            testHost,
+			contentType:     "application/cloudevents+json",
+			body:            testEvent1,
+			expectedStatus:  nethttp.StatusTeapot,
+			expectDelegated: true,
+		},
+		{
+			name:           "Batch Produced In Order",
+			method:         nethttp.MethodPost,
+			host:           testHost,
+			contentType:    batchMimeType,
+			body:           "[" + testEvent1 + "," + testEvent2 + "," + testEvent3 + "]",
+			expectedStatus: nethttp.StatusAccepted,
+			expectedResults: []Result{
+				{Id: "id-1", Status: nethttp.StatusAccepted},
+				{Id: "id-2", Status: nethttp.StatusAccepted},
+				{Id: "id-3", Status: nethttp.StatusAccepted},
+			},
+			expectedIds: []string{"id-1", "id-2", "id-3"},
+		},
+		{
+			name:           "Batch Stopped At First Failure",
+			method:         nethttp.MethodPost,
+			host:           testHost,
+			contentType:    batchMimeType,
+			body:           "[" + testEvent1 + "," + testEvent2 + "," + testEvent3 + "]",
+			failIds:        []string{"id-2"},
+			expectedStatus: nethttp.StatusMultiStatus,
+			expectedResults: []Result{
+				{Id: "id-1", Status: nethttp.StatusAccepted},
+				{Id: "id-2", Status: nethttp.StatusInternalServerError, Error: "produce failed"},
+				{Id: "id-3", Status: nethttp.StatusFailedDependency, Error: "not produced after a previous event of the batch failed"},
+			},
+			expectedIds: []string{"id-1", "id-2"},
+		},
+		{
+			name:           "Batch With Invalid Event",
+			method:         nethttp.MethodPost,
+			host:           testHost,
+			contentType:    batchMimeType,
+			body:           "[" + testEvent1 + "," + invalidEvent + "]",
+			expectedStatus: nethttp.StatusBadRequest,
+			expectedResults: []Result{
+				{Id: "id-1", Status: nethttp.StatusFailedDependency, Error: "not produced because the batch contains invalid events"},
+				{Id: "id-4", Status: nethttp.StatusBadRequest, Error: "source: REQUIRED\n"},
+			},
+		},
+		{
+			name:           "Malformed Batch",
+			method:         nethttp.MethodPost,
+			host:           testHost,
+			contentType:    batchMimeType,
+			body:           "{",
+			expectedStatus: nethttp.StatusBadRequest,
+		},
+		{
+			name:           "Empty Batch",
+			method:         nethttp.MethodPost,
+			host:           testHost,
+			contentType:    batchMimeType,
+			body:           "[]",
+			expectedStatus: nethttp.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Method",
+			method:         nethttp.MethodGet,
+			host:           testHost,
+			contentType:    batchMimeType,
+			expectedStatus: nethttp.StatusMethodNotAllowed,
+		},
+		{
+			name:           "Invalid Host",
+			method:         nethttp.MethodPost,
+			host:           "unknown",
+			contentType:    batchMimeType,
+			body:           "[" + testEvent1 + "]",
+			expectedStatus: nethttp.StatusInternalServerError,
+		},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The Next Handler & The Receiver Function (Recording The Ids Of The Produced Events)
+			delegated := false
+			next := nethttp.HandlerFunc(func(response nethttp.ResponseWriter, _ *nethttp.Request) {
+				delegated = true
+				response.WriteHeader(nethttp.StatusTeapot)
+			})
+			var producedIds []string
+			receiverFunc := func(ctx context.Context, channelReference eventingchannel.ChannelReference, message binding.Message, _ []binding.Transformer, _ nethttp.Header) error {
+				assert.Equal(t, "test-channel-kn-channel", channelReference.Name)
+				assert.Equal(t, "test-namespace", channelReference.Namespace)
+				event, err := binding.ToEvent(ctx, message)
+				require.Nil(t, err)
+				producedIds = append(producedIds, event.ID())
+				for _, failId := range testCase.failIds {
+					if event.ID() == failId {
+						return errors.New("produce failed")
+					}
+				}
+				return nil
+			}
+
+			// Perform The Test
+			handler := NewHandler(logtesting.TestLogger(t).Desugar(), next, eventingchannel.ParseChannel, receiverFunc, nil)
+			request := httptest.NewRequest(testCase.method, "http://"+testCase.host+"/", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", testCase.contentType)
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+
+			// Verify The Results
+			assert.Equal(t, testCase.expectedStatus, response.Code)
+			assert.Equal(t, testCase.expectDelegated, delegated)
+			assert.Equal(t, testCase.expectedIds, producedIds)
+			if testCase.expectedResults != nil {
+				var results []Result
+				require.Nil(t, json.Unmarshal(response.Body.Bytes(), &results))
+				assert.Equal(t, testCase.expectedResults, results)
+			}
+		})
+	}
+}
+
+// Test The IsBatch() Functionality
+func TestIsBatch(t *testing.T) {
+	for contentType, expected := range map[string]bool{
+		"application/cloudevents-batch+json":                true,
+		"application/cloudevents-batch+json; charset=UTF-8": true,
+		"application/cloudevents+json":                      false,
+		"application/json":                                  false,
+		"":                                                  false,
+	} {
+		request := httptest.NewRequest(nethttp.MethodPost, "/", nil)
+		request.Header.Set("Content-Type", contentType)
+		assert.Equal(t, expected, IsBatch(request), contentType)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bodylimit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/validation"
+)
+
+// The Default Maximum Request Body Size (Room For A Batch Of Events Up To Kafka's Default Maximum Message Size)
+const DefaultMaxBodySize = 4 * 1024 * 1024
+
+// The Problem Details Type Of Requests Rejected For The Size Of Their Body
+const RequestTooLargeType = "urn:knative:eventing-kafka:request-too-large"
+
+// The Error Reading A Request Body Beyond The Maximum Size
+var ErrTooLarge = errors.New("request body too large")
+
+//
+// Maximum Request Body Size Of The Receiver
+//
+// Requests declaring a Content-Length larger than the maximum are rejected with 413 before any of their body is
+// read.  The bodies of the remaining requests (including chunked requests of unknown length) are streamed to the
+// next handler through a reader failing with ErrTooLarge once they exceed the maximum, so that no handler ever
+// buffers more than the maximum, and the failure response of the next handler is replaced with a 413 (closing the
+// connection rather than reading the rest of the oversized body).
+//
+type Limit struct {
+	logger      *zap.Logger
+	maxBodySize int64
+}
+
+// Validate The Specified Maximum Body Size (nil Being The Default)
+func ValidateMaxBodySize(maxBodySize *resource.Quantity) error {
+	if maxBodySize != nil && maxBodySize.Sign() < 0 {
+		return fmt.Errorf("invalid maxBodySize %s: must be >= 0", maxBodySize.String())
+	}
+	return nil
+}
+
+// Limit Constructor - Using The Default If The Maximum Body Size Is Not Positive (Assumes A Valid Size)
+func NewLimit(logger *zap.Logger, maxBodySize *resource.Quantity) *Limit {
+	limit := &Limit{logger: logger, maxBodySize: DefaultMaxBodySize}
+	if maxBodySize != nil && maxBodySize.Sign() > 0 {
+		limit.maxBodySize = maxBodySize.Value()
+	}
+	return limit
+}
+
+// Get The Maximum Request Body Size In Bytes
+func (l *Limit) MaxBodySize() int64 {
+	return l.maxBodySize
+}
+
+// Wrap The Specified Handler To Reject Requests Whose Body Exceeds The Maximum Size With 413
+func (l *Limit) Handler(next nethttp.Handler) nethttp.Handler {
+	return nethttp.HandlerFunc(func(response nethttp.ResponseWriter, request *nethttp.Request) {
+
+		// Reject Requests Declaring A Larger Body Early
+		if request.ContentLength > l.maxBodySize {
+			l.reject(response, request.ContentLength)
+			return
+		}
+
+		// Stream The Body With The Limit, Replacing The Next Handler's Response If It Was Exceeded
+		if request.Body == nil || request.Body == nethttp.NoBody {
+			next.ServeHTTP(response, request)
+			return
+		}
+		body := &limitedBody{body: request.Body, remaining: l.maxBodySize}
+		request.Body = body
+		next.ServeHTTP(&limitedResponseWriter{ResponseWriter: response, limit: l, body: body}, request)
+	})
+}
+
+// Write The Problem Details Of A Request Whose Body Exceeds The Maximum Size (-1 If Its Size Is Unknown)
+func (l *Limit) reject(response nethttp.ResponseWriter, contentLength int64) {
+	l.logger.Info("Rejecting Request Exceeding The Maximum Body Size", zap.Int64("ContentLength", contentLength), zap.Int64("MaxBodySize", l.maxBodySize))
+	response.Header().Set("Connection", "close")
+	validation.WriteProblem(response, &validation.Problem{
+		Type:   RequestTooLargeType,
+		Title:  "Request Too Large",
+		Status: nethttp.StatusRequestEntityTooLarge,
+		Detail: fmt.Sprintf("the request body exceeds the maximum of %d bytes", l.maxBodySize),
+	})
+}
+
+// A Request Body Failing With ErrTooLarge Once More Than The Remaining Bytes Are Read
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	exceeded  bool
+}
+
+// Read From The Body (Reading One More Byte Than Remains To Detect Bodies Exceeding The Limit)
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, ErrTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = 0
+		b.exceeded = true
+		return n, ErrTooLarge
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// Close The Body
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// A ResponseWriter Replacing The Response With 413 If The Request Body Exceeded The Limit Before It Was Written
+type limitedResponseWriter struct {
+	nethttp.ResponseWriter
+	limit    *Limit
+	body     *limitedBody
+	written  bool
+	rejected bool
+}
+
+// Write The Status Code, Or The 413 Problem Details Instead Of An Error Once The Body Exceeded The Limit
+func (w *limitedResponseWriter) WriteHeader(statusCode int) {
+	if w.written {
+		return
+	}
+	w.written = true
+	if w.body.exceeded && statusCode >= nethttp.StatusBadRequest {
+		w.rejected = true
+		for header := range w.ResponseWriter.Header() {
+			w.ResponseWriter.Header().Del(header)
+		}
+		w.limit.reject(w.ResponseWriter, -1)
+		return
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write The Response Body (Discarded If Replaced By The 413 Problem Details)
+func (w *limitedResponseWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(nethttp.StatusOK)
+	}
+	if w.rejected {
+		return len(p), nil
+	}
+	return w.ResponseWriter.Write(p)
+}
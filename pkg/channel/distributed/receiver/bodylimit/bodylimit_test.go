@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bodylimit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/validation"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ValidateMaxBodySize() Functionality
+func TestValidateMaxBodySize(t *testing.T) {
+	assert.Nil(t, ValidateMaxBodySize(nil))
+	assert.Nil(t, ValidateMaxBodySize(resource.NewQuantity(0, resource.BinarySI)))
+	assert.Nil(t, ValidateMaxBodySize(resourceQuantity("1Mi")))
+	assert.NotNil(t, ValidateMaxBodySize(resourceQuantity("-1Mi")))
+}
+
+// Test The NewLimit() Functionality
+func TestNewLimit(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Equal(t, int64(DefaultMaxBodySize), NewLimit(logger, nil).MaxBodySize())
+	assert.Equal(t, int64(DefaultMaxBodySize), NewLimit(logger, resource.NewQuantity(0, resource.BinarySI)).MaxBodySize())
+	assert.Equal(t, int64(1048576), NewLimit(logger, resourceQuantity("1Mi")).MaxBodySize())
+}
+
+// Test The Limit's Handler Functionality
+func TestLimitHandler(t *testing.T) {
+
+	// A Handler Reading The Whole Body (Failing With 400 As The Validator Does), Then Accepting The Request
+	var readBody string
+	var readErr error
+	next := nethttp.HandlerFunc(func(response nethttp.ResponseWriter, request *nethttp.Request) {
+		body, err := ioutil.ReadAll(request.Body)
+		readBody, readErr = string(body), err
+		if err != nil {
+			response.WriteHeader(nethttp.StatusBadRequest)
+			_, _ = response.Write([]byte("failed to read the body"))
+			return
+		}
+		response.Header().Set("Location", "/accepted")
+		response.WriteHeader(nethttp.StatusAccepted)
+		_, _ = response.Write([]byte("accepted"))
+	})
+	handler := NewLimit(logtesting.TestLogger(t).Desugar(), resourceQuantity("8")).Handler(next)
+
+	// Define The TestCases
+	testCases := []struct {
+		name           string
+		body           string
+		contentLength  int64
+		wantStatusCode int
+		wantBody       string
+		wantErr        error
+	}{
+		{name: "Within The Limit", body: "12345678", contentLength: 8, wantStatusCode: nethttp.StatusAccepted, wantBody: "12345678"},
+		{name: "Unknown Length Within The Limit", body: "1234", contentLength: -1, wantStatusCode: nethttp.StatusAccepted, wantBody: "1234"},
+		{name: "Empty", body: "", contentLength: 0, wantStatusCode: nethttp.StatusAccepted, wantBody: ""},
+		{name: "Declared Length Exceeding The Limit", body: "123456789", contentLength: 9, wantStatusCode: nethttp.StatusRequestEntityTooLarge, wantBody: "unread"}, // Rejected Before Reading The Body
+		{name: "Unknown Length Exceeding The Limit", body: "123456789", contentLength: -1, wantStatusCode: nethttp.StatusRequestEntityTooLarge, wantBody: "12345678", wantErr: ErrTooLarge},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			readBody, readErr = "unread", nil
+			request := httptest.NewRequest(nethttp.MethodPost, "/", ioutil.NopCloser(iotest.OneByteReader(strings.NewReader(testCase.body))))
+			request.ContentLength = testCase.contentLength
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			assert.Equal(t, testCase.wantStatusCode, response.Code)
+			assert.Equal(t, testCase.wantBody, readBody)
+			assert.Equal(t, testCase.wantErr, readErr)
+			if testCase.wantStatusCode == nethttp.StatusRequestEntityTooLarge {
+				assert.Equal(t, validation.ProblemContentType, response.Header().Get("Content-Type"))
+				assert.Equal(t, "close", response.Header().Get("Connection"))
+				assert.Empty(t, response.Header().Get("Location"))
+				problem := &validation.Problem{}
+				assert.Nil(t, json.Unmarshal(response.Body.Bytes(), problem))
+				assert.Equal(t, RequestTooLargeType, problem.Type)
+				assert.Equal(t, nethttp.StatusRequestEntityTooLarge, problem.Status)
+			} else {
+				assert.Equal(t, "/accepted", response.Header().Get("Location"))
+				assert.Equal(t, "accepted", response.Body.String())
+			}
+		})
+	}
+}
+
+// Test That Bodies Exceeding The Limit Never Return More Than The Limit, However They Are Read
+func TestLimitedBody(t *testing.T) {
+	body := &limitedBody{body: ioutil.NopCloser(strings.NewReader("1234567890")), remaining: 4}
+	buffer := make([]byte, 100)
+	n, err := body.Read(buffer)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, ErrTooLarge, err)
+	n, err = body.Read(buffer)
+	assert.Zero(t, n)
+	assert.Equal(t, ErrTooLarge, err)
+	assert.Nil(t, body.Close())
+
+	// Bodies Of Exactly The Limit Are Read Completely
+	body = &limitedBody{body: ioutil.NopCloser(strings.NewReader("1234")), remaining: 4}
+	data, err := ioutil.ReadAll(body)
+	assert.Nil(t, err)
+	assert.Equal(t, "1234", string(data))
+}
+
+// Utility Function For Parsing A Resource Quantity
+func resourceQuantity(value string) *resource.Quantity {
+	quantity := resource.MustParse(value)
+	return &quantity
+}
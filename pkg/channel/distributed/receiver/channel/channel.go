@@ -19,11 +19,19 @@ package channel
 import (
 	"context"
 	"errors"
+	"sort"
 
 	"go.uber.org/zap"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	k8sclientcmd "k8s.io/client-go/tools/clientcmd"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/middleware"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/health"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/keytemplate"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/status"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/util"
 	kafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	kafkainformers "knative.dev/eventing-kafka/pkg/client/informers/externalversions"
 	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
@@ -87,6 +95,11 @@ func InitializeKafkaChannelLister(ctx context.Context, serverUrl string, kubecon
 	return nil
 }
 
+// Get The KafkaChannel Lister Singleton (nil Until Initialized)
+func GetKafkaChannelLister() kafkalisters.KafkaChannelLister {
+	return kafkaChannelLister
+}
+
 // Validate The Specified ChannelReference Is For A Valid (Existing / READY) KafkaChannel
 func ValidateKafkaChannel(channelReference eventingChannel.ChannelReference) error {
 
@@ -122,6 +135,118 @@ func ValidateKafkaChannel(channelReference eventingChannel.ChannelReference) err
 	return nil
 }
 
+// Get The EventTypeRouting Of The Specified KafkaChannel (nil If Not Enabled)
+func GetEventTypeRouting(channelReference eventingChannel.ChannelReference) (*routing.EventTypeRouting, error) {
+
+	// Attempt To Get The KafkaChannel From The KafkaChannel Lister
+	kafkaChannel, err := kafkaChannelLister.KafkaChannels(channelReference.Namespace).Get(channelReference.Name)
+	if err != nil {
+		logger.Error("Failed To Find KafkaChannel For EventTypeRouting", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The EventTypeRouting From The KafkaChannel's Annotations
+	eventTypeRouting, err := routing.NewEventTypeRouting(kafkaChannel.Annotations)
+	if err != nil {
+		logger.Error("Invalid KafkaChannel EventTypeRouting Annotations", zap.Error(err))
+		return nil, err
+	}
+	return eventTypeRouting, nil
+}
+
+// Get The Record KeyTemplate Of The Specified KafkaChannel (nil If Not Annotated)
+func GetKeyTemplate(channelReference eventingChannel.ChannelReference) (*keytemplate.KeyTemplate, error) {
+
+	// Attempt To Get The KafkaChannel From The KafkaChannel Lister
+	kafkaChannel, err := kafkaChannelLister.KafkaChannels(channelReference.Namespace).Get(channelReference.Name)
+	if err != nil {
+		logger.Error("Failed To Find KafkaChannel For KeyTemplate", zap.Error(err))
+		return nil, err
+	}
+
+	// Parse The KeyTemplate From The KafkaChannel's Annotations
+	keyTemplate, err := keytemplate.NewKeyTemplate(kafkaChannel.Annotations)
+	if err != nil {
+		logger.Error("Invalid KafkaChannel KeyTemplate Annotation", zap.Error(err))
+		return nil, err
+	}
+	return keyTemplate, nil
+}
+
+// Get The Names Of The Middleware Modules Selected By The Specified KafkaChannel (nil If Not Annotated)
+func GetMiddleware(channelReference eventingChannel.ChannelReference) ([]string, error) {
+
+	// Attempt To Get The KafkaChannel From The KafkaChannel Lister
+	kafkaChannel, err := kafkaChannelLister.KafkaChannels(channelReference.Namespace).Get(channelReference.Name)
+	if err != nil {
+		logger.Error("Failed To Find KafkaChannel For Middleware", zap.Error(err))
+		return nil, err
+	}
+	return middleware.ChannelMiddleware(kafkaChannel.Annotations), nil
+}
+
+// Get The Topic Name Of The Specified KafkaChannel (Which May Be Overridden By The Topic Annotation)
+func GetTopicName(channelReference eventingChannel.ChannelReference) (string, error) {
+
+	// Attempt To Get The KafkaChannel From The KafkaChannel Lister
+	kafkaChannel, err := kafkaChannelLister.KafkaChannels(channelReference.Namespace).Get(channelReference.Name)
+	if err != nil {
+		logger.Error("Failed To Find KafkaChannel For TopicName", zap.Error(err))
+		return "", err
+	}
+	return topicName(kafkaChannel), nil
+}
+
+// Get The Topic Name Of The Specified KafkaChannel (The Annotated Topic If Any, Otherwise Derived From Its Name)
+func topicName(kafkaChannel *kafkav1beta1.KafkaChannel) string {
+	if topicName := kafkaChannel.Annotations[kafkav1beta1.TopicAnnotation]; topicName != "" {
+		return topicName
+	}
+	return util.TopicName(eventingChannel.ChannelReference{Namespace: kafkaChannel.Namespace, Name: kafkaChannel.Name})
+}
+
+// List The KafkaChannels Served By The Receiver & Their Topics (Sorted By Namespace & Name)
+func ListChannels() ([]status.ChannelStatus, error) {
+
+	// Validate The KafkaChannel Lister (Must Be Initialized)
+	if kafkaChannelLister == nil {
+		return nil, errors.New("uninitialized kafkachannel lister")
+	}
+
+	// List All KafkaChannels From The KafkaChannel Lister
+	kafkaChannels, err := kafkaChannelLister.List(labels.Everything())
+	if err != nil {
+		logger.Error("Failed To List KafkaChannels", zap.Error(err))
+		return nil, err
+	}
+
+	// Describe Each KafkaChannel & Its Topic
+	channels := make([]status.ChannelStatus, 0, len(kafkaChannels))
+	for _, kafkaChannel := range kafkaChannels {
+		channels = append(channels, status.ChannelStatus{
+			Namespace: kafkaChannel.Namespace,
+			Name:      kafkaChannel.Name,
+			Topic:     topicName(kafkaChannel),
+		})
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Namespace+"/"+channels[i].Name < channels[j].Namespace+"/"+channels[j].Name
+	})
+	return channels, nil
+}
+
+// Determine Whether The Specified KafkaChannel's Topic Is Compacted
+func IsCompacted(channelReference eventingChannel.ChannelReference) (bool, error) {
+
+	// Attempt To Get The KafkaChannel From The KafkaChannel Lister
+	kafkaChannel, err := kafkaChannelLister.KafkaChannels(channelReference.Namespace).Get(channelReference.Name)
+	if err != nil {
+		logger.Error("Failed To Find KafkaChannel For CleanupPolicy", zap.Error(err))
+		return false, err
+	}
+	return kafkaChannel.Spec.CleanupPolicy == kafkav1beta1.CleanupPolicyCompact, nil
+}
+
 // Close The Channel Lister (Stop Processing)
 func Close() {
 	if stopChan != nil {
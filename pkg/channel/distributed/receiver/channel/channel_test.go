@@ -22,10 +22,15 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	channelhealth "knative.dev/eventing-kafka/pkg/channel/distributed/receiver/health"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/status"
 	receivertesting "knative.dev/eventing-kafka/pkg/channel/distributed/receiver/testing"
 	kafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	fakeclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned/fake"
+	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
 	"knative.dev/pkg/logging"
 	logtesting "knative.dev/pkg/logging/testing"
 )
@@ -107,3 +112,280 @@ func TestClose(t *testing.T) {
 	// Verify stopChan Was Closed
 	assert.False(t, ok)
 }
+
+// Test The GetEventTypeRouting() Functionality
+func TestGetEventTypeRouting(t *testing.T) {
+
+	// Set The Package Level Logger To A Test Logger
+	logger = logtesting.TestLogger(t).Desugar()
+
+	// Test Data
+	channelReference := receivertesting.CreateChannelReference("TestChannelName", "TestChannelNamespace")
+	topicName := channelReference.Namespace + "." + channelReference.Name
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		exists      bool
+		annotations map[string]string
+		wantTopics  []string
+		wantErr     bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Not Found", exists: false, wantErr: true},
+		{name: "Routing Disabled", exists: true},
+		{name: "Routing Enabled", exists: true, annotations: map[string]string{constants.EventTypesAnnotation: "com.example.a"}, wantTopics: []string{topicName + ".com.example.a"}},
+		{name: "Invalid Routing", exists: true, annotations: map[string]string{constants.EventTypesAnnotation: "com.example.a", constants.SubscriberEventTypesAnnotation: "invalid"}, wantErr: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The Package Level KafkaChannel Lister With An Indexer Containing The KafkaChannel
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if testCase.exists {
+				kafkaChannel := receivertesting.CreateKafkaChannel(channelReference.Name, channelReference.Namespace, corev1.ConditionTrue)
+				kafkaChannel.Annotations = testCase.annotations
+				assert.Nil(t, indexer.Add(kafkaChannel))
+			}
+			kafkaChannelLister = kafkalisters.NewKafkaChannelLister(indexer)
+
+			// Perform The Test
+			eventTypeRouting, err := GetEventTypeRouting(channelReference)
+
+			// Verify The Results
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.wantTopics, eventTypeRouting.Topics(topicName))
+		})
+	}
+}
+
+// Test The GetKeyTemplate() Functionality
+func TestGetKeyTemplate(t *testing.T) {
+
+	// Set The Package Level Logger To A Test Logger
+	logger = logtesting.TestLogger(t).Desugar()
+
+	// Test Data
+	channelReference := receivertesting.CreateChannelReference("TestChannelName", "TestChannelNamespace")
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		exists      bool
+		annotations map[string]string
+		wantNil     bool
+		wantErr     bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Not Found", exists: false, wantNil: true, wantErr: true},
+		{name: "Not Annotated", exists: true, wantNil: true},
+		{name: "Annotated", exists: true, annotations: map[string]string{constants.KeyTemplateAnnotation: "{{.Subject}}"}},
+		{name: "Invalid Template", exists: true, annotations: map[string]string{constants.KeyTemplateAnnotation: "{{.Subject"}, wantNil: true, wantErr: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The Package Level KafkaChannel Lister With An Indexer Containing The KafkaChannel
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if testCase.exists {
+				kafkaChannel := receivertesting.CreateKafkaChannel(channelReference.Name, channelReference.Namespace, corev1.ConditionTrue)
+				kafkaChannel.Annotations = testCase.annotations
+				assert.Nil(t, indexer.Add(kafkaChannel))
+			}
+			kafkaChannelLister = kafkalisters.NewKafkaChannelLister(indexer)
+
+			// Perform The Test
+			keyTemplate, err := GetKeyTemplate(channelReference)
+
+			// Verify The Results
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.wantNil, keyTemplate == nil)
+		})
+	}
+}
+
+// Test The GetMiddleware() Functionality
+func TestGetMiddleware(t *testing.T) {
+
+	// Set The Package Level Logger To A Test Logger
+	logger = logtesting.TestLogger(t).Desugar()
+
+	// Test Data
+	channelReference := receivertesting.CreateChannelReference("TestChannelName", "TestChannelNamespace")
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		exists      bool
+		annotations map[string]string
+		wantNames   []string
+		wantErr     bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Not Found", exists: false, wantErr: true},
+		{name: "Not Annotated", exists: true},
+		{name: "Annotated", exists: true, annotations: map[string]string{constants.MiddlewareAnnotation: "enrich,validate"}, wantNames: []string{"enrich", "validate"}},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The Package Level KafkaChannel Lister With An Indexer Containing The KafkaChannel
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if testCase.exists {
+				kafkaChannel := receivertesting.CreateKafkaChannel(channelReference.Name, channelReference.Namespace, corev1.ConditionTrue)
+				kafkaChannel.Annotations = testCase.annotations
+				assert.Nil(t, indexer.Add(kafkaChannel))
+			}
+			kafkaChannelLister = kafkalisters.NewKafkaChannelLister(indexer)
+
+			// Perform The Test
+			names, err := GetMiddleware(channelReference)
+
+			// Verify The Results
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.wantNames, names)
+		})
+	}
+}
+
+// Test The GetTopicName() Functionality
+func TestGetTopicName(t *testing.T) {
+
+	// Set The Package Level Logger To A Test Logger
+	logger = logtesting.TestLogger(t).Desugar()
+
+	// Test Data
+	channelReference := receivertesting.CreateChannelReference("TestChannelName", "TestChannelNamespace")
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name          string
+		exists        bool
+		topic         string
+		wantTopicName string
+		wantErr       bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Not Found", exists: false, wantErr: true},
+		{name: "Default Topic", exists: true, wantTopicName: "TestChannelNamespace.TestChannelName"},
+		{name: "Annotated Topic", exists: true, topic: "migrated-topic", wantTopicName: "migrated-topic"},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The Package Level KafkaChannel Lister With An Indexer Containing The KafkaChannel
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if testCase.exists {
+				kafkaChannel := receivertesting.CreateKafkaChannel(channelReference.Name, channelReference.Namespace, corev1.ConditionTrue)
+				if testCase.topic != "" {
+					kafkaChannel.Annotations = map[string]string{kafkav1beta1.TopicAnnotation: testCase.topic}
+				}
+				assert.Nil(t, indexer.Add(kafkaChannel))
+			}
+			kafkaChannelLister = kafkalisters.NewKafkaChannelLister(indexer)
+
+			// Perform The Test
+			topicName, err := GetTopicName(channelReference)
+
+			// Verify The Results
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.wantTopicName, topicName)
+		})
+	}
+}
+
+// Test The ListChannels() Functionality
+func TestListChannels(t *testing.T) {
+
+	// Set The Package Level Logger To A Test Logger
+	logger = logtesting.TestLogger(t).Desugar()
+
+	// An Uninitialized KafkaChannel Lister Is An Error
+	kafkaChannelLister = nil
+	channels, err := ListChannels()
+	assert.NotNil(t, err)
+	assert.Nil(t, channels)
+
+	// Mock The Package Level KafkaChannel Lister With An Indexer Containing Two KafkaChannels
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	annotatedKafkaChannel := receivertesting.CreateKafkaChannel("TestChannelName2", "TestChannelNamespace", corev1.ConditionTrue)
+	annotatedKafkaChannel.Annotations = map[string]string{kafkav1beta1.TopicAnnotation: "migrated-topic"}
+	assert.Nil(t, indexer.Add(annotatedKafkaChannel))
+	assert.Nil(t, indexer.Add(receivertesting.CreateKafkaChannel("TestChannelName1", "TestChannelNamespace", corev1.ConditionFalse)))
+	kafkaChannelLister = kafkalisters.NewKafkaChannelLister(indexer)
+
+	// Perform The Test
+	channels, err = ListChannels()
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Equal(t, []status.ChannelStatus{
+		{Namespace: "TestChannelNamespace", Name: "TestChannelName1", Topic: "TestChannelNamespace.TestChannelName1"},
+		{Namespace: "TestChannelNamespace", Name: "TestChannelName2", Topic: "migrated-topic"},
+	}, channels)
+}
+
+// Test The IsCompacted() Functionality
+func TestIsCompacted(t *testing.T) {
+
+	// Set The Package Level Logger To A Test Logger
+	logger = logtesting.TestLogger(t).Desugar()
+
+	// Test Data
+	channelReference := receivertesting.CreateChannelReference("TestChannelName", "TestChannelNamespace")
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name          string
+		exists        bool
+		cleanupPolicy string
+		wantCompacted bool
+		wantErr       bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Not Found", exists: false, wantErr: true},
+		{name: "Default CleanupPolicy", exists: true},
+		{name: "Delete CleanupPolicy", exists: true, cleanupPolicy: kafkav1beta1.CleanupPolicyDelete},
+		{name: "Compact CleanupPolicy", exists: true, cleanupPolicy: kafkav1beta1.CleanupPolicyCompact, wantCompacted: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Mock The Package Level KafkaChannel Lister With An Indexer Containing The KafkaChannel
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if testCase.exists {
+				kafkaChannel := receivertesting.CreateKafkaChannel(channelReference.Name, channelReference.Namespace, corev1.ConditionTrue)
+				kafkaChannel.Spec.CleanupPolicy = testCase.cleanupPolicy
+				assert.Nil(t, indexer.Add(kafkaChannel))
+			}
+			kafkaChannelLister = kafkalisters.NewKafkaChannelLister(indexer)
+
+			// Perform The Test
+			compacted, err := IsCompacted(channelReference)
+
+			// Verify The Results
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.wantCompacted, compacted)
+		})
+	}
+}
@@ -24,6 +24,9 @@ const (
 
 	MetricsInterval = 5 * time.Second
 
+	// The Port Of The Receiver's HTTP Server (Same As The Knative Eventing MessageReceiver)
+	HttpPort = 8080
+
 	ExtensionKeyPartitionKey = "partitionkey"
 
 	KafkaHeaderKeyContentType = "content-type"
@@ -42,6 +42,13 @@ type Environment struct {
 	// Kafka Authorization
 	KafkaUsername string // Optional
 	KafkaPassword string // Optional
+
+	// Schema Registry Authorization (The Credentials Required By The Configured Auth Mode)
+	SchemaRegistryUsername     string // Optional
+	SchemaRegistryPassword     string // Optional
+	SchemaRegistryToken        string // Optional
+	SchemaRegistryClientId     string // Optional
+	SchemaRegistryClientSecret string // Optional
 }
 
 // Get The Environment
@@ -101,10 +108,19 @@ func GetEnvironment(logger *zap.Logger) (*Environment, error) {
 	// Get The Optional KafkaPassword Config Value
 	environment.KafkaPassword = env.GetOptionalConfigValue(logger, env.KafkaPasswordEnvVarKey, "")
 
-	// Clone The Environment & Mask The Password For Safe Logging
+	// Get The Optional Schema Registry Credentials
+	environment.SchemaRegistryUsername = env.GetOptionalConfigValue(logger, env.SchemaRegistryUsernameEnvVarKey, "")
+	environment.SchemaRegistryPassword = env.GetOptionalConfigValue(logger, env.SchemaRegistryPasswordEnvVarKey, "")
+	environment.SchemaRegistryToken = env.GetOptionalConfigValue(logger, env.SchemaRegistryTokenEnvVarKey, "")
+	environment.SchemaRegistryClientId = env.GetOptionalConfigValue(logger, env.SchemaRegistryClientIdEnvVarKey, "")
+	environment.SchemaRegistryClientSecret = env.GetOptionalConfigValue(logger, env.SchemaRegistryClientSecretEnvVarKey, "")
+
+	// Clone The Environment & Mask The Passwords / Secrets For Safe Logging
 	safeEnvironment := *environment
-	if len(safeEnvironment.KafkaPassword) > 0 {
-		safeEnvironment.KafkaPassword = "*************"
+	for _, secret := range []*string{&safeEnvironment.KafkaPassword, &safeEnvironment.SchemaRegistryPassword, &safeEnvironment.SchemaRegistryToken, &safeEnvironment.SchemaRegistryClientSecret} {
+		if len(*secret) > 0 {
+			*secret = "*************"
+		}
 	}
 
 	// Log The Receiver Configuration Loaded From Environment Variables
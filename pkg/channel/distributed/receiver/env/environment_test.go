@@ -152,6 +152,32 @@ func TestGetEnvironment(t *testing.T) {
 	}
 }
 
+// Test The Optional Schema Registry Credentials Of The GetEnvironment() Functionality
+func TestGetEnvironmentSchemaRegistryCredentials(t *testing.T) {
+	testCase := getValidTestCase("Schema Registry Credentials")
+	os.Clearenv()
+	assertSetenv(t, env.MetricsDomainEnvVarKey, testCase.metricsDomain)
+	assertSetenv(t, env.MetricsPortEnvVarKey, testCase.metricsPort)
+	assertSetenv(t, env.HealthPortEnvVarKey, testCase.healthPort)
+	assertSetenv(t, env.KafkaBrokerEnvVarKey, testCase.kafkaBrokers)
+	assertSetenv(t, env.ServiceNameEnvVarKey, testCase.serviceName)
+	assertSetenv(t, env.PodNameEnvVarKey, testCase.podName)
+	assertSetenv(t, env.ContainerNameEnvVarKEy, testCase.containerName)
+	assertSetenv(t, env.SchemaRegistryUsernameEnvVarKey, "TestRegistryUsername")
+	assertSetenv(t, env.SchemaRegistryPasswordEnvVarKey, "TestRegistryPassword")
+	assertSetenv(t, env.SchemaRegistryTokenEnvVarKey, "TestRegistryToken")
+	assertSetenv(t, env.SchemaRegistryClientIdEnvVarKey, "TestRegistryClientId")
+	assertSetenv(t, env.SchemaRegistryClientSecretEnvVarKey, "TestRegistryClientSecret")
+
+	environment, err := GetEnvironment(getLogger())
+	assert.Nil(t, err)
+	assert.Equal(t, "TestRegistryUsername", environment.SchemaRegistryUsername)
+	assert.Equal(t, "TestRegistryPassword", environment.SchemaRegistryPassword)
+	assert.Equal(t, "TestRegistryToken", environment.SchemaRegistryToken)
+	assert.Equal(t, "TestRegistryClientId", environment.SchemaRegistryClientId)
+	assert.Equal(t, "TestRegistryClientSecret", environment.SchemaRegistryClientSecret)
+}
+
 func assertSetenv(t *testing.T, envKey string, value string) {
 	assert.Nil(t, os.Setenv(envKey, value))
 }
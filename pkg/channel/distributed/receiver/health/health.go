@@ -30,10 +30,12 @@ type Server struct {
 	// Additional Synchronization Mutexes
 	producerMutex sync.Mutex // Synchronizes access to the producerReady flag
 	channelMutex  sync.Mutex // Synchronizes access to the channelReady flag
+	drainMutex    sync.Mutex // Synchronizes access to the draining flag
 
 	// Additional Internal Flags
 	producerReady bool // A flag that the producer sets when it is ready
 	channelReady  bool // A flag that the channel sets when it is ready
+	draining      bool // A flag that the HTTP server sets when it starts draining (overrides readiness)
 }
 
 // Creates A New Server With Specified Configuration
@@ -59,6 +61,13 @@ func (chs *Server) SetChannelReady(isReady bool) {
 	chs.channelMutex.Unlock()
 }
 
+// Synchronized Function To Set Draining Flag (Reporting Not Ready Regardless Of The Producer & Channel)
+func (chs *Server) SetDraining(isDraining bool) {
+	chs.drainMutex.Lock()
+	chs.draining = isDraining
+	chs.drainMutex.Unlock()
+}
+
 // Set All Liveness And Readiness Flags To False
 func (chs *Server) Shutdown() {
 	chs.Server.Shutdown()
@@ -76,11 +85,18 @@ func (chs *Server) ChannelReady() bool {
 	return chs.channelReady
 }
 
+// Access Function For Draining Flag
+func (chs *Server) Draining() bool {
+	chs.drainMutex.Lock()
+	defer chs.drainMutex.Unlock()
+	return chs.draining
+}
+
 // Functions That Implement The HealthInterface
 
 // Response Function For Readiness Requests (/healthy)
 func (chs *Server) Ready() bool {
-	return chs.producerReady && chs.channelReady
+	return chs.producerReady && chs.channelReady && !chs.Draining()
 }
 
 // Response Function For Liveness Requests (/healthz)
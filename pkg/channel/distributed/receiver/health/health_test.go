@@ -90,6 +90,13 @@ func TestChannelHealthHandler(t *testing.T) {
 	chs.SetChannelReady(true)
 	chs.SetProducerReady(true)
 	getEventToHandler(t, chs.HandleReadiness, readinessPath, http.StatusOK)
+
+	// Verify that draining sets the readiness status to false regardless of the other flags
+	chs.SetDraining(true)
+	assert.True(t, chs.Draining())
+	getEventToHandler(t, chs.HandleReadiness, readinessPath, http.StatusInternalServerError)
+	chs.SetDraining(false)
+	getEventToHandler(t, chs.HandleReadiness, readinessPath, http.StatusOK)
 }
 
 //
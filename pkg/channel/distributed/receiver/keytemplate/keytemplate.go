@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keytemplate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"text/template"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Cache Of The Parsed KeyTemplates By Template Text (The Annotation Is Read For Every Event)
+var keyTemplates sync.Map
+
+//
+// Record Key Template Of A Single KafkaChannel
+//
+// The receiver otherwise keys the Kafka records by the CloudEvent's partitionkey extension (if any), or by its
+// subject for compacted topics.  When a KafkaChannel is annotated with a Go template (constants.KeyTemplateAnnotation)
+// the record key is instead rendered from the CloudEvent (e.g. "{{.Extensions.tenantid}}-{{.Subject}}"), so that
+// the ordering domains and compaction keys can be derived from existing attributes without changing the producers.
+// Referencing a missing extension is an error.  A nil *KeyTemplate is valid and represents the default keying.
+//
+type KeyTemplate struct {
+	template *template.Template
+}
+
+// Create The KeyTemplate From The Specified KafkaChannel Annotations (nil If Not Annotated)
+func NewKeyTemplate(annotations map[string]string) (*KeyTemplate, error) {
+	text := annotations[constants.KeyTemplateAnnotation]
+	if len(text) == 0 {
+		return nil, nil
+	}
+	if keyTemplate, ok := keyTemplates.Load(text); ok {
+		return keyTemplate.(*KeyTemplate), nil
+	}
+	parsedTemplate, err := template.New("key").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", constants.KeyTemplateAnnotation, err)
+	}
+	keyTemplate, _ := keyTemplates.LoadOrStore(text, &KeyTemplate{template: parsedTemplate})
+	return keyTemplate.(*KeyTemplate), nil
+}
+
+// Render The Record Key Of The Specified Message (Which Is Returned As An Event Message Along With Any Remaining Transformers)
+func (k *KeyTemplate) RecordKey(ctx context.Context, message binding.Message, transformers []binding.Transformer) (binding.Message, []binding.Transformer, string, error) {
+
+	// Convert The Message To An Event (Applying Transformers) Unless It Already Is One
+	var keyEvent *event.Event
+	if eventMessage, ok := message.(*binding.EventMessage); ok && len(transformers) == 0 {
+		keyEvent = (*event.Event)(eventMessage)
+	} else {
+		var err error
+		keyEvent, err = binding.ToEvent(ctx, message, transformers...)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		message, transformers = binding.ToMessage(keyEvent), nil
+	}
+
+	// Render The Template Against The Event
+	var key bytes.Buffer
+	if err := k.template.Execute(&key, keyEvent); err != nil {
+		return message, transformers, "", fmt.Errorf("failed to render record key template: %w", err)
+	}
+	return message, transformers, key.String(), nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keytemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+)
+
+// Test The NewKeyTemplate() Functionality
+func TestNewKeyTemplate(t *testing.T) {
+
+	// Templates Are Only Created For Annotated KafkaChannels
+	keyTemplate, err := NewKeyTemplate(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, keyTemplate)
+
+	// Parsed Templates Are Cached
+	keyTemplate, err = NewKeyTemplate(map[string]string{constants.KeyTemplateAnnotation: "{{.Subject}}"})
+	assert.Nil(t, err)
+	assert.NotNil(t, keyTemplate)
+	cachedKeyTemplate, err := NewKeyTemplate(map[string]string{constants.KeyTemplateAnnotation: "{{.Subject}}"})
+	assert.Nil(t, err)
+	assert.Same(t, keyTemplate, cachedKeyTemplate)
+
+	// Invalid Templates Are Rejected
+	keyTemplate, err = NewKeyTemplate(map[string]string{constants.KeyTemplateAnnotation: "{{.Subject"})
+	assert.NotNil(t, err)
+	assert.Nil(t, keyTemplate)
+}
+
+// Test The KeyTemplate's RecordKey() Functionality
+func TestKeyTemplateRecordKey(t *testing.T) {
+
+	// Test Data
+	testEvent := event.New()
+	testEvent.SetID("TestId")
+	testEvent.SetType("TestType")
+	testEvent.SetSource("TestSource")
+	testEvent.SetSubject("TestSubject")
+	testEvent.SetExtension("tenantid", "TestTenant")
+	keyTemplate, err := NewKeyTemplate(map[string]string{constants.KeyTemplateAnnotation: "{{.Extensions.tenantid}}-{{.Subject}}-{{.Extensions.region}}"})
+	assert.Nil(t, err)
+
+	// Extensions Added By Transformers Are Rendered (The Returned Message Includes Them)
+	transformedEvent := testEvent.Clone()
+	message, transformers, key, err := keyTemplate.RecordKey(context.TODO(), binding.ToMessage(&transformedEvent), []binding.Transformer{transformer.AddExtension("region", "eu")})
+	assert.Nil(t, err)
+	assert.Empty(t, transformers)
+	assert.Equal(t, "TestTenant-TestSubject-eu", key)
+	keyEvent, err := binding.ToEvent(context.TODO(), message)
+	assert.Nil(t, err)
+	assert.Equal(t, "eu", keyEvent.Extensions()["region"])
+
+	// Missing Extensions Are An Error
+	_, _, _, err = keyTemplate.RecordKey(context.TODO(), binding.ToMessage(&testEvent), nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"errors"
+	"net"
+	nethttp "net/http"
+	"strings"
+)
+
+// The Headers Identifying The Caller When Set By A Trusted Proxy (e.g. A Service Mesh Sidecar)
+const (
+	ForwardedForHeader        = "X-Forwarded-For"
+	ForwardedClientCertHeader = "X-Forwarded-Client-Cert"
+)
+
+// The Prefixes Of The Kubernetes ServiceAccount Token Subjects & SPIFFE IDs Identifying The Caller's Namespace
+const (
+	serviceAccountSubjectPrefix = "system:serviceaccount:"
+	spiffeScheme                = "spiffe"
+)
+
+// The Error Authenticating A Caller Without The Required Bearer Token (Rejected With A 401)
+var ErrTokenRequired = errors.New("a bearer token is required")
+
+//
+// The Identity Of The Caller Sending An Event
+//
+// The SourceIP is the remote address of the request, or the address appended to the X-Forwarded-For header by a
+// trusted proxy.  The CertSubjects are the common name, DNS & URI SANs of the client certificate (presented to the
+// receiver or forwarded by a trusted proxy in its X-Forwarded-Client-Cert header) and the Claims are those of the
+// verified bearer token (nil without one).  The Namespace is that of the ServiceAccount of the token's subject
+// or the certificate's SPIFFE ID (empty if neither identifies it).
+//
+type Identity struct {
+	SourceIP     net.IP
+	CertSubjects []string
+	Claims       map[string]interface{}
+	Namespace    string
+}
+
+// Identify The Caller Of The Specified Request (Returning An Error If Its Token Is Missing Or Invalid)
+func identify(request *nethttp.Request, trustForwardedHeaders bool, verifier *tokenVerifier) (*Identity, error) {
+	identity := &Identity{
+		SourceIP: remoteIP(request.RemoteAddr),
+	}
+
+	// Identify The Caller By The Headers Of A Trusted Proxy Or The Connection
+	if trustForwardedHeaders {
+		if forwardedFor := request.Header.Get(ForwardedForHeader); len(forwardedFor) > 0 {
+			addresses := strings.Split(forwardedFor, ",")
+			if sourceIP := net.ParseIP(strings.TrimSpace(addresses[len(addresses)-1])); sourceIP != nil {
+				identity.SourceIP = sourceIP
+			}
+		}
+		identity.CertSubjects = forwardedCertSubjects(request.Header.Get(ForwardedClientCertHeader))
+	}
+	if request.TLS != nil && len(request.TLS.PeerCertificates) > 0 {
+		certificate := request.TLS.PeerCertificates[0]
+		identity.CertSubjects = nil
+		if len(certificate.Subject.CommonName) > 0 {
+			identity.CertSubjects = append(identity.CertSubjects, certificate.Subject.CommonName)
+		}
+		identity.CertSubjects = append(identity.CertSubjects, certificate.DNSNames...)
+		for _, uri := range certificate.URIs {
+			identity.CertSubjects = append(identity.CertSubjects, uri.String())
+		}
+	}
+
+	// Verify The Caller's Bearer Token (Ignored Unless Public Keys Are Configured To Verify It With)
+	if verifier != nil {
+		token := bearerToken(request)
+		if len(token) == 0 {
+			if verifier.required {
+				return nil, ErrTokenRequired
+			}
+		} else {
+			claims, err := verifier.verify(token)
+			if err != nil {
+				return nil, err
+			}
+			identity.Claims = claims
+		}
+	}
+
+	// Identify The Caller's Namespace From Its ServiceAccount Token Or SPIFFE ID
+	identity.Namespace = identity.namespace()
+	return identity, nil
+}
+
+// Get The Namespace Of The Caller From Its Token's ServiceAccount Subject Or Its Certificate's SPIFFE ID
+func (i *Identity) namespace() string {
+	if subject, ok := i.Claims["sub"].(string); ok && strings.HasPrefix(subject, serviceAccountSubjectPrefix) {
+		if parts := strings.Split(strings.TrimPrefix(subject, serviceAccountSubjectPrefix), ":"); len(parts) == 2 {
+			return parts[0]
+		}
+	}
+	for _, subject := range i.CertSubjects {
+		if !strings.HasPrefix(subject, spiffeScheme+"://") {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(subject, spiffeScheme+"://"), "/")
+		for index := 1; index+1 < len(parts); index++ {
+			if parts[index] == "ns" {
+				return parts[index+1]
+			}
+		}
+	}
+	return ""
+}
+
+// Utility Function For Getting The IP Of A Remote Address
+func remoteIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// Utility Function For Getting The Bearer Token Of A Request's Authorization Header (Empty If None)
+func bearerToken(request *nethttp.Request) string {
+	authorization := request.Header.Get("Authorization")
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(authorization[7:])
+}
+
+// Utility Function For Getting The Subjects (Subject CN, DNS & URI) Of The Client Certificate In The Last Element Of
+// An X-Forwarded-Client-Cert Header (Added By The Proxy Closest To The Receiver)
+func forwardedCertSubjects(forwardedClientCert string) []string {
+	if len(forwardedClientCert) == 0 {
+		return nil
+	}
+	elements := splitQuoted(forwardedClientCert, ',')
+	var subjects []string
+	for _, field := range splitQuoted(elements[len(elements)-1], ';') {
+		keyValue := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		value := strings.Trim(keyValue[1], `"`)
+		switch strings.ToLower(keyValue[0]) {
+		case "subject":
+			for _, attribute := range splitQuoted(value, ',') {
+				if name := strings.TrimSpace(attribute); strings.HasPrefix(name, "CN=") {
+					subjects = append(subjects, strings.TrimPrefix(name, "CN="))
+				}
+			}
+		case "dns", "uri":
+			subjects = append(subjects, value)
+		}
+	}
+	return subjects
+}
+
+// Utility Function For Splitting A String By A Separator Outside Of Double Quotes
+func splitQuoted(value string, separator rune) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for index, character := range value {
+		switch {
+		case character == '"':
+			quoted = !quoted
+		case character == separator && !quoted:
+			parts = append(parts, value[start:index])
+			start = index + 1
+		}
+	}
+	return append(parts, value[start:])
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	jwt "github.com/form3tech-oss/jwt-go"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// The Clock Skew Tolerated When Validating The Expiry, Not-Before & Issued-At Times Of Tokens
+const TokenClockSkew = 30 * time.Second
+
+// The Allowlist Of JWS Signing Algorithms (Never "none" Or The Shared Secret HS256/384/512)
+var allowedAlgorithms = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodRS384.Alg(),
+	jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodES512.Alg(),
+}
+
+//
+// Verifier Of The Callers' Bearer Tokens (JWTs)
+//
+// Tokens must be signed with one of the allowedAlgorithms by one of the configured public keys, and are then valid
+// if they have an expiry which hasn't passed (and aren't yet valid or issued in the future), and are issued by the
+// configured issuer & intended for one of the configured audiences.  Unsigned tokens ("none") and tokens signed with
+// shared secrets (HS256...) are rejected, as are tokens with malformed (non-numeric) exp, nbf or iat claims.
+//
+type tokenVerifier struct {
+	issuer    string
+	audiences []string
+	keys      []crypto.PublicKey
+	required  bool
+	parser    *jwt.Parser
+	now       func() time.Time
+}
+
+// Create A Token Verifier From The Specified JWT Config (nil If It Has No Public Keys & Tokens Aren't Required)
+func newTokenVerifier(jwtConfig config.EKPolicyJWT) (*tokenVerifier, error) {
+	var keys []crypto.PublicKey
+	for index, publicKeys := range jwtConfig.PublicKeys {
+		parsedKeys, err := parsePublicKeys(publicKeys)
+		if err != nil {
+			return nil, fmt.Errorf("jwt publicKeys[%d] is invalid: %v", index, err)
+		}
+		keys = append(keys, parsedKeys...)
+	}
+	if len(keys) == 0 {
+		if jwtConfig.Required || len(jwtConfig.Issuer) > 0 || len(jwtConfig.Audiences) > 0 {
+			return nil, errors.New("jwt requires publicKeys to verify tokens with")
+		}
+		return nil, nil
+	}
+	return &tokenVerifier{
+		issuer:    jwtConfig.Issuer,
+		audiences: jwtConfig.Audiences,
+		keys:      keys,
+		required:  jwtConfig.Required,
+		// The Registered Claims Are Validated By verifyClaims() In Order To Tolerate The Clock Skew
+		parser: &jwt.Parser{ValidMethods: allowedAlgorithms, UseJSONNumber: true, SkipClaimsValidation: true},
+		now:    time.Now,
+	}, nil
+}
+
+// Verify The Specified Token, Returning Its Claims
+func (v *tokenVerifier) verify(token string) (map[string]interface{}, error) {
+
+	// Parse The Token & Verify Its Signature Against Each Of The Public Keys
+	var claims jwt.MapClaims
+	var err error
+	for _, key := range v.keys {
+		claims = jwt.MapClaims{}
+		_, err = v.parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return key, nil })
+		if err == nil {
+			break
+		}
+		var validationErr *jwt.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors != jwt.ValidationErrorSignatureInvalid {
+			return nil, fmt.Errorf("token is invalid: %v", err)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("token signature is not verified by any of the public keys: %v", err)
+	}
+
+	// Validate The Registered Claims
+	if err = v.verifyClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Validate The Expiry, Not-Before, Issued-At, Issuer & Audience Claims Of A Token
+func (v *tokenVerifier) verifyClaims(claims jwt.MapClaims) error {
+	now := v.now()
+	expiry, ok, err := numericClaim(claims, "exp")
+	if err != nil {
+		return err
+	} else if !ok {
+		return errors.New("token has no exp claim")
+	} else if now.After(expiry.Add(TokenClockSkew)) {
+		return fmt.Errorf("token expired at %s", expiry.UTC().Format(time.RFC3339))
+	}
+	notBefore, ok, err := numericClaim(claims, "nbf")
+	if err != nil {
+		return err
+	} else if ok && now.Before(notBefore.Add(-TokenClockSkew)) {
+		return fmt.Errorf("token is not valid before %s", notBefore.UTC().Format(time.RFC3339))
+	}
+	issuedAt, ok, err := numericClaim(claims, "iat")
+	if err != nil {
+		return err
+	} else if ok && now.Before(issuedAt.Add(-TokenClockSkew)) {
+		return fmt.Errorf("token is issued in the future at %s", issuedAt.UTC().Format(time.RFC3339))
+	}
+	if len(v.issuer) > 0 && claims["iss"] != v.issuer {
+		return fmt.Errorf("token issuer %v is not %s", claims["iss"], v.issuer)
+	}
+	if len(v.audiences) > 0 && !containsAny(claimValues(claims["aud"]), v.audiences) {
+		return fmt.Errorf("token audience %v is not one of %s", claims["aud"], strings.Join(v.audiences, ", "))
+	}
+	return nil
+}
+
+// Utility Function For Parsing The RSA & ECDSA Public Keys Of PEM Encoded Public Keys & Certificates
+func parsePublicKeys(publicKeys string) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	rest := []byte(publicKeys)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var certificate *x509.Certificate
+			certificate, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				key = certificate.PublicKey
+			}
+		default:
+			return nil, fmt.Errorf("PEM block type %q is not a public key or certificate", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			keys = append(keys, key)
+		default:
+			return nil, fmt.Errorf("public key type %T is not RSA or ECDSA", key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded public keys or certificates found")
+	}
+	return keys, nil
+}
+
+// Utility Function For Getting The Time Of A NumericDate Claim (Whether Present, Or An Error If Not A Number)
+func numericClaim(claims jwt.MapClaims, name string) (time.Time, bool, error) {
+	claim, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	number, ok := claim.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("token %s claim %v is not a NumericDate", name, claim)
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("token %s claim %v is not a NumericDate", name, claim)
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true, nil
+}
+
+// Utility Function For Getting The Values Of A String Or Array Claim
+func claimValues(claim interface{}) []string {
+	switch value := claim.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, element := range value {
+			values = append(values, fmt.Sprint(element))
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
+
+// Utility Function For Determining Whether Any Of The Values Is One Of The Expected Values
+func containsAny(values []string, expected []string) bool {
+	for _, value := range values {
+		for _, expectedValue := range expected {
+			if value == expectedValue {
+				return true
+			}
+		}
+	}
+	return false
+}
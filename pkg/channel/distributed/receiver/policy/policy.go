@@ -0,0 +1,574 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	nethttp "net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/batch"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/validation"
+	eventingchannel "knative.dev/eventing/pkg/channel"
+)
+
+// Ingress Policy Actions
+const (
+	ActionAllow  = "allow"
+	ActionReject = "reject"
+	ActionTag    = "tag"
+)
+
+// Ingress Policy Variables (Substituted In The Patterns Of The Rules & The Values Of Their Extensions)
+const (
+	VariableCallerNamespace  = "${caller.namespace}"
+	VariableChannelNamespace = "${channel.namespace}"
+	VariableChannelName      = "${channel.name}"
+)
+
+// Problem Details (RFC 7807) Constants
+const (
+	ForbiddenEventType  = "urn:knative:eventing-kafka:forbidden-event"
+	UnauthenticatedType = "urn:knative:eventing-kafka:unauthenticated"
+)
+
+// The Characters Allowed In Attribute & Extension Names By The CloudEvents Spec
+var attributeNameRegexp = regexp.MustCompile("^[a-z0-9]+$")
+
+//
+// Ingress Policy Of The Events Received By The Receiver
+//
+// The policy stops tenants from spoofing the events of other tenants (e.g. sending events with the ce-source of
+// another namespace) by deciding whether to accept each event based on the Identity of its caller (source IP, client
+// certificate & verified bearer token claims), the KafkaChannel it is sent to and its CloudEvent attributes.  The
+// rules are evaluated in order, and the first allowing or rejecting rule matching an event decides it, while the
+// matching tagging rules set their extensions on the event (e.g. to record the caller's namespace for subscribers).
+// Events matching none of the allowing or rejecting rules are subject to the default action.  Rejected events are
+// answered with a 403 and requests whose bearer token is invalid (or missing when required) with a 401.
+//
+// A nil *Policy is valid and accepts all events.
+//
+type Policy struct {
+	logger *zap.Logger
+	state  *policyState
+	lock   sync.RWMutex
+}
+
+// The Compiled (Immutable) Configuration Of A Policy, Replaced As A Whole When The Config Changes
+type policyState struct {
+	trustForwardedHeaders bool
+	verifier              *tokenVerifier
+	rules                 []*rule
+	defaultAction         string
+}
+
+// A Compiled Rule Of The Ingress Policy
+type rule struct {
+	name               string
+	action             string
+	channels           []string
+	sourceNets         []*net.IPNet
+	clientCertSubjects []string
+	claims             map[string]string
+	attributes         map[string]string
+	extensions         map[string]string
+}
+
+// The Decision Of The Ingress Policy On An Event
+type Decision struct {
+	Action     string
+	Rule       string
+	Extensions map[string]string
+}
+
+// Validate The Specified Policy Config
+func ValidatePolicyConfig(policyConfig config.EKPolicyConfig) error {
+	_, err := compile(policyConfig)
+	return err
+}
+
+// Policy Constructor - Returns nil If The Policy Is Not Enabled (Assumes A Valid Config)
+func NewPolicy(logger *zap.Logger, policyConfig config.EKPolicyConfig) *Policy {
+	if !policyConfig.Enabled {
+		return nil
+	}
+	state, err := compile(policyConfig)
+	if err != nil {
+		logger.Error("Invalid Ingress Policy Config - Rejecting All Events", zap.Error(err))
+		state = &policyState{defaultAction: ActionReject}
+	}
+	return &Policy{logger: logger, state: state}
+}
+
+// Apply The Policy Config Of The Specified (Changed) ConfigMap, Keeping The Current Config If It Is Invalid
+// (The Policy Is Only Enabled Or Disabled On Restart)
+func (p *Policy) ConfigChanged(configMap *corev1.ConfigMap) {
+	if p == nil || configMap == nil {
+		return
+	}
+	ekConfig, _, err := config.ParseEventingKafkaConfig(configMap.Data[config.EventingKafkaSettingsConfigKey])
+	if err != nil {
+		p.logger.Error("Failed To Parse The Ingress Policy Config - Ignoring Changes", zap.Error(err))
+		return
+	}
+	state, err := compile(ekConfig.Receiver.Policy)
+	if err != nil {
+		p.logger.Error("Invalid Ingress Policy Config - Ignoring Changes", zap.Error(err))
+		return
+	}
+	p.lock.Lock()
+	p.state = state
+	p.lock.Unlock()
+	p.logger.Info("Applied Ingress Policy Config", zap.Int("Rules", len(state.rules)))
+}
+
+// Get The Current Compiled Configuration Of The Policy
+func (p *Policy) currentState() *policyState {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.state
+}
+
+// Decide Whether To Accept The Specified Event Sent By The Caller To The Specified KafkaChannel
+func (p *Policy) Decide(identity *Identity, channelReference eventingchannel.ChannelReference, cloudEvent *event.Event) Decision {
+	if p == nil {
+		return Decision{Action: ActionAllow}
+	}
+	return p.currentState().decide(identity, channelReference, cloudEvent)
+}
+
+// Decide Whether To Accept The Specified Event With The Compiled Configuration
+func (s *policyState) decide(identity *Identity, channelReference eventingchannel.ChannelReference, cloudEvent *event.Event) Decision {
+	variables := map[string]string{
+		VariableCallerNamespace:  identity.Namespace,
+		VariableChannelNamespace: channelReference.Namespace,
+		VariableChannelName:      channelReference.Name,
+	}
+	decision := Decision{Action: s.defaultAction}
+	for _, rule := range s.rules {
+		if !rule.matches(identity, channelReference, cloudEvent, variables) {
+			continue
+		}
+		if rule.action == ActionTag {
+			for name, value := range rule.extensions {
+				if expanded, ok := expand(value, variables); ok {
+					if decision.Extensions == nil {
+						decision.Extensions = make(map[string]string)
+					}
+					decision.Extensions[name] = expanded
+				}
+			}
+			continue
+		}
+		decision.Action = rule.action
+		decision.Rule = rule.name
+		return decision
+	}
+	return decision
+}
+
+// Determine Whether The Rule Matches The Specified Event (All Of Its Conditions Must Match)
+func (r *rule) matches(identity *Identity, channelReference eventingchannel.ChannelReference, cloudEvent *event.Event, variables map[string]string) bool {
+	if len(r.channels) > 0 && !matchAny(r.channels, []string{channelReference.Namespace + "/" + channelReference.Name}, variables) {
+		return false
+	}
+	if len(r.sourceNets) > 0 && !containsIP(r.sourceNets, identity.SourceIP) {
+		return false
+	}
+	if len(r.clientCertSubjects) > 0 && !matchAny(r.clientCertSubjects, identity.CertSubjects, variables) {
+		return false
+	}
+	for claim, pattern := range r.claims {
+		if !matchAny([]string{pattern}, claimValues(identity.Claims[claim]), variables) {
+			return false
+		}
+	}
+	for attribute, pattern := range r.attributes {
+		value, ok := attributeValue(cloudEvent, attribute)
+		if !ok || !matchAny([]string{pattern}, []string{value}, variables) {
+			return false
+		}
+	}
+	return true
+}
+
+// Wrap The Specified Handler To Reject Forbidden Events & Tag Accepted Ones (Returns It As-Is If nil)
+func (p *Policy) Handler(next nethttp.Handler) nethttp.Handler {
+	if p == nil {
+		return next
+	}
+	return nethttp.HandlerFunc(func(response nethttp.ResponseWriter, request *nethttp.Request) {
+
+		// Only Event Requests Are Subject To The Policy (Leaving Others To Be Rejected By The Next Handler)
+		if request.Method != nethttp.MethodPost {
+			next.ServeHTTP(response, request)
+			return
+		}
+
+		// Identify The Caller
+		state := p.currentState()
+		identity, err := identify(request, state.trustForwardedHeaders, state.verifier)
+		if err != nil {
+			p.logger.Info("Rejecting Unauthenticated Event Request", zap.String("RemoteAddr", request.RemoteAddr), zap.Error(err))
+			response.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			validation.WriteProblem(response, &validation.Problem{
+				Type:   UnauthenticatedType,
+				Title:  "Unauthenticated Caller",
+				Status: nethttp.StatusUnauthorized,
+				Detail: err.Error(),
+			})
+			return
+		}
+
+		// Buffer The Body So That It Can Be Read By Both The Policy & The Next Handler
+		body, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			p.logger.Info("Failed To Read Request Body", zap.Error(err))
+			response.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+
+		// Decide On The Batched Or Single Event (Requests Without A Channel Or Events Are Left For The Next Handler To Reject)
+		channelReference := resolveChannel(request.Host)
+		var problem *validation.Problem
+		var tagged []byte
+		if batch.IsBatch(request) {
+			problem, tagged = p.decideBatch(state, identity, channelReference, body)
+		} else {
+			problem, tagged = p.decideMessage(request.Context(), state, identity, channelReference, request.Header, body)
+		}
+		if problem != nil {
+			p.logger.Info("Rejecting Forbidden Event Request", zap.Any("ChannelReference", channelReference), zap.Stringer("SourceIP", identity.SourceIP),
+				zap.String("CallerNamespace", identity.Namespace), zap.Any("Problem", problem))
+			validation.WriteProblem(response, problem)
+			return
+		}
+
+		// Replace The Body With The Tagged Events (Sending A Single Event In Structured Mode)
+		if tagged != nil {
+			body = tagged
+			if !batch.IsBatch(request) {
+				for header := range request.Header {
+					if strings.HasPrefix(strings.ToLower(header), "ce-") {
+						request.Header.Del(header)
+					}
+				}
+				request.Header.Set("Content-Type", event.ApplicationCloudEventsJSON)
+			}
+			request.ContentLength = int64(len(body))
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(response, request)
+	})
+}
+
+// Decide On The Binary Or Structured Event Of A Request, Returning A Problem If Rejected & The Tagged Event (nil If Untagged)
+func (p *Policy) decideMessage(ctx context.Context, state *policyState, identity *Identity, channelReference eventingchannel.ChannelReference, header nethttp.Header, body []byte) (*validation.Problem, []byte) {
+	cloudEvent, err := binding.ToEvent(ctx, cehttp.NewMessage(header, ioutil.NopCloser(bytes.NewReader(body))))
+	if err != nil {
+		return nil, nil
+	}
+	decision := state.decide(identity, channelReference, cloudEvent)
+	if decision.Action == ActionReject {
+		return forbiddenEventProblem(fmt.Sprintf("event %s is forbidden", cloudEvent.ID()), []string{rejection(cloudEvent, decision)}), nil
+	}
+	if len(decision.Extensions) == 0 {
+		return nil, nil
+	}
+	tag(cloudEvent, decision)
+	tagged, err := json.Marshal(cloudEvent)
+	if err != nil {
+		p.logger.Warn("Failed To Marshal Tagged Event", zap.String("EventID", cloudEvent.ID()), zap.Error(err))
+		return nil, nil
+	}
+	return nil, tagged
+}
+
+// Decide On The Events Of A Batched Request, Rejecting The Whole Batch If Any Event Is Rejected (Events Which Cannot
+// Be Parsed Are Left For The Batch Handler To Report)
+func (p *Policy) decideBatch(state *policyState, identity *Identity, channelReference eventingchannel.ChannelReference, body []byte) (*validation.Problem, []byte) {
+	var rawEvents []json.RawMessage
+	if json.Unmarshal(body, &rawEvents) != nil || len(rawEvents) > batch.MaxBatchSize {
+		return nil, nil
+	}
+	var violations []string
+	tagged := false
+	for index, rawEvent := range rawEvents {
+		cloudEvent := event.New()
+		if json.Unmarshal(rawEvent, &cloudEvent) != nil {
+			continue
+		}
+		decision := state.decide(identity, channelReference, &cloudEvent)
+		if decision.Action == ActionReject {
+			violations = append(violations, fmt.Sprintf("[%d] %s", index, rejection(&cloudEvent, decision)))
+			continue
+		}
+		if len(decision.Extensions) > 0 {
+			tag(&cloudEvent, decision)
+			if taggedEvent, err := json.Marshal(&cloudEvent); err == nil {
+				rawEvents[index] = taggedEvent
+				tagged = true
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return forbiddenEventProblem("the batch contains forbidden events (none of which were produced)", violations), nil
+	}
+	if !tagged {
+		return nil, nil
+	}
+	taggedBatch, err := json.Marshal(rawEvents)
+	if err != nil {
+		p.logger.Warn("Failed To Marshal Tagged Batch", zap.Error(err))
+		return nil, nil
+	}
+	return nil, taggedBatch
+}
+
+// Compile The Specified Policy Config, Validating It
+func compile(policyConfig config.EKPolicyConfig) (*policyState, error) {
+	state := &policyState{
+		trustForwardedHeaders: policyConfig.TrustForwardedHeaders,
+		defaultAction:         policyConfig.DefaultAction,
+	}
+	switch state.defaultAction {
+	case "":
+		state.defaultAction = ActionAllow
+	case ActionAllow, ActionReject:
+	default:
+		return nil, fmt.Errorf("defaultAction %q must be %s or %s", policyConfig.DefaultAction, ActionAllow, ActionReject)
+	}
+	verifier, err := newTokenVerifier(policyConfig.JWT)
+	if err != nil {
+		return nil, err
+	}
+	state.verifier = verifier
+	names := make(map[string]bool, len(policyConfig.Rules))
+	for index, ruleConfig := range policyConfig.Rules {
+		compiledRule, err := compileRule(ruleConfig)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d] is invalid: %v", index, err)
+		}
+		if names[compiledRule.name] {
+			return nil, fmt.Errorf("rules[%d] name %q is not unique", index, compiledRule.name)
+		}
+		names[compiledRule.name] = true
+		state.rules = append(state.rules, compiledRule)
+	}
+	return state, nil
+}
+
+// Compile The Specified Rule Config, Validating It
+func compileRule(ruleConfig config.EKPolicyRule) (*rule, error) {
+	if len(ruleConfig.Name) == 0 {
+		return nil, fmt.Errorf("name is required")
+	}
+	switch ruleConfig.Action {
+	case ActionAllow, ActionReject:
+		if len(ruleConfig.Extensions) > 0 {
+			return nil, fmt.Errorf("extensions are only set by the %s action", ActionTag)
+		}
+	case ActionTag:
+		if len(ruleConfig.Extensions) == 0 {
+			return nil, fmt.Errorf("the %s action requires extensions", ActionTag)
+		}
+	default:
+		return nil, fmt.Errorf("action %q must be %s, %s or %s", ruleConfig.Action, ActionAllow, ActionReject, ActionTag)
+	}
+	compiledRule := &rule{
+		name:               ruleConfig.Name,
+		action:             ruleConfig.Action,
+		channels:           ruleConfig.Channels,
+		clientCertSubjects: ruleConfig.ClientCertSubjects,
+		claims:             ruleConfig.Claims,
+		attributes:         ruleConfig.Attributes,
+		extensions:         ruleConfig.Extensions,
+	}
+	for _, cidr := range ruleConfig.SourceCIDRs {
+		if ip := net.ParseIP(cidr); ip != nil {
+			cidr = ip.String() + "/128"
+			if ip.To4() != nil {
+				cidr = ip.String() + "/32"
+			}
+		}
+		_, sourceNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("sourceCIDRs %q is not an IP or CIDR", cidr)
+		}
+		compiledRule.sourceNets = append(compiledRule.sourceNets, sourceNet)
+	}
+	for attribute := range ruleConfig.Attributes {
+		if !attributeNameRegexp.MatchString(attribute) {
+			return nil, fmt.Errorf("attributes %q must consist of lowercase letters & digits", attribute)
+		}
+	}
+	for extension := range ruleConfig.Extensions {
+		if !attributeNameRegexp.MatchString(extension) {
+			return nil, fmt.Errorf("extensions %q must consist of lowercase letters & digits", extension)
+		}
+	}
+	return compiledRule, nil
+}
+
+// Utility Function For Resolving The KafkaChannel Addressed By The Host Of A Request (Empty If Unknown)
+func resolveChannel(host string) eventingchannel.ChannelReference {
+	channelReference, err := eventingchannel.ParseChannel(host)
+	if err != nil {
+		return eventingchannel.ChannelReference{}
+	}
+	channelReference.Name = kafkautil.TrimKafkaChannelServiceNameSuffix(channelReference.Name)
+	return channelReference
+}
+
+// Utility Function For Getting The Value Of A CloudEvent Attribute Or Extension (False If It Isn't Set)
+func attributeValue(cloudEvent *event.Event, attribute string) (string, bool) {
+	var value string
+	switch attribute {
+	case "specversion":
+		value = cloudEvent.SpecVersion()
+	case "id":
+		value = cloudEvent.ID()
+	case "source":
+		value = cloudEvent.Source()
+	case "type":
+		value = cloudEvent.Type()
+	case "subject":
+		value = cloudEvent.Subject()
+	case "datacontenttype":
+		value = cloudEvent.DataContentType()
+	case "dataschema":
+		value = cloudEvent.DataSchema()
+	default:
+		extension, ok := cloudEvent.Extensions()[attribute]
+		if !ok {
+			return "", false
+		}
+		value = fmt.Sprint(extension)
+	}
+	return value, len(value) > 0
+}
+
+// Utility Function For Determining Whether Any Of The Patterns Matches Any Of The Values
+func matchAny(patterns []string, values []string, variables map[string]string) bool {
+	for _, pattern := range patterns {
+		expanded, ok := expand(pattern, variables)
+		if !ok {
+			continue
+		}
+		for _, value := range values {
+			if wildcardMatch(expanded, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Utility Function For Substituting The Variables In A Pattern (False If Any Of Its Variables Is Unknown)
+func expand(pattern string, variables map[string]string) (string, bool) {
+	if !strings.Contains(pattern, "${") {
+		return pattern, true
+	}
+	for variable, value := range variables {
+		if strings.Contains(pattern, variable) {
+			if len(value) == 0 {
+				return "", false
+			}
+			pattern = strings.ReplaceAll(pattern, variable, value)
+		}
+	}
+	return pattern, !strings.Contains(pattern, "${")
+}
+
+// Utility Function For Matching A Value Against A Pattern Whose "*" Wildcards Match Any Characters (Including "/")
+func wildcardMatch(pattern string, value string) bool {
+	segments := strings.Split(pattern, "*")
+	if len(segments) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, segments[0]) {
+		return false
+	}
+	value = value[len(segments[0]):]
+	for _, segment := range segments[1 : len(segments)-1] {
+		index := strings.Index(value, segment)
+		if index < 0 {
+			return false
+		}
+		value = value[index+len(segment):]
+	}
+	return strings.HasSuffix(value, segments[len(segments)-1])
+}
+
+// Utility Function For Determining Whether Any Of The Networks Contains The IP
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Utility Function For Setting The Extensions Of A Decision On An Event (In A Deterministic Order)
+func tag(cloudEvent *event.Event, decision Decision) {
+	names := make([]string, 0, len(decision.Extensions))
+	for name := range decision.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cloudEvent.SetExtension(name, decision.Extensions[name])
+	}
+}
+
+// Utility Function For Describing The Rejection Of An Event
+func rejection(cloudEvent *event.Event, decision Decision) string {
+	if len(decision.Rule) == 0 {
+		return fmt.Sprintf("event %s from source %s is rejected by the default action", cloudEvent.ID(), cloudEvent.Source())
+	}
+	return fmt.Sprintf("event %s from source %s is rejected by rule %s", cloudEvent.ID(), cloudEvent.Source(), decision.Rule)
+}
+
+// Utility Function For Creating The Problem Of A Forbidden Event
+func forbiddenEventProblem(detail string, violations []string) *validation.Problem {
+	return &validation.Problem{
+		Type:       ForbiddenEventType,
+		Title:      "Forbidden CloudEvent",
+		Status:     nethttp.StatusForbidden,
+		Detail:     detail,
+		Violations: violations,
+	}
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package policy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/validation"
+	eventingchannel "knative.dev/eventing/pkg/channel"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testHost             = "test-channel-kn-channel.team-a.svc.cluster.local"
+	testOwnEvent         = `{"specversion":"1.0","id":"id-1","source":"/apis/v1/namespaces/team-a/pingsources/ping","type":"test-type"}`
+	testSpoofedEvent     = `{"specversion":"1.0","id":"id-2","source":"/apis/v1/namespaces/team-b/pingsources/ping","type":"test-type"}`
+	testStructuredType   = "application/cloudevents+json"
+	testBatchType        = "application/cloudevents-batch+json"
+	testForwardedCertSAN = "spiffe://cluster.local/ns/team-a/sa/sender"
+)
+
+// The Test Policy Tagging Events With The Caller's Namespace & Rejecting Events Of Other Namespaces' Sources
+var testPolicyConfig = config.EKPolicyConfig{
+	Enabled:               true,
+	TrustForwardedHeaders: true,
+	Rules: []config.EKPolicyRule{
+		{Name: "trusted-network", Action: ActionAllow, SourceCIDRs: []string{"198.51.100.0/24"}},
+		{Name: "tag-caller", Action: ActionTag, Extensions: map[string]string{"callerns": VariableCallerNamespace}},
+		{Name: "own-sources", Action: ActionAllow, Attributes: map[string]string{"source": "/apis/v1/namespaces/${caller.namespace}/*"}},
+	},
+	DefaultAction: ActionReject,
+}
+
+// Test The ValidatePolicyConfig() Functionality
+func TestValidatePolicyConfig(t *testing.T) {
+	assert.Nil(t, ValidatePolicyConfig(config.EKPolicyConfig{}))
+	assert.Nil(t, ValidatePolicyConfig(testPolicyConfig))
+	assert.Nil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Name: "ip", Action: ActionReject, SourceCIDRs: []string{"10.0.0.1", "fd00::/8"}}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{DefaultAction: ActionTag}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Action: ActionAllow}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Name: "a", Action: "deny"}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Name: "a", Action: ActionAllow}, {Name: "a", Action: ActionReject}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Name: "a", Action: ActionTag}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Name: "a", Action: ActionAllow, Extensions: map[string]string{"x": "y"}}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Name: "a", Action: ActionTag, Extensions: map[string]string{"Caller-NS": "y"}}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Name: "a", Action: ActionAllow, Attributes: map[string]string{"ce-source": "y"}}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{Rules: []config.EKPolicyRule{{Name: "a", Action: ActionAllow, SourceCIDRs: []string{"10.0.0.0/33"}}}}))
+	assert.NotNil(t, ValidatePolicyConfig(config.EKPolicyConfig{JWT: config.EKPolicyJWT{Required: true}}))
+}
+
+// Test The NewPolicy() Functionality
+func TestNewPolicy(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Nil(t, NewPolicy(logger, config.EKPolicyConfig{}))
+	assert.NotNil(t, NewPolicy(logger, testPolicyConfig))
+	var nilPolicy *Policy
+	assert.Equal(t, ActionAllow, nilPolicy.Decide(&Identity{}, eventingchannel.ChannelReference{}, &event.Event{}).Action)
+}
+
+// Test The Policy.Decide() Functionality
+func TestDecide(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name               string
+		identity           *Identity
+		channel            eventingchannel.ChannelReference
+		source             string
+		expectedAction     string
+		expectedRule       string
+		expectedExtensions map[string]string
+	}
+
+	// Create The TestCases
+	teamA := eventingchannel.ChannelReference{Namespace: "team-a", Name: "test-channel"}
+	testCases := []TestCase{
+		{name: "Own Source", identity: &Identity{SourceIP: net.ParseIP("10.0.0.1"), Namespace: "team-a"}, channel: teamA, source: "/apis/v1/namespaces/team-a/pingsources/ping",
+			expectedAction: ActionAllow, expectedRule: "own-sources", expectedExtensions: map[string]string{"callerns": "team-a"}},
+		{name: "Spoofed Source", identity: &Identity{SourceIP: net.ParseIP("10.0.0.1"), Namespace: "team-b"}, channel: teamA, source: "/apis/v1/namespaces/team-a/pingsources/ping",
+			expectedAction: ActionReject, expectedExtensions: map[string]string{"callerns": "team-b"}},
+		{name: "Unknown Caller Namespace", identity: &Identity{SourceIP: net.ParseIP("10.0.0.1")}, channel: teamA, source: "/apis/v1/namespaces/team-a/pingsources/ping",
+			expectedAction: ActionReject},
+		{name: "Trusted Network", identity: &Identity{SourceIP: net.ParseIP("198.51.100.10")}, channel: teamA, source: "anything",
+			expectedAction: ActionAllow, expectedRule: "trusted-network"},
+	}
+
+	// Create The Policy
+	policy := NewPolicy(logtesting.TestLogger(t).Desugar(), testPolicyConfig)
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cloudEvent := event.New()
+			cloudEvent.SetID("id")
+			cloudEvent.SetType("test-type")
+			cloudEvent.SetSource(testCase.source)
+			decision := policy.Decide(testCase.identity, testCase.channel, &cloudEvent)
+			assert.Equal(t, testCase.expectedAction, decision.Action)
+			assert.Equal(t, testCase.expectedRule, decision.Rule)
+			assert.Equal(t, testCase.expectedExtensions, decision.Extensions)
+		})
+	}
+
+	// Rules May Match The Channel, The Client Certificate & The Token Claims
+	policy = NewPolicy(logtesting.TestLogger(t).Desugar(), config.EKPolicyConfig{
+		Enabled: true,
+		Rules: []config.EKPolicyRule{
+			{Name: "channel", Action: ActionReject, Channels: []string{"${caller.namespace}/private-*"}},
+			{Name: "cert", Action: ActionAllow, ClientCertSubjects: []string{"spiffe://cluster.local/ns/${channel.namespace}/*"}},
+			{Name: "claims", Action: ActionAllow, Claims: map[string]string{"groups": "senders"}},
+		},
+		DefaultAction: ActionReject,
+	})
+	cloudEvent := event.New()
+	assert.Equal(t, "channel", policy.Decide(&Identity{Namespace: "team-a", CertSubjects: []string{testForwardedCertSAN}},
+		eventingchannel.ChannelReference{Namespace: "team-a", Name: "private-channel"}, &cloudEvent).Rule)
+	assert.Equal(t, "cert", policy.Decide(&Identity{Namespace: "team-a", CertSubjects: []string{testForwardedCertSAN}}, teamA, &cloudEvent).Rule)
+	assert.Equal(t, "claims", policy.Decide(&Identity{Claims: map[string]interface{}{"groups": []interface{}{"readers", "senders"}}}, teamA, &cloudEvent).Rule)
+	assert.Equal(t, ActionReject, policy.Decide(&Identity{Claims: map[string]interface{}{"groups": "readers"}}, teamA, &cloudEvent).Action)
+}
+
+// Test The identify() Functionality
+func TestIdentify(t *testing.T) {
+
+	// The Forwarded Headers Are Only Trusted When Configured
+	request := httptest.NewRequest(nethttp.MethodPost, "/", nil)
+	request.RemoteAddr = "10.1.2.3:41234"
+	request.Header.Set(ForwardedForHeader, "203.0.113.1, 198.51.100.7")
+	request.Header.Set(ForwardedClientCertHeader, `By=spiffe://cluster.local/ns/knative-eventing/sa/receiver;Subject="CN=sender,O=Team A";URI=`+testForwardedCertSAN)
+	identity, err := identify(request, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.2.3", identity.SourceIP.String())
+	assert.Nil(t, identity.CertSubjects)
+	assert.Empty(t, identity.Namespace)
+	identity, err = identify(request, true, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "198.51.100.7", identity.SourceIP.String())
+	assert.Equal(t, []string{"sender", testForwardedCertSAN}, identity.CertSubjects)
+	assert.Equal(t, "team-a", identity.Namespace)
+
+	// The Namespace Is Identified By The ServiceAccount Token's Subject
+	rsaKey, _ := newTestKeys(t)
+	verifier, err := newTokenVerifier(config.EKPolicyJWT{PublicKeys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}, Required: true})
+	assert.Nil(t, err)
+	request = httptest.NewRequest(nethttp.MethodPost, "/", nil)
+	request.Header.Set("Authorization", "Bearer "+signToken(t, "RS256", rsaKey, map[string]interface{}{"sub": "system:serviceaccount:team-b:sender", "exp": time.Now().Unix() + 60}))
+	identity, err = identify(request, false, verifier)
+	assert.Nil(t, err)
+	assert.Equal(t, "team-b", identity.Namespace)
+
+	// Missing & Invalid Tokens Are Rejected
+	request.Header.Set("Authorization", "Bearer invalid")
+	_, err = identify(request, false, verifier)
+	assert.NotNil(t, err)
+	request.Header.Del("Authorization")
+	_, err = identify(request, false, verifier)
+	assert.Equal(t, ErrTokenRequired, err)
+}
+
+// Test The Policy.Handler() Functionality
+func TestHandler(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name               string
+		method             string
+		header             map[string]string
+		body               string
+		expectedStatus     int
+		expectedViolations []string
+		expectedCallerNs   []string
+	}
+
+	// Create The TestCases
+	callerHeaders := func(header map[string]string) map[string]string {
+		header[ForwardedClientCertHeader] = "URI=" + testForwardedCertSAN
+		return header
+	}
+	testCases := []TestCase{
+		{name: "Own Structured Event", method: nethttp.MethodPost, header: callerHeaders(map[string]string{"Content-Type": testStructuredType}), body: testOwnEvent,
+			expectedStatus: nethttp.StatusAccepted, expectedCallerNs: []string{"team-a"}},
+		{name: "Spoofed Structured Event", method: nethttp.MethodPost, header: callerHeaders(map[string]string{"Content-Type": testStructuredType}), body: testSpoofedEvent,
+			expectedStatus: nethttp.StatusForbidden, expectedViolations: []string{"event id-2 from source /apis/v1/namespaces/team-b/pingsources/ping is rejected by the default action"}},
+		{name: "Own Binary Event", method: nethttp.MethodPost, body: `{"n":1}`, expectedStatus: nethttp.StatusAccepted, expectedCallerNs: []string{"team-a"},
+			header: callerHeaders(map[string]string{"Content-Type": "application/json", "Ce-Specversion": "1.0", "Ce-Id": "id-1", "Ce-Source": "/apis/v1/namespaces/team-a/x", "Ce-Type": "test-type"})},
+		{name: "Unidentified Caller", method: nethttp.MethodPost, header: map[string]string{"Content-Type": testStructuredType}, body: testOwnEvent, expectedStatus: nethttp.StatusForbidden},
+		{name: "Own Batch", method: nethttp.MethodPost, header: callerHeaders(map[string]string{"Content-Type": testBatchType}), body: "[" + testOwnEvent + "," + testOwnEvent + "]",
+			expectedStatus: nethttp.StatusAccepted, expectedCallerNs: []string{"team-a", "team-a"}},
+		{name: "Batch With Spoofed Event", method: nethttp.MethodPost, header: callerHeaders(map[string]string{"Content-Type": testBatchType}), body: "[" + testOwnEvent + "," + testSpoofedEvent + "]",
+			expectedStatus: nethttp.StatusForbidden, expectedViolations: []string{"[1] event id-2 from source /apis/v1/namespaces/team-b/pingsources/ping is rejected by the default action"}},
+		{name: "Not A CloudEvent Left To The Next Handler", method: nethttp.MethodPost, header: map[string]string{"Content-Type": "text/plain"}, body: "hello", expectedStatus: nethttp.StatusAccepted},
+		{name: "Non-Event Request", method: nethttp.MethodGet, expectedStatus: nethttp.StatusAccepted},
+	}
+
+	// Create The Policy
+	policy := NewPolicy(logtesting.TestLogger(t).Desugar(), testPolicyConfig)
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Next Handler Verifying The (Tagged) Events It Receives
+			handler := policy.Handler(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+				body, err := ioutil.ReadAll(request.Body)
+				assert.Nil(t, err)
+				assert.Equal(t, int64(len(body)), request.ContentLength)
+				if testCase.expectedCallerNs == nil {
+					assert.Equal(t, testCase.body, string(body))
+				} else if request.Header.Get("Content-Type") == testBatchType {
+					var cloudEvents []event.Event
+					assert.Nil(t, json.Unmarshal(body, &cloudEvents))
+					for index, cloudEvent := range cloudEvents {
+						assert.Equal(t, testCase.expectedCallerNs[index], cloudEvent.Extensions()["callerns"])
+					}
+				} else {
+					assert.Equal(t, testStructuredType, request.Header.Get("Content-Type"))
+					assert.Empty(t, request.Header.Get("Ce-Id"))
+					cloudEvent := event.New()
+					assert.Nil(t, json.Unmarshal(body, &cloudEvent))
+					assert.Equal(t, "id-1", cloudEvent.ID())
+					assert.Equal(t, testCase.expectedCallerNs[0], cloudEvent.Extensions()["callerns"])
+				}
+				writer.WriteHeader(nethttp.StatusAccepted)
+			}))
+
+			// Perform The Test
+			request := httptest.NewRequest(testCase.method, "http://"+testHost+"/", strings.NewReader(testCase.body))
+			for key, value := range testCase.header {
+				request.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			// Verify The Results
+			assert.Equal(t, testCase.expectedStatus, recorder.Code)
+			if testCase.expectedStatus == nethttp.StatusForbidden {
+				problem := &validation.Problem{}
+				assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), problem))
+				assert.Equal(t, ForbiddenEventType, problem.Type)
+				if testCase.expectedViolations != nil {
+					assert.Equal(t, testCase.expectedViolations, problem.Violations)
+				}
+			}
+		})
+	}
+
+	// Callers Without A Required Token Are Rejected With A 401
+	rsaKey, _ := newTestKeys(t)
+	policy = NewPolicy(logtesting.TestLogger(t).Desugar(), config.EKPolicyConfig{Enabled: true,
+		JWT: config.EKPolicyJWT{PublicKeys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}, Required: true}})
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(nethttp.MethodPost, "http://"+testHost+"/", strings.NewReader(testOwnEvent))
+	policy.Handler(nethttp.NotFoundHandler()).ServeHTTP(recorder, request)
+	assert.Equal(t, nethttp.StatusUnauthorized, recorder.Code)
+	assert.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+
+	// A nil Policy Returns The Next Handler As-Is
+	var nilPolicy *Policy
+	next := nethttp.NotFoundHandler()
+	assert.NotNil(t, nilPolicy.Handler(next))
+}
+
+// Test The Policy.ConfigChanged() Functionality
+func TestConfigChanged(t *testing.T) {
+	policy := NewPolicy(logtesting.TestLogger(t).Desugar(), config.EKPolicyConfig{Enabled: true})
+	assert.Equal(t, ActionAllow, policy.currentState().defaultAction)
+
+	// Valid Changes Are Applied
+	policy.ConfigChanged(&corev1.ConfigMap{Data: map[string]string{config.EventingKafkaSettingsConfigKey: `
+receiver:
+  policy:
+    enabled: true
+    defaultAction: reject
+    rules:
+    - name: internal
+      action: allow
+      sourceCIDRs: ["10.0.0.0/8"]
+`}})
+	assert.Equal(t, ActionReject, policy.currentState().defaultAction)
+	assert.Len(t, policy.currentState().rules, 1)
+
+	// Invalid Changes Are Ignored
+	policy.ConfigChanged(&corev1.ConfigMap{Data: map[string]string{config.EventingKafkaSettingsConfigKey: `
+receiver:
+  policy:
+    defaultAction: maybe
+`}})
+	assert.Equal(t, ActionReject, policy.currentState().defaultAction)
+	assert.Len(t, policy.currentState().rules, 1)
+}
+
+// Test The wildcardMatch() Functionality
+func TestWildcardMatch(t *testing.T) {
+	assert.True(t, wildcardMatch("exact", "exact"))
+	assert.False(t, wildcardMatch("exact", "exactly"))
+	assert.True(t, wildcardMatch("/namespaces/a/*", "/namespaces/a/pingsources/ping"))
+	assert.False(t, wildcardMatch("/namespaces/a/*", "/namespaces/ab/pingsources/ping"))
+	assert.True(t, wildcardMatch("*.example.com", "api.example.com"))
+	assert.True(t, wildcardMatch("a*b*c", "aXbYc"))
+	assert.False(t, wildcardMatch("ab*b", "ab"))
+	assert.True(t, wildcardMatch("*", ""))
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package producer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/constants"
+)
+
+// The Prefix Of The Kafka Header Keys Of CloudEvent Attributes & Extensions (As In The CloudEvents Kafka Protocol Binding)
+const ceHeaderPrefix = "ce_"
+
+// The Largest Value Buffer Returned To The Pool (Larger Ones, From Occasional Large Events, Are Left To The GC)
+const maxPooledValueSize = 1024 * 1024
+
+// The Pre-Computed Kafka Header Keys (Shared By Every Produced Message, So They Must Never Be Modified)
+var (
+	contentTypeHeaderKey = []byte(constants.KafkaHeaderKeyContentType)
+	attributeHeaderKeys  = newAttributeHeaderKeys()
+)
+
+// The Pool Of producerMessageBuffers (Reused Once Their Messages Have Been Sent)
+var producerMessageBufferPool = sync.Pool{New: func() interface{} { return &producerMessageBuffer{} }}
+
+// Utility Function For Creating The Kafka Header Keys Of The Attributes Of Every CloudEvent Spec Version
+func newAttributeHeaderKeys() map[string][]byte {
+	headerKeys := make(map[string][]byte)
+	for _, version := range spec.VS.Versions() {
+		for _, attribute := range version.Attributes() {
+			headerKeys[attribute.Name()] = []byte(ceHeaderPrefix + attribute.Name())
+		}
+	}
+	return headerKeys
+}
+
+//
+// Reusable Sarama ProducerMessage & Buffers
+//
+// The ProducerMessage written from a CloudEvents Message, along with the buffer holding its value and the arena
+// holding its header values (and any extension header keys), are reused for subsequent messages once it has been
+// sent rather than being allocated for every event.  Attribute header keys are shared rather than allocated at all.
+// The SyncProducer must therefore be done with the message when SendMessage() returns, which holds for the Sarama
+// SyncProducer (whose produce requests have been answered by then).
+//
+type producerMessageBuffer struct {
+	message sarama.ProducerMessage
+	value   bytes.Buffer
+	arena   []byte
+}
+
+// Get A producerMessageBuffer From The Pool For A Message To The Specified Topic
+func newProducerMessageBuffer(topicName string) *producerMessageBuffer {
+	buffer := producerMessageBufferPool.Get().(*producerMessageBuffer)
+	buffer.message.Topic = topicName
+	return buffer
+}
+
+// Return The producerMessageBuffer To The Pool (Its Message Must Not Be Used Afterwards)
+func (b *producerMessageBuffer) release() {
+	if b.value.Cap() > maxPooledValueSize || cap(b.arena) > maxPooledValueSize {
+		return
+	}
+	b.message = sarama.ProducerMessage{Headers: b.message.Headers[:0]}
+	b.value.Reset()
+	b.arena = b.arena[:0]
+	producerMessageBufferPool.Put(b)
+}
+
+//
+// Write The Specified CloudEvents Message To The Buffer's ProducerMessage
+//
+// This is the equivalent of the CloudEvents kafka_sarama WriteProducerMessage(), including its mapping of the
+// partitionkey extension to the record key unless keyMapping is false, but writes into the reused buffers.
+//
+func (b *producerMessageBuffer) write(ctx context.Context, message binding.Message, keyMapping bool, transformers ...binding.Transformer) error {
+	var key string
+	if keyMapping {
+		transformers = append(transformers, binding.TransformerFunc(func(reader binding.MessageMetadataReader, _ binding.MessageMetadataWriter) error {
+			if extension := reader.GetExtension(constants.ExtensionKeyPartitionKey); !types.IsZero(extension) {
+				extensionString, err := types.Format(extension)
+				if err != nil {
+					return err
+				}
+				key = extensionString
+			}
+			return nil
+		}))
+	}
+	writer := (*producerMessageWriter)(b)
+	_, err := binding.Write(ctx, message, writer, writer, transformers...)
+	if key != "" {
+		b.message.Key = sarama.StringEncoder(key)
+	}
+	return err
+}
+
+// Get A Transformer Setting The Timestamp Of The Buffer's ProducerMessage From The CloudEvent's Time Attribute
+//
+// The record timestamp otherwise defaults to the time it is produced.  A missing or unparsable time attribute leaves
+// that default, as does a topic with the LogAppendTime timestamp type (whose brokers overwrite the timestamp).
+//
+func (b *producerMessageBuffer) timestampTransformer() binding.Transformer {
+	return binding.TransformerFunc(func(reader binding.MessageMetadataReader, _ binding.MessageMetadataWriter) error {
+		if _, value := reader.GetAttribute(spec.Time); value != nil {
+			if timestamp, err := types.ToTime(value); err == nil && !timestamp.IsZero() {
+				b.message.Timestamp = timestamp
+			}
+		}
+		return nil
+	})
+}
+
+// Verify The producerMessageWriter Implements The CloudEvents binding.StructuredWriter & BinaryWriter
+var _ binding.StructuredWriter = &producerMessageWriter{}
+var _ binding.BinaryWriter = &producerMessageWriter{}
+
+// The Writer Of A producerMessageBuffer's ProducerMessage
+type producerMessageWriter producerMessageBuffer
+
+// Implement The binding.StructuredWriter SetStructuredEvent() Function
+func (w *producerMessageWriter) SetStructuredEvent(_ context.Context, format format.Format, event io.Reader) error {
+	w.message.Headers = append(w.message.Headers[:0], sarama.RecordHeader{Key: contentTypeHeaderKey, Value: w.arenaBytes("", format.MediaType())})
+	return w.SetData(event)
+}
+
+// Implement The binding.BinaryWriter Start() Function
+func (w *producerMessageWriter) Start(context.Context) error {
+	w.message.Headers = w.message.Headers[:0]
+	return nil
+}
+
+// Implement The binding.BinaryWriter End() Function
+func (w *producerMessageWriter) End(context.Context) error {
+	return nil
+}
+
+// Implement The binding.BinaryWriter SetData() Function (Reading The Data Into The Reused Value Buffer)
+func (w *producerMessageWriter) SetData(data io.Reader) error {
+	w.value.Reset()
+	if _, err := w.value.ReadFrom(data); err != nil {
+		return err
+	}
+	w.message.Value = sarama.ByteEncoder(w.value.Bytes())
+	return nil
+}
+
+// Implement The binding.BinaryWriter SetAttribute() Function
+func (w *producerMessageWriter) SetAttribute(attribute spec.Attribute, value interface{}) error {
+	headerKey := contentTypeHeaderKey
+	if attribute.Kind() != spec.DataContentType {
+		headerKey = attributeHeaderKeys[attribute.Name()]
+		if headerKey == nil {
+			headerKey = w.arenaBytes(ceHeaderPrefix, attribute.Name())
+		}
+	}
+	return w.setHeader(headerKey, value)
+}
+
+// Implement The binding.BinaryWriter SetExtension() Function
+func (w *producerMessageWriter) SetExtension(name string, value interface{}) error {
+	if value == nil {
+		w.removeHeader(ceHeaderPrefix, name)
+		return nil
+	}
+	return w.setHeader(w.arenaBytes(ceHeaderPrefix, name), value)
+}
+
+// Add A Header With The Specified Key & (Formatted) Value, Or Remove Any Header With The Key If The Value Is nil
+func (w *producerMessageWriter) setHeader(headerKey []byte, value interface{}) error {
+	if value == nil {
+		w.removeHeader("", string(headerKey))
+		return nil
+	}
+	valueString, err := types.Format(value)
+	if err != nil {
+		return err
+	}
+	w.message.Headers = append(w.message.Headers, sarama.RecordHeader{Key: headerKey, Value: w.arenaBytes("", valueString)})
+	return nil
+}
+
+// Remove The First Header With The Specified Key Prefix & Name
+func (w *producerMessageWriter) removeHeader(prefix string, name string) {
+	for index, header := range w.message.Headers {
+		if len(header.Key) == len(prefix)+len(name) && string(header.Key[:len(prefix)]) == prefix && string(header.Key[len(prefix):]) == name {
+			w.message.Headers = append(w.message.Headers[:index], w.message.Headers[index+1:]...)
+			return
+		}
+	}
+}
+
+// Append The Specified Strings To The Arena, Returning Their Bytes (Which Remain Valid As The Arena Grows)
+func (w *producerMessageWriter) arenaBytes(prefix string, value string) []byte {
+	start := len(w.arena)
+	w.arena = append(w.arena, prefix...)
+	w.arena = append(w.arena, value...)
+	return w.arena[start:len(w.arena):len(w.arena)]
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package producer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/stretchr/testify/assert"
+	receivertesting "knative.dev/eventing-kafka/pkg/channel/distributed/receiver/testing"
+)
+
+// Test That The producerMessageBuffer Writes The Same ProducerMessages As The CloudEvents kafka_sarama Protocol
+func TestProducerMessageBufferWrite(t *testing.T) {
+
+	// A Transformer Removing The Subject & An Extension And Adding Another Extension
+	transformer := binding.TransformerFunc(func(_ binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		_ = writer.SetAttribute(spec.V1.AttributeFromKind(spec.Subject), nil)
+		_ = writer.SetExtension("partitionkey", nil)
+		return writer.SetExtension("added", "value")
+	})
+
+	// Define The TestCases
+	testCases := []struct {
+		name         string
+		message      func() binding.Message
+		keyMapping   bool
+		transformers []binding.Transformer
+	}{
+		{name: "Binary", message: func() binding.Message { return receivertesting.CreateBindingMessage(cloudevents.VersionV1) }, keyMapping: true},
+		{name: "Binary v0.3", message: func() binding.Message { return receivertesting.CreateBindingMessage(cloudevents.VersionV03) }, keyMapping: true},
+		{name: "Binary Without Key Mapping", message: func() binding.Message { return receivertesting.CreateBindingMessage(cloudevents.VersionV1) }},
+		{name: "Binary Transformed", message: func() binding.Message { return receivertesting.CreateBindingMessage(cloudevents.VersionV1) }, keyMapping: true, transformers: []binding.Transformer{transformer}},
+		{name: "Structured", message: func() binding.Message { return createStructuredMessage(t) }, keyMapping: true},
+	}
+
+	// Run The TestCases Twice, Reusing The Released Buffers The Second Time
+	for _, pass := range []string{"New", "Reused"} {
+		for _, testCase := range testCases {
+			t.Run(pass+" "+testCase.name, func(t *testing.T) {
+				ctx := context.Background()
+				wantMessage := &sarama.ProducerMessage{Topic: receivertesting.TopicName}
+				if !testCase.keyMapping {
+					ctx = kafkasaramaprotocol.WithSkipKeyMapping(ctx)
+				}
+				assert.Nil(t, kafkasaramaprotocol.WriteProducerMessage(ctx, testCase.message(), wantMessage, testCase.transformers...))
+
+				buffer := newProducerMessageBuffer(receivertesting.TopicName)
+				defer buffer.release()
+				assert.Nil(t, buffer.write(context.Background(), testCase.message(), testCase.keyMapping, testCase.transformers...))
+				assert.Equal(t, wantMessage.Topic, buffer.message.Topic)
+				assert.Equal(t, wantMessage.Key, buffer.message.Key)
+				assert.Equal(t, wantMessage.Value, buffer.message.Value)
+				assert.ElementsMatch(t, wantMessage.Headers, buffer.message.Headers)
+			})
+		}
+	}
+}
+
+// Test That Released producerMessageBuffers Are Reset (Oversized Ones Not Being Pooled)
+func TestProducerMessageBufferRelease(t *testing.T) {
+	buffer := newProducerMessageBuffer(receivertesting.TopicName)
+	assert.Nil(t, buffer.write(context.Background(), receivertesting.CreateBindingMessage(cloudevents.VersionV1), true))
+	assert.NotEmpty(t, buffer.message.Headers)
+	buffer.release()
+	assert.Empty(t, buffer.message.Topic)
+	assert.Nil(t, buffer.message.Key)
+	assert.Nil(t, buffer.message.Value)
+	assert.Empty(t, buffer.message.Headers)
+	assert.Zero(t, buffer.value.Len())
+	assert.Empty(t, buffer.arena)
+
+	oversizedBuffer := &producerMessageBuffer{message: sarama.ProducerMessage{Topic: receivertesting.TopicName}}
+	oversizedBuffer.value.Write(bytes.Repeat([]byte("x"), maxPooledValueSize+1))
+	oversizedBuffer.release()
+	assert.Equal(t, receivertesting.TopicName, oversizedBuffer.message.Topic) // Not Reset As It Was Not Pooled
+}
+
+// Utility Function For Creating A Structured Mode Message
+func createStructuredMessage(t *testing.T) binding.Message {
+	event := receivertesting.CreateCloudEvent(cloudevents.VersionV1)
+	eventBytes, err := event.MarshalJSON()
+	assert.Nil(t, err)
+	return kafkasaramaprotocol.NewMessage(eventBytes, cloudevents.ApplicationCloudEventsJSON, nil)
+}
@@ -19,30 +19,39 @@ package producer
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
 
+	"knative.dev/eventing-kafka/pkg/common/headers"
 	"knative.dev/eventing-kafka/pkg/common/tracing"
 
 	"go.opencensus.io/trace"
 
 	"github.com/Shopify/sarama"
-	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/types"
 	gometrics "github.com/rcrowley/go-metrics"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
 	kafkaproducer "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/producer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
 	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/latency"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/health"
-	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/util"
-	eventingChannel "knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/keytemplate"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/status"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/throttle"
 )
 
 // Producer Struct
 type Producer struct {
 	logger             *zap.Logger
+	kafkaClient        sarama.Client
 	kafkaProducer      sarama.SyncProducer
 	healthServer       *health.Server
 	statsReporter      metrics.StatsReporter
@@ -51,6 +60,14 @@ type Producer struct {
 	metricsStoppedChan chan struct{}
 	configuration      *sarama.Config
 	brokers            []string
+	faultInjector      *faults.Injector
+	throttle           *throttle.Throttle
+	headersPolicy      *headers.Policy
+	envelope           *encryption.Envelope
+	hopTimestamps      bool
+	ceTimeTimestamps   bool
+	errorTracker       *status.ErrorTracker
+	producerFactory    kafkaproducer.SyncProducerFactory
 }
 
 // Initialize The Producer
@@ -58,10 +75,27 @@ func NewProducer(logger *zap.Logger,
 	config *sarama.Config,
 	brokers []string,
 	statsReporter metrics.StatsReporter,
-	healthServer *health.Server) (*Producer, error) {
-
-	// Create The Kafka Producer Using The Specified Kafka Authentication
-	kafkaProducer, metricsRegistry, err := createSyncProducerWrapper(config, brokers)
+	healthServer *health.Server,
+	faultInjector *faults.Injector,
+	throttle *throttle.Throttle,
+	headersPolicy *headers.Policy,
+	envelope *encryption.Envelope,
+	hopTimestamps bool,
+	ceTimeTimestamps bool,
+	errorTracker *status.ErrorTracker,
+	producerFactory kafkaproducer.SyncProducerFactory) (*Producer, error) {
+
+	// Create The Kafka Producer Using The Specified Kafka Authentication (From A Client Whose Brokers Are Reported In The Status)
+	// An Injected (Non-nil) Factory Replaces The Connection To The Brokers, Leaving No Client To Report
+	var kafkaClient sarama.Client
+	var kafkaProducer sarama.SyncProducer
+	var metricsRegistry gometrics.Registry
+	var err error
+	if producerFactory != nil {
+		kafkaProducer, metricsRegistry, err = kafkaproducer.CreateSyncProducerWithFactory(producerFactory, brokers, config)
+	} else {
+		kafkaClient, kafkaProducer, metricsRegistry, err = createSyncProducerWrapper(config, brokers)
+	}
 	if err != nil {
 		logger.Error("Failed To Create Kafka SyncProducer - Exiting", zap.Error(err), zap.Any("Brokers", brokers))
 		return nil, err
@@ -69,9 +103,13 @@ func NewProducer(logger *zap.Logger,
 		logger.Info("Successfully Created Kafka SyncProducer")
 	}
 
+	// Encrypt The Values Of The Produced Records If Envelope Encryption Is Enabled (Non-nil)
+	kafkaProducer = envelope.SyncProducer(kafkaProducer)
+
 	// Create A New Producer
 	producer := &Producer{
 		logger:             logger,
+		kafkaClient:        kafkaClient,
 		kafkaProducer:      kafkaProducer,
 		healthServer:       healthServer,
 		statsReporter:      statsReporter,
@@ -80,6 +118,14 @@ func NewProducer(logger *zap.Logger,
 		metricsStoppedChan: make(chan struct{}),
 		configuration:      config,
 		brokers:            brokers,
+		faultInjector:      faultInjector,
+		throttle:           throttle,
+		headersPolicy:      headersPolicy,
+		envelope:           envelope,
+		hopTimestamps:      hopTimestamps,
+		ceTimeTimestamps:   ceTimeTimestamps,
+		errorTracker:       errorTracker,
+		producerFactory:    producerFactory,
 	}
 
 	// Start Observing Metrics
@@ -94,12 +140,15 @@ func NewProducer(logger *zap.Logger,
 }
 
 // Wrapper Around Common Kafka SyncProducer Creation To Facilitate Unit Testing
-var createSyncProducerWrapper = func(config *sarama.Config, brokers []string) (sarama.SyncProducer, gometrics.Registry, error) {
-	return kafkaproducer.CreateSyncProducer(brokers, config)
+var createSyncProducerWrapper = func(config *sarama.Config, brokers []string) (sarama.Client, sarama.SyncProducer, gometrics.Registry, error) {
+	return kafkaproducer.CreateClientSyncProducer(brokers, config)
 }
 
 // Produce A KafkaMessage From The Specified CloudEvent To The Specified Topic And Wait For The Delivery Report
-func (p *Producer) ProduceKafkaMessage(ctx context.Context, channelReference eventingChannel.ChannelReference, message binding.Message, transformers ...binding.Transformer) error {
+// If EventTypeRouting Is Specified (Non-nil) Then Routed Event Types Are Produced To Their Sub-Topic Instead
+// If The Topic Is Compacted Then The Record Key Is Set To The CloudEvent's PartitionKey Or Subject (One Is Required)
+// If A KeyTemplate Is Specified (Non-nil) Then The Record Key Is Instead Rendered From The CloudEvent
+func (p *Producer) ProduceKafkaMessage(ctx context.Context, topicName string, eventTypeRouting *routing.EventTypeRouting, compacted bool, keyTemplate *keytemplate.KeyTemplate, message binding.Message, transformers ...binding.Transformer) error {
 
 	// Validate The Kafka Producer (Must Be Pre-Initialized)
 	if p.kafkaProducer == nil {
@@ -107,35 +156,162 @@ func (p *Producer) ProduceKafkaMessage(ctx context.Context, channelReference eve
 		return errors.New("uninitialized kafka producer - unable to produce message")
 	}
 
-	// Get The Topic Name From The ChannelReference
-	topicName := util.TopicName(channelReference)
-	logger := p.logger.With(zap.String("Topic", topicName))
+	// Route The Message To The Event Type's Sub-Topic If Enabled
+	if eventTypeRouting != nil {
+		var eventType string
+		var err error
+		message, transformers, eventType, err = getEventType(ctx, message, transformers)
+		if err != nil {
+			p.logger.Error("Failed To Determine Event Type For EventTypeRouting", zap.Error(err))
+			return err
+		}
+		topicName = eventTypeRouting.ProducerTopic(topicName, eventType)
+	}
+	topicField := zap.String("Topic", topicName) // Added To Each Log Entry Rather Than Creating A Logger For Every Message
+
+	// Initialize A Reused Sarama ProducerMessage With The Specified Topic Name (Released Once Sent)
+	producerMessageBuffer := newProducerMessageBuffer(topicName)
+	defer producerMessageBuffer.release()
+	producerMessage := &producerMessageBuffer.message
+	keyMapping := true
+
+	// Key The Message By The KafkaChannel's KeyTemplate (Overriding The PartitionKey Mapping Of The SaramaKafka Protocol)
+	if keyTemplate != nil {
+		var recordKey string
+		var err error
+		message, transformers, recordKey, err = keyTemplate.RecordKey(ctx, message, transformers)
+		if err != nil {
+			p.logger.Warn("Failed To Render Record Key From KeyTemplate", topicField, zap.Error(err))
+			return err
+		}
+		if len(recordKey) > 0 {
+			producerMessage.Key = sarama.StringEncoder(recordKey)
+			keyMapping = false
+		}
+	}
+
+	// Key The Message Deterministically For Compacted Topics (Which Reject Messages Without A Key)
+	if compacted && producerMessage.Key == nil {
+		if keyTemplate != nil {
+			p.logger.Warn("Message With Empty Rendered Key Cannot Be Produced To Compacted Topic", topicField)
+			return errors.New("messages produced to a compacted channel require a non-empty key from the key template")
+		}
+		var recordKey string
+		var err error
+		message, transformers, recordKey, err = getRecordKey(ctx, message, transformers)
+		if err != nil {
+			p.logger.Error("Failed To Determine Record Key For Compacted Topic", topicField, zap.Error(err))
+			return err
+		}
+		if len(recordKey) == 0 {
+			p.logger.Warn("Message Without PartitionKey Or Subject Cannot Be Produced To Compacted Topic", topicField)
+			return errors.New("messages produced to a compacted channel require a partitionkey extension or subject")
+		}
+		producerMessage.Key = sarama.StringEncoder(recordKey)
+	}
 
-	// Initialize The Sarama ProducerMessage With The Specified Topic Name
-	producerMessage := &sarama.ProducerMessage{Topic: topicName}
+	// Timestamp The Record With The CloudEvent's Time (Rather Than The Produce Time) If Configured
+	if p.ceTimeTimestamps {
+		transformers = append(transformers, producerMessageBuffer.timestampTransformer())
+	}
 
-	// Use The SaramaKafka Protocol To Convert The Binding Message To A ProducerMessage
-	err := kafkasaramaprotocol.WriteProducerMessage(ctx, message, producerMessage, transformers...)
+	// Write The Binding Message To The ProducerMessage (As The SaramaKafka Protocol Would, But Into The Reused Buffers)
+	err := producerMessageBuffer.write(ctx, message, keyMapping, transformers...)
 	if err != nil {
 		p.logger.Error("Failed To Convert BindingMessage To Sarama ProducerMessage", zap.Error(err))
 		return err
 	}
 
+	// Write Back Any CloudEvent Extensions Propagated From Kafka Headers (Per The Headers Policy)
+	producerMessage.Headers = append(producerMessage.Headers, p.headersPolicy.ProducerHeaders(producerMessage.Headers)...)
+
+	// Never Accept The Headers Reserved For The Envelope Encryption From Inbound Events (Even If Encryption Is Disabled)
+	producerMessage.Headers = encryption.StripReservedHeaders(producerMessage.Headers)
+
 	// Add The "traceparent" And "tracestate" Headers To The Message (Helps Tie Related Messages Together In Traces)
 	producerMessage.Headers = append(producerMessage.Headers, tracing.SerializeTrace(trace.FromContext(ctx).SpanContext())...)
 
-	// Produce The Kafka Message To The Kafka Topic
-	logger.Debug("Producing Kafka Message", zap.Any("Headers", producerMessage.Headers), zap.Any("Message", producerMessage.Value))
+	// Inject Any Configured Produce Failures (Non-Production Only)
+	err = p.faultInjector.ProduceFailure()
+	if err != nil {
+		p.logger.Error("Failed To Send Message To Kafka", topicField, zap.Error(err))
+		p.errorTracker.Record(topicName, err)
+		return err
+	}
+
+	// Inject The Times At Which The Event Was Received & Produced (For Tracking The End-To-End Latency) If Enabled
+	if p.hopTimestamps {
+		producerMessage.Headers = append(producerMessage.Headers, latency.ProducerHeaders(latency.ReceivedTime(ctx), time.Now())...)
+	}
+
+	// Produce The Kafka Message To The Kafka Topic (Checking The Debug Level First To Avoid Creating The Fields Of Every Message)
+	if checkedEntry := p.logger.Check(zap.DebugLevel, "Producing Kafka Message"); checkedEntry != nil {
+		checkedEntry.Write(topicField, zap.Any("Headers", producerMessage.Headers), zap.Any("Message", producerMessage.Value))
+	}
+	sendStart := time.Now()
 	partition, offset, err := p.kafkaProducer.SendMessage(producerMessage)
+	p.throttle.Observe(time.Since(sendStart)) // Slow Produce Requests Indicate Broker Quota Throttling
 	if err != nil {
-		logger.Error("Failed To Send Message To Kafka", zap.Error(err))
+		p.logger.Error("Failed To Send Message To Kafka", topicField, zap.Error(err))
+		p.errorTracker.Record(topicName, err)
 		return err
 	} else {
-		logger.Debug("Successfully Sent Message To Kafka", zap.Int32("Partition", partition), zap.Int64("Offset", offset))
+		p.logger.Debug("Successfully Sent Message To Kafka", topicField, zap.Int32("Partition", partition), zap.Int64("Offset", offset))
 		return nil
 	}
 }
 
+// Get The CloudEvent Type Of The Specified Message (Non Binary/Event Encoded Messages Are Converted To Event Messages)
+func getEventType(ctx context.Context, message binding.Message, transformers []binding.Transformer) (binding.Message, []binding.Transformer, string, error) {
+
+	// Read The Type Attribute Directly When Possible (Structured Messages Have No Attribute Metadata)
+	encoding := message.ReadEncoding()
+	if metadataReader, ok := message.(binding.MessageMetadataReader); ok && (encoding == binding.EncodingBinary || encoding == binding.EncodingEvent) {
+		if _, eventType := metadataReader.GetAttribute(spec.Type); eventType != nil {
+			eventTypeString, err := types.ToString(eventType)
+			return message, transformers, eventTypeString, err
+		}
+	}
+
+	// Otherwise Convert The Message To An Event (Applying Transformers) & Return A New Event Message
+	event, err := binding.ToEvent(ctx, message, transformers...)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return binding.ToMessage(event), nil, event.Type(), nil
+}
+
+// Get The Record Key Of The Specified Message, Being Its PartitionKey Extension Or Else Its Subject (Empty If Neither)
+func getRecordKey(ctx context.Context, message binding.Message, transformers []binding.Transformer) (binding.Message, []binding.Transformer, string, error) {
+
+	// Convert Any Non Binary/Event Encoded Messages To Event Messages (Structured Messages Have No Attribute Metadata)
+	encoding := message.ReadEncoding()
+	metadataReader, ok := message.(binding.MessageMetadataReader)
+	if !ok || (encoding != binding.EncodingBinary && encoding != binding.EncodingEvent) {
+		event, err := binding.ToEvent(ctx, message, transformers...)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		eventMessage := (*binding.EventMessage)(event)
+		message, transformers, metadataReader = eventMessage, nil, eventMessage
+	}
+
+	// Prefer The PartitionKey Extension
+	if partitionKey := metadataReader.GetExtension(constants.ExtensionKeyPartitionKey); partitionKey != nil {
+		partitionKeyString, err := types.ToString(partitionKey)
+		if err != nil || len(partitionKeyString) > 0 {
+			return message, transformers, partitionKeyString, err
+		}
+	}
+
+	// Otherwise Fall Back To The Subject
+	if _, subject := metadataReader.GetAttribute(spec.Subject); subject != nil {
+		subjectString, err := types.ToString(subject)
+		return message, transformers, subjectString, err
+	}
+	return message, transformers, "", nil
+}
+
 // Async Process For Observing Kafka Metrics
 func (p *Producer) ObserveMetrics(interval time.Duration) {
 
@@ -180,6 +356,34 @@ func (p *Producer) Close() {
 	} else {
 		p.logger.Info("Successfully Closed Kafka Producer")
 	}
+
+	// Close The Kafka Client (Which The SyncProducer Does Not Own)
+	if p.kafkaClient != nil {
+		err = p.kafkaClient.Close()
+		if err != nil {
+			p.logger.Error("Failed To Close Kafka Client", zap.Error(err))
+		}
+	}
+}
+
+// Get The Connection States Of The Kafka Brokers Known To The Producer's Client (Sorted By ID)
+func (p *Producer) BrokerStatuses() []status.BrokerStatus {
+	if p == nil || p.kafkaClient == nil {
+		return nil
+	}
+	brokers := p.kafkaClient.Brokers()
+	brokerStatuses := make([]status.BrokerStatus, 0, len(brokers))
+	for _, broker := range brokers {
+		brokerStatus := status.BrokerStatus{ID: broker.ID(), Addr: broker.Addr()}
+		connected, err := broker.Connected()
+		brokerStatus.Connected = connected
+		if err != nil {
+			brokerStatus.Error = err.Error()
+		}
+		brokerStatuses = append(brokerStatuses, brokerStatus)
+	}
+	sort.Slice(brokerStatuses, func(i, j int) bool { return brokerStatuses[i].ID < brokerStatuses[j].ID })
+	return brokerStatuses
 }
 
 // ConfigChanged is called by the configMapObserver handler function in main() so that
@@ -207,6 +411,8 @@ func (p *Producer) ConfigChanged(configMap *v1.ConfigMap) *Producer {
 
 		// Some of the current config settings may not be overridden by the configmap (username, password, etc.)
 		kafkasarama.UpdateSaramaConfig(newConfig, p.configuration.ClientID, p.configuration.Net.SASL.User, p.configuration.Net.SASL.Password)
+		kafkasarama.UpdateSaramaTokenProvider(newConfig, p.configuration.Net.SASL.TokenProvider)
+		kafkasarama.CarryForwardSaramaDialer(newConfig, p.configuration)
 
 		// Ignore the "Admin" and "Consumer" sections when comparing, as changes to those do not require restarting the Producer
 		if kafkasarama.ConfigEqual(newConfig, p.configuration, newConfig.Admin, newConfig.Consumer) {
@@ -218,7 +424,7 @@ func (p *Producer) ConfigChanged(configMap *v1.ConfigMap) *Producer {
 	// Create A New Producer With The New Configuration (Reusing All Other Existing Config)
 	p.logger.Info("Producer Changes Detected In New Configuration - Closing & Recreating Producer")
 	p.Close()
-	reconfiguredKafkaProducer, err := NewProducer(p.logger, newConfig, p.brokers, p.statsReporter, p.healthServer)
+	reconfiguredKafkaProducer, err := NewProducer(p.logger, newConfig, p.brokers, p.statsReporter, p.healthServer, p.faultInjector, p.throttle, p.headersPolicy, p.envelope, p.hopTimestamps, p.ceTimeTimestamps, p.errorTracker, p.producerFactory)
 	if err != nil {
 		p.logger.Fatal("Failed To Create Kafka Producer With New Configuration", zap.Error(err))
 		return nil
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package producer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"go.uber.org/zap"
+	receivertesting "knative.dev/eventing-kafka/pkg/channel/distributed/receiver/testing"
+)
+
+// Run with go test ./pkg/channel/distributed/receiver/producer/ -run=^$ -bench=. -benchmem
+
+// The Data Sizes Of The Benchmarked Events
+var benchmarkDataSizes = []int{256, 4096, 65536}
+
+// Benchmark Writing An Event To A ProducerMessage Via The CloudEvents kafka_sarama Protocol & The Pooled Buffers
+func BenchmarkWriteProducerMessage(b *testing.B) {
+	for _, dataSize := range benchmarkDataSizes {
+		event := createBenchmarkEvent(dataSize)
+		b.Run(fmt.Sprintf("kafka_sarama/%d", dataSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				producerMessage := &sarama.ProducerMessage{Topic: receivertesting.TopicName}
+				if err := kafkasaramaprotocol.WriteProducerMessage(context.Background(), binding.ToMessage(event), producerMessage); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("producerMessageBuffer/%d", dataSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				producerMessageBuffer := newProducerMessageBuffer(receivertesting.TopicName)
+				if err := producerMessageBuffer.write(context.Background(), binding.ToMessage(event), true); err != nil {
+					b.Fatal(err)
+				}
+				producerMessageBuffer.release()
+			}
+		})
+	}
+}
+
+// Benchmark Producing Events Through The Producer (To A SyncProducer Which Discards Them)
+func BenchmarkProduceKafkaMessage(b *testing.B) {
+	producer := &Producer{logger: zap.NewNop(), kafkaProducer: benchmarkSyncProducer{}}
+	for _, dataSize := range benchmarkDataSizes {
+		event := createBenchmarkEvent(dataSize)
+		b.Run(fmt.Sprintf("%d", dataSize), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(dataSize))
+			for i := 0; i < b.N; i++ {
+				if err := producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, binding.ToMessage(event)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Utility Function For Creating A CloudEvent With A JSON Payload Of (About) The Specified Size
+func createBenchmarkEvent(dataSize int) *cloudevents.Event {
+	event := receivertesting.CreateCloudEvent(cloudevents.VersionV1)
+	_ = event.SetData(cloudevents.ApplicationJSON, []byte(fmt.Sprintf(`{"payload":%q}`, strings.Repeat("x", dataSize))))
+	return event
+}
+
+// A SyncProducer Which Accepts & Discards Every Message (Without Retaining It, As The Sarama SyncProducer)
+type benchmarkSyncProducer struct{}
+
+func (benchmarkSyncProducer) SendMessage(*sarama.ProducerMessage) (int32, int64, error) {
+	return 0, 0, nil
+}
+
+func (benchmarkSyncProducer) SendMessages([]*sarama.ProducerMessage) error {
+	return nil
+}
+
+func (benchmarkSyncProducer) Close() error {
+	return nil
+}
@@ -20,9 +20,13 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/Shopify/sarama"
+	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/ghodss/yaml"
 	gometrics "github.com/rcrowley/go-metrics"
 	"github.com/stretchr/testify/assert"
@@ -30,10 +34,19 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/encryption"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/routing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/latency"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/constants"
 	channelhealth "knative.dev/eventing-kafka/pkg/channel/distributed/receiver/health"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/keytemplate"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/status"
 	receivertesting "knative.dev/eventing-kafka/pkg/channel/distributed/receiver/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/throttle"
+	"knative.dev/eventing-kafka/pkg/common/headers"
 	logtesting "knative.dev/pkg/logging/testing"
 	"knative.dev/pkg/system"
 
@@ -124,17 +137,47 @@ func TestNewProducer(t *testing.T) {
 	assert.True(t, producer.healthServer.ProducerReady())
 }
 
+// Test The NewProducer Constructor With An Injected SyncProducer Factory
+func TestNewProducerWithFactory(t *testing.T) {
+
+	// Stub The Kafka Producer Creation Wrapper To Verify It Is Not Used When A Factory Is Specified
+	createSyncProducerWrapperPlaceholder := createSyncProducerWrapper
+	createSyncProducerWrapper = func(config *sarama.Config, brokers []string) (sarama.Client, sarama.SyncProducer, gometrics.Registry, error) {
+		t.Fatal("Unexpected Connection To The Kafka Brokers")
+		return nil, nil, nil, nil
+	}
+	defer func() { createSyncProducerWrapper = createSyncProducerWrapperPlaceholder }()
+
+	// Create A Factory Returning A Mock Kafka SyncProducer
+	mockSyncProducer := receivertesting.NewMockSyncProducer()
+	factory := func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+		assert.Equal(t, []string{receivertesting.KafkaBrokers}, brokers)
+		return mockSyncProducer, nil
+	}
+
+	// Perform The Test
+	logger := logtesting.TestLogger(t).Desugar()
+	healthServer := channelhealth.NewChannelHealthServer("12345")
+	producer, err := NewProducer(logger, getSaramaConfigFromYaml(t, TestSaramaConfigYaml), []string{receivertesting.KafkaBrokers}, metrics.NewStatsReporter(logger), healthServer, nil, nil, nil, nil, false, false, nil, factory)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Equal(t, mockSyncProducer, producer.kafkaProducer)
+	assert.Nil(t, producer.kafkaClient)
+	assert.True(t, producer.healthServer.ProducerReady())
+	producer.Close()
+}
+
 // Test The ProduceKafkaMessage() Functionality For Event With PartitionKey
 func TestProduceKafkaMessage(t *testing.T) {
 
 	// Create Test Data
 	mockSyncProducer := receivertesting.NewMockSyncProducer()
 	producer := createTestProducer(t, mockSyncProducer)
-	channelReference := receivertesting.CreateChannelReference(receivertesting.ChannelName, receivertesting.ChannelNamespace)
 	bindingMessage := receivertesting.CreateBindingMessage(cloudevents.VersionV1)
 
 	// Perform The Test & Verify Results
-	err := producer.ProduceKafkaMessage(context.Background(), channelReference, bindingMessage)
+	err := producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, bindingMessage)
 	assert.Nil(t, err)
 
 	// Verify Message Was Produced Correctly
@@ -157,6 +200,331 @@ func TestProduceKafkaMessage(t *testing.T) {
 	receivertesting.ValidateProducerMessageHeader(t, producerMessage.Headers, constants.CeKafkaHeaderKeyPartitionKey, receivertesting.PartitionKey)
 }
 
+// Test The ProduceKafkaMessage() Functionality With EventTypeRouting
+func TestProduceKafkaMessageEventTypeRouting(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name          string
+		eventTypes    string
+		structured    bool
+		expectedTopic string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Routed Event Type", eventTypes: receivertesting.EventType, expectedTopic: receivertesting.TopicName + "." + receivertesting.EventType},
+		{name: "Routed Structured Event Type", eventTypes: receivertesting.EventType, structured: true, expectedTopic: receivertesting.TopicName + "." + receivertesting.EventType},
+		{name: "Unrouted Event Type", eventTypes: "com.example.other", expectedTopic: receivertesting.TopicName},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create Test Data
+			mockSyncProducer := receivertesting.NewMockSyncProducer()
+			producer := createTestProducer(t, mockSyncProducer)
+			eventTypeRouting, err := routing.NewEventTypeRouting(map[string]string{kafkaconstants.EventTypesAnnotation: testCase.eventTypes})
+			assert.Nil(t, err)
+			bindingMessage := receivertesting.CreateBindingMessage(cloudevents.VersionV1)
+			if testCase.structured {
+				event := receivertesting.CreateCloudEvent(cloudevents.VersionV1)
+				eventBytes, err := event.MarshalJSON()
+				assert.Nil(t, err)
+				bindingMessage = kafkasaramaprotocol.NewMessage(eventBytes, cloudevents.ApplicationCloudEventsJSON, nil)
+			}
+
+			// Perform The Test
+			err = producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, eventTypeRouting, false, nil, bindingMessage)
+
+			// Verify The Message Was Produced To The Expected Topic
+			assert.Nil(t, err)
+			producerMessage := mockSyncProducer.GetMessage()
+			assert.Equal(t, testCase.expectedTopic, producerMessage.Topic)
+			receivertesting.ValidateProducerMessageHeader(t, producerMessage.Headers, constants.CeKafkaHeaderKeyType, receivertesting.EventType)
+		})
+	}
+}
+
+// Test The ProduceKafkaMessage() Functionality For Compacted Topics
+func TestProduceKafkaMessageCompacted(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name         string
+		partitionKey bool
+		subject      bool
+		structured   bool
+		expectedKey  string
+		expectErr    bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "PartitionKey", partitionKey: true, subject: true, expectedKey: receivertesting.PartitionKey},
+		{name: "Structured PartitionKey", partitionKey: true, subject: true, structured: true, expectedKey: receivertesting.PartitionKey},
+		{name: "Subject", subject: true, expectedKey: receivertesting.EventSubject},
+		{name: "Structured Subject", subject: true, structured: true, expectedKey: receivertesting.EventSubject},
+		{name: "No PartitionKey Or Subject", expectErr: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create Test Data
+			mockSyncProducer := receivertesting.NewMockSyncProducer()
+			producer := createTestProducer(t, mockSyncProducer)
+			event := receivertesting.CreateCloudEvent(cloudevents.VersionV1)
+			if !testCase.partitionKey {
+				event.SetExtension(constants.ExtensionKeyPartitionKey, nil)
+			}
+			if !testCase.subject {
+				event.SetSubject("")
+			}
+			bindingMessage := binding.ToMessage(event)
+			if testCase.structured {
+				eventBytes, err := event.MarshalJSON()
+				assert.Nil(t, err)
+				bindingMessage = kafkasaramaprotocol.NewMessage(eventBytes, cloudevents.ApplicationCloudEventsJSON, nil)
+			}
+
+			// Perform The Test
+			err := producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, true, nil, bindingMessage)
+
+			// Verify The Message Was Produced With The Expected Key (Or Rejected)
+			if testCase.expectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				producerMessage := mockSyncProducer.GetMessage()
+				key, err := producerMessage.Key.Encode()
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.expectedKey, string(key))
+				receivertesting.ValidateProducerMessageHeader(t, producerMessage.Headers, constants.CeKafkaHeaderKeyId, receivertesting.EventId)
+			}
+		})
+	}
+}
+
+// Test The ProduceKafkaMessage() Functionality With A KeyTemplate
+func TestProduceKafkaMessageKeyTemplate(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		template    string
+		compacted   bool
+		expectedKey string
+		expectErr   bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Rendered Key", template: "{{.Extensions.tenantid}}-{{.Subject}}", expectedKey: "TestTenant-" + receivertesting.EventSubject},
+		{name: "Rendered Key Compacted", template: "{{.Extensions.tenantid}}", compacted: true, expectedKey: "TestTenant"},
+		{name: "Empty Key Falls Back To PartitionKey", template: `{{""}}`, expectedKey: receivertesting.PartitionKey},
+		{name: "Empty Key Compacted", template: `{{""}}`, compacted: true, expectErr: true},
+		{name: "Missing Extension", template: "{{.Extensions.missing}}", expectErr: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create Test Data
+			mockSyncProducer := receivertesting.NewMockSyncProducer()
+			producer := createTestProducer(t, mockSyncProducer)
+			event := receivertesting.CreateCloudEvent(cloudevents.VersionV1)
+			event.SetExtension("tenantid", "TestTenant")
+			keyTemplate, err := keytemplate.NewKeyTemplate(map[string]string{kafkaconstants.KeyTemplateAnnotation: testCase.template})
+			assert.Nil(t, err)
+
+			// Perform The Test
+			err = producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, testCase.compacted, keyTemplate, binding.ToMessage(event))
+
+			// Verify The Message Was Produced With The Expected Key (Or Rejected)
+			if testCase.expectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				producerMessage := mockSyncProducer.GetMessage()
+				key, err := producerMessage.Key.Encode()
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.expectedKey, string(key))
+				receivertesting.ValidateProducerMessageHeader(t, producerMessage.Headers, constants.CeKafkaHeaderKeyId, receivertesting.EventId)
+			}
+		})
+	}
+}
+
+// Test The ProduceKafkaMessage() Functionality With Injected Produce Failures
+func TestProduceKafkaMessageFaultInjection(t *testing.T) {
+
+	// Create Test Data
+	mockSyncProducer := receivertesting.NewMockSyncProducer()
+	producer := createTestProducer(t, mockSyncProducer)
+	producer.faultInjector = faults.NewInjector(producer.logger, commonconfig.EKFaultInjectionConfig{Enabled: true, ProduceFailurePercent: 100})
+	bindingMessage := receivertesting.CreateBindingMessage(cloudevents.VersionV1)
+
+	// Perform The Test & Verify Results
+	err := producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, bindingMessage)
+	assert.Equal(t, faults.ErrInjectedProduceFailure, err)
+
+	// Verify The Failure Is Recorded For The Status Endpoint
+	producer.errorTracker = status.NewErrorTracker(time.Minute)
+	err = producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, bindingMessage)
+	assert.Equal(t, faults.ErrInjectedProduceFailure, err)
+	assert.Equal(t, 1, producer.errorTracker.Summaries()[receivertesting.TopicName][status.CategoryInjected].Count)
+}
+
+// Test The Producer's BrokerStatuses() Functionality
+func TestBrokerStatuses(t *testing.T) {
+
+	// A Producer Without A Client Reports No Brokers
+	producer := createTestProducer(t, receivertesting.NewMockSyncProducer())
+	assert.Nil(t, producer.BrokerStatuses())
+
+	// Create A Client Of A Mock Kafka Broker
+	mockBroker := sarama.NewMockBroker(t, 1)
+	defer mockBroker.Close()
+	mockBroker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).SetBroker(mockBroker.Addr(), mockBroker.BrokerID()),
+	})
+	client, err := sarama.NewClient([]string{mockBroker.Addr()}, sarama.NewConfig())
+	assert.Nil(t, err)
+	defer client.Close()
+
+	// Perform The Test & Verify Results
+	producer.kafkaClient = client
+	brokerStatuses := producer.BrokerStatuses()
+	assert.Len(t, brokerStatuses, 1)
+	assert.Equal(t, mockBroker.BrokerID(), brokerStatuses[0].ID)
+	assert.Equal(t, mockBroker.Addr(), brokerStatuses[0].Addr)
+	assert.Empty(t, brokerStatuses[0].Error)
+}
+
+// Test The ProduceKafkaMessage() Functionality With A Headers Policy
+func TestProduceKafkaMessageHeadersPolicy(t *testing.T) {
+
+	// Create Test Data (Writing The "partitionkey" Extension Back As An "x-key" Header)
+	mockSyncProducer := receivertesting.NewMockSyncProducer()
+	producer := createTestProducer(t, mockSyncProducer)
+	producer.headersPolicy = &headers.Policy{Enabled: true, Allow: []string{"x-*"}, StripPrefix: "x-", ExtensionPrefix: "partition"}
+	bindingMessage := receivertesting.CreateBindingMessage(cloudevents.VersionV1)
+
+	// Perform The Test & Verify Results
+	err := producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, bindingMessage)
+	assert.Nil(t, err)
+	producerMessage := mockSyncProducer.GetMessage()
+	assert.NotNil(t, producerMessage)
+	receivertesting.ValidateProducerMessageHeader(t, producerMessage.Headers, constants.CeKafkaHeaderKeyPartitionKey, receivertesting.PartitionKey)
+	receivertesting.ValidateProducerMessageHeader(t, producerMessage.Headers, "x-key", receivertesting.PartitionKey)
+}
+
+// Test The ProduceKafkaMessage() Functionality Never Writes Back The Headers Reserved For The Envelope Encryption
+func TestProduceKafkaMessageReservedHeaders(t *testing.T) {
+
+	// Create Test Data (A Headers Policy Which Would Write The "partitionkey" Extension Back As A "kn-encryption-key" Header)
+	mockSyncProducer := receivertesting.NewMockSyncProducer()
+	producer := createTestProducer(t, mockSyncProducer)
+	producer.headersPolicy = &headers.Policy{Enabled: true, Allow: []string{"kn-*"}, StripPrefix: "kn-encryption-", ExtensionPrefix: "partition"}
+	bindingMessage := receivertesting.CreateBindingMessage(cloudevents.VersionV1)
+
+	// Perform The Test & Verify The Reserved Header Was Stripped
+	err := producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, bindingMessage)
+	assert.Nil(t, err)
+	producerMessage := mockSyncProducer.GetMessage()
+	assert.NotNil(t, producerMessage)
+	receivertesting.ValidateProducerMessageHeader(t, producerMessage.Headers, constants.CeKafkaHeaderKeyPartitionKey, receivertesting.PartitionKey)
+	for _, header := range producerMessage.Headers {
+		assert.False(t, encryption.IsReservedHeader(string(header.Key)), string(header.Key))
+	}
+}
+
+// Test The ProduceKafkaMessage() Functionality With Hop Timestamps Enabled
+func TestProduceKafkaMessageHopTimestamps(t *testing.T) {
+
+	// Create Test Data
+	mockSyncProducer := receivertesting.NewMockSyncProducer()
+	producer := createTestProducer(t, mockSyncProducer)
+	producer.hopTimestamps = true
+	bindingMessage := receivertesting.CreateBindingMessage(cloudevents.VersionV1)
+	receivedTime := time.Now().Add(-time.Second)
+
+	// Perform The Test & Verify Results
+	err := producer.ProduceKafkaMessage(latency.WithReceivedTime(context.Background(), receivedTime), receivertesting.TopicName, nil, false, nil, bindingMessage)
+	assert.Nil(t, err)
+	producerMessage := mockSyncProducer.GetMessage()
+	receivertesting.ValidateProducerMessageHeader(t, producerMessage.Headers, latency.ReceivedTimeHeader, strconv.FormatInt(receivedTime.UnixNano(), 10))
+	producedHeader := receivertesting.GetProducerMessageHeader(t, producerMessage.Headers, latency.ProducedTimeHeader)
+	assert.NotNil(t, producedHeader)
+	producedNanos, err := strconv.ParseInt(string(producedHeader.Value), 10, 64)
+	assert.Nil(t, err)
+	assert.True(t, producedNanos > receivedTime.UnixNano())
+}
+
+// Test The ProduceKafkaMessage() Functionality With CloudEvent Time Timestamps Enabled
+func TestProduceKafkaMessageCeTimeTimestamps(t *testing.T) {
+
+	// Create Test Data
+	mockSyncProducer := receivertesting.NewMockSyncProducer()
+	producer := createTestProducer(t, mockSyncProducer)
+	producer.ceTimeTimestamps = true
+	ceTime := time.Date(2020, time.October, 1, 12, 30, 0, 0, time.UTC)
+	event := receivertesting.CreateCloudEvent(cloudevents.VersionV1)
+	event.SetTime(ceTime)
+
+	// Perform The Test & Verify The Record Is Timestamped With The CloudEvent's Time
+	err := producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, binding.ToMessage(event))
+	assert.Nil(t, err)
+	producerMessage := mockSyncProducer.GetMessage()
+	assert.True(t, ceTime.Equal(producerMessage.Timestamp))
+
+	// Verify A CloudEvent Without A Time Leaves The Timestamp To The Kafka Producer (The Produce Time)
+	err = producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, receivertesting.CreateBindingMessage(cloudevents.VersionV1))
+	assert.Nil(t, err)
+	producerMessage = mockSyncProducer.GetMessage()
+	assert.True(t, producerMessage.Timestamp.IsZero())
+
+	// Verify The Timestamp Is Not Set From The CloudEvent's Time Unless Enabled
+	producer.ceTimeTimestamps = false
+	err = producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, binding.ToMessage(event))
+	assert.Nil(t, err)
+	producerMessage = mockSyncProducer.GetMessage()
+	assert.True(t, producerMessage.Timestamp.IsZero())
+}
+
+// Test The ProduceKafkaMessage() Functionality With Throttling Enabled
+func TestProduceKafkaMessageThrottle(t *testing.T) {
+
+	// Create A Producer Whose Kafka SyncProducer Is Slower Than The Throttle's Latency Threshold
+	producer := createTestProducer(t, &slowSyncProducer{SyncProducer: receivertesting.NewMockSyncProducer(), delay: 5 * time.Millisecond})
+	producer.throttle = throttle.NewThrottle(producer.logger, commonconfig.EKThrottleConfig{Enabled: true, LatencyThresholdMillis: 1})
+	bindingMessage := receivertesting.CreateBindingMessage(cloudevents.VersionV1)
+
+	// Perform The Test
+	err := producer.ProduceKafkaMessage(context.Background(), receivertesting.TopicName, nil, false, nil, bindingMessage)
+
+	// Verify The Message Was Produced & The Throttle Is Backing Off
+	assert.Nil(t, err)
+	ok, retryAfter := producer.throttle.Acquire()
+	assert.False(t, ok)
+	assert.True(t, retryAfter > 0)
+}
+
+// Kafka SyncProducer Wrapper Delaying Every Sent Message
+type slowSyncProducer struct {
+	sarama.SyncProducer
+	delay time.Duration
+}
+
+func (p *slowSyncProducer) SendMessage(message *sarama.ProducerMessage) (int32, int64, error) {
+	time.Sleep(p.delay)
+	return p.SyncProducer.SendMessage(message)
+}
+
 func getBaseConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		TypeMeta: v1.TypeMeta{
@@ -177,9 +545,9 @@ func getBaseConfigMap() *corev1.ConfigMap {
 func TestConfigChanged(t *testing.T) {
 	// Stub The Kafka Producer Creation Wrapper With Test Version Returning Specified SyncProducer
 	createSyncProducerWrapperPlaceholder := createSyncProducerWrapper
-	createSyncProducerWrapper = func(config *sarama.Config, brokers []string) (sarama.SyncProducer, gometrics.Registry, error) {
+	createSyncProducerWrapper = func(config *sarama.Config, brokers []string) (sarama.Client, sarama.SyncProducer, gometrics.Registry, error) {
 		registry := gometrics.NewRegistry()
-		return receivertesting.NewMockSyncProducer(), registry, nil
+		return nil, receivertesting.NewMockSyncProducer(), registry, nil
 	}
 	defer func() { createSyncProducerWrapper = createSyncProducerWrapperPlaceholder }()
 
@@ -260,11 +628,11 @@ func createTestProducer(t *testing.T, kafkaSyncProducer sarama.SyncProducer) *Pr
 
 	// Stub The Kafka Producer Creation Wrapper With Test Version Returning Specified SyncProducer
 	createSyncProducerWrapperPlaceholder := createSyncProducerWrapper
-	createSyncProducerWrapper = func(config *sarama.Config, brokers []string) (sarama.SyncProducer, gometrics.Registry, error) {
+	createSyncProducerWrapper = func(config *sarama.Config, brokers []string) (sarama.Client, sarama.SyncProducer, gometrics.Registry, error) {
 		assert.Equal(t, testConfig, config)
 		assert.Equal(t, []string{receivertesting.KafkaBrokers}, brokers)
 		registry := gometrics.NewRegistry()
-		return kafkaSyncProducer, registry, nil
+		return nil, kafkaSyncProducer, registry, nil
 	}
 	defer func() { createSyncProducerWrapper = createSyncProducerWrapperPlaceholder }()
 
@@ -276,7 +644,7 @@ func createTestProducer(t *testing.T, kafkaSyncProducer sarama.SyncProducer) *Pr
 	statsReporter := metrics.NewStatsReporter(logger)
 
 	// Create The Producer
-	producer, err := NewProducer(logger, testConfig, []string{receivertesting.KafkaBrokers}, statsReporter, healthServer)
+	producer, err := NewProducer(logger, testConfig, []string{receivertesting.KafkaBrokers}, statsReporter, healthServer, nil, nil, nil, nil, false, false, nil, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, kafkaSyncProducer, producer.kafkaProducer)
 	assert.Equal(t, healthServer, producer.healthServer)
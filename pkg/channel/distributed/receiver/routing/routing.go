@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"fmt"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/pkg/network"
+)
+
+// Validate The Specified Receiver Addressing
+func ValidateAddressing(addressing string) error {
+	if !kafkautil.IsValidReceiverAddressing(addressing) {
+		return fmt.Errorf("invalid addressing %q: must be %q or %q", addressing, kafkaconstants.ReceiverAddressingHost, kafkaconstants.ReceiverAddressingPath)
+	}
+	return nil
+}
+
+//
+// Path-Based KafkaChannel Addressing
+//
+// With host-based addressing (the default) the KafkaChannel of a request is parsed from its Host, that of the
+// KafkaChannel's Service (an ExternalName of the receiver's Service).  With path-based addressing the KafkaChannels
+// are instead addressed by a /<namespace>/<name> path on the receiver's Service itself, which does not require the
+// KafkaChannel Services to be resolvable (e.g. by the DNS of a remote cluster or through an ingress).  The returned
+// Handler rewrites such requests to the root path of their KafkaChannel Service's Host, so that the ingress policy,
+// validation, batching and Knative MessageReceiver downstream resolve the KafkaChannel as they always have.  Requests
+// to the root path are left as-is (still host-based) and other paths are rejected with 404.  The next Handler is
+// returned as-is unless the addressing is path-based.
+//
+func Handler(logger *zap.Logger, addressing string, next nethttp.Handler) nethttp.Handler {
+	if addressing != kafkaconstants.ReceiverAddressingPath {
+		return next
+	}
+	return nethttp.HandlerFunc(func(response nethttp.ResponseWriter, request *nethttp.Request) {
+		if request.URL.Path == "/" {
+			next.ServeHTTP(response, request)
+			return
+		}
+		namespace, name, ok := kafkautil.KafkaChannelOfPath(request.URL.Path)
+		if !ok {
+			logger.Info("Rejecting Request To An Invalid KafkaChannel Path", zap.String("Path", request.URL.Path))
+			response.WriteHeader(nethttp.StatusNotFound)
+			return
+		}
+		request.Host = network.GetServiceHostname(kafkautil.AppendKafkaChannelServiceNameSuffix(name), namespace)
+		request.URL.Path = "/"
+		request.URL.RawPath = ""
+		next.ServeHTTP(response, request)
+	})
+}
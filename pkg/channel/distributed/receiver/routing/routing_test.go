@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	eventingchannel "knative.dev/eventing/pkg/channel"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ValidateAddressing() Functionality
+func TestValidateAddressing(t *testing.T) {
+	assert.Nil(t, ValidateAddressing(""))
+	assert.Nil(t, ValidateAddressing(kafkaconstants.ReceiverAddressingHost))
+	assert.Nil(t, ValidateAddressing(kafkaconstants.ReceiverAddressingPath))
+	assert.NotNil(t, ValidateAddressing("invalid"))
+}
+
+// Test The Handler() Functionality
+func TestHandler(t *testing.T) {
+
+	// A Handler Recording The Host & Path Of The Requests It Receives
+	var host, path string
+	next := nethttp.HandlerFunc(func(response nethttp.ResponseWriter, request *nethttp.Request) {
+		host, path = request.Host, request.URL.Path
+		response.WriteHeader(nethttp.StatusAccepted)
+	})
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Host-Based Addressing Returns The Next Handler As-Is
+	response := httptest.NewRecorder()
+	Handler(logger, kafkaconstants.ReceiverAddressingHost, next).ServeHTTP(response, httptest.NewRequest(nethttp.MethodPost, "http://receiver.knative-eventing.svc.cluster.local/my-namespace/my-channel", nil))
+	assert.Equal(t, "/my-namespace/my-channel", path)
+
+	// Define The Path-Based TestCases
+	testCases := []struct {
+		name         string
+		url          string
+		expectedCode int
+		expectedHost string
+	}{
+		{name: "KafkaChannel Path", url: "http://receiver.knative-eventing.svc.cluster.local/my-namespace/my-channel", expectedCode: nethttp.StatusAccepted, expectedHost: "my-channel-kn-channel.my-namespace.svc.cluster.local"},
+		{name: "Host-Based Root Path", url: "http://my-channel-kn-channel.my-namespace.svc.cluster.local/", expectedCode: nethttp.StatusAccepted, expectedHost: "my-channel-kn-channel.my-namespace.svc.cluster.local"},
+		{name: "Invalid Path", url: "http://receiver.knative-eventing.svc.cluster.local/my-namespace", expectedCode: nethttp.StatusNotFound},
+		{name: "Invalid Name", url: "http://receiver.knative-eventing.svc.cluster.local/my-namespace/My_Channel", expectedCode: nethttp.StatusNotFound},
+		{name: "Nested Path", url: "http://receiver.knative-eventing.svc.cluster.local/my-namespace/my-channel/more", expectedCode: nethttp.StatusNotFound},
+	}
+
+	// Run The TestCases
+	handler := Handler(logger, kafkaconstants.ReceiverAddressingPath, next)
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			host, path = "", ""
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, httptest.NewRequest(nethttp.MethodPost, testCase.url, nil))
+			assert.Equal(t, testCase.expectedCode, response.Code)
+			assert.Equal(t, testCase.expectedHost, host)
+			if testCase.expectedCode == nethttp.StatusAccepted {
+				assert.Equal(t, "/", path)
+				channelReference, err := eventingchannel.ParseChannel(host)
+				assert.Nil(t, err)
+				assert.Equal(t, "my-namespace", channelReference.Namespace)
+				assert.Equal(t, "my-channel-kn-channel", channelReference.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/constants"
+	commonlistener "knative.dev/eventing-kafka/pkg/channel/distributed/common/listener"
+)
+
+// Shutdown Defaults
+const (
+	DefaultDrainPeriod = commonconstants.DefaultReceiverDrainMillis * time.Millisecond
+	DefaultGracePeriod = commonconstants.DefaultReceiverTimeoutMillis * time.Millisecond
+)
+
+// Drainable Is The Readiness Reported As False Once The Server Starts Draining (e.g. The Receiver's Health Server)
+type Drainable interface {
+	SetDraining(isDraining bool)
+}
+
+//
+// Graceful Shutdown Of The Receiver's HTTP Server
+//
+// Terminating a receiver Pod during a rolling update would otherwise cut the requests in flight, and fail the
+// requests routed to it before its Service's Endpoints are updated.  Once its context is done the Server...
+//
+//   - Reports itself not ready, so that the Service stops routing new connections to it, and disables keep-alives
+//     so that clients close their connections after their current request and retry on another receiver.
+//   - Keeps serving for the drain period while the Endpoints removal propagates.
+//   - Stops accepting connections and waits up to the grace period for the in-flight requests to complete, after
+//     which any remaining connections are closed.
+//
+// ...leaving the caller to close (flush) the producer once the in-flight requests have been produced.
+//
+type Server struct {
+	logger      *zap.Logger
+	port        int
+	server      *nethttp.Server
+	drainable   Drainable
+	drainPeriod time.Duration
+	gracePeriod time.Duration
+}
+
+// Validate The Specified Shutdown Config
+func ValidateShutdownConfig(shutdownConfig config.EKShutdownConfig) error {
+	if shutdownConfig.DrainMillis < 0 {
+		return fmt.Errorf("drainMillis %d must not be negative", shutdownConfig.DrainMillis)
+	}
+	if shutdownConfig.TimeoutMillis < 0 {
+		return fmt.Errorf("timeoutMillis %d must not be negative", shutdownConfig.TimeoutMillis)
+	}
+	return nil
+}
+
+// Server Constructor (Assumes A Valid Config)
+func NewServer(logger *zap.Logger, port int, handler nethttp.Handler, drainable Drainable, shutdownConfig config.EKShutdownConfig) *Server {
+	return &Server{
+		logger:      logger,
+		port:        port,
+		server:      &nethttp.Server{Handler: handler},
+		drainable:   drainable,
+		drainPeriod: durationOrDefault(shutdownConfig.DrainMillis, DefaultDrainPeriod),
+		gracePeriod: durationOrDefault(shutdownConfig.TimeoutMillis, DefaultGracePeriod),
+	}
+}
+
+// Listen On The Server's Port & Serve Requests Until The Context Is Done, Then Shut Down Gracefully (Blocking)
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := commonlistener.Listen(strconv.Itoa(s.port))
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve Requests From The Specified Listener Until The Context Is Done, Then Shut Down Gracefully (Blocking)
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+
+	// Serve Requests Until The Server Fails Or The Context Is Done
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- s.server.Serve(listener)
+	}()
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Report Not Ready & Hand Off The Clients' Subsequent Requests To Other Receivers While Draining
+	s.logger.Info("Draining HTTP Server", zap.Duration("DrainPeriod", s.drainPeriod), zap.Duration("GracePeriod", s.gracePeriod))
+	s.drainable.SetDraining(true)
+	s.server.SetKeepAlivesEnabled(false)
+	time.Sleep(s.drainPeriod)
+
+	// Stop Accepting Connections & Wait For The In-Flight Requests Within The Grace Period
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.gracePeriod)
+	defer cancel()
+	err := s.server.Shutdown(shutdownCtx)
+	if err != nil {
+		s.logger.Warn("In-Flight Requests Did Not Complete Within The Grace Period - Closing Connections", zap.Error(err))
+		_ = s.server.Close()
+	} else {
+		s.logger.Info("Successfully Completed In-Flight Requests")
+	}
+	<-errChan // Wait For The Serving Goroutine To Exit (ErrServerClosed)
+	return err
+}
+
+// Convert The Specified Milliseconds To A Duration, Or The Default If Not Positive
+func durationOrDefault(millis int64, defaultDuration time.Duration) time.Duration {
+	if millis <= 0 {
+		return defaultDuration
+	}
+	return time.Duration(millis) * time.Millisecond
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+	"net"
+	nethttp "net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ValidateShutdownConfig() Functionality
+func TestValidateShutdownConfig(t *testing.T) {
+	assert.Nil(t, ValidateShutdownConfig(config.EKShutdownConfig{}))
+	assert.Nil(t, ValidateShutdownConfig(config.EKShutdownConfig{DrainMillis: 1000, TimeoutMillis: 10000}))
+	assert.NotNil(t, ValidateShutdownConfig(config.EKShutdownConfig{DrainMillis: -1}))
+	assert.NotNil(t, ValidateShutdownConfig(config.EKShutdownConfig{TimeoutMillis: -1}))
+}
+
+// Test The NewServer() Functionality
+func TestNewServer(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Unset Values Are Defaulted
+	server := NewServer(logger, 8080, nethttp.NotFoundHandler(), &mockDrainable{}, config.EKShutdownConfig{})
+	assert.Equal(t, DefaultDrainPeriod, server.drainPeriod)
+	assert.Equal(t, DefaultGracePeriod, server.gracePeriod)
+
+	// Specified Values Are Used
+	server = NewServer(logger, 8080, nethttp.NotFoundHandler(), &mockDrainable{}, config.EKShutdownConfig{DrainMillis: 1000, TimeoutMillis: 2000})
+	assert.Equal(t, time.Second, server.drainPeriod)
+	assert.Equal(t, 2*time.Second, server.gracePeriod)
+}
+
+// Test The Server's Graceful Shutdown Completing An In-Flight Request
+func TestServe(t *testing.T) {
+
+	// Start A Server With A Slow Request In Flight
+	drainable := &mockDrainable{}
+	handler := newSlowHandler()
+	defer handler.releaseRequests()
+	url, serveErrChan, cancel := startServer(t, handler, drainable, config.EKShutdownConfig{DrainMillis: 500, TimeoutMillis: 5000})
+	slowResponseChan := sendRequest(url + "/slow")
+	<-handler.started
+
+	// Terminate The Server & Verify It Reports Not Ready
+	cancel()
+	assert.Eventually(t, drainable.isDraining, time.Second, 10*time.Millisecond)
+
+	// Verify Requests Are Still Served While Draining, Closing Their Connections
+	response, err := (&nethttp.Client{}).Get(url + "/fast")
+	assert.Nil(t, err)
+	assert.Equal(t, nethttp.StatusOK, response.StatusCode)
+	assert.True(t, response.Close)
+	_ = response.Body.Close()
+
+	// Verify The In-Flight Request Completes & The Server Shuts Down Gracefully
+	handler.releaseRequests()
+	slowResult := <-slowResponseChan
+	assert.Nil(t, slowResult.err)
+	assert.Equal(t, nethttp.StatusOK, slowResult.statusCode)
+	assert.Nil(t, <-serveErrChan)
+}
+
+// Test The Server's Graceful Shutdown When An In-Flight Request Exceeds The Grace Period
+func TestServeGracePeriodExceeded(t *testing.T) {
+
+	// Start A Server With A Slow Request In Flight
+	drainable := &mockDrainable{}
+	handler := newSlowHandler()
+	defer handler.releaseRequests()
+	url, serveErrChan, cancel := startServer(t, handler, drainable, config.EKShutdownConfig{DrainMillis: 1, TimeoutMillis: 100})
+	slowResponseChan := sendRequest(url + "/slow")
+	<-handler.started
+
+	// Terminate The Server & Verify The In-Flight Request Was Cut Once The Grace Period Elapsed
+	cancel()
+	assert.Equal(t, context.DeadlineExceeded, <-serveErrChan)
+	assert.NotNil(t, (<-slowResponseChan).err)
+	assert.True(t, drainable.isDraining())
+}
+
+//
+// Test Utilities
+//
+
+// Start A Server On A Random Local Port, Returning Its URL, The Channel Of Its Serve() Result & Its Cancel Function
+func startServer(t *testing.T, handler nethttp.Handler, drainable Drainable, shutdownConfig config.EKShutdownConfig) (string, chan error, context.CancelFunc) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := NewServer(logtesting.TestLogger(t).Desugar(), 0, handler, drainable, shutdownConfig)
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErrChan := make(chan error, 1)
+	go func() {
+		serveErrChan <- server.Serve(ctx, listener)
+	}()
+	return "http://" + listener.Addr().String(), serveErrChan, cancel
+}
+
+// The Result Of An Asynchronous Request
+type requestResult struct {
+	statusCode int
+	err        error
+}
+
+// Send A GET Request To The Specified URL Asynchronously
+func sendRequest(url string) chan requestResult {
+	resultChan := make(chan requestResult, 1)
+	go func() {
+		response, err := (&nethttp.Client{}).Get(url)
+		if err != nil {
+			resultChan <- requestResult{err: err}
+			return
+		}
+		_ = response.Body.Close()
+		resultChan <- requestResult{statusCode: response.StatusCode}
+	}()
+	return resultChan
+}
+
+// Handler Blocking The /slow Requests Until Released
+type slowHandler struct {
+	started     chan struct{}
+	released    chan struct{}
+	releaseOnce sync.Once
+}
+
+func newSlowHandler() *slowHandler {
+	return &slowHandler{started: make(chan struct{}, 1), released: make(chan struct{})}
+}
+
+func (h *slowHandler) ServeHTTP(writer nethttp.ResponseWriter, request *nethttp.Request) {
+	if request.URL.Path == "/slow" {
+		h.started <- struct{}{}
+		<-h.released
+	}
+	writer.WriteHeader(nethttp.StatusOK)
+}
+
+func (h *slowHandler) releaseRequests() {
+	h.releaseOnce.Do(func() { close(h.released) })
+}
+
+// Mock Drainable Recording Whether It Is Draining
+type mockDrainable struct {
+	draining bool
+	lock     sync.Mutex
+}
+
+func (m *mockDrainable) SetDraining(isDraining bool) {
+	m.lock.Lock()
+	m.draining = isDraining
+	m.lock.Unlock()
+}
+
+func (m *mockDrainable) isDraining() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.draining
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
+)
+
+// Status Constants
+const (
+	Path               = "/status"        // The Endpoint Of The Status (Served By The Health Server)
+	DefaultErrorWindow = 10 * time.Minute // The Window Within Which Produce Errors Are Considered Recent
+	MaxRecordedErrors  = 1000             // The Most Recent Produce Errors Retained Within The Window
+)
+
+// Produce Error Categories
+const (
+	CategoryOutOfBrokers      = "outOfBrokers"
+	CategoryTimeout           = "timeout"
+	CategoryLeaderUnavailable = "leaderUnavailable"
+	CategoryUnknownTopic      = "unknownTopic"
+	CategoryNotEnoughReplicas = "notEnoughReplicas"
+	CategoryMessageTooLarge   = "messageTooLarge"
+	CategoryAuthorization     = "authorization"
+	CategoryEncoding          = "encoding"
+	CategoryClosed            = "closed"
+	CategoryInjected          = "injected"
+	CategoryKafka             = "kafka" // Any Other Error Returned By The Kafka Brokers
+	CategoryOther             = "other"
+)
+
+// ChannelStatus Describes A KafkaChannel Served By The Receiver & The Topic To Which Its Events Are Produced
+type ChannelStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Topic     string `json:"topic"`
+}
+
+// BrokerStatus Describes The Connection State Of One Of The Producer's Kafka Brokers
+type BrokerStatus struct {
+	ID        int32  `json:"id"`
+	Addr      string `json:"addr"`
+	Connected bool   `json:"connected"`
+	Error     string `json:"error,omitempty"` // The Error Of The Last Connection Attempt (If Any)
+}
+
+// ErrorSummary Summarizes The Recent Produce Errors Of A Single Category For A Topic
+type ErrorSummary struct {
+	Count     int       `json:"count"`
+	LastError string    `json:"lastError"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Status Is The JSON Document Served At The Status Endpoint
+type Status struct {
+	Channels           []ChannelStatus                     `json:"channels"`
+	ChannelsError      string                              `json:"channelsError,omitempty"`
+	ProduceErrors      map[string]map[string]*ErrorSummary `json:"produceErrors"` // Keyed By Topic & Then Category
+	ErrorWindowSeconds int64                               `json:"errorWindowSeconds"`
+	Brokers            []BrokerStatus                      `json:"brokers"`
+}
+
+// A Single Recorded Produce Error
+type produceError struct {
+	time     time.Time
+	topic    string
+	category string
+	message  string
+}
+
+//
+// Recent Produce Error Tracker
+//
+// The ErrorTracker retains the produce errors of the receiver within a sliding window (bounded to the most recent
+// MaxRecordedErrors) so that the status endpoint can report which topics are failing and why, without having to
+// scrape and correlate the receiver's logs.  A nil *ErrorTracker is valid and records nothing.
+//
+type ErrorTracker struct {
+	window time.Duration
+	errors []produceError
+	lock   sync.Mutex
+}
+
+// ErrorTracker Constructor (Defaulting The Window If Not Positive)
+func NewErrorTracker(window time.Duration) *ErrorTracker {
+	if window <= 0 {
+		window = DefaultErrorWindow
+	}
+	return &ErrorTracker{window: window}
+}
+
+// Record A Failure To Produce To The Specified Topic
+func (t *ErrorTracker) Record(topic string, err error) {
+	if t == nil || err == nil {
+		return
+	}
+	now := time.Now()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.errors = append(t.prune(now), produceError{time: now, topic: topic, category: Category(err), message: err.Error()})
+	if len(t.errors) > MaxRecordedErrors {
+		t.errors = t.errors[len(t.errors)-MaxRecordedErrors:]
+	}
+}
+
+// Summarize The Recent Produce Errors By Topic & Category
+func (t *ErrorTracker) Summaries() map[string]map[string]*ErrorSummary {
+	summaries := make(map[string]map[string]*ErrorSummary)
+	if t == nil {
+		return summaries
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.errors = t.prune(time.Now())
+	for _, produceError := range t.errors {
+		categories, ok := summaries[produceError.topic]
+		if !ok {
+			categories = make(map[string]*ErrorSummary)
+			summaries[produceError.topic] = categories
+		}
+		summary, ok := categories[produceError.category]
+		if !ok {
+			summary = &ErrorSummary{}
+			categories[produceError.category] = summary
+		}
+		summary.Count++
+		summary.LastError = produceError.message // Errors Are Recorded In Chronological Order
+		summary.LastSeen = produceError.time
+	}
+	return summaries
+}
+
+// The Window Within Which Produce Errors Are Considered Recent
+func (t *ErrorTracker) Window() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return t.window
+}
+
+// Drop The Errors Older Than The Window (Caller Must Hold The Lock)
+func (t *ErrorTracker) prune(now time.Time) []produceError {
+	cutoff := now.Add(-t.window)
+	index := sort.Search(len(t.errors), func(i int) bool { return t.errors[i].time.After(cutoff) })
+	return t.errors[index:]
+}
+
+// Categorize A Produce Error For Support Tooling (Based On The Sarama / Kafka Error Types)
+func Category(err error) string {
+	var kError sarama.KError
+	var packetEncodingError sarama.PacketEncodingError
+	var netError net.Error
+	switch {
+	case errors.Is(err, faults.ErrInjectedProduceFailure):
+		return CategoryInjected
+	case errors.Is(err, sarama.ErrOutOfBrokers):
+		return CategoryOutOfBrokers
+	case errors.Is(err, sarama.ErrClosedClient), errors.Is(err, sarama.ErrShuttingDown):
+		return CategoryClosed
+	case errors.As(err, &packetEncodingError):
+		return CategoryEncoding
+	case errors.As(err, &kError):
+		switch kError {
+		case sarama.ErrRequestTimedOut:
+			return CategoryTimeout
+		case sarama.ErrNotLeaderForPartition, sarama.ErrLeaderNotAvailable:
+			return CategoryLeaderUnavailable
+		case sarama.ErrUnknownTopicOrPartition:
+			return CategoryUnknownTopic
+		case sarama.ErrNotEnoughReplicas, sarama.ErrNotEnoughReplicasAfterAppend:
+			return CategoryNotEnoughReplicas
+		case sarama.ErrMessageSizeTooLarge:
+			return CategoryMessageTooLarge
+		case sarama.ErrTopicAuthorizationFailed, sarama.ErrClusterAuthorizationFailed, sarama.ErrSASLAuthenticationFailed:
+			return CategoryAuthorization
+		default:
+			return CategoryKafka
+		}
+	case errors.As(err, &netError) && netError.Timeout():
+		return CategoryTimeout
+	default:
+		return CategoryOther
+	}
+}
+
+//
+// Receiver Status Handler
+//
+// The Handler serves the receiver's status as JSON for support tooling: the KafkaChannels (and topics) it serves,
+// the recent produce errors by topic and category, and the connection states of the producer's Kafka brokers.
+// The channels and brokers are obtained via functions as the KafkaChannel lister and the producer are initialized
+// (and the producer possibly recreated) after the health server has started.
+//
+type Handler struct {
+	logger       *zap.Logger
+	channels     func() ([]ChannelStatus, error)
+	errorTracker *ErrorTracker
+	brokers      func() []BrokerStatus
+}
+
+// Handler Constructor
+func NewHandler(logger *zap.Logger, channels func() ([]ChannelStatus, error), errorTracker *ErrorTracker, brokers func() []BrokerStatus) *Handler {
+	return &Handler{
+		logger:       logger,
+		channels:     channels,
+		errorTracker: errorTracker,
+		brokers:      brokers,
+	}
+}
+
+// Serve The Receiver's Status
+func (h *Handler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		http.Error(responseWriter, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Gather The Status (Reporting Rather Than Failing On An Unavailable KafkaChannel Lister)
+	status := Status{
+		Channels:           []ChannelStatus{},
+		ProduceErrors:      h.errorTracker.Summaries(),
+		ErrorWindowSeconds: int64(h.errorTracker.Window() / time.Second),
+		Brokers:            []BrokerStatus{},
+	}
+	channels, err := h.channels()
+	if err != nil {
+		h.logger.Warn("Failed To List KafkaChannels For Status", zap.Error(err))
+		status.ChannelsError = err.Error()
+	} else if channels != nil {
+		status.Channels = channels
+	}
+	if brokers := h.brokers(); brokers != nil {
+		status.Brokers = brokers
+	}
+
+	// Write The Status As JSON
+	responseWriter.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(responseWriter).Encode(status)
+}
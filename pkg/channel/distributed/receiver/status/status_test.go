@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/faults"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Timeout Error (Implements net.Error)
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// Test The Category() Functionality
+func TestCategory(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name string
+		err  error
+		want string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Injected", err: faults.ErrInjectedProduceFailure, want: CategoryInjected},
+		{name: "Out Of Brokers", err: sarama.ErrOutOfBrokers, want: CategoryOutOfBrokers},
+		{name: "Closed Client", err: sarama.ErrClosedClient, want: CategoryClosed},
+		{name: "Shutting Down", err: sarama.ErrShuttingDown, want: CategoryClosed},
+		{name: "Encoding", err: sarama.PacketEncodingError{Info: "bad"}, want: CategoryEncoding},
+		{name: "Request Timed Out", err: sarama.ErrRequestTimedOut, want: CategoryTimeout},
+		{name: "Network Timeout", err: timeoutError{}, want: CategoryTimeout},
+		{name: "Not Leader", err: sarama.ErrNotLeaderForPartition, want: CategoryLeaderUnavailable},
+		{name: "Unknown Topic", err: sarama.ErrUnknownTopicOrPartition, want: CategoryUnknownTopic},
+		{name: "Not Enough Replicas", err: sarama.ErrNotEnoughReplicasAfterAppend, want: CategoryNotEnoughReplicas},
+		{name: "Message Too Large", err: sarama.ErrMessageSizeTooLarge, want: CategoryMessageTooLarge},
+		{name: "Authorization", err: sarama.ErrTopicAuthorizationFailed, want: CategoryAuthorization},
+		{name: "Other Kafka Error", err: sarama.ErrInvalidMessage, want: CategoryKafka},
+		{name: "Wrapped Kafka Error", err: fmt.Errorf("produce failed: %w", sarama.ErrMessageSizeTooLarge), want: CategoryMessageTooLarge},
+		{name: "Other", err: errors.New("other"), want: CategoryOther},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, Category(testCase.err))
+		})
+	}
+}
+
+// Test The ErrorTracker Functionality
+func TestErrorTracker(t *testing.T) {
+
+	// Record Errors For Two Topics
+	errorTracker := NewErrorTracker(time.Minute)
+	errorTracker.Record("topic1", sarama.ErrOutOfBrokers)
+	errorTracker.Record("topic1", sarama.ErrMessageSizeTooLarge)
+	errorTracker.Record("topic1", sarama.ErrOutOfBrokers)
+	errorTracker.Record("topic2", errors.New("other"))
+	errorTracker.Record("topic2", nil)
+
+	// Verify The Summaries
+	summaries := errorTracker.Summaries()
+	assert.Len(t, summaries, 2)
+	assert.Len(t, summaries["topic1"], 2)
+	assert.Equal(t, 2, summaries["topic1"][CategoryOutOfBrokers].Count)
+	assert.Equal(t, sarama.ErrOutOfBrokers.Error(), summaries["topic1"][CategoryOutOfBrokers].LastError)
+	assert.Equal(t, 1, summaries["topic1"][CategoryMessageTooLarge].Count)
+	assert.Equal(t, 1, summaries["topic2"][CategoryOther].Count)
+	assert.Equal(t, "other", summaries["topic2"][CategoryOther].LastError)
+
+	// Verify Errors Outside The Window Are Dropped
+	errorTracker.errors[0].time = time.Now().Add(-2 * time.Minute)
+	errorTracker.errors[1].time = time.Now().Add(-2 * time.Minute)
+	summaries = errorTracker.Summaries()
+	assert.Equal(t, 1, summaries["topic1"][CategoryOutOfBrokers].Count)
+	assert.Nil(t, summaries["topic1"][CategoryMessageTooLarge])
+	assert.Len(t, errorTracker.errors, 2)
+
+	// Verify The Number Of Recorded Errors Is Bounded
+	for i := 0; i < MaxRecordedErrors+10; i++ {
+		errorTracker.Record("topic3", sarama.ErrRequestTimedOut)
+	}
+	assert.Len(t, errorTracker.errors, MaxRecordedErrors)
+
+	// Verify The Window Is Defaulted
+	assert.Equal(t, DefaultErrorWindow, NewErrorTracker(0).Window())
+}
+
+// Test A nil ErrorTracker Is Valid
+func TestNilErrorTracker(t *testing.T) {
+	var errorTracker *ErrorTracker
+	errorTracker.Record("topic", sarama.ErrOutOfBrokers)
+	assert.Empty(t, errorTracker.Summaries())
+	assert.Equal(t, time.Duration(0), errorTracker.Window())
+}
+
+// Test The Handler Functionality
+func TestHandler(t *testing.T) {
+
+	// Test Data
+	logger := logtesting.TestLogger(t).Desugar()
+	channels := []ChannelStatus{{Namespace: "namespace", Name: "name", Topic: "namespace.name"}}
+	brokers := []BrokerStatus{{ID: 1, Addr: "broker:9092", Connected: true}}
+	errorTracker := NewErrorTracker(time.Minute)
+	errorTracker.Record("namespace.name", sarama.ErrOutOfBrokers)
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		method      string
+		channelsErr error
+		wantCode    int
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Status", method: http.MethodGet, wantCode: http.StatusOK},
+		{name: "Channels Unavailable", method: http.MethodGet, channelsErr: errors.New("uninitialized"), wantCode: http.StatusOK},
+		{name: "Method Not Allowed", method: http.MethodPost, wantCode: http.StatusMethodNotAllowed},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Handler
+			handler := NewHandler(logger, func() ([]ChannelStatus, error) {
+				if testCase.channelsErr != nil {
+					return nil, testCase.channelsErr
+				}
+				return channels, nil
+			}, errorTracker, func() []BrokerStatus { return brokers })
+
+			// Perform The Test
+			responseRecorder := httptest.NewRecorder()
+			handler.ServeHTTP(responseRecorder, httptest.NewRequest(testCase.method, Path, nil))
+
+			// Verify The Results
+			assert.Equal(t, testCase.wantCode, responseRecorder.Code)
+			if testCase.wantCode != http.StatusOK {
+				return
+			}
+			var status Status
+			assert.Nil(t, json.Unmarshal(responseRecorder.Body.Bytes(), &status))
+			if testCase.channelsErr != nil {
+				assert.Empty(t, status.Channels)
+				assert.Equal(t, testCase.channelsErr.Error(), status.ChannelsError)
+			} else {
+				assert.Equal(t, channels, status.Channels)
+				assert.Empty(t, status.ChannelsError)
+			}
+			assert.Equal(t, brokers, status.Brokers)
+			assert.Equal(t, int64(60), status.ErrorWindowSeconds)
+			assert.Equal(t, 1, status.ProduceErrors["namespace.name"][CategoryOutOfBrokers].Count)
+		})
+	}
+}
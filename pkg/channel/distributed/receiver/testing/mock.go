@@ -47,7 +47,7 @@ func NewMockSyncProducer() *MockSyncProducer {
 }
 
 func (p *MockSyncProducer) SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error) {
-	p.producerMessages <- *msg
+	p.producerMessages <- copyProducerMessage(msg)
 	p.offset = p.offset + 1
 	return 1, p.offset, nil
 }
@@ -57,6 +57,20 @@ func (p *MockSyncProducer) SendMessages(_ []*sarama.ProducerMessage) error {
 	return nil
 }
 
+// Copy The Value & Headers Of A Sent Message (Which The Producer Reuses Once Sent)
+func copyProducerMessage(msg *sarama.ProducerMessage) sarama.ProducerMessage {
+	producerMessage := *msg
+	if msg.Value != nil {
+		value, _ := msg.Value.Encode()
+		producerMessage.Value = sarama.ByteEncoder(append([]byte{}, value...))
+	}
+	producerMessage.Headers = make([]sarama.RecordHeader, len(msg.Headers))
+	for index, header := range msg.Headers {
+		producerMessage.Headers[index] = sarama.RecordHeader{Key: append([]byte{}, header.Key...), Value: append([]byte{}, header.Value...)}
+	}
+	return producerMessage
+}
+
 func (p *MockSyncProducer) GetMessage() sarama.ProducerMessage {
 	return <-p.producerMessages
 }
@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+)
+
+// Throttle Defaults
+const (
+	DefaultLatencyThreshold = time.Second
+	DefaultInitialBackoff   = 500 * time.Millisecond
+	DefaultMaxBackoff       = 30 * time.Second
+	DefaultMaxInFlight      = 1000
+)
+
+//
+// Broker Quota Aware Throttle Of The Receiver's Produce Requests
+//
+// Kafka enforces its client quotas by delaying the responses to (or muting the connections of) the clients
+// exceeding them, which Sarama's SyncProducer does not surface other than as slow produce requests.  Produce
+// requests slower than the latency threshold therefore start a backoff, doubling (up to the maximum) whenever
+// the throttling persists after it has elapsed, during which requests are rejected with a Retry-After rather
+// than queued.  Requests beyond the maximum number of in-flight requests are rejected the same way.  A nil
+// *Throttle is valid and never throttles any requests.
+//
+type Throttle struct {
+	logger           *zap.Logger
+	latencyThreshold time.Duration
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	maxInFlight      int
+	inFlight         int
+	backoff          time.Duration
+	backoffUntil     time.Time
+	lock             sync.Mutex
+	now              func() time.Time
+}
+
+// Validate The Specified Throttle Config
+func ValidateThrottleConfig(throttleConfig config.EKThrottleConfig) error {
+	if throttleConfig.LatencyThresholdMillis < 0 {
+		return fmt.Errorf("latencyThresholdMillis %d must not be negative", throttleConfig.LatencyThresholdMillis)
+	}
+	if throttleConfig.InitialBackoffMillis < 0 {
+		return fmt.Errorf("initialBackoffMillis %d must not be negative", throttleConfig.InitialBackoffMillis)
+	}
+	if throttleConfig.MaxBackoffMillis < 0 {
+		return fmt.Errorf("maxBackoffMillis %d must not be negative", throttleConfig.MaxBackoffMillis)
+	}
+	if throttleConfig.MaxBackoffMillis > 0 && throttleConfig.InitialBackoffMillis > throttleConfig.MaxBackoffMillis {
+		return fmt.Errorf("initialBackoffMillis %d must not exceed maxBackoffMillis %d", throttleConfig.InitialBackoffMillis, throttleConfig.MaxBackoffMillis)
+	}
+	if throttleConfig.MaxInFlight < 0 {
+		return fmt.Errorf("maxInFlight %d must not be negative", throttleConfig.MaxInFlight)
+	}
+	return nil
+}
+
+// Throttle Constructor - Returns nil If Throttling Is Not Enabled (Assumes A Valid Config)
+func NewThrottle(logger *zap.Logger, throttleConfig config.EKThrottleConfig) *Throttle {
+	if !throttleConfig.Enabled {
+		return nil
+	}
+	throttle := &Throttle{
+		logger:           logger,
+		latencyThreshold: durationOrDefault(throttleConfig.LatencyThresholdMillis, DefaultLatencyThreshold),
+		initialBackoff:   durationOrDefault(throttleConfig.InitialBackoffMillis, DefaultInitialBackoff),
+		maxBackoff:       durationOrDefault(throttleConfig.MaxBackoffMillis, DefaultMaxBackoff),
+		maxInFlight:      throttleConfig.MaxInFlight,
+		now:              time.Now,
+	}
+	if throttle.maxInFlight <= 0 {
+		throttle.maxInFlight = DefaultMaxInFlight
+	}
+	if throttle.initialBackoff > throttle.maxBackoff {
+		throttle.initialBackoff = throttle.maxBackoff
+	}
+	return throttle
+}
+
+// Acquire An In-Flight Request Slot, Unless Backing Off Or At The Maximum (Returning The Retry-After Delay Instead)
+func (t *Throttle) Acquire() (bool, time.Duration) {
+	if t == nil {
+		return true, 0
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Reject Requests While Backing Off
+	if remaining := t.backoffUntil.Sub(t.now()); remaining > 0 {
+		metrics.RecordThrottledRequest(t.logger, metrics.ReasonBackoff)
+		return false, remaining
+	}
+
+	// Reject Requests Beyond The Maximum In-Flight Requests
+	if t.inFlight >= t.maxInFlight {
+		metrics.RecordThrottledRequest(t.logger, metrics.ReasonInFlight)
+		return false, t.initialBackoff
+	}
+
+	t.inFlight++
+	return true, 0
+}
+
+// Release An In-Flight Request Slot Obtained From Acquire()
+func (t *Throttle) Release() {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.inFlight > 0 {
+		t.inFlight--
+	}
+}
+
+// Observe The Latency Of A Produce Request, Backing Off While It Exceeds The Latency Threshold
+func (t *Throttle) Observe(latency time.Duration) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	now := t.now()
+
+	// Reset The Backoff Once Produce Requests Are Fast Again After It Has Elapsed
+	if latency < t.latencyThreshold {
+		if t.backoff > 0 && !now.Before(t.backoffUntil) {
+			t.backoff = 0
+			metrics.RecordThrottleBackoff(0)
+			t.logger.Info("Kafka Produce Latency Recovered - No Longer Throttling Requests")
+		}
+		return
+	}
+
+	// Start (Or Escalate Once Elapsed) The Backoff
+	metrics.RecordThrottledProduce()
+	if now.Before(t.backoffUntil) {
+		return
+	}
+	if t.backoff == 0 {
+		t.backoff = t.initialBackoff
+	} else {
+		t.backoff *= 2
+		if t.backoff > t.maxBackoff {
+			t.backoff = t.maxBackoff
+		}
+	}
+	t.backoffUntil = now.Add(t.backoff)
+	metrics.RecordThrottleBackoff(t.backoff)
+	t.logger.Warn("Kafka Produce Latency Exceeds Threshold (Broker Quota?) - Throttling Requests",
+		zap.Duration("Latency", latency),
+		zap.Duration("Threshold", t.latencyThreshold),
+		zap.Duration("Backoff", t.backoff))
+}
+
+// Wrap The Specified Handler To Reject Requests With 503 & Retry-After While Throttled (Returns It As-Is If nil)
+func (t *Throttle) Handler(next nethttp.Handler) nethttp.Handler {
+	if t == nil {
+		return next
+	}
+	return nethttp.HandlerFunc(func(response nethttp.ResponseWriter, request *nethttp.Request) {
+		ok, retryAfter := t.Acquire()
+		if !ok {
+			response.Header().Set("Retry-After", strconv.FormatInt(retryAfterSeconds(retryAfter), 10))
+			response.WriteHeader(nethttp.StatusServiceUnavailable)
+			return
+		}
+		defer t.Release()
+		next.ServeHTTP(response, request)
+	})
+}
+
+// Utility Function For Rounding A Retry-After Delay Up To Whole Seconds (At Least One)
+func retryAfterSeconds(retryAfter time.Duration) int64 {
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// Utility Function For Converting Milliseconds To A Duration (Using The Default If Not Positive)
+func durationOrDefault(millis int64, defaultDuration time.Duration) time.Duration {
+	if millis <= 0 {
+		return defaultDuration
+	}
+	return time.Duration(millis) * time.Millisecond
+}
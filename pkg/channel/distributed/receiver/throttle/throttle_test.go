@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The ValidateThrottleConfig() Functionality
+func TestValidateThrottleConfig(t *testing.T) {
+	assert.Nil(t, ValidateThrottleConfig(config.EKThrottleConfig{}))
+	assert.Nil(t, ValidateThrottleConfig(config.EKThrottleConfig{Enabled: true, LatencyThresholdMillis: 500, InitialBackoffMillis: 100, MaxBackoffMillis: 1000, MaxInFlight: 10}))
+	assert.Nil(t, ValidateThrottleConfig(config.EKThrottleConfig{InitialBackoffMillis: 60000}))
+	assert.NotNil(t, ValidateThrottleConfig(config.EKThrottleConfig{LatencyThresholdMillis: -1}))
+	assert.NotNil(t, ValidateThrottleConfig(config.EKThrottleConfig{InitialBackoffMillis: -1}))
+	assert.NotNil(t, ValidateThrottleConfig(config.EKThrottleConfig{MaxBackoffMillis: -1}))
+	assert.NotNil(t, ValidateThrottleConfig(config.EKThrottleConfig{InitialBackoffMillis: 2000, MaxBackoffMillis: 1000}))
+	assert.NotNil(t, ValidateThrottleConfig(config.EKThrottleConfig{MaxInFlight: -1}))
+}
+
+// Test The NewThrottle() Functionality
+func TestNewThrottle(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Disabled Throttling Returns nil
+	assert.Nil(t, NewThrottle(logger, config.EKThrottleConfig{}))
+
+	// Unset Values Are Defaulted
+	throttle := NewThrottle(logger, config.EKThrottleConfig{Enabled: true})
+	assert.Equal(t, DefaultLatencyThreshold, throttle.latencyThreshold)
+	assert.Equal(t, DefaultInitialBackoff, throttle.initialBackoff)
+	assert.Equal(t, DefaultMaxBackoff, throttle.maxBackoff)
+	assert.Equal(t, DefaultMaxInFlight, throttle.maxInFlight)
+
+	// The InitialBackoff Is Capped At The MaxBackoff
+	throttle = NewThrottle(logger, config.EKThrottleConfig{Enabled: true, InitialBackoffMillis: 60000})
+	assert.Equal(t, DefaultMaxBackoff, throttle.initialBackoff)
+}
+
+// Test The Nil Throttle Functionality
+func TestNilThrottle(t *testing.T) {
+	var throttle *Throttle
+	ok, retryAfter := throttle.Acquire()
+	assert.True(t, ok)
+	assert.Zero(t, retryAfter)
+	throttle.Release()
+	throttle.Observe(time.Hour)
+	next := nethttp.FileServer(nethttp.Dir("."))
+	assert.Same(t, next, throttle.Handler(next))
+}
+
+// Test The Throttle's Backoff Functionality
+func TestThrottleBackoff(t *testing.T) {
+
+	// Create A Throttle With A Controllable Clock
+	now := time.Now()
+	throttle := NewThrottle(logtesting.TestLogger(t).Desugar(), config.EKThrottleConfig{
+		Enabled:                true,
+		LatencyThresholdMillis: 1000,
+		InitialBackoffMillis:   1000,
+		MaxBackoffMillis:       3000,
+	})
+	throttle.now = func() time.Time { return now }
+
+	// Fast Produce Requests Do Not Throttle
+	throttle.Observe(10 * time.Millisecond)
+	assertAcquire(t, throttle, true, 0)
+
+	// A Slow Produce Request Starts The Initial Backoff
+	throttle.Observe(2 * time.Second)
+	assertAcquire(t, throttle, false, time.Second)
+
+	// Further Slow Produce Requests Do Not Escalate The Backoff Before It Has Elapsed
+	now = now.Add(500 * time.Millisecond)
+	throttle.Observe(2 * time.Second)
+	assertAcquire(t, throttle, false, 500*time.Millisecond)
+
+	// Persistent Throttling Doubles The Backoff Up To The Maximum
+	now = now.Add(500 * time.Millisecond)
+	throttle.Observe(2 * time.Second)
+	assertAcquire(t, throttle, false, 2*time.Second)
+	now = now.Add(2 * time.Second)
+	throttle.Observe(2 * time.Second)
+	assertAcquire(t, throttle, false, 3*time.Second)
+
+	// Fast Produce Requests Reset The Backoff Once Elapsed
+	now = now.Add(3 * time.Second)
+	throttle.Observe(10 * time.Millisecond)
+	assert.Zero(t, throttle.backoff)
+	assertAcquire(t, throttle, true, 0)
+	throttle.Observe(2 * time.Second)
+	assertAcquire(t, throttle, false, time.Second)
+}
+
+// Test The Throttle's Handler Functionality
+func TestThrottleHandler(t *testing.T) {
+
+	// Create A Throttle Allowing A Single In-Flight Request
+	now := time.Now()
+	throttle := NewThrottle(logtesting.TestLogger(t).Desugar(), config.EKThrottleConfig{Enabled: true, MaxInFlight: 1, InitialBackoffMillis: 1500})
+	throttle.now = func() time.Time { return now }
+
+	// Create A Handler Which Issues A Nested Request While In-Flight
+	var nestedResponse *httptest.ResponseRecorder
+	var handler nethttp.Handler
+	handler = throttle.Handler(nethttp.HandlerFunc(func(response nethttp.ResponseWriter, request *nethttp.Request) {
+		if nestedResponse == nil {
+			nestedResponse = httptest.NewRecorder()
+			handler.ServeHTTP(nestedResponse, request)
+		}
+		response.WriteHeader(nethttp.StatusAccepted)
+	}))
+
+	// Requests Beyond The Maximum In-Flight Requests Are Rejected
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(nethttp.MethodPost, "/", nil))
+	assert.Equal(t, nethttp.StatusAccepted, response.Code)
+	assert.Equal(t, nethttp.StatusServiceUnavailable, nestedResponse.Code)
+	assert.Equal(t, "2", nestedResponse.Header().Get("Retry-After"))
+
+	// The In-Flight Request Slot Is Released
+	assert.Zero(t, throttle.inFlight)
+
+	// Requests Are Rejected While Backing Off
+	throttle.Observe(time.Minute)
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(nethttp.MethodPost, "/", nil))
+	assert.Equal(t, nethttp.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, "2", response.Header().Get("Retry-After"))
+}
+
+// Test The retryAfterSeconds() Functionality
+func TestRetryAfterSeconds(t *testing.T) {
+	assert.Equal(t, int64(1), retryAfterSeconds(0))
+	assert.Equal(t, int64(1), retryAfterSeconds(time.Millisecond))
+	assert.Equal(t, int64(1), retryAfterSeconds(time.Second))
+	assert.Equal(t, int64(2), retryAfterSeconds(1001*time.Millisecond))
+}
+
+// Utility Function For Asserting The Result Of Acquire() (Releasing Any Acquired Slot)
+func assertAcquire(t *testing.T, throttle *Throttle, expectedOk bool, expectedRetryAfter time.Duration) {
+	ok, retryAfter := throttle.Acquire()
+	assert.Equal(t, expectedOk, ok)
+	assert.Equal(t, expectedRetryAfter, retryAfter)
+	if ok {
+		throttle.Release()
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Schema Registry Authentication Modes
+const (
+	SchemaRegistryAuthNone   = "none"
+	SchemaRegistryAuthBasic  = "basic"
+	SchemaRegistryAuthBearer = "bearer"
+	SchemaRegistryAuthOAuth2 = "oauth2"
+)
+
+// OAuth2 Token Constants
+const (
+	MaxTokenResponseBytes = 64 * 1024
+	TokenExpiryMargin     = 30 * time.Second
+)
+
+// The Credentials Of The Schema Registry (Loaded From The Environment, Which References The Auth Secret)
+type RegistryCredentials struct {
+	Username     string
+	Password     string
+	Token        string
+	ClientId     string
+	ClientSecret string
+}
+
+// Validate The Specified Schema Registry Auth Config & That The Credentials Of Its Mode Are Present
+func ValidateRegistryAuth(authConfig config.EKSchemaRegistryAuthConfig, credentials RegistryCredentials) error {
+	var missing []string
+	switch authConfig.Mode {
+	case "", SchemaRegistryAuthNone:
+		return nil
+	case SchemaRegistryAuthBasic:
+		missing = missingCredentials(map[string]string{"username": credentials.Username, "password": credentials.Password})
+	case SchemaRegistryAuthBearer:
+		missing = missingCredentials(map[string]string{"token": credentials.Token})
+	case SchemaRegistryAuthOAuth2:
+		tokenURL, err := url.Parse(authConfig.TokenURL)
+		if err != nil || !tokenURL.IsAbs() || len(tokenURL.Host) == 0 {
+			return fmt.Errorf("schemaRegistryAuth.tokenURL %q must be an absolute URL", authConfig.TokenURL)
+		}
+		missing = missingCredentials(map[string]string{"clientId": credentials.ClientId, "clientSecret": credentials.ClientSecret})
+	default:
+		return fmt.Errorf("schemaRegistryAuth.mode %q must be one of %s, %s, %s or %s", authConfig.Mode,
+			SchemaRegistryAuthNone, SchemaRegistryAuthBasic, SchemaRegistryAuthBearer, SchemaRegistryAuthOAuth2)
+	}
+	if len(authConfig.SecretName) == 0 {
+		return fmt.Errorf("schemaRegistryAuth.mode %s requires a schemaRegistryAuth.secretName", authConfig.Mode)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("schemaRegistryAuth.mode %s requires the %s keys of secret %s", authConfig.Mode, strings.Join(missing, " & "), authConfig.SecretName)
+	}
+	return nil
+}
+
+// Create The Transport Authenticating Requests To The Schema Registry (nil For The Default Transport Without Authentication)
+func newRegistryTransport(authConfig config.EKSchemaRegistryAuthConfig, credentials RegistryCredentials) nethttp.RoundTripper {
+	switch authConfig.Mode {
+	case SchemaRegistryAuthBasic:
+		return &basicAuthTransport{username: credentials.Username, password: credentials.Password}
+	case SchemaRegistryAuthBearer:
+		return &bearerTransport{token: func(context.Context) (string, error) { return credentials.Token, nil }}
+	case SchemaRegistryAuthOAuth2:
+		tokenSource := &clientCredentialsTokenSource{
+			tokenURL:     authConfig.TokenURL,
+			scopes:       authConfig.Scopes,
+			clientId:     credentials.ClientId,
+			clientSecret: credentials.ClientSecret,
+			httpClient:   &nethttp.Client{Timeout: SchemaFetchTimeout},
+			now:          time.Now,
+		}
+		return &bearerTransport{token: tokenSource.Token}
+	default:
+		return nil
+	}
+}
+
+// A Transport Adding HTTP Basic Authentication To Requests
+type basicAuthTransport struct {
+	username string
+	password string
+}
+
+// Add The Basic Authentication To A Copy Of The Request (RoundTrippers Must Not Modify The Request)
+func (t *basicAuthTransport) RoundTrip(request *nethttp.Request) (*nethttp.Response, error) {
+	authenticatedRequest := request.Clone(request.Context())
+	authenticatedRequest.SetBasicAuth(t.username, t.password)
+	return nethttp.DefaultTransport.RoundTrip(authenticatedRequest)
+}
+
+// A Transport Adding A Bearer Token To Requests
+type bearerTransport struct {
+	token func(ctx context.Context) (string, error)
+}
+
+// Add The Bearer Token To A Copy Of The Request (RoundTrippers Must Not Modify The Request)
+func (t *bearerTransport) RoundTrip(request *nethttp.Request) (*nethttp.Response, error) {
+	token, err := t.token(request.Context())
+	if err != nil {
+		return nil, err
+	}
+	authenticatedRequest := request.Clone(request.Context())
+	authenticatedRequest.Header.Set("Authorization", "Bearer "+token)
+	return nethttp.DefaultTransport.RoundTrip(authenticatedRequest)
+}
+
+//
+// An OAuth2 Token Source Using The Client Credentials Grant
+//
+// The client credentials are sent with HTTP Basic Authentication, as accepted by the token endpoints of Keycloak
+// (securing the Apicurio Registry) and of most other OAuth2 servers.  Tokens are reused until shortly before they
+// expire (or indefinitely if the token endpoint does not specify their expiry).
+//
+type clientCredentialsTokenSource struct {
+	tokenURL     string
+	scopes       []string
+	clientId     string
+	clientSecret string
+	httpClient   *nethttp.Client
+	token        string
+	expiry       time.Time
+	lock         sync.Mutex
+	now          func() time.Time
+}
+
+// Get The Current Token, Requesting A New One If It Has Expired
+func (s *clientCredentialsTokenSource) Token(ctx context.Context) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.token) > 0 && (s.expiry.IsZero() || s.now().Before(s.expiry)) {
+		return s.token, nil
+	}
+	token, expiresIn, err := s.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expiry = time.Time{}
+	if expiresIn > 0 {
+		lifetime := time.Duration(expiresIn) * time.Second
+		if lifetime > 2*TokenExpiryMargin {
+			lifetime -= TokenExpiryMargin
+		}
+		s.expiry = s.now().Add(lifetime)
+	}
+	return s.token, nil
+}
+
+// Request A New Token (& The Seconds In Which It Expires) From The Token Endpoint
+func (s *clientCredentialsTokenSource) requestToken(ctx context.Context) (string, int64, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+	request, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(url.QueryEscape(s.clientId), url.QueryEscape(s.clientSecret))
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to request schema registry token: %w", err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, MaxTokenResponseBytes))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read schema registry token: %w", err)
+	}
+	if response.StatusCode != nethttp.StatusOK {
+		return "", 0, fmt.Errorf("unexpected schema registry token response status %d", response.StatusCode)
+	}
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err = json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", 0, fmt.Errorf("invalid schema registry token response: %w", err)
+	}
+	if len(tokenResponse.AccessToken) == 0 {
+		return "", 0, fmt.Errorf("schema registry token response has no access_token")
+	}
+	return tokenResponse.AccessToken, tokenResponse.ExpiresIn, nil
+}
+
+// Utility Function For Getting The Names Of The Missing (Empty) Credentials
+func missingCredentials(credentials map[string]string) []string {
+	var missing []string
+	for _, name := range []string{"username", "password", "token", "clientId", "clientSecret"} {
+		if value, ok := credentials[name]; ok && len(value) == 0 {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+)
+
+// Test The ValidateRegistryAuth() Functionality
+func TestValidateRegistryAuth(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name        string
+		authConfig  config.EKSchemaRegistryAuthConfig
+		credentials RegistryCredentials
+		expectError bool
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "No Auth", authConfig: config.EKSchemaRegistryAuthConfig{}},
+		{name: "None", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthNone}},
+		{name: "Basic", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthBasic, SecretName: "registry"},
+			credentials: RegistryCredentials{Username: "user", Password: "pass"}},
+		{name: "Basic Without Password", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthBasic, SecretName: "registry"},
+			credentials: RegistryCredentials{Username: "user"}, expectError: true},
+		{name: "Basic Without Secret", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthBasic},
+			credentials: RegistryCredentials{Username: "user", Password: "pass"}, expectError: true},
+		{name: "Bearer", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthBearer, SecretName: "registry"},
+			credentials: RegistryCredentials{Token: "token"}},
+		{name: "Bearer Without Token", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthBearer, SecretName: "registry"}, expectError: true},
+		{name: "OAuth2", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthOAuth2, SecretName: "registry", TokenURL: "https://keycloak/token"},
+			credentials: RegistryCredentials{ClientId: "id", ClientSecret: "secret"}},
+		{name: "OAuth2 Without TokenURL", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthOAuth2, SecretName: "registry"},
+			credentials: RegistryCredentials{ClientId: "id", ClientSecret: "secret"}, expectError: true},
+		{name: "OAuth2 Without Client Secret", authConfig: config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthOAuth2, SecretName: "registry", TokenURL: "https://keycloak/token"},
+			credentials: RegistryCredentials{ClientId: "id"}, expectError: true},
+		{name: "Unknown Mode", authConfig: config.EKSchemaRegistryAuthConfig{Mode: "kerberos", SecretName: "registry"}, expectError: true},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ValidateRegistryAuth(testCase.authConfig, testCase.credentials)
+			assert.Equal(t, testCase.expectError, err != nil, err)
+		})
+	}
+}
+
+// Test The Authentication Of Schema Registry Requests
+func TestRegistryTransport(t *testing.T) {
+
+	// Create A Test Token Endpoint Counting The Tokens Issued
+	tokens := 0
+	tokenServer := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		clientId, clientSecret, ok := request.BasicAuth()
+		if !ok || clientId != "id" || clientSecret != "secret" || request.FormValue("grant_type") != "client_credentials" {
+			writer.WriteHeader(nethttp.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "registry-read openid", request.FormValue("scope"))
+		tokens++
+		_, _ = fmt.Fprintf(writer, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 300}`, tokens)
+	}))
+	defer tokenServer.Close()
+
+	// Create A Test Registry Echoing The Authorization Header
+	registry := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		_, _ = writer.Write([]byte(request.Header.Get("Authorization")))
+	}))
+	defer registry.Close()
+	authorization := func(transport nethttp.RoundTripper) string {
+		response, err := (&nethttp.Client{Transport: transport}).Get(registry.URL)
+		assert.Nil(t, err)
+		defer response.Body.Close()
+		body := make([]byte, 128)
+		n, _ := response.Body.Read(body)
+		return string(body[:n])
+	}
+
+	// No Authentication Uses The Default Transport
+	assert.Nil(t, newRegistryTransport(config.EKSchemaRegistryAuthConfig{}, RegistryCredentials{}))
+
+	// Basic & Bearer Authentication Send The Credentials
+	credentials := RegistryCredentials{Username: "user", Password: "pass", Token: "static", ClientId: "id", ClientSecret: "secret"}
+	assert.Equal(t, "Basic dXNlcjpwYXNz", authorization(newRegistryTransport(config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthBasic}, credentials)))
+	assert.Equal(t, "Bearer static", authorization(newRegistryTransport(config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthBearer}, credentials)))
+
+	// OAuth2 Tokens Are Reused Until They Expire
+	authConfig := config.EKSchemaRegistryAuthConfig{Mode: SchemaRegistryAuthOAuth2, TokenURL: tokenServer.URL, Scopes: []string{"registry-read", "openid"}}
+	transport := newRegistryTransport(authConfig, credentials).(*bearerTransport)
+	assert.Equal(t, "Bearer token-1", authorization(transport))
+	assert.Equal(t, "Bearer token-1", authorization(transport))
+	tokenSource := &clientCredentialsTokenSource{tokenURL: tokenServer.URL, scopes: authConfig.Scopes, clientId: "id", clientSecret: "secret",
+		httpClient: &nethttp.Client{}, now: time.Now}
+	now := time.Now()
+	tokenSource.now = func() time.Time { return now }
+	token, err := tokenSource.Token(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "token-2", token)
+	now = now.Add(5 * time.Minute)
+	token, err = tokenSource.Token(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "token-3", token)
+
+	// Token Endpoint Failures Fail The Request
+	tokenSource = &clientCredentialsTokenSource{tokenURL: tokenServer.URL, clientId: "id", clientSecret: "wrong", httpClient: &nethttp.Client{}, now: time.Now}
+	_, err = tokenSource.Token(context.TODO())
+	assert.NotNil(t, err)
+	_, err = (&nethttp.Client{Transport: &bearerTransport{token: tokenSource.Token}}).Get(registry.URL)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+)
+
+// Subject Registry API Constants
+const (
+	JsonSchemaType             = "JSON"
+	ConfluentAcceptHeader      = "application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, application/json"
+	ApicurioGroup              = "default"
+	ApicurioArtifactTypeHeader = "X-Registry-ArtifactType"
+)
+
+//
+// The API Of A Schema Registry Looking Up The Latest Schema Registered Under A Subject
+//
+// The Confluent API is also implemented by Karapace and by the compatibility API of the Apicurio Registry
+// (/apis/ccompat/v6), while the native API of the Apicurio Registry (/apis/registry/v2) has its own implementation.
+//
+type subjectAPI interface {
+
+	// The URL Of The Latest Schema Of The Specified Subject
+	url(baseURL string, subject string) string
+
+	// The Accept Header Of Requests For Schemas (Empty If None)
+	accept() string
+
+	// Parse The Response Of A Request For A Schema (ErrSchemaNotFound If It Is Invalid Or Not A JSON Schema)
+	parse(body []byte, header nethttp.Header) (*Schema, error)
+}
+
+// Get The subjectAPI Of The Specified Schema Registry Type (nil If The Registry Has No Subjects)
+func newSubjectAPI(registryType string) subjectAPI {
+	switch registryType {
+	case SchemaRegistryTypeConfluent, SchemaRegistryTypeKarapace, SchemaRegistryTypeApicurioCompat:
+		return confluentAPI{}
+	case SchemaRegistryTypeApicurio:
+		return apicurioAPI{}
+	default:
+		return nil
+	}
+}
+
+// The Confluent Schema Registry API (Also Implemented By Karapace & The Apicurio Compatibility API)
+type confluentAPI struct{}
+
+// The Latest Version Of The Subject
+func (confluentAPI) url(baseURL string, subject string) string {
+	return fmt.Sprintf("%s/subjects/%s/versions/latest", baseURL, url.PathEscape(subject))
+}
+
+// The Schema Registry Content Types (Some Implementations Reject Requests Without Them)
+func (confluentAPI) accept() string {
+	return ConfluentAcceptHeader
+}
+
+// The Schema Is A String Field, Whose schemaType Is Omitted For Avro Schemas
+func (confluentAPI) parse(body []byte, _ nethttp.Header) (*Schema, error) {
+	var registeredSchema struct {
+		SchemaType string `json:"schemaType"`
+		Schema     string `json:"schema"`
+	}
+	if err := json.Unmarshal(body, &registeredSchema); err != nil {
+		return nil, fmt.Errorf("%w: invalid registry response: %v", ErrSchemaNotFound, err)
+	}
+	if len(registeredSchema.SchemaType) == 0 {
+		registeredSchema.SchemaType = "AVRO"
+	}
+	if registeredSchema.SchemaType != JsonSchemaType {
+		return nil, fmt.Errorf("%w: schema type %s is not supported", ErrSchemaNotFound, registeredSchema.SchemaType)
+	}
+	return parseRegisteredSchema([]byte(registeredSchema.Schema))
+}
+
+// The Native Apicurio Registry (v2) API, Whose Artifacts Of The Default Group Are Identified By Their Subject
+type apicurioAPI struct{}
+
+// The Latest Version Of The Artifact
+func (apicurioAPI) url(baseURL string, subject string) string {
+	return fmt.Sprintf("%s/groups/%s/artifacts/%s", baseURL, ApicurioGroup, url.PathEscape(subject))
+}
+
+// The Artifact Is Returned As-Is
+func (apicurioAPI) accept() string {
+	return ""
+}
+
+// The Artifact Is The Schema Itself, With Its Type As A Header
+func (apicurioAPI) parse(body []byte, header nethttp.Header) (*Schema, error) {
+	if artifactType := header.Get(ApicurioArtifactTypeHeader); len(artifactType) > 0 && artifactType != JsonSchemaType {
+		return nil, fmt.Errorf("%w: schema type %s is not supported", ErrSchemaNotFound, artifactType)
+	}
+	return parseRegisteredSchema(body)
+}
@@ -0,0 +1,448 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	nethttp "net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema Registry Constants
+const (
+	DefaultSchemaCacheDuration = 5 * time.Minute
+	SchemaFetchTimeout         = 5 * time.Second
+	MaxSchemaBytes             = 1024 * 1024
+)
+
+// Schema Registry Types
+const (
+	SchemaRegistryTypeDataSchema     = "dataschema"
+	SchemaRegistryTypeConfluent      = "confluent"
+	SchemaRegistryTypeKarapace       = "karapace"
+	SchemaRegistryTypeApicurio       = "apicurio"
+	SchemaRegistryTypeApicurioCompat = "apicurio-ccompat"
+)
+
+// Subject Name Strategies (Deriving The Subject Of An Event's Schema In Registries With Subjects)
+const (
+	SubjectNameStrategyTopic       = "topic"
+	SubjectNameStrategyRecord      = "record"
+	SubjectNameStrategyTopicRecord = "topic-record"
+)
+
+// The Error Returned By The SchemaRegistry When A Schema Is Not Registered
+var ErrSchemaNotFound = errors.New("schema not found in the registry")
+
+// The Error Returned By The SchemaRegistry When No Schema Is Registered Under A Subject (Is Also ErrSchemaNotFound)
+var ErrSubjectNotFound = fmt.Errorf("%w: no schema is registered under the subject", ErrSchemaNotFound)
+
+//
+// A Subset Of JSON Schema Sufficient For Validating Event Data
+//
+// The supported keywords are type, enum, const, required, properties, additionalProperties (as a boolean),
+// items (as a single schema), minItems, maxItems, minLength, maxLength, pattern, minimum & maximum.  Any other
+// keywords are ignored, so schemas using them are validated less strictly rather than rejected.
+//
+type Schema struct {
+	Type                 schemaTypes        `json:"type,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Const                *interface{}       `json:"const,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"-"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	pattern              *regexp.Regexp
+}
+
+// The JSON Schema "type" Keyword, Which May Be A Single Type Or A List Of Types
+type schemaTypes []string
+
+// Unmarshal A Single Type Or A List Of Types
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("type must be a string or a list of strings: %w", err)
+	}
+	*t = multiple
+	return nil
+}
+
+// Parse The Specified JSON Schema (Compiling Its Patterns)
+func ParseSchema(data []byte) (*Schema, error) {
+	schema := &Schema{}
+	err := json.Unmarshal(data, schema)
+	if err != nil {
+		return nil, err
+	}
+	return schema, schema.compile()
+}
+
+// Unmarshal The Schema, Interpreting A Boolean additionalProperties (Schemas Are Ignored)
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plainSchema Schema
+	err := json.Unmarshal(data, (*plainSchema)(s))
+	if err != nil {
+		return err
+	}
+	var additionalProperties struct {
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	err = json.Unmarshal(data, &additionalProperties)
+	if err != nil {
+		return err
+	}
+	var allowed bool
+	if json.Unmarshal(additionalProperties.AdditionalProperties, &allowed) == nil {
+		s.AdditionalProperties = &allowed
+	}
+	return nil
+}
+
+// Compile The Patterns Of The Schema & Its Sub-Schemas
+func (s *Schema) compile() error {
+	if len(s.Pattern) > 0 {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = pattern
+	}
+	for _, property := range s.Properties {
+		if property != nil {
+			if err := property.compile(); err != nil {
+				return err
+			}
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+// Validate The Specified JSON Data Against The Schema, Returning The Violations (None If Valid)
+func (s *Schema) Validate(data []byte) []string {
+	var value interface{}
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return []string{fmt.Sprintf("data is not valid JSON: %v", err)}
+	}
+	return s.validate(value, "data")
+}
+
+// Validate A Decoded JSON Value At The Specified Path Against The Schema
+func (s *Schema) validate(value interface{}, path string) []string {
+	if s == nil {
+		return nil
+	}
+
+	// Validate The Type (Any Other Violations Would Be Meaningless If It Does Not Match)
+	if len(s.Type) > 0 && !s.matchesType(value) {
+		return []string{fmt.Sprintf("%s must be of type %s", path, strings.Join(s.Type, " or "))}
+	}
+
+	// Validate The Enum & Const Keywords
+	var violations []string
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		violations = append(violations, fmt.Sprintf("%s must be one of %v", path, s.Enum))
+	}
+	if s.Const != nil && !reflect.DeepEqual(*s.Const, value) {
+		violations = append(violations, fmt.Sprintf("%s must be %v", path, *s.Const))
+	}
+
+	// Validate The Type Specific Keywords
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		violations = append(violations, s.validateObject(typedValue, path)...)
+	case []interface{}:
+		if s.MinItems != nil && len(typedValue) < *s.MinItems {
+			violations = append(violations, fmt.Sprintf("%s must have at least %d items", path, *s.MinItems))
+		}
+		if s.MaxItems != nil && len(typedValue) > *s.MaxItems {
+			violations = append(violations, fmt.Sprintf("%s must have at most %d items", path, *s.MaxItems))
+		}
+		for index, item := range typedValue {
+			violations = append(violations, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, index))...)
+		}
+	case string:
+		length := utf8.RuneCountInString(typedValue)
+		if s.MinLength != nil && length < *s.MinLength {
+			violations = append(violations, fmt.Sprintf("%s must be at least %d characters", path, *s.MinLength))
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			violations = append(violations, fmt.Sprintf("%s must be at most %d characters", path, *s.MaxLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(typedValue) {
+			violations = append(violations, fmt.Sprintf("%s must match the pattern %q", path, s.Pattern))
+		}
+	case float64:
+		if s.Minimum != nil && typedValue < *s.Minimum {
+			violations = append(violations, fmt.Sprintf("%s must be at least %v", path, *s.Minimum))
+		}
+		if s.Maximum != nil && typedValue > *s.Maximum {
+			violations = append(violations, fmt.Sprintf("%s must be at most %v", path, *s.Maximum))
+		}
+	}
+	return violations
+}
+
+// Validate The Keywords Of An Object Value (Properties Are Validated In Sorted Order For Stable Violations)
+func (s *Schema) validateObject(object map[string]interface{}, path string) []string {
+	var violations []string
+	for _, required := range s.Required {
+		if _, ok := object[required]; !ok {
+			violations = append(violations, fmt.Sprintf("%s.%s is required", path, required))
+		}
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				violations = append(violations, fmt.Sprintf("%s.%s is not allowed", path, name))
+			}
+			continue
+		}
+		violations = append(violations, property.validate(object[name], path+"."+name)...)
+	}
+	return violations
+}
+
+// Determine Whether The Decoded JSON Value Matches Any Of The Schema's Types
+func (s *Schema) matchesType(value interface{}) bool {
+	for _, schemaType := range s.Type {
+		switch typedValue := value.(type) {
+		case nil:
+			if schemaType == "null" {
+				return true
+			}
+		case bool:
+			if schemaType == "boolean" {
+				return true
+			}
+		case string:
+			if schemaType == "string" {
+				return true
+			}
+		case float64:
+			if schemaType == "number" || (schemaType == "integer" && typedValue == math.Trunc(typedValue)) {
+				return true
+			}
+		case []interface{}:
+			if schemaType == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if schemaType == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Utility Function For Determining Whether The Values Contain The Specified Value
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// Registry Of The JSON Schemas Validating The Data Of Events
+//
+// A "dataschema" registry fetches schemas over HTTP from the dataschema URI of events, which must refer to the
+// registry's base URL so that events cannot direct the receiver to fetch arbitrary URLs.  The other registries
+// instead look up the latest schema registered under a subject (derived from the KafkaChannel's topic and/or the
+// event's type by the Validator) through their subjectAPI.  Schemas are cached for the cache duration, and schemas
+// which are not found (or are invalid) are cached as such, so that events referencing them are rejected without
+// repeatedly fetching them.  Requests are authenticated by the transport (the default transport if nil).
+//
+type SchemaRegistry struct {
+	baseURL       string
+	subjects      subjectAPI
+	cacheDuration time.Duration
+	httpClient    *nethttp.Client
+	cache         map[string]*cachedSchema
+	lock          sync.Mutex
+	now           func() time.Time
+}
+
+// A Fetched Schema (Or The Reason It Could Not Be Used) & When It Expires
+type cachedSchema struct {
+	schema  *Schema
+	err     error
+	expires time.Time
+}
+
+// SchemaRegistry Constructor (The Type Defaults To A "dataschema" Registry)
+func NewSchemaRegistry(registryType string, baseURL string, cacheDuration time.Duration, transport nethttp.RoundTripper) *SchemaRegistry {
+	subjects := newSubjectAPI(registryType)
+	if subjects != nil {
+		baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+	if cacheDuration <= 0 {
+		cacheDuration = DefaultSchemaCacheDuration
+	}
+	return &SchemaRegistry{
+		baseURL:       baseURL,
+		subjects:      subjects,
+		cacheDuration: cacheDuration,
+		httpClient:    &nethttp.Client{Timeout: SchemaFetchTimeout, Transport: transport},
+		cache:         make(map[string]*cachedSchema),
+		now:           time.Now,
+	}
+}
+
+// Determine Whether The Registry Looks Up Schemas By Subject (Rather Than By dataschema)
+func (r *SchemaRegistry) HasSubjects() bool {
+	return r.subjects != nil
+}
+
+// Determine Whether The Specified dataschema Refers To The Registry (Never For Subject Registries)
+func (r *SchemaRegistry) Contains(dataSchema string) bool {
+	return !r.HasSubjects() && strings.HasPrefix(dataSchema, r.baseURL)
+}
+
+// Get The Schema Of The Specified dataschema (ErrSchemaNotFound If Not Registered Or Invalid, Otherwise Any Other Error Is Transient)
+func (r *SchemaRegistry) Schema(ctx context.Context, dataSchema string) (*Schema, error) {
+	if !r.Contains(dataSchema) {
+		return nil, ErrSchemaNotFound
+	}
+	return r.cachedSchema(dataSchema, func() (*Schema, error) {
+		return r.fetchDataSchema(ctx, dataSchema)
+	})
+}
+
+// Get The Latest Schema Of The Specified Subject (ErrSubjectNotFound If None Is Registered, ErrSchemaNotFound If
+// It Is Invalid Or Not A JSON Schema, Otherwise Any Other Error Is Transient)
+func (r *SchemaRegistry) SubjectSchema(ctx context.Context, subject string) (*Schema, error) {
+	if !r.HasSubjects() {
+		return nil, ErrSubjectNotFound
+	}
+	return r.cachedSchema(subject, func() (*Schema, error) {
+		return r.fetchSubjectSchema(ctx, subject)
+	})
+}
+
+// Return The Cached Schema Of The Specified Key If It Has Not Expired, Otherwise Fetch It (Caching It Unless The Failure Was Transient)
+func (r *SchemaRegistry) cachedSchema(key string, fetch func() (*Schema, error)) (*Schema, error) {
+	r.lock.Lock()
+	cached, ok := r.cache[key]
+	r.lock.Unlock()
+	if ok && r.now().Before(cached.expires) {
+		return cached.schema, cached.err
+	}
+	schema, err := fetch()
+	if err == nil || errors.Is(err, ErrSchemaNotFound) {
+		r.lock.Lock()
+		r.cache[key] = &cachedSchema{schema: schema, err: err, expires: r.now().Add(r.cacheDuration)}
+		r.lock.Unlock()
+	}
+	return schema, err
+}
+
+// Fetch & Parse The Schema Of The Specified dataschema
+func (r *SchemaRegistry) fetchDataSchema(ctx context.Context, dataSchema string) (*Schema, error) {
+	body, _, err := r.get(ctx, dataSchema, "", ErrSchemaNotFound)
+	if err != nil {
+		return nil, err
+	}
+	return parseRegisteredSchema(body)
+}
+
+// Fetch & Parse The Latest Schema Of The Specified Subject
+func (r *SchemaRegistry) fetchSubjectSchema(ctx context.Context, subject string) (*Schema, error) {
+	body, header, err := r.get(ctx, r.subjects.url(r.baseURL, subject), r.subjects.accept(), ErrSubjectNotFound)
+	if err != nil {
+		return nil, err
+	}
+	return r.subjects.parse(body, header)
+}
+
+// Get The Body & Header Of The Specified Registry URL (The notFound Error If It Does Not Exist)
+func (r *SchemaRegistry) get(ctx context.Context, registryURL string, accept string, notFound error) ([]byte, nethttp.Header, error) {
+	request, err := nethttp.NewRequest(nethttp.MethodGet, registryURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrSchemaNotFound, err)
+	}
+	if len(accept) > 0 {
+		request.Header.Set("Accept", accept)
+	}
+	response, err := r.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+	switch {
+	case response.StatusCode == nethttp.StatusNotFound:
+		return nil, nil, notFound
+	case response.StatusCode == nethttp.StatusUnauthorized || response.StatusCode == nethttp.StatusForbidden:
+		return nil, nil, fmt.Errorf("schema registry rejected the credentials with response status %d", response.StatusCode)
+	case response.StatusCode != nethttp.StatusOK:
+		return nil, nil, fmt.Errorf("unexpected schema registry response status %d", response.StatusCode)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, MaxSchemaBytes+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) > MaxSchemaBytes {
+		return nil, nil, fmt.Errorf("%w: schema exceeds %d bytes", ErrSchemaNotFound, MaxSchemaBytes)
+	}
+	return body, response.Header, nil
+}
+
+// Utility Function For Parsing A Schema Fetched From The Registry (Invalid Schemas Are Not Found)
+func parseRegisteredSchema(body []byte) (*Schema, error) {
+	schema, err := ParseSchema(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid schema: %v", ErrSchemaNotFound, err)
+	}
+	return schema, nil
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Data
+const testSchema = `{
+  "type": "object",
+  "required": ["name", "count"],
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string", "minLength": 2, "maxLength": 5, "pattern": "^[a-z]+$"},
+    "count": {"type": "integer", "minimum": 1, "maximum": 10},
+    "color": {"enum": ["red", "green"]},
+    "version": {"const": 1},
+    "tags": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string"}},
+    "note": {"type": ["string", "null"], "additionalProperties": {"type": "string"}}
+  }
+}`
+
+// Test The ParseSchema() Functionality
+func TestParseSchema(t *testing.T) {
+	schema, err := ParseSchema([]byte(testSchema))
+	assert.Nil(t, err)
+	assert.Equal(t, schemaTypes{"object"}, schema.Type)
+	assert.False(t, *schema.AdditionalProperties)
+	assert.Equal(t, schemaTypes{"string", "null"}, schema.Properties["note"].Type)
+	assert.Nil(t, schema.Properties["note"].AdditionalProperties)
+	assert.NotNil(t, schema.Properties["name"].pattern)
+	_, err = ParseSchema([]byte(`{"properties": {"name": {"pattern": "["}}}`))
+	assert.NotNil(t, err)
+	_, err = ParseSchema([]byte(`{"type": 1}`))
+	assert.NotNil(t, err)
+	_, err = ParseSchema([]byte(`[`))
+	assert.NotNil(t, err)
+}
+
+// Test The Schema's Validate() Functionality
+func TestSchemaValidate(t *testing.T) {
+	schema, err := ParseSchema([]byte(testSchema))
+	assert.Nil(t, err)
+	assert.Empty(t, schema.Validate([]byte(`{"name": "abc", "count": 3, "color": "red", "version": 1, "tags": ["a"], "note": null}`)))
+	assert.Equal(t, []string{"data must be of type object"}, schema.Validate([]byte(`[]`)))
+	assert.Equal(t, []string{"data.name is required", "data.count is required"}, schema.Validate([]byte(`{}`)))
+	assert.Equal(t, []string{
+		"data.color must be one of [red green]",
+		"data.count must be of type integer",
+		"data.name must be at most 5 characters",
+		"data.name must match the pattern \"^[a-z]+$\"",
+		"data.other is not allowed",
+		"data.tags must have at most 2 items",
+		"data.tags[1] must be of type string",
+		"data.version must be 1",
+	}, schema.Validate([]byte(`{"name": "ABCDEF", "count": 1.5, "color": "blue", "version": 2, "tags": ["a", 1, "c"], "other": true}`)))
+	assert.Equal(t, []string{
+		"data.count must be at least 1",
+		"data.name must be at least 2 characters",
+		"data.tags must have at least 1 items",
+	}, schema.Validate([]byte(`{"name": "a", "count": 0, "tags": []}`)))
+	assert.Equal(t, []string{"data.count must be at most 10"}, schema.Validate([]byte(`{"name": "ab", "count": 11}`)))
+	assert.Len(t, schema.Validate([]byte(`{`)), 1)
+}
+
+// Test The SchemaRegistry Functionality
+func TestSchemaRegistry(t *testing.T) {
+
+	// Create A Test Schema Registry Counting The Requests
+	requests := 0
+	registry := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		requests++
+		switch request.URL.Path {
+		case "/schemas/valid":
+			_, _ = writer.Write([]byte(testSchema))
+		case "/schemas/invalid":
+			_, _ = writer.Write([]byte(`{"type": 1}`))
+		case "/schemas/unavailable":
+			writer.WriteHeader(nethttp.StatusBadGateway)
+		default:
+			writer.WriteHeader(nethttp.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+	now := time.Now()
+	schemaRegistry := NewSchemaRegistry("", registry.URL+"/schemas/", time.Minute, nil)
+	schemaRegistry.now = func() time.Time { return now }
+	ctx := context.TODO()
+
+	// Schemas Outside The Registry Are Never Fetched
+	assert.False(t, schemaRegistry.Contains("http://elsewhere/schemas/valid"))
+	_, err := schemaRegistry.Schema(ctx, "http://elsewhere/schemas/valid")
+	assert.True(t, errors.Is(err, ErrSchemaNotFound))
+	assert.Equal(t, 0, requests)
+
+	// Schemas Are Fetched & Cached Until They Expire
+	schema, err := schemaRegistry.Schema(ctx, registry.URL+"/schemas/valid")
+	assert.Nil(t, err)
+	assert.NotNil(t, schema)
+	_, _ = schemaRegistry.Schema(ctx, registry.URL+"/schemas/valid")
+	assert.Equal(t, 1, requests)
+	now = now.Add(2 * time.Minute)
+	_, _ = schemaRegistry.Schema(ctx, registry.URL+"/schemas/valid")
+	assert.Equal(t, 2, requests)
+
+	// Unknown & Invalid Schemas Are Not Found (And Cached As Such)
+	for _, path := range []string{"/schemas/unknown", "/schemas/invalid"} {
+		_, err = schemaRegistry.Schema(ctx, registry.URL+path)
+		assert.True(t, errors.Is(err, ErrSchemaNotFound), path)
+		_, err = schemaRegistry.Schema(ctx, registry.URL+path)
+		assert.True(t, errors.Is(err, ErrSchemaNotFound), path)
+	}
+	assert.Equal(t, 4, requests)
+
+	// Transient Failures Are Not Cached
+	for i := 0; i < 2; i++ {
+		_, err = schemaRegistry.Schema(ctx, registry.URL+"/schemas/unavailable")
+		assert.NotNil(t, err)
+		assert.False(t, errors.Is(err, ErrSchemaNotFound))
+	}
+	assert.Equal(t, 6, requests)
+
+	// The Cache Duration Is Defaulted
+	assert.Equal(t, DefaultSchemaCacheDuration, NewSchemaRegistry("", registry.URL, 0, nil).cacheDuration)
+}
+
+// Test The SchemaRegistry's SubjectSchema() Functionality With Each Registry Type
+func TestSubjectSchemaRegistry(t *testing.T) {
+
+	// Create A Test Registry Serving Both The Confluent (Requiring Its Content Types) & Apicurio APIs
+	requests := 0
+	registry := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		requests++
+		if strings.HasPrefix(request.URL.Path, "/subjects/") && request.Header.Get("Accept") != ConfluentAcceptHeader {
+			writer.WriteHeader(nethttp.StatusNotAcceptable)
+			return
+		}
+		switch request.URL.Path {
+		case "/subjects/topic-value/versions/latest":
+			_, _ = writer.Write([]byte(`{"subject": "topic-value", "version": 3, "id": 7, "schemaType": "JSON", "schema": "{\"type\": \"object\"}"}`))
+		case "/subjects/avro-value/versions/latest":
+			_, _ = writer.Write([]byte(`{"subject": "avro-value", "version": 1, "id": 8, "schema": "{\"type\": \"record\"}"}`))
+		case "/subjects/unavailable-value/versions/latest", "/groups/default/artifacts/unavailable-value":
+			writer.WriteHeader(nethttp.StatusServiceUnavailable)
+		case "/groups/default/artifacts/topic-value":
+			writer.Header().Set(ApicurioArtifactTypeHeader, JsonSchemaType)
+			_, _ = writer.Write([]byte(`{"type": "object"}`))
+		case "/groups/default/artifacts/avro-value":
+			writer.Header().Set(ApicurioArtifactTypeHeader, "AVRO")
+			_, _ = writer.Write([]byte(`{"type": "record"}`))
+		default:
+			writer.WriteHeader(nethttp.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+	ctx := context.TODO()
+
+	for _, registryType := range []string{SchemaRegistryTypeConfluent, SchemaRegistryTypeKarapace, SchemaRegistryTypeApicurio, SchemaRegistryTypeApicurioCompat} {
+		t.Run(registryType, func(t *testing.T) {
+			requests = 0
+			schemaRegistry := NewSchemaRegistry(registryType, registry.URL+"/", time.Minute, nil)
+			assert.True(t, schemaRegistry.HasSubjects())
+			assert.False(t, schemaRegistry.Contains(registry.URL+"/subjects/topic-value"))
+
+			// JSON Schemas Are Fetched & Cached
+			schema, err := schemaRegistry.SubjectSchema(ctx, "topic-value")
+			assert.Nil(t, err)
+			assert.Equal(t, schemaTypes{"object"}, schema.Type)
+			_, _ = schemaRegistry.SubjectSchema(ctx, "topic-value")
+			assert.Equal(t, 1, requests)
+
+			// Unregistered Subjects Are Distinguished From Unsupported Schema Types (Both Are Not Found)
+			_, err = schemaRegistry.SubjectSchema(ctx, "unknown-value")
+			assert.True(t, errors.Is(err, ErrSubjectNotFound))
+			assert.True(t, errors.Is(err, ErrSchemaNotFound))
+			_, err = schemaRegistry.SubjectSchema(ctx, "avro-value")
+			assert.False(t, errors.Is(err, ErrSubjectNotFound))
+			assert.True(t, errors.Is(err, ErrSchemaNotFound))
+			assert.Contains(t, err.Error(), "schema type AVRO is not supported")
+
+			// Transient Failures Are Not Treated As Not Found
+			_, err = schemaRegistry.SubjectSchema(ctx, "unavailable-value")
+			assert.NotNil(t, err)
+			assert.False(t, errors.Is(err, ErrSchemaNotFound))
+		})
+	}
+
+	// DataSchema Registries Have No Subjects
+	schemaRegistry := NewSchemaRegistry("", registry.URL, time.Minute, nil)
+	assert.False(t, schemaRegistry.HasSubjects())
+	_, err := schemaRegistry.SubjectSchema(ctx, "topic-value")
+	assert.True(t, errors.Is(err, ErrSubjectNotFound))
+}
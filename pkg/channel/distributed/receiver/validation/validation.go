@@ -0,0 +1,411 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	nethttp "net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/receiver/batch"
+)
+
+// Problem Details (RFC 7807) Constants
+const (
+	ProblemContentType    = "application/problem+json"
+	InvalidEventType      = "urn:knative:eventing-kafka:invalid-event"
+	SchemaUnavailableType = "urn:knative:eventing-kafka:schema-unavailable"
+)
+
+// The Maximum Length Of Extension Attribute Names Recommended By The CloudEvents Spec
+const MaxExtensionNameLength = 20
+
+// The Characters Allowed In Extension Attribute Names By The CloudEvents Spec
+var extensionNameRegexp = regexp.MustCompile("^[a-z0-9]+$")
+
+// A Problem Details (RFC 7807) Response Describing Why An Event Was Rejected
+type Problem struct {
+	Type       string   `json:"type"`
+	Title      string   `json:"title"`
+	Status     int      `json:"status"`
+	Detail     string   `json:"detail,omitempty"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Problem Implements The error Interface
+func (p *Problem) Error() string {
+	return fmt.Sprintf("%s: %s", p.Title, strings.Join(p.Violations, ", "))
+}
+
+//
+// Ingress Validation Of The Events Received By The Receiver
+//
+// Events are validated before they are produced to Kafka, so that invalid events are rejected at the edge
+// rather than being dispatched to (and failing in) every subscriber.  The validation levels are cumulative...
+//
+//   - The CloudEvents spec's requirements are always validated (as by the CloudEvents SDK).
+//   - Strict validation additionally requires specversion 1.0, an absolute dataschema URI, extension attribute
+//     names of at most 20 lowercase letters & digits, and valid JSON data when the datacontenttype is JSON.
+//   - Every event must carry the RequiredExtensions.
+//   - The data of events whose dataschema refers to the SchemaRegistry must be valid against the schema, or with
+//     a Confluent or Apicurio SchemaRegistry, against the latest schema of the event's subject.  The subject is
+//     named by the SubjectNameStrategy from the KafkaChannel's topic ("<topic>-value"), the event's type (the
+//     record name) or both ("<topic>-<type>").
+//
+// A nil *Validator is valid and does not validate any events.
+//
+type Validator struct {
+	logger              *zap.Logger
+	strict              bool
+	requiredExtensions  []string
+	schemaRegistry      *SchemaRegistry
+	subjectNameStrategy string
+	schemaRequired      bool
+	topicResolver       TopicResolver
+}
+
+// Resolves The Kafka Topic Of The KafkaChannel Addressed By The Host Of A Request (For Naming Schema Subjects)
+type TopicResolver func(host string) (string, error)
+
+// Validate The Specified Validation Config
+func ValidateValidationConfig(validationConfig config.EKValidationConfig) error {
+	for _, extension := range validationConfig.RequiredExtensions {
+		if !extensionNameRegexp.MatchString(extension) {
+			return fmt.Errorf("requiredExtensions %q must consist of lowercase letters & digits", extension)
+		}
+	}
+	if len(validationConfig.SchemaRegistryURL) > 0 {
+		registryURL, err := url.Parse(validationConfig.SchemaRegistryURL)
+		if err != nil || !registryURL.IsAbs() || len(registryURL.Host) == 0 {
+			return fmt.Errorf("schemaRegistryURL %q must be an absolute URL", validationConfig.SchemaRegistryURL)
+		}
+	}
+	switch validationConfig.SchemaRegistryType {
+	case "", SchemaRegistryTypeDataSchema, SchemaRegistryTypeConfluent, SchemaRegistryTypeKarapace, SchemaRegistryTypeApicurio, SchemaRegistryTypeApicurioCompat:
+	default:
+		return fmt.Errorf("schemaRegistryType %q must be one of %s, %s, %s, %s or %s", validationConfig.SchemaRegistryType, SchemaRegistryTypeDataSchema,
+			SchemaRegistryTypeConfluent, SchemaRegistryTypeKarapace, SchemaRegistryTypeApicurio, SchemaRegistryTypeApicurioCompat)
+	}
+	switch validationConfig.SubjectNameStrategy {
+	case "", SubjectNameStrategyTopic, SubjectNameStrategyRecord, SubjectNameStrategyTopicRecord:
+	default:
+		return fmt.Errorf("subjectNameStrategy %q must be one of %s, %s or %s", validationConfig.SubjectNameStrategy,
+			SubjectNameStrategyTopic, SubjectNameStrategyRecord, SubjectNameStrategyTopicRecord)
+	}
+	if len(validationConfig.SchemaRegistryType) > 0 && len(validationConfig.SchemaRegistryURL) == 0 {
+		return fmt.Errorf("schemaRegistryType %q requires a schemaRegistryURL", validationConfig.SchemaRegistryType)
+	}
+	if validationConfig.SchemaCacheSeconds < 0 {
+		return fmt.Errorf("schemaCacheSeconds %d must not be negative", validationConfig.SchemaCacheSeconds)
+	}
+	return nil
+}
+
+// Validator Constructor - Returns nil If Validation Is Not Enabled (Assumes A Valid Config & Registry Credentials)
+// The TopicResolver (Optional) Is Required For Naming The Subjects Of The "topic" & "topic-record" Strategies
+func NewValidator(logger *zap.Logger, validationConfig config.EKValidationConfig, credentials RegistryCredentials, topicResolver TopicResolver) *Validator {
+	if !validationConfig.Enabled {
+		return nil
+	}
+	validator := &Validator{
+		logger:              logger,
+		strict:              validationConfig.Strict,
+		requiredExtensions:  validationConfig.RequiredExtensions,
+		subjectNameStrategy: validationConfig.SubjectNameStrategy,
+		schemaRequired:      validationConfig.SchemaRequired,
+		topicResolver:       topicResolver,
+	}
+	if len(validator.subjectNameStrategy) == 0 {
+		validator.subjectNameStrategy = SubjectNameStrategyTopic
+	}
+	if len(validationConfig.SchemaRegistryURL) > 0 {
+		validator.schemaRegistry = NewSchemaRegistry(validationConfig.SchemaRegistryType, validationConfig.SchemaRegistryURL,
+			time.Duration(validationConfig.SchemaCacheSeconds)*time.Second, newRegistryTransport(validationConfig.SchemaRegistryAuth, credentials))
+	}
+	return validator
+}
+
+// Validate The Specified Event Produced To The Specified Topic (Empty If Unknown), Returning A Problem Describing Why It Is Invalid (nil If Valid)
+func (v *Validator) Validate(ctx context.Context, topicName string, cloudEvent *event.Event) *Problem {
+	if v == nil {
+		return nil
+	}
+
+	// Validate The CloudEvents Spec's Requirements & (If Strict) Recommendations
+	violations := specViolations(cloudEvent)
+	if v.strict {
+		violations = append(violations, strictViolations(cloudEvent)...)
+	}
+
+	// Validate The Required Extensions
+	extensions := cloudEvent.Extensions()
+	for _, extension := range v.requiredExtensions {
+		if _, ok := extensions[extension]; !ok {
+			violations = append(violations, fmt.Sprintf("extension %s is required", extension))
+		}
+	}
+
+	// Validate The Data Against Any Registered Schema (Only Once The Event Is Otherwise Valid)
+	if len(violations) == 0 && v.schemaRegistry != nil {
+		schemaViolations, err := v.schemaViolations(ctx, topicName, cloudEvent)
+		if err != nil {
+			v.logger.Warn("Failed To Fetch Schema From Registry", zap.String("Topic", topicName), zap.String("EventType", cloudEvent.Type()), zap.Error(err))
+			return &Problem{
+				Type:   SchemaUnavailableType,
+				Title:  "Schema Registry Unavailable",
+				Status: nethttp.StatusServiceUnavailable,
+				Detail: fmt.Sprintf("the schema of event %s could not be fetched from the registry", cloudEvent.ID()),
+			}
+		}
+		violations = append(violations, schemaViolations...)
+	}
+
+	// Return Any Violations As A Problem
+	if len(violations) == 0 {
+		return nil
+	}
+	return invalidEventProblem(fmt.Sprintf("event %s is invalid", cloudEvent.ID()), violations)
+}
+
+// Validate The Data Of The Specified Event Against Its Registered Schema (Returns An Error Only If The Registry Is Unavailable)
+func (v *Validator) schemaViolations(ctx context.Context, topicName string, cloudEvent *event.Event) ([]string, error) {
+
+	// Look Up The Schema Of The dataschema Or Subject (Events Without Either Are Not Validated)
+	var schema *Schema
+	var err error
+	var reference string
+	if v.schemaRegistry.HasSubjects() {
+		subject := v.subjectName(topicName, cloudEvent.Type())
+		if len(subject) == 0 {
+			return nil, nil
+		}
+		reference = "subject " + subject
+		schema, err = v.schemaRegistry.SubjectSchema(ctx, subject)
+		if errors.Is(err, ErrSubjectNotFound) && !v.schemaRequired {
+			return nil, nil
+		}
+	} else {
+		dataSchema := cloudEvent.DataSchema()
+		if !v.schemaRegistry.Contains(dataSchema) {
+			return nil, nil
+		}
+		reference = "dataschema " + dataSchema
+		schema, err = v.schemaRegistry.Schema(ctx, dataSchema)
+	}
+
+	// Validate The Data Against The Schema
+	switch {
+	case errors.Is(err, ErrSchemaNotFound):
+		return []string{fmt.Sprintf("%s is not registered: %v", reference, err)}, nil
+	case err != nil:
+		return nil, err
+	default:
+		return schema.Validate(cloudEvent.Data()), nil
+	}
+}
+
+// Get The Subject Of An Event's Schema As Named By The SubjectNameStrategy (Empty If The Topic Is Required But Unknown)
+func (v *Validator) subjectName(topicName string, eventType string) string {
+	switch v.subjectNameStrategy {
+	case SubjectNameStrategyRecord:
+		return eventType
+	case SubjectNameStrategyTopicRecord:
+		if len(topicName) == 0 {
+			return ""
+		}
+		return topicName + "-" + eventType
+	default:
+		if len(topicName) == 0 {
+			return ""
+		}
+		return topicName + "-value"
+	}
+}
+
+// Resolve The Topic Of The KafkaChannel Addressed By The Request (Empty If Unknown, Leaving The Next Handler To Reject It)
+func (v *Validator) resolveTopic(request *nethttp.Request) string {
+	if v.topicResolver == nil {
+		return ""
+	}
+	topicName, err := v.topicResolver(request.Host)
+	if err != nil {
+		v.logger.Debug("Failed To Resolve The Topic Of The Request", zap.String("Host", request.Host), zap.Error(err))
+		return ""
+	}
+	return topicName
+}
+
+// Wrap The Specified Handler To Reject Invalid Events With A Problem Details Response (Returns It As-Is If nil)
+func (v *Validator) Handler(next nethttp.Handler) nethttp.Handler {
+	if v == nil {
+		return next
+	}
+	return nethttp.HandlerFunc(func(response nethttp.ResponseWriter, request *nethttp.Request) {
+
+		// Only Event Requests Are Validated (Leaving Others To Be Rejected By The Next Handler)
+		if request.Method != nethttp.MethodPost {
+			next.ServeHTTP(response, request)
+			return
+		}
+
+		// Buffer The Body So That It Can Be Read By Both The Validator & The Next Handler
+		body, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			v.logger.Info("Failed To Read Request Body", zap.Error(err))
+			response.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+
+		// Validate The Batched Or Single Event
+		var problem *Problem
+		topicName := v.resolveTopic(request)
+		if batch.IsBatch(request) {
+			problem = v.validateBatch(request.Context(), topicName, body)
+		} else {
+			problem = v.validateMessage(request.Context(), topicName, request.Header, body)
+		}
+		if problem != nil {
+			v.logger.Info("Rejecting Invalid Event Request", zap.Any("Problem", problem))
+			WriteProblem(response, problem)
+			return
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(response, request)
+	})
+}
+
+// Validate The Binary Or Structured Event Of A Request
+func (v *Validator) validateMessage(ctx context.Context, topicName string, header nethttp.Header, body []byte) *Problem {
+	cloudEvent, err := binding.ToEvent(ctx, cehttp.NewMessage(header, ioutil.NopCloser(bytes.NewReader(body))))
+	if err != nil {
+		return invalidEventProblem("the request does not contain a CloudEvent", []string{err.Error()})
+	}
+	return v.Validate(ctx, topicName, cloudEvent)
+}
+
+// Validate The Events Of A Batched Request (Events Which Cannot Be Parsed Are Left For The Batch Handler To Report)
+func (v *Validator) validateBatch(ctx context.Context, topicName string, body []byte) *Problem {
+	var rawEvents []json.RawMessage
+	if json.Unmarshal(body, &rawEvents) != nil || len(rawEvents) > batch.MaxBatchSize {
+		return nil
+	}
+	var problem *Problem
+	for index, rawEvent := range rawEvents {
+		cloudEvent := event.New()
+		if json.Unmarshal(rawEvent, &cloudEvent) != nil {
+			continue
+		}
+		eventProblem := v.Validate(ctx, topicName, &cloudEvent)
+		if eventProblem == nil {
+			continue
+		}
+		if eventProblem.Status != nethttp.StatusBadRequest {
+			return eventProblem
+		}
+		if problem == nil {
+			problem = invalidEventProblem("the batch contains invalid events (none of which were produced)", nil)
+		}
+		for _, violation := range eventProblem.Violations {
+			problem.Violations = append(problem.Violations, fmt.Sprintf("[%d] %s", index, violation))
+		}
+	}
+	return problem
+}
+
+// Utility Function For Getting The Sorted Violations Of The CloudEvents Spec's Requirements
+func specViolations(cloudEvent *event.Event) []string {
+	var validationErr event.ValidationError
+	err := cloudEvent.Validate()
+	if err == nil || !errors.As(err, &validationErr) {
+		if err != nil {
+			return []string{err.Error()}
+		}
+		return nil
+	}
+	violations := make([]string, 0, len(validationErr))
+	for attribute, attributeErr := range validationErr {
+		violations = append(violations, fmt.Sprintf("%s: %v", attribute, attributeErr))
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+// Utility Function For Getting The Violations Of The CloudEvents Spec's Recommendations (Enforced When Strict)
+func strictViolations(cloudEvent *event.Event) []string {
+	var violations []string
+	if cloudEvent.SpecVersion() != event.CloudEventsVersionV1 {
+		violations = append(violations, fmt.Sprintf("specversion %s must be %s", cloudEvent.SpecVersion(), event.CloudEventsVersionV1))
+	}
+	if dataSchema := cloudEvent.DataSchema(); len(dataSchema) > 0 {
+		if dataSchemaURL, err := url.Parse(dataSchema); err != nil || !dataSchemaURL.IsAbs() {
+			violations = append(violations, fmt.Sprintf("dataschema %s must be an absolute URI", dataSchema))
+		}
+	}
+	extensionNames := make([]string, 0, len(cloudEvent.Extensions()))
+	for extension := range cloudEvent.Extensions() {
+		extensionNames = append(extensionNames, extension)
+	}
+	sort.Strings(extensionNames)
+	for _, extension := range extensionNames {
+		if !extensionNameRegexp.MatchString(extension) || len(extension) > MaxExtensionNameLength {
+			violations = append(violations, fmt.Sprintf("extension %s must consist of at most %d lowercase letters & digits", extension, MaxExtensionNameLength))
+		}
+	}
+	if isJson(cloudEvent.DataContentType()) && len(cloudEvent.Data()) > 0 && !json.Valid(cloudEvent.Data()) {
+		violations = append(violations, fmt.Sprintf("data must be valid JSON for datacontenttype %s", cloudEvent.DataContentType()))
+	}
+	return violations
+}
+
+// Utility Function For Determining Whether A datacontenttype Is JSON
+func isJson(dataContentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(dataContentType)
+	return err == nil && (mediaType == event.ApplicationJSON || mediaType == event.TextJSON || strings.HasSuffix(mediaType, "+json"))
+}
+
+// Utility Function For Creating The Problem Of An Invalid Event
+func invalidEventProblem(detail string, violations []string) *Problem {
+	return &Problem{
+		Type:       InvalidEventType,
+		Title:      "Invalid CloudEvent",
+		Status:     nethttp.StatusBadRequest,
+		Detail:     detail,
+		Violations: violations,
+	}
+}
+
+// Write The Specified Problem As A Problem Details (RFC 7807) Response
+func WriteProblem(response nethttp.ResponseWriter, problem *Problem) {
+	response.Header().Set("Content-Type", ProblemContentType)
+	response.WriteHeader(problem.Status)
+	_ = json.NewEncoder(response).Encode(problem)
+}
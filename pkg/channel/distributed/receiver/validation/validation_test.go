@@ -0,0 +1,286 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Data
+const (
+	testValidEvent     = `{"specversion":"1.0","id":"id-1","source":"test-source","type":"test-type","partitionkey":"key","data":{"n":1}}`
+	testMissingKey     = `{"specversion":"1.0","id":"id-2","source":"test-source","type":"test-type","data":{"n":2}}`
+	testMissingSource  = `{"specversion":"1.0","id":"id-3","type":"test-type","partitionkey":"key"}`
+	testStructuredType = "application/cloudevents+json"
+	testBatchType      = "application/cloudevents-batch+json"
+)
+
+// Test The ValidateValidationConfig() Functionality
+func TestValidateValidationConfig(t *testing.T) {
+	assert.Nil(t, ValidateValidationConfig(config.EKValidationConfig{}))
+	assert.Nil(t, ValidateValidationConfig(config.EKValidationConfig{Enabled: true, Strict: true, RequiredExtensions: []string{"partitionkey"},
+		SchemaRegistryURL: "http://registry/schemas/", SchemaCacheSeconds: 60}))
+	assert.NotNil(t, ValidateValidationConfig(config.EKValidationConfig{RequiredExtensions: []string{"Partition-Key"}}))
+	assert.NotNil(t, ValidateValidationConfig(config.EKValidationConfig{SchemaRegistryURL: "/schemas/"}))
+	assert.NotNil(t, ValidateValidationConfig(config.EKValidationConfig{SchemaCacheSeconds: -1}))
+	assert.Nil(t, ValidateValidationConfig(config.EKValidationConfig{SchemaRegistryURL: "http://registry", SchemaRegistryType: SchemaRegistryTypeConfluent,
+		SubjectNameStrategy: SubjectNameStrategyTopicRecord, SchemaRequired: true}))
+	assert.NotNil(t, ValidateValidationConfig(config.EKValidationConfig{SchemaRegistryURL: "http://registry", SchemaRegistryType: "glue"}))
+	assert.NotNil(t, ValidateValidationConfig(config.EKValidationConfig{SchemaRegistryURL: "http://registry", SubjectNameStrategy: "type"}))
+	assert.NotNil(t, ValidateValidationConfig(config.EKValidationConfig{SchemaRegistryType: SchemaRegistryTypeApicurio}))
+}
+
+// Test The NewValidator() Functionality
+func TestNewValidator(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Nil(t, NewValidator(logger, config.EKValidationConfig{}, RegistryCredentials{}, nil))
+	validator := NewValidator(logger, config.EKValidationConfig{Enabled: true}, RegistryCredentials{}, nil)
+	assert.NotNil(t, validator)
+	assert.Nil(t, validator.schemaRegistry)
+	validator = NewValidator(logger, config.EKValidationConfig{Enabled: true, SchemaRegistryURL: "http://registry/schemas/", SchemaCacheSeconds: 60}, RegistryCredentials{}, nil)
+	assert.Equal(t, "http://registry/schemas/", validator.schemaRegistry.baseURL)
+	assert.Equal(t, int64(60), int64(validator.schemaRegistry.cacheDuration.Seconds()))
+	assert.Equal(t, SubjectNameStrategyTopic, validator.subjectNameStrategy)
+}
+
+// Test The Validator's Validation Against Confluent Subject Schemas
+func TestValidateSubjectSchema(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	ctx := context.TODO()
+
+	// Create A Test Confluent Registry With Schemas Registered Under Each Strategy's Subject
+	registry := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		switch request.URL.Path {
+		case "/subjects/test-topic-value/versions/latest", "/subjects/test-type/versions/latest", "/subjects/test-topic-test-type/versions/latest":
+			_, _ = writer.Write([]byte(`{"schemaType": "JSON", "schema": "{\"type\": \"object\", \"required\": [\"n\"]}"}`))
+		case "/subjects/unavailable-value/versions/latest":
+			writer.WriteHeader(nethttp.StatusInternalServerError)
+		default:
+			writer.WriteHeader(nethttp.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	// Each Strategy Validates Against The Schema Of Its Subject
+	invalidEvent := createTestEvent(t, testValidEvent)
+	_ = invalidEvent.SetData(event.ApplicationJSON, map[string]int{"m": 1})
+	for _, strategy := range []string{SubjectNameStrategyTopic, SubjectNameStrategyRecord, SubjectNameStrategyTopicRecord} {
+		validator := NewValidator(logger, config.EKValidationConfig{Enabled: true, SchemaRegistryURL: registry.URL,
+			SchemaRegistryType: SchemaRegistryTypeConfluent, SubjectNameStrategy: strategy}, RegistryCredentials{}, nil)
+		assert.Nil(t, validator.Validate(ctx, "test-topic", createTestEvent(t, testValidEvent)), strategy)
+		assert.Equal(t, []string{"data.n is required"}, validator.Validate(ctx, "test-topic", invalidEvent).Violations, strategy)
+	}
+
+	// Events Of Unregistered Subjects (Or Unknown Topics) Are Only Rejected When A Schema Is Required
+	validator := NewValidator(logger, config.EKValidationConfig{Enabled: true, SchemaRegistryURL: registry.URL, SchemaRegistryType: SchemaRegistryTypeConfluent}, RegistryCredentials{}, nil)
+	assert.Nil(t, validator.Validate(ctx, "other-topic", invalidEvent))
+	assert.Nil(t, validator.Validate(ctx, "", invalidEvent))
+	validator = NewValidator(logger, config.EKValidationConfig{Enabled: true, SchemaRegistryURL: registry.URL, SchemaRegistryType: SchemaRegistryTypeConfluent, SchemaRequired: true}, RegistryCredentials{}, nil)
+	problem := validator.Validate(ctx, "other-topic", invalidEvent)
+	assert.Equal(t, nethttp.StatusBadRequest, problem.Status)
+	assert.Len(t, problem.Violations, 1)
+	assert.Contains(t, problem.Violations[0], "subject other-topic-value is not registered")
+
+	// Unavailable Registries Are Reported As Such
+	problem = validator.Validate(ctx, "unavailable", invalidEvent)
+	assert.Equal(t, nethttp.StatusServiceUnavailable, problem.Status)
+	assert.Equal(t, SchemaUnavailableType, problem.Type)
+}
+
+// Test The Validator's Validate() Functionality
+func TestValidate(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	ctx := context.TODO()
+
+	// Create A Test Schema Registry
+	registry := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		switch request.URL.Path {
+		case "/schemas/test":
+			_, _ = writer.Write([]byte(`{"type": "object", "required": ["n"]}`))
+		case "/schemas/unavailable":
+			writer.WriteHeader(nethttp.StatusInternalServerError)
+		default:
+			writer.WriteHeader(nethttp.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	// A Nil Validator Accepts All Events
+	var nilValidator *Validator
+	assert.Nil(t, nilValidator.Validate(ctx, "", &event.Event{}))
+
+	// The Spec's Requirements Are Always Validated
+	validator := NewValidator(logger, config.EKValidationConfig{Enabled: true}, RegistryCredentials{}, nil)
+	assert.Nil(t, validator.Validate(ctx, "", createTestEvent(t, testValidEvent)))
+	problem := validator.Validate(ctx, "", createTestEvent(t, testMissingSource))
+	assert.Equal(t, nethttp.StatusBadRequest, problem.Status)
+	assert.Equal(t, InvalidEventType, problem.Type)
+	assert.Equal(t, []string{"source: REQUIRED"}, problem.Violations)
+
+	// Strict Validation Enforces The Spec's Recommendations
+	strictEvent := createTestEvent(t, testValidEvent)
+	strictEvent.SetExtension("averyveryverylongextension", "value")
+	strictEvent.SetDataSchema("relative/schema")
+	assert.Nil(t, validator.Validate(ctx, "", strictEvent))
+	validator = NewValidator(logger, config.EKValidationConfig{Enabled: true, Strict: true}, RegistryCredentials{}, nil)
+	assert.Equal(t, []string{
+		"dataschema relative/schema must be an absolute URI",
+		"extension averyveryverylongextension must consist of at most 20 lowercase letters & digits",
+	}, validator.Validate(ctx, "", strictEvent).Violations)
+	invalidJson := createTestEvent(t, testValidEvent)
+	invalidJson.DataEncoded = []byte("{")
+	invalidJson.SetDataContentType("application/vnd.test+json")
+	assert.Equal(t, []string{"data must be valid JSON for datacontenttype application/vnd.test+json"}, validator.Validate(ctx, "", invalidJson).Violations)
+	legacyEvent := createTestEvent(t, testValidEvent)
+	legacyEvent.SetSpecVersion(event.CloudEventsVersionV03)
+	assert.Equal(t, []string{"specversion 0.3 must be 1.0"}, validator.Validate(ctx, "", legacyEvent).Violations)
+
+	// Required Extensions Must Be Present
+	validator = NewValidator(logger, config.EKValidationConfig{Enabled: true, RequiredExtensions: []string{"partitionkey"}}, RegistryCredentials{}, nil)
+	assert.Nil(t, validator.Validate(ctx, "", createTestEvent(t, testValidEvent)))
+	assert.Equal(t, []string{"extension partitionkey is required"}, validator.Validate(ctx, "", createTestEvent(t, testMissingKey)).Violations)
+
+	// The Data Of Events Referencing The Schema Registry Is Validated
+	validator = NewValidator(logger, config.EKValidationConfig{Enabled: true, SchemaRegistryURL: registry.URL + "/schemas/"}, RegistryCredentials{}, nil)
+	schemaEvent := createTestEvent(t, testValidEvent)
+	schemaEvent.SetDataSchema("http://elsewhere/schemas/test")
+	assert.Nil(t, validator.Validate(ctx, "", schemaEvent))
+	schemaEvent.SetDataSchema(registry.URL + "/schemas/test")
+	assert.Nil(t, validator.Validate(ctx, "", schemaEvent))
+	_ = schemaEvent.SetData(event.ApplicationJSON, map[string]int{"m": 1})
+	assert.Equal(t, []string{"data.n is required"}, validator.Validate(ctx, "", schemaEvent).Violations)
+	schemaEvent.SetDataSchema(registry.URL + "/schemas/unknown")
+	assert.Equal(t, nethttp.StatusBadRequest, validator.Validate(ctx, "", schemaEvent).Status)
+	schemaEvent.SetDataSchema(registry.URL + "/schemas/unavailable")
+	problem = validator.Validate(ctx, "", schemaEvent)
+	assert.Equal(t, nethttp.StatusServiceUnavailable, problem.Status)
+	assert.Equal(t, SchemaUnavailableType, problem.Type)
+}
+
+// Test The Validator's Handler() Functionality
+func TestHandler(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name               string
+		method             string
+		header             map[string]string
+		body               string
+		expectedStatus     int
+		expectedViolations []string
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Valid Structured Event", method: nethttp.MethodPost, header: map[string]string{"Content-Type": testStructuredType}, body: testValidEvent, expectedStatus: nethttp.StatusAccepted},
+		{name: "Invalid Structured Event", method: nethttp.MethodPost, header: map[string]string{"Content-Type": testStructuredType}, body: testMissingKey,
+			expectedStatus: nethttp.StatusBadRequest, expectedViolations: []string{"extension partitionkey is required"}},
+		{name: "Valid Binary Event", method: nethttp.MethodPost, body: `{"n":1}`, expectedStatus: nethttp.StatusAccepted,
+			header: map[string]string{"Content-Type": "application/json", "Ce-Specversion": "1.0", "Ce-Id": "id-1", "Ce-Source": "test-source", "Ce-Type": "test-type", "Ce-Partitionkey": "key"}},
+		{name: "Invalid Binary Event", method: nethttp.MethodPost, body: `{"n":1}`, expectedStatus: nethttp.StatusBadRequest, expectedViolations: []string{"extension partitionkey is required"},
+			header: map[string]string{"Content-Type": "application/json", "Ce-Specversion": "1.0", "Ce-Id": "id-1", "Ce-Source": "test-source", "Ce-Type": "test-type"}},
+		{name: "Not A CloudEvent", method: nethttp.MethodPost, header: map[string]string{"Content-Type": "text/plain"}, body: "hello", expectedStatus: nethttp.StatusBadRequest},
+		{name: "Valid Batch", method: nethttp.MethodPost, header: map[string]string{"Content-Type": testBatchType}, body: "[" + testValidEvent + "," + testValidEvent + "]", expectedStatus: nethttp.StatusAccepted},
+		{name: "Invalid Batch", method: nethttp.MethodPost, header: map[string]string{"Content-Type": testBatchType}, body: "[" + testValidEvent + "," + testMissingKey + "," + testMissingSource + "]",
+			expectedStatus: nethttp.StatusBadRequest, expectedViolations: []string{"[1] extension partitionkey is required", "[2] source: REQUIRED"}},
+		{name: "Malformed Batch Left To The Batch Handler", method: nethttp.MethodPost, header: map[string]string{"Content-Type": testBatchType}, body: "[", expectedStatus: nethttp.StatusAccepted},
+		{name: "Non-Event Request", method: nethttp.MethodGet, expectedStatus: nethttp.StatusAccepted},
+	}
+
+	// Create A Validator Requiring The partitionkey Extension
+	validator := NewValidator(logtesting.TestLogger(t).Desugar(), config.EKValidationConfig{Enabled: true, RequiredExtensions: []string{"partitionkey"}}, RegistryCredentials{}, nil)
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create A Next Handler Verifying It Receives The Complete Body
+			handler := validator.Handler(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+				body, err := ioutil.ReadAll(request.Body)
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.body, string(body))
+				writer.WriteHeader(nethttp.StatusAccepted)
+			}))
+
+			// Perform The Test
+			request := httptest.NewRequest(testCase.method, "/", strings.NewReader(testCase.body))
+			for key, value := range testCase.header {
+				request.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			// Verify The Results
+			assert.Equal(t, testCase.expectedStatus, recorder.Code)
+			if testCase.expectedStatus == nethttp.StatusBadRequest {
+				assert.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+				problem := &Problem{}
+				assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), problem))
+				assert.Equal(t, InvalidEventType, problem.Type)
+				assert.Equal(t, nethttp.StatusBadRequest, problem.Status)
+				if testCase.expectedViolations != nil {
+					assert.Equal(t, testCase.expectedViolations, problem.Violations)
+				}
+			}
+		})
+	}
+
+	// The Topic Of The Request's Host Is Resolved For Naming Subjects
+	registry := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		if request.URL.Path == "/subjects/test-topic-value/versions/latest" {
+			_, _ = writer.Write([]byte(`{"schemaType": "JSON", "schema": "{\"required\": [\"m\"]}"}`))
+			return
+		}
+		writer.WriteHeader(nethttp.StatusNotFound)
+	}))
+	defer registry.Close()
+	topicResolver := func(host string) (string, error) {
+		assert.Equal(t, "test-channel.test-namespace", host)
+		return "test-topic", nil
+	}
+	validator = NewValidator(logtesting.TestLogger(t).Desugar(), config.EKValidationConfig{Enabled: true, SchemaRegistryURL: registry.URL,
+		SchemaRegistryType: SchemaRegistryTypeConfluent}, RegistryCredentials{}, topicResolver)
+	request := httptest.NewRequest(nethttp.MethodPost, "http://test-channel.test-namespace/", strings.NewReader(testValidEvent))
+	request.Header.Set("Content-Type", testStructuredType)
+	recorder := httptest.NewRecorder()
+	validator.Handler(nethttp.NotFoundHandler()).ServeHTTP(recorder, request)
+	assert.Equal(t, nethttp.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "data.m is required")
+
+	// A Nil Validator Returns The Next Handler As-Is
+	var nilValidator *Validator
+	next := nethttp.NewServeMux()
+	assert.Same(t, next, nilValidator.Handler(next))
+}
+
+// Utility Function For Creating A Test Event From Its Structured JSON
+func createTestEvent(t *testing.T, structuredJson string) *event.Event {
+	cloudEvent := event.New()
+	assert.Nil(t, json.Unmarshal([]byte(structuredJson), &cloudEvent))
+	return &cloudEvent
+}
@@ -28,6 +28,14 @@ type FakeMessagingV1beta1 struct {
 	*testing.Fake
 }
 
+func (c *FakeMessagingV1beta1) ChannelAccessPolicies(namespace string) v1beta1.ChannelAccessPolicyInterface {
+	return &FakeChannelAccessPolicies{c, namespace}
+}
+
+func (c *FakeMessagingV1beta1) EventRedeliveries(namespace string) v1beta1.EventRedeliveryInterface {
+	return &FakeEventRedeliveries{c, namespace}
+}
+
 func (c *FakeMessagingV1beta1) KafkaChannels(namespace string) v1beta1.KafkaChannelInterface {
 	return &FakeKafkaChannels{c, namespace}
 }
@@ -18,4 +18,8 @@ limitations under the License.
 
 package v1beta1
 
+type ChannelAccessPolicyExpansion interface{}
+
+type EventRedeliveryExpansion interface{}
+
 type KafkaChannelExpansion interface{}
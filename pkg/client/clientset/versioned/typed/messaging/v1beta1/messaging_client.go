@@ -26,6 +26,8 @@ import (
 
 type MessagingV1beta1Interface interface {
 	RESTClient() rest.Interface
+	ChannelAccessPoliciesGetter
+	EventRedeliveriesGetter
 	KafkaChannelsGetter
 }
 
@@ -34,6 +36,14 @@ type MessagingV1beta1Client struct {
 	restClient rest.Interface
 }
 
+func (c *MessagingV1beta1Client) ChannelAccessPolicies(namespace string) ChannelAccessPolicyInterface {
+	return newChannelAccessPolicies(c, namespace)
+}
+
+func (c *MessagingV1beta1Client) EventRedeliveries(namespace string) EventRedeliveryInterface {
+	return newEventRedeliveries(c, namespace)
+}
+
 func (c *MessagingV1beta1Client) KafkaChannels(namespace string) KafkaChannelInterface {
 	return newKafkaChannels(c, namespace)
 }
@@ -70,6 +70,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Messaging().V1alpha1().KafkaChannels().Informer()}, nil
 
 		// Group=messaging.knative.dev, Version=v1beta1
+	case messagingv1beta1.SchemeGroupVersion.WithResource("channelaccesspolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Messaging().V1beta1().ChannelAccessPolicies().Informer()}, nil
+	case messagingv1beta1.SchemeGroupVersion.WithResource("eventredeliveries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Messaging().V1beta1().EventRedeliveries().Informer()}, nil
 	case messagingv1beta1.SchemeGroupVersion.WithResource("kafkachannels"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Messaging().V1beta1().KafkaChannels().Informer()}, nil
 
@@ -24,6 +24,10 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ChannelAccessPolicies returns a ChannelAccessPolicyInformer.
+	ChannelAccessPolicies() ChannelAccessPolicyInformer
+	// EventRedeliveries returns a EventRedeliveryInformer.
+	EventRedeliveries() EventRedeliveryInformer
 	// KafkaChannels returns a KafkaChannelInformer.
 	KafkaChannels() KafkaChannelInformer
 }
@@ -39,6 +43,16 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ChannelAccessPolicies returns a ChannelAccessPolicyInformer.
+func (v *version) ChannelAccessPolicies() ChannelAccessPolicyInformer {
+	return &channelAccessPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// EventRedeliveries returns a EventRedeliveryInformer.
+func (v *version) EventRedeliveries() EventRedeliveryInformer {
+	return &eventRedeliveryInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // KafkaChannels returns a KafkaChannelInformer.
 func (v *version) KafkaChannels() KafkaChannelInformer {
 	return &kafkaChannelInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -18,6 +18,22 @@ limitations under the License.
 
 package v1beta1
 
+// ChannelAccessPolicyListerExpansion allows custom methods to be added to
+// ChannelAccessPolicyLister.
+type ChannelAccessPolicyListerExpansion interface{}
+
+// ChannelAccessPolicyNamespaceListerExpansion allows custom methods to be added to
+// ChannelAccessPolicyNamespaceLister.
+type ChannelAccessPolicyNamespaceListerExpansion interface{}
+
+// EventRedeliveryListerExpansion allows custom methods to be added to
+// EventRedeliveryLister.
+type EventRedeliveryListerExpansion interface{}
+
+// EventRedeliveryNamespaceListerExpansion allows custom methods to be added to
+// EventRedeliveryNamespaceLister.
+type EventRedeliveryNamespaceListerExpansion interface{}
+
 // KafkaChannelListerExpansion allows custom methods to be added to
 // KafkaChannelLister.
 type KafkaChannelListerExpansion interface{}
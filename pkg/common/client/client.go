@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client builds Kafka client configurations from the KafkaAuthSpec shared by
+// the KafkaSource, the KafkaBinding and both KafkaChannel implementations.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+)
+
+// NewConfigFromSpec builds the Kafka configuration from the given KafkaAuthSpec, reading
+// the referenced secrets from the given namespace.
+func NewConfigFromSpec(ctx context.Context, kubeClient kubernetes.Interface, namespace string, spec bindingsv1beta1.KafkaAuthSpec) ([]string, *sarama.Config, error) {
+	cfg := sarama.NewConfig()
+	cfg.Version = sarama.V2_0_0_0
+	cfg.Consumer.Return.Errors = true
+
+	if err := UpdateConfigFromSpec(ctx, kubeClient, namespace, spec, cfg); err != nil {
+		return nil, nil, err
+	}
+	return spec.BootstrapServers, cfg, nil
+}
+
+// UpdateConfigFromSpec applies the SASL and TLS settings of the given KafkaAuthSpec to an
+// existing Kafka configuration, reading the referenced secrets from the given namespace.
+// Settings which are not enabled in the spec are left as they are.
+func UpdateConfigFromSpec(ctx context.Context, kubeClient kubernetes.Interface, namespace string, spec bindingsv1beta1.KafkaAuthSpec, cfg *sarama.Config) error {
+	if spec.Net.SASL.Enable {
+		user, err := secretValue(ctx, kubeClient, namespace, spec.Net.SASL.User.SecretKeyRef)
+		if err != nil {
+			return err
+		}
+		password, err := secretValue(ctx, kubeClient, namespace, spec.Net.SASL.Password.SecretKeyRef)
+		if err != nil {
+			return err
+		}
+		cfg.Net.SASL.Enable = true
+		cfg.Net.SASL.User = user
+		cfg.Net.SASL.Password = password
+	}
+
+	if spec.Net.TLS.Enable {
+		cert, err := secretValue(ctx, kubeClient, namespace, spec.Net.TLS.Cert.SecretKeyRef)
+		if err != nil {
+			return err
+		}
+		key, err := secretValue(ctx, kubeClient, namespace, spec.Net.TLS.Key.SecretKeyRef)
+		if err != nil {
+			return err
+		}
+		caCert, err := secretValue(ctx, kubeClient, namespace, spec.Net.TLS.CACert.SecretKeyRef)
+		if err != nil {
+			return err
+		}
+		tlsConfig, err := NewTLSConfig(cert, key, caCert)
+		if err != nil {
+			return err
+		}
+		cfg.Net.TLS.Enable = true
+		cfg.Net.TLS.Config = tlsConfig
+	}
+
+	return nil
+}
+
+// ParseAuthSpec parses a KafkaAuthSpec from its YAML (or JSON) representation, as found in
+// the configmaps of the KafkaChannel implementations. An empty string yields a nil spec.
+func ParseAuthSpec(authSpec string) (*bindingsv1beta1.KafkaAuthSpec, error) {
+	if authSpec == "" {
+		return nil, nil
+	}
+
+	spec := &bindingsv1beta1.KafkaAuthSpec{}
+	if err := yaml.Unmarshal([]byte(authSpec), spec); err != nil {
+		return nil, fmt.Errorf("failed to parse KafkaAuthSpec: %w", err)
+	}
+	if len(spec.BootstrapServers) == 0 {
+		return nil, fmt.Errorf("KafkaAuthSpec is missing bootstrapServers")
+	}
+	return spec, nil
+}
+
+// secretValue returns the value of the secret key described by ref.
+// If ref is nil, an empty value is returned.
+func secretValue(ctx context.Context, kubeClient kubernetes.Interface, namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	if ref == nil {
+		return "", nil
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("missing key %q in secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return string(value), nil
+}
+
+// NewTLSConfig returns a *tls.Config using the given client cert, client key,
+// and CA certificate. If none are appropriate, a nil *tls.Config is returned.
+func NewTLSConfig(clientCert, clientKey, caCert string) (*tls.Config, error) {
+	valid := false
+
+	config := &tls.Config{}
+
+	if clientCert != "" && clientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+		valid = true
+	}
+
+	if caCert != "" {
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM([]byte(caCert))
+		config.RootCAs = caCertPool
+		// The CN of Heroku Kafka certs do not match the hostname of the
+		// broker, but Go's default TLS behavior requires that they do.
+		config.VerifyPeerCertificate = verifyCertSkipHostname(caCertPool)
+		config.InsecureSkipVerify = true
+		valid = true
+	}
+
+	if !valid {
+		config = nil
+	}
+
+	return config, nil
+}
+
+// verifyCertSkipHostname verifies certificates in the same way that the
+// default TLS handshake does, except it skips hostname verification. It must
+// be used with InsecureSkipVerify.
+func verifyCertSkipHostname(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(certs [][]byte, _ [][]*x509.Certificate) error {
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			CurrentTime:   time.Now(),
+			Intermediates: x509.NewCertPool(),
+		}
+
+		leaf, err := x509.ParseCertificate(certs[0])
+		if err != nil {
+			return err
+		}
+
+		for _, asn1Data := range certs[1:] {
+			cert, err := x509.ParseCertificate(asn1Data)
+			if err != nil {
+				return err
+			}
+
+			opts.Intermediates.AddCert(cert)
+		}
+
+		_, err = leaf.Verify(opts)
+		return err
+	}
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	cert, key := generateCert(t)
+
+	for _, tt := range []struct {
+		name       string
+		cert       string
+		key        string
+		caCert     string
+		wantErr    bool
+		wantNil    bool
+		wantClient bool
+		wantServer bool
+	}{{
+		name:    "all empty",
+		wantNil: true,
+	}, {
+		name:    "bad input",
+		cert:    "x",
+		key:     "y",
+		caCert:  "z",
+		wantErr: true,
+	}, {
+		name:    "only cert",
+		cert:    cert,
+		wantNil: true,
+	}, {
+		name:    "only key",
+		key:     key,
+		wantNil: true,
+	}, {
+		name:       "cert and key",
+		cert:       cert,
+		key:        key,
+		wantClient: true,
+	}, {
+		name:       "only caCert",
+		caCert:     cert,
+		wantServer: true,
+	}, {
+		name:       "cert, key, and caCert",
+		cert:       cert,
+		key:        key,
+		caCert:     cert,
+		wantClient: true,
+		wantServer: true,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewTLSConfig(tt.cert, tt.key, tt.caCert)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("wanted error")
+				}
+				return
+			}
+
+			if tt.wantNil {
+				if c != nil {
+					t.Fatal("wanted non-nil config")
+				}
+				return
+			}
+
+			var wantCertificates int
+			if tt.wantClient {
+				wantCertificates = 1
+			} else {
+				wantCertificates = 0
+			}
+			if got, want := len(c.Certificates), wantCertificates; got != want {
+				t.Errorf("got %d Certificates, wanted %d", got, want)
+			}
+
+			if tt.wantServer {
+				if c.RootCAs == nil {
+					t.Error("wanted non-nil RootCAs")
+				}
+
+				if c.VerifyPeerCertificate == nil {
+					t.Error("wanted non-nil VerifyPeerCertificate")
+				}
+
+				if !c.InsecureSkipVerify {
+					t.Error("wanted InsecureSkipVerify")
+				}
+			} else {
+				if c.RootCAs != nil {
+					t.Error("wanted nil RootCAs")
+				}
+
+				if c.VerifyPeerCertificate != nil {
+					t.Error("wanted nil VerifyPeerCertificate")
+				}
+
+				if c.InsecureSkipVerify {
+					t.Error("wanted false InsecureSkipVerify")
+				}
+			}
+		})
+	}
+
+}
+
+func TestVerifyCertSkipHostname(t *testing.T) {
+	cert, _ := generateCert(t)
+	certPem, _ := pem.Decode([]byte(cert))
+
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM([]byte(cert))
+
+	v := verifyCertSkipHostname(caCertPool)
+
+	err := v([][]byte{certPem.Bytes}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert2, _ := generateCert(t)
+	cert2Pem, _ := pem.Decode([]byte(cert2))
+
+	err = v([][]byte{cert2Pem.Bytes}, nil)
+	// Error expected as we're still verifying with the first cert.
+	if err == nil {
+		t.Fatal("wanted error")
+	}
+}
+
+// Lifted from the RSA path of https://golang.org/src/crypto/tls/generate_cert.go.
+func generateCert(t *testing.T) (string, string) {
+	t.Helper()
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notBefore := time.Now().Add(-5 * time.Minute)
+	notAfter := notBefore.Add(time.Hour)
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"Acme Co"},
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var certOut bytes.Buffer
+	if err := pem.Encode(&certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes}); err != nil {
+		t.Fatal(err)
+	}
+
+	var keyOut bytes.Buffer
+	if err := pem.Encode(&keyOut, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)}); err != nil {
+		t.Fatal(err)
+	}
+
+	return certOut.String(), keyOut.String()
+}
+
+func TestNewConfigFromSpec(t *testing.T) {
+	ctx := context.Background()
+
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sasl"},
+		Data: map[string][]byte{
+			"user":     []byte("my-user"),
+			"password": []byte("my-password"),
+		},
+	})
+
+	secretRef := func(name, key string) bindingsv1beta1.SecretValueFromSource {
+		return bindingsv1beta1.SecretValueFromSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  key,
+			},
+		}
+	}
+
+	spec := bindingsv1beta1.KafkaAuthSpec{
+		BootstrapServers: []string{"server1:9092", "server2:9092"},
+		Net: bindingsv1beta1.KafkaNetSpec{
+			SASL: bindingsv1beta1.KafkaSASLSpec{
+				Enable:   true,
+				User:     secretRef("sasl", "user"),
+				Password: secretRef("sasl", "password"),
+			},
+		},
+	}
+
+	servers, config, err := NewConfigFromSpec(ctx, kubeClient, "ns", spec)
+	require.NoError(t, err)
+	require.Equal(t, []string{"server1:9092", "server2:9092"}, servers)
+	require.True(t, config.Net.SASL.Enable)
+	require.Equal(t, "my-user", config.Net.SASL.User)
+	require.Equal(t, "my-password", config.Net.SASL.Password)
+	require.False(t, config.Net.TLS.Enable)
+
+	// missing secret key
+	spec.Net.SASL.Password = secretRef("sasl", "missing")
+	_, _, err = NewConfigFromSpec(ctx, kubeClient, "ns", spec)
+	require.Error(t, err)
+
+	// missing secret
+	spec.Net.SASL.Password = secretRef("missing", "password")
+	_, _, err = NewConfigFromSpec(ctx, kubeClient, "ns", spec)
+	require.Error(t, err)
+}
+
+func TestUpdateConfigFromSpec(t *testing.T) {
+	ctx := context.Background()
+	cert, key := generateCert(t)
+
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tls"},
+		Data: map[string][]byte{
+			"user.crt": []byte(cert),
+			"user.key": []byte(key),
+			"ca.crt":   []byte(cert),
+		},
+	})
+
+	secretRef := func(key string) bindingsv1beta1.SecretValueFromSource {
+		return bindingsv1beta1.SecretValueFromSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "tls"},
+				Key:                  key,
+			},
+		}
+	}
+
+	// Settings which are not enabled in the spec are left as they are
+	config := sarama.NewConfig()
+	config.ClientID = "my-client"
+	config.Net.SASL.User = "configured-user"
+	require.NoError(t, UpdateConfigFromSpec(ctx, kubeClient, "ns", bindingsv1beta1.KafkaAuthSpec{}, config))
+	require.Equal(t, "configured-user", config.Net.SASL.User)
+	require.False(t, config.Net.TLS.Enable)
+
+	spec := bindingsv1beta1.KafkaAuthSpec{
+		BootstrapServers: []string{"server1:9093"},
+		Net: bindingsv1beta1.KafkaNetSpec{
+			TLS: bindingsv1beta1.KafkaTLSSpec{
+				Enable: true,
+				Cert:   secretRef("user.crt"),
+				Key:    secretRef("user.key"),
+				CACert: secretRef("ca.crt"),
+			},
+		},
+	}
+	require.NoError(t, UpdateConfigFromSpec(ctx, kubeClient, "ns", spec, config))
+	require.Equal(t, "my-client", config.ClientID)
+	require.True(t, config.Net.TLS.Enable)
+	require.Len(t, config.Net.TLS.Config.Certificates, 1)
+	require.NotNil(t, config.Net.TLS.Config.RootCAs)
+
+	// invalid certificate
+	spec.Net.TLS.Key = secretRef("ca.crt")
+	require.Error(t, UpdateConfigFromSpec(ctx, kubeClient, "ns", spec, sarama.NewConfig()))
+}
+
+func TestParseAuthSpec(t *testing.T) {
+	spec, err := ParseAuthSpec("")
+	require.NoError(t, err)
+	require.Nil(t, spec)
+
+	spec, err = ParseAuthSpec(`
+bootstrapServers:
+- server1:9092
+net:
+  sasl:
+    enable: true
+    user:
+      secretKeyRef:
+        name: sasl
+        key: user
+`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"server1:9092"}, spec.BootstrapServers)
+	require.True(t, spec.Net.SASL.Enable)
+	require.Equal(t, "sasl", spec.Net.SASL.User.SecretKeyRef.Name)
+	require.Equal(t, "user", spec.Net.SASL.User.SecretKeyRef.Key)
+
+	_, err = ParseAuthSpec("net:\n  sasl:\n    enable: true\n")
+	require.Error(t, err)
+
+	_, err = ParseAuthSpec("bootstrapServers: [")
+	require.Error(t, err)
+}
+
+func TestNewClientID(t *testing.T) {
+	fields := ClientIDFields{Component: "dispatcher", Namespace: "ns", Channel: "my-channel", Pod: "dispatcher-abc"}
+
+	clientID, err := NewClientID("", fields)
+	require.NoError(t, err)
+	require.Equal(t, "dispatcher", clientID)
+
+	clientID, err = NewClientID("{{.Component}}.{{.Namespace}}.{{.Channel}}.{{.Pod}}", fields)
+	require.NoError(t, err)
+	require.Equal(t, "dispatcher.ns.my-channel.dispatcher-abc", clientID)
+
+	// invalid characters are replaced
+	clientID, err = NewClientID("{{.Component}} {{.Namespace}}/{{.Channel}}", fields)
+	require.NoError(t, err)
+	require.Equal(t, "dispatcher-ns-my-channel", clientID)
+
+	// empty results fall back to the component
+	clientID, err = NewClientID("{{.Channel}}", ClientIDFields{Component: "receiver"})
+	require.NoError(t, err)
+	require.Equal(t, "receiver", clientID)
+
+	_, err = NewClientID("{{.Component", fields)
+	require.Error(t, err)
+
+	_, err = NewClientID("{{.Unknown}}", fields)
+	require.Error(t, err)
+
+	// rendered client ids pass sarama's validation
+	config := sarama.NewConfig()
+	config.ClientID, err = NewClientID("{{.Component}}:{{.Pod}}", fields)
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// invalidClientIDChars matches the characters which Kafka (and sarama's config validation)
+// does not accept in a client.id.
+var invalidClientIDChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// ClientIDFields are the values available to client.id templates, e.g.
+// "{{.Component}}.{{.Namespace}}.{{.Channel}}.{{.Pod}}". Fields which do not apply to a
+// client (e.g. the channel of a receiver shared by all channels) are empty.
+type ClientIDFields struct {
+	Component string
+	Namespace string
+	Channel   string
+	Pod       string
+}
+
+// NewClientID renders the given client.id template (Go text/template syntax) with the given
+// fields. Characters which are not valid in a Kafka client.id are replaced by "-". An empty
+// template, or one which renders to nothing, yields the component name.
+func NewClientID(clientIDTemplate string, fields ClientIDFields) (string, error) {
+	if strings.TrimSpace(clientIDTemplate) == "" {
+		return fields.Component, nil
+	}
+
+	tmpl, err := template.New("clientId").Option("missingkey=error").Parse(clientIDTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid client.id template %q: %w", clientIDTemplate, err)
+	}
+
+	var clientID strings.Builder
+	if err := tmpl.Execute(&clientID, fields); err != nil {
+		return "", fmt.Errorf("invalid client.id template %q: %w", clientIDTemplate, err)
+	}
+
+	rendered := invalidClientIDChars.ReplaceAllString(strings.TrimSpace(clientID.String()), "-")
+	if rendered == "" {
+		return fields.Component, nil
+	}
+	return rendered, nil
+}
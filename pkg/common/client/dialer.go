@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/Shopify/sarama"
+)
+
+// Dialer is the interface of the dialers with which sarama connects to the brokers (that of
+// its Net.Proxy.Dialer config).
+type Dialer interface {
+	Dial(network string, address string) (net.Conn, error)
+}
+
+// BrokerAddressDialer dials Kafka brokers at the addresses to which their advertised addresses
+// are rewritten, e.g. in split-horizon DNS environments or development setups port-forwarding
+// to the brokers, where the addresses the brokers advertise in their metadata are unreachable
+// from the client. Addresses without a rewrite are dialed as-is. Only the dialed address is
+// rewritten, so TLS server names are still verified against the advertised addresses.
+type BrokerAddressDialer struct {
+	// Rewrites maps advertised broker addresses ("host:port") to reachable ones.
+	Rewrites map[string]string
+
+	// Dialer dials the (rewritten) addresses.
+	Dialer Dialer
+}
+
+// Dial connects to the given address, or to its rewrite if there is one.
+func (d *BrokerAddressDialer) Dial(network string, address string) (net.Conn, error) {
+	if rewrite, ok := d.Rewrites[address]; ok {
+		address = rewrite
+	}
+	return d.Dialer.Dial(network, address)
+}
+
+// ValidateBrokerAddressRewrites returns an error if any advertised or rewritten broker address
+// of the given rewrites is not of the form "host:port".
+func ValidateBrokerAddressRewrites(rewrites map[string]string) error {
+	for advertised, rewrite := range rewrites {
+		for _, address := range []string{advertised, rewrite} {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil || strings.TrimSpace(host) == "" || strings.TrimSpace(port) == "" {
+				return fmt.Errorf("invalid broker address rewrite %q -> %q: addresses must be of the form host:port", advertised, rewrite)
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateConfigBrokerAddressRewrites updates the sarama config to dial its brokers via a
+// BrokerAddressDialer with the given rewrites, wrapping any proxy dialer already configured
+// (otherwise dialing directly with the config's dial timeout, keep-alive and local address).
+// Empty rewrites leave the config as-is.
+func UpdateConfigBrokerAddressRewrites(cfg *sarama.Config, rewrites map[string]string) error {
+	if len(rewrites) == 0 {
+		return nil
+	}
+	if err := ValidateBrokerAddressRewrites(rewrites); err != nil {
+		return err
+	}
+
+	var dialer Dialer = &net.Dialer{
+		Timeout:   cfg.Net.DialTimeout,
+		KeepAlive: cfg.Net.KeepAlive,
+		LocalAddr: cfg.Net.LocalAddr,
+	}
+	if cfg.Net.Proxy.Enable && cfg.Net.Proxy.Dialer != nil {
+		dialer = cfg.Net.Proxy.Dialer
+		if existing, ok := dialer.(*BrokerAddressDialer); ok {
+			dialer = existing.Dialer // Replace rather than stack previously applied rewrites
+		}
+	}
+
+	copied := make(map[string]string, len(rewrites))
+	for advertised, rewrite := range rewrites {
+		copied[advertised] = rewrite
+	}
+	cfg.Net.Proxy.Enable = true
+	cfg.Net.Proxy.Dialer = &BrokerAddressDialer{Rewrites: copied, Dialer: dialer}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBrokerAddressRewrites(t *testing.T) {
+	tests := []struct {
+		name     string
+		rewrites map[string]string
+		wantErr  bool
+	}{
+		{name: "nil"},
+		{name: "valid", rewrites: map[string]string{"kafka-0.internal:9092": "localhost:19092", "10.0.0.1:9093": "kafka.example.com:443"}},
+		{name: "advertised without port", rewrites: map[string]string{"kafka-0.internal": "localhost:19092"}, wantErr: true},
+		{name: "rewrite without port", rewrites: map[string]string{"kafka-0.internal:9092": "localhost"}, wantErr: true},
+		{name: "empty host", rewrites: map[string]string{"kafka-0.internal:9092": ":19092"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBrokerAddressRewrites(tt.rewrites)
+			require.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestUpdateConfigBrokerAddressRewrites(t *testing.T) {
+	// Empty Rewrites Leave The Config As-Is
+	config := sarama.NewConfig()
+	require.NoError(t, UpdateConfigBrokerAddressRewrites(config, nil))
+	require.False(t, config.Net.Proxy.Enable)
+	require.Nil(t, config.Net.Proxy.Dialer)
+
+	// Invalid Rewrites Are Rejected
+	require.Error(t, UpdateConfigBrokerAddressRewrites(config, map[string]string{"kafka-0": "localhost"}))
+	require.False(t, config.Net.Proxy.Enable)
+
+	// Rewrites Dial Directly With The Config's Net Settings
+	config.Net.DialTimeout = 7
+	rewrites := map[string]string{"kafka-0.internal:9092": "localhost:19092"}
+	require.NoError(t, UpdateConfigBrokerAddressRewrites(config, rewrites))
+	require.True(t, config.Net.Proxy.Enable)
+	dialer, ok := config.Net.Proxy.Dialer.(*BrokerAddressDialer)
+	require.True(t, ok)
+	require.Equal(t, rewrites, dialer.Rewrites)
+	netDialer, ok := dialer.Dialer.(*net.Dialer)
+	require.True(t, ok)
+	require.Equal(t, config.Net.DialTimeout, netDialer.Timeout)
+
+	// Reapplying Replaces Rather Than Stacks The Rewrites
+	rewrites = map[string]string{"kafka-1.internal:9092": "localhost:19093"}
+	require.NoError(t, UpdateConfigBrokerAddressRewrites(config, rewrites))
+	dialer = config.Net.Proxy.Dialer.(*BrokerAddressDialer)
+	require.Equal(t, rewrites, dialer.Rewrites)
+	require.Equal(t, netDialer, dialer.Dialer)
+
+	// An Existing Proxy Dialer Is Wrapped
+	proxyConfig := sarama.NewConfig()
+	proxyConfig.Net.Proxy.Enable = true
+	proxyConfig.Net.Proxy.Dialer = netDialer
+	require.NoError(t, UpdateConfigBrokerAddressRewrites(proxyConfig, rewrites))
+	require.Equal(t, netDialer, proxyConfig.Net.Proxy.Dialer.(*BrokerAddressDialer).Dialer)
+}
+
+func TestBrokerAddressDialer(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()),
+	})
+
+	// The Advertised Address Is Unresolvable, So Connecting Requires The Rewrite
+	advertised := "kafka-0.advertised.invalid:9092"
+	config := sarama.NewConfig()
+	config.Metadata.Retry.Max = 0
+	require.NoError(t, UpdateConfigBrokerAddressRewrites(config, map[string]string{advertised: broker.Addr()}))
+	client, err := sarama.NewClient([]string{advertised}, config)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+}
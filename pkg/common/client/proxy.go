@@ -0,0 +1,301 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// The egress proxy schemes supported by the ProxyDialer.
+const (
+	ProxySchemeSOCKS5  = "socks5"
+	ProxySchemeSOCKS5H = "socks5h" // Identical to socks5 - broker host names are always resolved by the proxy
+	ProxySchemeHTTP    = "http"
+)
+
+// ProxyDialer connects to Kafka brokers through an egress proxy, either a SOCKS5 proxy
+// (optionally with username/password authentication) or an HTTP proxy supporting the CONNECT
+// method (optionally with basic authentication). This is needed in locked-down networks where
+// direct egress from the cluster to the brokers is prohibited. TLS to the brokers is negotiated
+// through the tunnel as usual.
+type ProxyDialer struct {
+	// Scheme is the ProxyScheme of the proxy.
+	Scheme string
+
+	// Address is the "host:port" of the proxy.
+	Address string
+
+	// Username and Password authenticate with the proxy if the Username is not empty.
+	Username string
+	Password string
+
+	// Timeout bounds the connection to the proxy and the handshake (no limit if zero).
+	Timeout time.Duration
+
+	// Dialer dials the proxy.
+	Dialer Dialer
+}
+
+// NewProxyDialer parses the proxy URL (e.g. "socks5://proxy.example.com:1080" or
+// "http://proxy.example.com:3128") into a ProxyDialer. Credentials in the URL are used unless
+// a username is given explicitly.
+func NewProxyDialer(proxyURL string, username string, password string, timeout time.Duration, dialer Dialer) (*ProxyDialer, error) {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch parsed.Scheme {
+	case ProxySchemeSOCKS5, ProxySchemeSOCKS5H, ProxySchemeHTTP:
+	default:
+		return nil, fmt.Errorf("invalid proxy url %q: the scheme must be %s, %s or %s", proxyURL, ProxySchemeSOCKS5, ProxySchemeSOCKS5H, ProxySchemeHTTP)
+	}
+	if host, port, err := net.SplitHostPort(parsed.Host); err != nil || host == "" || port == "" {
+		return nil, fmt.Errorf("invalid proxy url %q: the host must be of the form host:port", proxyURL)
+	}
+	if username == "" && parsed.User != nil {
+		username = parsed.User.Username()
+		password, _ = parsed.User.Password()
+	}
+	return &ProxyDialer{
+		Scheme:   parsed.Scheme,
+		Address:  parsed.Host,
+		Username: username,
+		Password: password,
+		Timeout:  timeout,
+		Dialer:   dialer,
+	}, nil
+}
+
+// Dial connects to the given broker address through the proxy.
+func (d *ProxyDialer) Dial(network string, address string) (net.Conn, error) {
+	conn, err := d.Dialer.Dial(network, d.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", d.Address, err)
+	}
+	if d.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(d.Timeout))
+	}
+	if d.Scheme == ProxySchemeHTTP {
+		conn, err = d.connectHTTP(conn, address)
+	} else {
+		err = d.connectSOCKS5(conn, address)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy %s failed to connect to %s: %w", d.Address, address, err)
+	}
+	if d.Timeout > 0 {
+		_ = conn.SetDeadline(time.Time{})
+	}
+	return conn, nil
+}
+
+// connectHTTP tunnels to the address with an HTTP CONNECT request.
+func (d *ProxyDialer) connectHTTP(conn net.Conn, address string) (net.Conn, error) {
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if d.Username != "" {
+		request.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(d.Username+":"+d.Password)))
+	}
+	if err := request.Write(conn); err != nil {
+		return conn, err
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return conn, err
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return conn, fmt.Errorf("unexpected CONNECT response status %q", response.Status)
+	}
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn which first returns the bytes read ahead of the CONNECT response.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// The SOCKS5 protocol constants (RFC 1928 & RFC 1929).
+const (
+	socks5Version          = 5
+	socks5AuthNone         = 0
+	socks5AuthPassword     = 2
+	socks5AuthUnacceptable = 0xff
+	socks5PasswordVersion  = 1
+	socks5CommandConnect   = 1
+	socks5AddressIPv4      = 1
+	socks5AddressDomain    = 3
+	socks5AddressIPv6      = 4
+)
+
+// connectSOCKS5 tunnels to the address with a SOCKS5 CONNECT request.
+func (d *ProxyDialer) connectSOCKS5(conn net.Conn, address string) error {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portString)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %q", portString)
+	}
+
+	// Negotiate the authentication method
+	methods := []byte{socks5AuthNone}
+	if d.Username != "" {
+		methods = []byte{socks5AuthPassword}
+	}
+	if _, err = conn.Write(append([]byte{socks5Version, byte(len(methods))}, methods...)); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err = io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != socks5Version {
+		return fmt.Errorf("unexpected SOCKS version %d", reply[0])
+	}
+	switch reply[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if len(d.Username) > 255 || len(d.Password) > 255 {
+			return errors.New("SOCKS5 username and password must not exceed 255 bytes")
+		}
+		request := []byte{socks5PasswordVersion, byte(len(d.Username))}
+		request = append(request, d.Username...)
+		request = append(request, byte(len(d.Password)))
+		request = append(request, d.Password...)
+		if _, err = conn.Write(request); err != nil {
+			return err
+		}
+		if _, err = io.ReadFull(conn, reply); err != nil {
+			return err
+		}
+		if reply[1] != 0 {
+			return errors.New("SOCKS5 authentication failed")
+		}
+	case socks5AuthUnacceptable:
+		return errors.New("no acceptable SOCKS5 authentication method")
+	default:
+		return fmt.Errorf("unexpected SOCKS5 authentication method %d", reply[1])
+	}
+
+	// Request the connection to the address (leaving host names to be resolved by the proxy)
+	request := []byte{socks5Version, socks5CommandConnect, 0}
+	if ip := net.ParseIP(host); ip != nil && ip.To4() != nil {
+		request = append(append(request, socks5AddressIPv4), ip.To4()...)
+	} else if ip != nil {
+		request = append(append(request, socks5AddressIPv6), ip.To16()...)
+	} else {
+		if len(host) > 255 {
+			return fmt.Errorf("host name %q is too long", host)
+		}
+		request = append(append(request, socks5AddressDomain, byte(len(host))), host...)
+	}
+	request = append(request, byte(port>>8), byte(port))
+	if _, err = conn.Write(request); err != nil {
+		return err
+	}
+
+	// Read the reply, discarding the address bound by the proxy
+	header := make([]byte, 4)
+	if _, err = io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[1] != 0 {
+		return fmt.Errorf("SOCKS5 connect failed with reply code %d", header[1])
+	}
+	var boundLength int
+	switch header[3] {
+	case socks5AddressIPv4:
+		boundLength = net.IPv4len
+	case socks5AddressIPv6:
+		boundLength = net.IPv6len
+	case socks5AddressDomain:
+		if _, err = io.ReadFull(conn, header[:1]); err != nil {
+			return err
+		}
+		boundLength = int(header[0])
+	default:
+		return fmt.Errorf("unexpected SOCKS5 address type %d", header[3])
+	}
+	_, err = io.ReadFull(conn, make([]byte, boundLength+2))
+	return err
+}
+
+// UpdateConfigProxy updates the sarama config to connect to its brokers through the egress
+// proxy at the given URL, dialing the proxy with the config's dial timeout, keep-alive and local
+// address. An empty proxy URL leaves the config as-is. Any broker address rewrites must be
+// applied afterwards (so that the rewritten addresses are those requested from the proxy).
+func UpdateConfigProxy(cfg *sarama.Config, proxyURL string, username string, password string) error {
+	if proxyURL == "" {
+		return nil
+	}
+	dialer, err := NewProxyDialer(proxyURL, username, password, cfg.Net.DialTimeout, &net.Dialer{
+		Timeout:   cfg.Net.DialTimeout,
+		KeepAlive: cfg.Net.KeepAlive,
+		LocalAddr: cfg.Net.LocalAddr,
+	})
+	if err != nil {
+		return err
+	}
+	cfg.Net.Proxy.Enable = true
+	cfg.Net.Proxy.Dialer = dialer
+	return nil
+}
+
+// CarryForwardDialer applies the egress proxy and broker address rewrites (if any) of the
+// current sarama config to a new one, rebuilding the dialers from the new config's settings.
+func CarryForwardDialer(newCfg *sarama.Config, currentCfg *sarama.Config) {
+	if !currentCfg.Net.Proxy.Enable {
+		return
+	}
+	dialer := currentCfg.Net.Proxy.Dialer
+	var rewrites map[string]string
+	if addressDialer, ok := dialer.(*BrokerAddressDialer); ok {
+		rewrites = addressDialer.Rewrites
+		dialer = addressDialer.Dialer
+	}
+	if proxyDialer, ok := dialer.(*ProxyDialer); ok {
+		_ = UpdateConfigProxy(newCfg, proxyDialer.Scheme+"://"+proxyDialer.Address, proxyDialer.Username, proxyDialer.Password) // Already validated
+	}
+	_ = UpdateConfigBrokerAddressRewrites(newCfg, rewrites) // Already validated
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBrokerMaxWait is how long WaitForBrokers waits for the Kafka brokers by default.
+	DefaultBrokerMaxWait = 5 * time.Minute
+
+	// DefaultBrokerInitialBackoff is the default delay before the second broker probe.
+	DefaultBrokerInitialBackoff = 1 * time.Second
+
+	// DefaultBrokerMaxBackoff is the default upper bound of the delay between broker probes.
+	DefaultBrokerMaxBackoff = 30 * time.Second
+)
+
+// newClientWrapper wraps sarama.NewClient so that it may be replaced in unit tests.
+var newClientWrapper = sarama.NewClient
+
+// BrokerWaitConfig controls how WaitForBrokers probes the Kafka brokers. Unset (zero)
+// values are replaced by the corresponding defaults.
+type BrokerWaitConfig struct {
+	MaxWait        time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// withDefaults returns a copy of the BrokerWaitConfig with unset values defaulted.
+func (c BrokerWaitConfig) withDefaults() BrokerWaitConfig {
+	if c.MaxWait <= 0 {
+		c.MaxWait = DefaultBrokerMaxWait
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultBrokerInitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultBrokerMaxBackoff
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		c.MaxBackoff = c.InitialBackoff
+	}
+	return c
+}
+
+// WaitForBrokers blocks until a Kafka client can be connected to the given brokers with the
+// given configuration, probing with an exponential backoff between attempts. This lets
+// components started before Kafka is reachable (e.g. during a cluster cold start) wait for it
+// instead of failing with the first connection error. An error is returned when the brokers
+// are still unreachable after the configured maximum wait, or when the context is done.
+func WaitForBrokers(ctx context.Context, logger *zap.Logger, brokers []string, config *sarama.Config, waitConfig BrokerWaitConfig) error {
+	waitConfig = waitConfig.withDefaults()
+
+	// Each probe is a single attempt - the retries are driven by the backoff below
+	probeConfig := sarama.NewConfig()
+	if config != nil {
+		*probeConfig = *config
+	}
+	probeConfig.Metadata.Retry.Max = 0
+
+	start := time.Now()
+	deadline := start.Add(waitConfig.MaxWait)
+	backoff := waitConfig.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		client, err := newClientWrapper(brokers, probeConfig)
+		if err == nil {
+			_ = client.Close()
+			logger.Info("Kafka brokers reachable",
+				zap.Strings("brokers", brokers),
+				zap.Int("attempts", attempt),
+				zap.Duration("elapsed", time.Since(start)))
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("kafka brokers %v unreachable after %d attempts over %s: %w", brokers, attempt, time.Since(start).Round(time.Millisecond), err)
+		}
+		if backoff > remaining {
+			backoff = remaining
+		}
+		logger.Warn("Kafka brokers not reachable yet, waiting before retrying",
+			zap.Strings("brokers", brokers),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Duration("remaining", remaining),
+			zap.Error(err))
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > waitConfig.MaxBackoff {
+			backoff = waitConfig.MaxBackoff
+		}
+	}
+}
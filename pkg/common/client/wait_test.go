@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWaitForBrokers(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()),
+	})
+
+	// Fail The First Two Attempts Before Connecting To The Mock Broker
+	attempts := 0
+	restore := stubNewClient(func(addrs []string, config *sarama.Config) (sarama.Client, error) {
+		attempts++
+		require.Equal(t, 0, config.Metadata.Retry.Max)
+		if attempts < 3 {
+			return nil, sarama.ErrOutOfBrokers
+		}
+		return sarama.NewClient(addrs, config)
+	})
+	defer restore()
+
+	config := sarama.NewConfig()
+	config.Metadata.Retry.Max = 5
+	waitConfig := BrokerWaitConfig{MaxWait: time.Second, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	err := WaitForBrokers(context.TODO(), zap.NewNop(), []string{broker.Addr()}, config, waitConfig)
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+	require.Equal(t, 5, config.Metadata.Retry.Max) // The Caller's Config Is Not Modified
+}
+
+func TestWaitForBrokersMaxWait(t *testing.T) {
+	attempts := 0
+	restore := stubNewClient(func(addrs []string, config *sarama.Config) (sarama.Client, error) {
+		attempts++
+		return nil, sarama.ErrOutOfBrokers
+	})
+	defer restore()
+
+	waitConfig := BrokerWaitConfig{MaxWait: 20 * time.Millisecond, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	err := WaitForBrokers(context.TODO(), zap.NewNop(), []string{"unreachable:9092"}, sarama.NewConfig(), waitConfig)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, sarama.ErrOutOfBrokers))
+	require.Contains(t, err.Error(), "unreachable after")
+	require.Greater(t, attempts, 1)
+}
+
+func TestWaitForBrokersContextDone(t *testing.T) {
+	restore := stubNewClient(func(addrs []string, config *sarama.Config) (sarama.Client, error) {
+		return nil, sarama.ErrOutOfBrokers
+	})
+	defer restore()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	err := WaitForBrokers(ctx, zap.NewNop(), []string{"unreachable:9092"}, nil, BrokerWaitConfig{})
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestBrokerWaitConfigWithDefaults(t *testing.T) {
+	require.Equal(t, BrokerWaitConfig{
+		MaxWait:        DefaultBrokerMaxWait,
+		InitialBackoff: DefaultBrokerInitialBackoff,
+		MaxBackoff:     DefaultBrokerMaxBackoff,
+	}, BrokerWaitConfig{}.withDefaults())
+
+	// A Max Backoff Below The Initial Backoff Is Raised To It
+	require.Equal(t, 2*time.Second, BrokerWaitConfig{InitialBackoff: 2 * time.Second, MaxBackoff: time.Second}.withDefaults().MaxBackoff)
+}
+
+// stubNewClient replaces the sarama client constructor and returns a function restoring it.
+func stubNewClient(stub func([]string, *sarama.Config) (sarama.Client, error)) func() {
+	original := newClientWrapper
+	newClientWrapper = stub
+	return func() { newClientWrapper = original }
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package consumer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Shopify/sarama"
+)
+
+const (
+	// BalanceStrategyCooperativeSticky is the name of the incremental cooperative rebalancing protocol
+	// (KIP-429), which avoids the stop-the-world rebalances of the eager strategies.
+	BalanceStrategyCooperativeSticky = "cooperative-sticky"
+)
+
+// balanceStrategies are the eager rebalance strategies supported by the sarama client, by name.
+var balanceStrategies = map[string]sarama.BalanceStrategy{
+	sarama.RangeBalanceStrategyName:      sarama.BalanceStrategyRange,
+	sarama.RoundRobinBalanceStrategyName: sarama.BalanceStrategyRoundRobin,
+	sarama.StickyBalanceStrategyName:     sarama.BalanceStrategySticky,
+}
+
+// BalanceStrategyNames returns the names of the supported rebalance strategies.
+func BalanceStrategyNames() []string {
+	return []string{sarama.RangeBalanceStrategyName, sarama.RoundRobinBalanceStrategyName, sarama.StickyBalanceStrategyName}
+}
+
+// NewBalanceStrategy returns the consumer group rebalance strategy with the given name (case insensitive).
+// The incremental cooperative strategy is rejected, as the sarama client only implements the eager
+// rebalance protocol.
+func NewBalanceStrategy(name string) (sarama.BalanceStrategy, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if strategy, ok := balanceStrategies[name]; ok {
+		return strategy, nil
+	}
+	if name == BalanceStrategyCooperativeSticky {
+		return nil, fmt.Errorf("rebalance strategy %q is not supported by the Kafka client, use one of %v", name, BalanceStrategyNames())
+	}
+	return nil, fmt.Errorf("unknown rebalance strategy %q, use one of %v", name, BalanceStrategyNames())
+}
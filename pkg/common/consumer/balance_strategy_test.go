@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package consumer
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBalanceStrategy(t *testing.T) {
+	testCases := map[string]struct {
+		name    string
+		want    sarama.BalanceStrategy
+		wantErr string
+	}{
+		"range": {
+			name: "range",
+			want: sarama.BalanceStrategyRange,
+		},
+		"roundrobin": {
+			name: "roundrobin",
+			want: sarama.BalanceStrategyRoundRobin,
+		},
+		"sticky": {
+			name: "sticky",
+			want: sarama.BalanceStrategySticky,
+		},
+		"case and spaces": {
+			name: " Sticky ",
+			want: sarama.BalanceStrategySticky,
+		},
+		"cooperative sticky": {
+			name:    "cooperative-sticky",
+			wantErr: `rebalance strategy "cooperative-sticky" is not supported by the Kafka client, use one of [range roundrobin sticky]`,
+		},
+		"unknown": {
+			name:    "foo",
+			wantErr: `unknown rebalance strategy "foo", use one of [range roundrobin sticky]`,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewBalanceStrategy(tc.name)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
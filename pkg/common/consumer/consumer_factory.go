@@ -31,8 +31,9 @@ type KafkaConsumerGroupFactory interface {
 }
 
 type kafkaConsumerGroupFactoryImpl struct {
-	config *sarama.Config
-	addrs  []string
+	config     *sarama.Config
+	addrs      []string
+	partitions []int32
 }
 
 type customConsumerGroup struct {
@@ -54,7 +55,13 @@ func (c *customConsumerGroup) Close() error {
 var _ sarama.ConsumerGroup = (*customConsumerGroup)(nil)
 
 func (c kafkaConsumerGroupFactoryImpl) StartConsumerGroup(groupID string, topics []string, logger *zap.SugaredLogger, handler KafkaConsumerHandler) (sarama.ConsumerGroup, error) {
-	consumerGroup, err := newConsumerGroup(c.addrs, groupID, c.config)
+	var consumerGroup sarama.ConsumerGroup
+	var err error
+	if len(c.partitions) > 0 {
+		consumerGroup, err = newPartitionConsumerGroup(c.addrs, groupID, c.partitions, c.config)
+	} else {
+		consumerGroup, err = newConsumerGroup(c.addrs, groupID, c.config)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +77,7 @@ func (c kafkaConsumerGroupFactoryImpl) StartConsumerGroup(groupID string, topics
 				return
 			}
 			if err != nil {
-				consumerHandler.errors <- err
+				consumerHandler.sendError(err)
 			}
 
 			select {
@@ -88,6 +95,12 @@ func NewConsumerGroupFactory(addrs []string, config *sarama.Config) KafkaConsume
 	return kafkaConsumerGroupFactoryImpl{addrs: addrs, config: config}
 }
 
+// NewPartitionConsumerGroupFactory creates a factory whose consumer groups consume the given partitions of the topics,
+// bypassing the consumer group protocol. The offsets are still committed on behalf of the consumer group ID.
+func NewPartitionConsumerGroupFactory(addrs []string, config *sarama.Config, partitions []int32) KafkaConsumerGroupFactory {
+	return kafkaConsumerGroupFactoryImpl{addrs: addrs, config: config, partitions: partitions}
+}
+
 var _ KafkaConsumerGroupFactory = (*kafkaConsumerGroupFactoryImpl)(nil)
 
 func mergeErrorChannels(channels ...<-chan error) <-chan error {
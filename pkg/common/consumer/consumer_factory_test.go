@@ -155,3 +155,27 @@ func TestErrorWhileNewConsumerGroup(t *testing.T) {
 		t.Errorf("Should contain an error with message boom!. Got %v", err)
 	}
 }
+
+func TestPartitionConsumerGroupFactory(t *testing.T) {
+
+	var gotPartitions []int32
+	defer func(original func([]string, string, []int32, *sarama.Config) (sarama.ConsumerGroup, error)) {
+		newPartitionConsumerGroup = original
+	}(newPartitionConsumerGroup)
+	newPartitionConsumerGroup = func(addrs []string, groupID string, partitions []int32, config *sarama.Config) (sarama.ConsumerGroup, error) {
+		gotPartitions = partitions
+		return &mockConsumerGroup{}, nil
+	}
+	newConsumerGroup = mockedNewConsumerGroupFromClient(nil, false, false, false, true)
+
+	factory := NewPartitionConsumerGroupFactory([]string{"b1", "b2"}, sarama.NewConfig(), []int32{1, 3})
+	cg, err := factory.StartConsumerGroup("bla", []string{}, zap.L().Sugar(), nil)
+	if err != nil {
+		t.Errorf("Should not throw error %v", err)
+	}
+	_ = cg.Close()
+
+	if len(gotPartitions) != 2 || gotPartitions[0] != 1 || gotPartitions[1] != 3 {
+		t.Errorf("Should create a partition consumer group for partitions [1 3]. Got %v", gotPartitions)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
@@ -31,6 +32,17 @@ type KafkaConsumerHandler interface {
 	Handle(context context.Context, message *sarama.ConsumerMessage) (bool, error)
 }
 
+// KafkaConsumerRetryingHandler is a KafkaConsumerHandler whose messages failing without being marked are retried in
+// place rather than skipped. The failure is thereby isolated to the partition of the message, the other partitions
+// (and topics) being consumed independently with their own backoff. The Failure is recorded in the metadata of the
+// offset committed for the partition until the message is handled.
+type KafkaConsumerRetryingHandler interface {
+	KafkaConsumerHandler
+
+	// RetryBackoff returns the delay before the given retry (starting at 1) of a failed message.
+	RetryBackoff(attempt int) time.Duration
+}
+
 // ConsumerHandler implements sarama.ConsumerGroupHandler and provides some glue code to simplify message handling
 // You must implement KafkaConsumerHandler and create a new SaramaConsumerHandler with it
 type SaramaConsumerHandler struct {
@@ -38,9 +50,10 @@ type SaramaConsumerHandler struct {
 	handler KafkaConsumerHandler
 
 	logger *zap.SugaredLogger
-	// Errors channel
-	closeErrors sync.Once
-	errors      chan error
+	// Errors channel, closed by the Cleanup of the first session
+	errorsLock   sync.RWMutex
+	errorsClosed bool
+	errors       chan error
 }
 
 func NewConsumerHandler(logger *zap.SugaredLogger, handler KafkaConsumerHandler) SaramaConsumerHandler {
@@ -58,12 +71,25 @@ func (consumer *SaramaConsumerHandler) Setup(sarama.ConsumerGroupSession) error
 
 // Cleanup is run at the end of a session, once all ConsumeClaim goroutines have exited
 func (consumer *SaramaConsumerHandler) Cleanup(session sarama.ConsumerGroupSession) error {
-	consumer.closeErrors.Do(func() {
+	consumer.errorsLock.Lock()
+	defer consumer.errorsLock.Unlock()
+	if !consumer.errorsClosed {
 		close(consumer.errors)
-	})
+		consumer.errorsClosed = true
+	}
 	return nil
 }
 
+// sendError enqueues the error in the errors channel. The errors of the sessions following the Cleanup of the first
+// one, which closed the channel, are only logged rather than panicking (restarting the consumers of every topic).
+func (consumer *SaramaConsumerHandler) sendError(err error) {
+	consumer.errorsLock.RLock()
+	defer consumer.errorsLock.RUnlock()
+	if !consumer.errorsClosed {
+		consumer.errors <- err
+	}
+}
+
 // ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages().
 func (consumer *SaramaConsumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	consumer.logger.Info(fmt.Sprintf("Starting partition consumer, topic: %s, partition: %d, initialOffset: %d", claim.Topic(), claim.Partition(), claim.InitialOffset()))
@@ -77,23 +103,57 @@ func (consumer *SaramaConsumerHandler) ConsumeClaim(session sarama.ConsumerGroup
 			consumer.logger.Debugw("Message claimed", zap.String("topic", message.Topic), zap.Binary("value", message.Value))
 		}
 
+		if !consumer.handle(session, message) {
+			break // The session ended while retrying the message
+		}
+	}
+
+	consumer.logger.Infof("Stopping partition consumer, topic: %s, partition: %d", claim.Topic(), claim.Partition())
+	return nil
+}
+
+// handle handles the message and marks it if required. The failed messages which are not marked are retried in place
+// if the handler is a KafkaConsumerRetryingHandler, until they are handled or the session ends (returning false).
+func (consumer *SaramaConsumerHandler) handle(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) bool {
+	var failure *Failure
+	for attempt := 1; ; attempt++ {
 		mustMark, err := consumer.handler.Handle(session.Context(), message)
 
 		if err != nil {
 			consumer.logger.Infow("Failure while handling a message", zap.String("topic", message.Topic), zap.Int32("partition", message.Partition), zap.Int64("offset", message.Offset), zap.Error(err))
-			consumer.errors <- err
+			consumer.sendError(err)
 		}
 		if mustMark {
-			session.MarkMessage(message, "") // Mark kafka message as processed
+			session.MarkMessage(message, "") // Mark kafka message as processed, clearing any recorded failure
 			if ce := consumer.logger.Desugar().Check(zap.DebugLevel, "debugging"); ce != nil {
 				consumer.logger.Debugw("Message marked", zap.String("topic", message.Topic), zap.Binary("value", message.Value))
 			}
+			return true
 		}
 
-	}
+		retryingHandler, ok := consumer.handler.(KafkaConsumerRetryingHandler)
+		if !ok || err == nil {
+			return true
+		}
 
-	consumer.logger.Infof("Stopping partition consumer, topic: %s, partition: %d", claim.Topic(), claim.Partition())
-	return nil
+		// Record the failure in the offset of the partition (marking it first in case no offset was committed yet)
+		if failure == nil {
+			failure = &Failure{Since: time.Now()}
+		}
+		failure.Error = err.Error()
+		failure.Attempts = attempt
+		metadata := failure.Metadata()
+		session.MarkOffset(message.Topic, message.Partition, message.Offset, metadata)
+		session.ResetOffset(message.Topic, message.Partition, message.Offset, metadata)
+
+		backoff := time.NewTimer(retryingHandler.RetryBackoff(attempt))
+		select {
+		case <-session.Context().Done():
+			backoff.Stop()
+			return false
+		case <-backoff.C:
+		}
+	}
 }
 
 var _ sarama.ConsumerGroupHandler = (*SaramaConsumerHandler)(nil)
@@ -20,9 +20,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
 
@@ -39,7 +42,9 @@ var mockMessage = sarama.ConsumerMessage{
 }
 
 type mockConsumerGroupSession struct {
-	marked bool
+	ctx      context.Context
+	marked   bool
+	metadata string
 }
 
 func (m *mockConsumerGroupSession) Commit() {
@@ -62,14 +67,16 @@ func (m *mockConsumerGroupSession) MarkOffset(topic string, partition int32, off
 }
 
 func (m *mockConsumerGroupSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+	m.metadata = metadata
 }
 
 func (m *mockConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
 	m.marked = true
+	m.metadata = metadata
 }
 
 func (m *mockConsumerGroupSession) Context() context.Context {
-	return nil
+	return m.ctx
 }
 
 var _ sarama.ConsumerGroupSession = (*mockConsumerGroupSession)(nil)
@@ -159,3 +166,76 @@ func Test(t *testing.T) {
 		})
 	}
 }
+
+// mockRetryingHandler fails the first failures attempts to handle a message, without marking it.
+type mockRetryingHandler struct {
+	failures int
+	attempts int
+	backoffs []int
+	session  *mockConsumerGroupSession
+	failed   []string
+}
+
+func (m *mockRetryingHandler) Handle(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+	m.attempts++
+	if m.attempts <= m.failures {
+		return false, fmt.Errorf("failure %d", m.attempts)
+	}
+	return true, nil
+}
+
+func (m *mockRetryingHandler) RetryBackoff(attempt int) time.Duration {
+	m.backoffs = append(m.backoffs, attempt)
+	// The failure is recorded before backing off
+	m.failed = append(m.failed, m.session.metadata)
+	return time.Millisecond
+}
+
+func TestRetryingHandler(t *testing.T) {
+	session := &mockConsumerGroupSession{ctx: context.Background()}
+	handler := &mockRetryingHandler{failures: 2, session: session}
+	cgh := NewConsumerHandler(zap.NewNop().Sugar(), handler)
+	claim := mockConsumerGroupClaim{msg: &mockMessage}
+
+	_ = cgh.Setup(session)
+	assert.Nil(t, cgh.ConsumeClaim(session, claim))
+
+	// The message is retried in place until handled, recording its failure meanwhile
+	assert.Equal(t, 3, handler.attempts)
+	assert.Equal(t, []int{1, 2}, handler.backoffs)
+	assert.Len(t, handler.failed, 2)
+	failure := ParseFailure(handler.failed[1])
+	assert.NotNil(t, failure)
+	assert.Equal(t, "failure 2", failure.Error)
+	assert.Equal(t, 2, failure.Attempts)
+	assert.Equal(t, ParseFailure(handler.failed[0]).Since, failure.Since)
+	assert.True(t, session.marked)
+	assert.Empty(t, session.metadata)
+	assert.Equal(t, "failure 1", (<-cgh.errors).Error())
+	assert.Equal(t, "failure 2", (<-cgh.errors).Error())
+
+	// The retries stop with the session
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	session = &mockConsumerGroupSession{ctx: ctx}
+	handler = &mockRetryingHandler{failures: 10, session: session}
+	cgh = NewConsumerHandler(zap.NewNop().Sugar(), handler)
+	assert.Nil(t, cgh.ConsumeClaim(session, claim))
+	assert.Equal(t, 1, handler.attempts)
+	assert.False(t, session.marked)
+	assert.NotNil(t, ParseFailure(session.metadata))
+
+	// The errors of the following sessions are dropped rather than sent to the closed channel
+	_ = cgh.Cleanup(session)
+	cgh.sendError(errors.New("dropped"))
+}
+
+func TestFailureMetadata(t *testing.T) {
+	since := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	failure := &Failure{Error: strings.Repeat("x", maxFailureErrorLength+1), Since: since, Attempts: 3}
+	parsed := ParseFailure(failure.Metadata())
+	assert.Equal(t, &Failure{Error: strings.Repeat("x", maxFailureErrorLength), Since: since, Attempts: 3}, parsed)
+	assert.Nil(t, ParseFailure(""))
+	assert.Nil(t, ParseFailure("failure:{"))
+	assert.Nil(t, ParseFailure(`{"error":"not prefixed"}`))
+}
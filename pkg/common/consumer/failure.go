@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumer
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// failureMetadataPrefix prefixes the offset metadata recording a Failure.
+	failureMetadataPrefix = "failure:"
+
+	// maxFailureErrorLength bounds the error recorded in the offset metadata, which the brokers limit to 4KiB by default.
+	maxFailureErrorLength = 1024
+)
+
+// Failure describes the failure to handle the message a partition is stalled on. It is recorded in the metadata of
+// the offset committed for the partition (that of the failing message) until the message is handled, so that the
+// stalled partitions of a consumer group can be observed by listing its committed offsets.
+type Failure struct {
+	Error    string    `json:"error"`
+	Since    time.Time `json:"since"`
+	Attempts int       `json:"attempts"`
+}
+
+// Metadata returns the offset metadata recording the Failure.
+func (f *Failure) Metadata() string {
+	failure := *f
+	if len(failure.Error) > maxFailureErrorLength {
+		failure.Error = failure.Error[:maxFailureErrorLength]
+	}
+	data, _ := json.Marshal(&failure) // Failures Only Hold Strings, Times & Numbers, Which Always Marshal
+	return failureMetadataPrefix + string(data)
+}
+
+// ParseFailure returns the Failure recorded in the offset metadata, or nil if it doesn't record one.
+func ParseFailure(metadata string) *Failure {
+	if !strings.HasPrefix(metadata, failureMetadataPrefix) {
+		return nil
+	}
+	failure := &Failure{}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(metadata, failureMetadataPrefix)), failure); err != nil {
+		return nil
+	}
+	return failure
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+var newPartitionConsumerGroup = func(addrs []string, groupID string, partitions []int32, config *sarama.Config) (sarama.ConsumerGroup, error) {
+	client, err := sarama.NewClient(addrs, config)
+	if err != nil {
+		return nil, err
+	}
+	group, err := newPartitionConsumerGroupFromClient(groupID, partitions, client)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return group, nil
+}
+
+// partitionConsumerGroup is a sarama.ConsumerGroup consuming a static assignment of partitions (the same
+// partitions of every topic) instead of joining the consumer group. The offsets are still fetched and
+// committed on behalf of the consumer group ID, which must not be used by other (non static) consumers.
+type partitionConsumerGroup struct {
+	client        sarama.Client
+	consumer      sarama.Consumer
+	offsetManager sarama.OffsetManager
+	partitions    []int32
+
+	lock       sync.Mutex
+	errors     chan error
+	errorsLock sync.RWMutex
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+func newPartitionConsumerGroupFromClient(groupID string, partitions []int32, client sarama.Client) (*partitionConsumerGroup, error) {
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	offsetManager, err := sarama.NewOffsetManagerFromClient(groupID, client)
+	if err != nil {
+		_ = consumer.Close()
+		return nil, err
+	}
+
+	return &partitionConsumerGroup{
+		client:        client,
+		consumer:      consumer,
+		offsetManager: offsetManager,
+		partitions:    partitions,
+		errors:        make(chan error, client.Config().ChannelBufferSize),
+		closed:        make(chan struct{}),
+	}, nil
+}
+
+// Consume consumes the assigned partitions of the given topics until the context is cancelled
+// or the group is closed.
+func (g *partitionConsumerGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	select {
+	case <-g.closed:
+		return sarama.ErrClosedConsumerGroup
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sess := &partitionConsumerGroupSession{
+		ctx:    ctx,
+		claims: make(map[string][]int32, len(topics)),
+		poms:   make(map[string]map[int32]sarama.PartitionOffsetManager, len(topics)),
+	}
+	defer sess.close()
+
+	claims := make([]*partitionConsumerGroupClaim, 0, len(topics)*len(g.partitions))
+	defer func() {
+		for _, claim := range claims {
+			claim.AsyncClose()
+		}
+	}()
+
+	for _, topic := range topics {
+		if err := g.checkPartitions(topic); err != nil {
+			g.backoff(ctx)
+			return err
+		}
+		sess.claims[topic] = g.partitions
+		sess.poms[topic] = make(map[int32]sarama.PartitionOffsetManager, len(g.partitions))
+
+		for _, partition := range g.partitions {
+			claim, err := g.newClaim(sess, topic, partition)
+			if err != nil {
+				g.backoff(ctx)
+				return err
+			}
+			claims = append(claims, claim)
+		}
+	}
+
+	if err := handler.Setup(sess); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, claim := range claims {
+		wg.Add(1)
+		go func(claim *partitionConsumerGroupClaim) {
+			defer wg.Done()
+			if err := handler.ConsumeClaim(sess, claim); err != nil {
+				g.handleError(err, claim.topic, claim.partition)
+			}
+		}(claim)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-g.closed:
+	}
+
+	// Stop the claims and wait for the handler to return from ConsumeClaim
+	for _, claim := range claims {
+		claim.AsyncClose()
+	}
+	wg.Wait()
+
+	return handler.Cleanup(sess)
+}
+
+// backoff waits before retrying to consume, unless the context is cancelled or the group is closed.
+func (g *partitionConsumerGroup) backoff(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-g.closed:
+	case <-time.After(g.client.Config().Consumer.Retry.Backoff):
+	}
+}
+
+// checkPartitions ensures the assigned partitions exist in the given topic.
+func (g *partitionConsumerGroup) checkPartitions(topic string) error {
+	available, err := g.client.Partitions(topic)
+	if err != nil {
+		return err
+	}
+	for _, partition := range g.partitions {
+		found := false
+		for _, p := range available {
+			if p == partition {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("partition %d does not exist in topic %s", partition, topic)
+		}
+	}
+	return nil
+}
+
+// newClaim starts consuming the given partition from the committed offset of the consumer group.
+func (g *partitionConsumerGroup) newClaim(sess *partitionConsumerGroupSession, topic string, partition int32) (*partitionConsumerGroupClaim, error) {
+	pom, err := g.offsetManager.ManagePartition(topic, partition)
+	if err != nil {
+		return nil, err
+	}
+	sess.poms[topic][partition] = pom
+
+	go func() {
+		for err := range pom.Errors() {
+			g.handleError(err, topic, partition)
+		}
+	}()
+
+	offset, _ := pom.NextOffset()
+	pc, err := g.consumer.ConsumePartition(topic, partition, offset)
+	if err == sarama.ErrOffsetOutOfRange {
+		offset = g.client.Config().Consumer.Offsets.Initial
+		pc, err = g.consumer.ConsumePartition(topic, partition, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for err := range pc.Errors() {
+			g.handleError(err, topic, partition)
+		}
+	}()
+
+	return &partitionConsumerGroupClaim{
+		topic:             topic,
+		partition:         partition,
+		offset:            offset,
+		PartitionConsumer: pc,
+	}, nil
+}
+
+func (g *partitionConsumerGroup) handleError(err error, topic string, partition int32) {
+	if _, ok := err.(*sarama.ConsumerError); !ok {
+		err = &sarama.ConsumerError{
+			Topic:     topic,
+			Partition: partition,
+			Err:       err,
+		}
+	}
+
+	g.errorsLock.RLock()
+	defer g.errorsLock.RUnlock()
+
+	select {
+	case <-g.closed:
+		return
+	default:
+	}
+
+	select {
+	case g.errors <- err:
+	default:
+		// no error listener
+	}
+}
+
+func (g *partitionConsumerGroup) Errors() <-chan error {
+	return g.errors
+}
+
+// Close stops the consumption and commits the marked offsets.
+func (g *partitionConsumerGroup) Close() (err error) {
+	g.closeOnce.Do(func() {
+		close(g.closed)
+
+		// Wait for Consume to return
+		g.lock.Lock()
+		defer g.lock.Unlock()
+
+		if e := g.offsetManager.Close(); e != nil {
+			err = e
+		}
+		if e := g.consumer.Close(); e != nil {
+			err = e
+		}
+		if e := g.client.Close(); e != nil {
+			err = e
+		}
+
+		g.errorsLock.Lock()
+		close(g.errors)
+		g.errorsLock.Unlock()
+	})
+	return
+}
+
+var _ sarama.ConsumerGroup = (*partitionConsumerGroup)(nil)
+
+// partitionConsumerGroupSession is the sarama.ConsumerGroupSession of a partitionConsumerGroup, marking the offsets
+// with the offset manager of the consumer group.
+type partitionConsumerGroupSession struct {
+	ctx    context.Context
+	claims map[string][]int32
+	poms   map[string]map[int32]sarama.PartitionOffsetManager
+}
+
+func (s *partitionConsumerGroupSession) Claims() map[string][]int32 { return s.claims }
+func (s *partitionConsumerGroupSession) MemberID() string           { return "" }
+func (s *partitionConsumerGroupSession) GenerationID() int32        { return -1 }
+func (s *partitionConsumerGroupSession) Context() context.Context   { return s.ctx }
+
+func (s *partitionConsumerGroupSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	if pom := s.poms[topic][partition]; pom != nil {
+		pom.MarkOffset(offset, metadata)
+	}
+}
+
+func (s *partitionConsumerGroupSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+	if pom := s.poms[topic][partition]; pom != nil {
+		pom.ResetOffset(offset, metadata)
+	}
+}
+
+func (s *partitionConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+// Commit is a no-op: the offsets are committed periodically by the offset manager, and when the group is closed.
+func (s *partitionConsumerGroupSession) Commit() {}
+
+// close releases the partition offset managers, keeping the marked offsets for the next commit.
+func (s *partitionConsumerGroupSession) close() {
+	for _, poms := range s.poms {
+		for _, pom := range poms {
+			pom.AsyncClose()
+		}
+	}
+}
+
+var _ sarama.ConsumerGroupSession = (*partitionConsumerGroupSession)(nil)
+
+// partitionConsumerGroupClaim is the sarama.ConsumerGroupClaim of a statically assigned partition.
+type partitionConsumerGroupClaim struct {
+	topic     string
+	partition int32
+	offset    int64
+	sarama.PartitionConsumer
+
+	closeOnce sync.Once
+}
+
+func (c *partitionConsumerGroupClaim) Topic() string        { return c.topic }
+func (c *partitionConsumerGroupClaim) Partition() int32     { return c.partition }
+func (c *partitionConsumerGroupClaim) InitialOffset() int64 { return c.offset }
+
+// AsyncClose closes the partition consumer only once, as it is called both when the session ends and on errors.
+func (c *partitionConsumerGroupClaim) AsyncClose() {
+	c.closeOnce.Do(c.PartitionConsumer.AsyncClose)
+}
+
+var _ sarama.ConsumerGroupClaim = (*partitionConsumerGroupClaim)(nil)
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTopic = "topic1"
+	testGroup = "group"
+)
+
+// markingHandler marks the consumed messages and reports the next offset of their partition.
+type markingHandler struct {
+	nextOffsets chan int64
+	cleanedUp   chan struct{}
+}
+
+func (h *markingHandler) Setup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *markingHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	close(h.cleanedUp)
+	return nil
+}
+
+func (h *markingHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for message := range claim.Messages() {
+		session.MarkMessage(message, "")
+		nextOffset, _ := session.(*partitionConsumerGroupSession).poms[message.Topic][message.Partition].NextOffset()
+		h.nextOffsets <- nextOffset
+	}
+	return nil
+}
+
+func newMockPartitionBroker(t *testing.T) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(testTopic, 0, broker.BrokerID()).
+			SetLeader(testTopic, 1, broker.BrokerID()),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, testGroup, broker),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset(testGroup, testTopic, 1, 5, "", sarama.ErrNoError),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(testTopic, 1, sarama.OffsetOldest, 0).
+			SetOffset(testTopic, 1, sarama.OffsetNewest, 10),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			SetMessage(testTopic, 1, 5, sarama.StringEncoder("message")),
+		"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t),
+	})
+	return broker
+}
+
+func TestPartitionConsumerGroup(t *testing.T) {
+	broker := newMockPartitionBroker(t)
+	defer broker.Close()
+
+	config := sarama.NewConfig()
+	group, err := newPartitionConsumerGroup([]string{broker.Addr()}, testGroup, []int32{1}, config)
+	require.NoError(t, err)
+
+	handler := &markingHandler{
+		nextOffsets: make(chan int64),
+		cleanedUp:   make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consumed := make(chan error)
+	go func() {
+		consumed <- group.Consume(ctx, []string{testTopic}, handler)
+	}()
+
+	// The partition is consumed from the committed offset of the consumer group
+	select {
+	case nextOffset := <-handler.nextOffsets:
+		assert.Equal(t, int64(6), nextOffset)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the message")
+	}
+
+	cancel()
+	assert.NoError(t, <-consumed)
+	<-handler.cleanedUp
+
+	assert.NoError(t, group.Close())
+	assert.NoError(t, group.Close())
+	assert.Equal(t, sarama.ErrClosedConsumerGroup, group.Consume(context.Background(), []string{testTopic}, handler))
+
+	// The errors channel is closed with the group
+	_, ok := <-group.Errors()
+	assert.False(t, ok)
+}
+
+func TestPartitionConsumerGroupMissingPartition(t *testing.T) {
+	broker := newMockPartitionBroker(t)
+	defer broker.Close()
+
+	config := sarama.NewConfig()
+	config.Consumer.Retry.Backoff = time.Millisecond
+	group, err := newPartitionConsumerGroup([]string{broker.Addr()}, testGroup, []int32{1, 2}, config)
+	require.NoError(t, err)
+	defer func() { _ = group.Close() }()
+
+	err = group.Consume(context.Background(), []string{testTopic}, &markingHandler{})
+	assert.EqualError(t, err, "partition 2 does not exist in topic topic1")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+)
+
+// CloudEvent extensions describing the failed delivery of an event sent to a dead letter sink.
+const (
+	// ErrorDestExtension is the URI of the destination the delivery failed to.
+	ErrorDestExtension = "knativeerrordest"
+	// ErrorCodeExtension is the HTTP response code of the failed delivery (-1 if no response was received).
+	ErrorCodeExtension = "knativeerrorcode"
+	// ErrorDataExtension is the base64 encoded error message of the failed delivery.
+	ErrorDataExtension = "knativeerrordata"
+	// ErrorRetriesExtension is the number of retries attempted before the delivery was abandoned.
+	ErrorRetriesExtension = "knativeerrorretries"
+	// ErrorTopicExtension is the Kafka topic the event was consumed from.
+	ErrorTopicExtension = "knativeerrortopic"
+	// ErrorPartitionExtension is the Kafka partition the event was consumed from.
+	ErrorPartitionExtension = "knativeerrorpartition"
+	// ErrorOffsetExtension is the Kafka offset the event was consumed from.
+	ErrorOffsetExtension = "knativeerroroffset"
+
+	// MaxErrorDataLength is the maximum length of the (un-encoded) error message.
+	MaxErrorDataLength = 1024
+)
+
+// DeliveryError describes the failed delivery of a Kafka message to its destination.
+type DeliveryError struct {
+	Destination  string
+	ResponseCode int
+	Err          error
+	Retries      int
+	Message      *sarama.ConsumerMessage
+}
+
+// Transformers returns the transformers which add the delivery error extensions to an event.
+func (e *DeliveryError) Transformers() binding.Transformers {
+	errorData := ""
+	if e.Err != nil {
+		errorData = e.Err.Error()
+	}
+	if len(errorData) > MaxErrorDataLength {
+		errorData = errorData[:MaxErrorDataLength]
+	}
+
+	transformers := binding.Transformers{
+		transformer.AddExtension(ErrorDestExtension, e.Destination),
+		transformer.AddExtension(ErrorCodeExtension, strconv.Itoa(e.ResponseCode)),
+		transformer.AddExtension(ErrorDataExtension, base64.StdEncoding.EncodeToString([]byte(errorData))),
+		transformer.AddExtension(ErrorRetriesExtension, strconv.Itoa(e.Retries)),
+	}
+	if e.Message != nil {
+		transformers = append(transformers,
+			transformer.AddExtension(ErrorTopicExtension, e.Message.Topic),
+			transformer.AddExtension(ErrorPartitionExtension, strconv.FormatInt(int64(e.Message.Partition), 10)),
+			transformer.AddExtension(ErrorOffsetExtension, strconv.FormatInt(e.Message.Offset, 10)))
+	}
+	return transformers
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryErrorTransformers(t *testing.T) {
+	testCases := map[string]struct {
+		deliveryError      *DeliveryError
+		expectedExtensions map[string]interface{}
+	}{
+		"with message": {
+			deliveryError: &DeliveryError{
+				Destination:  "http://subscriber",
+				ResponseCode: 500,
+				Err:          errors.New("test error"),
+				Retries:      3,
+				Message:      &sarama.ConsumerMessage{Topic: "topic", Partition: 1, Offset: 2},
+			},
+			expectedExtensions: map[string]interface{}{
+				ErrorDestExtension:      "http://subscriber",
+				ErrorCodeExtension:      "500",
+				ErrorDataExtension:      base64.StdEncoding.EncodeToString([]byte("test error")),
+				ErrorRetriesExtension:   "3",
+				ErrorTopicExtension:     "topic",
+				ErrorPartitionExtension: "1",
+				ErrorOffsetExtension:    "2",
+			},
+		},
+		"without message": {
+			deliveryError: &DeliveryError{
+				Destination:  "http://subscriber",
+				ResponseCode: -1,
+				Err:          errors.New(strings.Repeat("x", MaxErrorDataLength+1)),
+			},
+			expectedExtensions: map[string]interface{}{
+				ErrorDestExtension:    "http://subscriber",
+				ErrorCodeExtension:    "-1",
+				ErrorDataExtension:    base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", MaxErrorDataLength))),
+				ErrorRetriesExtension: "0",
+			},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			e := event.New()
+			e.SetID("id")
+			e.SetSource("source")
+			e.SetType("type")
+
+			transformed, err := binding.ToEvent(context.TODO(), binding.ToMessage(&e), tc.deliveryError.Transformers()...)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedExtensions, transformed.Extensions())
+		})
+	}
+}
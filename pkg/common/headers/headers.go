@@ -0,0 +1,173 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package headers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+)
+
+const (
+	// CloudEventHeaderPrefix is the prefix of the Kafka headers holding CloudEvent attributes & extensions (binary mode).
+	CloudEventHeaderPrefix = "ce_"
+	// ContentTypeHeader is the Kafka header holding the datacontenttype (binary mode).
+	ContentTypeHeader = "content-type"
+)
+
+// Characters which are not valid in CloudEvent extension names.
+var invalidExtensionCharacters = regexp.MustCompile(`[^a-z0-9]`)
+
+// Policy controls which Kafka record headers are propagated into CloudEvent extensions when records are consumed,
+// and which extensions are written back as Kafka record headers when events are produced.  The CloudEvent attribute
+// headers (ce_*) and content-type are never subject to the Policy.
+//
+// A header is propagated if it matches the Allow list (or the Allow list is empty) and does not match the Deny list,
+// where each entry is either an exact header name or a prefix ending in "*".  The header's StripPrefix (if present)
+// is removed, and the remainder lowercased with characters other than a-z and 0-9 removed, before being appended to
+// the ExtensionPrefix to form the extension name.  When producing, the extensions starting with the ExtensionPrefix
+// are reversed into headers by replacing the ExtensionPrefix with the StripPrefix.  Header names are therefore only
+// reproduced exactly if they are lowercase alphanumeric (after the StripPrefix).
+//
+// A nil or disabled *Policy is valid and propagates no headers.
+type Policy struct {
+	Enabled         bool     `json:"enabled,omitempty"`
+	Allow           []string `json:"allow,omitempty"`
+	Deny            []string `json:"deny,omitempty"`
+	StripPrefix     string   `json:"stripPrefix,omitempty"`
+	ExtensionPrefix string   `json:"extensionPrefix,omitempty"`
+}
+
+// Parse returns the valid Policy of the specified JSON (a nil Policy if empty).
+func Parse(value string) (*Policy, error) {
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+	policy := &Policy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("invalid headers policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate returns an error if the Policy's patterns or ExtensionPrefix are invalid.
+func (p *Policy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, pattern := range append(append([]string{}, p.Allow...), p.Deny...) {
+		if len(pattern) == 0 || strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+			return fmt.Errorf("invalid header pattern %q: expected a header name or a prefix ending in '*'", pattern)
+		}
+	}
+	if invalidExtensionCharacters.MatchString(p.ExtensionPrefix) {
+		return fmt.Errorf("invalid extensionPrefix %q: only lowercase letters and digits are allowed", p.ExtensionPrefix)
+	}
+	return nil
+}
+
+// Allowed returns true if the specified header is propagated by the Policy.
+func (p *Policy) Allowed(header string) bool {
+	if p == nil || !p.Enabled || strings.HasPrefix(header, CloudEventHeaderPrefix) || header == ContentTypeHeader {
+		return false
+	}
+	if len(p.Allow) > 0 && !matchesAny(header, p.Allow) {
+		return false
+	}
+	return !matchesAny(header, p.Deny)
+}
+
+// ExtensionName returns the name of the CloudEvent extension into which the specified header is propagated (false
+// if the header is not propagated by the Policy).
+func (p *Policy) ExtensionName(header string) (string, bool) {
+	if !p.Allowed(header) {
+		return "", false
+	}
+	name := invalidExtensionCharacters.ReplaceAllString(strings.ToLower(strings.TrimPrefix(header, p.StripPrefix)), "")
+	if len(name) == 0 {
+		return "", false
+	}
+	return p.ExtensionPrefix + name, true
+}
+
+// HeaderName returns the name of the Kafka header into which the specified CloudEvent extension is written back
+// (false if the extension was not propagated from a header by the Policy).
+func (p *Policy) HeaderName(extension string) (string, bool) {
+	if p == nil || !p.Enabled || !strings.HasPrefix(extension, p.ExtensionPrefix) || len(extension) == len(p.ExtensionPrefix) {
+		return "", false
+	}
+	header := p.StripPrefix + strings.TrimPrefix(extension, p.ExtensionPrefix)
+	if !p.Allowed(header) {
+		return "", false
+	}
+	return header, true
+}
+
+// Transformers returns the transformers which add the CloudEvent extensions of the specified consumed headers.
+func (p *Policy) Transformers(headers []*sarama.RecordHeader) binding.Transformers {
+	var transformers binding.Transformers
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+		if extension, ok := p.ExtensionName(string(header.Key)); ok {
+			transformers = append(transformers, transformer.AddExtension(extension, string(header.Value)))
+		}
+	}
+	return transformers
+}
+
+// ProducerHeaders returns the Kafka headers written back from the CloudEvent extension headers (ce_*) of the
+// specified produced headers.
+func (p *Policy) ProducerHeaders(headers []sarama.RecordHeader) []sarama.RecordHeader {
+	if p == nil || !p.Enabled {
+		return nil
+	}
+	var producerHeaders []sarama.RecordHeader
+	for _, header := range headers {
+		key := string(header.Key)
+		if !strings.HasPrefix(key, CloudEventHeaderPrefix) {
+			continue
+		}
+		if name, ok := p.HeaderName(strings.TrimPrefix(key, CloudEventHeaderPrefix)); ok {
+			producerHeaders = append(producerHeaders, sarama.RecordHeader{Key: []byte(name), Value: header.Value})
+		}
+	}
+	return producerHeaders
+}
+
+// Utility function for determining whether a header matches any of the specified names or prefixes ending in "*".
+func matchesAny(header string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "*") {
+			if strings.HasPrefix(header, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		} else if header == pattern {
+			return true
+		}
+	}
+	return false
+}
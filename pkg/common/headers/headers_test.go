@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package headers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	policy, err := Parse("")
+	assert.Nil(t, err)
+	assert.Nil(t, policy)
+
+	policy, err = Parse(`{"enabled": true, "allow": ["x-*"], "stripPrefix": "x-", "extensionPrefix": "kafka"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, &Policy{Enabled: true, Allow: []string{"x-*"}, StripPrefix: "x-", ExtensionPrefix: "kafka"}, policy)
+
+	_, err = Parse("not json")
+	assert.NotNil(t, err)
+	_, err = Parse(`{"enabled": true, "extensionPrefix": "Kafka-"}`)
+	assert.NotNil(t, err)
+}
+
+func TestPolicyValidate(t *testing.T) {
+	var nilPolicy *Policy
+	assert.Nil(t, nilPolicy.Validate())
+	assert.Nil(t, (&Policy{Allow: []string{"x-*", "*", "exact"}, Deny: []string{"x-secret"}, ExtensionPrefix: "kafkaheader"}).Validate())
+	assert.NotNil(t, (&Policy{Allow: []string{""}}).Validate())
+	assert.NotNil(t, (&Policy{Deny: []string{"x-*-secret"}}).Validate())
+	assert.NotNil(t, (&Policy{ExtensionPrefix: "Kafka-Header"}).Validate())
+}
+
+func TestPolicyNames(t *testing.T) {
+	testCases := map[string]struct {
+		policy            *Policy
+		header            string
+		expectedExtension string
+		expectedHeader    string
+	}{
+		"nil policy": {
+			header: "x-tenant",
+		},
+		"disabled policy": {
+			policy: &Policy{ExtensionPrefix: "kh"},
+			header: "x-tenant",
+		},
+		"all headers": {
+			policy:            &Policy{Enabled: true, ExtensionPrefix: "kh"},
+			header:            "tenant",
+			expectedExtension: "khtenant",
+			expectedHeader:    "tenant",
+		},
+		"cloudevent header": {
+			policy: &Policy{Enabled: true},
+			header: "ce_type",
+		},
+		"content type header": {
+			policy: &Policy{Enabled: true},
+			header: "content-type",
+		},
+		"allowed prefix": {
+			policy:            &Policy{Enabled: true, Allow: []string{"x-*"}, StripPrefix: "x-", ExtensionPrefix: "kh"},
+			header:            "x-tenant",
+			expectedExtension: "khtenant",
+			expectedHeader:    "x-tenant",
+		},
+		"not allowed": {
+			policy: &Policy{Enabled: true, Allow: []string{"x-*"}},
+			header: "tenant",
+		},
+		"denied": {
+			policy: &Policy{Enabled: true, Allow: []string{"x-*"}, Deny: []string{"x-secret"}},
+			header: "x-secret",
+		},
+		"sanitized name": {
+			policy:            &Policy{Enabled: true, ExtensionPrefix: "kh"},
+			header:            "Tenant_ID",
+			expectedExtension: "khtenantid",
+			expectedHeader:    "tenantid",
+		},
+		"empty name": {
+			policy: &Policy{Enabled: true, StripPrefix: "x-"},
+			header: "x-",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			extension, ok := tc.policy.ExtensionName(tc.header)
+			assert.Equal(t, len(tc.expectedExtension) > 0, ok)
+			assert.Equal(t, tc.expectedExtension, extension)
+			if ok {
+				header, ok := tc.policy.HeaderName(extension)
+				assert.True(t, ok)
+				assert.Equal(t, tc.expectedHeader, header)
+			}
+		})
+	}
+
+	// Extensions Without The ExtensionPrefix Are Not Written Back
+	policy := &Policy{Enabled: true, ExtensionPrefix: "kh"}
+	_, ok := policy.HeaderName("partitionkey")
+	assert.False(t, ok)
+	_, ok = policy.HeaderName("kh")
+	assert.False(t, ok)
+}
+
+func TestPolicyTransformers(t *testing.T) {
+	policy := &Policy{Enabled: true, Deny: []string{"traceparent"}, StripPrefix: "x-", ExtensionPrefix: "kh"}
+	headers := []*sarama.RecordHeader{
+		{Key: []byte("ce_type"), Value: []byte("type")},
+		{Key: []byte("content-type"), Value: []byte("application/json")},
+		{Key: []byte("traceparent"), Value: []byte("00-1")},
+		{Key: []byte("x-tenant"), Value: []byte("acme")},
+		nil,
+	}
+
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	transformed, err := binding.ToEvent(context.TODO(), binding.ToMessage(&e), policy.Transformers(headers)...)
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"khtenant": "acme"}, transformed.Extensions())
+
+	var nilPolicy *Policy
+	assert.Empty(t, nilPolicy.Transformers(headers))
+}
+
+func TestPolicyProducerHeaders(t *testing.T) {
+	policy := &Policy{Enabled: true, Allow: []string{"x-*"}, StripPrefix: "x-", ExtensionPrefix: "kh"}
+	headers := []sarama.RecordHeader{
+		{Key: []byte("ce_type"), Value: []byte("type")},
+		{Key: []byte("ce_khtenant"), Value: []byte("acme")},
+		{Key: []byte("ce_partitionkey"), Value: []byte("key")},
+		{Key: []byte("khother"), Value: []byte("other")},
+	}
+
+	assert.Equal(t, []sarama.RecordHeader{{Key: []byte("x-tenant"), Value: []byte("acme")}}, policy.ProducerHeaders(headers))
+
+	var nilPolicy *Policy
+	assert.Nil(t, nilPolicy.ProducerHeaders(headers))
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// The Relative Precision Of The Histogram's Logarithmic Buckets (1%)
+const bucketGrowth = 1.01
+
+// LatencySummary Is The JSON Summary Of A Histogram's Latencies (In Milliseconds)
+type LatencySummary struct {
+	Min  float64 `json:"minMillis"`
+	Mean float64 `json:"meanMillis"`
+	P50  float64 `json:"p50Millis"`
+	P90  float64 `json:"p90Millis"`
+	P99  float64 `json:"p99Millis"`
+	P999 float64 `json:"p999Millis"`
+	Max  float64 `json:"maxMillis"`
+}
+
+//
+// Latency Histogram
+//
+// Latencies are counted in logarithmic buckets of 1% relative precision (from a microsecond), so that the memory
+// of a soak run is bounded (a few thousand buckets cover an hour) however many events are recorded, while the
+// percentiles are accurate to 1%.  The Histogram is safe for concurrent use.
+//
+type Histogram struct {
+	counts map[int]int64
+	count  int64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+	lock   sync.Mutex
+}
+
+// Histogram Constructor
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make(map[int]int64)}
+}
+
+// Record The Specified Latency (Negative Latencies, e.g. Due To Clock Skew, Are Recorded As Zero)
+func (h *Histogram) Record(latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.counts[bucket(latency)]++
+	if h.count == 0 || latency < h.min {
+		h.min = latency
+	}
+	if latency > h.max {
+		h.max = latency
+	}
+	h.count++
+	h.sum += latency
+}
+
+// Get The Number Of Recorded Latencies
+func (h *Histogram) Count() int64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.count
+}
+
+// Get The Latency Below Which The Specified Percentage Of The Recorded Latencies Fall (Zero If None Were Recorded)
+func (h *Histogram) Percentile(percent float64) time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.percentile(percent)
+}
+
+// Summarize The Recorded Latencies
+func (h *Histogram) Summary() LatencySummary {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.count == 0 {
+		return LatencySummary{}
+	}
+	return LatencySummary{
+		Min:  millis(h.min),
+		Mean: millis(h.sum / time.Duration(h.count)),
+		P50:  millis(h.percentile(50)),
+		P90:  millis(h.percentile(90)),
+		P99:  millis(h.percentile(99)),
+		P999: millis(h.percentile(99.9)),
+		Max:  millis(h.max),
+	}
+}
+
+// Get The Percentile (The Caller Must Hold The Lock) As The Upper Bound Of Its Bucket, Within The Min & Max
+func (h *Histogram) percentile(percent float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	buckets := make([]int, 0, len(h.counts))
+	for index := range h.counts {
+		buckets = append(buckets, index)
+	}
+	sort.Ints(buckets)
+	rank := int64(math.Ceil(percent / 100 * float64(h.count)))
+	var cumulative int64
+	for _, index := range buckets {
+		cumulative += h.counts[index]
+		if cumulative >= rank {
+			latency := time.Duration(math.Pow(bucketGrowth, float64(index)) * float64(time.Microsecond))
+			if latency < h.min {
+				return h.min
+			} else if latency > h.max {
+				return h.max
+			}
+			return latency
+		}
+	}
+	return h.max
+}
+
+// Get The Index Of The Bucket Counting The Specified Latency
+func bucket(latency time.Duration) int {
+	if latency <= time.Microsecond {
+		return 0
+	}
+	return int(math.Ceil(math.Log(float64(latency)/float64(time.Microsecond)) / math.Log(bucketGrowth)))
+}
+
+// Convert The Specified Duration To (Fractional) Milliseconds
+func millis(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
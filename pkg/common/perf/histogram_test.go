@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test The Histogram's Percentiles & Summary
+func TestHistogram(t *testing.T) {
+
+	// An Empty Histogram Summarizes As Zero
+	histogram := NewHistogram()
+	assert.Equal(t, LatencySummary{}, histogram.Summary())
+	assert.Equal(t, time.Duration(0), histogram.Percentile(50))
+
+	// Record 1ms To 1000ms (And A Negative Latency, Recorded As Zero)
+	for i := 1; i <= 1000; i++ {
+		histogram.Record(time.Duration(i) * time.Millisecond)
+	}
+	histogram.Record(-time.Second)
+	assert.Equal(t, int64(1001), histogram.Count())
+
+	// Verify The Percentiles Within The Buckets' 1% Precision
+	testCases := []struct {
+		percent float64
+		want    time.Duration
+	}{
+		{percent: 50, want: 500 * time.Millisecond},
+		{percent: 90, want: 900 * time.Millisecond},
+		{percent: 99, want: 990 * time.Millisecond},
+		{percent: 100, want: time.Second},
+	}
+	for _, testCase := range testCases {
+		assert.InEpsilon(t, float64(testCase.want), float64(histogram.Percentile(testCase.percent)), 0.01)
+	}
+
+	// Verify The Summary
+	summary := histogram.Summary()
+	assert.Equal(t, float64(0), summary.Min)
+	assert.Equal(t, float64(1000), summary.Max)
+	assert.InEpsilon(t, 500.0, summary.Mean, 0.01)
+	assert.InEpsilon(t, 999.0, summary.P999, 0.01)
+}
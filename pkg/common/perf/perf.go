@@ -0,0 +1,271 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// The Modes Of A Run
+const (
+	ModeBoth    = "both"    // Send Events To The Target & Receive Them As Its Sink
+	ModeSend    = "send"    // Only Send Events To The Target (Their Sink Being Another Run's Receiver)
+	ModeReceive = "receive" // Only Receive Events (Sent By Another Run)
+)
+
+// The Default Time Allowed For The Events Sent To Be Received Once Sending Has Finished
+const DefaultDrainTimeout = 30 * time.Second
+
+// Config Is The Configuration Of A Run
+type Config struct {
+	Mode           string        // One Of The Modes (Defaults To ModeBoth)
+	Target         string        // The URL To Which Events Are Sent (Defaults To The Receiver Itself In ModeBoth)
+	SinkAddress    string        // The Address On Which The Receiver Listens (e.g. ":8080")
+	RunId          string        // The Id Of The Run (The Receiver Counts Any Run's Events If Empty In ModeReceive)
+	Rate           int           // Events Per Second
+	Duration       time.Duration // How Long To Send For (Or Receive For In ModeReceive, Until Interrupted If Zero)
+	Concurrency    int           // The Maximum Number Of Events Being Sent Concurrently
+	PayloadSize    int           // The Size (In Bytes) Of Each Event's Data
+	DrainTimeout   time.Duration // How Long To Wait For The Sent Events To Be Received (ModeBoth)
+	ReportInterval time.Duration // The Interval Between Interim Results (None If Zero)
+}
+
+// Results Are The (JSON) Results Of A Run, Also Written As Interim Results While It Is Running
+type Results struct {
+	RunId    string           `json:"runId,omitempty"`
+	Mode     string           `json:"mode"`
+	Target   string           `json:"target,omitempty"`
+	Interim  bool             `json:"interim,omitempty"`
+	Elapsed  float64          `json:"elapsedSeconds"`
+	Rate     int              `json:"rate,omitempty"` // The Configured Rate, The Achieved Rate Being The Sent Throughput
+	Sent     *SenderResults   `json:"sent,omitempty"`
+	Received *ReceiverResults `json:"received,omitempty"`
+	Lost     *int64           `json:"lost,omitempty"` // The Events Sent Successfully But Not Received (ModeBoth)
+}
+
+// Validate The Config & Apply Its Defaults
+func (c *Config) Validate() error {
+	if len(c.Mode) == 0 {
+		c.Mode = ModeBoth
+	}
+	if c.Mode != ModeBoth && c.Mode != ModeSend && c.Mode != ModeReceive {
+		return fmt.Errorf("invalid mode %q (expected %q, %q or %q)", c.Mode, ModeBoth, ModeSend, ModeReceive)
+	}
+	if c.Mode != ModeReceive {
+		if c.Mode == ModeSend && len(c.Target) == 0 {
+			return errors.New("a target is required to send events")
+		}
+		if c.Rate <= 0 || c.Duration <= 0 || c.Concurrency <= 0 {
+			return errors.New("the rate, duration & concurrency must be positive to send events")
+		}
+		if c.PayloadSize < 0 {
+			return errors.New("the payload size must not be negative")
+		}
+	}
+	if c.Mode != ModeSend && len(c.SinkAddress) == 0 {
+		return errors.New("a sink address is required to receive events")
+	}
+	if c.DrainTimeout <= 0 {
+		c.DrainTimeout = DefaultDrainTimeout
+	}
+	return nil
+}
+
+//
+// Run The Load Generator Per The Specified Config
+//
+// Events are sent to the target at the configured rate (each sent by the next of the concurrent workers, the
+// achieved rate falling short when all are busy) and/or received by a sink Receiver listening on the sink address.
+// When both sending & receiving, the run waits up to the drain timeout for the sent events to be received once
+// sending has finished, and the events still not received are reported as lost.  The results are written to the
+// output as a line of JSON, preceded by interim results every report interval (for soak runs), and returned.
+//
+func Run(ctx context.Context, logger *zap.Logger, config Config, output io.Writer) (*Results, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	started := time.Now()
+
+	// Start The Receiver (Defaulting The Target To It When Both Sending & Receiving)
+	var receiver *Receiver
+	if config.Mode != ModeSend {
+		listener, err := net.Listen("tcp", config.SinkAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on the sink address %s: %w", config.SinkAddress, err)
+		}
+		receiver = NewReceiver(logger, config.RunId)
+		server := &http.Server{Handler: receiver}
+		go func() { _ = server.Serve(listener) }()
+		defer func() { _ = server.Close() }()
+		logger.Info("Receiving Events", zap.String("Address", listener.Addr().String()))
+		if config.Mode == ModeBoth && len(config.Target) == 0 {
+			config.Target = "http://" + listener.Addr().String()
+		}
+	}
+
+	// Create The Sender
+	var sender *Sender
+	if config.Mode != ModeReceive {
+		client := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: config.Concurrency}}
+		sender = NewSender(logger, client, config.Target, config.RunId, config.PayloadSize)
+	}
+
+	// Write Interim Results Every Report Interval Until The Run Is Done
+	encoder := json.NewEncoder(output)
+	var encoderLock sync.Mutex
+	results := func(interim bool) *Results {
+		return newResults(config, started, interim, sender, receiver)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	if config.ReportInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(config.ReportInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					encoderLock.Lock()
+					_ = encoder.Encode(results(true))
+					encoderLock.Unlock()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	// Send The Events, Or Receive Them For The Duration (Or Until Interrupted)
+	if sender != nil {
+		logger.Info("Sending Events", zap.String("Target", config.Target), zap.Int("Rate", config.Rate), zap.Duration("Duration", config.Duration))
+		send(ctx, config, sender)
+	} else {
+		receiveCtx, cancel := ctx, context.CancelFunc(func() {})
+		if config.Duration > 0 {
+			receiveCtx, cancel = context.WithTimeout(ctx, config.Duration)
+		}
+		<-receiveCtx.Done()
+		cancel()
+	}
+
+	// Wait For The Sent Events To Be Received
+	if sender != nil && receiver != nil {
+		drain(ctx, config.DrainTimeout, sender, receiver)
+	}
+
+	// Write The Final Results
+	finalResults := results(false)
+	encoderLock.Lock()
+	defer encoderLock.Unlock()
+	if err := encoder.Encode(finalResults); err != nil {
+		return nil, fmt.Errorf("failed to write the results: %w", err)
+	}
+	return finalResults, nil
+}
+
+// Send Events At The Configured Rate For The Configured Duration (Or Until The Context Is Done)
+func send(ctx context.Context, config Config, sender *Sender) {
+	sequences := make(chan uint64)
+	var workers sync.WaitGroup
+	for i := 0; i < config.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for sequence := range sequences {
+				_ = sender.Send(ctx, sequence)
+			}
+		}()
+	}
+
+	// Pace The Events By Their Scheduled Send Times (Not Drifting When Workers Were Busy)
+	interval := time.Second / time.Duration(config.Rate)
+	start := time.Now()
+	end := start.Add(config.Duration)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for sequence := uint64(0); ; sequence++ {
+		scheduled := start.Add(time.Duration(sequence) * interval)
+		if !scheduled.Before(end) {
+			break
+		}
+		timer.Reset(time.Until(scheduled))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case sequences <- sequence:
+		case <-ctx.Done():
+		}
+	}
+	close(sequences)
+	workers.Wait()
+}
+
+// Wait Until The Events Sent Successfully Have Been Received (Or The Drain Timeout / Context Is Done)
+func drain(ctx context.Context, drainTimeout time.Duration, sender *Sender, receiver *Receiver) {
+	ctx, cancel := context.WithTimeout(ctx, drainTimeout)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for receiver.Count() < sender.Succeeded() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Create The Results Of The Specified Sender & Receiver (Either Of Which May Be nil)
+func newResults(config Config, started time.Time, interim bool, sender *Sender, receiver *Receiver) *Results {
+	results := &Results{
+		RunId:   config.RunId,
+		Mode:    config.Mode,
+		Target:  config.Target,
+		Interim: interim,
+		Elapsed: time.Since(started).Seconds(),
+	}
+	if sender != nil {
+		results.Rate = config.Rate
+		results.Sent = sender.Results()
+	}
+	if receiver != nil {
+		results.Received = receiver.Results()
+	}
+	if sender != nil && receiver != nil && !interim {
+		lost := results.Sent.Count - results.Sent.Errors - results.Received.Count
+		if lost < 0 {
+			lost = 0
+		}
+		results.Lost = &lost
+	}
+	return results
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Config's Validation & Defaults
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "Both Defaulted", config: Config{SinkAddress: ":8080", Rate: 1, Duration: time.Second, Concurrency: 1}},
+		{name: "Receive", config: Config{Mode: ModeReceive, SinkAddress: ":8080"}},
+		{name: "Send", config: Config{Mode: ModeSend, Target: "http://target", Rate: 1, Duration: time.Second, Concurrency: 1}},
+		{name: "Invalid Mode", config: Config{Mode: "invalid"}, wantErr: "invalid mode"},
+		{name: "Send Without Target", config: Config{Mode: ModeSend, Rate: 1, Duration: time.Second, Concurrency: 1}, wantErr: "target is required"},
+		{name: "Zero Rate", config: Config{SinkAddress: ":8080", Duration: time.Second, Concurrency: 1}, wantErr: "must be positive"},
+		{name: "Negative Payload", config: Config{SinkAddress: ":8080", Rate: 1, Duration: time.Second, Concurrency: 1, PayloadSize: -1}, wantErr: "payload size"},
+		{name: "Receive Without Sink", config: Config{Mode: ModeReceive}, wantErr: "sink address is required"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.config.Validate()
+			if len(testCase.wantErr) > 0 {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error(), testCase.wantErr)
+			} else {
+				assert.Nil(t, err)
+				assert.NotEmpty(t, testCase.config.Mode)
+				assert.Equal(t, DefaultDrainTimeout, testCase.config.DrainTimeout)
+			}
+		})
+	}
+}
+
+// Test A Run Sending Events To Its Own Sink (The Loopback Baseline)
+func TestRunLoopback(t *testing.T) {
+
+	// Perform The Test
+	output := &bytes.Buffer{}
+	results, err := Run(context.TODO(), logtesting.TestLogger(t).Desugar(), Config{
+		SinkAddress:    "127.0.0.1:0",
+		RunId:          "test-run",
+		Rate:           200,
+		Duration:       500 * time.Millisecond,
+		Concurrency:    4,
+		PayloadSize:    64,
+		ReportInterval: 200 * time.Millisecond,
+	}, output)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.NotNil(t, results)
+	assert.Equal(t, ModeBoth, results.Mode)
+	assert.True(t, strings.HasPrefix(results.Target, "http://127.0.0.1:"))
+	assert.Equal(t, int64(100), results.Sent.Count)
+	assert.Equal(t, int64(0), results.Sent.Errors)
+	assert.Equal(t, int64(100), results.Received.Count)
+	assert.Equal(t, int64(0), *results.Lost)
+
+	// Verify The Interim Results Preceded The Final Results On The Output
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.True(t, len(lines) >= 2)
+	interim, final := &Results{}, &Results{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), interim))
+	assert.True(t, interim.Interim)
+	assert.Nil(t, json.Unmarshal([]byte(lines[len(lines)-1]), final))
+	assert.False(t, final.Interim)
+	assert.Equal(t, int64(100), final.Received.Count)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+)
+
+// ReceiverResults Are The Counts, Throughput & End-To-End Latencies Of The Events Received By A Receiver
+type ReceiverResults struct {
+	Count      int64          `json:"count"`
+	Duplicates int64          `json:"duplicates"`
+	Throughput float64        `json:"throughput"` // Events Per Second Between The First & Last Received
+	Latency    LatencySummary `json:"latency"`    // From Being Sent To Being Received
+}
+
+//
+// The Sink Receiver Of The Load Generator's Events
+//
+// The Receiver is an HTTP handler accepting the events sent by Senders (in binary or structured mode), measuring
+// the latency from their send time to their receipt and counting duplicates (by run & sequence, so that its memory
+// is bounded in soak runs).  Events of other runs than the Receiver's (if any) or without the load generator's
+// extensions are acknowledged but otherwise ignored.  The latencies are only meaningful if the clocks of the
+// Sender & Receiver are synchronized, which is trivially the case when both run in the same process.
+//
+type Receiver struct {
+	logger   *zap.Logger
+	runId    string
+	now      func() time.Time
+	latency  *Histogram
+	received map[string][]uint64 // Bitsets Of The Received Sequences, Keyed By Run
+	count    int64
+	dupes    int64
+	first    time.Time
+	last     time.Time
+	lock     sync.Mutex
+}
+
+// Receiver Constructor - Events Of Any Run Are Counted If The RunId Is Empty
+func NewReceiver(logger *zap.Logger, runId string) *Receiver {
+	return &Receiver{
+		logger:   logger,
+		runId:    runId,
+		now:      time.Now,
+		latency:  NewHistogram(),
+		received: make(map[string][]uint64),
+	}
+}
+
+// Receive An Event (Implements http.Handler)
+func (r *Receiver) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	receivedAt := r.now()
+	event, err := binding.ToEvent(request.Context(), cehttp.NewMessageFromHttpRequest(request))
+	if err != nil {
+		r.logger.Debug("Received An Invalid CloudEvent", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	writer.WriteHeader(http.StatusAccepted)
+
+	// Ignore Events Of Other Runs Or Without The Load Generator's Extensions
+	extensions := event.Extensions()
+	runId, _ := extensions[RunIdExtension].(string)
+	sequenceString, _ := extensions[SequenceExtension].(string)
+	sentAtString, _ := extensions[SentAtExtension].(string)
+	sequence, sequenceErr := strconv.ParseUint(sequenceString, 10, 64)
+	sentAt, sentAtErr := time.Parse(time.RFC3339Nano, sentAtString)
+	if len(runId) == 0 || (len(r.runId) > 0 && runId != r.runId) || sequenceErr != nil || sentAtErr != nil {
+		r.logger.Debug("Ignoring Foreign CloudEvent", zap.String("ID", event.ID()), zap.String("RunId", runId))
+		return
+	}
+
+	// Count The Event (Or Duplicate) & Record Its Latency
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.markReceived(runId, sequence) {
+		r.dupes++
+		return
+	}
+	r.latency.Record(receivedAt.Sub(sentAt))
+	if r.count == 0 {
+		r.first = receivedAt
+	}
+	r.last = receivedAt
+	r.count++
+}
+
+// Get The Number Of Distinct Events Received
+func (r *Receiver) Count() int64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.count
+}
+
+// Get The Results Of The Events Received So Far
+func (r *Receiver) Results() *ReceiverResults {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return &ReceiverResults{
+		Count:      r.count,
+		Duplicates: r.dupes,
+		Throughput: throughput(r.count, r.last.Sub(r.first)),
+		Latency:    r.latency.Summary(),
+	}
+}
+
+// Mark The Specified Sequence Of The Specified Run As Received (The Caller Must Hold The Lock), Returning Whether
+// It Was Not Already Received
+func (r *Receiver) markReceived(runId string, sequence uint64) bool {
+	bits := r.received[runId]
+	word, mask := sequence/64, uint64(1)<<(sequence%64)
+	for uint64(len(bits)) <= word {
+		bits = append(bits, 0)
+	}
+	r.received[runId] = bits
+	if bits[word]&mask != 0 {
+		return false
+	}
+	bits[word] |= mask
+	return true
+}
+
+// Calculate The Throughput (Per Second) Of The Specified Count Of Events Over The Specified Duration
+func throughput(count int64, duration time.Duration) float64 {
+	if count == 0 {
+		return 0
+	} else if duration <= 0 {
+		return float64(count)
+	}
+	return float64(count) / duration.Seconds()
+}
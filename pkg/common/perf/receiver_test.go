@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Sender & Receiver's Counting Of Events, Duplicates, Foreign Runs & Errors
+func TestSenderReceiver(t *testing.T) {
+
+	// Create A Receiver Of The Test Run & A Server For It
+	logger := logtesting.TestLogger(t).Desugar()
+	receiver := NewReceiver(logger, "test-run")
+	receiver.now = func() time.Time { return time.Now().Add(10 * time.Millisecond) }
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	// Send Events 0-4, Event 2 Again & An Event Of Another Run
+	sender := NewSender(logger, server.Client(), server.URL, "test-run", 16)
+	for _, sequence := range []uint64{0, 1, 2, 3, 4, 2} {
+		assert.Nil(t, sender.Send(context.TODO(), sequence))
+	}
+	assert.Nil(t, NewSender(logger, server.Client(), server.URL, "other-run", 16).Send(context.TODO(), 0))
+
+	// Verify The Sender's Results
+	assert.Equal(t, int64(6), sender.Succeeded())
+	sent := sender.Results()
+	assert.Equal(t, int64(6), sent.Count)
+	assert.Equal(t, int64(0), sent.Errors)
+	assert.True(t, sent.Throughput > 0)
+
+	// Verify The Receiver's Results (The Duplicate Counted As Such, The Other Run Ignored)
+	assert.Equal(t, int64(5), receiver.Count())
+	received := receiver.Results()
+	assert.Equal(t, int64(5), received.Count)
+	assert.Equal(t, int64(1), received.Duplicates)
+	assert.True(t, received.Latency.Min >= 10)
+
+	// Verify An Invalid CloudEvent Is Rejected
+	response, err := http.Post(server.URL, "text/plain", strings.NewReader("not an event"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	_ = response.Body.Close()
+}
+
+// Test The Sender Counts Non-2xx Responses As Errors
+func TestSenderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	sender := NewSender(logtesting.TestLogger(t).Desugar(), server.Client(), server.URL, "test-run", 0)
+	assert.NotNil(t, sender.Send(context.TODO(), 0))
+	assert.Equal(t, int64(0), sender.Succeeded())
+	sent := sender.Results()
+	assert.Equal(t, int64(1), sent.Count)
+	assert.Equal(t, int64(1), sent.Errors)
+	assert.Equal(t, LatencySummary{}, sent.Latency)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+)
+
+// The Attributes Of The Load Generator's Events
+const (
+	EventSource = "knative.dev/eventing-kafka/cmd/perf"
+	EventType   = "dev.knative.eventing.kafka.perf"
+
+	RunIdExtension    = "perfrunid"    // The Run Which Sent The Event
+	SequenceExtension = "perfsequence" // The Sequence Number Of The Event Within Its Run (From Zero)
+	SentAtExtension   = "perfsentat"   // The (RFC3339Nano) Time At Which The Event Was Sent
+)
+
+// SenderResults Are The Counts, Throughput & Publish Latencies Of The Events Sent By A Sender
+type SenderResults struct {
+	Count      int64          `json:"count"`
+	Errors     int64          `json:"errors"`
+	Throughput float64        `json:"throughput"` // Events Per Second Since The Sender Started
+	Latency    LatencySummary `json:"latency"`    // Of The Successful Sends (Until The Target's Response)
+}
+
+//
+// The Load Generator's Event Sender
+//
+// The Sender POSTs CloudEvents (in binary mode) with a payload of the configured size to the target, which is the
+// address of a KafkaChannel, a KafkaSink or a receiver producing to the topic of a KafkaSource.  Each event carries
+// the run id, its sequence number & send time as extensions, by which Receivers measure the end-to-end latency.
+// Sends answered with anything but a 2xx are counted as errors.  The Sender is safe for concurrent use.
+//
+type Sender struct {
+	logger  *zap.Logger
+	client  *http.Client
+	target  string
+	runId   string
+	payload []byte
+	now     func() time.Time
+	latency *Histogram
+	started time.Time
+	count   int64
+	errors  int64
+	lock    sync.Mutex
+}
+
+// Sender Constructor
+func NewSender(logger *zap.Logger, client *http.Client, target string, runId string, payloadSize int) *Sender {
+	return &Sender{
+		logger:  logger,
+		client:  client,
+		target:  target,
+		runId:   runId,
+		payload: bytes.Repeat([]byte("x"), payloadSize),
+		now:     time.Now,
+		latency: NewHistogram(),
+		started: time.Now(),
+	}
+}
+
+// Send The Event With The Specified Sequence Number
+func (s *Sender) Send(ctx context.Context, sequence uint64) error {
+	sentAt := s.now()
+	err := s.send(ctx, sequence, sentAt)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.count++
+	if err != nil {
+		s.errors++
+		s.logger.Debug("Failed To Send Event", zap.Uint64("Sequence", sequence), zap.Error(err))
+		return err
+	}
+	s.latency.Record(s.now().Sub(sentAt))
+	return nil
+}
+
+// Get The Number Of Events Sent Successfully
+func (s *Sender) Succeeded() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.count - s.errors
+}
+
+// Get The Results Of The Events Sent So Far
+func (s *Sender) Results() *SenderResults {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return &SenderResults{
+		Count:      s.count,
+		Errors:     s.errors,
+		Throughput: throughput(s.count, s.now().Sub(s.started)),
+		Latency:    s.latency.Summary(),
+	}
+}
+
+// Send The Event With The Specified Sequence Number & Send Time To The Target
+func (s *Sender) send(ctx context.Context, sequence uint64, sentAt time.Time) error {
+
+	// Create The Event
+	event := cloudevents.NewEvent()
+	event.SetID(s.runId + "-" + strconv.FormatUint(sequence, 10))
+	event.SetSource(EventSource)
+	event.SetType(EventType)
+	event.SetTime(sentAt)
+	event.SetExtension(RunIdExtension, s.runId)
+	event.SetExtension(SequenceExtension, strconv.FormatUint(sequence, 10))
+	event.SetExtension(SentAtExtension, sentAt.UTC().Format(time.RFC3339Nano))
+	if err := event.SetData("text/plain", s.payload); err != nil {
+		return err
+	}
+
+	// Write The Event To A Binary Mode Request & POST It To The Target
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.target, nil)
+	if err != nil {
+		return err
+	}
+	if err = cehttp.WriteRequest(ctx, binding.ToMessage(&event), request); err != nil {
+		return err
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("target responded with status %d", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// The Keys Of The Heap Profiler's Settings In The Observability ConfigMap (Alongside knative's "profiling.enable")
+const (
+	HeapWatermarkKey     = "profiling.heap-watermark"      // The Heap Size (Quantity, e.g. "512Mi") Above Which Profiles Are Captured (Disabled If Unset)
+	HeapProfileDirKey    = "profiling.heap-profile-dir"    // The Directory To Which Profiles Are Written
+	HeapMaxProfilesKey   = "profiling.heap-max-profiles"   // The Number Of Most Recent Profiles Kept
+	HeapCheckIntervalKey = "profiling.heap-check-interval" // The (Go Duration) Interval Between Checks Of The Heap Size
+)
+
+// The Heap Profiler's Defaults
+const (
+	DefaultHeapMaxProfiles   = 5
+	DefaultHeapCheckInterval = 30 * time.Second
+)
+
+// The Growth Beyond The Last Captured Heap Size Which Is Captured Again (While Above The Watermark)
+const heapGrowthFactor = 1.25
+
+// The Prefix & Suffix Of The Captured Profiles' File Names
+const (
+	heapProfilePrefix = "heap-"
+	heapProfileSuffix = ".pb.gz"
+)
+
+// HeapConfig Is The Heap Profiler's Configuration, Parsed From The Observability ConfigMap
+type HeapConfig struct {
+	Watermark     uint64
+	Directory     string
+	MaxProfiles   int
+	CheckInterval time.Duration
+}
+
+// The Default Directory To Which Profiles Are Written
+func DefaultHeapProfileDir() string {
+	return filepath.Join(os.TempDir(), "heap-profiles")
+}
+
+// Parse The Heap Profiler's Configuration From The Specified (Observability ConfigMap) Data
+func NewHeapConfigFromMap(data map[string]string) (*HeapConfig, error) {
+	config := &HeapConfig{
+		Directory:     DefaultHeapProfileDir(),
+		MaxProfiles:   DefaultHeapMaxProfiles,
+		CheckInterval: DefaultHeapCheckInterval,
+	}
+	if value := strings.TrimSpace(data[HeapWatermarkKey]); len(value) > 0 {
+		watermark, err := resource.ParseQuantity(value)
+		if err != nil || watermark.Sign() < 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a non-negative quantity (e.g. 512Mi)", HeapWatermarkKey, value)
+		}
+		config.Watermark = uint64(watermark.Value())
+	}
+	if value := strings.TrimSpace(data[HeapProfileDirKey]); len(value) > 0 {
+		config.Directory = value
+	}
+	if value := strings.TrimSpace(data[HeapMaxProfilesKey]); len(value) > 0 {
+		maxProfiles, err := strconv.Atoi(value)
+		if err != nil || maxProfiles <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a positive integer", HeapMaxProfilesKey, value)
+		}
+		config.MaxProfiles = maxProfiles
+	}
+	if value := strings.TrimSpace(data[HeapCheckIntervalKey]); len(value) > 0 {
+		checkInterval, err := time.ParseDuration(value)
+		if err != nil || checkInterval <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a positive duration (e.g. 30s)", HeapCheckIntervalKey, value)
+		}
+		config.CheckInterval = checkInterval
+	}
+	return config, nil
+}
+
+//
+// Heap Profiler
+//
+// The HeapProfiler periodically checks the size of the heap and, once it exceeds the configured watermark, writes a
+// heap profile to the configured directory (from which it can be copied with "kubectl cp").  Further profiles are
+// only captured as the heap reaches new highs (25% above the last captured size), until it falls back below the
+// watermark, and only the most recent profiles are kept.  This captures the growth of a leaking component without
+// the pprof endpoints having been enabled (and scraped) beforehand.  It is disabled until a watermark is configured.
+//
+type HeapProfiler struct {
+	logger    *zap.Logger
+	config    HeapConfig
+	threshold uint64 // The Heap Size Above Which The Next Profile Is Captured (Zero For The Watermark)
+	heapSize  func() uint64
+	now       func() time.Time
+	lock      sync.Mutex
+}
+
+// HeapProfiler Constructor (Disabled Until Configured)
+func NewHeapProfiler(logger *zap.Logger) *HeapProfiler {
+	config, _ := NewHeapConfigFromMap(nil)
+	return &HeapProfiler{
+		logger:   logger,
+		config:   *config,
+		heapSize: heapSize,
+		now:      time.Now,
+	}
+}
+
+// Update The Configuration From The Specified (Observability ConfigMap) Data, Keeping The Current One If Invalid
+func (p *HeapProfiler) Update(data map[string]string) {
+	config, err := NewHeapConfigFromMap(data)
+	if err != nil {
+		p.logger.Error("Invalid Heap Profiler Configuration - Ignoring", zap.Error(err))
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if *config != p.config {
+		p.logger.Info("Heap Profiler Configured", zap.Uint64("Watermark", config.Watermark), zap.String("Directory", config.Directory),
+			zap.Int("MaxProfiles", config.MaxProfiles), zap.Duration("CheckInterval", config.CheckInterval))
+		p.config = *config
+		p.threshold = 0
+	}
+}
+
+// Update The Configuration From The Specified Observability ConfigMap (A configmap.Observer)
+func (p *HeapProfiler) UpdateFromConfigMap(configMap *corev1.ConfigMap) {
+	p.Update(configMap.Data)
+}
+
+// Start Checking The Heap Size Every Check Interval Until The Context Is Done (Non-Blocking)
+func (p *HeapProfiler) Start(ctx context.Context) {
+	go func() {
+		for {
+			p.lock.Lock()
+			checkInterval := p.config.CheckInterval
+			p.lock.Unlock()
+			select {
+			case <-time.After(checkInterval):
+				p.check()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Check The Heap Size, Capturing A Profile If It Exceeds The Watermark & Is A New High
+func (p *HeapProfiler) check() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.config.Watermark == 0 {
+		return
+	}
+
+	// Re-Arm At The Watermark Once Back Below It
+	size := p.heapSize()
+	if size < p.config.Watermark {
+		p.threshold = 0
+		return
+	}
+	if p.threshold > 0 && size < p.threshold {
+		return
+	}
+
+	// Capture A Profile & Raise The Threshold Beyond The Current Size
+	path, err := p.capture()
+	if err != nil {
+		p.logger.Error("Failed To Capture Heap Profile", zap.Uint64("HeapSize", size), zap.Error(err))
+		return
+	}
+	p.threshold = uint64(float64(size) * heapGrowthFactor)
+	p.logger.Warn("Heap Size Exceeded Watermark - Captured Heap Profile", zap.Uint64("HeapSize", size),
+		zap.Uint64("Watermark", p.config.Watermark), zap.String("Path", path))
+	p.prune()
+}
+
+// Write A Heap Profile To The Configured Directory (The Caller Must Hold The Lock), Returning Its Path
+func (p *HeapProfiler) capture() (string, error) {
+	if err := os.MkdirAll(p.config.Directory, 0755); err != nil {
+		return "", err
+	}
+	name := heapProfilePrefix + p.now().UTC().Format("20060102T150405.000000000Z") + heapProfileSuffix
+	path := filepath.Join(p.config.Directory, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	err = pprof.Lookup("heap").WriteTo(file, 0)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// Remove All But The Most Recent Profiles (The Caller Must Hold The Lock)
+func (p *HeapProfiler) prune() {
+	files, err := ioutil.ReadDir(p.config.Directory)
+	if err != nil {
+		p.logger.Warn("Failed To List Heap Profiles", zap.Error(err))
+		return
+	}
+	var profiles []string
+	for _, file := range files {
+		if !file.IsDir() && strings.HasPrefix(file.Name(), heapProfilePrefix) && strings.HasSuffix(file.Name(), heapProfileSuffix) {
+			profiles = append(profiles, file.Name())
+		}
+	}
+	sort.Strings(profiles) // The Timestamped Names Sort Chronologically
+	for len(profiles) > p.config.MaxProfiles {
+		if err := os.Remove(filepath.Join(p.config.Directory, profiles[0])); err != nil {
+			p.logger.Warn("Failed To Remove Heap Profile", zap.String("Name", profiles[0]), zap.Error(err))
+		}
+		profiles = profiles[1:]
+	}
+}
+
+// Get The Size Of The Heap (The Bytes Of Allocated Heap Objects)
+func heapSize() uint64 {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return memStats.HeapAlloc
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profiling
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewHeapConfigFromMap() Functionality
+func TestNewHeapConfigFromMap(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    map[string]string
+		want    *HeapConfig
+		wantErr bool
+	}{
+		{name: "Defaults", want: &HeapConfig{Directory: DefaultHeapProfileDir(), MaxProfiles: DefaultHeapMaxProfiles, CheckInterval: DefaultHeapCheckInterval}},
+		{
+			name: "Configured",
+			data: map[string]string{HeapWatermarkKey: "512Mi", HeapProfileDirKey: "/profiles", HeapMaxProfilesKey: "3", HeapCheckIntervalKey: "10s"},
+			want: &HeapConfig{Watermark: 512 * 1024 * 1024, Directory: "/profiles", MaxProfiles: 3, CheckInterval: 10 * time.Second},
+		},
+		{name: "Invalid Watermark", data: map[string]string{HeapWatermarkKey: "lots"}, wantErr: true},
+		{name: "Negative Watermark", data: map[string]string{HeapWatermarkKey: "-1Mi"}, wantErr: true},
+		{name: "Invalid MaxProfiles", data: map[string]string{HeapMaxProfilesKey: "0"}, wantErr: true},
+		{name: "Invalid CheckInterval", data: map[string]string{HeapCheckIntervalKey: "often"}, wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config, err := NewHeapConfigFromMap(testCase.data)
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.want, config)
+		})
+	}
+}
+
+// Test The HeapProfiler Captures Profiles Above The Watermark On New Highs & Keeps The Most Recent
+func TestHeapProfiler(t *testing.T) {
+
+	// Create A HeapProfiler With A Fake Heap Size & Clock Writing To A Temporary Directory
+	directory, err := ioutil.TempDir("", "heap-profiler-test")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(directory) }()
+	profiler := NewHeapProfiler(logtesting.TestLogger(t).Desugar())
+	var size uint64
+	profiler.heapSize = func() uint64 { return size }
+	now := time.Now()
+	profiler.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	// Verify Nothing Is Captured Until A Watermark Is Configured
+	size = 1000
+	profiler.check()
+	assert.Equal(t, 0, countProfiles(t, directory))
+	profiler.UpdateFromConfigMap(&corev1.ConfigMap{Data: map[string]string{HeapWatermarkKey: "100", HeapProfileDirKey: directory, HeapMaxProfilesKey: "2"}})
+
+	// Check A Sequence Of Heap Sizes, Verifying The Number Of Profiles Kept After Each
+	testCases := []struct {
+		size uint64
+		want int
+	}{
+		{size: 50, want: 0},  // Below The Watermark
+		{size: 100, want: 1}, // Reached The Watermark
+		{size: 120, want: 1}, // Not A New High (Below 125)
+		{size: 125, want: 2}, // A New High
+		{size: 50, want: 2},  // Back Below The Watermark (Re-Arming)
+		{size: 110, want: 2}, // Exceeded The Watermark Again (Pruned To The MaxProfiles)
+	}
+	for _, testCase := range testCases {
+		size = testCase.size
+		profiler.check()
+		assert.Equal(t, testCase.want, countProfiles(t, directory), "Heap Size %d", testCase.size)
+	}
+
+	// Verify An Invalid Configuration Is Ignored
+	profiler.Update(map[string]string{HeapWatermarkKey: "lots"})
+	assert.Equal(t, uint64(100), profiler.config.Watermark)
+}
+
+// Count The Heap Profiles In The Specified Directory
+func countProfiles(t *testing.T, directory string) int {
+	files, err := ioutil.ReadDir(directory)
+	assert.Nil(t, err)
+	return len(files)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package tracing
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/Shopify/sarama"
@@ -86,3 +87,37 @@ func ParseSpanContext(headers map[string][]byte) (sc trace.SpanContext, ok bool)
 
 	return format.SpanContextFromHeaders(traceParent, traceState)
 }
+
+// StartTraceFromRecordHeaders is the equivalent of StartTraceFromMessage for the headers of a consumed
+// Kafka record, reading the traceparent and tracestate headers in place so that the caller need not first
+// convert the record into a (map backed) protocol message.
+func StartTraceFromRecordHeaders(logger *zap.SugaredLogger, inCtx context.Context, recordHeaders []*sarama.RecordHeader, topic string) (context.Context, *trace.Span) {
+	sc, ok := ParseRecordSpanContext(recordHeaders)
+	if !ok {
+		logger.Warn("Cannot parse the spancontext, creating a new span")
+		return trace.StartSpan(inCtx, "kafkachannel-"+topic)
+	}
+
+	return trace.StartSpanWithRemoteParent(inCtx, "kafkachannel-"+topic, sc)
+}
+
+// ParseRecordSpanContext is the equivalent of ParseSpanContext for the headers of a Kafka record,
+// whose keys are matched case-insensitively as the protocol message would after lower-casing them.
+func ParseRecordSpanContext(recordHeaders []*sarama.RecordHeader) (sc trace.SpanContext, ok bool) {
+	traceParentBytes, ok := recordHeader(recordHeaders, traceParentHeader)
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+	traceStateBytes, _ := recordHeader(recordHeaders, traceStateHeader)
+	return format.SpanContextFromHeaders(string(traceParentBytes), string(traceStateBytes))
+}
+
+// recordHeader returns the value of the last record header with the specified (lower case) key
+func recordHeader(recordHeaders []*sarama.RecordHeader, key string) (value []byte, ok bool) {
+	for _, recordHeader := range recordHeaders {
+		if recordHeader != nil && len(recordHeader.Key) == len(key) && bytes.EqualFold(recordHeader.Key, []byte(key)) {
+			value, ok = recordHeader.Value, true
+		}
+	}
+	return value, ok
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Shopify/sarama"
 	protocolkafka "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	logtesting "knative.dev/pkg/logging/testing"
 
@@ -102,3 +103,27 @@ func TestStartTraceFromMessage(t *testing.T) {
 	require.NotNil(t, ctx)
 	require.NotNil(t, span)
 }
+
+// Verify that the span context parsed from record headers matches that parsed from the equivalent
+// message headers, and that StartTraceFromRecordHeaders creates spans with or without them.
+func TestStartTraceFromRecordHeaders(t *testing.T) {
+	logger := logtesting.TestLogger(t)
+
+	ctx, span := StartTraceFromRecordHeaders(logger, context.TODO(), nil, "testTopic")
+	require.NotNil(t, ctx)
+	require.NotNil(t, span)
+
+	var recordHeaders []*sarama.RecordHeader
+	for _, h := range SerializeTrace(sampleSpanContext) {
+		recordHeaders = append(recordHeaders, &sarama.RecordHeader{Key: []byte(h.Key), Value: h.Value})
+	}
+	recordHeaders[0].Key = []byte("TraceParent") // Keys Are Matched Case-Insensitively
+	outSpanContext, ok := ParseRecordSpanContext(recordHeaders)
+	require.True(t, ok)
+	require.Equal(t, sampleSpanContext, outSpanContext)
+
+	ctx, span = StartTraceFromRecordHeaders(logger, context.TODO(), recordHeaders, "testTopic")
+	require.NotNil(t, ctx)
+	require.NotNil(t, span)
+	require.Equal(t, traceID, span.SpanContext().TraceID)
+}
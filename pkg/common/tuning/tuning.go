@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tuning provides named presets which expand into a coherent set of sarama consumer and producer
+// settings, so that a consumer can be tuned for throughput or latency without reasoning about the individual
+// fetch, buffering and batching settings (and their interactions).
+package tuning
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// Preset is the name of a tuning preset.  The empty Preset leaves the sarama config unchanged.
+type Preset string
+
+const (
+	// Throughput favors large fetches and batches (and compression) over the latency of individual messages.
+	Throughput Preset = "throughput"
+
+	// Latency fetches and sends every message as soon as possible, at the expense of more (and smaller) requests.
+	Latency Preset = "latency"
+
+	// Balanced bounds the latency added by batching to a few milliseconds while still batching under load.
+	Balanced Preset = "balanced"
+)
+
+// settings are the sarama settings a preset expands into.
+type settings struct {
+	fetchMin           int32
+	fetchDefault       int32
+	maxWaitTime        time.Duration
+	channelBufferSize  int
+	autoCommitInterval time.Duration
+	flushBytes         int
+	flushFrequency     time.Duration
+	compression        sarama.CompressionCodec
+}
+
+// presets are the settings of the supported presets, by name.
+var presets = map[Preset]settings{
+	Throughput: {
+		fetchMin:           64 * 1024,
+		fetchDefault:       4 * 1024 * 1024,
+		maxWaitTime:        500 * time.Millisecond,
+		channelBufferSize:  1024,
+		autoCommitInterval: 5 * time.Second,
+		flushBytes:         1024 * 1024,
+		flushFrequency:     50 * time.Millisecond,
+		compression:        sarama.CompressionSnappy,
+	},
+	Latency: {
+		fetchMin:           1,
+		fetchDefault:       256 * 1024,
+		maxWaitTime:        10 * time.Millisecond,
+		channelBufferSize:  64,
+		autoCommitInterval: time.Second,
+		flushBytes:         0,
+		flushFrequency:     0,
+		compression:        sarama.CompressionNone,
+	},
+	Balanced: {
+		fetchMin:           1,
+		fetchDefault:       1024 * 1024,
+		maxWaitTime:        100 * time.Millisecond,
+		channelBufferSize:  256,
+		autoCommitInterval: time.Second,
+		flushBytes:         64 * 1024,
+		flushFrequency:     10 * time.Millisecond,
+		compression:        sarama.CompressionSnappy,
+	},
+}
+
+// PresetNames returns the names of the supported presets.
+func PresetNames() []string {
+	return []string{string(Throughput), string(Latency), string(Balanced)}
+}
+
+// NewPreset returns the preset with the given name (case insensitive), or the empty Preset if the name is empty.
+func NewPreset(name string) (Preset, error) {
+	preset := Preset(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := presets[preset]; ok || len(preset) == 0 {
+		return preset, nil
+	}
+	return "", fmt.Errorf("unknown tuning preset %q, use one of %v", name, PresetNames())
+}
+
+// Apply overwrites the consumer fetch, buffering and offset commit settings, and the producer batching and
+// compression settings, of the given sarama config with those of the preset.  The settings affecting the
+// durability or ordering of messages (e.g. the required acks, retries and idempotence) are left unchanged.
+func (p Preset) Apply(config *sarama.Config) {
+	s, ok := presets[p]
+	if !ok || config == nil {
+		return
+	}
+	config.ChannelBufferSize = s.channelBufferSize
+	config.Consumer.Fetch.Min = s.fetchMin
+	config.Consumer.Fetch.Default = s.fetchDefault
+	config.Consumer.MaxWaitTime = s.maxWaitTime
+	config.Consumer.Offsets.AutoCommit.Interval = s.autoCommitInterval
+	config.Producer.Flush.Bytes = s.flushBytes
+	config.Producer.Flush.Frequency = s.flushFrequency
+	config.Producer.Compression = s.compression
+}
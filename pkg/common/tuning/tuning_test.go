@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPreset(t *testing.T) {
+	testCases := map[string]struct {
+		name    string
+		want    Preset
+		wantErr string
+	}{
+		"empty": {
+			name: "",
+			want: "",
+		},
+		"throughput": {
+			name: "throughput",
+			want: Throughput,
+		},
+		"latency": {
+			name: "latency",
+			want: Latency,
+		},
+		"case and spaces": {
+			name: " Balanced ",
+			want: Balanced,
+		},
+		"unknown": {
+			name:    "fast",
+			wantErr: `unknown tuning preset "fast", use one of [throughput latency balanced]`,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewPreset(tc.name)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPresetApply(t *testing.T) {
+	// The empty preset leaves the config unchanged
+	config := sarama.NewConfig()
+	Preset("").Apply(config)
+	assert.Equal(t, sarama.NewConfig().Consumer.Fetch, config.Consumer.Fetch)
+	assert.Equal(t, sarama.NewConfig().Producer.Flush, config.Producer.Flush)
+
+	// Every preset expands into a valid config, leaving the durability settings unchanged
+	for _, name := range PresetNames() {
+		t.Run(name, func(t *testing.T) {
+			config := sarama.NewConfig()
+			config.Producer.RequiredAcks = sarama.WaitForAll
+			Preset(name).Apply(config)
+			assert.Nil(t, config.Validate())
+			assert.Equal(t, sarama.WaitForAll, config.Producer.RequiredAcks)
+			assert.Equal(t, sarama.NewConfig().Producer.Retry, config.Producer.Retry)
+		})
+	}
+
+	// The throughput preset batches more than the latency preset
+	throughput := sarama.NewConfig()
+	Throughput.Apply(throughput)
+	latency := sarama.NewConfig()
+	Latency.Apply(latency)
+	assert.Greater(t, throughput.Consumer.Fetch.Min, latency.Consumer.Fetch.Min)
+	assert.True(t, throughput.Consumer.MaxWaitTime > latency.Consumer.MaxWaitTime)
+	assert.Greater(t, throughput.Producer.Flush.Bytes, latency.Producer.Flush.Bytes)
+	assert.Equal(t, time.Duration(0), latency.Producer.Flush.Frequency)
+	assert.Equal(t, sarama.CompressionSnappy, throughput.Producer.Compression)
+
+	// A nil config is ignored
+	Throughput.Apply(nil)
+}
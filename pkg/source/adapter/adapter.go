@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	kafkasource "knative.dev/eventing-kafka/pkg/source"
 
@@ -32,12 +33,23 @@ import (
 
 	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/common/client"
 	"knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	"knative.dev/eventing-kafka/pkg/common/headers"
+	"knative.dev/eventing-kafka/pkg/common/profiling"
+	"knative.dev/eventing-kafka/pkg/common/tuning"
+	"knative.dev/eventing-kafka/pkg/source/attributes"
 	"knative.dev/pkg/logging"
 )
 
 const (
 	resourceGroup = "kafkasources.sources.knative.dev"
+
+	// retryBackoffInitial and retryBackoffMax bound the exponential backoff between the retries of a message which
+	// failed to be delivered, which only stall the partition of the message.
+	retryBackoffInitial = time.Second
+	retryBackoffMax     = time.Minute
 )
 
 type adapterConfig struct {
@@ -47,6 +59,27 @@ type adapterConfig struct {
 	ConsumerGroup string   `envconfig:"KAFKA_CONSUMER_GROUP" required:"true"`
 	Name          string   `envconfig:"NAME" required:"true"`
 	KeyType       string   `envconfig:"KEY_TYPE" required:"false"`
+	// DeadLetterSink is the optional URI of the sink receiving the events which failed to be delivered.
+	DeadLetterSink string `envconfig:"K_DEAD_LETTER_SINK" required:"false"`
+	// RebalanceStrategy optionally overrides the rebalance strategy of the consumer group.
+	RebalanceStrategy string `envconfig:"KAFKA_REBALANCE_STRATEGY" required:"false"`
+	// TuningPreset optionally overrides the fetch, buffering and offset commit settings of the consumer group.
+	TuningPreset string `envconfig:"KAFKA_TUNING_PRESET" required:"false"`
+	// Partitions optionally assigns the given partitions of the topics, instead of joining the consumer group.
+	Partitions []int32 `envconfig:"KAFKA_PARTITIONS" required:"false"`
+	// ConsumeFrom and ConsumeTo optionally bound the consumption to the messages whose timestamp is within [from, to).
+	ConsumeFrom time.Time `envconfig:"KAFKA_CONSUME_FROM" required:"false"`
+	ConsumeTo   time.Time `envconfig:"KAFKA_CONSUME_TO" required:"false"`
+	// HeadersPolicy is the optional JSON policy of the Kafka headers propagated into CloudEvent extensions.
+	HeadersPolicy string `envconfig:"KAFKA_HEADERS_POLICY" required:"false"`
+	// StartupMaxWait optionally overrides how long to wait at startup for the Kafka brokers to become reachable.
+	StartupMaxWait time.Duration `envconfig:"KAFKA_STARTUP_MAX_WAIT" required:"false"`
+	// DerivedAttributes is the optional JSON map of the JSONPath templates deriving CloudEvent attributes from the payload.
+	DerivedAttributes string `envconfig:"KAFKA_DERIVED_ATTRIBUTES" required:"false"`
+	// Sinks is the optional JSON list of the sinks the events are fanned out to, instead of the K_SINK.
+	Sinks string `envconfig:"KAFKA_SINKS" required:"false"`
+	// CeTime optionally selects the CloudEvent time of the records which aren't CloudEvents (record, logAppendTime or wallClock).
+	CeTime string `envconfig:"KAFKA_CE_TIME" required:"false"`
 }
 
 func NewEnvConfig() adapter.EnvConfigAccessor {
@@ -59,9 +92,16 @@ type Adapter struct {
 	reporter          pkgsource.StatsReporter
 	logger            *zap.SugaredLogger
 	keyTypeMapper     func([]byte) interface{}
+	headersPolicy     *headers.Policy
+	derivation        *attributes.Derivation
+	sinks             []fanOutSink
+	timeMode          attributes.TimeMode
+	// the topics whose record timestamps are their log append time, with the logAppendTime time mode
+	logAppendTimeTopics map[string]bool
 }
 
 var _ adapter.MessageAdapter = (*Adapter)(nil)
+var _ consumer.KafkaConsumerRetryingHandler = (*Adapter)(nil)
 var _ adapter.MessageAdapterConstructor = NewAdapter
 
 func NewAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, httpMessageSender *kncloudevents.HTTPMessageSender, reporter pkgsource.StatsReporter) adapter.MessageAdapter {
@@ -78,25 +118,110 @@ func NewAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, httpMe
 }
 
 func (a *Adapter) Start(ctx context.Context) error {
+	a.startHeapProfiler(ctx)
 	return a.start(ctx.Done())
 }
 
+// startHeapProfiler captures heap profiles on high memory watermarks if configured in the observability ConfigMap,
+// whose data the source controller passes in K_METRICS_CONFIG (redeploying the adapter when it changes).
+func (a *Adapter) startHeapProfiler(ctx context.Context) {
+	metricsConfig, err := a.config.GetMetricsConfig()
+	if err != nil {
+		a.logger.Warnw("Failed to read the metrics config, heap profiles won't be captured", zap.Error(err))
+		return
+	} else if metricsConfig == nil {
+		return
+	}
+	heapProfiler := profiling.NewHeapProfiler(a.logger.Desugar())
+	heapProfiler.Update(metricsConfig.ConfigMap)
+	heapProfiler.Start(ctx)
+}
+
 func (a *Adapter) start(stopCh <-chan struct{}) error {
 	a.logger.Infow("Starting with config: ",
 		zap.String("Topics", strings.Join(a.config.Topics, ",")),
 		zap.String("ConsumerGroup", a.config.ConsumerGroup),
+		zap.Int32s("Partitions", a.config.Partitions),
+		zap.String("RebalanceStrategy", a.config.RebalanceStrategy),
+		zap.String("TuningPreset", a.config.TuningPreset),
 		zap.String("SinkURI", a.config.Sink),
+		zap.String("DeadLetterSinkURI", a.config.DeadLetterSink),
 		zap.String("Name", a.config.Name),
 		zap.String("Namespace", a.config.Namespace),
+		zap.Time("ConsumeFrom", a.config.ConsumeFrom),
+		zap.Time("ConsumeTo", a.config.ConsumeTo),
+		zap.Duration("StartupMaxWait", a.config.StartupMaxWait),
+		zap.String("DerivedAttributes", a.config.DerivedAttributes),
+		zap.String("Sinks", a.config.Sinks),
+		zap.String("CeTime", a.config.CeTime),
 	)
 
+	headersPolicy, err := headers.Parse(a.config.HeadersPolicy)
+	if err != nil {
+		return fmt.Errorf("failed to create the headers policy: %w", err)
+	}
+	a.headersPolicy = headersPolicy
+
+	derivation, err := attributes.Parse(a.config.DerivedAttributes)
+	if err != nil {
+		return fmt.Errorf("failed to create the derived attributes: %w", err)
+	}
+	a.derivation = derivation
+
+	timeMode, err := attributes.ParseTimeMode(a.config.CeTime)
+	if err != nil {
+		return fmt.Errorf("failed to create the time mode: %w", err)
+	}
+	a.timeMode = timeMode
+
+	fanOutSinks, err := newFanOutSinks(a.config.Sinks, a.config.DeadLetterSink)
+	if err != nil {
+		return fmt.Errorf("failed to create the sinks: %w", err)
+	}
+	a.sinks = fanOutSinks
+
 	// init consumer group
 	addrs, config, err := kafkasource.NewConfig(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to create the config: %w", err)
 	}
 
+	preset, err := tuning.NewPreset(a.config.TuningPreset)
+	if err != nil {
+		return fmt.Errorf("failed to create the config: %w", err)
+	}
+	preset.Apply(config)
+
+	if a.config.RebalanceStrategy != "" {
+		strategy, err := consumer.NewBalanceStrategy(a.config.RebalanceStrategy)
+		if err != nil {
+			return fmt.Errorf("failed to create the config: %w", err)
+		}
+		config.Consumer.Group.Rebalance.Strategy = strategy
+	}
+
+	// a bounded consumption window replays the topics from their oldest available message
+	if !a.config.ConsumeFrom.IsZero() {
+		config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	}
+
+	// wait for the brokers to be reachable (e.g. during a cluster cold start) rather than failing to start the consumer group
+	waitCtx, cancelWait := contextForStop(stopCh)
+	err = client.WaitForBrokers(waitCtx, a.logger.Desugar(), addrs, config, client.BrokerWaitConfig{MaxWait: a.config.StartupMaxWait})
+	cancelWait()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the kafka brokers: %w", err)
+	}
+
+	// only the topics with the LogAppendTime timestamp type record the log append time of their records
+	if a.timeMode == attributes.TimeLogAppend {
+		a.logAppendTimeTopics = a.describeLogAppendTimeTopics(addrs, config)
+	}
+
 	consumerGroupFactory := consumer.NewConsumerGroupFactory(addrs, config)
+	if len(a.config.Partitions) > 0 {
+		consumerGroupFactory = consumer.NewPartitionConsumerGroupFactory(addrs, config, a.config.Partitions)
+	}
 	group, err := consumerGroupFactory.StartConsumerGroup(a.config.ConsumerGroup, a.config.Topics, a.logger, a)
 	if err != nil {
 		panic(err)
@@ -115,10 +240,31 @@ func (a *Adapter) start(stopCh <-chan struct{}) error {
 	return nil
 }
 
+// contextForStop returns a context which is cancelled when the given stop channel is closed.
+func contextForStop(stopCh <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
 func (a *Adapter) Handle(ctx context.Context, msg *sarama.ConsumerMessage) (bool, error) {
+	if inWindow, commit := a.inConsumptionWindow(msg); !inWindow {
+		return commit, nil
+	}
+
 	ctx, span := trace.StartSpan(ctx, "kafka-source")
 	defer span.End()
 
+	if len(a.sinks) > 0 {
+		return a.fanOut(ctx, span, msg)
+	}
+
 	req, err := a.httpMessageSender.NewCloudEventRequest(ctx)
 	if err != nil {
 		return false, err
@@ -134,12 +280,22 @@ func (a *Adapter) Handle(ctx context.Context, msg *sarama.ConsumerMessage) (bool
 
 	if err != nil {
 		a.logger.Debug("Error while sending the message", zap.Error(err))
-		return false, err // Error while sending, don't commit offset
+		return a.handleDeliveryError(ctx, span, msg, a.config.DeadLetterSink, &deadletter.DeliveryError{
+			Destination:  a.config.Sink,
+			ResponseCode: http.StatusInternalServerError,
+			Err:          err,
+			Message:      msg,
+		})
 	}
 
 	if res.StatusCode/100 != 2 {
 		a.logger.Debug("Unexpected status code", zap.Int("status code", res.StatusCode))
-		return false, fmt.Errorf("%d %s", res.StatusCode, http.StatusText(res.StatusCode))
+		return a.handleDeliveryError(ctx, span, msg, a.config.DeadLetterSink, &deadletter.DeliveryError{
+			Destination:  a.config.Sink,
+			ResponseCode: res.StatusCode,
+			Err:          fmt.Errorf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+			Message:      msg,
+		})
 	}
 
 	reportArgs := &pkgsource.ReportArgs{
@@ -151,3 +307,91 @@ func (a *Adapter) Handle(ctx context.Context, msg *sarama.ConsumerMessage) (bool
 	_ = a.reporter.ReportEventCount(reportArgs, res.StatusCode)
 	return true, nil
 }
+
+// describeLogAppendTimeTopics returns which of the topics have the LogAppendTime timestamp type, warning about the
+// others (or all of them if their config can't be described), whose records fall back to their consumption time.
+func (a *Adapter) describeLogAppendTimeTopics(addrs []string, config *sarama.Config) map[string]bool {
+	admin, err := sarama.NewClusterAdmin(addrs, config)
+	if err != nil {
+		a.logger.Warnw("Failed to describe the timestamp type of the topics, the events will have their consumption time", zap.Error(err))
+		return nil
+	}
+	defer func() { _ = admin.Close() }()
+
+	topics, err := logAppendTimeTopics(admin, a.config.Topics)
+	if err != nil {
+		a.logger.Warnw("Failed to describe the timestamp type of the topics, the events will have their consumption time", zap.Error(err))
+		return nil
+	}
+	for _, topic := range a.config.Topics {
+		if !topics[topic] {
+			a.logger.Warnw("The topic doesn't have the LogAppendTime timestamp type, its events will have their consumption time", zap.String("topic", topic))
+		}
+	}
+	return topics
+}
+
+// RetryBackoff returns the delay before retrying a message which failed to be delivered (to the sinks and dead
+// letter sinks), doubling from retryBackoffInitial up to retryBackoffMax.
+func (a *Adapter) RetryBackoff(attempt int) time.Duration {
+	backoff := retryBackoffInitial
+	for i := 1; i < attempt && backoff < retryBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > retryBackoffMax {
+		backoff = retryBackoffMax
+	}
+	return backoff
+}
+
+// inConsumptionWindow returns whether the message timestamp is within the consumption window (if any). The messages
+// produced before the window are skipped and committed, while the ones produced after are left uncommitted so that
+// the committed offsets stop at the end of the window.
+func (a *Adapter) inConsumptionWindow(msg *sarama.ConsumerMessage) (inWindow bool, commit bool) {
+	if msg.Timestamp.IsZero() {
+		return true, false
+	}
+	if !a.config.ConsumeFrom.IsZero() && msg.Timestamp.Before(a.config.ConsumeFrom) {
+		a.logger.Debug("Skipping message produced before the consumption window", zap.String("topic", msg.Topic), zap.Int32("partition", msg.Partition), zap.Int64("offset", msg.Offset))
+		return false, true
+	}
+	if !a.config.ConsumeTo.IsZero() && !msg.Timestamp.Before(a.config.ConsumeTo) {
+		a.logger.Debug("Skipping message produced after the consumption window", zap.String("topic", msg.Topic), zap.Int32("partition", msg.Partition), zap.Int64("offset", msg.Offset))
+		return false, false
+	}
+	return true, false
+}
+
+// handleDeliveryError sends the message which failed to be delivered to the dead letter sink (if configured),
+// enriched with the delivery error extensions. The offset is only committed if the dead letter sink accepted it.
+func (a *Adapter) handleDeliveryError(ctx context.Context, span *trace.Span, msg *sarama.ConsumerMessage, deadLetterSink string, deliveryError *deadletter.DeliveryError) (bool, error) {
+	if deadLetterSink == "" {
+		return false, deliveryError.Err // Error while sending, don't commit offset
+	}
+
+	req, err := a.httpMessageSender.NewCloudEventRequestWithTarget(ctx, deadLetterSink)
+	if err != nil {
+		return false, err
+	}
+
+	err = a.ConsumerMessageToHttpRequest(ctx, span, msg, req, deliveryError.Transformers()...)
+	if err != nil {
+		a.logger.Debug("failed to create dead letter request", zap.Error(err))
+		return false, err
+	}
+
+	res, err := a.httpMessageSender.Send(req)
+	if err != nil {
+		a.logger.Debug("Error while sending the message to the dead letter sink", zap.Error(err))
+		return false, fmt.Errorf("unable to complete request to either %s (%v) or %s (%v)", deliveryError.Destination, deliveryError.Err, deadLetterSink, err)
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		a.logger.Debug("Unexpected dead letter sink status code", zap.Int("status code", res.StatusCode))
+		return false, fmt.Errorf("unable to complete request to either %s (%v) or %s (%d %s)", deliveryError.Destination, deliveryError.Err, deadLetterSink, res.StatusCode, http.StatusText(res.StatusCode))
+	}
+
+	a.logger.Debug("Message sent to the dead letter sink", zap.String("topic", msg.Topic), zap.Int32("partition", msg.Partition), zap.Int64("offset", msg.Offset))
+	return true, nil
+}
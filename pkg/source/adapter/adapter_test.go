@@ -18,7 +18,9 @@ package kafka
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -36,6 +38,8 @@ import (
 	"knative.dev/eventing/pkg/kncloudevents"
 
 	sourcesv1beta1 "knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/headers"
+	"knative.dev/eventing-kafka/pkg/source/attributes"
 )
 
 func TestPostMessage_ServeHTTP_binary_mode(t *testing.T) {
@@ -44,6 +48,7 @@ func TestPostMessage_ServeHTTP_binary_mode(t *testing.T) {
 	testCases := map[string]struct {
 		sink            func(http.ResponseWriter, *http.Request)
 		keyTypeMapper   string
+		headersPolicy   *headers.Policy
 		message         *sarama.ConsumerMessage
 		expectedHeaders map[string]string
 		expectedBody    string
@@ -204,6 +209,80 @@ func TestPostMessage_ServeHTTP_binary_mode(t *testing.T) {
 			expectedBody: `{"key":"value"}`,
 			error:        false,
 		},
+		"accepted_headers_policy": {
+			sink:          sinkAccepted,
+			headersPolicy: &headers.Policy{Enabled: true, Allow: []string{"x-*"}, StripPrefix: "x-"},
+			message: &sarama.ConsumerMessage{
+				Key:   []byte("key"),
+				Topic: "topic1",
+				Headers: []*sarama.RecordHeader{
+					{
+						Key: []byte("x-tenant"), Value: []byte("acme"),
+					},
+					{
+						Key: []byte("name"), Value: []byte("Francesco"),
+					},
+				},
+				Value:     mustJsonMarshal(t, map[string]string{"key": "value"}),
+				Partition: 1,
+				Offset:    2,
+				Timestamp: aTimestamp,
+			},
+			expectedHeaders: map[string]string{
+				"ce-specversion": "1.0",
+				"ce-id":          makeEventId(1, 2),
+				"ce-time":        types.FormatTime(aTimestamp),
+				"ce-type":        sourcesv1beta1.KafkaEventType,
+				"ce-source":      sourcesv1beta1.KafkaEventSource("test", "test", "topic1"),
+				"ce-subject":     makeEventSubject(1, 2),
+				"ce-key":         "key",
+				"ce-tenant":      "acme",
+			},
+			expectedBody: `{"key":"value"}`,
+			error:        false,
+		},
+		"accepted_binary_headers_policy": {
+			sink:          sinkAccepted,
+			headersPolicy: &headers.Policy{Enabled: true, Allow: []string{"x-*"}, StripPrefix: "x-"},
+			message: &sarama.ConsumerMessage{
+				Key:   []byte("key"),
+				Topic: "topic1",
+				Headers: []*sarama.RecordHeader{
+					{
+						Key: []byte("ce_specversion"), Value: []byte("1.0"),
+					},
+					{
+						Key: []byte("ce_id"), Value: []byte("A234-1234-1234"),
+					},
+					{
+						Key: []byte("ce_type"), Value: []byte("com.github.pull.create"),
+					},
+					{
+						Key: []byte("ce_source"), Value: []byte("https://github.com/cloudevents/spec/pull"),
+					},
+					{
+						Key: []byte("content-type"), Value: []byte("application/json"),
+					},
+					{
+						Key: []byte("x-tenant"), Value: []byte("acme"),
+					},
+				},
+				Value:     mustJsonMarshal(t, map[string]string{"key": "value"}),
+				Partition: 1,
+				Offset:    2,
+				Timestamp: aTimestamp,
+			},
+			expectedHeaders: map[string]string{
+				"ce-specversion": "1.0",
+				"ce-id":          "A234-1234-1234",
+				"ce-type":        "com.github.pull.create",
+				"ce-source":      "https://github.com/cloudevents/spec/pull",
+				"content-type":   "application/json",
+				"ce-tenant":      "acme",
+			},
+			expectedBody: `{"key":"value"}`,
+			error:        false,
+		},
 		"accepted_structured": {
 			sink: sinkAccepted,
 			message: &sarama.ConsumerMessage{
@@ -354,6 +433,7 @@ func TestPostMessage_ServeHTTP_binary_mode(t *testing.T) {
 				logger:            zap.NewNop().Sugar(),
 				reporter:          statsReporter,
 				keyTypeMapper:     getKeyTypeMapper(tc.keyTypeMapper),
+				headersPolicy:     tc.headersPolicy,
 			}
 
 			_, err = a.Handle(context.TODO(), tc.message)
@@ -434,16 +514,273 @@ func sinkRejected(writer http.ResponseWriter, _ *http.Request) {
 	writer.WriteHeader(http.StatusRequestTimeout)
 }
 
+func TestHandle_DeadLetterSink(t *testing.T) {
+	testCases := map[string]struct {
+		deadLetterSink func(http.ResponseWriter, *http.Request)
+		expectedCommit bool
+	}{
+		"dead_letter_sink_accepted": {
+			deadLetterSink: sinkAccepted,
+			expectedCommit: true,
+		},
+		"dead_letter_sink_rejected": {
+			deadLetterSink: sinkRejected,
+			expectedCommit: false,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			sinkServer := httptest.NewServer(&fakeHandler{handler: sinkRejected})
+			defer sinkServer.Close()
+			h := &fakeHandler{handler: tc.deadLetterSink}
+			deadLetterSinkServer := httptest.NewServer(h)
+			defer deadLetterSinkServer.Close()
+
+			statsReporter, _ := source.NewStatsReporter()
+			s, err := kncloudevents.NewHTTPMessageSender(nil, sinkServer.URL)
+			require.NoError(t, err)
+
+			a := &Adapter{
+				config: &adapterConfig{
+					EnvConfig: adapter.EnvConfig{
+						Sink:      sinkServer.URL,
+						Namespace: "test",
+					},
+					Topics:         []string{"topic1"},
+					ConsumerGroup:  "group",
+					Name:           "test",
+					DeadLetterSink: deadLetterSinkServer.URL,
+				},
+				httpMessageSender: s,
+				logger:            zap.NewNop().Sugar(),
+				reporter:          statsReporter,
+				keyTypeMapper:     getKeyTypeMapper(""),
+			}
+
+			commit, err := a.Handle(context.TODO(), &sarama.ConsumerMessage{
+				Topic:     "topic1",
+				Value:     mustJsonMarshal(t, map[string]string{"key": "value"}),
+				Partition: 1,
+				Offset:    2,
+				Timestamp: time.Now(),
+			})
+
+			require.Equal(t, tc.expectedCommit, commit)
+			require.Equal(t, !tc.expectedCommit, err != nil)
+			require.Equal(t, sinkServer.URL, h.header.Get("ce-knativeerrordest"))
+			require.Equal(t, "408", h.header.Get("ce-knativeerrorcode"))
+			require.Equal(t, base64.StdEncoding.EncodeToString([]byte("408 Request Timeout")), h.header.Get("ce-knativeerrordata"))
+			require.Equal(t, "0", h.header.Get("ce-knativeerrorretries"))
+			require.Equal(t, "topic1", h.header.Get("ce-knativeerrortopic"))
+			require.Equal(t, "1", h.header.Get("ce-knativeerrorpartition"))
+			require.Equal(t, "2", h.header.Get("ce-knativeerroroffset"))
+			require.Equal(t, `{"key":"value"}`, string(h.body))
+		})
+	}
+}
+
 func TestAdapter_Start(t *testing.T) { // just increase code coverage
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Increasing coverage
 	_ = os.Setenv("KAFKA_BOOTSTRAP_SERVERS", "my-cluster-kafka-bootstrap.my-kafka-namespace:9092")
 
-	a := NewAdapter(ctx, NewEnvConfig(), nil, nil)
-	require.Panics(t, func() {
-		_ = a.Start(ctx)
-	})
+	// Unreachable brokers are reported once the startup wait is exhausted
+	a := NewAdapter(ctx, &adapterConfig{StartupMaxWait: time.Millisecond}, nil, nil)
+	err := a.Start(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to connect to the kafka brokers")
+
+	cancel()
+}
+
+func TestAdapter_StartStopped(t *testing.T) {
+	_ = os.Setenv("KAFKA_BOOTSTRAP_SERVERS", "my-cluster-kafka-bootstrap.my-kafka-namespace:9092")
 
+	// Stopping the adapter ends the wait for unreachable brokers
+	a := NewAdapter(context.Background(), NewEnvConfig(), nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
+	err := a.Start(ctx)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestAdapter_StartInvalidRebalanceStrategy(t *testing.T) {
+	_ = os.Setenv("KAFKA_BOOTSTRAP_SERVERS", "my-cluster-kafka-bootstrap.my-kafka-namespace:9092")
+
+	a := &Adapter{
+		config: &adapterConfig{
+			RebalanceStrategy: "cooperative-sticky",
+		},
+		logger: zap.NewNop().Sugar(),
+	}
+	err := a.start(make(chan struct{}))
+	require.EqualError(t, err, `failed to create the config: rebalance strategy "cooperative-sticky" is not supported by the Kafka client, use one of [range roundrobin sticky]`)
+}
+
+func TestHandle_ConsumptionWindow(t *testing.T) {
+	from := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	testCases := map[string]struct {
+		timestamp      time.Time
+		expectedCommit bool
+		expectedSent   bool
+	}{
+		"before_window": {
+			timestamp:      from.Add(-time.Second),
+			expectedCommit: true,
+			expectedSent:   false,
+		},
+		"window_start": {
+			timestamp:      from,
+			expectedCommit: true,
+			expectedSent:   true,
+		},
+		"within_window": {
+			timestamp:      from.Add(time.Minute),
+			expectedCommit: true,
+			expectedSent:   true,
+		},
+		"window_end": {
+			timestamp:      to,
+			expectedCommit: false,
+			expectedSent:   false,
+		},
+		"after_window": {
+			timestamp:      to.Add(time.Minute),
+			expectedCommit: false,
+			expectedSent:   false,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			h := &fakeHandler{handler: sinkAccepted}
+			sinkServer := httptest.NewServer(h)
+			defer sinkServer.Close()
+
+			statsReporter, _ := source.NewStatsReporter()
+			s, err := kncloudevents.NewHTTPMessageSender(nil, sinkServer.URL)
+			require.NoError(t, err)
+
+			a := &Adapter{
+				config: &adapterConfig{
+					EnvConfig: adapter.EnvConfig{
+						Sink:      sinkServer.URL,
+						Namespace: "test",
+					},
+					Topics:        []string{"topic1"},
+					ConsumerGroup: "group",
+					Name:          "test",
+					ConsumeFrom:   from,
+					ConsumeTo:     to,
+				},
+				httpMessageSender: s,
+				logger:            zap.NewNop().Sugar(),
+				reporter:          statsReporter,
+				keyTypeMapper:     getKeyTypeMapper(""),
+			}
+
+			commit, err := a.Handle(context.TODO(), &sarama.ConsumerMessage{
+				Topic:     "topic1",
+				Value:     mustJsonMarshal(t, map[string]string{"key": "value"}),
+				Partition: 1,
+				Offset:    2,
+				Timestamp: tc.timestamp,
+			})
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCommit, commit)
+			require.Equal(t, tc.expectedSent, h.body != nil)
+		})
+	}
+}
+
+func TestHandle_DerivedAttributes(t *testing.T) {
+	derivation, err := attributes.New(map[string]string{
+		"type":    "com.example.order.{.status}",
+		"subject": "{.id}",
+		"region":  "{.region}",
+	})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		value           []byte
+		expectedType    string
+		expectedSubject string
+		expectedRegion  string
+	}{
+		"json_payload": {
+			value:           mustJsonMarshal(t, map[string]string{"status": "shipped", "id": "1234", "region": "emea"}),
+			expectedType:    "com.example.order.shipped",
+			expectedSubject: "1234",
+			expectedRegion:  "emea",
+		},
+		"missing_fields": {
+			value:           mustJsonMarshal(t, map[string]string{"status": "shipped"}),
+			expectedType:    "com.example.order.shipped",
+			expectedSubject: "partition:1#2",
+		},
+		"non_json_payload": {
+			value:           []byte("shipped"),
+			expectedType:    sourcesv1beta1.KafkaEventType,
+			expectedSubject: "partition:1#2",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			h := &fakeHandler{handler: sinkAccepted}
+			sinkServer := httptest.NewServer(h)
+			defer sinkServer.Close()
+
+			statsReporter, _ := source.NewStatsReporter()
+			s, err := kncloudevents.NewHTTPMessageSender(nil, sinkServer.URL)
+			require.NoError(t, err)
+
+			a := &Adapter{
+				config: &adapterConfig{
+					EnvConfig: adapter.EnvConfig{
+						Sink:      sinkServer.URL,
+						Namespace: "test",
+					},
+					Topics:        []string{"topic1"},
+					ConsumerGroup: "group",
+					Name:          "test",
+				},
+				httpMessageSender: s,
+				logger:            zap.NewNop().Sugar(),
+				reporter:          statsReporter,
+				keyTypeMapper:     getKeyTypeMapper(""),
+				derivation:        derivation,
+			}
+
+			commit, err := a.Handle(context.TODO(), &sarama.ConsumerMessage{
+				Topic:     "topic1",
+				Value:     tc.value,
+				Partition: 1,
+				Offset:    2,
+				Timestamp: time.Now(),
+			})
+
+			require.NoError(t, err)
+			require.True(t, commit)
+			require.Equal(t, tc.expectedType, h.header.Get("ce-type"))
+			require.Equal(t, tc.expectedSubject, h.header.Get("ce-subject"))
+			require.Equal(t, tc.expectedRegion, h.header.Get("ce-region"))
+			require.Equal(t, string(tc.value), string(h.body))
+		})
+	}
+}
+
+func TestAdapter_RetryBackoff(t *testing.T) {
+	a := &Adapter{}
+	require.Equal(t, time.Second, a.RetryBackoff(1))
+	require.Equal(t, 2*time.Second, a.RetryBackoff(2))
+	require.Equal(t, 32*time.Second, a.RetryBackoff(6))
+	require.Equal(t, time.Minute, a.RetryBackoff(7))
+	require.Equal(t, time.Minute, a.RetryBackoff(1000))
 }
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	"knative.dev/eventing-kafka/pkg/source/sinks"
+	"knative.dev/eventing/pkg/kncloudevents"
+	pkgsource "knative.dev/pkg/source"
+)
+
+// fanOutSink is one of the sinks the events are fanned out to, with its own retries and dead letter sink.
+type fanOutSink struct {
+	uri            string
+	deadLetterSink string
+	retryConfig    kncloudevents.RetryConfig
+}
+
+// newFanOutSinks returns the sinks of the specified JSON (none if empty). The sinks without a dead letter sink of
+// their own fall back to the dead letter sink of the source.
+func newFanOutSinks(value string, deadLetterSink string) ([]fanOutSink, error) {
+	parsed, err := sinks.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	fanOutSinks := make([]fanOutSink, 0, len(parsed))
+	for i := range parsed {
+		retryConfig, err := parsed[i].RetryConfig()
+		if err != nil {
+			return nil, err
+		}
+		sink := fanOutSink{
+			uri:            parsed[i].URI,
+			deadLetterSink: parsed[i].DeadLetterSink,
+			retryConfig:    retryConfig,
+		}
+		if sink.deadLetterSink == "" {
+			sink.deadLetterSink = deadLetterSink
+		}
+		fanOutSinks = append(fanOutSinks, sink)
+	}
+	return fanOutSinks, nil
+}
+
+// fanOut delivers the message to all the sinks concurrently. The offset is only committed once every sink either
+// accepted the event or, having exhausted its retries, had it accepted by its dead letter sink. Otherwise the message
+// is redelivered to all the sinks, those which already accepted it included.
+func (a *Adapter) fanOut(ctx context.Context, span *trace.Span, msg *sarama.ConsumerMessage) (bool, error) {
+	commits := make([]bool, len(a.sinks))
+	errs := make([]error, len(a.sinks))
+	var wg sync.WaitGroup
+	for i := range a.sinks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			commits[i], errs[i] = a.deliverToSink(ctx, span, msg, &a.sinks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	commit := true
+	var failures []string
+	for i := range a.sinks {
+		commit = commit && commits[i]
+		if errs[i] != nil {
+			failures = append(failures, errs[i].Error())
+		}
+	}
+	if len(failures) > 0 {
+		return commit, fmt.Errorf("failed to deliver to %d of %d sinks: %s", len(failures), len(a.sinks), strings.Join(failures, "; "))
+	}
+	return commit, nil
+}
+
+// deliverToSink sends the message to the sink with its retries, and then to its dead letter sink if it still failed.
+func (a *Adapter) deliverToSink(ctx context.Context, span *trace.Span, msg *sarama.ConsumerMessage, sink *fanOutSink) (bool, error) {
+	req, err := a.httpMessageSender.NewCloudEventRequestWithTarget(ctx, sink.uri)
+	if err != nil {
+		return false, err
+	}
+
+	err = a.ConsumerMessageToHttpRequest(ctx, span, msg, req)
+	if err != nil {
+		a.logger.Debug("failed to create request", zap.String("sink", sink.uri), zap.Error(err))
+		return true, err
+	}
+
+	retries := 0
+	retryConfig := countingRetryConfig(&sink.retryConfig, &retries)
+	res, err := a.httpMessageSender.SendWithRetries(req, &retryConfig)
+
+	if err != nil {
+		a.logger.Debug("Error while sending the message", zap.String("sink", sink.uri), zap.Error(err))
+		return a.handleDeliveryError(ctx, span, msg, sink.deadLetterSink, &deadletter.DeliveryError{
+			Destination:  sink.uri,
+			ResponseCode: http.StatusInternalServerError,
+			Err:          err,
+			Retries:      retries,
+			Message:      msg,
+		})
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		a.logger.Debug("Unexpected status code", zap.String("sink", sink.uri), zap.Int("status code", res.StatusCode))
+		return a.handleDeliveryError(ctx, span, msg, sink.deadLetterSink, &deadletter.DeliveryError{
+			Destination:  sink.uri,
+			ResponseCode: res.StatusCode,
+			Err:          fmt.Errorf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+			Retries:      retries,
+			Message:      msg,
+		})
+	}
+
+	reportArgs := &pkgsource.ReportArgs{
+		Namespace:     a.config.Namespace,
+		Name:          a.config.Name,
+		ResourceGroup: resourceGroup,
+	}
+
+	_ = a.reporter.ReportEventCount(reportArgs, res.StatusCode)
+	return true, nil
+}
+
+// countingRetryConfig wraps the CheckRetry of the RetryConfig to count the retries actually performed.
+func countingRetryConfig(retryConfig *kncloudevents.RetryConfig, retries *int) kncloudevents.RetryConfig {
+	countingRetryConfig := *retryConfig
+	countingRetryConfig.CheckRetry = func(ctx context.Context, response *http.Response, err error) (bool, error) {
+		retry, checkRetryErr := retryConfig.CheckRetry(ctx, response, err)
+		if retry && *retries < retryConfig.RetryMax {
+			*retries++
+		}
+		return retry, checkRetryErr
+	}
+	return countingRetryConfig
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/source"
+)
+
+func TestNewFanOutSinks(t *testing.T) {
+	fanOutSinks, err := newFanOutSinks("", "http://dls")
+	require.NoError(t, err)
+	require.Empty(t, fanOutSinks)
+
+	fanOutSinks, err = newFanOutSinks(`[{"uri":"http://a","retry":2},{"uri":"http://b","deadLetterSink":"http://dls-b"}]`, "http://dls")
+	require.NoError(t, err)
+	require.Len(t, fanOutSinks, 2)
+	require.Equal(t, "http://a", fanOutSinks[0].uri)
+	require.Equal(t, "http://dls", fanOutSinks[0].deadLetterSink)
+	require.Equal(t, 2, fanOutSinks[0].retryConfig.RetryMax)
+	require.Equal(t, "http://b", fanOutSinks[1].uri)
+	require.Equal(t, "http://dls-b", fanOutSinks[1].deadLetterSink)
+	require.Equal(t, 0, fanOutSinks[1].retryConfig.RetryMax)
+
+	_, err = newFanOutSinks(`[{"deadLetterSink":"http://dls"}]`, "")
+	require.Error(t, err)
+}
+
+// sinkFailing rejects the first failures requests, then accepts the others.
+func sinkFailing(failures int32) func(http.ResponseWriter, *http.Request) {
+	var count int32
+	return func(writer http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&count, 1) <= failures {
+			sinkRejected(writer, req)
+		} else {
+			sinkAccepted(writer, req)
+		}
+	}
+}
+
+func TestHandle_FanOut(t *testing.T) {
+	testCases := map[string]struct {
+		secondSink         func(http.ResponseWriter, *http.Request)
+		secondSinkSpec     string
+		deadLetterSink     func(http.ResponseWriter, *http.Request)
+		expectedCommit     bool
+		expectedDeadLetter bool
+		expectedRetries    string
+	}{
+		"all_sinks_accepted": {
+			secondSink:     sinkAccepted,
+			expectedCommit: true,
+		},
+		"sink_accepted_after_retries": {
+			secondSink:     sinkFailing(2),
+			secondSinkSpec: `,"retry":2,"backoffDelay":"PT0S"`,
+			expectedCommit: true,
+		},
+		"sink_rejected_without_dead_letter_sink": {
+			secondSink:     sinkRejected,
+			expectedCommit: false,
+		},
+		"sink_rejected_dead_letter_sink_accepted": {
+			secondSink:         sinkFailing(3),
+			secondSinkSpec:     `,"retry":2,"backoffDelay":"PT0S"`,
+			deadLetterSink:     sinkAccepted,
+			expectedCommit:     true,
+			expectedDeadLetter: true,
+			expectedRetries:    "2",
+		},
+		"sink_rejected_dead_letter_sink_rejected": {
+			secondSink:         sinkRejected,
+			deadLetterSink:     sinkRejected,
+			expectedCommit:     false,
+			expectedDeadLetter: true,
+			expectedRetries:    "0",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			first := &fakeHandler{handler: sinkAccepted}
+			firstSinkServer := httptest.NewServer(first)
+			defer firstSinkServer.Close()
+			second := &fakeHandler{handler: tc.secondSink}
+			secondSinkServer := httptest.NewServer(second)
+			defer secondSinkServer.Close()
+
+			secondSinkSpec := tc.secondSinkSpec
+			deadLetter := &fakeHandler{handler: tc.deadLetterSink}
+			if tc.deadLetterSink != nil {
+				deadLetterSinkServer := httptest.NewServer(deadLetter)
+				defer deadLetterSinkServer.Close()
+				secondSinkSpec += fmt.Sprintf(`,"deadLetterSink":%q`, deadLetterSinkServer.URL)
+			}
+			fanOutSinks, err := newFanOutSinks(fmt.Sprintf(`[{"uri":%q},{"uri":%q%s}]`, firstSinkServer.URL, secondSinkServer.URL, secondSinkSpec), "")
+			require.NoError(t, err)
+
+			statsReporter, _ := source.NewStatsReporter()
+			s, err := kncloudevents.NewHTTPMessageSender(nil, firstSinkServer.URL)
+			require.NoError(t, err)
+
+			a := &Adapter{
+				config: &adapterConfig{
+					EnvConfig: adapter.EnvConfig{
+						Sink:      firstSinkServer.URL,
+						Namespace: "test",
+					},
+					Topics:        []string{"topic1"},
+					ConsumerGroup: "group",
+					Name:          "test",
+				},
+				httpMessageSender: s,
+				logger:            zap.NewNop().Sugar(),
+				reporter:          statsReporter,
+				keyTypeMapper:     getKeyTypeMapper(""),
+				sinks:             fanOutSinks,
+			}
+
+			commit, err := a.Handle(context.TODO(), &sarama.ConsumerMessage{
+				Topic:     "topic1",
+				Value:     mustJsonMarshal(t, map[string]string{"key": "value"}),
+				Partition: 1,
+				Offset:    2,
+				Timestamp: time.Now(),
+			})
+
+			require.Equal(t, tc.expectedCommit, commit)
+			require.Equal(t, !tc.expectedCommit, err != nil)
+			require.Equal(t, `{"key":"value"}`, string(first.body))
+			require.Equal(t, `{"key":"value"}`, string(second.body))
+			require.Equal(t, first.header.Get("ce-id"), second.header.Get("ce-id"))
+			require.Equal(t, tc.expectedDeadLetter, deadLetter.body != nil)
+			if tc.expectedDeadLetter {
+				require.Equal(t, secondSinkServer.URL, deadLetter.header.Get("ce-knativeerrordest"))
+				require.Equal(t, "408", deadLetter.header.Get("ce-knativeerrorcode"))
+				require.Equal(t, tc.expectedRetries, deadLetter.header.Get("ce-knativeerrorretries"))
+			}
+		})
+	}
+}
@@ -34,9 +34,10 @@ import (
 	"go.uber.org/zap"
 
 	sourcesv1beta1 "knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/headers"
 )
 
-func (a *Adapter) ConsumerMessageToHttpRequest(ctx context.Context, span *trace.Span, cm *sarama.ConsumerMessage, req *nethttp.Request) error {
+func (a *Adapter) ConsumerMessageToHttpRequest(ctx context.Context, span *trace.Span, cm *sarama.ConsumerMessage, req *nethttp.Request, transformers ...binding.Transformer) error {
 	msg := protocolkafka.NewMessageFromConsumerMessage(cm)
 
 	defer func() {
@@ -48,10 +49,14 @@ func (a *Adapter) ConsumerMessageToHttpRequest(ctx context.Context, span *trace.
 
 	// Build tracing ext to write it as output
 	tracingExt := extensions.FromSpanContext(span.SpanContext())
+	transformers = append(transformers, tracingExt.WriteTransformer())
+
+	// Add the extensions of the headers allowed by the headers policy (if any)
+	transformers = append(transformers, a.headersPolicy.Transformers(cm.Headers)...)
 
 	if msg.ReadEncoding() != binding.EncodingUnknown {
 		// Message is a CloudEvent -> Encode directly to HTTP
-		return http.WriteRequest(ctx, msg, req, tracingExt.WriteTransformer())
+		return http.WriteRequest(ctx, msg, req, transformers...)
 	}
 
 	a.logger.Debug("Message is not a CloudEvent -> We need to translate it to a valid CloudEvent")
@@ -60,19 +65,24 @@ func (a *Adapter) ConsumerMessageToHttpRequest(ctx context.Context, span *trace.
 	event := cloudevents.NewEvent()
 
 	event.SetID(makeEventId(cm.Partition, cm.Offset))
-	event.SetTime(cm.Timestamp)
+	event.SetTime(a.eventTime(cm))
 	event.SetType(sourcesv1beta1.KafkaEventType)
 	event.SetSource(sourcesv1beta1.KafkaEventSource(a.config.Namespace, a.config.Name, cm.Topic))
 	event.SetSubject(makeEventSubject(cm.Partition, cm.Offset))
 
-	dumpKafkaMetaToEvent(&event, a.keyTypeMapper, cm.Key, kafkaMsg)
+	dumpKafkaMetaToEvent(&event, a.keyTypeMapper, a.headersPolicy, cm.Key, kafkaMsg)
+
+	// Derive the configured attributes from the payload (if any), keeping the default ones on failure
+	if err := a.derivation.Apply(&event, kafkaMsg.Value); err != nil {
+		a.logger.Debug("Failed to derive the attributes", zap.Error(err))
+	}
 
 	err := event.SetData(kafkaMsg.ContentType, kafkaMsg.Value)
 	if err != nil {
 		return err
 	}
 
-	return http.WriteRequest(ctx, binding.ToMessage(&event), req, tracingExt.WriteTransformer())
+	return http.WriteRequest(ctx, binding.ToMessage(&event), req, transformers...)
 }
 
 func makeEventId(partition int32, offset int64) string {
@@ -96,10 +106,14 @@ func makeEventSubject(partition int32, offset int64) string {
 
 var replaceBadCharacters = regexp.MustCompile(`[^a-zA-Z0-9]`).ReplaceAllString
 
-func dumpKafkaMetaToEvent(event *cloudevents.Event, keyTypeMapper func([]byte) interface{}, key []byte, msg *protocolkafka.Message) {
+func dumpKafkaMetaToEvent(event *cloudevents.Event, keyTypeMapper func([]byte) interface{}, headersPolicy *headers.Policy, key []byte, msg *protocolkafka.Message) {
 	if len(key) > 0 {
 		event.SetExtension("key", keyTypeMapper(key))
 	}
+	// An enabled headers policy replaces the default "kafkaheader" prefixed extensions
+	if headersPolicy != nil && headersPolicy.Enabled {
+		return
+	}
 	for k, v := range msg.Headers {
 		// Let's skip the content-type, we already transport it with datacontenttype field
 		if k != "content-type" {
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"time"
+
+	"github.com/Shopify/sarama"
+
+	"knative.dev/eventing-kafka/pkg/source/attributes"
+)
+
+const (
+	// timestampTypeConfig is the topic config of the type of the timestamps of its records.
+	timestampTypeConfig = "message.timestamp.type"
+
+	// logAppendTime is the timestamp type of the topics whose records are timestamped by the brokers.
+	logAppendTime = "LogAppendTime"
+)
+
+// eventTime returns the CloudEvent time of the record, as selected by the time mode of the adapter.
+func (a *Adapter) eventTime(cm *sarama.ConsumerMessage) time.Time {
+	switch a.timeMode {
+	case attributes.TimeWallClock:
+		return time.Now()
+	case attributes.TimeLogAppend:
+		// the timestamp of the records of the other topics is their create time
+		if !a.logAppendTimeTopics[cm.Topic] {
+			return time.Now()
+		}
+	}
+	return cm.Timestamp
+}
+
+// logAppendTimeTopics returns which of the topics have the LogAppendTime timestamp type.
+func logAppendTimeTopics(admin sarama.ClusterAdmin, topics []string) (map[string]bool, error) {
+	logAppendTimeTopics := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		entries, err := admin.DescribeConfig(sarama.ConfigResource{
+			Type:        sarama.TopicResource,
+			Name:        topic,
+			ConfigNames: []string{timestampTypeConfig},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Name == timestampTypeConfig && entry.Value == logAppendTime {
+				logAppendTimeTopics[topic] = true
+			}
+		}
+	}
+	return logAppendTimeTopics, nil
+}
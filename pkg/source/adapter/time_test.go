@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+
+	"knative.dev/eventing-kafka/pkg/source/attributes"
+)
+
+func TestEventTime(t *testing.T) {
+	timestamp := time.Now().Add(-time.Hour)
+
+	testCases := map[string]struct {
+		timeMode            attributes.TimeMode
+		logAppendTimeTopics map[string]bool
+		expectTimestamp     bool
+	}{
+		"record": {
+			timeMode:        attributes.TimeRecord,
+			expectTimestamp: true,
+		},
+		"log append time topic": {
+			timeMode:            attributes.TimeLogAppend,
+			logAppendTimeTopics: map[string]bool{"topic1": true},
+			expectTimestamp:     true,
+		},
+		"create time topic": {
+			timeMode:            attributes.TimeLogAppend,
+			logAppendTimeTopics: map[string]bool{"topic2": true},
+			expectTimestamp:     false,
+		},
+		"wall clock": {
+			timeMode:        attributes.TimeWallClock,
+			expectTimestamp: false,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			a := &Adapter{timeMode: tc.timeMode, logAppendTimeTopics: tc.logAppendTimeTopics}
+
+			before := time.Now()
+			eventTime := a.eventTime(&sarama.ConsumerMessage{Topic: "topic1", Timestamp: timestamp})
+
+			if tc.expectTimestamp {
+				require.Equal(t, timestamp, eventTime)
+			} else {
+				require.False(t, eventTime.Before(before))
+			}
+		})
+	}
+}
+
+func TestLogAppendTimeTopics(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()),
+		"DescribeConfigsRequest": sarama.NewMockSequence(
+			&sarama.DescribeConfigsResponse{Resources: []*sarama.ResourceResponse{{
+				Name:    "topic1",
+				Type:    sarama.TopicResource,
+				Configs: []*sarama.ConfigEntry{{Name: timestampTypeConfig, Value: logAppendTime}},
+			}}},
+			&sarama.DescribeConfigsResponse{Resources: []*sarama.ResourceResponse{{
+				Name:    "topic2",
+				Type:    sarama.TopicResource,
+				Configs: []*sarama.ConfigEntry{{Name: timestampTypeConfig, Value: "CreateTime"}},
+			}}},
+		),
+	})
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V1_0_0_0
+	admin, err := sarama.NewClusterAdmin([]string{broker.Addr()}, config)
+	require.NoError(t, err)
+	defer func() { _ = admin.Close() }()
+
+	topics, err := logAppendTimeTopics(admin, []string{"topic1", "topic2"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"topic1": true}, topics)
+}
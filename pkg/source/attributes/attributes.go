@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// TypeAttribute is the name of the derivable CloudEvent type attribute.
+	TypeAttribute = "type"
+	// SubjectAttribute is the name of the derivable CloudEvent subject attribute.
+	SubjectAttribute = "subject"
+)
+
+// The valid names of CloudEvent extensions.
+var extensionName = regexp.MustCompile(`^[a-z0-9]{1,20}$`)
+
+// The CloudEvent context attributes which can't be derived, as they identify the record or describe its data.
+var reservedAttributes = map[string]bool{
+	"id":              true,
+	"source":          true,
+	"specversion":     true,
+	"datacontenttype": true,
+	"dataschema":      true,
+	"time":            true,
+	"data":            true,
+}
+
+// Derivation derives CloudEvent attributes from the JSON payload of the records, each attribute being set to the
+// output of a Kubernetes JSONPath template (e.g. "{.order.status}") applied to the payload.  The derivable
+// attributes are the type, the subject and any extension.  An attribute whose template has no output (a missing
+// field) or whose record payload is not JSON keeps its default value.
+//
+// A nil *Derivation is valid and derives no attributes.
+type Derivation struct {
+	names     []string
+	templates map[string]string
+}
+
+// New returns the valid Derivation of the specified templates by attribute name (a nil Derivation if empty).
+func New(templates map[string]string) (*Derivation, error) {
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	derivation := &Derivation{templates: make(map[string]string, len(templates))}
+	for name, template := range templates {
+		if err := validateName(name); err != nil {
+			return nil, err
+		}
+		if _, err := parse(name, template); err != nil {
+			return nil, fmt.Errorf("invalid template of the %q attribute: %w", name, err)
+		}
+		derivation.names = append(derivation.names, name)
+		derivation.templates[name] = template
+	}
+	sort.Strings(derivation.names)
+	return derivation, nil
+}
+
+// Parse returns the valid Derivation of the specified JSON templates by attribute name (a nil Derivation if empty).
+func Parse(value string) (*Derivation, error) {
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+	templates := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &templates); err != nil {
+		return nil, fmt.Errorf("invalid derived attributes: %w", err)
+	}
+	return New(templates)
+}
+
+// Format returns the JSON of the specified templates by attribute name (empty if there are none), as understood
+// by Parse.
+func Format(templates map[string]string) string {
+	if len(templates) == 0 {
+		return ""
+	}
+	value, _ := json.Marshal(templates) // String Maps Always Marshal
+	return string(value)
+}
+
+// Apply sets the attributes of the event derived from its JSON payload.
+func (d *Derivation) Apply(event *cloudevents.Event, payload []byte) error {
+	if d == nil {
+		return nil
+	}
+	var data interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return fmt.Errorf("unable to derive attributes from a non JSON payload: %w", err)
+	}
+	for _, name := range d.names {
+		path, _ := parse(name, d.templates[name]) // Validated By New()
+		var value bytes.Buffer
+		if err := path.Execute(&value, data); err != nil {
+			return fmt.Errorf("unable to derive the %q attribute: %w", name, err)
+		}
+		if value.Len() == 0 {
+			continue
+		}
+		switch name {
+		case TypeAttribute:
+			event.SetType(value.String())
+		case SubjectAttribute:
+			event.SetSubject(value.String())
+		default:
+			event.SetExtension(name, value.String())
+		}
+	}
+	return nil
+}
+
+// validateName returns an error if the attribute can't be derived.
+func validateName(name string) error {
+	if reservedAttributes[name] {
+		return fmt.Errorf("the %q attribute can't be derived", name)
+	}
+	if !extensionName.MatchString(name) {
+		return fmt.Errorf("invalid attribute name %q: must consist of 1 to 20 lowercase letters or digits", name)
+	}
+	return nil
+}
+
+// parse returns the parsed JSONPath template of the attribute.  The templates are parsed on each use, as a parsed
+// JSONPath can't be executed concurrently.
+func parse(name string, template string) (*jsonpath.JSONPath, error) {
+	path := jsonpath.New(name).AllowMissingKeys(true)
+	if err := path.Parse(template); err != nil {
+		return nil, err
+	}
+	return path, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := map[string]struct {
+		templates map[string]string
+		wantErr   bool
+	}{
+		"none":               {},
+		"type and subject":   {templates: map[string]string{"type": "{.kind}", "subject": "{.order.id}"}},
+		"extension":          {templates: map[string]string{"region": "{.region}"}},
+		"reserved attribute": {templates: map[string]string{"id": "{.id}"}, wantErr: true},
+		"invalid extension":  {templates: map[string]string{"order-region": "{.region}"}, wantErr: true},
+		"invalid template":   {templates: map[string]string{"type": "{.kind"}, wantErr: true},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			derivation, err := New(tc.templates)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, len(tc.templates) == 0 || tc.wantErr, derivation == nil)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	templates := map[string]string{"type": "{.kind}", "region": "{.region}"}
+	derivation, err := Parse(Format(templates))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"region", "type"}, derivation.names)
+	assert.Equal(t, templates, derivation.templates)
+
+	derivation, err = Parse(" ")
+	require.NoError(t, err)
+	assert.Nil(t, derivation)
+
+	_, err = Parse(`["type"]`)
+	assert.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	derivation, err := New(map[string]string{
+		"type":    "com.example.order.{.status}",
+		"subject": "{.order.id}",
+		"region":  "{.order.region}",
+		"missing": "{.missing}",
+	})
+	require.NoError(t, err)
+
+	event := cloudevents.NewEvent()
+	event.SetType("dev.knative.kafka.event")
+	event.SetSubject("partition:0#1")
+	require.NoError(t, derivation.Apply(&event, []byte(`{"status":"shipped","order":{"id":"1234","region":"emea"}}`)))
+	assert.Equal(t, "com.example.order.shipped", event.Type())
+	assert.Equal(t, "1234", event.Subject())
+	assert.Equal(t, "emea", event.Extensions()["region"])
+	assert.NotContains(t, event.Extensions(), "missing")
+
+	// A non JSON payload keeps the default attributes
+	event = cloudevents.NewEvent()
+	event.SetType("dev.knative.kafka.event")
+	assert.Error(t, derivation.Apply(&event, []byte("not json")))
+	assert.Equal(t, "dev.knative.kafka.event", event.Type())
+
+	// A nil derivation derives nothing
+	var none *Derivation
+	assert.NoError(t, none.Apply(&event, []byte("not json")))
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"fmt"
+)
+
+// TimeMode selects what the CloudEvent time attribute of the records which aren't CloudEvents is set to. The
+// records which are CloudEvents keep their own time attribute.
+type TimeMode string
+
+const (
+	// TimeRecord sets the time to the timestamp of the record, being its create time or, on the topics with the
+	// LogAppendTime timestamp type, its log append time (the default).
+	TimeRecord TimeMode = "record"
+
+	// TimeLogAppend sets the time to the log append time of the record. Kafka only records it on the topics with the
+	// LogAppendTime timestamp type, so the records of the other topics fall back to the time they are consumed.
+	TimeLogAppend TimeMode = "logAppendTime"
+
+	// TimeWallClock sets the time to the time the record is consumed.
+	TimeWallClock TimeMode = "wallClock"
+)
+
+// ParseTimeMode returns the named TimeMode (TimeRecord if empty).
+func ParseTimeMode(name string) (TimeMode, error) {
+	switch mode := TimeMode(name); mode {
+	case "":
+		return TimeRecord, nil
+	case TimeRecord, TimeLogAppend, TimeWallClock:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown time mode %q: must be %q, %q or %q", name, TimeRecord, TimeLogAppend, TimeWallClock)
+	}
+}
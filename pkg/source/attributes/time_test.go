@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeMode(t *testing.T) {
+	testCases := map[string]struct {
+		name    string
+		want    TimeMode
+		wantErr bool
+	}{
+		"default":         {name: "", want: TimeRecord},
+		"record":          {name: "record", want: TimeRecord},
+		"log append time": {name: "logAppendTime", want: TimeLogAppend},
+		"wall clock":      {name: "wallClock", want: TimeWallClock},
+		"unknown":         {name: "createTime", wantErr: true},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			mode, err := ParseTimeMode(tc.name)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.want, mode)
+		})
+	}
+}
@@ -18,12 +18,11 @@ package source
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
-	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/kelseyhightower/envconfig"
+
+	"knative.dev/eventing-kafka/pkg/common/client"
 )
 
 type AdapterSASL struct {
@@ -51,15 +50,19 @@ type envConfig struct {
 
 // NewConfig extracts the Kafka configuration from the environment.
 func NewConfig(ctx context.Context) ([]string, *sarama.Config, error) {
-	cfg := sarama.NewConfig()
-	cfg.Version = sarama.V2_0_0_0
-	cfg.Consumer.Return.Errors = true
-
 	var env envConfig
 	if err := envconfig.Process("", &env); err != nil {
 		return nil, nil, err
 	}
 
+	return newConfig(env)
+}
+
+func newConfig(env envConfig) ([]string, *sarama.Config, error) {
+	cfg := sarama.NewConfig()
+	cfg.Version = sarama.V2_0_0_0
+	cfg.Consumer.Return.Errors = true
+
 	if env.Net.SASL.Enable {
 		cfg.Net.SASL.Enable = true
 		cfg.Net.SASL.User = env.Net.SASL.User
@@ -68,7 +71,7 @@ func NewConfig(ctx context.Context) ([]string, *sarama.Config, error) {
 
 	if env.Net.TLS.Enable {
 		cfg.Net.TLS.Enable = true
-		tlsConfig, err := newTLSConfig(env.Net.TLS.Cert, env.Net.TLS.Key, env.Net.TLS.CACert)
+		tlsConfig, err := client.NewTLSConfig(env.Net.TLS.Cert, env.Net.TLS.Key, env.Net.TLS.CACert)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -90,67 +93,3 @@ func NewProducer(ctx context.Context) (sarama.Client, error) {
 
 	return sarama.NewClient(bs, cfg)
 }
-
-// newTLSConfig returns a *tls.Config using the given ceClient cert, ceClient key,
-// and CA certificate. If none are appropriate, a nil *tls.Config is returned.
-func newTLSConfig(clientCert, clientKey, caCert string) (*tls.Config, error) {
-	valid := false
-
-	config := &tls.Config{}
-
-	if clientCert != "" && clientKey != "" {
-		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
-		if err != nil {
-			return nil, err
-		}
-		config.Certificates = []tls.Certificate{cert}
-		valid = true
-	}
-
-	if caCert != "" {
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM([]byte(caCert))
-		config.RootCAs = caCertPool
-		// The CN of Heroku Kafka certs do not match the hostname of the
-		// broker, but Go's default TLS behavior requires that they do.
-		config.VerifyPeerCertificate = verifyCertSkipHostname(caCertPool)
-		config.InsecureSkipVerify = true
-		valid = true
-	}
-
-	if !valid {
-		config = nil
-	}
-
-	return config, nil
-}
-
-// verifyCertSkipHostname verifies certificates in the same way that the
-// default TLS handshake does, except it skips hostname verification. It must
-// be used with InsecureSkipVerify.
-func verifyCertSkipHostname(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
-	return func(certs [][]byte, _ [][]*x509.Certificate) error {
-		opts := x509.VerifyOptions{
-			Roots:         roots,
-			CurrentTime:   time.Now(),
-			Intermediates: x509.NewCertPool(),
-		}
-
-		leaf, err := x509.ParseCertificate(certs[0])
-		if err != nil {
-			return err
-		}
-
-		for _, asn1Data := range certs[1:] {
-			cert, err := x509.ParseCertificate(asn1Data)
-			if err != nil {
-				return err
-			}
-
-			opts.Intermediates.AddCert(cert)
-		}
-
-		_, err = leaf.Verify(opts)
-		return err
-	}
-}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+)
+
+// consumptionWindowCheckInterval is the interval between two checks of the completion of a consumption window
+// whose end has passed.
+const consumptionWindowCheckInterval = time.Minute
+
+// reconcileConsumptionWindow marks the KafkaSource as completed once its consumer group has consumed all the
+// events produced within its consumption window, and requeues it until then.
+func (r *Reconciler) reconcileConsumptionWindow(ctx context.Context, src *v1beta1.KafkaSource) {
+	window := src.Spec.ConsumptionWindow
+	if window == nil || src.Status.IsCompleted() {
+		return
+	}
+
+	// Events can still be produced within the window until its end
+	if remaining := time.Until(window.To.Time); remaining > 0 {
+		src.Status.MarkNotCompleted("ConsumptionInProgress", "Consuming the events produced until %s", window.To.UTC().Format(time.RFC3339))
+		r.enqueueAfter(src, remaining)
+		return
+	}
+
+	completed, err := r.consumptionWindowCompleted(ctx, src)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Unable to check the completion of the consumption window", zap.Error(err))
+		src.Status.MarkNotCompleted("ConsumptionCheckFailed", "Unable to check the consumed offsets: %v", err)
+		r.enqueueAfter(src, consumptionWindowCheckInterval)
+		return
+	}
+	if !completed {
+		src.Status.MarkNotCompleted("ConsumptionInProgress", "Consuming the remaining events produced until %s", window.To.UTC().Format(time.RFC3339))
+		r.enqueueAfter(src, consumptionWindowCheckInterval)
+		return
+	}
+
+	logging.FromContext(ctx).Infow("Consumption window completed", zap.String("consumerGroup", src.Spec.ConsumerGroup))
+	src.Status.MarkCompleted()
+}
+
+// consumptionWindowCompleted returns whether the committed offsets of the consumer group have reached, on every
+// partition, the offset of the first event produced after the end of the consumption window.
+func (r *Reconciler) consumptionWindowCompleted(ctx context.Context, src *v1beta1.KafkaSource) (bool, error) {
+	client, admin, err := r.newKafkaClient(ctx, src)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = admin.Close() }()
+
+	partitions, err := sourcePartitions(client, src)
+	if err != nil {
+		return false, err
+	}
+
+	committed, err := admin.ListConsumerGroupOffsets(src.Spec.ConsumerGroup, partitions)
+	if err != nil {
+		return false, err
+	}
+
+	to := src.Spec.ConsumptionWindow.To.UnixNano() / int64(time.Millisecond)
+	for topic, topicPartitions := range partitions {
+		for _, partition := range topicPartitions {
+			end, err := client.GetOffset(topic, partition, to)
+			if err != nil {
+				return false, err
+			}
+			// No event has been produced after the end of the window yet
+			if end == -1 {
+				if end, err = client.GetOffset(topic, partition, sarama.OffsetNewest); err != nil {
+					return false, err
+				}
+			}
+
+			oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+			if err != nil {
+				return false, err
+			}
+			// Nothing left to consume within the window
+			if end <= oldest {
+				continue
+			}
+
+			block := committed.GetBlock(topic, partition)
+			if block == nil || block.Offset < end {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
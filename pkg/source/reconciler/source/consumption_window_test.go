@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+)
+
+const (
+	testTopic = "topic1"
+	testGroup = "group"
+)
+
+func TestReconcileConsumptionWindow(t *testing.T) {
+	to := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	toMillis := to.UnixNano() / int64(time.Millisecond)
+
+	testCases := map[string]struct {
+		endOffset       int64 // offset of the first event produced after the window (-1 if none)
+		newestOffset    int64
+		oldestOffset    int64
+		committedOffset int64
+		expectCompleted bool
+	}{
+		"consumed": {
+			endOffset:       10,
+			oldestOffset:    0,
+			committedOffset: 10,
+			expectCompleted: true,
+		},
+		"still consuming": {
+			endOffset:       10,
+			oldestOffset:    0,
+			committedOffset: 5,
+			expectCompleted: false,
+		},
+		"nothing committed": {
+			endOffset:       10,
+			oldestOffset:    0,
+			committedOffset: -1,
+			expectCompleted: false,
+		},
+		"no event after the window, consumed": {
+			endOffset:       -1,
+			newestOffset:    8,
+			oldestOffset:    0,
+			committedOffset: 8,
+			expectCompleted: true,
+		},
+		"no event after the window, still consuming": {
+			endOffset:       -1,
+			newestOffset:    8,
+			oldestOffset:    0,
+			committedOffset: 2,
+			expectCompleted: false,
+		},
+		"no event within the window": {
+			endOffset:       4,
+			oldestOffset:    4,
+			committedOffset: -1,
+			expectCompleted: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			broker := sarama.NewMockBroker(t, 1)
+			defer broker.Close()
+
+			broker.SetHandlerByMap(map[string]sarama.MockResponse{
+				"MetadataRequest": sarama.NewMockMetadataResponse(t).
+					SetBroker(broker.Addr(), broker.BrokerID()).
+					SetController(broker.BrokerID()).
+					SetLeader(testTopic, 0, broker.BrokerID()),
+				"OffsetRequest": sarama.NewMockOffsetResponse(t).
+					SetVersion(1).
+					SetOffset(testTopic, 0, toMillis, tc.endOffset).
+					SetOffset(testTopic, 0, sarama.OffsetNewest, tc.newestOffset).
+					SetOffset(testTopic, 0, sarama.OffsetOldest, tc.oldestOffset),
+				"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+					SetCoordinator(sarama.CoordinatorGroup, testGroup, broker),
+				"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+					SetOffset(testGroup, testTopic, 0, tc.committedOffset, "", sarama.ErrNoError),
+			})
+
+			src := newConsumptionWindowSource(broker.Addr(), to.Add(-time.Hour), to)
+			r, requeued := newConsumptionWindowReconciler()
+
+			r.reconcileConsumptionWindow(logtesting.TestContextWithLogger(t), src)
+
+			assert.Equal(t, tc.expectCompleted, src.Status.IsCompleted())
+			if tc.expectCompleted {
+				assert.Empty(t, *requeued)
+			} else {
+				assert.Equal(t, []time.Duration{consumptionWindowCheckInterval}, *requeued)
+			}
+		})
+	}
+}
+
+func TestReconcileConsumptionWindowInProgress(t *testing.T) {
+	// No broker is needed while the window is still open
+	src := newConsumptionWindowSource("unused:9092", time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	r, requeued := newConsumptionWindowReconciler()
+
+	r.reconcileConsumptionWindow(context.TODO(), src)
+
+	assert.False(t, src.Status.IsCompleted())
+	assert.Equal(t, "ConsumptionInProgress", src.Status.GetCondition(v1beta1.KafkaConditionCompleted).Reason)
+	assert.Len(t, *requeued, 1)
+	assert.True(t, (*requeued)[0] > 59*time.Minute && (*requeued)[0] <= time.Hour)
+
+	// A completed source is not checked anymore
+	*requeued = nil
+	src.Status.MarkCompleted()
+	r.reconcileConsumptionWindow(context.TODO(), src)
+	assert.True(t, src.Status.IsCompleted())
+	assert.Empty(t, *requeued)
+}
+
+func TestReplicasChanged(t *testing.T) {
+	zero, one, two := int32(0), int32(1), int32(2)
+	assert.False(t, replicasChanged(&one, &one))
+	assert.False(t, replicasChanged(&two, &one))
+	assert.True(t, replicasChanged(&one, &zero))
+	assert.True(t, replicasChanged(&zero, &one))
+	assert.True(t, replicasChanged(nil, &one))
+	assert.False(t, replicasChanged(nil, nil))
+}
+
+func newConsumptionWindowSource(bootstrapServer string, from time.Time, to time.Time) *v1beta1.KafkaSource {
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source",
+			Namespace: "ns",
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{bootstrapServer},
+			},
+			Topics:        []string{testTopic},
+			ConsumerGroup: testGroup,
+			ConsumptionWindow: &v1beta1.KafkaConsumptionWindow{
+				From: metav1.NewTime(from),
+				To:   metav1.NewTime(to),
+			},
+		},
+	}
+	src.Status.InitializeConditions()
+	return src
+}
+
+func newConsumptionWindowReconciler() (*Reconciler, *[]time.Duration) {
+	requeued := &[]time.Duration{}
+	return &Reconciler{
+		KubeClientSet: fake.NewSimpleClientset(),
+		enqueueAfter: func(_ interface{}, after time.Duration) {
+			*requeued = append(*requeued, after)
+		},
+	}, requeued
+}
@@ -63,6 +63,7 @@ func NewController(
 
 	impl := kafkasource.NewImpl(ctx, c)
 	c.sinkResolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
+	c.enqueueAfter = impl.EnqueueAfter
 
 	logging.FromContext(ctx).Info("Setting up kafka event handlers")
 
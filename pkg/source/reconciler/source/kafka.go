@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Shopify/sarama"
+
+	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/client"
+)
+
+// newKafkaClient connects to the Kafka cluster of the KafkaSource, returning a client along with a cluster admin
+// sharing it. Closing the cluster admin closes the underlying client.
+func (r *Reconciler) newKafkaClient(ctx context.Context, src *v1beta1.KafkaSource) (sarama.Client, sarama.ClusterAdmin, error) {
+	addrs, config, err := client.NewConfigFromSpec(ctx, r.KubeClientSet, src.Namespace, src.Spec.KafkaAuthSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kafkaClient, err := sarama.NewClient(addrs, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(kafkaClient)
+	if err != nil {
+		_ = kafkaClient.Close()
+		return nil, nil, err
+	}
+	return kafkaClient, admin, nil
+}
+
+// sourcePartitions returns the partitions consumed by the KafkaSource, by topic.
+func sourcePartitions(kafkaClient sarama.Client, src *v1beta1.KafkaSource) (map[string][]int32, error) {
+	partitions := make(map[string][]int32)
+	for _, topics := range src.Spec.Topics {
+		for _, topic := range strings.Split(topics, ",") {
+			// A source with statically assigned partitions only consumes those
+			if len(src.Spec.Partitions) > 0 {
+				partitions[topic] = src.Spec.Partitions
+				continue
+			}
+			topicPartitions, err := kafkaClient.Partitions(topic)
+			if err != nil {
+				return nil, err
+			}
+			partitions[topic] = topicPartitions
+		}
+	}
+	return partitions, nil
+}
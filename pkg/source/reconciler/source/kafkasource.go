@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmeta"
@@ -38,6 +39,7 @@ import (
 
 	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
 	"knative.dev/eventing-kafka/pkg/source/reconciler/source/resources"
+	"knative.dev/eventing-kafka/pkg/source/sinks"
 
 	"k8s.io/client-go/kubernetes"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
@@ -92,6 +94,10 @@ type Reconciler struct {
 	sinkResolver *resolver.URIResolver
 
 	configs source.ConfigAccessor
+
+	// enqueueAfter requeues the KafkaSource after the given duration, e.g. to check the completion of its
+	// consumption window.
+	enqueueAfter func(obj interface{}, after time.Duration)
 }
 
 // Check that our Reconciler implements Interface
@@ -100,21 +106,12 @@ var _ reconcilerkafkasource.Interface = (*Reconciler)(nil)
 func (r *Reconciler) ReconcileKind(ctx context.Context, src *v1beta1.KafkaSource) pkgreconciler.Event {
 	src.Status.InitializeConditions()
 
-	if (src.Spec.Sink == duckv1.Destination{}) {
+	if (src.Spec.Sink == duckv1.Destination{}) && len(src.Spec.Sinks) == 0 {
 		src.Status.MarkNoSink("SinkMissing", "")
 		return fmt.Errorf("spec.sink missing")
 	}
 
-	dest := src.Spec.Sink.DeepCopy()
-	if dest.Ref != nil {
-		// To call URIFromDestination(), dest.Ref must have a Namespace. If there is
-		// no Namespace defined in dest.Ref, we will use the Namespace of the source
-		// as the Namespace of dest.Ref.
-		if dest.Ref.Namespace == "" {
-			dest.Ref.Namespace = src.GetNamespace()
-		}
-	}
-	sinkURI, err := r.sinkResolver.URIFromDestinationV1(ctx, *dest, src)
+	sinkURI, fanOutSinks, err := r.resolveSinks(ctx, src)
 	if err != nil {
 		src.Status.MarkNoSink("NotFound", "")
 		//delete adapter deployment if sink not found
@@ -124,6 +121,11 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, src *v1beta1.KafkaSource
 		return fmt.Errorf("getting sink URI: %v", err)
 	}
 	src.Status.MarkSink(sinkURI)
+	src.Status.SinkURIs = nil
+	for _, fanOutSink := range fanOutSinks {
+		uri, _ := apis.ParseURL(fanOutSink.URI)
+		src.Status.SinkURIs = append(src.Status.SinkURIs, *uri)
+	}
 
 	if val, ok := src.GetLabels()[v1beta1.KafkaKeyTypeLabel]; ok {
 		found := false
@@ -143,7 +145,16 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, src *v1beta1.KafkaSource
 
 	// TODO(mattmoor): create KafkaBinding for the receive adapter.
 
-	ra, err := r.createReceiveAdapter(ctx, src, sinkURI)
+	r.reconcileConsumptionWindow(ctx, src)
+
+	// The consumer group must be seeded with any imported offsets before the receive adapter starts consuming
+	if err := r.importOffsets(ctx, src); err != nil {
+		src.Status.MarkNotDeployed("OffsetsImportFailed", "Unable to import the offsets: %v", err)
+		logging.FromContext(ctx).Error("Unable to import the offsets of the consumer group", zap.Error(err))
+		return err
+	}
+
+	ra, err := r.createReceiveAdapter(ctx, src, sinkURI, fanOutSinks)
 	if err != nil {
 		var event *pkgreconciler.ReconcilerEvent
 		isReconcilerEvent := pkgreconciler.EventAs(err, &event)
@@ -158,15 +169,66 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, src *v1beta1.KafkaSource
 	src.Status.MarkDeployed(ra)
 	src.Status.CloudEventAttributes = r.createCloudEventAttributes(src)
 
+	r.reconcileTopicFailures(ctx, src)
+	r.reconcileOffsetsExport(ctx, src)
+
 	return nil
 }
 
-func (r *Reconciler) createReceiveAdapter(ctx context.Context, src *v1beta1.KafkaSource, sinkURI *apis.URL) (*appsv1.Deployment, error) {
+// resolveSinks returns the URI of the sink of the KafkaSource or, if it fans its events out to several sinks, the URI
+// of the first one along with all the resolved sinks.
+func (r *Reconciler) resolveSinks(ctx context.Context, src *v1beta1.KafkaSource) (*apis.URL, []sinks.Sink, error) {
+	if len(src.Spec.Sinks) == 0 {
+		sinkURI, err := r.resolveDestination(ctx, src, &src.Spec.Sink)
+		return sinkURI, nil, err
+	}
+
+	fanOutSinks := make([]sinks.Sink, 0, len(src.Spec.Sinks))
+	for i := range src.Spec.Sinks {
+		sinkURI, err := r.resolveDestination(ctx, src, &src.Spec.Sinks[i].Destination)
+		if err != nil {
+			return nil, nil, fmt.Errorf("spec.sinks[%d]: %w", i, err)
+		}
+		fanOutSink := sinks.Sink{URI: sinkURI.String()}
+		if delivery := src.Spec.Sinks[i].Delivery; delivery != nil {
+			if delivery.DeadLetterSink != nil {
+				deadLetterSinkURI, err := r.resolveDestination(ctx, src, delivery.DeadLetterSink)
+				if err != nil {
+					return nil, nil, fmt.Errorf("spec.sinks[%d].delivery.deadLetterSink: %w", i, err)
+				}
+				fanOutSink.DeadLetterSink = deadLetterSinkURI.String()
+			}
+			fanOutSink.Retry = delivery.Retry
+			fanOutSink.BackoffPolicy = delivery.BackoffPolicy
+			fanOutSink.BackoffDelay = delivery.BackoffDelay
+		}
+		fanOutSinks = append(fanOutSinks, fanOutSink)
+	}
+	sinkURI, _ := apis.ParseURL(fanOutSinks[0].URI)
+	return sinkURI, fanOutSinks, nil
+}
+
+// resolveDestination returns the URI of the destination, whose Ref defaults to the namespace of the KafkaSource.
+func (r *Reconciler) resolveDestination(ctx context.Context, src *v1beta1.KafkaSource, destination *duckv1.Destination) (*apis.URL, error) {
+	dest := destination.DeepCopy()
+	if dest.Ref != nil {
+		// To call URIFromDestination(), dest.Ref must have a Namespace. If there is
+		// no Namespace defined in dest.Ref, we will use the Namespace of the source
+		// as the Namespace of dest.Ref.
+		if dest.Ref.Namespace == "" {
+			dest.Ref.Namespace = src.GetNamespace()
+		}
+	}
+	return r.sinkResolver.URIFromDestinationV1(ctx, *dest, src)
+}
+
+func (r *Reconciler) createReceiveAdapter(ctx context.Context, src *v1beta1.KafkaSource, sinkURI *apis.URL, fanOutSinks []sinks.Sink) (*appsv1.Deployment, error) {
 	raArgs := resources.ReceiveAdapterArgs{
 		Image:          r.receiveAdapterImage,
 		Source:         src,
 		Labels:         resources.GetLabels(src.Name),
 		SinkURI:        sinkURI.String(),
+		Sinks:          fanOutSinks,
 		AdditionalEnvs: r.configs.ToEnvVars(),
 	}
 	expected := resources.MakeReceiveAdapter(&raArgs)
@@ -191,8 +253,9 @@ func (r *Reconciler) createReceiveAdapter(ctx context.Context, src *v1beta1.Kafk
 		return nil, err
 	} else if !metav1.IsControlledBy(ra, src) {
 		return nil, fmt.Errorf("deployment %q is not owned by KafkaSource %q", ra.Name, src.Name)
-	} else if podSpecChanged(ra.Spec.Template.Spec, expected.Spec.Template.Spec) {
+	} else if podSpecChanged(ra.Spec.Template.Spec, expected.Spec.Template.Spec) || replicasChanged(ra.Spec.Replicas, expected.Spec.Replicas) {
 		ra.Spec.Template.Spec = expected.Spec.Template.Spec
+		ra.Spec.Replicas = expected.Spec.Replicas
 		if ra, err = r.KubeClientSet.AppsV1().Deployments(src.Namespace).Update(ctx, ra, metav1.UpdateOptions{}); err != nil {
 			return ra, err
 		}
@@ -225,6 +288,15 @@ func podSpecChanged(oldPodSpec corev1.PodSpec, newPodSpec corev1.PodSpec) bool {
 	return false
 }
 
+// replicasChanged only reports the scaling down of the receive adapter of a completed KafkaSource (and the scaling
+// up from there), leaving any other replicas count untouched.
+func replicasChanged(oldReplicas *int32, newReplicas *int32) bool {
+	if oldReplicas == nil || newReplicas == nil {
+		return oldReplicas != newReplicas
+	}
+	return (*oldReplicas == 0) != (*newReplicas == 0)
+}
+
 func (r *Reconciler) createCloudEventAttributes(src *v1beta1.KafkaSource) []duckv1.CloudEventAttributes {
 	ceAttributes := make([]duckv1.CloudEventAttributes, 0, len(src.Spec.Topics))
 	for i := range src.Spec.Topics {
@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+)
+
+const (
+	// offsetsExportInterval is the interval between two exports of the committed offsets of a KafkaSource.
+	offsetsExportInterval = time.Minute
+
+	// offsetsConfigMapKey is the key of the exported offsets (JSON offsets by partition by topic) in their ConfigMap.
+	offsetsConfigMapKey = "offsets"
+
+	// offsetsConfigMapConsumerGroupKey is the key of the consumer group of the exported offsets in their ConfigMap.
+	offsetsConfigMapConsumerGroupKey = "consumerGroup"
+
+	kafkaSourceOffsetsImported = "KafkaSourceOffsetsImported"
+)
+
+// offsets are the committed offsets of a consumer group, by partition by topic.
+type offsets map[string]map[int32]int64
+
+// importOffsets seeds the partitions of the consumer group of the KafkaSource which have no committed offset yet
+// with the offsets of its import ConfigMap, so that a KafkaSource moved from another cluster or namespace resumes
+// from its position there. Partitions with committed offsets are left untouched, making the import idempotent.
+func (r *Reconciler) importOffsets(ctx context.Context, src *v1beta1.KafkaSource) error {
+	name, ok := src.GetAnnotations()[v1beta1.KafkaOffsetsImportAnnotation]
+	if !ok || name == "" {
+		return nil
+	}
+
+	configMap, err := r.KubeClientSet.CoreV1().ConfigMaps(src.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("getting the offsets ConfigMap %q: %w", name, err)
+	}
+	imported := offsets{}
+	if err := json.Unmarshal([]byte(configMap.Data[offsetsConfigMapKey]), &imported); err != nil {
+		return fmt.Errorf("parsing the offsets of ConfigMap %q: %w", name, err)
+	}
+
+	kafkaClient, admin, err := r.newKafkaClient(ctx, src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = admin.Close() }()
+
+	partitions, err := sourcePartitions(kafkaClient, src)
+	if err != nil {
+		return err
+	}
+	committed, err := admin.ListConsumerGroupOffsets(src.Spec.ConsumerGroup, partitions)
+	if err != nil {
+		return err
+	}
+
+	// Only the partitions consumed by the source without a committed offset are seeded
+	seeded := 0
+	offsetManager, err := sarama.NewOffsetManagerFromClient(src.Spec.ConsumerGroup, kafkaClient)
+	if err != nil {
+		return err
+	}
+	for topic, topicPartitions := range partitions {
+		for _, partition := range topicPartitions {
+			offset, ok := imported[topic][partition]
+			if block := committed.GetBlock(topic, partition); !ok || (block != nil && block.Offset >= 0) {
+				continue
+			}
+			partitionOffsetManager, err := offsetManager.ManagePartition(topic, partition)
+			if err != nil {
+				_ = offsetManager.Close()
+				return err
+			}
+			partitionOffsetManager.MarkOffset(offset, "")
+			partitionOffsetManager.AsyncClose()
+			seeded++
+		}
+	}
+
+	// Closing the offset manager flushes the offsets to the broker
+	if err := offsetManager.Close(); err != nil {
+		return err
+	}
+	if seeded > 0 {
+		logging.FromContext(ctx).Infow("Imported the offsets of the consumer group", zap.String("consumerGroup", src.Spec.ConsumerGroup),
+			zap.String("configMap", name), zap.Int("partitions", seeded))
+		controller.GetEventRecorder(ctx).Eventf(src, corev1.EventTypeNormal, kafkaSourceOffsetsImported,
+			"Imported the offsets of %d partitions from ConfigMap \"%s/%s\"", seeded, src.Namespace, name)
+	}
+	return nil
+}
+
+// reconcileOffsetsExport mirrors the committed offsets of the consumer group of the KafkaSource into its export
+// ConfigMap, and requeues the KafkaSource to refresh them.
+func (r *Reconciler) reconcileOffsetsExport(ctx context.Context, src *v1beta1.KafkaSource) {
+	name, ok := src.GetAnnotations()[v1beta1.KafkaOffsetsExportAnnotation]
+	if !ok || name == "" {
+		return
+	}
+	if err := r.exportOffsets(ctx, src, name); err != nil {
+		logging.FromContext(ctx).Errorw("Unable to export the offsets of the consumer group", zap.String("configMap", name), zap.Error(err))
+	}
+	r.enqueueAfter(src, offsetsExportInterval)
+}
+
+// exportOffsets writes the committed offsets of the consumer group of the KafkaSource into the named ConfigMap. The
+// ConfigMap is deliberately not owned by the KafkaSource so that it outlives it, but is labelled with its name so
+// that ConfigMaps which are not exports of the KafkaSource are never overwritten.
+func (r *Reconciler) exportOffsets(ctx context.Context, src *v1beta1.KafkaSource, name string) error {
+	kafkaClient, admin, err := r.newKafkaClient(ctx, src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = admin.Close() }()
+
+	partitions, err := sourcePartitions(kafkaClient, src)
+	if err != nil {
+		return err
+	}
+	committed, err := admin.ListConsumerGroupOffsets(src.Spec.ConsumerGroup, partitions)
+	if err != nil {
+		return err
+	}
+	exported := offsets{}
+	for topic, topicPartitions := range partitions {
+		for _, partition := range topicPartitions {
+			if block := committed.GetBlock(topic, partition); block != nil && block.Offset >= 0 {
+				if exported[topic] == nil {
+					exported[topic] = make(map[int32]int64)
+				}
+				exported[topic][partition] = block.Offset
+			}
+		}
+	}
+	data, err := json.Marshal(exported)
+	if err != nil {
+		return err
+	}
+	expected := map[string]string{
+		offsetsConfigMapKey:              string(data),
+		offsetsConfigMapConsumerGroupKey: src.Spec.ConsumerGroup,
+	}
+
+	configMaps := r.KubeClientSet.CoreV1().ConfigMaps(src.Namespace)
+	configMap, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: src.Namespace,
+				Labels:    map[string]string{v1beta1.KafkaOffsetsSourceLabel: src.Name},
+			},
+			Data: expected,
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	if configMap.Labels[v1beta1.KafkaOffsetsSourceLabel] != src.Name {
+		return fmt.Errorf("ConfigMap %q is not an offsets export of KafkaSource %q", name, src.Name)
+	}
+	if configMap.Data[offsetsConfigMapKey] == expected[offsetsConfigMapKey] && configMap.Data[offsetsConfigMapConsumerGroupKey] == src.Spec.ConsumerGroup {
+		return nil
+	}
+	configMap = configMap.DeepCopy()
+	configMap.Data = expected
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
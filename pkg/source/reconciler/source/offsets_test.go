@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
+	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+)
+
+const testOffsetsConfigMap = "source-offsets"
+
+func TestReconcileOffsetsExport(t *testing.T) {
+	broker := newOffsetsMockBroker(t, 42)
+	defer broker.Close()
+
+	src := newOffsetsSource(broker.Addr(), v1beta1.KafkaOffsetsExportAnnotation)
+	r, requeued := newConsumptionWindowReconciler()
+	ctx := logtesting.TestContextWithLogger(t)
+
+	// The committed offsets are mirrored into a new ConfigMap labelled with the source
+	r.reconcileOffsetsExport(ctx, src)
+	assert.Equal(t, []time.Duration{offsetsExportInterval}, *requeued)
+	configMap, err := r.KubeClientSet.CoreV1().ConfigMaps(src.Namespace).Get(ctx, testOffsetsConfigMap, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"topic1":{"0":42}}`, configMap.Data[offsetsConfigMapKey])
+	assert.Equal(t, testGroup, configMap.Data[offsetsConfigMapConsumerGroupKey])
+	assert.Equal(t, src.Name, configMap.Labels[v1beta1.KafkaOffsetsSourceLabel])
+	assert.Empty(t, configMap.OwnerReferences)
+
+	// Existing exports are updated
+	configMap.Data[offsetsConfigMapKey] = `{"topic1":{"0":1}}`
+	_, err = r.KubeClientSet.CoreV1().ConfigMaps(src.Namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+	r.reconcileOffsetsExport(ctx, src)
+	configMap, err = r.KubeClientSet.CoreV1().ConfigMaps(src.Namespace).Get(ctx, testOffsetsConfigMap, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"topic1":{"0":42}}`, configMap.Data[offsetsConfigMapKey])
+
+	// ConfigMaps which are not exports of the source are never overwritten
+	configMap.Labels = nil
+	configMap.Data = map[string]string{"other": "data"}
+	_, err = r.KubeClientSet.CoreV1().ConfigMaps(src.Namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+	assert.NotNil(t, r.exportOffsets(ctx, src, testOffsetsConfigMap))
+	configMap, err = r.KubeClientSet.CoreV1().ConfigMaps(src.Namespace).Get(ctx, testOffsetsConfigMap, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"other": "data"}, configMap.Data)
+
+	// Sources without the annotation are not exported
+	*requeued = nil
+	src.Annotations = nil
+	r.reconcileOffsetsExport(ctx, src)
+	assert.Empty(t, *requeued)
+}
+
+func TestImportOffsets(t *testing.T) {
+	testCases := map[string]struct {
+		committedOffset int64
+		offsets         string
+		expectError     bool
+		expectSeeded    bool
+	}{
+		"seeds the uncommitted partitions": {
+			committedOffset: -1,
+			offsets:         `{"topic1":{"0":7}}`,
+			expectSeeded:    true,
+		},
+		"leaves the committed partitions": {
+			committedOffset: 5,
+			offsets:         `{"topic1":{"0":7}}`,
+		},
+		"ignores the other topics": {
+			committedOffset: -1,
+			offsets:         `{"topic2":{"0":7}}`,
+		},
+		"invalid offsets": {
+			committedOffset: -1,
+			offsets:         `{"topic1":`,
+			expectError:     true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			broker := newOffsetsMockBroker(t, tc.committedOffset)
+			defer broker.Close()
+
+			src := newOffsetsSource(broker.Addr(), v1beta1.KafkaOffsetsImportAnnotation)
+			r, _ := newConsumptionWindowReconciler()
+			recorder := record.NewFakeRecorder(10)
+			ctx := controller.WithEventRecorder(logtesting.TestContextWithLogger(t), recorder)
+			_, err := r.KubeClientSet.CoreV1().ConfigMaps(src.Namespace).Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: testOffsetsConfigMap, Namespace: src.Namespace},
+				Data:       map[string]string{offsetsConfigMapKey: tc.offsets},
+			}, metav1.CreateOptions{})
+			assert.Nil(t, err)
+
+			err = r.importOffsets(ctx, src)
+
+			assert.Equal(t, tc.expectError, err != nil, err)
+			var seeded []int64
+			for _, request := range broker.History() {
+				if commitRequest, ok := request.Request.(*sarama.OffsetCommitRequest); ok {
+					offset, _, err := commitRequest.Offset(testTopic, 0)
+					assert.Nil(t, err)
+					seeded = append(seeded, offset)
+				}
+			}
+			if tc.expectSeeded {
+				assert.Equal(t, []int64{7}, seeded)
+				assert.Len(t, recorder.Events, 1)
+			} else {
+				assert.Empty(t, seeded)
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
+
+func TestImportOffsetsMissingConfigMap(t *testing.T) {
+	src := newOffsetsSource("unused:9092", v1beta1.KafkaOffsetsImportAnnotation)
+	r, _ := newConsumptionWindowReconciler()
+	assert.NotNil(t, r.importOffsets(context.TODO(), src))
+
+	// Sources without the annotation are not imported
+	src.Annotations = nil
+	assert.Nil(t, r.importOffsets(context.TODO(), src))
+}
+
+func newOffsetsMockBroker(t *testing.T, committedOffset int64) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()).
+			SetLeader(testTopic, 0, broker.BrokerID()),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, testGroup, broker),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset(testGroup, testTopic, 0, committedOffset, "", sarama.ErrNoError),
+		"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t).
+			SetError(testGroup, testTopic, 0, sarama.ErrNoError),
+	})
+	return broker
+}
+
+func newOffsetsSource(bootstrapServer string, annotation string) *v1beta1.KafkaSource {
+	return &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "source",
+			Namespace:   "ns",
+			Annotations: map[string]string{annotation: testOffsetsConfigMap},
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{bootstrapServer},
+			},
+			Topics:        []string{testTopic},
+			ConsumerGroup: testGroup,
+		},
+	}
+}
@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	"knative.dev/eventing-kafka/pkg/source/attributes"
+	"knative.dev/eventing-kafka/pkg/source/sinks"
 	"knative.dev/pkg/kmeta"
 )
 
@@ -33,11 +36,16 @@ type ReceiveAdapterArgs struct {
 	Source         *v1beta1.KafkaSource
 	Labels         map[string]string
 	SinkURI        string
+	Sinks          []sinks.Sink
 	AdditionalEnvs []corev1.EnvVar
 }
 
 func MakeReceiveAdapter(args *ReceiveAdapterArgs) *v1.Deployment {
 	replicas := int32(1)
+	// A KafkaSource which consumed its whole consumption window doesn't need a receive adapter anymore
+	if args.Source.Status.IsCompleted() {
+		replicas = 0
+	}
 
 	env := append([]corev1.EnvVar{{
 		Name:  "KAFKA_BOOTSTRAP_SERVERS",
@@ -72,6 +80,83 @@ func MakeReceiveAdapter(args *ReceiveAdapterArgs) *v1.Deployment {
 		})
 	}
 
+	if val, ok := args.Source.GetAnnotations()[v1beta1.KafkaDeadLetterSinkAnnotation]; ok {
+		env = append(env, corev1.EnvVar{
+			Name:  "K_DEAD_LETTER_SINK",
+			Value: val,
+		})
+	}
+
+	if val, ok := args.Source.GetAnnotations()[v1beta1.KafkaRebalanceStrategyAnnotation]; ok {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_REBALANCE_STRATEGY",
+			Value: val,
+		})
+	}
+
+	if val, ok := args.Source.GetAnnotations()[v1beta1.KafkaTuningPresetAnnotation]; ok {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_TUNING_PRESET",
+			Value: val,
+		})
+	}
+
+	if val, ok := args.Source.GetAnnotations()[v1beta1.KafkaHeadersPolicyAnnotation]; ok {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_HEADERS_POLICY",
+			Value: val,
+		})
+	}
+
+	if val, ok := args.Source.GetAnnotations()[v1beta1.KafkaStartupMaxWaitAnnotation]; ok {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_STARTUP_MAX_WAIT",
+			Value: val,
+		})
+	}
+
+	if val, ok := args.Source.GetAnnotations()[v1beta1.KafkaCloudEventTimeAnnotation]; ok {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_CE_TIME",
+			Value: val,
+		})
+	}
+
+	if len(args.Source.Spec.DerivedAttributes) > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_DERIVED_ATTRIBUTES",
+			Value: attributes.Format(args.Source.Spec.DerivedAttributes),
+		})
+	}
+
+	if len(args.Sinks) > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_SINKS",
+			Value: sinks.Format(args.Sinks),
+		})
+	}
+
+	if len(args.Source.Spec.Partitions) > 0 {
+		partitions := make([]string, 0, len(args.Source.Spec.Partitions))
+		for _, partition := range args.Source.Spec.Partitions {
+			partitions = append(partitions, strconv.Itoa(int(partition)))
+		}
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_PARTITIONS",
+			Value: strings.Join(partitions, ","),
+		})
+	}
+
+	if window := args.Source.Spec.ConsumptionWindow; window != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_CONSUME_FROM",
+			Value: window.From.UTC().Format(time.RFC3339),
+		}, corev1.EnvVar{
+			Name:  "KAFKA_CONSUME_TO",
+			Value: window.To.UTC().Format(time.RFC3339),
+		})
+	}
+
 	env = appendEnvFromSecretKeyRef(env, "KAFKA_NET_SASL_USER", args.Source.Spec.Net.SASL.User.SecretKeyRef)
 	env = appendEnvFromSecretKeyRef(env, "KAFKA_NET_SASL_PASSWORD", args.Source.Spec.Net.SASL.Password.SecretKeyRef)
 	env = appendEnvFromSecretKeyRef(env, "KAFKA_NET_TLS_CERT", args.Source.Spec.Net.TLS.Cert.SecretKeyRef)
@@ -18,6 +18,7 @@ package resources
 
 import (
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +27,7 @@ import (
 
 	bindingsv1beta1 "knative.dev/eventing-kafka/pkg/apis/bindings/v1beta1"
 	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	"knative.dev/eventing-kafka/pkg/source/sinks"
 	"knative.dev/pkg/kmp"
 )
 
@@ -584,3 +586,332 @@ func TestMakeReceiveAdapterKeyType(t *testing.T) {
 		t.Errorf("unexpected deploy (-want, +got) = %v", diff)
 	}
 }
+
+func TestMakeReceiveAdapterDeadLetterSink(t *testing.T) {
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			Annotations: map[string]string{
+				v1beta1.KafkaDeadLetterSinkAnnotation: "http://dead-letter-sink",
+			},
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "K_DEAD_LETTER_SINK", Value: "http://dead-letter-sink"}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
+
+func TestMakeReceiveAdapterRebalanceStrategy(t *testing.T) {
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			Annotations: map[string]string{
+				v1beta1.KafkaRebalanceStrategyAnnotation: "sticky",
+			},
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "KAFKA_REBALANCE_STRATEGY", Value: "sticky"}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
+
+func TestMakeReceiveAdapterTuningPreset(t *testing.T) {
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			Annotations: map[string]string{
+				v1beta1.KafkaTuningPresetAnnotation: "latency",
+			},
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "KAFKA_TUNING_PRESET", Value: "latency"}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
+
+func TestMakeReceiveAdapterHeadersPolicy(t *testing.T) {
+	policy := `{"enabled": true, "allow": ["x-*"]}`
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			Annotations: map[string]string{
+				v1beta1.KafkaHeadersPolicyAnnotation: policy,
+			},
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "KAFKA_HEADERS_POLICY", Value: policy}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
+
+func TestMakeReceiveAdapterStartupMaxWait(t *testing.T) {
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			Annotations: map[string]string{
+				v1beta1.KafkaStartupMaxWaitAnnotation: "10m",
+			},
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "KAFKA_STARTUP_MAX_WAIT", Value: "10m"}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
+
+func TestMakeReceiveAdapterCloudEventTime(t *testing.T) {
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			Annotations: map[string]string{
+				v1beta1.KafkaCloudEventTimeAnnotation: "wallClock",
+			},
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "KAFKA_CE_TIME", Value: "wallClock"}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
+
+func TestMakeReceiveAdapterConsumptionWindow(t *testing.T) {
+	from := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+			ConsumptionWindow: &v1beta1.KafkaConsumptionWindow{
+				From: metav1.NewTime(from),
+				To:   metav1.NewTime(from.Add(time.Hour)),
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := []corev1.EnvVar{
+		{Name: "KAFKA_CONSUME_FROM", Value: "2020-10-01T00:00:00Z"},
+		{Name: "KAFKA_CONSUME_TO", Value: "2020-10-01T01:00:00Z"},
+	}
+	if diff, _ := kmp.SafeDiff(want, env[len(env)-2:]); diff != "" {
+		t.Errorf("unexpected consumption window env (-want, +got) = %v", diff)
+	}
+	if *got.Spec.Replicas != 1 {
+		t.Errorf("expected 1 replica, got %d", *got.Spec.Replicas)
+	}
+
+	// the receive adapter is scaled down once the consumption window is completed
+	src.Status.InitializeConditions()
+	src.Status.MarkCompleted()
+	got = MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+	if *got.Spec.Replicas != 0 {
+		t.Errorf("expected 0 replicas, got %d", *got.Spec.Replicas)
+	}
+}
+
+func TestMakeReceiveAdapterPartitions(t *testing.T) {
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+			Partitions:    []int32{0, 3, 4},
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "KAFKA_PARTITIONS", Value: "0,3,4"}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
+
+func TestMakeReceiveAdapterSinks(t *testing.T) {
+	retry := int32(3)
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup: "group",
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "http://sink1",
+		Sinks: []sinks.Sink{
+			{URI: "http://sink1"},
+			{URI: "http://sink2", DeadLetterSink: "http://dls2", Retry: &retry},
+		},
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "KAFKA_SINKS", Value: `[{"uri":"http://sink1"},{"uri":"http://sink2","deadLetterSink":"http://dls2","retry":3}]`}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
+
+func TestMakeReceiveAdapterDerivedAttributes(t *testing.T) {
+	src := &v1beta1.KafkaSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1beta1.KafkaSourceSpec{
+			Topics: []string{"topic1,topic2"},
+			KafkaAuthSpec: bindingsv1beta1.KafkaAuthSpec{
+				BootstrapServers: []string{"server1,server2"},
+			},
+			ConsumerGroup:     "group",
+			DerivedAttributes: map[string]string{"type": "{.kind}"},
+		},
+	}
+
+	got := MakeReceiveAdapter(&ReceiveAdapterArgs{
+		Image:   "test-image",
+		Source:  src,
+		SinkURI: "sink-uri",
+	})
+
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "KAFKA_DERIVED_ATTRIBUTES", Value: `{"type":"{.kind}"}`}
+	if env[len(env)-1] != want {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+)
+
+// topicFailuresCheckInterval is the interval between two checks of the partitions stalled by delivery failures.
+const topicFailuresCheckInterval = time.Minute
+
+// reconcileTopicFailures surfaces in the status of the KafkaSource the partitions whose consumption the receive
+// adapter has stalled on an event failing to be delivered, and requeues it to keep them up to date.
+func (r *Reconciler) reconcileTopicFailures(ctx context.Context, src *v1beta1.KafkaSource) {
+	// The receive adapter of a completed KafkaSource is scaled down and doesn't consume anymore
+	if src.Status.IsCompleted() {
+		src.Status.MarkTopicFailures(nil)
+		return
+	}
+
+	failures, err := r.topicFailures(ctx, src)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Unable to check the stalled partitions of the consumer group", zap.Error(err))
+		src.Status.MarkTopicsHealthUnknown("TopicsCheckFailed", "Unable to check the committed offsets: %v", err)
+	} else {
+		src.Status.MarkTopicFailures(failures)
+	}
+	r.enqueueAfter(src, topicFailuresCheckInterval)
+}
+
+// topicFailures returns, sorted by topic, the partitions whose committed offset records a delivery failure.
+func (r *Reconciler) topicFailures(ctx context.Context, src *v1beta1.KafkaSource) ([]v1beta1.KafkaTopicFailure, error) {
+	client, admin, err := r.newKafkaClient(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = admin.Close() }()
+
+	partitions, err := sourcePartitions(client, src)
+	if err != nil {
+		return nil, err
+	}
+
+	committed, err := admin.ListConsumerGroupOffsets(src.Spec.ConsumerGroup, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []v1beta1.KafkaTopicFailure
+	for topic, topicPartitions := range partitions {
+		var topicFailure *v1beta1.KafkaTopicFailure
+		for _, partition := range topicPartitions {
+			block := committed.GetBlock(topic, partition)
+			if block == nil {
+				continue
+			}
+			failure := consumer.ParseFailure(block.Metadata)
+			if failure == nil {
+				continue
+			}
+			if topicFailure == nil {
+				topicFailure = &v1beta1.KafkaTopicFailure{Topic: topic}
+			}
+			topicFailure.Partitions = append(topicFailure.Partitions, partition)
+			// The topic reports its longest stalled partition
+			if topicFailure.Since.IsZero() || failure.Since.Before(topicFailure.Since.Time) {
+				topicFailure.Error = failure.Error
+				topicFailure.Since = metav1.NewTime(failure.Since)
+			}
+		}
+		if topicFailure != nil {
+			sort.Slice(topicFailure.Partitions, func(i, j int) bool { return topicFailure.Partitions[i] < topicFailure.Partitions[j] })
+			failures = append(failures, *topicFailure)
+		}
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Topic < failures[j].Topic })
+	return failures, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	"knative.dev/eventing-kafka/pkg/apis/sources/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+)
+
+func TestReconcileTopicFailures(t *testing.T) {
+	since := time.Now().Add(-time.Hour).Truncate(time.Second)
+	failure := &consumer.Failure{Error: "408 Request Timeout", Since: since, Attempts: 12}
+	recentFailure := &consumer.Failure{Error: "500 Internal Server Error", Since: since.Add(time.Minute), Attempts: 3}
+
+	testCases := map[string]struct {
+		metadata         [2]string
+		expectHealthy    bool
+		expectPartitions []int32
+	}{
+		"healthy": {
+			expectHealthy: true,
+		},
+		"one stalled partition": {
+			metadata:         [2]string{"", failure.Metadata()},
+			expectPartitions: []int32{1},
+		},
+		"all stalled partitions": {
+			metadata:         [2]string{recentFailure.Metadata(), failure.Metadata()},
+			expectPartitions: []int32{0, 1},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			broker := sarama.NewMockBroker(t, 1)
+			defer broker.Close()
+
+			broker.SetHandlerByMap(map[string]sarama.MockResponse{
+				"MetadataRequest": sarama.NewMockMetadataResponse(t).
+					SetBroker(broker.Addr(), broker.BrokerID()).
+					SetController(broker.BrokerID()).
+					SetLeader(testTopic, 0, broker.BrokerID()).
+					SetLeader(testTopic, 1, broker.BrokerID()),
+				"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+					SetCoordinator(sarama.CoordinatorGroup, testGroup, broker),
+				"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+					SetOffset(testGroup, testTopic, 0, 5, tc.metadata[0], sarama.ErrNoError).
+					SetOffset(testGroup, testTopic, 1, 7, tc.metadata[1], sarama.ErrNoError),
+			})
+
+			src := newConsumptionWindowSource(broker.Addr(), since, time.Now().Add(time.Hour))
+			src.Spec.ConsumptionWindow = nil
+			r, requeued := newConsumptionWindowReconciler()
+
+			r.reconcileTopicFailures(logtesting.TestContextWithLogger(t), src)
+
+			assert.Equal(t, []time.Duration{topicFailuresCheckInterval}, *requeued)
+			condition := src.Status.GetCondition(v1beta1.KafkaConditionTopicsHealthy)
+			require.NotNil(t, condition)
+			assert.Equal(t, tc.expectHealthy, condition.IsTrue())
+			if tc.expectHealthy {
+				assert.Empty(t, src.Status.TopicFailures)
+				return
+			}
+			require.Len(t, src.Status.TopicFailures, 1)
+			assert.Equal(t, testTopic, src.Status.TopicFailures[0].Topic)
+			assert.Equal(t, tc.expectPartitions, src.Status.TopicFailures[0].Partitions)
+			assert.Equal(t, failure.Error, src.Status.TopicFailures[0].Error)
+			assert.True(t, since.Equal(src.Status.TopicFailures[0].Since.Time))
+		})
+	}
+}
+
+func TestReconcileTopicFailuresUnreachable(t *testing.T) {
+	src := newConsumptionWindowSource("localhost:0", time.Now(), time.Now().Add(time.Hour))
+	src.Spec.ConsumptionWindow = nil
+	r, requeued := newConsumptionWindowReconciler()
+
+	r.reconcileTopicFailures(logtesting.TestContextWithLogger(t), src)
+
+	assert.Equal(t, []time.Duration{topicFailuresCheckInterval}, *requeued)
+	assert.True(t, src.Status.GetCondition(v1beta1.KafkaConditionTopicsHealthy).IsUnknown())
+
+	// A completed source is not checked anymore
+	*requeued = nil
+	src.Status.MarkCompleted()
+	r.reconcileTopicFailures(logtesting.TestContextWithLogger(t), src)
+	assert.Empty(t, *requeued)
+	assert.True(t, src.Status.GetCondition(v1beta1.KafkaConditionTopicsHealthy).IsTrue())
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// DefaultBackoffDelay is the ISO 8601 delay of the retries of the Sinks which don't specify one.
+const DefaultBackoffDelay = "PT1S"
+
+// Sink is a resolved destination of a KafkaSource fanning its events out to several sinks, as passed by the source
+// controller to the receive adapter (in the KAFKA_SINKS environment variable).  The delivery fields are those of
+// the destination's DeliverySpec, with its dead letter sink resolved to a URI.
+type Sink struct {
+	URI            string                            `json:"uri"`
+	DeadLetterSink string                            `json:"deadLetterSink,omitempty"`
+	Retry          *int32                            `json:"retry,omitempty"`
+	BackoffPolicy  *eventingduckv1.BackoffPolicyType `json:"backoffPolicy,omitempty"`
+	BackoffDelay   *string                           `json:"backoffDelay,omitempty"`
+}
+
+// Parse returns the Sinks of the specified JSON (no Sinks if empty).
+func Parse(value string) ([]Sink, error) {
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+	var sinks []Sink
+	if err := json.Unmarshal([]byte(value), &sinks); err != nil {
+		return nil, fmt.Errorf("invalid sinks: %w", err)
+	}
+	for i := range sinks {
+		if sinks[i].URI == "" {
+			return nil, fmt.Errorf("invalid sinks: sink %d has no uri", i)
+		}
+		if _, err := sinks[i].RetryConfig(); err != nil {
+			return nil, fmt.Errorf("invalid sinks: sink %d: %w", i, err)
+		}
+	}
+	return sinks, nil
+}
+
+// Format returns the JSON of the specified Sinks (empty if there are none), as understood by Parse.
+func Format(sinks []Sink) string {
+	if len(sinks) == 0 {
+		return ""
+	}
+	value, _ := json.Marshal(sinks) // Sinks Only Hold Strings & Numbers, Which Always Marshal
+	return string(value)
+}
+
+// RetryConfig returns the configuration of the retries of the deliveries to the Sink.  Unlike the retries of the
+// Knative DeliverySpec, failures to connect to the Sink are retried too, and the backoff policy and delay default
+// to an exponential backoff from one second.
+func (s *Sink) RetryConfig() (kncloudevents.RetryConfig, error) {
+	deliverySpec := eventingduckv1.DeliverySpec{
+		Retry:         s.Retry,
+		BackoffPolicy: s.BackoffPolicy,
+		BackoffDelay:  s.BackoffDelay,
+	}
+	if deliverySpec.BackoffPolicy == nil {
+		backoffPolicy := eventingduckv1.BackoffPolicyExponential
+		deliverySpec.BackoffPolicy = &backoffPolicy
+	} else if *deliverySpec.BackoffPolicy != eventingduckv1.BackoffPolicyExponential && *deliverySpec.BackoffPolicy != eventingduckv1.BackoffPolicyLinear {
+		return kncloudevents.RetryConfig{}, fmt.Errorf("unsupported backoff policy %q", *deliverySpec.BackoffPolicy)
+	}
+	if deliverySpec.BackoffDelay == nil {
+		backoffDelay := DefaultBackoffDelay
+		deliverySpec.BackoffDelay = &backoffDelay
+	}
+	retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(deliverySpec)
+	if err != nil {
+		return retryConfig, err
+	}
+	retryConfig.CheckRetry = checkRetry
+	return retryConfig, nil
+}
+
+// checkRetry retries the deliveries which failed to connect or were not accepted.
+func checkRetry(_ context.Context, response *nethttp.Response, err error) (bool, error) {
+	return err != nil || response == nil || response.StatusCode/100 != 2, nil
+}
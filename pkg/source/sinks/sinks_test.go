@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+func TestParse(t *testing.T) {
+	retry := int32(3)
+	delay := "PT2S"
+	linear := eventingduckv1.BackoffPolicyLinear
+	testCases := map[string]struct {
+		value   string
+		want    []Sink
+		wantErr bool
+	}{
+		"empty": {},
+		"sinks": {
+			value: `[{"uri":"http://a"},{"uri":"http://b","deadLetterSink":"http://dls","retry":3,"backoffPolicy":"linear","backoffDelay":"PT2S"}]`,
+			want:  []Sink{{URI: "http://a"}, {URI: "http://b", DeadLetterSink: "http://dls", Retry: &retry, BackoffPolicy: &linear, BackoffDelay: &delay}},
+		},
+		"invalid json":           {value: `{"uri":"http://a"}`, wantErr: true},
+		"missing uri":            {value: `[{"deadLetterSink":"http://dls"}]`, wantErr: true},
+		"invalid backoff delay":  {value: `[{"uri":"http://a","backoffDelay":"1s"}]`, wantErr: true},
+		"invalid backoff policy": {value: `[{"uri":"http://a","backoffPolicy":"constant"}]`, wantErr: true},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			sinks, err := Parse(tc.value)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.want, sinks)
+			if err == nil {
+				formatted, err := Parse(Format(sinks))
+				require.NoError(t, err)
+				assert.Equal(t, sinks, formatted)
+			}
+		})
+	}
+}
+
+func TestRetryConfig(t *testing.T) {
+	retry := int32(2)
+	delay := "PT2S"
+	linear := eventingduckv1.BackoffPolicyLinear
+
+	// The backoff defaults to an exponential one from one second
+	sink := Sink{URI: "http://a", Retry: &retry}
+	retryConfig, err := sink.RetryConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 2, retryConfig.RetryMax)
+	assert.Equal(t, 2*time.Second, retryConfig.Backoff(1, nil))
+	assert.Equal(t, 4*time.Second, retryConfig.Backoff(2, nil))
+
+	sink = Sink{URI: "http://a", BackoffPolicy: &linear, BackoffDelay: &delay}
+	retryConfig, err = sink.RetryConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 0, retryConfig.RetryMax)
+	assert.Equal(t, 4*time.Second, retryConfig.Backoff(2, nil))
+
+	// Connection failures are retried, as well as the responses which were not accepted
+	for _, tc := range []struct {
+		response *http.Response
+		err      error
+		want     bool
+	}{
+		{response: &http.Response{StatusCode: http.StatusAccepted}, want: false},
+		{response: &http.Response{StatusCode: http.StatusBadRequest}, want: true},
+		{response: &http.Response{StatusCode: http.StatusServiceUnavailable}, want: true},
+		{err: errors.New("connection refused"), want: true},
+	} {
+		retry, err := retryConfig.CheckRetry(context.TODO(), tc.response, tc.err)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, retry)
+	}
+}